@@ -0,0 +1,12 @@
+/*
+Copyright (c) Microsoft Corporation.
+Licensed under the MIT license.
+*/
+
+// +kubebuilder:object:generate=true
+// +groupName=networking.fleet.azure.com
+// +groupGoName=Networking
+// +k8s:openapi-gen=true
+
+// Package v1alpha1 contains API Schema definitions for the networking.fleet v1alpha1 API group.
+package v1alpha1
@@ -20,6 +20,19 @@ type Endpoint struct {
 	// +kubebuilder:validation:MinItems:1
 	// +kubebuilder:validation:MaxItems:100
 	Addresses []string `json:"addresses"`
+	// NodeName is the name of the node hosting the Endpoint in its origin cluster, if known. It is passed on to the
+	// imported EndpointSlices, so that the derived Services can use the Local internal traffic policy.
+	// +optional
+	NodeName *string `json:"nodeName,omitempty"`
+	// Serving indicates that the Endpoint is able to receive traffic. It is only set on the terminating Endpoints,
+	// which are exported when their origin cluster propagates the terminating endpoints; an Endpoint without the
+	// field is ready.
+	// +optional
+	Serving *bool `json:"serving,omitempty"`
+	// Terminating indicates that the Endpoint is terminating. The terminating Endpoints are imported as not ready,
+	// so that they only receive traffic from the consumers which drain the terminating endpoints gracefully.
+	// +optional
+	Terminating *bool `json:"terminating,omitempty"`
 }
 
 // OwnerServiceReference points to the Service that owns the exported EndpointSlice.
@@ -60,6 +73,7 @@ type EndpointSliceExportSpec struct {
 	OwnerServiceReference OwnerServiceReference `json:"ownerServiceReference"`
 }
 
+// +genclient
 // +kubebuilder:object:root=true
 // +kubebuilder:resource:scope=Namespaced,categories={fleet-networking}
 // +kubebuilder:subresource:status
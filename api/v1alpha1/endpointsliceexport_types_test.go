@@ -0,0 +1,60 @@
+/*
+Copyright (c) Microsoft Corporation.
+Licensed under the MIT license.
+*/
+
+package v1alpha1
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"k8s.io/utils/ptr"
+)
+
+// TestEndpointRoundTrip tests that the conditions of the terminating endpoints survive the serialization, and that
+// the ready endpoints are serialized as before, so that the members which do not know the conditions see no change.
+func TestEndpointRoundTrip(t *testing.T) {
+	tests := []struct {
+		name     string
+		endpoint Endpoint
+		wantJSON string
+	}{
+		{
+			name: "ready endpoint",
+			endpoint: Endpoint{
+				Addresses: []string{"1.2.3.4"},
+				NodeName:  ptr.To("node-1"),
+			},
+			wantJSON: `{"addresses":["1.2.3.4"],"nodeName":"node-1"}`,
+		},
+		{
+			name: "serving terminating endpoint",
+			endpoint: Endpoint{
+				Addresses:   []string{"1.2.3.4"},
+				Serving:     ptr.To(true),
+				Terminating: ptr.To(true),
+			},
+			wantJSON: `{"addresses":["1.2.3.4"],"serving":true,"terminating":true}`,
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			data, err := json.Marshal(tc.endpoint)
+			if err != nil {
+				t.Fatalf("json.Marshal() = %v, want no error", err)
+			}
+			if string(data) != tc.wantJSON {
+				t.Errorf("json.Marshal() = %s, want %s", data, tc.wantJSON)
+			}
+			var got Endpoint
+			if err := json.Unmarshal(data, &got); err != nil {
+				t.Fatalf("json.Unmarshal() = %v, want no error", err)
+			}
+			if diff := cmp.Diff(tc.endpoint, got); diff != "" {
+				t.Errorf("json.Unmarshal() mismatch (-want, +got):\n%s", diff)
+			}
+		})
+	}
+}
@@ -9,8 +9,33 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
+// EndpointSliceImportConditionType identifies a specific condition of an EndpointSliceImport.
+type EndpointSliceImportConditionType string
+
+const (
+	// EndpointSliceImportImported means that the endpoints of the EndpointSliceImport have been imported into the
+	// member cluster.
+	// Its condition status can be one of the following:
+	// - "True" means the endpoints have been imported.
+	// - "False" means the endpoints cannot be imported, e.g. the member cluster does not support their address type.
+	EndpointSliceImportImported EndpointSliceImportConditionType = "Imported"
+)
+
+// EndpointSliceImportStatus contains the current status of an EndpointSliceImport, as reported by the member cluster
+// which imports it.
+type EndpointSliceImportStatus struct {
+	// +optional
+	// +patchStrategy=merge
+	// +patchMergeKey=type
+	// +listType=map
+	// +listMapKey=type
+	Conditions []metav1.Condition `json:"conditions,omitempty" patchStrategy:"merge" patchMergeKey:"type"`
+}
+
+// +genclient
 // +kubebuilder:object:root=true
 // +kubebuilder:resource:scope=Namespaced,categories={fleet-networking}
+// +kubebuilder:subresource:status
 
 // EndpointSliceImport is a data transport type that hub cluster uses to distribute exported EndpointSlices
 // to member clusters.
@@ -20,6 +45,8 @@ type EndpointSliceImport struct {
 	metav1.ObjectMeta `json:"metadata,omitempty"`
 	// +kubebuilder:validation:Required
 	Spec EndpointSliceExportSpec `json:"spec"`
+	// +optional
+	Status EndpointSliceImportStatus `json:"status,omitempty"`
 }
 
 // +kubebuilder:object:root=true
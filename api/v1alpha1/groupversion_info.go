@@ -3,9 +3,6 @@ Copyright (c) Microsoft Corporation.
 Licensed under the MIT license.
 */
 
-// Package v1alpha1 contains API Schema definitions for the networking.fleet v1alpha1 API group.
-// +kubebuilder:object:generate=true
-// +groupName=networking.fleet.azure.com
 package v1alpha1
 
 import (
@@ -22,4 +19,12 @@ var (
 
 	// AddToScheme adds the types in this group-version to the given scheme.
 	AddToScheme = SchemeBuilder.AddToScheme
+
+	// SchemeGroupVersion is an alias of GroupVersion, which the generated typed client refers to.
+	SchemeGroupVersion = GroupVersion
 )
+
+// Resource takes an unqualified resource and returns a group qualified GroupResource; the generated listers refer to it.
+func Resource(resource string) schema.GroupResource {
+	return GroupVersion.WithResource(resource).GroupResource()
+}
@@ -17,10 +17,27 @@ type InternalServiceExportSpec struct {
 	// +listType=atomic
 	Ports []ServicePort `json:"ports"`
 	// The reference to the source Service.
+	// If the Service is exported under a different name, the name fields of the reference use the exported name.
 	// +kubebuilder:validation:Required
 	ServiceReference ExportedObjectReference `json:"serviceReference"`
+	// LocalServiceName is the name of the source Service in the member cluster; it is only set when the Service is
+	// exported under a different name, as specified by the serviceExport "networking.fleet.azure.com/exported-name"
+	// annotation.
+	// +optional
+	LocalServiceName string `json:"localServiceName,omitempty"`
 	// Type is the type of the Service in each cluster.
 	Type corev1.ServiceType `json:"type,omitempty"`
+	// Headless determines if the exported Service is headless, i.e. its cluster IP is None; the ServiceImport is of
+	// the Headless type when the Service its spec is resolved from is headless.
+	// +optional
+	Headless bool `json:"headless,omitempty"`
+	// AddressFamilies are the IP families of the exported Service, as specified by its spec.ipFamilies; a dual-stack
+	// Service has both IPv4 and IPv6, with its primary family first. The Service is assumed to be IPv4 only if the
+	// families are not reported.
+	// +optional
+	// +listType=atomic
+	// +kubebuilder:validation:MaxItems=2
+	AddressFamilies []corev1.IPFamily `json:"addressFamilies,omitempty"`
 	// IsDNSLabelConfigured determines if the Service has a DNS label configured.
 	// A valid DNS label should be configured when the public IP address of the Service is configured as an Azure Traffic
 	// Manager endpoint.
@@ -30,12 +47,44 @@ type InternalServiceExportSpec struct {
 	IsDNSLabelConfigured bool `json:"isDNSLabelConfigured,omitempty"`
 	// IsInternalLoadBalancer determines if the Service is an internal load balancer type.
 	IsInternalLoadBalancer bool `json:"isInternalLoadBalancer,omitempty"`
+	// IsLoadBalancerPending determines if the Service is a load balancer type whose IP address has not been
+	// provisioned by the cloud provider yet.
+	IsLoadBalancerPending bool `json:"isLoadBalancerPending,omitempty"`
+	// IsPublicIPExplicit determines if the public IP is specified explicitly by the serviceExport
+	// "networking.fleet.azure.com/azure-pip-resource-id" annotation, e.g. when the Service is exposed by a Gateway
+	// instead of a Load Balancer type Service, rather than looked up from the load balancer of the Service; the
+	// Service can be of any type then.
+	// +optional
+	IsPublicIPExplicit bool `json:"isPublicIPExplicit,omitempty"`
 	// PublicIPResourceID is the Azure Resource URI of public IP. This is only applicable for Load Balancer type Services.
 	PublicIPResourceID *string `json:"publicIPResourceID,omitempty"`
+	// ExternalTrafficPolicy is the external traffic policy of the Service. This is only applicable for Load Balancer
+	// type Services; with the Local policy, the load balancer serves the Service only on the nodes running its ready
+	// pods, as reported by the health check node port.
+	// +optional
+	ExternalTrafficPolicy corev1.ServiceExternalTrafficPolicy `json:"externalTrafficPolicy,omitempty"`
+	// HealthCheckNodePort is the node port serving the health checks of the Service when its external traffic policy
+	// is Local.
+	// +optional
+	HealthCheckNodePort int32 `json:"healthCheckNodePort,omitempty"`
 	// Weight is the weight of the ServiceExport.
 	// If unspecified, weight defaults to 1.
 	// The value is from serviceExport "networking.fleet.azure.com/weight" annotation and should be in the range [0, 1000].
 	Weight *int64 `json:"weight,omitempty"`
+	// ClusterRegion is the Azure region of the exporting cluster, if known.
+	// +optional
+	ClusterRegion string `json:"clusterRegion,omitempty"`
+	// ClusterVNetID is the resource ID of the virtual network of the exporting cluster, if known.
+	// +optional
+	ClusterVNetID string `json:"clusterVNetID,omitempty"`
+	// ConsumerPolicy restricts which member clusters can import the exported Service; it is copied from the
+	// spec of the ServiceExport.
+	// +optional
+	ConsumerPolicy *ConsumerPolicy `json:"consumerPolicy,omitempty"`
+	// Health is the health of the exported Service as reported by the health probe configured by the ServiceExport,
+	// if any; it is written by the member agent apart from the rest of the spec, whenever the health changes.
+	// +optional
+	Health *ExportHealth `json:"health,omitempty"`
 }
 
 // InternalServiceExportStatus contains the current status of an InternalServiceExport.
@@ -46,11 +95,56 @@ type InternalServiceExportStatus struct {
 	// +listType=map
 	// +listMapKey=type
 	Conditions []metav1.Condition `json:"conditions,omitempty" patchStrategy:"merge" patchMergeKey:"type"`
+
+	// LastHeartbeatTime is the last time the member cluster confirmed that the exported Service is up to date.
+	// It is refreshed periodically even when nothing has changed, so that the hub cluster can tell stale exports
+	// (e.g., from a member cluster whose agent is down) apart.
+	// +optional
+	LastHeartbeatTime *metav1.Time `json:"lastHeartbeatTime,omitempty"`
+
+	// ActiveImporterCount is the number of member clusters which import the exported Service. It is computed by the
+	// hub cluster and reported back to the ServiceExport in the member cluster.
+	// +optional
+	ActiveImporterCount int32 `json:"activeImporterCount,omitempty"`
+
+	// Resolution is the resolution of the exported Service across the fleet, as computed by the hub cluster. The
+	// member cluster uses it to preview the outcome of a change to the Service before the change is exported.
+	// +optional
+	Resolution *ExportResolution `json:"resolution,omitempty"`
+}
+
+// ExportResolution is the resolution of an exported Service across the fleet, i.e. the spec which the exports of the
+// Service must match and the member clusters exporting and importing it.
+type ExportResolution struct {
+	// Ports are the resolved ports of the Service.
+	// +optional
+	// +listType=atomic
+	Ports []ServicePort `json:"ports,omitempty"`
+
+	// IPFamilies are the resolved IP families of the Service.
+	// +optional
+	// +listType=atomic
+	IPFamilies []corev1.IPFamily `json:"ipFamilies,omitempty"`
+
+	// ExportingClusters are the IDs of the member clusters whose exports of the Service have been accepted.
+	// +optional
+	// +listType=set
+	ExportingClusters []string `json:"exportingClusters,omitempty"`
+
+	// ImporterClusters are the IDs of the member clusters which import the Service.
+	// +optional
+	// +listType=set
+	ImporterClusters []string `json:"importerClusters,omitempty"`
 }
 
+// +genclient
 // +kubebuilder:object:root=true
 // +kubebuilder:resource:scope=Namespaced,categories={fleet-networking},shortName=internalsvcexport
 // +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:JSONPath=`.spec.serviceReference.namespacedName`,name="Service",type=string
+// +kubebuilder:printcolumn:JSONPath=`.spec.serviceReference.clusterId`,name="Cluster",type=string
+// +kubebuilder:printcolumn:JSONPath=`.metadata.labels.networking\.fleet\.azure\.com/export-state`,name="State",type=string
+// +kubebuilder:printcolumn:JSONPath=`.metadata.creationTimestamp`,name="Age",type=date
 
 // InternalServiceExport is a data transport type that member clusters in the fleet use to upload the spec of
 // exported Service to the hub cluster.
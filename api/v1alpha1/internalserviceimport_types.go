@@ -9,6 +9,7 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
+// +genclient
 // +kubebuilder:object:root=true
 // +kubebuilder:resource:scope=Namespaced,categories={fleet-networking},shortName=internalsvcimport
 // +kubebuilder:subresource:status
@@ -16,6 +17,10 @@ import (
 // InternalServiceImport is used by the MCS controller to import the Service to a single cluster manually,
 // while ServiceImport is logical identifiers for a Service that exists in another cluster or that stretches
 // across multiple clusters and it will be automatically created in the hub cluster when exporting service.
+//
+// The spec (the import request) is owned by the member cluster which imports the Service and the status (the
+// resolution of the import) by the hub cluster; each side applies its own part with server-side apply under its
+// own field manager, and restores it when the other side, or anyone else, changes it.
 type InternalServiceImport struct {
 	metav1.TypeMeta `json:",inline"`
 	// +optional
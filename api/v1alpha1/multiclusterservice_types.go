@@ -8,14 +8,83 @@ package v1alpha1
 import (
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
 )
 
 // MultiClusterServiceSpec defines the desired state of MultiClusterService.
+// +kubebuilder:validation:XValidation:rule="!has(self.healthCheckNodePort) || (has(self.externalTrafficPolicy) && self.externalTrafficPolicy == 'Local')",message="healthCheckNodePort can only be set when externalTrafficPolicy is Local"
 type MultiClusterServiceSpec struct {
 	// ServiceImport is the reference to the Service with the same name exported in the member clusters.
 	ServiceImport ServiceImportRef `json:"serviceImport,omitempty"`
+
+	// Ports restricts the ports of the derived load balancer Service, and of the EndpointSlices imported for it, to
+	// the given subset of the ports of the ServiceImport. Each item is either the name or the number of a port.
+	// If not set, all the ports of the ServiceImport are exposed.
+	// +optional
+	// +listType=atomic
+	// +kubebuilder:validation:MaxItems=100
+	Ports []intstr.IntOrString `json:"ports,omitempty"`
+
+	// ExternalTrafficPolicy is the external traffic policy of the derived load balancer Service.
+	// Defaults to Cluster.
+	// +optional
+	// +kubebuilder:validation:Enum=Cluster;Local
+	ExternalTrafficPolicy corev1.ServiceExternalTrafficPolicy `json:"externalTrafficPolicy,omitempty"`
+
+	// InternalTrafficPolicy is the internal traffic policy of the derived load balancer Service. With the Local
+	// policy, the traffic from within the member cluster is only routed to the imported endpoints on the same node;
+	// it is not applied if none of the imported endpoints carries a node name, as the traffic would be dropped.
+	// Defaults to Cluster.
+	// +optional
+	// +kubebuilder:validation:Enum=Cluster;Local
+	InternalTrafficPolicy corev1.ServiceInternalTrafficPolicy `json:"internalTrafficPolicy,omitempty"`
+
+	// HealthCheckNodePort is the health check node port of the derived load balancer Service.
+	// It can only be set when ExternalTrafficPolicy is Local and cannot be changed once set; if not set, a port will be
+	// allocated automatically.
+	// +optional
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=65535
+	// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="healthCheckNodePort is immutable"
+	HealthCheckNodePort *int32 `json:"healthCheckNodePort,omitempty"`
+
+	// IdleTimeoutMinutes is the TCP idle timeout, in minutes, of the Azure load balancer created for the derived
+	// Service. If not set, the Azure default (4 minutes) applies.
+	// +optional
+	// +kubebuilder:validation:Minimum=4
+	// +kubebuilder:validation:Maximum=30
+	IdleTimeoutMinutes *int32 `json:"idleTimeoutMinutes,omitempty"`
+
+	// DeduplicateEndpoints imports only once the endpoints exported from multiple clusters with the same addresses
+	// and ports, e.g. the pods of a workload stretched across clusters over a shared virtual network, so that they do
+	// not receive a larger share of the traffic. It must not be set if the clusters reuse the same IP ranges for
+	// different workloads.
+	// Defaults to false.
+	// +optional
+	DeduplicateEndpoints bool `json:"deduplicateEndpoints,omitempty"`
+
+	// ExporterHealthPolicy specifies how the health of the service reported by the exporting clusters, whose
+	// ServiceExports configure a health probe, affects the import of their endpoints. With ExcludeUnhealthy, the
+	// endpoints of the clusters reporting the service as Unhealthy are not imported, as long as another exporting
+	// cluster does not; the clusters whose health is Unknown or not reported are always imported.
+	// Defaults to Ignore.
+	// +optional
+	// +kubebuilder:validation:Enum=Ignore;ExcludeUnhealthy
+	ExporterHealthPolicy ExporterHealthPolicy `json:"exporterHealthPolicy,omitempty"`
 }
 
+// ExporterHealthPolicy specifies how the health of the service reported by the exporting clusters affects the import
+// of their endpoints.
+type ExporterHealthPolicy string
+
+const (
+	// ExporterHealthPolicyIgnore imports the endpoints of all the exporting clusters regardless of their health.
+	ExporterHealthPolicyIgnore ExporterHealthPolicy = "Ignore"
+	// ExporterHealthPolicyExcludeUnhealthy leaves out the endpoints of the exporting clusters which report the service
+	// as Unhealthy, as long as another exporting cluster does not.
+	ExporterHealthPolicyExcludeUnhealthy ExporterHealthPolicy = "ExcludeUnhealthy"
+)
+
 // ServiceImportRef is the reference to the ServiceImport. To consume multi-cluster service, users are expected to use
 // ServiceImport. When mcs controller sees the MCS definition, the ServiceImport will be created in the importing
 // cluster to represent the multi-cluster service.
@@ -35,6 +104,27 @@ type MultiClusterServiceStatus struct {
 	// +optional
 	LoadBalancer corev1.LoadBalancerStatus `json:"loadBalancer,omitempty"`
 
+	// HealthCheckNodePort is the health check node port in effect on the derived load balancer Service, if any.
+	// +optional
+	HealthCheckNodePort int32 `json:"healthCheckNodePort,omitempty"`
+
+	// IdleTimeoutMinutes is the TCP idle timeout, in minutes, in effect on the Azure load balancer created for the
+	// derived Service.
+	// +optional
+	IdleTimeoutMinutes int32 `json:"idleTimeoutMinutes,omitempty"`
+
+	// FleetSystemNamespace is the reserved namespace of the member cluster in which the derived Service and the
+	// imported EndpointSlices are placed; it is unset when there is no derived Service.
+	// +optional
+	FleetSystemNamespace string `json:"fleetSystemNamespace,omitempty"`
+
+	// SkippedClusters are the exporting clusters whose endpoints are not imported, as they are unreachable from the
+	// member cluster per the network properties of the clusters, when the member agent is configured to skip
+	// unreachable clusters, or as they report the service as Unhealthy, per the exporter health policy.
+	// +optional
+	// +listType=set
+	SkippedClusters []string `json:"skippedClusters,omitempty"`
+
 	// Current service state
 	// +optional
 	// +patchMergeKey=type
@@ -52,8 +142,27 @@ const (
 	// multi-cluster service and its configurations have been recognized as valid by a mcs-controller.
 	// This will be false if the ServiceImport is not found in the hub cluster.
 	MultiClusterServiceValid MultiClusterServiceConditionType = "Valid"
+
+	// MultiClusterServicePortsFound means that all the ports requested by the Ports filter of this multi-cluster
+	// service exist in the ServiceImport.
+	// This will be false if any requested port is not found; the condition is absent if no filter is specified.
+	MultiClusterServicePortsFound MultiClusterServiceConditionType = "PortsFound"
+
+	// MultiClusterServiceInternalTrafficPolicyApplied means that the internal traffic policy of this multi-cluster
+	// service has been applied to the derived Service.
+	// This will be false if the Local policy is requested but none of the imported endpoints carries a node name;
+	// the condition is absent if no policy is specified.
+	MultiClusterServiceInternalTrafficPolicyApplied MultiClusterServiceConditionType = "InternalTrafficPolicyApplied"
+
+	// MultiClusterServiceIPFamiliesApplied means that the derived Service of this multi-cluster service has all the
+	// IP families of the ServiceImport.
+	// This will be false if the ServiceImport is dual-stack but the cluster does not support dual-stack Services, and
+	// the derived Service falls back to the primary IP family; the condition is absent if the ServiceImport is not
+	// dual-stack.
+	MultiClusterServiceIPFamiliesApplied MultiClusterServiceConditionType = "IPFamiliesApplied"
 )
 
+// +genclient
 // +kubebuilder:object:root=true
 // +kubebuilder:resource:scope=Namespaced,categories={fleet-networking},shortName=mcs
 // +kubebuilder:subresource:status
@@ -21,8 +21,146 @@ const (
 	// field(s) under contention, which cluster won, and why.
 	// Users should not expect detailed per-cluster information in the conflict message.
 	ServiceExportConflict ServiceExportConditionType = "Conflict"
+	// ServiceExportEndpointsTruncated means that the Service has more ready endpoints than the member cluster is
+	// allowed to export per Service, and only a subset of them has been exported.
+	// When "True", the condition message contains the number of exported endpoints and the total number of ready
+	// endpoints.
+	ServiceExportEndpointsTruncated ServiceExportConditionType = "EndpointsTruncated"
+	// ServiceExportNoReadyBackends means that the Service is valid for export, but none of its EndpointSlices has a
+	// ready endpoint, e.g. its selector matches no (ready) pods. The condition is informational only and does not
+	// block the export.
+	// When "True", the condition message contains the selector of the Service.
+	ServiceExportNoReadyBackends ServiceExportConditionType = "NoReadyBackends"
+	// ServiceExportLoadBalancerPending means that the Service is of the LoadBalancer type, but the cloud provider has
+	// not provisioned an IP address for its load balancer yet. The condition is informational only and does not block
+	// the export; the Service cannot be added as an Azure Traffic Manager endpoint until the IP is provisioned.
+	// When "True", the condition message contains the type of the load balancer.
+	ServiceExportLoadBalancerPending ServiceExportConditionType = "LoadBalancerPending"
+	// ServiceExportPublicIPValid means that the Azure public IP address specified by the
+	// "networking.fleet.azure.com/azure-pip-resource-id" annotation of the ServiceExport exists and has a DNS label
+	// configured, so that the Service can be added as an Azure Traffic Manager endpoint. It is only reported when the
+	// annotation is set.
+	// When "False", the condition message contains the reason why the public IP address is rejected.
+	ServiceExportPublicIPValid ServiceExportConditionType = "PublicIPValid"
+	// ServiceExportDNSLabelConfigured means that the member agent, which manages the DNS labels of the Azure public IP
+	// addresses (see the --manage-pip-dns-label flag), cannot set a DNS label on the public IP address of the load
+	// balancer of the Service, e.g. because it lacks the permission to update the public IP address. The Service is
+	// still exported, but it cannot be added as an Azure Traffic Manager endpoint until a DNS label is configured. It is
+	// only reported when the DNS label cannot be set, and removed once it is configured.
+	// When "False", the condition message contains the reason why the DNS label cannot be set.
+	ServiceExportDNSLabelConfigured ServiceExportConditionType = "DNSLabelConfigured"
+	// ServiceExportHostNetworkEndpointsExcluded means that some endpoints of the Service are backed by pods in the host
+	// network, whose addresses are the IPs of their nodes, and have not been exported. Such endpoints are exported only
+	// if the ServiceExport has the "networking.fleet.azure.com/allow-hostnetwork-endpoints" annotation set to "true",
+	// in which case the condition is removed.
+	// When "True", the condition message contains the annotation to set for exporting the endpoints.
+	ServiceExportHostNetworkEndpointsExcluded ServiceExportConditionType = "HostNetworkEndpointsExcluded"
 )
 
+// ServiceExportSpec specifies how a Service is exported.
+type ServiceExportSpec struct {
+	// ConsumerPolicy restricts which member clusters can import the exported Service. The member clusters outside the
+	// policy see the Service as if it were not exported from this cluster.
+	// If unspecified, the Service can be imported by any member cluster.
+	// +optional
+	ConsumerPolicy *ConsumerPolicy `json:"consumerPolicy,omitempty"`
+
+	// HealthProbe configures an application-level health probe, which the member agent sends to the Service
+	// periodically; its result is published to the importing clusters, e.g. so that they prefer the healthy exporting
+	// clusters in an active/passive failover. If unspecified, the health of the Service is not reported.
+	// +optional
+	HealthProbe *HealthProbe `json:"healthProbe,omitempty"`
+}
+
+// HealthProbe specifies an HTTP GET request the member agent sends to the cluster IP of the exported Service.
+// The Service is Healthy when the response has a 2xx status code, and Unhealthy on any other status code; a probe
+// which gets no response, e.g. because it times out or the connection is refused, leaves the health Unknown.
+// The response may carry a score of the health, e.g. derived from the replica lag of a database, in the
+// X-Fleet-Health-Score header, as an integer from 0 to 100; the score defaults to 100 when the Service is Healthy and
+// to 0 otherwise.
+type HealthProbe struct {
+	// Path is the path of the HTTP GET request.
+	// Defaults to /.
+	// +optional
+	// +kubebuilder:validation:Pattern=`^/`
+	// +kubebuilder:validation:MaxLength=1024
+	Path string `json:"path,omitempty"`
+
+	// Port is the port of the Service the request is sent to.
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=65535
+	Port int32 `json:"port"`
+
+	// PeriodSeconds is how often, in seconds, the Service is probed.
+	// Defaults to 30 seconds.
+	// +optional
+	// +kubebuilder:validation:Minimum=5
+	// +kubebuilder:validation:Maximum=3600
+	PeriodSeconds int32 `json:"periodSeconds,omitempty"`
+
+	// TimeoutSeconds is the number of seconds after which the probe times out.
+	// Defaults to 5 seconds.
+	// +optional
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=60
+	TimeoutSeconds int32 `json:"timeoutSeconds,omitempty"`
+}
+
+// ExportHealthState is the health of an exported Service as reported by its health probe.
+type ExportHealthState string
+
+const (
+	// ExportHealthy means that the last health probe of the Service got a response with a 2xx status code.
+	ExportHealthy ExportHealthState = "Healthy"
+	// ExportUnhealthy means that the last health probe of the Service got a response with another status code.
+	ExportUnhealthy ExportHealthState = "Unhealthy"
+	// ExportHealthUnknown means that the last health probe of the Service got no response, e.g. because it timed
+	// out; the health of the Service cannot be told apart from the reachability of the Service within its cluster.
+	ExportHealthUnknown ExportHealthState = "Unknown"
+)
+
+// ExportHealth is the health of an exported Service as reported by its health probe in the exporting cluster.
+type ExportHealth struct {
+	// State is the result of the last health probe of the Service.
+	// +kubebuilder:validation:Enum=Healthy;Unhealthy;Unknown
+	State ExportHealthState `json:"state"`
+
+	// Score is the score of the health, from 0 to 100, as reported by the last health probe which got a response.
+	// +optional
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=100
+	Score int32 `json:"score,omitempty"`
+
+	// LastTransitionTime is the last time the state changed.
+	// +optional
+	LastTransitionTime metav1.Time `json:"lastTransitionTime,omitempty"`
+
+	// Message is a human readable message about the last health probe, e.g. the status code of the response.
+	// +optional
+	Message string `json:"message,omitempty"`
+}
+
+// ConsumerPolicy specifies the member clusters which are allowed to import an exported Service.
+// A member cluster listed in the deniedClusters is never allowed. Otherwise, if neither allowedClusters nor
+// clusterSelector is specified, every member cluster is allowed; if any of them is specified, a member cluster is
+// allowed when it is listed in the allowedClusters or its MemberCluster labels match the clusterSelector.
+type ConsumerPolicy struct {
+	// AllowedClusters is the list of the IDs of the member clusters allowed to import the Service.
+	// +optional
+	// +listType=set
+	AllowedClusters []string `json:"allowedClusters,omitempty"`
+
+	// DeniedClusters is the list of the IDs of the member clusters not allowed to import the Service.
+	// +optional
+	// +listType=set
+	DeniedClusters []string `json:"deniedClusters,omitempty"`
+
+	// ClusterSelector selects the member clusters allowed to import the Service by the labels of their
+	// MemberCluster objects in the hub cluster.
+	// +optional
+	ClusterSelector *metav1.LabelSelector `json:"clusterSelector,omitempty"`
+}
+
 // ServiceExportStatus contains the current status of an export.
 type ServiceExportStatus struct {
 	// +optional
@@ -31,8 +169,60 @@ type ServiceExportStatus struct {
 	// +listType=map
 	// +listMapKey=type
 	Conditions []metav1.Condition `json:"conditions,omitempty" patchStrategy:"merge" patchMergeKey:"type"`
+
+	// ActiveImporterCount is the number of member clusters which import the exported Service, as reported by the
+	// hub cluster.
+	// +optional
+	ActiveImporterCount int32 `json:"activeImporterCount,omitempty"`
+
+	// Preview is the preview of the export of the pending changes to the Service, computed while the ServiceExport
+	// has the "networking.fleet.azure.com/preview" annotation set to "true".
+	// +optional
+	Preview *ServiceExportPreview `json:"preview,omitempty"`
 }
 
+// ExportConflictOutcome is the expected outcome of the conflict resolution of an export in the hub cluster.
+type ExportConflictOutcome string
+
+const (
+	// ExportConflictNone means that the export is expected to be accepted.
+	ExportConflictNone ExportConflictOutcome = "NoConflict"
+	// ExportConflictConflicted means that the export is expected to be in conflict with the exports of the other
+	// member clusters, and the endpoints of the member cluster to be withdrawn from the importing member clusters.
+	ExportConflictConflicted ExportConflictOutcome = "Conflict"
+	// ExportConflictUnknown means that the outcome cannot be told, as the hub cluster has not reported the resolution
+	// of the Service yet.
+	ExportConflictUnknown ExportConflictOutcome = "Unknown"
+)
+
+// ServiceExportPreview is the preview of the export of the pending changes to a Service; nothing is written to the
+// hub cluster while the preview is on.
+type ServiceExportPreview struct {
+	// ServiceGeneration is the generation of the Service the preview has been computed from.
+	// +optional
+	ServiceGeneration int64 `json:"serviceGeneration,omitempty"`
+
+	// Diff is the change the export of the Service in the hub cluster would undergo, as a diff of its spec from the
+	// current export (-) to the previewed one (+); it is empty if the export would not change.
+	// +optional
+	Diff string `json:"diff,omitempty"`
+
+	// Conflict is the expected outcome of the conflict resolution of the previewed export in the hub cluster.
+	// +kubebuilder:validation:Enum=NoConflict;Conflict;Unknown
+	Conflict ExportConflictOutcome `json:"conflict"`
+
+	// Message is a human readable message about the expected outcome of the conflict resolution.
+	// +optional
+	Message string `json:"message,omitempty"`
+
+	// ImporterClusters are the IDs of the member clusters which import the Service, as last reported by the hub
+	// cluster, and which would be affected by the change.
+	// +optional
+	// +listType=set
+	ImporterClusters []string `json:"importerClusters,omitempty"`
+}
+
+// +genclient
 // +kubebuilder:object:root=true
 // +kubebuilder:resource:scope=Namespaced,categories={fleet-networking},shortName=svcexport
 // +kubebuilder:subresource:status
@@ -48,12 +238,17 @@ type ServiceExportStatus struct {
 // If unspecified, weight defaults to 1.
 // The value should be in the range [0, 1000].
 // Any invalid value will default to default value.
+// The annotation "networking.fleet.azure.com/exported-name" specifies the name under which the service is exported
+// to the fleet; services exported under the same name are aggregated into one serviceImport, and their specs must
+// match. The value must be a valid DNS-1035 label. If unspecified, the service is exported under its own name.
 // +kubebuilder:validation:XValidation:rule="size(self.metadata.name) < 64",message="metadata.name max length is 63"
 type ServiceExport struct {
 	metav1.TypeMeta `json:",inline"`
 	// +optional
 	metav1.ObjectMeta `json:"metadata,omitempty"`
 	// +optional
+	Spec ServiceExportSpec `json:"spec,omitempty"`
+	// +optional
 	Status ServiceExportStatus `json:"status,omitempty"`
 }
 
@@ -11,6 +11,7 @@ import (
 	"k8s.io/apimachinery/pkg/util/intstr"
 )
 
+// +genclient
 // +kubebuilder:object:root=true
 // +kubebuilder:resource:scope=Namespaced,categories={fleet-networking},shortName=svcimport
 // +kubebuilder:subresource:status
@@ -92,6 +93,7 @@ type ServiceImportStatus struct {
 	IPs []string `json:"ips,omitempty"`
 	// type defines the type of this service.
 	// Must be ClusterSetIP or Headless.
+	// It is Headless when the exported service the spec is resolved from is headless.
 	// +kubebuilder:validation:Enum=ClusterSetIP;Headless
 	// +optional
 	Type ServiceImportType `json:"type,omitempty"`
@@ -111,19 +113,135 @@ type ServiceImportStatus struct {
 	// +optional
 	Ports []ServicePort `json:"ports,omitempty"`
 
+	// ipFamilies are the IP families of the exported services, with the primary family first; the services exported
+	// from all the clusters must have the same families. The derived services request the same families, and fall
+	// back to the primary family when the importing cluster does not support dual-stack.
+	// +listType=atomic
+	// +kubebuilder:validation:MaxItems=2
+	// +optional
+	IPFamilies []corev1.IPFamily `json:"ipFamilies,omitempty"`
+
 	// clusters is the list of exporting clusters from which this service was derived.
 	// +optional
 	// +patchStrategy=merge
 	// +patchMergeKey=cluster
 	// +listType=map
 	// +listMapKey=cluster
-	Clusters []ClusterStatus `json:"clusters,omitempty"`
+	Clusters []ClusterStatus `json:"clusters,omitempty" patchStrategy:"merge" patchMergeKey:"cluster"`
+
+	// missingClusters is the list of clusters which are expected to export the service, as specified by the
+	// networking.fleet.azure.com/expected-exporters annotation, but are not part of the clusters list.
+	// +optional
+	// +listType=map
+	// +listMapKey=cluster
+	MissingClusters []MissingClusterStatus `json:"missingClusters,omitempty"`
+
+	// consumerPolicies is the list of the consumer policies of the exporting clusters which restrict the member
+	// clusters allowed to import the service. It is only set in the hub cluster; the exporting clusters whose
+	// policies do not allow a member cluster are left out of the clusters list imported by that member cluster.
+	// +optional
+	// +listType=map
+	// +listMapKey=cluster
+	ConsumerPolicies []ClusterConsumerPolicy `json:"consumerPolicies,omitempty"`
+
+	// clusterWeights is the list of the weights of the services exported from the clusters which set one; the weight
+	// of the service exported from the other clusters defaults to 1. It is only set in the hub cluster, which
+	// annotates the EndpointSliceImports with the weight of their exporting cluster.
+	// +optional
+	// +listType=map
+	// +listMapKey=cluster
+	ClusterWeights []ClusterWeight `json:"clusterWeights,omitempty"`
+
+	// clusterHealth is the list of the health of the service as reported by the exporting clusters whose
+	// ServiceExports configure a health probe. The endpoints of the clusters reporting the service as Unhealthy are
+	// not imported by the MultiClusterServices with the ExcludeUnhealthy exporter health policy.
+	// +optional
+	// +listType=map
+	// +listMapKey=cluster
+	ClusterHealth []ClusterExportHealth `json:"clusterHealth,omitempty"`
+
+	// dnsNames are the DNS names under which the service can be discovered across the fleet, for display by
+	// discovery tooling: the clusterset name (<name>.<namespace>.svc.clusterset.local), followed by the FQDNs of the
+	// programmed Azure Traffic Manager profiles the service is a backend of. It is only set in the hub cluster.
+	// +listType=atomic
+	// +optional
+	DNSNames []string `json:"dnsNames,omitempty"`
+
+	// importerClusters are the sorted IDs of the member clusters which currently import the service, i.e. whose hub
+	// namespaces hold an InternalServiceImport of it. Their number is mirrored to the status of the ServiceExports of
+	// the exporting clusters as activeImporterCount, e.g. to scale the exported workloads to zero in the clusters
+	// whose service nobody consumes. It is only set in the hub cluster.
+	// +listType=set
+	// +optional
+	ImporterClusters []string `json:"importerClusters,omitempty"`
 }
 
 // ClusterStatus contains service configuration mapped to a specific source cluster.
 type ClusterStatus struct {
 	// cluster is the name of the exporting cluster. Must be a valid RFC-1123 DNS label.
 	Cluster string `json:"cluster"`
+
+	// region is the Azure region of the exporting cluster, if known.
+	// +optional
+	Region string `json:"region,omitempty"`
+
+	// vnetID is the resource ID of the virtual network of the exporting cluster, if known.
+	// +optional
+	VNetID string `json:"vnetID,omitempty"`
+}
+
+// ClusterConsumerPolicy is the consumer policy of the service exported from a specific source cluster.
+type ClusterConsumerPolicy struct {
+	// cluster is the name of the exporting cluster. Must be a valid RFC-1123 DNS label.
+	Cluster string `json:"cluster"`
+
+	// policy restricts which member clusters can import the service exported from the cluster.
+	Policy ConsumerPolicy `json:"policy"`
+}
+
+// ClusterWeight is the weight of the service exported from a specific source cluster.
+type ClusterWeight struct {
+	// cluster is the name of the exporting cluster. Must be a valid RFC-1123 DNS label.
+	Cluster string `json:"cluster"`
+
+	// weight is the weight of the service exported from the cluster.
+	Weight int64 `json:"weight"`
+}
+
+// ClusterExportHealth is the health of the service exported from a specific source cluster.
+type ClusterExportHealth struct {
+	// cluster is the name of the exporting cluster. Must be a valid RFC-1123 DNS label.
+	Cluster string `json:"cluster"`
+
+	// health is the health of the service as reported by the health probe in the exporting cluster.
+	Health ExportHealth `json:"health"`
+}
+
+// MissingClusterReason explains why an expected exporting cluster is missing from a ServiceImport.
+type MissingClusterReason string
+
+const (
+	// MissingClusterReasonExportNotFound means that no InternalServiceExport of the service is found from the cluster.
+	MissingClusterReasonExportNotFound MissingClusterReason = "ExportNotFound"
+	// MissingClusterReasonExportConflicted means that the service exported from the cluster is in conflict with the
+	// resolved spec of the ServiceImport.
+	MissingClusterReasonExportConflicted MissingClusterReason = "ExportConflicted"
+	// MissingClusterReasonExportInvalid means that the service exported from the cluster is invalid, e.g., it exports
+	// no port, and hence cannot be imported.
+	MissingClusterReasonExportInvalid MissingClusterReason = "ExportInvalid"
+	// MissingClusterReasonExportPending means that the InternalServiceExport of the service is found from the cluster
+	// but has not been accepted yet, or is being deleted.
+	MissingClusterReasonExportPending MissingClusterReason = "ExportPending"
+)
+
+// MissingClusterStatus describes an expected exporting cluster which is missing from a ServiceImport.
+type MissingClusterStatus struct {
+	// cluster is the name of the expected exporting cluster.
+	Cluster string `json:"cluster"`
+
+	// reason is a brief CamelCase string that describes why the cluster is missing.
+	// +kubebuilder:validation:Enum=ExportNotFound;ExportConflicted;ExportInvalid;ExportPending
+	Reason MissingClusterReason `json:"reason"`
 }
 
 // +kubebuilder:object:root=true
@@ -0,0 +1,79 @@
+/*
+Copyright (c) Microsoft Corporation.
+Licensed under the MIT license.
+*/
+
+package v1alpha1
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+func TestToServicePort(t *testing.T) {
+	appProtocol := "app-protocol"
+	tests := []struct {
+		name string
+		port ServicePort
+		want corev1.ServicePort
+	}{
+		{
+			name: "TCP port",
+			port: ServicePort{
+				Name:        "web",
+				Protocol:    corev1.ProtocolTCP,
+				AppProtocol: &appProtocol,
+				Port:        80,
+				TargetPort:  intstr.FromInt(8080),
+			},
+			want: corev1.ServicePort{
+				Name:        "web",
+				Protocol:    corev1.ProtocolTCP,
+				AppProtocol: &appProtocol,
+				Port:        80,
+				TargetPort:  intstr.FromInt(8080),
+			},
+		},
+		{
+			name: "UDP port",
+			port: ServicePort{
+				Name:       "game",
+				Protocol:   corev1.ProtocolUDP,
+				Port:       7777,
+				TargetPort: intstr.FromInt(7777),
+			},
+			want: corev1.ServicePort{
+				Name:       "game",
+				Protocol:   corev1.ProtocolUDP,
+				Port:       7777,
+				TargetPort: intstr.FromInt(7777),
+			},
+		},
+		{
+			name: "SCTP port",
+			port: ServicePort{
+				Name:       "signal",
+				Protocol:   corev1.ProtocolSCTP,
+				Port:       9999,
+				TargetPort: intstr.FromString("signal"),
+			},
+			want: corev1.ServicePort{
+				Name:       "signal",
+				Protocol:   corev1.ProtocolSCTP,
+				Port:       9999,
+				TargetPort: intstr.FromString("signal"),
+			},
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got := tc.port.ToServicePort()
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("ToServicePort() mismatch (-want, +got):\n%s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,264 @@
+/*
+Copyright (c) Microsoft Corporation.
+Licensed under the MIT license.
+*/
+
+package v1alpha1
+
+import (
+	"fmt"
+
+	"sigs.k8s.io/controller-runtime/pkg/conversion"
+
+	"go.goms.io/fleet-networking/api/v1beta1"
+)
+
+// The v1beta1 traffic manager APIs are the conversion hub (and the storage version); the v1alpha1 APIs are converted
+// to and from them.
+//
+// The two versions share the same schema today, so every field is converted one to one. When a field is added to only
+// one of the versions, it must be preserved in an annotation on the other version so that a round trip through the
+// hub does not lose it.
+
+var (
+	_ conversion.Convertible = &TrafficManagerProfile{}
+	_ conversion.Convertible = &TrafficManagerBackend{}
+)
+
+// ConvertTo converts this TrafficManagerProfile to the hub (v1beta1) version.
+func (src *TrafficManagerProfile) ConvertTo(dstRaw conversion.Hub) error {
+	dst, ok := dstRaw.(*v1beta1.TrafficManagerProfile)
+	if !ok {
+		return fmt.Errorf("unexpected type %T, want *v1beta1.TrafficManagerProfile", dstRaw)
+	}
+	in := src.DeepCopy()
+
+	dst.ObjectMeta = in.ObjectMeta
+
+	dst.Spec.ResourceGroup = in.Spec.ResourceGroup
+	dst.Spec.MonitorConfig = nil
+	if mc := in.Spec.MonitorConfig; mc != nil {
+		dst.Spec.MonitorConfig = &v1beta1.MonitorConfig{
+			IntervalInSeconds:         mc.IntervalInSeconds,
+			Path:                      mc.Path,
+			Port:                      mc.Port,
+			InheritPortFromBackend:    mc.InheritPortFromBackend,
+			TimeoutInSeconds:          mc.TimeoutInSeconds,
+			ToleratedNumberOfFailures: mc.ToleratedNumberOfFailures,
+		}
+		if mc.Protocol != nil {
+			protocol := v1beta1.TrafficManagerMonitorProtocol(*mc.Protocol)
+			dst.Spec.MonitorConfig.Protocol = &protocol
+		}
+	}
+
+	dst.Status.DNSName = in.Status.DNSName
+	dst.Status.ResourceID = in.Status.ResourceID
+	dst.Status.Conditions = in.Status.Conditions
+	dst.Status.LastSyncedTime = in.Status.LastSyncedTime
+	dst.Status.MonitorStatus = v1beta1.TrafficManagerProfileMonitorStatus(in.Status.MonitorStatus)
+	return nil
+}
+
+// ConvertFrom converts from the hub (v1beta1) version to this TrafficManagerProfile.
+func (dst *TrafficManagerProfile) ConvertFrom(srcRaw conversion.Hub) error {
+	src, ok := srcRaw.(*v1beta1.TrafficManagerProfile)
+	if !ok {
+		return fmt.Errorf("unexpected type %T, want *v1beta1.TrafficManagerProfile", srcRaw)
+	}
+	in := src.DeepCopy()
+
+	dst.ObjectMeta = in.ObjectMeta
+
+	dst.Spec.ResourceGroup = in.Spec.ResourceGroup
+	dst.Spec.MonitorConfig = nil
+	if mc := in.Spec.MonitorConfig; mc != nil {
+		dst.Spec.MonitorConfig = &MonitorConfig{
+			IntervalInSeconds:         mc.IntervalInSeconds,
+			Path:                      mc.Path,
+			Port:                      mc.Port,
+			InheritPortFromBackend:    mc.InheritPortFromBackend,
+			TimeoutInSeconds:          mc.TimeoutInSeconds,
+			ToleratedNumberOfFailures: mc.ToleratedNumberOfFailures,
+		}
+		if mc.Protocol != nil {
+			protocol := TrafficManagerMonitorProtocol(*mc.Protocol)
+			dst.Spec.MonitorConfig.Protocol = &protocol
+		}
+	}
+
+	dst.Status.DNSName = in.Status.DNSName
+	dst.Status.ResourceID = in.Status.ResourceID
+	dst.Status.Conditions = in.Status.Conditions
+	dst.Status.LastSyncedTime = in.Status.LastSyncedTime
+	dst.Status.MonitorStatus = TrafficManagerProfileMonitorStatus(in.Status.MonitorStatus)
+	return nil
+}
+
+// ConvertTo converts this TrafficManagerBackend to the hub (v1beta1) version.
+func (src *TrafficManagerBackend) ConvertTo(dstRaw conversion.Hub) error {
+	dst, ok := dstRaw.(*v1beta1.TrafficManagerBackend)
+	if !ok {
+		return fmt.Errorf("unexpected type %T, want *v1beta1.TrafficManagerBackend", dstRaw)
+	}
+	in := src.DeepCopy()
+
+	dst.ObjectMeta = in.ObjectMeta
+
+	dst.Spec.Profile = v1beta1.TrafficManagerProfileRef{Name: in.Spec.Profile.Name}
+	dst.Spec.Backend = v1beta1.TrafficManagerBackendRef{Name: in.Spec.Backend.Name}
+	dst.Spec.Weight = in.Spec.Weight
+	dst.Spec.CustomHeaders = convertCustomHeadersToHub(in.Spec.CustomHeaders)
+	dst.Spec.SubnetOverrides = nil
+	if in.Spec.SubnetOverrides != nil {
+		dst.Spec.SubnetOverrides = make([]v1beta1.TrafficManagerSubnetOverride, len(in.Spec.SubnetOverrides))
+		for i, override := range in.Spec.SubnetOverrides {
+			dst.Spec.SubnetOverrides[i] = v1beta1.TrafficManagerSubnetOverride{
+				Cluster: override.Cluster,
+				Subnets: override.Subnets,
+			}
+		}
+	}
+	dst.Spec.Priorities = nil
+	if in.Spec.Priorities != nil {
+		dst.Spec.Priorities = make([]v1beta1.TrafficManagerClusterPriority, len(in.Spec.Priorities))
+		for i, priority := range in.Spec.Priorities {
+			dst.Spec.Priorities[i] = v1beta1.TrafficManagerClusterPriority{
+				Cluster:  priority.Cluster,
+				Priority: priority.Priority,
+			}
+		}
+	}
+
+	dst.Status.Endpoints = nil
+	if in.Status.Endpoints != nil {
+		dst.Status.Endpoints = make([]v1beta1.TrafficManagerEndpointStatus, len(in.Status.Endpoints))
+		for i, endpoint := range in.Status.Endpoints {
+			dst.Status.Endpoints[i] = v1beta1.TrafficManagerEndpointStatus{
+				Name:          endpoint.Name,
+				ResourceID:    endpoint.ResourceID,
+				Weight:        endpoint.Weight,
+				Target:        endpoint.Target,
+				CustomHeaders: convertCustomHeadersToHub(endpoint.CustomHeaders),
+				Subnets:       endpoint.Subnets,
+				Priority:      endpoint.Priority,
+			}
+			if endpoint.From != nil {
+				dst.Status.Endpoints[i].From = &v1beta1.FromCluster{
+					ClusterStatus: v1beta1.ClusterStatus{Cluster: endpoint.From.Cluster, Region: endpoint.From.Region, VNetID: endpoint.From.VNetID},
+					Weight:        endpoint.From.Weight,
+				}
+			}
+		}
+	}
+	dst.Status.Conditions = in.Status.Conditions
+	dst.Status.LastSyncedTime = in.Status.LastSyncedTime
+	dst.Status.InvalidClusters = nil
+	if in.Status.InvalidClusters != nil {
+		dst.Status.InvalidClusters = make([]v1beta1.TrafficManagerInvalidCluster, len(in.Status.InvalidClusters))
+		for i, invalid := range in.Status.InvalidClusters {
+			dst.Status.InvalidClusters[i] = v1beta1.TrafficManagerInvalidCluster{
+				Cluster: invalid.Cluster,
+				Reason:  v1beta1.TrafficManagerInvalidClusterReason(invalid.Reason),
+				Message: invalid.Message,
+			}
+		}
+	}
+	dst.Status.TotalInvalid = in.Status.TotalInvalid
+	return nil
+}
+
+// ConvertFrom converts from the hub (v1beta1) version to this TrafficManagerBackend.
+func (dst *TrafficManagerBackend) ConvertFrom(srcRaw conversion.Hub) error {
+	src, ok := srcRaw.(*v1beta1.TrafficManagerBackend)
+	if !ok {
+		return fmt.Errorf("unexpected type %T, want *v1beta1.TrafficManagerBackend", srcRaw)
+	}
+	in := src.DeepCopy()
+
+	dst.ObjectMeta = in.ObjectMeta
+
+	dst.Spec.Profile = TrafficManagerProfileRef{Name: in.Spec.Profile.Name}
+	dst.Spec.Backend = TrafficManagerBackendRef{Name: in.Spec.Backend.Name}
+	dst.Spec.Weight = in.Spec.Weight
+	dst.Spec.CustomHeaders = convertCustomHeadersFromHub(in.Spec.CustomHeaders)
+	dst.Spec.SubnetOverrides = nil
+	if in.Spec.SubnetOverrides != nil {
+		dst.Spec.SubnetOverrides = make([]TrafficManagerSubnetOverride, len(in.Spec.SubnetOverrides))
+		for i, override := range in.Spec.SubnetOverrides {
+			dst.Spec.SubnetOverrides[i] = TrafficManagerSubnetOverride{
+				Cluster: override.Cluster,
+				Subnets: override.Subnets,
+			}
+		}
+	}
+	dst.Spec.Priorities = nil
+	if in.Spec.Priorities != nil {
+		dst.Spec.Priorities = make([]TrafficManagerClusterPriority, len(in.Spec.Priorities))
+		for i, priority := range in.Spec.Priorities {
+			dst.Spec.Priorities[i] = TrafficManagerClusterPriority{
+				Cluster:  priority.Cluster,
+				Priority: priority.Priority,
+			}
+		}
+	}
+
+	dst.Status.Endpoints = nil
+	if in.Status.Endpoints != nil {
+		dst.Status.Endpoints = make([]TrafficManagerEndpointStatus, len(in.Status.Endpoints))
+		for i, endpoint := range in.Status.Endpoints {
+			dst.Status.Endpoints[i] = TrafficManagerEndpointStatus{
+				Name:          endpoint.Name,
+				ResourceID:    endpoint.ResourceID,
+				Weight:        endpoint.Weight,
+				Target:        endpoint.Target,
+				CustomHeaders: convertCustomHeadersFromHub(endpoint.CustomHeaders),
+				Subnets:       endpoint.Subnets,
+				Priority:      endpoint.Priority,
+			}
+			if endpoint.From != nil {
+				dst.Status.Endpoints[i].From = &FromCluster{
+					ClusterStatus: ClusterStatus{Cluster: endpoint.From.Cluster, Region: endpoint.From.Region, VNetID: endpoint.From.VNetID},
+					Weight:        endpoint.From.Weight,
+				}
+			}
+		}
+	}
+	dst.Status.Conditions = in.Status.Conditions
+	dst.Status.LastSyncedTime = in.Status.LastSyncedTime
+	dst.Status.InvalidClusters = nil
+	if in.Status.InvalidClusters != nil {
+		dst.Status.InvalidClusters = make([]TrafficManagerInvalidCluster, len(in.Status.InvalidClusters))
+		for i, invalid := range in.Status.InvalidClusters {
+			dst.Status.InvalidClusters[i] = TrafficManagerInvalidCluster{
+				Cluster: invalid.Cluster,
+				Reason:  TrafficManagerInvalidClusterReason(invalid.Reason),
+				Message: invalid.Message,
+			}
+		}
+	}
+	dst.Status.TotalInvalid = in.Status.TotalInvalid
+	return nil
+}
+
+func convertCustomHeadersToHub(headers []TrafficManagerEndpointCustomHeader) []v1beta1.TrafficManagerEndpointCustomHeader {
+	if headers == nil {
+		return nil
+	}
+	res := make([]v1beta1.TrafficManagerEndpointCustomHeader, len(headers))
+	for i, header := range headers {
+		res[i] = v1beta1.TrafficManagerEndpointCustomHeader{Name: header.Name, Value: header.Value}
+	}
+	return res
+}
+
+func convertCustomHeadersFromHub(headers []v1beta1.TrafficManagerEndpointCustomHeader) []TrafficManagerEndpointCustomHeader {
+	if headers == nil {
+		return nil
+	}
+	res := make([]TrafficManagerEndpointCustomHeader, len(headers))
+	for i, header := range headers {
+		res[i] = TrafficManagerEndpointCustomHeader{Name: header.Name, Value: header.Value}
+	}
+	return res
+}
@@ -0,0 +1,96 @@
+/*
+Copyright (c) Microsoft Corporation.
+Licensed under the MIT license.
+*/
+
+package v1alpha1
+
+import (
+	"math/rand"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"k8s.io/apimachinery/pkg/api/apitesting/fuzzer"
+	metafuzzer "k8s.io/apimachinery/pkg/apis/meta/fuzzer"
+	"k8s.io/apimachinery/pkg/runtime"
+	runtimeserializer "k8s.io/apimachinery/pkg/runtime/serializer"
+	"sigs.k8s.io/controller-runtime/pkg/conversion"
+
+	"go.goms.io/fleet-networking/api/v1beta1"
+)
+
+const (
+	fuzzIterations = 1000
+)
+
+func TestTrafficManagerConversionRoundTrip(t *testing.T) {
+	scheme := runtime.NewScheme()
+	if err := AddToScheme(scheme); err != nil {
+		t.Fatalf("AddToScheme() got error %v, want no error", err)
+	}
+	if err := v1beta1.AddToScheme(scheme); err != nil {
+		t.Fatalf("v1beta1.AddToScheme() got error %v, want no error", err)
+	}
+	codecs := runtimeserializer.NewCodecFactory(scheme)
+
+	tests := []struct {
+		name     string
+		newSpoke func() conversion.Convertible
+		newHub   func() conversion.Hub
+	}{
+		{
+			name:     "TrafficManagerProfile",
+			newSpoke: func() conversion.Convertible { return &TrafficManagerProfile{} },
+			newHub:   func() conversion.Hub { return &v1beta1.TrafficManagerProfile{} },
+		},
+		{
+			name:     "TrafficManagerBackend",
+			newSpoke: func() conversion.Convertible { return &TrafficManagerBackend{} },
+			newHub:   func() conversion.Hub { return &v1beta1.TrafficManagerBackend{} },
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			f := fuzzer.FuzzerFor(metafuzzer.Funcs, rand.NewSource(time.Now().UnixNano()), codecs)
+
+			t.Run("spoke-hub-spoke", func(t *testing.T) {
+				for i := 0; i < fuzzIterations; i++ {
+					spoke := tc.newSpoke()
+					f.Fuzz(spoke)
+
+					hub := tc.newHub()
+					if err := spoke.ConvertTo(hub); err != nil {
+						t.Fatalf("ConvertTo() got error %v, want no error", err)
+					}
+					got := tc.newSpoke()
+					if err := got.ConvertFrom(hub); err != nil {
+						t.Fatalf("ConvertFrom() got error %v, want no error", err)
+					}
+					if diff := cmp.Diff(spoke, got); diff != "" {
+						t.Fatalf("spoke-hub-spoke round trip mismatch (-want, +got):\n%s", diff)
+					}
+				}
+			})
+
+			t.Run("hub-spoke-hub", func(t *testing.T) {
+				for i := 0; i < fuzzIterations; i++ {
+					hub := tc.newHub()
+					f.Fuzz(hub)
+
+					spoke := tc.newSpoke()
+					if err := spoke.ConvertFrom(hub); err != nil {
+						t.Fatalf("ConvertFrom() got error %v, want no error", err)
+					}
+					got := tc.newHub()
+					if err := spoke.ConvertTo(got); err != nil {
+						t.Fatalf("ConvertTo() got error %v, want no error", err)
+					}
+					if diff := cmp.Diff(hub, got); diff != "" {
+						t.Fatalf("hub-spoke-hub round trip mismatch (-want, +got):\n%s", diff)
+					}
+				}
+			})
+		})
+	}
+}
@@ -6,12 +6,14 @@ const (
 	TrafficManagerBackendKind = "TrafficManagerBackend"
 )
 
+// +genclient
 // +kubebuilder:object:root=true
 // +kubebuilder:resource:scope=Namespaced,categories={fleet-networking},shortName=tmb
 // +kubebuilder:subresource:status
 // +kubebuilder:printcolumn:JSONPath=`.spec.profile.name`,name="Profile",type=string
 // +kubebuilder:printcolumn:JSONPath=`.spec.backend.name`,name="Backend",type=string
 // +kubebuilder:printcolumn:JSONPath=`.status.conditions[?(@.type=='Accepted')].status`,name="Is-Accepted",type=string
+// +kubebuilder:printcolumn:JSONPath=`.status.conditions[?(@.reason=='Disabled')].status`,name="Disabled",type=string
 // +kubebuilder:printcolumn:JSONPath=`.metadata.creationTimestamp`,name="Age",type=date
 
 // TrafficManagerBackend is used to manage the Azure Traffic Manager Endpoints using cloud native way.
@@ -46,7 +48,8 @@ type TrafficManagerBackendSpec struct {
 	// The total weight of endpoints behind the serviceImport when using the 'Weighted' traffic routing method.
 	// Possible values are from 0 to 1000.
 	// By default, the routing method is 'Weighted'.
-	// If weight is set to 0, all the endpoints behind the serviceImport will be removed from the profile.
+	// If weight is set to 0, all the endpoints behind the serviceImport will be removed from the profile,
+	// and the Accepted condition reports the "Disabled" reason.
 	// The actual weight of each endpoint is the ceiling value of a number computed as weight/(sum of all weights behind the serviceImport)
 	// * weight of serviceExport.
 	// For example, if the weight is 500 and there are two serviceExports from cluster-1 (weight: 100) and cluster-2 (weight: 200)
@@ -59,6 +62,71 @@ type TrafficManagerBackendSpec struct {
 	// +kubebuilder:validation:Maximum=1000
 	// +kubebuilder:default=1
 	Weight *int64 `json:"weight,omitempty"`
+
+	// CustomHeaders are the custom headers sent in the health probe requests to all the endpoints of the backend;
+	// they override the custom headers configured on the profile, if any.
+	// +optional
+	// +listType=map
+	// +listMapKey=name
+	// +kubebuilder:validation:MaxItems=8
+	CustomHeaders []TrafficManagerEndpointCustomHeader `json:"customHeaders,omitempty"`
+
+	// SubnetOverrides routes the requests from the given client subnets to the endpoints of specific clusters, e.g.,
+	// to route a test subnet to a canary cluster. The overrides take effect only when the profile uses the 'Subnet'
+	// traffic routing method.
+	// The subnets of different clusters must not overlap.
+	// +optional
+	// +listType=map
+	// +listMapKey=cluster
+	SubnetOverrides []TrafficManagerSubnetOverride `json:"subnetOverrides,omitempty"`
+
+	// Priorities are the priorities of the endpoints of specific clusters, which order the endpoints for failover
+	// when the profile uses the 'Priority' traffic routing method; the endpoint with the lowest value is the primary.
+	// The endpoints of the clusters which are not listed are assigned the next unused priorities after the listed
+	// ones, in the order of the cluster names. When no priority is listed, Azure assigns the priorities.
+	// +optional
+	// +listType=map
+	// +listMapKey=cluster
+	// +kubebuilder:validation:MaxItems=200
+	// +kubebuilder:validation:XValidation:rule="self.all(x, self.exists_one(y, y.priority == x.priority))",message="priorities must be unique"
+	Priorities []TrafficManagerClusterPriority `json:"priorities,omitempty"`
+}
+
+// TrafficManagerEndpointCustomHeader is a custom header sent in the health probe requests to an endpoint.
+type TrafficManagerEndpointCustomHeader struct {
+	// Name of the header.
+	// +required
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
+
+	// Value of the header.
+	// +required
+	Value string `json:"value"`
+}
+
+// TrafficManagerSubnetOverride maps client subnets to the endpoint of a cluster.
+type TrafficManagerSubnetOverride struct {
+	// Cluster is the name of the member cluster whose endpoint the subnets are routed to.
+	// +required
+	Cluster string `json:"cluster"`
+
+	// Subnets are the client subnets in CIDR notation (e.g., 10.1.0.0/16), either IPv4 or IPv6.
+	// +required
+	// +kubebuilder:validation:MinItems=1
+	Subnets []string `json:"subnets"`
+}
+
+// TrafficManagerClusterPriority is the priority of the endpoint of a cluster.
+type TrafficManagerClusterPriority struct {
+	// Cluster is the name of the member cluster whose endpoint is assigned the priority.
+	// +required
+	Cluster string `json:"cluster"`
+
+	// Priority of the endpoint; possible values are from 1 to 1000, lower values having higher priorities.
+	// +required
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=1000
+	Priority int64 `json:"priority"`
 }
 
 // TrafficManagerProfileRef is a reference to a trafficManagerProfile object in the same namespace as the TrafficManagerBackend object.
@@ -99,6 +167,19 @@ type TrafficManagerEndpointStatus struct {
 	// From is where the endpoint is exported from.
 	// +optional
 	From *FromCluster `json:"from,omitempty"`
+
+	// CustomHeaders are the custom headers applied to the endpoint.
+	// +optional
+	CustomHeaders []TrafficManagerEndpointCustomHeader `json:"customHeaders,omitempty"`
+
+	// Subnets are the client subnets routed to the endpoint, in CIDR notation.
+	// +optional
+	Subnets []string `json:"subnets,omitempty"`
+
+	// The priority of this endpoint when using the 'Priority' traffic routing method.
+	// Possible values are from 1 to 1000.
+	// +optional
+	Priority *int64 `json:"priority,omitempty"`
 }
 
 // FromCluster contains service configuration mapped to a specific source cluster.
@@ -124,8 +205,99 @@ type TrafficManagerBackendStatus struct {
 	// +listType=map
 	// +listMapKey=type
 	Conditions []metav1.Condition `json:"conditions,omitempty" patchStrategy:"merge" patchMergeKey:"type"`
+
+	// LastSyncedTime is the last time a reconciliation completed all of its Azure Traffic Manager operations
+	// successfully, including the reconciliations which did not need to change anything. Unlike the transition time
+	// of the conditions, it keeps moving while the backend is in a steady state; it is refreshed at a coarse
+	// granularity (one minute by default).
+	// +optional
+	LastSyncedTime *metav1.Time `json:"lastSyncedTime,omitempty"`
+
+	// InvalidClusters lists the clusters whose exported services cannot be exposed as Azure Traffic Manager endpoints,
+	// sorted by the cluster name, along with the reason of each of them; the list is capped at
+	// TrafficManagerBackendMaxInvalidClusters entries, while TotalInvalid counts all of them. It complements the
+	// Accepted condition, whose message names only one example.
+	// +optional
+	// +listType=map
+	// +listMapKey=cluster
+	// +kubebuilder:validation:MaxItems=20
+	InvalidClusters []TrafficManagerInvalidCluster `json:"invalidClusters,omitempty"`
+
+	// TotalInvalid is the number of the clusters whose exported services cannot be exposed as Azure Traffic Manager
+	// endpoints, including those left out of InvalidClusters.
+	// +optional
+	TotalInvalid int32 `json:"totalInvalid,omitempty"`
 }
 
+// TrafficManagerBackendMaxInvalidClusters is the maximum number of the clusters listed in the InvalidClusters of the
+// backend status.
+const TrafficManagerBackendMaxInvalidClusters = 20
+
+// TrafficManagerInvalidCluster describes a cluster whose exported service cannot be exposed as an Azure Traffic Manager
+// endpoint.
+type TrafficManagerInvalidCluster struct {
+	// Cluster is the name of the cluster exporting the service.
+	// +required
+	Cluster string `json:"cluster"`
+
+	// Reason is a machine-readable reason why the service cannot be exposed.
+	// +required
+	Reason TrafficManagerInvalidClusterReason `json:"reason"`
+
+	// Message is a human-readable message with the details.
+	// +optional
+	Message string `json:"message,omitempty"`
+}
+
+// TrafficManagerInvalidClusterReason is the reason why the service exported from a cluster cannot be exposed as an
+// Azure Traffic Manager endpoint.
+// +kubebuilder:validation:Enum=UnsupportedType;InternalLoadBalancer;LoadBalancerPending;NoDNSLabel;UnsupportedProtocol;AgentUpgradeRequired;StaleExport;LocalExternalTrafficPolicy;AzureClientError;Throttled;Invalid
+type TrafficManagerInvalidClusterReason string
+
+const (
+	// TrafficManagerInvalidClusterReasonUnsupportedType is used when the service is neither a LoadBalancer service
+	// nor exported with a public IP address.
+	TrafficManagerInvalidClusterReasonUnsupportedType TrafficManagerInvalidClusterReason = "UnsupportedType"
+
+	// TrafficManagerInvalidClusterReasonInternalLoadBalancer is used when the service is exposed by an internal load
+	// balancer.
+	TrafficManagerInvalidClusterReasonInternalLoadBalancer TrafficManagerInvalidClusterReason = "InternalLoadBalancer"
+
+	// TrafficManagerInvalidClusterReasonLoadBalancerPending is used when the load balancer IP of the service has not
+	// been provisioned yet.
+	TrafficManagerInvalidClusterReasonLoadBalancerPending TrafficManagerInvalidClusterReason = "LoadBalancerPending"
+
+	// TrafficManagerInvalidClusterReasonNoDNSLabel is used when no DNS label is configured to the public IP of the
+	// service.
+	TrafficManagerInvalidClusterReasonNoDNSLabel TrafficManagerInvalidClusterReason = "NoDNSLabel"
+
+	// TrafficManagerInvalidClusterReasonUnsupportedProtocol is used when a port of the service uses another protocol
+	// than TCP.
+	TrafficManagerInvalidClusterReasonUnsupportedProtocol TrafficManagerInvalidClusterReason = "UnsupportedProtocol"
+
+	// TrafficManagerInvalidClusterReasonAgentUpgradeRequired is used when the member agent of the cluster has not
+	// reported the Azure Traffic Manager information of the service.
+	TrafficManagerInvalidClusterReasonAgentUpgradeRequired TrafficManagerInvalidClusterReason = "AgentUpgradeRequired"
+
+	// TrafficManagerInvalidClusterReasonStaleExport is used when the exported service has not been refreshed by its
+	// member cluster for longer than the max staleness.
+	TrafficManagerInvalidClusterReasonStaleExport TrafficManagerInvalidClusterReason = "StaleExport"
+
+	// TrafficManagerInvalidClusterReasonLocalExternalTrafficPolicy is used when the external traffic policy of the
+	// service is Local; the service is still exposed unless the hub controller rejects such services.
+	TrafficManagerInvalidClusterReasonLocalExternalTrafficPolicy TrafficManagerInvalidClusterReason = "LocalExternalTrafficPolicy"
+
+	// TrafficManagerInvalidClusterReasonAzureClientError is used when Azure rejects the endpoint of the service.
+	TrafficManagerInvalidClusterReasonAzureClientError TrafficManagerInvalidClusterReason = "AzureClientError"
+
+	// TrafficManagerInvalidClusterReasonThrottled is used when the request for the endpoint of the service is
+	// throttled by Azure.
+	TrafficManagerInvalidClusterReasonThrottled TrafficManagerInvalidClusterReason = "Throttled"
+
+	// TrafficManagerInvalidClusterReasonInvalid is used when the service is invalid for any other reason.
+	TrafficManagerInvalidClusterReasonInvalid TrafficManagerInvalidClusterReason = "Invalid"
+)
+
 // TrafficManagerBackendConditionType is a type of condition associated with a TrafficManagerBackendStatus. This type
 // should be used within the TrafficManagerBackendStatus.Conditions field.
 type TrafficManagerBackendConditionType string
@@ -140,28 +312,57 @@ const (
 	// Possible reasons for this condition to be True are:
 	//
 	// * "Accepted"
+	// * "Disabled"
 	//
 	// Possible reasons for this condition to be False are:
 	//
 	// * "Invalid"
+	// * "NamespaceNotAllowed"
+	// * "AgentUpgradeRequired"
 	//
 	// Possible reasons for this condition to be Unknown are:
 	//
 	// * "Pending"
+	// * "ReadOnly"
 	//
 	TrafficManagerBackendConditionAccepted TrafficManagerBackendConditionType = "Accepted"
 
 	// TrafficManagerBackendReasonAccepted is used with the "Accepted" condition when the condition is True.
 	TrafficManagerBackendReasonAccepted TrafficManagerBackendConditionReason = "Accepted"
 
+	// TrafficManagerBackendReasonDisabled is used with the "Accepted" condition when the condition is True and the
+	// weight of the backend is 0, so that all its endpoints have been removed from the Profile.
+	TrafficManagerBackendReasonDisabled TrafficManagerBackendConditionReason = "Disabled"
+
 	// TrafficManagerBackendReasonInvalid is used with the "Accepted" condition when one or
 	// more endpoint references have an invalid or unsupported configuration
 	// and cannot be configured on the Profile with more details in the message.
 	TrafficManagerBackendReasonInvalid TrafficManagerBackendConditionReason = "Invalid"
 
+	// TrafficManagerBackendReasonDuplicate is used with the "Accepted" condition when the condition is False and an
+	// older backend in the same namespace already refers to the same Profile and backend, so that none of the
+	// endpoints of this backend are configured on the Profile.
+	TrafficManagerBackendReasonDuplicate TrafficManagerBackendConditionReason = "DuplicateBackend"
+
 	// TrafficManagerBackendReasonPending is used with the "Accepted" when creating or updating endpoint hits an internal error with
 	// more details in the message and the controller will keep retry.
 	TrafficManagerBackendReasonPending TrafficManagerBackendConditionReason = "Pending"
+
+	// TrafficManagerBackendReasonReadOnly is used with the "Accepted" condition when the condition is Unknown and the
+	// controller runs in the read-only mode, so that the changes the endpoints need are observed but not applied.
+	TrafficManagerBackendReasonReadOnly TrafficManagerBackendConditionReason = "ReadOnly"
+
+	// TrafficManagerBackendReasonNamespaceNotAllowed is used with the "Accepted" condition when the condition is False
+	// and the namespace of the backend is not allowed to use Azure Traffic Manager by the hub controller, so that the
+	// endpoints of the backend are neither created nor updated on the Profile.
+	TrafficManagerBackendReasonNamespaceNotAllowed TrafficManagerBackendConditionReason = "NamespaceNotAllowed"
+
+	// TrafficManagerBackendReasonAgentUpgradeRequired is used with the "Accepted" condition when the condition is False
+	// and the member agents of some exporting clusters have not reported the Azure Traffic Manager information of their
+	// exported services, e.g. as they predate the traffic manager feature, so that the services cannot be configured on
+	// the Profile until the member agents are upgraded (or their traffic manager feature is enabled); the message names
+	// the clusters.
+	TrafficManagerBackendReasonAgentUpgradeRequired TrafficManagerBackendConditionReason = "AgentUpgradeRequired"
 )
 
 //+kubebuilder:object:root=true
@@ -14,8 +14,41 @@ import (
 	"k8s.io/api/discovery/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	runtime "k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/intstr"
 )
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterConsumerPolicy) DeepCopyInto(out *ClusterConsumerPolicy) {
+	*out = *in
+	in.Policy.DeepCopyInto(&out.Policy)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterConsumerPolicy.
+func (in *ClusterConsumerPolicy) DeepCopy() *ClusterConsumerPolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterConsumerPolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterExportHealth) DeepCopyInto(out *ClusterExportHealth) {
+	*out = *in
+	in.Health.DeepCopyInto(&out.Health)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterExportHealth.
+func (in *ClusterExportHealth) DeepCopy() *ClusterExportHealth {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterExportHealth)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ClusterStatus) DeepCopyInto(out *ClusterStatus) {
 	*out = *in
@@ -31,6 +64,51 @@ func (in *ClusterStatus) DeepCopy() *ClusterStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterWeight) DeepCopyInto(out *ClusterWeight) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterWeight.
+func (in *ClusterWeight) DeepCopy() *ClusterWeight {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterWeight)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ConsumerPolicy) DeepCopyInto(out *ConsumerPolicy) {
+	*out = *in
+	if in.AllowedClusters != nil {
+		in, out := &in.AllowedClusters, &out.AllowedClusters
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.DeniedClusters != nil {
+		in, out := &in.DeniedClusters, &out.DeniedClusters
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.ClusterSelector != nil {
+		in, out := &in.ClusterSelector, &out.ClusterSelector
+		*out = new(metav1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ConsumerPolicy.
+func (in *ConsumerPolicy) DeepCopy() *ConsumerPolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(ConsumerPolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Endpoint) DeepCopyInto(out *Endpoint) {
 	*out = *in
@@ -39,6 +117,21 @@ func (in *Endpoint) DeepCopyInto(out *Endpoint) {
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
+	if in.NodeName != nil {
+		in, out := &in.NodeName, &out.NodeName
+		*out = new(string)
+		**out = **in
+	}
+	if in.Serving != nil {
+		in, out := &in.Serving, &out.Serving
+		*out = new(bool)
+		**out = **in
+	}
+	if in.Terminating != nil {
+		in, out := &in.Terminating, &out.Terminating
+		*out = new(bool)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Endpoint.
@@ -146,6 +239,7 @@ func (in *EndpointSliceImport) DeepCopyInto(out *EndpointSliceImport) {
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
 	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EndpointSliceImport.
@@ -198,6 +292,81 @@ func (in *EndpointSliceImportList) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *EndpointSliceImportStatus) DeepCopyInto(out *EndpointSliceImportStatus) {
+	*out = *in
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]metav1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EndpointSliceImportStatus.
+func (in *EndpointSliceImportStatus) DeepCopy() *EndpointSliceImportStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(EndpointSliceImportStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ExportHealth) DeepCopyInto(out *ExportHealth) {
+	*out = *in
+	in.LastTransitionTime.DeepCopyInto(&out.LastTransitionTime)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ExportHealth.
+func (in *ExportHealth) DeepCopy() *ExportHealth {
+	if in == nil {
+		return nil
+	}
+	out := new(ExportHealth)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ExportResolution) DeepCopyInto(out *ExportResolution) {
+	*out = *in
+	if in.Ports != nil {
+		in, out := &in.Ports, &out.Ports
+		*out = make([]ServicePort, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.IPFamilies != nil {
+		in, out := &in.IPFamilies, &out.IPFamilies
+		*out = make([]corev1.IPFamily, len(*in))
+		copy(*out, *in)
+	}
+	if in.ExportingClusters != nil {
+		in, out := &in.ExportingClusters, &out.ExportingClusters
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.ImporterClusters != nil {
+		in, out := &in.ImporterClusters, &out.ImporterClusters
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ExportResolution.
+func (in *ExportResolution) DeepCopy() *ExportResolution {
+	if in == nil {
+		return nil
+	}
+	out := new(ExportResolution)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ExportedObjectReference) DeepCopyInto(out *ExportedObjectReference) {
 	*out = *in
@@ -235,6 +404,21 @@ func (in *FromCluster) DeepCopy() *FromCluster {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HealthProbe) DeepCopyInto(out *HealthProbe) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HealthProbe.
+func (in *HealthProbe) DeepCopy() *HealthProbe {
+	if in == nil {
+		return nil
+	}
+	out := new(HealthProbe)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *InternalServiceExport) DeepCopyInto(out *InternalServiceExport) {
 	*out = *in
@@ -305,6 +489,11 @@ func (in *InternalServiceExportSpec) DeepCopyInto(out *InternalServiceExportSpec
 		}
 	}
 	in.ServiceReference.DeepCopyInto(&out.ServiceReference)
+	if in.AddressFamilies != nil {
+		in, out := &in.AddressFamilies, &out.AddressFamilies
+		*out = make([]corev1.IPFamily, len(*in))
+		copy(*out, *in)
+	}
 	if in.PublicIPResourceID != nil {
 		in, out := &in.PublicIPResourceID, &out.PublicIPResourceID
 		*out = new(string)
@@ -315,6 +504,16 @@ func (in *InternalServiceExportSpec) DeepCopyInto(out *InternalServiceExportSpec
 		*out = new(int64)
 		**out = **in
 	}
+	if in.ConsumerPolicy != nil {
+		in, out := &in.ConsumerPolicy, &out.ConsumerPolicy
+		*out = new(ConsumerPolicy)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Health != nil {
+		in, out := &in.Health, &out.Health
+		*out = new(ExportHealth)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InternalServiceExportSpec.
@@ -337,6 +536,15 @@ func (in *InternalServiceExportStatus) DeepCopyInto(out *InternalServiceExportSt
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.LastHeartbeatTime != nil {
+		in, out := &in.LastHeartbeatTime, &out.LastHeartbeatTime
+		*out = (*in).DeepCopy()
+	}
+	if in.Resolution != nil {
+		in, out := &in.Resolution, &out.Resolution
+		*out = new(ExportResolution)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InternalServiceExportStatus.
@@ -424,6 +632,21 @@ func (in *InternalServiceImportSpec) DeepCopy() *InternalServiceImportSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MissingClusterStatus) DeepCopyInto(out *MissingClusterStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MissingClusterStatus.
+func (in *MissingClusterStatus) DeepCopy() *MissingClusterStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(MissingClusterStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *MonitorConfig) DeepCopyInto(out *MonitorConfig) {
 	*out = *in
@@ -442,6 +665,11 @@ func (in *MonitorConfig) DeepCopyInto(out *MonitorConfig) {
 		*out = new(int64)
 		**out = **in
 	}
+	if in.InheritPortFromBackend != nil {
+		in, out := &in.InheritPortFromBackend, &out.InheritPortFromBackend
+		*out = new(bool)
+		**out = **in
+	}
 	if in.Protocol != nil {
 		in, out := &in.Protocol, &out.Protocol
 		*out = new(TrafficManagerMonitorProtocol)
@@ -474,7 +702,7 @@ func (in *MultiClusterService) DeepCopyInto(out *MultiClusterService) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
-	out.Spec = in.Spec
+	in.Spec.DeepCopyInto(&out.Spec)
 	in.Status.DeepCopyInto(&out.Status)
 }
 
@@ -532,6 +760,21 @@ func (in *MultiClusterServiceList) DeepCopyObject() runtime.Object {
 func (in *MultiClusterServiceSpec) DeepCopyInto(out *MultiClusterServiceSpec) {
 	*out = *in
 	out.ServiceImport = in.ServiceImport
+	if in.Ports != nil {
+		in, out := &in.Ports, &out.Ports
+		*out = make([]intstr.IntOrString, len(*in))
+		copy(*out, *in)
+	}
+	if in.HealthCheckNodePort != nil {
+		in, out := &in.HealthCheckNodePort, &out.HealthCheckNodePort
+		*out = new(int32)
+		**out = **in
+	}
+	if in.IdleTimeoutMinutes != nil {
+		in, out := &in.IdleTimeoutMinutes, &out.IdleTimeoutMinutes
+		*out = new(int32)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MultiClusterServiceSpec.
@@ -548,6 +791,11 @@ func (in *MultiClusterServiceSpec) DeepCopy() *MultiClusterServiceSpec {
 func (in *MultiClusterServiceStatus) DeepCopyInto(out *MultiClusterServiceStatus) {
 	*out = *in
 	in.LoadBalancer.DeepCopyInto(&out.LoadBalancer)
+	if in.SkippedClusters != nil {
+		in, out := &in.SkippedClusters, &out.SkippedClusters
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 	if in.Conditions != nil {
 		in, out := &in.Conditions, &out.Conditions
 		*out = make([]metav1.Condition, len(*in))
@@ -587,6 +835,7 @@ func (in *ServiceExport) DeepCopyInto(out *ServiceExport) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
 	in.Status.DeepCopyInto(&out.Status)
 }
 
@@ -640,6 +889,51 @@ func (in *ServiceExportList) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ServiceExportPreview) DeepCopyInto(out *ServiceExportPreview) {
+	*out = *in
+	if in.ImporterClusters != nil {
+		in, out := &in.ImporterClusters, &out.ImporterClusters
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ServiceExportPreview.
+func (in *ServiceExportPreview) DeepCopy() *ServiceExportPreview {
+	if in == nil {
+		return nil
+	}
+	out := new(ServiceExportPreview)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ServiceExportSpec) DeepCopyInto(out *ServiceExportSpec) {
+	*out = *in
+	if in.ConsumerPolicy != nil {
+		in, out := &in.ConsumerPolicy, &out.ConsumerPolicy
+		*out = new(ConsumerPolicy)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.HealthProbe != nil {
+		in, out := &in.HealthProbe, &out.HealthProbe
+		*out = new(HealthProbe)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ServiceExportSpec.
+func (in *ServiceExportSpec) DeepCopy() *ServiceExportSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ServiceExportSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ServiceExportStatus) DeepCopyInto(out *ServiceExportStatus) {
 	*out = *in
@@ -650,6 +944,11 @@ func (in *ServiceExportStatus) DeepCopyInto(out *ServiceExportStatus) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.Preview != nil {
+		in, out := &in.Preview, &out.Preview
+		*out = new(ServiceExportPreview)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ServiceExportStatus.
@@ -755,11 +1054,50 @@ func (in *ServiceImportStatus) DeepCopyInto(out *ServiceImportStatus) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.IPFamilies != nil {
+		in, out := &in.IPFamilies, &out.IPFamilies
+		*out = make([]corev1.IPFamily, len(*in))
+		copy(*out, *in)
+	}
 	if in.Clusters != nil {
 		in, out := &in.Clusters, &out.Clusters
 		*out = make([]ClusterStatus, len(*in))
 		copy(*out, *in)
 	}
+	if in.MissingClusters != nil {
+		in, out := &in.MissingClusters, &out.MissingClusters
+		*out = make([]MissingClusterStatus, len(*in))
+		copy(*out, *in)
+	}
+	if in.ConsumerPolicies != nil {
+		in, out := &in.ConsumerPolicies, &out.ConsumerPolicies
+		*out = make([]ClusterConsumerPolicy, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.ClusterWeights != nil {
+		in, out := &in.ClusterWeights, &out.ClusterWeights
+		*out = make([]ClusterWeight, len(*in))
+		copy(*out, *in)
+	}
+	if in.ClusterHealth != nil {
+		in, out := &in.ClusterHealth, &out.ClusterHealth
+		*out = make([]ClusterExportHealth, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.DNSNames != nil {
+		in, out := &in.DNSNames, &out.DNSNames
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.ImporterClusters != nil {
+		in, out := &in.ImporterClusters, &out.ImporterClusters
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ServiceImportStatus.
@@ -899,6 +1237,23 @@ func (in *TrafficManagerBackendSpec) DeepCopyInto(out *TrafficManagerBackendSpec
 		*out = new(int64)
 		**out = **in
 	}
+	if in.CustomHeaders != nil {
+		in, out := &in.CustomHeaders, &out.CustomHeaders
+		*out = make([]TrafficManagerEndpointCustomHeader, len(*in))
+		copy(*out, *in)
+	}
+	if in.SubnetOverrides != nil {
+		in, out := &in.SubnetOverrides, &out.SubnetOverrides
+		*out = make([]TrafficManagerSubnetOverride, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Priorities != nil {
+		in, out := &in.Priorities, &out.Priorities
+		*out = make([]TrafficManagerClusterPriority, len(*in))
+		copy(*out, *in)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TrafficManagerBackendSpec.
@@ -928,6 +1283,15 @@ func (in *TrafficManagerBackendStatus) DeepCopyInto(out *TrafficManagerBackendSt
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.LastSyncedTime != nil {
+		in, out := &in.LastSyncedTime, &out.LastSyncedTime
+		*out = (*in).DeepCopy()
+	}
+	if in.InvalidClusters != nil {
+		in, out := &in.InvalidClusters, &out.InvalidClusters
+		*out = make([]TrafficManagerInvalidCluster, len(*in))
+		copy(*out, *in)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TrafficManagerBackendStatus.
@@ -940,6 +1304,36 @@ func (in *TrafficManagerBackendStatus) DeepCopy() *TrafficManagerBackendStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TrafficManagerClusterPriority) DeepCopyInto(out *TrafficManagerClusterPriority) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TrafficManagerClusterPriority.
+func (in *TrafficManagerClusterPriority) DeepCopy() *TrafficManagerClusterPriority {
+	if in == nil {
+		return nil
+	}
+	out := new(TrafficManagerClusterPriority)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TrafficManagerEndpointCustomHeader) DeepCopyInto(out *TrafficManagerEndpointCustomHeader) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TrafficManagerEndpointCustomHeader.
+func (in *TrafficManagerEndpointCustomHeader) DeepCopy() *TrafficManagerEndpointCustomHeader {
+	if in == nil {
+		return nil
+	}
+	out := new(TrafficManagerEndpointCustomHeader)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *TrafficManagerEndpointStatus) DeepCopyInto(out *TrafficManagerEndpointStatus) {
 	*out = *in
@@ -958,6 +1352,21 @@ func (in *TrafficManagerEndpointStatus) DeepCopyInto(out *TrafficManagerEndpoint
 		*out = new(FromCluster)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.CustomHeaders != nil {
+		in, out := &in.CustomHeaders, &out.CustomHeaders
+		*out = make([]TrafficManagerEndpointCustomHeader, len(*in))
+		copy(*out, *in)
+	}
+	if in.Subnets != nil {
+		in, out := &in.Subnets, &out.Subnets
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Priority != nil {
+		in, out := &in.Priority, &out.Priority
+		*out = new(int64)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TrafficManagerEndpointStatus.
@@ -970,6 +1379,21 @@ func (in *TrafficManagerEndpointStatus) DeepCopy() *TrafficManagerEndpointStatus
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TrafficManagerInvalidCluster) DeepCopyInto(out *TrafficManagerInvalidCluster) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TrafficManagerInvalidCluster.
+func (in *TrafficManagerInvalidCluster) DeepCopy() *TrafficManagerInvalidCluster {
+	if in == nil {
+		return nil
+	}
+	out := new(TrafficManagerInvalidCluster)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *TrafficManagerProfile) DeepCopyInto(out *TrafficManagerProfile) {
 	*out = *in
@@ -1079,6 +1503,10 @@ func (in *TrafficManagerProfileStatus) DeepCopyInto(out *TrafficManagerProfileSt
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.LastSyncedTime != nil {
+		in, out := &in.LastSyncedTime, &out.LastSyncedTime
+		*out = (*in).DeepCopy()
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TrafficManagerProfileStatus.
@@ -1090,3 +1518,23 @@ func (in *TrafficManagerProfileStatus) DeepCopy() *TrafficManagerProfileStatus {
 	in.DeepCopyInto(out)
 	return out
 }
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TrafficManagerSubnetOverride) DeepCopyInto(out *TrafficManagerSubnetOverride) {
+	*out = *in
+	if in.Subnets != nil {
+		in, out := &in.Subnets, &out.Subnets
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TrafficManagerSubnetOverride.
+func (in *TrafficManagerSubnetOverride) DeepCopy() *TrafficManagerSubnetOverride {
+	if in == nil {
+		return nil
+	}
+	out := new(TrafficManagerSubnetOverride)
+	in.DeepCopyInto(out)
+	return out
+}
@@ -10,4 +10,12 @@ type ClusterStatus struct {
 	// cluster is the name of the exporting cluster. Must be a valid RFC-1123 DNS
 	// label.
 	Cluster string `json:"cluster"`
+
+	// region is the Azure region of the exporting cluster, if known.
+	// +optional
+	Region string `json:"region,omitempty"`
+
+	// vnetID is the resource ID of the virtual network of the exporting cluster, if known.
+	// +optional
+	VNetID string `json:"vnetID,omitempty"`
 }
@@ -6,6 +6,8 @@ Licensed under the MIT license.
 // +kubebuilder:object:generate=true
 // +k8s:deepcopy-gen=package,register
 // +groupName=networking.fleet.azure.com
+// +groupGoName=Networking
+// +k8s:openapi-gen=true
 
 // Package v1beta1 contains API Schema definitions for the fleet networking v1beta1 API group.
 package v1beta1
@@ -0,0 +1,12 @@
+/*
+Copyright (c) Microsoft Corporation.
+Licensed under the MIT license.
+*/
+
+package v1beta1
+
+// Hub marks TrafficManagerProfile as a conversion hub; the v1alpha1 version is converted to and from this version.
+func (*TrafficManagerProfile) Hub() {}
+
+// Hub marks TrafficManagerBackend as a conversion hub; the v1alpha1 version is converted to and from this version.
+func (*TrafficManagerBackend) Hub() {}
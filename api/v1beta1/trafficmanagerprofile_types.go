@@ -13,12 +13,14 @@ const (
 	TrafficManagerProfileKind = "TrafficManagerProfile"
 )
 
+// +genclient
 // +kubebuilder:object:root=true
 // +kubebuilder:resource:scope=Namespaced,categories={fleet-networking},shortName=tmp
 // +kubebuilder:subresource:status
 // +kubebuilder:storageversion
 // +kubebuilder:printcolumn:JSONPath=`.status.dnsName`,name="DNS-Name",type=string
 // +kubebuilder:printcolumn:JSONPath=`.status.conditions[?(@.type=='Programmed')].status`,name="Is-Programmed",type=string
+// +kubebuilder:printcolumn:JSONPath=`.status.monitorStatus`,name="Monitor",type=string
 // +kubebuilder:printcolumn:JSONPath=`.metadata.creationTimestamp`,name="Age",type=date
 
 // TrafficManagerProfile is used to manage a simple Azure Traffic Manager Profile using cloud native way.
@@ -73,6 +75,13 @@ type MonitorConfig struct {
 	// +kubebuilder:default=80
 	Port *int64 `json:"port,omitempty"`
 
+	// InheritPortFromBackend, if set to true, makes Azure Traffic Manager probe the port of the Service exported by
+	// the backends of the profile instead of Port, so that the monitor port follows the service port when it changes.
+	// The exported Services must have a single port and agree on it; otherwise, the profile is rejected as invalid.
+	// Port is probed until any backend Service is exported.
+	// +optional
+	InheritPortFromBackend *bool `json:"inheritPortFromBackend,omitempty"`
+
 	// The protocol (HTTP, HTTPS or TCP) used to probe for endpoint health.
 	// +kubebuilder:validation:Enum=HTTP;HTTPS;TCP
 	// +optional
@@ -127,8 +136,39 @@ type TrafficManagerProfileStatus struct {
 	// +listType=map
 	// +listMapKey=type
 	Conditions []metav1.Condition `json:"conditions,omitempty" patchStrategy:"merge" patchMergeKey:"type"`
+
+	// LastSyncedTime is the last time a reconciliation completed all of its Azure Traffic Manager operations
+	// successfully, including the reconciliations which did not need to change anything. Unlike the transition time
+	// of the conditions, it keeps moving while the profile is in a steady state; it is refreshed at a coarse
+	// granularity (one minute by default).
+	// +optional
+	LastSyncedTime *metav1.Time `json:"lastSyncedTime,omitempty"`
+
+	// MonitorStatus is the profile-level monitor status of the Azure Traffic Manager profile reported by Azure, which
+	// summarizes the health of its endpoints; it is Disabled when the profile is disabled. It is refreshed on every
+	// reconciliation, including the periodic resyncs, and unset when the Azure Traffic Manager profile cannot be
+	// observed.
+	// +optional
+	MonitorStatus TrafficManagerProfileMonitorStatus `json:"monitorStatus,omitempty"`
 }
 
+// TrafficManagerProfileMonitorStatus is the profile-level monitor status of an Azure Traffic Manager profile.
+type TrafficManagerProfileMonitorStatus string
+
+const (
+	// TrafficManagerProfileMonitorStatusCheckingEndpoints means that the endpoints of the profile are being probed
+	// for the first time.
+	TrafficManagerProfileMonitorStatusCheckingEndpoints TrafficManagerProfileMonitorStatus = "CheckingEndpoints"
+	// TrafficManagerProfileMonitorStatusOnline means that at least one endpoint of the profile is online.
+	TrafficManagerProfileMonitorStatusOnline TrafficManagerProfileMonitorStatus = "Online"
+	// TrafficManagerProfileMonitorStatusDegraded means that none of the enabled endpoints of the profile is online.
+	TrafficManagerProfileMonitorStatusDegraded TrafficManagerProfileMonitorStatus = "Degraded"
+	// TrafficManagerProfileMonitorStatusDisabled means that the profile is disabled.
+	TrafficManagerProfileMonitorStatusDisabled TrafficManagerProfileMonitorStatus = "Disabled"
+	// TrafficManagerProfileMonitorStatusInactive means that the profile has no enabled endpoint.
+	TrafficManagerProfileMonitorStatusInactive TrafficManagerProfileMonitorStatus = "Inactive"
+)
+
 // TrafficManagerProfileConditionType is a type of condition associated with a
 // Traffic Manager Profile. This type should be used within the TrafficManagerProfileStatus.Conditions field.
 type TrafficManagerProfileConditionType string
@@ -153,10 +193,12 @@ const (
 	//
 	// * "Invalid"
 	// * "DNSNameNotAvailable"
+	// * "NamespaceNotAllowed"
 	//
 	// Possible reasons for this condition to be Unknown are:
 	//
 	// * "Pending"
+	// * "ReadOnly"
 	//
 	TrafficManagerProfileConditionProgrammed TrafficManagerProfileConditionType = "Programmed"
 
@@ -172,6 +214,16 @@ const (
 	// TrafficManagerProfileReasonPending is used with the "Programmed" when creating or updating the profile hits an internal error
 	// with more details in the message and the controller will keep retry.
 	TrafficManagerProfileReasonPending TrafficManagerProfileConditionReason = "Pending"
+
+	// TrafficManagerProfileReasonReadOnly is used with the "Programmed" condition when the condition is Unknown and the
+	// controller runs in the read-only mode, so that the Azure Traffic Manager profile is observed but not created,
+	// updated or deleted.
+	TrafficManagerProfileReasonReadOnly TrafficManagerProfileConditionReason = "ReadOnly"
+
+	// TrafficManagerProfileReasonNamespaceNotAllowed is used with the "Programmed" condition when the condition is False
+	// and the namespace of the profile is not allowed to use Azure Traffic Manager by the hub controller, so that the
+	// Azure Traffic Manager profile is neither created nor updated.
+	TrafficManagerProfileReasonNamespaceNotAllowed TrafficManagerProfileConditionReason = "NamespaceNotAllowed"
 )
 
 //+kubebuilder:object:root=true
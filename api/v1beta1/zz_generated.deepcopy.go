@@ -68,6 +68,11 @@ func (in *MonitorConfig) DeepCopyInto(out *MonitorConfig) {
 		*out = new(int64)
 		**out = **in
 	}
+	if in.InheritPortFromBackend != nil {
+		in, out := &in.InheritPortFromBackend, &out.InheritPortFromBackend
+		*out = new(bool)
+		**out = **in
+	}
 	if in.Protocol != nil {
 		in, out := &in.Protocol, &out.Protocol
 		*out = new(TrafficManagerMonitorProtocol)
@@ -179,6 +184,23 @@ func (in *TrafficManagerBackendSpec) DeepCopyInto(out *TrafficManagerBackendSpec
 		*out = new(int64)
 		**out = **in
 	}
+	if in.CustomHeaders != nil {
+		in, out := &in.CustomHeaders, &out.CustomHeaders
+		*out = make([]TrafficManagerEndpointCustomHeader, len(*in))
+		copy(*out, *in)
+	}
+	if in.SubnetOverrides != nil {
+		in, out := &in.SubnetOverrides, &out.SubnetOverrides
+		*out = make([]TrafficManagerSubnetOverride, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Priorities != nil {
+		in, out := &in.Priorities, &out.Priorities
+		*out = make([]TrafficManagerClusterPriority, len(*in))
+		copy(*out, *in)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TrafficManagerBackendSpec.
@@ -208,6 +230,15 @@ func (in *TrafficManagerBackendStatus) DeepCopyInto(out *TrafficManagerBackendSt
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.LastSyncedTime != nil {
+		in, out := &in.LastSyncedTime, &out.LastSyncedTime
+		*out = (*in).DeepCopy()
+	}
+	if in.InvalidClusters != nil {
+		in, out := &in.InvalidClusters, &out.InvalidClusters
+		*out = make([]TrafficManagerInvalidCluster, len(*in))
+		copy(*out, *in)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TrafficManagerBackendStatus.
@@ -220,6 +251,36 @@ func (in *TrafficManagerBackendStatus) DeepCopy() *TrafficManagerBackendStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TrafficManagerClusterPriority) DeepCopyInto(out *TrafficManagerClusterPriority) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TrafficManagerClusterPriority.
+func (in *TrafficManagerClusterPriority) DeepCopy() *TrafficManagerClusterPriority {
+	if in == nil {
+		return nil
+	}
+	out := new(TrafficManagerClusterPriority)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TrafficManagerEndpointCustomHeader) DeepCopyInto(out *TrafficManagerEndpointCustomHeader) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TrafficManagerEndpointCustomHeader.
+func (in *TrafficManagerEndpointCustomHeader) DeepCopy() *TrafficManagerEndpointCustomHeader {
+	if in == nil {
+		return nil
+	}
+	out := new(TrafficManagerEndpointCustomHeader)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *TrafficManagerEndpointStatus) DeepCopyInto(out *TrafficManagerEndpointStatus) {
 	*out = *in
@@ -238,6 +299,21 @@ func (in *TrafficManagerEndpointStatus) DeepCopyInto(out *TrafficManagerEndpoint
 		*out = new(FromCluster)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.CustomHeaders != nil {
+		in, out := &in.CustomHeaders, &out.CustomHeaders
+		*out = make([]TrafficManagerEndpointCustomHeader, len(*in))
+		copy(*out, *in)
+	}
+	if in.Subnets != nil {
+		in, out := &in.Subnets, &out.Subnets
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Priority != nil {
+		in, out := &in.Priority, &out.Priority
+		*out = new(int64)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TrafficManagerEndpointStatus.
@@ -250,6 +326,21 @@ func (in *TrafficManagerEndpointStatus) DeepCopy() *TrafficManagerEndpointStatus
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TrafficManagerInvalidCluster) DeepCopyInto(out *TrafficManagerInvalidCluster) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TrafficManagerInvalidCluster.
+func (in *TrafficManagerInvalidCluster) DeepCopy() *TrafficManagerInvalidCluster {
+	if in == nil {
+		return nil
+	}
+	out := new(TrafficManagerInvalidCluster)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *TrafficManagerProfile) DeepCopyInto(out *TrafficManagerProfile) {
 	*out = *in
@@ -359,6 +450,10 @@ func (in *TrafficManagerProfileStatus) DeepCopyInto(out *TrafficManagerProfileSt
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.LastSyncedTime != nil {
+		in, out := &in.LastSyncedTime, &out.LastSyncedTime
+		*out = (*in).DeepCopy()
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TrafficManagerProfileStatus.
@@ -370,3 +465,23 @@ func (in *TrafficManagerProfileStatus) DeepCopy() *TrafficManagerProfileStatus {
 	in.DeepCopyInto(out)
 	return out
 }
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TrafficManagerSubnetOverride) DeepCopyInto(out *TrafficManagerSubnetOverride) {
+	*out = *in
+	if in.Subnets != nil {
+		in, out := &in.Subnets, &out.Subnets
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TrafficManagerSubnetOverride.
+func (in *TrafficManagerSubnetOverride) DeepCopy() *TrafficManagerSubnetOverride {
+	if in == nil {
+		return nil
+	}
+	out := new(TrafficManagerSubnetOverride)
+	in.DeepCopyInto(out)
+	return out
+}
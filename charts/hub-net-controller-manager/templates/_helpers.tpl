@@ -49,3 +49,19 @@ Selector labels
 app.kubernetes.io/name: {{ include "hub-net-controller-manager.name" . }}
 app.kubernetes.io/instance: {{ .Release.Name }}
 {{- end }}
+
+{{/*
+Merge patch switching the traffic manager CRDs to the Webhook conversion strategy, served by the webhook Service of
+this release. With webhookCertManager, cert-manager injects the CA bundle of the serving certificate into the CRDs.
+*/}}
+{{- define "hub-net-controller-manager.conversionPatch" -}}
+{{- $fullname := include "hub-net-controller-manager.fullname" . }}
+{{- $service := dict "namespace" .Values.fleetSystemNamespace "name" (printf "%s-webhook" $fullname) "path" "/convert" "port" 443 }}
+{{- $webhook := dict "conversionReviewVersions" (list "v1") "clientConfig" (dict "service" $service) }}
+{{- $patch := dict "spec" (dict "conversion" (dict "strategy" "Webhook" "webhook" $webhook)) }}
+{{- if .Values.webhookCertManager }}
+{{- $annotations := dict "cert-manager.io/inject-ca-from" (printf "%s/%s-serving-cert" .Values.fleetSystemNamespace $fullname) }}
+{{- $_ := set $patch "metadata" (dict "annotations" $annotations) }}
+{{- end }}
+{{- toJson $patch }}
+{{- end }}
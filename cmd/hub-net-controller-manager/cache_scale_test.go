@@ -0,0 +1,194 @@
+/*
+Copyright (c) Microsoft Corporation.
+Licensed under the MIT license.
+*/
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"golang.org/x/sync/errgroup"
+	corev1 "k8s.io/api/core/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/rest"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/envtest"
+
+	fleetnetv1alpha1 "go.goms.io/fleet-networking/api/v1alpha1"
+)
+
+const (
+	// runScaleTestsEnv enables the scale tests, which seed thousands of objects and take minutes to run.
+	runScaleTestsEnv = "RUN_SCALE_TESTS"
+
+	// scaleFixtureExports is the number of EndpointSliceExports seeded in the hub cluster, as exported by
+	// scaleFixtureMembers member clusters.
+	scaleFixtureExports      = 10000
+	scaleFixtureMembers      = 200
+	scaleFixtureEndpoints    = 20
+	scaleFixtureSeedingLimit = 20
+	scaleFixtureFieldManager = "member-net-controller-manager"
+)
+
+// TestHubCacheOptions_ScaleFixture reports the heap held by the hub cache of EndpointSliceExports, with the default
+// cache options and with the ones of the hub manager, against an envtest API server seeded with the scale fixture.
+func TestHubCacheOptions_ScaleFixture(t *testing.T) {
+	if os.Getenv(runScaleTestsEnv) != "true" {
+		t.Skipf("Skipping the scale tests; set %s=true to run them", runScaleTestsEnv)
+	}
+
+	testEnv := &envtest.Environment{
+		CRDDirectoryPaths:     []string{filepath.Join("..", "..", "config", "crd", "bases")},
+		ErrorIfCRDPathMissing: true,
+	}
+	cfg, err := testEnv.Start()
+	if err != nil {
+		t.Fatalf("Failed to start the test environment: %v", err)
+	}
+	defer func() {
+		if err := testEnv.Stop(); err != nil {
+			t.Errorf("Failed to stop the test environment: %v", err)
+		}
+	}()
+	k8sClient, err := client.New(cfg, client.Options{Scheme: scheme})
+	if err != nil {
+		t.Fatalf("Failed to create the client: %v", err)
+	}
+
+	ctx := context.Background()
+	seedEndpointSliceExports(ctx, t, k8sClient)
+
+	defaultHeap := cachedEndpointSliceExportsHeap(ctx, t, cfg, cache.Options{})
+	hubHeap := cachedEndpointSliceExportsHeap(ctx, t, cfg, hubCacheOptions())
+	t.Logf("Heap held by the cache of %d endpointSliceExports: %d KiB with the default cache options, %d KiB with the hub cache options (%.0f%% less)",
+		scaleFixtureExports, defaultHeap/1024, hubHeap/1024, 100*(1-float64(hubHeap)/float64(defaultHeap)))
+	if hubHeap >= defaultHeap {
+		t.Errorf("Heap held with the hub cache options, got %d bytes, want less than the %d bytes held with the default options", hubHeap, defaultHeap)
+	}
+}
+
+// seedEndpointSliceExports applies the EndpointSliceExports of the scale fixture, the way the member agents do, so that
+// they carry the managed fields of the member agents.
+func seedEndpointSliceExports(ctx context.Context, t *testing.T, k8sClient client.Client) {
+	for i := 0; i < scaleFixtureMembers; i++ {
+		ns := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: fmt.Sprintf("fleet-member-member-%d", i)}}
+		if err := k8sClient.Create(ctx, ns); err != nil {
+			t.Fatalf("Failed to create namespace %s: %v", ns.Name, err)
+		}
+	}
+
+	g, gctx := errgroup.WithContext(ctx)
+	g.SetLimit(scaleFixtureSeedingLimit)
+	for i := 0; i < scaleFixtureExports; i++ {
+		endpoints := make([]fleetnetv1alpha1.Endpoint, 0, scaleFixtureEndpoints)
+		for j := 0; j < scaleFixtureEndpoints; j++ {
+			endpoints = append(endpoints, fleetnetv1alpha1.Endpoint{Addresses: []string{fmt.Sprintf("10.%d.%d.%d", i/256, i%256, j)}})
+		}
+		endpointSliceExport := &fleetnetv1alpha1.EndpointSliceExport{
+			TypeMeta: metav1.TypeMeta{
+				APIVersion: fleetnetv1alpha1.GroupVersion.String(),
+				Kind:       "EndpointSliceExport",
+			},
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: fmt.Sprintf("fleet-member-member-%d", i%scaleFixtureMembers),
+				Name:      fmt.Sprintf("work-app-%d", i),
+			},
+			Spec: fleetnetv1alpha1.EndpointSliceExportSpec{
+				AddressType: discoveryv1.AddressTypeIPv4,
+				Endpoints:   endpoints,
+				Ports: []discoveryv1.EndpointPort{
+					{
+						Name:     ptr.To("http"),
+						Protocol: ptr.To(corev1.ProtocolTCP),
+						Port:     ptr.To(int32(8080)),
+					},
+				},
+				EndpointSliceReference: fleetnetv1alpha1.ExportedObjectReference{
+					ClusterID:       fmt.Sprintf("member-%d", i%scaleFixtureMembers),
+					Kind:            "EndpointSlice",
+					Namespace:       "work",
+					Name:            fmt.Sprintf("app-%d", i),
+					ResourceVersion: "1",
+					UID:             "0",
+					NamespacedName:  fmt.Sprintf("work/app-%d", i),
+					ExportedSince:   metav1.Now(),
+				},
+				OwnerServiceReference: fleetnetv1alpha1.OwnerServiceReference{
+					Namespace:      "work",
+					Name:           fmt.Sprintf("app-%d", i),
+					NamespacedName: fmt.Sprintf("work/app-%d", i),
+				},
+			},
+		}
+		g.Go(func() error {
+			return k8sClient.Patch(gctx, endpointSliceExport, client.Apply, client.FieldOwner(scaleFixtureFieldManager), client.ForceOwnership)
+		})
+	}
+	if err := g.Wait(); err != nil {
+		t.Fatalf("Failed to seed the endpointSliceExports: %v", err)
+	}
+}
+
+// cachedEndpointSliceExportsHeap starts a cache with the given options, waits until it holds all the
+// EndpointSliceExports of the scale fixture, and returns the growth of the live heap; the cache is stopped before it
+// returns.
+func cachedEndpointSliceExportsHeap(ctx context.Context, t *testing.T, cfg *rest.Config, opts cache.Options) int64 {
+	opts.Scheme = scheme
+	before := liveHeap()
+
+	cacheCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	c, err := cache.New(cfg, opts)
+	if err != nil {
+		t.Fatalf("Failed to create the cache: %v", err)
+	}
+	if _, err := c.GetInformer(cacheCtx, &fleetnetv1alpha1.EndpointSliceExport{}); err != nil {
+		t.Fatalf("Failed to get the endpointSliceExport informer: %v", err)
+	}
+	stopped := make(chan struct{})
+	go func() {
+		defer close(stopped)
+		if err := c.Start(cacheCtx); err != nil {
+			t.Errorf("Failed to start the cache: %v", err)
+		}
+	}()
+	defer func() {
+		cancel()
+		<-stopped
+	}()
+	if !c.WaitForCacheSync(cacheCtx) {
+		t.Fatalf("Failed to sync the cache")
+	}
+	if got := cachedEndpointSliceExports(cacheCtx, t, c); got != scaleFixtureExports {
+		t.Fatalf("Cached endpointSliceExports, got %d, want %d", got, scaleFixtureExports)
+	}
+	return liveHeap() - before
+}
+
+// cachedEndpointSliceExports returns the number of EndpointSliceExports in the cache; the listed copies are
+// dropped once it returns, so that they are not measured as held by the cache.
+func cachedEndpointSliceExports(ctx context.Context, t *testing.T, c cache.Cache) int {
+	endpointSliceExportList := &fleetnetv1alpha1.EndpointSliceExportList{}
+	if err := c.List(ctx, endpointSliceExportList); err != nil {
+		t.Fatalf("Failed to list the cached endpointSliceExports: %v", err)
+	}
+	return len(endpointSliceExportList.Items)
+}
+
+// liveHeap returns the bytes of the heap objects which are reachable after a garbage collection.
+func liveHeap() int64 {
+	var stats runtime.MemStats
+	runtime.GC()
+	runtime.GC()
+	runtime.ReadMemStats(&stats)
+	return int64(stats.HeapAlloc)
+}
@@ -8,14 +8,17 @@ Licensed under the MIT license.
 package main
 
 import (
+	"context"
 	"flag"
 	"fmt"
+	"net/http"
 	"os"
 	"time"
 
 	// Import all Kubernetes client auth plugins (e.g. Azure, GCP, OIDC, etc.)
 	// to ensure that exec-entrypoint and run can make use of them.
 	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/trafficmanager/armtrafficmanager"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/util/rand"
@@ -27,7 +30,10 @@ import (
 	"sigs.k8s.io/cloud-provider-azure/pkg/azclient"
 	"sigs.k8s.io/cloud-provider-azure/pkg/azclient/policy/ratelimit"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/healthz"
+	ctrlmetrics "sigs.k8s.io/controller-runtime/pkg/metrics"
 	metricsserver "sigs.k8s.io/controller-runtime/pkg/metrics/server"
 	"sigs.k8s.io/controller-runtime/pkg/webhook"
 
@@ -38,13 +44,18 @@ import (
 
 	fleetnetv1alpha1 "go.goms.io/fleet-networking/api/v1alpha1"
 	fleetnetv1beta1 "go.goms.io/fleet-networking/api/v1beta1"
+	"go.goms.io/fleet-networking/pkg/common/apicompat"
+	"go.goms.io/fleet-networking/pkg/common/cachetransform"
+	"go.goms.io/fleet-networking/pkg/common/cloudconfig"
+	"go.goms.io/fleet-networking/pkg/common/consistency"
+	"go.goms.io/fleet-networking/pkg/common/reconcilewatchdog"
+	"go.goms.io/fleet-networking/pkg/common/version"
 	"go.goms.io/fleet-networking/pkg/controllers/hub/endpointsliceexport"
 	"go.goms.io/fleet-networking/pkg/controllers/hub/internalserviceexport"
 	"go.goms.io/fleet-networking/pkg/controllers/hub/internalserviceimport"
 	"go.goms.io/fleet-networking/pkg/controllers/hub/membercluster"
 	"go.goms.io/fleet-networking/pkg/controllers/hub/serviceimport"
-	"go.goms.io/fleet-networking/pkg/controllers/hub/trafficmanagerbackend"
-	"go.goms.io/fleet-networking/pkg/controllers/hub/trafficmanagerprofile"
+	trafficmanagerwebhook "go.goms.io/fleet-networking/pkg/webhook/trafficmanager"
 )
 
 var (
@@ -64,10 +75,66 @@ var (
 	forceDeleteWaitTime = flag.Duration("force-delete-wait-time", 15*time.Minute, "The duration the fleet hub agent waits before trying to force delete a member cluster.")
 
 	enableV1Beta1APIs = flag.Bool("enable-v1beta1-apis", true, "If set, the agents will watch for the v1beta1 APIs.")
+	requireV1Beta1    = flag.Bool("require-v1beta1", false, "If set, the hub agent exits when the v1beta1 APIs are not enabled or the v1beta1 MemberCluster CRD is not installed, instead of running without the memberCluster controller; set it once the fleet has completed the migration to the v1beta1 APIs.")
+
+	enableEndpointSliceExportController   = flag.Bool("enable-endpointsliceexport-controller", true, "If set, the endpointSliceExport controller is started. When disabled, its watches and indexes are not registered.")
+	enableInternalServiceExportController = flag.Bool("enable-internalserviceexport-controller", true, "If set, the internalServiceExport controller is started. When disabled, its watches and indexes are not registered.")
+	enableInternalServiceImportController = flag.Bool("enable-internalserviceimport-controller", true, "If set, the internalServiceImport controller is started. When disabled, its watches and indexes are not registered.")
+	enableServiceImportController         = flag.Bool("enable-serviceimport-controller", true, "If set, the serviceImport controller is started. When disabled, its watches and indexes are not registered.")
+	enableMemberClusterController         = flag.Bool("enable-membercluster-controller", true, "If set, the memberCluster controller is started when the v1beta1 APIs are enabled and installed. When disabled, its watches are not registered.")
+
+	endpointSliceExportOrphanGracePeriod            = flag.Duration("endpointsliceexport-orphan-grace-period", 5*time.Minute, "How long an EndpointSliceExport may exist, counting from when it is exported, without the InternalServiceExport of its owner service before it is deleted as an orphan, together with the EndpointSliceImports distributed from it. Set to 0 to disable the cleanup.")
+	endpointSliceExportDeletionCandidateGracePeriod = flag.Duration("endpointsliceexport-deletion-candidate-grace-period", 30*time.Second, "How long an EndpointSliceExport marked as a deletion candidate by the member agent, e.g. while its ServiceExport is revalidated after an agent restart, keeps being distributed before it is deleted, unless the member agent exports the EndpointSlice again. Set to 0 to delete the deletion candidates right away.")
 
 	enableTrafficManagerFeature = flag.Bool("enable-traffic-manager-feature", false, "If set, the traffic manager feature will be enabled.")
 
+	enableDefaultingWebhook = flag.Bool("enable-defaulting-webhook", false, "If set, the defaulting webhooks of the traffic manager APIs will be served by the webhook server.")
+
+	enableConversionWebhook = flag.Bool("enable-conversion-webhook", false, "If set, the conversion webhook between the v1alpha1 and v1beta1 traffic manager APIs will be served by the webhook server.")
+
+	atmEndpointMaxStaleness = flag.Duration("atm-endpoint-max-staleness", 15*time.Minute, "The maximum duration since the hub agent last observed a new heartbeat of an exported service before it is excluded from the Azure Traffic Manager endpoints. Set to 0 to disable the check.")
+
+	trafficManagerBackendShardCount = flag.Int("traffic-manager-backend-shard-count", 1, "The number of shards the trafficManagerBackends are split into by the hash of their namespaced names. When greater than 1, the trafficManagerBackend controller runs on every replica without leader election and reconciles only the backends of its own shard; the other controllers keep using leader election.")
+	trafficManagerBackendShardIndex = flag.Int("traffic-manager-backend-shard-index", -1, "The index of the trafficManagerBackend shard reconciled by this replica. When negative, it is derived from the StatefulSet ordinal suffix of the pod name given by the POD_NAME environment variable. Used only when the shard count is greater than 1.")
+
+	atmEndpointMaxConcurrentRequests = flag.Int("atm-endpoint-max-concurrent-requests", 16, "The maximum number of concurrent Azure Traffic Manager requests creating, updating or deleting the endpoints across all the trafficManagerBackends. The limit is halved whenever Azure throttles a request and gradually recovers as the requests succeed. Set to 0 to disable the limit.")
+
+	trafficManagerBackendStartupJitterWindow = flag.Duration("traffic-manager-backend-startup-jitter-window", 10*time.Second, "The window over which the first reconciliations of the trafficManagerBackends are spread after the controller starts, so that a restarted hub agent does not send the requests of all the backends to Azure at once. Set to 0 to disable the jitter.")
+
+	atmBulkEndpointUpdateThreshold = flag.Int("atm-bulk-endpoint-update-threshold", 5, "The number of Azure Traffic Manager endpoint creations or updates in a single trafficManagerBackend reconciliation above which the endpoints are submitted with a single profile update instead of one request per endpoint. Set to 0 to disable the bulk update.")
+
+	atmRejectLocalExternalTrafficPolicy = flag.Bool("atm-reject-local-external-traffic-policy", false, "If set, the exported services whose external traffic policy is Local are excluded from the Azure Traffic Manager endpoints instead of being only reported in the trafficManagerBackend condition.")
+
+	atmLastSyncedTimeUpdateInterval = flag.Duration("atm-last-synced-time-update-interval", time.Minute, "The granularity of the last synced time reported in the status of the trafficManagerProfiles and trafficManagerBackends; it is refreshed after a successful reconciliation only when it is older than the interval.")
+
+	atmProfileMonitorStatusResyncInterval = flag.Duration("atm-profile-monitor-status-resync-interval", 5*time.Minute, "The interval at which the trafficManagerProfiles are reconciled again, so that the monitor status reported by Azure Traffic Manager is refreshed in their status and metrics. Set to 0 to disable the resync.")
+
+	atmProfileCacheTTL = flag.Duration("atm-profile-cache-ttl", 5*time.Second, "The duration for which an Azure Traffic Manager profile read by the trafficManagerBackend controller is reused by the other backends of the profile, so that the backends enqueued together do not read the same profile from Azure; a cached profile is dropped as soon as the controller changes its endpoints. Set to 0 to disable the cache.")
+
+	atmCheckDNSNameAvailability = flag.Bool("atm-check-dns-name-availability", false, "If set, the relative DNS name of a trafficManagerProfile is checked for availability with Azure before its Azure Traffic Manager profile is first created, so that a taken name is reported faster.")
+
+	azureReadOnly = flag.Bool("azure-read-only", false, "If set, the trafficManagerProfile and trafficManagerBackend controllers only read the Azure Traffic Manager resources: the requests which would create, update or delete them are logged and reported with the ReadOnly condition reason instead of being sent, and the finalizers of the deleted objects are kept while their Azure resources exist.")
+
+	trafficManagerAllowedNamespaces = flag.String("traffic-manager-allowed-namespaces", "", "The comma separated namespaces whose trafficManagerProfiles and trafficManagerBackends are programmed; the ones of the other namespaces are refused with the NamespaceNotAllowed condition reason without calling Azure, and the Azure resources previously created for them are left as is. All the namespaces are allowed if empty.")
+
+	trafficManagerStrict = flag.Bool("traffic-manager-strict", false, "If set, the hub agent exits when the traffic manager feature is enabled but the Azure Traffic Manager clients cannot be built from the cloud config; otherwise, only the traffic manager controllers are disabled and the other controllers keep running.")
+
 	cloudConfigFile = flag.String("cloud-config", "/etc/kubernetes/provider/azure.json", "The path to the cloud config file which will be used to access the Azure resource.")
+
+	cloudConfigReloadInterval = flag.Duration("cloud-config-reload-interval", time.Minute, "How often the cloud config file is checked for changes, e.g., after the Azure resources are moved to another subscription or resource group; the Azure clients are rebuilt without a restart when it has changed. Set to 0 to disable the reload.")
+
+	enableConsistencyChecker = flag.Bool("enable-consistency-checker", false, "If set, the EndpointSliceExports and EndpointSliceImports across the fleet are periodically compared; the missing and orphaned EndpointSliceImports are reported through the logs and metrics without being modified.")
+	consistencyCheckInterval = flag.Duration("consistency-check-interval", 10*time.Minute, "How often the consistency checker runs. Used only when the consistency checker is enabled.")
+	consistencyReportNS      = flag.String("consistency-report-namespace", "fleet-system", "The namespace of the ConfigMap the consistency checker writes the summary of the last check to.")
+	consistencyReportName    = flag.String("consistency-report-configmap", "", "The name of the ConfigMap the consistency checker writes the summary of the last check to. No ConfigMap is written if empty.")
+
+	reconcileStuckThreshold     = flag.Duration("reconcile-stuck-threshold", reconcilewatchdog.DefaultThreshold, "How long a single reconciliation of a controller may run before it is reported stuck, with an error log naming the object and the fleet_networking_reconcile_stuck_total metric; the reconciliation is not interrupted. Set to 0 to disable the detection.")
+	reconcileStuckGoroutineDump = flag.Bool("reconcile-stuck-goroutine-dump", false, "If set, the stacks of all the goroutines are written to stderr the first time a reconciliation is reported stuck in the process.")
+)
+
+const (
+	// podNameEnvKey is the environment variable holding the name of the pod, set by the downward API.
+	podNameEnvKey = "POD_NAME"
 )
 
 var (
@@ -105,11 +172,23 @@ func main() {
 		klog.InfoS("flag:", "name", f.Name, "value", f.Value)
 	})
 
+	buildInfo := version.Get("hub-net-controller-manager", version.Features{
+		V1Beta1Enabled:        *enableV1Beta1APIs,
+		TrafficManagerEnabled: *enableTrafficManagerFeature,
+		NetworkingFeaturesEnabled: *enableEndpointSliceExportController || *enableInternalServiceExportController ||
+			*enableInternalServiceImportController || *enableServiceImportController,
+	}, flag.CommandLine)
+	klog.InfoS("Build info", "version", buildInfo.Version, "goVersion", buildInfo.GoVersion, "features", buildInfo.Features)
+	ctrlmetrics.Registry.MustRegister(buildInfo.Collector())
+
 	hubConfig := ctrl.GetConfigOrDie()
 	mgr, err := ctrl.NewManager(hubConfig, ctrl.Options{
 		Scheme: scheme,
+		Cache:  hubCacheOptions(),
 		Metrics: metricsserver.Options{
 			BindAddress: *metricsAddr,
+			// The probe server of the manager cannot be extended, so the build info is served next to the metrics.
+			ExtraHandlers: map[string]http.Handler{version.Path: buildInfo.Handler()},
 		},
 		WebhookServer: webhook.NewServer(webhook.Options{
 			Port: 9443,
@@ -137,55 +216,60 @@ func main() {
 
 	ctx := ctrl.SetupSignalHandler()
 
-	klog.V(1).InfoS("Start to setup EndpointsliceExport controller")
-	if err := (&endpointsliceexport.Reconciler{
-		HubClient: mgr.GetClient(),
-	}).SetupWithManager(ctx, mgr); err != nil {
-		klog.ErrorS(err, "Unable to create EndpointsliceExport controller")
-		exitWithErrorFunc()
+	discoverClient := discovery.NewDiscoveryClientForConfigOrDie(hubConfig)
+	controllers := controllerToggles{
+		endpointSliceExport:   *enableEndpointSliceExportController,
+		internalServiceExport: *enableInternalServiceExportController,
+		internalServiceImport: *enableInternalServiceImportController,
+		serviceImport:         *enableServiceImportController,
 	}
-
-	klog.V(1).InfoS("Start to setup InternalServiceExport controller")
-	if err := (&internalserviceexport.Reconciler{
-		Client:        mgr.GetClient(),
-		RetryInternal: *internalServiceExportRetryInterval,
-	}).SetupWithManager(mgr); err != nil {
-		klog.ErrorS(err, "Unable to create InternalServiceExport controller")
+	if *requireV1Beta1 && !*enableV1Beta1APIs {
+		klog.ErrorS(fmt.Errorf("the v1beta1 APIs are required but not enabled"), "Invalid flags; set --enable-v1beta1-apis along with --require-v1beta1")
 		exitWithErrorFunc()
 	}
-
-	klog.V(1).InfoS("Start to setup InternalServiceImport controller")
-	if err := (&internalserviceimport.Reconciler{
-		HubClient: mgr.GetClient(),
-	}).SetupWithManager(ctx, mgr); err != nil {
-		klog.ErrorS(err, "Unable to create InternalServiceImport controller")
-		exitWithErrorFunc()
+	if *enableV1Beta1APIs {
+		gvk := clusterv1beta1.GroupVersion.WithKind(clusterv1beta1.MemberClusterKind)
+		if err := utils.CheckCRDInstalled(discoverClient, gvk); err != nil {
+			if *requireV1Beta1 {
+				klog.ErrorS(err, "The v1beta1 APIs are required but the v1beta1 MemberCluster CRD is not installed", "GVK", gvk)
+				exitWithErrorFunc()
+			}
+			klog.InfoS("The v1beta1 MemberCluster CRD is not installed; the memberCluster controller is not started", "GVK", gvk)
+		} else {
+			controllers.memberCluster = *enableMemberClusterController
+			logMemberAgentAPIVersions(ctx, mgr.GetAPIReader())
+		}
 	}
-
-	klog.V(1).InfoS("Start to setup ServiceImport controller")
-	if err := (&serviceimport.Reconciler{
-		Client:   mgr.GetClient(),
-		Recorder: mgr.GetEventRecorderFor(serviceimport.ControllerName),
-	}).SetupWithManager(ctx, mgr); err != nil {
-		klog.ErrorS(err, "Unable to create ServiceImport controller")
+	for _, w := range controllers.warnings() {
+		klog.Warning(w)
+	}
+	reconcilewatchdog.Configure(reconcilewatchdog.Options{
+		Threshold:      *reconcileStuckThreshold,
+		DumpGoroutines: *reconcileStuckGoroutineDump,
+	})
+	if err := setupControllers(ctx, mgr, controllers); err != nil {
+		klog.ErrorS(err, "Unable to set up the controllers")
 		exitWithErrorFunc()
 	}
 
-	discoverClient := discovery.NewDiscoveryClientForConfigOrDie(hubConfig)
-	if *enableV1Beta1APIs {
-		gvk := clusterv1beta1.GroupVersion.WithKind(clusterv1beta1.MemberClusterKind)
-		if utils.CheckCRDInstalled(discoverClient, gvk) == nil {
-			klog.V(1).InfoS("Start to setup MemberCluster controller")
-			if err := (&membercluster.Reconciler{
-				Client:              mgr.GetClient(),
-				Recorder:            mgr.GetEventRecorderFor(membercluster.ControllerName),
-				ForceDeleteWaitTime: *forceDeleteWaitTime,
-			}).SetupWithManager(mgr); err != nil {
-				klog.ErrorS(err, "Unable to create MemberCluster controller")
+	if *enableConversionWebhook {
+		// The v1beta1 traffic manager APIs are the conversion hub; the webhook server serves the /convert endpoint
+		// which the API server calls to convert the objects between the v1alpha1 and v1beta1 versions.
+		klog.V(1).InfoS("Start to setup the traffic manager conversion webhook")
+		for _, obj := range []runtime.Object{&fleetnetv1beta1.TrafficManagerProfile{}, &fleetnetv1beta1.TrafficManagerBackend{}} {
+			if err := ctrl.NewWebhookManagedBy(mgr).For(obj).Complete(); err != nil {
+				klog.ErrorS(err, "Unable to create the conversion webhook", "type", fmt.Sprintf("%T", obj))
 				exitWithErrorFunc()
 			}
 		}
 	}
+	if *enableDefaultingWebhook {
+		klog.V(1).InfoS("Start to setup the traffic manager defaulting webhooks")
+		if err := trafficmanagerwebhook.SetupWebhooksWithManager(mgr); err != nil {
+			klog.ErrorS(err, "Unable to create the defaulting webhooks")
+			exitWithErrorFunc()
+		}
+	}
 	if *enableTrafficManagerFeature {
 		klog.V(1).InfoS("Traffic manager feature is enabled, checking the required CRDs")
 		for _, gvk := range trafficManagerFeatureRequiredGVKs {
@@ -195,40 +279,22 @@ func main() {
 			}
 		}
 
-		klog.V(1).InfoS("Traffic manager feature is enabled, loading cloud config and creating azure clients", "cloudConfigFile", *cloudConfigFile)
-		cloudConfig, err := azure.NewCloudConfigFromFile(*cloudConfigFile)
-		if err != nil {
-			klog.ErrorS(err, "Unable to load cloud config", "file name", *cloudConfigFile)
-			exitWithErrorFunc()
-		}
-		cloudConfig.SetUserAgent("fleet-hub-net-controller-manager")
-		klog.V(1).InfoS("Cloud config loaded", "cloudConfig", cloudConfig)
-
-		profilesClient, endpointsClient, err := initAzureTrafficManagerClients(cloudConfig) // profilesClient, endpointsClient, err
-		if err != nil {
-			klog.ErrorS(err, "Unable to create Azure Traffic Manager clients")
-			exitWithErrorFunc()
-		}
-		klog.V(1).InfoS("Start to setup TrafficManagerProfile controller")
-		if err := (&trafficmanagerprofile.Reconciler{
-			Client:            mgr.GetClient(),
-			ProfilesClient:    profilesClient,
-			ResourceGroupName: cloudConfig.ResourceGroup,
-		}).SetupWithManager(mgr); err != nil {
-			klog.ErrorS(err, "Unable to create TrafficManagerProfile controller")
+		if err := setupTrafficManagerFeature(ctx, mgr, controllers.serviceImport, *trafficManagerStrict, initAzureTrafficManagerClients); err != nil {
+			klog.ErrorS(err, "Unable to set up the traffic manager feature")
 			exitWithErrorFunc()
 		}
+	}
 
-		klog.V(1).InfoS("Start to setup TrafficManagerBackend controller")
-		if err := (&trafficmanagerbackend.Reconciler{
-			Client:            mgr.GetClient(),
-			ProfilesClient:    profilesClient,
-			EndpointsClient:   endpointsClient,
-			ResourceGroupName: cloudConfig.ResourceGroup,
-			// serviceImport controller has already enabled the internalServiceExportIndexer.
-			// Therefore, no need to setup it again.
-		}).SetupWithManager(ctx, mgr, true); err != nil {
-			klog.ErrorS(err, "Unable to create TrafficManagerProfile controller")
+	if *enableConsistencyChecker {
+		klog.V(1).InfoS("Start to setup the consistency checker", "interval", *consistencyCheckInterval)
+		if err := mgr.Add(&consistency.Checker{
+			Reader:          mgr.GetAPIReader(),
+			Writer:          mgr.GetClient(),
+			Interval:        *consistencyCheckInterval,
+			ReportNamespace: *consistencyReportNS,
+			ReportName:      *consistencyReportName,
+		}); err != nil {
+			klog.ErrorS(err, "Unable to set up the consistency checker")
 			exitWithErrorFunc()
 		}
 	}
@@ -240,11 +306,135 @@ func main() {
 	}
 }
 
+// hubCacheOptions returns the options of the hub manager cache, which holds every EndpointSliceExport,
+// EndpointSliceImport and InternalServiceExport in the fleet and hence dominates the memory usage of the hub agent at
+// scale.
+func hubCacheOptions() cache.Options {
+	return cache.Options{
+		// The fields which are never read by the controllers are stripped. MemberCluster objects are only read, or
+		// merge-patched with the mirrored network properties, by the networking controllers and hence their
+		// last-applied-configuration annotations can be dropped as well; so are the objects watched with their
+		// metadata only, i.e. namespaces and InternalMemberClusters.
+		DefaultTransform: cachetransform.StripManagedFieldsAndReadOnlyAnnotations(&clusterv1beta1.MemberCluster{}, &metav1.PartialObjectMetadata{}),
+	}
+}
+
+// logMemberAgentAPIVersions logs, for every member cluster, whether its member agent runs with the v1beta1 APIs
+// enabled, i.e. reports its status on the v1beta1 InternalMemberCluster, so that the progress of the migration to the
+// v1beta1 APIs across the fleet can be told at a glance; the member agents fall back to the v1alpha1 APIs on their own
+// while the v1beta1 ones are rolled out.
+func logMemberAgentAPIVersions(ctx context.Context, reader client.Reader) {
+	memberClusters := &clusterv1beta1.MemberClusterList{}
+	if err := reader.List(ctx, memberClusters); err != nil {
+		klog.ErrorS(err, "Failed to list member clusters for the API compatibility matrix")
+		return
+	}
+	for i := range memberClusters.Items {
+		mc := &memberClusters.Items[i]
+		reportsV1Beta1 := apicompat.AgentReportsV1Beta1(mc, clusterv1beta1.ServiceExportImportAgent)
+		if !reportsV1Beta1 && *requireV1Beta1 {
+			klog.ErrorS(fmt.Errorf("the member agent does not report on the v1beta1 APIs"), "The v1beta1 APIs are required but the member agent has not been migrated, or has not joined yet", "memberCluster", klog.KObj(mc))
+			continue
+		}
+		klog.InfoS("Member agent API compatibility", "memberCluster", klog.KObj(mc), "v1beta1", reportsV1Beta1)
+	}
+}
+
+// controllerToggles holds which of the multi-cluster service controllers are started by the hub manager, so that
+// they can be rolled out in stages.
+type controllerToggles struct {
+	endpointSliceExport   bool
+	internalServiceExport bool
+	internalServiceImport bool
+	serviceImport         bool
+	memberCluster         bool
+}
+
+// warnings returns the messages describing the combinations of the enabled controllers which leave the exported or
+// imported services half-processed.
+func (t controllerToggles) warnings() []string {
+	var res []string
+	if t.internalServiceExport && !t.serviceImport {
+		res = append(res, "The internalserviceexport controller is enabled without the serviceimport controller: "+
+			"the ServiceImports created for the exported services are never resolved and the InternalServiceExports are requeued forever")
+	}
+	if t.serviceImport && !t.internalServiceExport {
+		res = append(res, "The serviceimport controller is enabled without the internalserviceexport controller: "+
+			"no ServiceImport is created for the newly exported services")
+	}
+	if t.internalServiceImport && !t.serviceImport {
+		res = append(res, "The internalserviceimport controller is enabled without the serviceimport controller: "+
+			"the InternalServiceImports are never fulfilled")
+	}
+	if t.endpointSliceExport && !t.serviceImport {
+		res = append(res, "The endpointsliceexport controller is enabled without the serviceimport controller: "+
+			"the EndpointSliceExports are not imported until the ServiceImports are resolved")
+	}
+	return res
+}
+
+// setupControllers sets up the enabled multi-cluster service controllers with the manager; the disabled
+// controllers register neither their watches nor their indexes, so that their objects are not cached.
+func setupControllers(ctx context.Context, mgr ctrl.Manager, t controllerToggles) error {
+	if t.endpointSliceExport {
+		klog.V(1).InfoS("Start to setup EndpointsliceExport controller")
+		if err := (&endpointsliceexport.Reconciler{
+			HubClient:                    mgr.GetClient(),
+			OrphanGracePeriod:            *endpointSliceExportOrphanGracePeriod,
+			DeletionCandidateGracePeriod: *endpointSliceExportDeletionCandidateGracePeriod,
+		}).SetupWithManager(ctx, mgr); err != nil {
+			return fmt.Errorf("failed to create EndpointsliceExport controller: %w", err)
+		}
+	}
+
+	if t.internalServiceExport {
+		klog.V(1).InfoS("Start to setup InternalServiceExport controller")
+		if err := (&internalserviceexport.Reconciler{
+			Client:        mgr.GetClient(),
+			RetryInternal: *internalServiceExportRetryInterval,
+		}).SetupWithManager(mgr); err != nil {
+			return fmt.Errorf("failed to create InternalServiceExport controller: %w", err)
+		}
+	}
+
+	if t.internalServiceImport {
+		klog.V(1).InfoS("Start to setup InternalServiceImport controller")
+		if err := (&internalserviceimport.Reconciler{
+			HubClient: mgr.GetClient(),
+		}).SetupWithManager(ctx, mgr); err != nil {
+			return fmt.Errorf("failed to create InternalServiceImport controller: %w", err)
+		}
+	}
+
+	if t.serviceImport {
+		klog.V(1).InfoS("Start to setup ServiceImport controller")
+		if err := (&serviceimport.Reconciler{
+			Client:                      mgr.GetClient(),
+			Recorder:                    mgr.GetEventRecorderFor(serviceimport.ControllerName),
+			EnableTrafficManagerFeature: *enableTrafficManagerFeature,
+		}).SetupWithManager(ctx, mgr); err != nil {
+			return fmt.Errorf("failed to create ServiceImport controller: %w", err)
+		}
+	}
+
+	if t.memberCluster {
+		klog.V(1).InfoS("Start to setup MemberCluster controller")
+		if err := (&membercluster.Reconciler{
+			Client:              mgr.GetClient(),
+			Recorder:            mgr.GetEventRecorderFor(membercluster.ControllerName),
+			ForceDeleteWaitTime: *forceDeleteWaitTime,
+		}).SetupWithManager(mgr); err != nil {
+			return fmt.Errorf("failed to create MemberCluster controller: %w", err)
+		}
+	}
+	return nil
+}
+
 // initAzureTrafficManagerClients initializes the Azure Traffic Manager profiles and endpoints clients.
-func initAzureTrafficManagerClients(cloudConfig *azure.CloudConfig) (*armtrafficmanager.ProfilesClient, *armtrafficmanager.EndpointsClient, error) {
+func initAzureTrafficManagerClients(cloudConfig *azure.CloudConfig) (cloudconfig.TrafficManagerClients, error) {
 	authProvider, err := azclient.NewAuthProvider(&cloudConfig.ARMClientConfig, &cloudConfig.AzureAuthConfig)
 	if err != nil {
-		return nil, nil, fmt.Errorf("failed to create Azure auth provider: %w", err)
+		return cloudconfig.TrafficManagerClients{}, fmt.Errorf("failed to create Azure auth provider: %w", err)
 	}
 
 	factoryConfig := &azclient.ClientFactoryConfig{
@@ -253,7 +443,7 @@ func initAzureTrafficManagerClients(cloudConfig *azure.CloudConfig) (*armtraffic
 	}
 	options, err := azclient.GetDefaultResourceClientOption(&cloudConfig.ARMClientConfig, factoryConfig)
 	if err != nil {
-		return nil, nil, fmt.Errorf("failed to get default resource client option: %w", err)
+		return cloudconfig.TrafficManagerClients{}, fmt.Errorf("failed to get default resource client option: %w", err)
 	}
 
 	if rateLimitPolicy := ratelimit.NewRateLimitPolicy(cloudConfig.Config); rateLimitPolicy != nil {
@@ -262,12 +452,16 @@ func initAzureTrafficManagerClients(cloudConfig *azure.CloudConfig) (*armtraffic
 
 	profilesClient, err := armtrafficmanager.NewProfilesClient(cloudConfig.SubscriptionID, authProvider.GetAzIdentity(), options)
 	if err != nil {
-		return nil, nil, fmt.Errorf("failed to create Azure trafficManager profiles client: %w", err)
+		return cloudconfig.TrafficManagerClients{}, fmt.Errorf("failed to create Azure trafficManager profiles client: %w", err)
 	}
 
 	endpointsClient, err := armtrafficmanager.NewEndpointsClient(cloudConfig.SubscriptionID, authProvider.GetAzIdentity(), options)
 	if err != nil {
-		return nil, nil, fmt.Errorf("failed to create Azure trafficManager endpoints client: %w", err)
+		return cloudconfig.TrafficManagerClients{}, fmt.Errorf("failed to create Azure trafficManager endpoints client: %w", err)
 	}
-	return profilesClient, endpointsClient, nil
+	return cloudconfig.TrafficManagerClients{
+		ProfilesClient:    profilesClient,
+		EndpointsClient:   endpointsClient,
+		ResourceGroupName: cloudConfig.ResourceGroup,
+	}, nil
 }
@@ -0,0 +1,258 @@
+/*
+Copyright (c) Microsoft Corporation.
+Licensed under the MIT license.
+*/
+
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"k8s.io/client-go/rest"
+	"k8s.io/utils/ptr"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/config"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	metricsserver "sigs.k8s.io/controller-runtime/pkg/metrics/server"
+
+	"go.goms.io/fleet/pkg/utils/cloudconfig/azure"
+
+	"go.goms.io/fleet-networking/pkg/common/cloudconfig"
+)
+
+// fakeManager wraps a manager which is never started and records the indexes and the runnables registered by the
+// controllers instead of adding them to the wrapped manager.
+type fakeManager struct {
+	ctrl.Manager
+
+	indexes   []string
+	runnables int
+}
+
+func (m *fakeManager) GetFieldIndexer() client.FieldIndexer {
+	return m
+}
+
+func (m *fakeManager) IndexField(_ context.Context, obj client.Object, field string, _ client.IndexerFunc) error {
+	m.indexes = append(m.indexes, fmt.Sprintf("%T %s", obj, field))
+	return nil
+}
+
+func (m *fakeManager) Add(_ manager.Runnable) error {
+	m.runnables++
+	return nil
+}
+
+func newFakeManager(t *testing.T) *fakeManager {
+	// The manager does not contact the API server until it is started.
+	mgr, err := ctrl.NewManager(&rest.Config{Host: "https://127.0.0.1:6443"}, ctrl.Options{
+		Scheme:  scheme,
+		Metrics: metricsserver.Options{BindAddress: "0"},
+		Controller: config.Controller{
+			// The same controllers are set up by every test case.
+			SkipNameValidation: ptr.To(true),
+		},
+	})
+	if err != nil {
+		t.Fatalf("NewManager() = %v", err)
+	}
+	return &fakeManager{Manager: mgr}
+}
+
+func TestSetupControllers(t *testing.T) {
+	const (
+		endpointSliceImportIndex = "*v1alpha1.EndpointSliceImport .metadata.name"
+		endpointSliceExportIndex = "*v1alpha1.EndpointSliceExport .spec.ownerServiceReference.namespacedName"
+		internalSvcImportIndex   = "*v1alpha1.InternalServiceImport .spec.serviceImportReference.namespacedName"
+		internalSvcExportIndex   = "*v1alpha1.InternalServiceExport .spec.serviceReference.namespacedName"
+		allControllers           = 5
+	)
+	allEnabled := controllerToggles{
+		endpointSliceExport:   true,
+		internalServiceExport: true,
+		internalServiceImport: true,
+		serviceImport:         true,
+		memberCluster:         true,
+	}
+	testCases := []struct {
+		name            string
+		toggles         controllerToggles
+		wantIndexes     []string
+		wantControllers int
+		wantWarnings    []string
+	}{
+		{
+			name:            "all controllers are enabled",
+			toggles:         allEnabled,
+			wantIndexes:     []string{endpointSliceImportIndex, endpointSliceExportIndex, internalSvcImportIndex, internalSvcExportIndex},
+			wantControllers: allControllers,
+		},
+		{
+			name: "member cluster controller is disabled",
+			toggles: controllerToggles{
+				endpointSliceExport:   true,
+				internalServiceExport: true,
+				internalServiceImport: true,
+				serviceImport:         true,
+			},
+			wantIndexes:     []string{endpointSliceImportIndex, endpointSliceExportIndex, internalSvcImportIndex, internalSvcExportIndex},
+			wantControllers: allControllers - 1,
+		},
+		{
+			name:            "all controllers are disabled",
+			toggles:         controllerToggles{},
+			wantControllers: 0,
+		},
+		{
+			name:            "only member cluster controller is enabled",
+			toggles:         controllerToggles{memberCluster: true},
+			wantControllers: 1,
+		},
+		{
+			name: "exports are processed without the imports",
+			toggles: controllerToggles{
+				internalServiceExport: true,
+				serviceImport:         true,
+			},
+			wantIndexes:     []string{internalSvcExportIndex},
+			wantControllers: 2,
+		},
+		{
+			name: "serviceImport controller is disabled",
+			toggles: controllerToggles{
+				endpointSliceExport:   true,
+				internalServiceExport: true,
+				internalServiceImport: true,
+				memberCluster:         true,
+			},
+			wantIndexes:     []string{endpointSliceImportIndex, endpointSliceExportIndex, internalSvcImportIndex},
+			wantControllers: allControllers - 1,
+			wantWarnings: []string{
+				"internalserviceexport controller is enabled without the serviceimport controller",
+				"internalserviceimport controller is enabled without the serviceimport controller",
+				"endpointsliceexport controller is enabled without the serviceimport controller",
+			},
+		},
+		{
+			name:            "serviceImport controller is enabled without the internalServiceExport controller",
+			toggles:         controllerToggles{serviceImport: true},
+			wantIndexes:     []string{internalSvcExportIndex},
+			wantControllers: 1,
+			wantWarnings: []string{
+				"serviceimport controller is enabled without the internalserviceexport controller",
+			},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			mgr := newFakeManager(t)
+			if err := setupControllers(context.Background(), mgr, tc.toggles); err != nil {
+				t.Fatalf("setupControllers() = %v, want nil", err)
+			}
+			if diff := cmp.Diff(tc.wantIndexes, mgr.indexes, cmpopts.EquateEmpty()); diff != "" {
+				t.Errorf("setupControllers() indexes mismatch (-want, +got):\n%s", diff)
+			}
+			if mgr.runnables != tc.wantControllers {
+				t.Errorf("setupControllers() added %d controllers, want %d", mgr.runnables, tc.wantControllers)
+			}
+
+			gotWarnings := tc.toggles.warnings()
+			if len(gotWarnings) != len(tc.wantWarnings) {
+				t.Fatalf("warnings() = %v, want %d warnings", gotWarnings, len(tc.wantWarnings))
+			}
+			for i := range gotWarnings {
+				if !strings.Contains(gotWarnings[i], tc.wantWarnings[i]) {
+					t.Errorf("warnings()[%d] = %q, want it to contain %q", i, gotWarnings[i], tc.wantWarnings[i])
+				}
+			}
+		})
+	}
+}
+
+// TestSetupTrafficManagerFeature tests that only the traffic manager controllers are disabled when the cloud config
+// is unusable, unless the strict mode is set.
+func TestSetupTrafficManagerFeature(t *testing.T) {
+	const validCloudConfig = `{
+  "cloud": "AzurePublicCloud",
+  "tenantId": "tenant",
+  "subscriptionId": "subscription",
+  "useManagedIdentityExtension": true,
+  "location": "westus",
+  "resourceGroup": "resource-group"
+}`
+	build := func(cloudConfig *azure.CloudConfig) (cloudconfig.TrafficManagerClients, error) {
+		return cloudconfig.TrafficManagerClients{ResourceGroupName: cloudConfig.ResourceGroup}, nil
+	}
+	testCases := []struct {
+		name          string
+		content       *string
+		strict        bool
+		wantErr       bool
+		wantRunnables int
+		wantDegraded  float64
+	}{
+		{
+			name:    "valid cloud config",
+			content: ptr.To(validCloudConfig),
+			// The cloud config reloader and the two controllers.
+			wantRunnables: 3,
+		},
+		{
+			name:         "malformed cloud config",
+			content:      ptr.To(`{"cloud": "AzurePublicCloud",`),
+			wantDegraded: 1,
+		},
+		{
+			name:         "missing cloud config",
+			wantDegraded: 1,
+		},
+		{
+			name:    "malformed cloud config in the strict mode",
+			content: ptr.To(`{"cloud": "AzurePublicCloud",`),
+			strict:  true,
+			wantErr: true,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			filePath := filepath.Join(t.TempDir(), "azure.json")
+			if tc.content != nil {
+				if err := os.WriteFile(filePath, []byte(*tc.content), 0600); err != nil {
+					t.Fatalf("WriteFile() = %v", err)
+				}
+			}
+			originalCloudConfigFile := *cloudConfigFile
+			*cloudConfigFile = filePath
+			t.Cleanup(func() { *cloudConfigFile = originalCloudConfigFile })
+			featureDegraded.Reset()
+
+			mgr := newFakeManager(t)
+			err := setupTrafficManagerFeature(context.Background(), mgr, true, tc.strict, build)
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("setupTrafficManagerFeature() = %v, want error %t", err, tc.wantErr)
+			}
+			if tc.wantErr {
+				if !errors.Is(err, errCloudConfig) {
+					t.Errorf("setupTrafficManagerFeature() = %v, want an error wrapping %v", err, errCloudConfig)
+				}
+				return
+			}
+			if mgr.runnables != tc.wantRunnables {
+				t.Errorf("setupTrafficManagerFeature() added %d runnables, want %d", mgr.runnables, tc.wantRunnables)
+			}
+			if got := testutil.ToFloat64(featureDegraded.WithLabelValues(trafficManagerFeature)); got != tc.wantDegraded {
+				t.Errorf("featureDegraded{feature=%q} = %v, want %v", trafficManagerFeature, got, tc.wantDegraded)
+			}
+		})
+	}
+}
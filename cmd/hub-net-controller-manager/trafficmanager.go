@@ -0,0 +1,146 @@
+/*
+Copyright (c) Microsoft Corporation.
+Licensed under the MIT license.
+*/
+
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"k8s.io/klog/v2"
+	ctrl "sigs.k8s.io/controller-runtime"
+	ctrlmetrics "sigs.k8s.io/controller-runtime/pkg/metrics"
+
+	"go.goms.io/fleet-networking/pkg/common/adaptivelimiter"
+	"go.goms.io/fleet-networking/pkg/common/cloudconfig"
+	"go.goms.io/fleet-networking/pkg/common/env"
+	"go.goms.io/fleet-networking/pkg/common/metrics"
+	"go.goms.io/fleet-networking/pkg/common/nsallowlist"
+	"go.goms.io/fleet-networking/pkg/common/sharding"
+	"go.goms.io/fleet-networking/pkg/controllers/hub/trafficmanagerbackend"
+	"go.goms.io/fleet-networking/pkg/controllers/hub/trafficmanagerprofile"
+)
+
+// trafficManagerFeature is the feature label of the traffic manager controllers.
+const trafficManagerFeature = "traffic-manager"
+
+var (
+	// featureDegraded reports the features which are enabled but disabled at startup, as their dependencies are
+	// unusable; it is set to 1 for a degraded feature and to 0 for a feature which is set up.
+	featureDegraded = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: metrics.MetricsNamespace,
+			Subsystem: metrics.MetricsSubsystem,
+			Name:      "feature_degraded",
+			Help:      "Whether an enabled feature of the hub agent has been disabled at startup as its dependencies are unusable",
+		},
+		[]string{"feature"},
+	)
+
+	// errCloudConfig is returned when the Azure clients cannot be built from the cloud config.
+	errCloudConfig = errors.New("unusable cloud config")
+)
+
+func init() {
+	// Register featureDegraded (fleet_networking_feature_degraded) metric with the controller runtime global metrics
+	// registry.
+	ctrlmetrics.Registry.MustRegister(featureDegraded)
+}
+
+// setupTrafficManagerFeature sets up the trafficManagerProfile and trafficManagerBackend controllers. When the Azure
+// Traffic Manager clients cannot be built from the cloud config, only these controllers are disabled, so that the
+// other controllers keep running, unless strict is set; the feature is then reported degraded by the
+// featureDegraded metric. It returns an error when the manager should exit.
+func setupTrafficManagerFeature(ctx context.Context, mgr ctrl.Manager, serviceImportEnabled, strict bool, build cloudconfig.ClientsBuilder[cloudconfig.TrafficManagerClients]) error {
+	err := setupTrafficManagerControllers(ctx, mgr, serviceImportEnabled, build)
+	switch {
+	case err == nil:
+		featureDegraded.WithLabelValues(trafficManagerFeature).Set(0)
+		return nil
+	case strict || !errors.Is(err, errCloudConfig):
+		return err
+	}
+	klog.ErrorS(err, "The traffic manager feature is DISABLED as the Azure Traffic Manager clients cannot be built; "+
+		"fix the cloud config and restart the hub agent to enable it, the other controllers keep running",
+		"cloudConfigFile", *cloudConfigFile)
+	featureDegraded.WithLabelValues(trafficManagerFeature).Set(1)
+	return nil
+}
+
+// setupTrafficManagerControllers loads the cloud config and sets up the trafficManagerProfile and
+// trafficManagerBackend controllers; the returned error wraps errCloudConfig when the Azure Traffic Manager clients
+// cannot be built, in which case no controller has been set up.
+func setupTrafficManagerControllers(ctx context.Context, mgr ctrl.Manager, serviceImportEnabled bool, build cloudconfig.ClientsBuilder[cloudconfig.TrafficManagerClients]) error {
+	var podName string
+	if *trafficManagerBackendShardCount > 1 && *trafficManagerBackendShardIndex < 0 {
+		var err error
+		if podName, err = env.Lookup(podNameEnvKey); err != nil {
+			return fmt.Errorf("failed to derive the trafficManagerBackend shard index: %w", err)
+		}
+	}
+	shard, err := sharding.New(*trafficManagerBackendShardCount, *trafficManagerBackendShardIndex, podName)
+	if err != nil {
+		return fmt.Errorf("invalid trafficManagerBackend shard: %w", err)
+	}
+
+	klog.V(1).InfoS("Traffic manager feature is enabled, loading cloud config and creating azure clients", "cloudConfigFile", *cloudConfigFile, "reloadInterval", *cloudConfigReloadInterval)
+	azureClients := cloudconfig.NewReloader(*cloudConfigFile, "fleet-hub-net-controller-manager", *cloudConfigReloadInterval, build)
+	clients, err := azureClients.Current()
+	if err != nil {
+		return fmt.Errorf("%w: failed to create Azure Traffic Manager clients: %w", errCloudConfig, err)
+	}
+	if err := mgr.Add(azureClients); err != nil {
+		return fmt.Errorf("failed to set up the cloud config reloader: %w", err)
+	}
+
+	allowedNamespaces := nsallowlist.Parse(*trafficManagerAllowedNamespaces)
+	klog.V(1).InfoS("Start to setup TrafficManagerProfile controller", "allowedNamespaces", allowedNamespaces.String())
+	if err := (&trafficmanagerprofile.Reconciler{
+		Client:                       mgr.GetClient(),
+		ProfilesClient:               clients.ProfilesClient,
+		ResourceGroupName:            clients.ResourceGroupName,
+		AzureClients:                 azureClients,
+		LastSyncedTimeUpdateInterval: *atmLastSyncedTimeUpdateInterval,
+		CheckDNSNameAvailability:     *atmCheckDNSNameAvailability,
+		MonitorStatusResyncInterval:  *atmProfileMonitorStatusResyncInterval,
+		ReadOnly:                     *azureReadOnly,
+		AllowedNamespaces:            allowedNamespaces,
+		Recorder:                     mgr.GetEventRecorderFor(trafficmanagerprofile.ControllerName),
+	}).SetupWithManager(mgr); err != nil {
+		return fmt.Errorf("failed to create TrafficManagerProfile controller: %w", err)
+	}
+
+	// The limiter is shared by all the reconciliations, as Azure throttles the requests per subscription.
+	var concurrencyLimiter *adaptivelimiter.Limiter
+	if *atmEndpointMaxConcurrentRequests > 0 {
+		concurrencyLimiter = adaptivelimiter.New("trafficmanagerbackend", *atmEndpointMaxConcurrentRequests)
+	}
+	klog.V(1).InfoS("Start to setup TrafficManagerBackend controller", "shardCount", shard.Count, "shardIndex", shard.Index)
+	if err := (&trafficmanagerbackend.Reconciler{
+		Client:                           mgr.GetClient(),
+		ProfilesClient:                   clients.ProfilesClient,
+		EndpointsClient:                  clients.EndpointsClient,
+		ResourceGroupName:                clients.ResourceGroupName,
+		MaxExportStaleness:               *atmEndpointMaxStaleness,
+		Shard:                            shard,
+		BulkEndpointUpdateThreshold:      *atmBulkEndpointUpdateThreshold,
+		AzureClients:                     azureClients,
+		LastSyncedTimeUpdateInterval:     *atmLastSyncedTimeUpdateInterval,
+		RejectLocalExternalTrafficPolicy: *atmRejectLocalExternalTrafficPolicy,
+		ReadOnly:                         *azureReadOnly,
+		AllowedNamespaces:                allowedNamespaces,
+		ConcurrencyLimiter:               concurrencyLimiter,
+		StartupJitterWindow:              *trafficManagerBackendStartupJitterWindow,
+		ProfileCacheTTL:                  *atmProfileCacheTTL,
+		Recorder:                         mgr.GetEventRecorderFor(trafficmanagerbackend.ControllerName),
+		// serviceImport controller has already enabled the internalServiceExportIndexer when it is enabled.
+		// Therefore, no need to setup it again.
+	}).SetupWithManager(ctx, mgr, serviceImportEnabled); err != nil {
+		return fmt.Errorf("failed to create TrafficManagerBackend controller: %w", err)
+	}
+	return nil
+}
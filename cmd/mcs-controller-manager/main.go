@@ -9,6 +9,8 @@ package main
 import (
 	"context"
 	"flag"
+	"net"
+	"net/http"
 	"os"
 	"os/signal"
 	"sync"
@@ -18,15 +20,19 @@ import (
 	"k8s.io/apimachinery/pkg/util/rand"
 	// Import all Kubernetes client auth plugins (e.g. Azure, GCP, OIDC, etc.)
 	// to ensure that exec-entrypoint and run can make use of them.
+	"k8s.io/client-go/discovery"
 	_ "k8s.io/client-go/plugin/pkg/client/auth"
 	"k8s.io/client-go/rest"
+	"k8s.io/client-go/util/connrotation"
 	"k8s.io/klog/v2"
 	"sigs.k8s.io/controller-runtime/pkg/cache"
 	"sigs.k8s.io/controller-runtime/pkg/manager"
+	ctrlmetrics "sigs.k8s.io/controller-runtime/pkg/metrics"
 	metricsserver "sigs.k8s.io/controller-runtime/pkg/metrics/server"
 	"sigs.k8s.io/controller-runtime/pkg/webhook"
 
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
 	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
 	ctrl "sigs.k8s.io/controller-runtime"
@@ -35,9 +41,12 @@ import (
 	//+kubebuilder:scaffold:imports
 	clusterv1beta1 "go.goms.io/fleet/apis/cluster/v1beta1"
 	fleetv1alpha1 "go.goms.io/fleet/apis/v1alpha1"
+	"go.goms.io/fleet/pkg/utils"
 
 	fleetnetv1alpha1 "go.goms.io/fleet-networking/api/v1alpha1"
 	"go.goms.io/fleet-networking/pkg/common/hubconfig"
+	"go.goms.io/fleet-networking/pkg/common/preflight"
+	"go.goms.io/fleet-networking/pkg/common/version"
 	imcv1alpha1 "go.goms.io/fleet-networking/pkg/controllers/member/internalmembercluster/v1alpha1"
 	imcv1beta1 "go.goms.io/fleet-networking/pkg/controllers/member/internalmembercluster/v1beta1"
 	"go.goms.io/fleet-networking/pkg/controllers/multiclusterservice"
@@ -60,6 +69,18 @@ var (
 
 	isV1Alpha1APIEnabled = flag.Bool("enable-v1alpha1-apis", true, "If set, the agents will watch for the v1alpha1 APIs.")
 	isV1Beta1APIEnabled  = flag.Bool("enable-v1beta1-apis", false, "If set, the agents will watch for the v1beta1 APIs.")
+
+	enableGatewayBackends = flag.Bool("enable-gateway-backends", false, "If set, the agent maintains the Gateway API ReferenceGrants which allow the HTTPRoutes to reference the derived services of the multiClusterServices annotated with networking.fleet.azure.com/gateway-backend: \"true\"; the Gateway API CRDs must be installed in the member cluster.")
+
+	hubConfigReloadInterval = flag.Duration("hub-config-reload-interval", time.Minute, "How often the hub server URL and CA are checked for changes, e.g., after the hub cluster is migrated, when they are mounted from the hub config directory set by the HUB_CONFIG_DIR environment variable; a new hub config is validated against the member cluster namespace, then the hub clients are swapped to it without a restart, and a hub config failing the validation is rejected. Set to 0 to disable the reload.")
+
+	validateConfigAndExit = flag.Bool("validate-config-and-exit", false, "If set, the agent validates its configuration (the hub config, the member cluster name, the reachability of and its permissions in both clusters and the installed CRDs), prints a report and exits without starting the controllers; the exit code is non-zero if any validation fails.")
+)
+
+var (
+	gatewayBackendsFeatureRequiredGVKs = []schema.GroupVersionKind{
+		multiclusterservice.ReferenceGrantGVK,
+	}
 )
 
 func init() {
@@ -92,12 +113,72 @@ func main() {
 		klog.InfoS("flag:", "name", f.Name, "value", f.Value)
 	})
 
+	if *validateConfigAndExit {
+		report := preflight.Run(context.Background(), preflightOptions())
+		report.Print(os.Stdout)
+		if report.Failed() {
+			exitWithErrorFunc()
+		}
+		return
+	}
+
 	memberConfig, memberOptions := prepareMemberParameters()
 
+	// The derived Services are created in the reserved namespace.
+	if err := preflight.CheckReservedNamespace(context.Background(), memberConfig, *fleetSystemNamespace, reservedNamespacePermissions); err != nil {
+		klog.ErrorS(err, "The reserved fleet namespace cannot be used; check the --fleet-system-namespace flag and the RBAC rules of the agent", "namespace", *fleetSystemNamespace)
+		exitWithErrorFunc()
+	}
+
+	if *enableGatewayBackends {
+		klog.V(1).InfoS("Gateway backends feature is enabled, checking the required CRDs")
+		discoverClient := discovery.NewDiscoveryClientForConfigOrDie(memberConfig)
+		for _, gvk := range gatewayBackendsFeatureRequiredGVKs {
+			if err := utils.CheckCRDInstalled(discoverClient, gvk); err != nil {
+				klog.ErrorS(err, "Unable to find the required CRD", "GVK", gvk)
+				exitWithErrorFunc()
+			}
+		}
+	}
+
 	hubConfig, hubOptions, err := prepareHubParameters(memberConfig)
 	if err != nil {
 		exitWithErrorFunc()
 	}
+	mcHubNamespace, err := hubconfig.FetchMemberClusterNamespace()
+	if err != nil {
+		exitWithErrorFunc()
+	}
+	if err := preflight.CheckMemberClusterNamespace(context.Background(), hubConfig, mcHubNamespace); err != nil {
+		klog.ErrorS(err, "The member cluster namespace cannot be used; check the MEMBER_CLUSTER_NAME environment variable and whether the member cluster has joined the fleet", "namespace", mcHubNamespace)
+		exitWithErrorFunc()
+	}
+	var hubConfigRotator *hubconfig.Rotator
+	if dir := hubconfig.ConfigDir(); dir != "" {
+		hubConfigRotator, err = hubconfig.NewRotator(hubConfig, dir, *hubConfigReloadInterval, func(ctx context.Context, config *rest.Config) error {
+			return preflight.CheckMemberClusterNamespace(ctx, config, mcHubNamespace)
+		})
+		if err != nil {
+			klog.ErrorS(err, "Unable to set up the hub config rotator", "dir", dir)
+			exitWithErrorFunc()
+		}
+		hubConfig.Wrap(hubConfigRotator.WrapTransport)
+		// Track the connections to the hub cluster, so that the hub watches are re-established against the new hub
+		// server by closing the connections to the previous one.
+		hubDialer := connrotation.NewDialer((&net.Dialer{Timeout: 30 * time.Second, KeepAlive: 30 * time.Second}).DialContext)
+		hubConfig.Dial = hubDialer.DialContext
+		hubConfigRotator.OnRotate = hubDialer.CloseAll
+	}
+
+	buildInfo := version.Get("mcs-controller-manager", version.Features{
+		V1Beta1Enabled:            *isV1Beta1APIEnabled,
+		NetworkingFeaturesEnabled: true,
+	}, flag.CommandLine)
+	klog.InfoS("Build info", "version", buildInfo.Version, "goVersion", buildInfo.GoVersion, "features", buildInfo.Features)
+	ctrlmetrics.Registry.MustRegister(buildInfo.Collector())
+	// The probe servers of the managers cannot be extended, so the build info is served next to the metrics.
+	hubOptions.Metrics.ExtraHandlers = map[string]http.Handler{version.Path: buildInfo.Handler()}
+	memberOptions.Metrics.ExtraHandlers = map[string]http.Handler{version.Path: buildInfo.Handler()}
 
 	// Setup hub controller manager.
 	hubMgr, err := ctrl.NewManager(hubConfig, *hubOptions)
@@ -128,6 +209,13 @@ func main() {
 		klog.ErrorS(err, "Unable to set up ready check for member manager")
 		exitWithErrorFunc()
 	}
+	if hubConfigRotator != nil {
+		// The rotator runs with the member manager, which keeps running whatever the hub server.
+		if err := memberMgr.Add(hubConfigRotator); err != nil {
+			klog.ErrorS(err, "Unable to set up the hub config rotator")
+			exitWithErrorFunc()
+		}
+	}
 
 	ctx, cancel := context.WithCancel(context.Background())
 
@@ -235,6 +323,61 @@ func prepareMemberParameters() (*rest.Config, *ctrl.Options) {
 	return ctrl.GetConfigOrDie(), memberOpts
 }
 
+// reservedNamespacePermissions are the permissions the mcs agent requires in the reserved fleet namespace of the
+// member cluster.
+var reservedNamespacePermissions = []preflight.Permission{
+	{Group: "", Resource: "services", Verb: "watch"},
+	{Group: "", Resource: "services", Verb: "create"},
+	{Group: "", Resource: "services", Verb: "update"},
+	{Group: "", Resource: "services", Verb: "delete"},
+}
+
+// preflightOptions returns the resources and the permissions the mcs agent requires, as granted by its RBAC rules.
+func preflightOptions() preflight.Options {
+	netGroup := fleetnetv1alpha1.GroupVersion.Group
+	opts := preflight.Options{
+		TLSClientInsecure: *tlsClientInsecure,
+		GetMemberConfig:   ctrl.GetConfig,
+		MemberPermissions: []preflight.Permission{
+			{Group: netGroup, Resource: "multiclusterservices", Verb: "watch"},
+			{Group: netGroup, Resource: "multiclusterservices", Subresource: "status", Verb: "update"},
+			{Group: netGroup, Resource: "serviceimports", Verb: "create"},
+			{Group: netGroup, Resource: "serviceimports", Verb: "delete"},
+			{Group: "", Resource: "services", Verb: "create"},
+			{Group: "", Resource: "services", Verb: "delete"},
+			{Group: "", Resource: "events", Verb: "create"},
+		},
+		MemberResources: []schema.GroupVersionResource{
+			fleetnetv1alpha1.GroupVersion.WithResource("multiclusterservices"),
+			fleetnetv1alpha1.GroupVersion.WithResource("serviceimports"),
+		},
+		ReservedNamespace:            *fleetSystemNamespace,
+		ReservedNamespacePermissions: reservedNamespacePermissions,
+	}
+	if *enableGatewayBackends {
+		gatewayGroup := multiclusterservice.ReferenceGrantGVK.Group
+		opts.ReservedNamespacePermissions = append(opts.ReservedNamespacePermissions,
+			preflight.Permission{Group: gatewayGroup, Resource: "referencegrants", Verb: "watch"},
+			preflight.Permission{Group: gatewayGroup, Resource: "referencegrants", Verb: "create"},
+			preflight.Permission{Group: gatewayGroup, Resource: "referencegrants", Verb: "update"},
+			preflight.Permission{Group: gatewayGroup, Resource: "referencegrants", Verb: "delete"})
+		opts.MemberResources = append(opts.MemberResources, multiclusterservice.ReferenceGrantGVK.GroupVersion().WithResource("referencegrants"))
+	}
+	if *isV1Alpha1APIEnabled {
+		opts.HubPermissions = append(opts.HubPermissions,
+			preflight.Permission{Group: fleetv1alpha1.GroupVersion.Group, Resource: "internalmemberclusters", Verb: "watch"},
+			preflight.Permission{Group: fleetv1alpha1.GroupVersion.Group, Resource: "internalmemberclusters", Subresource: "status", Verb: "update"})
+		opts.HubResources = append(opts.HubResources, fleetv1alpha1.GroupVersion.WithResource("internalmemberclusters"))
+	}
+	if *isV1Beta1APIEnabled {
+		opts.HubPermissions = append(opts.HubPermissions,
+			preflight.Permission{Group: clusterv1beta1.GroupVersion.Group, Resource: "internalmemberclusters", Verb: "watch"},
+			preflight.Permission{Group: clusterv1beta1.GroupVersion.Group, Resource: "internalmemberclusters", Subresource: "status", Verb: "update"})
+		opts.HubResources = append(opts.HubResources, clusterv1beta1.GroupVersion.WithResource("internalmemberclusters"))
+	}
+	return opts
+}
+
 func setupControllersWithManager(_ context.Context, hubMgr, memberMgr manager.Manager) error {
 	klog.V(1).InfoS("Begin to setup controllers with controller manager")
 	memberClient := memberMgr.GetClient()
@@ -251,6 +394,18 @@ func setupControllersWithManager(_ context.Context, hubMgr, memberMgr manager.Ma
 		return err
 	}
 
+	if *enableGatewayBackends {
+		klog.V(1).InfoS("Create gatewaybackend reconciler")
+		if err := (&multiclusterservice.GatewayBackendReconciler{
+			Client:               memberClient,
+			Scheme:               memberMgr.GetScheme(),
+			FleetSystemNamespace: *fleetSystemNamespace,
+		}).SetupWithManager(memberMgr); err != nil {
+			klog.ErrorS(err, "Unable to create gatewaybackend reconciler")
+			return err
+		}
+	}
+
 	if *isV1Alpha1APIEnabled {
 		klog.V(1).InfoS("Create internalmembercluster (v1alpha1 API) reconciler")
 		if err := (&imcv1alpha1.Reconciler{
@@ -0,0 +1,232 @@
+/*
+Copyright (c) Microsoft Corporation.
+Licensed under the MIT license.
+*/
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"runtime"
+	"testing"
+
+	"golang.org/x/sync/errgroup"
+	corev1 "k8s.io/api/core/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/client-go/rest"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/envtest"
+)
+
+const (
+	// runScaleTestsEnv enables the scale tests, which seed thousands of objects and take minutes to run.
+	runScaleTestsEnv = "RUN_SCALE_TESTS"
+
+	// scaleFixturePods is the number of pods seeded in the member cluster, i.e. one pod per node of a 5,000-node
+	// cluster; scaleFixtureServiceSlices EndpointSlices are in use by a Service and scaleFixtureCustomSlices are
+	// managed by a custom controller.
+	scaleFixturePods          = 5000
+	scaleFixtureServiceSlices = 2000
+	scaleFixtureCustomSlices  = 2000
+	scaleFixtureEndpoints     = 20
+	scaleFixtureNamespace     = "scale-test"
+	scaleFixtureSeedingLimit  = 20
+)
+
+// TestMemberCacheOptions_ScaleFixture reports the heap held by the member cache of pods and EndpointSlices, with the
+// default cache options and with the ones of the member manager, against an envtest API server seeded with the scale
+// fixture.
+func TestMemberCacheOptions_ScaleFixture(t *testing.T) {
+	if os.Getenv(runScaleTestsEnv) != "true" {
+		t.Skipf("Skipping the scale tests; set %s=true to run them", runScaleTestsEnv)
+	}
+
+	testEnv := &envtest.Environment{}
+	cfg, err := testEnv.Start()
+	if err != nil {
+		t.Fatalf("Failed to start the test environment: %v", err)
+	}
+	defer func() {
+		if err := testEnv.Stop(); err != nil {
+			t.Errorf("Failed to stop the test environment: %v", err)
+		}
+	}()
+	k8sClient, err := client.New(cfg, client.Options{Scheme: scheme})
+	if err != nil {
+		t.Fatalf("Failed to create the client: %v", err)
+	}
+
+	ctx := context.Background()
+	seedMemberScaleFixture(ctx, t, k8sClient)
+
+	defaultHeap, defaultSlices := cachedPodsAndEndpointSlicesHeap(ctx, t, cfg, cache.Options{})
+	memberHeap, memberSlices := cachedPodsAndEndpointSlicesHeap(ctx, t, cfg, memberCacheOptions())
+	t.Logf("Heap held by the cache of %d pods and %d endpointSlices: %d KiB with the default cache options (%d endpointSlices cached), %d KiB with the member cache options (%d endpointSlices cached, %.0f%% less)",
+		scaleFixturePods, scaleFixtureServiceSlices+scaleFixtureCustomSlices, defaultHeap/1024, defaultSlices, memberHeap/1024, memberSlices,
+		100*(1-float64(memberHeap)/float64(defaultHeap)))
+	if memberSlices != scaleFixtureServiceSlices {
+		t.Errorf("EndpointSlices cached with the member cache options, got %d, want %d", memberSlices, scaleFixtureServiceSlices)
+	}
+	if memberHeap >= defaultHeap {
+		t.Errorf("Heap held with the member cache options, got %d bytes, want less than the %d bytes held with the default options", memberHeap, defaultHeap)
+	}
+}
+
+// seedMemberScaleFixture creates the pods and the EndpointSlices of the scale fixture.
+func seedMemberScaleFixture(ctx context.Context, t *testing.T, k8sClient client.Client) {
+	ns := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: scaleFixtureNamespace}}
+	if err := k8sClient.Create(ctx, ns); err != nil {
+		t.Fatalf("Failed to create namespace %s: %v", ns.Name, err)
+	}
+
+	g, gctx := errgroup.WithContext(ctx)
+	g.SetLimit(scaleFixtureSeedingLimit)
+	for i := 0; i < scaleFixturePods; i++ {
+		pod := &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: scaleFixtureNamespace,
+				Name:      fmt.Sprintf("app-%d", i),
+				Labels:    map[string]string{"app": "app", "pod-template-hash": "5d8f7c9b6"},
+			},
+			Spec: corev1.PodSpec{
+				NodeName: fmt.Sprintf("node-%d", i),
+				Containers: []corev1.Container{
+					{
+						Name:  "app",
+						Image: "mcr.microsoft.com/azuredocs/aks-helloworld:v1",
+						Ports: []corev1.ContainerPort{{Name: "http", ContainerPort: 8080}},
+						Env: []corev1.EnvVar{
+							{Name: "TITLE", Value: "Fleet networking scale fixture"},
+							{Name: "POD_NAME", ValueFrom: &corev1.EnvVarSource{FieldRef: &corev1.ObjectFieldSelector{FieldPath: "metadata.name"}}},
+						},
+						Resources: corev1.ResourceRequirements{
+							Requests: corev1.ResourceList{
+								corev1.ResourceCPU:    resource.MustParse("100m"),
+								corev1.ResourceMemory: resource.MustParse("128Mi"),
+							},
+						},
+						ReadinessProbe: &corev1.Probe{
+							ProbeHandler: corev1.ProbeHandler{
+								HTTPGet: &corev1.HTTPGetAction{Path: "/healthz", Port: intstr.FromString("http")},
+							},
+						},
+					},
+				},
+			},
+		}
+		g.Go(func() error {
+			return k8sClient.Create(gctx, pod)
+		})
+	}
+	for i := 0; i < scaleFixtureServiceSlices+scaleFixtureCustomSlices; i++ {
+		endpoints := make([]discoveryv1.Endpoint, 0, scaleFixtureEndpoints)
+		for j := 0; j < scaleFixtureEndpoints; j++ {
+			endpoints = append(endpoints, discoveryv1.Endpoint{
+				Addresses:  []string{fmt.Sprintf("10.%d.%d.%d", i/256, i%256, j)},
+				Conditions: discoveryv1.EndpointConditions{Ready: ptr.To(true)},
+				NodeName:   ptr.To(fmt.Sprintf("node-%d", (i*scaleFixtureEndpoints+j)%scaleFixturePods)),
+			})
+		}
+		labels := map[string]string{discoveryv1.LabelManagedBy: "custom-controller"}
+		if i < scaleFixtureServiceSlices {
+			labels = map[string]string{discoveryv1.LabelServiceName: fmt.Sprintf("app-%d", i), discoveryv1.LabelManagedBy: "endpointslice-controller.k8s.io"}
+		}
+		endpointSlice := &discoveryv1.EndpointSlice{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: scaleFixtureNamespace,
+				Name:      fmt.Sprintf("app-%d", i),
+				Labels:    labels,
+			},
+			AddressType: discoveryv1.AddressTypeIPv4,
+			Endpoints:   endpoints,
+			Ports: []discoveryv1.EndpointPort{
+				{
+					Name:     ptr.To("http"),
+					Protocol: ptr.To(corev1.ProtocolTCP),
+					Port:     ptr.To(int32(8080)),
+				},
+			},
+		}
+		g.Go(func() error {
+			return k8sClient.Create(gctx, endpointSlice)
+		})
+	}
+	if err := g.Wait(); err != nil {
+		t.Fatalf("Failed to seed the scale fixture: %v", err)
+	}
+}
+
+// cachedPodsAndEndpointSlicesHeap starts a cache of pods and EndpointSlices with the given options, waits until it
+// has synced, and returns the growth of the live heap and the number of cached EndpointSlices; the cache is stopped
+// before it returns.
+func cachedPodsAndEndpointSlicesHeap(ctx context.Context, t *testing.T, cfg *rest.Config, opts cache.Options) (int64, int) {
+	opts.Scheme = scheme
+	before := liveHeap()
+
+	cacheCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	c, err := cache.New(cfg, opts)
+	if err != nil {
+		t.Fatalf("Failed to create the cache: %v", err)
+	}
+	for _, obj := range []client.Object{&corev1.Pod{}, &discoveryv1.EndpointSlice{}} {
+		if _, err := c.GetInformer(cacheCtx, obj); err != nil {
+			t.Fatalf("Failed to get the %T informer: %v", obj, err)
+		}
+	}
+	stopped := make(chan struct{})
+	go func() {
+		defer close(stopped)
+		if err := c.Start(cacheCtx); err != nil {
+			t.Errorf("Failed to start the cache: %v", err)
+		}
+	}()
+	defer func() {
+		cancel()
+		<-stopped
+	}()
+	if !c.WaitForCacheSync(cacheCtx) {
+		t.Fatalf("Failed to sync the cache")
+	}
+	if got := cachedPods(cacheCtx, t, c); got != scaleFixturePods {
+		t.Fatalf("Cached pods, got %d, want %d", got, scaleFixturePods)
+	}
+	slices := cachedEndpointSlices(cacheCtx, t, c)
+	return liveHeap() - before, slices
+}
+
+// cachedPods returns the number of pods of the scale fixture in the cache, leaving out the objects the API server
+// creates on its own, e.g. the EndpointSlice of the kubernetes Service; the listed copies are dropped once it returns,
+// so that they are not measured as held by the cache.
+func cachedPods(ctx context.Context, t *testing.T, c cache.Cache) int {
+	podList := &corev1.PodList{}
+	if err := c.List(ctx, podList, client.InNamespace(scaleFixtureNamespace)); err != nil {
+		t.Fatalf("Failed to list the cached pods: %v", err)
+	}
+	return len(podList.Items)
+}
+
+// cachedEndpointSlices returns the number of EndpointSlices in the cache; see cachedPods.
+func cachedEndpointSlices(ctx context.Context, t *testing.T, c cache.Cache) int {
+	endpointSliceList := &discoveryv1.EndpointSliceList{}
+	if err := c.List(ctx, endpointSliceList, client.InNamespace(scaleFixtureNamespace)); err != nil {
+		t.Fatalf("Failed to list the cached endpointSlices: %v", err)
+	}
+	return len(endpointSliceList.Items)
+}
+
+// liveHeap returns the bytes of the heap objects which are reachable after a garbage collection.
+func liveHeap() int64 {
+	var stats runtime.MemStats
+	runtime.GC()
+	runtime.GC()
+	runtime.ReadMemStats(&stats)
+	return int64(stats.HeapAlloc)
+}
@@ -0,0 +1,136 @@
+/*
+Copyright (c) Microsoft Corporation.
+Licensed under the MIT license.
+*/
+
+package main
+
+import (
+	"fmt"
+	"strconv"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	fleetnetv1alpha1 "go.goms.io/fleet-networking/api/v1alpha1"
+)
+
+var _ = Describe("member controllers across a hub server rotation", func() {
+	existingSvcKey := types.NamespacedName{Namespace: testMemberUserNS, Name: "before-rotation"}
+	newSvcKey := types.NamespacedName{Namespace: testMemberUserNS, Name: "after-rotation"}
+	firstHubConfigMapKey := types.NamespacedName{Namespace: testHubNamespace, Name: "first-hub-only"}
+	secondHubConfigMapKey := types.NamespacedName{Namespace: testHubNamespace, Name: "second-hub-only"}
+
+	internalSvcExportKey := func(svcKey types.NamespacedName) types.NamespacedName {
+		return types.NamespacedName{Namespace: testHubNamespace, Name: fmt.Sprintf("%s-%s", svcKey.Namespace, svcKey.Name)}
+	}
+
+	createServiceExport := func(svcKey types.NamespacedName) {
+		svc := &corev1.Service{
+			ObjectMeta: metav1.ObjectMeta{Namespace: svcKey.Namespace, Name: svcKey.Name},
+			Spec: corev1.ServiceSpec{
+				Ports: []corev1.ServicePort{{Port: 80}},
+			},
+		}
+		Expect(memberClient.Create(ctx, svc)).Should(Succeed())
+		svcExport := &fleetnetv1alpha1.ServiceExport{
+			ObjectMeta: metav1.ObjectMeta{Namespace: svcKey.Namespace, Name: svcKey.Name},
+		}
+		Expect(memberClient.Create(ctx, svcExport)).Should(Succeed())
+	}
+
+	// cachedConfigMapNames returns the names of the ConfigMaps in the hub namespace as seen by the cache of the hub
+	// manager.
+	cachedConfigMapNames := func() ([]string, error) {
+		configMapList := &corev1.ConfigMapList{}
+		if err := hubManagerClient.List(ctx, configMapList, client.InNamespace(testHubNamespace)); err != nil {
+			return nil, err
+		}
+		names := []string{}
+		for _, cm := range configMapList.Items {
+			if cm.Name == firstHubConfigMapKey.Name || cm.Name == secondHubConfigMapKey.Name {
+				names = append(names, cm.Name)
+			}
+		}
+		return names, nil
+	}
+
+	// currentResourceVersion returns the current resource version of a hub cluster.
+	currentResourceVersion := func(c client.Client) int {
+		configMapList := &corev1.ConfigMapList{}
+		Expect(c.List(ctx, configMapList, client.InNamespace(testHubNamespace))).Should(Succeed())
+		rv, err := strconv.Atoi(configMapList.ResourceVersion)
+		Expect(err).NotTo(HaveOccurred())
+		return rv
+	}
+
+	BeforeEach(func() {
+		Expect(hubClient.Create(ctx, &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Namespace: firstHubConfigMapKey.Namespace, Name: firstHubConfigMapKey.Name}})).Should(Succeed())
+		secondHubConfigMap := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Namespace: secondHubConfigMapKey.Namespace, Name: secondHubConfigMapKey.Name}}
+		Expect(secondHubClient.Create(ctx, secondHubConfigMap)).Should(Succeed())
+
+		By("moving the resource version of the second hub cluster past the one of the first hub cluster")
+		// The resource versions the informers hold after a rotation are then unrelated either way: within the history
+		// of the second hub cluster, whose events since would be replayed on top of the objects of the first one, and
+		// ahead of the first hub cluster, which would hold the lists until they time out as too large.
+		target := currentResourceVersion(hubClient) + 100
+		for i := 0; currentResourceVersion(secondHubClient) < target; i++ {
+			secondHubConfigMap.Data = map[string]string{"updates": strconv.Itoa(i)}
+			Expect(secondHubClient.Update(ctx, secondHubConfigMap)).Should(Succeed())
+		}
+
+		By("exporting a service to the first hub cluster")
+		createServiceExport(existingSvcKey)
+		Eventually(func() error {
+			return hubClient.Get(ctx, internalSvcExportKey(existingSvcKey), &fleetnetv1alpha1.InternalServiceExport{})
+		}, eventuallyTimeout, eventuallyInterval).Should(Succeed())
+
+		By("watching the first hub cluster from the hub cache")
+		Eventually(cachedConfigMapNames, eventuallyTimeout, eventuallyInterval).Should(ConsistOf(firstHubConfigMapKey.Name))
+	})
+
+	AfterEach(func() {
+		By("making sure the hub config points to the first hub cluster")
+		writeHubConfigDir(hubCfg)
+		Eventually(cachedConfigMapNames, eventuallyTimeout, eventuallyInterval).Should(ConsistOf(firstHubConfigMapKey.Name))
+
+		for _, svcKey := range []types.NamespacedName{existingSvcKey, newSvcKey} {
+			Expect(client.IgnoreNotFound(memberClient.Delete(ctx, &fleetnetv1alpha1.ServiceExport{ObjectMeta: metav1.ObjectMeta{Namespace: svcKey.Namespace, Name: svcKey.Name}}))).Should(Succeed())
+			Expect(client.IgnoreNotFound(memberClient.Delete(ctx, &corev1.Service{ObjectMeta: metav1.ObjectMeta{Namespace: svcKey.Namespace, Name: svcKey.Name}}))).Should(Succeed())
+			Expect(client.IgnoreNotFound(secondHubClient.Delete(ctx, &fleetnetv1alpha1.InternalServiceExport{ObjectMeta: metav1.ObjectMeta{Namespace: testHubNamespace, Name: internalSvcExportKey(svcKey).Name}}))).Should(Succeed())
+		}
+		Expect(hubClient.Delete(ctx, &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Namespace: firstHubConfigMapKey.Namespace, Name: firstHubConfigMapKey.Name}})).Should(Succeed())
+		Expect(secondHubClient.Delete(ctx, &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Namespace: secondHubConfigMapKey.Namespace, Name: secondHubConfigMapKey.Name}})).Should(Succeed())
+	})
+
+	It("should relist the hub cache and export the services to the new hub cluster", func() {
+		By("rotating the hub config to the second hub cluster")
+		writeHubConfigDir(secondHubCfg)
+
+		By("relisting the hub cache from the second hub cluster")
+		Eventually(cachedConfigMapNames, eventuallyTimeout, eventuallyInterval).Should(ConsistOf(secondHubConfigMapKey.Name))
+
+		By("exporting the existing and the new services to the second hub cluster")
+		createServiceExport(newSvcKey)
+		for _, svcKey := range []types.NamespacedName{existingSvcKey, newSvcKey} {
+			Eventually(func() error {
+				return secondHubClient.Get(ctx, internalSvcExportKey(svcKey), &fleetnetv1alpha1.InternalServiceExport{})
+			}, eventuallyTimeout, eventuallyInterval).Should(Succeed(), "internalServiceExport of %s", svcKey)
+		}
+
+		By("rotating the hub config back to the first hub cluster")
+		writeHubConfigDir(hubCfg)
+
+		By("relisting the hub cache from the first hub cluster")
+		Eventually(cachedConfigMapNames, eventuallyTimeout, eventuallyInterval).Should(ConsistOf(firstHubConfigMapKey.Name))
+
+		By("exporting the new service to the first hub cluster")
+		Eventually(func() error {
+			return hubClient.Get(ctx, internalSvcExportKey(newSvcKey), &fleetnetv1alpha1.InternalServiceExport{})
+		}, eventuallyTimeout, eventuallyInterval).Should(Succeed())
+	})
+})
@@ -9,41 +9,64 @@ package main
 
 import (
 	"context"
+	"errors"
 	"flag"
 	"fmt"
+	"net"
+	"net/http"
 	"os"
 	"os/signal"
+	"strings"
 	"sync"
 	"syscall"
 	"time"
 
+	"golang.org/x/time/rate"
 	// Import all Kubernetes client auth plugins (e.g. Azure, GCP, OIDC, etc.)
 	// to ensure that exec-entrypoint and run can make use of them.
+	corev1 "k8s.io/api/core/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/selection"
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/rand"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/client-go/discovery"
 	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
 	_ "k8s.io/client-go/plugin/pkg/client/auth"
 	"k8s.io/client-go/rest"
+	"k8s.io/client-go/util/connrotation"
 	"k8s.io/klog/v2"
-	"sigs.k8s.io/cloud-provider-azure/pkg/azclient"
-	"sigs.k8s.io/cloud-provider-azure/pkg/azclient/policy/ratelimit"
-	"sigs.k8s.io/cloud-provider-azure/pkg/azclient/publicipaddressclient"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/cache"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/healthz"
 	"sigs.k8s.io/controller-runtime/pkg/manager"
+	ctrlmetrics "sigs.k8s.io/controller-runtime/pkg/metrics"
 	metricsserver "sigs.k8s.io/controller-runtime/pkg/metrics/server"
 	"sigs.k8s.io/controller-runtime/pkg/webhook"
 
 	//+kubebuilder:scaffold:imports
 	clusterv1beta1 "go.goms.io/fleet/apis/cluster/v1beta1"
 	fleetv1alpha1 "go.goms.io/fleet/apis/v1alpha1"
-	"go.goms.io/fleet/pkg/utils/cloudconfig/azure"
 
 	fleetnetv1alpha1 "go.goms.io/fleet-networking/api/v1alpha1"
+	"go.goms.io/fleet-networking/pkg/common/apicompat"
+	"go.goms.io/fleet-networking/pkg/common/cachetransform"
+	"go.goms.io/fleet-networking/pkg/common/clockskew"
+	"go.goms.io/fleet-networking/pkg/common/cloudconfig"
 	"go.goms.io/fleet-networking/pkg/common/env"
+	"go.goms.io/fleet-networking/pkg/common/exportmigration"
 	"go.goms.io/fleet-networking/pkg/common/hubconfig"
+	"go.goms.io/fleet-networking/pkg/common/networkingmode"
+	"go.goms.io/fleet-networking/pkg/common/preflight"
+	"go.goms.io/fleet-networking/pkg/common/rbac"
+	"go.goms.io/fleet-networking/pkg/common/version"
+	"go.goms.io/fleet-networking/pkg/common/watchdog"
+	"go.goms.io/fleet-networking/pkg/common/writelimiter"
+	"go.goms.io/fleet-networking/pkg/controllers/member/clustersetdns"
 	"go.goms.io/fleet-networking/pkg/controllers/member/endpointslice"
 	"go.goms.io/fleet-networking/pkg/controllers/member/endpointsliceexport"
 	"go.goms.io/fleet-networking/pkg/controllers/member/endpointsliceimport"
@@ -71,10 +94,60 @@ var (
 
 	isV1Alpha1APIEnabled = flag.Bool("enable-v1alpha1-apis", true, "If set, the agents will watch for the v1alpha1 APIs.")
 	isV1Beta1APIEnabled  = flag.Bool("enable-v1beta1-apis", false, "If set, the agents will watch for the v1beta1 APIs.")
+	requireV1Beta1       = flag.Bool("require-v1beta1", false, "If set, the member agent exits when the v1beta1 APIs are not enabled or the hub cluster does not serve them, instead of falling back to the v1alpha1 APIs; set it once the fleet has completed the migration to the v1beta1 APIs.")
 
 	enableTrafficManagerFeature = flag.Bool("enable-traffic-manager-feature", false, "If set, the traffic manager feature will be enabled.")
 
-	cloudConfigFile = flag.String("cloud-config", "/etc/kubernetes/provider/azure.json", "The path to the cloud config file which will be used to access the Azure resource.")
+	cloudProviderName = flag.String("cloud-provider", serviceexport.CloudProviderAzure, "The cloud provider hosting the member cluster, which resolves the load balancer information of the exported services for the traffic manager feature; must be azure or none.")
+	cloudConfigFile   = flag.String("cloud-config", "/etc/kubernetes/provider/azure.json", "The path to the cloud config file which will be used to access the Azure resource. It is loaded only when the Azure cloud provider is used by the traffic manager feature.")
+
+	cloudConfigReloadInterval = flag.Duration("cloud-config-reload-interval", time.Minute, "How often the cloud config file is checked for changes, e.g., after the Azure resources are moved to another subscription or resource group; the Azure clients are rebuilt without a restart when it has changed. Set to 0 to disable the reload.")
+
+	managePublicIPDNSLabel = flag.Bool("manage-pip-dns-label", false, "If set, a DNS label derived from the member cluster and the service is set on the Azure public IP address of an exported load balancer service when it has none and the service does not have the azure-dns-label-name annotation, as required by the traffic manager feature; it requires the write permission on the public IP addresses. Only applicable to the azure cloud provider.")
+
+	internalServiceExportHeartbeatInterval = flag.Duration("internal-service-export-heartbeat-interval", 5*time.Minute, "How often the member agent refreshes the heartbeat of the services exported to the hub cluster, so that the hub cluster can detect stale exports. Set to 0 to disable the heartbeat.")
+
+	maxExportedEndpointsPerService = flag.Int("max-exported-endpoints-per-service", 0, "The maximum number of ready endpoints exported per service across all its endpoint slices; when a service has more, a deterministic subset of them is exported. Set to 0 for no limit.")
+
+	propagateTerminatingEndpoints = flag.Bool("propagate-terminating-endpoints", false, "If set, the terminating endpoints which are still serving are exported with their serving and terminating conditions, and imported as not ready, so that the consumers which drain the terminating endpoints gracefully can do so across clusters. Enable it only once all the member agents have been upgraded, as the older ones import the terminating endpoints as ready.")
+
+	backfillMetricsAnnotations = flag.Bool("backfill-metrics-annotations", false, "If set, the exported endpoint slices missing the metrics last seen generation and timestamp annotations, e.g. those exported before the annotations were introduced, are stamped once in the background at startup, with their current generation and the current time, at a limited rate; a summary count is logged when done. The endpoint slice controller repairs the annotations on reconciliation regardless.")
+
+	exportedEndpointSliceManagers = flag.String("exported-endpointslice-managers", endpointslice.KubeControllerManagerEndpointSliceManager, "The comma-separated values of the endpointslice.kubernetes.io/managed-by label of the endpoint slices to export; the endpoint slices managed by other controllers are not exported, and are unexported if exported before. The endpoint slices exported from other namespaces with the owner service namespace annotation are subject to the same check, so the managed-by value of the mirroring controller must be listed for them. Set to all to export the endpoint slices of all controllers.")
+
+	requiredNamespaceLabels = flag.String("required-namespace-labels", "", "The comma-separated key=value labels a namespace must have before the ServiceExports in it are honored, e.g. networking.fleet.azure.com/export-allowed=true; the services of a namespace are unexported once it loses any of the labels. Empty disables the check.")
+
+	hubExportShards = flag.Int("hub-export-shards", 1, "The number of namespaces in the hub cluster across which the exports of the member cluster are sharded by service, to stay below the per-namespace object count limits of the hub cluster; the first shard is the member cluster namespace fleet-member-<member cluster name>, and the others, fleet-member-<member cluster name>-shard-<index>, must be created beforehand. The count must not be changed while services are exported. Set to 1 to keep all the exports in the member cluster namespace.")
+
+	onClusterIDChange = flag.String("on-cluster-id-change", string(exportmigration.ModeRecreate), "How the objects exported to the hub cluster under a previous member cluster ID, e.g. before the member cluster was re-onboarded under a new name, are migrated at startup when they are still linked with a live service or endpoint slice: adopt rewrites the member cluster ID in them, keeping them to avoid churn on the importing clusters; recreate deletes them, so that they are exported again under the current ID.")
+
+	deletionProtectionGracePeriod = flag.Duration("deletion-protection-grace-period", 5*time.Minute, "How long the unexport of a deleted ServiceExport annotated with networking.fleet.azure.com/deletion-protection=true is delayed while other member clusters still import the service; a warning event with the importer count is emitted on the ServiceExport meanwhile. The service is unexported after the grace period regardless. Set to 0 to disable the deletion protection.")
+
+	exportHealthProbeQPS   = flag.Float64("export-health-probe-qps", 10, "The number of health probes per second sent to the exported services whose ServiceExports configure a health probe, across all of them; the probes above the limit are delayed. Set to 0 for no limit.")
+	exportHealthProbeBurst = flag.Int("export-health-probe-burst", 20, "The number of health probes allowed in a burst above --export-health-probe-qps.")
+
+	hubWatchStalenessThreshold = flag.Duration("hub-watch-staleness-threshold", 10*time.Minute, "The duration after which a hub informer that has not received any event is checked against the hub API server; on drift, the hub watches are restarted. Set to 0 to disable the check.")
+
+	clockSkewThreshold = flag.Duration("clock-skew-threshold", 30*time.Second, "The skew of the local clock against the hub API server, estimated from the Date headers of the hub responses, above which a warning is logged; the export timestamps written by the agent and the latency metrics are off by the skew. The estimated skew is always reported by the fleet_networking_member_clock_skew_seconds metric. Set to 0 to disable the warning.")
+
+	hubWriteQPS              = flag.Float64("hub-write-qps", 20, "The number of writes per second to the hub cluster allowed across all the controllers of the agent, so that a member cluster cannot starve the others sharing the hub cluster; the writes are also backed off whenever the hub API server throttles the agent, for the delay of its Retry-After header if any. The saturation of the limit is reported by the fleet_networking_hub_write_limiter_saturation metric. Set to 0 to only back off on throttling.")
+	hubWriteBurst            = flag.Int("hub-write-burst", 40, "The number of writes to the hub cluster allowed in a burst above --hub-write-qps.")
+	hubWriteMaxShutdownDelay = flag.Duration("hub-write-max-shutdown-delay", 5*time.Second, "The longest a write to the hub cluster is held back by the write limit once the agent is shutting down, so that the cleanup on shutdown, e.g., the removal of finalizers, is not delayed.")
+
+	hubAPICompatibilityCheckInterval = flag.Duration("hub-api-compatibility-check-interval", 10*time.Minute, "How often the member agent verifies that the hub cluster still serves the fleet-networking CRD versions it has been built for; on skew, the agent reports not ready. The check also runs at startup. Set to 0 to disable the check.")
+
+	publishNetworkProperties        = flag.Bool("publish-network-properties", false, "If set, the region and the virtual network of the member cluster are loaded from the cloud config file and published to the hub cluster, so that the importing clusters can tell whether the endpoints exported from this cluster are reachable. Requires --enable-v1beta1-apis.")
+	skipUnreachableClusterEndpoints = flag.Bool("skip-unreachable-cluster-endpoints", false, "If set, the endpoints exported from the clusters which are unreachable from this member cluster, per the published network properties, are not imported; the skipped clusters are recorded in the MultiClusterService status. Requires --publish-network-properties.")
+
+	manageClustersetDNS    = flag.Bool("manage-clusterset-dns", false, "If set, the imported services are published under their clusterset DNS names, <service>.<namespace>.svc.clusterset.local, with CoreDNS rewrite rules mapping the names of the active MultiClusterServices to their derived services, which the agent maintains in the CoreDNS custom ConfigMap; the content of the ConfigMap the agent has not written is never modified.")
+	clustersetDNSConfigMap = flag.String("clusterset-dns-configmap", clustersetdns.DefaultConfigMap, "The <namespace>/<name> of the CoreDNS custom ConfigMap in which the clusterset DNS rewrite rules are maintained when --manage-clusterset-dns is set.")
+	clustersetDNSConfigKey = flag.String("clusterset-dns-configmap-key", clustersetdns.DefaultConfigMapKey, "The key of the CoreDNS custom ConfigMap which holds the clusterset DNS rewrite rules; the rules are written between marker comments, and other entries of the key are kept.")
+
+	verifyRBAC = flag.Bool("verify-rbac", true, "If set, the agent verifies at startup, with self subject access reviews, that it is granted in both clusters every permission the RBAC markers of its enabled controllers declare, and logs the missing ones in a single error; the agent starts regardless.")
+
+	hubConfigReloadInterval = flag.Duration("hub-config-reload-interval", time.Minute, "How often the hub server URL and CA are checked for changes, e.g., after the hub cluster is migrated, when they are mounted from the hub config directory set by the HUB_CONFIG_DIR environment variable; a new hub config is validated against the member cluster namespace, then the hub clients are swapped to it without a restart, and a hub config failing the validation is rejected. Set to 0 to disable the reload.")
+
+	validateConfigAndExit = flag.Bool("validate-config-and-exit", false, "If set, the agent validates its configuration (the hub config, the member cluster name, the reachability of and its permissions in both clusters, the installed CRDs and the cloud config), prints a report and exits without starting the controllers; the exit code is non-zero if any validation fails.")
 )
 
 func init() {
@@ -107,12 +180,85 @@ func main() {
 		klog.InfoS("flag:", "name", f.Name, "value", f.Value)
 	})
 
+	if *validateConfigAndExit {
+		report := preflight.Run(context.Background(), preflightOptions())
+		report.Print(os.Stdout)
+		if report.Failed() {
+			exitWithErrorFunc()
+		}
+		return
+	}
+
 	memberConfig, memberOptions := prepareMemberParameters()
 
+	// The derived Services are read from, and the imported EndpointSlices are written to, the reserved namespace.
+	if err := preflight.CheckReservedNamespace(context.Background(), memberConfig, *fleetSystemNamespace, reservedNamespacePermissions); err != nil {
+		klog.ErrorS(err, "The reserved fleet namespace cannot be used; check the --fleet-system-namespace flag and the RBAC rules of the agent", "namespace", *fleetSystemNamespace)
+		exitWithErrorFunc()
+	}
+
 	hubConfig, hubOptions, err := prepareHubParameters(memberConfig)
 	if err != nil {
 		exitWithErrorFunc()
 	}
+	mcHubNamespace, err := hubconfig.FetchMemberClusterNamespace()
+	if err != nil {
+		exitWithErrorFunc()
+	}
+	// The rotator swaps the innermost transport of the hub clients, so it must wrap the hub config first.
+	var hubConfigRotator *hubconfig.Rotator
+	if dir := hubconfig.ConfigDir(); dir != "" {
+		hubConfigRotator, err = hubconfig.NewRotator(hubConfig, dir, *hubConfigReloadInterval, func(ctx context.Context, config *rest.Config) error {
+			return preflight.CheckMemberClusterNamespace(ctx, config, mcHubNamespace)
+		})
+		if err != nil {
+			klog.ErrorS(err, "Unable to set up the hub config rotator", "dir", dir)
+			exitWithErrorFunc()
+		}
+		hubConfig.Wrap(hubConfigRotator.WrapTransport)
+	}
+	// Estimate the skew of the local clock from every hub response, starting with the preflight check below.
+	hubConfig.Wrap((&clockskew.Tracker{Threshold: *clockSkewThreshold}).WrapTransport)
+	// Limit the writes to the hub cluster across all the controllers, each of which builds its client from the config.
+	hubWriteLimiter := writelimiter.New(*hubWriteQPS, *hubWriteBurst, *hubWriteMaxShutdownDelay)
+	hubConfig.Wrap(hubWriteLimiter.WrapTransport)
+	ctrlmetrics.Registry.MustRegister(hubWriteLimiter.Collector())
+	if err := preflight.CheckMemberClusterNamespace(context.Background(), hubConfig, mcHubNamespace); err != nil {
+		klog.ErrorS(err, "The member cluster namespace cannot be used; check the MEMBER_CLUSTER_NAME environment variable and whether the member cluster has joined the fleet", "namespace", mcHubNamespace)
+		exitWithErrorFunc()
+	}
+	for _, ns := range hubconfig.ExportNamespaces(mcHubNamespace, *hubExportShards)[1:] {
+		if err := preflight.CheckMemberClusterNamespace(context.Background(), hubConfig, ns); err != nil {
+			klog.ErrorS(err, "The hub export shard namespace cannot be used; check the --hub-export-shards flag and whether the shard namespaces have been created in the hub cluster", "namespace", ns)
+			exitWithErrorFunc()
+		}
+	}
+	if err := resolveIMCAPIVersions(hubConfig); err != nil {
+		exitWithErrorFunc()
+	}
+	// The build info reports the API versions reconciled with the hub cluster rather than the enabled ones.
+	buildInfo := version.Get("member-net-controller-manager", version.Features{
+		V1Beta1Enabled:            *isV1Beta1APIEnabled,
+		TrafficManagerEnabled:     *enableTrafficManagerFeature,
+		NetworkingFeaturesEnabled: true,
+	}, flag.CommandLine)
+	klog.InfoS("Build info", "version", buildInfo.Version, "goVersion", buildInfo.GoVersion, "features", buildInfo.Features)
+	ctrlmetrics.Registry.MustRegister(buildInfo.Collector())
+	// The probe servers of the managers cannot be extended, so the build info is served next to the metrics.
+	hubOptions.Metrics.ExtraHandlers = map[string]http.Handler{version.Path: buildInfo.Handler()}
+	memberOptions.Metrics.ExtraHandlers = map[string]http.Handler{version.Path: buildInfo.Handler()}
+	if *verifyRBAC {
+		member, hub := rbac.BuildMatrix(enabledControllers())
+		if err := preflight.VerifyRBAC(context.Background(), memberConfig, hubConfig, hubconfig.ExportNamespaces(mcHubNamespace, *hubExportShards), member, hub); err != nil {
+			klog.ErrorS(err, "The agent is not granted every permission its controllers require; the affected controllers fail until the RBAC rules of the agent are fixed")
+		}
+	}
+	if err := migrateExports(memberConfig, hubConfig, mcHubNamespace); err != nil {
+		exitWithErrorFunc()
+	}
+	// Track the connections to the hub cluster, so that the hub watches can be forced to restart by closing them.
+	hubDialer := connrotation.NewDialer((&net.Dialer{Timeout: 30 * time.Second, KeepAlive: 30 * time.Second}).DialContext)
+	hubConfig.Dial = hubDialer.DialContext
 
 	// Setup hub controller manager.
 	hubMgr, err := ctrl.NewManager(hubConfig, *hubOptions)
@@ -143,11 +289,25 @@ func main() {
 		klog.ErrorS(err, "Unable to set up ready check for member manager")
 		exitWithErrorFunc()
 	}
+	if hubConfigRotator != nil {
+		// The hub watches are re-established against the new hub server by closing the connections to the previous
+		// one; the rotator runs with the member manager, which keeps running whatever the hub server.
+		hubConfigRotator.OnRotate = hubDialer.CloseAll
+		if err := memberMgr.Add(hubConfigRotator); err != nil {
+			klog.ErrorS(err, "Unable to set up the hub config rotator")
+			exitWithErrorFunc()
+		}
+	}
 
 	ctx, cancel := context.WithCancel(context.Background())
+	// Bound the delay of the writes to the hub cluster once the agent is shutting down.
+	go func() {
+		<-ctx.Done()
+		hubWriteLimiter.Drain()
+	}()
 
 	klog.V(1).InfoS("Setup controllers with controller manager")
-	if err := setupControllersWithManager(ctx, hubMgr, memberMgr); err != nil {
+	if err := setupControllersWithManager(ctx, hubMgr, memberMgr, hubDialer, hubConfigRotator); err != nil {
 		klog.ErrorS(err, "Unable to setup controllers with manager")
 		exitWithErrorFunc()
 	}
@@ -209,6 +369,16 @@ func prepareHubParameters(memberConfig *rest.Config) (*rest.Config, *ctrl.Option
 		klog.ErrorS(err, "Failed to get member cluster hub namespace")
 		return nil, nil, err
 	}
+	if err := hubconfig.ValidateExportShards(mcHubNamespace, *hubExportShards); err != nil {
+		klog.ErrorS(err, "Invalid hub export shards", "hubExportShards", *hubExportShards)
+		return nil, nil, err
+	}
+	// The exports of the member cluster are sharded across the member cluster namespace and the shard namespaces,
+	// while the imports always reside in the member cluster namespace.
+	cacheNamespaces := map[string]cache.Config{}
+	for _, ns := range hubconfig.ExportNamespaces(mcHubNamespace, *hubExportShards) {
+		cacheNamespaces[ns] = cache.Config{}
+	}
 
 	hubOptions := &ctrl.Options{
 		Scheme: scheme,
@@ -223,11 +393,10 @@ func prepareHubParameters(memberConfig *rest.Config) (*rest.Config, *ctrl.Option
 		LeaderElectionID:        "2bf2b407.hub.networking.fleet.azure.com",
 		LeaderElectionNamespace: *leaderElectionNamespace, // This requires we have access to resource "leases" in API group "coordination.k8s.io" under leaderElectionNamespace.
 		LeaderElectionConfig:    memberConfig,
-		// Restricts the manager's cache to watch objects in the member hub namespace.
+		// Restricts the manager's cache to watch objects in the member hub namespace and its export shard namespaces.
 		Cache: cache.Options{
-			DefaultNamespaces: map[string]cache.Config{
-				mcHubNamespace: {},
-			},
+			DefaultNamespaces: cacheNamespaces,
+			DefaultTransform:  cachetransform.StripManagedFieldsAndReadOnlyAnnotations(),
 		},
 	}
 	return hubConfig, hubOptions, nil
@@ -246,11 +415,163 @@ func prepareMemberParameters() (*rest.Config, *ctrl.Options) {
 		LeaderElection:          *enableLeaderElection,
 		LeaderElectionNamespace: *leaderElectionNamespace,
 		LeaderElectionID:        "2bf2b407.member.networking.fleet.azure.com",
+		Cache:                   memberCacheOptions(),
+		// Secrets and ConfigMaps are read one at a time, if at all, e.g. the CoreDNS custom ConfigMap; caching them
+		// would keep every Secret and ConfigMap of the member cluster in memory.
+		Client: client.Options{
+			Cache: &client.CacheOptions{
+				DisableFor: []client.Object{&corev1.Secret{}, &corev1.ConfigMap{}},
+			},
+		},
 	}
 	return ctrl.GetConfigOrDie(), memberOpts
 }
 
-func setupControllersWithManager(ctx context.Context, hubMgr, memberMgr manager.Manager) error {
+// memberCacheOptions returns the options of the member manager cache, which holds the objects of the whole member
+// cluster and hence dominates the memory usage of the agent at scale.
+func memberCacheOptions() cache.Options {
+	return cache.Options{
+		// The managed fields are never read by the controllers; namespaces are only read, so their
+		// last-applied-configuration annotations can be dropped as well.
+		DefaultTransform: cachetransform.StripManagedFieldsAndReadOnlyAnnotations(&corev1.Namespace{}),
+		ByObject: map[client.Object]cache.ByObject{
+			// The endpointslice controller looks up the pods behind the exported endpoints to tell whether they run in
+			// the host network; only the fields it reads are cached.
+			&corev1.Pod{}: {Transform: cachetransform.TrimPodToHostNetworkFields},
+			// Only the EndpointSlices in use by a Service are exported or imported; the others, e.g. those of
+			// the custom controllers of other projects, are not cached. An exported EndpointSlice which loses
+			// its Service name label is removed from the cache, and its export is withdrawn by the
+			// endpointsliceexport controller.
+			&discoveryv1.EndpointSlice{}: {Label: endpointSliceSelector},
+			// Services and ServiceExports are cached in all namespaces on purpose: a namespace is onboarded, and
+			// unexported again, whenever it gains or loses the required namespace labels, while the namespaces of a
+			// cache are fixed once it has started.
+		},
+	}
+}
+
+// endpointSliceSelector selects the EndpointSlices with the Service name label.
+var endpointSliceSelector = labels.NewSelector().Add(*mustNewRequirement(discoveryv1.LabelServiceName, selection.Exists, nil))
+
+func mustNewRequirement(key string, op selection.Operator, vals []string) *labels.Requirement {
+	requirement, err := labels.NewRequirement(key, op, vals)
+	if err != nil {
+		panic(err)
+	}
+	return requirement
+}
+
+// reservedNamespacePermissions are the permissions the member agent requires in the reserved fleet namespace of the
+// member cluster.
+var reservedNamespacePermissions = []preflight.Permission{
+	{Group: "", Resource: "services", Verb: "get"},
+	{Group: "", Resource: "services", Verb: "watch"},
+	{Group: "discovery.k8s.io", Resource: "endpointslices", Verb: "list"},
+	{Group: "discovery.k8s.io", Resource: "endpointslices", Verb: "create"},
+	{Group: "discovery.k8s.io", Resource: "endpointslices", Verb: "update"},
+	{Group: "discovery.k8s.io", Resource: "endpointslices", Verb: "delete"},
+}
+
+// preflightOptions returns the resources and the permissions the member agent requires, as granted by its RBAC rules.
+func preflightOptions() preflight.Options {
+	opts := preflight.Options{
+		TLSClientInsecure: *tlsClientInsecure,
+		GetMemberConfig:   ctrl.GetConfig,
+		HubResources:      append([]schema.GroupVersionResource{}, apicompat.RequiredHubResources...),
+		MemberResources: []schema.GroupVersionResource{
+			fleetnetv1alpha1.GroupVersion.WithResource("serviceexports"),
+			fleetnetv1alpha1.GroupVersion.WithResource("serviceimports"),
+			fleetnetv1alpha1.GroupVersion.WithResource("multiclusterservices"),
+		},
+		ReservedNamespace:            *fleetSystemNamespace,
+		ReservedNamespacePermissions: reservedNamespacePermissions,
+	}
+	// Check the same permissions as the startup RBAC verification, i.e. the ones declared by the RBAC markers of the
+	// enabled controllers.
+	opts.MemberPermissions, opts.HubPermissions = rbac.BuildMatrix(enabledControllers())
+	if *isV1Alpha1APIEnabled {
+		opts.HubResources = append(opts.HubResources, fleetv1alpha1.GroupVersion.WithResource("internalmemberclusters"))
+	}
+	if *isV1Beta1APIEnabled {
+		opts.HubResources = append(opts.HubResources, clusterv1beta1.GroupVersion.WithResource("internalmemberclusters"))
+	}
+	if (*enableTrafficManagerFeature && *cloudProviderName == serviceexport.CloudProviderAzure) || *publishNetworkProperties {
+		opts.CloudConfigFile = *cloudConfigFile
+	}
+	return opts
+}
+
+// migrateExports migrates the objects exported to the hub cluster under a previous member cluster ID before the
+// controllers start; a failure to migrate them is logged only, as the controllers work regardless.
+func migrateExports(memberConfig, hubConfig *rest.Config, mcHubNamespace string) error {
+	mode, err := exportmigration.ParseMode(*onClusterIDChange)
+	if err != nil {
+		klog.ErrorS(err, "Invalid --on-cluster-id-change flag")
+		return err
+	}
+	mcName, err := env.LookupMemberClusterName()
+	if err != nil {
+		klog.ErrorS(err, "Member cluster name cannot be empty")
+		return err
+	}
+	memberClient, err := client.New(memberConfig, client.Options{Scheme: scheme})
+	if err != nil {
+		klog.ErrorS(err, "Unable to create member client for the export migration")
+		return err
+	}
+	hubClient, err := client.New(hubConfig, client.Options{Scheme: scheme})
+	if err != nil {
+		klog.ErrorS(err, "Unable to create hub client for the export migration")
+		return err
+	}
+	migrator := &exportmigration.Migrator{
+		MemberClient:    memberClient,
+		HubClient:       hubClient,
+		MemberClusterID: mcName,
+		HubNamespaces:   hubconfig.ExportNamespaces(mcHubNamespace, *hubExportShards),
+		Mode:            mode,
+	}
+	if _, err := migrator.Run(context.Background()); err != nil {
+		klog.ErrorS(err, "Failed to migrate the objects exported under a previous member cluster ID; they are left as is", "mode", mode)
+	}
+	return nil
+}
+
+// resolveIMCAPIVersions discovers the versions of the InternalMemberCluster API served by the hub cluster, logs the
+// compatibility matrix, and narrows the enabled versions down to the served ones, or falls back to the served version
+// when none of the enabled ones is, so that the v1beta1 APIs can be enabled on the hub and the member clusters in any
+// order; the --enable-v1alpha1-apis and --enable-v1beta1-apis flags are updated accordingly before the controllers are
+// set up. With --require-v1beta1, an incompatibility is fatal instead.
+func resolveIMCAPIVersions(hubConfig *rest.Config) error {
+	enabled := apicompat.IMCVersions{V1Alpha1: *isV1Alpha1APIEnabled, V1Beta1: *isV1Beta1APIEnabled}
+	discoveryClient, err := discovery.NewDiscoveryClientForConfig(hubConfig)
+	if err != nil {
+		klog.ErrorS(err, "Unable to create hub discovery client")
+		return err
+	}
+	served, err := apicompat.DiscoverIMCVersions(discoveryClient)
+	if err != nil {
+		if *requireV1Beta1 {
+			klog.ErrorS(err, "Failed to discover the internalMemberCluster API versions served by the hub cluster")
+			return err
+		}
+		klog.ErrorS(err, "Failed to discover the internalMemberCluster API versions served by the hub cluster; the enabled versions are reconciled as they are", "enabled", enabled)
+		return nil
+	}
+	resolved, err := apicompat.ResolveIMCVersions(enabled, served, *requireV1Beta1)
+	klog.InfoS("InternalMemberCluster API compatibility", "enabled", enabled, "servedByHub", served, "reconciled", resolved, "requireV1Beta1", *requireV1Beta1)
+	if err != nil {
+		klog.ErrorS(err, "The internalMemberCluster API versions are incompatible with the hub cluster; check the --enable-v1alpha1-apis, --enable-v1beta1-apis and --require-v1beta1 flags")
+		return err
+	}
+	if resolved != enabled {
+		klog.InfoS("Reconciling the internalMemberCluster API versions served by the hub cluster instead of the enabled ones; restart the member agent once the hub cluster has been upgraded", "enabled", enabled, "reconciled", resolved)
+	}
+	*isV1Alpha1APIEnabled, *isV1Beta1APIEnabled = resolved.V1Alpha1, resolved.V1Beta1
+	return nil
+}
+
+func setupControllersWithManager(ctx context.Context, hubMgr, memberMgr manager.Manager, hubDialer *connrotation.Dialer, hubConfigRotator *hubconfig.Rotator) error {
 	klog.V(1).InfoS("Begin to setup controllers with controller manager")
 
 	mcName, err := env.LookupMemberClusterName()
@@ -268,17 +589,70 @@ func setupControllersWithManager(ctx context.Context, hubMgr, memberMgr manager.
 	memberClient := memberMgr.GetClient()
 	hubClient := hubMgr.GetClient()
 
-	klog.V(1).InfoS("Create endpointslice controller")
+	if *hubAPICompatibilityCheckInterval > 0 {
+		klog.V(1).InfoS("Create hub API compatibility checker", "interval", *hubAPICompatibilityCheckInterval)
+		discoveryClient, err := discovery.NewDiscoveryClientForConfig(hubMgr.GetConfig())
+		if err != nil {
+			klog.ErrorS(err, "Unable to create hub discovery client")
+			return err
+		}
+		checker := &apicompat.Checker{
+			Discovery: discoveryClient,
+			Required:  apicompat.RequiredHubResources,
+			Interval:  *hubAPICompatibilityCheckInterval,
+		}
+		// Check once at startup so that a skew is reported before the controllers start writing to the hub cluster;
+		// a discovery failure is not fatal, as the check is retried periodically.
+		if err := checker.Check(); err != nil {
+			klog.ErrorS(err, "Failed to check the hub API compatibility at startup")
+		}
+		if err := hubMgr.AddReadyzCheck("hub-api-compatibility", checker.ReadyzCheck); err != nil {
+			klog.ErrorS(err, "Unable to set up hub API compatibility ready check")
+			return err
+		}
+		if err := hubMgr.Add(checker); err != nil {
+			klog.ErrorS(err, "Unable to create hub API compatibility checker")
+			return err
+		}
+	}
+
+	// The networking mode is read from the internal member cluster (v1beta1 API) only; without it, the member cluster
+	// both exports and imports Services.
+	var networkingModeGate *networkingmode.Gate
+	if *isV1Beta1APIEnabled {
+		networkingModeGate = networkingmode.NewGate()
+	}
+
+	endpointSliceManagers := endpointslice.ParseExportedEndpointSliceManagers(*exportedEndpointSliceManagers)
+	klog.V(1).InfoS("Create endpointslice controller", "exportedEndpointSliceManagers", endpointSliceManagers)
 	if err := (&endpointslice.Reconciler{
-		MemberClusterID: mcName,
-		MemberClient:    memberClient,
-		HubClient:       hubClient,
-		HubNamespace:    mcHubNamespace,
+		MemberClusterID:                mcName,
+		MemberClient:                   memberClient,
+		HubClient:                      hubClient,
+		HubNamespace:                   mcHubNamespace,
+		HubExportShards:                *hubExportShards,
+		MaxExportedEndpointsPerService: *maxExportedEndpointsPerService,
+		ExportedEndpointSliceManagers:  endpointSliceManagers,
+		PropagateTerminatingEndpoints:  *propagateTerminatingEndpoints,
+		HubServerChanges:               hubConfigRotator.Subscribe(),
+		Recorder:                       memberMgr.GetEventRecorderFor(endpointslice.ControllerName),
 	}).SetupWithManager(ctx, memberMgr); err != nil {
 		klog.ErrorS(err, "Unable to create endpointslice controller")
 		return err
 	}
 
+	if *backfillMetricsAnnotations {
+		klog.V(1).InfoS("Create endpointslice metrics annotations backfill", "qps", endpointslice.DefaultMetricsAnnotationsBackfillQPS)
+		if err := memberMgr.Add(&endpointslice.MetricsAnnotationsBackfill{
+			MemberClient: memberClient,
+			APIReader:    memberMgr.GetAPIReader(),
+			Limiter:      rate.NewLimiter(rate.Limit(endpointslice.DefaultMetricsAnnotationsBackfillQPS), 1),
+		}); err != nil {
+			klog.ErrorS(err, "Unable to create endpointslice metrics annotations backfill")
+			return err
+		}
+	}
+
 	klog.V(1).InfoS("Create endpointsliceexport controller")
 	if err := (&endpointsliceexport.Reconciler{
 		MemberClient: memberClient,
@@ -288,12 +662,29 @@ func setupControllersWithManager(ctx context.Context, hubMgr, memberMgr manager.
 		return err
 	}
 
-	klog.V(1).InfoS("Create endpointsliceimport controller")
+	var networkProperties cloudconfig.NetworkProperties
+	if *publishNetworkProperties {
+		if networkProperties, err = cloudconfig.LoadNetworkProperties(*cloudConfigFile); err != nil {
+			klog.ErrorS(err, "Unable to load the network properties of the member cluster")
+			return err
+		}
+		klog.V(1).InfoS("Loaded the network properties of the member cluster", "region", networkProperties.Region, "vnetID", networkProperties.VNetID)
+	} else if *skipUnreachableClusterEndpoints {
+		err := errors.New("--skip-unreachable-cluster-endpoints requires --publish-network-properties")
+		klog.ErrorS(err, "Invalid network properties configuration")
+		return err
+	}
+
+	klog.V(1).InfoS("Create endpointsliceimport controller", "skipUnreachableClusterEndpoints", *skipUnreachableClusterEndpoints)
 	if err := (&endpointsliceimport.Reconciler{
-		MemberClusterID:      mcName,
-		MemberClient:         memberClient,
-		HubClient:            hubClient,
-		FleetSystemNamespace: *fleetSystemNamespace,
+		MemberClusterID:         mcName,
+		MemberClient:            memberClient,
+		HubClient:               hubClient,
+		FleetSystemNamespace:    *fleetSystemNamespace,
+		NetworkProperties:       networkProperties,
+		SkipUnreachableClusters: *skipUnreachableClusterEndpoints,
+		NetworkingMode:          networkingModeGate,
+		Recorder:                memberMgr.GetEventRecorderFor(endpointsliceimport.ControllerName),
 	}).SetupWithManager(ctx, memberMgr, hubMgr); err != nil {
 		klog.ErrorS(err, "Unable to create endpointsliceimport controller")
 		return err
@@ -319,53 +710,104 @@ func setupControllersWithManager(ctx context.Context, hubMgr, memberMgr manager.
 		return err
 	}
 
-	var azurePublicIPAddressClient publicipaddressclient.Interface
-	var resourceGroupName string
-	if *enableTrafficManagerFeature {
+	cloudProvider, err := serviceexport.NewCloudProvider(*cloudProviderName, *cloudConfigFile, *cloudConfigReloadInterval)
+	if err != nil {
+		klog.ErrorS(err, "Unable to create cloud provider")
+		return err
+	}
+	if azureCloudProvider, ok := cloudProvider.(*serviceexport.AzureCloudProvider); ok && *enableTrafficManagerFeature {
+		// The traffic manager feature cannot work without a valid cloud config; fail fast instead of on the first
+		// export of a load balancer service.
 		klog.V(1).InfoS("Traffic manager feature is enabled, loading cloud config and creating azure clients", "cloudConfigFile", *cloudConfigFile)
-		cloudConfig, err := azure.NewCloudConfigFromFile(*cloudConfigFile)
-		if err != nil {
-			klog.ErrorS(err, "Unable to load cloud config", "file name", *cloudConfigFile)
+		if err := azureCloudProvider.LoadCloudConfig(); err != nil {
+			klog.ErrorS(err, "Unable to load cloud config", "cloudConfigFile", *cloudConfigFile)
 			return err
 		}
-		cloudConfig.SetUserAgent("fleet-member-net-controller-manager")
-		klog.V(1).InfoS("Cloud config loaded", "cloudConfig", cloudConfig)
-
-		azurePublicIPAddressClient, err = initAzureNetworkClients(cloudConfig)
-		if err != nil {
-			klog.ErrorS(err, "Unable to create Azure Traffic Manager clients")
+		azureCloudProvider.ManagePublicIPDNSLabel = *managePublicIPDNSLabel
+		azureCloudProvider.ClusterID = mcName
+	}
+	if runnable, ok := cloudProvider.(manager.Runnable); ok {
+		// The cloud provider reloads the cloud config when it changes.
+		if err := memberMgr.Add(runnable); err != nil {
+			klog.ErrorS(err, "Unable to set up the cloud config reloader")
 			return err
 		}
-
-		resourceGroupName = cloudConfig.ResourceGroup
 	}
 
-	klog.V(1).InfoS("Create serviceexport reconciler", "enableTrafficManagerFeature", *enableTrafficManagerFeature)
+	namespaceLabels, err := labels.ConvertSelectorToLabelsMap(*requiredNamespaceLabels)
+	if err != nil {
+		klog.ErrorS(err, "Invalid required namespace labels", "requiredNamespaceLabels", *requiredNamespaceLabels)
+		return err
+	}
+	klog.V(1).InfoS("Create serviceexport reconciler", "enableTrafficManagerFeature", *enableTrafficManagerFeature, "cloudProvider", *cloudProviderName, "requiredNamespaceLabels", namespaceLabels, "deletionProtectionGracePeriod", *deletionProtectionGracePeriod)
 	if err := (&serviceexport.Reconciler{
-		MemberClient:                memberClient,
-		HubClient:                   hubClient,
-		MemberClusterID:             mcName,
-		HubNamespace:                mcHubNamespace,
-		Recorder:                    memberMgr.GetEventRecorderFor(serviceexport.ControllerName),
-		EnableTrafficManagerFeature: *enableTrafficManagerFeature,
-		ResourceGroupName:           resourceGroupName,
-		AzurePublicIPAddressClient:  azurePublicIPAddressClient,
+		MemberClient:                  memberClient,
+		HubClient:                     hubClient,
+		MemberClusterID:               mcName,
+		HubNamespace:                  mcHubNamespace,
+		HubExportShards:               *hubExportShards,
+		Recorder:                      memberMgr.GetEventRecorderFor(serviceexport.ControllerName),
+		EnableTrafficManagerFeature:   *enableTrafficManagerFeature,
+		CloudProvider:                 cloudProvider,
+		HeartbeatInterval:             *internalServiceExportHeartbeatInterval,
+		RequiredNamespaceLabels:       namespaceLabels,
+		NetworkProperties:             networkProperties,
+		NetworkingMode:                networkingModeGate,
+		DeletionProtectionGracePeriod: *deletionProtectionGracePeriod,
+		HubServerChanges:              hubConfigRotator.Subscribe(),
 	}).SetupWithManager(memberMgr); err != nil {
 		klog.ErrorS(err, "Unable to create serviceexport reconciler")
 		return err
 	}
 
+	klog.V(1).InfoS("Create serviceexport health probe reconciler", "qps", *exportHealthProbeQPS, "burst", *exportHealthProbeBurst)
+	var healthProbeLimiter *rate.Limiter
+	if *exportHealthProbeQPS > 0 {
+		healthProbeLimiter = rate.NewLimiter(rate.Limit(*exportHealthProbeQPS), *exportHealthProbeBurst)
+	}
+	if err := (&serviceexport.HealthProbeReconciler{
+		MemberClient:    memberClient,
+		HubClient:       hubClient,
+		HubNamespace:    mcHubNamespace,
+		HubExportShards: *hubExportShards,
+		Limiter:         healthProbeLimiter,
+	}).SetupWithManager(memberMgr); err != nil {
+		klog.ErrorS(err, "Unable to create serviceexport health probe reconciler")
+		return err
+	}
+
 	klog.V(1).InfoS("Create serviceimport reconciler")
 	if err := (&serviceimport.Reconciler{
 		MemberClient:    memberClient,
 		HubClient:       hubClient,
 		MemberClusterID: mcName,
 		HubNamespace:    mcHubNamespace,
-	}).SetupWithManager(memberMgr); err != nil {
+		NetworkingMode:  networkingModeGate,
+	}).SetupWithManager(memberMgr, hubMgr); err != nil {
 		klog.ErrorS(err, "Unable to create serviceimport reconciler")
 		return err
 	}
 
+	if *manageClustersetDNS {
+		namespace, name, ok := strings.Cut(*clustersetDNSConfigMap, "/")
+		if !ok || namespace == "" || name == "" {
+			err := fmt.Errorf("invalid --clusterset-dns-configmap %q, want <namespace>/<name>", *clustersetDNSConfigMap)
+			klog.ErrorS(err, "Invalid clusterset DNS configuration")
+			return err
+		}
+		klog.V(1).InfoS("Create clusterset DNS reconciler", "configMap", klog.KRef(namespace, name), "key", *clustersetDNSConfigKey)
+		if err := (&clustersetdns.Reconciler{
+			MemberClient:         memberClient,
+			ConfigMapReader:      memberMgr.GetAPIReader(),
+			ConfigMap:            types.NamespacedName{Namespace: namespace, Name: name},
+			ConfigMapKey:         *clustersetDNSConfigKey,
+			FleetSystemNamespace: *fleetSystemNamespace,
+		}).SetupWithManager(memberMgr); err != nil {
+			klog.ErrorS(err, "Unable to create clusterset DNS reconciler")
+			return err
+		}
+	}
+
 	if *isV1Alpha1APIEnabled {
 		klog.V(1).InfoS("Create internalmembercluster (v1alpha1 API) reconciler")
 		if err := (&imcv1alpha1.Reconciler{
@@ -381,43 +823,40 @@ func setupControllersWithManager(ctx context.Context, hubMgr, memberMgr manager.
 	if *isV1Beta1APIEnabled {
 		klog.V(1).InfoS("Create internalmembercluster (v1beta1 API) reconciler")
 		if err := (&imcv1beta1.Reconciler{
-			MemberClient: memberClient,
-			HubClient:    hubClient,
-			AgentType:    clusterv1beta1.ServiceExportImportAgent,
+			MemberClient:      memberClient,
+			HubClient:         hubClient,
+			AgentType:         clusterv1beta1.ServiceExportImportAgent,
+			NetworkProperties: networkProperties,
+			NetworkingMode:    networkingModeGate,
 		}).SetupWithManager(hubMgr); err != nil {
 			klog.ErrorS(err, "Unable to create internalmembercluster (v1beta1 API) reconciler")
 			return err
 		}
 	}
 
-	klog.V(1).InfoS("Succeeded to setup controllers with controller manager")
-	return nil
-}
-
-// initAzureNetworkClients initializes the Azure network resource clients, currently only publicIPAddressClient.
-func initAzureNetworkClients(cloudConfig *azure.CloudConfig) (publicipaddressclient.Interface, error) {
-	authProvider, err := azclient.NewAuthProvider(&cloudConfig.ARMClientConfig, &cloudConfig.AzureAuthConfig)
-	if err != nil {
-		return nil, fmt.Errorf("failed to create Azure auth provider: %w", err)
-	}
-
-	factoryConfig := &azclient.ClientFactoryConfig{
-		CloudProviderBackoff: true,
-		SubscriptionID:       cloudConfig.SubscriptionID,
-	}
-	options, err := azclient.GetDefaultResourceClientOption(&cloudConfig.ARMClientConfig, factoryConfig)
-	if err != nil {
-		return nil, fmt.Errorf("failed to get default resource client option: %w", err)
-	}
-
-	if rateLimitPolicy := ratelimit.NewRateLimitPolicy(cloudConfig.Config); rateLimitPolicy != nil {
-		options.ClientOptions.PerCallPolicies = append(options.ClientOptions.PerCallPolicies, rateLimitPolicy)
-	}
-
-	pipClient, err := publicipaddressclient.New(cloudConfig.SubscriptionID, authProvider.GetAzIdentity(), options)
-	if err != nil {
-		return nil, fmt.Errorf("failed to create Azure PublicIPAddress client: %w", err)
+	if *hubWatchStalenessThreshold > 0 {
+		klog.V(1).InfoS("Create hub watch watchdog", "stalenessThreshold", *hubWatchStalenessThreshold)
+		if err := hubMgr.Add(&watchdog.Watchdog{
+			Informers:    hubMgr.GetCache(),
+			CachedReader: hubMgr.GetCache(),
+			APIReader:    hubMgr.GetAPIReader(),
+			Scheme:       hubMgr.GetScheme(),
+			Namespace:    mcHubNamespace,
+			Objects: []client.Object{
+				&fleetnetv1alpha1.EndpointSliceExport{},
+				&fleetnetv1alpha1.EndpointSliceImport{},
+				&fleetnetv1alpha1.InternalServiceExport{},
+				&fleetnetv1alpha1.InternalServiceImport{},
+			},
+			StalenessThreshold: *hubWatchStalenessThreshold,
+			CheckInterval:      *hubWatchStalenessThreshold / 2,
+			Restart:            hubDialer.CloseAll,
+		}); err != nil {
+			klog.ErrorS(err, "Unable to create hub watch watchdog")
+			return err
+		}
 	}
 
-	return pipClient, nil
+	klog.V(1).InfoS("Succeeded to setup controllers with controller manager")
+	return nil
 }
@@ -0,0 +1,85 @@
+/*
+Copyright (c) Microsoft Corporation.
+Licensed under the MIT license.
+*/
+
+package main
+
+import (
+	"fmt"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	fleetnetv1alpha1 "go.goms.io/fleet-networking/api/v1alpha1"
+)
+
+const (
+	eventuallyTimeout  = time.Second * 10
+	eventuallyInterval = time.Millisecond * 250
+)
+
+var _ = Describe("member controllers with the none cloud provider", func() {
+	svcName := "app"
+	svcKey := types.NamespacedName{Namespace: testMemberUserNS, Name: svcName}
+	internalSvcExportKey := types.NamespacedName{Namespace: testHubNamespace, Name: fmt.Sprintf("%s-%s", testMemberUserNS, svcName)}
+
+	BeforeEach(func() {
+		svc := &corev1.Service{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: svcKey.Namespace,
+				Name:      svcKey.Name,
+			},
+			Spec: corev1.ServiceSpec{
+				Type: corev1.ServiceTypeLoadBalancer,
+				Ports: []corev1.ServicePort{
+					{
+						Port: 80,
+					},
+				},
+			},
+		}
+		Expect(memberClient.Create(ctx, svc)).Should(Succeed())
+		svc.Status.LoadBalancer.Ingress = []corev1.LoadBalancerIngress{{IP: "1.2.3.4"}}
+		Expect(memberClient.Status().Update(ctx, svc)).Should(Succeed())
+
+		svcExport := &fleetnetv1alpha1.ServiceExport{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: svcKey.Namespace,
+				Name:      svcKey.Name,
+			},
+		}
+		Expect(memberClient.Create(ctx, svcExport)).Should(Succeed())
+	})
+
+	AfterEach(func() {
+		Expect(memberClient.Delete(ctx, &fleetnetv1alpha1.ServiceExport{ObjectMeta: metav1.ObjectMeta{Namespace: svcKey.Namespace, Name: svcKey.Name}})).Should(Succeed())
+		Expect(memberClient.Delete(ctx, &corev1.Service{ObjectMeta: metav1.ObjectMeta{Namespace: svcKey.Namespace, Name: svcKey.Name}})).Should(Succeed())
+	})
+
+	It("should export the load balancer service without loading the Azure cloud config", func() {
+		Eventually(func() error {
+			svcExport := &fleetnetv1alpha1.ServiceExport{}
+			if err := memberClient.Get(ctx, svcKey, svcExport); err != nil {
+				return fmt.Errorf("serviceExport Get(%+v), got %w, want no error", svcKey, err)
+			}
+			if !meta.IsStatusConditionTrue(svcExport.Status.Conditions, string(fleetnetv1alpha1.ServiceExportValid)) {
+				return fmt.Errorf("serviceExport conditions, got %+v, want valid condition", svcExport.Status.Conditions)
+			}
+
+			internalSvcExport := &fleetnetv1alpha1.InternalServiceExport{}
+			if err := hubClient.Get(ctx, internalSvcExportKey, internalSvcExport); err != nil {
+				return fmt.Errorf("internalServiceExport Get(%+v), got %w, want no error", internalSvcExportKey, err)
+			}
+			if internalSvcExport.Spec.Type != corev1.ServiceTypeLoadBalancer || internalSvcExport.Spec.PublicIPResourceID != nil {
+				return fmt.Errorf("internalServiceExport spec, got type %q and public IP %v, want the service type and no public IP", internalSvcExport.Spec.Type, internalSvcExport.Spec.PublicIPResourceID)
+			}
+			return nil
+		}, eventuallyTimeout, eventuallyInterval).Should(Succeed())
+	})
+})
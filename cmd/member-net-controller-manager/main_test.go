@@ -0,0 +1,77 @@
+/*
+Copyright (c) Microsoft Corporation.
+Licensed under the MIT license.
+*/
+
+package main
+
+import (
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// TestMemberCacheOptions tests that the member manager cache only holds the EndpointSlices in use by a Service and
+// strips the managed fields of the cached objects.
+func TestMemberCacheOptions(t *testing.T) {
+	opts := memberCacheOptions()
+
+	var endpointSliceLabels labels.Selector
+	for obj, byObject := range opts.ByObject {
+		if _, ok := obj.(*discoveryv1.EndpointSlice); ok {
+			endpointSliceLabels = byObject.Label
+		}
+	}
+	if endpointSliceLabels == nil {
+		t.Fatal("memberCacheOptions() has no EndpointSlice label selector")
+	}
+	testCases := []struct {
+		name   string
+		labels map[string]string
+		want   bool
+	}{
+		{
+			name:   "in use by a service",
+			labels: map[string]string{discoveryv1.LabelServiceName: "app"},
+			want:   true,
+		},
+		{
+			name:   "imported",
+			labels: map[string]string{discoveryv1.LabelServiceName: "derived-app", discoveryv1.LabelManagedBy: "fleet-networking"},
+			want:   true,
+		},
+		{
+			name:   "managed by a custom controller",
+			labels: map[string]string{discoveryv1.LabelManagedBy: "custom-controller"},
+		},
+		{
+			name: "no labels",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := endpointSliceLabels.Matches(labels.Set(tc.labels)); got != tc.want {
+				t.Errorf("EndpointSlice label selector Matches(%v) = %t, want %t", tc.labels, got, tc.want)
+			}
+		})
+	}
+
+	svc := &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace:     "work",
+			Name:          "app",
+			ManagedFields: []metav1.ManagedFieldsEntry{{Manager: "kubectl", Operation: metav1.ManagedFieldsOperationApply}},
+		},
+	}
+	got, err := opts.DefaultTransform(svc)
+	if err != nil {
+		t.Fatalf("DefaultTransform(), got %v, want no error", err)
+	}
+	if managedFields := got.(client.Object).GetManagedFields(); managedFields != nil {
+		t.Errorf("DefaultTransform() managed fields, got %v, want nil", managedFields)
+	}
+}
@@ -0,0 +1,179 @@
+/*
+Copyright (c) Microsoft Corporation.
+Licensed under the MIT license.
+*/
+
+package main
+
+import (
+	"go.goms.io/fleet-networking/pkg/common/rbac"
+)
+
+const (
+	netGroup       = "networking.fleet.azure.com"
+	discoveryGroup = "discovery.k8s.io"
+	coreGroup      = ""
+)
+
+var (
+	allVerbs    = []string{"get", "list", "watch", "create", "update", "patch", "delete"}
+	readVerbs   = []string{"get", "list", "watch"}
+	statusVerbs = []string{"get", "update", "patch"}
+	eventVerbs  = []string{"create", "patch"}
+
+	// leaveRules are the rules the internalmembercluster controllers require in the member cluster to withdraw the
+	// exports and the imports when the member cluster leaves the fleet.
+	leaveRules = []rbac.Rule{
+		{Group: netGroup, Resources: []string{"multiclusterservices", "serviceexports"}, Verbs: []string{"get", "list", "delete"}},
+	}
+)
+
+// memberAgentControllers is the permission matrix of the member agent; the rules of each controller must match its
+// kubebuilder RBAC markers, which is enforced by the unit tests.
+var memberAgentControllers = []rbac.Controller{
+	{
+		Name:    "endpointslice",
+		Package: "pkg/controllers/member/endpointslice",
+		MemberRules: []rbac.Rule{
+			{Group: discoveryGroup, Resources: []string{"endpointslices"}, Verbs: []string{"get", "list", "watch", "update", "patch"}},
+			{Group: netGroup, Resources: []string{"serviceexports"}, Verbs: readVerbs},
+			{Group: netGroup, Resources: []string{"serviceexports/status"}, Verbs: statusVerbs},
+			{Group: coreGroup, Resources: []string{"events"}, Verbs: eventVerbs},
+			{Group: coreGroup, Resources: []string{"pods"}, Verbs: readVerbs},
+			{Group: coreGroup, Resources: []string{"namespaces"}, Verbs: readVerbs},
+		},
+		HubRules: []rbac.Rule{
+			{Group: netGroup, Resources: []string{"endpointsliceexports"}, Verbs: allVerbs},
+		},
+	},
+	{
+		Name:    "endpointsliceexport",
+		Package: "pkg/controllers/member/endpointsliceexport",
+		MemberRules: []rbac.Rule{
+			{Group: discoveryGroup, Resources: []string{"endpointslices"}, Verbs: readVerbs},
+		},
+		HubRules: []rbac.Rule{
+			{Group: netGroup, Resources: []string{"endpointsliceexports"}, Verbs: []string{"get", "list", "watch", "delete"}},
+		},
+	},
+	{
+		Name:    "endpointsliceimport",
+		Package: "pkg/controllers/member/endpointsliceimport",
+		MemberRules: []rbac.Rule{
+			{Group: discoveryGroup, Resources: []string{"endpointslices"}, Verbs: allVerbs},
+			{Group: netGroup, Resources: []string{"multiclusterservices", "serviceimports"}, Verbs: readVerbs},
+			{Group: netGroup, Resources: []string{"multiclusterservices/status"}, Verbs: statusVerbs},
+			{Group: coreGroup, Resources: []string{"services"}, Verbs: readVerbs},
+			{Group: coreGroup, Resources: []string{"events"}, Verbs: eventVerbs},
+		},
+		HubRules: []rbac.Rule{
+			{Group: netGroup, Resources: []string{"endpointsliceimports"}, Verbs: []string{"get", "list", "watch", "update", "patch"}},
+			{Group: netGroup, Resources: []string{"endpointsliceimports/status"}, Verbs: statusVerbs},
+		},
+	},
+	{
+		Name:    "internalserviceexport",
+		Package: "pkg/controllers/member/internalserviceexport",
+		MemberRules: []rbac.Rule{
+			{Group: netGroup, Resources: []string{"serviceexports"}, Verbs: allVerbs},
+			{Group: netGroup, Resources: []string{"serviceexports/status"}, Verbs: statusVerbs},
+			{Group: coreGroup, Resources: []string{"events"}, Verbs: eventVerbs},
+		},
+		HubRules: []rbac.Rule{
+			{Group: netGroup, Resources: []string{"internalserviceexports"}, Verbs: allVerbs},
+			{Group: netGroup, Resources: []string{"internalserviceexports/status"}, Verbs: statusVerbs},
+		},
+	},
+	{
+		Name:    "internalserviceimport",
+		Package: "pkg/controllers/member/internalserviceimport",
+		MemberRules: []rbac.Rule{
+			{Group: netGroup, Resources: []string{"serviceimports"}, Verbs: []string{"get", "list"}},
+			{Group: netGroup, Resources: []string{"serviceimports/status"}, Verbs: statusVerbs},
+		},
+		HubRules: []rbac.Rule{
+			{Group: netGroup, Resources: []string{"internalserviceimports"}, Verbs: []string{"get", "list", "watch", "delete"}},
+		},
+	},
+	{
+		Name:    "serviceexport",
+		Package: "pkg/controllers/member/serviceexport",
+		MemberRules: []rbac.Rule{
+			{Group: netGroup, Resources: []string{"serviceexports"}, Verbs: allVerbs},
+			{Group: netGroup, Resources: []string{"serviceexports/status"}, Verbs: statusVerbs},
+			{Group: netGroup, Resources: []string{"serviceexports/finalizers"}, Verbs: []string{"update"}},
+			{Group: coreGroup, Resources: []string{"services"}, Verbs: allVerbs},
+			{Group: coreGroup, Resources: []string{"namespaces"}, Verbs: readVerbs},
+			{Group: discoveryGroup, Resources: []string{"endpointslices"}, Verbs: readVerbs},
+			{Group: coreGroup, Resources: []string{"events"}, Verbs: eventVerbs},
+		},
+		HubRules: []rbac.Rule{
+			{Group: netGroup, Resources: []string{"internalserviceexports"}, Verbs: allVerbs},
+			{Group: netGroup, Resources: []string{"internalserviceexports/status"}, Verbs: statusVerbs},
+		},
+	},
+	{
+		Name:    "serviceimport",
+		Package: "pkg/controllers/member/serviceimport",
+		MemberRules: []rbac.Rule{
+			{Group: netGroup, Resources: []string{"serviceimports"}, Verbs: []string{"get", "list", "watch", "update", "patch"}},
+			{Group: netGroup, Resources: []string{"serviceimports/finalizers"}, Verbs: []string{"get", "update"}},
+		},
+		HubRules: []rbac.Rule{
+			{Group: netGroup, Resources: []string{"internalserviceimports"}, Verbs: allVerbs},
+		},
+	},
+	{
+		Name:        "internalmembercluster-v1alpha1",
+		Package:     "pkg/controllers/member/internalmembercluster/v1alpha1",
+		MemberRules: leaveRules,
+		HubRules: []rbac.Rule{
+			{Group: "fleet.azure.com", Resources: []string{"internalmemberclusters"}, Verbs: readVerbs},
+			{Group: "fleet.azure.com", Resources: []string{"internalmemberclusters/status"}, Verbs: statusVerbs},
+		},
+	},
+	{
+		Name:        "internalmembercluster-v1beta1",
+		Package:     "pkg/controllers/member/internalmembercluster/v1beta1",
+		MemberRules: leaveRules,
+		HubRules: []rbac.Rule{
+			{Group: "cluster.kubernetes-fleet.io", Resources: []string{"internalmemberclusters"}, Verbs: []string{"get", "list", "watch", "patch"}},
+			{Group: "cluster.kubernetes-fleet.io", Resources: []string{"internalmemberclusters/status"}, Verbs: statusVerbs},
+		},
+	},
+	{
+		Name:    "exportmigration",
+		Package: "pkg/common/exportmigration",
+		MemberRules: []rbac.Rule{
+			{Group: discoveryGroup, Resources: []string{"endpointslices"}, Verbs: []string{"get", "update"}},
+			{Group: coreGroup, Resources: []string{"services"}, Verbs: []string{"get"}},
+		},
+		HubRules: []rbac.Rule{
+			{Group: netGroup, Resources: []string{"endpointsliceexports", "internalserviceexports"}, Verbs: []string{"list", "patch", "delete"}},
+		},
+	},
+	{
+		Name:    "clustersetdns",
+		Package: "pkg/controllers/member/clustersetdns",
+		MemberRules: []rbac.Rule{
+			{Group: netGroup, Resources: []string{"multiclusterservices"}, Verbs: readVerbs},
+			{Group: coreGroup, Resources: []string{"configmaps"}, Verbs: []string{"get", "create", "update"}},
+		},
+	},
+}
+
+// enabledControllers returns the entries of the permission matrix of the controllers enabled by the flags.
+func enabledControllers() []rbac.Controller {
+	disabled := map[string]bool{
+		"internalmembercluster-v1alpha1": !*isV1Alpha1APIEnabled,
+		"internalmembercluster-v1beta1":  !*isV1Beta1APIEnabled,
+		"clustersetdns":                  !*manageClustersetDNS,
+	}
+	var controllers []rbac.Controller
+	for _, c := range memberAgentControllers {
+		if !disabled[c.Name] {
+			controllers = append(controllers, c)
+		}
+	}
+	return controllers
+}
@@ -0,0 +1,92 @@
+/*
+Copyright (c) Microsoft Corporation.
+Licensed under the MIT license.
+*/
+
+package main
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"go.goms.io/fleet-networking/pkg/common/rbac"
+)
+
+// repoRoot is the root of the repository relative to the directory of the package.
+var repoRoot = filepath.Join("..", "..")
+
+// parsePackageMarkers returns the rules declared by the RBAC markers of the non-test Go files in the directory.
+func parsePackageMarkers(t *testing.T, dir string) []rbac.Rule {
+	t.Helper()
+	files, err := filepath.Glob(filepath.Join(dir, "*.go"))
+	if err != nil {
+		t.Fatalf("Glob(%s) = %v, want no error", dir, err)
+	}
+	var rules []rbac.Rule
+	for _, f := range files {
+		if strings.HasSuffix(f, "_test.go") {
+			continue
+		}
+		src, err := os.ReadFile(f)
+		if err != nil {
+			t.Fatalf("ReadFile(%s) = %v, want no error", f, err)
+		}
+		fileRules, err := rbac.ParseMarkers(string(src))
+		if err != nil {
+			t.Fatalf("ParseMarkers(%s) = %v, want no error", f, err)
+		}
+		rules = append(rules, fileRules...)
+	}
+	return rules
+}
+
+// TestMemberAgentControllers_MatchRBACMarkers tests that the permission matrix of every controller matches the RBAC
+// markers from which its role manifests are generated.
+func TestMemberAgentControllers_MatchRBACMarkers(t *testing.T) {
+	for _, c := range memberAgentControllers {
+		t.Run(c.Name, func(t *testing.T) {
+			want := rbac.Expand(parsePackageMarkers(t, filepath.Join(repoRoot, c.Package)))
+			if len(want) == 0 {
+				t.Fatalf("package %s has no RBAC marker", c.Package)
+			}
+			got := rbac.Expand(append(append([]rbac.Rule{}, c.MemberRules...), c.HubRules...))
+			if diff := cmp.Diff(want, got); diff != "" {
+				t.Errorf("permission matrix of %s mismatches its RBAC markers (-markers, +matrix):\n%s", c.Name, diff)
+			}
+		})
+	}
+}
+
+// TestMemberAgentControllers_Complete tests that every member controller package with RBAC markers has an entry in the
+// permission matrix.
+func TestMemberAgentControllers_Complete(t *testing.T) {
+	packages := make(map[string]bool, len(memberAgentControllers))
+	for _, c := range memberAgentControllers {
+		packages[c.Package] = true
+	}
+	root := filepath.Join(repoRoot, "pkg", "controllers", "member")
+	err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || !d.IsDir() {
+			return err
+		}
+		if len(parsePackageMarkers(t, path)) == 0 {
+			return nil
+		}
+		pkg, err := filepath.Rel(repoRoot, path)
+		if err != nil {
+			return err
+		}
+		if pkg = filepath.ToSlash(pkg); !packages[pkg] {
+			t.Errorf("package %s has RBAC markers but no entry in the permission matrix", pkg)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("WalkDir(%s) = %v, want no error", root, err)
+	}
+}
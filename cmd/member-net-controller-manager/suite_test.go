@@ -0,0 +1,199 @@
+/*
+Copyright (c) Microsoft Corporation.
+Licensed under the MIT license.
+*/
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"go/build"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/util/connrotation"
+	"k8s.io/klog/v2"
+	"k8s.io/utils/ptr"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/config"
+	"sigs.k8s.io/controller-runtime/pkg/envtest"
+	"sigs.k8s.io/controller-runtime/pkg/log/zap"
+	metricsserver "sigs.k8s.io/controller-runtime/pkg/metrics/server"
+
+	"go.goms.io/fleet-networking/pkg/common/hubconfig"
+	"go.goms.io/fleet-networking/pkg/common/preflight"
+	"go.goms.io/fleet-networking/pkg/controllers/member/serviceexport"
+)
+
+const (
+	testMemberClusterName = "member-1"
+	testMemberUserNS      = "work"
+
+	// testHubToken authenticates the member agent with both hub clusters, as the client certificates of envtest are
+	// issued by a different CA for each API server.
+	testHubToken = "member-1-token" //nolint:gosec
+)
+
+var (
+	memberTestEnv    *envtest.Environment
+	hubTestEnv       *envtest.Environment
+	secondHubTestEnv *envtest.Environment
+	memberClient     client.Client
+	hubClient        client.Client
+	secondHubClient  client.Client
+	// hubManagerClient is the cached client of the hub manager, which follows the hub server rotations.
+	hubManagerClient client.Client
+	ctx              context.Context
+	cancel           context.CancelFunc
+
+	// hubCfg and secondHubCfg are the admin configs of the hub clusters the hub config directory points to.
+	hubCfg       *rest.Config
+	secondHubCfg *rest.Config
+	hubConfigDir string
+
+	testHubNamespace = fmt.Sprintf(hubconfig.HubNamespaceNameFormat, testMemberClusterName)
+)
+
+func TestAPIs(t *testing.T) {
+	RegisterFailHandler(Fail)
+
+	RunSpecs(t, "Member Net Controller Manager Suite")
+}
+
+var _ = BeforeSuite(func() {
+	klog.SetLogger(zap.New(zap.WriteTo(GinkgoWriter), zap.UseDevMode(true)))
+
+	ctx, cancel = context.WithCancel(context.TODO())
+
+	By("bootstrap the test environment")
+	memberTestEnv = &envtest.Environment{
+		CRDDirectoryPaths:     []string{filepath.Join("..", "..", "config", "crd", "bases")},
+		ErrorIfCRDPathMissing: true,
+	}
+	memberCfg, err := memberTestEnv.Start()
+	Expect(err).NotTo(HaveOccurred())
+	Expect(memberCfg).NotTo(BeNil())
+
+	// The member agent is rotated between two hub clusters, which accept the same token.
+	tokenAuthFile := filepath.Join(GinkgoT().TempDir(), "tokens.csv")
+	Expect(os.WriteFile(tokenAuthFile, []byte(fmt.Sprintf("%s,%s,%s,\"system:masters\"\n", testHubToken, testMemberClusterName, testMemberClusterName)), 0o600)).Should(Succeed())
+	hubTestEnv = newHubTestEnv(tokenAuthFile)
+	hubCfg, err = hubTestEnv.Start()
+	Expect(err).NotTo(HaveOccurred())
+	Expect(hubCfg).NotTo(BeNil())
+	secondHubTestEnv = newHubTestEnv(tokenAuthFile)
+	secondHubCfg, err = secondHubTestEnv.Start()
+	Expect(err).NotTo(HaveOccurred())
+	Expect(secondHubCfg).NotTo(BeNil())
+
+	memberClient, err = client.New(memberCfg, client.Options{Scheme: scheme})
+	Expect(err).NotTo(HaveOccurred())
+	hubClient, err = client.New(hubCfg, client.Options{Scheme: scheme})
+	Expect(err).NotTo(HaveOccurred())
+	secondHubClient, err = client.New(secondHubCfg, client.Options{Scheme: scheme})
+	Expect(err).NotTo(HaveOccurred())
+
+	By("create the namespaces")
+	Expect(memberClient.Create(ctx, &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: testMemberUserNS}})).Should(Succeed())
+	Expect(memberClient.Create(ctx, &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: *fleetSystemNamespace}})).Should(Succeed())
+	Expect(hubClient.Create(ctx, &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: testHubNamespace}})).Should(Succeed())
+	Expect(secondHubClient.Create(ctx, &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: testHubNamespace}})).Should(Succeed())
+
+	By("set up the member controllers with the none cloud provider")
+	Expect(os.Setenv("MEMBER_CLUSTER_NAME", testMemberClusterName)).Should(Succeed())
+	*cloudProviderName = serviceexport.CloudProviderNone
+	*enableTrafficManagerFeature = true
+	// The cloud config file does not exist; the controllers must not load it with the none cloud provider.
+	*cloudConfigFile = filepath.Join(GinkgoT().TempDir(), "azure.json")
+	*isV1Alpha1APIEnabled = false
+	*isV1Beta1APIEnabled = true
+
+	mgrOpts := ctrl.Options{
+		Scheme: scheme,
+		Metrics: metricsserver.Options{
+			BindAddress: "0",
+		},
+		Controller: config.Controller{
+			SkipNameValidation: ptr.To(true),
+		},
+	}
+	By("set up the hub config rotator with the first hub cluster")
+	hubConfigDir = GinkgoT().TempDir()
+	writeHubConfigDir(hubCfg)
+	memberHubCfg := &rest.Config{
+		Host:            hubCfg.Host,
+		BearerToken:     testHubToken,
+		TLSClientConfig: rest.TLSClientConfig{CAData: hubCfg.CAData},
+	}
+	hubConfigRotator, err := hubconfig.NewRotator(memberHubCfg, hubConfigDir, 100*time.Millisecond, func(ctx context.Context, config *rest.Config) error {
+		return preflight.CheckMemberClusterNamespace(ctx, config, testHubNamespace)
+	})
+	Expect(err).NotTo(HaveOccurred())
+	memberHubCfg.Wrap(hubConfigRotator.WrapTransport)
+	hubDialer := connrotation.NewDialer((&net.Dialer{}).DialContext)
+	memberHubCfg.Dial = hubDialer.DialContext
+	hubConfigRotator.OnRotate = hubDialer.CloseAll
+
+	hubMgr, err := ctrl.NewManager(memberHubCfg, mgrOpts)
+	Expect(err).NotTo(HaveOccurred())
+	hubManagerClient = hubMgr.GetClient()
+	memberMgr, err := ctrl.NewManager(memberCfg, mgrOpts)
+	Expect(err).NotTo(HaveOccurred())
+	Expect(memberMgr.Add(hubConfigRotator)).Should(Succeed())
+
+	Expect(setupControllersWithManager(ctx, hubMgr, memberMgr, hubDialer, hubConfigRotator)).Should(Succeed())
+
+	go func() {
+		defer GinkgoRecover()
+		Expect(hubMgr.Start(ctx)).Should(Succeed(), "failed to start hub manager")
+	}()
+	go func() {
+		defer GinkgoRecover()
+		Expect(memberMgr.Start(ctx)).Should(Succeed(), "failed to start member manager")
+	}()
+})
+
+var _ = AfterSuite(func() {
+	defer klog.Flush()
+	cancel()
+
+	By("tearing down the test environment")
+	Expect(memberTestEnv.Stop()).Should(Succeed())
+	Expect(hubTestEnv.Stop()).Should(Succeed())
+	Expect(secondHubTestEnv.Stop()).Should(Succeed())
+})
+
+// newHubTestEnv returns a hub cluster test environment which authenticates the member agent with the tokens of the
+// token auth file.
+func newHubTestEnv(tokenAuthFile string) *envtest.Environment {
+	env := &envtest.Environment{
+		CRDDirectoryPaths: []string{
+			filepath.Join("..", "..", "config", "crd", "bases"),
+			// The package name must match with the version of the fleet package in use.
+			filepath.Join(build.Default.GOPATH, "pkg", "mod", "go.goms.io", "fleet@v0.11.4", "config", "crd", "bases"),
+		},
+		ErrorIfCRDPathMissing: true,
+	}
+	env.ControlPlane.GetAPIServer().Configure().Append("token-auth-file", tokenAuthFile)
+	return env
+}
+
+// writeHubConfigDir points the hub config directory to a hub cluster. Each file is replaced atomically; the rotator
+// rejects the hub config when it reads the server of one hub cluster with the CA of the other, and retries later.
+func writeHubConfigDir(cfg *rest.Config) {
+	for name, content := range map[string][]byte{"server": []byte(cfg.Host), "ca.crt": cfg.CAData} {
+		tmp := filepath.Join(hubConfigDir, "."+name)
+		Expect(os.WriteFile(tmp, content, 0o600)).Should(Succeed())
+		Expect(os.Rename(tmp, filepath.Join(hubConfigDir, name))).Should(Succeed())
+	}
+}
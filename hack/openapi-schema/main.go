@@ -0,0 +1,62 @@
+/*
+Copyright (c) Microsoft Corporation.
+Licensed under the MIT license.
+*/
+
+// Binary openapi-schema prints the OpenAPI v2 schema of the fleet networking API types, as generated by openapi-gen in
+// pkg/client/openapi, in JSON. applyconfiguration-gen embeds the schema in the apply configurations so that the
+// fake clientset can serve server-side apply requests.
+//
+// Usage:
+//
+//	go run ./hack/openapi-schema > schema.json
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"k8s.io/kube-openapi/pkg/common"
+	"k8s.io/kube-openapi/pkg/util"
+	"k8s.io/kube-openapi/pkg/validation/spec"
+
+	"go.goms.io/fleet-networking/pkg/client/openapi"
+)
+
+func main() {
+	refFunc := func(name string) spec.Ref {
+		return spec.MustCreateRef("#/definitions/" + util.ToRESTFriendlyName(name))
+	}
+	defs := openapi.GetOpenAPIDefinitions(refFunc)
+	schemaDefs := make(map[string]spec.Schema, len(defs))
+	for name, def := range defs {
+		// Some types, e.g. IntOrString, carry a v2 specific schema as an extension of their definition.
+		if schema, ok := def.Schema.Extensions[common.ExtensionV2Schema]; ok {
+			if v2Schema, isSchema := schema.(spec.Schema); isSchema {
+				schemaDefs[util.ToRESTFriendlyName(name)] = v2Schema
+				continue
+			}
+		}
+		schemaDefs[util.ToRESTFriendlyName(name)] = def.Schema
+	}
+
+	swagger := &spec.Swagger{
+		SwaggerProps: spec.SwaggerProps{
+			Swagger: "2.0",
+			Info: &spec.Info{
+				InfoProps: spec.InfoProps{
+					Title:   "fleet-networking",
+					Version: "unversioned",
+				},
+			},
+			Definitions: schemaDefs,
+		},
+	}
+	data, err := json.MarshalIndent(swagger, "", "  ")
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to marshal the OpenAPI schema: %v\n", err)
+		os.Exit(1)
+	}
+	fmt.Println(string(data))
+}
@@ -0,0 +1,143 @@
+/*
+Copyright (c) Microsoft Corporation.
+Licensed under the MIT license.
+*/
+
+// Binary verify-codegen runs a code generation command and fails if it changes any file under the given paths, i.e.
+// if the generated code committed in the repository is stale. The files are restored to their committed state once
+// the check is done.
+//
+// Usage:
+//
+//	go run ./hack/verify-codegen --paths=api,pkg/client -- make generate
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+var paths = flag.String("paths", "", "The comma-separated list of the directories holding generated code.")
+
+func main() {
+	flag.Parse()
+	if *paths == "" || flag.NArg() == 0 {
+		fmt.Fprintln(os.Stderr, "usage: verify-codegen --paths=<dir>[,<dir>...] -- <command> [args...]")
+		os.Exit(2)
+	}
+	dirs := strings.Split(*paths, ",")
+
+	before, err := snapshot(dirs)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to read the generated code: %v\n", err)
+		os.Exit(1)
+	}
+	cmd := exec.Command(flag.Arg(0), flag.Args()[1:]...) //nolint:gosec
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	runErr := cmd.Run()
+	after, err := snapshot(dirs)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to read the regenerated code: %v\n", err)
+		os.Exit(1)
+	}
+	if err := restore(before, after); err != nil {
+		fmt.Fprintf(os.Stderr, "failed to restore the generated code: %v\n", err)
+		os.Exit(1)
+	}
+	if runErr != nil {
+		fmt.Fprintf(os.Stderr, "failed to run %q: %v\n", strings.Join(flag.Args(), " "), runErr)
+		os.Exit(1)
+	}
+
+	if stale := diff(before, after); len(stale) > 0 {
+		fmt.Fprintf(os.Stderr, "The generated code is stale; run %q and commit the result:\n", strings.Join(flag.Args(), " "))
+		for _, s := range stale {
+			fmt.Fprintf(os.Stderr, "  %s\n", s)
+		}
+		os.Exit(1)
+	}
+	fmt.Println("The generated code is up to date.")
+}
+
+// snapshot returns the contents of the regular files under the given directories, by path.
+func snapshot(dirs []string) (map[string][]byte, error) {
+	files := map[string][]byte{}
+	for _, dir := range dirs {
+		err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+			if err != nil {
+				if os.IsNotExist(err) && path == dir {
+					// The directory is yet to be generated.
+					return nil
+				}
+				return err
+			}
+			if !d.Type().IsRegular() {
+				return nil
+			}
+			content, err := os.ReadFile(path)
+			if err != nil {
+				return err
+			}
+			files[path] = content
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	return files, nil
+}
+
+// diff returns the sorted list of the files which are added, removed or changed from before to after.
+func diff(before, after map[string][]byte) []string {
+	var res []string
+	for path, content := range before {
+		newContent, ok := after[path]
+		switch {
+		case !ok:
+			res = append(res, "removed: "+path)
+		case !bytes.Equal(content, newContent):
+			res = append(res, "changed: "+path)
+		}
+	}
+	for path := range after {
+		if _, ok := before[path]; !ok {
+			res = append(res, "added: "+path)
+		}
+	}
+	sort.Slice(res, func(i, j int) bool {
+		return res[i][strings.Index(res[i], " ")+1:] < res[j][strings.Index(res[j], " ")+1:]
+	})
+	return res
+}
+
+// restore brings the files back to their contents before the generation, removing the files it added.
+func restore(before, after map[string][]byte) error {
+	for path := range after {
+		if _, ok := before[path]; !ok {
+			if err := os.Remove(path); err != nil {
+				return err
+			}
+		}
+	}
+	for path, content := range before {
+		if newContent, ok := after[path]; ok && bytes.Equal(content, newContent) {
+			continue
+		}
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			return err
+		}
+		if err := os.WriteFile(path, content, 0o644); err != nil { //nolint:gosec
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,80 @@
+/*
+Copyright (c) Microsoft Corporation.
+Licensed under the MIT license.
+*/
+
+package client_test
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/tools/cache"
+
+	fleetnetv1alpha1 "go.goms.io/fleet-networking/api/v1alpha1"
+	fleetnetv1beta1 "go.goms.io/fleet-networking/api/v1beta1"
+	applyv1beta1 "go.goms.io/fleet-networking/pkg/client/applyconfiguration/api/v1beta1"
+	"go.goms.io/fleet-networking/pkg/client/clientset/versioned/fake"
+	"go.goms.io/fleet-networking/pkg/client/informers/externalversions"
+)
+
+// Example lists the ServiceImports of a namespace with the informer backed lister, and server-side applies a
+// TrafficManagerProfile owned by the "example-controller" field manager.
+func Example() {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	// Replace the fake clientset with versioned.NewForConfig(restConfig) to talk to a real hub cluster.
+	client := fake.NewClientset(
+		&fleetnetv1alpha1.ServiceImport{ObjectMeta: metav1.ObjectMeta{Namespace: "work", Name: "app"}},
+		&fleetnetv1alpha1.ServiceImport{ObjectMeta: metav1.ObjectMeta{Namespace: "work", Name: "db"}},
+		&fleetnetv1alpha1.ServiceImport{ObjectMeta: metav1.ObjectMeta{Namespace: "other", Name: "app"}},
+	)
+
+	factory := externalversions.NewSharedInformerFactoryWithOptions(client, 0, externalversions.WithNamespace("work"))
+	serviceImportInformer := factory.Networking().V1alpha1().ServiceImports()
+	lister := serviceImportInformer.Lister()
+	stopCh := make(chan struct{})
+	factory.Start(stopCh)
+	defer func() {
+		close(stopCh)
+		factory.Shutdown()
+	}()
+	if !cache.WaitForCacheSync(ctx.Done(), serviceImportInformer.Informer().HasSynced) {
+		fmt.Println("failed to sync the serviceImport informer")
+		return
+	}
+
+	serviceImports, err := lister.ServiceImports("work").List(labels.Everything())
+	if err != nil {
+		fmt.Printf("failed to list the serviceImports: %v\n", err)
+		return
+	}
+	names := make(map[string]bool, len(serviceImports))
+	for _, svcImport := range serviceImports {
+		names[svcImport.Name] = true
+	}
+	fmt.Printf("serviceImports in work: app=%t db=%t\n", names["app"], names["db"])
+
+	profile := applyv1beta1.TrafficManagerProfile("app-profile", "work").
+		WithSpec(applyv1beta1.TrafficManagerProfileSpec().
+			WithResourceGroup("app-rg").
+			WithMonitorConfig(applyv1beta1.MonitorConfig().
+				WithProtocol(fleetnetv1beta1.TrafficManagerMonitorProtocolHTTPS).
+				WithPath("/healthz").
+				WithPort(443)))
+	applied, err := client.NetworkingV1beta1().TrafficManagerProfiles("work").Apply(ctx, profile, metav1.ApplyOptions{FieldManager: "example-controller", Force: true})
+	if err != nil {
+		fmt.Printf("failed to apply the trafficManagerProfile: %v\n", err)
+		return
+	}
+	fmt.Printf("applied trafficManagerProfile %s/%s in resource group %s, probing %s %s\n",
+		applied.Namespace, applied.Name, applied.Spec.ResourceGroup, *applied.Spec.MonitorConfig.Protocol, *applied.Spec.MonitorConfig.Path)
+
+	// Output:
+	// serviceImports in work: app=true db=true
+	// applied trafficManagerProfile work/app-profile in resource group app-rg, probing HTTPS /healthz
+}
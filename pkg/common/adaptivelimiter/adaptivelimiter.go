@@ -0,0 +1,159 @@
+/*
+Copyright (c) Microsoft Corporation.
+Licensed under the MIT license.
+*/
+
+// Package adaptivelimiter features a limiter of the concurrent calls to a server which throttles its clients, e.g.
+// Azure Resource Manager, shared by all the reconciliations of a controller.
+//
+// The limit adapts to the throttling of the server: it is halved when a call is throttled and grows back by one call
+// for every limit calls which succeed, so that the reconciliations converge to the concurrency the server accepts
+// instead of all failing and retrying together.
+package adaptivelimiter
+
+import (
+	"context"
+	"math"
+	"sync"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"k8s.io/klog/v2"
+	ctrlmetrics "sigs.k8s.io/controller-runtime/pkg/metrics"
+
+	"go.goms.io/fleet-networking/pkg/common/metrics"
+)
+
+const (
+	// minLimit is the lowest limit, so that the calls keep being admitted one by one while the server throttles them.
+	minLimit = 1
+	// decreaseFactor is the factor by which the limit shrinks when a call is throttled.
+	decreaseFactor = 0.5
+)
+
+var (
+	// limiterLimit reports the current limit of the concurrent calls of each limiter.
+	limiterLimit = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: metrics.MetricsNamespace,
+			Subsystem: metrics.MetricsSubsystem,
+			Name:      "adaptive_limiter_limit",
+			Help:      "The current limit of the concurrent calls admitted by the adaptive limiter",
+		},
+		[]string{"limiter"},
+	)
+
+	// limiterRejections counts the calls admitted by each limiter which the server has rejected as throttled.
+	limiterRejections = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: metrics.MetricsNamespace,
+			Subsystem: metrics.MetricsSubsystem,
+			Name:      "adaptive_limiter_rejections_total",
+			Help:      "The number of calls admitted by the adaptive limiter which the server rejected as throttled (429 Too Many Requests)",
+		},
+		[]string{"limiter"},
+	)
+)
+
+func init() {
+	// Register limiterLimit (fleet_networking_adaptive_limiter_limit) and limiterRejections
+	// (fleet_networking_adaptive_limiter_rejections_total) metrics with the controller runtime global metrics registry.
+	ctrlmetrics.Registry.MustRegister(limiterLimit, limiterRejections)
+}
+
+// Limiter limits the number of the concurrent calls, adapting the limit to the throttling of the server.
+type Limiter struct {
+	name     string
+	maxLimit float64
+
+	mu sync.Mutex
+	// limit is the current limit; its integer part is the number of calls admitted at once.
+	limit    float64
+	inFlight int
+	// generation is bumped every time the limit shrinks, so that the calls admitted under a limit which has already
+	// been shrunk do not shrink it again.
+	generation uint64
+	// changed is closed, and replaced, when a call is released or the limit changes.
+	changed chan struct{}
+}
+
+// Permit is the admission of a call by the limiter, which must be released once the call returns.
+type Permit struct {
+	limiter    *Limiter
+	generation uint64
+	released   bool
+}
+
+// New returns a limiter, reported by the metrics with the given name, which admits up to maxLimit concurrent calls;
+// a maxLimit below 1 admits the calls one by one.
+func New(name string, maxLimit int) *Limiter {
+	if maxLimit < minLimit {
+		maxLimit = minLimit
+	}
+	l := &Limiter{
+		name:     name,
+		maxLimit: float64(maxLimit),
+		limit:    float64(maxLimit),
+		changed:  make(chan struct{}),
+	}
+	limiterLimit.WithLabelValues(name).Set(l.limit)
+	return l
+}
+
+// Limit returns the number of calls currently admitted at once.
+func (l *Limiter) Limit() int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return int(l.limit)
+}
+
+// Acquire waits until the call is admitted, or the context is done, in which case the context error is returned, so
+// that a shutdown is not blocked by the calls waiting for their turn.
+func (l *Limiter) Acquire(ctx context.Context) (*Permit, error) {
+	for {
+		l.mu.Lock()
+		if l.inFlight < int(l.limit) {
+			l.inFlight++
+			permit := &Permit{limiter: l, generation: l.generation}
+			l.mu.Unlock()
+			return permit, nil
+		}
+		changed := l.changed
+		l.mu.Unlock()
+
+		select {
+		case <-changed:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+}
+
+// Release releases the call admitted with the permit, shrinking the limit if the server has throttled the call, or
+// growing it back otherwise; releasing a permit more than once has no effect.
+func (p *Permit) Release(throttled bool) {
+	l := p.limiter
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if p.released {
+		return
+	}
+	p.released = true
+	l.inFlight--
+
+	switch {
+	case throttled && p.generation == l.generation:
+		limiterRejections.WithLabelValues(l.name).Inc()
+		l.limit = math.Max(math.Floor(l.limit*decreaseFactor), minLimit)
+		l.generation++
+		klog.V(2).InfoS("The server throttled the call; the concurrency limit has been shrunk", "limiter", l.name, "limit", int(l.limit))
+	case throttled:
+		// The call was admitted before the limit shrank; the throttling it observed has already been accounted for.
+		limiterRejections.WithLabelValues(l.name).Inc()
+	default:
+		// The limit grows by one call once as many calls as the limit have succeeded.
+		l.limit = math.Min(l.limit+1/l.limit, l.maxLimit)
+	}
+	limiterLimit.WithLabelValues(l.name).Set(math.Floor(l.limit))
+	close(l.changed)
+	l.changed = make(chan struct{})
+}
@@ -0,0 +1,215 @@
+/*
+Copyright (c) Microsoft Corporation.
+Licensed under the MIT license.
+*/
+
+package adaptivelimiter
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+// throttlingServer is a fake server which throttles the calls above a number of concurrent calls.
+type throttlingServer struct {
+	threshold int
+
+	mu       sync.Mutex
+	inFlight int
+}
+
+// call returns whether the call has been throttled.
+func (s *throttlingServer) call() bool {
+	s.mu.Lock()
+	s.inFlight++
+	throttled := s.inFlight > s.threshold
+	s.mu.Unlock()
+
+	time.Sleep(time.Millisecond)
+
+	s.mu.Lock()
+	s.inFlight--
+	s.mu.Unlock()
+	return throttled
+}
+
+// TestAcquire tests that the calls above the limit wait until a call is released.
+func TestAcquire(t *testing.T) {
+	ctx := context.Background()
+	l := New("test-acquire", 2)
+	first, err := l.Acquire(ctx)
+	if err != nil {
+		t.Fatalf("Acquire() got error %v, want no error", err)
+	}
+	if _, err := l.Acquire(ctx); err != nil {
+		t.Fatalf("Acquire() got error %v, want no error", err)
+	}
+
+	admitted := make(chan struct{})
+	go func() {
+		if _, err := l.Acquire(ctx); err != nil {
+			t.Errorf("Acquire() got error %v, want no error", err)
+		}
+		close(admitted)
+	}()
+	select {
+	case <-admitted:
+		t.Fatal("Acquire() admitted a call above the limit")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	first.Release(false)
+	select {
+	case <-admitted:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Acquire() did not admit the call after a call was released")
+	}
+}
+
+// TestAcquire_ContextDone tests that a call waiting for its turn returns once its context is done.
+func TestAcquire_ContextDone(t *testing.T) {
+	l := New("test-context-done", 1)
+	if _, err := l.Acquire(context.Background()); err != nil {
+		t.Fatalf("Acquire() got error %v, want no error", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() {
+		_, err := l.Acquire(ctx)
+		done <- err
+	}()
+	cancel()
+	select {
+	case err := <-done:
+		if !errors.Is(err, context.Canceled) {
+			t.Errorf("Acquire() got error %v, want %v", err, context.Canceled)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Acquire() did not return after the context was cancelled")
+	}
+}
+
+// TestRelease tests how the limit shrinks and grows back.
+func TestRelease(t *testing.T) {
+	ctx := context.Background()
+	name := "test-release"
+	limiterRejections.DeleteLabelValues(name)
+	l := New(name, 8)
+
+	// The calls admitted under the same limit shrink it only once.
+	var permits []*Permit
+	for i := 0; i < 3; i++ {
+		permit, err := l.Acquire(ctx)
+		if err != nil {
+			t.Fatalf("Acquire() got error %v, want no error", err)
+		}
+		permits = append(permits, permit)
+	}
+	for _, permit := range permits {
+		permit.Release(true)
+	}
+	if got := l.Limit(); got != 4 {
+		t.Errorf("Limit() after the concurrent throttled calls = %d, want 4", got)
+	}
+	if got := testutil.ToFloat64(limiterRejections.WithLabelValues(name)); got != 3 {
+		t.Errorf("rejections metric = %v, want 3", got)
+	}
+
+	// A permit released twice is accounted for once.
+	permit, err := l.Acquire(ctx)
+	if err != nil {
+		t.Fatalf("Acquire() got error %v, want no error", err)
+	}
+	permit.Release(true)
+	permit.Release(true)
+	if got := l.Limit(); got != 2 {
+		t.Errorf("Limit() after another throttled call = %d, want 2", got)
+	}
+
+	for i := 0; i < 2; i++ {
+		permit, err := l.Acquire(ctx)
+		if err != nil {
+			t.Fatalf("Acquire() got error %v, want no error", err)
+		}
+		permit.Release(true)
+	}
+	if got := l.Limit(); got != minLimit {
+		t.Errorf("Limit() after the throttled calls = %d, want %d", got, minLimit)
+	}
+
+	// The limit grows by one call once as many calls as the limit have succeeded.
+	wantLimits := []int{2, 2, 2, 3, 3, 3, 4}
+	for i, want := range wantLimits {
+		permit, err := l.Acquire(ctx)
+		if err != nil {
+			t.Fatalf("Acquire() got error %v, want no error", err)
+		}
+		permit.Release(false)
+		if got := l.Limit(); got != want {
+			t.Errorf("Limit() after %d succeeded calls = %d, want %d", i+1, got, want)
+		}
+	}
+	if got := testutil.ToFloat64(limiterLimit.WithLabelValues(name)); got != 4 {
+		t.Errorf("limit metric = %v, want 4", got)
+	}
+
+	for i := 0; i < 100; i++ {
+		permit, err := l.Acquire(ctx)
+		if err != nil {
+			t.Fatalf("Acquire() got error %v, want no error", err)
+		}
+		permit.Release(false)
+	}
+	if got := l.Limit(); got != 8 {
+		t.Errorf("Limit() after the succeeded calls = %d, want the max limit 8", got)
+	}
+}
+
+// TestLimiter_Convergence tests that concurrent callers retrying their throttled calls all complete, with only a
+// minority of the calls throttled, against a server throttling the calls above a concurrency threshold.
+func TestLimiter_Convergence(t *testing.T) {
+	const (
+		callers         = 50
+		callsPerCaller  = 20
+		serverThreshold = 4
+	)
+	name := "test-convergence"
+	limiterRejections.DeleteLabelValues(name)
+	l := New(name, 32)
+	server := &throttlingServer{threshold: serverThreshold}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+	var wg sync.WaitGroup
+	for i := 0; i < callers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for succeeded := 0; succeeded < callsPerCaller; {
+				permit, err := l.Acquire(ctx)
+				if err != nil {
+					t.Errorf("Acquire() got error %v, want no error", err)
+					return
+				}
+				throttled := server.call()
+				permit.Release(throttled)
+				if !throttled {
+					succeeded++
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	// Without the limiter, most of the calls of the callers would be throttled.
+	rejections := testutil.ToFloat64(limiterRejections.WithLabelValues(name))
+	if max := float64(callers*callsPerCaller) / 2; rejections > max {
+		t.Errorf("rejections metric = %v, want at most %v", rejections, max)
+	}
+}
@@ -0,0 +1,207 @@
+/*
+Copyright (c) Microsoft Corporation.
+Licensed under the MIT license.
+*/
+
+// Package apicompat features a checker which verifies that the hub cluster still serves the versions of the
+// fleet-networking CRDs the member agent has been built for, and resolves the versions of the InternalMemberCluster
+// API the member agent reconciles while the fleet migrates to the v1beta1 APIs.
+package apicompat
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/discovery"
+	"k8s.io/klog/v2"
+	ctrlmetrics "sigs.k8s.io/controller-runtime/pkg/metrics"
+
+	fleetnetv1alpha1 "go.goms.io/fleet-networking/api/v1alpha1"
+	"go.goms.io/fleet-networking/pkg/common/metrics"
+)
+
+// RequiredHubResources are the fleet-networking resources, in the versions this binary has been built for, which the
+// member agent reads and writes in the hub cluster.
+var RequiredHubResources = []schema.GroupVersionResource{
+	fleetnetv1alpha1.GroupVersion.WithResource("endpointsliceexports"),
+	fleetnetv1alpha1.GroupVersion.WithResource("endpointsliceimports"),
+	fleetnetv1alpha1.GroupVersion.WithResource("internalserviceexports"),
+	fleetnetv1alpha1.GroupVersion.WithResource("internalserviceimports"),
+}
+
+var (
+	// apiVersionSkew reports, per CRD, whether the hub cluster no longer serves the version the member agent requires.
+	apiVersionSkew = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: metrics.MetricsNamespace,
+			Subsystem: metrics.MetricsSubsystem,
+			Name:      "api_version_skew",
+			Help:      "Whether the hub cluster no longer serves the version of the CRD required by the member agent (1) or not (0)",
+		},
+		[]string{"crd"},
+	)
+)
+
+func init() {
+	// Register apiVersionSkew (fleet_networking_api_version_skew) metric
+	// with the controller runtime global metrics registry.
+	ctrlmetrics.Registry.MustRegister(apiVersionSkew)
+}
+
+// Checker discovers the versions of the fleet-networking CRDs served by the hub cluster and compares them with the
+// required ones. On skew, it logs a single error naming the CRDs and versions and fails the readiness check, instead
+// of letting the controllers flood the logs with per-object validation errors.
+//
+// Checker implements the controller-runtime Runnable interface and runs whether the manager is the leader or not.
+type Checker struct {
+	// Discovery discovers the resources served by the hub cluster.
+	Discovery discovery.DiscoveryInterface
+	// Required are the resources the member agent requires.
+	Required []schema.GroupVersionResource
+	// Interval is how often the compatibility is checked after the startup.
+	Interval time.Duration
+
+	mu      sync.Mutex
+	skewErr error
+}
+
+// NeedLeaderElection implements the LeaderElectionRunnable interface; the readiness of every replica depends on the
+// checker.
+func (c *Checker) NeedLeaderElection() bool {
+	return false
+}
+
+// Start checks the compatibility periodically and blocks until the context is done.
+func (c *Checker) Start(ctx context.Context) error {
+	klog.V(2).InfoS("Starting the hub API compatibility checker", "interval", c.Interval)
+	ticker := time.NewTicker(c.Interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			klog.V(2).InfoS("Stopping the hub API compatibility checker")
+			return nil
+		case <-ticker.C:
+			if err := c.Check(); err != nil {
+				klog.ErrorS(err, "Failed to check the hub API compatibility")
+			}
+		}
+	}
+}
+
+// ReadyzCheck is a healthz.Checker which fails while the hub cluster does not serve the required versions.
+func (c *Checker) ReadyzCheck(_ *http.Request) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.skewErr
+}
+
+// Check discovers the resources served by the hub cluster and updates the compatibility state. It returns an error
+// only when the discovery fails, in which case the state is left unchanged.
+func (c *Checker) Check() error {
+	servedVersions, err := c.servedVersions()
+	if err != nil {
+		return err
+	}
+
+	// Group the required resources by version, so that each version is discovered once.
+	resourcesByVersion := make(map[schema.GroupVersion][]string)
+	for _, gvr := range c.Required {
+		resourcesByVersion[gvr.GroupVersion()] = append(resourcesByVersion[gvr.GroupVersion()], gvr.Resource)
+	}
+	var skews []string
+	for gv, resources := range resourcesByVersion {
+		served, err := c.servedResources(gv, servedVersions[gv.Group])
+		if err != nil {
+			return err
+		}
+		for _, resource := range resources {
+			crd := schema.GroupResource{Group: gv.Group, Resource: resource}.String()
+			if served[resource] {
+				apiVersionSkew.WithLabelValues(crd).Set(0)
+				continue
+			}
+			apiVersionSkew.WithLabelValues(crd).Set(1)
+			skews = append(skews, describeSkew(crd, gv.Version, servedVersions[gv.Group]))
+		}
+	}
+	sort.Strings(skews)
+
+	var skewErr error
+	if len(skews) > 0 {
+		skewErr = fmt.Errorf("the hub cluster does not serve the fleet-networking API versions this member agent has been built for; upgrade the member agent to match the hub cluster (%s)", strings.Join(skews, "; "))
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	switch {
+	case skewErr != nil && (c.skewErr == nil || c.skewErr.Error() != skewErr.Error()):
+		// Log only when the skew changes, so that a persistent skew does not flood the logs.
+		klog.ErrorS(skewErr, "Hub API version skew detected")
+	case skewErr == nil && c.skewErr != nil:
+		klog.InfoS("Hub API version skew resolved")
+	}
+	c.skewErr = skewErr
+	return nil
+}
+
+// servedVersions returns the versions served by the hub cluster per API group.
+func (c *Checker) servedVersions() (map[string][]string, error) {
+	groups, err := c.Discovery.ServerGroups()
+	if err != nil {
+		return nil, fmt.Errorf("failed to discover the API groups of the hub cluster: %w", err)
+	}
+	res := make(map[string][]string, len(groups.Groups))
+	for _, group := range groups.Groups {
+		for _, version := range group.Versions {
+			res[group.Name] = append(res[group.Name], version.Version)
+		}
+	}
+	return res, nil
+}
+
+// servedResources returns the set of resources served by the hub cluster in the given version.
+func (c *Checker) servedResources(gv schema.GroupVersion, servedVersions []string) (map[string]bool, error) {
+	isServed := false
+	for _, v := range servedVersions {
+		if v == gv.Version {
+			isServed = true
+			break
+		}
+	}
+	if !isServed {
+		return map[string]bool{}, nil
+	}
+	resourceList, err := c.Discovery.ServerResourcesForGroupVersion(gv.String())
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return map[string]bool{}, nil
+		}
+		return nil, fmt.Errorf("failed to discover the resources of %s in the hub cluster: %w", gv, err)
+	}
+	res := make(map[string]bool, len(resourceList.APIResources))
+	for _, r := range resourceList.APIResources {
+		res[r.Name] = true
+	}
+	return res, nil
+}
+
+func describeSkew(crd, requiredVersion string, servedVersions []string) string {
+	for _, v := range servedVersions {
+		if v == requiredVersion {
+			return fmt.Sprintf("CRD %s is not served in version %s", crd, requiredVersion)
+		}
+	}
+	if len(servedVersions) == 0 {
+		return fmt.Sprintf("CRD %s requires version %s, but no version of the API group is served", crd, requiredVersion)
+	}
+	return fmt.Sprintf("CRD %s requires version %s, but only versions [%s] are served", crd, requiredVersion, strings.Join(servedVersions, ", "))
+}
@@ -0,0 +1,174 @@
+/*
+Copyright (c) Microsoft Corporation.
+Licensed under the MIT license.
+*/
+
+package apicompat
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	fakediscovery "k8s.io/client-go/discovery/fake"
+	clienttesting "k8s.io/client-go/testing"
+
+	fleetnetv1alpha1 "go.goms.io/fleet-networking/api/v1alpha1"
+)
+
+const (
+	v1beta1GroupVersion = "networking.fleet.azure.com/v1beta1"
+
+	internalServiceExportCRD = "internalserviceexports.networking.fleet.azure.com"
+	internalServiceImportCRD = "internalserviceimports.networking.fleet.azure.com"
+)
+
+func resourceList(groupVersion string, resources ...string) *metav1.APIResourceList {
+	list := &metav1.APIResourceList{GroupVersion: groupVersion}
+	for _, r := range resources {
+		list.APIResources = append(list.APIResources, metav1.APIResource{Name: r})
+	}
+	return list
+}
+
+func TestCheck(t *testing.T) {
+	required := []schema.GroupVersionResource{
+		fleetnetv1alpha1.GroupVersion.WithResource("internalserviceexports"),
+		fleetnetv1alpha1.GroupVersion.WithResource("internalserviceimports"),
+	}
+	tests := []struct {
+		name      string
+		resources []*metav1.APIResourceList
+		wantSkew  map[string]float64
+		// wantErrSubstrings are the substrings of the readiness error; the hub is compatible when it is empty.
+		wantErrSubstrings []string
+	}{
+		{
+			name: "compatible",
+			resources: []*metav1.APIResourceList{
+				resourceList(fleetnetv1alpha1.GroupVersion.String(), "internalserviceexports", "internalserviceimports", "serviceimports"),
+			},
+			wantSkew: map[string]float64{
+				internalServiceExportCRD: 0,
+				internalServiceImportCRD: 0,
+			},
+		},
+		{
+			name: "newer hub still serving the required version",
+			resources: []*metav1.APIResourceList{
+				resourceList(fleetnetv1alpha1.GroupVersion.String(), "internalserviceexports", "internalserviceimports"),
+				resourceList(v1beta1GroupVersion, "internalserviceexports", "internalserviceimports"),
+			},
+			wantSkew: map[string]float64{
+				internalServiceExportCRD: 0,
+				internalServiceImportCRD: 0,
+			},
+		},
+		{
+			name: "newer hub no longer serving the required version",
+			resources: []*metav1.APIResourceList{
+				resourceList(v1beta1GroupVersion, "internalserviceexports", "internalserviceimports"),
+			},
+			wantSkew: map[string]float64{
+				internalServiceExportCRD: 1,
+				internalServiceImportCRD: 1,
+			},
+			wantErrSubstrings: []string{
+				"CRD internalserviceexports.networking.fleet.azure.com requires version v1alpha1, but only versions [v1beta1] are served",
+				"CRD internalserviceimports.networking.fleet.azure.com requires version v1alpha1, but only versions [v1beta1] are served",
+			},
+		},
+		{
+			name: "missing CRD",
+			resources: []*metav1.APIResourceList{
+				resourceList(fleetnetv1alpha1.GroupVersion.String(), "internalserviceexports"),
+			},
+			wantSkew: map[string]float64{
+				internalServiceExportCRD: 0,
+				internalServiceImportCRD: 1,
+			},
+			wantErrSubstrings: []string{
+				"CRD internalserviceimports.networking.fleet.azure.com is not served in version v1alpha1",
+			},
+		},
+		{
+			name: "missing API group",
+			wantSkew: map[string]float64{
+				internalServiceExportCRD: 1,
+				internalServiceImportCRD: 1,
+			},
+			wantErrSubstrings: []string{
+				"CRD internalserviceexports.networking.fleet.azure.com requires version v1alpha1, but no version of the API group is served",
+			},
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			checker := &Checker{
+				Discovery: &fakediscovery.FakeDiscovery{Fake: &clienttesting.Fake{Resources: tc.resources}},
+				Required:  required,
+			}
+			if err := checker.Check(); err != nil {
+				t.Fatalf("Check() got error %v, want no error", err)
+			}
+
+			for crd, want := range tc.wantSkew {
+				if got := testutil.ToFloat64(apiVersionSkew.WithLabelValues(crd)); got != want {
+					t.Errorf("apiVersionSkew{crd=%q} = %v, want %v", crd, got, want)
+				}
+			}
+
+			gotErr := checker.ReadyzCheck(nil)
+			if len(tc.wantErrSubstrings) == 0 {
+				if gotErr != nil {
+					t.Errorf("ReadyzCheck() got error %v, want no error", gotErr)
+				}
+				return
+			}
+			if gotErr == nil {
+				t.Fatalf("ReadyzCheck() got no error, want error")
+			}
+			for _, want := range tc.wantErrSubstrings {
+				if !strings.Contains(gotErr.Error(), want) {
+					t.Errorf("ReadyzCheck() = %v, want error containing %q", gotErr, want)
+				}
+			}
+		})
+	}
+}
+
+func TestCheck_DiscoveryFailure(t *testing.T) {
+	fake := &clienttesting.Fake{
+		Resources: []*metav1.APIResourceList{
+			resourceList(v1beta1GroupVersion, "internalserviceexports"),
+		},
+	}
+	checker := &Checker{
+		Discovery: &fakediscovery.FakeDiscovery{Fake: fake},
+		Required:  []schema.GroupVersionResource{fleetnetv1alpha1.GroupVersion.WithResource("internalserviceexports")},
+	}
+	if err := checker.Check(); err != nil {
+		t.Fatalf("Check() got error %v, want no error", err)
+	}
+	if err := checker.ReadyzCheck(nil); err == nil {
+		t.Fatalf("ReadyzCheck() got no error, want error")
+	}
+
+	// A failed discovery leaves the state unchanged.
+	fake.AddReactor("get", "group", func(clienttesting.Action) (bool, runtime.Object, error) {
+		return true, nil, errors.New("connection refused")
+	})
+	fake.Resources = []*metav1.APIResourceList{
+		resourceList(fleetnetv1alpha1.GroupVersion.String(), "internalserviceexports"),
+	}
+	if err := checker.Check(); err == nil {
+		t.Fatalf("Check() got no error, want error")
+	}
+	if err := checker.ReadyzCheck(nil); err == nil {
+		t.Fatalf("ReadyzCheck() got no error, want error")
+	}
+}
@@ -0,0 +1,111 @@
+/*
+Copyright (c) Microsoft Corporation.
+Licensed under the MIT license.
+*/
+
+package apicompat
+
+import (
+	"errors"
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/discovery"
+
+	clusterv1beta1 "go.goms.io/fleet/apis/cluster/v1beta1"
+	fleetv1alpha1 "go.goms.io/fleet/apis/v1alpha1"
+)
+
+// The InternalMemberCluster resources through which the member agent joins and leaves the fleet, one per version of
+// the fleet APIs; the hub cluster serves the v1beta1 one only once the v1beta1 fleet APIs have been rolled out, and
+// may stop serving the v1alpha1 one afterwards.
+var (
+	V1Alpha1InternalMemberClusters = fleetv1alpha1.GroupVersion.WithResource("internalmemberclusters")
+	V1Beta1InternalMemberClusters  = clusterv1beta1.GroupVersion.WithResource("internalmemberclusters")
+)
+
+// IMCVersions tells which versions of the InternalMemberCluster API are enabled, served or reconciled.
+type IMCVersions struct {
+	V1Alpha1 bool
+	V1Beta1  bool
+}
+
+// String returns the versions in the form of "v1alpha1=true,v1beta1=false", as logged in the compatibility matrix.
+func (v IMCVersions) String() string {
+	return fmt.Sprintf("v1alpha1=%t,v1beta1=%t", v.V1Alpha1, v.V1Beta1)
+}
+
+// DiscoverIMCVersions returns the versions of the InternalMemberCluster API served by the hub cluster.
+func DiscoverIMCVersions(d discovery.DiscoveryInterface) (IMCVersions, error) {
+	c := &Checker{Discovery: d}
+	servedVersions, err := c.servedVersions()
+	if err != nil {
+		return IMCVersions{}, err
+	}
+	isServed := func(gvr schema.GroupVersionResource) (bool, error) {
+		served, err := c.servedResources(gvr.GroupVersion(), servedVersions[gvr.Group])
+		if err != nil {
+			return false, err
+		}
+		return served[gvr.Resource], nil
+	}
+	var served IMCVersions
+	if served.V1Alpha1, err = isServed(V1Alpha1InternalMemberClusters); err != nil {
+		return IMCVersions{}, err
+	}
+	if served.V1Beta1, err = isServed(V1Beta1InternalMemberClusters); err != nil {
+		return IMCVersions{}, err
+	}
+	return served, nil
+}
+
+// ResolveIMCVersions returns the versions of the InternalMemberCluster API the member agent reconciles, given the
+// enabled versions and those served by the hub cluster.
+//
+// The enabled versions which the hub cluster does not serve are skipped. When none of them is served, e.g. the
+// v1beta1 APIs are enabled on the member clusters before the hub cluster has been upgraded, or the other way
+// around, the member agent falls back to the served version, so that it keeps joining the fleet whatever the order
+// in which the v1beta1 APIs are rolled out. With requireV1Beta1, for fleets which have completed the migration, the
+// v1beta1 version must be both enabled and served instead.
+func ResolveIMCVersions(enabled, served IMCVersions, requireV1Beta1 bool) (IMCVersions, error) {
+	if requireV1Beta1 {
+		if !enabled.V1Beta1 {
+			return IMCVersions{}, errors.New("the v1beta1 APIs are required but not enabled")
+		}
+		if !served.V1Beta1 {
+			return IMCVersions{}, fmt.Errorf("the v1beta1 APIs are required but the hub cluster does not serve %s", V1Beta1InternalMemberClusters)
+		}
+	}
+	if !enabled.V1Alpha1 && !enabled.V1Beta1 {
+		return IMCVersions{}, nil
+	}
+
+	resolved := IMCVersions{
+		V1Alpha1: enabled.V1Alpha1 && served.V1Alpha1,
+		V1Beta1:  enabled.V1Beta1 && served.V1Beta1,
+	}
+	if resolved.V1Alpha1 || resolved.V1Beta1 {
+		return resolved, nil
+	}
+	switch {
+	case served.V1Beta1:
+		resolved.V1Beta1 = true
+	case served.V1Alpha1:
+		resolved.V1Alpha1 = true
+	default:
+		return IMCVersions{}, fmt.Errorf("the hub cluster serves neither %s nor %s", V1Alpha1InternalMemberClusters, V1Beta1InternalMemberClusters)
+	}
+	return resolved, nil
+}
+
+// AgentReportsV1Beta1 returns true if the member agent of the given type reports its status on the v1beta1 APIs of
+// a member cluster, i.e. it runs with the v1beta1 APIs enabled; the hub agent uses it to tell the API versions the
+// member agents have been rolled out with.
+func AgentReportsV1Beta1(mc *clusterv1beta1.MemberCluster, agentType clusterv1beta1.AgentType) bool {
+	for _, agentStatus := range mc.Status.AgentStatus {
+		if agentStatus.Type == agentType {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,148 @@
+/*
+Copyright (c) Microsoft Corporation.
+Licensed under the MIT license.
+*/
+
+package apicompat
+
+import (
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	fakediscovery "k8s.io/client-go/discovery/fake"
+	clienttesting "k8s.io/client-go/testing"
+
+	clusterv1beta1 "go.goms.io/fleet/apis/cluster/v1beta1"
+)
+
+var (
+	hubServingV1Alpha1 = []*metav1.APIResourceList{
+		resourceList(V1Alpha1InternalMemberClusters.GroupVersion().String(), "internalmemberclusters", "memberclusters"),
+	}
+	hubServingV1Beta1 = []*metav1.APIResourceList{
+		resourceList(V1Beta1InternalMemberClusters.GroupVersion().String(), "internalmemberclusters", "memberclusters"),
+	}
+	hubServingBoth = append(append([]*metav1.APIResourceList{}, hubServingV1Alpha1...), hubServingV1Beta1...)
+)
+
+// TestResolveIMCVersions_EnablementOrderings tests the InternalMemberCluster API versions reconciled by the member
+// agent through each stage of the migration to the v1beta1 APIs, whichever of the hub and the member clusters has the
+// v1beta1 APIs enabled first; the served versions are discovered from a fake hub cluster.
+func TestResolveIMCVersions_EnablementOrderings(t *testing.T) {
+	alphaOnly := IMCVersions{V1Alpha1: true}
+	betaOnly := IMCVersions{V1Beta1: true}
+	both := IMCVersions{V1Alpha1: true, V1Beta1: true}
+	tests := []struct {
+		name           string
+		hubResources   []*metav1.APIResourceList
+		enabled        IMCVersions
+		requireV1Beta1 bool
+		want           IMCVersions
+		wantErr        bool
+	}{
+		{
+			name:         "before the migration",
+			hubResources: hubServingV1Alpha1,
+			enabled:      alphaOnly,
+			want:         alphaOnly,
+		},
+		{
+			name:         "hub first: the hub serves both versions while the members have the v1alpha1 APIs enabled",
+			hubResources: hubServingBoth,
+			enabled:      alphaOnly,
+			want:         alphaOnly,
+		},
+		{
+			name:         "members first: the hub serves the v1alpha1 version only while the members have the v1beta1 APIs enabled",
+			hubResources: hubServingV1Alpha1,
+			enabled:      betaOnly,
+			want:         alphaOnly,
+		},
+		{
+			name:         "members first with both versions enabled",
+			hubResources: hubServingV1Alpha1,
+			enabled:      both,
+			want:         alphaOnly,
+		},
+		{
+			name:         "both versions enabled and served",
+			hubResources: hubServingBoth,
+			enabled:      both,
+			want:         both,
+		},
+		{
+			name:         "the hub no longer serves the v1alpha1 version while the members have the v1alpha1 APIs enabled",
+			hubResources: hubServingV1Beta1,
+			enabled:      alphaOnly,
+			want:         betaOnly,
+		},
+		{
+			name:           "migration completed",
+			hubResources:   hubServingV1Beta1,
+			enabled:        betaOnly,
+			requireV1Beta1: true,
+			want:           betaOnly,
+		},
+		{
+			name:           "migration completed while the hub still serves both versions",
+			hubResources:   hubServingBoth,
+			enabled:        both,
+			requireV1Beta1: true,
+			want:           both,
+		},
+		{
+			name:           "v1beta1 required but not served by the hub",
+			hubResources:   hubServingV1Alpha1,
+			enabled:        betaOnly,
+			requireV1Beta1: true,
+			wantErr:        true,
+		},
+		{
+			name:           "v1beta1 required but not enabled",
+			hubResources:   hubServingBoth,
+			enabled:        alphaOnly,
+			requireV1Beta1: true,
+			wantErr:        true,
+		},
+		{
+			name:         "no version served by the hub",
+			hubResources: []*metav1.APIResourceList{resourceList(v1beta1GroupVersion, "internalserviceexports")},
+			enabled:      betaOnly,
+			wantErr:      true,
+		},
+		{
+			name:         "no version enabled",
+			hubResources: hubServingBoth,
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			served, err := DiscoverIMCVersions(&fakediscovery.FakeDiscovery{Fake: &clienttesting.Fake{Resources: tc.hubResources}})
+			if err != nil {
+				t.Fatalf("DiscoverIMCVersions() = %v, want no error", err)
+			}
+			got, err := ResolveIMCVersions(tc.enabled, served, tc.requireV1Beta1)
+			if gotErr := err != nil; gotErr != tc.wantErr {
+				t.Fatalf("ResolveIMCVersions(%s, %s, %t) error = %v, want error %t", tc.enabled, served, tc.requireV1Beta1, err, tc.wantErr)
+			}
+			if got != tc.want {
+				t.Errorf("ResolveIMCVersions(%s, %s, %t) = %s, want %s", tc.enabled, served, tc.requireV1Beta1, got, tc.want)
+			}
+		})
+	}
+}
+
+func TestAgentReportsV1Beta1(t *testing.T) {
+	mc := &clusterv1beta1.MemberCluster{
+		Status: clusterv1beta1.MemberClusterStatus{
+			AgentStatus: []clusterv1beta1.AgentStatus{{Type: clusterv1beta1.MemberAgent}},
+		},
+	}
+	if AgentReportsV1Beta1(mc, clusterv1beta1.ServiceExportImportAgent) {
+		t.Errorf("AgentReportsV1Beta1() = true, want false")
+	}
+	mc.Status.AgentStatus = append(mc.Status.AgentStatus, clusterv1beta1.AgentStatus{Type: clusterv1beta1.ServiceExportImportAgent})
+	if !AgentReportsV1Beta1(mc, clusterv1beta1.ServiceExportImportAgent) {
+		t.Errorf("AgentReportsV1Beta1() = false, want true")
+	}
+}
@@ -8,6 +8,7 @@ package azureerrors
 
 import (
 	"errors"
+	"fmt"
 	"net/http"
 
 	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
@@ -32,8 +33,56 @@ func IsConflict(err error) bool {
 	return errors.As(err, &responseError) && responseError.StatusCode == http.StatusConflict
 }
 
+// dnsNameNotAvailableErrorCode is the error code returned with a http 409 error by the azure server when the relative
+// DNS name of an Azure Traffic Manager profile is taken by another profile.
+const dnsNameNotAvailableErrorCode = "Conflict"
+
+// IsDNSNameNotAvailable determines if the error is returned by the azure server because the relative DNS name of an
+// Azure Traffic Manager profile is not available; the other conflicts, e.g. with an operation in progress, are not.
+func IsDNSNameNotAvailable(err error) bool {
+	var responseError *azcore.ResponseError
+	return errors.As(err, &responseError) && responseError.StatusCode == http.StatusConflict &&
+		responseError.ErrorCode == dnsNameNotAvailableErrorCode
+}
+
 // IsThrottled determines if the error is a http 429 error returned by the azure server.
 func IsThrottled(err error) bool {
 	var responseError *azcore.ResponseError
 	return errors.As(err, &responseError) && responseError.StatusCode == http.StatusTooManyRequests
 }
+
+// IsPreconditionFailed determines if the error is a http 412 error returned by the azure server, e.g. when the ETag
+// given in the If-Match header no longer matches the resource.
+func IsPreconditionFailed(err error) bool {
+	var responseError *azcore.ResponseError
+	return errors.As(err, &responseError) && responseError.StatusCode == http.StatusPreconditionFailed
+}
+
+// IsForbidden determines if the error is a http 403 error returned by the azure server, e.g. when the identity lacks
+// the permission to perform the operation.
+func IsForbidden(err error) bool {
+	var responseError *azcore.ResponseError
+	return errors.As(err, &responseError) && responseError.StatusCode == http.StatusForbidden
+}
+
+// dnsRecordInUseErrorCode is the error code returned with a http 400 error by the azure server when the DNS label of a
+// public IP address is taken by another public IP address in the same region.
+const dnsRecordInUseErrorCode = "DnsRecordInUse"
+
+// IsDNSRecordInUse determines if the error is returned by the azure server because the DNS label of a public IP address
+// is not available.
+func IsDNSRecordInUse(err error) bool {
+	var responseError *azcore.ResponseError
+	return errors.As(err, &responseError) && responseError.StatusCode == http.StatusBadRequest &&
+		responseError.ErrorCode == dnsRecordInUseErrorCode
+}
+
+// Summary returns a short summary of the error for the events and the status messages: the error code and the http
+// status code when the error is returned by the azure server, or the error message otherwise.
+func Summary(err error) string {
+	var responseError *azcore.ResponseError
+	if errors.As(err, &responseError) {
+		return fmt.Sprintf("%s (status code %d)", responseError.ErrorCode, responseError.StatusCode)
+	}
+	return err.Error()
+}
@@ -7,6 +7,7 @@ package azureerrors
 
 import (
 	"errors"
+	"fmt"
 	"testing"
 
 	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
@@ -123,6 +124,53 @@ func TestIsConflict(t *testing.T) {
 	}
 }
 
+func TestIsDNSNameNotAvailable(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{
+			name: "nil error",
+			err:  nil,
+			want: false,
+		},
+		{
+			name: "not azure error",
+			err:  errors.New("not azure error"),
+			want: false,
+		},
+		{
+			name: "bad request error",
+			err:  &azcore.ResponseError{StatusCode: 400, ErrorCode: "Conflict"},
+			want: false,
+		},
+		{
+			name: "conflict error with another operation",
+			err:  &azcore.ResponseError{StatusCode: 409, ErrorCode: "AnotherOperationInProgress"},
+			want: false,
+		},
+		{
+			name: "dns name not available error",
+			err:  &azcore.ResponseError{StatusCode: 409, ErrorCode: "Conflict"},
+			want: true,
+		},
+		{
+			name: "wrapped dns name not available error",
+			err:  fmt.Errorf("failed to create profile: %w", &azcore.ResponseError{StatusCode: 409, ErrorCode: "Conflict"}),
+			want: true,
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got := IsDNSNameNotAvailable(tc.err)
+			if got != tc.want {
+				t.Errorf("IsDNSNameNotAvailable() = %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
+
 func TestIsThrottled(t *testing.T) {
 	tests := []struct {
 		name string
@@ -159,3 +207,145 @@ func TestIsThrottled(t *testing.T) {
 		})
 	}
 }
+
+func TestIsPreconditionFailed(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{
+			name: "nil error",
+			err:  nil,
+			want: false,
+		},
+		{
+			name: "not azure error",
+			err:  errors.New("not azure error"),
+			want: false,
+		},
+		{
+			name: "conflict error",
+			err:  &azcore.ResponseError{StatusCode: 409},
+			want: false,
+		},
+		{
+			name: "precondition failed error",
+			err:  &azcore.ResponseError{StatusCode: 412},
+			want: true,
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got := IsPreconditionFailed(tc.err)
+			if got != tc.want {
+				t.Errorf("IsPreconditionFailed() = %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestIsForbidden(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{
+			name: "nil error",
+			err:  nil,
+			want: false,
+		},
+		{
+			name: "not azure error",
+			err:  errors.New("not azure error"),
+			want: false,
+		},
+		{
+			name: "unauthorized error",
+			err:  &azcore.ResponseError{StatusCode: 401},
+			want: false,
+		},
+		{
+			name: "forbidden error",
+			err:  &azcore.ResponseError{StatusCode: 403, ErrorCode: "AuthorizationFailed"},
+			want: true,
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got := IsForbidden(tc.err)
+			if got != tc.want {
+				t.Errorf("IsForbidden() = %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestIsDNSRecordInUse(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{
+			name: "nil error",
+			err:  nil,
+			want: false,
+		},
+		{
+			name: "not azure error",
+			err:  errors.New("not azure error"),
+			want: false,
+		},
+		{
+			name: "other bad request error",
+			err:  &azcore.ResponseError{StatusCode: 400, ErrorCode: "InvalidDomainNameLabel"},
+			want: false,
+		},
+		{
+			name: "dns record in use error",
+			err:  &azcore.ResponseError{StatusCode: 400, ErrorCode: "DnsRecordInUse"},
+			want: true,
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got := IsDNSRecordInUse(tc.err)
+			if got != tc.want {
+				t.Errorf("IsDNSRecordInUse() = %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestSummary(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want string
+	}{
+		{
+			name: "not azure error",
+			err:  errors.New("not azure error"),
+			want: "not azure error",
+		},
+		{
+			name: "conflict error",
+			err:  &azcore.ResponseError{StatusCode: 409, ErrorCode: "Conflict"},
+			want: "Conflict (status code 409)",
+		},
+		{
+			name: "wrapped throttled error",
+			err:  fmt.Errorf("failed to create the profile: %w", &azcore.ResponseError{StatusCode: 429, ErrorCode: "TooManyRequests"}),
+			want: "TooManyRequests (status code 429)",
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := Summary(tc.err); got != tc.want {
+				t.Errorf("Summary() = %q, want %q", got, tc.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,80 @@
+/*
+Copyright (c) Microsoft Corporation.
+Licensed under the MIT license.
+*/
+
+// Package cachetransform provides transform functions which trim objects before they are committed to the
+// controller-runtime cache, so that the memory footprint of the informers stays small at scale.
+package cachetransform
+
+import (
+	"reflect"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	toolscache "k8s.io/client-go/tools/cache"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// StripManagedFieldsAndReadOnlyAnnotations returns a transform func which strips the managed fields of every object
+// before it is committed to the cache; for objects whose type matches one of the given read-only objects, it
+// also strips the kubectl last-applied-configuration annotation, which often holds a full copy of the object.
+//
+// The annotation must only be stripped from objects that are never written back by the controllers sharing the
+// cache, as an update built from a cached copy would otherwise remove the annotation from the API server.
+// Managed fields are safe to strip for all objects, as the API server keeps the existing managed fields when
+// an update request does not set them.
+func StripManagedFieldsAndReadOnlyAnnotations(readOnlyObjs ...client.Object) toolscache.TransformFunc {
+	readOnlyTypes := make(map[reflect.Type]bool, len(readOnlyObjs))
+	for _, obj := range readOnlyObjs {
+		readOnlyTypes[reflect.TypeOf(obj)] = true
+	}
+	return func(in interface{}) (interface{}, error) {
+		obj, err := meta.Accessor(in)
+		if err != nil {
+			// Tombstones and other non-object values are passed through as they are.
+			return in, nil
+		}
+		// Nil check the managed fields to avoid hitting https://github.com/kubernetes/kubernetes/issues/124337.
+		if obj.GetManagedFields() != nil {
+			obj.SetManagedFields(nil)
+		}
+		if !readOnlyTypes[reflect.TypeOf(in)] {
+			return in, nil
+		}
+		if annotations := obj.GetAnnotations(); annotations != nil {
+			if _, ok := annotations[corev1.LastAppliedConfigAnnotation]; ok {
+				delete(annotations, corev1.LastAppliedConfigAnnotation)
+				obj.SetAnnotations(annotations)
+			}
+		}
+		return in, nil
+	}
+}
+
+// TrimPodToHostNetworkFields is a transform func which keeps only the fields of a pod telling whether it runs in the
+// host network, i.e., its host network spec and its pod and host IPs, so that all the pods of a cluster can be cached
+// with little memory. The pods must be read-only for the controllers sharing the cache.
+func TrimPodToHostNetworkFields(in interface{}) (interface{}, error) {
+	pod, ok := in.(*corev1.Pod)
+	if !ok {
+		return in, nil
+	}
+	return &corev1.Pod{
+		TypeMeta: pod.TypeMeta,
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace:       pod.Namespace,
+			Name:            pod.Name,
+			UID:             pod.UID,
+			ResourceVersion: pod.ResourceVersion,
+		},
+		Spec: corev1.PodSpec{
+			HostNetwork: pod.Spec.HostNetwork,
+		},
+		Status: corev1.PodStatus{
+			PodIP:  pod.Status.PodIP,
+			HostIP: pod.Status.HostIP,
+		},
+	}, nil
+}
@@ -0,0 +1,238 @@
+/*
+Copyright (c) Microsoft Corporation.
+Licensed under the MIT license.
+*/
+
+package cachetransform
+
+import (
+	"fmt"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	corev1 "k8s.io/api/core/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	toolscache "k8s.io/client-go/tools/cache"
+
+	fleetnetv1alpha1 "go.goms.io/fleet-networking/api/v1alpha1"
+)
+
+const (
+	testNamespace  = "work"
+	testName       = "app"
+	testAnnotation = "networking.fleet.azure.com/test"
+)
+
+func TestStripManagedFieldsAndReadOnlyAnnotations(t *testing.T) {
+	managedFields := []metav1.ManagedFieldsEntry{
+		{
+			Manager:   "kubectl",
+			Operation: metav1.ManagedFieldsOperationApply,
+		},
+	}
+	tests := []struct {
+		name string
+		in   interface{}
+		want interface{}
+	}{
+		{
+			name: "writable object",
+			in: &fleetnetv1alpha1.ServiceImport{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace:     testNamespace,
+					Name:          testName,
+					ManagedFields: managedFields,
+					Annotations: map[string]string{
+						corev1.LastAppliedConfigAnnotation: "{}",
+						testAnnotation:                     "true",
+					},
+				},
+			},
+			want: &fleetnetv1alpha1.ServiceImport{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: testNamespace,
+					Name:      testName,
+					Annotations: map[string]string{
+						corev1.LastAppliedConfigAnnotation: "{}",
+						testAnnotation:                     "true",
+					},
+				},
+			},
+		},
+		{
+			name: "read-only object",
+			in: &corev1.Namespace{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:          testNamespace,
+					ManagedFields: managedFields,
+					Annotations: map[string]string{
+						corev1.LastAppliedConfigAnnotation: "{}",
+						testAnnotation:                     "true",
+					},
+				},
+			},
+			want: &corev1.Namespace{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: testNamespace,
+					Annotations: map[string]string{
+						testAnnotation: "true",
+					},
+				},
+			},
+		},
+		{
+			name: "read-only object without annotations",
+			in: &corev1.Namespace{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:          testNamespace,
+					ManagedFields: managedFields,
+				},
+			},
+			want: &corev1.Namespace{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: testNamespace,
+				},
+			},
+		},
+		{
+			name: "tombstone",
+			in: toolscache.DeletedFinalStateUnknown{
+				Key: testName,
+			},
+			want: toolscache.DeletedFinalStateUnknown{
+				Key: testName,
+			},
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			transform := StripManagedFieldsAndReadOnlyAnnotations(&corev1.Namespace{})
+			got, err := transform(tc.in)
+			if err != nil {
+				t.Fatalf("transform() got error %v, want no error", err)
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("transform() mismatch (-want, +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+// scaleFixtureSize is the number of EndpointSliceExports held by the scale fixture, which matches a hub cluster
+// serving a few hundred member clusters.
+const scaleFixtureSize = 10000
+
+// endpointSliceExportForScale returns an EndpointSliceExport as stored by the API server, with the managed fields
+// of the member agent which writes it.
+func endpointSliceExportForScale(i int) *fleetnetv1alpha1.EndpointSliceExport {
+	endpoints := make([]fleetnetv1alpha1.Endpoint, 0, 20)
+	fields := make([]string, 0, 20)
+	for j := 0; j < 20; j++ {
+		addr := fmt.Sprintf("10.%d.%d.%d", i/65536, (i/256)%256, j)
+		endpoints = append(endpoints, fleetnetv1alpha1.Endpoint{Addresses: []string{addr}})
+		fields = append(fields, fmt.Sprintf(`{"addresses":["%s"]}:{".":{},"f:addresses":{}}`, addr))
+	}
+	return &fleetnetv1alpha1.EndpointSliceExport{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: fmt.Sprintf("fleet-member-cluster-%d", i%200),
+			Name:      fmt.Sprintf("app-%d", i),
+			ManagedFields: []metav1.ManagedFieldsEntry{
+				{
+					Manager:    "member-net-controller-manager",
+					Operation:  metav1.ManagedFieldsOperationApply,
+					APIVersion: fleetnetv1alpha1.GroupVersion.String(),
+					FieldsType: "FieldsV1",
+					FieldsV1: &metav1.FieldsV1{
+						Raw: []byte(`{"f:spec":{"f:endpoints":{` + strings.Join(fields, ",") + `}}}`),
+					},
+				},
+			},
+		},
+		Spec: fleetnetv1alpha1.EndpointSliceExportSpec{
+			AddressType: discoveryv1.AddressTypeIPv4,
+			Endpoints:   endpoints,
+		},
+	}
+}
+
+// BenchmarkStripManagedFields_ScaleFixture reports the heap retained by the scale fixture of EndpointSliceExports, as
+// held by the cache, with and without the transform; run it with -benchtime=1x to compare the retained-bytes metric.
+func BenchmarkStripManagedFields_ScaleFixture(b *testing.B) {
+	for _, strip := range []bool{false, true} {
+		b.Run(fmt.Sprintf("strip=%v", strip), func(b *testing.B) {
+			transform := StripManagedFieldsAndReadOnlyAnnotations()
+			for n := 0; n < b.N; n++ {
+				var before, after runtime.MemStats
+				runtime.GC()
+				runtime.ReadMemStats(&before)
+				cached := make([]interface{}, 0, scaleFixtureSize)
+				for i := 0; i < scaleFixtureSize; i++ {
+					var obj interface{} = endpointSliceExportForScale(i)
+					if strip {
+						var err error
+						if obj, err = transform(obj); err != nil {
+							b.Fatalf("transform() got error %v, want no error", err)
+						}
+					}
+					cached = append(cached, obj)
+				}
+				runtime.GC()
+				runtime.ReadMemStats(&after)
+				b.ReportMetric(float64(int64(after.HeapAlloc)-int64(before.HeapAlloc)), "retained-bytes")
+				runtime.KeepAlive(cached)
+			}
+		})
+	}
+}
+
+func TestTrimPodToHostNetworkFields(t *testing.T) {
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace:       testNamespace,
+			Name:            testName,
+			UID:             "1",
+			ResourceVersion: "2",
+			Labels:          map[string]string{"app": testName},
+		},
+		Spec: corev1.PodSpec{
+			HostNetwork: true,
+			Containers:  []corev1.Container{{Name: testName, Image: "app:latest"}},
+		},
+		Status: corev1.PodStatus{
+			Phase:  corev1.PodRunning,
+			PodIP:  "10.1.0.1",
+			HostIP: "10.1.0.1",
+		},
+	}
+	want := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace:       testNamespace,
+			Name:            testName,
+			UID:             "1",
+			ResourceVersion: "2",
+		},
+		Spec: corev1.PodSpec{
+			HostNetwork: true,
+		},
+		Status: corev1.PodStatus{
+			PodIP:  "10.1.0.1",
+			HostIP: "10.1.0.1",
+		},
+	}
+	got, err := TrimPodToHostNetworkFields(pod)
+	if err != nil {
+		t.Fatalf("TrimPodToHostNetworkFields() = %v, want no error", err)
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("TrimPodToHostNetworkFields() mismatch (-want, +got):\n%s", diff)
+	}
+
+	// Tombstones are passed through as they are.
+	tombstone := toolscache.DeletedFinalStateUnknown{Key: "work/app", Obj: pod}
+	if got, err := TrimPodToHostNetworkFields(tombstone); err != nil || !cmp.Equal(got, tombstone) {
+		t.Errorf("TrimPodToHostNetworkFields(tombstone) = %v, %v, want the tombstone and no error", got, err)
+	}
+}
@@ -0,0 +1,137 @@
+/*
+Copyright (c) Microsoft Corporation.
+Licensed under the MIT license.
+*/
+
+// Package clockskew features a tracker which estimates the skew of the local clock against the clock of the hub API
+// server from the Date headers of the hub responses, as the timestamps the member agent writes (e.g., the
+// exportedSince of the exported objects) are compared with the clocks of the hub and the other member clusters.
+package clockskew
+
+import (
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"k8s.io/klog/v2"
+	ctrlmetrics "sigs.k8s.io/controller-runtime/pkg/metrics"
+
+	"go.goms.io/fleet-networking/pkg/common/metrics"
+)
+
+const (
+	// dateResolution is the resolution of the Date header (RFC 7231), which truncates the server time to the second.
+	dateResolution = time.Second
+
+	// maxRoundTrip is the longest round trip of a response whose Date header is used; the server time of a slower
+	// response is too uncertain to estimate the skew from.
+	maxRoundTrip = 2 * time.Second
+)
+
+var (
+	// memberClockSkew is the estimated skew of the local clock against the hub API server.
+	memberClockSkew = prometheus.NewGauge(
+		prometheus.GaugeOpts{
+			Namespace: metrics.MetricsNamespace,
+			Subsystem: metrics.MetricsSubsystem,
+			Name:      "member_clock_skew_seconds",
+			Help:      "The estimated skew of the local clock against the hub API server clock, positive when the local clock is ahead",
+		},
+	)
+)
+
+func init() {
+	// Register memberClockSkew (fleet_networking_member_clock_skew_seconds) metric
+	// with the controller runtime global metrics registry.
+	ctrlmetrics.Registry.MustRegister(memberClockSkew)
+}
+
+// Estimate returns the skew of the local clock against the server, positive when the local clock is ahead, from a
+// response sent and received at the given local times and dated by the server, together with the uncertainty of the
+// estimate. The server is assumed to date the response halfway through the round trip, at any time within the second
+// of its Date header.
+func Estimate(sent, received, serverDate time.Time) (skew, uncertainty time.Duration) {
+	roundTrip := received.Sub(sent)
+	if roundTrip < 0 {
+		roundTrip = 0
+	}
+	localMidpoint := sent.Add(roundTrip / 2)
+	serverMidpoint := serverDate.Add(dateResolution / 2)
+	return localMidpoint.Sub(serverMidpoint), roundTrip/2 + dateResolution/2
+}
+
+// Tracker tracks the skew of the local clock against the hub API server; it logs a warning when the skew exceeds
+// the threshold beyond the uncertainty of the estimate, and reports the skew with the
+// fleet_networking_member_clock_skew_seconds metric.
+type Tracker struct {
+	// Threshold is the skew above which a warning is logged; 0 disables the warning.
+	Threshold time.Duration
+
+	mu       sync.Mutex
+	skew     time.Duration
+	exceeded bool
+}
+
+// Skew returns the last estimated skew of the local clock, positive when the local clock is ahead.
+func (t *Tracker) Skew() time.Duration {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.skew
+}
+
+// Observe estimates the skew from a response sent and received at the given local times and dated by the server.
+func (t *Tracker) Observe(sent, received, serverDate time.Time) {
+	if received.Sub(sent) > maxRoundTrip {
+		return
+	}
+	skew, uncertainty := Estimate(sent, received, serverDate)
+	memberClockSkew.Set(skew.Seconds())
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.skew = skew
+	exceeded := t.Threshold > 0 && abs(skew)-uncertainty > t.Threshold
+	switch {
+	case exceeded && !t.exceeded:
+		klog.InfoS("The local clock is skewed against the hub cluster; the export timestamps and latency metrics are off by the skew, check the time sync of the member cluster",
+			"skew", skew, "uncertainty", uncertainty, "threshold", t.Threshold)
+	case !exceeded && t.exceeded:
+		klog.InfoS("The local clock is back in sync with the hub cluster", "skew", skew, "uncertainty", uncertainty, "threshold", t.Threshold)
+	}
+	t.exceeded = exceeded
+}
+
+// WrapTransport returns a transport which observes the Date headers of the responses of the given transport; it can
+// be set as the WrapTransport of a rest.Config, so that every hub request, including the watches, is observed.
+func (t *Tracker) WrapTransport(rt http.RoundTripper) http.RoundTripper {
+	return &roundTripper{tracker: t, delegate: rt}
+}
+
+type roundTripper struct {
+	tracker  *Tracker
+	delegate http.RoundTripper
+}
+
+var _ http.RoundTripper = &roundTripper{}
+
+// RoundTrip implements the http.RoundTripper interface.
+func (r *roundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	sent := time.Now()
+	resp, err := r.delegate.RoundTrip(req)
+	if err != nil {
+		return resp, err
+	}
+	received := time.Now()
+	if date, parseErr := http.ParseTime(resp.Header.Get("Date")); parseErr == nil {
+		r.tracker.Observe(sent, received, date)
+	}
+	return resp, nil
+}
+
+func abs(d time.Duration) time.Duration {
+	if d < 0 {
+		return -d
+	}
+	return d
+}
@@ -0,0 +1,154 @@
+/*
+Copyright (c) Microsoft Corporation.
+Licensed under the MIT license.
+*/
+
+package clockskew
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+// TestEstimate tests the Estimate function.
+func TestEstimate(t *testing.T) {
+	sent := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	testCases := []struct {
+		name            string
+		received        time.Time
+		serverDate      time.Time
+		wantSkew        time.Duration
+		wantUncertainty time.Duration
+	}{
+		{
+			name:            "in sync",
+			received:        sent.Add(time.Second),
+			serverDate:      sent,
+			wantSkew:        0,
+			wantUncertainty: time.Second,
+		},
+		{
+			name:            "local clock ahead",
+			received:        sent.Add(200 * time.Millisecond),
+			serverDate:      sent.Add(-10 * time.Minute),
+			wantSkew:        10*time.Minute - 400*time.Millisecond,
+			wantUncertainty: 600 * time.Millisecond,
+		},
+		{
+			name:            "local clock behind",
+			received:        sent.Add(200 * time.Millisecond),
+			serverDate:      sent.Add(10 * time.Minute),
+			wantSkew:        -10*time.Minute - 400*time.Millisecond,
+			wantUncertainty: 600 * time.Millisecond,
+		},
+		{
+			name:            "local clock stepped back during the round trip",
+			received:        sent.Add(-time.Second),
+			serverDate:      sent,
+			wantSkew:        -500 * time.Millisecond,
+			wantUncertainty: 500 * time.Millisecond,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			gotSkew, gotUncertainty := Estimate(sent, tc.received, tc.serverDate)
+			if gotSkew != tc.wantSkew || gotUncertainty != tc.wantUncertainty {
+				t.Errorf("Estimate() = (%v, %v), want (%v, %v)", gotSkew, gotUncertainty, tc.wantSkew, tc.wantUncertainty)
+			}
+		})
+	}
+}
+
+// TestTrackerObserve tests the Tracker.Observe method.
+func TestTrackerObserve(t *testing.T) {
+	sent := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	testCases := []struct {
+		name         string
+		threshold    time.Duration
+		received     time.Time
+		serverDate   time.Time
+		wantSkew     time.Duration
+		wantExceeded bool
+	}{
+		{
+			name:       "in sync",
+			threshold:  30 * time.Second,
+			received:   sent.Add(100 * time.Millisecond),
+			serverDate: sent,
+			wantSkew:   -450 * time.Millisecond,
+		},
+		{
+			name:         "skew above the threshold",
+			threshold:    30 * time.Second,
+			received:     sent.Add(100 * time.Millisecond),
+			serverDate:   sent.Add(-10 * time.Minute),
+			wantSkew:     10*time.Minute - 450*time.Millisecond,
+			wantExceeded: true,
+		},
+		{
+			name:       "skew within the uncertainty above the threshold",
+			threshold:  30 * time.Second,
+			received:   sent.Add(100 * time.Millisecond),
+			serverDate: sent.Add(-31 * time.Second),
+			wantSkew:   31*time.Second - 450*time.Millisecond,
+		},
+		{
+			name:       "skew above the disabled threshold",
+			received:   sent.Add(100 * time.Millisecond),
+			serverDate: sent.Add(-10 * time.Minute),
+			wantSkew:   10*time.Minute - 450*time.Millisecond,
+		},
+		{
+			name:       "slow response",
+			threshold:  30 * time.Second,
+			received:   sent.Add(5 * time.Second),
+			serverDate: sent.Add(-10 * time.Minute),
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			memberClockSkew.Set(0)
+			tracker := &Tracker{Threshold: tc.threshold}
+			tracker.Observe(sent, tc.received, tc.serverDate)
+			if got := tracker.Skew(); got != tc.wantSkew {
+				t.Errorf("Skew() = %v, want %v", got, tc.wantSkew)
+			}
+			if tracker.exceeded != tc.wantExceeded {
+				t.Errorf("exceeded = %t, want %t", tracker.exceeded, tc.wantExceeded)
+			}
+			if got := testutil.ToFloat64(memberClockSkew); got != tc.wantSkew.Seconds() {
+				t.Errorf("fleet_networking_member_clock_skew_seconds = %v, want %v", got, tc.wantSkew.Seconds())
+			}
+		})
+	}
+}
+
+// TestWrapTransport tests that the transport returned by Tracker.WrapTransport observes the Date headers.
+func TestWrapTransport(t *testing.T) {
+	serverClockAhead := time.Hour
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Date", time.Now().Add(serverClockAhead).UTC().Format(http.TimeFormat))
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	tracker := &Tracker{Threshold: time.Minute}
+	client := &http.Client{Transport: tracker.WrapTransport(http.DefaultTransport)}
+	resp, err := client.Get(server.URL)
+	if err != nil {
+		t.Fatalf("Get() = %v, want no error", err)
+	}
+	resp.Body.Close()
+
+	// The Date header truncates the server time to the second.
+	if got := tracker.Skew(); got > -serverClockAhead+2*time.Second || got < -serverClockAhead-2*time.Second {
+		t.Errorf("Skew() = %v, want about %v", got, -serverClockAhead)
+	}
+	if !tracker.exceeded {
+		t.Errorf("exceeded = false, want true")
+	}
+}
@@ -0,0 +1,66 @@
+/*
+Copyright (c) Microsoft Corporation.
+Licensed under the MIT license.
+*/
+
+package cloudconfig
+
+import (
+	"fmt"
+	"strings"
+
+	"go.goms.io/fleet/pkg/utils/cloudconfig/azure"
+)
+
+// NetworkProperties are the network properties of a member cluster, which help the importing clusters decide whether
+// the endpoints exported from the cluster are reachable.
+type NetworkProperties struct {
+	// Region is the Azure region of the cluster.
+	Region string
+	// VNetID is the resource ID of the virtual network of the cluster.
+	VNetID string
+}
+
+// NewNetworkProperties returns the network properties described by the Azure cloud config.
+func NewNetworkProperties(cloudConfig *azure.CloudConfig) NetworkProperties {
+	props := NetworkProperties{Region: cloudConfig.Location}
+	if cloudConfig.VnetName != "" {
+		vnetRG := cloudConfig.VnetResourceGroup
+		if vnetRG == "" {
+			vnetRG = cloudConfig.ResourceGroup
+		}
+		props.VNetID = fmt.Sprintf("/subscriptions/%s/resourceGroups/%s/providers/Microsoft.Network/virtualNetworks/%s",
+			cloudConfig.SubscriptionID, vnetRG, cloudConfig.VnetName)
+	}
+	return props
+}
+
+// LoadNetworkProperties loads the network properties from the Azure cloud config file.
+func LoadNetworkProperties(filePath string) (NetworkProperties, error) {
+	cloudConfig, err := azure.NewCloudConfigFromFile(filePath)
+	if err != nil {
+		return NetworkProperties{}, fmt.Errorf("failed to load the cloud config %s: %w", filePath, err)
+	}
+	return NewNetworkProperties(cloudConfig), nil
+}
+
+// IsEmpty returns true if none of the properties is known.
+func (p NetworkProperties) IsEmpty() bool {
+	return p.Region == "" && p.VNetID == ""
+}
+
+// CanReach returns true if the endpoints of a cluster with the remote properties are reachable from a cluster with
+// the properties p.
+//
+// The clusters in the same virtual network can always reach each other, while the ones in different virtual networks
+// are assumed not to be peered; when the virtual network of either cluster is unknown, the clusters are compared by
+// region instead. Clusters whose properties are unknown are considered reachable.
+func (p NetworkProperties) CanReach(remote NetworkProperties) bool {
+	if p.VNetID != "" && remote.VNetID != "" {
+		return strings.EqualFold(p.VNetID, remote.VNetID)
+	}
+	if p.Region != "" && remote.Region != "" {
+		return strings.EqualFold(p.Region, remote.Region)
+	}
+	return true
+}
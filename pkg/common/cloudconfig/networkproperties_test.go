@@ -0,0 +1,147 @@
+/*
+Copyright (c) Microsoft Corporation.
+Licensed under the MIT license.
+*/
+
+package cloudconfig
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"go.goms.io/fleet/pkg/utils/cloudconfig/azure"
+)
+
+func TestNewNetworkProperties(t *testing.T) {
+	tests := []struct {
+		name        string
+		cloudConfig *azure.CloudConfig
+		want        NetworkProperties
+	}{
+		{
+			name: "no vnet",
+			cloudConfig: &azure.CloudConfig{
+				Location:       "westus",
+				SubscriptionID: "sub",
+				ResourceGroup:  "rg",
+			},
+			want: NetworkProperties{Region: "westus"},
+		},
+		{
+			name: "vnet in the cluster resource group",
+			cloudConfig: &azure.CloudConfig{
+				Location:       "westus",
+				SubscriptionID: "sub",
+				ResourceGroup:  "rg",
+				VnetName:       "vnet",
+			},
+			want: NetworkProperties{
+				Region: "westus",
+				VNetID: "/subscriptions/sub/resourceGroups/rg/providers/Microsoft.Network/virtualNetworks/vnet",
+			},
+		},
+		{
+			name: "vnet in another resource group",
+			cloudConfig: &azure.CloudConfig{
+				Location:          "eastus",
+				SubscriptionID:    "sub",
+				ResourceGroup:     "rg",
+				VnetName:          "vnet",
+				VnetResourceGroup: "vnet-rg",
+			},
+			want: NetworkProperties{
+				Region: "eastus",
+				VNetID: "/subscriptions/sub/resourceGroups/vnet-rg/providers/Microsoft.Network/virtualNetworks/vnet",
+			},
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got := NewNetworkProperties(tc.cloudConfig)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("NewNetworkProperties() mismatch (-want, +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestLoadNetworkProperties(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "azure.json")
+	if err := os.WriteFile(path, []byte(fmt.Sprintf(cloudConfigFormat, "sub", "rg", "tenant")), 0600); err != nil {
+		t.Fatalf("failed to write the cloud config: %v", err)
+	}
+	got, err := LoadNetworkProperties(path)
+	if err != nil {
+		t.Fatalf("LoadNetworkProperties() got error %v, want no error", err)
+	}
+	if want := (NetworkProperties{Region: "westus"}); got != want {
+		t.Errorf("LoadNetworkProperties() = %+v, want %+v", got, want)
+	}
+
+	if _, err := LoadNetworkProperties(filepath.Join(t.TempDir(), "missing.json")); err == nil {
+		t.Errorf("LoadNetworkProperties() of a missing file got no error, want error")
+	}
+}
+
+func TestCanReach(t *testing.T) {
+	vnetA := "/subscriptions/sub/resourceGroups/rg/providers/Microsoft.Network/virtualNetworks/a"
+	vnetB := "/subscriptions/sub/resourceGroups/rg/providers/Microsoft.Network/virtualNetworks/b"
+	tests := []struct {
+		name   string
+		local  NetworkProperties
+		remote NetworkProperties
+		want   bool
+	}{
+		{
+			name: "unknown properties",
+			want: true,
+		},
+		{
+			name:   "same vnet",
+			local:  NetworkProperties{Region: "westus", VNetID: vnetA},
+			remote: NetworkProperties{Region: "westus", VNetID: vnetA},
+			want:   true,
+		},
+		{
+			name:   "same vnet in different case",
+			local:  NetworkProperties{VNetID: vnetA},
+			remote: NetworkProperties{VNetID: "/SUBSCRIPTIONS/sub/resourceGroups/RG/providers/Microsoft.Network/virtualNetworks/a"},
+			want:   true,
+		},
+		{
+			name:   "different vnets in the same region",
+			local:  NetworkProperties{Region: "westus", VNetID: vnetA},
+			remote: NetworkProperties{Region: "westus", VNetID: vnetB},
+			want:   false,
+		},
+		{
+			name:   "same region with an unknown vnet",
+			local:  NetworkProperties{Region: "westus", VNetID: vnetA},
+			remote: NetworkProperties{Region: "WestUS"},
+			want:   true,
+		},
+		{
+			name:   "different regions",
+			local:  NetworkProperties{Region: "westus"},
+			remote: NetworkProperties{Region: "eastus"},
+			want:   false,
+		},
+		{
+			name:   "unknown remote region",
+			local:  NetworkProperties{Region: "westus"},
+			remote: NetworkProperties{},
+			want:   true,
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := tc.local.CanReach(tc.remote); got != tc.want {
+				t.Errorf("CanReach() = %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
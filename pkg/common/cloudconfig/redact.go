@@ -0,0 +1,103 @@
+/*
+Copyright (c) Microsoft Corporation.
+Licensed under the MIT license.
+*/
+
+package cloudconfig
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+
+	"go.goms.io/fleet/pkg/utils/cloudconfig/azure"
+)
+
+const (
+	// redactedValue replaces the secrets set in the cloud config when it is logged.
+	redactedValue = "REDACTED"
+
+	// fingerprintLength is the number of the hex characters of the fingerprint of a cloud config.
+	fingerprintLength = 12
+)
+
+// RedactedCloudConfig is a cloud config which can be logged: its secrets, i.e. the AAD client secret and the password
+// of the AAD client certificate, are masked, while the fields identifying where the Azure resources are, e.g. the
+// tenant, the subscription and the resource group, are kept as is. It comes with a fingerprint of the whole cloud
+// config, so that the support can tell whether two agents run with the same cloud config without seeing it.
+type RedactedCloudConfig struct {
+	Cloud                                 string `json:"cloud,omitempty"`
+	TenantID                              string `json:"tenantID,omitempty"`
+	SubscriptionID                        string `json:"subscriptionID,omitempty"`
+	ResourceGroup                         string `json:"resourceGroup,omitempty"`
+	Location                              string `json:"location,omitempty"`
+	VnetName                              string `json:"vnetName,omitempty"`
+	VnetResourceGroup                     string `json:"vnetResourceGroup,omitempty"`
+	AADClientID                           string `json:"aadClientID,omitempty"`
+	AADClientSecret                       string `json:"aadClientSecret,omitempty"`
+	AADClientCertPath                     string `json:"aadClientCertPath,omitempty"`
+	AADClientCertPassword                 string `json:"aadClientCertPassword,omitempty"`
+	UseManagedIdentityExtension           bool   `json:"useManagedIdentityExtension,omitempty"`
+	UserAssignedIdentityID                string `json:"userAssignedIdentityID,omitempty"`
+	UseFederatedWorkloadIdentityExtension bool   `json:"useFederatedWorkloadIdentityExtension,omitempty"`
+	Fingerprint                           string `json:"fingerprint"`
+}
+
+// Redact returns the cloud config to log in place of the given one.
+func Redact(cloudConfig *azure.CloudConfig) RedactedCloudConfig {
+	if cloudConfig == nil {
+		return RedactedCloudConfig{}
+	}
+	return RedactedCloudConfig{
+		Cloud:                                 cloudConfig.Cloud,
+		TenantID:                              cloudConfig.TenantID,
+		SubscriptionID:                        cloudConfig.SubscriptionID,
+		ResourceGroup:                         cloudConfig.ResourceGroup,
+		Location:                              cloudConfig.Location,
+		VnetName:                              cloudConfig.VnetName,
+		VnetResourceGroup:                     cloudConfig.VnetResourceGroup,
+		AADClientID:                           cloudConfig.AADClientID,
+		AADClientSecret:                       redactSecret(cloudConfig.AADClientSecret),
+		AADClientCertPath:                     cloudConfig.AADClientCertPath,
+		AADClientCertPassword:                 redactSecret(cloudConfig.AADClientCertPassword),
+		UseManagedIdentityExtension:           cloudConfig.UseManagedIdentityExtension,
+		UserAssignedIdentityID:                cloudConfig.UserAssignedIdentityID,
+		UseFederatedWorkloadIdentityExtension: cloudConfig.UseFederatedWorkloadIdentityExtension,
+		Fingerprint:                           Fingerprint(cloudConfig),
+	}
+}
+
+// String implements the fmt.Stringer interface.
+func (c RedactedCloudConfig) String() string {
+	return fmt.Sprintf("{cloud=%q tenantID=%q subscriptionID=%q resourceGroup=%q location=%q vnetName=%q vnetResourceGroup=%q aadClientID=%q aadClientSecret=%q aadClientCertPath=%q aadClientCertPassword=%q useManagedIdentityExtension=%t userAssignedIdentityID=%q useFederatedWorkloadIdentityExtension=%t fingerprint=%q}",
+		c.Cloud, c.TenantID, c.SubscriptionID, c.ResourceGroup, c.Location, c.VnetName, c.VnetResourceGroup, c.AADClientID, c.AADClientSecret,
+		c.AADClientCertPath, c.AADClientCertPassword, c.UseManagedIdentityExtension, c.UserAssignedIdentityID, c.UseFederatedWorkloadIdentityExtension, c.Fingerprint)
+}
+
+// MarshalLog implements the logr.Marshaler interface, so that the structured loggers log the fields rather than the
+// string.
+func (c RedactedCloudConfig) MarshalLog() any {
+	// The alias drops the methods, so that the loggers do not call MarshalLog again.
+	type redactedCloudConfig RedactedCloudConfig
+	return redactedCloudConfig(c)
+}
+
+// Fingerprint returns a short non-reversible fingerprint of the whole cloud config, including its secrets; the same
+// cloud config always has the same fingerprint, whatever the formatting of the file it is loaded from.
+func Fingerprint(cloudConfig *azure.CloudConfig) string {
+	data, err := json.Marshal(cloudConfig)
+	if err != nil {
+		// It never happens as the cloud config is decoded from JSON.
+		return ""
+	}
+	hash := sha256.Sum256(data)
+	return hex.EncodeToString(hash[:])[:fingerprintLength]
+}
+
+func redactSecret(secret string) string {
+	if secret == "" {
+		return ""
+	}
+	return redactedValue
+}
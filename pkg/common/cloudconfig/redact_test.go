@@ -0,0 +1,92 @@
+/*
+Copyright (c) Microsoft Corporation.
+Licensed under the MIT license.
+*/
+
+package cloudconfig
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+
+	"sigs.k8s.io/cloud-provider-azure/pkg/azclient"
+
+	"go.goms.io/fleet/pkg/utils/cloudconfig/azure"
+)
+
+const (
+	clientSecret       = "client-secret-value"
+	clientCertPassword = "client-cert-password-value"
+)
+
+func cloudConfigWithSecrets() *azure.CloudConfig {
+	return &azure.CloudConfig{
+		ARMClientConfig: azclient.ARMClientConfig{
+			Cloud:    "AzurePublicCloud",
+			TenantID: "tenant",
+		},
+		AzureAuthConfig: azclient.AzureAuthConfig{
+			AADClientID:           "client",
+			AADClientSecret:       clientSecret,
+			AADClientCertPath:     "/etc/cert.pfx",
+			AADClientCertPassword: clientCertPassword,
+		},
+		Location:       "westus",
+		SubscriptionID: "sub",
+		ResourceGroup:  "rg",
+	}
+}
+
+// TestRedact tests that the secrets of the cloud config never appear in its formatted or marshaled forms, while the
+// identifying fields do.
+func TestRedact(t *testing.T) {
+	redacted := Redact(cloudConfigWithSecrets())
+	marshaled, err := json.Marshal(redacted.MarshalLog())
+	if err != nil {
+		t.Fatalf("json.Marshal(MarshalLog()) = %v, want no error", err)
+	}
+	outputs := map[string]string{
+		"String()":      redacted.String(),
+		"%v":            fmt.Sprintf("%v", redacted),
+		"%+v":           fmt.Sprintf("%+v", redacted),
+		"%#v":           fmt.Sprintf("%#v", redacted),
+		"MarshalLog()":  string(marshaled),
+		"%+v of struct": fmt.Sprintf("%+v", redacted.MarshalLog()),
+	}
+	for format, output := range outputs {
+		for _, secret := range []string{clientSecret, clientCertPassword} {
+			if strings.Contains(output, secret) {
+				t.Errorf("%s = %s, want no secret %q", format, output, secret)
+			}
+		}
+		for _, field := range []string{"tenant", "sub", "rg", redactedValue, redacted.Fingerprint} {
+			if !strings.Contains(output, field) {
+				t.Errorf("%s = %s, want %q", format, output, field)
+			}
+		}
+	}
+
+	if got := Redact(&azure.CloudConfig{}); got.AADClientSecret != "" || got.AADClientCertPassword != "" {
+		t.Errorf("Redact() of a cloud config without secrets = %v, want no redacted secrets", got)
+	}
+}
+
+func TestFingerprint(t *testing.T) {
+	want := Fingerprint(cloudConfigWithSecrets())
+	if len(want) != fingerprintLength {
+		t.Errorf("Fingerprint() = %q, want %d characters", want, fingerprintLength)
+	}
+	if got := Fingerprint(cloudConfigWithSecrets()); got != want {
+		t.Errorf("Fingerprint() of the same cloud config = %q, want %q", got, want)
+	}
+	rotated := cloudConfigWithSecrets()
+	rotated.AADClientSecret = "rotated-client-secret"
+	if got := Fingerprint(rotated); got == want {
+		t.Errorf("Fingerprint() of the cloud config with a rotated secret = %q, want a different fingerprint", got)
+	}
+	if strings.Contains(clientSecret, want) {
+		t.Errorf("Fingerprint() = %q, want no part of the secret", want)
+	}
+}
@@ -0,0 +1,175 @@
+/*
+Copyright (c) Microsoft Corporation.
+Licensed under the MIT license.
+*/
+
+// Package cloudconfig features a reloader which watches the Azure cloud config file and rebuilds the Azure clients
+// when the file changes, e.g., after the Azure resources are moved to another subscription or resource group, without
+// restarting the controllers.
+package cloudconfig
+
+import (
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"os"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/trafficmanager/armtrafficmanager"
+	"github.com/prometheus/client_golang/prometheus"
+	"k8s.io/klog/v2"
+	ctrlmetrics "sigs.k8s.io/controller-runtime/pkg/metrics"
+
+	"go.goms.io/fleet/pkg/utils/cloudconfig/azure"
+
+	"go.goms.io/fleet-networking/pkg/common/metrics"
+)
+
+const (
+	reloadResultSucceeded = "succeeded"
+	reloadResultFailed    = "failed"
+)
+
+var (
+	// cloudConfigReloadsTotal counts the loads of the cloud config file which rebuilt the Azure clients, by result.
+	cloudConfigReloadsTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: metrics.MetricsNamespace,
+			Subsystem: metrics.MetricsSubsystem,
+			Name:      "cloud_config_reloads_total",
+			Help:      "Total number of the loads of the Azure cloud config file after it has changed, by result",
+		},
+		[]string{"result"},
+	)
+)
+
+func init() {
+	// Register cloudConfigReloadsTotal (fleet_networking_cloud_config_reloads_total) metric
+	// with the controller runtime global metrics registry.
+	ctrlmetrics.Registry.MustRegister(cloudConfigReloadsTotal)
+}
+
+// TrafficManagerClients are the Azure Traffic Manager clients used by the hub controllers.
+type TrafficManagerClients struct {
+	ProfilesClient    *armtrafficmanager.ProfilesClient
+	EndpointsClient   *armtrafficmanager.EndpointsClient
+	ResourceGroupName string // default resource group name to create azure traffic manager resources
+}
+
+// ClientsBuilder builds the Azure clients from the cloud config.
+type ClientsBuilder[T any] func(cloudConfig *azure.CloudConfig) (T, error)
+
+// Reloader holds the Azure clients built from the cloud config file. It checks the content of the file periodically
+// and, when it has changed, validates the new cloud config and swaps the clients atomically. A cloud config which
+// fails the validation, or from which the clients cannot be built, is rejected and the previous clients are kept.
+//
+// The callers are expected to get the clients once at the start of each reconciliation, so that the in-flight
+// reconciliations complete with the clients they have started with.
+//
+// Reloader implements the controller-runtime Runnable interface and runs whether the manager is the leader or not.
+type Reloader[T any] struct {
+	filePath  string
+	userAgent string
+	interval  time.Duration
+	build     ClientsBuilder[T]
+
+	// mu serializes the loads of the file.
+	mu      sync.Mutex
+	hash    [sha256.Size]byte
+	current atomic.Pointer[T]
+}
+
+// NewReloader returns a reloader of the cloud config file; the file is not loaded until the clients are first
+// requested. The content of the file is checked every interval; 0 disables the reload.
+func NewReloader[T any](filePath, userAgent string, interval time.Duration, build ClientsBuilder[T]) *Reloader[T] {
+	return &Reloader[T]{
+		filePath:  filePath,
+		userAgent: userAgent,
+		interval:  interval,
+		build:     build,
+	}
+}
+
+// Current returns the clients built from the latest valid cloud config, loading the cloud config file on the first
+// call; a failed first load is retried on the next call.
+func (r *Reloader[T]) Current() (T, error) {
+	if clients := r.current.Load(); clients != nil {
+		return *clients, nil
+	}
+	if _, err := r.Reload(); err != nil {
+		var zero T
+		return zero, err
+	}
+	return *r.current.Load(), nil
+}
+
+// Reload loads the cloud config file and rebuilds the clients if the content of the file has changed since the last
+// successful load. It returns true if the clients have been swapped.
+func (r *Reloader[T]) Reload() (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	contents, err := os.ReadFile(r.filePath)
+	if err != nil {
+		cloudConfigReloadsTotal.WithLabelValues(reloadResultFailed).Inc()
+		return false, fmt.Errorf("failed to read cloud config file %s: %w", r.filePath, err)
+	}
+	hash := sha256.Sum256(contents)
+	if r.current.Load() != nil && hash == r.hash {
+		return false, nil
+	}
+
+	cloudConfig, err := azure.NewCloudConfigFromFile(r.filePath)
+	if err != nil {
+		cloudConfigReloadsTotal.WithLabelValues(reloadResultFailed).Inc()
+		return false, fmt.Errorf("rejected cloud config file %s: %w", r.filePath, err)
+	}
+	cloudConfig.SetUserAgent(r.userAgent)
+	clients, err := r.build(cloudConfig)
+	if err != nil {
+		cloudConfigReloadsTotal.WithLabelValues(reloadResultFailed).Inc()
+		return false, fmt.Errorf("failed to create Azure clients from cloud config file %s: %w", r.filePath, err)
+	}
+
+	isFirstLoad := r.current.Load() == nil
+	r.current.Store(&clients)
+	r.hash = hash
+	if isFirstLoad {
+		klog.V(1).InfoS("Cloud config loaded", "cloudConfigFile", r.filePath, "cloudConfig", Redact(cloudConfig))
+		return true, nil
+	}
+	cloudConfigReloadsTotal.WithLabelValues(reloadResultSucceeded).Inc()
+	klog.InfoS("Cloud config changed, the Azure clients have been rebuilt", "cloudConfigFile", r.filePath, "cloudConfig", Redact(cloudConfig))
+	return true, nil
+}
+
+// NeedLeaderElection implements the LeaderElectionRunnable interface; the clients may be used by the controllers
+// running on every replica.
+func (r *Reloader[T]) NeedLeaderElection() bool {
+	return false
+}
+
+// Start reloads the cloud config file periodically and blocks until the context is done.
+func (r *Reloader[T]) Start(ctx context.Context) error {
+	if r.interval <= 0 {
+		klog.V(2).InfoS("The cloud config reload is disabled", "cloudConfigFile", r.filePath)
+		<-ctx.Done()
+		return nil
+	}
+	klog.V(2).InfoS("Starting the cloud config reloader", "cloudConfigFile", r.filePath, "interval", r.interval)
+	ticker := time.NewTicker(r.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			klog.V(2).InfoS("Stopping the cloud config reloader", "cloudConfigFile", r.filePath)
+			return nil
+		case <-ticker.C:
+			if _, err := r.Reload(); err != nil {
+				klog.ErrorS(err, "Failed to reload the cloud config, keeping the current Azure clients", "cloudConfigFile", r.filePath)
+			}
+		}
+	}
+}
@@ -0,0 +1,206 @@
+/*
+Copyright (c) Microsoft Corporation.
+Licensed under the MIT license.
+*/
+
+package cloudconfig
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/arm"
+	azcorefake "github.com/Azure/azure-sdk-for-go/sdk/azcore/fake"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/trafficmanager/armtrafficmanager"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+
+	"go.goms.io/fleet/pkg/utils/cloudconfig/azure"
+)
+
+const (
+	cloudConfigFormat = `{
+  "cloud": "AzurePublicCloud",
+  "tenantId": "%[3]s",
+  "subscriptionId": "%[1]s",
+  "useManagedIdentityExtension": true,
+  "location": "westus",
+  "resourceGroup": "%[2]s"
+}`
+)
+
+// recordingTransport records the paths of the requests and responds with an empty profile.
+type recordingTransport struct {
+	mu    sync.Mutex
+	paths []string
+}
+
+func (t *recordingTransport) Do(req *http.Request) (*http.Response, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.paths = append(t.paths, req.URL.Path)
+	return &http.Response{
+		StatusCode: http.StatusOK,
+		Header:     http.Header{"Content-Type": []string{"application/json"}},
+		Body:       io.NopCloser(strings.NewReader(`{}`)),
+		Request:    req,
+	}, nil
+}
+
+func (t *recordingTransport) lastPath() string {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if len(t.paths) == 0 {
+		return ""
+	}
+	return t.paths[len(t.paths)-1]
+}
+
+func newFakeTrafficManagerClientsBuilder(transport *recordingTransport) ClientsBuilder[TrafficManagerClients] {
+	return func(cloudConfig *azure.CloudConfig) (TrafficManagerClients, error) {
+		options := &arm.ClientOptions{ClientOptions: azcore.ClientOptions{Transport: transport}}
+		profilesClient, err := armtrafficmanager.NewProfilesClient(cloudConfig.SubscriptionID, &azcorefake.TokenCredential{}, options)
+		if err != nil {
+			return TrafficManagerClients{}, err
+		}
+		endpointsClient, err := armtrafficmanager.NewEndpointsClient(cloudConfig.SubscriptionID, &azcorefake.TokenCredential{}, options)
+		if err != nil {
+			return TrafficManagerClients{}, err
+		}
+		return TrafficManagerClients{
+			ProfilesClient:    profilesClient,
+			EndpointsClient:   endpointsClient,
+			ResourceGroupName: cloudConfig.ResourceGroup,
+		}, nil
+	}
+}
+
+func writeCloudConfig(t *testing.T, filePath, subscriptionID, resourceGroup, tenantID string) {
+	t.Helper()
+	if err := os.WriteFile(filePath, []byte(fmt.Sprintf(cloudConfigFormat, subscriptionID, resourceGroup, tenantID)), 0600); err != nil {
+		t.Fatalf("failed to write the cloud config file: %v", err)
+	}
+}
+
+// getProfile sends a request with the profiles client and returns the path of the request.
+func getProfile(t *testing.T, transport *recordingTransport, clients TrafficManagerClients) string {
+	t.Helper()
+	if _, err := clients.ProfilesClient.Get(context.Background(), clients.ResourceGroupName, "profile", nil); err != nil {
+		t.Fatalf("ProfilesClient.Get() got error %v, want no error", err)
+	}
+	return transport.lastPath()
+}
+
+func TestReloader(t *testing.T) {
+	filePath := filepath.Join(t.TempDir(), "azure.json")
+	transport := &recordingTransport{}
+	reloader := NewReloader(filePath, "test", time.Minute, newFakeTrafficManagerClientsBuilder(transport))
+
+	// The first load fails as the file does not exist yet, and is retried on the next call.
+	if _, err := reloader.Current(); err == nil {
+		t.Fatalf("Current() got no error, want error")
+	}
+
+	writeCloudConfig(t, filePath, "sub-1", "rg-1", "tenant-1")
+	oldClients, err := reloader.Current()
+	if err != nil {
+		t.Fatalf("Current() got error %v, want no error", err)
+	}
+	wantOldPath := "/subscriptions/sub-1/resourceGroups/rg-1/providers/Microsoft.Network/trafficmanagerprofiles/profile"
+	if got := getProfile(t, transport, oldClients); got != wantOldPath {
+		t.Errorf("request path = %q, want %q", got, wantOldPath)
+	}
+
+	// The file is unchanged.
+	if swapped, err := reloader.Reload(); err != nil || swapped {
+		t.Errorf("Reload() of the unchanged file = %v, %v, want false, nil", swapped, err)
+	}
+
+	// The resources are moved to another subscription and resource group.
+	succeeded := testutil.ToFloat64(cloudConfigReloadsTotal.WithLabelValues(reloadResultSucceeded))
+	writeCloudConfig(t, filePath, "sub-2", "rg-2", "tenant-1")
+	if swapped, err := reloader.Reload(); err != nil || !swapped {
+		t.Fatalf("Reload() of the changed file = %v, %v, want true, nil", swapped, err)
+	}
+	if got := testutil.ToFloat64(cloudConfigReloadsTotal.WithLabelValues(reloadResultSucceeded)); got != succeeded+1 {
+		t.Errorf("cloudConfigReloadsTotal{result=%q} = %v, want %v", reloadResultSucceeded, got, succeeded+1)
+	}
+	newClients, err := reloader.Current()
+	if err != nil {
+		t.Fatalf("Current() got error %v, want no error", err)
+	}
+	wantNewPath := "/subscriptions/sub-2/resourceGroups/rg-2/providers/Microsoft.Network/trafficmanagerprofiles/profile"
+	if got := getProfile(t, transport, newClients); got != wantNewPath {
+		t.Errorf("request path after the reload = %q, want %q", got, wantNewPath)
+	}
+	// The clients obtained before the reload, e.g., by an in-flight reconciliation, keep working on the old ones.
+	if got := getProfile(t, transport, oldClients); got != wantOldPath {
+		t.Errorf("request path of the old clients = %q, want %q", got, wantOldPath)
+	}
+
+	// An invalid cloud config is rejected and the current clients are kept.
+	failed := testutil.ToFloat64(cloudConfigReloadsTotal.WithLabelValues(reloadResultFailed))
+	writeCloudConfig(t, filePath, "", "rg-3", "tenant-1")
+	if swapped, err := reloader.Reload(); err == nil || swapped {
+		t.Errorf("Reload() of the invalid file = %v, %v, want false, error", swapped, err)
+	}
+	if got := testutil.ToFloat64(cloudConfigReloadsTotal.WithLabelValues(reloadResultFailed)); got != failed+1 {
+		t.Errorf("cloudConfigReloadsTotal{result=%q} = %v, want %v", reloadResultFailed, got, failed+1)
+	}
+	clients, err := reloader.Current()
+	if err != nil {
+		t.Fatalf("Current() got error %v, want no error", err)
+	}
+	if got := getProfile(t, transport, clients); got != wantNewPath {
+		t.Errorf("request path after the rejected reload = %q, want %q", got, wantNewPath)
+	}
+}
+
+func TestReloader_Start(t *testing.T) {
+	filePath := filepath.Join(t.TempDir(), "azure.json")
+	writeCloudConfig(t, filePath, "sub-1", "rg-1", "tenant-1")
+	transport := &recordingTransport{}
+	reloader := NewReloader(filePath, "test", 10*time.Millisecond, newFakeTrafficManagerClientsBuilder(transport))
+	if _, err := reloader.Current(); err != nil {
+		t.Fatalf("Current() got error %v, want no error", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() {
+		done <- reloader.Start(ctx)
+	}()
+	defer func() {
+		cancel()
+		if err := <-done; err != nil {
+			t.Errorf("Start() got error %v, want no error", err)
+		}
+	}()
+
+	// The resources are moved to a subscription of another tenant.
+	writeCloudConfig(t, filePath, "sub-2", "rg-1", "tenant-2")
+	wantPath := "/subscriptions/sub-2/resourceGroups/rg-1/providers/Microsoft.Network/trafficmanagerprofiles/profile"
+	deadline := time.Now().Add(10 * time.Second)
+	for {
+		clients, err := reloader.Current()
+		if err != nil {
+			t.Fatalf("Current() got error %v, want no error", err)
+		}
+		got := getProfile(t, transport, clients)
+		if got == wantPath {
+			return
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("request path = %q, want %q after the file is reloaded", got, wantPath)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
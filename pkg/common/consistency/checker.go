@@ -0,0 +1,311 @@
+/*
+Copyright (c) Microsoft Corporation.
+Licensed under the MIT license.
+*/
+
+// Package consistency features a read-only checker which periodically verifies that every EndpointSlice exported to
+// the hub cluster has been distributed to each member cluster importing its Service, and that no EndpointSlice has
+// been distributed to a member cluster which does not import the Service any more.
+package consistency
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	ctrlmetrics "sigs.k8s.io/controller-runtime/pkg/metrics"
+
+	fleetnetv1alpha1 "go.goms.io/fleet-networking/api/v1alpha1"
+	"go.goms.io/fleet-networking/pkg/common/listguard"
+	"go.goms.io/fleet-networking/pkg/common/metrics"
+	"go.goms.io/fleet-networking/pkg/common/objectmeta"
+)
+
+const (
+	// maxReportedImports caps the number of missing or orphaned EndpointSliceImports listed in the report ConfigMap.
+	maxReportedImports = 50
+
+	// Keys of the data in the report ConfigMap.
+	reportKeyCheckedAt        = "checkedAt"
+	reportKeyMissingCount     = "missingImports"
+	reportKeyOrphanedCount    = "orphanedImports"
+	reportKeyMissingExamples  = "missingImportExamples"
+	reportKeyOrphanedExamples = "orphanedImportExamples"
+)
+
+var (
+	// missingImportsTotal counts the EndpointSliceImports found missing by the consistency checker.
+	missingImportsTotal = prometheus.NewCounter(
+		prometheus.CounterOpts{
+			Namespace: metrics.MetricsNamespace,
+			Subsystem: metrics.MetricsSubsystem,
+			Name:      "consistency_missing_imports_total",
+			Help:      "The number of EndpointSliceImports expected by an importing member cluster but found missing by the consistency checker",
+		},
+	)
+	// orphanedImportsTotal counts the EndpointSliceImports found orphaned by the consistency checker.
+	orphanedImportsTotal = prometheus.NewCounter(
+		prometheus.CounterOpts{
+			Namespace: metrics.MetricsNamespace,
+			Subsystem: metrics.MetricsSubsystem,
+			Name:      "consistency_orphaned_imports_total",
+			Help:      "The number of EndpointSliceImports found by the consistency checker with no matching export or importing member cluster",
+		},
+	)
+)
+
+func init() {
+	// Register missingImportsTotal (fleet_networking_consistency_missing_imports_total) and orphanedImportsTotal
+	// (fleet_networking_consistency_orphaned_imports_total) metrics with the controller runtime global metrics registry.
+	ctrlmetrics.Registry.MustRegister(missingImportsTotal, orphanedImportsTotal)
+}
+
+// Result is the result of a single consistency check.
+type Result struct {
+	// MissingImports are the EndpointSliceImports which should have been distributed to an importing member cluster,
+	// keyed by the namespace of the member cluster and the name of the EndpointSliceExport.
+	MissingImports []types.NamespacedName
+	// OrphanedImports are the EndpointSliceImports with no matching EndpointSliceExport, or distributed to a member
+	// cluster which does not import the Service.
+	OrphanedImports []types.NamespacedName
+}
+
+// Checker periodically compares the EndpointSliceExports with the EndpointSliceImports across the fleet and reports
+// the discrepancies through the logs, the metrics and, optionally, a summary ConfigMap. The checker never modifies
+// the EndpointSliceExports or EndpointSliceImports; fixing the discrepancies is left to the controllers.
+//
+// Checker implements the controller-runtime Runnable interface and runs on the leader only.
+type Checker struct {
+	// Reader lists the objects; it should read from the API server directly (e.g., the API reader of the manager) so
+	// that the objects are listed in pages instead of all being held by the cache.
+	Reader client.Reader
+	// Writer writes the report ConfigMap.
+	Writer client.Client
+	// Interval is how often the check runs.
+	Interval time.Duration
+	// PageSize is the number of objects listed per request; the default page size is used when it is not positive.
+	PageSize int64
+	// ReportNamespace and ReportName identify the ConfigMap the summary of the last check is written to; no ConfigMap
+	// is written if ReportName is empty.
+	ReportNamespace string
+	ReportName      string
+}
+
+// NeedLeaderElection implements the LeaderElectionRunnable interface; one report per fleet is enough.
+func (c *Checker) NeedLeaderElection() bool {
+	return true
+}
+
+// Start implements the Runnable interface; it runs the check every interval until the context is cancelled.
+func (c *Checker) Start(ctx context.Context) error {
+	klog.InfoS("Starting the consistency checker", "interval", c.Interval, "report", klog.KRef(c.ReportNamespace, c.ReportName))
+	defer klog.InfoS("Stopping the consistency checker")
+
+	ticker := time.NewTicker(c.Interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			if _, err := c.CheckAndReport(ctx); err != nil {
+				klog.ErrorS(err, "Failed to check the consistency of EndpointSliceExports and EndpointSliceImports")
+			}
+		}
+	}
+}
+
+// CheckAndReport runs a single check and reports the result.
+func (c *Checker) CheckAndReport(ctx context.Context) (*Result, error) {
+	startTime := time.Now()
+	report, err := c.Check(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, name := range report.MissingImports {
+		klog.InfoS("Found missing EndpointSliceImport", "endpointSliceImport", name)
+	}
+	for _, name := range report.OrphanedImports {
+		klog.InfoS("Found orphaned EndpointSliceImport", "endpointSliceImport", name)
+	}
+	missingImportsTotal.Add(float64(len(report.MissingImports)))
+	orphanedImportsTotal.Add(float64(len(report.OrphanedImports)))
+	klog.V(2).InfoS("Checked the consistency of EndpointSliceExports and EndpointSliceImports",
+		"missingImports", len(report.MissingImports),
+		"orphanedImports", len(report.OrphanedImports),
+		"latency", time.Since(startTime).Milliseconds())
+
+	if c.ReportName == "" {
+		return report, nil
+	}
+	if err := c.writeReport(ctx, report, startTime); err != nil {
+		return report, err
+	}
+	return report, nil
+}
+
+// Check compares the EndpointSliceExports with the EndpointSliceImports across the fleet.
+//
+// An EndpointSliceImport is expected in the namespace of every member cluster which has claimed the Service of an
+// EndpointSliceExport (as annotated on the ServiceImport), with the same name as the EndpointSliceExport. Objects
+// being deleted are skipped, and so are the EndpointSliceExports whose ServiceImport has no accepted exports yet, as
+// the EndpointSliceExport controller waits for the ServiceImport in this case.
+func (c *Checker) Check(ctx context.Context) (*Result, error) {
+	// importers are the namespaces of the member clusters importing a Service, keyed by the namespaced name of its
+	// ServiceImport; pending are the ServiceImports with no accepted exports yet.
+	importers := map[string][]string{}
+	pending := map[string]bool{}
+	svcImportList := &fleetnetv1alpha1.ServiceImportList{}
+	if err := c.forEachPage(ctx, svcImportList, func() {
+		for i := range svcImportList.Items {
+			svcImport := &svcImportList.Items[i]
+			key := types.NamespacedName{Namespace: svcImport.Namespace, Name: svcImport.Name}.String()
+			if svcImport.DeletionTimestamp != nil || len(svcImport.Status.Clusters) == 0 {
+				pending[key] = true
+				continue
+			}
+			data, ok := svcImport.Annotations[objectmeta.ServiceImportAnnotationServiceInUseBy]
+			if !ok {
+				continue
+			}
+			svcInUseBy := &fleetnetv1alpha1.ServiceInUseBy{}
+			if err := json.Unmarshal([]byte(data), svcInUseBy); err != nil {
+				// The EndpointSliceExport controller cannot distribute the EndpointSlices either; treat the
+				// ServiceImport as pending so that its EndpointSliceImports are not reported.
+				klog.ErrorS(err, "Failed to unmarshal data for in-use Services from ServiceImport annotations", "serviceImport", klog.KObj(svcImport), "data", data)
+				pending[key] = true
+				continue
+			}
+			for ns := range svcInUseBy.MemberClusters {
+				importers[key] = append(importers[key], string(ns))
+			}
+		}
+	}); err != nil {
+		return nil, fmt.Errorf("failed to list ServiceImports: %w", err)
+	}
+
+	// expected are the EndpointSliceImports which should exist; skipped are the names of the EndpointSliceExports
+	// whose ServiceImport is pending.
+	expected := map[types.NamespacedName]bool{}
+	skipped := map[string]bool{}
+	endpointSliceExportList := &fleetnetv1alpha1.EndpointSliceExportList{}
+	if err := c.forEachPage(ctx, endpointSliceExportList, func() {
+		for i := range endpointSliceExportList.Items {
+			endpointSliceExport := &endpointSliceExportList.Items[i]
+			svcKey := types.NamespacedName{
+				Namespace: endpointSliceExport.Spec.OwnerServiceReference.Namespace,
+				Name:      endpointSliceExport.Spec.OwnerServiceReference.Name,
+			}.String()
+			if endpointSliceExport.DeletionTimestamp != nil || pending[svcKey] {
+				skipped[endpointSliceExport.Name] = true
+				continue
+			}
+			for _, ns := range importers[svcKey] {
+				expected[types.NamespacedName{Namespace: ns, Name: endpointSliceExport.Name}] = true
+			}
+		}
+	}); err != nil {
+		return nil, fmt.Errorf("failed to list EndpointSliceExports: %w", err)
+	}
+
+	report := &Result{}
+	endpointSliceImportList := &fleetnetv1alpha1.EndpointSliceImportList{}
+	if err := c.forEachPage(ctx, endpointSliceImportList, func() {
+		for i := range endpointSliceImportList.Items {
+			endpointSliceImport := &endpointSliceImportList.Items[i]
+			if endpointSliceImport.DeletionTimestamp != nil || skipped[endpointSliceImport.Name] {
+				continue
+			}
+			key := types.NamespacedName{Namespace: endpointSliceImport.Namespace, Name: endpointSliceImport.Name}
+			if expected[key] {
+				delete(expected, key)
+				continue
+			}
+			report.OrphanedImports = append(report.OrphanedImports, key)
+		}
+	}); err != nil {
+		return nil, fmt.Errorf("failed to list EndpointSliceImports: %w", err)
+	}
+	for key := range expected {
+		report.MissingImports = append(report.MissingImports, key)
+	}
+	sortNamespacedNames(report.MissingImports)
+	sortNamespacedNames(report.OrphanedImports)
+	return report, nil
+}
+
+// forEachPage lists the objects page by page into the list and calls fn after each page.
+func (c *Checker) forEachPage(ctx context.Context, list client.ObjectList, fn func()) error {
+	return listguard.ForEachPage(ctx, c.Reader, list, c.PageSize, func() error {
+		fn()
+		return nil
+	})
+}
+
+// writeReport creates or updates the report ConfigMap with the summary of the check.
+func (c *Checker) writeReport(ctx context.Context, report *Result, checkedAt time.Time) error {
+	data := map[string]string{
+		reportKeyCheckedAt:        checkedAt.UTC().Format(time.RFC3339),
+		reportKeyMissingCount:     strconv.Itoa(len(report.MissingImports)),
+		reportKeyOrphanedCount:    strconv.Itoa(len(report.OrphanedImports)),
+		reportKeyMissingExamples:  formatExamples(report.MissingImports),
+		reportKeyOrphanedExamples: formatExamples(report.OrphanedImports),
+	}
+	reportRef := klog.KRef(c.ReportNamespace, c.ReportName)
+
+	cm := &corev1.ConfigMap{}
+	err := c.Writer.Get(ctx, types.NamespacedName{Namespace: c.ReportNamespace, Name: c.ReportName}, cm)
+	switch {
+	case apierrors.IsNotFound(err):
+		cm = &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: c.ReportNamespace,
+				Name:      c.ReportName,
+			},
+			Data: data,
+		}
+		if err := c.Writer.Create(ctx, cm); err != nil {
+			klog.ErrorS(err, "Failed to create the consistency report", "configMap", reportRef)
+			return err
+		}
+		return nil
+	case err != nil:
+		klog.ErrorS(err, "Failed to get the consistency report", "configMap", reportRef)
+		return err
+	}
+	cm.Data = data
+	if err := c.Writer.Update(ctx, cm); err != nil {
+		klog.ErrorS(err, "Failed to update the consistency report", "configMap", reportRef)
+		return err
+	}
+	return nil
+}
+
+// formatExamples returns the first few namespaced names, one per line.
+func formatExamples(names []types.NamespacedName) string {
+	res := ""
+	for i, name := range names {
+		if i == maxReportedImports {
+			res += fmt.Sprintf("+%d more\n", len(names)-maxReportedImports)
+			break
+		}
+		res += name.String() + "\n"
+	}
+	return res
+}
+
+func sortNamespacedNames(names []types.NamespacedName) {
+	sort.Slice(names, func(i, j int) bool {
+		return names[i].String() < names[j].String()
+	})
+}
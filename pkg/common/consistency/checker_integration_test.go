@@ -0,0 +1,117 @@
+/*
+Copyright (c) Microsoft Corporation.
+Licensed under the MIT license.
+*/
+
+package consistency
+
+import (
+	"fmt"
+
+	"github.com/google/go-cmp/cmp"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	fleetnetv1alpha1 "go.goms.io/fleet-networking/api/v1alpha1"
+)
+
+const (
+	reportName = "fleet-networking-consistency-report"
+	// numberOfExports is larger than the page size so that the objects are listed in multiple pages.
+	numberOfExports = 5
+	pageSize        = 2
+)
+
+var _ = Describe("Test the consistency checker", Ordered, func() {
+	var checker *Checker
+	var wantMissing, wantOrphaned []types.NamespacedName
+
+	BeforeAll(func() {
+		checker = &Checker{
+			Reader:          k8sClient,
+			Writer:          k8sClient,
+			PageSize:        pageSize,
+			ReportNamespace: fleetSystemNS,
+			ReportName:      reportName,
+		}
+
+		By("Creating the ServiceImport imported by member cluster B")
+		svcImport := serviceImport(svcName, hubNSForMemberB)
+		status := svcImport.Status
+		Expect(k8sClient.Create(ctx, svcImport)).Should(Succeed())
+		svcImport.Status = status
+		Expect(k8sClient.Status().Update(ctx, svcImport)).Should(Succeed())
+
+		By("Creating the EndpointSliceExports with an EndpointSliceImport for every other one")
+		for i := 0; i < numberOfExports; i++ {
+			name := fmt.Sprintf("%s-%d", endpointSliceExportName, i)
+			Expect(k8sClient.Create(ctx, endpointSliceExport(name, svcName))).Should(Succeed())
+			if i%2 == 0 {
+				Expect(k8sClient.Create(ctx, endpointSliceImport(hubNSForMemberB, name, svcName))).Should(Succeed())
+			} else {
+				wantMissing = append(wantMissing, types.NamespacedName{Namespace: hubNSForMemberB, Name: name})
+			}
+		}
+
+		By("Creating the orphaned EndpointSliceImports")
+		orphans := []*fleetnetv1alpha1.EndpointSliceImport{
+			// Member cluster C does not import the Service.
+			endpointSliceImport(hubNSForMemberC, fmt.Sprintf("%s-%d", endpointSliceExportName, 0), svcName),
+			// The EndpointSliceExport does not exist.
+			endpointSliceImport(hubNSForMemberB, altEndpointSliceExportName, altSvcName),
+		}
+		for _, orphan := range orphans {
+			Expect(k8sClient.Create(ctx, orphan)).Should(Succeed())
+		}
+		wantOrphaned = []types.NamespacedName{
+			{Namespace: hubNSForMemberB, Name: altEndpointSliceExportName},
+			{Namespace: hubNSForMemberC, Name: fmt.Sprintf("%s-%d", endpointSliceExportName, 0)},
+		}
+	})
+
+	It("Should detect the missing and orphaned EndpointSliceImports across pages", func() {
+		report, err := checker.CheckAndReport(ctx)
+		Expect(err).NotTo(HaveOccurred())
+		want := &Result{MissingImports: wantMissing, OrphanedImports: wantOrphaned}
+		Expect(cmp.Diff(report, want)).Should(BeEmpty(), "report mismatch (-got, +want)")
+	})
+
+	It("Should write the report ConfigMap", func() {
+		cm := &corev1.ConfigMap{}
+		Expect(k8sClient.Get(ctx, types.NamespacedName{Namespace: fleetSystemNS, Name: reportName}, cm)).Should(Succeed())
+		Expect(cm.Data).Should(HaveKeyWithValue(reportKeyMissingCount, fmt.Sprint(len(wantMissing))))
+		Expect(cm.Data).Should(HaveKeyWithValue(reportKeyOrphanedCount, fmt.Sprint(len(wantOrphaned))))
+		Expect(cm.Data).Should(HaveKeyWithValue(reportKeyMissingExamples, formatExamples(wantMissing)))
+		Expect(cm.Data).Should(HaveKeyWithValue(reportKeyOrphanedExamples, formatExamples(wantOrphaned)))
+	})
+
+	It("Should not modify the EndpointSliceExports or EndpointSliceImports", func() {
+		endpointSliceExportList := &fleetnetv1alpha1.EndpointSliceExportList{}
+		Expect(k8sClient.List(ctx, endpointSliceExportList)).Should(Succeed())
+		Expect(endpointSliceExportList.Items).Should(HaveLen(numberOfExports))
+
+		endpointSliceImportList := &fleetnetv1alpha1.EndpointSliceImportList{}
+		Expect(k8sClient.List(ctx, endpointSliceImportList)).Should(Succeed())
+		Expect(endpointSliceImportList.Items).Should(HaveLen(numberOfExports - len(wantMissing) + len(wantOrphaned)))
+	})
+
+	It("Should report no discrepancy once the EndpointSliceImports are fixed", func() {
+		for _, name := range wantMissing {
+			Expect(k8sClient.Create(ctx, endpointSliceImport(name.Namespace, name.Name, svcName))).Should(Succeed())
+		}
+		for _, name := range wantOrphaned {
+			Expect(k8sClient.Delete(ctx, endpointSliceImport(name.Namespace, name.Name, svcName))).Should(Succeed())
+		}
+
+		report, err := checker.CheckAndReport(ctx)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(cmp.Diff(report, &Result{})).Should(BeEmpty(), "report mismatch (-got, +want)")
+
+		cm := &corev1.ConfigMap{}
+		Expect(k8sClient.Get(ctx, types.NamespacedName{Namespace: fleetSystemNS, Name: reportName}, cm)).Should(Succeed())
+		Expect(cm.Data).Should(HaveKeyWithValue(reportKeyMissingCount, "0"))
+		Expect(cm.Data).Should(HaveKeyWithValue(reportKeyOrphanedCount, "0"))
+	})
+})
@@ -0,0 +1,321 @@
+/*
+Copyright (c) Microsoft Corporation.
+Licensed under the MIT license.
+*/
+
+package consistency
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	corev1 "k8s.io/api/core/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	fleetnetv1alpha1 "go.goms.io/fleet-networking/api/v1alpha1"
+	"go.goms.io/fleet-networking/pkg/common/objectmeta"
+)
+
+const (
+	hubNSForMemberA = "bravelion"
+	hubNSForMemberB = "highflyingcat"
+	hubNSForMemberC = "singingbutterfly"
+	memberUserNS    = "work"
+	svcName         = "app"
+	altSvcName      = "app2"
+
+	endpointSliceExportName    = "work-app-endpointslice-1a2bc"
+	altEndpointSliceExportName = "work-app2-endpointslice-3d4ef"
+)
+
+func TestMain(m *testing.M) {
+	// Add custom APIs to the runtime scheme.
+	if err := fleetnetv1alpha1.AddToScheme(scheme.Scheme); err != nil {
+		log.Fatalf("failed to add custom APIs to the runtime scheme: %v", err)
+	}
+
+	os.Exit(m.Run())
+}
+
+// serviceImport returns a ServiceImport exported by member cluster A and imported by the given member clusters.
+func serviceImport(name string, importers ...string) *fleetnetv1alpha1.ServiceImport {
+	svcImport := &fleetnetv1alpha1.ServiceImport{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: memberUserNS,
+			Name:      name,
+		},
+		Status: fleetnetv1alpha1.ServiceImportStatus{
+			Clusters: []fleetnetv1alpha1.ClusterStatus{{Cluster: hubNSForMemberA}},
+		},
+	}
+	if len(importers) > 0 {
+		svcInUseBy := make([]string, 0, len(importers))
+		for _, ns := range importers {
+			svcInUseBy = append(svcInUseBy, fmt.Sprintf("%q:%q", ns, ns))
+		}
+		svcImport.Annotations = map[string]string{
+			objectmeta.ServiceImportAnnotationServiceInUseBy: fmt.Sprintf(`{"MemberClusters":{%s}}`, strings.Join(svcInUseBy, ",")),
+		}
+	}
+	return svcImport
+}
+
+func endpointSliceSpec(svc string) fleetnetv1alpha1.EndpointSliceExportSpec {
+	return fleetnetv1alpha1.EndpointSliceExportSpec{
+		AddressType: discoveryv1.AddressTypeIPv4,
+		Endpoints: []fleetnetv1alpha1.Endpoint{
+			{Addresses: []string{"1.2.3.4"}},
+		},
+		OwnerServiceReference: fleetnetv1alpha1.OwnerServiceReference{
+			Namespace:      memberUserNS,
+			Name:           svc,
+			NamespacedName: fmt.Sprintf("%s/%s", memberUserNS, svc),
+		},
+	}
+}
+
+func endpointSliceExport(name, svc string) *fleetnetv1alpha1.EndpointSliceExport {
+	return &fleetnetv1alpha1.EndpointSliceExport{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: hubNSForMemberA,
+			Name:      name,
+		},
+		Spec: endpointSliceSpec(svc),
+	}
+}
+
+func endpointSliceImport(ns, name, svc string) *fleetnetv1alpha1.EndpointSliceImport {
+	return &fleetnetv1alpha1.EndpointSliceImport{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: ns,
+			Name:      name,
+		},
+		Spec: endpointSliceSpec(svc),
+	}
+}
+
+// TestCheck tests the Checker.Check method.
+func TestCheck(t *testing.T) {
+	deletionTimestamp := metav1.Now()
+	deletingExport := endpointSliceExport(endpointSliceExportName, svcName)
+	deletingExport.DeletionTimestamp = &deletionTimestamp
+	deletingExport.Finalizers = []string{"networking.fleet.azure.com/endpointsliceexport-cleanup"}
+	deletingImport := endpointSliceImport(hubNSForMemberB, endpointSliceExportName, svcName)
+	deletingImport.DeletionTimestamp = &deletionTimestamp
+	deletingImport.Finalizers = []string{"networking.fleet.azure.com/endpointsliceimport-cleanup"}
+	pendingServiceImport := serviceImport(svcName, hubNSForMemberB)
+	pendingServiceImport.Status.Clusters = nil
+	corruptedServiceImport := serviceImport(svcName)
+	corruptedServiceImport.Annotations = map[string]string{objectmeta.ServiceImportAnnotationServiceInUseBy: "{"}
+
+	testCases := []struct {
+		name       string
+		objs       []client.Object
+		wantReport *Result
+	}{
+		{
+			name: "consistent",
+			objs: []client.Object{
+				serviceImport(svcName, hubNSForMemberB, hubNSForMemberC),
+				endpointSliceExport(endpointSliceExportName, svcName),
+				endpointSliceImport(hubNSForMemberB, endpointSliceExportName, svcName),
+				endpointSliceImport(hubNSForMemberC, endpointSliceExportName, svcName),
+			},
+			wantReport: &Result{},
+		},
+		{
+			name: "missing imports",
+			objs: []client.Object{
+				serviceImport(svcName, hubNSForMemberB, hubNSForMemberC),
+				serviceImport(altSvcName, hubNSForMemberB),
+				endpointSliceExport(endpointSliceExportName, svcName),
+				endpointSliceExport(altEndpointSliceExportName, altSvcName),
+				endpointSliceImport(hubNSForMemberB, endpointSliceExportName, svcName),
+			},
+			wantReport: &Result{
+				MissingImports: []types.NamespacedName{
+					{Namespace: hubNSForMemberB, Name: altEndpointSliceExportName},
+					{Namespace: hubNSForMemberC, Name: endpointSliceExportName},
+				},
+			},
+		},
+		{
+			name: "orphaned imports",
+			objs: []client.Object{
+				serviceImport(svcName, hubNSForMemberB),
+				endpointSliceExport(endpointSliceExportName, svcName),
+				endpointSliceImport(hubNSForMemberB, endpointSliceExportName, svcName),
+				// The member cluster does not import the Service.
+				endpointSliceImport(hubNSForMemberC, endpointSliceExportName, svcName),
+				// The EndpointSliceExport does not exist.
+				endpointSliceImport(hubNSForMemberB, altEndpointSliceExportName, altSvcName),
+			},
+			wantReport: &Result{
+				OrphanedImports: []types.NamespacedName{
+					{Namespace: hubNSForMemberB, Name: altEndpointSliceExportName},
+					{Namespace: hubNSForMemberC, Name: endpointSliceExportName},
+				},
+			},
+		},
+		{
+			name: "imports of a service which is no longer imported are orphaned",
+			objs: []client.Object{
+				serviceImport(svcName),
+				endpointSliceExport(endpointSliceExportName, svcName),
+				endpointSliceImport(hubNSForMemberB, endpointSliceExportName, svcName),
+			},
+			wantReport: &Result{
+				OrphanedImports: []types.NamespacedName{
+					{Namespace: hubNSForMemberB, Name: endpointSliceExportName},
+				},
+			},
+		},
+		{
+			name: "objects being deleted are skipped",
+			objs: []client.Object{
+				serviceImport(svcName, hubNSForMemberC),
+				deletingExport,
+				deletingImport,
+			},
+			wantReport: &Result{},
+		},
+		{
+			name: "exports of a pending service import are skipped",
+			objs: []client.Object{
+				pendingServiceImport,
+				endpointSliceExport(endpointSliceExportName, svcName),
+				endpointSliceImport(hubNSForMemberC, endpointSliceExportName, svcName),
+			},
+			wantReport: &Result{},
+		},
+		{
+			name: "exports of a service import with corrupted annotations are skipped",
+			objs: []client.Object{
+				corruptedServiceImport,
+				endpointSliceExport(endpointSliceExportName, svcName),
+				endpointSliceImport(hubNSForMemberC, endpointSliceExportName, svcName),
+			},
+			wantReport: &Result{},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			fakeHubClient := fake.NewClientBuilder().
+				WithScheme(scheme.Scheme).
+				WithObjects(tc.objs...).
+				Build()
+			c := &Checker{Reader: fakeHubClient, Writer: fakeHubClient, PageSize: 1}
+			report, err := c.Check(context.Background())
+			if err != nil {
+				t.Fatalf("Check() = %v, want no error", err)
+			}
+			if diff := cmp.Diff(report, tc.wantReport); diff != "" {
+				t.Errorf("Check() report mismatch (-got, +want):\n%s", diff)
+			}
+		})
+	}
+}
+
+// TestCheckAndReport tests the Checker.CheckAndReport method.
+func TestCheckAndReport(t *testing.T) {
+	reportName := types.NamespacedName{Namespace: "fleet-system", Name: "fleet-networking-consistency-report"}
+	fakeHubClient := fake.NewClientBuilder().
+		WithScheme(scheme.Scheme).
+		WithObjects(
+			serviceImport(svcName, hubNSForMemberB),
+			endpointSliceExport(endpointSliceExportName, svcName),
+			endpointSliceImport(hubNSForMemberC, endpointSliceExportName, svcName),
+		).
+		Build()
+	c := &Checker{
+		Reader:          fakeHubClient,
+		Writer:          fakeHubClient,
+		ReportNamespace: reportName.Namespace,
+		ReportName:      reportName.Name,
+	}
+	ctx := context.Background()
+
+	missingBefore := testutil.ToFloat64(missingImportsTotal)
+	orphanedBefore := testutil.ToFloat64(orphanedImportsTotal)
+	if _, err := c.CheckAndReport(ctx); err != nil {
+		t.Fatalf("CheckAndReport() = %v, want no error", err)
+	}
+	if got := testutil.ToFloat64(missingImportsTotal) - missingBefore; got != 1 {
+		t.Errorf("missing imports counter increased by %v, want 1", got)
+	}
+	if got := testutil.ToFloat64(orphanedImportsTotal) - orphanedBefore; got != 1 {
+		t.Errorf("orphaned imports counter increased by %v, want 1", got)
+	}
+
+	cm := &corev1.ConfigMap{}
+	if err := fakeHubClient.Get(ctx, reportName, cm); err != nil {
+		t.Fatalf("ConfigMap Get(%v) = %v, want no error", reportName, err)
+	}
+	if _, err := time.Parse(time.RFC3339, cm.Data[reportKeyCheckedAt]); err != nil {
+		t.Errorf("report checkedAt %q is not a valid time: %v", cm.Data[reportKeyCheckedAt], err)
+	}
+	wantData := map[string]string{
+		reportKeyCheckedAt:        cm.Data[reportKeyCheckedAt],
+		reportKeyMissingCount:     "1",
+		reportKeyOrphanedCount:    "1",
+		reportKeyMissingExamples:  fmt.Sprintf("%s/%s\n", hubNSForMemberB, endpointSliceExportName),
+		reportKeyOrphanedExamples: fmt.Sprintf("%s/%s\n", hubNSForMemberC, endpointSliceExportName),
+	}
+	if diff := cmp.Diff(cm.Data, wantData); diff != "" {
+		t.Errorf("report ConfigMap data mismatch (-got, +want):\n%s", diff)
+	}
+
+	// Fix the inconsistency; the report should be updated.
+	if err := fakeHubClient.Create(ctx, endpointSliceImport(hubNSForMemberB, endpointSliceExportName, svcName)); err != nil {
+		t.Fatalf("EndpointSliceImport Create() = %v, want no error", err)
+	}
+	if err := fakeHubClient.Delete(ctx, endpointSliceImport(hubNSForMemberC, endpointSliceExportName, svcName)); err != nil {
+		t.Fatalf("EndpointSliceImport Delete() = %v, want no error", err)
+	}
+	if _, err := c.CheckAndReport(ctx); err != nil {
+		t.Fatalf("CheckAndReport() = %v, want no error", err)
+	}
+	if err := fakeHubClient.Get(ctx, reportName, cm); err != nil {
+		t.Fatalf("ConfigMap Get(%v) = %v, want no error", reportName, err)
+	}
+	wantData = map[string]string{
+		reportKeyCheckedAt:        cm.Data[reportKeyCheckedAt],
+		reportKeyMissingCount:     "0",
+		reportKeyOrphanedCount:    "0",
+		reportKeyMissingExamples:  "",
+		reportKeyOrphanedExamples: "",
+	}
+	if diff := cmp.Diff(cm.Data, wantData); diff != "" {
+		t.Errorf("report ConfigMap data mismatch (-got, +want):\n%s", diff)
+	}
+}
+
+// TestFormatExamples tests the formatExamples function.
+func TestFormatExamples(t *testing.T) {
+	names := make([]types.NamespacedName, 0, maxReportedImports+2)
+	want := ""
+	for i := 0; i < maxReportedImports+2; i++ {
+		name := types.NamespacedName{Namespace: hubNSForMemberB, Name: fmt.Sprintf("export-%d", i)}
+		names = append(names, name)
+		if i < maxReportedImports {
+			want += name.String() + "\n"
+		}
+	}
+	want += "+2 more\n"
+	if got := formatExamples(names); got != want {
+		t.Errorf("formatExamples() = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,156 @@
+/*
+Copyright (c) Microsoft Corporation.
+Licensed under the MIT license.
+*/
+
+// Package consumerpolicy features the helpers to evaluate the consumer policies of the exported services, which
+// restrict the member clusters allowed to import them.
+package consumerpolicy
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	clusterv1beta1 "go.goms.io/fleet/apis/cluster/v1beta1"
+
+	fleetnetv1alpha1 "go.goms.io/fleet-networking/api/v1alpha1"
+)
+
+// Evaluator evaluates the consumer policies for a single importing member cluster; the labels of its MemberCluster
+// are only read, once, when a policy has a cluster selector.
+type Evaluator struct {
+	reader    client.Reader
+	clusterID string
+
+	labels       labels.Set
+	labelsLoaded bool
+}
+
+// NewEvaluator returns an Evaluator for the given importing member cluster.
+func NewEvaluator(reader client.Reader, clusterID string) *Evaluator {
+	return &Evaluator{reader: reader, clusterID: clusterID}
+}
+
+// Permits returns true if the policy allows the member cluster to import the service. A nil policy allows any
+// member cluster.
+func (e *Evaluator) Permits(ctx context.Context, policy *fleetnetv1alpha1.ConsumerPolicy) (bool, error) {
+	if policy == nil {
+		return true, nil
+	}
+	if slices.Contains(policy.DeniedClusters, e.clusterID) {
+		return false, nil
+	}
+	if len(policy.AllowedClusters) == 0 && policy.ClusterSelector == nil {
+		return true, nil
+	}
+	if slices.Contains(policy.AllowedClusters, e.clusterID) {
+		return true, nil
+	}
+	if policy.ClusterSelector == nil {
+		return false, nil
+	}
+	selector, err := metav1.LabelSelectorAsSelector(policy.ClusterSelector)
+	if err != nil {
+		// The selector cannot be fixed by retrying; no cluster is selected until the policy is updated.
+		return false, nil
+	}
+	clusterLabels, err := e.memberClusterLabels(ctx)
+	if err != nil {
+		return false, err
+	}
+	return selector.Matches(clusterLabels), nil
+}
+
+// PermittedStatus returns a copy of the serviceImport status as seen by the member cluster: the exporting clusters
+// whose consumer policies do not allow the member cluster, and their health, are left out, and the policies
+// themselves, as well as the cluster weights, the DNS names and the importer clusters reported in the hub cluster, are
+// dropped.
+func (e *Evaluator) PermittedStatus(ctx context.Context, status *fleetnetv1alpha1.ServiceImportStatus) (*fleetnetv1alpha1.ServiceImportStatus, error) {
+	res := status.DeepCopy()
+	res.ConsumerPolicies = nil
+	res.ClusterWeights = nil
+	res.DNSNames = nil
+	res.ImporterClusters = nil
+	if len(status.ConsumerPolicies) == 0 {
+		return res, nil
+	}
+	clusters := make([]fleetnetv1alpha1.ClusterStatus, 0, len(res.Clusters))
+	for _, cs := range res.Clusters {
+		permitted, err := e.Permits(ctx, Find(status, cs.Cluster))
+		if err != nil {
+			return nil, err
+		}
+		if permitted {
+			clusters = append(clusters, cs)
+		}
+	}
+	if len(clusters) == 0 {
+		// The service is seen as if it were never exported.
+		return &fleetnetv1alpha1.ServiceImportStatus{}, nil
+	}
+	res.Clusters = clusters
+	res.ClusterHealth = slices.DeleteFunc(res.ClusterHealth, func(h fleetnetv1alpha1.ClusterExportHealth) bool {
+		return !slices.ContainsFunc(clusters, func(cs fleetnetv1alpha1.ClusterStatus) bool { return cs.Cluster == h.Cluster })
+	})
+	if len(res.ClusterHealth) == 0 {
+		res.ClusterHealth = nil
+	}
+	return res, nil
+}
+
+func (e *Evaluator) memberClusterLabels(ctx context.Context) (labels.Set, error) {
+	if e.labelsLoaded {
+		return e.labels, nil
+	}
+	mc := &clusterv1beta1.MemberCluster{}
+	if err := e.reader.Get(ctx, types.NamespacedName{Name: e.clusterID}, mc); err != nil {
+		if !apierrors.IsNotFound(err) {
+			return nil, fmt.Errorf("failed to get the memberCluster %s: %w", e.clusterID, err)
+		}
+		// A member cluster without a MemberCluster object has no labels.
+	}
+	e.labels = mc.Labels
+	e.labelsLoaded = true
+	return e.labels, nil
+}
+
+// Find returns the consumer policy of the service exported from the given cluster, or nil if it has none.
+func Find(status *fleetnetv1alpha1.ServiceImportStatus, cluster string) *fleetnetv1alpha1.ConsumerPolicy {
+	for i := range status.ConsumerPolicies {
+		if status.ConsumerPolicies[i].Cluster == cluster {
+			return &status.ConsumerPolicies[i].Policy
+		}
+	}
+	return nil
+}
+
+// Set records the consumer policy of the service exported from the given cluster in the serviceImport status; a nil
+// policy removes the record.
+func Set(status *fleetnetv1alpha1.ServiceImportStatus, cluster string, policy *fleetnetv1alpha1.ConsumerPolicy) {
+	if policy == nil {
+		status.ConsumerPolicies = slices.DeleteFunc(status.ConsumerPolicies, func(p fleetnetv1alpha1.ClusterConsumerPolicy) bool {
+			return p.Cluster == cluster
+		})
+		if len(status.ConsumerPolicies) == 0 {
+			status.ConsumerPolicies = nil
+		}
+		return
+	}
+	for i := range status.ConsumerPolicies {
+		if status.ConsumerPolicies[i].Cluster == cluster {
+			status.ConsumerPolicies[i].Policy = *policy.DeepCopy()
+			return
+		}
+	}
+	status.ConsumerPolicies = append(status.ConsumerPolicies, fleetnetv1alpha1.ClusterConsumerPolicy{
+		Cluster: cluster,
+		Policy:  *policy.DeepCopy(),
+	})
+}
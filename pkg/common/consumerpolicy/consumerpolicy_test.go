@@ -0,0 +1,339 @@
+/*
+Copyright (c) Microsoft Corporation.
+Licensed under the MIT license.
+*/
+
+package consumerpolicy
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	clusterv1beta1 "go.goms.io/fleet/apis/cluster/v1beta1"
+
+	fleetnetv1alpha1 "go.goms.io/fleet-networking/api/v1alpha1"
+)
+
+const (
+	memberClusterA = "member-a"
+	memberClusterB = "member-b"
+	memberClusterC = "member-c"
+)
+
+func testScheme(t *testing.T) *runtime.Scheme {
+	scheme := runtime.NewScheme()
+	if err := clusterv1beta1.AddToScheme(scheme); err != nil {
+		t.Fatalf("failed to add scheme: %v", err)
+	}
+	return scheme
+}
+
+func newTestEvaluator(t *testing.T, clusterID string) *Evaluator {
+	mc := &clusterv1beta1.MemberCluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   memberClusterA,
+			Labels: map[string]string{"env": "prod"},
+		},
+	}
+	fakeClient := fake.NewClientBuilder().WithScheme(testScheme(t)).WithObjects(mc).Build()
+	return NewEvaluator(fakeClient, clusterID)
+}
+
+func TestPermits(t *testing.T) {
+	prodSelector := &metav1.LabelSelector{MatchLabels: map[string]string{"env": "prod"}}
+	testCases := []struct {
+		name      string
+		clusterID string
+		policy    *fleetnetv1alpha1.ConsumerPolicy
+		want      bool
+	}{
+		{
+			name:      "nil policy",
+			clusterID: memberClusterA,
+			want:      true,
+		},
+		{
+			name:      "empty policy",
+			clusterID: memberClusterA,
+			policy:    &fleetnetv1alpha1.ConsumerPolicy{},
+			want:      true,
+		},
+		{
+			name:      "cluster is denied",
+			clusterID: memberClusterA,
+			policy: &fleetnetv1alpha1.ConsumerPolicy{
+				DeniedClusters: []string{memberClusterA},
+			},
+		},
+		{
+			name:      "other cluster is denied",
+			clusterID: memberClusterB,
+			policy: &fleetnetv1alpha1.ConsumerPolicy{
+				DeniedClusters: []string{memberClusterA},
+			},
+			want: true,
+		},
+		{
+			name:      "cluster is allowed",
+			clusterID: memberClusterB,
+			policy: &fleetnetv1alpha1.ConsumerPolicy{
+				AllowedClusters: []string{memberClusterB},
+			},
+			want: true,
+		},
+		{
+			name:      "cluster is not in the allowed list",
+			clusterID: memberClusterC,
+			policy: &fleetnetv1alpha1.ConsumerPolicy{
+				AllowedClusters: []string{memberClusterB},
+			},
+		},
+		{
+			name:      "cluster is both allowed and denied",
+			clusterID: memberClusterB,
+			policy: &fleetnetv1alpha1.ConsumerPolicy{
+				AllowedClusters: []string{memberClusterB},
+				DeniedClusters:  []string{memberClusterB},
+			},
+		},
+		{
+			name:      "cluster matches the selector",
+			clusterID: memberClusterA,
+			policy: &fleetnetv1alpha1.ConsumerPolicy{
+				ClusterSelector: prodSelector,
+			},
+			want: true,
+		},
+		{
+			name:      "cluster without memberCluster does not match the selector",
+			clusterID: memberClusterB,
+			policy: &fleetnetv1alpha1.ConsumerPolicy{
+				ClusterSelector: prodSelector,
+			},
+		},
+		{
+			name:      "selected cluster is denied",
+			clusterID: memberClusterA,
+			policy: &fleetnetv1alpha1.ConsumerPolicy{
+				DeniedClusters:  []string{memberClusterA},
+				ClusterSelector: prodSelector,
+			},
+		},
+		{
+			name:      "invalid selector",
+			clusterID: memberClusterA,
+			policy: &fleetnetv1alpha1.ConsumerPolicy{
+				ClusterSelector: &metav1.LabelSelector{
+					MatchExpressions: []metav1.LabelSelectorRequirement{
+						{Key: "env", Operator: "invalid"},
+					},
+				},
+			},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := newTestEvaluator(t, tc.clusterID).Permits(context.Background(), tc.policy)
+			if err != nil {
+				t.Fatalf("Permits() got error %v, want no error", err)
+			}
+			if got != tc.want {
+				t.Errorf("Permits() = %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestPermittedStatus(t *testing.T) {
+	status := fleetnetv1alpha1.ServiceImportStatus{
+		Type: fleetnetv1alpha1.ClusterSetIP,
+		Ports: []fleetnetv1alpha1.ServicePort{
+			{Name: "http", Port: 80},
+		},
+		Clusters: []fleetnetv1alpha1.ClusterStatus{
+			{Cluster: memberClusterA},
+			{Cluster: memberClusterB},
+		},
+	}
+	testCases := []struct {
+		name      string
+		clusterID string
+		policies  []fleetnetv1alpha1.ClusterConsumerPolicy
+		weights   []fleetnetv1alpha1.ClusterWeight
+		dnsNames  []string
+		importers []string
+		health    []fleetnetv1alpha1.ClusterExportHealth
+		want      *fleetnetv1alpha1.ServiceImportStatus
+	}{
+		{
+			name:      "no policies",
+			clusterID: memberClusterC,
+			want:      status.DeepCopy(),
+		},
+		{
+			name:      "one exporting cluster denies the importer",
+			clusterID: memberClusterC,
+			policies: []fleetnetv1alpha1.ClusterConsumerPolicy{
+				{
+					Cluster: memberClusterA,
+					Policy:  fleetnetv1alpha1.ConsumerPolicy{DeniedClusters: []string{memberClusterC}},
+				},
+			},
+			want: &fleetnetv1alpha1.ServiceImportStatus{
+				Type:     status.Type,
+				Ports:    status.Ports,
+				Clusters: []fleetnetv1alpha1.ClusterStatus{{Cluster: memberClusterB}},
+			},
+		},
+		{
+			name:      "health of the exporting cluster denying the importer is dropped",
+			clusterID: memberClusterC,
+			policies: []fleetnetv1alpha1.ClusterConsumerPolicy{
+				{
+					Cluster: memberClusterA,
+					Policy:  fleetnetv1alpha1.ConsumerPolicy{DeniedClusters: []string{memberClusterC}},
+				},
+			},
+			health: []fleetnetv1alpha1.ClusterExportHealth{
+				{Cluster: memberClusterA, Health: fleetnetv1alpha1.ExportHealth{State: fleetnetv1alpha1.ExportHealthy, Score: 100}},
+				{Cluster: memberClusterB, Health: fleetnetv1alpha1.ExportHealth{State: fleetnetv1alpha1.ExportUnhealthy}},
+			},
+			want: &fleetnetv1alpha1.ServiceImportStatus{
+				Type:     status.Type,
+				Ports:    status.Ports,
+				Clusters: []fleetnetv1alpha1.ClusterStatus{{Cluster: memberClusterB}},
+				ClusterHealth: []fleetnetv1alpha1.ClusterExportHealth{
+					{Cluster: memberClusterB, Health: fleetnetv1alpha1.ExportHealth{State: fleetnetv1alpha1.ExportUnhealthy}},
+				},
+			},
+		},
+		{
+			name:      "all exporting clusters deny the importer",
+			clusterID: memberClusterC,
+			policies: []fleetnetv1alpha1.ClusterConsumerPolicy{
+				{
+					Cluster: memberClusterA,
+					Policy:  fleetnetv1alpha1.ConsumerPolicy{DeniedClusters: []string{memberClusterC}},
+				},
+				{
+					Cluster: memberClusterB,
+					Policy:  fleetnetv1alpha1.ConsumerPolicy{AllowedClusters: []string{memberClusterA}},
+				},
+			},
+			want: &fleetnetv1alpha1.ServiceImportStatus{},
+		},
+		{
+			name:      "all exporting clusters allow the importer",
+			clusterID: memberClusterA,
+			policies: []fleetnetv1alpha1.ClusterConsumerPolicy{
+				{
+					Cluster: memberClusterB,
+					Policy:  fleetnetv1alpha1.ConsumerPolicy{AllowedClusters: []string{memberClusterA}},
+				},
+			},
+			want: status.DeepCopy(),
+		},
+		{
+			name:      "cluster weights are dropped",
+			clusterID: memberClusterC,
+			weights:   []fleetnetv1alpha1.ClusterWeight{{Cluster: memberClusterA, Weight: 3}},
+			want:      status.DeepCopy(),
+		},
+		{
+			name:      "dns names are dropped",
+			clusterID: memberClusterC,
+			dnsNames:  []string{"app.work.svc.clusterset.local"},
+			want:      status.DeepCopy(),
+		},
+		{
+			name:      "importer clusters are dropped",
+			clusterID: memberClusterC,
+			importers: []string{memberClusterA, memberClusterC},
+			want:      status.DeepCopy(),
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			in := status.DeepCopy()
+			in.ConsumerPolicies = tc.policies
+			in.ClusterWeights = tc.weights
+			in.DNSNames = tc.dnsNames
+			in.ImporterClusters = tc.importers
+			in.ClusterHealth = tc.health
+			got, err := newTestEvaluator(t, tc.clusterID).PermittedStatus(context.Background(), in)
+			if err != nil {
+				t.Fatalf("PermittedStatus() got error %v, want no error", err)
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("PermittedStatus() mismatch (-want, +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestSet(t *testing.T) {
+	policyA := fleetnetv1alpha1.ConsumerPolicy{AllowedClusters: []string{memberClusterA}}
+	policyB := fleetnetv1alpha1.ConsumerPolicy{DeniedClusters: []string{memberClusterB}}
+	testCases := []struct {
+		name     string
+		policies []fleetnetv1alpha1.ClusterConsumerPolicy
+		cluster  string
+		policy   *fleetnetv1alpha1.ConsumerPolicy
+		want     []fleetnetv1alpha1.ClusterConsumerPolicy
+	}{
+		{
+			name:    "add a policy",
+			cluster: memberClusterA,
+			policy:  &policyA,
+			want:    []fleetnetv1alpha1.ClusterConsumerPolicy{{Cluster: memberClusterA, Policy: policyA}},
+		},
+		{
+			name: "update a policy in place",
+			policies: []fleetnetv1alpha1.ClusterConsumerPolicy{
+				{Cluster: memberClusterA, Policy: policyA},
+				{Cluster: memberClusterC, Policy: policyA},
+			},
+			cluster: memberClusterA,
+			policy:  &policyB,
+			want: []fleetnetv1alpha1.ClusterConsumerPolicy{
+				{Cluster: memberClusterA, Policy: policyB},
+				{Cluster: memberClusterC, Policy: policyA},
+			},
+		},
+		{
+			name: "remove a policy",
+			policies: []fleetnetv1alpha1.ClusterConsumerPolicy{
+				{Cluster: memberClusterA, Policy: policyA},
+				{Cluster: memberClusterC, Policy: policyA},
+			},
+			cluster: memberClusterA,
+			want:    []fleetnetv1alpha1.ClusterConsumerPolicy{{Cluster: memberClusterC, Policy: policyA}},
+		},
+		{
+			name:     "remove the last policy",
+			policies: []fleetnetv1alpha1.ClusterConsumerPolicy{{Cluster: memberClusterA, Policy: policyA}},
+			cluster:  memberClusterA,
+		},
+		{
+			name:    "remove a policy which does not exist",
+			cluster: memberClusterA,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			status := &fleetnetv1alpha1.ServiceImportStatus{ConsumerPolicies: tc.policies}
+			Set(status, tc.cluster, tc.policy)
+			if diff := cmp.Diff(tc.want, status.ConsumerPolicies); diff != "" {
+				t.Errorf("Set() mismatch (-want, +got):\n%s", diff)
+			}
+			if got := Find(status, tc.cluster); !cmp.Equal(got, tc.policy) {
+				t.Errorf("Find() = %v, want %v", got, tc.policy)
+			}
+		})
+	}
+}
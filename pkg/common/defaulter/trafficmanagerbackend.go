@@ -11,8 +11,22 @@ import (
 	fleetnetv1beta1 "go.goms.io/fleet-networking/api/v1beta1"
 )
 
-// SetDefaultsTrafficManagerBackend sets the default values for TrafficManagerBackend.
+// trafficManagerBackendDefaulters are the defaulters of TrafficManagerBackend keyed by the versions of the defaults.
+var trafficManagerBackendDefaulters = map[string]func(obj *fleetnetv1beta1.TrafficManagerBackend){
+	DefaultsVersion1: setDefaultsTrafficManagerBackendV1,
+}
+
+// SetDefaultsTrafficManagerBackend sets the default values for TrafficManagerBackend, following the version of the
+// defaults recorded on the object.
 func SetDefaultsTrafficManagerBackend(obj *fleetnetv1beta1.TrafficManagerBackend) {
+	setDefaults, ok := trafficManagerBackendDefaulters[defaultsVersion(obj)]
+	if !ok {
+		setDefaults = trafficManagerBackendDefaulters[LatestDefaultsVersion]
+	}
+	setDefaults(obj)
+}
+
+func setDefaultsTrafficManagerBackendV1(obj *fleetnetv1beta1.TrafficManagerBackend) {
 	if obj.Spec.Weight == nil {
 		obj.Spec.Weight = ptr.To(int64(1))
 	}
@@ -9,9 +9,11 @@ import (
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/utils/ptr"
 
 	fleetnetv1beta1 "go.goms.io/fleet-networking/api/v1beta1"
+	"go.goms.io/fleet-networking/pkg/common/objectmeta"
 )
 
 func TestSetDefaultsTrafficManagerBackend(t *testing.T) {
@@ -31,6 +33,23 @@ func TestSetDefaultsTrafficManagerBackend(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "TrafficManagerBackend with unknown defaults version",
+			obj: &fleetnetv1beta1.TrafficManagerBackend{
+				ObjectMeta: metav1.ObjectMeta{
+					Annotations: map[string]string{objectmeta.DefaultsVersionAnnotation: "unknown"},
+				},
+				Spec: fleetnetv1beta1.TrafficManagerBackendSpec{},
+			},
+			want: &fleetnetv1beta1.TrafficManagerBackend{
+				ObjectMeta: metav1.ObjectMeta{
+					Annotations: map[string]string{objectmeta.DefaultsVersionAnnotation: "unknown"},
+				},
+				Spec: fleetnetv1beta1.TrafficManagerBackendSpec{
+					Weight: ptr.To(int64(1)),
+				},
+			},
+		},
 		{
 			name: "TrafficManagerBackend with values",
 			obj: &fleetnetv1beta1.TrafficManagerBackend{
@@ -12,8 +12,22 @@ import (
 	fleetnetv1beta1 "go.goms.io/fleet-networking/api/v1beta1"
 )
 
-// SetDefaultsTrafficManagerProfile sets the default values for TrafficManagerProfile.
+// trafficManagerProfileDefaulters are the defaulters of TrafficManagerProfile keyed by the versions of the defaults.
+var trafficManagerProfileDefaulters = map[string]func(obj *fleetnetv1beta1.TrafficManagerProfile){
+	DefaultsVersion1: setDefaultsTrafficManagerProfileV1,
+}
+
+// SetDefaultsTrafficManagerProfile sets the default values for TrafficManagerProfile, following the version of the
+// defaults recorded on the object.
 func SetDefaultsTrafficManagerProfile(obj *fleetnetv1beta1.TrafficManagerProfile) {
+	setDefaults, ok := trafficManagerProfileDefaulters[defaultsVersion(obj)]
+	if !ok {
+		setDefaults = trafficManagerProfileDefaulters[LatestDefaultsVersion]
+	}
+	setDefaults(obj)
+}
+
+func setDefaultsTrafficManagerProfileV1(obj *fleetnetv1beta1.TrafficManagerProfile) {
 	if obj.Spec.MonitorConfig == nil {
 		obj.Spec.MonitorConfig = &fleetnetv1beta1.MonitorConfig{}
 	}
@@ -0,0 +1,46 @@
+/*
+Copyright (c) Microsoft Corporation.
+Licensed under the MIT license.
+*/
+
+package defaulter
+
+import (
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"go.goms.io/fleet-networking/pkg/common/objectmeta"
+)
+
+// The versions of the defaults.
+//
+// A new version must be added whenever a default value is changed or a new default is introduced; the defaults of
+// the existing versions must never be changed, as the objects recorded with them would be rewritten otherwise.
+const (
+	// DefaultsVersion1 is the version of the defaults applied by the controllers before the defaults were versioned.
+	DefaultsVersion1 = "1"
+
+	// LatestDefaultsVersion is the version of the defaults applied to the newly created objects.
+	LatestDefaultsVersion = DefaultsVersion1
+)
+
+// SetLatestDefaultsVersion records the latest version of the defaults on the object; it should only be called
+// when the object is created.
+func SetLatestDefaultsVersion(obj client.Object) {
+	annotations := obj.GetAnnotations()
+	if annotations == nil {
+		annotations = map[string]string{}
+	}
+	annotations[objectmeta.DefaultsVersionAnnotation] = LatestDefaultsVersion
+	obj.SetAnnotations(annotations)
+}
+
+// defaultsVersion returns the version of the defaults recorded on the object. The objects created before the
+// defaults were versioned have no version recorded and receive the defaults of DefaultsVersion1; the objects
+// recorded with an unknown version (e.g., after a downgrade) receive the latest known defaults.
+func defaultsVersion(obj client.Object) string {
+	version, ok := obj.GetAnnotations()[objectmeta.DefaultsVersionAnnotation]
+	if !ok {
+		return DefaultsVersion1
+	}
+	return version
+}
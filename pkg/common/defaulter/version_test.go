@@ -0,0 +1,95 @@
+/*
+Copyright (c) Microsoft Corporation.
+Licensed under the MIT license.
+*/
+
+package defaulter
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	fleetnetv1beta1 "go.goms.io/fleet-networking/api/v1beta1"
+	"go.goms.io/fleet-networking/pkg/common/objectmeta"
+)
+
+func TestSetLatestDefaultsVersion(t *testing.T) {
+	tests := []struct {
+		name        string
+		annotations map[string]string
+		want        map[string]string
+	}{
+		{
+			name: "annotations are nil",
+			want: map[string]string{
+				objectmeta.DefaultsVersionAnnotation: LatestDefaultsVersion,
+			},
+		},
+		{
+			name: "annotations have other keys",
+			annotations: map[string]string{
+				"key": "value",
+			},
+			want: map[string]string{
+				"key":                                "value",
+				objectmeta.DefaultsVersionAnnotation: LatestDefaultsVersion,
+			},
+		},
+		{
+			name: "old version is recorded",
+			annotations: map[string]string{
+				objectmeta.DefaultsVersionAnnotation: "0",
+			},
+			want: map[string]string{
+				objectmeta.DefaultsVersionAnnotation: LatestDefaultsVersion,
+			},
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			obj := &fleetnetv1beta1.TrafficManagerBackend{
+				ObjectMeta: metav1.ObjectMeta{
+					Annotations: tc.annotations,
+				},
+			}
+			SetLatestDefaultsVersion(obj)
+			if diff := cmp.Diff(tc.want, obj.Annotations); diff != "" {
+				t.Errorf("SetLatestDefaultsVersion() annotations mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestDefaultsVersion(t *testing.T) {
+	tests := []struct {
+		name        string
+		annotations map[string]string
+		want        string
+	}{
+		{
+			name: "no version is recorded",
+			want: DefaultsVersion1,
+		},
+		{
+			name: "version is recorded",
+			annotations: map[string]string{
+				objectmeta.DefaultsVersionAnnotation: "2",
+			},
+			want: "2",
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			obj := &fleetnetv1beta1.TrafficManagerBackend{
+				ObjectMeta: metav1.ObjectMeta{
+					Annotations: tc.annotations,
+				},
+			}
+			if got := defaultsVersion(obj); got != tc.want {
+				t.Errorf("defaultsVersion() = %q, want %q", got, tc.want)
+			}
+		})
+	}
+}
@@ -9,23 +9,47 @@ package env
 import (
 	"fmt"
 	"os"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/util/validation"
 )
 
 const (
 	// Environment variable keys
 	memberClusterNameEnvKey = "MEMBER_CLUSTER_NAME"
+
+	// MemberClusterNameMaxLength is the max length of the member cluster name; the hub namespace of the member
+	// cluster, fleet-member-<member cluster name>, must be a valid DNS-1123 label of at most 63 characters.
+	MemberClusterNameMaxLength = validation.DNS1123LabelMaxLength - len("fleet-member-")
 )
 
-// Lookup returns environment variable when found otherwise error will be returned.
+// Lookup returns environment variable with the surrounding whitespace trimmed when found otherwise error will be
+// returned; a variable which is set to an empty or whitespace-only value is treated as an error too.
 func Lookup(envKey string) (string, error) {
 	value, ok := os.LookupEnv(envKey)
 	if !ok {
 		return "", fmt.Errorf("failed to retrieve the environment variable value from %s", envKey)
 	}
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return "", fmt.Errorf("environment variable %s is set to an empty value", envKey)
+	}
 	return value, nil
 }
 
-// LookupMemberClusterName returns the member cluster name.
+// LookupMemberClusterName returns the member cluster name, which must be a valid DNS-1123 label of at most
+// MemberClusterNameMaxLength characters.
 func LookupMemberClusterName() (string, error) {
-	return Lookup(memberClusterNameEnvKey)
+	name, err := Lookup(memberClusterNameEnvKey)
+	if err != nil {
+		return "", err
+	}
+	if len(name) > MemberClusterNameMaxLength {
+		return "", fmt.Errorf("environment variable %s %q is invalid: must be no more than %d characters, got %d",
+			memberClusterNameEnvKey, name, MemberClusterNameMaxLength, len(name))
+	}
+	if errs := validation.IsDNS1123Label(name); len(errs) > 0 {
+		return "", fmt.Errorf("environment variable %s %q is invalid: %s", memberClusterNameEnvKey, name, strings.Join(errs, "; "))
+	}
+	return name, nil
 }
@@ -6,7 +6,9 @@ Licensed under the MIT license.
 package env
 
 import (
+	"fmt"
 	"os"
+	"strings"
 	"testing"
 )
 
@@ -54,23 +56,112 @@ func TestLookup(t *testing.T) {
 	}
 }
 
+func TestLookup_Normalization(t *testing.T) {
+	key := "test-env-normalization"
+	testCases := []struct {
+		name    string
+		value   string
+		want    string
+		wantErr string
+	}{
+		{
+			name:  "surrounding whitespace is trimmed",
+			value: "  test-value\n",
+			want:  "test-value",
+		},
+		{
+			name:    "empty value",
+			value:   "",
+			wantErr: "environment variable test-env-normalization is set to an empty value",
+		},
+		{
+			name:    "whitespace-only value",
+			value:   " \t\n",
+			wantErr: "environment variable test-env-normalization is set to an empty value",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Setenv(key, tc.value)
+			got, err := Lookup(key)
+			if tc.wantErr != "" {
+				if err == nil || err.Error() != tc.wantErr {
+					t.Fatalf("Lookup(%v) got err %v, want err %q", key, err, tc.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Lookup(%v) got err %v, want no err", key, err)
+			}
+			if got != tc.want {
+				t.Errorf("Lookup(%v) = %q, want %q", key, got, tc.want)
+			}
+		})
+	}
+}
+
 func TestLookupMemberClusterName(t *testing.T) {
+	invalidLabelErrMsg := "a lowercase RFC 1123 label must consist of lower case alphanumeric characters or '-', and must start and end with an alphanumeric character (e.g. 'my-name',  or '123-abc', regex used for validation is '[a-z0-9]([-a-z0-9]*[a-z0-9])?')"
 	testCases := []struct {
 		name    string
 		key     string
 		value   string
-		wantErr bool
+		want    string
+		wantErr string
 	}{
 		{
-			name:    "environment variable is present",
-			key:     memberClusterNameEnvKey,
-			value:   "test-value",
-			wantErr: false,
+			name:  "environment variable is present",
+			key:   memberClusterNameEnvKey,
+			value: "test-value",
+			want:  "test-value",
 		},
 		{
 			name:    "environment variable is not present",
 			key:     memberClusterNameEnvKey,
-			wantErr: true,
+			wantErr: "failed to retrieve the environment variable value from MEMBER_CLUSTER_NAME",
+		},
+		{
+			name:  "trailing newline is trimmed",
+			key:   memberClusterNameEnvKey,
+			value: "member-1\n",
+			want:  "member-1",
+		},
+		{
+			name:  "name of the max length",
+			key:   memberClusterNameEnvKey,
+			value: strings.Repeat("a", MemberClusterNameMaxLength),
+			want:  strings.Repeat("a", MemberClusterNameMaxLength),
+		},
+		{
+			name:    "whitespace-only name",
+			key:     memberClusterNameEnvKey,
+			value:   "   ",
+			wantErr: "environment variable MEMBER_CLUSTER_NAME is set to an empty value",
+		},
+		{
+			name:    "name is too long",
+			key:     memberClusterNameEnvKey,
+			value:   strings.Repeat("a", MemberClusterNameMaxLength+1),
+			wantErr: fmt.Sprintf("environment variable MEMBER_CLUSTER_NAME %q is invalid: must be no more than 50 characters, got 51", strings.Repeat("a", MemberClusterNameMaxLength+1)),
+		},
+		{
+			name:    "name has uppercase letters",
+			key:     memberClusterNameEnvKey,
+			value:   "Member-1",
+			wantErr: `environment variable MEMBER_CLUSTER_NAME "Member-1" is invalid: ` + invalidLabelErrMsg,
+		},
+		{
+			name:    "name has invalid characters",
+			key:     memberClusterNameEnvKey,
+			value:   "member_1.west",
+			wantErr: `environment variable MEMBER_CLUSTER_NAME "member_1.west" is invalid: ` + invalidLabelErrMsg,
+		},
+		{
+			name:    "name ends with a dash",
+			key:     memberClusterNameEnvKey,
+			value:   "member-",
+			wantErr: `environment variable MEMBER_CLUSTER_NAME "member-" is invalid: ` + invalidLabelErrMsg,
 		},
 	}
 
@@ -82,16 +173,18 @@ func TestLookupMemberClusterName(t *testing.T) {
 				os.Setenv(tc.key, tc.value)
 			}
 			val, err := LookupMemberClusterName()
-			if (err != nil) != tc.wantErr {
-				t.Fatalf("LookupMemberClusterName() got err %v, want err %v", err, tc.wantErr)
-			}
-
-			if tc.wantErr {
+			if tc.wantErr != "" {
+				if err == nil || err.Error() != tc.wantErr {
+					t.Fatalf("LookupMemberClusterName() got err %v, want err %q", err, tc.wantErr)
+				}
 				return
 			}
+			if err != nil {
+				t.Fatalf("LookupMemberClusterName() got err %v, want no err", err)
+			}
 
-			if val != tc.value {
-				t.Errorf("LookupMemberClusterName() = %v, want %v", tc.value, val)
+			if val != tc.want {
+				t.Errorf("LookupMemberClusterName() = %q, want %q", val, tc.want)
 			}
 		})
 	}
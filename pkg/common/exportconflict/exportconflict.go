@@ -0,0 +1,65 @@
+/*
+Copyright (c) Microsoft Corporation.
+Licensed under the MIT license.
+*/
+
+// Package exportconflict features the read-only evaluation of the conflicts between the exports of a service, shared
+// by the hub cluster, which resolves the conflicts, and the member clusters, which preview their outcome before a
+// change is exported.
+package exportconflict
+
+import (
+	"fmt"
+	"slices"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+
+	fleetnetv1alpha1 "go.goms.io/fleet-networking/api/v1alpha1"
+	"go.goms.io/fleet-networking/pkg/common/ipfamily"
+)
+
+// IsSpecResolved returns true if the spec of an export matches the resolved ports and IP families of the service.
+func IsSpecResolved(ports []fleetnetv1alpha1.ServicePort, ipFamilies []corev1.IPFamily, spec *fleetnetv1alpha1.InternalServiceExportSpec) bool {
+	// To simplify the implementation, we compare the whole ports structure.
+	// TODO, change to compare the ports by ignoring the order and protocol and port are the map keys.
+	return equality.Semantic.DeepEqual(ports, spec.Ports) && ipfamily.Equal(ipFamilies, spec.AddressFamilies)
+}
+
+// ResolutionOf returns the resolution of a serviceImport as reported to the exporting clusters.
+func ResolutionOf(serviceImport *fleetnetv1alpha1.ServiceImport, importers []string) *fleetnetv1alpha1.ExportResolution {
+	resolution := &fleetnetv1alpha1.ExportResolution{
+		Ports:            serviceImport.Status.Ports,
+		IPFamilies:       serviceImport.Status.IPFamilies,
+		ImporterClusters: importers,
+	}
+	for _, cluster := range serviceImport.Status.Clusters {
+		resolution.ExportingClusters = append(resolution.ExportingClusters, cluster.Cluster)
+	}
+	return resolution.DeepCopy()
+}
+
+// Evaluate returns the expected outcome of the conflict resolution of an export with the given spec against the
+// resolution of the service, along with a human readable message; it reads nothing but its arguments.
+//
+// An export matching the resolved spec is accepted. Otherwise, it is in conflict as long as other clusters export the
+// service with the resolved spec; if none does, the spec of the service is resolved anew from the exports, which
+// accepts the export unless another cluster exports the service with yet another spec which is resolved first.
+func Evaluate(resolution *fleetnetv1alpha1.ExportResolution, spec *fleetnetv1alpha1.InternalServiceExportSpec) (fleetnetv1alpha1.ExportConflictOutcome, string) {
+	if resolution == nil {
+		return fleetnetv1alpha1.ExportConflictUnknown, "the hub cluster has not reported the resolution of the service yet"
+	}
+	if len(resolution.Ports) == 0 {
+		return fleetnetv1alpha1.ExportConflictNone, "the service has not been resolved yet; its spec will be resolved from the exports"
+	}
+	if IsSpecResolved(resolution.Ports, resolution.IPFamilies, spec) {
+		return fleetnetv1alpha1.ExportConflictNone, "the export matches the resolved spec of the service"
+	}
+	others := slices.DeleteFunc(slices.Clone(resolution.ExportingClusters), func(cluster string) bool {
+		return cluster == spec.ServiceReference.ClusterID
+	})
+	if len(others) == 0 {
+		return fleetnetv1alpha1.ExportConflictNone, "no other cluster exports the service; its spec will be resolved anew from the exports"
+	}
+	return fleetnetv1alpha1.ExportConflictConflicted, fmt.Sprintf("the export does not match the spec of the service resolved from clusters %v; the endpoints of the cluster would be withdrawn from the importing clusters and from Azure Traffic Manager", others)
+}
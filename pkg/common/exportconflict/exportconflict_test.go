@@ -0,0 +1,136 @@
+/*
+Copyright (c) Microsoft Corporation.
+Licensed under the MIT license.
+*/
+
+package exportconflict
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	fleetnetv1alpha1 "go.goms.io/fleet-networking/api/v1alpha1"
+)
+
+var (
+	httpPorts  = []fleetnetv1alpha1.ServicePort{{Name: "http", Protocol: corev1.ProtocolTCP, Port: 80}}
+	httpsPorts = []fleetnetv1alpha1.ServicePort{{Name: "https", Protocol: corev1.ProtocolTCP, Port: 443}}
+)
+
+func exportSpec(clusterID string, ports []fleetnetv1alpha1.ServicePort, families ...corev1.IPFamily) *fleetnetv1alpha1.InternalServiceExportSpec {
+	return &fleetnetv1alpha1.InternalServiceExportSpec{
+		Ports:            ports,
+		AddressFamilies:  families,
+		ServiceReference: fleetnetv1alpha1.ExportedObjectReference{ClusterID: clusterID},
+	}
+}
+
+// TestEvaluate tests the Evaluate function.
+func TestEvaluate(t *testing.T) {
+	testCases := []struct {
+		name       string
+		resolution *fleetnetv1alpha1.ExportResolution
+		spec       *fleetnetv1alpha1.InternalServiceExportSpec
+		want       fleetnetv1alpha1.ExportConflictOutcome
+	}{
+		{
+			name: "no resolution reported",
+			spec: exportSpec("member-1", httpPorts),
+			want: fleetnetv1alpha1.ExportConflictUnknown,
+		},
+		{
+			name:       "service not resolved yet",
+			resolution: &fleetnetv1alpha1.ExportResolution{},
+			spec:       exportSpec("member-1", httpsPorts),
+			want:       fleetnetv1alpha1.ExportConflictNone,
+		},
+		{
+			name: "matching spec",
+			resolution: &fleetnetv1alpha1.ExportResolution{
+				Ports:             httpPorts,
+				ExportingClusters: []string{"member-1", "member-2"},
+			},
+			spec: exportSpec("member-1", httpPorts),
+			want: fleetnetv1alpha1.ExportConflictNone,
+		},
+		{
+			name: "matching spec with unreported IPv4 family",
+			resolution: &fleetnetv1alpha1.ExportResolution{
+				Ports:             httpPorts,
+				IPFamilies:        []corev1.IPFamily{corev1.IPv4Protocol},
+				ExportingClusters: []string{"member-2"},
+			},
+			spec: exportSpec("member-1", httpPorts),
+			want: fleetnetv1alpha1.ExportConflictNone,
+		},
+		{
+			name: "ports changed with other exporting clusters",
+			resolution: &fleetnetv1alpha1.ExportResolution{
+				Ports:             httpPorts,
+				ExportingClusters: []string{"member-1", "member-2"},
+			},
+			spec: exportSpec("member-1", httpsPorts),
+			want: fleetnetv1alpha1.ExportConflictConflicted,
+		},
+		{
+			name: "IP families changed with other exporting clusters",
+			resolution: &fleetnetv1alpha1.ExportResolution{
+				Ports:             httpPorts,
+				ExportingClusters: []string{"member-1", "member-2"},
+			},
+			spec: exportSpec("member-1", httpPorts, corev1.IPv4Protocol, corev1.IPv6Protocol),
+			want: fleetnetv1alpha1.ExportConflictConflicted,
+		},
+		{
+			name: "ports changed by the sole exporting cluster",
+			resolution: &fleetnetv1alpha1.ExportResolution{
+				Ports:             httpPorts,
+				ExportingClusters: []string{"member-1"},
+			},
+			spec: exportSpec("member-1", httpsPorts),
+			want: fleetnetv1alpha1.ExportConflictNone,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, message := Evaluate(tc.resolution, tc.spec)
+			if got != tc.want {
+				t.Errorf("Evaluate() = %s (%s), want %s", got, message, tc.want)
+			}
+			if message == "" {
+				t.Error("Evaluate() message is empty, want a message")
+			}
+		})
+	}
+}
+
+// TestResolutionOf tests the ResolutionOf function.
+func TestResolutionOf(t *testing.T) {
+	serviceImport := &fleetnetv1alpha1.ServiceImport{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "work", Name: "app"},
+		Status: fleetnetv1alpha1.ServiceImportStatus{
+			Ports:      httpPorts,
+			IPFamilies: []corev1.IPFamily{corev1.IPv4Protocol},
+			Clusters:   []fleetnetv1alpha1.ClusterStatus{{Cluster: "member-1"}, {Cluster: "member-2"}},
+		},
+	}
+	want := &fleetnetv1alpha1.ExportResolution{
+		Ports:             httpPorts,
+		IPFamilies:        []corev1.IPFamily{corev1.IPv4Protocol},
+		ExportingClusters: []string{"member-1", "member-2"},
+		ImporterClusters:  []string{"member-3"},
+	}
+	got := ResolutionOf(serviceImport, []string{"member-3"})
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("ResolutionOf() mismatch (-want, +got):\n%s", diff)
+	}
+	// The resolution does not share the ports with the serviceImport.
+	got.Ports[0].Port = 8080
+	if serviceImport.Status.Ports[0].Port != 80 {
+		t.Errorf("ResolutionOf() shares the ports with the serviceImport")
+	}
+}
@@ -0,0 +1,66 @@
+/*
+Copyright (c) Microsoft Corporation.
+Licensed under the MIT license.
+*/
+
+// Package exporthealth features the helpers to record and evaluate the health of the exported services, as reported
+// by the health probes of the exporting clusters.
+package exporthealth
+
+import (
+	"slices"
+
+	fleetnetv1alpha1 "go.goms.io/fleet-networking/api/v1alpha1"
+)
+
+// Find returns the health of the service exported from the given cluster, or nil if the cluster does not report it.
+func Find(status *fleetnetv1alpha1.ServiceImportStatus, cluster string) *fleetnetv1alpha1.ExportHealth {
+	for i := range status.ClusterHealth {
+		if status.ClusterHealth[i].Cluster == cluster {
+			return &status.ClusterHealth[i].Health
+		}
+	}
+	return nil
+}
+
+// Set records the health of the service exported from the given cluster in the serviceImport status; a nil health
+// removes the record.
+func Set(status *fleetnetv1alpha1.ServiceImportStatus, cluster string, health *fleetnetv1alpha1.ExportHealth) {
+	if health == nil {
+		status.ClusterHealth = slices.DeleteFunc(status.ClusterHealth, func(h fleetnetv1alpha1.ClusterExportHealth) bool {
+			return h.Cluster == cluster
+		})
+		if len(status.ClusterHealth) == 0 {
+			status.ClusterHealth = nil
+		}
+		return
+	}
+	for i := range status.ClusterHealth {
+		if status.ClusterHealth[i].Cluster == cluster {
+			status.ClusterHealth[i].Health = *health.DeepCopy()
+			return
+		}
+	}
+	status.ClusterHealth = append(status.ClusterHealth, fleetnetv1alpha1.ClusterExportHealth{
+		Cluster: cluster,
+		Health:  *health.DeepCopy(),
+	})
+}
+
+// IsUnhealthy returns true if the given cluster reports the service as Unhealthy.
+func IsUnhealthy(status *fleetnetv1alpha1.ServiceImportStatus, cluster string) bool {
+	health := Find(status, cluster)
+	return health != nil && health.State == fleetnetv1alpha1.ExportUnhealthy
+}
+
+// ShouldExclude returns true if the endpoints exported from the given cluster should not be imported per the
+// exporter health policy: the cluster reports the service as Unhealthy while another exporting cluster does not, so
+// that the traffic is never left without any endpoint when all the clusters report the service as Unhealthy.
+func ShouldExclude(policy fleetnetv1alpha1.ExporterHealthPolicy, status *fleetnetv1alpha1.ServiceImportStatus, cluster string) bool {
+	if policy != fleetnetv1alpha1.ExporterHealthPolicyExcludeUnhealthy || !IsUnhealthy(status, cluster) {
+		return false
+	}
+	return slices.ContainsFunc(status.Clusters, func(cs fleetnetv1alpha1.ClusterStatus) bool {
+		return cs.Cluster != cluster && !IsUnhealthy(status, cs.Cluster)
+	})
+}
@@ -0,0 +1,157 @@
+/*
+Copyright (c) Microsoft Corporation.
+Licensed under the MIT license.
+*/
+
+package exporthealth
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	fleetnetv1alpha1 "go.goms.io/fleet-networking/api/v1alpha1"
+)
+
+const (
+	memberClusterA = "member-a"
+	memberClusterB = "member-b"
+	memberClusterC = "member-c"
+)
+
+var (
+	healthy   = fleetnetv1alpha1.ExportHealth{State: fleetnetv1alpha1.ExportHealthy, Score: 100}
+	unhealthy = fleetnetv1alpha1.ExportHealth{State: fleetnetv1alpha1.ExportUnhealthy, Message: "503 Service Unavailable"}
+	unknown   = fleetnetv1alpha1.ExportHealth{State: fleetnetv1alpha1.ExportHealthUnknown}
+)
+
+func TestSet(t *testing.T) {
+	testCases := []struct {
+		name    string
+		records []fleetnetv1alpha1.ClusterExportHealth
+		cluster string
+		health  *fleetnetv1alpha1.ExportHealth
+		want    []fleetnetv1alpha1.ClusterExportHealth
+	}{
+		{
+			name:    "add a health",
+			cluster: memberClusterA,
+			health:  &healthy,
+			want:    []fleetnetv1alpha1.ClusterExportHealth{{Cluster: memberClusterA, Health: healthy}},
+		},
+		{
+			name: "update a health in place",
+			records: []fleetnetv1alpha1.ClusterExportHealth{
+				{Cluster: memberClusterA, Health: healthy},
+				{Cluster: memberClusterC, Health: healthy},
+			},
+			cluster: memberClusterA,
+			health:  &unhealthy,
+			want: []fleetnetv1alpha1.ClusterExportHealth{
+				{Cluster: memberClusterA, Health: unhealthy},
+				{Cluster: memberClusterC, Health: healthy},
+			},
+		},
+		{
+			name: "remove a health",
+			records: []fleetnetv1alpha1.ClusterExportHealth{
+				{Cluster: memberClusterA, Health: healthy},
+				{Cluster: memberClusterC, Health: healthy},
+			},
+			cluster: memberClusterA,
+			want:    []fleetnetv1alpha1.ClusterExportHealth{{Cluster: memberClusterC, Health: healthy}},
+		},
+		{
+			name:    "remove the last health",
+			records: []fleetnetv1alpha1.ClusterExportHealth{{Cluster: memberClusterA, Health: healthy}},
+			cluster: memberClusterA,
+		},
+		{
+			name:    "remove a health which does not exist",
+			cluster: memberClusterA,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			status := &fleetnetv1alpha1.ServiceImportStatus{ClusterHealth: tc.records}
+			Set(status, tc.cluster, tc.health)
+			if diff := cmp.Diff(tc.want, status.ClusterHealth); diff != "" {
+				t.Errorf("Set() mismatch (-want, +got):\n%s", diff)
+			}
+			if got := Find(status, tc.cluster); !cmp.Equal(got, tc.health) {
+				t.Errorf("Find() = %v, want %v", got, tc.health)
+			}
+		})
+	}
+}
+
+func TestShouldExclude(t *testing.T) {
+	clusters := []fleetnetv1alpha1.ClusterStatus{
+		{Cluster: memberClusterA},
+		{Cluster: memberClusterB},
+		{Cluster: memberClusterC},
+	}
+	testCases := []struct {
+		name    string
+		policy  fleetnetv1alpha1.ExporterHealthPolicy
+		records []fleetnetv1alpha1.ClusterExportHealth
+		want    map[string]bool
+	}{
+		{
+			name:   "ignore policy",
+			policy: fleetnetv1alpha1.ExporterHealthPolicyIgnore,
+			records: []fleetnetv1alpha1.ClusterExportHealth{
+				{Cluster: memberClusterA, Health: unhealthy},
+				{Cluster: memberClusterB, Health: healthy},
+			},
+			want: map[string]bool{memberClusterA: false, memberClusterB: false, memberClusterC: false},
+		},
+		{
+			name: "unset policy",
+			records: []fleetnetv1alpha1.ClusterExportHealth{
+				{Cluster: memberClusterA, Health: unhealthy},
+				{Cluster: memberClusterB, Health: healthy},
+			},
+			want: map[string]bool{memberClusterA: false, memberClusterB: false, memberClusterC: false},
+		},
+		{
+			name:   "unhealthy cluster is excluded",
+			policy: fleetnetv1alpha1.ExporterHealthPolicyExcludeUnhealthy,
+			records: []fleetnetv1alpha1.ClusterExportHealth{
+				{Cluster: memberClusterA, Health: unhealthy},
+				{Cluster: memberClusterB, Health: healthy},
+				{Cluster: memberClusterC, Health: unknown},
+			},
+			want: map[string]bool{memberClusterA: true, memberClusterB: false, memberClusterC: false},
+		},
+		{
+			name:   "unhealthy cluster is excluded in favor of a cluster not reporting its health",
+			policy: fleetnetv1alpha1.ExporterHealthPolicyExcludeUnhealthy,
+			records: []fleetnetv1alpha1.ClusterExportHealth{
+				{Cluster: memberClusterA, Health: unhealthy},
+				{Cluster: memberClusterB, Health: unhealthy},
+			},
+			want: map[string]bool{memberClusterA: true, memberClusterB: true, memberClusterC: false},
+		},
+		{
+			name:   "no cluster is excluded when all of them are unhealthy",
+			policy: fleetnetv1alpha1.ExporterHealthPolicyExcludeUnhealthy,
+			records: []fleetnetv1alpha1.ClusterExportHealth{
+				{Cluster: memberClusterA, Health: unhealthy},
+				{Cluster: memberClusterB, Health: unhealthy},
+				{Cluster: memberClusterC, Health: unhealthy},
+			},
+			want: map[string]bool{memberClusterA: false, memberClusterB: false, memberClusterC: false},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			status := &fleetnetv1alpha1.ServiceImportStatus{Clusters: clusters, ClusterHealth: tc.records}
+			for cluster, want := range tc.want {
+				if got := ShouldExclude(tc.policy, status, cluster); got != want {
+					t.Errorf("ShouldExclude(%s) = %v, want %v", cluster, got, want)
+				}
+			}
+		})
+	}
+}
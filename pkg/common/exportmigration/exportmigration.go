@@ -0,0 +1,235 @@
+/*
+Copyright (c) Microsoft Corporation.
+Licensed under the MIT license.
+*/
+
+// Package exportmigration migrates, at the startup of the member agent, the objects exported to the hub cluster under
+// a previous member cluster ID, e.g. after the member cluster has been re-onboarded under a new name.
+//
+// The references of the exported objects, and hence their links with the exporting member cluster, are immutable
+// once the objects are created; without the migration, the objects exported under the previous ID are neither
+// updated nor withdrawn by the agent, while the same Services and EndpointSlices are exported again under the
+// current ID.
+package exportmigration
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	fleetnetv1alpha1 "go.goms.io/fleet-networking/api/v1alpha1"
+	"go.goms.io/fleet-networking/pkg/common/hubclient"
+	"go.goms.io/fleet-networking/pkg/common/objectmeta"
+)
+
+// Mode is how the objects exported under a previous member cluster ID are migrated.
+type Mode string
+
+const (
+	// ModeAdopt rewrites the member cluster ID in the exported objects, which are kept, so that the importing
+	// member clusters see no churn.
+	ModeAdopt Mode = "adopt"
+	// ModeRecreate deletes the exported objects, which are exported again under the current member cluster ID by the
+	// controllers.
+	ModeRecreate Mode = "recreate"
+)
+
+// ParseMode returns the migration mode of the value of the --on-cluster-id-change flag.
+func ParseMode(s string) (Mode, error) {
+	switch mode := Mode(s); mode {
+	case ModeAdopt, ModeRecreate:
+		return mode, nil
+	default:
+		return "", fmt.Errorf("invalid migration mode %q, want %s or %s", s, ModeAdopt, ModeRecreate)
+	}
+}
+
+//+kubebuilder:rbac:groups=networking.fleet.azure.com,resources=endpointsliceexports;internalserviceexports,verbs=list;patch;delete
+//+kubebuilder:rbac:groups=discovery.k8s.io,resources=endpointslices,verbs=get;update
+//+kubebuilder:rbac:groups="",resources=services,verbs=get
+
+// Migrator migrates the objects exported to the hub cluster under a previous member cluster ID.
+//
+// Only the exported objects linked, by UID, with a live object of the member cluster are migrated; the others are
+// left to the controllers, which withdraw them as they do for any stale export.
+type Migrator struct {
+	MemberClient client.Client
+	HubClient    client.Client
+	// MemberClusterID is the current ID of the member cluster.
+	MemberClusterID string
+	// HubNamespaces are the namespaces in the hub cluster in which the objects of the member cluster are exported.
+	HubNamespaces []string
+	Mode          Mode
+}
+
+// Summary counts the exported objects found under a previous member cluster ID, per outcome.
+type Summary struct {
+	AdoptedEndpointSliceExports   int
+	DeletedEndpointSliceExports   int
+	AdoptedInternalServiceExports int
+	DeletedInternalServiceExports int
+	// Unlinked is the number of the exported objects which are not linked with any live object of the member cluster
+	// and are left untouched.
+	Unlinked int
+}
+
+// Run migrates the exported objects in every hub namespace of the member cluster; it stops at the first error, and
+// returns the objects migrated so far.
+func (m *Migrator) Run(ctx context.Context) (Summary, error) {
+	var summary Summary
+	for _, ns := range m.HubNamespaces {
+		if err := m.migrateEndpointSliceExports(ctx, ns, &summary); err != nil {
+			return summary, err
+		}
+		if err := m.migrateInternalServiceExports(ctx, ns, &summary); err != nil {
+			return summary, err
+		}
+	}
+	klog.InfoS("Migrated the objects exported under a previous member cluster ID",
+		"memberClusterID", m.MemberClusterID,
+		"mode", m.Mode,
+		"adoptedEndpointSliceExports", summary.AdoptedEndpointSliceExports,
+		"deletedEndpointSliceExports", summary.DeletedEndpointSliceExports,
+		"adoptedInternalServiceExports", summary.AdoptedInternalServiceExports,
+		"deletedInternalServiceExports", summary.DeletedInternalServiceExports,
+		"unlinked", summary.Unlinked)
+	return summary, nil
+}
+
+// migrateEndpointSliceExports migrates the EndpointSliceExports of a hub namespace.
+func (m *Migrator) migrateEndpointSliceExports(ctx context.Context, ns string, summary *Summary) error {
+	endpointSliceExports := &fleetnetv1alpha1.EndpointSliceExportList{}
+	if err := m.HubClient.List(ctx, endpointSliceExports, client.InNamespace(ns)); err != nil {
+		klog.ErrorS(err, "Failed to list endpointSliceExports", "namespace", ns)
+		return err
+	}
+	for i := range endpointSliceExports.Items {
+		endpointSliceExport := &endpointSliceExports.Items[i]
+		ref := endpointSliceExport.Spec.EndpointSliceReference
+		if ref.ClusterID == m.MemberClusterID {
+			continue
+		}
+		endpointSlice := &discoveryv1.EndpointSlice{}
+		linked, err := m.isLinked(ctx, types.NamespacedName{Namespace: ref.Namespace, Name: ref.Name}, ref.UID, endpointSlice)
+		if err != nil {
+			klog.ErrorS(err, "Failed to get endpointSlice", "endpointSliceExport", klog.KObj(endpointSliceExport))
+			return err
+		}
+		if !linked {
+			summary.Unlinked++
+			continue
+		}
+
+		if m.Mode == ModeAdopt {
+			if err := m.adopt(ctx, endpointSliceExport, &endpointSliceExport.Spec.EndpointSliceReference); err != nil {
+				return err
+			}
+			summary.AdoptedEndpointSliceExports++
+			continue
+		}
+		if err := hubclient.Delete(ctx, m.HubClient, endpointSliceExport, "endpointSlice", klog.KObj(endpointSlice)); err != nil {
+			return err
+		}
+		summary.DeletedEndpointSliceExports++
+		klog.V(2).InfoS("Deleted endpointSliceExport of a previous member cluster ID",
+			"endpointSliceExport", klog.KObj(endpointSliceExport),
+			"endpointSlice", klog.KObj(endpointSlice),
+			"previousClusterID", ref.ClusterID)
+		// Remove the unique name, which embeds the previous ID, so that a new one is assigned for the export; this
+		// must happen after the EndpointSliceExport has been deleted.
+		if endpointSlice.Annotations[objectmeta.ExportedObjectAnnotationUniqueName] == endpointSliceExport.Name {
+			delete(endpointSlice.Annotations, objectmeta.ExportedObjectAnnotationUniqueName)
+			if err := m.MemberClient.Update(ctx, endpointSlice); err != nil {
+				klog.ErrorS(err, "Failed to remove endpointslice unique name annotation", "endpointSlice", klog.KObj(endpointSlice))
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// migrateInternalServiceExports migrates the InternalServiceExports of a hub namespace.
+func (m *Migrator) migrateInternalServiceExports(ctx context.Context, ns string, summary *Summary) error {
+	internalSvcExports := &fleetnetv1alpha1.InternalServiceExportList{}
+	if err := m.HubClient.List(ctx, internalSvcExports, client.InNamespace(ns)); err != nil {
+		klog.ErrorS(err, "Failed to list internalServiceExports", "namespace", ns)
+		return err
+	}
+	for i := range internalSvcExports.Items {
+		internalSvcExport := &internalSvcExports.Items[i]
+		ref := internalSvcExport.Spec.ServiceReference
+		if ref.ClusterID == m.MemberClusterID {
+			continue
+		}
+		// The reference holds the name under which the Service is exported, which may differ from its own name.
+		svcName := ref.Name
+		if internalSvcExport.Spec.LocalServiceName != "" {
+			svcName = internalSvcExport.Spec.LocalServiceName
+		}
+		svcKey := types.NamespacedName{Namespace: ref.Namespace, Name: svcName}
+		linked, err := m.isLinked(ctx, svcKey, ref.UID, &corev1.Service{})
+		if err != nil {
+			klog.ErrorS(err, "Failed to get service", "internalServiceExport", klog.KObj(internalSvcExport))
+			return err
+		}
+		if !linked {
+			summary.Unlinked++
+			continue
+		}
+
+		if m.Mode == ModeAdopt {
+			if err := m.adopt(ctx, internalSvcExport, &internalSvcExport.Spec.ServiceReference); err != nil {
+				return err
+			}
+			summary.AdoptedInternalServiceExports++
+			continue
+		}
+		if err := hubclient.Delete(ctx, m.HubClient, internalSvcExport, "service", svcKey); err != nil {
+			return err
+		}
+		summary.DeletedInternalServiceExports++
+		klog.V(2).InfoS("Deleted internalServiceExport of a previous member cluster ID",
+			"internalServiceExport", klog.KObj(internalSvcExport),
+			"service", svcKey,
+			"previousClusterID", ref.ClusterID)
+	}
+	return nil
+}
+
+// isLinked returns if the object of the member cluster with the key exists and has the given UID; the object is read
+// into obj.
+func (m *Migrator) isLinked(ctx context.Context, key types.NamespacedName, uid types.UID, obj client.Object) (bool, error) {
+	if err := m.MemberClient.Get(ctx, key, obj); err != nil {
+		if apierrors.IsNotFound(err) {
+			return false, nil
+		}
+		return false, err
+	}
+	return obj.GetUID() == uid, nil
+}
+
+// adopt rewrites the member cluster ID in the exported object, whose reference is ref; the object is otherwise left
+// as is, and its fields are taken back by the next apply of the controllers.
+func (m *Migrator) adopt(ctx context.Context, obj client.Object, ref *fleetnetv1alpha1.ExportedObjectReference) error {
+	previousClusterID := ref.ClusterID
+	patch := client.MergeFrom(obj.DeepCopyObject().(client.Object))
+	ref.ClusterID = m.MemberClusterID
+	labels := obj.GetLabels()
+	if labels == nil {
+		labels = map[string]string{}
+	}
+	labels[objectmeta.ExportedObjectLabelMemberCluster] = m.MemberClusterID
+	obj.SetLabels(labels)
+	if err := m.HubClient.Patch(ctx, obj, patch); err != nil {
+		klog.ErrorS(err, "Failed to adopt exported object", "object", klog.KObj(obj), "previousClusterID", previousClusterID)
+		return err
+	}
+	klog.V(2).InfoS("Adopted exported object of a previous member cluster ID", "object", klog.KObj(obj), "previousClusterID", previousClusterID)
+	return nil
+}
@@ -0,0 +1,256 @@
+/*
+Copyright (c) Microsoft Corporation.
+Licensed under the MIT license.
+*/
+
+package exportmigration
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	corev1 "k8s.io/api/core/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	fleetnetv1alpha1 "go.goms.io/fleet-networking/api/v1alpha1"
+	"go.goms.io/fleet-networking/pkg/common/objectmeta"
+)
+
+const (
+	hubNamespace      = "fleet-member-member-2"
+	memberNamespace   = "work"
+	previousClusterID = "member-1"
+	currentClusterID  = "member-2"
+
+	svcName                 = "app"
+	svcUID                  = types.UID("svc-uid")
+	endpointSliceName       = "app-abcde"
+	endpointSliceUID        = types.UID("endpointslice-uid")
+	endpointSliceUniqueName = "member-1-work-app-abcde-12345"
+	internalSvcExportName   = "work-app"
+)
+
+func newFakeClient(objs ...client.Object) client.Client {
+	scheme := runtime.NewScheme()
+	if err := clientgoscheme.AddToScheme(scheme); err != nil {
+		panic(err)
+	}
+	if err := fleetnetv1alpha1.AddToScheme(scheme); err != nil {
+		panic(err)
+	}
+	return fake.NewClientBuilder().WithScheme(scheme).WithObjects(objs...).Build()
+}
+
+func exportedObjectReference(clusterID, name string, uid types.UID) fleetnetv1alpha1.ExportedObjectReference {
+	return fleetnetv1alpha1.ExportedObjectReference{
+		ClusterID:      clusterID,
+		Namespace:      memberNamespace,
+		Name:           name,
+		UID:            uid,
+		NamespacedName: types.NamespacedName{Namespace: memberNamespace, Name: name}.String(),
+	}
+}
+
+func endpointSliceExport(name, clusterID, endpointSliceName string, uid types.UID) *fleetnetv1alpha1.EndpointSliceExport {
+	return &fleetnetv1alpha1.EndpointSliceExport{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: hubNamespace,
+			Name:      name,
+			Labels:    map[string]string{objectmeta.ExportedObjectLabelMemberCluster: clusterID},
+		},
+		Spec: fleetnetv1alpha1.EndpointSliceExportSpec{
+			EndpointSliceReference: exportedObjectReference(clusterID, endpointSliceName, uid),
+		},
+	}
+}
+
+func internalServiceExport(name, clusterID, svcName string, uid types.UID) *fleetnetv1alpha1.InternalServiceExport {
+	return &fleetnetv1alpha1.InternalServiceExport{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: hubNamespace,
+			Name:      name,
+			Labels:    map[string]string{objectmeta.ExportedObjectLabelMemberCluster: clusterID},
+		},
+		Spec: fleetnetv1alpha1.InternalServiceExportSpec{
+			ServiceReference: exportedObjectReference(clusterID, svcName, uid),
+		},
+	}
+}
+
+// memberObjects returns the live Service and EndpointSlice of the member cluster which were exported under the
+// previous member cluster ID.
+func memberObjects() []client.Object {
+	return []client.Object{
+		&corev1.Service{
+			ObjectMeta: metav1.ObjectMeta{Namespace: memberNamespace, Name: svcName, UID: svcUID},
+		},
+		&discoveryv1.EndpointSlice{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace:   memberNamespace,
+				Name:        endpointSliceName,
+				UID:         endpointSliceUID,
+				Annotations: map[string]string{objectmeta.ExportedObjectAnnotationUniqueName: endpointSliceUniqueName},
+			},
+			AddressType: discoveryv1.AddressTypeIPv4,
+		},
+	}
+}
+
+// hubObjects returns the exported objects of the member cluster in the hub cluster: the ones of the live objects
+// exported under the previous member cluster ID, one of an object deleted since, and one already exported under the
+// current ID.
+func hubObjects() []client.Object {
+	return []client.Object{
+		endpointSliceExport(endpointSliceUniqueName, previousClusterID, endpointSliceName, endpointSliceUID),
+		internalServiceExport(internalSvcExportName, previousClusterID, svcName, svcUID),
+		endpointSliceExport("member-1-work-app-fghij-67890", previousClusterID, "app-fghij", "deleted-uid"),
+		internalServiceExport("work-current", currentClusterID, "current", "current-uid"),
+	}
+}
+
+// TestParseMode tests the ParseMode function.
+func TestParseMode(t *testing.T) {
+	testCases := []struct {
+		name    string
+		value   string
+		want    Mode
+		wantErr bool
+	}{
+		{name: "adopt", value: "adopt", want: ModeAdopt},
+		{name: "recreate", value: "recreate", want: ModeRecreate},
+		{name: "invalid", value: "keep", wantErr: true},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := ParseMode(tc.value)
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("ParseMode(%q) got error %v, want error %t", tc.value, err, tc.wantErr)
+			}
+			if got != tc.want {
+				t.Errorf("ParseMode(%q) = %q, want %q", tc.value, got, tc.want)
+			}
+		})
+	}
+}
+
+// TestRun_Adopt tests that the exported objects of the live objects are kept and rewritten with the current member
+// cluster ID in the adopt mode.
+func TestRun_Adopt(t *testing.T) {
+	ctx := context.Background()
+	memberClient := newFakeClient(memberObjects()...)
+	hubClient := newFakeClient(hubObjects()...)
+	m := &Migrator{
+		MemberClient:    memberClient,
+		HubClient:       hubClient,
+		MemberClusterID: currentClusterID,
+		HubNamespaces:   []string{hubNamespace},
+		Mode:            ModeAdopt,
+	}
+	got, err := m.Run(ctx)
+	if err != nil {
+		t.Fatalf("Run() got error %v, want no error", err)
+	}
+	want := Summary{AdoptedEndpointSliceExports: 1, AdoptedInternalServiceExports: 1, Unlinked: 1}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("Run() summary mismatch (-want, +got):\n%s", diff)
+	}
+
+	endpointSliceExport := &fleetnetv1alpha1.EndpointSliceExport{}
+	if err := hubClient.Get(ctx, types.NamespacedName{Namespace: hubNamespace, Name: endpointSliceUniqueName}, endpointSliceExport); err != nil {
+		t.Fatalf("Get() endpointSliceExport got error %v, want no error", err)
+	}
+	if got := endpointSliceExport.Spec.EndpointSliceReference.ClusterID; got != currentClusterID {
+		t.Errorf("endpointSliceExport cluster ID = %q, want %q", got, currentClusterID)
+	}
+	if got := endpointSliceExport.Labels[objectmeta.ExportedObjectLabelMemberCluster]; got != currentClusterID {
+		t.Errorf("endpointSliceExport member cluster label = %q, want %q", got, currentClusterID)
+	}
+	if got := endpointSliceExport.Spec.EndpointSliceReference.UID; got != endpointSliceUID {
+		t.Errorf("endpointSliceExport UID = %q, want %q", got, endpointSliceUID)
+	}
+
+	internalSvcExport := &fleetnetv1alpha1.InternalServiceExport{}
+	if err := hubClient.Get(ctx, types.NamespacedName{Namespace: hubNamespace, Name: internalSvcExportName}, internalSvcExport); err != nil {
+		t.Fatalf("Get() internalServiceExport got error %v, want no error", err)
+	}
+	if got := internalSvcExport.Spec.ServiceReference.ClusterID; got != currentClusterID {
+		t.Errorf("internalServiceExport cluster ID = %q, want %q", got, currentClusterID)
+	}
+	if got := internalSvcExport.Labels[objectmeta.ExportedObjectLabelMemberCluster]; got != currentClusterID {
+		t.Errorf("internalServiceExport member cluster label = %q, want %q", got, currentClusterID)
+	}
+
+	// The unique name is kept, so that the EndpointSlice stays linked with the adopted EndpointSliceExport.
+	endpointSlice := &discoveryv1.EndpointSlice{}
+	if err := memberClient.Get(ctx, types.NamespacedName{Namespace: memberNamespace, Name: endpointSliceName}, endpointSlice); err != nil {
+		t.Fatalf("Get() endpointSlice got error %v, want no error", err)
+	}
+	if got := endpointSlice.Annotations[objectmeta.ExportedObjectAnnotationUniqueName]; got != endpointSliceUniqueName {
+		t.Errorf("endpointSlice unique name = %q, want %q", got, endpointSliceUniqueName)
+	}
+
+	// The export of the deleted EndpointSlice is left to the endpointsliceexport controller.
+	unlinked := &fleetnetv1alpha1.EndpointSliceExport{}
+	if err := hubClient.Get(ctx, types.NamespacedName{Namespace: hubNamespace, Name: "member-1-work-app-fghij-67890"}, unlinked); err != nil {
+		t.Fatalf("Get() unlinked endpointSliceExport got error %v, want no error", err)
+	}
+	if got := unlinked.Spec.EndpointSliceReference.ClusterID; got != previousClusterID {
+		t.Errorf("unlinked endpointSliceExport cluster ID = %q, want %q", got, previousClusterID)
+	}
+}
+
+// TestRun_Recreate tests that the exported objects of the live objects are deleted, and the unique names of the
+// EndpointSlices are removed, in the recreate mode.
+func TestRun_Recreate(t *testing.T) {
+	ctx := context.Background()
+	memberClient := newFakeClient(memberObjects()...)
+	hubClient := newFakeClient(hubObjects()...)
+	m := &Migrator{
+		MemberClient:    memberClient,
+		HubClient:       hubClient,
+		MemberClusterID: currentClusterID,
+		HubNamespaces:   []string{hubNamespace},
+		Mode:            ModeRecreate,
+	}
+	got, err := m.Run(ctx)
+	if err != nil {
+		t.Fatalf("Run() got error %v, want no error", err)
+	}
+	want := Summary{DeletedEndpointSliceExports: 1, DeletedInternalServiceExports: 1, Unlinked: 1}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("Run() summary mismatch (-want, +got):\n%s", diff)
+	}
+
+	err = hubClient.Get(ctx, types.NamespacedName{Namespace: hubNamespace, Name: endpointSliceUniqueName}, &fleetnetv1alpha1.EndpointSliceExport{})
+	if !apierrors.IsNotFound(err) {
+		t.Errorf("Get() endpointSliceExport got error %v, want not found", err)
+	}
+	err = hubClient.Get(ctx, types.NamespacedName{Namespace: hubNamespace, Name: internalSvcExportName}, &fleetnetv1alpha1.InternalServiceExport{})
+	if !apierrors.IsNotFound(err) {
+		t.Errorf("Get() internalServiceExport got error %v, want not found", err)
+	}
+
+	endpointSlice := &discoveryv1.EndpointSlice{}
+	if err := memberClient.Get(ctx, types.NamespacedName{Namespace: memberNamespace, Name: endpointSliceName}, endpointSlice); err != nil {
+		t.Fatalf("Get() endpointSlice got error %v, want no error", err)
+	}
+	if got, ok := endpointSlice.Annotations[objectmeta.ExportedObjectAnnotationUniqueName]; ok {
+		t.Errorf("endpointSlice unique name = %q, want none", got)
+	}
+
+	// The objects which are not linked, or are exported under the current ID, are left untouched.
+	if err := hubClient.Get(ctx, types.NamespacedName{Namespace: hubNamespace, Name: "member-1-work-app-fghij-67890"}, &fleetnetv1alpha1.EndpointSliceExport{}); err != nil {
+		t.Errorf("Get() unlinked endpointSliceExport got error %v, want no error", err)
+	}
+	if err := hubClient.Get(ctx, types.NamespacedName{Namespace: hubNamespace, Name: "work-current"}, &fleetnetv1alpha1.InternalServiceExport{}); err != nil {
+		t.Errorf("Get() current internalServiceExport got error %v, want no error", err)
+	}
+}
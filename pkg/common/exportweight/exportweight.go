@@ -0,0 +1,48 @@
+/*
+Copyright (c) Microsoft Corporation.
+Licensed under the MIT license.
+*/
+
+// Package exportweight features the helpers to record the weights of the exported services, as set by the exporting
+// clusters, in the ServiceImport status.
+package exportweight
+
+import (
+	"slices"
+
+	fleetnetv1alpha1 "go.goms.io/fleet-networking/api/v1alpha1"
+)
+
+// Find returns the weight of the service exported from the given cluster, or nil if the cluster does not set one.
+func Find(status *fleetnetv1alpha1.ServiceImportStatus, cluster string) *int64 {
+	for i := range status.ClusterWeights {
+		if status.ClusterWeights[i].Cluster == cluster {
+			return &status.ClusterWeights[i].Weight
+		}
+	}
+	return nil
+}
+
+// Set records the weight of the service exported from the given cluster in the serviceImport status; a nil weight
+// removes the record.
+func Set(status *fleetnetv1alpha1.ServiceImportStatus, cluster string, weight *int64) {
+	if weight == nil {
+		status.ClusterWeights = slices.DeleteFunc(status.ClusterWeights, func(w fleetnetv1alpha1.ClusterWeight) bool {
+			return w.Cluster == cluster
+		})
+		if len(status.ClusterWeights) == 0 {
+			status.ClusterWeights = nil
+		}
+		return
+	}
+	for i := range status.ClusterWeights {
+		if status.ClusterWeights[i].Cluster == cluster {
+			status.ClusterWeights[i].Weight = *weight
+			return
+		}
+	}
+	status.ClusterWeights = append(status.ClusterWeights, fleetnetv1alpha1.ClusterWeight{
+		Cluster: cluster,
+		Weight:  *weight,
+	})
+}
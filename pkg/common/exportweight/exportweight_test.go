@@ -0,0 +1,80 @@
+/*
+Copyright (c) Microsoft Corporation.
+Licensed under the MIT license.
+*/
+
+package exportweight
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"k8s.io/utils/ptr"
+
+	fleetnetv1alpha1 "go.goms.io/fleet-networking/api/v1alpha1"
+)
+
+const (
+	memberClusterA = "member-a"
+	memberClusterB = "member-b"
+)
+
+func TestSet(t *testing.T) {
+	testCases := []struct {
+		name    string
+		weights []fleetnetv1alpha1.ClusterWeight
+		cluster string
+		weight  *int64
+		want    []fleetnetv1alpha1.ClusterWeight
+	}{
+		{
+			name:    "add a weight",
+			cluster: memberClusterA,
+			weight:  ptr.To(int64(3)),
+			want:    []fleetnetv1alpha1.ClusterWeight{{Cluster: memberClusterA, Weight: 3}},
+		},
+		{
+			name: "update a weight in place",
+			weights: []fleetnetv1alpha1.ClusterWeight{
+				{Cluster: memberClusterA, Weight: 3},
+				{Cluster: memberClusterB, Weight: 2},
+			},
+			cluster: memberClusterA,
+			weight:  ptr.To(int64(0)),
+			want: []fleetnetv1alpha1.ClusterWeight{
+				{Cluster: memberClusterA, Weight: 0},
+				{Cluster: memberClusterB, Weight: 2},
+			},
+		},
+		{
+			name: "remove a weight",
+			weights: []fleetnetv1alpha1.ClusterWeight{
+				{Cluster: memberClusterA, Weight: 3},
+				{Cluster: memberClusterB, Weight: 2},
+			},
+			cluster: memberClusterA,
+			want:    []fleetnetv1alpha1.ClusterWeight{{Cluster: memberClusterB, Weight: 2}},
+		},
+		{
+			name:    "remove the last weight",
+			weights: []fleetnetv1alpha1.ClusterWeight{{Cluster: memberClusterA, Weight: 3}},
+			cluster: memberClusterA,
+		},
+		{
+			name:    "remove a weight which does not exist",
+			cluster: memberClusterA,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			status := &fleetnetv1alpha1.ServiceImportStatus{ClusterWeights: tc.weights}
+			Set(status, tc.cluster, tc.weight)
+			if diff := cmp.Diff(tc.want, status.ClusterWeights); diff != "" {
+				t.Errorf("Set() mismatch (-want, +got):\n%s", diff)
+			}
+			if got := Find(status, tc.cluster); !cmp.Equal(got, tc.weight) {
+				t.Errorf("Find() = %v, want %v", got, tc.weight)
+			}
+		})
+	}
+}
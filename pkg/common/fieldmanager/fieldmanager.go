@@ -0,0 +1,45 @@
+/*
+Copyright (c) Microsoft Corporation.
+Licensed under the MIT license.
+*/
+
+// Package fieldmanager provides the helpers for the networking controllers moving from client-side updates to
+// server-side apply.
+package fieldmanager
+
+import (
+	"context"
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/client-go/util/csaupgrade"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// UpgradeManagedFields transfers the ownership of the fields an object got from the given client-side (i.e., Update)
+// field managers to the server-side apply field manager, so that the fields which the server-side apply manager
+// stops setting later are removed, instead of being kept under the ownership of the old managers forever.
+//
+// The object is patched only if any of its managed fields entries belongs to the old managers; the patch is guarded
+// by the resource version of the given object.
+func UpgradeManagedFields(ctx context.Context, c client.Client, obj client.Object, ssaManager string, csaManagers ...string) error {
+	return upgradeManagedFields(ctx, c, obj, ssaManager, csaManagers)
+}
+
+// UpgradeStatusManagedFields is UpgradeManagedFields for the fields the given client-side field managers updated
+// through the status subresource of an object.
+func UpgradeStatusManagedFields(ctx context.Context, c client.Client, obj client.Object, ssaManager string, csaManagers ...string) error {
+	return upgradeManagedFields(ctx, c, obj, ssaManager, csaManagers, csaupgrade.Subresource("status"))
+}
+
+func upgradeManagedFields(ctx context.Context, c client.Client, obj client.Object, ssaManager string, csaManagers []string, opts ...csaupgrade.Option) error {
+	patch, err := csaupgrade.UpgradeManagedFieldsPatch(obj, sets.New(csaManagers...), ssaManager, opts...)
+	if err != nil {
+		return fmt.Errorf("failed to build the managed fields upgrade patch: %w", err)
+	}
+	if patch == nil {
+		return nil
+	}
+	return c.Patch(ctx, obj, client.RawPatch(types.JSONPatchType, patch))
+}
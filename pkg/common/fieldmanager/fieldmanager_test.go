@@ -0,0 +1,160 @@
+/*
+Copyright (c) Microsoft Corporation.
+Licensed under the MIT license.
+*/
+
+package fieldmanager
+
+import (
+	"context"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+)
+
+const (
+	ssaManager = "ssa-manager"
+	csaManager = "csa-manager"
+)
+
+func TestUpgradeManagedFields(t *testing.T) {
+	fieldsV1 := &metav1.FieldsV1{Raw: []byte(`{"f:data":{"f:key":{}}}`)}
+	tests := []struct {
+		name          string
+		managedFields []metav1.ManagedFieldsEntry
+		wantPatch     bool
+	}{
+		{
+			name: "no managed fields",
+		},
+		{
+			name: "fields managed by the server-side apply manager only",
+			managedFields: []metav1.ManagedFieldsEntry{
+				{
+					Manager:    ssaManager,
+					Operation:  metav1.ManagedFieldsOperationApply,
+					APIVersion: "v1",
+					FieldsType: "FieldsV1",
+					FieldsV1:   fieldsV1,
+				},
+			},
+		},
+		{
+			name: "fields updated by another manager",
+			managedFields: []metav1.ManagedFieldsEntry{
+				{
+					Manager:    "other-manager",
+					Operation:  metav1.ManagedFieldsOperationUpdate,
+					APIVersion: "v1",
+					FieldsType: "FieldsV1",
+					FieldsV1:   fieldsV1,
+				},
+			},
+		},
+		{
+			name: "fields updated by the client-side manager",
+			managedFields: []metav1.ManagedFieldsEntry{
+				{
+					Manager:    csaManager,
+					Operation:  metav1.ManagedFieldsOperationUpdate,
+					APIVersion: "v1",
+					FieldsType: "FieldsV1",
+					FieldsV1:   fieldsV1,
+				},
+			},
+			wantPatch: true,
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			obj := &corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace:     "default",
+					Name:          "config",
+					ManagedFields: tc.managedFields,
+				},
+				Data: map[string]string{"key": "value"},
+			}
+			var gotPatchType types.PatchType
+			fakeClient := fake.NewClientBuilder().
+				WithObjects(obj.DeepCopy()).
+				WithInterceptorFuncs(interceptor.Funcs{
+					Patch: func(ctx context.Context, c client.WithWatch, obj client.Object, patch client.Patch, opts ...client.PatchOption) error {
+						gotPatchType = patch.Type()
+						return nil
+					},
+				}).
+				Build()
+
+			if err := UpgradeManagedFields(context.Background(), fakeClient, obj, ssaManager, csaManager); err != nil {
+				t.Fatalf("UpgradeManagedFields() = %v, want no error", err)
+			}
+			if gotPatch := gotPatchType != ""; gotPatch != tc.wantPatch {
+				t.Errorf("UpgradeManagedFields() patched the object: %t, want %t", gotPatch, tc.wantPatch)
+			}
+			if tc.wantPatch && gotPatchType != types.JSONPatchType {
+				t.Errorf("UpgradeManagedFields() patch type = %s, want %s", gotPatchType, types.JSONPatchType)
+			}
+		})
+	}
+}
+
+func TestUpgradeStatusManagedFields(t *testing.T) {
+	obj := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "default",
+			Name:      "config",
+			ManagedFields: []metav1.ManagedFieldsEntry{
+				{
+					Manager:     csaManager,
+					Operation:   metav1.ManagedFieldsOperationUpdate,
+					APIVersion:  "v1",
+					FieldsType:  "FieldsV1",
+					FieldsV1:    &metav1.FieldsV1{Raw: []byte(`{"f:status":{"f:key":{}}}`)},
+					Subresource: "status",
+				},
+			},
+		},
+	}
+	tests := []struct {
+		name      string
+		upgrade   func(ctx context.Context, c client.Client, obj client.Object, ssaManager string, csaManagers ...string) error
+		wantPatch bool
+	}{
+		{
+			name:    "main resource upgrade skips the status fields",
+			upgrade: UpgradeManagedFields,
+		},
+		{
+			name:      "status subresource upgrade takes over the status fields",
+			upgrade:   UpgradeStatusManagedFields,
+			wantPatch: true,
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			patched := false
+			fakeClient := fake.NewClientBuilder().
+				WithObjects(obj.DeepCopy()).
+				WithInterceptorFuncs(interceptor.Funcs{
+					Patch: func(ctx context.Context, c client.WithWatch, obj client.Object, patch client.Patch, opts ...client.PatchOption) error {
+						patched = true
+						return nil
+					},
+				}).
+				Build()
+
+			if err := tc.upgrade(context.Background(), fakeClient, obj.DeepCopy(), ssaManager, csaManager); err != nil {
+				t.Fatalf("upgrade() = %v, want no error", err)
+			}
+			if patched != tc.wantPatch {
+				t.Errorf("upgrade() patched the object: %t, want %t", patched, tc.wantPatch)
+			}
+		})
+	}
+}
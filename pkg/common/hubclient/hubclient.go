@@ -0,0 +1,145 @@
+/*
+Copyright (c) Microsoft Corporation.
+Licensed under the MIT license.
+*/
+
+// Package hubclient provides the helpers shared between the member controllers to write the objects they export to
+// the hub cluster, so that the conflict and not found errors are handled, wrapped and logged the same way.
+//
+// The exported objects are written with server-side apply, with the member agent as the field manager; the objects
+// passed in must be built from the desired state only, so that the fields added by the hub side are left untouched.
+package hubclient
+
+import (
+	"context"
+	"fmt"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"go.goms.io/fleet/pkg/utils/controller"
+
+	fleetnetv1alpha1 "go.goms.io/fleet-networking/api/v1alpha1"
+	"go.goms.io/fleet-networking/pkg/common/objectmeta"
+	"go.goms.io/fleet-networking/pkg/common/rejectiondiff"
+)
+
+// CreateOrUpdateEndpointSliceExport creates or updates the EndpointSliceExport in the hub cluster; the key/value pairs
+// identify the exported EndpointSlice in the logs.
+func CreateOrUpdateEndpointSliceExport(ctx context.Context, hubClient client.Client, endpointSliceExport *fleetnetv1alpha1.EndpointSliceExport, keysAndValues ...any) error {
+	return apply(ctx, hubClient, endpointSliceExport, "endpointSliceExport", keysAndValues)
+}
+
+// EnsureInternalServiceExport creates or updates the InternalServiceExport in the hub cluster; the key/value pairs
+// identify the exported Service in the logs.
+func EnsureInternalServiceExport(ctx context.Context, hubClient client.Client, internalSvcExport *fleetnetv1alpha1.InternalServiceExport, keysAndValues ...any) error {
+	return apply(ctx, hubClient, internalSvcExport, "internalServiceExport", keysAndValues)
+}
+
+// DeleteIfLinked deletes the exported object (an EndpointSliceExport or an InternalServiceExport) with the namespace
+// and the name of obj from the hub cluster, if the reference of the object it exports has the given UID; the object
+// is read into obj. It returns false, without error, if the object is not found or is linked with another object,
+// e.g. after a unique name has been assigned to more than one object.
+func DeleteIfLinked(ctx context.Context, hubClient client.Client, obj client.Object, uid types.UID, keysAndValues ...any) (bool, error) {
+	key := client.ObjectKeyFromObject(obj)
+	kind := objectKind(obj)
+	if err := hubClient.Get(ctx, key, obj); err != nil {
+		if apierrors.IsNotFound(err) {
+			klog.V(4).InfoS("Exported object is not found; skip the deletion", append([]any{kind, key}, keysAndValues...)...)
+			return false, nil
+		}
+		klog.ErrorS(err, "Failed to get exported object", append([]any{kind, key}, keysAndValues...)...)
+		return false, controller.NewAPIServerError(false, err)
+	}
+	ref, err := exportedObjectReference(obj)
+	if err != nil {
+		return false, err
+	}
+	if ref.UID != uid {
+		klog.V(2).InfoS("Exported object is linked with another object; skip the deletion",
+			append([]any{kind, key, "linkedUID", ref.UID, "uid", uid}, keysAndValues...)...)
+		return false, nil
+	}
+	if err := Delete(ctx, hubClient, obj, keysAndValues...); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// Delete deletes the exported object from the hub cluster; an object which is not found is considered deleted.
+func Delete(ctx context.Context, hubClient client.Client, obj client.Object, keysAndValues ...any) error {
+	kind := objectKind(obj)
+	if err := hubClient.Delete(ctx, obj); err != nil && !apierrors.IsNotFound(err) {
+		klog.ErrorS(err, "Failed to delete exported object", append([]any{kind, klog.KObj(obj)}, keysAndValues...)...)
+		return controller.NewAPIServerError(false, err)
+	}
+	return nil
+}
+
+// apply creates or updates the object in the hub cluster with server-side apply, taking over the fields owned by the
+// other field managers. When the hub cluster rejects the object as invalid, the returned error summarizes the diff
+// between the spec of the object in the hub cluster and the attempted one.
+func apply(ctx context.Context, hubClient client.Client, obj client.Object, kind string, keysAndValues []any) error {
+	if err := hubClient.Patch(ctx, obj, client.Apply,
+		client.FieldOwner(objectmeta.MemberAgentFieldManager), client.ForceOwnership); err != nil {
+		klog.ErrorS(err, "Failed to apply exported object", append([]any{kind, klog.KObj(obj)}, keysAndValues...)...)
+		lastSpec := func() any { return lastKnownSpec(ctx, hubClient, obj) }
+		err = rejectiondiff.Explain(err, kind, lastSpec, objectSpec(obj), append([]any{kind, klog.KObj(obj)}, keysAndValues...)...)
+		return controller.NewUpdateIgnoreConflictError(err)
+	}
+	return nil
+}
+
+// lastKnownSpec returns the spec of the object in the hub cluster, or nil if it cannot be read, e.g. when it has not
+// been created yet.
+func lastKnownSpec(ctx context.Context, hubClient client.Client, obj client.Object) any {
+	last, ok := obj.DeepCopyObject().(client.Object)
+	if !ok {
+		return nil
+	}
+	if err := hubClient.Get(ctx, client.ObjectKeyFromObject(obj), last); err != nil {
+		klog.V(2).InfoS("Failed to get the exported object to diff the rejected write", "object", klog.KObj(obj), "error", err)
+		return nil
+	}
+	return objectSpec(last)
+}
+
+// objectSpec returns the spec of the EndpointSliceExport or the InternalServiceExport, or the object itself for any
+// other type.
+func objectSpec(obj client.Object) any {
+	switch o := obj.(type) {
+	case *fleetnetv1alpha1.EndpointSliceExport:
+		return &o.Spec
+	case *fleetnetv1alpha1.InternalServiceExport:
+		return &o.Spec
+	default:
+		return obj
+	}
+}
+
+// objectKind returns the logging key of the exported object.
+func objectKind(obj client.Object) string {
+	switch obj.(type) {
+	case *fleetnetv1alpha1.EndpointSliceExport:
+		return "endpointSliceExport"
+	case *fleetnetv1alpha1.InternalServiceExport:
+		return "internalServiceExport"
+	default:
+		return "object"
+	}
+}
+
+// exportedObjectReference returns the reference of the object exported by the EndpointSliceExport or the
+// InternalServiceExport.
+func exportedObjectReference(obj client.Object) (*fleetnetv1alpha1.ExportedObjectReference, error) {
+	switch o := obj.(type) {
+	case *fleetnetv1alpha1.EndpointSliceExport:
+		return &o.Spec.EndpointSliceReference, nil
+	case *fleetnetv1alpha1.InternalServiceExport:
+		return &o.Spec.ServiceReference, nil
+	default:
+		return nil, controller.NewUnexpectedBehaviorError(fmt.Errorf("object %s of type %T does not export an object", klog.KObj(obj), obj))
+	}
+}
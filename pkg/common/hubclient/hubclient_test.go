@@ -0,0 +1,303 @@
+/*
+Copyright (c) Microsoft Corporation.
+Licensed under the MIT license.
+*/
+
+package hubclient
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+
+	"go.goms.io/fleet/pkg/utils/controller"
+
+	fleetnetv1alpha1 "go.goms.io/fleet-networking/api/v1alpha1"
+	"go.goms.io/fleet-networking/pkg/common/objectmeta"
+)
+
+const (
+	hubNamespace = "fleet-member-member-1"
+	exportName   = "app-svc"
+	linkedUID    = types.UID("linked-uid")
+	otherUID     = types.UID("other-uid")
+)
+
+var (
+	endpointSliceExportGR   = schema.GroupResource{Group: fleetnetv1alpha1.GroupVersion.Group, Resource: "endpointsliceexports"}
+	internalSvcExportGR     = schema.GroupResource{Group: fleetnetv1alpha1.GroupVersion.Group, Resource: "internalserviceexports"}
+	errConflict             = apierrors.NewConflict(endpointSliceExportGR, exportName, nil)
+	errInternalSvcNotFound  = apierrors.NewNotFound(internalSvcExportGR, exportName)
+	errEndpointSliceMissing = apierrors.NewNotFound(endpointSliceExportGR, exportName)
+)
+
+func newFakeHubClient(funcs interceptor.Funcs, objs ...client.Object) client.Client {
+	scheme := runtime.NewScheme()
+	if err := fleetnetv1alpha1.AddToScheme(scheme); err != nil {
+		panic(err)
+	}
+	return fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithObjects(objs...).
+		WithInterceptorFuncs(funcs).
+		Build()
+}
+
+func endpointSliceExport(uid types.UID) *fleetnetv1alpha1.EndpointSliceExport {
+	return &fleetnetv1alpha1.EndpointSliceExport{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: hubNamespace,
+			Name:      exportName,
+		},
+		Spec: fleetnetv1alpha1.EndpointSliceExportSpec{
+			EndpointSliceReference: fleetnetv1alpha1.ExportedObjectReference{UID: uid},
+		},
+	}
+}
+
+func internalServiceExport(uid types.UID) *fleetnetv1alpha1.InternalServiceExport {
+	return &fleetnetv1alpha1.InternalServiceExport{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: hubNamespace,
+			Name:      exportName,
+		},
+		Spec: fleetnetv1alpha1.InternalServiceExportSpec{
+			ServiceReference: fleetnetv1alpha1.ExportedObjectReference{UID: uid},
+		},
+	}
+}
+
+// applyInterceptor returns the interceptor which records the options of the apply patches and fails them with
+// patchErr, as the fake client does not support server-side apply.
+func applyInterceptor(patchErr error, gotOpts *client.PatchOptions) interceptor.Funcs {
+	return interceptor.Funcs{
+		Patch: func(_ context.Context, _ client.WithWatch, _ client.Object, patch client.Patch, opts ...client.PatchOption) error {
+			if patch.Type() != types.ApplyPatchType {
+				return apierrors.NewBadRequest("not an apply patch")
+			}
+			gotOpts.ApplyOptions(opts)
+			return patchErr
+		},
+	}
+}
+
+// TestApply tests the CreateOrUpdateEndpointSliceExport and EnsureInternalServiceExport functions.
+func TestApply(t *testing.T) {
+	testCases := []struct {
+		name     string
+		patchErr error
+		apply    func(ctx context.Context, hubClient client.Client) error
+	}{
+		{
+			name: "endpointSliceExport is applied",
+			apply: func(ctx context.Context, hubClient client.Client) error {
+				return CreateOrUpdateEndpointSliceExport(ctx, hubClient, endpointSliceExport(linkedUID))
+			},
+		},
+		{
+			name:     "endpointSliceExport apply conflicts",
+			patchErr: errConflict,
+			apply: func(ctx context.Context, hubClient client.Client) error {
+				return CreateOrUpdateEndpointSliceExport(ctx, hubClient, endpointSliceExport(linkedUID))
+			},
+		},
+		{
+			name: "internalServiceExport is applied",
+			apply: func(ctx context.Context, hubClient client.Client) error {
+				return EnsureInternalServiceExport(ctx, hubClient, internalServiceExport(linkedUID), "service", "work/app")
+			},
+		},
+		{
+			name:     "internalServiceExport apply fails",
+			patchErr: errInternalSvcNotFound,
+			apply: func(ctx context.Context, hubClient client.Client) error {
+				return EnsureInternalServiceExport(ctx, hubClient, internalServiceExport(linkedUID), "service", "work/app")
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			gotOpts := &client.PatchOptions{}
+			hubClient := newFakeHubClient(applyInterceptor(tc.patchErr, gotOpts))
+			err := tc.apply(context.Background(), hubClient)
+			switch {
+			case tc.patchErr == nil && err != nil:
+				t.Fatalf("apply, got %v, want no error", err)
+			case tc.patchErr != nil && err == nil:
+				t.Fatalf("apply, got no error, want %v", tc.patchErr)
+			case apierrors.IsConflict(tc.patchErr) && !errors.Is(err, controller.ErrExpectedBehavior):
+				t.Fatalf("apply, got %v, want an expected behavior error", err)
+			case apierrors.IsNotFound(tc.patchErr) && !errors.Is(err, controller.ErrAPIServerError):
+				t.Fatalf("apply, got %v, want an API server error", err)
+			}
+			if gotOpts.FieldManager != objectmeta.MemberAgentFieldManager {
+				t.Errorf("apply field manager, got %q, want %q", gotOpts.FieldManager, objectmeta.MemberAgentFieldManager)
+			}
+			if gotOpts.Force == nil || !*gotOpts.Force {
+				t.Errorf("apply force ownership, got %v, want true", gotOpts.Force)
+			}
+		})
+	}
+}
+
+// TestApply_Rejected tests that the error of a write rejected as invalid summarizes the diff with the object in the hub
+// cluster.
+func TestApply_Rejected(t *testing.T) {
+	errInvalid := apierrors.NewInvalid(schema.GroupKind{Group: fleetnetv1alpha1.GroupVersion.Group, Kind: "EndpointSliceExport"},
+		exportName, field.ErrorList{field.Forbidden(field.NewPath("spec", "endpointSliceReference", "uid"), "immutable")})
+	hubClient := newFakeHubClient(applyInterceptor(errInvalid, &client.PatchOptions{}), endpointSliceExport(linkedUID))
+	err := CreateOrUpdateEndpointSliceExport(context.Background(), hubClient, endpointSliceExport(otherUID))
+	if !errors.Is(err, controller.ErrAPIServerError) {
+		t.Fatalf("CreateOrUpdateEndpointSliceExport() = %v, want an API server error", err)
+	}
+	if !strings.Contains(err.Error(), "spec diff: 2 changed lines") || !strings.Contains(err.Error(), string(linkedUID)) {
+		t.Errorf("CreateOrUpdateEndpointSliceExport() = %v, want the summary of the diff of the UIDs", err)
+	}
+}
+
+// TestDeleteIfLinked tests the DeleteIfLinked function.
+func TestDeleteIfLinked(t *testing.T) {
+	testCases := []struct {
+		name        string
+		existing    client.Object
+		obj         client.Object
+		deleteErr   error
+		wantDeleted bool
+		wantExists  bool
+		wantErr     bool
+	}{
+		{
+			name:        "linked endpointSliceExport is deleted",
+			existing:    endpointSliceExport(linkedUID),
+			obj:         endpointSliceExport(""),
+			wantDeleted: true,
+		},
+		{
+			name:        "linked internalServiceExport is deleted",
+			existing:    internalServiceExport(linkedUID),
+			obj:         internalServiceExport(""),
+			wantDeleted: true,
+		},
+		{
+			name: "not found endpointSliceExport is skipped",
+			obj:  endpointSliceExport(""),
+		},
+		{
+			name:       "endpointSliceExport linked with another endpointSlice is kept",
+			existing:   endpointSliceExport(otherUID),
+			obj:        endpointSliceExport(""),
+			wantExists: true,
+		},
+		{
+			name:       "internalServiceExport linked with another service is kept",
+			existing:   internalServiceExport(otherUID),
+			obj:        internalServiceExport(""),
+			wantExists: true,
+		},
+		{
+			name:        "endpointSliceExport deleted concurrently",
+			existing:    endpointSliceExport(linkedUID),
+			obj:         endpointSliceExport(""),
+			deleteErr:   errEndpointSliceMissing,
+			wantDeleted: true,
+			wantExists:  true,
+		},
+		{
+			name:       "endpointSliceExport delete conflicts",
+			existing:   endpointSliceExport(linkedUID),
+			obj:        endpointSliceExport(""),
+			deleteErr:  errConflict,
+			wantExists: true,
+			wantErr:    true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx := context.Background()
+			var objs []client.Object
+			if tc.existing != nil {
+				objs = append(objs, tc.existing)
+			}
+			funcs := interceptor.Funcs{}
+			if tc.deleteErr != nil {
+				funcs.Delete = func(_ context.Context, _ client.WithWatch, _ client.Object, _ ...client.DeleteOption) error {
+					return tc.deleteErr
+				}
+			}
+			hubClient := newFakeHubClient(funcs, objs...)
+
+			deleted, err := DeleteIfLinked(ctx, hubClient, tc.obj, linkedUID, "endpointSlice", "work/app-1")
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("DeleteIfLinked(), got %v, want error %t", err, tc.wantErr)
+			}
+			if tc.wantErr && !errors.Is(err, controller.ErrAPIServerError) {
+				t.Errorf("DeleteIfLinked(), got %v, want an API server error", err)
+			}
+			if deleted != tc.wantDeleted {
+				t.Errorf("DeleteIfLinked(), got deleted %t, want %t", deleted, tc.wantDeleted)
+			}
+
+			key := client.ObjectKeyFromObject(tc.obj)
+			err = hubClient.Get(ctx, key, tc.obj)
+			if gotExists := err == nil; gotExists != tc.wantExists {
+				t.Errorf("exported object Get(%v), got %v, want exists %t", key, err, tc.wantExists)
+			}
+		})
+	}
+}
+
+// TestDelete tests the Delete function.
+func TestDelete(t *testing.T) {
+	testCases := []struct {
+		name      string
+		existing  client.Object
+		deleteErr error
+		wantErr   bool
+	}{
+		{
+			name:     "internalServiceExport is deleted",
+			existing: internalServiceExport(linkedUID),
+		},
+		{
+			name: "not found internalServiceExport is considered deleted",
+		},
+		{
+			name:      "delete fails",
+			existing:  internalServiceExport(linkedUID),
+			deleteErr: errConflict,
+			wantErr:   true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var objs []client.Object
+			if tc.existing != nil {
+				objs = append(objs, tc.existing)
+			}
+			funcs := interceptor.Funcs{}
+			if tc.deleteErr != nil {
+				funcs.Delete = func(_ context.Context, _ client.WithWatch, _ client.Object, _ ...client.DeleteOption) error {
+					return tc.deleteErr
+				}
+			}
+			hubClient := newFakeHubClient(funcs, objs...)
+			if err := Delete(context.Background(), hubClient, internalServiceExport("")); (err != nil) != tc.wantErr {
+				t.Fatalf("Delete(), got %v, want error %t", err, tc.wantErr)
+			}
+		})
+	}
+}
@@ -8,15 +8,20 @@ package hubconfig
 
 import (
 	"bufio"
+	"crypto/sha256"
 	"encoding/base64"
+	"encoding/hex"
 	"errors"
 	"fmt"
+	"hash/fnv"
 	"io"
 	"net/http"
 	"net/textproto"
 	"os"
+	"path/filepath"
 	"strings"
 
+	"k8s.io/apimachinery/pkg/util/validation"
 	"k8s.io/client-go/rest"
 	"k8s.io/client-go/util/retry"
 	"k8s.io/klog/v2"
@@ -31,18 +36,35 @@ const (
 	tokenConfigPathEnvKey = "CONFIG_PATH" //nolint:gosec
 	hubCAEnvKey           = "HUB_CERTIFICATE_AUTHORITY"
 	hubKubeHeaderEnvKey   = "HUB_KUBE_HEADER"
+	hubConfigDirEnvKey    = "HUB_CONFIG_DIR"
+
+	// The files of the hub config directory, e.g. the keys of a mounted Secret; the CA is PEM encoded.
+	hubConfigServerFile = "server"
+	hubConfigCAFile     = "ca.crt"
+
+	// redactedValue replaces the values of the custom headers when the hub config is logged.
+	redactedValue = "REDACTED"
+	// fingerprintLength is the number of the hex characters of the fingerprint of a hub config.
+	fingerprintLength = 12
 
 	// Naming pattern of member cluster namespace in hub cluster, should be the same as envValue as defined in
 	// https://github.com/Azure/fleet/blob/main/pkg/utils/common.go
 	HubNamespaceNameFormat = "fleet-member-%s"
+
+	// HubShardNamespaceNameFormat is the naming pattern of the additional namespaces in the hub cluster across which
+	// the exports of a member cluster are sharded, derived from the member cluster namespace and the shard index; the
+	// first shard is the member cluster namespace itself.
+	HubShardNamespaceNameFormat = "%s-shard-%d"
 )
 
 // PrepareHubConfig return the config holding attributes for a Kubernetes client to request hub cluster.
 // Called must make sure all required environment variables are well set.
+//
+// The hub server URL and CA are read from the files of the hub config directory instead of the environment variables
+// when the HUB_CONFIG_DIR environment variable is set, so that they can be rotated without a restart (see Rotator).
 func PrepareHubConfig(tlsClientInsecure bool) (*rest.Config, error) {
-	hubURL, err := env.Lookup(hubServerURLEnvKey)
+	hubURL, caData, err := lookupHubEndpoint()
 	if err != nil {
-		klog.ErrorS(err, "Hub cluster endpoint URL cannot be empty")
 		return nil, err
 	}
 
@@ -60,8 +82,8 @@ func PrepareHubConfig(tlsClientInsecure bool) (*rest.Config, error) {
 		_, err := os.Stat(tokenFilePath)
 		return err
 	}); err != nil {
-		klog.ErrorS(err, "Cannot retrieve token file from the path %s", tokenFilePath)
-		return nil, err
+		klog.ErrorS(err, "Cannot retrieve token file", "path", tokenFilePath)
+		return nil, fmt.Errorf("failed to retrieve the token file %s set by the environment variable %s: %w", tokenFilePath, tokenConfigPathEnvKey, err)
 	}
 	var hubConfig *rest.Config
 	if tlsClientInsecure {
@@ -73,15 +95,6 @@ func PrepareHubConfig(tlsClientInsecure bool) (*rest.Config, error) {
 			},
 		}
 	} else {
-		var caData []byte
-		hubCA, err := env.Lookup(hubCAEnvKey)
-		if err == nil {
-			caData, err = base64.StdEncoding.DecodeString(hubCA)
-			if err != nil {
-				klog.ErrorS(err, "Cannot decode hub cluster certificate authority data")
-				return nil, err
-			}
-		}
 		hubConfig = &rest.Config{
 			BearerTokenFile: tokenFilePath,
 			Host:            hubURL,
@@ -94,26 +107,187 @@ func PrepareHubConfig(tlsClientInsecure bool) (*rest.Config, error) {
 
 	// Sometime the hub cluster need additional http header for authentication or authorization.
 	// the "HUB_KUBE_HEADER" to allow sending custom header to hub's API Server for authentication and authorization.
+	var customHeader http.Header
 	if header, err := env.Lookup(hubKubeHeaderEnvKey); err == nil {
 		r := textproto.NewReader(bufio.NewReader(strings.NewReader(header)))
 		h, err := r.ReadMIMEHeader()
 		if err != nil && !errors.Is(err, io.EOF) {
-			klog.ErrorS(err, "failed to parse HUB_KUBE_HEADER %q", header)
+			// The header may carry credentials, so that neither it nor the parse error, which quotes the malformed
+			// line, is logged or returned.
+			err = fmt.Errorf("environment variable %s is not a valid MIME header", hubKubeHeaderEnvKey)
+			klog.ErrorS(err, "Failed to parse HUB_KUBE_HEADER", "headerLength", len(header))
 			return nil, err
 		}
+		customHeader = http.Header(h)
 		hubConfig.WrapTransport = func(rt http.RoundTripper) http.RoundTripper {
-			return httpclient.NewCustomHeadersRoundTripper(http.Header(h), rt)
+			return httpclient.NewCustomHeadersRoundTripper(customHeader, rt)
 		}
 	}
+	klog.V(1).InfoS("Hub config prepared", "hubConfig", redact(hubConfig, customHeader))
 	return hubConfig, nil
 }
 
+// lookupHubEndpoint returns the hub server URL and CA, read from the hub config directory if any, or from the
+// environment variables otherwise.
+func lookupHubEndpoint() (string, []byte, error) {
+	if dir := ConfigDir(); dir != "" {
+		hubURL, caData, err := readConfigDir(dir)
+		if err != nil {
+			klog.ErrorS(err, "Cannot read the hub config directory", "dir", dir)
+			return "", nil, err
+		}
+		return hubURL, caData, nil
+	}
+
+	hubURL, err := env.Lookup(hubServerURLEnvKey)
+	if err != nil {
+		klog.ErrorS(err, "Hub cluster endpoint URL cannot be empty")
+		return "", nil, err
+	}
+	var caData []byte
+	if hubCA, err := env.Lookup(hubCAEnvKey); err == nil {
+		caData, err = base64.StdEncoding.DecodeString(hubCA)
+		if err != nil {
+			klog.ErrorS(err, "Cannot decode hub cluster certificate authority data")
+			return "", nil, fmt.Errorf("environment variable %s is not valid base64 encoded data: %w", hubCAEnvKey, err)
+		}
+	}
+	return hubURL, caData, nil
+}
+
+// ConfigDir returns the hub config directory set by the HUB_CONFIG_DIR environment variable, or an empty string if
+// the hub server URL and CA are set by the environment variables.
+func ConfigDir() string {
+	dir, err := env.Lookup(hubConfigDirEnvKey)
+	if err != nil {
+		return ""
+	}
+	return dir
+}
+
+// readConfigDir reads the hub server URL and the CA, if any, from the files of the hub config directory.
+func readConfigDir(dir string) (string, []byte, error) {
+	server, err := os.ReadFile(filepath.Join(dir, hubConfigServerFile))
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to read the hub server URL from the hub config directory: %w", err)
+	}
+	hubURL := strings.TrimSpace(string(server))
+	if hubURL == "" {
+		return "", nil, fmt.Errorf("file %s of the hub config directory %s is empty", hubConfigServerFile, dir)
+	}
+	caData, err := os.ReadFile(filepath.Join(dir, hubConfigCAFile))
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return "", nil, fmt.Errorf("failed to read the hub CA from the hub config directory: %w", err)
+	}
+	return hubURL, caData, nil
+}
+
+// redactedConfig is a hub config which can be logged: the values of the custom headers, which may carry credentials,
+// are masked, and the CA data is summarized by its size, while the hub server URL and the path of the token file are
+// kept as is; the token itself is never read. It comes with a fingerprint of the whole hub config, including the
+// header values, so that the support can tell whether two agents run with the same hub config without seeing it; the
+// token, which is refreshed periodically, is left out of the fingerprint.
+type redactedConfig struct {
+	Host            string            `json:"host"`
+	BearerTokenFile string            `json:"bearerTokenFile"`
+	Insecure        bool              `json:"insecure"`
+	CADataBytes     int               `json:"caDataBytes,omitempty"`
+	Headers         map[string]string `json:"headers,omitempty"`
+	Fingerprint     string            `json:"fingerprint"`
+}
+
+func redact(hubConfig *rest.Config, customHeader http.Header) redactedConfig {
+	res := redactedConfig{
+		Host:            hubConfig.Host,
+		BearerTokenFile: hubConfig.BearerTokenFile,
+		Insecure:        hubConfig.Insecure,
+		CADataBytes:     len(hubConfig.CAData),
+		Fingerprint:     fingerprint(hubConfig, customHeader),
+	}
+	if len(customHeader) > 0 {
+		res.Headers = make(map[string]string, len(customHeader))
+		for name := range customHeader {
+			res.Headers[name] = redactedValue
+		}
+	}
+	return res
+}
+
+// String implements the fmt.Stringer interface.
+func (c redactedConfig) String() string {
+	return fmt.Sprintf("{host=%q bearerTokenFile=%q insecure=%t caDataBytes=%d headers=%v fingerprint=%q}",
+		c.Host, c.BearerTokenFile, c.Insecure, c.CADataBytes, c.Headers, c.Fingerprint)
+}
+
+// MarshalLog implements the logr.Marshaler interface, so that the structured loggers log the fields rather than the
+// string.
+func (c redactedConfig) MarshalLog() any {
+	// The alias drops the methods, so that the loggers do not call MarshalLog again.
+	type config redactedConfig
+	return config(c)
+}
+
+// fingerprint returns a short non-reversible fingerprint of the hub config and its custom headers.
+func fingerprint(hubConfig *rest.Config, customHeader http.Header) string {
+	h := sha256.New()
+	fmt.Fprintf(h, "host=%s\ntokenFile=%s\ninsecure=%t\nca=", hubConfig.Host, hubConfig.BearerTokenFile, hubConfig.Insecure)
+	_, _ = h.Write(hubConfig.CAData)
+	_, _ = h.Write([]byte("\n"))
+	// http.Header.Write sorts the headers by name.
+	_ = customHeader.Write(h)
+	return hex.EncodeToString(h.Sum(nil))[:fingerprintLength]
+}
+
 // FetchMemberClusterNamespace gets the assigned namespace for the member cluster in the hub.
 func FetchMemberClusterNamespace() (string, error) {
 	mcName, err := env.LookupMemberClusterName()
 	if err != nil {
-		klog.ErrorS(err, "Member cluster name cannot be empty")
+		klog.ErrorS(err, "Member cluster name is missing or invalid")
 		return "", err
 	}
 	return fmt.Sprintf(HubNamespaceNameFormat, mcName), nil
 }
+
+// ExportNamespaces returns the namespaces in the hub cluster across which the exports (InternalServiceExports and
+// EndpointSliceExports) of a member cluster are sharded; with at most one shard, it is the member cluster namespace
+// only.
+func ExportNamespaces(mcNamespace string, shards int) []string {
+	if shards <= 1 {
+		return []string{mcNamespace}
+	}
+	namespaces := make([]string, 0, shards)
+	namespaces = append(namespaces, mcNamespace)
+	for i := 1; i < shards; i++ {
+		namespaces = append(namespaces, fmt.Sprintf(HubShardNamespaceNameFormat, mcNamespace, i))
+	}
+	return namespaces
+}
+
+// ExportNamespace returns the namespace in the hub cluster of the exports of a Service, selected by the hash of the
+// namespace and the name of the Service, so that the exports of a Service always land in the same shard.
+func ExportNamespace(mcNamespace string, shards int, svcNamespace, svcName string) string {
+	if shards <= 1 {
+		return mcNamespace
+	}
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(svcNamespace + "/" + svcName))
+	shard := int(h.Sum32() % uint32(shards))
+	if shard == 0 {
+		return mcNamespace
+	}
+	return fmt.Sprintf(HubShardNamespaceNameFormat, mcNamespace, shard)
+}
+
+// ValidateExportShards returns an error if the exports of a member cluster cannot be sharded across the given number
+// of namespaces, i.e. the count is not positive or a shard namespace name is not a valid DNS-1123 label.
+func ValidateExportShards(mcNamespace string, shards int) error {
+	if shards < 1 {
+		return fmt.Errorf("the number of hub export shards must be at least 1, got %d", shards)
+	}
+	for _, ns := range ExportNamespaces(mcNamespace, shards) {
+		if errs := validation.IsDNS1123Label(ns); len(errs) > 0 {
+			return fmt.Errorf("the hub export shard namespace %q is invalid: %s", ns, strings.Join(errs, "; "))
+		}
+	}
+	return nil
+}
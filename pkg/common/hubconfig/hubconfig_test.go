@@ -6,13 +6,19 @@ Licensed under the MIT license.
 package hubconfig
 
 import (
+	"bytes"
 	"encoding/base64"
+	"encoding/json"
+	"flag"
 	"fmt"
+	"net/http"
 	"os"
+	"strings"
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
 	"k8s.io/client-go/rest"
+	"k8s.io/klog/v2"
 )
 
 func TestPrepareHubConfig(t *testing.T) {
@@ -110,6 +116,47 @@ func TestPrepareHubConfig(t *testing.T) {
 				}
 			},
 		},
+		{
+			name:                 "environment variable `HUB_SERVER_URL` is empty - error",
+			environmentVariables: map[string]string{hubServerURLEnvKey: " ", tokenConfigPathEnvKey: fakeConfigtokenConfigPathEnvVal},
+			tlsClientInsecure:    true,
+			validate: func(t *testing.T, config *rest.Config, err error) {
+				wantErr := "environment variable HUB_SERVER_URL is set to an empty value"
+				if err == nil || err.Error() != wantErr {
+					t.Errorf("got error %v, want %q", err, wantErr)
+				}
+			},
+		},
+		{
+			name:                 "environment variables have surrounding whitespace - trimmed",
+			environmentVariables: map[string]string{hubServerURLEnvKey: fakeHubhubServerURLEnvVal + "\n", tokenConfigPathEnvKey: " " + fakeConfigtokenConfigPathEnvVal},
+			tlsClientInsecure:    true,
+			validate: func(t *testing.T, config *rest.Config, err error) {
+				if err != nil {
+					t.Errorf("not expect error but actually get error %s", err)
+				}
+				wantConfig := &rest.Config{
+					BearerTokenFile: fakeConfigtokenConfigPathEnvVal,
+					Host:            fakeHubhubServerURLEnvVal,
+					TLSClientConfig: rest.TLSClientConfig{
+						Insecure: true,
+					},
+				}
+				if !cmp.Equal(config, wantConfig) {
+					t.Errorf("got hub config %+v, want %+v", config, wantConfig)
+				}
+			},
+		},
+		{
+			name:                 "environment variable `HUB_CERTIFICATE_AUTHORITY` is not base64 encoded - error",
+			environmentVariables: map[string]string{hubServerURLEnvKey: fakeHubhubServerURLEnvVal, tokenConfigPathEnvKey: fakeConfigtokenConfigPathEnvVal, hubCAEnvKey: "not-base64!"},
+			tlsClientInsecure:    false,
+			validate: func(t *testing.T, config *rest.Config, err error) {
+				if err == nil || !strings.HasPrefix(err.Error(), "environment variable HUB_CERTIFICATE_AUTHORITY is not valid base64 encoded data") {
+					t.Errorf("got error %v, want the error about HUB_CERTIFICATE_AUTHORITY", err)
+				}
+			},
+		},
 		{
 			name:                 "environment variable `HUB_KUBE_HEADER` exists - config have WrapTransport",
 			environmentVariables: map[string]string{hubServerURLEnvKey: fakeHubhubServerURLEnvVal, hubKubeHeaderEnvKey: "custom-header: value", tokenConfigPathEnvKey: fakeConfigtokenConfigPathEnvVal},
@@ -148,6 +195,88 @@ func TestPrepareHubConfig(t *testing.T) {
 	}
 }
 
+// TestPrepareHubConfig_NoSecretsLogged tests that the values of the custom headers, which may carry credentials, never
+// appear in the logs, whether the header is valid or not.
+func TestPrepareHubConfig_NoSecretsLogged(t *testing.T) {
+	const secret = "hub-token-value"
+	var buf bytes.Buffer
+	fs := flag.NewFlagSet(t.Name(), flag.ContinueOnError)
+	klog.InitFlags(fs)
+	if err := fs.Parse([]string{"-logtostderr=false", "-v=1"}); err != nil {
+		t.Fatalf("failed to parse the klog flags: %v", err)
+	}
+	klog.SetOutput(&buf)
+	t.Cleanup(func() {
+		_ = fs.Parse([]string{"-logtostderr=true", "-v=0"})
+	})
+
+	for _, header := range []string{"Authorization: Bearer " + secret, "Bearer " + secret} {
+		t.Setenv(hubServerURLEnvKey, "fake-hub-server-url")
+		t.Setenv(tokenConfigPathEnvKey, "testdata/fake-config-path")
+		t.Setenv(hubKubeHeaderEnvKey, header)
+		_, _ = PrepareHubConfig(true)
+	}
+	klog.Flush()
+	if buf.Len() == 0 {
+		t.Fatal("PrepareHubConfig() logged nothing, want the hub config logged")
+	}
+	if strings.Contains(buf.String(), secret) {
+		t.Errorf("PrepareHubConfig() logs = %s, want no secret %q", buf.String(), secret)
+	}
+}
+
+// TestRedact tests that the values of the custom headers never appear in the formatted or marshaled forms of the hub
+// config, while the hub server URL and the header names do.
+func TestRedact(t *testing.T) {
+	const secret = "hub-token-value"
+	hubConfig := &rest.Config{
+		Host:            "https://hub.example.com",
+		BearerTokenFile: "/config/token",
+		TLSClientConfig: rest.TLSClientConfig{CAData: []byte("fake-certificate-authority")},
+	}
+	redacted := redact(hubConfig, http.Header{"Authorization": []string{"Bearer " + secret}})
+	marshaled, err := json.Marshal(redacted.MarshalLog())
+	if err != nil {
+		t.Fatalf("json.Marshal(MarshalLog()) = %v, want no error", err)
+	}
+	outputs := map[string]string{
+		"String()":     redacted.String(),
+		"%v":           fmt.Sprintf("%v", redacted),
+		"%+v":          fmt.Sprintf("%+v", redacted),
+		"MarshalLog()": string(marshaled),
+	}
+	for format, output := range outputs {
+		if strings.Contains(output, secret) {
+			t.Errorf("%s = %s, want no secret %q", format, output, secret)
+		}
+		for _, field := range []string{hubConfig.Host, hubConfig.BearerTokenFile, "Authorization", redactedValue, redacted.Fingerprint} {
+			if !strings.Contains(output, field) {
+				t.Errorf("%s = %s, want %q", format, output, field)
+			}
+		}
+	}
+}
+
+func TestFingerprint(t *testing.T) {
+	hubConfig := &rest.Config{Host: "https://hub.example.com", BearerTokenFile: "/config/token"}
+	header := http.Header{"Authorization": []string{"Bearer token-1"}, "X-Cluster": []string{"member-1"}}
+	want := fingerprint(hubConfig, header)
+	if len(want) != fingerprintLength {
+		t.Errorf("fingerprint() = %q, want %d characters", want, fingerprintLength)
+	}
+	sameHeader := http.Header{"X-Cluster": []string{"member-1"}, "Authorization": []string{"Bearer token-1"}}
+	if got := fingerprint(rest.CopyConfig(hubConfig), sameHeader); got != want {
+		t.Errorf("fingerprint() of the same hub config = %q, want %q", got, want)
+	}
+	rotated := http.Header{"Authorization": []string{"Bearer token-2"}, "X-Cluster": []string{"member-1"}}
+	if got := fingerprint(hubConfig, rotated); got == want {
+		t.Errorf("fingerprint() of the hub config with another header value = %q, want a different fingerprint", got)
+	}
+	if got := fingerprint(&rest.Config{Host: "https://other-hub.example.com", BearerTokenFile: "/config/token"}, header); got == want {
+		t.Errorf("fingerprint() of another hub = %q, want a different fingerprint", got)
+	}
+}
+
 func TestFetchMemberClusterNamespace(t *testing.T) {
 	memberCluster := "cluster-a"
 	testCases := []struct {
@@ -169,6 +298,24 @@ func TestFetchMemberClusterNamespace(t *testing.T) {
 			envKey:  "MEMBER_CLUSTER_NAME",
 			wantErr: true,
 		},
+		{
+			name:     "environment variable has surrounding whitespace",
+			envKey:   "MEMBER_CLUSTER_NAME",
+			envValue: " " + memberCluster + "\n",
+			want:     fmt.Sprintf(HubNamespaceNameFormat, memberCluster),
+		},
+		{
+			name:     "environment variable is not a valid DNS-1123 label",
+			envKey:   "MEMBER_CLUSTER_NAME",
+			envValue: "Cluster_A",
+			wantErr:  true,
+		},
+		{
+			name:     "environment variable is too long for the hub namespace",
+			envKey:   "MEMBER_CLUSTER_NAME",
+			envValue: strings.Repeat("a", 51),
+			wantErr:  true,
+		},
 	}
 
 	for _, tc := range testCases {
@@ -193,3 +340,110 @@ func TestFetchMemberClusterNamespace(t *testing.T) {
 		})
 	}
 }
+
+func TestExportNamespaces(t *testing.T) {
+	mcNamespace := "fleet-member-cluster-a"
+	testCases := []struct {
+		name   string
+		shards int
+		want   []string
+	}{
+		{
+			name:   "sharding is not configured",
+			shards: 0,
+			want:   []string{mcNamespace},
+		},
+		{
+			name:   "one shard",
+			shards: 1,
+			want:   []string{mcNamespace},
+		},
+		{
+			name:   "three shards",
+			shards: 3,
+			want:   []string{mcNamespace, "fleet-member-cluster-a-shard-1", "fleet-member-cluster-a-shard-2"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if diff := cmp.Diff(tc.want, ExportNamespaces(mcNamespace, tc.shards)); diff != "" {
+				t.Errorf("ExportNamespaces() mismatch (-want, +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestExportNamespace(t *testing.T) {
+	mcNamespace := "fleet-member-cluster-a"
+	if got := ExportNamespace(mcNamespace, 1, "work", "app"); got != mcNamespace {
+		t.Errorf("ExportNamespace() with one shard = %v, want %v", got, mcNamespace)
+	}
+
+	shards := 3
+	namespaces := map[string]bool{}
+	for _, ns := range ExportNamespaces(mcNamespace, shards) {
+		namespaces[ns] = false
+	}
+	for i := 0; i < 100; i++ {
+		svcName := fmt.Sprintf("app-%d", i)
+		got := ExportNamespace(mcNamespace, shards, "work", svcName)
+		if _, ok := namespaces[got]; !ok {
+			t.Fatalf("ExportNamespace(%s) = %v, want one of %v", svcName, got, ExportNamespaces(mcNamespace, shards))
+		}
+		if again := ExportNamespace(mcNamespace, shards, "work", svcName); again != got {
+			t.Fatalf("ExportNamespace(%s) = %v, then %v, want the same namespace", svcName, got, again)
+		}
+		namespaces[got] = true
+	}
+	for ns, used := range namespaces {
+		if !used {
+			t.Errorf("ExportNamespace() never selects the shard namespace %v", ns)
+		}
+	}
+}
+
+func TestValidateExportShards(t *testing.T) {
+	testCases := []struct {
+		name        string
+		mcNamespace string
+		shards      int
+		wantErr     bool
+	}{
+		{
+			name:        "one shard",
+			mcNamespace: "fleet-member-cluster-a",
+			shards:      1,
+		},
+		{
+			name:        "multiple shards",
+			mcNamespace: "fleet-member-cluster-a",
+			shards:      4,
+		},
+		{
+			name:        "no shard",
+			mcNamespace: "fleet-member-cluster-a",
+			shards:      0,
+			wantErr:     true,
+		},
+		{
+			name:        "shard namespace name is too long",
+			mcNamespace: fmt.Sprintf(HubNamespaceNameFormat, strings.Repeat("a", 50)),
+			shards:      2,
+			wantErr:     true,
+		},
+		{
+			name:        "long member cluster namespace without sharding",
+			mcNamespace: fmt.Sprintf(HubNamespaceNameFormat, strings.Repeat("a", 50)),
+			shards:      1,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if err := ValidateExportShards(tc.mcNamespace, tc.shards); (err != nil) != tc.wantErr {
+				t.Errorf("ValidateExportShards() = %v, want err %v", err, tc.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,338 @@
+/*
+Copyright (c) Microsoft Corporation.
+Licensed under the MIT license.
+*/
+
+package hubconfig
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/rest"
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	ctrlmetrics "sigs.k8s.io/controller-runtime/pkg/metrics"
+
+	"go.goms.io/fleet-networking/pkg/common/metrics"
+)
+
+const (
+	rotationResultSucceeded = "succeeded"
+	rotationResultRejected  = "rejected"
+)
+
+var (
+	// hubConfigRotationsTotal counts the rotations of the hub server URL and CA after the hub config directory has
+	// changed, by result.
+	hubConfigRotationsTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: metrics.MetricsNamespace,
+			Subsystem: metrics.MetricsSubsystem,
+			Name:      "hub_config_rotations_total",
+			Help:      "Total number of the rotations of the hub server URL and CA after the hub config directory has changed, by result",
+		},
+		[]string{"result"},
+	)
+)
+
+func init() {
+	// Register hubConfigRotationsTotal (fleet_networking_hub_config_rotations_total) metric
+	// with the controller runtime global metrics registry.
+	ctrlmetrics.Registry.MustRegister(hubConfigRotationsTotal)
+}
+
+// ValidateFunc performs a test request against the hub cluster with a candidate hub config.
+type ValidateFunc func(ctx context.Context, hubConfig *rest.Config) error
+
+// Rotator rotates the hub server URL and CA of the hub clients, e.g. when the hub cluster is migrated, without
+// restarting the agent. It checks the files of the hub config directory periodically and, when they have changed,
+// validates the new hub config with a test request, then swaps the transport the hub clients send their requests
+// with atomically; a hub config which fails the validation is rejected and the current one is kept.
+//
+// The clients, and hence the controllers and the caches built from the hub config, are kept as they are: their
+// requests are sent to the new hub server, while the watches are re-established with OnRotate, e.g. by closing the
+// connections to the previous hub server, and the informers relist from the new hub server. The controllers writing
+// to the hub cluster are notified through Subscribe.
+//
+// Rotator implements the controller-runtime Runnable interface and runs whether the manager is the leader or not.
+type Rotator struct {
+	dir      string
+	interval time.Duration
+	validate ValidateFunc
+	// template is the hub config as prepared by PrepareHubConfig, before the transport of the hub clients is
+	// wrapped; the candidate hub configs are derived from it.
+	template *rest.Config
+	// hubConfig is the hub config the hub clients are built from; its dialer, if any, is used for the new hub
+	// server, so that the connections keep being tracked.
+	hubConfig *rest.Config
+	// OnRotate, if set, is called once the hub server has been swapped.
+	OnRotate func()
+
+	// mu serializes the loads of the directory.
+	mu          sync.Mutex
+	hash        [sha256.Size]byte
+	current     atomic.Pointer[hubEndpoint]
+	subscribers []chan event.GenericEvent
+}
+
+// hubEndpoint is a hub server the hub clients are rotated to, with the transport trusting its CA.
+type hubEndpoint struct {
+	url       *url.URL
+	transport http.RoundTripper
+	// listed holds the keys (see watchKey) of the collections listed from the hub server, after which the resource
+	// versions the watches resume from are the hub server's own.
+	listed sync.Map
+}
+
+// NewRotator returns a rotator of the hub config prepared by PrepareHubConfig from the hub config directory; it must
+// be called before the transport of the hub config is wrapped, and the hub config then wrapped with
+// Rotator.WrapTransport before any other wrapper. The content of the directory is checked every interval; 0 disables
+// the rotation.
+func NewRotator(hubConfig *rest.Config, dir string, interval time.Duration, validate ValidateFunc) (*Rotator, error) {
+	r := &Rotator{
+		dir:       dir,
+		interval:  interval,
+		validate:  validate,
+		template:  rest.CopyConfig(hubConfig),
+		hubConfig: hubConfig,
+	}
+	hubURL, caData, err := readConfigDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	r.hash = hashEndpoint(hubURL, caData)
+	return r, nil
+}
+
+// hashEndpoint returns the hash of a hub server URL and CA.
+func hashEndpoint(hubURL string, caData []byte) [sha256.Size]byte {
+	h := sha256.New()
+	fmt.Fprintf(h, "server=%s\nca=", hubURL)
+	_, _ = h.Write(caData)
+	var hash [sha256.Size]byte
+	copy(hash[:], h.Sum(nil))
+	return hash
+}
+
+// WrapTransport returns a transport which sends the requests to the current hub server; it is set as the innermost
+// WrapTransport of the hub config, so that the transport it wraps, i.e. the one trusting the CA of the initial hub
+// server, is replaced as a whole once rotated.
+func (r *Rotator) WrapTransport(rt http.RoundTripper) http.RoundTripper {
+	return &rotatingRoundTripper{rotator: r, initial: rt, initialURL: parseHost(r.template.Host)}
+}
+
+// Rotate loads the hub config directory and swaps the hub server if the content of the directory has changed since
+// the last successful load. It returns true if the hub server has been swapped.
+func (r *Rotator) Rotate(ctx context.Context) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	hubURL, caData, err := readConfigDir(r.dir)
+	if err != nil {
+		hubConfigRotationsTotal.WithLabelValues(rotationResultRejected).Inc()
+		return false, err
+	}
+	hash := hashEndpoint(hubURL, caData)
+	if hash == r.hash {
+		return false, nil
+	}
+	candidate := rest.CopyConfig(r.template)
+	candidate.Host = hubURL
+	candidate.CAData = caData
+	hubEndpointURL := parseHost(candidate.Host)
+	if hubEndpointURL == nil {
+		hubConfigRotationsTotal.WithLabelValues(rotationResultRejected).Inc()
+		return false, fmt.Errorf("rejected hub config %s: invalid hub server URL", redact(candidate, nil))
+	}
+	if r.validate != nil {
+		if err := r.validate(ctx, candidate); err != nil {
+			hubConfigRotationsTotal.WithLabelValues(rotationResultRejected).Inc()
+			return false, fmt.Errorf("rejected hub config %s: %w", redact(candidate, nil), err)
+		}
+	}
+	// The transport trusts the new CA and keeps the custom headers, if any; the authentication and the other
+	// wrappers of the hub clients are applied around it as before.
+	transport, err := rest.TransportFor(&rest.Config{
+		Host:            candidate.Host,
+		TLSClientConfig: candidate.TLSClientConfig,
+		WrapTransport:   r.template.WrapTransport,
+		Dial:            r.hubConfig.Dial,
+	})
+	if err != nil {
+		hubConfigRotationsTotal.WithLabelValues(rotationResultRejected).Inc()
+		return false, fmt.Errorf("failed to create the transport for hub config %s: %w", redact(candidate, nil), err)
+	}
+	r.current.Store(&hubEndpoint{url: hubEndpointURL, transport: transport})
+	r.hash = hash
+	hubConfigRotationsTotal.WithLabelValues(rotationResultSucceeded).Inc()
+	klog.InfoS("Hub config changed, the hub clients have been rotated to the new hub server", "hubConfig", redact(candidate, nil))
+	if r.OnRotate != nil {
+		r.OnRotate()
+	}
+	for _, ch := range r.subscribers {
+		// A pending notification is enough, as the subscribers re-enqueue all their objects on any.
+		select {
+		case ch <- event.GenericEvent{Object: &metav1.PartialObjectMetadata{}}:
+		default:
+		}
+	}
+	return true, nil
+}
+
+// Subscribe returns a channel which receives an event every time the hub server is swapped; the event carries no
+// object. It is meant to be used as a channel source by the controllers writing to the hub cluster, which re-enqueue
+// all the objects they reconcile on the event, as the new hub server might not have them. A nil Rotator, i.e. one
+// which never rotates, returns a nil channel.
+func (r *Rotator) Subscribe() <-chan event.GenericEvent {
+	if r == nil {
+		return nil
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	ch := make(chan event.GenericEvent, 1)
+	r.subscribers = append(r.subscribers, ch)
+	return ch
+}
+
+// NeedLeaderElection implements the LeaderElectionRunnable interface; the hub clients are used by the controllers
+// running on every replica.
+func (r *Rotator) NeedLeaderElection() bool {
+	return false
+}
+
+// Start rotates the hub config periodically and blocks until the context is done.
+func (r *Rotator) Start(ctx context.Context) error {
+	if r.interval <= 0 {
+		klog.V(2).InfoS("The hub config rotation is disabled", "dir", r.dir)
+		<-ctx.Done()
+		return nil
+	}
+	klog.V(2).InfoS("Starting the hub config rotator", "dir", r.dir, "interval", r.interval)
+	ticker := time.NewTicker(r.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			klog.V(2).InfoS("Stopping the hub config rotator", "dir", r.dir)
+			return nil
+		case <-ticker.C:
+			if _, err := r.Rotate(ctx); err != nil {
+				klog.ErrorS(err, "Failed to rotate the hub config, keeping the current hub server", "dir", r.dir)
+			}
+		}
+	}
+}
+
+// rotatingRoundTripper sends the requests to the current hub server of a rotator, or with the initial transport until
+// the hub config is first rotated.
+//
+// The resource versions of two hub servers are unrelated: an informer resuming its watch on the new hub server from
+// a resource version of the previous one would miss the objects changed in between, or never learn about the ones
+// deleted. Such watches are answered with 410 Gone, as if the resource version had expired, until the collection has
+// been listed from the new hub server, so that the informers relist first; the lists themselves drop the resource
+// version of the previous hub server, which the new one would make wait for or reject as too large, and read the
+// latest state instead.
+type rotatingRoundTripper struct {
+	rotator    *Rotator
+	initial    http.RoundTripper
+	initialURL *url.URL
+}
+
+// RoundTrip implements the http.RoundTripper interface.
+func (rt *rotatingRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	current := rt.rotator.current.Load()
+	if current == nil || rt.initialURL == nil {
+		return rt.initial.RoundTrip(req)
+	}
+	// The request URL is built by the client from the initial hub server URL; its scheme, host and path prefix are
+	// replaced with the ones of the current hub server.
+	key, watching := watchKey(req)
+	listed := isListed(current, key, req.URL.Query().Get("resourceVersion"))
+	if watching && !listed {
+		return expiredResponse(req), nil
+	}
+	req = req.Clone(req.Context())
+	if !listed {
+		query := req.URL.Query()
+		query.Del("resourceVersion")
+		query.Del("resourceVersionMatch")
+		req.URL.RawQuery = query.Encode()
+	}
+	req.URL.Scheme = current.url.Scheme
+	req.URL.Host = current.url.Host
+	req.URL.Path = strings.TrimSuffix(current.url.Path, "/") + strings.TrimPrefix(req.URL.Path, strings.TrimSuffix(rt.initialURL.Path, "/"))
+	req.URL.RawPath = ""
+	req.Host = ""
+	resp, err := current.transport.RoundTrip(req)
+	if err == nil && !watching && req.Method == http.MethodGet && resp.StatusCode == http.StatusOK {
+		current.listed.Store(key, struct{}{})
+	}
+	return resp, err
+}
+
+// watchKey returns the key of the collection a request lists or watches, i.e. its path and selectors, and whether
+// the request is a watch.
+func watchKey(req *http.Request) (string, bool) {
+	query := req.URL.Query()
+	watching := query.Get("watch") == "true" || query.Get("watch") == "1"
+	return fmt.Sprintf("%s?labelSelector=%s&fieldSelector=%s", req.URL.Path, query.Get("labelSelector"), query.Get("fieldSelector")), watching
+}
+
+// isListed returns whether a watch may resume from the resource version on the hub server, i.e. if the watch starts
+// from any resource version or if its collection has been listed from the hub server.
+func isListed(endpoint *hubEndpoint, key, resourceVersion string) bool {
+	if resourceVersion == "" || resourceVersion == "0" {
+		return true
+	}
+	_, ok := endpoint.listed.Load(key)
+	return ok
+}
+
+// expiredResponse returns the response of the API server to a watch from an expired resource version.
+func expiredResponse(req *http.Request) *http.Response {
+	status := &metav1.Status{
+		TypeMeta: metav1.TypeMeta{Kind: "Status", APIVersion: "v1"},
+		Status:   metav1.StatusFailure,
+		Message:  "the resource version is from the previous hub server; relist from the new hub server",
+		Reason:   metav1.StatusReasonExpired,
+		Code:     http.StatusGone,
+	}
+	body, _ := json.Marshal(status)
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", http.StatusGone, http.StatusText(http.StatusGone)),
+		StatusCode:    http.StatusGone,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        http.Header{"Content-Type": []string{"application/json"}},
+		Body:          io.NopCloser(bytes.NewReader(body)),
+		ContentLength: int64(len(body)),
+		Request:       req,
+	}
+}
+
+// parseHost parses the host of a hub config as a URL, defaulting the scheme to https as the Kubernetes clients do;
+// it returns nil if the host is not a valid URL.
+func parseHost(host string) *url.URL {
+	if !strings.Contains(host, "://") {
+		host = "https://" + host
+	}
+	u, err := url.Parse(host)
+	if err != nil || u.Host == "" {
+		return nil
+	}
+	return u
+}
@@ -0,0 +1,298 @@
+/*
+Copyright (c) Microsoft Corporation.
+Licensed under the MIT license.
+*/
+
+package hubconfig
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"math/big"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"k8s.io/apimachinery/pkg/version"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+)
+
+// newHubServer starts a TLS server with a self-signed certificate of its own, which answers the version requests
+// with the given git version as long as they carry the token of testdata/fake-config-path; it returns the server and
+// its PEM encoded CA.
+func newHubServer(t *testing.T, gitVersion string) (*httptest.Server, []byte) {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("GenerateKey() = %v, want no error", err)
+	}
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: gitVersion},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+		IPAddresses:           []net.IP{net.ParseIP("127.0.0.1")},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatalf("CreateCertificate() = %v, want no error", err)
+	}
+
+	srv := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if req.Header.Get("Authorization") != "Bearer this is token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		if req.URL.Path != "/version" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(version.Info{GitVersion: gitVersion})
+	}))
+	srv.TLS = &tls.Config{Certificates: []tls.Certificate{{Certificate: [][]byte{der}, PrivateKey: key}}}
+	srv.StartTLS()
+	t.Cleanup(srv.Close)
+	return srv, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+}
+
+// writeConfigDir writes the hub server URL and CA to the hub config directory.
+func writeConfigDir(t *testing.T, dir, hubURL string, caData []byte) {
+	t.Helper()
+	if err := os.WriteFile(filepath.Join(dir, hubConfigServerFile), []byte(hubURL+"\n"), 0600); err != nil {
+		t.Fatalf("WriteFile() = %v, want no error", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, hubConfigCAFile), caData, 0600); err != nil {
+		t.Fatalf("WriteFile() = %v, want no error", err)
+	}
+}
+
+// TestRotator tests that the clients built once from the hub config resume against a second hub server, with a CA of
+// its own, after the hub config directory has changed, and keep the current hub server if the new hub config is
+// rejected.
+func TestRotator(t *testing.T) {
+	ctx := context.Background()
+	hub1, hub1CA := newHubServer(t, "hub-1")
+	hub2, hub2CA := newHubServer(t, "hub-2")
+	hub3, hub3CA := newHubServer(t, "hub-3")
+
+	dir := t.TempDir()
+	writeConfigDir(t, dir, hub1.URL, hub1CA)
+	t.Setenv(hubConfigDirEnvKey, dir)
+	t.Setenv(tokenConfigPathEnvKey, "testdata/fake-config-path")
+	hubConfig, err := PrepareHubConfig(false)
+	if err != nil {
+		t.Fatalf("PrepareHubConfig() = %v, want no error", err)
+	}
+
+	validate := func(ctx context.Context, config *rest.Config) error {
+		if config.Host == hub3.URL {
+			return errors.New("member cluster namespace not found")
+		}
+		return nil
+	}
+	rotator, err := NewRotator(hubConfig, dir, time.Minute, validate)
+	if err != nil {
+		t.Fatalf("NewRotator() = %v, want no error", err)
+	}
+	rotated := 0
+	rotator.OnRotate = func() { rotated++ }
+	hubConfig.Wrap(rotator.WrapTransport)
+	clientset, err := kubernetes.NewForConfig(hubConfig)
+	if err != nil {
+		t.Fatalf("NewForConfig() = %v, want no error", err)
+	}
+
+	steps := []struct {
+		name        string
+		hubURL      string
+		caData      []byte
+		wantRotated bool
+		wantErr     bool
+		wantVersion string
+	}{
+		{
+			name:        "unchanged",
+			hubURL:      hub1.URL,
+			caData:      hub1CA,
+			wantVersion: "hub-1",
+		},
+		{
+			name:        "hub server changed",
+			hubURL:      hub2.URL,
+			caData:      hub2CA,
+			wantRotated: true,
+			wantVersion: "hub-2",
+		},
+		{
+			name:        "hub config rejected",
+			hubURL:      hub3.URL,
+			caData:      hub3CA,
+			wantErr:     true,
+			wantVersion: "hub-2",
+		},
+		{
+			name:        "hub server changed back",
+			hubURL:      hub1.URL,
+			caData:      hub1CA,
+			wantRotated: true,
+			wantVersion: "hub-1",
+		},
+	}
+	wantRotations := 0
+	for _, step := range steps {
+		writeConfigDir(t, dir, step.hubURL, step.caData)
+		gotRotated, err := rotator.Rotate(ctx)
+		if (err != nil) != step.wantErr {
+			t.Fatalf("%s: Rotate() = %v, want error %t", step.name, err, step.wantErr)
+		}
+		if gotRotated != step.wantRotated {
+			t.Errorf("%s: Rotate() = %t, want %t", step.name, gotRotated, step.wantRotated)
+		}
+		if step.wantRotated {
+			wantRotations++
+		}
+		if rotated != wantRotations {
+			t.Errorf("%s: OnRotate called %d times, want %d", step.name, rotated, wantRotations)
+		}
+		got, err := clientset.Discovery().ServerVersion()
+		if err != nil {
+			t.Fatalf("%s: ServerVersion() = %v, want no error", step.name, err)
+		}
+		if got.GitVersion != step.wantVersion {
+			t.Errorf("%s: ServerVersion() = %s, want %s", step.name, got.GitVersion, step.wantVersion)
+		}
+	}
+}
+
+// TestRotatingRoundTripper_PathPrefix tests that the path prefix of the initial hub server URL is replaced with the
+// one of the current hub server.
+func TestRotatingRoundTripper_PathPrefix(t *testing.T) {
+	var gotPath string
+	current := http.RoundTripper(roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		gotPath = req.URL.Scheme + "://" + req.URL.Host + req.URL.Path
+		return &http.Response{StatusCode: http.StatusOK, Body: http.NoBody}, nil
+	}))
+	rotator := &Rotator{template: &rest.Config{Host: "https://hub-1.example.com/proxy/"}}
+	rotator.current.Store(&hubEndpoint{url: parseHost("hub-2.example.com/k8s"), transport: current})
+	rt := rotator.WrapTransport(http.DefaultTransport)
+
+	req, err := http.NewRequest(http.MethodGet, "https://hub-1.example.com/proxy/api/v1/namespaces", nil)
+	if err != nil {
+		t.Fatalf("NewRequest() = %v, want no error", err)
+	}
+	resp, err := rt.RoundTrip(req)
+	if err != nil {
+		t.Fatalf("RoundTrip() = %v, want no error", err)
+	}
+	resp.Body.Close()
+	if want := "https://hub-2.example.com/k8s/api/v1/namespaces"; gotPath != want {
+		t.Errorf("RoundTrip() sent to %s, want %s", gotPath, want)
+	}
+	if req.URL.Host != "hub-1.example.com" {
+		t.Errorf("RoundTrip() modified the original request URL host to %s", req.URL.Host)
+	}
+}
+
+// TestRotatingRoundTripper_Relist tests that the watches resuming from a resource version are answered with 410 Gone
+// once rotated, until their collection has been listed from the current hub server.
+func TestRotatingRoundTripper_Relist(t *testing.T) {
+	var sent []string
+	current := http.RoundTripper(roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		sent = append(sent, req.URL.RequestURI())
+		return &http.Response{StatusCode: http.StatusOK, Body: http.NoBody}, nil
+	}))
+	rotator := &Rotator{template: &rest.Config{Host: "https://hub-1.example.com"}}
+	rotator.current.Store(&hubEndpoint{url: parseHost("https://hub-2.example.com"), transport: current})
+	rt := rotator.WrapTransport(http.DefaultTransport)
+
+	const exports = "/apis/networking.fleet.azure.com/v1alpha1/namespaces/fleet-member-member-1/internalserviceexports"
+	steps := []struct {
+		name       string
+		uri        string
+		wantStatus int
+		// wantSent is the request URI sent to the hub server, if any.
+		wantSent string
+	}{
+		{
+			name:       "watch from a resource version of the previous hub server",
+			uri:        exports + "?watch=true&resourceVersion=1234",
+			wantStatus: http.StatusGone,
+		},
+		{
+			name:       "watch from any resource version",
+			uri:        exports + "?watch=true&resourceVersion=0",
+			wantStatus: http.StatusOK,
+			wantSent:   exports + "?watch=true&resourceVersion=0",
+		},
+		{
+			name:       "list from a resource version of the previous hub server",
+			uri:        exports + "?limit=500&resourceVersion=1234&resourceVersionMatch=NotOlderThan",
+			wantStatus: http.StatusOK,
+			wantSent:   exports + "?limit=500",
+		},
+		{
+			name:       "watch from the resource version of the list",
+			uri:        exports + "?watch=true&resourceVersion=56",
+			wantStatus: http.StatusOK,
+			wantSent:   exports + "?watch=true&resourceVersion=56",
+		},
+		{
+			name:       "list from the resource version of the list",
+			uri:        exports + "?resourceVersion=56",
+			wantStatus: http.StatusOK,
+			wantSent:   exports + "?resourceVersion=56",
+		},
+		{
+			name:       "watch of a collection with other selectors",
+			uri:        exports + "?watch=true&resourceVersion=56&labelSelector=app%3Dweb",
+			wantStatus: http.StatusGone,
+		},
+	}
+	for _, step := range steps {
+		sent = nil
+		req, err := http.NewRequest(http.MethodGet, "https://hub-1.example.com"+step.uri, nil)
+		if err != nil {
+			t.Fatalf("%s: NewRequest() = %v, want no error", step.name, err)
+		}
+		resp, err := rt.RoundTrip(req)
+		if err != nil {
+			t.Fatalf("%s: RoundTrip() = %v, want no error", step.name, err)
+		}
+		resp.Body.Close()
+		if resp.StatusCode != step.wantStatus {
+			t.Errorf("%s: RoundTrip() status = %d, want %d", step.name, resp.StatusCode, step.wantStatus)
+		}
+		var wantSent []string
+		if step.wantSent != "" {
+			wantSent = []string{step.wantSent}
+		}
+		if diff := cmp.Diff(wantSent, sent); diff != "" {
+			t.Errorf("%s: RoundTrip() sent requests to the hub server mismatch (-want, +got):\n%s", step.name, diff)
+		}
+	}
+}
+
+type roundTripperFunc func(req *http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
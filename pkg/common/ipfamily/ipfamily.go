@@ -0,0 +1,61 @@
+/*
+Copyright (c) Microsoft Corporation.
+Licensed under the MIT license.
+*/
+
+// Package ipfamily features the helpers to handle the IP families of the exported services, which are either
+// single-stack or dual-stack.
+package ipfamily
+
+import (
+	"slices"
+
+	corev1 "k8s.io/api/core/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
+)
+
+// normalize returns the IP families of an exported service; the services exported by the agents which do not report
+// the families are IPv4 only.
+func normalize(families []corev1.IPFamily) []corev1.IPFamily {
+	if len(families) == 0 {
+		return []corev1.IPFamily{corev1.IPv4Protocol}
+	}
+	return families
+}
+
+// Equal returns true if two exported services have the same IP families; the order is ignored, as the importing
+// clusters only require the same families, regardless of which one is primary.
+func Equal(a, b []corev1.IPFamily) bool {
+	a, b = normalize(a), normalize(b)
+	if len(a) != len(b) {
+		return false
+	}
+	for _, family := range a {
+		if !slices.Contains(b, family) {
+			return false
+		}
+	}
+	return true
+}
+
+// IsDualStack returns true if the IP families are dual-stack.
+func IsDualStack(families []corev1.IPFamily) bool {
+	return len(families) > 1
+}
+
+// SupportsAddressType returns true if a Service of the given IP families can have the endpoints of the address type.
+// Services whose families are unknown are assumed to support all address types.
+func SupportsAddressType(families []corev1.IPFamily, addressType discoveryv1.AddressType) bool {
+	if len(families) == 0 {
+		return true
+	}
+	switch addressType {
+	case discoveryv1.AddressTypeIPv4:
+		return slices.Contains(families, corev1.IPv4Protocol)
+	case discoveryv1.AddressTypeIPv6:
+		return slices.Contains(families, corev1.IPv6Protocol)
+	default:
+		// FQDN endpoints do not depend on the IP families.
+		return true
+	}
+}
@@ -0,0 +1,116 @@
+/*
+Copyright (c) Microsoft Corporation.
+Licensed under the MIT license.
+*/
+
+package ipfamily
+
+import (
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
+)
+
+var (
+	ipv4      = []corev1.IPFamily{corev1.IPv4Protocol}
+	ipv6      = []corev1.IPFamily{corev1.IPv6Protocol}
+	dualStack = []corev1.IPFamily{corev1.IPv4Protocol, corev1.IPv6Protocol}
+	ipv6First = []corev1.IPFamily{corev1.IPv6Protocol, corev1.IPv4Protocol}
+)
+
+func TestEqual(t *testing.T) {
+	testCases := []struct {
+		name string
+		a    []corev1.IPFamily
+		b    []corev1.IPFamily
+		want bool
+	}{
+		{
+			name: "both unknown",
+			want: true,
+		},
+		{
+			name: "unknown and IPv4",
+			b:    ipv4,
+			want: true,
+		},
+		{
+			name: "unknown and IPv6",
+			b:    ipv6,
+		},
+		{
+			name: "IPv4 and dual-stack",
+			a:    ipv4,
+			b:    dualStack,
+		},
+		{
+			name: "dual-stack with different primary families",
+			a:    dualStack,
+			b:    ipv6First,
+			want: true,
+		},
+		{
+			name: "IPv4 and IPv6",
+			a:    ipv4,
+			b:    ipv6,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := Equal(tc.a, tc.b); got != tc.want {
+				t.Errorf("Equal() = %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestSupportsAddressType(t *testing.T) {
+	testCases := []struct {
+		name        string
+		families    []corev1.IPFamily
+		addressType discoveryv1.AddressType
+		want        bool
+	}{
+		{
+			name:        "unknown families",
+			addressType: discoveryv1.AddressTypeIPv6,
+			want:        true,
+		},
+		{
+			name:        "IPv4 endpoints of IPv4 service",
+			families:    ipv4,
+			addressType: discoveryv1.AddressTypeIPv4,
+			want:        true,
+		},
+		{
+			name:        "IPv6 endpoints of IPv4 service",
+			families:    ipv4,
+			addressType: discoveryv1.AddressTypeIPv6,
+		},
+		{
+			name:        "IPv4 endpoints of IPv6 service",
+			families:    ipv6,
+			addressType: discoveryv1.AddressTypeIPv4,
+		},
+		{
+			name:        "IPv6 endpoints of dual-stack service",
+			families:    dualStack,
+			addressType: discoveryv1.AddressTypeIPv6,
+			want:        true,
+		},
+		{
+			name:        "FQDN endpoints",
+			families:    ipv4,
+			addressType: discoveryv1.AddressTypeFQDN,
+			want:        true,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := SupportsAddressType(tc.families, tc.addressType); got != tc.want {
+				t.Errorf("SupportsAddressType() = %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,62 @@
+/*
+Copyright (c) Microsoft Corporation.
+Licensed under the MIT license.
+*/
+
+// Package listguard provides the helpers shared between networking controllers to keep the List calls made on large
+// fleets bounded.
+//
+// The reconcilers list objects from the informer caches, which do not support continue tokens; they are expected to
+// scope their List calls with the field indexes and to report the calls returning unexpectedly many objects with
+// ObserveListSize. The readers which list objects directly from the API server page through them with ForEachPage.
+package listguard
+
+import (
+	"context"
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// DefaultPageSize is the number of objects listed per request from the API server when the page size is not set.
+	DefaultPageSize int64 = 500
+	// LargeListThreshold is the number of objects returned by a single List call of a reconcile above which the call
+	// is logged, so that the reconcile paths which do not scale with the size of the fleet can be spotted.
+	LargeListThreshold = 1000
+)
+
+// ForEachPage lists the objects page by page into the list and calls fn after each page; listing stops at the first
+// error returned by fn. It must be used with a reader which lists the objects from the API server, as the informer
+// caches reject continue tokens.
+func ForEachPage(ctx context.Context, reader client.Reader, list client.ObjectList, pageSize int64, fn func() error, opts ...client.ListOption) error {
+	if pageSize <= 0 {
+		pageSize = DefaultPageSize
+	}
+	continueToken := ""
+	for {
+		pageOpts := append(opts[:len(opts):len(opts)], client.Limit(pageSize), client.Continue(continueToken))
+		if err := reader.List(ctx, list, pageOpts...); err != nil {
+			return err
+		}
+		if err := fn(); err != nil {
+			return err
+		}
+		if continueToken = list.GetContinue(); continueToken == "" {
+			return nil
+		}
+	}
+}
+
+// ObserveListSize logs the List call which has returned the list if it holds more objects than LargeListThreshold,
+// with the given key/value pairs identifying the object being reconciled, and returns the number of objects in the list.
+func ObserveListSize(list client.ObjectList, keysAndValues ...any) int {
+	size := meta.LenList(list)
+	if size > LargeListThreshold {
+		klog.InfoS("Listed more objects than expected in a single call; the reconcile may not scale with the fleet",
+			append([]any{"listType", fmt.Sprintf("%T", list), "count", size, "threshold", LargeListThreshold}, keysAndValues...)...)
+	}
+	return size
+}
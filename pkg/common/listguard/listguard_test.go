@@ -0,0 +1,146 @@
+/*
+Copyright (c) Microsoft Corporation.
+Licensed under the MIT license.
+*/
+
+package listguard
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+)
+
+const (
+	testNamespace = "work"
+)
+
+func configMaps(count int) []client.Object {
+	objs := make([]client.Object, 0, count)
+	for i := 0; i < count; i++ {
+		objs = append(objs, &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: testNamespace,
+				Name:      fmt.Sprintf("cm-%03d", i),
+			},
+		})
+	}
+	return objs
+}
+
+// newPagingReader returns a fake reader which serves the ConfigMaps page by page, as the fake client ignores the
+// limit and continue options; the continue token is the index of the first ConfigMap of the next page.
+func newPagingReader(objs ...client.Object) client.Reader {
+	return fake.NewClientBuilder().
+		WithScheme(scheme.Scheme).
+		WithObjects(objs...).
+		WithInterceptorFuncs(interceptor.Funcs{
+			List: func(ctx context.Context, c client.WithWatch, list client.ObjectList, opts ...client.ListOption) error {
+				if err := c.List(ctx, list, opts...); err != nil {
+					return err
+				}
+				listOpts := &client.ListOptions{}
+				listOpts.ApplyOptions(opts)
+				cmList := list.(*corev1.ConfigMapList)
+				start := 0
+				if listOpts.Continue != "" {
+					var err error
+					if start, err = strconv.Atoi(listOpts.Continue); err != nil {
+						return err
+					}
+				}
+				end := min(start+int(listOpts.Limit), len(cmList.Items))
+				if end < len(cmList.Items) {
+					cmList.Continue = strconv.Itoa(end)
+				}
+				cmList.Items = cmList.Items[start:end]
+				return nil
+			},
+		}).
+		Build()
+}
+
+// TestForEachPage tests the ForEachPage function.
+func TestForEachPage(t *testing.T) {
+	fnErr := errors.New("fn error")
+	testCases := []struct {
+		name      string
+		count     int
+		pageSize  int64
+		failAt    int
+		wantPages []int
+		wantErr   error
+	}{
+		{
+			name:      "no objects",
+			pageSize:  2,
+			wantPages: []int{0},
+		},
+		{
+			name:      "single page",
+			count:     2,
+			pageSize:  2,
+			wantPages: []int{2},
+		},
+		{
+			name:      "multiple pages",
+			count:     5,
+			pageSize:  2,
+			wantPages: []int{2, 2, 1},
+		},
+		{
+			name:      "default page size",
+			count:     5,
+			wantPages: []int{5},
+		},
+		{
+			name:      "fn error",
+			count:     5,
+			pageSize:  2,
+			failAt:    2,
+			wantPages: []int{2, 2},
+			wantErr:   fnErr,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			reader := newPagingReader(configMaps(tc.count)...)
+			list := &corev1.ConfigMapList{}
+			var gotPages []int
+			err := ForEachPage(context.Background(), reader, list, tc.pageSize, func() error {
+				gotPages = append(gotPages, len(list.Items))
+				if len(gotPages) == tc.failAt {
+					return fnErr
+				}
+				return nil
+			}, client.InNamespace(testNamespace))
+			if !errors.Is(err, tc.wantErr) {
+				t.Fatalf("ForEachPage() = %v, want %v", err, tc.wantErr)
+			}
+			if diff := cmp.Diff(tc.wantPages, gotPages); diff != "" {
+				t.Errorf("ForEachPage() page sizes mismatch (-want, +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+// TestObserveListSize tests the ObserveListSize function.
+func TestObserveListSize(t *testing.T) {
+	list := &corev1.ConfigMapList{Items: make([]corev1.ConfigMap, LargeListThreshold+1)}
+	if got := ObserveListSize(list, "namespace", testNamespace); got != LargeListThreshold+1 {
+		t.Errorf("ObserveListSize() = %d, want %d", got, LargeListThreshold+1)
+	}
+	if got := ObserveListSize(&corev1.ConfigMapList{}); got != 0 {
+		t.Errorf("ObserveListSize() = %d, want 0", got)
+	}
+}
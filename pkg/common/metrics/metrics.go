@@ -67,4 +67,22 @@ var (
 	ExportDurationMillisecondsBuckets = []float64{1000, 2500, 5000, 10000, 25000, 50000}
 	// The right bound of export durations; any data point beyond this limit will be capped.
 	ExportDurationRightBound = ExportDurationMillisecondsBuckets[len(ExportDurationMillisecondsBuckets)-1] * 2
+	// The same buckets as ExportDurationMillisecondsBuckets, in seconds, for the metrics following the Prometheus
+	// convention of using base units.
+	ExportLatencySecondsBuckets = []float64{1, 2.5, 5, 10, 25, 50}
 )
+
+// ClampExportDuration bounds an export duration, computed from timestamps which may come from the skewed clocks of
+// different clusters, to the range the export metrics observe: a non-positive duration, which only happens when the
+// clocks (or the annotations carrying the timestamps) are out of order, is replaced with exactly 1 second, so that it
+// does not show up as a negative outlier; a duration beyond ExportDurationRightBound is capped, so that the large
+// outliers do not skew the stats (e.g. averages).
+func ClampExportDuration(d time.Duration) time.Duration {
+	if d <= 0 {
+		return time.Second
+	}
+	if rightBound := time.Duration(ExportDurationRightBound) * time.Millisecond; d > rightBound {
+		return rightBound
+	}
+	return d
+}
@@ -0,0 +1,53 @@
+/*
+Copyright (c) Microsoft Corporation.
+Licensed under the MIT license.
+*/
+
+package metrics
+
+import (
+	"testing"
+	"time"
+)
+
+// TestClampExportDuration tests the ClampExportDuration function.
+func TestClampExportDuration(t *testing.T) {
+	testCases := []struct {
+		name     string
+		duration time.Duration
+		want     time.Duration
+	}{
+		{
+			name:     "negative duration (clock skew)",
+			duration: -10 * time.Minute,
+			want:     time.Second,
+		},
+		{
+			name:     "zero duration",
+			duration: 0,
+			want:     time.Second,
+		},
+		{
+			name:     "duration within the bounds",
+			duration: 2500 * time.Millisecond,
+			want:     2500 * time.Millisecond,
+		},
+		{
+			name:     "duration at the right bound",
+			duration: 100 * time.Second,
+			want:     100 * time.Second,
+		},
+		{
+			name:     "duration beyond the right bound",
+			duration: time.Hour,
+			want:     100 * time.Second,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := ClampExportDuration(tc.duration); got != tc.want {
+				t.Errorf("ClampExportDuration(%v) = %v, want %v", tc.duration, got, tc.want)
+			}
+		})
+	}
+}
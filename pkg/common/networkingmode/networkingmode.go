@@ -0,0 +1,109 @@
+/*
+Copyright (c) Microsoft Corporation.
+Licensed under the MIT license.
+*/
+
+// Package networkingmode tracks the networking mode of a member cluster, which fleet admins set with a label on the
+// MemberCluster in the hub cluster to restrict the member cluster to exporting or importing Services only.
+//
+// The hub agent mirrors the label onto the InternalMemberCluster, from which the member agent reads it into a Gate;
+// the member controllers check the Gate before they export or import a Service and are notified when the mode
+// changes, so that the mode takes effect without restarting the member agent.
+package networkingmode
+
+import (
+	"sync"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+)
+
+// Mode is the networking mode of a member cluster.
+type Mode string
+
+const (
+	// ModeBoth enables both the export and the import of Services; it is the mode of the member clusters which do
+	// not have the networking mode label.
+	ModeBoth Mode = "both"
+	// ModeExportOnly enables the export of Services only; the Services imported by the member cluster are withdrawn.
+	ModeExportOnly Mode = "export-only"
+	// ModeImportOnly enables the import of Services only; the Services exported by the member cluster are withdrawn.
+	ModeImportOnly Mode = "import-only"
+)
+
+// Parse returns the mode of the networking mode label value; an empty value is parsed as ModeBoth. It returns false
+// if the value is not a known mode.
+func Parse(value string) (Mode, bool) {
+	switch mode := Mode(value); mode {
+	case "":
+		return ModeBoth, true
+	case ModeBoth, ModeExportOnly, ModeImportOnly:
+		return mode, true
+	default:
+		return "", false
+	}
+}
+
+// Gate holds the current networking mode of the member cluster; it starts in ModeBoth.
+//
+// A nil Gate enables both the export and the import of Services, so that the controllers set up without a Gate
+// behave as before.
+type Gate struct {
+	mu          sync.RWMutex
+	mode        Mode
+	subscribers []chan event.GenericEvent
+}
+
+// NewGate returns a Gate in ModeBoth.
+func NewGate() *Gate {
+	return &Gate{mode: ModeBoth}
+}
+
+// Mode returns the current networking mode.
+func (g *Gate) Mode() Mode {
+	if g == nil {
+		return ModeBoth
+	}
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+	return g.mode
+}
+
+// ExportEnabled returns if the member cluster may export Services.
+func (g *Gate) ExportEnabled() bool {
+	return g.Mode() != ModeImportOnly
+}
+
+// ImportEnabled returns if the member cluster may import Services.
+func (g *Gate) ImportEnabled() bool {
+	return g.Mode() != ModeExportOnly
+}
+
+// Set sets the networking mode and notifies the subscribers if it has changed; it returns if the mode has changed.
+func (g *Gate) Set(mode Mode) bool {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if g.mode == mode {
+		return false
+	}
+	g.mode = mode
+	for _, ch := range g.subscribers {
+		// The subscribers read the current mode when they are notified, so a pending notification is enough.
+		select {
+		case ch <- event.GenericEvent{Object: &metav1.PartialObjectMetadata{}}:
+		default:
+		}
+	}
+	return true
+}
+
+// Subscribe returns a channel which receives an event every time the networking mode changes; the event carries no
+// object. It is meant to be used as a channel source by the controllers, which re-enqueue all the objects they
+// reconcile on the event.
+func (g *Gate) Subscribe() <-chan event.GenericEvent {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	ch := make(chan event.GenericEvent, 1)
+	g.subscribers = append(g.subscribers, ch)
+	return ch
+}
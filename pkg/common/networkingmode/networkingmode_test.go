@@ -0,0 +1,137 @@
+/*
+Copyright (c) Microsoft Corporation.
+Licensed under the MIT license.
+*/
+
+package networkingmode
+
+import (
+	"testing"
+)
+
+// TestParse tests the Parse function.
+func TestParse(t *testing.T) {
+	testCases := []struct {
+		name     string
+		value    string
+		wantMode Mode
+		wantOK   bool
+	}{
+		{
+			name:     "empty value",
+			wantMode: ModeBoth,
+			wantOK:   true,
+		},
+		{
+			name:     "both",
+			value:    "both",
+			wantMode: ModeBoth,
+			wantOK:   true,
+		},
+		{
+			name:     "export only",
+			value:    "export-only",
+			wantMode: ModeExportOnly,
+			wantOK:   true,
+		},
+		{
+			name:     "import only",
+			value:    "import-only",
+			wantMode: ModeImportOnly,
+			wantOK:   true,
+		},
+		{
+			name:  "unknown value",
+			value: "Export-Only",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			gotMode, gotOK := Parse(tc.value)
+			if gotMode != tc.wantMode || gotOK != tc.wantOK {
+				t.Errorf("Parse(%q) = (%q, %t), want (%q, %t)", tc.value, gotMode, gotOK, tc.wantMode, tc.wantOK)
+			}
+		})
+	}
+}
+
+// TestGate tests the Gate methods.
+func TestGate(t *testing.T) {
+	testCases := []struct {
+		name        string
+		mode        Mode
+		wantChanged bool
+		wantExport  bool
+		wantImport  bool
+	}{
+		{
+			name:       "unchanged",
+			mode:       ModeBoth,
+			wantExport: true,
+			wantImport: true,
+		},
+		{
+			name:        "export only",
+			mode:        ModeExportOnly,
+			wantChanged: true,
+			wantExport:  true,
+		},
+		{
+			name:        "import only",
+			mode:        ModeImportOnly,
+			wantChanged: true,
+			wantImport:  true,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewGate()
+			ch := g.Subscribe()
+			if gotChanged := g.Set(tc.mode); gotChanged != tc.wantChanged {
+				t.Fatalf("Set(%q) = %t, want %t", tc.mode, gotChanged, tc.wantChanged)
+			}
+			if got := g.ExportEnabled(); got != tc.wantExport {
+				t.Errorf("ExportEnabled() = %t, want %t", got, tc.wantExport)
+			}
+			if got := g.ImportEnabled(); got != tc.wantImport {
+				t.Errorf("ImportEnabled() = %t, want %t", got, tc.wantImport)
+			}
+			select {
+			case <-ch:
+				if !tc.wantChanged {
+					t.Errorf("Set(%q) notified the subscriber, want no notification", tc.mode)
+				}
+			default:
+				if tc.wantChanged {
+					t.Errorf("Set(%q) did not notify the subscriber", tc.mode)
+				}
+			}
+		})
+	}
+}
+
+// TestGate_Nil tests that a nil Gate enables both the export and the import of Services.
+func TestGate_Nil(t *testing.T) {
+	var g *Gate
+	if g.Mode() != ModeBoth || !g.ExportEnabled() || !g.ImportEnabled() {
+		t.Errorf("nil Gate, got mode %q, want %q with both the export and the import enabled", g.Mode(), ModeBoth)
+	}
+}
+
+// TestGate_PendingNotification tests that the changes of the mode do not block on a subscriber which has not read
+// its pending notification.
+func TestGate_PendingNotification(t *testing.T) {
+	g := NewGate()
+	ch := g.Subscribe()
+	g.Set(ModeExportOnly)
+	g.Set(ModeImportOnly)
+	<-ch
+	select {
+	case <-ch:
+		t.Errorf("got two notifications, want one pending notification")
+	default:
+	}
+	if got := g.Mode(); got != ModeImportOnly {
+		t.Errorf("Mode() = %q, want %q", got, ModeImportOnly)
+	}
+}
@@ -0,0 +1,43 @@
+/*
+Copyright (c) Microsoft Corporation.
+Licensed under the MIT license.
+*/
+
+// Package nsallowlist features the allowlist of the namespaces whose objects a controller acts on, independently of
+// the RBAC of the users creating them.
+package nsallowlist
+
+import (
+	"sort"
+	"strings"
+)
+
+// Allowlist is a set of namespaces. The zero value, as well as an empty allowlist, allows all the namespaces.
+type Allowlist map[string]bool
+
+// Parse returns the allowlist of the comma separated namespaces; the spaces around the namespaces and the empty
+// entries are ignored.
+func Parse(s string) Allowlist {
+	res := Allowlist{}
+	for _, ns := range strings.Split(s, ",") {
+		if ns = strings.TrimSpace(ns); ns != "" {
+			res[ns] = true
+		}
+	}
+	return res
+}
+
+// Allows returns true if the objects of the namespace may be acted on.
+func (a Allowlist) Allows(namespace string) bool {
+	return len(a) == 0 || a[namespace]
+}
+
+// String returns the sorted comma separated namespaces of the allowlist.
+func (a Allowlist) String() string {
+	res := make([]string, 0, len(a))
+	for ns := range a {
+		res = append(res, ns)
+	}
+	sort.Strings(res)
+	return strings.Join(res, ",")
+}
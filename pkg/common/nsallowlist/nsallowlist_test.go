@@ -0,0 +1,65 @@
+/*
+Copyright (c) Microsoft Corporation.
+Licensed under the MIT license.
+*/
+
+package nsallowlist
+
+import (
+	"testing"
+)
+
+func TestAllowlist(t *testing.T) {
+	tests := []struct {
+		name       string
+		value      string
+		namespace  string
+		want       bool
+		wantString string
+	}{
+		{
+			name:      "empty allowlist allows all the namespaces",
+			value:     "",
+			namespace: "team-a",
+			want:      true,
+		},
+		{
+			name:      "only separators allow all the namespaces",
+			value:     " , ,",
+			namespace: "team-a",
+			want:      true,
+		},
+		{
+			name:       "allowed namespace",
+			value:      "team-b, team-a",
+			namespace:  "team-a",
+			want:       true,
+			wantString: "team-a,team-b",
+		},
+		{
+			name:       "not allowed namespace",
+			value:      "team-b,team-a,",
+			namespace:  "team-c",
+			want:       false,
+			wantString: "team-a,team-b",
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			a := Parse(tc.value)
+			if got := a.Allows(tc.namespace); got != tc.want {
+				t.Errorf("Parse(%q).Allows(%q) = %t, want %t", tc.value, tc.namespace, got, tc.want)
+			}
+			if got := a.String(); got != tc.wantString {
+				t.Errorf("Parse(%q).String() = %q, want %q", tc.value, got, tc.wantString)
+			}
+		})
+	}
+}
+
+func TestAllowlist_Nil(t *testing.T) {
+	var a Allowlist
+	if !a.Allows("team-a") {
+		t.Errorf("nil Allowlist.Allows() = false, want true")
+	}
+}
@@ -0,0 +1,17 @@
+/*
+Copyright (c) Microsoft Corporation.
+Licensed under the MIT license.
+*/
+
+package objectmeta
+
+import metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+// ExportedServiceName returns the name under which the Service of a ServiceExport is exported to the fleet; it is the
+// name specified in the exported name annotation of the ServiceExport, if present, or the name of the Service itself.
+func ExportedServiceName(svcExport metav1.Object) string {
+	if exportedName, ok := svcExport.GetAnnotations()[ServiceExportAnnotationExportedName]; ok {
+		return exportedName
+	}
+	return svcExport.GetName()
+}
@@ -0,0 +1,49 @@
+/*
+Copyright (c) Microsoft Corporation.
+Licensed under the MIT license.
+*/
+
+package objectmeta
+
+import (
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// TestExportedServiceName tests the ExportedServiceName function.
+func TestExportedServiceName(t *testing.T) {
+	testCases := []struct {
+		name      string
+		svcExport *metav1.ObjectMeta
+		want      string
+	}{
+		{
+			name: "should return the service name",
+			svcExport: &metav1.ObjectMeta{
+				Namespace: "work",
+				Name:      "billing-v1",
+			},
+			want: "billing-v1",
+		},
+		{
+			name: "should return the exported name",
+			svcExport: &metav1.ObjectMeta{
+				Namespace: "work",
+				Name:      "billing-v1",
+				Annotations: map[string]string{
+					ServiceExportAnnotationExportedName: "billing",
+				},
+			},
+			want: "billing",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := ExportedServiceName(tc.svcExport); got != tc.want {
+				t.Fatalf("ExportedServiceName(%+v) = %s, want %s", tc.svcExport, got, tc.want)
+			}
+		})
+	}
+}
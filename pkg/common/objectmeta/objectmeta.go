@@ -26,6 +26,38 @@ const (
 	// TrafficManagerBackendFinalizer a finalizer added by the TrafficManagerBackend controller to all trafficManagerBackends,
 	// to make sure that the controller can react to backend deletions if necessary.
 	TrafficManagerBackendFinalizer = fleetNetworkingPrefix + "traffic-manager-backend-cleanup"
+
+	// ServiceImportCleanupFinalizer is the finalizer the InternalServiceImport controller adds to a ServiceImport in
+	// the hub cluster while a member cluster imports it, so that the import is withdrawn before the ServiceImport
+	// is deleted.
+	ServiceImportCleanupFinalizer = fleetNetworkingPrefix + "serviceimport-cleanup"
+)
+
+// Field managers
+const (
+	// MemberAgentFieldManager is the field manager the member agent uses when it applies the objects it owns,
+	// e.g. InternalServiceExports and EndpointSliceExports, to the hub cluster with server-side apply.
+	MemberAgentFieldManager = "fleet-networking-member-agent"
+
+	// MemberAgentLegacyFieldManager is the field manager the hub cluster recorded for the objects the member agent
+	// created or updated before it switched to server-side apply, derived from the user agent of the member agent.
+	MemberAgentLegacyFieldManager = "member-net-controller-manager"
+
+	// MemberAgentHealthProbeFieldManager is the field manager the member agent uses when it publishes the health of
+	// an exported Service to its InternalServiceExport, apart from the fields applied with MemberAgentFieldManager.
+	MemberAgentHealthProbeFieldManager = "fleet-networking-member-agent-health-probe"
+
+	// MemberAgentWeightFieldManager is the field manager the member agent uses when it patches the weight of an
+	// exported Service alone to its InternalServiceExport, as the rest of the spec is unchanged.
+	MemberAgentWeightFieldManager = "fleet-networking-member-agent-weight"
+
+	// HubAgentFieldManager is the field manager the hub agent uses when it applies the fields it owns on the objects
+	// written by the member agents with server-side apply, e.g. the status of InternalServiceImports.
+	HubAgentFieldManager = "fleet-networking-hub-agent"
+
+	// HubAgentLegacyFieldManager is the field manager the hub cluster recorded for the fields the hub agent updated
+	// before it switched to server-side apply, derived from the user agent of the hub agent.
+	HubAgentLegacyFieldManager = "hub-net-controller-manager"
 )
 
 // Labels
@@ -33,6 +65,32 @@ const (
 	// MultiClusterServiceLabelDerivedService is the label added by the MCS controller, which marks the
 	// derived Service behind a MCS.
 	MultiClusterServiceLabelDerivedService = fleetNetworkingPrefix + "derived-service"
+
+	// InternalServiceExportLabelState is the label added by the hub InternalServiceExport controller, which marks the
+	// current state of an InternalServiceExport, so that the exports in a given state can be listed across all the
+	// member cluster namespaces with a label selector.
+	InternalServiceExportLabelState = fleetNetworkingPrefix + "export-state"
+
+	// ExportedObjectLabelMemberCluster is the label added by the member agent to the objects it exports to the hub
+	// cluster, i.e. InternalServiceExports and EndpointSliceExports, whose value is the ID of the member cluster; the
+	// exports of a member cluster may be sharded across multiple hub namespaces and are listed with the label.
+	ExportedObjectLabelMemberCluster = fleetNetworkingPrefix + "member-cluster"
+
+	// MemberClusterLabelNetworkingMode is the label which fleet admins add to a MemberCluster to restrict the member
+	// cluster to exporting (`export-only`) or importing (`import-only`) Services; a member cluster without the label
+	// does both. The hub agent mirrors it onto the InternalMemberCluster, from which the member agent reads it.
+	MemberClusterLabelNetworkingMode = fleetNetworkingPrefix + "mode"
+)
+
+// Values of the InternalServiceExportLabelState label.
+const (
+	// InternalServiceExportStateValid marks an InternalServiceExport which does not conflict with the ServiceImport.
+	InternalServiceExportStateValid = "valid"
+	// InternalServiceExportStateConflicted marks an InternalServiceExport which conflicts with the ServiceImport.
+	InternalServiceExportStateConflicted = "conflicted"
+	// InternalServiceExportStateInvalid marks an InternalServiceExport which exports no port, and hence cannot be
+	// imported.
+	InternalServiceExportStateInvalid = "invalid"
 )
 
 // Annotations
@@ -41,6 +99,16 @@ const (
 	// of member clusters importing an exported Service.
 	ServiceImportAnnotationServiceInUseBy = fleetNetworkingPrefix + "service-in-use-by"
 
+	// ServiceImportAnnotationExpectedExporters is an annotation that marks the comma-separated list of member clusters
+	// which are expected to export the service; the clusters missing from the ServiceImport status are reported
+	// in the status.
+	ServiceImportAnnotationExpectedExporters = fleetNetworkingPrefix + "expected-exporters"
+
+	// ServiceImportAnnotationMultiClusterServices is an annotation that marks the comma-separated names of the
+	// MultiClusterServices referencing a ServiceImport in a member cluster; the ServiceImport is only deleted when the
+	// last of them is deleted.
+	ServiceImportAnnotationMultiClusterServices = fleetNetworkingPrefix + "multi-cluster-services"
+
 	// ExportedObjectAnnotationUniqueName is an annotation that marks the fleet-scoped unique name assigned to
 	// an exported object.
 	ExportedObjectAnnotationUniqueName = fleetNetworkingPrefix + "fleet-unique-name"
@@ -48,6 +116,99 @@ const (
 	// ServiceExportAnnotationWeight is an annotation that marks the weight of the ServiceExport.
 	ServiceExportAnnotationWeight = fleetNetworkingPrefix + "weight"
 
+	// ServiceExportAnnotationExportedName is an annotation that marks the name under which the Service is exported to
+	// the fleet; Services exported under the same name are aggregated into one ServiceImport in the hub cluster.
+	ServiceExportAnnotationExportedName = fleetNetworkingPrefix + "exported-name"
+
+	// ServiceExportAnnotationAllowedEndpointSliceNamespaces is an annotation that marks the comma-separated list of
+	// namespaces whose EndpointSlices may claim the exported Service as their owner with the
+	// EndpointSliceAnnotationOwnerServiceNamespace annotation.
+	ServiceExportAnnotationAllowedEndpointSliceNamespaces = fleetNetworkingPrefix + "allowed-endpointslice-namespaces"
+
+	// ServiceExportAnnotationAzurePublicIPResourceID is an annotation that marks the resource ID of the Azure public IP
+	// address exposing the Service, e.g. the public IP of a Gateway, when the Service is not exposed by a Load Balancer
+	// type Service itself. When set, the public IP is exported for the Traffic Manager feature instead of the one of the
+	// load balancer of the Service.
+	ServiceExportAnnotationAzurePublicIPResourceID = fleetNetworkingPrefix + "azure-pip-resource-id"
+
+	// ServiceExportAnnotationDeletionProtection is an annotation that opts a ServiceExport in to deletion protection
+	// when set to "true": once the ServiceExport is deleted while other member clusters still import the Service, the
+	// Service is unexported only after a grace period (see the --deletion-protection-grace-period flag of the member
+	// agent), so that operators are warned before the importers lose the Service.
+	ServiceExportAnnotationDeletionProtection = fleetNetworkingPrefix + "deletion-protection"
+
+	// ServiceExportAnnotationAllowHostNetworkEndpoints is an annotation that opts a ServiceExport in to exporting the
+	// endpoints backed by pods in the host network when set to "true"; the addresses of such endpoints are the IPs of
+	// their nodes, which are otherwise not exported to the fleet.
+	ServiceExportAnnotationAllowHostNetworkEndpoints = fleetNetworkingPrefix + "allow-hostnetwork-endpoints"
+
+	// ServiceExportAnnotationPreview is an annotation that puts a ServiceExport in the preview mode when set to "true":
+	// the changes to the Service (or the candidate spec in the ServiceExportAnnotationPreviewServiceSpec annotation)
+	// are not exported, and the outcome of their export is previewed in the status of the ServiceExport instead, until
+	// the annotation is removed.
+	ServiceExportAnnotationPreview = fleetNetworkingPrefix + "preview"
+
+	// ServiceExportAnnotationPreviewServiceSpec is an annotation that holds, in JSON, the candidate spec of the
+	// Service to preview in the preview mode, in place of the current spec of the Service.
+	ServiceExportAnnotationPreviewServiceSpec = fleetNetworkingPrefix + "preview-service-spec"
+
+	// InternalServiceExportAnnotationTrafficManagerInfoReported is an annotation that marks, when set to "true", an
+	// InternalServiceExport whose Azure Traffic Manager information (e.g. the public IP resource ID and whether a DNS
+	// label is configured) is reported by the member agent, so that the hub agent can tell the fields left unset by a
+	// member agent which predates them, or whose traffic manager feature is disabled, from the fields set to their
+	// zero values.
+	InternalServiceExportAnnotationTrafficManagerInfoReported = fleetNetworkingPrefix + "traffic-manager-info-reported"
+
+	// EndpointSliceExportAnnotationExcludedEndpoints is an annotation that marks the number of endpoints of the
+	// exported EndpointSlice which are excluded from the export as they are backed by pods in the host network.
+	EndpointSliceExportAnnotationExcludedEndpoints = fleetNetworkingPrefix + "excluded-endpoints"
+
+	// EndpointSliceExportAnnotationDeletionCandidateSince is an annotation that marks, with an RFC 3339 timestamp, an
+	// EndpointSliceExport which the member agent has withdrawn for a reason which may be transient, e.g. its
+	// ServiceExport being revalidated after an agent restart; the member agent marks the EndpointSliceExport instead of
+	// deleting it, and the hub agent deletes it only if it is not exported again, which clears the annotation, within
+	// a grace period (see the --endpointsliceexport-deletion-candidate-grace-period flag of the hub agent).
+	EndpointSliceExportAnnotationDeletionCandidateSince = fleetNetworkingPrefix + "deletion-candidate-since"
+
+	// EndpointSliceAnnotationOwnerServiceNamespace is an annotation that marks the namespace of the Service owning an
+	// EndpointSlice, for the EndpointSlices created in a namespace other than the one of their Service (e.g., by a
+	// service mirroring component); without it, the Service is looked up in the namespace of the EndpointSlice.
+	// Such EndpointSlices are exported only if the member agent allows their managed-by label value as well (see the
+	// --exported-endpointslice-managers flag), which by default allows kube-controller-manager only.
+	EndpointSliceAnnotationOwnerServiceNamespace = fleetNetworkingPrefix + "owner-service-namespace"
+
+	// EndpointSliceAnnotationImports is an annotation that marks the comma-separated names of the EndpointSliceImports
+	// whose endpoints an imported EndpointSlice holds.
+	EndpointSliceAnnotationImports = fleetNetworkingPrefix + "endpointslice-imports"
+
+	// EndpointSliceAnnotationOriginClusterID is an annotation that marks the ID of the cluster from which the endpoints
+	// of an imported EndpointSlice are imported.
+	EndpointSliceAnnotationOriginClusterID = fleetNetworkingPrefix + "origin-cluster-id"
+
+	// EndpointSliceAnnotationEndpointWeight is an annotation that marks the weight of the cluster from which the
+	// endpoints of an EndpointSliceImport, and of the EndpointSlices imported from it, are exported, for the dataplanes
+	// which can balance the traffic across the clusters by weight; it is absent when the exporting cluster does not set
+	// a weight, in which case the weight defaults to 1.
+	EndpointSliceAnnotationEndpointWeight = fleetNetworkingPrefix + "endpoint-weight"
+
+	// MemberClusterAnnotationRegion is an annotation that marks the Azure region of a member cluster; the member agent
+	// publishes it on its InternalMemberCluster and the hub agent mirrors it onto the MemberCluster.
+	MemberClusterAnnotationRegion = fleetNetworkingPrefix + "cluster-region"
+
+	// MemberClusterAnnotationVNetID is an annotation that marks the resource ID of the virtual network of a member
+	// cluster; it is published and mirrored the same way as MemberClusterAnnotationRegion.
+	MemberClusterAnnotationVNetID = fleetNetworkingPrefix + "cluster-vnet-id"
+
+	// NamespaceLabelExportAllowed is the label which fleet admins conventionally add to a member cluster namespace to
+	// onboard it to multi-cluster networking, when the member agent requires the namespaces to be labeled before
+	// their ServiceExports are honored, e.g. with `--required-namespace-labels=networking.fleet.azure.com/export-allowed=true`.
+	NamespaceLabelExportAllowed = fleetNetworkingPrefix + "export-allowed"
+
+	// DefaultsVersionAnnotation is an annotation that marks the version of the defaults applied to an object; the
+	// object keeps receiving the defaults of this version, so that changing a default later does not rewrite the
+	// existing objects.
+	DefaultsVersionAnnotation = fleetNetworkingPrefix + "defaults-version"
+
 	// ServiceAnnotationAzureLoadBalancerInternal is an annotation that marks the Service as an internal load balancer by cloud-provider-azure.
 	ServiceAnnotationAzureLoadBalancerInternal = "service.beta.kubernetes.io/azure-load-balancer-internal"
 
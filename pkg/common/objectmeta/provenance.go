@@ -0,0 +1,158 @@
+/*
+Copyright (c) Microsoft Corporation.
+Licensed under the MIT license.
+*/
+
+package objectmeta
+
+import (
+	"fmt"
+	"runtime/debug"
+	"sort"
+	"strings"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Provenance annotations record where an object written by the import path, i.e. an imported EndpointSlice or
+// a derived Service, comes from.
+const (
+	// ProvenanceAnnotationOriginClusterID is an annotation that marks the comma-separated IDs of the clusters from
+	// which an object is imported; it shares the key of EndpointSliceAnnotationOriginClusterID.
+	ProvenanceAnnotationOriginClusterID = EndpointSliceAnnotationOriginClusterID
+
+	// ProvenanceAnnotationOriginObject is an annotation that marks the comma-separated namespace/name of the exported
+	// objects from which an object is imported.
+	ProvenanceAnnotationOriginObject = fleetNetworkingPrefix + "origin-object"
+
+	// ProvenanceAnnotationOriginUID is an annotation that marks the comma-separated UIDs of the exported objects from
+	// which an object is imported.
+	ProvenanceAnnotationOriginUID = fleetNetworkingPrefix + "origin-uid"
+
+	// ProvenanceAnnotationExportedSince is an annotation that marks the earliest time, in RFC 3339 format, since
+	// which the exported objects from which an object is imported have been exported.
+	ProvenanceAnnotationExportedSince = fleetNetworkingPrefix + "exported-since"
+
+	// ProvenanceAnnotationAgentVersion is an annotation that marks the version of the fleet networking agent which
+	// last wrote an object.
+	ProvenanceAnnotationAgentVersion = fleetNetworkingPrefix + "agent-version"
+
+	// maxProvenanceValueLength caps the length of a provenance annotation value, so that an object imported from
+	// many exported objects does not bloat its annotations; the values beyond the cap are summarized as a count.
+	maxProvenanceValueLength = 256
+)
+
+// AgentVersion is the version of the fleet networking agent recorded in the provenance annotations, as read from
+// the build info of the binary.
+var AgentVersion = agentVersionFromBuildInfo()
+
+// ProvenanceSource is an exported object from which an object is imported; the unknown fields are left empty.
+type ProvenanceSource struct {
+	ClusterID     string
+	Namespace     string
+	Name          string
+	UID           string
+	ExportedSince time.Time
+}
+
+// SetProvenanceAnnotations sets the provenance annotations of an object imported from the given sources. The other
+// annotations of the object are left untouched, and the provenance annotations without a value are removed.
+func SetProvenanceAnnotations(obj metav1.Object, sources []ProvenanceSource) {
+	var clusterIDs, objects, uids []string
+	var exportedSince time.Time
+	for _, source := range sources {
+		clusterIDs = append(clusterIDs, source.ClusterID)
+		if source.Name != "" {
+			objects = append(objects, source.Namespace+"/"+source.Name)
+		}
+		uids = append(uids, source.UID)
+		if !source.ExportedSince.IsZero() && (exportedSince.IsZero() || source.ExportedSince.Before(exportedSince)) {
+			exportedSince = source.ExportedSince
+		}
+	}
+
+	values := map[string]string{
+		ProvenanceAnnotationOriginClusterID: formatProvenanceList(clusterIDs),
+		ProvenanceAnnotationOriginObject:    formatProvenanceList(objects),
+		ProvenanceAnnotationOriginUID:       formatProvenanceList(uids),
+		ProvenanceAnnotationAgentVersion:    AgentVersion,
+	}
+	if !exportedSince.IsZero() {
+		values[ProvenanceAnnotationExportedSince] = exportedSince.UTC().Format(time.RFC3339)
+	}
+
+	annotations := obj.GetAnnotations()
+	if annotations == nil {
+		annotations = map[string]string{}
+	}
+	for _, key := range []string{
+		ProvenanceAnnotationOriginClusterID,
+		ProvenanceAnnotationOriginObject,
+		ProvenanceAnnotationOriginUID,
+		ProvenanceAnnotationExportedSince,
+		ProvenanceAnnotationAgentVersion,
+	} {
+		if values[key] == "" {
+			delete(annotations, key)
+			continue
+		}
+		annotations[key] = values[key]
+	}
+	obj.SetAnnotations(annotations)
+}
+
+// formatProvenanceList returns the sorted, deduplicated, comma-separated non-empty values; the values which do not
+// fit in maxProvenanceValueLength are replaced with a "+N more" suffix.
+func formatProvenanceList(values []string) string {
+	set := make(map[string]bool, len(values))
+	unique := make([]string, 0, len(values))
+	for _, v := range values {
+		if v == "" || set[v] {
+			continue
+		}
+		set[v] = true
+		unique = append(unique, v)
+	}
+	sort.Strings(unique)
+
+	var b strings.Builder
+	for i, v := range unique {
+		suffix := ""
+		if rest := len(unique) - i - 1; rest > 0 {
+			// Reserve room for the summary of the remaining values, in case the next one does not fit.
+			suffix = fmt.Sprintf(",+%d more", rest)
+		}
+		sep := ""
+		if i > 0 {
+			sep = ","
+		}
+		if i > 0 && b.Len()+len(sep)+len(v)+len(suffix) > maxProvenanceValueLength {
+			fmt.Fprintf(&b, ",+%d more", len(unique)-i)
+			break
+		}
+		b.WriteString(sep)
+		b.WriteString(v)
+	}
+	return b.String()
+}
+
+// agentVersionFromBuildInfo returns the module version of the binary, or the VCS revision it is built from.
+func agentVersionFromBuildInfo() string {
+	info, ok := debug.ReadBuildInfo()
+	if !ok {
+		return "unknown"
+	}
+	if info.Main.Version != "" && info.Main.Version != "(devel)" {
+		return info.Main.Version
+	}
+	for _, setting := range info.Settings {
+		if setting.Key == "vcs.revision" && setting.Value != "" {
+			if len(setting.Value) > 12 {
+				return setting.Value[:12]
+			}
+			return setting.Value
+		}
+	}
+	return "devel"
+}
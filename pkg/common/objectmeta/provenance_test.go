@@ -0,0 +1,121 @@
+/*
+Copyright (c) Microsoft Corporation.
+Licensed under the MIT license.
+*/
+
+package objectmeta
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestSetProvenanceAnnotations(t *testing.T) {
+	exportedSince := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	testCases := []struct {
+		name            string
+		annotations     map[string]string
+		sources         []ProvenanceSource
+		wantAnnotations map[string]string
+	}{
+		{
+			name: "single source",
+			sources: []ProvenanceSource{
+				{ClusterID: "member-1", Namespace: "work", Name: "app-slice", UID: "uid-1", ExportedSince: exportedSince},
+			},
+			wantAnnotations: map[string]string{
+				ProvenanceAnnotationOriginClusterID: "member-1",
+				ProvenanceAnnotationOriginObject:    "work/app-slice",
+				ProvenanceAnnotationOriginUID:       "uid-1",
+				ProvenanceAnnotationExportedSince:   "2024-01-02T03:04:05Z",
+				ProvenanceAnnotationAgentVersion:    AgentVersion,
+			},
+		},
+		{
+			name: "multiple sources, unrelated annotations kept",
+			annotations: map[string]string{
+				"unrelated": "value",
+			},
+			sources: []ProvenanceSource{
+				{ClusterID: "member-2", Namespace: "work", Name: "app", UID: "uid-2", ExportedSince: exportedSince.Add(time.Hour)},
+				{ClusterID: "member-1", Namespace: "work", Name: "app", UID: "uid-1", ExportedSince: exportedSince},
+			},
+			wantAnnotations: map[string]string{
+				"unrelated":                         "value",
+				ProvenanceAnnotationOriginClusterID: "member-1,member-2",
+				ProvenanceAnnotationOriginObject:    "work/app",
+				ProvenanceAnnotationOriginUID:       "uid-1,uid-2",
+				ProvenanceAnnotationExportedSince:   "2024-01-02T03:04:05Z",
+				ProvenanceAnnotationAgentVersion:    AgentVersion,
+			},
+		},
+		{
+			name: "stale annotations without a value are removed",
+			annotations: map[string]string{
+				"unrelated":                       "value",
+				ProvenanceAnnotationOriginUID:     "uid-1",
+				ProvenanceAnnotationExportedSince: "2024-01-02T03:04:05Z",
+			},
+			sources: []ProvenanceSource{
+				{ClusterID: "member-1", Namespace: "work", Name: "app"},
+			},
+			wantAnnotations: map[string]string{
+				"unrelated":                         "value",
+				ProvenanceAnnotationOriginClusterID: "member-1",
+				ProvenanceAnnotationOriginObject:    "work/app",
+				ProvenanceAnnotationAgentVersion:    AgentVersion,
+			},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			obj := &metav1.ObjectMeta{Annotations: tc.annotations}
+			SetProvenanceAnnotations(obj, tc.sources)
+			if diff := cmp.Diff(tc.wantAnnotations, obj.GetAnnotations()); diff != "" {
+				t.Errorf("SetProvenanceAnnotations() annotations mismatch (-want, +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestFormatProvenanceList(t *testing.T) {
+	var many []string
+	for i := 0; i < 100; i++ {
+		many = append(many, fmt.Sprintf("00000000-0000-0000-0000-%012d", i))
+	}
+	testCases := []struct {
+		name   string
+		values []string
+		want   string
+	}{
+		{
+			name: "empty",
+		},
+		{
+			name:   "sorted and deduplicated",
+			values: []string{"b", "", "a", "b"},
+			want:   "a,b",
+		},
+		{
+			name:   "capped",
+			values: many,
+			want:   strings.Join(many[:6], ",") + ",+94 more",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got := formatProvenanceList(tc.values)
+			if got != tc.want {
+				t.Errorf("formatProvenanceList() = %q, want %q", got, tc.want)
+			}
+			if len(got) > maxProvenanceValueLength {
+				t.Errorf("formatProvenanceList() length = %d, want no more than %d", len(got), maxProvenanceValueLength)
+			}
+		})
+	}
+}
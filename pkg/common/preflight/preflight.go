@@ -0,0 +1,323 @@
+/*
+Copyright (c) Microsoft Corporation.
+Licensed under the MIT license.
+*/
+
+// Package preflight features the validations the agents run before joining a fleet, so that a misconfigured member
+// cluster (e.g., a wrong hub URL, a missing token secret or absent CRDs) is reported at once instead of by
+// crash-looping pods.
+package preflight
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	authorizationv1 "k8s.io/api/authorization/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+	"k8s.io/klog/v2"
+
+	"go.goms.io/fleet/pkg/utils/cloudconfig/azure"
+
+	"go.goms.io/fleet-networking/pkg/common/hubconfig"
+	"go.goms.io/fleet-networking/pkg/common/rbac"
+)
+
+var (
+	// newClientset creates the clientset of a cluster; it is a variable so that the tests can use fake clientsets.
+	newClientset = func(config *rest.Config) (kubernetes.Interface, error) {
+		return kubernetes.NewForConfig(config)
+	}
+)
+
+// Permission is an access the agent requires in a cluster.
+type Permission = rbac.Permission
+
+// Options are the configuration of the agent to validate.
+type Options struct {
+	// TLSClientInsecure is passed to hubconfig.PrepareHubConfig.
+	TLSClientInsecure bool
+	// GetMemberConfig returns the config to access the member cluster.
+	GetMemberConfig func() (*rest.Config, error)
+
+	// HubPermissions are the permissions the agent requires in its member cluster namespace of the hub cluster.
+	HubPermissions []Permission
+	// HubResources are the resources the agent requires the hub cluster to serve.
+	HubResources []schema.GroupVersionResource
+	// MemberPermissions are the cluster-wide permissions the agent requires in the member cluster.
+	MemberPermissions []Permission
+	// MemberResources are the resources the agent requires the member cluster to serve.
+	MemberResources []schema.GroupVersionResource
+	// ReservedNamespace, if set, is the reserved fleet namespace of the member cluster, which must exist.
+	ReservedNamespace string
+	// ReservedNamespacePermissions are the permissions the agent requires in the reserved namespace.
+	ReservedNamespacePermissions []Permission
+
+	// CloudConfigFile, if set, is the Azure cloud config file which must be valid.
+	CloudConfigFile string
+}
+
+// Result is the result of a single validation.
+type Result struct {
+	Name string
+	// Err is the failure of the validation; nil when it passes or is skipped.
+	Err error
+	// SkippedReason explains why the validation is skipped, e.g., when a validation it depends on has failed.
+	SkippedReason string
+}
+
+// Report is the result of all the validations.
+type Report []Result
+
+// Failed returns true if any of the validations has failed or been skipped.
+func (r Report) Failed() bool {
+	for _, res := range r {
+		if res.Err != nil || res.SkippedReason != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// Print writes the report in a human-readable form.
+func (r Report) Print(w io.Writer) {
+	for _, res := range r {
+		switch {
+		case res.Err != nil:
+			fmt.Fprintf(w, "[FAIL] %s: %v\n", res.Name, res.Err)
+		case res.SkippedReason != "":
+			fmt.Fprintf(w, "[SKIP] %s: %s\n", res.Name, res.SkippedReason)
+		default:
+			fmt.Fprintf(w, "[PASS] %s\n", res.Name)
+		}
+	}
+	if r.Failed() {
+		fmt.Fprintln(w, "Configuration validation failed")
+		return
+	}
+	fmt.Fprintln(w, "Configuration validation passed")
+}
+
+// Run runs all the validations; it does not stop at the first failure, so that the report covers every problem
+// which can be detected.
+func Run(ctx context.Context, opts Options) Report {
+	var report Report
+	add := func(name string, err error) bool {
+		report = append(report, Result{Name: name, Err: err})
+		return err == nil
+	}
+	skip := func(name, reason string) {
+		report = append(report, Result{Name: name, SkippedReason: reason})
+	}
+
+	hubNamespace, err := hubconfig.FetchMemberClusterNamespace()
+	add("member cluster name", err)
+
+	hubConfig, err := hubconfig.PrepareHubConfig(opts.TLSClientInsecure)
+	if add("hub config", err) {
+		checkCluster(ctx, "hub", hubConfig, hubNamespace, opts.HubPermissions, opts.HubResources, add, skip)
+	} else {
+		skipCluster("hub", opts.HubPermissions, opts.HubResources, "the hub config is not resolved", skip)
+	}
+
+	memberConfig, err := opts.GetMemberConfig()
+	var reservedNamespaceSkippedReason string
+	switch {
+	case !add("member config", err):
+		skipCluster("member", opts.MemberPermissions, opts.MemberResources, "the member config is not resolved", skip)
+		reservedNamespaceSkippedReason = "the member config is not resolved"
+	case !checkCluster(ctx, "member", memberConfig, "", opts.MemberPermissions, opts.MemberResources, add, skip):
+		reservedNamespaceSkippedReason = "the member cluster is not reachable"
+	}
+	if opts.ReservedNamespace != "" {
+		name := "member cluster reserved namespace"
+		if reservedNamespaceSkippedReason != "" {
+			skip(name, reservedNamespaceSkippedReason)
+		} else {
+			add(name, CheckReservedNamespace(ctx, memberConfig, opts.ReservedNamespace, opts.ReservedNamespacePermissions))
+		}
+	}
+
+	if opts.CloudConfigFile != "" {
+		_, err := azure.NewCloudConfigFromFile(opts.CloudConfigFile)
+		add("cloud config", err)
+	}
+	return report
+}
+
+// checkCluster validates the access to a cluster; it returns false if the cluster is not reachable.
+func checkCluster(ctx context.Context, cluster string, config *rest.Config, namespace string, permissions []Permission, resources []schema.GroupVersionResource, add func(string, error) bool, skip func(string, string)) bool {
+	clientset, err := newClientset(config)
+	if err == nil {
+		_, err = clientset.Discovery().ServerVersion()
+	}
+	if !add(cluster+" cluster reachability", err) {
+		skipCluster(cluster, permissions, resources, fmt.Sprintf("the %s cluster is not reachable", cluster), skip)
+		return false
+	}
+
+	if len(permissions) > 0 {
+		name := cluster + " cluster RBAC"
+		if cluster == "hub" && namespace == "" {
+			skip(name, "the member cluster namespace of the hub cluster is unknown")
+		} else {
+			add(name, checkPermissions(ctx, clientset, namespace, permissions))
+		}
+	}
+	if len(resources) > 0 {
+		add(cluster+" cluster CRDs", checkResources(clientset, resources))
+	}
+	return true
+}
+
+func skipCluster(cluster string, permissions []Permission, resources []schema.GroupVersionResource, reason string, skip func(string, string)) {
+	if len(permissions) > 0 {
+		skip(cluster+" cluster RBAC", reason)
+	}
+	if len(resources) > 0 {
+		skip(cluster+" cluster CRDs", reason)
+	}
+}
+
+// CheckReservedNamespace returns an error if the reserved fleet namespace does not exist in the cluster, or the agent
+// is denied any of the permissions in it; the agents run it at startup, as the objects they write to a namespace
+// that is missing, or not covered by their RBAC rules, would otherwise fail on every reconciliation.
+func CheckReservedNamespace(ctx context.Context, config *rest.Config, namespace string, permissions []Permission) error {
+	clientset, err := newClientset(config)
+	if err != nil {
+		return fmt.Errorf("failed to create the clientset: %w", err)
+	}
+	switch _, err := clientset.CoreV1().Namespaces().Get(ctx, namespace, metav1.GetOptions{}); {
+	case apierrors.IsNotFound(err):
+		return fmt.Errorf("reserved namespace %s does not exist", namespace)
+	case err != nil:
+		return fmt.Errorf("failed to get the reserved namespace %s: %w", namespace, err)
+	}
+	return checkPermissions(ctx, clientset, namespace, permissions)
+}
+
+// CheckMemberClusterNamespace returns an error if the namespace of the member cluster does not exist in the hub
+// cluster, which means the member cluster has not joined the fleet yet; the agents run it at startup so that they fail
+// with a clear error instead of retrying every hub request. The check is skipped if the agent is not allowed to get
+// the namespace, as the member agents are usually granted the access to their own namespace only.
+func CheckMemberClusterNamespace(ctx context.Context, config *rest.Config, namespace string) error {
+	clientset, err := newClientset(config)
+	if err != nil {
+		return fmt.Errorf("failed to create the clientset: %w", err)
+	}
+	switch _, err := clientset.CoreV1().Namespaces().Get(ctx, namespace, metav1.GetOptions{}); {
+	case apierrors.IsNotFound(err):
+		return fmt.Errorf("member cluster is not onboarded: namespace %s does not exist in the hub cluster", namespace)
+	case apierrors.IsForbidden(err):
+		klog.V(2).InfoS("Skipped checking the member cluster namespace in the hub cluster as the access is denied", "namespace", namespace)
+	case err != nil:
+		return fmt.Errorf("failed to get the member cluster namespace %s in the hub cluster: %w", namespace, err)
+	}
+	return nil
+}
+
+// VerifyRBAC reviews the permissions the agent requires cluster-wide in the member cluster and in each of the given
+// namespaces of the hub cluster, and returns a single error listing all the denied ones, so that the missing RBAC
+// rules are reported at once at startup instead of by Forbidden errors in the controllers.
+func VerifyRBAC(ctx context.Context, memberConfig, hubConfig *rest.Config, hubNamespaces []string, member, hub []Permission) error {
+	var problems []string
+	check := func(cluster string, config *rest.Config, namespace string, permissions []Permission) {
+		if len(permissions) == 0 {
+			return
+		}
+		clientset, err := newClientset(config)
+		if err == nil {
+			err = checkPermissions(ctx, clientset, namespace, permissions)
+		}
+		if err != nil {
+			problems = append(problems, fmt.Sprintf("%s cluster: %v", cluster, err))
+		}
+	}
+	check("member", memberConfig, "", member)
+	for _, ns := range hubNamespaces {
+		check("hub", hubConfig, ns, hub)
+	}
+	if len(problems) > 0 {
+		return fmt.Errorf("missing RBAC permissions: %s", strings.Join(problems, "; "))
+	}
+	return nil
+}
+
+// checkPermissions reviews the permissions with SelfSubjectAccessReviews and returns an error listing the denied ones.
+func checkPermissions(ctx context.Context, clientset kubernetes.Interface, namespace string, permissions []Permission) error {
+	var denied []string
+	for _, p := range permissions {
+		review := &authorizationv1.SelfSubjectAccessReview{
+			Spec: authorizationv1.SelfSubjectAccessReviewSpec{
+				ResourceAttributes: &authorizationv1.ResourceAttributes{
+					Namespace:   namespace,
+					Verb:        p.Verb,
+					Group:       p.Group,
+					Resource:    p.Resource,
+					Subresource: p.Subresource,
+				},
+			},
+		}
+		res, err := clientset.AuthorizationV1().SelfSubjectAccessReviews().Create(ctx, review, metav1.CreateOptions{})
+		if err != nil {
+			return fmt.Errorf("failed to review the permission to %s: %w", p, err)
+		}
+		if !res.Status.Allowed {
+			denied = append(denied, p.String())
+		}
+	}
+	if len(denied) > 0 {
+		where := "cluster-wide"
+		if namespace != "" {
+			where = "in namespace " + namespace
+		}
+		return fmt.Errorf("permissions denied %s: %s", where, strings.Join(denied, ", "))
+	}
+	return nil
+}
+
+// checkResources returns an error listing the resources which are not served.
+func checkResources(clientset kubernetes.Interface, resources []schema.GroupVersionResource) error {
+	served := make(map[schema.GroupVersion]map[string]bool)
+	var errs []error
+	var missing []string
+	for _, gvr := range resources {
+		gv := gvr.GroupVersion()
+		resourceNames, ok := served[gv]
+		if !ok {
+			list, err := clientset.Discovery().ServerResourcesForGroupVersion(gv.String())
+			switch {
+			case apierrors.IsNotFound(err):
+				// None of the resources of the version is served.
+				resourceNames = map[string]bool{}
+			case err != nil:
+				errs = append(errs, fmt.Errorf("failed to discover the resources of %s: %w", gv, err))
+				served[gv] = nil
+				continue
+			default:
+				resourceNames = make(map[string]bool, len(list.APIResources))
+				for _, r := range list.APIResources {
+					resourceNames[r.Name] = true
+				}
+			}
+			served[gv] = resourceNames
+		}
+		if resourceNames == nil && ok {
+			// The discovery of the version has failed and is already reported.
+			continue
+		}
+		if !resourceNames[gvr.Resource] {
+			missing = append(missing, fmt.Sprintf("%s in version %s", gvr.GroupResource(), gvr.Version))
+		}
+	}
+	if len(missing) > 0 {
+		errs = append(errs, fmt.Errorf("CRDs not installed: %s", strings.Join(missing, ", ")))
+	}
+	return errors.Join(errs...)
+}
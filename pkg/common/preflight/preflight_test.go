@@ -0,0 +1,560 @@
+/*
+Copyright (c) Microsoft Corporation.
+Licensed under the MIT license.
+*/
+
+package preflight
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/rest"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+const (
+	hubServerURL    = "https://hub.example.com"
+	memberServerURL = "https://member.example.com"
+	memberName      = "member-1"
+
+	validCloudConfig = `{
+  "cloud": "AzurePublicCloud",
+  "tenantId": "tenant",
+  "subscriptionId": "sub",
+  "useManagedIdentityExtension": true,
+  "location": "westus",
+  "resourceGroup": "rg"
+}`
+	// invalidCloudConfig misses the subscription ID; it is kept on a single line as the validation error quotes the
+	// file contents.
+	invalidCloudConfig = `{"cloud": "AzurePublicCloud", "tenantId": "tenant", "useManagedIdentityExtension": true, "location": "westus", "resourceGroup": "rg"}`
+)
+
+var (
+	internalMemberClusterGVR = schema.GroupVersionResource{Group: "fleet.azure.com", Version: "v1alpha1", Resource: "internalmemberclusters"}
+	endpointSliceExportGVR   = schema.GroupVersionResource{Group: "networking.fleet.azure.com", Version: "v1alpha1", Resource: "endpointsliceexports"}
+	serviceExportGVR         = schema.GroupVersionResource{Group: "networking.fleet.azure.com", Version: "v1alpha1", Resource: "serviceexports"}
+
+	hubPermissions = []Permission{
+		{Group: "fleet.azure.com", Resource: "internalmemberclusters", Verb: "get"},
+		{Group: "fleet.azure.com", Resource: "internalmemberclusters", Subresource: "status", Verb: "update"},
+	}
+	memberPermissions = []Permission{
+		{Group: "networking.fleet.azure.com", Resource: "serviceexports", Verb: "list"},
+	}
+)
+
+// fakeCluster configures the fake clientset of a cluster.
+type fakeCluster struct {
+	unreachable bool
+	// denied are the resources (with the subresources, if any) the agent has no access to.
+	denied []string
+	// served are the resources the cluster serves.
+	served []schema.GroupVersionResource
+	// wantNamespace is the namespace in which the access is reviewed; the access elsewhere is denied.
+	wantNamespace string
+}
+
+func (c fakeCluster) clientset() *fake.Clientset {
+	clientset := fake.NewSimpleClientset()
+	if c.unreachable {
+		clientset.PrependReactor("get", "version", func(_ k8stesting.Action) (bool, runtime.Object, error) {
+			return true, nil, errors.New("connection refused")
+		})
+	}
+	clientset.PrependReactor("create", "selfsubjectaccessreviews", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		review := action.(k8stesting.CreateAction).GetObject().(*authorizationv1.SelfSubjectAccessReview)
+		attrs := review.Spec.ResourceAttributes
+		resource := attrs.Resource
+		if attrs.Subresource != "" {
+			resource += "/" + attrs.Subresource
+		}
+		allowed := attrs.Namespace == c.wantNamespace
+		for _, d := range c.denied {
+			if d == resource {
+				allowed = false
+			}
+		}
+		review = review.DeepCopy()
+		review.Status.Allowed = allowed
+		return true, review, nil
+	})
+	lists := make(map[schema.GroupVersion]*metav1.APIResourceList)
+	for _, gvr := range c.served {
+		gv := gvr.GroupVersion()
+		if lists[gv] == nil {
+			lists[gv] = &metav1.APIResourceList{GroupVersion: gv.String()}
+			clientset.Resources = append(clientset.Resources, lists[gv])
+		}
+		lists[gv].APIResources = append(lists[gv].APIResources, metav1.APIResource{Name: gvr.Resource, Namespaced: true})
+	}
+	return clientset
+}
+
+func setEnv(t *testing.T, key, value string, present bool) {
+	t.Helper()
+	// t.Setenv restores the original value when the test completes.
+	t.Setenv(key, value)
+	if !present {
+		if err := os.Unsetenv(key); err != nil {
+			t.Fatalf("failed to unset %s: %v", key, err)
+		}
+	}
+}
+
+func TestRun(t *testing.T) {
+	healthyHub := fakeCluster{
+		served:        []schema.GroupVersionResource{internalMemberClusterGVR, endpointSliceExportGVR},
+		wantNamespace: "fleet-member-" + memberName,
+	}
+	healthyMember := fakeCluster{
+		served: []schema.GroupVersionResource{serviceExportGVR},
+	}
+
+	testCases := []struct {
+		name            string
+		noMemberName    bool
+		noHubServerURL  bool
+		noTokenFile     bool
+		hub             fakeCluster
+		member          fakeCluster
+		memberConfigErr error
+		cloudConfig     string
+		want            []string
+		wantFailed      bool
+	}{
+		{
+			name:        "all the validations pass",
+			hub:         healthyHub,
+			member:      healthyMember,
+			cloudConfig: validCloudConfig,
+			want: []string{
+				"[PASS] member cluster name",
+				"[PASS] hub config",
+				"[PASS] hub cluster reachability",
+				"[PASS] hub cluster RBAC",
+				"[PASS] hub cluster CRDs",
+				"[PASS] member config",
+				"[PASS] member cluster reachability",
+				"[PASS] member cluster RBAC",
+				"[PASS] member cluster CRDs",
+				"[PASS] cloud config",
+				"Configuration validation passed",
+			},
+		},
+		{
+			name:         "member cluster name is missing",
+			noMemberName: true,
+			hub:          healthyHub,
+			member:       healthyMember,
+			want: []string{
+				"[FAIL] member cluster name: failed to retrieve the environment variable value from MEMBER_CLUSTER_NAME",
+				"[PASS] hub config",
+				"[PASS] hub cluster reachability",
+				"[SKIP] hub cluster RBAC: the member cluster namespace of the hub cluster is unknown",
+				"[PASS] hub cluster CRDs",
+				"[PASS] member config",
+				"[PASS] member cluster reachability",
+				"[PASS] member cluster RBAC",
+				"[PASS] member cluster CRDs",
+				"Configuration validation failed",
+			},
+			wantFailed: true,
+		},
+		{
+			name:           "hub server URL is missing",
+			noHubServerURL: true,
+			hub:            healthyHub,
+			member:         healthyMember,
+			want: []string{
+				"[PASS] member cluster name",
+				"[FAIL] hub config: failed to retrieve the environment variable value from HUB_SERVER_URL",
+				"[SKIP] hub cluster RBAC: the hub config is not resolved",
+				"[SKIP] hub cluster CRDs: the hub config is not resolved",
+				"[PASS] member config",
+				"[PASS] member cluster reachability",
+				"[PASS] member cluster RBAC",
+				"[PASS] member cluster CRDs",
+				"Configuration validation failed",
+			},
+			wantFailed: true,
+		},
+		{
+			name:        "hub token file is missing",
+			noTokenFile: true,
+			hub:         healthyHub,
+			member:      healthyMember,
+			want: []string{
+				"[PASS] member cluster name",
+				"[FAIL] hub config: ",
+				"[SKIP] hub cluster RBAC: the hub config is not resolved",
+				"[SKIP] hub cluster CRDs: the hub config is not resolved",
+				"[PASS] member config",
+				"[PASS] member cluster reachability",
+				"[PASS] member cluster RBAC",
+				"[PASS] member cluster CRDs",
+				"Configuration validation failed",
+			},
+			wantFailed: true,
+		},
+		{
+			name:   "hub cluster is not reachable",
+			hub:    fakeCluster{unreachable: true},
+			member: healthyMember,
+			want: []string{
+				"[PASS] member cluster name",
+				"[PASS] hub config",
+				"[FAIL] hub cluster reachability: connection refused",
+				"[SKIP] hub cluster RBAC: the hub cluster is not reachable",
+				"[SKIP] hub cluster CRDs: the hub cluster is not reachable",
+				"[PASS] member config",
+				"[PASS] member cluster reachability",
+				"[PASS] member cluster RBAC",
+				"[PASS] member cluster CRDs",
+				"Configuration validation failed",
+			},
+			wantFailed: true,
+		},
+		{
+			name: "hub permission is denied",
+			hub: fakeCluster{
+				served:        healthyHub.served,
+				wantNamespace: healthyHub.wantNamespace,
+				denied:        []string{"internalmemberclusters/status"},
+			},
+			member: healthyMember,
+			want: []string{
+				"[PASS] member cluster name",
+				"[PASS] hub config",
+				"[PASS] hub cluster reachability",
+				"[FAIL] hub cluster RBAC: permissions denied in namespace fleet-member-member-1: update internalmemberclusters.fleet.azure.com/status",
+				"[PASS] hub cluster CRDs",
+				"[PASS] member config",
+				"[PASS] member cluster reachability",
+				"[PASS] member cluster RBAC",
+				"[PASS] member cluster CRDs",
+				"Configuration validation failed",
+			},
+			wantFailed: true,
+		},
+		{
+			name: "hub CRD is not installed",
+			hub: fakeCluster{
+				served:        []schema.GroupVersionResource{internalMemberClusterGVR},
+				wantNamespace: healthyHub.wantNamespace,
+			},
+			member: fakeCluster{},
+			want: []string{
+				"[PASS] member cluster name",
+				"[PASS] hub config",
+				"[PASS] hub cluster reachability",
+				"[PASS] hub cluster RBAC",
+				"[FAIL] hub cluster CRDs: CRDs not installed: endpointsliceexports.networking.fleet.azure.com in version v1alpha1",
+				"[PASS] member config",
+				"[PASS] member cluster reachability",
+				"[PASS] member cluster RBAC",
+				"[FAIL] member cluster CRDs: CRDs not installed: serviceexports.networking.fleet.azure.com in version v1alpha1",
+				"Configuration validation failed",
+			},
+			wantFailed: true,
+		},
+		{
+			name:            "member config is not resolved",
+			hub:             healthyHub,
+			memberConfigErr: errors.New("no kubeconfig"),
+			want: []string{
+				"[PASS] member cluster name",
+				"[PASS] hub config",
+				"[PASS] hub cluster reachability",
+				"[PASS] hub cluster RBAC",
+				"[PASS] hub cluster CRDs",
+				"[FAIL] member config: no kubeconfig",
+				"[SKIP] member cluster RBAC: the member config is not resolved",
+				"[SKIP] member cluster CRDs: the member config is not resolved",
+				"Configuration validation failed",
+			},
+			wantFailed: true,
+		},
+		{
+			name:        "cloud config is invalid",
+			hub:         healthyHub,
+			member:      healthyMember,
+			cloudConfig: invalidCloudConfig,
+			want: []string{
+				"[PASS] member cluster name",
+				"[PASS] hub config",
+				"[PASS] hub cluster reachability",
+				"[PASS] hub cluster RBAC",
+				"[PASS] hub cluster CRDs",
+				"[PASS] member config",
+				"[PASS] member cluster reachability",
+				"[PASS] member cluster RBAC",
+				"[PASS] member cluster CRDs",
+				"[FAIL] cloud config: ",
+				"Configuration validation failed",
+			},
+			wantFailed: true,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			dir := t.TempDir()
+			tokenFile := filepath.Join(dir, "token")
+			if !tc.noTokenFile {
+				if err := os.WriteFile(tokenFile, []byte("token"), 0600); err != nil {
+					t.Fatalf("failed to write the token file: %v", err)
+				}
+			}
+			setEnv(t, "MEMBER_CLUSTER_NAME", memberName, !tc.noMemberName)
+			setEnv(t, "HUB_SERVER_URL", hubServerURL, !tc.noHubServerURL)
+			setEnv(t, "CONFIG_PATH", tokenFile, true)
+			setEnv(t, "HUB_CERTIFICATE_AUTHORITY", "", false)
+
+			clientsets := map[string]kubernetes.Interface{
+				hubServerURL:    tc.hub.clientset(),
+				memberServerURL: tc.member.clientset(),
+			}
+			originalNewClientset := newClientset
+			defer func() { newClientset = originalNewClientset }()
+			newClientset = func(config *rest.Config) (kubernetes.Interface, error) {
+				clientset, ok := clientsets[config.Host]
+				if !ok {
+					return nil, fmt.Errorf("unexpected host %s", config.Host)
+				}
+				return clientset, nil
+			}
+
+			opts := Options{
+				GetMemberConfig: func() (*rest.Config, error) {
+					if tc.memberConfigErr != nil {
+						return nil, tc.memberConfigErr
+					}
+					return &rest.Config{Host: memberServerURL}, nil
+				},
+				HubPermissions:    hubPermissions,
+				HubResources:      []schema.GroupVersionResource{internalMemberClusterGVR, endpointSliceExportGVR},
+				MemberPermissions: memberPermissions,
+				MemberResources:   []schema.GroupVersionResource{serviceExportGVR},
+			}
+			if tc.cloudConfig != "" {
+				opts.CloudConfigFile = filepath.Join(dir, "azure.json")
+				if err := os.WriteFile(opts.CloudConfigFile, []byte(tc.cloudConfig), 0600); err != nil {
+					t.Fatalf("failed to write the cloud config file: %v", err)
+				}
+			}
+
+			report := Run(context.Background(), opts)
+			if got := report.Failed(); got != tc.wantFailed {
+				t.Errorf("Failed() = %v, want %v", got, tc.wantFailed)
+			}
+			var buf bytes.Buffer
+			report.Print(&buf)
+			got := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+			if len(got) != len(tc.want) {
+				t.Fatalf("Print() got lines %v, want %v", got, tc.want)
+			}
+			// The lines of the failures whose messages come from other packages are compared by their prefixes.
+			trimmed := make([]string, len(got))
+			for i := range got {
+				trimmed[i] = got[i]
+				if strings.HasSuffix(tc.want[i], ": ") && strings.HasPrefix(got[i], tc.want[i]) {
+					trimmed[i] = tc.want[i]
+				}
+			}
+			if diff := cmp.Diff(tc.want, trimmed); diff != "" {
+				t.Errorf("Print() mismatch (-want, +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestCheckReservedNamespace(t *testing.T) {
+	reservedNamespace := "custom-fleet-system"
+	permissions := []Permission{
+		{Group: "discovery.k8s.io", Resource: "endpointslices", Verb: "create"},
+		{Group: "", Resource: "services", Verb: "watch"},
+	}
+
+	testCases := []struct {
+		name            string
+		namespaceExists bool
+		cluster         fakeCluster
+		wantErr         string
+	}{
+		{
+			name:            "namespace exists and permissions are granted",
+			namespaceExists: true,
+			cluster:         fakeCluster{wantNamespace: reservedNamespace},
+		},
+		{
+			name:    "namespace does not exist",
+			cluster: fakeCluster{wantNamespace: reservedNamespace},
+			wantErr: "reserved namespace custom-fleet-system does not exist",
+		},
+		{
+			name:            "permissions are granted in another namespace only",
+			namespaceExists: true,
+			cluster:         fakeCluster{wantNamespace: "fleet-system"},
+			wantErr:         "permissions denied in namespace custom-fleet-system: create endpointslices.discovery.k8s.io, watch services",
+		},
+		{
+			name:            "permission is denied",
+			namespaceExists: true,
+			cluster:         fakeCluster{wantNamespace: reservedNamespace, denied: []string{"endpointslices"}},
+			wantErr:         "permissions denied in namespace custom-fleet-system: create endpointslices.discovery.k8s.io",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			clientset := tc.cluster.clientset()
+			if tc.namespaceExists {
+				ns := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: reservedNamespace}}
+				if err := clientset.Tracker().Add(ns); err != nil {
+					t.Fatalf("failed to add the namespace: %v", err)
+				}
+			}
+			originalNewClientset := newClientset
+			defer func() { newClientset = originalNewClientset }()
+			newClientset = func(_ *rest.Config) (kubernetes.Interface, error) {
+				return clientset, nil
+			}
+
+			err := CheckReservedNamespace(context.Background(), &rest.Config{Host: memberServerURL}, reservedNamespace, permissions)
+			switch {
+			case tc.wantErr == "" && err != nil:
+				t.Errorf("CheckReservedNamespace() = %v, want no error", err)
+			case tc.wantErr != "" && (err == nil || err.Error() != tc.wantErr):
+				t.Errorf("CheckReservedNamespace() = %v, want %q", err, tc.wantErr)
+			}
+		})
+	}
+}
+
+func TestCheckMemberClusterNamespace(t *testing.T) {
+	hubNamespace := "fleet-member-" + memberName
+
+	testCases := []struct {
+		name            string
+		namespaceExists bool
+		getErr          error
+		wantErr         string
+	}{
+		{
+			name:            "namespace exists",
+			namespaceExists: true,
+		},
+		{
+			name:    "namespace does not exist",
+			wantErr: "member cluster is not onboarded: namespace fleet-member-member-1 does not exist in the hub cluster",
+		},
+		{
+			name:   "access to the namespace is denied",
+			getErr: apierrors.NewForbidden(corev1.Resource("namespaces"), hubNamespace, errors.New("denied")),
+		},
+		{
+			name:    "failed to get the namespace",
+			getErr:  errors.New("connection refused"),
+			wantErr: "failed to get the member cluster namespace fleet-member-member-1 in the hub cluster: connection refused",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			clientset := fake.NewSimpleClientset()
+			if tc.namespaceExists {
+				ns := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: hubNamespace}}
+				if err := clientset.Tracker().Add(ns); err != nil {
+					t.Fatalf("failed to add the namespace: %v", err)
+				}
+			}
+			if tc.getErr != nil {
+				clientset.PrependReactor("get", "namespaces", func(_ k8stesting.Action) (bool, runtime.Object, error) {
+					return true, nil, tc.getErr
+				})
+			}
+			originalNewClientset := newClientset
+			defer func() { newClientset = originalNewClientset }()
+			newClientset = func(_ *rest.Config) (kubernetes.Interface, error) {
+				return clientset, nil
+			}
+
+			err := CheckMemberClusterNamespace(context.Background(), &rest.Config{Host: hubServerURL}, hubNamespace)
+			switch {
+			case tc.wantErr == "" && err != nil:
+				t.Errorf("CheckMemberClusterNamespace() = %v, want no error", err)
+			case tc.wantErr != "" && (err == nil || err.Error() != tc.wantErr):
+				t.Errorf("CheckMemberClusterNamespace() = %v, want %q", err, tc.wantErr)
+			}
+		})
+	}
+}
+
+func TestVerifyRBAC(t *testing.T) {
+	hubNamespace := "fleet-member-" + memberName
+	shardNamespace := hubNamespace + "-shard-1"
+
+	testCases := []struct {
+		name          string
+		member        fakeCluster
+		hub           fakeCluster
+		hubNamespaces []string
+		wantErr       string
+	}{
+		{
+			name:          "permissions are granted",
+			member:        fakeCluster{},
+			hub:           fakeCluster{wantNamespace: hubNamespace},
+			hubNamespaces: []string{hubNamespace},
+		},
+		{
+			name:          "permissions are denied in both clusters",
+			member:        fakeCluster{denied: []string{"serviceexports"}},
+			hub:           fakeCluster{wantNamespace: hubNamespace, denied: []string{"internalmemberclusters/status"}},
+			hubNamespaces: []string{hubNamespace},
+			wantErr:       "missing RBAC permissions: member cluster: permissions denied cluster-wide: list serviceexports.networking.fleet.azure.com; hub cluster: permissions denied in namespace fleet-member-member-1: update internalmemberclusters.fleet.azure.com/status",
+		},
+		{
+			name:          "permissions are denied in a shard namespace",
+			member:        fakeCluster{},
+			hub:           fakeCluster{wantNamespace: hubNamespace},
+			hubNamespaces: []string{hubNamespace, shardNamespace},
+			wantErr:       "missing RBAC permissions: hub cluster: permissions denied in namespace fleet-member-member-1-shard-1: get internalmemberclusters.fleet.azure.com, update internalmemberclusters.fleet.azure.com/status",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			clientsets := map[string]kubernetes.Interface{
+				hubServerURL:    tc.hub.clientset(),
+				memberServerURL: tc.member.clientset(),
+			}
+			originalNewClientset := newClientset
+			defer func() { newClientset = originalNewClientset }()
+			newClientset = func(config *rest.Config) (kubernetes.Interface, error) {
+				return clientsets[config.Host], nil
+			}
+
+			err := VerifyRBAC(context.Background(), &rest.Config{Host: memberServerURL}, &rest.Config{Host: hubServerURL}, tc.hubNamespaces, memberPermissions, hubPermissions)
+			switch {
+			case tc.wantErr == "" && err != nil:
+				t.Errorf("VerifyRBAC() = %v, want no error", err)
+			case tc.wantErr != "" && (err == nil || err.Error() != tc.wantErr):
+				t.Errorf("VerifyRBAC() = %v, want %q", err, tc.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,147 @@
+/*
+Copyright (c) Microsoft Corporation.
+Licensed under the MIT license.
+*/
+
+// Package rbac features the permission matrix of the agents, i.e. the RBAC rules each controller requires in the
+// member and the hub clusters. The rules of a controller mirror its kubebuilder RBAC markers, from which the role
+// manifests are generated, so that the permissions the agents verify at startup match the ones they are granted.
+package rbac
+
+import (
+	"bufio"
+	"fmt"
+	"sort"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// markerPrefix is the prefix of the kubebuilder RBAC markers.
+const markerPrefix = "//+kubebuilder:rbac:"
+
+// Permission is an access the agent requires in a cluster.
+type Permission struct {
+	Group    string
+	Resource string
+	// Subresource is the subresource of the resource, e.g., "status"; empty for the resource itself.
+	Subresource string
+	Verb        string
+}
+
+func (p Permission) String() string {
+	resource := schema.GroupResource{Group: p.Group, Resource: p.Resource}.String()
+	if p.Subresource != "" {
+		resource += "/" + p.Subresource
+	}
+	return fmt.Sprintf("%s %s", p.Verb, resource)
+}
+
+// Rule grants every verb on every resource of an API group, the same way as a kubebuilder RBAC marker does.
+type Rule struct {
+	Group string
+	// Resources are the resources, with the subresource if any, e.g., "serviceexports/status".
+	Resources []string
+	Verbs     []string
+}
+
+// Permissions returns the permissions granted by the rule.
+func (r Rule) Permissions() []Permission {
+	permissions := make([]Permission, 0, len(r.Resources)*len(r.Verbs))
+	for _, res := range r.Resources {
+		resource, subresource, _ := strings.Cut(res, "/")
+		for _, verb := range r.Verbs {
+			permissions = append(permissions, Permission{Group: r.Group, Resource: resource, Subresource: subresource, Verb: verb})
+		}
+	}
+	return permissions
+}
+
+// Controller is the entry of a controller in the permission matrix.
+type Controller struct {
+	Name string
+	// Package is the directory of the controller relative to the repository root; the RBAC markers of the Go files in
+	// it must declare the union of MemberRules and HubRules.
+	Package string
+	// MemberRules are the rules the controller requires cluster-wide in the member cluster.
+	MemberRules []Rule
+	// HubRules are the rules the controller requires in the member cluster namespaces of the hub cluster.
+	HubRules []Rule
+}
+
+// BuildMatrix returns the permissions the controllers require in the member and the hub clusters, deduplicated and
+// sorted.
+func BuildMatrix(controllers []Controller) (member, hub []Permission) {
+	var memberRules, hubRules []Rule
+	for _, c := range controllers {
+		memberRules = append(memberRules, c.MemberRules...)
+		hubRules = append(hubRules, c.HubRules...)
+	}
+	return Expand(memberRules), Expand(hubRules)
+}
+
+// Expand returns the permissions granted by the rules, deduplicated and sorted.
+func Expand(rules []Rule) []Permission {
+	seen := make(map[Permission]bool)
+	var permissions []Permission
+	for _, r := range rules {
+		for _, p := range r.Permissions() {
+			if !seen[p] {
+				seen[p] = true
+				permissions = append(permissions, p)
+			}
+		}
+	}
+	sort.Slice(permissions, func(i, j int) bool {
+		a, b := permissions[i], permissions[j]
+		if a.Group != b.Group {
+			return a.Group < b.Group
+		}
+		if a.Resource != b.Resource {
+			return a.Resource < b.Resource
+		}
+		if a.Subresource != b.Subresource {
+			return a.Subresource < b.Subresource
+		}
+		return a.Verb < b.Verb
+	})
+	return permissions
+}
+
+// ParseMarkers returns the rules declared by the kubebuilder RBAC markers in the Go source, e.g.
+// //+kubebuilder:rbac:groups="",resources=services,verbs=get;list;watch.
+func ParseMarkers(src string) ([]Rule, error) {
+	var rules []Rule
+	scanner := bufio.NewScanner(strings.NewReader(src))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if !strings.HasPrefix(line, markerPrefix) {
+			continue
+		}
+		var groups, resources, verbs []string
+		for _, arg := range strings.Split(strings.TrimPrefix(line, markerPrefix), ",") {
+			key, value, ok := strings.Cut(arg, "=")
+			if !ok {
+				return nil, fmt.Errorf("invalid RBAC marker %q: argument %q is not key=value", line, arg)
+			}
+			values := strings.Split(strings.Trim(value, `"`), ";")
+			switch key {
+			case "groups":
+				groups = values
+			case "resources":
+				resources = values
+			case "verbs":
+				verbs = values
+			default:
+				return nil, fmt.Errorf("invalid RBAC marker %q: unsupported argument %q", line, key)
+			}
+		}
+		if groups == nil || resources == nil || verbs == nil {
+			return nil, fmt.Errorf("invalid RBAC marker %q: groups, resources and verbs are required", line)
+		}
+		for _, group := range groups {
+			rules = append(rules, Rule{Group: group, Resources: resources, Verbs: verbs})
+		}
+	}
+	return rules, scanner.Err()
+}
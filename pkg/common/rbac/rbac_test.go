@@ -0,0 +1,112 @@
+/*
+Copyright (c) Microsoft Corporation.
+Licensed under the MIT license.
+*/
+
+package rbac
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+// TestBuildMatrix tests the BuildMatrix function.
+func TestBuildMatrix(t *testing.T) {
+	controllers := []Controller{
+		{
+			Name: "serviceexport",
+			MemberRules: []Rule{
+				{Group: "networking.fleet.azure.com", Resources: []string{"serviceexports/status"}, Verbs: []string{"update"}},
+				{Group: "", Resources: []string{"services"}, Verbs: []string{"watch", "get"}},
+			},
+			HubRules: []Rule{
+				{Group: "networking.fleet.azure.com", Resources: []string{"internalserviceexports"}, Verbs: []string{"patch"}},
+			},
+		},
+		{
+			Name: "endpointslice",
+			MemberRules: []Rule{
+				{Group: "", Resources: []string{"services"}, Verbs: []string{"get"}},
+				{Group: "networking.fleet.azure.com", Resources: []string{"serviceexports"}, Verbs: []string{"get"}},
+			},
+			HubRules: []Rule{
+				{Group: "networking.fleet.azure.com", Resources: []string{"endpointsliceexports", "internalserviceexports"}, Verbs: []string{"patch"}},
+			},
+		},
+	}
+	wantMember := []Permission{
+		{Group: "", Resource: "services", Verb: "get"},
+		{Group: "", Resource: "services", Verb: "watch"},
+		{Group: "networking.fleet.azure.com", Resource: "serviceexports", Verb: "get"},
+		{Group: "networking.fleet.azure.com", Resource: "serviceexports", Subresource: "status", Verb: "update"},
+	}
+	wantHub := []Permission{
+		{Group: "networking.fleet.azure.com", Resource: "endpointsliceexports", Verb: "patch"},
+		{Group: "networking.fleet.azure.com", Resource: "internalserviceexports", Verb: "patch"},
+	}
+
+	gotMember, gotHub := BuildMatrix(controllers)
+	if diff := cmp.Diff(wantMember, gotMember); diff != "" {
+		t.Errorf("BuildMatrix() member permissions mismatch (-want, +got):\n%s", diff)
+	}
+	if diff := cmp.Diff(wantHub, gotHub); diff != "" {
+		t.Errorf("BuildMatrix() hub permissions mismatch (-want, +got):\n%s", diff)
+	}
+}
+
+// TestParseMarkers tests the ParseMarkers function.
+func TestParseMarkers(t *testing.T) {
+	testCases := []struct {
+		name    string
+		src     string
+		want    []Rule
+		wantErr bool
+	}{
+		{
+			name: "markers",
+			src: `package serviceexport
+
+// Reconciler reconciles a ServiceExport object.
+type Reconciler struct{}
+
+//+kubebuilder:rbac:groups=networking.fleet.azure.com,resources=serviceexports;serviceexports/status,verbs=get;update
+//+kubebuilder:rbac:groups="",resources=events,verbs=create;patch
+	//+kubebuilder:rbac:groups=apps;batch,resources=deployments,verbs=get
+
+// Reconcile exports a Service.
+`,
+			want: []Rule{
+				{Group: "networking.fleet.azure.com", Resources: []string{"serviceexports", "serviceexports/status"}, Verbs: []string{"get", "update"}},
+				{Group: "", Resources: []string{"events"}, Verbs: []string{"create", "patch"}},
+				{Group: "apps", Resources: []string{"deployments"}, Verbs: []string{"get"}},
+				{Group: "batch", Resources: []string{"deployments"}, Verbs: []string{"get"}},
+			},
+		},
+		{
+			name: "no marker",
+			src:  "package serviceexport\n",
+		},
+		{
+			name:    "missing verbs",
+			src:     `//+kubebuilder:rbac:groups="",resources=events`,
+			wantErr: true,
+		},
+		{
+			name:    "unsupported argument",
+			src:     `//+kubebuilder:rbac:groups="",resources=events,verbs=get,namespace=fleet-system`,
+			wantErr: true,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := ParseMarkers(tc.src)
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("ParseMarkers() = %v, want error %t", err, tc.wantErr)
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("ParseMarkers() mismatch (-want, +got):\n%s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,121 @@
+/*
+Copyright (c) Microsoft Corporation.
+Licensed under the MIT license.
+*/
+
+// Package reconcilewatchdog features a reconciler decorator which detects the reconciliations running for longer than
+// a threshold, e.g. blocked on a call without a timeout or on a deadlock, which would otherwise silently stall a worker
+// of the controller.
+package reconcilewatchdog
+
+import (
+	"context"
+	"io"
+	"os"
+	"runtime/pprof"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"k8s.io/klog/v2"
+	ctrlmetrics "sigs.k8s.io/controller-runtime/pkg/metrics"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"go.goms.io/fleet-networking/pkg/common/metrics"
+)
+
+// DefaultThreshold is the duration after which a reconciliation is reported stuck, unless configured otherwise.
+const DefaultThreshold = 5 * time.Minute
+
+var (
+	// reconcileStuckTotal counts the reconciliations which have run for longer than the threshold, per controller.
+	reconcileStuckTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: metrics.MetricsNamespace,
+			Subsystem: metrics.MetricsSubsystem,
+			Name:      "reconcile_stuck_total",
+			Help:      "The number of reconciliations which have run for longer than the stuck reconcile threshold",
+		},
+		[]string{"controller"},
+	)
+)
+
+func init() {
+	// Register reconcileStuckTotal (fleet_networking_reconcile_stuck_total) metric with the controller runtime global
+	// metrics registry.
+	ctrlmetrics.Registry.MustRegister(reconcileStuckTotal)
+}
+
+// Options configure the detection of the stuck reconciliations.
+type Options struct {
+	// Threshold is how long a reconciliation may run before it is reported stuck; the detection is disabled when it
+	// is not positive.
+	Threshold time.Duration
+	// DumpGoroutines, if set, writes the stacks of all the goroutines to stderr the first time a reconciliation is
+	// reported stuck in the process.
+	DumpGoroutines bool
+}
+
+var (
+	options = Options{Threshold: DefaultThreshold}
+
+	// dumpOnce guards the goroutine dump, which is written once per process lifetime.
+	dumpOnce sync.Once
+	// dumpOutput is where the goroutine dump is written.
+	dumpOutput io.Writer = os.Stderr
+)
+
+// Configure sets the options of the reconcilers wrapped afterwards; it must be called before the controllers are set
+// up.
+func Configure(opts Options) {
+	options = opts
+}
+
+// Wrap returns a reconciler which runs r and reports its reconciliations running for longer than the configured
+// threshold; the reconciliations are never interrupted. The name of the controller labels the reports.
+func Wrap(controllerName string, r reconcile.Reconciler) reconcile.Reconciler {
+	if options.Threshold <= 0 {
+		return r
+	}
+	return &watchdog{controllerName: controllerName, reconciler: r, opts: options}
+}
+
+// watchdog is the reconciler decorator returned by Wrap.
+type watchdog struct {
+	controllerName string
+	reconciler     reconcile.Reconciler
+	opts           Options
+}
+
+// Reconcile implements the reconcile.Reconciler interface.
+func (w *watchdog) Reconcile(ctx context.Context, req reconcile.Request) (reconcile.Result, error) {
+	startTime := time.Now()
+	reported := make(chan struct{})
+	timer := time.AfterFunc(w.opts.Threshold, func() {
+		defer close(reported)
+		w.reportStuck(req, startTime)
+	})
+	res, err := w.reconciler.Reconcile(ctx, req)
+	if !timer.Stop() {
+		// The reconciliation has been reported stuck; wait for the report to complete, so that it is never
+		// interleaved with the next reconciliation of the worker.
+		<-reported
+	}
+	return res, err
+}
+
+// reportStuck reports the reconciliation of the request, which started at startTime, as stuck.
+func (w *watchdog) reportStuck(req reconcile.Request, startTime time.Time) {
+	reconcileStuckTotal.WithLabelValues(w.controllerName).Inc()
+	klog.ErrorS(nil, "Reconciliation is stuck", "controller", w.controllerName, "object", req.NamespacedName,
+		"runningFor", time.Since(startTime).String(), "threshold", w.opts.Threshold.String())
+	if !w.opts.DumpGoroutines {
+		return
+	}
+	dumpOnce.Do(func() {
+		klog.InfoS("Writing the goroutine dump to stderr", "controller", w.controllerName, "object", req.NamespacedName)
+		if err := pprof.Lookup("goroutine").WriteTo(dumpOutput, 2); err != nil {
+			klog.ErrorS(err, "Failed to write the goroutine dump")
+		}
+	})
+}
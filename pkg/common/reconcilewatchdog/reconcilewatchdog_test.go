@@ -0,0 +1,116 @@
+/*
+Copyright (c) Microsoft Corporation.
+Licensed under the MIT license.
+*/
+
+package reconcilewatchdog
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// sleepingReconciler is a reconciler which sleeps for the given duration before returning its result.
+type sleepingReconciler struct {
+	sleep time.Duration
+}
+
+func (r *sleepingReconciler) Reconcile(_ context.Context, _ reconcile.Request) (reconcile.Result, error) {
+	time.Sleep(r.sleep)
+	return reconcile.Result{RequeueAfter: time.Minute}, nil
+}
+
+// captureOutputs redirects the logs and the goroutine dump to buffers for the duration of the test, and rearms the
+// goroutine dump.
+func captureOutputs(t *testing.T) (logs, dump *bytes.Buffer) {
+	t.Helper()
+	logs, dump = &bytes.Buffer{}, &bytes.Buffer{}
+	klog.LogToStderr(false)
+	klog.SetOutput(logs)
+	originalDumpOutput := dumpOutput
+	dumpOutput = dump
+	dumpOnce = sync.Once{}
+	t.Cleanup(func() {
+		klog.SetOutput(nil)
+		klog.LogToStderr(true)
+		dumpOutput = originalDumpOutput
+	})
+	return logs, dump
+}
+
+func TestWrap(t *testing.T) {
+	req := reconcile.Request{NamespacedName: types.NamespacedName{Namespace: "work", Name: "app"}}
+	testCases := []struct {
+		name           string
+		controllerName string
+		sleep          time.Duration
+		wantStuck      bool
+	}{
+		{
+			name:           "reconciliation within the threshold",
+			controllerName: "fast",
+			sleep:          0,
+		},
+		{
+			name:           "reconciliation past the threshold",
+			controllerName: "slow",
+			sleep:          200 * time.Millisecond,
+			wantStuck:      true,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			logs, dump := captureOutputs(t)
+			Configure(Options{Threshold: 50 * time.Millisecond, DumpGoroutines: true})
+			t.Cleanup(func() { Configure(Options{Threshold: DefaultThreshold}) })
+
+			countBefore := testutil.ToFloat64(reconcileStuckTotal.WithLabelValues(tc.controllerName))
+			r := Wrap(tc.controllerName, &sleepingReconciler{sleep: tc.sleep})
+			got, err := r.Reconcile(context.Background(), req)
+			if err != nil {
+				t.Fatalf("Reconcile() got error %v, want no error", err)
+			}
+			// The wrapped reconciliation completes with its own result, stuck or not.
+			if want := (reconcile.Result{RequeueAfter: time.Minute}); got != want {
+				t.Errorf("Reconcile() = %+v, want %+v", got, want)
+			}
+
+			wantCount := 0.0
+			if tc.wantStuck {
+				wantCount = 1
+			}
+			if got := testutil.ToFloat64(reconcileStuckTotal.WithLabelValues(tc.controllerName)) - countBefore; got != wantCount {
+				t.Errorf("reconcile_stuck_total{controller=%q} = %v, want %v", tc.controllerName, got, wantCount)
+			}
+			klog.Flush()
+			if got := strings.Contains(logs.String(), "Reconciliation is stuck"); got != tc.wantStuck {
+				t.Errorf("stuck reconciliation logged = %t, want %t; logs:\n%s", got, tc.wantStuck, logs.String())
+			}
+			if tc.wantStuck && !strings.Contains(logs.String(), req.NamespacedName.String()) {
+				t.Errorf("stuck reconciliation log does not name the object %s; logs:\n%s", req.NamespacedName, logs.String())
+			}
+			if got := strings.Contains(dump.String(), "goroutine"); got != tc.wantStuck {
+				t.Errorf("goroutine dump written = %t, want %t", got, tc.wantStuck)
+			}
+		})
+	}
+}
+
+func TestWrap_Disabled(t *testing.T) {
+	Configure(Options{})
+	t.Cleanup(func() { Configure(Options{Threshold: DefaultThreshold}) })
+
+	r := &sleepingReconciler{}
+	if got := Wrap("disabled", r); got != reconcile.Reconciler(r) {
+		t.Errorf("Wrap() = %T, want the reconciler itself when the detection is disabled", got)
+	}
+}
@@ -0,0 +1,105 @@
+/*
+Copyright (c) Microsoft Corporation.
+Licensed under the MIT license.
+*/
+
+// Package rejectiondiff features a debug aid for the writes of the exported objects which the hub cluster rejects as
+// invalid, e.g. on a validation failure, an immutable field or an object which is too large: the spec of the last
+// known hub object is diffed with the attempted spec, so that what changed can be told without pulling both objects.
+//
+// The diffs are only computed for such rejections, and never for the conflicts or the server errors, which are
+// retried as is.
+package rejectiondiff
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/prometheus/client_golang/prometheus"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/klog/v2"
+	ctrlmetrics "sigs.k8s.io/controller-runtime/pkg/metrics"
+
+	"go.goms.io/fleet-networking/pkg/common/metrics"
+)
+
+const (
+	// MaxDiffBytes is the size after which the diffs are truncated.
+	MaxDiffBytes = 4096
+	// maxSummaryBytes is the size after which the first changed line quoted in the summaries is truncated.
+	maxSummaryBytes = 200
+)
+
+var (
+	// hubWriteRejectionsTotal counts the writes of the exported objects rejected by the hub cluster, per kind of the
+	// object and reason of the rejection.
+	hubWriteRejectionsTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: metrics.MetricsNamespace,
+			Subsystem: metrics.MetricsSubsystem,
+			Name:      "hub_write_rejections_total",
+			Help:      "The number of writes of the exported objects rejected by the hub cluster as invalid",
+		},
+		[]string{"kind", "reason"},
+	)
+)
+
+func init() {
+	// Register hubWriteRejectionsTotal (fleet_networking_hub_write_rejections_total) metric with the controller runtime
+	// global metrics registry.
+	ctrlmetrics.Registry.MustRegister(hubWriteRejectionsTotal)
+}
+
+// IsRejection returns true if the hub cluster rejected the write as invalid, i.e. the write would be rejected again
+// unless the object is changed; the conflicts, the server errors and the other transient errors are not rejections.
+func IsRejection(err error) bool {
+	return apierrors.IsInvalid(err) || apierrors.IsBadRequest(err) || apierrors.IsRequestEntityTooLargeError(err)
+}
+
+// Diff returns the diff between the last known spec and the attempted one, truncated to maxBytes.
+func Diff(last, attempted any, maxBytes int) string {
+	diff := cmp.Diff(last, attempted)
+	if len(diff) <= maxBytes {
+		return diff
+	}
+	return fmt.Sprintf("%s\n... (%d bytes truncated)", diff[:maxBytes], len(diff)-maxBytes)
+}
+
+// Summarize returns the one-line summary of the diff: the number of the changed lines and the first of them.
+func Summarize(diff string) string {
+	var changed int
+	var first string
+	for _, line := range strings.Split(diff, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if !strings.HasPrefix(trimmed, "-") && !strings.HasPrefix(trimmed, "+") {
+			continue
+		}
+		if changed++; first == "" {
+			first = strings.Join(strings.Fields(trimmed), " ")
+		}
+	}
+	if changed == 0 {
+		return "no spec change"
+	}
+	if len(first) > maxSummaryBytes {
+		first = first[:maxSummaryBytes] + "..."
+	}
+	return fmt.Sprintf("%d changed lines, first: %s", changed, first)
+}
+
+// Explain returns err with the summary of the diff between the last known spec and the attempted one appended, if
+// the hub cluster rejected the write as invalid; the full diff is logged at V(2), and the rejection is counted per
+// kind and reason. The last known spec is only read, with lastSpec, for the rejections, and may be nil, e.g. when
+// the object has not been created yet. Any other error is returned as is. The returned error wraps err.
+func Explain(err error, kind string, lastSpec func() any, attemptedSpec any, keysAndValues ...any) error {
+	if !IsRejection(err) {
+		return err
+	}
+	reason := string(apierrors.ReasonForError(err))
+	hubWriteRejectionsTotal.WithLabelValues(kind, reason).Inc()
+	diff := Diff(lastSpec(), attemptedSpec, MaxDiffBytes)
+	klog.V(2).InfoS("Hub cluster rejected the write of the exported object", append([]any{
+		"kind", kind, "reason", reason, "specDiff(-last,+attempted)", diff}, keysAndValues...)...)
+	return fmt.Errorf("%w (spec diff: %s)", err, Summarize(diff))
+}
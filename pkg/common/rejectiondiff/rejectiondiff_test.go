@@ -0,0 +1,179 @@
+/*
+Copyright (c) Microsoft Corporation.
+Licensed under the MIT license.
+*/
+
+package rejectiondiff
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+
+	fleetnetv1alpha1 "go.goms.io/fleet-networking/api/v1alpha1"
+)
+
+var internalSvcExportGK = schema.GroupKind{Group: fleetnetv1alpha1.GroupVersion.Group, Kind: "InternalServiceExport"}
+
+func internalServiceExportSpec(ports ...int32) *fleetnetv1alpha1.InternalServiceExportSpec {
+	spec := &fleetnetv1alpha1.InternalServiceExportSpec{
+		ServiceReference: fleetnetv1alpha1.ExportedObjectReference{ClusterID: "member-1", Namespace: "work", Name: "app"},
+	}
+	for _, port := range ports {
+		spec.Ports = append(spec.Ports, fleetnetv1alpha1.ServicePort{Port: port})
+	}
+	return spec
+}
+
+// TestIsRejection tests the IsRejection function.
+func TestIsRejection(t *testing.T) {
+	gr := schema.GroupResource{Group: fleetnetv1alpha1.GroupVersion.Group, Resource: "internalserviceexports"}
+	testCases := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{
+			name: "invalid",
+			err:  apierrors.NewInvalid(internalSvcExportGK, "work-app", field.ErrorList{field.Forbidden(field.NewPath("spec"), "immutable")}),
+			want: true,
+		},
+		{
+			name: "bad request",
+			err:  apierrors.NewBadRequest("malformed"),
+			want: true,
+		},
+		{
+			name: "request entity too large",
+			err:  apierrors.NewRequestEntityTooLargeError("limit is 3145728"),
+			want: true,
+		},
+		{
+			name: "conflict",
+			err:  apierrors.NewConflict(gr, "work-app", errors.New("modified")),
+		},
+		{
+			name: "internal error",
+			err:  apierrors.NewInternalError(errors.New("etcd")),
+		},
+		{
+			name: "service unavailable",
+			err:  apierrors.NewServiceUnavailable("unavailable"),
+		},
+		{
+			name: "not an API error",
+			err:  errors.New("connection refused"),
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := IsRejection(tc.err); got != tc.want {
+				t.Errorf("IsRejection(%v) = %t, want %t", tc.err, got, tc.want)
+			}
+		})
+	}
+}
+
+// TestDiff tests that the diffs are truncated to the given size.
+func TestDiff(t *testing.T) {
+	last := internalServiceExportSpec(80)
+	attempted := internalServiceExportSpec(80, 443)
+	for port := int32(1000); port < 1200; port++ {
+		attempted.Ports = append(attempted.Ports, fleetnetv1alpha1.ServicePort{Port: port})
+	}
+
+	full := Diff(last, attempted, 1<<20)
+	if !strings.Contains(full, "443") {
+		t.Fatalf("Diff() = %s, want the added port", full)
+	}
+	if strings.Contains(full, "truncated") {
+		t.Errorf("Diff() within the limit = %s, want no truncation", full)
+	}
+
+	const maxBytes = 256
+	got := Diff(last, attempted, maxBytes)
+	if !strings.HasPrefix(got, full[:maxBytes]) {
+		t.Errorf("Diff() truncated = %s, want the first %d bytes of the diff", got, maxBytes)
+	}
+	if len(got) > maxBytes+64 {
+		t.Errorf("Diff() truncated is %d bytes long, want at most %d bytes and a note", len(got), maxBytes)
+	}
+	if !strings.HasSuffix(got, "bytes truncated)") {
+		t.Errorf("Diff() truncated = %s, want a truncation note", got)
+	}
+}
+
+// TestSummarize tests the Summarize function.
+func TestSummarize(t *testing.T) {
+	if got, want := Summarize(Diff(internalServiceExportSpec(80), internalServiceExportSpec(80), MaxDiffBytes)), "no spec change"; got != want {
+		t.Errorf("Summarize() = %q, want %q", got, want)
+	}
+
+	got := Summarize(Diff(internalServiceExportSpec(80), internalServiceExportSpec(8080), MaxDiffBytes))
+	if !strings.HasPrefix(got, "2 changed lines, first: -") || !strings.Contains(got, "80") {
+		t.Errorf("Summarize() = %q, want the count and the first changed line", got)
+	}
+	if strings.Contains(got, "\n") {
+		t.Errorf("Summarize() = %q, want a single line", got)
+	}
+}
+
+// TestExplain tests that the diffs are only computed for the rejections.
+func TestExplain(t *testing.T) {
+	gr := schema.GroupResource{Group: fleetnetv1alpha1.GroupVersion.Group, Resource: "internalserviceexports"}
+	testCases := []struct {
+		name       string
+		err        error
+		wantReason string
+	}{
+		{
+			name:       "invalid",
+			err:        apierrors.NewInvalid(internalSvcExportGK, "work-app", field.ErrorList{field.Invalid(field.NewPath("spec", "ports"), nil, "too many")}),
+			wantReason: "Invalid",
+		},
+		{
+			name: "conflict",
+			err:  apierrors.NewConflict(gr, "work-app", errors.New("modified")),
+		},
+		{
+			name: "internal error",
+			err:  apierrors.NewInternalError(errors.New("etcd")),
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			const kind = "internalServiceExport"
+			var read bool
+			lastSpec := func() any {
+				read = true
+				return internalServiceExportSpec(80)
+			}
+			countBefore := testutil.ToFloat64(hubWriteRejectionsTotal.WithLabelValues(kind, tc.wantReason))
+
+			got := Explain(tc.err, kind, lastSpec, internalServiceExportSpec(8080), "service", "work/app")
+			if !errors.Is(got, tc.err) {
+				t.Fatalf("Explain() = %v, want an error wrapping %v", got, tc.err)
+			}
+			if tc.wantReason == "" {
+				if got != tc.err || read {
+					t.Errorf("Explain() = %v, read the last spec %t, want the error as is and no diff computed", got, read)
+				}
+				return
+			}
+			if !strings.Contains(got.Error(), "spec diff: 2 changed lines") {
+				t.Errorf("Explain() = %v, want the summary of the diff", got)
+			}
+			if !apierrors.IsInvalid(got) {
+				t.Errorf("Explain() = %v, want an invalid error", got)
+			}
+			if got := testutil.ToFloat64(hubWriteRejectionsTotal.WithLabelValues(kind, tc.wantReason)) - countBefore; got != 1 {
+				t.Errorf("hub_write_rejections_total{kind=%q, reason=%q} increased by %v, want 1", kind, tc.wantReason, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,89 @@
+/*
+Copyright (c) Microsoft Corporation.
+Licensed under the MIT license.
+*/
+
+// Package sharding features the helpers to split the reconciliation of objects across the replicas of a controller
+// by the hash of their namespaced names.
+package sharding
+
+import (
+	"fmt"
+	"hash/fnv"
+	"strconv"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// Shard is the subset of objects reconciled by a replica. The zero value, as well as a shard count of 1, means the
+// replica owns all the objects.
+type Shard struct {
+	// Count is the total number of shards.
+	Count int
+	// Index is the index of the shard owned by the replica, from 0 to Count-1.
+	Index int
+}
+
+// New returns the shard of the replica. When the index is negative, it is derived from the ordinal suffix of the pod
+// name (e.g., "hub-net-controller-manager-2" owns the shard 2), as assigned to the pods of a StatefulSet.
+func New(count, index int, podName string) (Shard, error) {
+	if count < 1 {
+		return Shard{}, fmt.Errorf("invalid shard count %d: must be at least 1", count)
+	}
+	if count == 1 {
+		return Shard{Count: 1}, nil
+	}
+	if index < 0 {
+		i := strings.LastIndex(podName, "-")
+		ordinal, err := strconv.Atoi(podName[i+1:])
+		if err != nil || ordinal < 0 {
+			return Shard{}, fmt.Errorf("failed to derive the shard index from the pod name %q: the pod name must end with the StatefulSet ordinal", podName)
+		}
+		index = ordinal
+	}
+	if index >= count {
+		return Shard{}, fmt.Errorf("invalid shard index %d: must be less than the shard count %d", index, count)
+	}
+	return Shard{Count: count, Index: index}, nil
+}
+
+// Enabled returns true if the objects are split across more than one shard.
+func (s Shard) Enabled() bool {
+	return s.Count > 1
+}
+
+// Owns returns true if the object with the given namespaced name belongs to the shard.
+func (s Shard) Owns(namespace, name string) bool {
+	if !s.Enabled() {
+		return true
+	}
+	h := fnv.New32a()
+	// The hash.Hash Write never returns an error.
+	_, _ = h.Write([]byte(types.NamespacedName{Namespace: namespace, Name: name}.String()))
+	return int(h.Sum32()%uint32(s.Count)) == s.Index
+}
+
+// Predicate filters out the events of the objects which do not belong to the shard.
+func (s Shard) Predicate() predicate.Predicate {
+	return predicate.NewPredicateFuncs(func(obj client.Object) bool {
+		return s.Owns(obj.GetNamespace(), obj.GetName())
+	})
+}
+
+// FilterRequests returns the requests of the objects which belong to the shard.
+func (s Shard) FilterRequests(requests []reconcile.Request) []reconcile.Request {
+	if !s.Enabled() {
+		return requests
+	}
+	res := make([]reconcile.Request, 0, len(requests))
+	for _, req := range requests {
+		if s.Owns(req.Namespace, req.Name) {
+			res = append(res, req)
+		}
+	}
+	return res
+}
@@ -0,0 +1,124 @@
+/*
+Copyright (c) Microsoft Corporation.
+Licensed under the MIT license.
+*/
+
+package sharding
+
+import (
+	"fmt"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	fleetnetv1beta1 "go.goms.io/fleet-networking/api/v1beta1"
+)
+
+const (
+	timeout  = time.Second * 10
+	interval = time.Millisecond * 250
+
+	profileName     = "profile"
+	numberOfBackend = 10
+)
+
+var _ = Describe("Test sharding across replicas", Ordered, func() {
+	var backendNames []types.NamespacedName
+
+	// reconciledByShards returns the number of reconciles of the backend per shard.
+	reconciledByShards := func(name types.NamespacedName) []int {
+		res := make([]int, len(replicas))
+		for i, r := range replicas {
+			res[i] = r.reconciledCount(name)
+		}
+		return res
+	}
+
+	It("Creating the trafficManagerBackends", func() {
+		for i := 0; i < numberOfBackend; i++ {
+			backend := &fleetnetv1beta1.TrafficManagerBackend{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: testNamespace,
+					Name:      fmt.Sprintf("backend-%d", i),
+				},
+				Spec: fleetnetv1beta1.TrafficManagerBackendSpec{
+					Profile: fleetnetv1beta1.TrafficManagerProfileRef{Name: profileName},
+					Backend: fleetnetv1beta1.TrafficManagerBackendRef{Name: "app"},
+				},
+			}
+			Expect(k8sClient.Create(ctx, backend)).Should(Succeed(), "failed to create trafficManagerBackend")
+			backendNames = append(backendNames, types.NamespacedName{Namespace: testNamespace, Name: backend.Name})
+		}
+	})
+
+	It("Validating each trafficManagerBackend is reconciled by the replica of its own shard only", func() {
+		for _, name := range backendNames {
+			Eventually(func() error {
+				counts := reconciledByShards(name)
+				for i, count := range counts {
+					owns := replicas[i].shard.Owns(name.Namespace, name.Name)
+					if owns && count == 0 {
+						return fmt.Errorf("backend %s has not been reconciled by shard %d which owns it", name, i)
+					}
+					if !owns && count > 0 {
+						return fmt.Errorf("backend %s has been reconciled %d times by shard %d which does not own it", name, count, i)
+					}
+				}
+				return nil
+			}, timeout, interval).Should(Succeed())
+		}
+	})
+
+	It("Validating the trafficManagerBackends are split across the replicas", func() {
+		for i, r := range replicas {
+			owned := 0
+			for _, name := range backendNames {
+				if r.shard.Owns(name.Namespace, name.Name) {
+					owned++
+				}
+			}
+			Expect(owned).ShouldNot(BeZero(), "shard %d owns none of the backends", i)
+		}
+	})
+
+	It("Creating the trafficManagerProfile", func() {
+		profile := &fleetnetv1beta1.TrafficManagerProfile{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: testNamespace,
+				Name:      profileName,
+			},
+		}
+		Expect(k8sClient.Create(ctx, profile)).Should(Succeed(), "failed to create trafficManagerProfile")
+	})
+
+	It("Validating the profile event enqueues the trafficManagerBackends of each shard by its own replica only", func() {
+		for _, name := range backendNames {
+			Eventually(func() error {
+				counts := reconciledByShards(name)
+				for i, count := range counts {
+					owns := replicas[i].shard.Owns(name.Namespace, name.Name)
+					if owns && count < 2 {
+						return fmt.Errorf("backend %s has not been reconciled by shard %d after the profile is created", name, i)
+					}
+					if !owns && count > 0 {
+						return fmt.Errorf("backend %s has been reconciled %d times by shard %d which does not own it", name, count, i)
+					}
+				}
+				return nil
+			}, timeout, interval).Should(Succeed())
+		}
+		for _, name := range backendNames {
+			Consistently(func() error {
+				for i, count := range reconciledByShards(name) {
+					if !replicas[i].shard.Owns(name.Namespace, name.Name) && count > 0 {
+						return fmt.Errorf("backend %s has been reconciled %d times by shard %d which does not own it", name, count, i)
+					}
+				}
+				return nil
+			}, time.Second, interval).Should(Succeed())
+		}
+	})
+})
@@ -0,0 +1,179 @@
+/*
+Copyright (c) Microsoft Corporation.
+Licensed under the MIT license.
+*/
+
+package sharding
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	fleetnetv1beta1 "go.goms.io/fleet-networking/api/v1beta1"
+)
+
+func TestNew(t *testing.T) {
+	tests := []struct {
+		name    string
+		count   int
+		index   int
+		podName string
+		want    Shard
+		wantErr bool
+	}{
+		{
+			name:  "sharding disabled",
+			count: 1,
+			index: -1,
+			want:  Shard{Count: 1},
+		},
+		{
+			name:  "explicit index",
+			count: 3,
+			index: 2,
+			want:  Shard{Count: 3, Index: 2},
+		},
+		{
+			name:    "index derived from the pod name",
+			count:   3,
+			index:   -1,
+			podName: "hub-net-controller-manager-1",
+			want:    Shard{Count: 3, Index: 1},
+		},
+		{
+			name:    "pod name without ordinal",
+			count:   3,
+			index:   -1,
+			podName: "hub-net-controller-manager-7d9f8b6c4-x2k9p",
+			wantErr: true,
+		},
+		{
+			name:    "index out of range",
+			count:   3,
+			index:   3,
+			wantErr: true,
+		},
+		{
+			name:    "ordinal out of range",
+			count:   3,
+			index:   -1,
+			podName: "hub-net-controller-manager-3",
+			wantErr: true,
+		},
+		{
+			name:    "invalid count",
+			count:   0,
+			wantErr: true,
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := New(tc.count, tc.index, tc.podName)
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("New() got error %v, want error %v", err, tc.wantErr)
+			}
+			if tc.wantErr {
+				return
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("New() mismatch (-want, +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestOwns(t *testing.T) {
+	const count = 3
+	shards := make([]Shard, count)
+	for i := range shards {
+		shards[i] = Shard{Count: count, Index: i}
+	}
+
+	objects := 300
+	owned := make([]int, count)
+	for i := 0; i < objects; i++ {
+		name := fmt.Sprintf("backend-%d", i)
+		owners := 0
+		for j, s := range shards {
+			if s.Owns("app", name) {
+				owners++
+				owned[j]++
+			}
+		}
+		if owners != 1 {
+			t.Fatalf("object app/%s is owned by %d shards, want 1", name, owners)
+		}
+		if !(Shard{}).Owns("app", name) || !(Shard{Count: 1}).Owns("app", name) {
+			t.Fatalf("Owns(app/%s) = false for the disabled shard, want true", name)
+		}
+	}
+	for i, n := range owned {
+		// The objects should be spread roughly evenly.
+		if n < objects/count/2 {
+			t.Errorf("shard %d owns %d of %d objects, want at least %d", i, n, objects, objects/count/2)
+		}
+	}
+}
+
+func TestPredicate(t *testing.T) {
+	shards := []Shard{{Count: 2, Index: 0}, {Count: 2, Index: 1}}
+	for i := 0; i < 20; i++ {
+		backend := &fleetnetv1beta1.TrafficManagerBackend{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: "app",
+				Name:      fmt.Sprintf("backend-%d", i),
+			},
+		}
+		accepted := 0
+		for _, s := range shards {
+			p := s.Predicate()
+			got := p.Create(event.CreateEvent{Object: backend})
+			if want := s.Owns(backend.Namespace, backend.Name); got != want {
+				t.Errorf("Predicate().Create(%s) = %v, want %v", backend.Name, got, want)
+			}
+			if p.Update(event.UpdateEvent{ObjectOld: backend, ObjectNew: backend}) != got ||
+				p.Delete(event.DeleteEvent{Object: backend}) != got ||
+				p.Generic(event.GenericEvent{Object: backend}) != got {
+				t.Errorf("Predicate() filters the events of %s inconsistently", backend.Name)
+			}
+			if got {
+				accepted++
+			}
+		}
+		if accepted != 1 {
+			t.Errorf("the events of %s are accepted by %d shards, want 1", backend.Name, accepted)
+		}
+	}
+}
+
+func TestFilterRequests(t *testing.T) {
+	var requests []reconcile.Request
+	for i := 0; i < 20; i++ {
+		requests = append(requests, reconcile.Request{NamespacedName: types.NamespacedName{Namespace: "app", Name: fmt.Sprintf("backend-%d", i)}})
+	}
+
+	if diff := cmp.Diff(requests, (Shard{}).FilterRequests(requests)); diff != "" {
+		t.Errorf("FilterRequests() of the disabled shard mismatch (-want, +got):\n%s", diff)
+	}
+
+	shards := []Shard{{Count: 2, Index: 0}, {Count: 2, Index: 1}}
+	total := 0
+	for _, s := range shards {
+		got := s.FilterRequests(requests)
+		for _, req := range got {
+			if !s.Owns(req.Namespace, req.Name) {
+				t.Errorf("FilterRequests() of shard %d returned %v owned by another shard", s.Index, req)
+			}
+		}
+		total += len(got)
+	}
+	if total != len(requests) {
+		t.Errorf("FilterRequests() of all the shards returned %d requests, want %d", total, len(requests))
+	}
+}
@@ -0,0 +1,175 @@
+/*
+Copyright (c) Microsoft Corporation.
+Licensed under the MIT license.
+*/
+
+package sharding
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/rest"
+	"k8s.io/klog/v2"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/envtest"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/log/zap"
+	metricsserver "sigs.k8s.io/controller-runtime/pkg/metrics/server"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	fleetnetv1beta1 "go.goms.io/fleet-networking/api/v1beta1"
+)
+
+const (
+	testNamespace = "sharding"
+	shardCount    = 2
+
+	profileFieldKey = ".spec.profile.name"
+)
+
+var (
+	cfg       *rest.Config
+	k8sClient client.Client
+	testEnv   *envtest.Environment
+	ctx       context.Context
+	cancel    context.CancelFunc
+
+	// replicas are the in-process replicas, one per shard.
+	replicas []*replica
+)
+
+// replica records the trafficManagerBackends reconciled by the controller of one shard, wired the same way as the
+// trafficManagerBackend controller.
+type replica struct {
+	shard Shard
+
+	mu         sync.Mutex
+	reconciled map[types.NamespacedName]int
+}
+
+func (r *replica) Reconcile(_ context.Context, req reconcile.Request) (reconcile.Result, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.reconciled[req.NamespacedName]++
+	return reconcile.Result{}, nil
+}
+
+func (r *replica) reconciledCount(name types.NamespacedName) int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.reconciled[name]
+}
+
+func (r *replica) setupWithManager(mgr ctrl.Manager) error {
+	if err := mgr.GetFieldIndexer().IndexField(ctx, &fleetnetv1beta1.TrafficManagerBackend{}, profileFieldKey, func(o client.Object) []string {
+		backend, ok := o.(*fleetnetv1beta1.TrafficManagerBackend)
+		if !ok || !r.shard.Owns(backend.Namespace, backend.Name) {
+			return []string{}
+		}
+		return []string{backend.Spec.Profile.Name}
+	}); err != nil {
+		return err
+	}
+	return ctrl.NewControllerManagedBy(mgr).
+		Named(fmt.Sprintf("shard-%d", r.shard.Index)).
+		For(&fleetnetv1beta1.TrafficManagerBackend{}, builder.WithPredicates(r.shard.Predicate())).
+		Watches(
+			&fleetnetv1beta1.TrafficManagerProfile{},
+			handler.EnqueueRequestsFromMapFunc(func(ctx context.Context, o client.Object) []reconcile.Request {
+				backendList := &fleetnetv1beta1.TrafficManagerBackendList{}
+				if err := mgr.GetClient().List(ctx, backendList, client.InNamespace(o.GetNamespace()), client.MatchingFields{profileFieldKey: o.GetName()}); err != nil {
+					return []reconcile.Request{}
+				}
+				res := make([]reconcile.Request, 0, len(backendList.Items))
+				for _, backend := range backendList.Items {
+					res = append(res, reconcile.Request{NamespacedName: types.NamespacedName{Namespace: backend.Namespace, Name: backend.Name}})
+				}
+				return res
+			}),
+		).
+		Complete(r)
+}
+
+func TestAPIs(t *testing.T) {
+	RegisterFailHandler(Fail)
+
+	RunSpecs(t, "Sharding Suite")
+}
+
+var _ = BeforeSuite(func() {
+	logger := zap.New(zap.WriteTo(GinkgoWriter), zap.UseDevMode(true))
+	klog.SetLogger(logger)
+	log.SetLogger(logger)
+
+	ctx, cancel = context.WithCancel(context.TODO())
+
+	By("bootstrapping test environment")
+	testEnv = &envtest.Environment{
+		CRDDirectoryPaths:     []string{filepath.Join("../../../", "config", "crd", "bases")},
+		ErrorIfCRDPathMissing: true,
+	}
+
+	var err error
+	cfg, err = testEnv.Start()
+	Expect(err).NotTo(HaveOccurred())
+	Expect(cfg).NotTo(BeNil())
+
+	Expect(fleetnetv1beta1.AddToScheme(scheme.Scheme)).Should(Succeed())
+
+	By("construct the k8s client")
+	k8sClient, err = client.New(cfg, client.Options{Scheme: scheme.Scheme})
+	Expect(err).NotTo(HaveOccurred())
+	Expect(k8sClient).NotTo(BeNil())
+
+	By("Create test namespace")
+	ns := corev1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: testNamespace,
+		},
+	}
+	Expect(k8sClient.Create(ctx, &ns)).Should(Succeed())
+
+	for i := 0; i < shardCount; i++ {
+		By(fmt.Sprintf("starting the replica of shard %d", i))
+		shard, err := New(shardCount, -1, fmt.Sprintf("hub-net-controller-manager-%d", i))
+		Expect(err).NotTo(HaveOccurred())
+
+		mgr, err := ctrl.NewManager(cfg, ctrl.Options{
+			Scheme: scheme.Scheme,
+			Metrics: metricsserver.Options{
+				BindAddress: "0",
+			},
+		})
+		Expect(err).NotTo(HaveOccurred())
+
+		r := &replica{shard: shard, reconciled: map[types.NamespacedName]int{}}
+		Expect(r.setupWithManager(mgr)).Should(Succeed())
+		replicas = append(replicas, r)
+
+		go func() {
+			defer GinkgoRecover()
+			Expect(mgr.Start(ctx)).Should(Succeed(), "failed to run manager")
+		}()
+	}
+})
+
+var _ = AfterSuite(func() {
+	defer klog.Flush()
+
+	cancel()
+	By("tearing down the test environment")
+	Expect(testEnv.Stop()).Should(Succeed())
+})
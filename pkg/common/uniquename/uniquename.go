@@ -9,6 +9,8 @@ package uniquename
 
 import (
 	"fmt"
+	"hash/fnv"
+	"sort"
 	"strings"
 	"unicode"
 
@@ -28,6 +30,8 @@ const (
 	DNS1035Label Format = 3
 
 	uuidLength = 5
+	// hashLength is the length of the hash of the full name components added to the names which have been truncated.
+	hashLength = 8
 )
 
 // minInt returns the smaller one of two integers.
@@ -48,26 +52,79 @@ func removeDots(s string) string {
 	return strings.ReplaceAll(s, ".", "")
 }
 
+// truncatedLengths returns the lengths to which the segments are truncated so that they fit in the budget together;
+// the shorter segments are kept whole, and the budget left is shared evenly between the longer ones.
+func truncatedLengths(budget int, segments []string) []int {
+	order := make([]int, len(segments))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(i, j int) bool {
+		return len(segments[order[i]]) < len(segments[order[j]])
+	})
+
+	lengths := make([]int, len(segments))
+	if budget < 0 {
+		budget = 0
+	}
+	for i, idx := range order {
+		lengths[idx] = minInt(len(segments[idx]), budget/(len(order)-i))
+		budget -= lengths[idx]
+	}
+	return lengths
+}
+
+// joinWithinLimit joins the segments and the suffix (if any) with dashes into a name of at most maxLength characters.
+// If the joined name is too long, the segments are truncated deterministically, the longest ones first, and a hash
+// of the full segments is added before the suffix, so that the names whose segments share the same truncated
+// prefixes stay unique; the dots and dashes left at the end of a truncated segment are trimmed, as they would
+// make the name invalid when followed by a dash.
+func joinWithinLimit(maxLength int, suffix string, segments ...string) string {
+	joined := strings.Join(segments, "-")
+	name := joined
+	if suffix != "" {
+		name = fmt.Sprintf("%s-%s", joined, suffix)
+	}
+	if len(name) <= maxLength {
+		return name
+	}
+
+	h := fnv.New32a()
+	// Hash.Write never returns an error.
+	_, _ = h.Write([]byte(joined))
+	hash := fmt.Sprintf("%0*x", hashLength, h.Sum32())
+
+	// Reserve the slots for the dashes between the segments, the hash, and the suffix.
+	reservedSlots := len(segments) + hashLength
+	if suffix != "" {
+		reservedSlots += 1 + len(suffix)
+	}
+	lengths := truncatedLengths(maxLength-reservedSlots, segments)
+	parts := make([]string, 0, len(segments)+2)
+	for i, seg := range segments {
+		parts = append(parts, strings.TrimRight(seg[:lengths[i]], ".-"))
+	}
+	parts = append(parts, hash)
+	if suffix != "" {
+		parts = append(parts, suffix)
+	}
+	return strings.Join(parts, "-")
+}
+
 // ClusterScopedUniqueName returns a name that is guaranteed to be unique within a cluster.
 // The name is formatted using an object's namespace, its name, and a 5 character long UUID suffix; the format
 // is [NAMESPACE]-[NAME]-[SUFFIX], e.g. an object `app` from the namespace `work` will be assigned a unique
-// name like `work-app-1x2yz`. The function may truncate name components as it sees fit.
+// name like `work-app-1x2yz`. If the name would exceed the length limit of the format, the longest name components
+// are truncated and a hash of the full components is added before the suffix, e.g. `work-app-1a2b3c4d-1x2yz`.
 // Note: this function assumes that
 //   - the input object namespace is a valid RFC 1123 DNS label; and
 //   - the input object name follows one of the three formats used in Kubernetes (RFC 1123 DNS subdomain,
 //     RFC 1123 DNS label, RFC 1035 DNS label).
 func ClusterScopedUniqueName(format Format, namespace, name string) (string, error) {
-	reservedSlots := 2 + uuidLength // 2 dashes + 5 character UUID string
-
 	switch format {
 	case DNS1123Subdomain:
 		availableSlots := validation.DNS1123SubdomainMaxLength // 253 characters
-		slotsPerSeg := (availableSlots - reservedSlots) / 2
-		uniqueName := fmt.Sprintf("%s-%s-%s",
-			namespace[:minInt(slotsPerSeg, len(namespace))],
-			name[:minInt(slotsPerSeg, len(name))],
-			uuid.NewUUID()[:uuidLength],
-		)
+		uniqueName := joinWithinLimit(availableSlots, string(uuid.NewUUID()[:uuidLength]), namespace, name)
 
 		if errs := validation.IsDNS1123Subdomain(uniqueName); len(errs) != 0 {
 			return "", fmt.Errorf("failed to format a unique RFC 1123 DNS subdomain name with namespace %s, name %s: %v", namespace, name, errs)
@@ -75,17 +132,12 @@ func ClusterScopedUniqueName(format Format, namespace, name string) (string, err
 		return uniqueName, nil
 	case DNS1123Label:
 		availableSlots := validation.DNS1123LabelMaxLength // 63 characters
-		slotsPerSeg := (availableSlots - reservedSlots) / 2
 
 		// If the object name is a RFC 1123 DNS subdomain, it may include dot characters, which is not allowed in
 		// RFC 1123 DNS labels.
 		name = removeDots(name)
 
-		uniqueName := fmt.Sprintf("%s-%s-%s",
-			namespace[:minInt(slotsPerSeg, len(namespace))],
-			name[:minInt(slotsPerSeg, len(name))],
-			uuid.NewUUID()[:uuidLength],
-		)
+		uniqueName := joinWithinLimit(availableSlots, string(uuid.NewUUID()[:uuidLength]), namespace, name)
 
 		if errs := validation.IsDNS1123Label(uniqueName); len(errs) != 0 {
 			return "", fmt.Errorf("failed to format a unique RFC 1123 DNS label name with namespace %s, name %s: %v", namespace, name, errs)
@@ -93,7 +145,6 @@ func ClusterScopedUniqueName(format Format, namespace, name string) (string, err
 		return uniqueName, nil
 	case DNS1035Label:
 		availableSlots := validation.DNS1035LabelMaxLength // 63 characters
-		slotsPerSeg := (availableSlots - reservedSlots) / 2
 
 		// Namespace names are RFC 1123 DNS labels, which may start with an alphanumeric character; RFC 1035 DNS
 		// labels, on the other hand, does not allow numeric characters at the beginning of the string.
@@ -104,11 +155,7 @@ func ClusterScopedUniqueName(format Format, namespace, name string) (string, err
 		// RFC 1035 DNS labels.
 		name = removeDots(name)
 
-		uniqueName := fmt.Sprintf("%s-%s-%s",
-			namespace[:minInt(slotsPerSeg, len(namespace))],
-			name[:minInt(slotsPerSeg, len(name))],
-			uuid.NewUUID()[:uuidLength],
-		)
+		uniqueName := joinWithinLimit(availableSlots, string(uuid.NewUUID()[:uuidLength]), namespace, name)
 
 		if errs := validation.IsDNS1035Label(uniqueName); len(errs) != 0 {
 			return "", fmt.Errorf("failed to format a unique RFC 1035 DNS label name with namespace %s, name %s: %v", namespace, name, errs)
@@ -121,26 +168,19 @@ func ClusterScopedUniqueName(format Format, namespace, name string) (string, err
 // FleetScopedUniqueName returns a name that is guaranteed to be unique within a cluster.
 // The name is formatted using an object's origin cluster, an object's namespace, its name, and a 5 character
 // long UUID suffix; the format is [CLUSTER ID]-[NAMESPACE]-[NAME]-[SUFFIX], e.g. an object `app` from the namespace
-// `work` in cluster `bravelion` will be assigned a unique name like `bravelion-work-app-1x2yz`. The function may
-// truncate name components as it sees fit.
+// `work` in cluster `bravelion` will be assigned a unique name like `bravelion-work-app-1x2yz`. If the name would
+// exceed the length limit of the format, the longest name components are truncated and a hash of the full
+// components is added before the suffix, e.g. `bravelion-work-app-1a2b3c4d-1x2yz`.
 // Note: this function assumes that
 //   - the input cluster ID is a valid RFC 1123 DNS subdomain; and
 //   - the input object namespace is a valid RFC 1123 DNS label; and
 //   - the input object name follows one of the three formats used in Kubernetes (RFC 1123 DNS subdomain,
 //     RFC 1123 DNS label, RFC 1035 DNS label).
 func FleetScopedUniqueName(format Format, clusterID, namespace, name string) (string, error) {
-	reservedSlots := 3 + uuidLength // 3 dashes + 5 character UUID string
-
 	switch format {
 	case DNS1123Subdomain:
 		availableSlots := validation.DNS1123SubdomainMaxLength // 253 characters
-		slotsPerSeg := (availableSlots - reservedSlots) / 3
-		uniqueName := fmt.Sprintf("%s-%s-%s-%s",
-			clusterID[:minInt(slotsPerSeg, len(clusterID))],
-			namespace[:minInt(slotsPerSeg, len(namespace))],
-			name[:minInt(slotsPerSeg, len(name))],
-			uuid.NewUUID()[:uuidLength],
-		)
+		uniqueName := joinWithinLimit(availableSlots, string(uuid.NewUUID()[:uuidLength]), clusterID, namespace, name)
 
 		if errs := validation.IsDNS1123Subdomain(uniqueName); len(errs) != 0 {
 			return "", fmt.Errorf("failed to format a unique RFC 1123 DNS subdomain name with cluster ID %s, namespace %s, name %s: %v",
@@ -149,19 +189,13 @@ func FleetScopedUniqueName(format Format, clusterID, namespace, name string) (st
 		return uniqueName, nil
 	case DNS1123Label:
 		availableSlots := validation.DNS1123LabelMaxLength // 63 characters
-		slotsPerSeg := (availableSlots - reservedSlots) / 3
 
 		// If the cluster ID and object name are valid RFC 1123 DNS subdomains, they may include dot characters,
 		// which is not allowed in RFC 1123 DNS labels.
 		clusterID = removeDots(clusterID)
 		name = removeDots(name)
 
-		uniqueName := fmt.Sprintf("%s-%s-%s-%s",
-			clusterID[:minInt(slotsPerSeg, len(clusterID))],
-			namespace[:minInt(slotsPerSeg, len(namespace))],
-			name[:minInt(slotsPerSeg, len(name))],
-			uuid.NewUUID()[:uuidLength],
-		)
+		uniqueName := joinWithinLimit(availableSlots, string(uuid.NewUUID()[:uuidLength]), clusterID, namespace, name)
 
 		if errs := validation.IsDNS1123Label(uniqueName); len(errs) != 0 {
 			return "", fmt.Errorf("failed to format a unique RFC 1123 DNS label name with cluster ID %s, namespace %s, name %s: %v",
@@ -170,19 +204,13 @@ func FleetScopedUniqueName(format Format, clusterID, namespace, name string) (st
 		return uniqueName, nil
 	case DNS1035Label:
 		availableSlots := validation.DNS1035LabelMaxLength // 63 characters
-		slotsPerSeg := (availableSlots - reservedSlots) / 3
 
 		// If the cluster ID and object name are valid RFC 1123 DNS subdomains, they may include dot characters,
 		// which is not allowed in RFC 1123 DNS labels.
 		clusterID = removeDots(clusterID)
 		name = removeDots(name)
 
-		uniqueName := fmt.Sprintf("%s-%s-%s-%s",
-			clusterID[:minInt(slotsPerSeg, len(clusterID))],
-			namespace[:minInt(slotsPerSeg, len(namespace))],
-			name[:minInt(slotsPerSeg, len(name))],
-			uuid.NewUUID()[:uuidLength],
-		)
+		uniqueName := joinWithinLimit(availableSlots, string(uuid.NewUUID()[:uuidLength]), clusterID, namespace, name)
 
 		if errs := validation.IsDNS1035Label(uniqueName); len(errs) != 0 {
 			return "", fmt.Errorf("failed to format a unique RFC 1035 DNS label name with cluster ID %s, namespace %s, name %s: %v",
@@ -193,6 +221,18 @@ func FleetScopedUniqueName(format Format, clusterID, namespace, name string) (st
 	return "", fmt.Errorf("not a valid name format: %d", format)
 }
 
+// ClusterScopedDeterministicName returns a RFC 1123 DNS subdomain name for an object which is unique within a cluster
+// and always the same for the same object, so that it can be used to look up the object exported with it.
+// The name is formatted using an object's namespace and its name; the format is [NAMESPACE]-[NAME], e.g. an object
+// `app` from the namespace `work` will be assigned the name `work-app`. If the name would exceed 253 characters,
+// the longest name components are truncated and a hash of the full components is added, e.g. `work-app-1a2b3c4d`.
+// Note: this function assumes that
+//   - the input object namespace is a valid RFC 1123 DNS label; and
+//   - the input object name is a valid RFC 1123 DNS subdomain.
+func ClusterScopedDeterministicName(namespace, name string) string {
+	return joinWithinLimit(validation.DNS1123SubdomainMaxLength, "", namespace, name)
+}
+
 // RandomLowerCaseAlphabeticString returns a string of lower case alphabetic characters only. This function
 // is best used for fallback cases where one cannot format a unique name as expected, as a lower case
 // alphabetic string of proper length is always a valid Kubernetes object name, regardless of the required name
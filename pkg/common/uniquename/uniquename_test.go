@@ -6,9 +6,12 @@ Licensed under the MIT license.
 package uniquename
 
 import (
+	"fmt"
 	"regexp"
 	"strings"
 	"testing"
+
+	"k8s.io/apimachinery/pkg/util/validation"
 )
 
 const (
@@ -51,11 +54,8 @@ func TestClusterScopedUniqueName(t *testing.T) {
 			format:     DNS1123Subdomain,
 			objectNS:   longObjectNS,
 			objectName: longObjectName,
-			wantPrefix: "mdlmpqe2ev31zgxar1gswscd3hsvypl5leh4nolfnq0vdg56a7a08iiu4988" +
-				"-c7t2c6oppjnryqcihwweexeobs7tlmf08ha4qb5htc4cifzpalhb5ec2lbh" +
-				"3j73reciaz2f0jfd2rl5qba6rzuuwgyw6d9e6la19bo89k41lphln4s4dy1g" +
-				"rh1d-", // 184 characters long
-			wantLength: 190,
+			wantPrefix: longObjectNS + "-" + longObjectName[:177] + "-b1332118-",
+			wantLength: 253,
 		},
 		{
 			name:       "should format RFC 1123 DNS label name",
@@ -78,7 +78,7 @@ func TestClusterScopedUniqueName(t *testing.T) {
 			format:     DNS1123Label,
 			objectNS:   longObjectNS,
 			objectName: longObjectName,
-			wantPrefix: "mdlmpqe2ev31zgxar1gswscd3hsv-c7t2c6oppjnryqcihwweexeobs7t-",
+			wantPrefix: "mdlmpqe2ev31zgxar1gswsc-c7t2c6oppjnryqcihwweexeo-b1332118-",
 			wantLength: 63,
 		},
 		{
@@ -110,7 +110,7 @@ func TestClusterScopedUniqueName(t *testing.T) {
 			format:     DNS1035Label,
 			objectNS:   longObjectNS,
 			objectName: longObjectName,
-			wantPrefix: "mdlmpqe2ev31zgxar1gswscd3hsv-c7t2c6oppjnryqcihwweexeobs7t-",
+			wantPrefix: "mdlmpqe2ev31zgxar1gswsc-c7t2c6oppjnryqcihwweexeo-b1332118-",
 			wantLength: 63,
 		},
 	}
@@ -159,11 +159,8 @@ func TestFleetScopedUniqueName(t *testing.T) {
 			clusterID:  longClusterID,
 			objectNS:   longObjectNS,
 			objectName: longObjectName,
-			wantPrefix: "lhwh0nrw3d03no2f1rf53dl9gliwe3rneqeuzt6k2qa9qn6epm-mdlmpqe2e" +
-				"v31zgxar1gswscd3hsvypl5leh4nolfnq0vdg56a7a08iiu4988-c7t2c6op" +
-				"pjnryqcihwweexeobs7tlmf08ha4qb5htc4cifzpalhb5ec2lbh3j73recia" +
-				"z2f0jfd2rl5qb-",
-			wantLength: 199,
+			wantPrefix: longClusterID + "-" + longObjectNS + "-" + longObjectName[:126] + "-a75e1f12-",
+			wantLength: 253,
 		},
 		{
 			name:       "should format RFC 1123 DNS label name",
@@ -180,8 +177,8 @@ func TestFleetScopedUniqueName(t *testing.T) {
 			clusterID:  longClusterID,
 			objectNS:   longObjectNS,
 			objectName: longObjectName,
-			wantPrefix: "lhwh0nrw3d03no2f1r-mdlmpqe2ev31zgxar1-c7t2c6oppjnryqcihw-",
-			wantLength: 62,
+			wantPrefix: "lhwh0nrw3d03no2-mdlmpqe2ev31zgx-c7t2c6oppjnryqci-a75e1f12-",
+			wantLength: 63,
 		},
 		{
 			name:       "should format RFC 1123 DNS label name (no dots allowed in cluster ID)",
@@ -216,8 +213,8 @@ func TestFleetScopedUniqueName(t *testing.T) {
 			clusterID:  longClusterID,
 			objectNS:   longObjectNS,
 			objectName: longObjectName,
-			wantPrefix: "lhwh0nrw3d03no2f1r-mdlmpqe2ev31zgxar1-c7t2c6oppjnryqcihw-",
-			wantLength: 62,
+			wantPrefix: "lhwh0nrw3d03no2-mdlmpqe2ev31zgx-c7t2c6oppjnryqci-a75e1f12-",
+			wantLength: 63,
 		},
 		{
 			name:       "should format RFC 1123 DNS label name (no dots allowed in cluster ID)",
@@ -257,6 +254,182 @@ func TestFleetScopedUniqueName(t *testing.T) {
 	}
 }
 
+// TestJoinWithinLimit tests the joinWithinLimit function at the boundary lengths.
+func TestJoinWithinLimit(t *testing.T) {
+	testCases := []struct {
+		name      string
+		maxLength int
+		suffix    string
+		segments  []string
+		want      string
+	}{
+		{
+			name:      "should join segments shorter than the limit",
+			maxLength: 20,
+			suffix:    "1x2yz",
+			segments:  []string{"bravelion", "work"},
+			want:      "bravelion-work-1x2yz",
+		},
+		{
+			name:      "should join segments as long as the limit",
+			maxLength: 20,
+			suffix:    "1x2yz",
+			segments:  []string{"bravelion", "work"},
+			want:      "bravelion-work-1x2yz",
+		},
+		{
+			name:      "should truncate segments one character longer than the limit",
+			maxLength: 19,
+			suffix:    "1x2yz",
+			segments:  []string{"bravelion", "work"},
+			want:      "br-w-624d22df-1x2yz",
+		},
+		{
+			name:      "should keep shorter segments whole",
+			maxLength: 30,
+			suffix:    "1x2yz",
+			segments:  []string{"work", strings.Repeat("a", 20)},
+			want:      "work-aaaaaaaaaa-da42e05b-1x2yz",
+		},
+		{
+			name:      "should trim dots and dashes at the end of truncated segments",
+			maxLength: 30,
+			suffix:    "1x2yz",
+			segments:  []string{"work", "app.examp.com.internal"},
+			want:      "work-app.examp-d6877fcd-1x2yz",
+		},
+		{
+			name:      "should join segments without suffix",
+			maxLength: 12,
+			segments:  []string{"work", strings.Repeat("a", 10)},
+			want:      "w-a-522dcba1",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got := joinWithinLimit(tc.maxLength, tc.suffix, tc.segments...)
+			if got != tc.want {
+				t.Errorf("joinWithinLimit(%d, %s, %v) = %s, want %s", tc.maxLength, tc.suffix, tc.segments, got, tc.want)
+			}
+			if len(got) > tc.maxLength {
+				t.Errorf("joinWithinLimit(%d, %s, %v) = %s, got length %d, want at most %d",
+					tc.maxLength, tc.suffix, tc.segments, got, len(got), tc.maxLength)
+			}
+		})
+	}
+}
+
+// TestUniqueNamesAtBoundaryLengths tests that the unique names are valid for every format when the cluster ID, the
+// namespace and the name are at or around their maximum lengths.
+func TestUniqueNamesAtBoundaryLengths(t *testing.T) {
+	clusterIDs := []string{
+		clusterID,
+		// Cluster IDs derived from Azure resource IDs are often 60+ characters long.
+		strings.Repeat("c", 60),
+		strings.Repeat("c", 63),
+		strings.Repeat("c", 100) + "." + strings.Repeat("c", 152),
+		strings.Repeat("c", 253),
+	}
+	namespaces := []string{objectNS, strings.Repeat("n", 62), strings.Repeat("n", 63)}
+	names := []string{
+		objectName,
+		strings.Repeat("a", 63),
+		strings.Repeat("a", 170) + "." + strings.Repeat("a", 81),
+		strings.Repeat("a", 252),
+		strings.Repeat("a", 253),
+	}
+	formats := []struct {
+		format    Format
+		maxLength int
+		validate  func(string) []string
+	}{
+		{format: DNS1123Subdomain, maxLength: validation.DNS1123SubdomainMaxLength, validate: validation.IsDNS1123Subdomain},
+		{format: DNS1123Label, maxLength: validation.DNS1123LabelMaxLength, validate: validation.IsDNS1123Label},
+		{format: DNS1035Label, maxLength: validation.DNS1035LabelMaxLength, validate: validation.IsDNS1035Label},
+	}
+
+	for _, f := range formats {
+		for _, ns := range namespaces {
+			for _, name := range names {
+				t.Run(fmt.Sprintf("cluster scoped/format %d/ns %d/name %d", f.format, len(ns), len(name)), func(t *testing.T) {
+					uniqueName, err := ClusterScopedUniqueName(f.format, ns, name)
+					if err != nil {
+						t.Fatalf("ClusterScopedUniqueName(%d, %s, %s), got %v, want no error", f.format, ns, name, err)
+					}
+					if len(uniqueName) > f.maxLength {
+						t.Errorf("ClusterScopedUniqueName(%d, %s, %s)=%s, got length %d, want at most %d",
+							f.format, ns, name, uniqueName, len(uniqueName), f.maxLength)
+					}
+				})
+				for _, id := range clusterIDs {
+					t.Run(fmt.Sprintf("fleet scoped/format %d/cluster ID %d/ns %d/name %d", f.format, len(id), len(ns), len(name)), func(t *testing.T) {
+						uniqueName, err := FleetScopedUniqueName(f.format, id, ns, name)
+						if err != nil {
+							t.Fatalf("FleetScopedUniqueName(%d, %s, %s, %s), got %v, want no error", f.format, id, ns, name, err)
+						}
+						if errs := f.validate(uniqueName); len(errs) != 0 {
+							t.Errorf("FleetScopedUniqueName(%d, %s, %s, %s)=%s, got invalid name: %v", f.format, id, ns, name, uniqueName, errs)
+						}
+					})
+				}
+			}
+		}
+	}
+}
+
+// TestClusterScopedDeterministicName tests the ClusterScopedDeterministicName function.
+func TestClusterScopedDeterministicName(t *testing.T) {
+	// The namespace and the name joined with a dash are exactly 253 characters long.
+	boundaryName := strings.Repeat("a", validation.DNS1123SubdomainMaxLength-len(objectNS)-1)
+	testCases := []struct {
+		name       string
+		objectNS   string
+		objectName string
+		want       string
+	}{
+		{
+			name:       "should format name",
+			objectNS:   objectNS,
+			objectName: objectName,
+			want:       "work-app",
+		},
+		{
+			name:       "should format name as long as the limit",
+			objectNS:   objectNS,
+			objectName: boundaryName,
+			want:       objectNS + "-" + boundaryName,
+		},
+		{
+			name:       "should format name (truncated)",
+			objectNS:   objectNS,
+			objectName: boundaryName + "a",
+			want:       objectNS + "-" + boundaryName[:239] + "-d038806a",
+		},
+		{
+			name:       "should format name (truncated, trailing dot trimmed)",
+			objectNS:   longObjectNS,
+			objectName: strings.Repeat("a", 182) + "." + strings.Repeat("b", 70),
+			want:       longObjectNS + "-" + strings.Repeat("a", 182) + "-8159de7f",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got := ClusterScopedDeterministicName(tc.objectNS, tc.objectName)
+			if got != tc.want {
+				t.Errorf("ClusterScopedDeterministicName(%s, %s) = %s, want %s", tc.objectNS, tc.objectName, got, tc.want)
+			}
+			if errs := validation.IsDNS1123Subdomain(got); len(errs) != 0 {
+				t.Errorf("ClusterScopedDeterministicName(%s, %s) = %s, got invalid name: %v", tc.objectNS, tc.objectName, got, errs)
+			}
+			if again := ClusterScopedDeterministicName(tc.objectNS, tc.objectName); again != got {
+				t.Errorf("ClusterScopedDeterministicName(%s, %s) = %s, then %s, want the same name", tc.objectNS, tc.objectName, got, again)
+			}
+		})
+	}
+}
+
 // TestRandomLowerCaseAlphabeticString tests the RandomLowerCaseAlphabeticString function.
 func TestRandomLowerCaseAlphabeticString(t *testing.T) {
 	testCases := []struct {
@@ -0,0 +1,120 @@
+/*
+Copyright (c) Microsoft Corporation.
+Licensed under the MIT license.
+*/
+
+// Package version features the build information of the fleet networking binaries, along with the features they
+// run with, so that the image and flag combination of every agent across a fleet can be told apart from its metrics
+// or its /version endpoint rather than from its startup logs.
+//
+// The version is injected at build time, e.g.
+//
+//	go build -ldflags "-X go.goms.io/fleet-networking/pkg/common/version.Version=v0.3.0" ./cmd/hub-net-controller-manager
+package version
+
+import (
+	"encoding/json"
+	"flag"
+	"net/http"
+	"runtime"
+	"strconv"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"go.goms.io/fleet-networking/pkg/common/metrics"
+)
+
+// Path is the path of the endpoint serving the build information.
+const Path = "/version"
+
+// Version is the version of the binary, injected at build time with ldflags; it is unknown for the binaries built
+// without it, e.g. by go run.
+var Version = "unknown"
+
+// Features are the features a binary runs with, as reported by the build info metric.
+type Features struct {
+	// V1Beta1Enabled is whether the v1beta1 APIs are enabled.
+	V1Beta1Enabled bool `json:"v1beta1Enabled"`
+	// TrafficManagerEnabled is whether the traffic manager feature is enabled.
+	TrafficManagerEnabled bool `json:"trafficManagerEnabled"`
+	// NetworkingFeaturesEnabled is whether the controllers exporting and importing the services run.
+	NetworkingFeaturesEnabled bool `json:"networkingFeaturesEnabled"`
+}
+
+// Info is the build information of a binary.
+type Info struct {
+	// Binary is the name of the binary, e.g. hub-net-controller-manager.
+	Binary    string   `json:"binary"`
+	Version   string   `json:"version"`
+	GoVersion string   `json:"goVersion"`
+	Features  Features `json:"features"`
+	// Flags are the values of the boolean flags of the binary, i.e. its feature flags, by name.
+	Flags map[string]bool `json:"flags,omitempty"`
+}
+
+// Get returns the build information of a binary; the values of the boolean flags are read from the flag set, if
+// any, which should have been parsed.
+func Get(binary string, features Features, fs *flag.FlagSet) Info {
+	info := Info{
+		Binary:    binary,
+		Version:   Version,
+		GoVersion: runtime.Version(),
+		Features:  features,
+	}
+	if fs != nil {
+		info.Flags = boolFlags(fs)
+	}
+	return info
+}
+
+// boolFlags returns the values of the boolean flags of a flag set by name.
+func boolFlags(fs *flag.FlagSet) map[string]bool {
+	flags := make(map[string]bool)
+	fs.VisitAll(func(f *flag.Flag) {
+		getter, ok := f.Value.(flag.Getter)
+		if !ok {
+			return
+		}
+		if value, ok := getter.Get().(bool); ok {
+			flags[f.Name] = value
+		}
+	})
+	return flags
+}
+
+// Collector returns the build info metric of the binary, fleet_networking_build_info, whose value is always 1; the
+// build information is carried by its labels.
+func (i Info) Collector() prometheus.Collector {
+	buildInfo := prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: metrics.MetricsNamespace,
+			Subsystem: metrics.MetricsSubsystem,
+			Name:      "build_info",
+			Help:      "The build information of the binary and the features it runs with; the value is always 1",
+		},
+		[]string{"binary", "version", "goversion", "v1beta1_enabled", "traffic_manager_enabled", "networking_features_enabled"},
+	)
+	buildInfo.WithLabelValues(
+		i.Binary,
+		i.Version,
+		i.GoVersion,
+		strconv.FormatBool(i.Features.V1Beta1Enabled),
+		strconv.FormatBool(i.Features.TrafficManagerEnabled),
+		strconv.FormatBool(i.Features.NetworkingFeaturesEnabled),
+	).Set(1)
+	return buildInfo
+}
+
+// Handler returns the handler serving the build information as JSON.
+func (i Info) Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet && r.Method != http.MethodHead {
+			w.Header().Set("Allow", "GET, HEAD")
+			http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		// Encoding the info never fails, and a failed write cannot be reported to the client anyway.
+		_ = json.NewEncoder(w).Encode(i)
+	})
+}
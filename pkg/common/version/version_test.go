@@ -0,0 +1,120 @@
+/*
+Copyright (c) Microsoft Corporation.
+Licensed under the MIT license.
+*/
+
+package version
+
+import (
+	"encoding/json"
+	"flag"
+	"net/http"
+	"net/http/httptest"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+func flagSetForTest(t *testing.T) *flag.FlagSet {
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	fs.Bool("enable-traffic-manager-feature", false, "")
+	fs.Bool("leader-elect", true, "")
+	fs.Duration("resync-interval", time.Minute, "")
+	fs.String("cloud-config", "/etc/azure.json", "")
+	if err := fs.Parse([]string{"--enable-traffic-manager-feature"}); err != nil {
+		t.Fatalf("Parse() = %v, want no error", err)
+	}
+	return fs
+}
+
+// TestGet tests the Get function.
+func TestGet(t *testing.T) {
+	original := Version
+	Version = "v1.2.3"
+	defer func() { Version = original }()
+
+	features := Features{V1Beta1Enabled: true, TrafficManagerEnabled: true}
+	got := Get("hub-net-controller-manager", features, flagSetForTest(t))
+	want := Info{
+		Binary:    "hub-net-controller-manager",
+		Version:   "v1.2.3",
+		GoVersion: runtime.Version(),
+		Features:  features,
+		// Only the boolean flags are reported.
+		Flags: map[string]bool{
+			"enable-traffic-manager-feature": true,
+			"leader-elect":                   true,
+		},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("Get() mismatch (-want, +got):\n%s", diff)
+	}
+}
+
+// TestCollector tests the build info metric.
+func TestCollector(t *testing.T) {
+	info := Info{
+		Binary:    "member-net-controller-manager",
+		Version:   "v1.2.3",
+		GoVersion: "go1.22.7",
+		Features:  Features{V1Beta1Enabled: true, NetworkingFeaturesEnabled: true},
+	}
+	want := `
+# HELP fleet_networking_build_info The build information of the binary and the features it runs with; the value is always 1
+# TYPE fleet_networking_build_info gauge
+fleet_networking_build_info{binary="member-net-controller-manager",goversion="go1.22.7",networking_features_enabled="true",traffic_manager_enabled="false",v1beta1_enabled="true",version="v1.2.3"} 1
+`
+	if err := testutil.CollectAndCompare(info.Collector(), strings.NewReader(want)); err != nil {
+		t.Errorf("CollectAndCompare() = %v, want no error", err)
+	}
+}
+
+// TestHandler tests the handler serving the build information.
+func TestHandler(t *testing.T) {
+	info := Get("mcs-controller-manager", Features{NetworkingFeaturesEnabled: true}, flagSetForTest(t))
+
+	testCases := []struct {
+		name       string
+		method     string
+		wantStatus int
+		wantInfo   bool
+	}{
+		{
+			name:       "get",
+			method:     http.MethodGet,
+			wantStatus: http.StatusOK,
+			wantInfo:   true,
+		},
+		{
+			name:       "post",
+			method:     http.MethodPost,
+			wantStatus: http.StatusMethodNotAllowed,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			info.Handler().ServeHTTP(rec, httptest.NewRequest(tc.method, Path, nil))
+			if rec.Code != tc.wantStatus {
+				t.Fatalf("ServeHTTP() status = %d, want %d", rec.Code, tc.wantStatus)
+			}
+			if !tc.wantInfo {
+				return
+			}
+			if got := rec.Header().Get("Content-Type"); got != "application/json" {
+				t.Errorf("ServeHTTP() Content-Type = %q, want application/json", got)
+			}
+			var got Info
+			if err := json.Unmarshal(rec.Body.Bytes(), &got); err != nil {
+				t.Fatalf("Unmarshal() = %v, want no error", err)
+			}
+			if diff := cmp.Diff(info, got); diff != "" {
+				t.Errorf("ServeHTTP() info mismatch (-want, +got):\n%s", diff)
+			}
+		})
+	}
+}
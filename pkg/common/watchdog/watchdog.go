@@ -0,0 +1,215 @@
+/*
+Copyright (c) Microsoft Corporation.
+Licensed under the MIT license.
+*/
+
+// Package watchdog features a watchdog which detects informers that silently stop receiving events (e.g., watches
+// stuck on a stale HTTP/2 connection) and forces their watches to restart.
+package watchdog
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	toolscache "k8s.io/client-go/tools/cache"
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+	ctrlmetrics "sigs.k8s.io/controller-runtime/pkg/metrics"
+
+	"go.goms.io/fleet-networking/pkg/common/listguard"
+	"go.goms.io/fleet-networking/pkg/common/metrics"
+)
+
+var (
+	// hubWatchRestarts counts the number of times the watchdog has forced the watches to restart.
+	hubWatchRestarts = prometheus.NewCounter(
+		prometheus.CounterOpts{
+			Namespace: metrics.MetricsNamespace,
+			Subsystem: metrics.MetricsSubsystem,
+			Name:      "hub_watch_restarts_total",
+			Help:      "The number of times the hub watches have been restarted due to staleness",
+		},
+	)
+)
+
+func init() {
+	// Register hubWatchRestarts (fleet_networking_hub_watch_restarts_total) metric
+	// with the controller runtime global metrics registry.
+	ctrlmetrics.Registry.MustRegister(hubWatchRestarts)
+}
+
+// Watchdog tracks the time of the last event received by the informers of the watched object types. If an informer
+// has not received any event for longer than the staleness threshold, the watchdog lists the objects directly from
+// the API server and compares them with the cached ones; on drift, the watches are forced to restart, which makes
+// the informers re-list and re-watch the objects.
+//
+// Watchdog implements the controller-runtime Runnable interface and runs whether the manager is the leader or not.
+type Watchdog struct {
+	// Informers are the informers of the cache to watch over.
+	Informers cache.Informers
+	// CachedReader reads objects from the cache; it is usually the same cache as the one of Informers.
+	CachedReader client.Reader
+	// APIReader reads objects directly from the API server.
+	APIReader client.Reader
+	Scheme    *runtime.Scheme
+	// Namespace is the namespace the cache is restricted to.
+	Namespace string
+	// Objects are the types of the objects whose informers are watched over.
+	Objects []client.Object
+	// StalenessThreshold is how long an informer can go without receiving any event before it is checked for drift.
+	StalenessThreshold time.Duration
+	// CheckInterval is how often the informers are checked.
+	CheckInterval time.Duration
+	// Restart forces the watches of the informers to restart, e.g., by closing the connections they are using.
+	Restart func()
+
+	mu             sync.Mutex
+	lastEventTimes map[schema.GroupVersionKind]time.Time
+}
+
+// NeedLeaderElection implements the LeaderElectionRunnable interface; the watchdog must run on every replica, as
+// the caches are started regardless of leader election.
+func (w *Watchdog) NeedLeaderElection() bool {
+	return false
+}
+
+// Start starts the watchdog and blocks until the context is done.
+func (w *Watchdog) Start(ctx context.Context) error {
+	w.mu.Lock()
+	w.lastEventTimes = make(map[schema.GroupVersionKind]time.Time, len(w.Objects))
+	w.mu.Unlock()
+
+	for _, obj := range w.Objects {
+		gvk, err := apiutil.GVKForObject(obj, w.Scheme)
+		if err != nil {
+			return fmt.Errorf("failed to get the GVK of %T: %w", obj, err)
+		}
+		informer, err := w.Informers.GetInformer(ctx, obj)
+		if err != nil {
+			return fmt.Errorf("failed to get the informer of %s: %w", gvk, err)
+		}
+		w.recordEvent(gvk)
+		onEvent := func(interface{}) { w.recordEvent(gvk) }
+		if _, err := informer.AddEventHandler(toolscache.ResourceEventHandlerFuncs{
+			AddFunc:    onEvent,
+			UpdateFunc: func(_, newObj interface{}) { onEvent(newObj) },
+			DeleteFunc: onEvent,
+		}); err != nil {
+			return fmt.Errorf("failed to add the event handler to the informer of %s: %w", gvk, err)
+		}
+	}
+
+	klog.V(2).InfoS("Starting the watchdog", "stalenessThreshold", w.StalenessThreshold, "checkInterval", w.CheckInterval)
+	ticker := time.NewTicker(w.CheckInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			klog.V(2).InfoS("Stopping the watchdog")
+			return nil
+		case <-ticker.C:
+			w.check(ctx)
+		}
+	}
+}
+
+func (w *Watchdog) recordEvent(gvk schema.GroupVersionKind) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.lastEventTimes[gvk] = time.Now()
+}
+
+// check restarts the watches if any stale informer has drifted from the API server.
+func (w *Watchdog) check(ctx context.Context) {
+	w.mu.Lock()
+	var staleGVKs []schema.GroupVersionKind
+	for gvk, lastEventTime := range w.lastEventTimes {
+		if time.Since(lastEventTime) > w.StalenessThreshold {
+			staleGVKs = append(staleGVKs, gvk)
+		}
+	}
+	w.mu.Unlock()
+
+	for _, gvk := range staleGVKs {
+		drifted, err := w.hasDrifted(ctx, gvk)
+		if err != nil {
+			klog.ErrorS(err, "Failed to check the informer for drift", "gvk", gvk)
+			continue
+		}
+		if !drifted {
+			klog.V(4).InfoS("The informer has not received events for a while but is in sync", "gvk", gvk)
+			continue
+		}
+
+		klog.InfoS("The informer has stopped receiving events and drifted from the API server; restarting the watches", "gvk", gvk, "stalenessThreshold", w.StalenessThreshold)
+		w.Restart()
+		hubWatchRestarts.Inc()
+
+		// All the watches have been restarted; give the informers a full threshold to catch up.
+		w.mu.Lock()
+		now := time.Now()
+		for k := range w.lastEventTimes {
+			w.lastEventTimes[k] = now
+		}
+		w.mu.Unlock()
+		return
+	}
+}
+
+// hasDrifted compares the objects of the given type in the cache with the ones in the API server; only the
+// names and resource versions are compared.
+func (w *Watchdog) hasDrifted(ctx context.Context, gvk schema.GroupVersionKind) (bool, error) {
+	listGVK := gvk.GroupVersion().WithKind(gvk.Kind + "List")
+	obj, err := w.Scheme.New(listGVK)
+	if err != nil {
+		return false, fmt.Errorf("failed to create the list object of %s: %w", gvk, err)
+	}
+	cachedList, ok := obj.(client.ObjectList)
+	if !ok {
+		return false, fmt.Errorf("unexpected list type %T of %s", obj, gvk)
+	}
+	if err := w.CachedReader.List(ctx, cachedList, client.InNamespace(w.Namespace)); err != nil {
+		return false, fmt.Errorf("failed to list %s from the cache: %w", gvk, err)
+	}
+	cachedItems, err := meta.ExtractList(cachedList)
+	if err != nil {
+		return false, fmt.Errorf("failed to extract the list of %s: %w", gvk, err)
+	}
+	resourceVersions := make(map[string]string, len(cachedItems))
+	for _, item := range cachedItems {
+		accessor, err := meta.Accessor(item)
+		if err != nil {
+			return false, fmt.Errorf("failed to access the object of %s: %w", gvk, err)
+		}
+		resourceVersions[accessor.GetName()] = accessor.GetResourceVersion()
+	}
+
+	// Only the metadata is needed from the API server; it is listed page by page, so that a namespace with many
+	// objects does not make a single large request.
+	apiList := &metav1.PartialObjectMetadataList{}
+	apiList.SetGroupVersionKind(listGVK)
+	apiCount := 0
+	drifted := false
+	if err := listguard.ForEachPage(ctx, w.APIReader, apiList, listguard.DefaultPageSize, func() error {
+		apiCount += len(apiList.Items)
+		for i := range apiList.Items {
+			item := &apiList.Items[i]
+			if rv, ok := resourceVersions[item.Name]; !ok || rv != item.ResourceVersion {
+				drifted = true
+			}
+		}
+		return nil
+	}, client.InNamespace(w.Namespace)); err != nil {
+		return false, fmt.Errorf("failed to list %s from the API server: %w", gvk, err)
+	}
+	return drifted || apiCount != len(cachedItems), nil
+}
@@ -0,0 +1,115 @@
+/*
+Copyright (c) Microsoft Corporation.
+Licensed under the MIT license.
+*/
+
+package watchdog
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	fleetnetv1alpha1 "go.goms.io/fleet-networking/api/v1alpha1"
+)
+
+const (
+	testNamespace = "fleet-member-member-1"
+)
+
+var (
+	internalServiceImportGVK = fleetnetv1alpha1.GroupVersion.WithKind("InternalServiceImport")
+)
+
+func internalServiceImport(name string) *fleetnetv1alpha1.InternalServiceImport {
+	return &fleetnetv1alpha1.InternalServiceImport{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: testNamespace,
+			Name:      name,
+		},
+	}
+}
+
+func TestCheck(t *testing.T) {
+	scheme := runtime.NewScheme()
+	if err := fleetnetv1alpha1.AddToScheme(scheme); err != nil {
+		t.Fatalf("AddToScheme() got error %v, want no error", err)
+	}
+
+	tests := []struct {
+		name          string
+		lastEventTime time.Time
+		cachedObjs    []client.Object
+		// apiObjs are the objects in the API server; the informer has not received the events of the
+		// changes on them, which simulates a stale watch.
+		apiObjs     []client.Object
+		wantRestart bool
+	}{
+		{
+			name:          "informer has received events recently",
+			lastEventTime: time.Now(),
+			cachedObjs:    []client.Object{internalServiceImport("app")},
+			apiObjs:       []client.Object{internalServiceImport("app"), internalServiceImport("web")},
+		},
+		{
+			name:          "stale informer is in sync with the API server",
+			lastEventTime: time.Now().Add(-time.Hour),
+			cachedObjs:    []client.Object{internalServiceImport("app")},
+			apiObjs:       []client.Object{internalServiceImport("app")},
+		},
+		{
+			name:          "stale informer misses a new object",
+			lastEventTime: time.Now().Add(-time.Hour),
+			cachedObjs:    []client.Object{internalServiceImport("app")},
+			apiObjs:       []client.Object{internalServiceImport("app"), internalServiceImport("web")},
+			wantRestart:   true,
+		},
+		{
+			name:          "stale informer misses a deletion",
+			lastEventTime: time.Now().Add(-time.Hour),
+			cachedObjs:    []client.Object{internalServiceImport("app"), internalServiceImport("web")},
+			apiObjs:       []client.Object{internalServiceImport("web")},
+			wantRestart:   true,
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx := context.Background()
+			restarted := false
+			w := &Watchdog{
+				CachedReader:       fake.NewClientBuilder().WithScheme(scheme).WithObjects(tc.cachedObjs...).Build(),
+				APIReader:          fake.NewClientBuilder().WithScheme(scheme).WithObjects(tc.apiObjs...).Build(),
+				Scheme:             scheme,
+				Namespace:          testNamespace,
+				StalenessThreshold: 10 * time.Minute,
+				Restart:            func() { restarted = true },
+				lastEventTimes: map[schema.GroupVersionKind]time.Time{
+					internalServiceImportGVK: tc.lastEventTime,
+				},
+			}
+			before := testutil.ToFloat64(hubWatchRestarts)
+
+			w.check(ctx)
+			if restarted != tc.wantRestart {
+				t.Errorf("check() restarted = %t, want %t", restarted, tc.wantRestart)
+			}
+			wantRestarts := before
+			if tc.wantRestart {
+				wantRestarts++
+			}
+			if got := testutil.ToFloat64(hubWatchRestarts); got != wantRestarts {
+				t.Errorf("hubWatchRestarts = %v, want %v", got, wantRestarts)
+			}
+			if tc.wantRestart && time.Since(w.lastEventTimes[internalServiceImportGVK]) > time.Minute {
+				t.Errorf("lastEventTimes[%s] = %v, want reset after restart", internalServiceImportGVK, w.lastEventTimes[internalServiceImportGVK])
+			}
+		})
+	}
+}
@@ -0,0 +1,264 @@
+/*
+Copyright (c) Microsoft Corporation.
+Licensed under the MIT license.
+*/
+
+// Package writelimiter features a limiter of the writes the member agent sends to the hub cluster, shared by the
+// hub-writing controllers of the agent process; it protects the hub API server from a member agent flooding it with
+// writes, e.g. because of a bug, beyond the rate limit of the individual clients.
+//
+// The limiter is injected into the hub client as a transport wrapper, so that the writes of all the controllers of
+// the process share one token bucket; it also honors the API Priority and Fairness throttling of the hub API server,
+// holding all the writes back when the hub API server rejects a request with 429 Too Many Requests.
+package writelimiter
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"golang.org/x/time/rate"
+	"k8s.io/klog/v2"
+	ctrlmetrics "sigs.k8s.io/controller-runtime/pkg/metrics"
+
+	"go.goms.io/fleet-networking/pkg/common/metrics"
+)
+
+const (
+	// DefaultInitialBackoff is the backoff after a throttled response without a Retry-After header; it doubles with
+	// every consecutive throttled response.
+	DefaultInitialBackoff = time.Second
+	// DefaultMaxBackoff is the longest backoff, whether or not the Retry-After header asks for a longer one.
+	DefaultMaxBackoff = 30 * time.Second
+)
+
+var (
+	// hubThrottledResponses counts the responses with which the hub API server throttles the member agent.
+	hubThrottledResponses = prometheus.NewCounter(
+		prometheus.CounterOpts{
+			Namespace: metrics.MetricsNamespace,
+			Subsystem: metrics.MetricsSubsystem,
+			Name:      "hub_throttled_responses_total",
+			Help:      "The number of responses with which the hub API server throttled the member agent (429 Too Many Requests)",
+		},
+	)
+)
+
+func init() {
+	// Register hubThrottledResponses (fleet_networking_hub_throttled_responses_total) metric
+	// with the controller runtime global metrics registry.
+	ctrlmetrics.Registry.MustRegister(hubThrottledResponses)
+}
+
+// Limiter limits the rate of the writes to the hub cluster with a token bucket, and backs them off while the hub API
+// server throttles the member agent.
+type Limiter struct {
+	// InitialBackoff is the backoff after a throttled response without a Retry-After header.
+	InitialBackoff time.Duration
+	// MaxBackoff caps the backoff, including the one the Retry-After header asks for.
+	MaxBackoff time.Duration
+
+	// bucket is the token bucket shared by the writes; nil if the rate of the writes is not limited.
+	bucket *rate.Limiter
+	burst  int
+	// maxShutdownDelay is the longest a write is held back once the limiter is drained.
+	maxShutdownDelay time.Duration
+
+	mu sync.Mutex
+	// backoff is the last backoff, which doubles with every consecutive throttled response; 0 after a response which
+	// is not throttled.
+	backoff time.Duration
+	// blockedUntil is the time until which the writes are backed off.
+	blockedUntil time.Time
+	// draining is closed once the limiter is drained.
+	draining chan struct{}
+	drained  bool
+}
+
+// New returns a limiter which allows qps writes per second with bursts of burst writes; a non-positive qps disables
+// the token bucket, in which case the writes are only backed off on throttled responses. Once the limiter is drained
+// (see Drain), e.g. on shutdown, a write is held back for at most maxShutdownDelay.
+func New(qps float64, burst int, maxShutdownDelay time.Duration) *Limiter {
+	l := &Limiter{
+		InitialBackoff:   DefaultInitialBackoff,
+		MaxBackoff:       DefaultMaxBackoff,
+		maxShutdownDelay: maxShutdownDelay,
+		draining:         make(chan struct{}),
+	}
+	if qps > 0 {
+		if burst < 1 {
+			burst = 1
+		}
+		l.bucket = rate.NewLimiter(rate.Limit(qps), burst)
+		l.burst = burst
+	}
+	return l
+}
+
+// Drain bounds the delay of the writes, including the ones being held back, to the max shutdown delay, so that the
+// cleanup on shutdown (e.g., the removal of finalizers) is not delayed indefinitely.
+func (l *Limiter) Drain() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if !l.drained {
+		l.drained = true
+		close(l.draining)
+	}
+}
+
+// Saturation returns how much of the capacity of the limiter is in use: 0 when the token bucket is full, 1 when it is
+// empty or the writes are backed off, and above 1 when writes are queued for tokens.
+func (l *Limiter) Saturation() float64 {
+	l.mu.Lock()
+	blocked := time.Now().Before(l.blockedUntil)
+	l.mu.Unlock()
+	saturation := 0.0
+	if l.bucket != nil {
+		saturation = 1 - l.bucket.Tokens()/float64(l.burst)
+	}
+	if blocked && saturation < 1 {
+		saturation = 1
+	}
+	if saturation < 0 {
+		saturation = 0
+	}
+	return saturation
+}
+
+// Collector returns the collector of the fleet_networking_hub_write_limiter_saturation metric, which reports the
+// saturation of the limiter on every scrape.
+func (l *Limiter) Collector() prometheus.Collector {
+	return prometheus.NewGaugeFunc(
+		prometheus.GaugeOpts{
+			Namespace: metrics.MetricsNamespace,
+			Subsystem: metrics.MetricsSubsystem,
+			Name:      "hub_write_limiter_saturation",
+			Help:      "The saturation of the limiter of the writes to the hub cluster; 1 when the writes are held back, above 1 when they are queued",
+		},
+		l.Saturation,
+	)
+}
+
+// WrapTransport returns a transport which limits the writes sent with the given transport; it can be set as the
+// WrapTransport of a rest.Config, so that every client built from the config shares the limiter.
+func (l *Limiter) WrapTransport(rt http.RoundTripper) http.RoundTripper {
+	return &roundTripper{limiter: l, delegate: rt}
+}
+
+// wait holds a write back until the token bucket allows it and the backoff is over, or the context is done.
+func (l *Limiter) wait(ctx context.Context) error {
+	var reservation *rate.Reservation
+	delay := time.Duration(0)
+	if l.bucket != nil {
+		reservation = l.bucket.Reserve()
+		delay = reservation.Delay()
+	}
+	l.mu.Lock()
+	if backoff := time.Until(l.blockedUntil); backoff > delay {
+		delay = backoff
+	}
+	draining := l.draining
+	if l.drained && delay > l.maxShutdownDelay {
+		delay = l.maxShutdownDelay
+		draining = nil
+	}
+	l.mu.Unlock()
+	if delay <= 0 {
+		return nil
+	}
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	deadline := time.Now().Add(delay)
+	for {
+		select {
+		case <-timer.C:
+			return nil
+		case <-ctx.Done():
+			if reservation != nil {
+				reservation.Cancel()
+			}
+			return ctx.Err()
+		case <-draining:
+			// The limiter has been drained while the write is held back; release it within the max shutdown delay.
+			draining = nil
+			if time.Until(deadline) > l.maxShutdownDelay {
+				timer.Reset(l.maxShutdownDelay)
+			}
+		}
+	}
+}
+
+// observe backs the writes off if the hub API server has throttled the request with the response.
+func (l *Limiter) observe(req *http.Request, resp *http.Response) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if resp.StatusCode != http.StatusTooManyRequests {
+		l.backoff = 0
+		return
+	}
+
+	hubThrottledResponses.Inc()
+	backoff, ok := retryAfter(resp)
+	if !ok {
+		backoff = 2 * l.backoff
+		if backoff < l.InitialBackoff {
+			backoff = l.InitialBackoff
+		}
+	}
+	if backoff > l.MaxBackoff {
+		backoff = l.MaxBackoff
+	}
+	l.backoff = backoff
+	if until := time.Now().Add(backoff); until.After(l.blockedUntil) {
+		l.blockedUntil = until
+	}
+	klog.V(2).InfoS("The hub API server throttled the member agent; the writes to the hub cluster are backed off",
+		"method", req.Method, "path", req.URL.Path, "backoff", backoff)
+}
+
+// retryAfter returns the delay the Retry-After header of a response asks for, in seconds as the Kubernetes API
+// server sets it.
+func retryAfter(resp *http.Response) (time.Duration, bool) {
+	seconds, err := strconv.Atoi(resp.Header.Get("Retry-After"))
+	if err != nil || seconds < 0 {
+		return 0, false
+	}
+	return time.Duration(seconds) * time.Second, true
+}
+
+// isWrite returns if a request writes to the API server.
+func isWrite(req *http.Request) bool {
+	switch req.Method {
+	case http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete:
+		return true
+	default:
+		return false
+	}
+}
+
+type roundTripper struct {
+	limiter  *Limiter
+	delegate http.RoundTripper
+}
+
+var _ http.RoundTripper = &roundTripper{}
+
+// RoundTrip implements the http.RoundTripper interface; the reads, including the watches, are never held back, but
+// their throttled responses back the writes off as well.
+func (r *roundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	if isWrite(req) {
+		if err := r.limiter.wait(req.Context()); err != nil {
+			return nil, err
+		}
+	}
+	resp, err := r.delegate.RoundTrip(req)
+	if err != nil {
+		return resp, err
+	}
+	r.limiter.observe(req, resp)
+	return resp, nil
+}
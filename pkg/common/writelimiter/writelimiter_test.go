@@ -0,0 +1,283 @@
+/*
+Copyright (c) Microsoft Corporation.
+Licensed under the MIT license.
+*/
+
+package writelimiter
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+const hubURL = "https://hub.example.com/apis/networking.fleet.azure.com/v1alpha1/namespaces/fleet-member-member-1/endpointsliceexports"
+
+// roundTripperFunc is a fake transport.
+type roundTripperFunc func(req *http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+// throttlingTransport is a fake transport which throttles the first requests sent with it.
+type throttlingTransport struct {
+	mu         sync.Mutex
+	throttled  int
+	retryAfter string
+	requests   int
+}
+
+func (t *throttlingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.requests++
+	resp := &http.Response{StatusCode: http.StatusOK, Header: http.Header{}, Request: req}
+	if t.throttled > 0 {
+		t.throttled--
+		resp.StatusCode = http.StatusTooManyRequests
+		if t.retryAfter != "" {
+			resp.Header.Set("Retry-After", t.retryAfter)
+		}
+	}
+	return resp, nil
+}
+
+// send sends a request with the transport and returns how long it took and the status code of the response; it
+// may be called from other goroutines than the test one, hence the errors are not fatal.
+func send(t *testing.T, rt http.RoundTripper, method string) (time.Duration, int) {
+	t.Helper()
+	req, err := http.NewRequest(method, hubURL, nil)
+	if err != nil {
+		t.Errorf("NewRequest() = %v, want no error", err)
+		return 0, 0
+	}
+	start := time.Now()
+	resp, err := rt.RoundTrip(req)
+	if err != nil {
+		t.Errorf("RoundTrip(%s) = %v, want no error", method, err)
+		return time.Since(start), 0
+	}
+	return time.Since(start), resp.StatusCode
+}
+
+// TestRetryAfter tests the retryAfter function.
+func TestRetryAfter(t *testing.T) {
+	testCases := []struct {
+		name   string
+		header string
+		want   time.Duration
+		wantOK bool
+	}{
+		{
+			name:   "seconds",
+			header: "3",
+			want:   3 * time.Second,
+			wantOK: true,
+		},
+		{
+			name: "missing",
+		},
+		{
+			name:   "http date",
+			header: "Wed, 21 Oct 2015 07:28:00 GMT",
+		},
+		{
+			name:   "negative",
+			header: "-1",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			resp := &http.Response{Header: http.Header{}}
+			if tc.header != "" {
+				resp.Header.Set("Retry-After", tc.header)
+			}
+			got, ok := retryAfter(resp)
+			if got != tc.want || ok != tc.wantOK {
+				t.Errorf("retryAfter() = %v, %t, want %v, %t", got, ok, tc.want, tc.wantOK)
+			}
+		})
+	}
+}
+
+// TestRoundTrip_AdaptiveBackoff tests that the writes are backed off exponentially on consecutive throttled responses
+// without a Retry-After header, and that the backoff is reset once a response is not throttled.
+func TestRoundTrip_AdaptiveBackoff(t *testing.T) {
+	fake := &throttlingTransport{throttled: 2}
+	limiter := New(0, 0, time.Second)
+	limiter.InitialBackoff = 50 * time.Millisecond
+	rt := limiter.WrapTransport(fake)
+	throttledBefore := testutil.ToFloat64(hubThrottledResponses)
+
+	steps := []struct {
+		name       string
+		method     string
+		minElapsed time.Duration
+		maxElapsed time.Duration
+		wantStatus int
+	}{
+		{
+			name:       "first write is throttled",
+			method:     http.MethodPatch,
+			maxElapsed: 40 * time.Millisecond,
+			wantStatus: http.StatusTooManyRequests,
+		},
+		{
+			name:       "write is backed off with the initial backoff",
+			method:     http.MethodPatch,
+			minElapsed: 40 * time.Millisecond,
+			maxElapsed: 90 * time.Millisecond,
+			wantStatus: http.StatusTooManyRequests,
+		},
+		{
+			name:       "write is backed off with the doubled backoff",
+			method:     http.MethodPut,
+			minElapsed: 90 * time.Millisecond,
+			maxElapsed: 500 * time.Millisecond,
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:       "write is not backed off after a response which is not throttled",
+			method:     http.MethodDelete,
+			maxElapsed: 40 * time.Millisecond,
+			wantStatus: http.StatusOK,
+		},
+	}
+	for _, step := range steps {
+		elapsed, status := send(t, rt, step.method)
+		if status != step.wantStatus {
+			t.Fatalf("%s: status = %d, want %d", step.name, status, step.wantStatus)
+		}
+		if elapsed < step.minElapsed || elapsed > step.maxElapsed {
+			t.Fatalf("%s: elapsed = %v, want between %v and %v", step.name, elapsed, step.minElapsed, step.maxElapsed)
+		}
+	}
+	if got := testutil.ToFloat64(hubThrottledResponses) - throttledBefore; got != 2 {
+		t.Errorf("fleet_networking_hub_throttled_responses_total increased by %v, want 2", got)
+	}
+}
+
+// TestRoundTrip_RetryAfter tests that the writes are backed off for the delay the Retry-After header asks for, capped
+// by the max backoff, while the reads are never held back.
+func TestRoundTrip_RetryAfter(t *testing.T) {
+	fake := &throttlingTransport{throttled: 1, retryAfter: "5"}
+	limiter := New(0, 0, time.Second)
+	limiter.MaxBackoff = 200 * time.Millisecond
+	rt := limiter.WrapTransport(fake)
+
+	if _, status := send(t, rt, http.MethodPost); status != http.StatusTooManyRequests {
+		t.Fatalf("first write status = %d, want %d", status, http.StatusTooManyRequests)
+	}
+	if got := limiter.Saturation(); got != 1 {
+		t.Errorf("Saturation() while backed off = %v, want 1", got)
+	}
+	if elapsed, _ := send(t, rt, http.MethodGet); elapsed > 100*time.Millisecond {
+		t.Errorf("read elapsed = %v, want it not to be held back", elapsed)
+	}
+	if elapsed, _ := send(t, rt, http.MethodPost); elapsed < 100*time.Millisecond || elapsed > time.Second {
+		t.Errorf("write elapsed = %v, want the max backoff of %v", elapsed, limiter.MaxBackoff)
+	}
+}
+
+// TestRoundTrip_SharedBucket tests that the writes of two controllers, each with its own client built from the same
+// config, share one token bucket, and that a throttled response of one controller backs the other off as well.
+func TestRoundTrip_SharedBucket(t *testing.T) {
+	const (
+		qps             = 20
+		writesPerClient = 5
+	)
+	fake := &throttlingTransport{}
+	limiter := New(qps, 1, time.Second)
+	// The transport of each client is wrapped separately, as rest.Config does for every client built from it.
+	controllerA, controllerB := limiter.WrapTransport(fake), limiter.WrapTransport(fake)
+
+	start := time.Now()
+	wg := sync.WaitGroup{}
+	for _, rt := range []http.RoundTripper{controllerA, controllerB} {
+		wg.Add(1)
+		go func(rt http.RoundTripper) {
+			defer wg.Done()
+			for i := 0; i < writesPerClient; i++ {
+				send(t, rt, http.MethodPatch)
+			}
+		}(rt)
+	}
+	wg.Wait()
+	// With a burst of 1, the 2*writesPerClient writes take at least (2*writesPerClient-1)/qps.
+	if elapsed, want := time.Since(start), (2*writesPerClient-1)*time.Second/qps; elapsed < want-20*time.Millisecond {
+		t.Errorf("writes of both controllers took %v, want at least %v with a shared bucket", elapsed, want)
+	}
+	if got := limiter.Saturation(); got <= 0 {
+		t.Errorf("Saturation() right after the writes = %v, want above 0", got)
+	}
+
+	// A throttled response to a write of controller A holds the writes of controller B back.
+	limiter.InitialBackoff = 200 * time.Millisecond
+	fake.mu.Lock()
+	fake.throttled = 1
+	fake.mu.Unlock()
+	send(t, controllerA, http.MethodPatch)
+	if elapsed, _ := send(t, controllerB, http.MethodPatch); elapsed < 150*time.Millisecond {
+		t.Errorf("write of the other controller elapsed = %v, want it to be backed off for %v", elapsed, limiter.InitialBackoff)
+	}
+}
+
+// TestDrain tests that once the limiter is drained, the writes, including the ones being held back, are delayed by at
+// most the max shutdown delay.
+func TestDrain(t *testing.T) {
+	const maxShutdownDelay = 50 * time.Millisecond
+	// A token every 100 seconds.
+	limiter := New(0.01, 1, maxShutdownDelay)
+	rt := limiter.WrapTransport(&throttlingTransport{})
+	send(t, rt, http.MethodPatch)
+
+	done := make(chan time.Duration)
+	go func() {
+		elapsed, _ := send(t, rt, http.MethodPatch)
+		done <- elapsed
+	}()
+	time.Sleep(20 * time.Millisecond)
+	limiter.Drain()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatalf("write held back before the drain is still held back %v after the drain, want at most %v", time.Second, maxShutdownDelay)
+	}
+
+	if elapsed, _ := send(t, rt, http.MethodDelete); elapsed > time.Second {
+		t.Errorf("write after the drain elapsed = %v, want at most %v", elapsed, maxShutdownDelay)
+	}
+	// Draining twice is a no-op.
+	limiter.Drain()
+}
+
+// TestRoundTrip_ContextDone tests that a write held back is abandoned once its context is done.
+func TestRoundTrip_ContextDone(t *testing.T) {
+	limiter := New(0.01, 1, time.Second)
+	requests := 0
+	rt := limiter.WrapTransport(roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		requests++
+		return &http.Response{StatusCode: http.StatusOK, Header: http.Header{}, Request: req}, nil
+	}))
+	send(t, rt, http.MethodPatch)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodPatch, hubURL, strings.NewReader("{}"))
+	if err != nil {
+		t.Fatalf("NewRequestWithContext() = %v, want no error", err)
+	}
+	if _, err := rt.RoundTrip(req); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("RoundTrip() = %v, want %v", err, context.DeadlineExceeded)
+	}
+	if requests != 1 {
+		t.Errorf("requests sent = %d, want 1", requests)
+	}
+}
@@ -11,6 +11,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"strconv"
 	"time"
 
 	"k8s.io/apimachinery/pkg/api/errors"
@@ -18,14 +19,21 @@ import (
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/klog/v2"
 	ctrl "sigs.k8s.io/controller-runtime"
+	ctrlbuilder "sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/event"
 	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	fleetnetv1alpha1 "go.goms.io/fleet-networking/api/v1alpha1"
 	"go.goms.io/fleet-networking/pkg/common/apiretry"
+	"go.goms.io/fleet-networking/pkg/common/consumerpolicy"
+	"go.goms.io/fleet-networking/pkg/common/exportweight"
+	"go.goms.io/fleet-networking/pkg/common/listguard"
 	"go.goms.io/fleet-networking/pkg/common/objectmeta"
+	"go.goms.io/fleet-networking/pkg/common/reconcilewatchdog"
 )
 
 const (
@@ -58,11 +66,25 @@ var (
 // Reconciler reconciles the distribution of EndpointSlices across the fleet.
 type Reconciler struct {
 	HubClient client.Client
+
+	// OrphanGracePeriod is how long an EndpointSliceExport may exist without the InternalServiceExport of its owner
+	// Service in the same hub namespace, counting from when it is exported, before it is deleted as an orphan, e.g.
+	// left behind when the ServiceExport is deleted while the member agent is down; 0 disables the check. The grace
+	// period keeps a new EndpointSliceExport which lands before its InternalServiceExport from being deleted.
+	OrphanGracePeriod time.Duration
+
+	// DeletionCandidateGracePeriod is how long an EndpointSliceExport marked as a deletion candidate by the member
+	// agent keeps being distributed, counting from when it is marked, before it is deleted, unless the member agent
+	// exports the EndpointSlice again, which clears the mark; this keeps the importing member clusters from losing the
+	// endpoints while the member agent revalidates its exports, e.g. after a restart.
+	DeletionCandidateGracePeriod time.Duration
 }
 
-//+kubebuilder:rbac:groups=networking.fleet.azure.com,resources=endpointsliceexports,verbs=get;list;watch;create;update;patch
+//+kubebuilder:rbac:groups=networking.fleet.azure.com,resources=endpointsliceexports,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups=networking.fleet.azure.com,resources=internalserviceexports,verbs=get;list;watch
 //+kubebuilder:rbac:groups=networking.fleet.azure.com,resources=endpointsliceimports,verbs=get;list;watch;create;update;patch;delete
 //+kubebuilder:rbac:groups=networking.fleet.azure.com,resources=serviceimports,verbs=get;list;watch
+//+kubebuilder:rbac:groups=cluster.kubernetes-fleet.io,resources=memberclusters,verbs=get;list;watch
 //+kubebuilder:rbac:groups=discovery.k8s.io,resources=endpointslices,verbs=get;create;update;patch;delete;list;watch
 
 // Reconcile distributes an exported EndpointSlice (in the form of EndpointSliceExports) to whichever member
@@ -106,6 +128,94 @@ func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Resu
 		return ctrl.Result{}, nil
 	}
 
+	if candidateSince, ok := deletionCandidateSince(endpointSliceExport); ok {
+		return r.handleDeletionCandidate(ctx, endpointSliceExport, candidateSince)
+	}
+
+	if r.OrphanGracePeriod <= 0 {
+		return r.distributeEndpointSlice(ctx, endpointSliceExport)
+	}
+	isOwnerExported, err := r.isOwnerServiceExported(ctx, endpointSliceExport)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	if isOwnerExported {
+		return r.distributeEndpointSlice(ctx, endpointSliceExport)
+	}
+	orphanedFor := time.Since(exportedSince(endpointSliceExport))
+	if orphanedFor < 0 {
+		// The export time is written with the clock of the member cluster, which may be ahead of the hub cluster.
+		orphanedFor = 0
+	}
+	if orphanedFor >= r.OrphanGracePeriod {
+		// The EndpointSliceImports distributed from the EndpointSliceExport are withdrawn when the deletion is
+		// processed, as the cleanup finalizer is set once they are distributed.
+		klog.V(2).InfoS("The owner Service is no longer exported; delete the orphaned EndpointSliceExport",
+			"endpointSliceExport", endpointSliceExportRef,
+			"ownerService", endpointSliceExport.Spec.OwnerServiceReference.NamespacedName,
+			"orphanedFor", orphanedFor)
+		if err := r.HubClient.Delete(ctx, endpointSliceExport); err != nil && !errors.IsNotFound(err) {
+			klog.ErrorS(err, "Failed to delete the orphaned EndpointSliceExport", "endpointSliceExport", endpointSliceExportRef)
+			return ctrl.Result{}, err
+		}
+		return ctrl.Result{}, nil
+	}
+
+	// The InternalServiceExport of a new export may land after its EndpointSliceExports; check again when the grace
+	// period ends.
+	klog.V(2).InfoS("The owner Service is not exported yet; check again after the grace period",
+		"endpointSliceExport", endpointSliceExportRef,
+		"ownerService", endpointSliceExport.Spec.OwnerServiceReference.NamespacedName)
+	res, err := r.distributeEndpointSlice(ctx, endpointSliceExport)
+	if err != nil {
+		return res, err
+	}
+	if graceRemaining := r.OrphanGracePeriod - orphanedFor; res.RequeueAfter == 0 || graceRemaining < res.RequeueAfter {
+		res.RequeueAfter = graceRemaining
+	}
+	return res, nil
+}
+
+// handleDeletionCandidate keeps distributing the EndpointSlice exported as the EndpointSliceExport marked as a deletion
+// candidate until the grace period ends, and deletes the EndpointSliceExport afterwards.
+func (r *Reconciler) handleDeletionCandidate(ctx context.Context, endpointSliceExport *fleetnetv1alpha1.EndpointSliceExport, candidateSince time.Time) (ctrl.Result, error) {
+	endpointSliceExportRef := klog.KObj(endpointSliceExport)
+	candidateFor := time.Since(candidateSince)
+	if candidateFor < 0 {
+		// The mark is written with the clock of the member cluster, which may be ahead of the hub cluster.
+		candidateFor = 0
+	}
+	if candidateFor >= r.DeletionCandidateGracePeriod {
+		// The EndpointSliceImports distributed from the EndpointSliceExport are withdrawn when the deletion is
+		// processed, as the cleanup finalizer is set once they are distributed.
+		klog.V(2).InfoS("The EndpointSliceExport has not been exported again within the grace period; delete the deletion candidate",
+			"endpointSliceExport", endpointSliceExportRef,
+			"candidateFor", candidateFor)
+		if err := r.HubClient.Delete(ctx, endpointSliceExport); err != nil && !errors.IsNotFound(err) {
+			klog.ErrorS(err, "Failed to delete the EndpointSliceExport marked as a deletion candidate", "endpointSliceExport", endpointSliceExportRef)
+			return ctrl.Result{}, err
+		}
+		return ctrl.Result{}, nil
+	}
+
+	klog.V(2).InfoS("The EndpointSliceExport is a deletion candidate; keep distributing it until the grace period ends",
+		"endpointSliceExport", endpointSliceExportRef,
+		"candidateFor", candidateFor)
+	res, err := r.distributeEndpointSlice(ctx, endpointSliceExport)
+	if err != nil {
+		return res, err
+	}
+	if graceRemaining := r.DeletionCandidateGracePeriod - candidateFor; res.RequeueAfter == 0 || graceRemaining < res.RequeueAfter {
+		res.RequeueAfter = graceRemaining
+	}
+	return res, nil
+}
+
+// distributeEndpointSlice distributes the EndpointSlice exported as the EndpointSliceExport to the member clusters
+// importing its owner Service, and withdraws it from the other member clusters.
+func (r *Reconciler) distributeEndpointSlice(ctx context.Context, endpointSliceExport *fleetnetv1alpha1.EndpointSliceExport) (ctrl.Result, error) {
+	endpointSliceExportRef := klog.KObj(endpointSliceExport)
+
 	// Inquire the corresponding ServiceImport to find out which member clusters the EndpointSlice should be
 	// distributed to.
 	ownerSvcNS := endpointSliceExport.Spec.OwnerServiceReference.Namespace
@@ -167,6 +277,15 @@ func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Resu
 		return ctrl.Result{}, nil
 	}
 
+	// Leave out the member clusters which are not allowed to import the Service by the consumer policy of the
+	// exporting cluster; their EndpointSliceImports (if any) are withdrawn.
+	if err := r.removeImportersNotPermitted(ctx, svcImport, endpointSliceExport, svcInUseBy); err != nil {
+		klog.ErrorS(err, "Failed to evaluate the consumer policy of the exporting cluster",
+			"serviceImport", svcImportRef,
+			"endpointSliceExport", endpointSliceExportRef)
+		return ctrl.Result{}, err
+	}
+
 	// Distribute the EndpointSlices.
 
 	// Add cleanup finalizer to the EndpointSliceExport; this must happen before EndpointSlice is distributed.
@@ -230,6 +349,7 @@ func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Resu
 			var createOrUpdateErr error
 			op, createOrUpdateErr = controllerutil.CreateOrUpdate(ctx, r.HubClient, endpointSliceImport, func() error {
 				endpointSliceImport.Spec = *endpointSliceExport.Spec.DeepCopy()
+				setEndpointWeight(endpointSliceImport, exportweight.Find(&svcImport.Status, endpointSliceExport.Spec.EndpointSliceReference.ClusterID))
 				return nil
 			})
 			return createOrUpdateErr
@@ -278,12 +398,14 @@ func (r *Reconciler) SetupWithManager(ctx context.Context, mgr ctrl.Manager) err
 		fieldMatcher := client.MatchingFields{
 			endpointSliceExportOwnerSvcNamespacedNameFieldKey: fmt.Sprintf("%s/%s", svcImport.Namespace, svcImport.Name),
 		}
-		if err := r.HubClient.List(ctx, endpointSliceExportList, fieldMatcher); err != nil {
+		// Only the names of the EndpointSliceExports are read, so that they are not deep-copied out of the cache.
+		if err := r.HubClient.List(ctx, endpointSliceExportList, fieldMatcher, client.UnsafeDisableDeepCopy); err != nil {
 			klog.ErrorS(err,
 				"Failed to list EndpointSliceExports for an imported Service",
 				"serviceImport", klog.KObj(svcImport))
 			return []reconcile.Request{}
 		}
+		listguard.ObserveListSize(endpointSliceExportList, "serviceImport", klog.KObj(svcImport))
 
 		reqs := make([]reconcile.Request, 0, len(endpointSliceExportList.Items))
 		for _, endpointSliceExport := range endpointSliceExportList.Items {
@@ -297,10 +419,111 @@ func (r *Reconciler) SetupWithManager(ctx context.Context, mgr ctrl.Manager) err
 		return reqs
 	})
 
-	return ctrl.NewControllerManagedBy(mgr).
+	builder := ctrl.NewControllerManagedBy(mgr).
 		For(&fleetnetv1alpha1.EndpointSliceExport{}).
-		Watches(&fleetnetv1alpha1.ServiceImport{}, eventHandlers).
-		Complete(r)
+		Watches(&fleetnetv1alpha1.ServiceImport{}, eventHandlers)
+	if r.OrphanGracePeriod > 0 {
+		// Enqueue the EndpointSliceExports of a Service when its InternalServiceExport is deleted, so that they are
+		// deleted as orphans if they outlive it; the orphans missed otherwise are found when the cache resyncs.
+		builder = builder.Watches(&fleetnetv1alpha1.InternalServiceExport{},
+			handler.EnqueueRequestsFromMapFunc(r.internalServiceExportToEndpointSliceExports),
+			ctrlbuilder.WithPredicates(predicate.Funcs{
+				CreateFunc:  func(event.CreateEvent) bool { return false },
+				UpdateFunc:  func(event.UpdateEvent) bool { return false },
+				DeleteFunc:  func(event.DeleteEvent) bool { return true },
+				GenericFunc: func(event.GenericEvent) bool { return false },
+			}))
+	}
+	return builder.Complete(reconcilewatchdog.Wrap("endpointsliceexport", r))
+}
+
+// internalServiceExportToEndpointSliceExports returns the EndpointSliceExports of the Service exported as the
+// InternalServiceExport, which are among the exports of the same member cluster.
+func (r *Reconciler) internalServiceExportToEndpointSliceExports(ctx context.Context, o client.Object) []reconcile.Request {
+	internalSvcExport, ok := o.(*fleetnetv1alpha1.InternalServiceExport)
+	if !ok {
+		return []reconcile.Request{}
+	}
+
+	endpointSliceExportList := &fleetnetv1alpha1.EndpointSliceExportList{}
+	listOpts := []client.ListOption{
+		memberClusterExportsListOption(internalSvcExport),
+		client.MatchingFields{endpointSliceExportOwnerSvcNamespacedNameFieldKey: internalSvcExport.Spec.ServiceReference.NamespacedName},
+		// Only the names of the EndpointSliceExports are read, so that they are not deep-copied out of the cache.
+		client.UnsafeDisableDeepCopy,
+	}
+	if err := r.HubClient.List(ctx, endpointSliceExportList, listOpts...); err != nil {
+		klog.ErrorS(err, "Failed to list EndpointSliceExports for an unexported Service",
+			"internalServiceExport", klog.KObj(internalSvcExport))
+		return []reconcile.Request{}
+	}
+
+	reqs := make([]reconcile.Request, 0, len(endpointSliceExportList.Items))
+	for _, endpointSliceExport := range endpointSliceExportList.Items {
+		reqs = append(reqs, reconcile.Request{
+			NamespacedName: types.NamespacedName{
+				Namespace: endpointSliceExport.Namespace,
+				Name:      endpointSliceExport.Name,
+			},
+		})
+	}
+	return reqs
+}
+
+// isOwnerServiceExported returns if the owner Service of the EndpointSliceExport is exported from the member cluster,
+// i.e., an InternalServiceExport of the Service exists among the exports of the member cluster.
+func (r *Reconciler) isOwnerServiceExported(ctx context.Context, endpointSliceExport *fleetnetv1alpha1.EndpointSliceExport) (bool, error) {
+	internalSvcExportList := &fleetnetv1alpha1.InternalServiceExportList{}
+	// The InternalServiceExports are only read, so that they are not deep-copied out of the cache.
+	if err := r.HubClient.List(ctx, internalSvcExportList, memberClusterExportsListOption(endpointSliceExport), client.UnsafeDisableDeepCopy); err != nil {
+		klog.ErrorS(err, "Failed to list InternalServiceExports", "endpointSliceExport", klog.KObj(endpointSliceExport))
+		return false, err
+	}
+	listguard.ObserveListSize(internalSvcExportList, "endpointSliceExport", klog.KObj(endpointSliceExport))
+	for idx := range internalSvcExportList.Items {
+		// The Service is referred to by the name under which it is exported by both objects.
+		if internalSvcExportList.Items[idx].Spec.ServiceReference.NamespacedName == endpointSliceExport.Spec.OwnerServiceReference.NamespacedName {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// memberClusterExportsListOption returns the option to list the exports of the member cluster which exports the
+// object; the exports of a member cluster may be sharded across multiple hub namespaces, and are listed by the member
+// cluster label, unless the object has been exported by a member agent which does not label its exports, in which
+// case all the exports of the member cluster reside in the namespace of the object.
+func memberClusterExportsListOption(obj client.Object) client.ListOption {
+	if clusterID, ok := obj.GetLabels()[objectmeta.ExportedObjectLabelMemberCluster]; ok {
+		return client.MatchingLabels{objectmeta.ExportedObjectLabelMemberCluster: clusterID}
+	}
+	return client.InNamespace(obj.GetNamespace())
+}
+
+// exportedSince returns when the EndpointSliceExport is exported; the creation time is used for the
+// EndpointSliceExports exported by the member agents which do not report the export time.
+func exportedSince(endpointSliceExport *fleetnetv1alpha1.EndpointSliceExport) time.Time {
+	if exportedSince := endpointSliceExport.Spec.EndpointSliceReference.ExportedSince; !exportedSince.IsZero() {
+		return exportedSince.Time
+	}
+	return endpointSliceExport.CreationTimestamp.Time
+}
+
+// deletionCandidateSince returns when the EndpointSliceExport is marked as a deletion candidate by the member agent,
+// and whether it is marked; a mark which cannot be parsed is ignored.
+func deletionCandidateSince(endpointSliceExport *fleetnetv1alpha1.EndpointSliceExport) (time.Time, bool) {
+	data, ok := endpointSliceExport.Annotations[objectmeta.EndpointSliceExportAnnotationDeletionCandidateSince]
+	if !ok {
+		return time.Time{}, false
+	}
+	candidateSince, err := time.Parse(time.RFC3339, data)
+	if err != nil {
+		klog.ErrorS(err, "Failed to parse the deletion candidate annotation; ignore it",
+			"endpointSliceExport", klog.KObj(endpointSliceExport),
+			"data", data)
+		return time.Time{}, false
+	}
+	return candidateSince, true
 }
 
 // withdrawEndpointSliceImports withdraws EndpointSliceImports distributed across the fleet.
@@ -316,6 +539,7 @@ func (r *Reconciler) withdrawAllEndpointSliceImports(ctx context.Context, endpoi
 			"endpointSliceExport", klog.KObj(endpointSliceExport))
 		return err
 	}
+	listguard.ObserveListSize(endpointSliceImportList, "endpointSliceExport", klog.KObj(endpointSliceExport))
 
 	// Withdraw EndpointSliceImports from member clusters.
 	for idx := range endpointSliceImportList.Items {
@@ -338,6 +562,46 @@ func (r *Reconciler) withdrawAllEndpointSliceImports(ctx context.Context, endpoi
 	return nil
 }
 
+// setEndpointWeight annotates the EndpointSliceImport with the weight of the cluster exporting its endpoints, or
+// removes the annotation if the weight is not set.
+func setEndpointWeight(endpointSliceImport *fleetnetv1alpha1.EndpointSliceImport, weight *int64) {
+	if weight == nil {
+		delete(endpointSliceImport.Annotations, objectmeta.EndpointSliceAnnotationEndpointWeight)
+		return
+	}
+	if endpointSliceImport.Annotations == nil {
+		endpointSliceImport.Annotations = map[string]string{}
+	}
+	endpointSliceImport.Annotations[objectmeta.EndpointSliceAnnotationEndpointWeight] = strconv.FormatInt(*weight, 10)
+}
+
+// removeImportersNotPermitted removes from the ServiceInUseBy information the member clusters which are not allowed to
+// import the Service by the consumer policy of the cluster exporting the EndpointSlice.
+func (r *Reconciler) removeImportersNotPermitted(ctx context.Context,
+	svcImport *fleetnetv1alpha1.ServiceImport,
+	endpointSliceExport *fleetnetv1alpha1.EndpointSliceExport,
+	svcInUseBy *fleetnetv1alpha1.ServiceInUseBy,
+) error {
+	policy := consumerpolicy.Find(&svcImport.Status, endpointSliceExport.Spec.EndpointSliceReference.ClusterID)
+	if policy == nil {
+		return nil
+	}
+	for ns, clusterID := range svcInUseBy.MemberClusters {
+		permitted, err := consumerpolicy.NewEvaluator(r.HubClient, string(clusterID)).Permits(ctx, policy)
+		if err != nil {
+			return err
+		}
+		if !permitted {
+			klog.V(2).InfoS("The member cluster is not allowed to import the Service by the consumer policy of the exporting cluster",
+				"clusterID", clusterID,
+				"serviceImport", klog.KObj(svcImport),
+				"endpointSliceExport", klog.KObj(endpointSliceExport))
+			delete(svcInUseBy.MemberClusters, ns)
+		}
+	}
+	return nil
+}
+
 // removeEndpointSliceExportCleanupFinalizer removes the cleanup finalizer from an EndpointSliceExport.
 func (r *Reconciler) removeEndpointSliceExportCleanupFinalizer(ctx context.Context, endpointSliceExport *fleetnetv1alpha1.EndpointSliceExport) error {
 	controllerutil.RemoveFinalizer(endpointSliceExport, endpointSliceExportCleanupFinalizer)
@@ -376,6 +640,7 @@ func (r *Reconciler) scanForEndpointSliceImports(
 			"endpointSliceExport", klog.KObj(endpointSliceExport))
 		return endpointSliceImportsToWithdraw, endpointSliceImportsToCreateOrUpdate, err
 	}
+	listguard.ObserveListSize(endpointSliceImportList, "endpointSliceExport", klog.KObj(endpointSliceExport))
 
 	// Match the EndpointSliceImports with the member clusters that have requested the EndpointSlice.
 	for idx := range endpointSliceImportList.Items {
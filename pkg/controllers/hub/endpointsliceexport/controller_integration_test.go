@@ -26,6 +26,8 @@ const (
 	eventuallyInterval   = time.Millisecond * 250
 	consistentlyDuration = time.Millisecond * 1000
 	consistentlyInterval = time.Millisecond * 150
+
+	deletionCandidateGracePeriod = time.Second * 3
 )
 
 var (
@@ -62,6 +64,36 @@ func unfulfilledAndRequestedServiceImport() *fleetnetv1alpha1.ServiceImport {
 	}
 }
 
+// endpointSliceImportCount returns the number of EndpointSliceImports distributed across the fleet.
+func endpointSliceImportCount() (int, error) {
+	endpointSliceImportList := &fleetnetv1alpha1.EndpointSliceImportList{}
+	if err := hubClient.List(ctx, endpointSliceImportList); err != nil {
+		return 0, err
+	}
+	return len(endpointSliceImportList.Items), nil
+}
+
+// setDeletionCandidateSince marks the EndpointSliceExport as a deletion candidate since the given time, as done by
+// the member agent when it withdraws the EndpointSlice, or clears the mark, as done when the member agent exports
+// the EndpointSlice again, if the time is zero.
+func setDeletionCandidateSince(candidateSince time.Time) {
+	Eventually(func() error {
+		endpointSliceExport := &fleetnetv1alpha1.EndpointSliceExport{}
+		if err := hubClient.Get(ctx, endpointSliceExportKey, endpointSliceExport); err != nil {
+			return err
+		}
+		if candidateSince.IsZero() {
+			delete(endpointSliceExport.Annotations, objectmeta.EndpointSliceExportAnnotationDeletionCandidateSince)
+		} else {
+			if endpointSliceExport.Annotations == nil {
+				endpointSliceExport.Annotations = map[string]string{}
+			}
+			endpointSliceExport.Annotations[objectmeta.EndpointSliceExportAnnotationDeletionCandidateSince] = candidateSince.UTC().Format(time.RFC3339)
+		}
+		return hubClient.Update(ctx, endpointSliceExport)
+	}, eventuallyTimeout, eventuallyInterval).Should(Succeed())
+}
+
 // fulfillSvcImport fulfills a ServiceImport by updating its status.
 func fulfillSvcImport(svcImport *fleetnetv1alpha1.ServiceImport) {
 	svcImport.Status = fleetnetv1alpha1.ServiceImportStatus{
@@ -1022,4 +1054,225 @@ var _ = Describe("endpointsliceexport controller", func() {
 			}, eventuallyTimeout, eventuallyInterval).Should(BeTrue())
 		})
 	})
+
+	Context("orphaned endpointsliceexport", func() {
+		var endpointSliceExport *fleetnetv1alpha1.EndpointSliceExport
+		var svcImport *fleetnetv1alpha1.ServiceImport
+
+		BeforeEach(func() {
+			svcImport = unfulfilledAndRequestedServiceImport()
+			Expect(hubClient.Create(ctx, svcImport)).Should(Succeed())
+			fulfillSvcImport(svcImport)
+			Expect(hubClient.Status().Update(ctx, svcImport)).Should(Succeed())
+
+			// The EndpointSliceExport has been distributed before its owner Service was unexported.
+			endpointSliceExport = ipv4EndpointSliceExport()
+			endpointSliceExport.Spec.EndpointSliceReference.ExportedSince = metav1.NewTime(time.Now().Add(-time.Hour * 2).Round(time.Second))
+			for _, ns := range []string{hubNSForMemberB, hubNSForMemberC} {
+				endpointSliceImport := &fleetnetv1alpha1.EndpointSliceImport{
+					ObjectMeta: metav1.ObjectMeta{
+						Namespace: ns,
+						Name:      endpointSliceExportName,
+					},
+					Spec: *endpointSliceExport.Spec.DeepCopy(),
+				}
+				Expect(hubClient.Create(ctx, endpointSliceImport)).Should(Succeed())
+			}
+			Expect(hubClient.Create(ctx, endpointSliceExport)).Should(Succeed())
+		})
+
+		AfterEach(func() {
+			Expect(hubClient.Delete(ctx, svcImport)).Should(Succeed())
+			// Confirm that ServiceImport is deleted; this helps make the test less flaky.
+			Eventually(func() error {
+				return client.IgnoreNotFound(hubClient.Get(ctx, svcImportKey, svcImport))
+			}, eventuallyTimeout, eventuallyInterval).Should(BeNil())
+		})
+
+		It("should delete the endpointsliceexport and withdraw all endpointsliceimports", func() {
+			Eventually(func() bool {
+				endpointSliceImportList := &fleetnetv1alpha1.EndpointSliceImportList{}
+				if err := hubClient.List(ctx, endpointSliceImportList); err != nil {
+					return false
+				}
+
+				if len(endpointSliceImportList.Items) != 0 {
+					return false
+				}
+
+				if err := hubClient.Get(ctx, endpointSliceExportKey, endpointSliceExport); !errors.IsNotFound(err) {
+					return false
+				}
+				return true
+			}, eventuallyTimeout, eventuallyInterval).Should(BeTrue())
+		})
+	})
+
+	Context("endpointsliceexport without internalserviceexport (within grace period)", func() {
+		var endpointSliceExport *fleetnetv1alpha1.EndpointSliceExport
+
+		BeforeEach(func() {
+			endpointSliceExport = ipv4EndpointSliceExport()
+			endpointSliceExport.Finalizers = []string{}
+			Expect(hubClient.Create(ctx, endpointSliceExport)).Should(Succeed())
+		})
+
+		AfterEach(func() {
+			Expect(hubClient.Delete(ctx, endpointSliceExport)).Should(Succeed())
+			Eventually(func() error {
+				return client.IgnoreNotFound(hubClient.Get(ctx, endpointSliceExportKey, endpointSliceExport))
+			}, eventuallyTimeout, eventuallyInterval).Should(BeNil())
+		})
+
+		It("should not delete the endpointsliceexport", func() {
+			Consistently(func() error {
+				return hubClient.Get(ctx, endpointSliceExportKey, endpointSliceExport)
+			}, consistentlyDuration, consistentlyInterval).Should(Succeed())
+		})
+	})
+
+	Context("endpointsliceexport with internalserviceexport", func() {
+		var endpointSliceExport *fleetnetv1alpha1.EndpointSliceExport
+		var internalSvcExport *fleetnetv1alpha1.InternalServiceExport
+
+		BeforeEach(func() {
+			internalSvcExport = &fleetnetv1alpha1.InternalServiceExport{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: hubNSForMemberA,
+					Name:      memberUserNS + "-" + svcName,
+				},
+				Spec: fleetnetv1alpha1.InternalServiceExportSpec{
+					Ports: []fleetnetv1alpha1.ServicePort{
+						{
+							Name:     httpPortName,
+							Protocol: httpPortProtocol,
+							Port:     httpPort,
+						},
+					},
+					ServiceReference: fleetnetv1alpha1.ExportedObjectReference{
+						ClusterID:       hubNSForMemberA,
+						Kind:            "Service",
+						Namespace:       memberUserNS,
+						Name:            svcName,
+						ResourceVersion: "0",
+						Generation:      1,
+						UID:             "00000000-0000-0000-0000-000000000001",
+						NamespacedName:  memberUserNS + "/" + svcName,
+						ExportedSince:   metav1.NewTime(time.Now().Add(-time.Hour * 2).Round(time.Second)),
+					},
+				},
+			}
+			Expect(hubClient.Create(ctx, internalSvcExport)).Should(Succeed())
+
+			endpointSliceExport = ipv4EndpointSliceExport()
+			endpointSliceExport.Finalizers = []string{}
+			endpointSliceExport.Spec.EndpointSliceReference.ExportedSince = metav1.NewTime(time.Now().Add(-time.Hour * 2).Round(time.Second))
+			Expect(hubClient.Create(ctx, endpointSliceExport)).Should(Succeed())
+		})
+
+		AfterEach(func() {
+			Expect(hubClient.Delete(ctx, endpointSliceExport)).Should(Succeed())
+			Eventually(func() error {
+				return client.IgnoreNotFound(hubClient.Get(ctx, endpointSliceExportKey, endpointSliceExport))
+			}, eventuallyTimeout, eventuallyInterval).Should(BeNil())
+
+			Expect(hubClient.Delete(ctx, internalSvcExport)).Should(Succeed())
+		})
+
+		It("should not delete the endpointsliceexport", func() {
+			Consistently(func() error {
+				return hubClient.Get(ctx, endpointSliceExportKey, endpointSliceExport)
+			}, consistentlyDuration, consistentlyInterval).Should(Succeed())
+		})
+	})
+
+	Context("endpointsliceexport marked as a deletion candidate and exported again (member agent restart)", func() {
+		var endpointSliceExport *fleetnetv1alpha1.EndpointSliceExport
+		var svcImport *fleetnetv1alpha1.ServiceImport
+
+		BeforeEach(func() {
+			svcImport = unfulfilledAndRequestedServiceImport()
+			Expect(hubClient.Create(ctx, svcImport)).Should(Succeed())
+			fulfillSvcImport(svcImport)
+			Expect(hubClient.Status().Update(ctx, svcImport)).Should(Succeed())
+
+			endpointSliceExport = ipv4EndpointSliceExport()
+			endpointSliceExport.Finalizers = []string{}
+			Expect(hubClient.Create(ctx, endpointSliceExport)).Should(Succeed())
+
+			// Wait until the EndpointSlice has been distributed.
+			Eventually(endpointSliceImportCount, eventuallyTimeout, eventuallyInterval).Should(Equal(2))
+		})
+
+		AfterEach(func() {
+			Expect(hubClient.Delete(ctx, endpointSliceExport)).Should(Succeed())
+			// Wait until all EndpointSliceExport resources are cleaned up; this helps make the test less flaky.
+			Eventually(func() bool {
+				if count, err := endpointSliceImportCount(); err != nil || count != 0 {
+					return false
+				}
+				return errors.IsNotFound(hubClient.Get(ctx, endpointSliceExportKey, endpointSliceExport))
+			}, eventuallyTimeout, eventuallyInterval).Should(BeTrue())
+
+			Expect(hubClient.Delete(ctx, svcImport)).Should(Succeed())
+			// Confirm that ServiceImport is deleted; this helps make the test less flaky.
+			Eventually(func() error {
+				return client.IgnoreNotFound(hubClient.Get(ctx, svcImportKey, svcImport))
+			}, eventuallyTimeout, eventuallyInterval).Should(BeNil())
+		})
+
+		It("should keep the endpointslice distributed to member clusters throughout", func() {
+			By("Marking the endpointsliceexport as a deletion candidate, as when the member agent withdraws it")
+			setDeletionCandidateSince(time.Now())
+			Consistently(endpointSliceImportCount, consistentlyDuration, consistentlyInterval).Should(Equal(2))
+
+			By("Clearing the mark, as when the member agent exports the endpointslice again")
+			setDeletionCandidateSince(time.Time{})
+
+			By("Checking the endpointslice stays distributed after the grace period")
+			Consistently(endpointSliceImportCount, deletionCandidateGracePeriod+consistentlyDuration, consistentlyInterval).Should(Equal(2))
+			Expect(hubClient.Get(ctx, endpointSliceExportKey, endpointSliceExport)).Should(Succeed())
+		})
+	})
+
+	Context("endpointsliceexport marked as a deletion candidate and not exported again", func() {
+		var endpointSliceExport *fleetnetv1alpha1.EndpointSliceExport
+		var svcImport *fleetnetv1alpha1.ServiceImport
+
+		BeforeEach(func() {
+			svcImport = unfulfilledAndRequestedServiceImport()
+			Expect(hubClient.Create(ctx, svcImport)).Should(Succeed())
+			fulfillSvcImport(svcImport)
+			Expect(hubClient.Status().Update(ctx, svcImport)).Should(Succeed())
+
+			endpointSliceExport = ipv4EndpointSliceExport()
+			endpointSliceExport.Finalizers = []string{}
+			Expect(hubClient.Create(ctx, endpointSliceExport)).Should(Succeed())
+
+			// Wait until the EndpointSlice has been distributed.
+			Eventually(endpointSliceImportCount, eventuallyTimeout, eventuallyInterval).Should(Equal(2))
+		})
+
+		AfterEach(func() {
+			Expect(hubClient.Delete(ctx, svcImport)).Should(Succeed())
+			// Confirm that ServiceImport is deleted; this helps make the test less flaky.
+			Eventually(func() error {
+				return client.IgnoreNotFound(hubClient.Get(ctx, svcImportKey, svcImport))
+			}, eventuallyTimeout, eventuallyInterval).Should(BeNil())
+		})
+
+		It("should withdraw all endpointsliceimports only after the grace period", func() {
+			By("Marking the endpointsliceexport as a deletion candidate, as when the member agent withdraws it")
+			setDeletionCandidateSince(time.Now())
+			Consistently(endpointSliceImportCount, consistentlyDuration, consistentlyInterval).Should(Equal(2))
+
+			By("Checking the endpointsliceexport is deleted and all endpointsliceimports are withdrawn")
+			Eventually(func() bool {
+				if count, err := endpointSliceImportCount(); err != nil || count != 0 {
+					return false
+				}
+				return errors.IsNotFound(hubClient.Get(ctx, endpointSliceExportKey, endpointSliceExport))
+			}, eventuallyTimeout, eventuallyInterval).Should(BeTrue())
+		})
+	})
 })
@@ -23,6 +23,8 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 
 	fleetnetv1alpha1 "go.goms.io/fleet-networking/api/v1alpha1"
+	"go.goms.io/fleet-networking/pkg/common/exportweight"
+	"go.goms.io/fleet-networking/pkg/common/objectmeta"
 )
 
 const (
@@ -437,3 +439,291 @@ func TestScanForEndpointSliceImports(t *testing.T) {
 		})
 	}
 }
+
+// TestIsOwnerServiceExported tests the Reconciler.isOwnerServiceExported method.
+func TestIsOwnerServiceExported(t *testing.T) {
+	internalSvcExport := func(namespace, svcNamespace, svcName string) *fleetnetv1alpha1.InternalServiceExport {
+		return &fleetnetv1alpha1.InternalServiceExport{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: namespace,
+				Name:      fmt.Sprintf("%s-%s", svcNamespace, svcName),
+			},
+			Spec: fleetnetv1alpha1.InternalServiceExportSpec{
+				ServiceReference: fleetnetv1alpha1.ExportedObjectReference{
+					ClusterID:      namespace,
+					Kind:           "Service",
+					Namespace:      svcNamespace,
+					Name:           svcName,
+					NamespacedName: fmt.Sprintf("%s/%s", svcNamespace, svcName),
+				},
+			},
+		}
+	}
+
+	labeled := func(internalSvcExport *fleetnetv1alpha1.InternalServiceExport, clusterID string) *fleetnetv1alpha1.InternalServiceExport {
+		internalSvcExport.Labels = map[string]string{objectmeta.ExportedObjectLabelMemberCluster: clusterID}
+		return internalSvcExport
+	}
+	shardNSForMemberA := hubNSForMemberA + "-shard-1"
+
+	testCases := []struct {
+		name                   string
+		memberClusterLabel     string
+		internalSvcExports     []*fleetnetv1alpha1.InternalServiceExport
+		wantIsOwnerSvcExported bool
+	}{
+		{
+			name: "should find the internalserviceexport of the owner service",
+			internalSvcExports: []*fleetnetv1alpha1.InternalServiceExport{
+				internalSvcExport(hubNSForMemberA, memberUserNS, "other-app"),
+				internalSvcExport(hubNSForMemberA, memberUserNS, svcName),
+			},
+			wantIsOwnerSvcExported: true,
+		},
+		{
+			name: "should not find the internalserviceexport of the owner service (no internalserviceexport)",
+		},
+		{
+			name: "should not find the internalserviceexport of the owner service (exported by another member cluster)",
+			internalSvcExports: []*fleetnetv1alpha1.InternalServiceExport{
+				internalSvcExport(hubNSForMemberB, memberUserNS, svcName),
+			},
+		},
+		{
+			name: "should not find the internalserviceexport of the owner service (another service)",
+			internalSvcExports: []*fleetnetv1alpha1.InternalServiceExport{
+				internalSvcExport(hubNSForMemberA, memberUserNS, "other-app"),
+			},
+		},
+		{
+			name:               "should find the internalserviceexport of the owner service (another shard namespace)",
+			memberClusterLabel: clusterIDForMemberA,
+			internalSvcExports: []*fleetnetv1alpha1.InternalServiceExport{
+				labeled(internalSvcExport(shardNSForMemberA, memberUserNS, svcName), clusterIDForMemberA),
+			},
+			wantIsOwnerSvcExported: true,
+		},
+		{
+			name:               "should not find the internalserviceexport of the owner service (labeled by another member cluster)",
+			memberClusterLabel: clusterIDForMemberA,
+			internalSvcExports: []*fleetnetv1alpha1.InternalServiceExport{
+				labeled(internalSvcExport(hubNSForMemberB, memberUserNS, svcName), clusterIDForMemberB),
+			},
+		},
+	}
+
+	ctx := context.Background()
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			fakeHubClientBuilder := fake.NewClientBuilder().WithScheme(scheme.Scheme)
+			for idx := range tc.internalSvcExports {
+				fakeHubClientBuilder = fakeHubClientBuilder.WithObjects(tc.internalSvcExports[idx])
+			}
+			reconciler := Reconciler{
+				HubClient: fakeHubClientBuilder.Build(),
+			}
+
+			endpointSliceExport := ipv4EndpointSliceExport()
+			if tc.memberClusterLabel != "" {
+				endpointSliceExport.Labels = map[string]string{objectmeta.ExportedObjectLabelMemberCluster: tc.memberClusterLabel}
+			}
+			isOwnerSvcExported, err := reconciler.isOwnerServiceExported(ctx, endpointSliceExport)
+			if err != nil {
+				t.Fatalf("isOwnerServiceExported(%+v), got %v, want no error", endpointSliceExport, err)
+			}
+			if isOwnerSvcExported != tc.wantIsOwnerSvcExported {
+				t.Fatalf("isOwnerServiceExported(%+v) = %t, want %t", endpointSliceExport, isOwnerSvcExported, tc.wantIsOwnerSvcExported)
+			}
+		})
+	}
+}
+
+// TestExportedSince tests the exportedSince function.
+func TestExportedSince(t *testing.T) {
+	createdAt := metav1.NewTime(time.Now().Add(-time.Hour).Round(time.Second))
+	exportedAt := metav1.NewTime(time.Now().Add(-time.Minute).Round(time.Second))
+
+	testCases := []struct {
+		name              string
+		exportedSince     metav1.Time
+		wantExportedSince time.Time
+	}{
+		{
+			name:              "should return the export time",
+			exportedSince:     exportedAt,
+			wantExportedSince: exportedAt.Time,
+		},
+		{
+			name:              "should return the creation time (no export time)",
+			wantExportedSince: createdAt.Time,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			endpointSliceExport := ipv4EndpointSliceExport()
+			endpointSliceExport.CreationTimestamp = createdAt
+			endpointSliceExport.Spec.EndpointSliceReference.ExportedSince = tc.exportedSince
+			if got := exportedSince(endpointSliceExport); !got.Equal(tc.wantExportedSince) {
+				t.Fatalf("exportedSince() = %v, want %v", got, tc.wantExportedSince)
+			}
+		})
+	}
+}
+
+// TestDeletionCandidateSince tests the deletionCandidateSince function.
+func TestDeletionCandidateSince(t *testing.T) {
+	markedAt := time.Now().Add(-time.Minute).Round(time.Second).UTC()
+
+	testCases := []struct {
+		name               string
+		annotations        map[string]string
+		wantCandidateSince time.Time
+		wantCandidate      bool
+	}{
+		{
+			name: "should return the time of the mark",
+			annotations: map[string]string{
+				objectmeta.EndpointSliceExportAnnotationDeletionCandidateSince: markedAt.Format(time.RFC3339),
+			},
+			wantCandidateSince: markedAt,
+			wantCandidate:      true,
+		},
+		{
+			name: "should not be a candidate (no mark)",
+		},
+		{
+			name: "should not be a candidate (malformed mark)",
+			annotations: map[string]string{
+				objectmeta.EndpointSliceExportAnnotationDeletionCandidateSince: "yesterday",
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			endpointSliceExport := ipv4EndpointSliceExport()
+			endpointSliceExport.Annotations = tc.annotations
+			got, ok := deletionCandidateSince(endpointSliceExport)
+			if ok != tc.wantCandidate || !got.Equal(tc.wantCandidateSince) {
+				t.Fatalf("deletionCandidateSince() = (%v, %t), want (%v, %t)", got, ok, tc.wantCandidateSince, tc.wantCandidate)
+			}
+		})
+	}
+}
+
+// TestHandleDeletionCandidate tests that an EndpointSliceExport marked as a deletion candidate is deleted only after
+// the grace period.
+func TestHandleDeletionCandidate(t *testing.T) {
+	const gracePeriod = time.Minute
+
+	testCases := []struct {
+		name           string
+		candidateSince time.Time
+		wantDeleted    bool
+	}{
+		{
+			name:           "should keep the endpoint slice export (within the grace period)",
+			candidateSince: time.Now().Add(-time.Second * 10),
+		},
+		{
+			name:           "should keep the endpoint slice export (marked with a clock ahead of the hub cluster)",
+			candidateSince: time.Now().Add(time.Hour),
+		},
+		{
+			name:           "should delete the endpoint slice export (grace period ended)",
+			candidateSince: time.Now().Add(-gracePeriod * 2),
+			wantDeleted:    true,
+		},
+	}
+
+	ctx := context.Background()
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			endpointSliceExport := ipv4EndpointSliceExport()
+			fakeHubClient := fake.NewClientBuilder().
+				WithScheme(scheme.Scheme).
+				WithObjects(endpointSliceExport).
+				Build()
+			reconciler := &Reconciler{
+				HubClient:                    fakeHubClient,
+				DeletionCandidateGracePeriod: gracePeriod,
+			}
+
+			res, err := reconciler.handleDeletionCandidate(ctx, endpointSliceExport, tc.candidateSince)
+			if err != nil {
+				t.Fatalf("handleDeletionCandidate(), got %v, want no error", err)
+			}
+
+			got := &fleetnetv1alpha1.EndpointSliceExport{}
+			if err := fakeHubClient.Get(ctx, endpointSliceExportKey, got); err != nil {
+				t.Fatalf("endpointSliceExport Get(%+v), got %v, want no error", endpointSliceExportKey, err)
+			}
+			if isDeleted := got.DeletionTimestamp != nil; isDeleted != tc.wantDeleted {
+				t.Fatalf("endpointSliceExport deleted, got %t, want %t", isDeleted, tc.wantDeleted)
+			}
+			if !tc.wantDeleted && (res.RequeueAfter <= 0 || res.RequeueAfter > gracePeriod) {
+				t.Errorf("handleDeletionCandidate() requeue after %v, want within the grace period %v", res.RequeueAfter, gracePeriod)
+			}
+		})
+	}
+}
+
+// TestSetEndpointWeight tests that the EndpointSliceImports are annotated with the weight of the exporting cluster,
+// as reported in the ServiceImport status, through its updates and removal.
+func TestSetEndpointWeight(t *testing.T) {
+	endpointSliceImport := &fleetnetv1alpha1.EndpointSliceImport{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace:   hubNSForMemberB,
+			Name:        endpointSliceExportName,
+			Annotations: map[string]string{"unrelated": "value"},
+		},
+	}
+	testCases := []struct {
+		name            string
+		weights         []fleetnetv1alpha1.ClusterWeight
+		wantAnnotations map[string]string
+	}{
+		{
+			name: "weight is set",
+			weights: []fleetnetv1alpha1.ClusterWeight{
+				{Cluster: hubNSForMemberB, Weight: 1},
+				{Cluster: hubNSForMemberA, Weight: 3},
+			},
+			wantAnnotations: map[string]string{
+				"unrelated": "value",
+				objectmeta.EndpointSliceAnnotationEndpointWeight: "3",
+			},
+		},
+		{
+			name:    "weight is updated",
+			weights: []fleetnetv1alpha1.ClusterWeight{{Cluster: hubNSForMemberA, Weight: 0}},
+			wantAnnotations: map[string]string{
+				"unrelated": "value",
+				objectmeta.EndpointSliceAnnotationEndpointWeight: "0",
+			},
+		},
+		{
+			name:            "weight is unset",
+			wantAnnotations: map[string]string{"unrelated": "value"},
+		},
+		{
+			name:            "cluster has no weight in the status",
+			weights:         []fleetnetv1alpha1.ClusterWeight{{Cluster: hubNSForMemberB, Weight: 2}},
+			wantAnnotations: map[string]string{"unrelated": "value"},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			status := &fleetnetv1alpha1.ServiceImportStatus{
+				Clusters:       []fleetnetv1alpha1.ClusterStatus{{Cluster: hubNSForMemberA}, {Cluster: hubNSForMemberB}},
+				ClusterWeights: tc.weights,
+			}
+			setEndpointWeight(endpointSliceImport, exportweight.Find(status, hubNSForMemberA))
+			if diff := cmp.Diff(tc.wantAnnotations, endpointSliceImport.Annotations); diff != "" {
+				t.Errorf("endpointSliceImport annotations mismatch (-want, +got):\n%s", diff)
+			}
+		})
+	}
+}
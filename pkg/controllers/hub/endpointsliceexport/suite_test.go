@@ -9,6 +9,7 @@ import (
 	"context"
 	"path/filepath"
 	"testing"
+	"time"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
@@ -106,6 +107,11 @@ var _ = BeforeSuite(func() {
 
 	err = (&Reconciler{
 		HubClient: hubClient,
+		// The EndpointSliceExports in the tests are exported just before they are created, and hence are not deleted
+		// as orphans unless their export time is set back by more than the grace period.
+		OrphanGracePeriod: time.Hour,
+		// The EndpointSliceExports marked as deletion candidates in the tests are deleted after a short grace period.
+		DeletionCandidateGracePeriod: deletionCandidateGracePeriod,
 	}).SetupWithManager(ctx, hubCtrlMgr)
 	Expect(err).NotTo(HaveOccurred())
 
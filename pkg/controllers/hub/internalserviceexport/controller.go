@@ -9,8 +9,10 @@ package internalserviceexport
 
 import (
 	"context"
+	"sync"
 	"time"
 
+	"github.com/prometheus/client_golang/prometheus"
 	"k8s.io/apimachinery/pkg/api/equality"
 	"k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/meta"
@@ -20,18 +22,54 @@ import (
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	ctrlmetrics "sigs.k8s.io/controller-runtime/pkg/metrics"
 
 	fleetnetv1alpha1 "go.goms.io/fleet-networking/api/v1alpha1"
 	"go.goms.io/fleet-networking/pkg/common/condition"
+	"go.goms.io/fleet-networking/pkg/common/consumerpolicy"
+	"go.goms.io/fleet-networking/pkg/common/exportconflict"
+	"go.goms.io/fleet-networking/pkg/common/exporthealth"
+	"go.goms.io/fleet-networking/pkg/common/exportweight"
+	"go.goms.io/fleet-networking/pkg/common/listguard"
+	"go.goms.io/fleet-networking/pkg/common/metrics"
 	"go.goms.io/fleet-networking/pkg/common/objectmeta"
+	"go.goms.io/fleet-networking/pkg/common/reconcilewatchdog"
 )
 
+var (
+	// internalServiceExportState reports the number of InternalServiceExports in each state, as marked by the
+	// InternalServiceExportLabelState label.
+	internalServiceExportState = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: metrics.MetricsNamespace,
+			Subsystem: metrics.MetricsSubsystem,
+			Name:      "internalserviceexport_state",
+			Help:      "The number of internalServiceExports in each state (valid, conflicted or invalid) across the fleet",
+		},
+		[]string{"state"},
+	)
+)
+
+func init() {
+	// Register internalServiceExportState (fleet_networking_internalserviceexport_state) metric
+	// with the controller runtime global metrics registry.
+	ctrlmetrics.Registry.MustRegister(internalServiceExportState)
+}
+
 // Reconciler reconciles a InternalServiceExport object.
 type Reconciler struct {
 	client.Client
 	// RetryInternal is the wait time for the controller to requeue the request and to wait for the
 	// ServiceImport controller to resolve the service Spec.
 	RetryInternal time.Duration
+
+	// states tracks the last recorded state of each InternalServiceExport, keyed by its namespaced name, so that the
+	// internalServiceExportState metric can be kept up to date as the exports change state or go away.
+	states sync.Map
+	// evaluated tracks the spec of each accepted InternalServiceExport, less the fields which take no part in the
+	// conflict resolution (see conflictSpec), as of the last evaluation of its conflicts, keyed by its namespaced name,
+	// so that the conflicts are not evaluated again when only the other fields, e.g. the weight, change.
+	evaluated sync.Map
 }
 
 //+kubebuilder:rbac:groups=networking.fleet.azure.com,resources=internalserviceexports,verbs=get;list;watch;create;update;patch;delete
@@ -60,6 +98,8 @@ func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Resu
 	if err := r.Client.Get(ctx, name, &internalServiceExport); err != nil {
 		if errors.IsNotFound(err) {
 			klog.V(4).InfoS("Ignoring NotFound internalServiceExport", "internalServiceExport", internalServiceExportKRef)
+			r.recordState(name, "")
+			r.evaluated.Delete(name)
 			return ctrl.Result{}, nil
 		}
 		klog.ErrorS(err, "Failed to get internalServiceExport", "internalServiceExport", internalServiceExportKRef)
@@ -67,6 +107,8 @@ func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Resu
 	}
 
 	if internalServiceExport.ObjectMeta.DeletionTimestamp != nil {
+		r.recordState(name, "")
+		r.evaluated.Delete(name)
 		return r.handleDelete(ctx, &internalServiceExport)
 	}
 
@@ -111,6 +153,16 @@ func (r *Reconciler) handleDelete(ctx context.Context, internalServiceExport *fl
 		return ctrl.Result{RequeueAfter: r.RetryInternal}, nil
 	}
 
+	exportedByOthers, err := r.isServiceExportedByOthersFromSameCluster(ctx, internalServiceExport, serviceImport)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	if exportedByOthers {
+		// The cluster still exports the service via other Services exported under the same name.
+		klog.V(2).InfoS("Keeping the cluster in the serviceImport as other services are exported under the same name", "serviceImport", serviceImportKRef, "internalServiceExport", internalServiceExportKObj)
+		return r.removeFinalizer(ctx, internalServiceExport)
+	}
+
 	oldStatus := serviceImport.Status.DeepCopy()
 	removeClusterFromServiceImportStatus(serviceImport, internalServiceExport.Spec.ServiceReference.ClusterID)
 	if err := r.updateServiceImportStatus(ctx, serviceImport, oldStatus); err != nil {
@@ -119,6 +171,47 @@ func (r *Reconciler) handleDelete(ctx context.Context, internalServiceExport *fl
 	return r.removeFinalizer(ctx, internalServiceExport)
 }
 
+// isServiceExportedByOthersFromSameCluster returns if there is another InternalServiceExport from the same cluster
+// which exports the Service under the same name and with the resolved spec of the serviceImport; this happens when
+// multiple Services in a member cluster are exported under the same name.
+func (r *Reconciler) isServiceExportedByOthersFromSameCluster(ctx context.Context, internalServiceExport *fleetnetv1alpha1.InternalServiceExport, serviceImport *fleetnetv1alpha1.ServiceImport) (bool, error) {
+	// InternalServiceExports from the same cluster are created in the namespace reserved for the cluster, or across
+	// its shard namespaces when the exports of the cluster are sharded, in which case they are listed by the member
+	// cluster label; the namespace is listed regardless, for the exports which have not been labeled yet.
+	listOptsList := [][]client.ListOption{{client.InNamespace(internalServiceExport.Namespace)}}
+	if clusterID, ok := internalServiceExport.Labels[objectmeta.ExportedObjectLabelMemberCluster]; ok {
+		listOptsList = append(listOptsList, []client.ListOption{client.MatchingLabels{objectmeta.ExportedObjectLabelMemberCluster: clusterID}})
+	}
+	for _, listOpts := range listOptsList {
+		internalServiceExportList := &fleetnetv1alpha1.InternalServiceExportList{}
+		if err := r.Client.List(ctx, internalServiceExportList, listOpts...); err != nil {
+			klog.ErrorS(err, "Failed to list internalServiceExports of the cluster", "clusterID", internalServiceExport.Spec.ServiceReference.ClusterID, "internalServiceExport", klog.KObj(internalServiceExport))
+			return false, err
+		}
+		listguard.ObserveListSize(internalServiceExportList, "internalServiceExport", klog.KObj(internalServiceExport))
+		for i := range internalServiceExportList.Items {
+			other := &internalServiceExportList.Items[i]
+			if (other.Namespace == internalServiceExport.Namespace && other.Name == internalServiceExport.Name) ||
+				other.DeletionTimestamp != nil ||
+				!controllerutil.ContainsFinalizer(other, objectmeta.InternalServiceExportFinalizer) ||
+				other.Spec.ServiceReference.ClusterID != internalServiceExport.Spec.ServiceReference.ClusterID ||
+				other.Spec.ServiceReference.NamespacedName != internalServiceExport.Spec.ServiceReference.NamespacedName {
+				continue
+			}
+			if isSpecResolved(serviceImport, other) {
+				return true, nil
+			}
+		}
+	}
+	return false, nil
+}
+
+// isSpecResolved returns if the spec of an InternalServiceExport matches the resolved spec of the serviceImport, i.e.
+// the ports and the IP families of the exported Service.
+func isSpecResolved(serviceImport *fleetnetv1alpha1.ServiceImport, internalServiceExport *fleetnetv1alpha1.InternalServiceExport) bool {
+	return exportconflict.IsSpecResolved(serviceImport.Status.Ports, serviceImport.Status.IPFamilies, &internalServiceExport.Spec)
+}
+
 func removeClusterFromServiceImportStatus(serviceImport *fleetnetv1alpha1.ServiceImport, clusterID string) {
 	var updatedClusters []fleetnetv1alpha1.ClusterStatus
 	for _, c := range serviceImport.Status.Clusters {
@@ -130,16 +223,29 @@ func removeClusterFromServiceImportStatus(serviceImport *fleetnetv1alpha1.Servic
 		serviceImport.Status = fleetnetv1alpha1.ServiceImportStatus{}
 	} else {
 		serviceImport.Status.Clusters = updatedClusters
+		consumerpolicy.Set(&serviceImport.Status, clusterID, nil)
+		exportweight.Set(&serviceImport.Status, clusterID, nil)
+		exporthealth.Set(&serviceImport.Status, clusterID, nil)
 	}
 }
 
-func addClusterToServiceImportStatus(serviceImport *fleetnetv1alpha1.ServiceImport, clusterID string) {
-	for _, c := range serviceImport.Status.Clusters {
-		if c.Cluster == clusterID {
+func addClusterToServiceImportStatus(serviceImport *fleetnetv1alpha1.ServiceImport, internalServiceExport *fleetnetv1alpha1.InternalServiceExport) {
+	clusterStatus := fleetnetv1alpha1.ClusterStatus{
+		Cluster: internalServiceExport.Spec.ServiceReference.ClusterID,
+		Region:  internalServiceExport.Spec.ClusterRegion,
+		VNetID:  internalServiceExport.Spec.ClusterVNetID,
+	}
+	consumerpolicy.Set(&serviceImport.Status, clusterStatus.Cluster, internalServiceExport.Spec.ConsumerPolicy)
+	exportweight.Set(&serviceImport.Status, clusterStatus.Cluster, internalServiceExport.Spec.Weight)
+	exporthealth.Set(&serviceImport.Status, clusterStatus.Cluster, internalServiceExport.Spec.Health)
+	for i := range serviceImport.Status.Clusters {
+		if serviceImport.Status.Clusters[i].Cluster == clusterStatus.Cluster {
+			// Keep the network properties of the exporting cluster up to date.
+			serviceImport.Status.Clusters[i] = clusterStatus
 			return
 		}
 	}
-	serviceImport.Status.Clusters = append(serviceImport.Status.Clusters, fleetnetv1alpha1.ClusterStatus{Cluster: clusterID})
+	serviceImport.Status.Clusters = append(serviceImport.Status.Clusters, clusterStatus)
 }
 
 func (r *Reconciler) updateServiceImportStatus(ctx context.Context, serviceImport *fleetnetv1alpha1.ServiceImport, oldStatus *fleetnetv1alpha1.ServiceImportStatus) error {
@@ -171,6 +277,10 @@ func (r *Reconciler) updateInternalServiceExportStatus(ctx context.Context, inte
 	if conflict {
 		desiredCond = condition.ConflictedServiceExportConflictCondition(*internalServiceExport)
 	}
+	// The state label is updated along with the conditions, so that the two never drift apart.
+	if err := r.updateStateLabel(ctx, internalServiceExport, exportState(internalServiceExport, conflict)); err != nil {
+		return err
+	}
 	currentCond := meta.FindStatusCondition(internalServiceExport.Status.Conditions, string(fleetnetv1alpha1.ServiceExportConflict))
 	if condition.EqualCondition(currentCond, &desiredCond) {
 		return nil
@@ -187,6 +297,58 @@ func (r *Reconciler) updateInternalServiceExportStatus(ctx context.Context, inte
 	return nil
 }
 
+// exportState returns the value of the state label of an InternalServiceExport.
+func exportState(internalServiceExport *fleetnetv1alpha1.InternalServiceExport, conflict bool) string {
+	switch {
+	case len(internalServiceExport.Spec.Ports) == 0:
+		return objectmeta.InternalServiceExportStateInvalid
+	case conflict:
+		return objectmeta.InternalServiceExportStateConflicted
+	default:
+		return objectmeta.InternalServiceExportStateValid
+	}
+}
+
+// updateStateLabel sets the state label of an InternalServiceExport and records its state in the metric.
+func (r *Reconciler) updateStateLabel(ctx context.Context, internalServiceExport *fleetnetv1alpha1.InternalServiceExport, state string) error {
+	if internalServiceExport.Labels[objectmeta.InternalServiceExportLabelState] != state {
+		exportKObj := klog.KObj(internalServiceExport)
+		oldState := internalServiceExport.Labels[objectmeta.InternalServiceExportLabelState]
+		if internalServiceExport.Labels == nil {
+			internalServiceExport.Labels = map[string]string{}
+		}
+		internalServiceExport.Labels[objectmeta.InternalServiceExportLabelState] = state
+		klog.V(2).InfoS("Updating internalServiceExport state label", "internalServiceExport", exportKObj, "state", state, "oldState", oldState)
+		if err := r.Client.Update(ctx, internalServiceExport); err != nil {
+			klog.ErrorS(err, "Failed to update internalServiceExport state label", "internalServiceExport", exportKObj, "state", state, "oldState", oldState)
+			return err
+		}
+	}
+	r.recordState(types.NamespacedName{Namespace: internalServiceExport.Namespace, Name: internalServiceExport.Name}, state)
+	return nil
+}
+
+// recordState tracks the state of an InternalServiceExport in the internalServiceExportState metric; an empty state
+// stops tracking the InternalServiceExport, e.g., when it is deleted.
+func (r *Reconciler) recordState(name types.NamespacedName, state string) {
+	var old any
+	var loaded bool
+	if state == "" {
+		old, loaded = r.states.LoadAndDelete(name)
+	} else {
+		old, loaded = r.states.Swap(name, state)
+	}
+	if loaded && old == state {
+		return
+	}
+	if loaded {
+		internalServiceExportState.WithLabelValues(old.(string)).Dec()
+	}
+	if state != "" {
+		internalServiceExportState.WithLabelValues(state).Inc()
+	}
+}
+
 func (r *Reconciler) handleUpdate(ctx context.Context, internalServiceExport *fleetnetv1alpha1.InternalServiceExport) (ctrl.Result, error) {
 	internalServiceExportKObj := klog.KObj(internalServiceExport)
 	// get serviceImport
@@ -220,10 +382,27 @@ func (r *Reconciler) handleUpdate(ctx context.Context, internalServiceExport *fl
 
 	oldStatus := serviceImport.Status.DeepCopy()
 	clusterID := internalServiceExport.Spec.ServiceReference.ClusterID
+	name := types.NamespacedName{Namespace: internalServiceExport.Namespace, Name: internalServiceExport.Name}
+
+	if isSpecResolved(serviceImport, internalServiceExport) && r.isEvaluated(name, internalServiceExport) {
+		// Only the fields which feed the cluster status in the serviceImport have changed since the export was
+		// accepted, e.g. the weight; the status is kept up to date without evaluating the conflicts again.
+		klog.V(3).InfoS("Conflicts of the internalServiceExport have been evaluated; update the cluster status only", "serviceImport", serviceImportKRef, "internalServiceExport", internalServiceExportKObj)
+		addClusterToServiceImportStatus(serviceImport, internalServiceExport)
+		return ctrl.Result{}, r.updateServiceImportStatus(ctx, serviceImport, oldStatus)
+	}
+	r.evaluated.Delete(name)
 
-	// To simplify the implementation, we compare the whole ports structure.
-	// TODO, change to compare the ports by ignoring the order and protocol and port are the map keys.
-	if !equality.Semantic.DeepEqual(serviceImport.Status.Ports, internalServiceExport.Spec.Ports) {
+	if !isSpecResolved(serviceImport, internalServiceExport) {
+		exportedByOthers, err := r.isServiceExportedByOthersFromSameCluster(ctx, internalServiceExport, serviceImport)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+		if exportedByOthers {
+			// Other Services exported under the same name from the same cluster have been accepted; only the
+			// current one is in conflict.
+			return ctrl.Result{}, r.updateInternalServiceExportStatus(ctx, internalServiceExport, true)
+		}
 		removeClusterFromServiceImportStatus(serviceImport, clusterID)
 		if err := r.updateServiceImportStatus(ctx, serviceImport, oldStatus); err != nil {
 			return ctrl.Result{}, err
@@ -238,17 +417,53 @@ func (r *Reconciler) handleUpdate(ctx context.Context, internalServiceExport *fl
 		return ctrl.Result{}, r.updateInternalServiceExportStatus(ctx, internalServiceExport, true)
 	}
 
-	addClusterToServiceImportStatus(serviceImport, clusterID)
+	addClusterToServiceImportStatus(serviceImport, internalServiceExport)
 	if err := r.updateServiceImportStatus(ctx, serviceImport, oldStatus); err != nil {
 		return ctrl.Result{}, err
 	}
 
-	return ctrl.Result{}, r.updateInternalServiceExportStatus(ctx, internalServiceExport, false)
+	if err := r.updateInternalServiceExportStatus(ctx, internalServiceExport, false); err != nil {
+		return ctrl.Result{}, err
+	}
+	r.evaluated.Store(name, conflictSpec(&internalServiceExport.Spec))
+	return ctrl.Result{}, nil
+}
+
+// isEvaluated returns if the conflicts of an InternalServiceExport have been evaluated, and the export accepted, with
+// the same spec but for the fields which take no part in the conflict resolution. The state label must still mark the
+// export as valid, e.g. in case it has been re-created under the same name.
+func (r *Reconciler) isEvaluated(name types.NamespacedName, internalServiceExport *fleetnetv1alpha1.InternalServiceExport) bool {
+	if internalServiceExport.Labels[objectmeta.InternalServiceExportLabelState] != exportState(internalServiceExport, false) {
+		return false
+	}
+	evaluated, ok := r.evaluated.Load(name)
+	if !ok {
+		return false
+	}
+	return equality.Semantic.DeepEqual(evaluated, conflictSpec(&internalServiceExport.Spec))
+}
+
+// conflictSpec returns a copy of the spec of an InternalServiceExport without the fields which only feed the cluster
+// status in the serviceImport, i.e. the weight, the health and the Azure Traffic Manager related information; a
+// change in any other field has the conflicts of the export evaluated again.
+func conflictSpec(spec *fleetnetv1alpha1.InternalServiceExportSpec) *fleetnetv1alpha1.InternalServiceExportSpec {
+	s := spec.DeepCopy()
+	s.Weight = nil
+	s.Health = nil
+	s.Type = ""
+	s.IsDNSLabelConfigured = false
+	s.IsInternalLoadBalancer = false
+	s.IsLoadBalancerPending = false
+	s.IsPublicIPExplicit = false
+	s.PublicIPResourceID = nil
+	s.ExternalTrafficPolicy = ""
+	s.HealthCheckNodePort = 0
+	return s
 }
 
 // SetupWithManager sets up the controller with the Manager.
 func (r *Reconciler) SetupWithManager(mgr ctrl.Manager) error {
 	return ctrl.NewControllerManagedBy(mgr).
 		For(&fleetnetv1alpha1.InternalServiceExport{}).
-		Complete(r)
+		Complete(reconcilewatchdog.Wrap("internalserviceexport", r))
 }
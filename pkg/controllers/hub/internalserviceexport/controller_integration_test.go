@@ -324,6 +324,7 @@ var _ = Describe("Test InternalServiceExport Controller", func() {
 				}
 				return cmp.Diff(want, internalServiceExportA.Status, options...)
 			}, timeout, interval).Should(BeEmpty())
+			Expect(internalServiceExportA.Labels[objectmeta.InternalServiceExportLabelState]).Should(Equal(objectmeta.InternalServiceExportStateValid))
 
 			By("Deleting internalServiceExportA")
 			Expect(k8sClient.Delete(ctx, internalServiceExportA)).Should(Succeed())
@@ -397,6 +398,16 @@ var _ = Describe("Test InternalServiceExport Controller", func() {
 				}
 				return cmp.Diff(want, internalServiceExportA.Status, options...)
 			}, timeout, interval).Should(BeEmpty())
+			Expect(internalServiceExportA.Labels[objectmeta.InternalServiceExportLabelState]).Should(Equal(objectmeta.InternalServiceExportStateConflicted))
+
+			By("Listing the conflicted internalServiceExports across the member cluster namespaces")
+			conflicted := &fleetnetv1alpha1.InternalServiceExportList{}
+			Expect(k8sClient.List(ctx, conflicted, client.MatchingLabels{objectmeta.InternalServiceExportLabelState: objectmeta.InternalServiceExportStateConflicted})).Should(Succeed())
+			var conflictedKeys []types.NamespacedName
+			for _, item := range conflicted.Items {
+				conflictedKeys = append(conflictedKeys, types.NamespacedName{Namespace: item.Namespace, Name: item.Name})
+			}
+			Expect(conflictedKeys).Should(ContainElement(types.NamespacedName{Namespace: testMemberClusterA, Name: testName}))
 
 			By("Checking serviceImport status")
 			Consistently(func() string {
@@ -406,6 +417,25 @@ var _ = Describe("Test InternalServiceExport Controller", func() {
 				return cmp.Diff(serviceImportStatus, &serviceImport.Status, options...)
 			}, duration, interval).Should(BeEmpty())
 
+			By("Resolving the conflict of internalServiceExportA")
+			Eventually(func() error {
+				key := types.NamespacedName{Namespace: testMemberClusterA, Name: testName}
+				if err := k8sClient.Get(ctx, key, internalServiceExportA); err != nil {
+					return err
+				}
+				internalServiceExportA.Spec.Ports = serviceImportStatus.Ports
+				return k8sClient.Update(ctx, internalServiceExportA)
+			}, timeout, interval).Should(Succeed())
+
+			By("Checking internalServiceExportA state label")
+			Eventually(func() string {
+				key := types.NamespacedName{Namespace: testMemberClusterA, Name: testName}
+				if err := k8sClient.Get(ctx, key, internalServiceExportA); err != nil {
+					return err.Error()
+				}
+				return internalServiceExportA.Labels[objectmeta.InternalServiceExportLabelState]
+			}, timeout, interval).Should(Equal(objectmeta.InternalServiceExportStateValid))
+
 			By("Deleting internalServiceExportA")
 			Expect(k8sClient.Delete(ctx, internalServiceExportA)).Should(Succeed())
 
@@ -491,4 +521,162 @@ var _ = Describe("Test InternalServiceExport Controller", func() {
 			}, timeout, interval).Should(BeTrue())
 		})
 	})
+
+	Context("Exporting multiple services under the same name from one cluster", func() {
+		var serviceImport fleetnetv1alpha1.ServiceImport
+		var internalServiceExportA *fleetnetv1alpha1.InternalServiceExport
+		var aliasedInternalServiceExport *fleetnetv1alpha1.InternalServiceExport
+		aliasedName := testName + "-v2"
+
+		BeforeEach(func() {
+			By("Creating serviceImport")
+			serviceImport = fleetnetv1alpha1.ServiceImport{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      testServiceName,
+					Namespace: testNamespace,
+				},
+			}
+			Expect(k8sClient.Create(ctx, &serviceImport)).Should(Succeed())
+
+			By("Updating serviceImport status")
+			serviceImport.Status = fleetnetv1alpha1.ServiceImportStatus{
+				Ports: importServicePorts,
+				Clusters: []fleetnetv1alpha1.ClusterStatus{
+					{
+						Cluster: testClusterID,
+					},
+				},
+				Type: fleetnetv1alpha1.ClusterSetIP,
+			}
+			Expect(k8sClient.Status().Update(ctx, &serviceImport)).Should(Succeed())
+
+			internalServiceExportA = &fleetnetv1alpha1.InternalServiceExport{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      testName,
+					Namespace: testMemberClusterA,
+				},
+				Spec: internalServiceExportSpec,
+			}
+			aliasedInternalServiceExport = &fleetnetv1alpha1.InternalServiceExport{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      aliasedName,
+					Namespace: testMemberClusterA,
+				},
+				Spec: *internalServiceExportSpec.DeepCopy(),
+			}
+			aliasedInternalServiceExport.Spec.LocalServiceName = testServiceName + "-v2"
+		})
+
+		AfterEach(func() {
+			By("Deleting internalServiceExports if exist")
+			Expect(client.IgnoreNotFound(k8sClient.Delete(ctx, internalServiceExportA))).Should(Succeed())
+			Expect(client.IgnoreNotFound(k8sClient.Delete(ctx, aliasedInternalServiceExport))).Should(Succeed())
+
+			By("Deleting serviceImport if exists")
+			Expect(client.IgnoreNotFound(k8sClient.Delete(ctx, &serviceImport))).Should(Succeed())
+		})
+
+		It("Aliased services with the same spec should be aggregated", func() {
+			By("Creating internalServiceExports")
+			Expect(k8sClient.Create(ctx, internalServiceExportA)).Should(Succeed())
+			Expect(k8sClient.Create(ctx, aliasedInternalServiceExport)).Should(Succeed())
+
+			By("Checking internalServiceExport status")
+			for _, name := range []string{testName, aliasedName} {
+				Eventually(func() string {
+					want := fleetnetv1alpha1.InternalServiceExportStatus{
+						Conditions: []metav1.Condition{
+							unconflictedServiceExportConflictCondition(testNamespace, testServiceName),
+						},
+					}
+					key := types.NamespacedName{Namespace: testMemberClusterA, Name: name}
+					internalServiceExport := fleetnetv1alpha1.InternalServiceExport{}
+					if err := k8sClient.Get(ctx, key, &internalServiceExport); err != nil {
+						return err.Error()
+					}
+					return cmp.Diff(want, internalServiceExport.Status, options...)
+				}, timeout, interval).Should(BeEmpty())
+			}
+
+			By("Deleting internalServiceExportA")
+			Expect(k8sClient.Delete(ctx, internalServiceExportA)).Should(Succeed())
+
+			By("Checking internalServiceExportA")
+			Eventually(func() bool {
+				key := types.NamespacedName{Namespace: testMemberClusterA, Name: testName}
+				return errors.IsNotFound(k8sClient.Get(ctx, key, internalServiceExportA))
+			}, timeout, interval).Should(BeTrue())
+
+			By("Checking serviceImport status")
+			Consistently(func() string {
+				want := fleetnetv1alpha1.ServiceImportStatus{
+					Ports: importServicePorts,
+					Clusters: []fleetnetv1alpha1.ClusterStatus{
+						{
+							Cluster: testClusterID,
+						},
+					},
+					Type: fleetnetv1alpha1.ClusterSetIP,
+				}
+				if err := k8sClient.Get(ctx, serviceImportKey, &serviceImport); err != nil {
+					return err.Error()
+				}
+				return cmp.Diff(want, serviceImport.Status, options...)
+			}, duration, interval).Should(BeEmpty())
+		})
+
+		It("Aliased service with a different spec should be marked as conflicted", func() {
+			By("Creating internalServiceExportA")
+			Expect(k8sClient.Create(ctx, internalServiceExportA)).Should(Succeed())
+
+			By("Checking internalServiceExportA status")
+			Eventually(func() string {
+				want := fleetnetv1alpha1.InternalServiceExportStatus{
+					Conditions: []metav1.Condition{
+						unconflictedServiceExportConflictCondition(testNamespace, testServiceName),
+					},
+				}
+				key := types.NamespacedName{Namespace: testMemberClusterA, Name: testName}
+				if err := k8sClient.Get(ctx, key, internalServiceExportA); err != nil {
+					return err.Error()
+				}
+				return cmp.Diff(want, internalServiceExportA.Status, options...)
+			}, timeout, interval).Should(BeEmpty())
+
+			By("Creating the aliased internalServiceExport with different ports")
+			aliasedInternalServiceExport.Spec.Ports = importServicePorts[:1]
+			Expect(k8sClient.Create(ctx, aliasedInternalServiceExport)).Should(Succeed())
+
+			By("Checking the aliased internalServiceExport status")
+			Eventually(func() string {
+				want := fleetnetv1alpha1.InternalServiceExportStatus{
+					Conditions: []metav1.Condition{
+						conflictedServiceExportConflictCondition(testNamespace, testServiceName),
+					},
+				}
+				key := types.NamespacedName{Namespace: testMemberClusterA, Name: aliasedName}
+				if err := k8sClient.Get(ctx, key, aliasedInternalServiceExport); err != nil {
+					return err.Error()
+				}
+				return cmp.Diff(want, aliasedInternalServiceExport.Status, options...)
+			}, timeout, interval).Should(BeEmpty())
+
+			By("Checking serviceImport status")
+			Consistently(func() string {
+				want := fleetnetv1alpha1.ServiceImportStatus{
+					Ports: importServicePorts,
+					Clusters: []fleetnetv1alpha1.ClusterStatus{
+						{
+							Cluster: testClusterID,
+						},
+					},
+					Type: fleetnetv1alpha1.ClusterSetIP,
+				}
+				if err := k8sClient.Get(ctx, serviceImportKey, &serviceImport); err != nil {
+					return err.Error()
+				}
+				return cmp.Diff(want, serviceImport.Status, options...)
+			}, duration, interval).Should(BeEmpty())
+		})
+	})
 })
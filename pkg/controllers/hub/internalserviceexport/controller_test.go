@@ -12,17 +12,21 @@ import (
 
 	"github.com/google/go-cmp/cmp"
 	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/prometheus/client_golang/prometheus/testutil"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/utils/ptr"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
 
 	fleetnetv1alpha1 "go.goms.io/fleet-networking/api/v1alpha1"
+	"go.goms.io/fleet-networking/pkg/common/exportweight"
 	"go.goms.io/fleet-networking/pkg/common/objectmeta"
 )
 
@@ -152,14 +156,93 @@ func TestHandleDelete(t *testing.T) {
 			TargetPort: intstr.IntOrString{IntVal: 9090},
 		},
 	}
+	aliasedInternalSvcExport := internalServiceExportForTest()
+	aliasedInternalSvcExport.Name = "my-ns-my-svc-v2"
+	aliasedInternalSvcExport.Finalizers = []string{objectmeta.InternalServiceExportFinalizer}
+	aliasedInternalSvcExport.Spec.LocalServiceName = "my-svc-v2"
+	memberClusterLabels := map[string]string{objectmeta.ExportedObjectLabelMemberCluster: testClusterID}
+	shardedInternalSvcExport := aliasedInternalSvcExport.DeepCopy()
+	shardedInternalSvcExport.Namespace = testMemberNamespace + "-shard-1"
+	shardedInternalSvcExport.Labels = memberClusterLabels
 	tests := []struct {
-		name              string
-		serviceImport     *fleetnetv1alpha1.ServiceImport
-		wantServiceImport *fleetnetv1alpha1.ServiceImport
+		name                        string
+		labels                      map[string]string
+		serviceImport               *fleetnetv1alpha1.ServiceImport
+		otherInternalServiceExports []client.Object
+		wantServiceImport           *fleetnetv1alpha1.ServiceImport
 	}{
 		{
 			name: "serviceImport has been deleted",
 		},
+		{
+			name: "another service from the same cluster is exported under the same name",
+			serviceImport: &fleetnetv1alpha1.ServiceImport{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      testServiceName,
+					Namespace: testNamespace,
+				},
+				Status: fleetnetv1alpha1.ServiceImportStatus{
+					Ports: importServicePorts,
+					Clusters: []fleetnetv1alpha1.ClusterStatus{
+						{
+							Cluster: testClusterID,
+						},
+					},
+					Type: fleetnetv1alpha1.ClusterSetIP,
+				},
+			},
+			otherInternalServiceExports: []client.Object{aliasedInternalSvcExport},
+			wantServiceImport: &fleetnetv1alpha1.ServiceImport{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      testServiceName,
+					Namespace: testNamespace,
+				},
+				Status: fleetnetv1alpha1.ServiceImportStatus{
+					Ports: importServicePorts,
+					Clusters: []fleetnetv1alpha1.ClusterStatus{
+						{
+							Cluster: testClusterID,
+						},
+					},
+					Type: fleetnetv1alpha1.ClusterSetIP,
+				},
+			},
+		},
+		{
+			name:   "another service from the same cluster is exported under the same name in another shard namespace",
+			labels: memberClusterLabels,
+			serviceImport: &fleetnetv1alpha1.ServiceImport{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      testServiceName,
+					Namespace: testNamespace,
+				},
+				Status: fleetnetv1alpha1.ServiceImportStatus{
+					Ports: importServicePorts,
+					Clusters: []fleetnetv1alpha1.ClusterStatus{
+						{
+							Cluster: testClusterID,
+						},
+					},
+					Type: fleetnetv1alpha1.ClusterSetIP,
+				},
+			},
+			otherInternalServiceExports: []client.Object{shardedInternalSvcExport},
+			wantServiceImport: &fleetnetv1alpha1.ServiceImport{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      testServiceName,
+					Namespace: testNamespace,
+				},
+				Status: fleetnetv1alpha1.ServiceImportStatus{
+					Ports: importServicePorts,
+					Clusters: []fleetnetv1alpha1.ClusterStatus{
+						{
+							Cluster: testClusterID,
+						},
+					},
+					Type: fleetnetv1alpha1.ClusterSetIP,
+				},
+			},
+		},
 		{
 			name: "the deleting internalServiceExport is the last exported service",
 			serviceImport: &fleetnetv1alpha1.ServiceImport{
@@ -275,12 +358,14 @@ func TestHandleDelete(t *testing.T) {
 
 			internalSvcExportObj := internalServiceExportForTest()
 			internalSvcExportObj.Finalizers = []string{objectmeta.InternalServiceExportFinalizer}
+			internalSvcExportObj.Labels = tc.labels
 			now := metav1.Now()
 			internalSvcExportObj.DeletionTimestamp = &now
 			objects := []client.Object{internalSvcExportObj}
 			if tc.serviceImport != nil {
 				objects = append(objects, tc.serviceImport)
 			}
+			objects = append(objects, tc.otherInternalServiceExports...)
 			fakeClient := fake.NewClientBuilder().
 				WithScheme(internalServiceExportScheme(t)).
 				WithObjects(objects...).
@@ -385,6 +470,22 @@ func TestHandleUpdate(t *testing.T) {
 			TargetPort: intstr.IntOrString{IntVal: 9090},
 		},
 	}
+	// The same ports as importServicePorts except for the protocol.
+	udpServicePorts := []fleetnetv1alpha1.ServicePort{
+		{
+			Name:        "portA",
+			Protocol:    corev1.ProtocolUDP,
+			Port:        8080,
+			AppProtocol: &appProtocol,
+			TargetPort:  intstr.IntOrString{IntVal: 8080},
+		},
+		{
+			Name:       "portB",
+			Protocol:   corev1.ProtocolUDP,
+			Port:       9090,
+			TargetPort: intstr.IntOrString{IntVal: 9090},
+		},
+	}
 	tests := []struct {
 		name                  string
 		internalSvcExport     *fleetnetv1alpha1.InternalServiceExport
@@ -507,6 +608,7 @@ func TestHandleUpdate(t *testing.T) {
 				ObjectMeta: metav1.ObjectMeta{
 					Name:      testName,
 					Namespace: testMemberNamespace,
+					Labels:    map[string]string{objectmeta.InternalServiceExportLabelState: objectmeta.InternalServiceExportStateValid},
 				},
 				Spec: fleetnetv1alpha1.InternalServiceExportSpec{
 					Ports: importServicePorts,
@@ -593,6 +695,7 @@ func TestHandleUpdate(t *testing.T) {
 				ObjectMeta: metav1.ObjectMeta{
 					Name:      testName,
 					Namespace: testMemberNamespace,
+					Labels:    map[string]string{objectmeta.InternalServiceExportLabelState: objectmeta.InternalServiceExportStateConflicted},
 				},
 				Spec: fleetnetv1alpha1.InternalServiceExportSpec{
 					Ports: []fleetnetv1alpha1.ServicePort{
@@ -636,6 +739,82 @@ func TestHandleUpdate(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "serviceExport just created and has a different protocol from serviceImport",
+			internalSvcExport: &fleetnetv1alpha1.InternalServiceExport{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      testName,
+					Namespace: testMemberNamespace,
+				},
+				Spec: fleetnetv1alpha1.InternalServiceExportSpec{
+					Ports: udpServicePorts,
+					ServiceReference: fleetnetv1alpha1.ExportedObjectReference{
+						ClusterID:       testClusterID,
+						Kind:            "Service",
+						Namespace:       testNamespace,
+						Name:            testServiceName,
+						ResourceVersion: "0",
+						Generation:      0,
+						UID:             "0",
+					},
+				},
+			},
+			serviceImport: &fleetnetv1alpha1.ServiceImport{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      testServiceName,
+					Namespace: testNamespace,
+				},
+				Status: fleetnetv1alpha1.ServiceImportStatus{
+					Ports: importServicePorts,
+					Clusters: []fleetnetv1alpha1.ClusterStatus{
+						{
+							Cluster: "member-2",
+						},
+					},
+					Type: fleetnetv1alpha1.ClusterSetIP,
+				},
+			},
+			want: ctrl.Result{},
+			wantInternalSvcExport: &fleetnetv1alpha1.InternalServiceExport{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      testName,
+					Namespace: testMemberNamespace,
+					Labels:    map[string]string{objectmeta.InternalServiceExportLabelState: objectmeta.InternalServiceExportStateConflicted},
+				},
+				Spec: fleetnetv1alpha1.InternalServiceExportSpec{
+					Ports: udpServicePorts,
+					ServiceReference: fleetnetv1alpha1.ExportedObjectReference{
+						ClusterID:       testClusterID,
+						Kind:            "Service",
+						Namespace:       testNamespace,
+						Name:            testServiceName,
+						ResourceVersion: "0",
+						Generation:      0,
+						UID:             "0",
+					},
+				},
+				Status: fleetnetv1alpha1.InternalServiceExportStatus{
+					Conditions: []metav1.Condition{
+						conflictedServiceExportConflictCondition(testNamespace, testServiceName),
+					},
+				},
+			},
+			wantServiceImport: &fleetnetv1alpha1.ServiceImport{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      testServiceName,
+					Namespace: testNamespace,
+				},
+				Status: fleetnetv1alpha1.ServiceImportStatus{
+					Ports: importServicePorts,
+					Clusters: []fleetnetv1alpha1.ClusterStatus{
+						{
+							Cluster: "member-2",
+						},
+					},
+					Type: fleetnetv1alpha1.ClusterSetIP,
+				},
+			},
+		},
 		{
 			name: "update serviceExport and old serviceExport has the same spec as serviceImport",
 			internalSvcExport: &fleetnetv1alpha1.InternalServiceExport{
@@ -692,6 +871,7 @@ func TestHandleUpdate(t *testing.T) {
 				ObjectMeta: metav1.ObjectMeta{
 					Name:      testName,
 					Namespace: testMemberNamespace,
+					Labels:    map[string]string{objectmeta.InternalServiceExportLabelState: objectmeta.InternalServiceExportStateConflicted},
 				},
 				Spec: fleetnetv1alpha1.InternalServiceExportSpec{
 					Ports: []fleetnetv1alpha1.ServicePort{
@@ -780,6 +960,7 @@ func TestHandleUpdate(t *testing.T) {
 				ObjectMeta: metav1.ObjectMeta{
 					Name:      testName,
 					Namespace: testMemberNamespace,
+					Labels:    map[string]string{objectmeta.InternalServiceExportLabelState: objectmeta.InternalServiceExportStateValid},
 				},
 				Spec: fleetnetv1alpha1.InternalServiceExportSpec{
 					Ports: importServicePorts,
@@ -956,3 +1137,436 @@ func TestHandleUpdate(t *testing.T) {
 		})
 	}
 }
+
+func TestAddClusterToServiceImportStatus(t *testing.T) {
+	internalSvcExport := &fleetnetv1alpha1.InternalServiceExport{
+		Spec: fleetnetv1alpha1.InternalServiceExportSpec{
+			ServiceReference: fleetnetv1alpha1.ExportedObjectReference{
+				ClusterID: testClusterID,
+			},
+			ClusterRegion: "westus",
+			ClusterVNetID: "vnet-id",
+			Weight:        ptr.To(int64(3)),
+		},
+	}
+	unhealthy := fleetnetv1alpha1.ExportHealth{State: fleetnetv1alpha1.ExportUnhealthy, Message: "503 Service Unavailable"}
+	testCases := []struct {
+		name              string
+		clusters          []fleetnetv1alpha1.ClusterStatus
+		clusterHealth     []fleetnetv1alpha1.ClusterExportHealth
+		health            *fleetnetv1alpha1.ExportHealth
+		wantClusters      []fleetnetv1alpha1.ClusterStatus
+		wantClusterHealth []fleetnetv1alpha1.ClusterExportHealth
+	}{
+		{
+			name:     "new cluster",
+			clusters: []fleetnetv1alpha1.ClusterStatus{{Cluster: "member-2"}},
+			wantClusters: []fleetnetv1alpha1.ClusterStatus{
+				{Cluster: "member-2"},
+				{Cluster: testClusterID, Region: "westus", VNetID: "vnet-id"},
+			},
+		},
+		{
+			name: "existing cluster with outdated network properties",
+			clusters: []fleetnetv1alpha1.ClusterStatus{
+				{Cluster: testClusterID, Region: "eastus"},
+				{Cluster: "member-2"},
+			},
+			wantClusters: []fleetnetv1alpha1.ClusterStatus{
+				{Cluster: testClusterID, Region: "westus", VNetID: "vnet-id"},
+				{Cluster: "member-2"},
+			},
+		},
+		{
+			name:     "existing cluster reporting the health of the service",
+			clusters: []fleetnetv1alpha1.ClusterStatus{{Cluster: testClusterID, Region: "westus", VNetID: "vnet-id"}},
+			health:   &unhealthy,
+			wantClusters: []fleetnetv1alpha1.ClusterStatus{
+				{Cluster: testClusterID, Region: "westus", VNetID: "vnet-id"},
+			},
+			wantClusterHealth: []fleetnetv1alpha1.ClusterExportHealth{{Cluster: testClusterID, Health: unhealthy}},
+		},
+		{
+			name:          "existing cluster no longer reporting the health of the service",
+			clusters:      []fleetnetv1alpha1.ClusterStatus{{Cluster: testClusterID, Region: "westus", VNetID: "vnet-id"}},
+			clusterHealth: []fleetnetv1alpha1.ClusterExportHealth{{Cluster: testClusterID, Health: unhealthy}},
+			wantClusters: []fleetnetv1alpha1.ClusterStatus{
+				{Cluster: testClusterID, Region: "westus", VNetID: "vnet-id"},
+			},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			serviceImport := &fleetnetv1alpha1.ServiceImport{
+				Status: fleetnetv1alpha1.ServiceImportStatus{Clusters: tc.clusters, ClusterHealth: tc.clusterHealth},
+			}
+			export := internalSvcExport.DeepCopy()
+			export.Spec.Health = tc.health
+			addClusterToServiceImportStatus(serviceImport, export)
+			if diff := cmp.Diff(tc.wantClusters, serviceImport.Status.Clusters); diff != "" {
+				t.Errorf("addClusterToServiceImportStatus() clusters mismatch (-want, +got):\n%s", diff)
+			}
+			wantClusterWeights := []fleetnetv1alpha1.ClusterWeight{{Cluster: testClusterID, Weight: 3}}
+			if diff := cmp.Diff(wantClusterWeights, serviceImport.Status.ClusterWeights); diff != "" {
+				t.Errorf("addClusterToServiceImportStatus() cluster weights mismatch (-want, +got):\n%s", diff)
+			}
+			if diff := cmp.Diff(tc.wantClusterHealth, serviceImport.Status.ClusterHealth); diff != "" {
+				t.Errorf("addClusterToServiceImportStatus() cluster health mismatch (-want, +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+// TestHandleUpdate_StateLabel tests the state label and the state metric as an internalServiceExport goes from valid
+// to conflicted and back to valid once the conflict is resolved.
+func TestHandleUpdate_StateLabel(t *testing.T) {
+	ctx := context.Background()
+	internalSvcExport := internalServiceExportForTest()
+	serviceImport := &fleetnetv1alpha1.ServiceImport{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      testServiceName,
+			Namespace: testNamespace,
+		},
+		Status: fleetnetv1alpha1.ServiceImportStatus{
+			Ports: internalSvcExport.Spec.Ports,
+			Type:  fleetnetv1alpha1.ClusterSetIP,
+		},
+	}
+	fakeClient := fake.NewClientBuilder().
+		WithScheme(internalServiceExportScheme(t)).
+		WithObjects(internalSvcExport, serviceImport).
+		WithStatusSubresource(internalSvcExport, serviceImport).
+		Build()
+	r := internalServiceExportReconciler(fakeClient)
+	key := types.NamespacedName{Namespace: testMemberNamespace, Name: testName}
+
+	baseline := map[string]float64{}
+	for _, state := range []string{objectmeta.InternalServiceExportStateValid, objectmeta.InternalServiceExportStateConflicted} {
+		baseline[state] = testutil.ToFloat64(internalServiceExportState.WithLabelValues(state))
+	}
+	checkState := func(step, wantState string) {
+		t.Helper()
+		got := &fleetnetv1alpha1.InternalServiceExport{}
+		if err := fakeClient.Get(ctx, key, got); err != nil {
+			t.Fatalf("%s: InternalServiceExport Get() got error %v, want no error", step, err)
+		}
+		if gotState := got.Labels[objectmeta.InternalServiceExportLabelState]; gotState != wantState {
+			t.Errorf("%s: InternalServiceExport state label = %q, want %q", step, gotState, wantState)
+		}
+		for state, base := range baseline {
+			want := base
+			if state == wantState {
+				want++
+			}
+			if got := testutil.ToFloat64(internalServiceExportState.WithLabelValues(state)); got != want {
+				t.Errorf("%s: internalServiceExportState{state=%q} = %v, want %v", step, state, got, want)
+			}
+		}
+	}
+	handleUpdate := func(step string) {
+		t.Helper()
+		current := &fleetnetv1alpha1.InternalServiceExport{}
+		if err := fakeClient.Get(ctx, key, current); err != nil {
+			t.Fatalf("%s: InternalServiceExport Get() got error %v, want no error", step, err)
+		}
+		if _, err := r.handleUpdate(ctx, current); err != nil {
+			t.Fatalf("%s: handleUpdate() got error %v, want no error", step, err)
+		}
+	}
+
+	handleUpdate("exported")
+	checkState("exported", objectmeta.InternalServiceExportStateValid)
+
+	// The serviceImport resolves a different spec, e.g., after the export has been withdrawn and other clusters have
+	// exported the service with another port.
+	if err := fakeClient.Get(ctx, types.NamespacedName{Namespace: testNamespace, Name: testServiceName}, serviceImport); err != nil {
+		t.Fatalf("ServiceImport Get() got error %v, want no error", err)
+	}
+	serviceImport.Status.Ports = internalSvcExport.Spec.Ports[:1]
+	serviceImport.Status.Clusters = []fleetnetv1alpha1.ClusterStatus{{Cluster: "member-2"}}
+	if err := fakeClient.Status().Update(ctx, serviceImport); err != nil {
+		t.Fatalf("ServiceImport Status().Update() got error %v, want no error", err)
+	}
+	handleUpdate("conflicted")
+	checkState("conflicted", objectmeta.InternalServiceExportStateConflicted)
+
+	// The conflict is resolved by exporting the same spec as the serviceImport.
+	resolved := &fleetnetv1alpha1.InternalServiceExport{}
+	if err := fakeClient.Get(ctx, key, resolved); err != nil {
+		t.Fatalf("InternalServiceExport Get() got error %v, want no error", err)
+	}
+	resolved.Spec.Ports = internalSvcExport.Spec.Ports[:1]
+	if err := fakeClient.Update(ctx, resolved); err != nil {
+		t.Fatalf("InternalServiceExport Update() got error %v, want no error", err)
+	}
+	handleUpdate("resolved")
+	checkState("resolved", objectmeta.InternalServiceExportStateValid)
+
+	// The deleted internalServiceExport is no longer counted.
+	if err := fakeClient.Delete(ctx, resolved); err != nil {
+		t.Fatalf("InternalServiceExport Delete() got error %v, want no error", err)
+	}
+	if _, err := r.Reconcile(ctx, ctrl.Request{NamespacedName: key}); err != nil {
+		t.Fatalf("Reconcile() got error %v, want no error", err)
+	}
+	for state, want := range baseline {
+		if got := testutil.ToFloat64(internalServiceExportState.WithLabelValues(state)); got != want {
+			t.Errorf("deleted: internalServiceExportState{state=%q} = %v, want %v", state, got, want)
+		}
+	}
+}
+
+// TestHandleUpdate_IPFamilies tests that an internalServiceExport is in conflict with the serviceImport if their IP
+// families differ, regardless of the order of the families.
+func TestHandleUpdate_IPFamilies(t *testing.T) {
+	dualStack := []corev1.IPFamily{corev1.IPv4Protocol, corev1.IPv6Protocol}
+	testCases := []struct {
+		name           string
+		exportFamilies []corev1.IPFamily
+		importFamilies []corev1.IPFamily
+		wantConflict   bool
+	}{
+		{
+			name:         "families are not reported",
+			wantConflict: false,
+		},
+		{
+			name:           "unreported families of an IPv4 serviceImport",
+			importFamilies: []corev1.IPFamily{corev1.IPv4Protocol},
+			wantConflict:   false,
+		},
+		{
+			name:           "dual-stack export with a different primary family",
+			exportFamilies: []corev1.IPFamily{corev1.IPv6Protocol, corev1.IPv4Protocol},
+			importFamilies: dualStack,
+			wantConflict:   false,
+		},
+		{
+			name:           "single-stack export of a dual-stack serviceImport",
+			exportFamilies: []corev1.IPFamily{corev1.IPv4Protocol},
+			importFamilies: dualStack,
+			wantConflict:   true,
+		},
+		{
+			name:           "IPv6 export of an IPv4 serviceImport",
+			exportFamilies: []corev1.IPFamily{corev1.IPv6Protocol},
+			wantConflict:   true,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx := context.Background()
+			internalSvcExport := internalServiceExportForTest()
+			internalSvcExport.Spec.AddressFamilies = tc.exportFamilies
+			serviceImport := &fleetnetv1alpha1.ServiceImport{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      testServiceName,
+					Namespace: testNamespace,
+				},
+				Status: fleetnetv1alpha1.ServiceImportStatus{
+					Ports:      internalSvcExport.Spec.Ports,
+					IPFamilies: tc.importFamilies,
+					Clusters:   []fleetnetv1alpha1.ClusterStatus{{Cluster: "member-2"}},
+					Type:       fleetnetv1alpha1.ClusterSetIP,
+				},
+			}
+			fakeClient := fake.NewClientBuilder().
+				WithScheme(internalServiceExportScheme(t)).
+				WithObjects(internalSvcExport, serviceImport).
+				WithStatusSubresource(internalSvcExport, serviceImport).
+				Build()
+			r := internalServiceExportReconciler(fakeClient)
+			if _, err := r.handleUpdate(ctx, internalSvcExport); err != nil {
+				t.Fatalf("handleUpdate() got error %v, want no error", err)
+			}
+
+			got := &fleetnetv1alpha1.InternalServiceExport{}
+			if err := fakeClient.Get(ctx, types.NamespacedName{Namespace: testMemberNamespace, Name: testName}, got); err != nil {
+				t.Fatalf("InternalServiceExport Get() got error %v, want no error", err)
+			}
+			wantState := objectmeta.InternalServiceExportStateValid
+			if tc.wantConflict {
+				wantState = objectmeta.InternalServiceExportStateConflicted
+			}
+			if gotState := got.Labels[objectmeta.InternalServiceExportLabelState]; gotState != wantState {
+				t.Errorf("InternalServiceExport state label = %q, want %q", gotState, wantState)
+			}
+
+			gotImport := &fleetnetv1alpha1.ServiceImport{}
+			if err := fakeClient.Get(ctx, types.NamespacedName{Namespace: testNamespace, Name: testServiceName}, gotImport); err != nil {
+				t.Fatalf("ServiceImport Get() got error %v, want no error", err)
+			}
+			wantClusters := []fleetnetv1alpha1.ClusterStatus{{Cluster: "member-2"}}
+			if !tc.wantConflict {
+				wantClusters = append(wantClusters, fleetnetv1alpha1.ClusterStatus{Cluster: testClusterID})
+			}
+			if diff := cmp.Diff(wantClusters, gotImport.Status.Clusters); diff != "" {
+				t.Errorf("ServiceImport clusters mismatch (-want, +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestExportState(t *testing.T) {
+	testCases := []struct {
+		name     string
+		noPorts  bool
+		conflict bool
+		want     string
+	}{
+		{
+			name: "valid",
+			want: objectmeta.InternalServiceExportStateValid,
+		},
+		{
+			name:     "conflicted",
+			conflict: true,
+			want:     objectmeta.InternalServiceExportStateConflicted,
+		},
+		{
+			name:     "no ports",
+			noPorts:  true,
+			conflict: true,
+			want:     objectmeta.InternalServiceExportStateInvalid,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			internalSvcExport := internalServiceExportForTest()
+			if tc.noPorts {
+				internalSvcExport.Spec.Ports = nil
+			}
+			if got := exportState(internalSvcExport, tc.conflict); got != tc.want {
+				t.Errorf("exportState() = %q, want %q", got, tc.want)
+			}
+		})
+	}
+}
+
+// hubWriteRecorder records the writes to the hub cluster, as the type of the written object along with the
+// subresource, if any.
+func hubWriteRecorder(writes *[]string) interceptor.Funcs {
+	return interceptor.Funcs{
+		Update: func(ctx context.Context, c client.WithWatch, obj client.Object, opts ...client.UpdateOption) error {
+			*writes = append(*writes, fmt.Sprintf("%T", obj))
+			return c.Update(ctx, obj, opts...)
+		},
+		SubResourceUpdate: func(ctx context.Context, c client.Client, subResourceName string, obj client.Object, opts ...client.SubResourceUpdateOption) error {
+			*writes = append(*writes, fmt.Sprintf("%T/%s", obj, subResourceName))
+			return c.SubResource(subResourceName).Update(ctx, obj, opts...)
+		},
+	}
+}
+
+// TestHandleUpdate_FieldChanges tests that the conflicts of an accepted internalServiceExport are evaluated again
+// when its ports change, but not when only its weight or its Traffic Manager related information change.
+func TestHandleUpdate_FieldChanges(t *testing.T) {
+	const (
+		serviceImportStatusWrite         = "*v1alpha1.ServiceImport/status"
+		internalServiceExportWrite       = "*v1alpha1.InternalServiceExport"
+		internalServiceExportStatusWrite = "*v1alpha1.InternalServiceExport/status"
+	)
+	testCases := []struct {
+		name             string
+		change           func(spec *fleetnetv1alpha1.InternalServiceExportSpec)
+		wantWrites       []string
+		wantState        string
+		wantWeight       *int64
+		wantClusterCount int
+	}{
+		{
+			name: "weight only",
+			change: func(spec *fleetnetv1alpha1.InternalServiceExportSpec) {
+				spec.Weight = ptr.To(int64(50))
+			},
+			wantWrites:       []string{serviceImportStatusWrite},
+			wantState:        objectmeta.InternalServiceExportStateValid,
+			wantWeight:       ptr.To(int64(50)),
+			wantClusterCount: 2,
+		},
+		{
+			name: "traffic manager information only",
+			change: func(spec *fleetnetv1alpha1.InternalServiceExportSpec) {
+				spec.Type = corev1.ServiceTypeLoadBalancer
+				spec.IsDNSLabelConfigured = true
+				spec.PublicIPResourceID = ptr.To("public-ip")
+			},
+			wantState:        objectmeta.InternalServiceExportStateValid,
+			wantClusterCount: 2,
+		},
+		{
+			name: "ports",
+			change: func(spec *fleetnetv1alpha1.InternalServiceExportSpec) {
+				spec.Ports = spec.Ports[:1]
+			},
+			wantWrites:       []string{serviceImportStatusWrite, internalServiceExportWrite, internalServiceExportStatusWrite},
+			wantState:        objectmeta.InternalServiceExportStateConflicted,
+			wantClusterCount: 1,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx := context.Background()
+			internalSvcExport := internalServiceExportForTest()
+			serviceImport := &fleetnetv1alpha1.ServiceImport{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      testServiceName,
+					Namespace: testNamespace,
+				},
+				Status: fleetnetv1alpha1.ServiceImportStatus{
+					Ports:    internalSvcExport.Spec.Ports,
+					Type:     fleetnetv1alpha1.ClusterSetIP,
+					Clusters: []fleetnetv1alpha1.ClusterStatus{{Cluster: "member-2"}},
+				},
+			}
+			var writes []string
+			fakeClient := fake.NewClientBuilder().
+				WithScheme(internalServiceExportScheme(t)).
+				WithObjects(internalSvcExport, serviceImport).
+				WithStatusSubresource(internalSvcExport, serviceImport).
+				WithInterceptorFuncs(hubWriteRecorder(&writes)).
+				Build()
+			r := internalServiceExportReconciler(fakeClient)
+			key := types.NamespacedName{Namespace: testMemberNamespace, Name: testName}
+			serviceImportKey := types.NamespacedName{Namespace: testNamespace, Name: testServiceName}
+
+			current := &fleetnetv1alpha1.InternalServiceExport{}
+			if err := fakeClient.Get(ctx, key, current); err != nil {
+				t.Fatalf("InternalServiceExport Get() got error %v, want no error", err)
+			}
+			if _, err := r.handleUpdate(ctx, current); err != nil {
+				t.Fatalf("handleUpdate() got error %v, want no error", err)
+			}
+
+			tc.change(&current.Spec)
+			if err := fakeClient.Update(ctx, current); err != nil {
+				t.Fatalf("InternalServiceExport Update() got error %v, want no error", err)
+			}
+			writes = nil
+			if _, err := r.handleUpdate(ctx, current); err != nil {
+				t.Fatalf("handleUpdate() got error %v, want no error", err)
+			}
+			if diff := cmp.Diff(tc.wantWrites, writes); diff != "" {
+				t.Errorf("handleUpdate() writes mismatch (-want, +got):\n%s", diff)
+			}
+
+			got := &fleetnetv1alpha1.InternalServiceExport{}
+			if err := fakeClient.Get(ctx, key, got); err != nil {
+				t.Fatalf("InternalServiceExport Get() got error %v, want no error", err)
+			}
+			if gotState := got.Labels[objectmeta.InternalServiceExportLabelState]; gotState != tc.wantState {
+				t.Errorf("InternalServiceExport state label = %q, want %q", gotState, tc.wantState)
+			}
+			gotServiceImport := &fleetnetv1alpha1.ServiceImport{}
+			if err := fakeClient.Get(ctx, serviceImportKey, gotServiceImport); err != nil {
+				t.Fatalf("ServiceImport Get() got error %v, want no error", err)
+			}
+			if got := len(gotServiceImport.Status.Clusters); got != tc.wantClusterCount {
+				t.Fatalf("ServiceImport clusters = %v, want %d clusters", gotServiceImport.Status.Clusters, tc.wantClusterCount)
+			}
+			if diff := cmp.Diff(tc.wantWeight, exportweight.Find(&gotServiceImport.Status, testClusterID)); diff != "" {
+				t.Errorf("ServiceImport cluster weight mismatch (-want, +got):\n%s", diff)
+			}
+		})
+	}
+}
@@ -11,10 +11,12 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
-	"reflect"
 	"time"
 
+	"k8s.io/apimachinery/pkg/api/equality"
 	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/klog/v2"
 	ctrl "sigs.k8s.io/controller-runtime"
@@ -24,12 +26,15 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	fleetnetv1alpha1 "go.goms.io/fleet-networking/api/v1alpha1"
+	"go.goms.io/fleet-networking/pkg/common/consumerpolicy"
+	"go.goms.io/fleet-networking/pkg/common/fieldmanager"
 	"go.goms.io/fleet-networking/pkg/common/objectmeta"
+	"go.goms.io/fleet-networking/pkg/common/reconcilewatchdog"
 )
 
 const (
 	internalSvcImportCleanupFinalizer = "networking.fleet.azure.com/internalsvcimport-cleanup"
-	svcImportCleanupFinalizer         = "networking.fleet.azure.com/serviceimport-cleanup"
+	svcImportCleanupFinalizer         = objectmeta.ServiceImportCleanupFinalizer
 
 	internalSvcImportSvcRefNamespacedNameFieldKey = ".spec.serviceImportReference.namespacedName"
 
@@ -41,9 +46,11 @@ type Reconciler struct {
 	HubClient client.Client
 }
 
-//+kubebuilder:rbac:groups=networking.fleet.azure.com,resources=internalserviceimports,verbs=get;list;watch
-//+kubebuilder:rbac:groups=networking.fleet.azure.com,resources=serviceimports,verbs=get;list
+//+kubebuilder:rbac:groups=networking.fleet.azure.com,resources=internalserviceimports,verbs=get;list;watch;update;patch
+//+kubebuilder:rbac:groups=networking.fleet.azure.com,resources=internalserviceimports/status,verbs=get;update;patch
+//+kubebuilder:rbac:groups=networking.fleet.azure.com,resources=serviceimports,verbs=get;list;watch;update
 //+kubebuilder:rbac:groups=networking.fleet.azure.com,resources=serviceimports/status,verbs=get;update;patch
+//+kubebuilder:rbac:groups=cluster.kubernetes-fleet.io,resources=memberclusters,verbs=get;list;watch
 
 // Reconcile checks if a member cluster can import a Service from the hub cluster and fulfills the import.
 func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
@@ -114,6 +121,28 @@ func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Resu
 	clusterNamespace := fleetnetv1alpha1.ClusterNamespace(internalSvcImport.Namespace)
 	clusterID := fleetnetv1alpha1.ClusterID(internalSvcImport.Spec.ServiceImportReference.ClusterID)
 
+	// Leave out the exporting clusters whose consumer policies do not allow the member cluster to import the Service;
+	// if none is left, the member cluster sees the Service as if it were never exported, and its import (if any) is
+	// withdrawn.
+	permittedStatus, err := consumerpolicy.NewEvaluator(r.HubClient, string(clusterID)).PermittedStatus(ctx, &svcImport.Status)
+	if err != nil {
+		klog.ErrorS(err, "Failed to evaluate the consumer policies of the exported Service",
+			"serviceImport", svcImportRef,
+			"internalServiceImport", internalSvcImportRef)
+		return ctrl.Result{}, err
+	}
+	if len(permittedStatus.Clusters) == 0 {
+		klog.V(2).InfoS("No exporting cluster allows the member cluster to import the Service; spec of imported Service (if any) will be cleared",
+			"serviceImport", svcImportRef,
+			"internalServiceImport", internalSvcImportRef)
+		if controllerutil.ContainsFinalizer(internalSvcImport, internalSvcImportCleanupFinalizer) {
+			if _, err := r.withdrawServiceImport(ctx, svcImport, internalSvcImport); err != nil {
+				return ctrl.Result{}, err
+			}
+		}
+		return r.clearInternalServiceImportStatus(ctx, internalSvcImport)
+	}
+
 	// Find out which member clusters have imported the Service.
 	svcInUseBy := extractServiceInUseByInfoFromServiceImport(svcImport)
 	if len(svcInUseBy.MemberClusters) > 0 {
@@ -123,7 +152,7 @@ func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Resu
 			klog.V(2).InfoS("The member cluster has imported the Service; will sync the imported Service spec",
 				"serviceImport", svcImportRef,
 				"internalServiceImport", internalSvcImportRef)
-			if err := r.fulfillInternalServiceImport(ctx, svcImport, internalSvcImport); err != nil {
+			if err := r.fulfillInternalServiceImport(ctx, permittedStatus, internalSvcImport); err != nil {
 				klog.ErrorS(err, "Failed to fulfill service import by updating InternalServiceImport status",
 					"serviceImport", svcImportRef,
 					"internalServiceImport", internalSvcImportRef)
@@ -160,7 +189,7 @@ func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Resu
 	}
 
 	// Fulfill the import (i.e. update the Service spec kept in InternalServiceImport status).
-	if err := r.fulfillInternalServiceImport(ctx, svcImport, internalSvcImport); err != nil {
+	if err := r.fulfillInternalServiceImport(ctx, permittedStatus, internalSvcImport); err != nil {
 		klog.ErrorS(err, "Failed to fulfill service import by updating InternalServiceImport status",
 			"serviceImport", svcImportRef,
 			"internalServiceImport", internalSvcImportRef)
@@ -219,7 +248,7 @@ func (r *Reconciler) SetupWithManager(ctx context.Context, mgr ctrl.Manager) err
 	return ctrl.NewControllerManagedBy(mgr).
 		For(&fleetnetv1alpha1.InternalServiceImport{}).
 		Watches(&fleetnetv1alpha1.ServiceImport{}, eventHandlers).
-		Complete(r)
+		Complete(reconcilewatchdog.Wrap("internalserviceimport", r))
 }
 
 // withdrawServiceImport withdraws the request to import a Service to a member cluster.
@@ -277,13 +306,7 @@ func (r *Reconciler) clearInternalServiceImportStatus(ctx context.Context, inter
 		return ctrl.Result{}, err
 	}
 
-	clearedInternalSvcImportStatus := fleetnetv1alpha1.ServiceImportStatus{}
-	if reflect.DeepEqual(internalSvcImport.Status, clearedInternalSvcImportStatus) {
-		// The state has stablized; skip the clearing.
-		return ctrl.Result{}, nil
-	}
-	internalSvcImport.Status = clearedInternalSvcImportStatus
-	if err := r.HubClient.Status().Update(ctx, internalSvcImport); err != nil {
+	if err := r.applyInternalServiceImportStatus(ctx, internalSvcImport, &fleetnetv1alpha1.ServiceImportStatus{}); err != nil {
 		klog.ErrorS(err, "Failed to clear InternalServiceImport status", "internalServiceImport", klog.KObj(internalSvcImport))
 		return ctrl.Result{}, err
 	}
@@ -335,19 +358,78 @@ func (r *Reconciler) clearServiceInUseByInfoFromServiceImport(ctx context.Contex
 	return r.HubClient.Update(ctx, svcImport)
 }
 
-// fulfillInternalServiceImport fulfills an import of a Service by syncing the Service spec to the status of an
-// InternalServiceImport.
+// fulfillInternalServiceImport fulfills an import of a Service by syncing the Service spec, as seen by the importing
+// member cluster, to the status of an InternalServiceImport.
 func (r *Reconciler) fulfillInternalServiceImport(ctx context.Context,
-	svcImport *fleetnetv1alpha1.ServiceImport,
+	svcImportStatus *fleetnetv1alpha1.ServiceImportStatus,
 	internalSvcImport *fleetnetv1alpha1.InternalServiceImport) error {
-	updatedInternalSvcImportStatus := svcImport.Status.DeepCopy()
-	if reflect.DeepEqual(internalSvcImport.Status, updatedInternalSvcImportStatus) {
-		// The state has stablized; skip the fulfillment.
+	return r.applyInternalServiceImportStatus(ctx, internalSvcImport, svcImportStatus)
+}
+
+// applyInternalServiceImportStatus applies the status of an InternalServiceImport.
+//
+// The hub cluster owns the status of an InternalServiceImport, i.e. the resolution of the import, and the importing
+// member cluster owns its spec; the status is applied with server-side apply under the hub agent field manager,
+// taking it back from any other manager which changed it. The status is applied only when it drifts from the wanted
+// one, so that the two sides do not keep overwriting each other.
+func (r *Reconciler) applyInternalServiceImportStatus(ctx context.Context,
+	internalSvcImport *fleetnetv1alpha1.InternalServiceImport,
+	status *fleetnetv1alpha1.ServiceImportStatus) error {
+	if equality.Semantic.DeepEqual(internalSvcImport.Status, *status) {
+		// The state has stablized; skip the apply.
+		return nil
+	}
+	internalSvcImportRef := klog.KObj(internalSvcImport)
+	klog.V(2).InfoS("Status of InternalServiceImport drifts from the resolved import",
+		"internalServiceImport", internalSvcImportRef,
+		"status", status,
+		"oldStatus", internalSvcImport.Status)
+
+	// InternalServiceImports written before the hub agent switched to server-side apply have their status owned by
+	// the Update operations of the agent; hand it over to the apply field manager, so that the fields the agent no
+	// longer sets are dropped.
+	if err := fieldmanager.UpgradeStatusManagedFields(ctx, r.HubClient, internalSvcImport,
+		objectmeta.HubAgentFieldManager, objectmeta.HubAgentLegacyFieldManager); err != nil {
+		klog.ErrorS(err, "Failed to upgrade the managed fields of InternalServiceImport", "internalServiceImport", internalSvcImportRef)
+		return err
+	}
+
+	if equality.Semantic.DeepEqual(*status, fleetnetv1alpha1.ServiceImportStatus{}) {
+		// Applying an empty status removes all the fields the agent owns and leaves a null status, which the API
+		// server rejects; clear the status as a whole instead.
+		internalSvcImport.Status = fleetnetv1alpha1.ServiceImportStatus{}
+		return r.HubClient.Status().Update(ctx, internalSvcImport, client.FieldOwner(objectmeta.HubAgentFieldManager))
+	}
+
+	// Only the status is applied, so that the spec stays with the member agent.
+	statusObj, err := runtime.DefaultUnstructuredConverter.ToUnstructured(status)
+	if err != nil {
+		return fmt.Errorf("failed to convert the InternalServiceImport status: %w", err)
+	}
+	applied := &unstructured.Unstructured{Object: map[string]interface{}{"status": statusObj}}
+	applied.SetGroupVersionKind(fleetnetv1alpha1.GroupVersion.WithKind("InternalServiceImport"))
+	applied.SetNamespace(internalSvcImport.Namespace)
+	applied.SetName(internalSvcImport.Name)
+	if err := r.HubClient.Status().Patch(ctx, applied, client.Apply,
+		client.FieldOwner(objectmeta.HubAgentFieldManager), client.ForceOwnership); err != nil {
+		return err
+	}
+	appliedInternalSvcImport := &fleetnetv1alpha1.InternalServiceImport{}
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(applied.Object, appliedInternalSvcImport); err != nil {
+		return fmt.Errorf("failed to convert the applied InternalServiceImport: %w", err)
+	}
+	*internalSvcImport = *appliedInternalSvcImport
+	if equality.Semantic.DeepEqual(internalSvcImport.Status, *status) {
 		return nil
 	}
-	internalSvcImport.Status = *updatedInternalSvcImportStatus
 
-	return r.HubClient.Status().Update(ctx, internalSvcImport)
+	// Server-side apply keeps the fields other managers added to the status, e.g. an extra cluster; replace the
+	// status as a whole to drop them.
+	klog.V(2).InfoS("Status of InternalServiceImport keeps the fields of other managers; replacing it",
+		"internalServiceImport", internalSvcImportRef,
+		"status", internalSvcImport.Status)
+	internalSvcImport.Status = *status.DeepCopy()
+	return r.HubClient.Status().Update(ctx, internalSvcImport, client.FieldOwner(objectmeta.HubAgentFieldManager))
 }
 
 // extractServiceInUseByInfoFromServiceImport extracts ServiceInUseBy information from annotations on a ServiceImport.
@@ -447,6 +447,121 @@ var _ = Describe("internalserviceimport controller", Ordered, func() {
 		})
 	})
 
+	Context("new internalserviceimport (consumer policies are set)", FlakeAttempts(3), func() {
+		var internalSvcImport *fleetnetv1alpha1.InternalServiceImport
+		var svcImport *fleetnetv1alpha1.ServiceImport
+
+		denyMemberA := fleetnetv1alpha1.ConsumerPolicy{
+			DeniedClusters: []string{clusterIDForMemberA},
+		}
+
+		BeforeEach(func() {
+			svcImport = unfulfilledAndRequestedServiceImport()
+			svcImport.Annotations = nil
+			svcImport.Finalizers = []string{}
+			Expect(hubClient.Create(ctx, svcImport)).Should(Succeed())
+			fulfillServiceImport(svcImport)
+			// Only the Service exported from member cluster B denies member cluster A.
+			svcImport.Status.ConsumerPolicies = []fleetnetv1alpha1.ClusterConsumerPolicy{
+				{
+					Cluster: clusterIDForMemberB,
+					Policy:  denyMemberA,
+				},
+			}
+			Expect(hubClient.Status().Update(ctx, svcImport)).Should(Succeed())
+
+			internalSvcImport = unfulfilledInternalServiceImport()
+			Expect(hubClient.Create(ctx, internalSvcImport)).Should(Succeed())
+		})
+
+		AfterEach(func() {
+			Expect(hubClient.Delete(ctx, internalSvcImport)).Should(Succeed())
+			// Confirm that InternalServiceImport is deleted; this helps make the test less flaky.
+			Eventually(func() bool {
+				internalSvcImport := &fleetnetv1alpha1.InternalServiceImport{}
+				if err := hubClient.Get(ctx, internalSvcImportAKey, internalSvcImport); err != nil && errors.IsNotFound(err) {
+					return true
+				}
+				return false
+			}, eventuallyTimeout, eventuallyInterval).Should(BeTrue())
+
+			Expect(hubClient.Delete(ctx, svcImport)).Should(Succeed())
+			// Confirm that ServiceImport is deleted; this helps make the test less flaky.
+			Eventually(func() bool {
+				svcImport := &fleetnetv1alpha1.ServiceImport{}
+				if err := hubClient.Get(ctx, svcImportKey, svcImport); err != nil && errors.IsNotFound(err) {
+					return true
+				}
+				return false
+			}, eventuallyTimeout, eventuallyInterval).Should(BeTrue())
+		})
+
+		It("should import from the permitted clusters only + should withdraw the import once all clusters deny it", func() {
+			// Check if InternalServiceImport is fulfilled with the clusters which permit the import only.
+			Eventually(func() bool {
+				internalSvcImport := &fleetnetv1alpha1.InternalServiceImport{}
+				if err := hubClient.Get(ctx, internalSvcImportAKey, internalSvcImport); err != nil {
+					return false
+				}
+
+				if !cmp.Equal(internalSvcImport.Finalizers, []string{internalSvcImportCleanupFinalizer}) {
+					return false
+				}
+
+				if len(internalSvcImport.Status.ConsumerPolicies) != 0 {
+					return false
+				}
+				clusters := internalSvcImport.Status.Clusters
+				return len(clusters) == 2 && clusters[0].Cluster == clusterIDForMemberA && clusters[1].Cluster == clusterIDForMemberC
+			}, eventuallyTimeout, eventuallyInterval).Should(BeTrue())
+
+			// Check if ServiceImport is claimed.
+			Eventually(func() bool {
+				svcImport := &fleetnetv1alpha1.ServiceImport{}
+				if err := hubClient.Get(ctx, svcImportKey, svcImport); err != nil {
+					return false
+				}
+
+				_, ok := svcImport.Annotations[objectmeta.ServiceImportAnnotationServiceInUseBy]
+				return ok
+			}, eventuallyTimeout, eventuallyInterval).Should(BeTrue())
+
+			// Deny member cluster A on all exporting clusters.
+			Expect(hubClient.Get(ctx, svcImportKey, svcImport)).Should(Succeed())
+			svcImport.Status.ConsumerPolicies = []fleetnetv1alpha1.ClusterConsumerPolicy{
+				{Cluster: clusterIDForMemberA, Policy: denyMemberA},
+				{Cluster: clusterIDForMemberB, Policy: denyMemberA},
+				{Cluster: clusterIDForMemberC, Policy: denyMemberA},
+			}
+			Expect(hubClient.Status().Update(ctx, svcImport)).Should(Succeed())
+
+			// Check if InternalServiceImport is cleared.
+			Eventually(func() bool {
+				internalSvcImport := &fleetnetv1alpha1.InternalServiceImport{}
+				if err := hubClient.Get(ctx, internalSvcImportAKey, internalSvcImport); err != nil {
+					return false
+				}
+
+				if len(internalSvcImport.Finalizers) != 0 {
+					return false
+				}
+
+				return cmp.Equal(internalSvcImport.Status, fleetnetv1alpha1.ServiceImportStatus{})
+			}, eventuallyTimeout, eventuallyInterval).Should(BeTrue())
+
+			// Check if the claim on ServiceImport is withdrawn.
+			Eventually(func() bool {
+				svcImport := &fleetnetv1alpha1.ServiceImport{}
+				if err := hubClient.Get(ctx, svcImportKey, svcImport); err != nil {
+					return false
+				}
+
+				_, ok := svcImport.Annotations[objectmeta.ServiceImportAnnotationServiceInUseBy]
+				return !ok && len(svcImport.Finalizers) == 0
+			}, eventuallyTimeout, eventuallyInterval).Should(BeTrue())
+		})
+	})
+
 	Context("serviceimport is created (with pre-existing internalserviceimports)", FlakeAttempts(3), func() {
 		var internalSvcImportB *fleetnetv1alpha1.InternalServiceImport
 		var internalSvcImportC *fleetnetv1alpha1.InternalServiceImport
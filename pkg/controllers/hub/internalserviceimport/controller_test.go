@@ -10,15 +10,20 @@ import (
 	"encoding/json"
 	"log"
 	"os"
+	"slices"
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/kubernetes/scheme"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
 
 	fleetnetv1alpha1 "go.goms.io/fleet-networking/api/v1alpha1"
 	"go.goms.io/fleet-networking/pkg/common/objectmeta"
@@ -109,6 +114,56 @@ func fulfilledServiceImport() *fleetnetv1alpha1.ServiceImport {
 	}
 }
 
+// statusWrites counts the writes to the InternalServiceImport status.
+type statusWrites struct {
+	applies int
+	updates int
+}
+
+// statusApplyInterceptor emulates the server-side apply of the InternalServiceImport status, which the fake client
+// does not support: the applied status replaces the status, except for the clusters listed in foreignClusters, which
+// are owned by another field manager and hence kept by the apply.
+func statusApplyInterceptor(writes *statusWrites, foreignClusters ...string) interceptor.Funcs {
+	return interceptor.Funcs{
+		SubResourcePatch: func(ctx context.Context, c client.Client, subResourceName string, obj client.Object, patch client.Patch, opts ...client.SubResourcePatchOption) error {
+			applied, ok := obj.(*unstructured.Unstructured)
+			if !ok || patch.Type() != types.ApplyPatchType {
+				return c.SubResource(subResourceName).Patch(ctx, obj, patch, opts...)
+			}
+			writes.applies++
+			status := fleetnetv1alpha1.ServiceImportStatus{}
+			if statusObj, ok := applied.Object["status"].(map[string]interface{}); ok {
+				if err := runtime.DefaultUnstructuredConverter.FromUnstructured(statusObj, &status); err != nil {
+					return err
+				}
+			}
+			live := &fleetnetv1alpha1.InternalServiceImport{}
+			if err := c.Get(ctx, client.ObjectKeyFromObject(obj), live); err != nil {
+				return err
+			}
+			for _, cs := range live.Status.Clusters {
+				if slices.Contains(foreignClusters, cs.Cluster) && !slices.Contains(status.Clusters, cs) {
+					status.Clusters = append(status.Clusters, cs)
+				}
+			}
+			live.Status = status
+			if err := c.Status().Update(ctx, live); err != nil {
+				return err
+			}
+			liveObj, err := runtime.DefaultUnstructuredConverter.ToUnstructured(live)
+			if err != nil {
+				return err
+			}
+			applied.Object = liveObj
+			return nil
+		},
+		SubResourceUpdate: func(ctx context.Context, c client.Client, subResourceName string, obj client.Object, opts ...client.SubResourceUpdateOption) error {
+			writes.updates++
+			return c.SubResource(subResourceName).Update(ctx, obj, opts...)
+		},
+	}
+}
+
 // TestMain bootstraps the test environment.
 func TestMain(m *testing.M) {
 	// Add custom APIs to the runtime scheme
@@ -424,6 +479,7 @@ func TestClearInternalServiceImportStatus(t *testing.T) {
 				WithScheme(scheme.Scheme).
 				WithObjects(tc.internalSvcImport).
 				WithStatusSubresource(tc.internalSvcImport).
+				WithInterceptorFuncs(statusApplyInterceptor(&statusWrites{})).
 				Build()
 			reconciler := Reconciler{
 				HubClient: fakeHubClient,
@@ -623,12 +679,13 @@ func TestFulfillInternalServiceImport(t *testing.T) {
 				WithScheme(scheme.Scheme).
 				WithObjects(tc.internalSvcImport).
 				WithStatusSubresource(tc.internalSvcImport).
+				WithInterceptorFuncs(statusApplyInterceptor(&statusWrites{})).
 				Build()
 			reconciler := Reconciler{
 				HubClient: fakeHubClient,
 			}
 
-			if err := reconciler.fulfillInternalServiceImport(ctx, tc.svcImport, tc.internalSvcImport); err != nil {
+			if err := reconciler.fulfillInternalServiceImport(ctx, &tc.svcImport.Status, tc.internalSvcImport); err != nil {
 				t.Fatalf("fulfillInternalServiceImport(%+v, %+v), got %v, want no error", tc.svcImport, tc.internalSvcImport, err)
 			}
 
@@ -687,3 +744,95 @@ func TestClearServiceInUseByInfoFromServiceImport(t *testing.T) {
 		})
 	}
 }
+
+// TestReconcile_StatusDrift tests that the status of an InternalServiceImport changed out-of-band is restored, and
+// that it stays untouched once restored over the following reconciliations.
+func TestReconcile_StatusDrift(t *testing.T) {
+	const foreignCluster = "foreign"
+	wantStatus := fulfilledServiceImport().Status
+	testCases := []struct {
+		name            string
+		status          fleetnetv1alpha1.ServiceImportStatus
+		foreignClusters []string
+		wantWrites      statusWrites
+	}{
+		{
+			name:   "status not changed",
+			status: *wantStatus.DeepCopy(),
+		},
+		{
+			name: "ports changed",
+			status: func() fleetnetv1alpha1.ServiceImportStatus {
+				status := wantStatus.DeepCopy()
+				status.Ports = status.Ports[:1]
+				status.Type = fleetnetv1alpha1.Headless
+				return *status
+			}(),
+			wantWrites: statusWrites{applies: 1},
+		},
+		{
+			name:       "status cleared",
+			wantWrites: statusWrites{applies: 1},
+		},
+		{
+			name: "cluster added by another field manager",
+			status: func() fleetnetv1alpha1.ServiceImportStatus {
+				status := wantStatus.DeepCopy()
+				status.Clusters = append(status.Clusters, fleetnetv1alpha1.ClusterStatus{Cluster: foreignCluster})
+				return *status
+			}(),
+			foreignClusters: []string{foreignCluster},
+			wantWrites:      statusWrites{applies: 1, updates: 1},
+		},
+	}
+
+	ctx := context.Background()
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			internalSvcImport := &fleetnetv1alpha1.InternalServiceImport{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace:  hubNSForMemberA,
+					Name:       internalSvcImportName,
+					Finalizers: []string{internalSvcImportCleanupFinalizer},
+				},
+				Spec: fleetnetv1alpha1.InternalServiceImportSpec{
+					ServiceImportReference: fleetnetv1alpha1.ExportedObjectReference{
+						ClusterID:      clusterIDForMemberA,
+						Kind:           "ServiceImport",
+						Namespace:      memberUserNS,
+						Name:           svcName,
+						NamespacedName: svcImportKey.String(),
+					},
+				},
+				Status: tc.status,
+			}
+			writes := &statusWrites{}
+			fakeHubClient := fake.NewClientBuilder().
+				WithScheme(scheme.Scheme).
+				WithObjects(fulfilledServiceImport(), internalSvcImport).
+				WithStatusSubresource(internalSvcImport).
+				WithInterceptorFuncs(statusApplyInterceptor(writes, tc.foreignClusters...)).
+				Build()
+			reconciler := Reconciler{
+				HubClient: fakeHubClient,
+			}
+
+			// The status converges within the first reconciliation; the following ones find nothing to restore.
+			for i := 0; i < 3; i++ {
+				if _, err := reconciler.Reconcile(ctx, ctrl.Request{NamespacedName: internalSvcImportAKey}); err != nil {
+					t.Fatalf("Reconcile() #%d got error %v, want no error", i, err)
+				}
+				got := &fleetnetv1alpha1.InternalServiceImport{}
+				if err := fakeHubClient.Get(ctx, internalSvcImportAKey, got); err != nil {
+					t.Fatalf("internalServiceImport Get(%+v), got %v, want no error", internalSvcImportAKey, err)
+				}
+				if diff := cmp.Diff(wantStatus, got.Status); diff != "" {
+					t.Errorf("Reconcile() #%d internalServiceImport status mismatch (-want, +got):\n%s", i, diff)
+				}
+			}
+			if *writes != tc.wantWrites {
+				t.Errorf("Reconcile() status writes = %+v, want %+v", *writes, tc.wantWrites)
+			}
+		})
+	}
+}
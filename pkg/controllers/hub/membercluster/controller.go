@@ -6,6 +6,9 @@ Licensed under the MIT license.
 // Package membercluster features the MemberCluster controller for watching
 // update/delete events to the MemberCluster object and removes finalizers
 // on all fleet networking resources in the fleet member cluster namespace.
+// It also mirrors the network properties published by the member agents on
+// the InternalMemberCluster onto the MemberCluster, and the networking mode
+// set by fleet admins on the MemberCluster onto the InternalMemberCluster.
 package membercluster
 
 import (
@@ -15,23 +18,37 @@ import (
 
 	"golang.org/x/sync/errgroup"
 	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/tools/record"
 	"k8s.io/klog/v2"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	clusterv1beta1 "go.goms.io/fleet/apis/cluster/v1beta1"
 
 	fleetnetv1alpha1 "go.goms.io/fleet-networking/api/v1alpha1"
 	"go.goms.io/fleet-networking/pkg/common/hubconfig"
+	"go.goms.io/fleet-networking/pkg/common/objectmeta"
+	"go.goms.io/fleet-networking/pkg/common/reconcilewatchdog"
 )
 
 const (
 	ControllerName = "membercluster-controller"
 )
 
+// networkPropertiesAnnotations are the annotations of the network properties which are mirrored from the internal
+// member cluster onto the member cluster.
+var networkPropertiesAnnotations = []string{
+	objectmeta.MemberClusterAnnotationRegion,
+	objectmeta.MemberClusterAnnotationVNetID,
+}
+
 // Reconciler reconciles a MemberCluster object.
 type Reconciler struct {
 	client.Client
@@ -40,6 +57,11 @@ type Reconciler struct {
 	ForceDeleteWaitTime time.Duration
 }
 
+//+kubebuilder:rbac:groups=cluster.kubernetes-fleet.io,resources=memberclusters,verbs=get;list;watch;patch
+//+kubebuilder:rbac:groups=cluster.kubernetes-fleet.io,resources=internalmemberclusters,verbs=get;list;watch;patch
+//+kubebuilder:rbac:groups=networking.fleet.azure.com,resources=internalserviceimports,verbs=get;list;update;delete
+//+kubebuilder:rbac:groups=networking.fleet.azure.com,resources=endpointsliceimports,verbs=get;list;update
+
 // Reconcile watches the deletion of the member cluster and removes finalizers on fleet networking resources in the
 // member cluster namespace.
 func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
@@ -60,8 +82,14 @@ func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Resu
 		return ctrl.Result{}, err
 	}
 	if mc.DeletionTimestamp.IsZero() {
-		klog.V(3).InfoS("The member cluster is not being deleted, ignore it", "memberCluster", mcObjRef)
-		return ctrl.Result{}, nil // no need to retry.
+		klog.V(3).InfoS("The member cluster is not being deleted, mirroring its network properties and networking mode", "memberCluster", mcObjRef)
+		return ctrl.Result{}, r.mirrorNetworkProperties(ctx, &mc)
+	}
+
+	// Withdraw the imports of the leaving member cluster, so that it is no longer counted as an importer of the
+	// services; the InternalServiceImport controller withdraws each import as its InternalServiceImport is deleted.
+	if err := r.deleteInternalServiceImports(ctx, &mc); err != nil {
+		return ctrl.Result{}, err
 	}
 
 	// Handle deleting member cluster, removes finalizers on all the resources in the cluster namespace
@@ -76,10 +104,118 @@ func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Resu
 	return ctrl.Result{RequeueAfter: r.ForceDeleteWaitTime - time.Since(mc.DeletionTimestamp.Time)}, nil
 }
 
+// mirrorNetworkProperties copies the network properties annotations of the internal member cluster onto the member
+// cluster, and the networking mode label of the member cluster onto the internal member cluster.
+func (r *Reconciler) mirrorNetworkProperties(ctx context.Context, mc *clusterv1beta1.MemberCluster) error {
+	mcObjRef := klog.KObj(mc)
+	// Only the annotations of the internal member cluster are read; its metadata is cached without its status.
+	imc := &metav1.PartialObjectMetadata{}
+	imc.SetGroupVersionKind(clusterv1beta1.GroupVersion.WithKind("InternalMemberCluster"))
+	imcKey := types.NamespacedName{Namespace: fmt.Sprintf(hubconfig.HubNamespaceNameFormat, mc.Name), Name: mc.Name}
+	if err := r.Client.Get(ctx, imcKey, imc); err != nil {
+		if errors.IsNotFound(err) {
+			klog.V(4).InfoS("Internal member cluster has not been created yet", "memberCluster", mcObjRef)
+			return nil
+		}
+		klog.ErrorS(err, "Failed to get internal member cluster", "memberCluster", mcObjRef, "internalMemberCluster", imcKey)
+		return err
+	}
+	if err := r.mirrorNetworkingMode(ctx, mc, imc); err != nil {
+		return err
+	}
+
+	patch := client.MergeFrom(mc.DeepCopy())
+	changed := false
+	for _, key := range networkPropertiesAnnotations {
+		want, found := imc.GetAnnotations()[key]
+		got, exists := mc.GetAnnotations()[key]
+		if found == exists && want == got {
+			continue
+		}
+		changed = true
+		annotations := mc.GetAnnotations()
+		if !found {
+			delete(annotations, key)
+			continue
+		}
+		if annotations == nil {
+			annotations = map[string]string{}
+		}
+		annotations[key] = want
+		mc.SetAnnotations(annotations)
+	}
+	if !changed {
+		return nil
+	}
+	if err := r.Client.Patch(ctx, mc, patch); err != nil {
+		klog.ErrorS(err, "Failed to mirror the network properties", "memberCluster", mcObjRef)
+		return err
+	}
+	klog.V(2).InfoS("Mirrored the network properties", "memberCluster", mcObjRef)
+	return nil
+}
+
+// mirrorNetworkingMode copies the networking mode label of the member cluster onto the internal member cluster, from
+// which the member agent reads it; the label is removed from the internal member cluster when the member cluster
+// does not have it.
+func (r *Reconciler) mirrorNetworkingMode(ctx context.Context, mc *clusterv1beta1.MemberCluster, imc *metav1.PartialObjectMetadata) error {
+	want, found := mc.GetLabels()[objectmeta.MemberClusterLabelNetworkingMode]
+	got, exists := imc.GetLabels()[objectmeta.MemberClusterLabelNetworkingMode]
+	if found == exists && want == got {
+		return nil
+	}
+
+	// The type meta of the metadata-only object is required to build the patch request, but it may have been cleared
+	// when the object was read.
+	imc.SetGroupVersionKind(clusterv1beta1.GroupVersion.WithKind("InternalMemberCluster"))
+	patch := client.MergeFrom(imc.DeepCopy())
+	labels := imc.GetLabels()
+	if found {
+		if labels == nil {
+			labels = map[string]string{}
+		}
+		labels[objectmeta.MemberClusterLabelNetworkingMode] = want
+	} else {
+		delete(labels, objectmeta.MemberClusterLabelNetworkingMode)
+	}
+	imc.SetLabels(labels)
+	if err := r.Client.Patch(ctx, imc, patch); err != nil {
+		klog.ErrorS(err, "Failed to mirror the networking mode", "memberCluster", klog.KObj(mc), "internalMemberCluster", klog.KObj(imc))
+		return err
+	}
+	klog.V(2).InfoS("Mirrored the networking mode", "memberCluster", klog.KObj(mc), "internalMemberCluster", klog.KObj(imc), "mode", want)
+	return nil
+}
+
+// deleteInternalServiceImports deletes the InternalServiceImports in the namespace of a leaving member cluster.
+func (r *Reconciler) deleteInternalServiceImports(ctx context.Context, mc *clusterv1beta1.MemberCluster) error {
+	mcObjRef := klog.KObj(mc)
+	mcNamespace := fmt.Sprintf(hubconfig.HubNamespaceNameFormat, mc.Name)
+	var internalServiceImportList fleetnetv1alpha1.InternalServiceImportList
+	if err := r.Client.List(ctx, &internalServiceImportList, client.InNamespace(mcNamespace)); err != nil {
+		klog.ErrorS(err, "Failed to list internalServiceImports", "memberCluster", mcObjRef)
+		return err
+	}
+	for i := range internalServiceImportList.Items {
+		isi := &internalServiceImportList.Items[i]
+		if isi.DeletionTimestamp != nil {
+			continue
+		}
+		isiObjRef := klog.KObj(isi)
+		if err := r.Client.Delete(ctx, isi); err != nil && !errors.IsNotFound(err) {
+			klog.ErrorS(err, "Failed to delete internalServiceImport", "memberCluster", mcObjRef, "internalServiceImport", isiObjRef)
+			return err
+		}
+		klog.V(2).InfoS("Deleted internalServiceImport of the leaving member cluster", "memberCluster", mcObjRef, "internalServiceImport", isiObjRef)
+	}
+	return nil
+}
+
 // removeFinalizer removes finalizers on the resources in the member cluster namespace.
-// For EndpointSliceExport, InternalServiceImport & InternalServiceExport resources, the finalizers should be
-// removed by other hub networking controllers when leaving. So this MemberCluster controller only handles
-// EndpointSliceImports here.
+// For EndpointSliceExport & InternalServiceExport resources, the finalizers should be removed by other hub
+// networking controllers when leaving. So this MemberCluster controller only handles EndpointSliceImports and
+// InternalServiceImports here; the ServiceImport controller drops the importers whose namespaces are being
+// deleted, in case their imports are not withdrawn.
 func (r *Reconciler) removeFinalizer(ctx context.Context, mc clusterv1beta1.MemberCluster) (ctrl.Result, error) {
 	// Remove finalizer for EndpointSliceImport resources in the cluster namespace.
 	mcObjRef := klog.KRef(mc.Namespace, mc.Name)
@@ -89,6 +225,13 @@ func (r *Reconciler) removeFinalizer(ctx context.Context, mc clusterv1beta1.Memb
 		klog.ErrorS(err, "Failed to list endpointSliceImports", "memberCluster", mcObjRef)
 		return ctrl.Result{}, err
 	}
+	// Remove finalizer for InternalServiceImport resources in the cluster namespace as well, whose imports have not
+	// been withdrawn in time.
+	var internalServiceImportList fleetnetv1alpha1.InternalServiceImportList
+	if err := r.Client.List(ctx, &internalServiceImportList, client.InNamespace(mcNamespace)); err != nil {
+		klog.ErrorS(err, "Failed to list internalServiceImports", "memberCluster", mcObjRef)
+		return ctrl.Result{}, err
+	}
 	errs, ctx := errgroup.WithContext(ctx)
 	for i := range endpointSliceImportList.Items {
 		esi := &endpointSliceImportList.Items[i]
@@ -105,6 +248,25 @@ func (r *Reconciler) removeFinalizer(ctx context.Context, mc clusterv1beta1.Memb
 			return nil
 		})
 	}
+
+	for i := range internalServiceImportList.Items {
+		isi := &internalServiceImportList.Items[i]
+		if len(isi.GetFinalizers()) == 0 {
+			continue
+		}
+		errs.Go(func() error {
+			isiObjRef := klog.KObj(isi)
+			isi.SetFinalizers(nil)
+			if err := r.Client.Update(ctx, isi); err != nil {
+				klog.ErrorS(err, "Failed to remove finalizers for internalServiceImport",
+					"memberCluster", mcObjRef, "internalServiceImport", isiObjRef)
+				return err
+			}
+			klog.V(2).InfoS("Removed finalizers for internalServiceImport",
+				"memberCluster", mcObjRef, "internalServiceImport", isiObjRef)
+			return nil
+		})
+	}
 	return ctrl.Result{}, errs.Wait()
 }
 
@@ -120,13 +282,35 @@ func (r *Reconciler) SetupWithManager(mgr ctrl.Manager) error {
 			return true
 		},
 		UpdateFunc: func(e event.UpdateEvent) bool {
-			// If new object is being deleted, trigger reconcile.
-			return !e.ObjectNew.GetDeletionTimestamp().IsZero()
+			// If new object is being deleted, or its networking mode has changed, trigger reconcile.
+			return !e.ObjectNew.GetDeletionTimestamp().IsZero() ||
+				e.ObjectOld.GetLabels()[objectmeta.MemberClusterLabelNetworkingMode] != e.ObjectNew.GetLabels()[objectmeta.MemberClusterLabelNetworkingMode]
+		},
+	}
+	networkPropertiesPredicate := predicate.Funcs{
+		UpdateFunc: func(e event.UpdateEvent) bool {
+			for _, key := range networkPropertiesAnnotations {
+				if e.ObjectOld.GetAnnotations()[key] != e.ObjectNew.GetAnnotations()[key] {
+					return true
+				}
+			}
+			// Restore the networking mode label if it is changed on the internal member cluster.
+			return e.ObjectOld.GetLabels()[objectmeta.MemberClusterLabelNetworkingMode] != e.ObjectNew.GetLabels()[objectmeta.MemberClusterLabelNetworkingMode]
+		},
+		DeleteFunc: func(e event.DeleteEvent) bool {
+			return false
 		},
 	}
-	// Watch for changes to primary resource MemberCluster
+	// Watch for changes to primary resource MemberCluster, and for the network properties published on the
+	// InternalMemberCluster, which shares the name of its MemberCluster; the creation of an InternalMemberCluster
+	// triggers the mirroring of the networking mode as well.
 	return ctrl.NewControllerManagedBy(mgr).
-		For(&clusterv1beta1.MemberCluster{}).
-		WithEventFilter(customPredicate).
-		Complete(r)
+		For(&clusterv1beta1.MemberCluster{}, builder.WithPredicates(customPredicate)).
+		Watches(&clusterv1beta1.InternalMemberCluster{},
+			handler.EnqueueRequestsFromMapFunc(func(_ context.Context, o client.Object) []reconcile.Request {
+				return []reconcile.Request{{NamespacedName: types.NamespacedName{Name: o.GetName()}}}
+			}),
+			builder.WithPredicates(networkPropertiesPredicate),
+			builder.OnlyMetadata).
+		Complete(reconcilewatchdog.Wrap("membercluster", r))
 }
@@ -8,6 +8,8 @@ import (
 	"time"
 
 	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
@@ -18,6 +20,8 @@ import (
 	clusterv1beta1 "go.goms.io/fleet/apis/cluster/v1beta1"
 
 	fleetnetv1alpha1 "go.goms.io/fleet-networking/api/v1alpha1"
+	"go.goms.io/fleet-networking/pkg/common/hubconfig"
+	"go.goms.io/fleet-networking/pkg/common/objectmeta"
 )
 
 const (
@@ -181,6 +185,274 @@ func TestRemoveFinalizer(t *testing.T) {
 	}
 }
 
+func TestDeleteInternalServiceImports(t *testing.T) {
+	mcNamespace := fmt.Sprintf(hubconfig.HubNamespaceNameFormat, testMemberClusterName)
+	otherNamespace := fmt.Sprintf(hubconfig.HubNamespaceNameFormat, "other-mc")
+	mc := &clusterv1beta1.MemberCluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: testMemberClusterName,
+		},
+	}
+	importedISI := &fleetnetv1alpha1.InternalServiceImport{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace:  mcNamespace,
+			Name:       "work-imported",
+			Finalizers: []string{"networking.fleet.azure.com/internalsvcimport-cleanup"},
+		},
+	}
+	pendingISI := &fleetnetv1alpha1.InternalServiceImport{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: mcNamespace,
+			Name:      "work-pending",
+		},
+	}
+	otherISI := &fleetnetv1alpha1.InternalServiceImport{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: otherNamespace,
+			Name:      "work-imported",
+		},
+	}
+	fakeClient := fake.NewClientBuilder().
+		WithScheme(testScheme(t)).
+		WithObjects(importedISI, pendingISI, otherISI).
+		Build()
+	r := Reconciler{
+		Client: fakeClient,
+	}
+
+	ctx := context.Background()
+	if err := r.deleteInternalServiceImports(ctx, mc); err != nil {
+		t.Fatalf("deleteInternalServiceImports() = %v, want no error", err)
+	}
+
+	// The import withdrawal is left to the InternalServiceImport controller, which removes the finalizer.
+	got := &fleetnetv1alpha1.InternalServiceImport{}
+	if err := fakeClient.Get(ctx, client.ObjectKeyFromObject(importedISI), got); err != nil {
+		t.Fatalf("Get(%v) = %v, want no error", client.ObjectKeyFromObject(importedISI), err)
+	}
+	if got.DeletionTimestamp == nil {
+		t.Errorf("internalServiceImport %v is not being deleted, want being deleted", client.ObjectKeyFromObject(importedISI))
+	}
+	if err := fakeClient.Get(ctx, client.ObjectKeyFromObject(pendingISI), got); !apierrors.IsNotFound(err) {
+		t.Errorf("Get(%v) = %v, want not found", client.ObjectKeyFromObject(pendingISI), err)
+	}
+	if err := fakeClient.Get(ctx, client.ObjectKeyFromObject(otherISI), got); err != nil {
+		t.Fatalf("Get(%v) = %v, want no error", client.ObjectKeyFromObject(otherISI), err)
+	}
+	if got.DeletionTimestamp != nil {
+		t.Errorf("internalServiceImport %v of another member cluster is being deleted, want not deleted", client.ObjectKeyFromObject(otherISI))
+	}
+}
+
+func TestRemoveFinalizer_InternalServiceImports(t *testing.T) {
+	mcNamespace := fmt.Sprintf(hubconfig.HubNamespaceNameFormat, testMemberClusterName)
+	mc := clusterv1beta1.MemberCluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: testMemberClusterName,
+		},
+	}
+	isi := &fleetnetv1alpha1.InternalServiceImport{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace:  mcNamespace,
+			Name:       "work-imported",
+			Finalizers: []string{"networking.fleet.azure.com/internalsvcimport-cleanup"},
+		},
+	}
+	fakeClient := fake.NewClientBuilder().
+		WithScheme(testScheme(t)).
+		WithObjects(isi).
+		Build()
+	r := Reconciler{
+		Client: fakeClient,
+	}
+
+	ctx := context.Background()
+	if _, err := r.removeFinalizer(ctx, mc); err != nil {
+		t.Fatalf("removeFinalizer() = %v, want no error", err)
+	}
+	got := &fleetnetv1alpha1.InternalServiceImport{}
+	if err := fakeClient.Get(ctx, client.ObjectKeyFromObject(isi), got); err != nil {
+		t.Fatalf("Get(%v) = %v, want no error", client.ObjectKeyFromObject(isi), err)
+	}
+	if len(got.Finalizers) != 0 {
+		t.Errorf("internalServiceImport finalizers = %v, want none", got.Finalizers)
+	}
+}
+
+func TestMirrorNetworkProperties(t *testing.T) {
+	imcNamespace := fmt.Sprintf(hubconfig.HubNamespaceNameFormat, testMemberClusterName)
+	testCases := []struct {
+		name              string
+		memberCluster     *clusterv1beta1.MemberCluster
+		imc               *clusterv1beta1.InternalMemberCluster
+		wantMCAnnotations map[string]string
+	}{
+		{
+			name: "internal member cluster is not found",
+			memberCluster: &clusterv1beta1.MemberCluster{
+				ObjectMeta: metav1.ObjectMeta{Name: testMemberClusterName},
+			},
+		},
+		{
+			name: "network properties are published",
+			memberCluster: &clusterv1beta1.MemberCluster{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:        testMemberClusterName,
+					Annotations: map[string]string{"other": "value"},
+				},
+			},
+			imc: &clusterv1beta1.InternalMemberCluster{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      testMemberClusterName,
+					Namespace: imcNamespace,
+					Annotations: map[string]string{
+						objectmeta.MemberClusterAnnotationRegion: "westus",
+						objectmeta.MemberClusterAnnotationVNetID: "vnet-id",
+						"ignored":                                "value",
+					},
+				},
+			},
+			wantMCAnnotations: map[string]string{
+				"other":                                  "value",
+				objectmeta.MemberClusterAnnotationRegion: "westus",
+				objectmeta.MemberClusterAnnotationVNetID: "vnet-id",
+			},
+		},
+		{
+			name: "network properties are updated and removed",
+			memberCluster: &clusterv1beta1.MemberCluster{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: testMemberClusterName,
+					Annotations: map[string]string{
+						objectmeta.MemberClusterAnnotationRegion: "westus",
+						objectmeta.MemberClusterAnnotationVNetID: "vnet-id",
+					},
+				},
+			},
+			imc: &clusterv1beta1.InternalMemberCluster{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      testMemberClusterName,
+					Namespace: imcNamespace,
+					Annotations: map[string]string{
+						objectmeta.MemberClusterAnnotationRegion: "eastus",
+					},
+				},
+			},
+			wantMCAnnotations: map[string]string{
+				objectmeta.MemberClusterAnnotationRegion: "eastus",
+			},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			objects := []client.Object{tc.memberCluster}
+			if tc.imc != nil {
+				objects = append(objects, tc.imc)
+			}
+			fakeClient := fake.NewClientBuilder().
+				WithScheme(testScheme(t)).
+				WithObjects(objects...).
+				Build()
+			r := Reconciler{
+				Client:              fakeClient,
+				ForceDeleteWaitTime: forceDeleteWaitTime,
+			}
+			ctx := context.Background()
+			if _, err := r.Reconcile(ctx, ctrl.Request{NamespacedName: types.NamespacedName{Name: testMemberClusterName}}); err != nil {
+				t.Fatalf("Reconcile() got error %v, want no error", err)
+			}
+
+			var got clusterv1beta1.MemberCluster
+			if err := fakeClient.Get(ctx, types.NamespacedName{Name: testMemberClusterName}, &got); err != nil {
+				t.Fatalf("Get() memberCluster got error %v, want no error", err)
+			}
+			want := tc.wantMCAnnotations
+			if want == nil {
+				want = tc.memberCluster.GetAnnotations()
+			}
+			if diff := cmp.Diff(want, got.GetAnnotations(), cmpopts.EquateEmpty()); diff != "" {
+				t.Errorf("memberCluster annotations mismatch (-want, +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestMirrorNetworkingMode(t *testing.T) {
+	imcNamespace := fmt.Sprintf(hubconfig.HubNamespaceNameFormat, testMemberClusterName)
+	testCases := []struct {
+		name          string
+		mcLabels      map[string]string
+		imcLabels     map[string]string
+		wantIMCLabels map[string]string
+	}{
+		{
+			name:     "networking mode is mirrored",
+			mcLabels: map[string]string{objectmeta.MemberClusterLabelNetworkingMode: "export-only"},
+			imcLabels: map[string]string{
+				"other": "value",
+			},
+			wantIMCLabels: map[string]string{
+				"other": "value",
+				objectmeta.MemberClusterLabelNetworkingMode: "export-only",
+			},
+		},
+		{
+			name:          "networking mode is updated",
+			mcLabels:      map[string]string{objectmeta.MemberClusterLabelNetworkingMode: "import-only"},
+			imcLabels:     map[string]string{objectmeta.MemberClusterLabelNetworkingMode: "export-only"},
+			wantIMCLabels: map[string]string{objectmeta.MemberClusterLabelNetworkingMode: "import-only"},
+		},
+		{
+			name: "networking mode is removed",
+			imcLabels: map[string]string{
+				"other": "value",
+				objectmeta.MemberClusterLabelNetworkingMode: "export-only",
+			},
+			wantIMCLabels: map[string]string{"other": "value"},
+		},
+		{
+			name: "no networking mode",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			mc := &clusterv1beta1.MemberCluster{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:   testMemberClusterName,
+					Labels: tc.mcLabels,
+				},
+			}
+			imc := &clusterv1beta1.InternalMemberCluster{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      testMemberClusterName,
+					Namespace: imcNamespace,
+					Labels:    tc.imcLabels,
+				},
+			}
+			fakeClient := fake.NewClientBuilder().
+				WithScheme(testScheme(t)).
+				WithObjects(mc, imc).
+				Build()
+			r := Reconciler{
+				Client:              fakeClient,
+				ForceDeleteWaitTime: forceDeleteWaitTime,
+			}
+			ctx := context.Background()
+			if _, err := r.Reconcile(ctx, ctrl.Request{NamespacedName: types.NamespacedName{Name: testMemberClusterName}}); err != nil {
+				t.Fatalf("Reconcile() got error %v, want no error", err)
+			}
+
+			var got clusterv1beta1.InternalMemberCluster
+			if err := fakeClient.Get(ctx, client.ObjectKeyFromObject(imc), &got); err != nil {
+				t.Fatalf("Get() internalMemberCluster got error %v, want no error", err)
+			}
+			if diff := cmp.Diff(tc.wantIMCLabels, got.GetLabels(), cmpopts.EquateEmpty()); diff != "" {
+				t.Errorf("internalMemberCluster labels mismatch (-want, +got):\n%s", diff)
+			}
+		})
+	}
+}
+
 func testScheme(t *testing.T) *runtime.Scheme {
 	scheme := runtime.NewScheme()
 	if err := clusterv1beta1.AddToScheme(scheme); err != nil {
@@ -152,6 +152,91 @@ var _ = Describe("Test MemberCluster Controller", func() {
 			}, eventuallyTimeout, eventuallyInterval).Should(BeTrue())
 		})
 	})
+
+	Context("Test MemberCluster controller, handle leaving member cluster with imports", func() {
+		const leavingMemberClusterName = "member-2"
+		leavingMemberNS := fmt.Sprintf(hubconfig.HubNamespaceNameFormat, leavingMemberClusterName)
+		internalServiceImportKey := types.NamespacedName{Namespace: leavingMemberNS, Name: "work-test-service"}
+
+		BeforeEach(func() {
+			mc := clusterv1beta1.MemberCluster{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:       leavingMemberClusterName,
+					Finalizers: []string{"test-member-cluster-cleanup-finalizer"},
+				},
+				Spec: clusterv1beta1.MemberClusterSpec{
+					Identity: rbacv1.Subject{
+						Name:      "test-subject",
+						Kind:      "ServiceAccount",
+						Namespace: "fleet-system",
+						APIGroup:  "",
+					},
+				},
+			}
+			Expect(hubClient.Create(ctx, &mc)).Should(Succeed())
+
+			memberNS := corev1.Namespace{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: leavingMemberNS,
+				},
+			}
+			Expect(hubClient.Create(ctx, &memberNS)).Should(Succeed())
+
+			// Create the InternalServiceImport of an active import.
+			isi := fleetnetv1alpha1.InternalServiceImport{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: internalServiceImportKey.Namespace,
+					Name:      internalServiceImportKey.Name,
+				},
+				Spec: fleetnetv1alpha1.InternalServiceImportSpec{
+					ServiceImportReference: fleetnetv1alpha1.ExportedObjectReference{
+						ClusterID:       leavingMemberClusterName,
+						Kind:            "ServiceImport",
+						Namespace:       "work",
+						Name:            "test-service",
+						ResourceVersion: "0",
+						Generation:      1,
+						UID:             "00000000-0000-0000-0000-000000000000",
+						NamespacedName:  "work/test-service",
+						ExportedSince:   metav1.NewTime(time.Now().Round(time.Second)),
+					},
+				},
+			}
+			Expect(hubClient.Create(ctx, &isi)).Should(Succeed())
+		})
+
+		It("should delete the InternalServiceImports in the fleet member namespace before the force delete wait time", func() {
+			var mc clusterv1beta1.MemberCluster
+			Expect(hubClient.Get(ctx, types.NamespacedName{Name: leavingMemberClusterName}, &mc)).Should(Succeed())
+			Expect(hubClient.Delete(ctx, &mc)).Should(Succeed())
+
+			// The force delete wait time is 1 minute for this IT; the imports are withdrawn well before it.
+			Eventually(func() bool {
+				var isi fleetnetv1alpha1.InternalServiceImport
+				return apierrors.IsNotFound(hubClient.Get(ctx, internalServiceImportKey, &isi))
+			}, 30*time.Second, time.Second).Should(BeTrue())
+		})
+
+		AfterEach(func() {
+			ns := corev1.Namespace{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: leavingMemberNS,
+				},
+			}
+			Expect(hubClient.Delete(ctx, &ns)).Should(Succeed())
+			var mc clusterv1beta1.MemberCluster
+			Eventually(func() error {
+				if err := hubClient.Get(ctx, types.NamespacedName{Name: leavingMemberClusterName}, &mc); err != nil {
+					return err
+				}
+				mc.SetFinalizers(nil)
+				return hubClient.Update(ctx, &mc)
+			}, eventuallyTimeout, eventuallyInterval).Should(Succeed())
+			Eventually(func() bool {
+				return apierrors.IsNotFound(hubClient.Get(ctx, types.NamespacedName{Name: leavingMemberClusterName}, &mc))
+			}, eventuallyTimeout, eventuallyInterval).Should(BeTrue())
+		})
+	})
 })
 
 func buildEndpointSliceImport(name string) *fleetnetv1alpha1.EndpointSliceImport {
@@ -9,28 +9,73 @@ package serviceimport
 
 import (
 	"context"
+	"encoding/json"
+	"sort"
+	"strings"
 	"time"
 
+	"github.com/prometheus/client_golang/prometheus"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/equality"
 	"k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/tools/record"
 	"k8s.io/klog/v2"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	ctrlmetrics "sigs.k8s.io/controller-runtime/pkg/metrics"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	fleetnetv1alpha1 "go.goms.io/fleet-networking/api/v1alpha1"
+	fleetnetv1beta1 "go.goms.io/fleet-networking/api/v1beta1"
 	"go.goms.io/fleet-networking/pkg/common/apiretry"
 	"go.goms.io/fleet-networking/pkg/common/condition"
+	"go.goms.io/fleet-networking/pkg/common/consumerpolicy"
+	"go.goms.io/fleet-networking/pkg/common/exportconflict"
+	"go.goms.io/fleet-networking/pkg/common/exporthealth"
+	"go.goms.io/fleet-networking/pkg/common/exportweight"
+	"go.goms.io/fleet-networking/pkg/common/listguard"
+	"go.goms.io/fleet-networking/pkg/common/metrics"
 	"go.goms.io/fleet-networking/pkg/common/objectmeta"
+	"go.goms.io/fleet-networking/pkg/common/reconcilewatchdog"
 )
 
+var (
+	// serviceImporterCount reports the number of member clusters importing each serviceImport, so that the exported
+	// workloads can be scaled to zero where nobody consumes the service.
+	serviceImporterCount = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: metrics.MetricsNamespace,
+			Subsystem: metrics.MetricsSubsystem,
+			Name:      "service_importer_count",
+			Help:      "The number of member clusters importing the service",
+		},
+		[]string{"namespace", "name"},
+	)
+)
+
+func init() {
+	// Register serviceImporterCount (fleet_networking_service_importer_count) metric with the controller runtime global
+	// metrics registry.
+	ctrlmetrics.Registry.MustRegister(serviceImporterCount)
+}
+
 const (
 	// fields name used to filter resources
 	exportedServiceFieldNamespacedName = ".spec.serviceReference.namespacedName"
+	// trafficManagerBackendServiceImportFieldKey indexes all the trafficManagerBackends by the serviceImports they
+	// refer to; unlike the index of the trafficManagerBackend controller, it is not limited to a shard.
+	trafficManagerBackendServiceImportFieldKey = ".spec.backend.name/all-shards"
+
+	// clusterSetDomain is the domain of the clusterset DNS names of the multi-cluster services.
+	clusterSetDomain = "svc.clusterset.local"
 
 	// ControllerName is the name of the Reconciler.
 	ControllerName = "serviceimport-controller"
@@ -40,6 +85,11 @@ const (
 type Reconciler struct {
 	client.Client
 	Recorder record.EventRecorder
+
+	// EnableTrafficManagerFeature, if set, adds the FQDNs of the programmed trafficManagerProfiles a serviceImport is
+	// the backend of to its DNS names; the trafficManagerBackends and trafficManagerProfiles are watched only then, as
+	// their APIs may not be installed otherwise.
+	EnableTrafficManagerFeature bool
 }
 
 // statusChange stores the internalServiceExports list whose status needs to be updated.
@@ -53,7 +103,10 @@ type statusChange struct {
 //+kubebuilder:rbac:groups=networking.fleet.azure.com,resources=serviceimports/finalizers,verbs=update
 //+kubebuilder:rbac:groups=networking.fleet.azure.com,resources=internalserviceexports,verbs=get;watch;list
 //+kubebuilder:rbac:groups=networking.fleet.azure.com,resources=internalserviceexports/status,verbs=get;update;patch
+//+kubebuilder:rbac:groups=networking.fleet.azure.com,resources=trafficmanagerbackends,verbs=get;list;watch
+//+kubebuilder:rbac:groups=networking.fleet.azure.com,resources=trafficmanagerprofiles,verbs=get;list;watch
 //+kubebuilder:rbac:groups="",resources=events,verbs=create;patch
+//+kubebuilder:rbac:groups="",resources=namespaces,verbs=get;list;watch
 
 // Reconcile resolves the service spec when the serviceImport status is empty and updates the status of internalServiceExports.
 func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
@@ -68,15 +121,21 @@ func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Resu
 	if err := r.Client.Get(ctx, req.NamespacedName, &serviceImport); err != nil {
 		if errors.IsNotFound(err) {
 			klog.V(4).InfoS("Ignoring NotFound serviceImport", "serviceImport", serviceImportKRef)
+			serviceImporterCount.DeleteLabelValues(req.Namespace, req.Name)
 			return ctrl.Result{}, nil
 		}
 		klog.ErrorS(err, "Failed to get serviceImport", "serviceImport", serviceImportKRef)
 		return ctrl.Result{}, err
 	}
+	// Drop the importers which have left the fleet; the update triggers another reconciliation, if the serviceImport
+	// still exists.
+	if pruned, err := r.pruneImportersOfRemovedMembers(ctx, &serviceImport); err != nil || pruned {
+		return ctrl.Result{}, err
+	}
 	// If the spec has already present, no need to resolve the service spec.
 	if len(serviceImport.Status.Clusters) != 0 {
 		klog.V(4).InfoS("Already resolved the service spec and skipping", "serviceImport", serviceImportKRef)
-		return ctrl.Result{}, nil
+		return ctrl.Result{}, r.refreshStatus(ctx, &serviceImport)
 	}
 
 	internalServiceExportList := &fleetnetv1alpha1.InternalServiceExportList{}
@@ -88,6 +147,7 @@ func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Resu
 		klog.ErrorS(err, "Failed to list internalServiceExports used by the serviceImport", "serviceImport", serviceImportKRef)
 		return ctrl.Result{}, err
 	}
+	listguard.ObserveListSize(internalServiceExportList, "serviceImport", serviceImportKRef)
 	if len(internalServiceExportList.Items) == 0 {
 		klog.V(2).InfoS("No internalServiceExport found and deleting serviceImport", "serviceImport", serviceImportKRef)
 		return r.deleteServiceImport(ctx, &serviceImport)
@@ -98,28 +158,29 @@ func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Resu
 	}
 
 	var resolvedPortsSpec *[]fleetnetv1alpha1.ServicePort
+	var resolvedIPFamilies []corev1.IPFamily
 	for i := range internalServiceExportList.Items {
-		v := internalServiceExportList.Items[i]
+		v := &internalServiceExportList.Items[i]
 		if v.DeletionTimestamp != nil { // skip if the resource is in the deleting state
-			klog.V(4).InfoS("Skipping the internalServiceExport which is in the deleting state", serviceImport, serviceImportKRef, "internalServiceExport", klog.KObj(&v))
+			klog.V(4).InfoS("Skipping the internalServiceExport which is in the deleting state", serviceImport, serviceImportKRef, "internalServiceExport", klog.KObj(v))
 			continue
 		}
 		// skip if the resource is just added which has not been handled by the internalServiceExport controller yet
-		if !controllerutil.ContainsFinalizer(&v, objectmeta.InternalServiceExportFinalizer) {
-			klog.V(3).InfoS("Skipping the internalServiceExport because of missing finalizer", "serviceImport", serviceImportKRef, "internalServiceExport", klog.KObj(&v))
+		if !controllerutil.ContainsFinalizer(v, objectmeta.InternalServiceExportFinalizer) {
+			klog.V(3).InfoS("Skipping the internalServiceExport because of missing finalizer", "serviceImport", serviceImportKRef, "internalServiceExport", klog.KObj(v))
 			continue
 		}
 
 		if resolvedPortsSpec == nil {
 			// pick the first internalServiceExport spec
 			resolvedPortsSpec = &v.Spec.Ports
+			resolvedIPFamilies = v.Spec.AddressFamilies
 		}
-		// TODO: ideally we should ignore the order when comparing the serviceImports; port and protocol are the key.
-		if !equality.Semantic.DeepEqual(*resolvedPortsSpec, v.Spec.Ports) {
-			change.conflict = append(change.conflict, &v)
+		if !exportconflict.IsSpecResolved(*resolvedPortsSpec, resolvedIPFamilies, &v.Spec) {
+			change.conflict = append(change.conflict, v)
 			continue
 		}
-		change.noConflict = append(change.noConflict, &v)
+		change.noConflict = append(change.noConflict, v)
 	}
 
 	if resolvedPortsSpec == nil {
@@ -133,6 +194,10 @@ func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Resu
 
 	// To reduce reconcile failure, we'll keep retry until it succeeds.
 	clusters := make([]fleetnetv1alpha1.ClusterStatus, 0, len(change.noConflict))
+	var status fleetnetv1alpha1.ServiceImportStatus
+	resolvedType := fleetnetv1alpha1.ClusterSetIP
+	// A cluster may export multiple services under the same name.
+	addedClusters := make(map[string]bool, len(change.noConflict))
 	for _, v := range change.noConflict {
 		klog.V(3).InfoS("Marking internalServiceExport status as nonConflict", "serviceImport", serviceImportKRef, "internalServiceExport", klog.KObj(v))
 		if err := r.updateInternalServiceExportWithRetry(ctx, v, false); err != nil {
@@ -141,7 +206,22 @@ func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Resu
 			}
 			return ctrl.Result{}, err
 		}
-		clusters = append(clusters, fleetnetv1alpha1.ClusterStatus{Cluster: v.Spec.ServiceReference.ClusterID})
+		if addedClusters[v.Spec.ServiceReference.ClusterID] {
+			continue
+		}
+		addedClusters[v.Spec.ServiceReference.ClusterID] = true
+		if len(clusters) == 0 {
+			// The type follows the first accepted export, from which the spec is resolved.
+			resolvedType = serviceImportType(v)
+		}
+		clusters = append(clusters, fleetnetv1alpha1.ClusterStatus{
+			Cluster: v.Spec.ServiceReference.ClusterID,
+			Region:  v.Spec.ClusterRegion,
+			VNetID:  v.Spec.ClusterVNetID,
+		})
+		consumerpolicy.Set(&status, v.Spec.ServiceReference.ClusterID, v.Spec.ConsumerPolicy)
+		exportweight.Set(&status, v.Spec.ServiceReference.ClusterID, v.Spec.Weight)
+		exporthealth.Set(&status, v.Spec.ServiceReference.ClusterID, v.Spec.Health)
 	}
 	if len(clusters) == 0 {
 		// At that time, all of internalServiceExports has been deleted.
@@ -155,11 +235,24 @@ func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Resu
 			return ctrl.Result{}, client.IgnoreNotFound(err)
 		}
 	}
+	dnsNames, err := r.buildDNSNames(ctx, &serviceImport)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
 	serviceImport.Status = fleetnetv1alpha1.ServiceImportStatus{
-		Ports:    *resolvedPortsSpec,
-		Clusters: clusters,
-		Type:     fleetnetv1alpha1.ClusterSetIP, // may support headless in the future
+		Ports:      *resolvedPortsSpec,
+		IPFamilies: resolvedIPFamilies,
+		Clusters:   clusters,
+		Type:       resolvedType,
+		// The conflict conditions of the internalServiceExports have been updated in place above.
+		MissingClusters:  buildMissingClusters(expectedExporters(&serviceImport), clusters, internalServiceExportList.Items),
+		ConsumerPolicies: status.ConsumerPolicies,
+		ClusterWeights:   status.ClusterWeights,
+		ClusterHealth:    status.ClusterHealth,
+		DNSNames:         dnsNames,
+		ImporterClusters: importerClusters(&serviceImport),
 	}
+	serviceImporterCount.WithLabelValues(serviceImport.Namespace, serviceImport.Name).Set(float64(len(serviceImport.Status.ImporterClusters)))
 	updateFunc := func() error {
 		return r.Status().Update(ctx, &serviceImport)
 	}
@@ -172,6 +265,211 @@ func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Resu
 	return ctrl.Result{}, nil
 }
 
+// refreshStatus recomputes the clusters which are expected to export the service but are missing from the resolved
+// serviceImport, as well as its type, DNS names and importers, and updates the serviceImport status when they change;
+// the type of the serviceImports resolved before it was derived from the exports is backfilled as well. The
+// number of importers of the serviceImport is refreshed in the status of its internalServiceExports, along with the
+// resolution of the serviceImport, and in the serviceImporterCount metric as well.
+func (r *Reconciler) refreshStatus(ctx context.Context, serviceImport *fleetnetv1alpha1.ServiceImport) error {
+	serviceImportKObj := klog.KObj(serviceImport)
+	internalServiceExportList := &fleetnetv1alpha1.InternalServiceExportList{}
+	listOpts := client.MatchingFields{
+		exportedServiceFieldNamespacedName: types.NamespacedName{Namespace: serviceImport.Namespace, Name: serviceImport.Name}.String(),
+	}
+	if err := r.Client.List(ctx, internalServiceExportList, &listOpts); err != nil {
+		klog.ErrorS(err, "Failed to list internalServiceExports used by the serviceImport", "serviceImport", serviceImportKObj)
+		return err
+	}
+	listguard.ObserveListSize(internalServiceExportList, "serviceImport", serviceImportKObj)
+	importers := importerClusters(serviceImport)
+	serviceImporterCount.WithLabelValues(serviceImport.Namespace, serviceImport.Name).Set(float64(len(importers)))
+	if err := r.updateActiveImporters(ctx, serviceImport, importers, internalServiceExportList.Items); err != nil {
+		return err
+	}
+	missingClusters := buildMissingClusters(expectedExporters(serviceImport), serviceImport.Status.Clusters, internalServiceExportList.Items)
+	dnsNames, err := r.buildDNSNames(ctx, serviceImport)
+	if err != nil {
+		return err
+	}
+	importType := resolvedServiceImportType(serviceImport, internalServiceExportList.Items)
+	if importType == serviceImport.Status.Type &&
+		equality.Semantic.DeepEqual(missingClusters, serviceImport.Status.MissingClusters) &&
+		equality.Semantic.DeepEqual(dnsNames, serviceImport.Status.DNSNames) &&
+		equality.Semantic.DeepEqual(importers, serviceImport.Status.ImporterClusters) {
+		return nil
+	}
+
+	serviceImport.Status.Type = importType
+	serviceImport.Status.MissingClusters = missingClusters
+	serviceImport.Status.DNSNames = dnsNames
+	serviceImport.Status.ImporterClusters = importers
+	klog.V(2).InfoS("Updating the type, missing clusters, DNS names and importers of the serviceImport", "serviceImport", serviceImportKObj, "type", importType, "missingClusters", missingClusters, "dnsNames", dnsNames, "importerClusters", importers)
+	if err := r.Client.Status().Update(ctx, serviceImport); err != nil {
+		klog.ErrorS(err, "Failed to update the type, missing clusters, DNS names and importers of the serviceImport", "serviceImport", serviceImportKObj)
+		return err
+	}
+	return nil
+}
+
+// buildDNSNames returns the DNS names of the serviceImport: its clusterset name, followed by the sorted FQDNs of the
+// programmed trafficManagerProfiles of the trafficManagerBackends referring to it, if the traffic manager feature is
+// enabled.
+func (r *Reconciler) buildDNSNames(ctx context.Context, serviceImport *fleetnetv1alpha1.ServiceImport) ([]string, error) {
+	res := []string{clusterSetDNSName(serviceImport)}
+	if !r.EnableTrafficManagerFeature {
+		return res, nil
+	}
+
+	serviceImportKObj := klog.KObj(serviceImport)
+	backendList := &fleetnetv1beta1.TrafficManagerBackendList{}
+	listOpts := []client.ListOption{
+		// ServiceImport and TrafficManagerBackend should be in the same namespace.
+		client.InNamespace(serviceImport.Namespace),
+		client.MatchingFields{trafficManagerBackendServiceImportFieldKey: serviceImport.Name},
+		// Only the profile names of the backends are read, so that they are not deep-copied out of the cache.
+		client.UnsafeDisableDeepCopy,
+	}
+	if err := r.Client.List(ctx, backendList, listOpts...); err != nil {
+		klog.ErrorS(err, "Failed to list trafficManagerBackends referring to the serviceImport", "serviceImport", serviceImportKObj)
+		return nil, err
+	}
+	listguard.ObserveListSize(backendList, "serviceImport", serviceImportKObj)
+
+	fqdns := make(map[string]bool, len(backendList.Items))
+	for i := range backendList.Items {
+		profileKey := types.NamespacedName{Namespace: serviceImport.Namespace, Name: backendList.Items[i].Spec.Profile.Name}
+		profile := &fleetnetv1beta1.TrafficManagerProfile{}
+		if err := r.Client.Get(ctx, profileKey, profile); err != nil {
+			if errors.IsNotFound(err) {
+				continue
+			}
+			klog.ErrorS(err, "Failed to get trafficManagerProfile", "serviceImport", serviceImportKObj, "trafficManagerProfile", profileKey)
+			return nil, err
+		}
+		if !isProfileProgrammed(profile) || profile.Status.DNSName == nil || *profile.Status.DNSName == "" {
+			continue
+		}
+		fqdns[*profile.Status.DNSName] = true
+	}
+	sorted := make([]string, 0, len(fqdns))
+	for fqdn := range fqdns {
+		sorted = append(sorted, fqdn)
+	}
+	sort.Strings(sorted)
+	return append(res, sorted...), nil
+}
+
+// clusterSetDNSName returns the clusterset DNS name of the serviceImport.
+func clusterSetDNSName(serviceImport *fleetnetv1alpha1.ServiceImport) string {
+	return serviceImport.Name + "." + serviceImport.Namespace + "." + clusterSetDomain
+}
+
+// isProfileProgrammed returns true if the Programmed condition of the current generation of the trafficManagerProfile
+// is true.
+func isProfileProgrammed(profile *fleetnetv1beta1.TrafficManagerProfile) bool {
+	cond := meta.FindStatusCondition(profile.Status.Conditions, string(fleetnetv1beta1.TrafficManagerProfileConditionProgrammed))
+	return cond != nil && cond.Status == metav1.ConditionTrue && cond.ObservedGeneration == profile.Generation
+}
+
+// expectedExporters returns the sorted list of clusters specified in the expected-exporters annotation of the
+// serviceImport.
+func expectedExporters(serviceImport *fleetnetv1alpha1.ServiceImport) []string {
+	value, ok := serviceImport.GetAnnotations()[objectmeta.ServiceImportAnnotationExpectedExporters]
+	if !ok {
+		return nil
+	}
+	clusters := make(map[string]bool)
+	for _, cluster := range strings.Split(value, ",") {
+		if cluster = strings.TrimSpace(cluster); cluster != "" {
+			clusters[cluster] = true
+		}
+	}
+	res := make([]string, 0, len(clusters))
+	for cluster := range clusters {
+		res = append(res, cluster)
+	}
+	sort.Strings(res)
+	return res
+}
+
+// buildMissingClusters returns the expected clusters which are not in the given cluster list, together with the
+// reason why each of them is missing, derived from the internalServiceExports of the service.
+func buildMissingClusters(expected []string, clusters []fleetnetv1alpha1.ClusterStatus, internalServiceExports []fleetnetv1alpha1.InternalServiceExport) []fleetnetv1alpha1.MissingClusterStatus {
+	if len(expected) == 0 {
+		return nil
+	}
+	exported := make(map[string]bool, len(clusters))
+	for _, c := range clusters {
+		exported[c.Cluster] = true
+	}
+	reasons := make(map[string]fleetnetv1alpha1.MissingClusterReason)
+	for i := range internalServiceExports {
+		v := &internalServiceExports[i]
+		clusterID := v.Spec.ServiceReference.ClusterID
+		if reasons[clusterID] == fleetnetv1alpha1.MissingClusterReasonExportConflicted {
+			continue
+		}
+		switch {
+		case v.DeletionTimestamp != nil:
+			if _, ok := reasons[clusterID]; !ok {
+				reasons[clusterID] = fleetnetv1alpha1.MissingClusterReasonExportPending
+			}
+		case meta.IsStatusConditionTrue(v.Status.Conditions, string(fleetnetv1alpha1.ServiceExportConflict)):
+			reasons[clusterID] = fleetnetv1alpha1.MissingClusterReasonExportConflicted
+		case len(v.Spec.Ports) == 0:
+			// An export without any port is never accepted; report it as invalid rather than pending forever.
+			reasons[clusterID] = fleetnetv1alpha1.MissingClusterReasonExportInvalid
+		default:
+			if _, ok := reasons[clusterID]; !ok {
+				reasons[clusterID] = fleetnetv1alpha1.MissingClusterReasonExportPending
+			}
+		}
+	}
+
+	var res []fleetnetv1alpha1.MissingClusterStatus
+	for _, cluster := range expected {
+		if exported[cluster] {
+			continue
+		}
+		reason, ok := reasons[cluster]
+		if !ok {
+			reason = fleetnetv1alpha1.MissingClusterReasonExportNotFound
+		}
+		res = append(res, fleetnetv1alpha1.MissingClusterStatus{Cluster: cluster, Reason: reason})
+	}
+	return res
+}
+
+// serviceImportType returns the type of the serviceImport resolved from the internalServiceExport.
+func serviceImportType(internalServiceExport *fleetnetv1alpha1.InternalServiceExport) fleetnetv1alpha1.ServiceImportType {
+	if internalServiceExport.Spec.Headless {
+		return fleetnetv1alpha1.Headless
+	}
+	return fleetnetv1alpha1.ClusterSetIP
+}
+
+// resolvedServiceImportType returns the type of the serviceImport resolved from the first accepted
+// internalServiceExport of its clusters; it returns the current type, or ClusterSetIP if none is set, when no such
+// internalServiceExport is found.
+func resolvedServiceImportType(serviceImport *fleetnetv1alpha1.ServiceImport, internalServiceExports []fleetnetv1alpha1.InternalServiceExport) fleetnetv1alpha1.ServiceImportType {
+	exported := make(map[string]bool, len(serviceImport.Status.Clusters))
+	for _, c := range serviceImport.Status.Clusters {
+		exported[c.Cluster] = true
+	}
+	for i := range internalServiceExports {
+		v := &internalServiceExports[i]
+		if v.DeletionTimestamp != nil || !exported[v.Spec.ServiceReference.ClusterID] ||
+			meta.IsStatusConditionTrue(v.Status.Conditions, string(fleetnetv1alpha1.ServiceExportConflict)) {
+			continue
+		}
+		return serviceImportType(v)
+	}
+	if serviceImport.Status.Type == "" {
+		return fleetnetv1alpha1.ClusterSetIP
+	}
+	return serviceImport.Status.Type
+}
+
 func (r *Reconciler) updateInternalServiceExportWithRetry(ctx context.Context, internalServiceExport *fleetnetv1alpha1.InternalServiceExport, conflict bool) error {
 	desiredCond := condition.UnconflictedServiceExportConflictCondition(*internalServiceExport)
 	if conflict {
@@ -194,6 +492,117 @@ func (r *Reconciler) updateInternalServiceExportWithRetry(ctx context.Context, i
 	return nil
 }
 
+// importerClusters returns the sorted IDs of the member clusters importing a serviceImport, as recorded in its
+// ServiceInUseBy annotation.
+func importerClusters(serviceImport *fleetnetv1alpha1.ServiceImport) []string {
+	data, ok := serviceImport.Annotations[objectmeta.ServiceImportAnnotationServiceInUseBy]
+	if !ok {
+		return nil
+	}
+	svcInUseBy := &fleetnetv1alpha1.ServiceInUseBy{}
+	if err := json.Unmarshal([]byte(data), svcInUseBy); err != nil {
+		// The internalServiceImport controller overwrites the corrupted data on the next import.
+		klog.ErrorS(err, "Failed to unmarshal ServiceInUseBy data", "serviceImport", klog.KObj(serviceImport), "data", data)
+		return nil
+	}
+	seen := make(map[fleetnetv1alpha1.ClusterID]bool, len(svcInUseBy.MemberClusters))
+	var res []string
+	for _, clusterID := range svcInUseBy.MemberClusters {
+		if !seen[clusterID] {
+			seen[clusterID] = true
+			res = append(res, string(clusterID))
+		}
+	}
+	sort.Strings(res)
+	return res
+}
+
+// updateActiveImporters reports the number of importers of a serviceImport in the status of its
+// internalServiceExports, from which the member clusters mirror it to their serviceExports, e.g. to protect the
+// serviceExports still in use from being deleted. The resolution of the serviceImport, importers included, is reported
+// as well, so that the member clusters can preview the outcome of a change before exporting it.
+func (r *Reconciler) updateActiveImporters(ctx context.Context, serviceImport *fleetnetv1alpha1.ServiceImport, importers []string, internalServiceExports []fleetnetv1alpha1.InternalServiceExport) error {
+	count := int32(len(importers))
+	resolution := exportconflict.ResolutionOf(serviceImport, importers)
+	for i := range internalServiceExports {
+		internalServiceExport := &internalServiceExports[i]
+		if internalServiceExport.DeletionTimestamp != nil ||
+			(internalServiceExport.Status.ActiveImporterCount == count && equality.Semantic.DeepEqual(internalServiceExport.Status.Resolution, resolution)) {
+			continue
+		}
+		exportKObj := klog.KObj(internalServiceExport)
+		klog.V(2).InfoS("Updating the active importers of the internalServiceExport", "serviceImport", klog.KObj(serviceImport), "internalServiceExport", exportKObj, "activeImporterCount", count, "oldActiveImporterCount", internalServiceExport.Status.ActiveImporterCount)
+		internalServiceExport.Status.ActiveImporterCount = count
+		internalServiceExport.Status.Resolution = resolution.DeepCopy()
+		updateFunc := func() error {
+			return r.Client.Status().Update(ctx, internalServiceExport)
+		}
+		if err := apiretry.Do(updateFunc); err != nil && !errors.IsNotFound(err) {
+			klog.ErrorS(err, "Failed to update the active importer count of the internalServiceExport with retry", "internalServiceExport", exportKObj)
+			return err
+		}
+	}
+	return nil
+}
+
+// pruneImportersOfRemovedMembers removes the member clusters whose namespaces no longer exist, or are being deleted,
+// from the importers recorded on a serviceImport. A member cluster which leaves the fleet without its imports being
+// withdrawn would otherwise be counted as an importer forever, and its cleanup finalizer would block the
+// serviceImport from being deleted after the last exporter is gone. Once no importer is left, the cleanup finalizer
+// is removed as well. It returns true if the serviceImport has been updated.
+func (r *Reconciler) pruneImportersOfRemovedMembers(ctx context.Context, serviceImport *fleetnetv1alpha1.ServiceImport) (bool, error) {
+	serviceImportKObj := klog.KObj(serviceImport)
+	data, ok := serviceImport.Annotations[objectmeta.ServiceImportAnnotationServiceInUseBy]
+	if !ok {
+		return false, nil
+	}
+	svcInUseBy := &fleetnetv1alpha1.ServiceInUseBy{}
+	if err := json.Unmarshal([]byte(data), svcInUseBy); err != nil {
+		// The internalServiceImport controller overwrites the corrupted data on the next import.
+		klog.ErrorS(err, "Failed to unmarshal ServiceInUseBy data", "serviceImport", serviceImportKObj, "data", data)
+		return false, nil
+	}
+
+	var removed []string
+	for clusterNamespace := range svcInUseBy.MemberClusters {
+		ns := &metav1.PartialObjectMetadata{}
+		ns.SetGroupVersionKind(corev1.SchemeGroupVersion.WithKind("Namespace"))
+		err := r.Client.Get(ctx, types.NamespacedName{Name: string(clusterNamespace)}, ns)
+		switch {
+		case errors.IsNotFound(err):
+		case err != nil:
+			klog.ErrorS(err, "Failed to get the namespace of the importing member cluster", "serviceImport", serviceImportKObj, "namespace", clusterNamespace)
+			return false, err
+		case ns.DeletionTimestamp == nil:
+			continue
+		}
+		delete(svcInUseBy.MemberClusters, clusterNamespace)
+		removed = append(removed, string(clusterNamespace))
+	}
+	if len(removed) == 0 {
+		return false, nil
+	}
+
+	if len(svcInUseBy.MemberClusters) == 0 {
+		delete(serviceImport.Annotations, objectmeta.ServiceImportAnnotationServiceInUseBy)
+		controllerutil.RemoveFinalizer(serviceImport, objectmeta.ServiceImportCleanupFinalizer)
+	} else {
+		updated, err := json.Marshal(svcInUseBy)
+		if err != nil {
+			return false, err
+		}
+		serviceImport.Annotations[objectmeta.ServiceImportAnnotationServiceInUseBy] = string(updated)
+	}
+	sort.Strings(removed)
+	klog.V(2).InfoS("Removing the importers whose member cluster namespaces no longer exist", "serviceImport", serviceImportKObj, "namespaces", removed)
+	if err := r.Client.Update(ctx, serviceImport); err != nil {
+		klog.ErrorS(err, "Failed to remove the importers of the removed member clusters", "serviceImport", serviceImportKObj)
+		return false, client.IgnoreNotFound(err)
+	}
+	r.Recorder.Eventf(serviceImport, corev1.EventTypeNormal, "StaleImportersRemoved", "Removed the importers of the member clusters which have left the fleet: %s", strings.Join(removed, ", "))
+	return true, nil
+}
+
 func (r *Reconciler) deleteServiceImport(ctx context.Context, serviceImport *fleetnetv1alpha1.ServiceImport) (ctrl.Result, error) {
 	r.Recorder.Eventf(serviceImport, corev1.EventTypeNormal, "NoExportedService", "No exported service and deleting serviceImport %s", serviceImport.Name)
 
@@ -218,7 +627,126 @@ func (r *Reconciler) SetupWithManager(ctx context.Context, mgr ctrl.Manager) err
 		return err
 	}
 
-	return ctrl.NewControllerManagedBy(mgr).
+	b := ctrl.NewControllerManagedBy(mgr).
 		For(&fleetnetv1alpha1.ServiceImport{}).
-		Complete(r)
+		// Watch for the internalServiceExports so that the missing clusters of the serviceImports which expect
+		// exporters are refreshed when an export is added, removed or marked as conflicted.
+		Watches(&fleetnetv1alpha1.InternalServiceExport{}, handler.EnqueueRequestsFromMapFunc(r.internalServiceExportHandler)).
+		// Watch for the namespaces being deleted, so that the importers of the member clusters leaving the fleet
+		// are removed from the serviceImports.
+		// Only the deletion of the namespaces matters; their metadata is enough.
+		Watches(&corev1.Namespace{}, handler.EnqueueRequestsFromMapFunc(r.namespaceHandler), builder.WithPredicates(namespaceDeletionPredicate()), builder.OnlyMetadata)
+	if r.EnableTrafficManagerFeature {
+		backendIndexerFunc := func(o client.Object) []string {
+			backend, ok := o.(*fleetnetv1beta1.TrafficManagerBackend)
+			if !ok {
+				return []string{}
+			}
+			return []string{backend.Spec.Backend.Name}
+		}
+		if err := mgr.GetFieldIndexer().IndexField(ctx, &fleetnetv1beta1.TrafficManagerBackend{}, trafficManagerBackendServiceImportFieldKey, backendIndexerFunc); err != nil {
+			klog.ErrorS(err, "Failed to create index", "field", trafficManagerBackendServiceImportFieldKey)
+			return err
+		}
+		// Watch for the trafficManagerBackends and trafficManagerProfiles, so that the DNS names of the serviceImports
+		// are refreshed when they are attached to or detached from a profile, or a profile is (un)programmed.
+		b = b.Watches(&fleetnetv1beta1.TrafficManagerBackend{}, handler.EnqueueRequestsFromMapFunc(trafficManagerBackendHandler)).
+			Watches(&fleetnetv1beta1.TrafficManagerProfile{}, handler.EnqueueRequestsFromMapFunc(r.trafficManagerProfileHandler))
+	}
+	return b.Complete(reconcilewatchdog.Wrap("serviceimport", r))
+}
+
+// trafficManagerBackendHandler enqueues the serviceImport a trafficManagerBackend refers to; for the updates, both
+// the old and the new serviceImports are enqueued.
+func trafficManagerBackendHandler(_ context.Context, object client.Object) []reconcile.Request {
+	backend, ok := object.(*fleetnetv1beta1.TrafficManagerBackend)
+	if !ok {
+		return nil
+	}
+	return []reconcile.Request{{NamespacedName: types.NamespacedName{Namespace: backend.Namespace, Name: backend.Spec.Backend.Name}}}
+}
+
+// trafficManagerProfileHandler enqueues the serviceImports referred to by the trafficManagerBackends of a
+// trafficManagerProfile.
+func (r *Reconciler) trafficManagerProfileHandler(ctx context.Context, object client.Object) []reconcile.Request {
+	backendList := &fleetnetv1beta1.TrafficManagerBackendList{}
+	// Only the names of the backends are read, so that they are not deep-copied out of the cache.
+	if err := r.Client.List(ctx, backendList, client.InNamespace(object.GetNamespace()), client.UnsafeDisableDeepCopy); err != nil {
+		klog.ErrorS(err, "Failed to list trafficManagerBackends", "trafficManagerProfile", klog.KObj(object))
+		return nil
+	}
+	listguard.ObserveListSize(backendList, "trafficManagerProfile", klog.KObj(object))
+	var reqs []reconcile.Request
+	for i := range backendList.Items {
+		backend := &backendList.Items[i]
+		if backend.Spec.Profile.Name != object.GetName() {
+			continue
+		}
+		reqs = append(reqs, reconcile.Request{NamespacedName: types.NamespacedName{Namespace: backend.Namespace, Name: backend.Spec.Backend.Name}})
+	}
+	return reqs
+}
+
+// namespaceDeletionPredicate filters the namespace events down to the namespaces being deleted.
+func namespaceDeletionPredicate() predicate.Funcs {
+	return predicate.Funcs{
+		CreateFunc: func(_ event.CreateEvent) bool {
+			return false
+		},
+		UpdateFunc: func(e event.UpdateEvent) bool {
+			return e.ObjectOld.GetDeletionTimestamp() == nil && e.ObjectNew.GetDeletionTimestamp() != nil
+		},
+		DeleteFunc: func(_ event.DeleteEvent) bool {
+			return true
+		},
+		GenericFunc: func(_ event.GenericEvent) bool {
+			return false
+		},
+	}
+}
+
+// namespaceHandler enqueues the serviceImports imported by the member cluster of a namespace.
+func (r *Reconciler) namespaceHandler(ctx context.Context, object client.Object) []reconcile.Request {
+	serviceImportList := &fleetnetv1alpha1.ServiceImportList{}
+	if err := r.Client.List(ctx, serviceImportList); err != nil {
+		klog.ErrorS(err, "Failed to list serviceImports", "namespace", klog.KObj(object))
+		return nil
+	}
+	var reqs []reconcile.Request
+	for i := range serviceImportList.Items {
+		serviceImport := &serviceImportList.Items[i]
+		data, ok := serviceImport.Annotations[objectmeta.ServiceImportAnnotationServiceInUseBy]
+		if !ok {
+			continue
+		}
+		svcInUseBy := &fleetnetv1alpha1.ServiceInUseBy{}
+		if err := json.Unmarshal([]byte(data), svcInUseBy); err != nil {
+			continue
+		}
+		if _, ok := svcInUseBy.MemberClusters[fleetnetv1alpha1.ClusterNamespace(object.GetName())]; ok {
+			reqs = append(reqs, reconcile.Request{NamespacedName: types.NamespacedName{Namespace: serviceImport.Namespace, Name: serviceImport.Name}})
+		}
+	}
+	return reqs
+}
+
+func (r *Reconciler) internalServiceExportHandler(ctx context.Context, object client.Object) []reconcile.Request {
+	internalServiceExport, ok := object.(*fleetnetv1alpha1.InternalServiceExport)
+	if !ok {
+		return nil
+	}
+	serviceImportKey := types.NamespacedName{
+		Namespace: internalServiceExport.Spec.ServiceReference.Namespace,
+		Name:      internalServiceExport.Spec.ServiceReference.Name,
+	}
+	serviceImport := &fleetnetv1alpha1.ServiceImport{}
+	if err := r.Client.Get(ctx, serviceImportKey, serviceImport); err != nil {
+		return nil
+	}
+	// Other serviceImports are resolved by their own events, unless the type of the export differs from theirs.
+	_, ok = serviceImport.GetAnnotations()[objectmeta.ServiceImportAnnotationExpectedExporters]
+	if !ok && (len(serviceImport.Status.Clusters) == 0 || serviceImportType(internalServiceExport) == serviceImport.Status.Type) {
+		return nil
+	}
+	return []reconcile.Request{{NamespacedName: serviceImportKey}}
 }
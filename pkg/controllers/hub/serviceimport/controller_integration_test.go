@@ -13,15 +13,20 @@ import (
 	"github.com/google/go-cmp/cmp/cmpopts"
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/utils/ptr"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 
 	fleetnetv1alpha1 "go.goms.io/fleet-networking/api/v1alpha1"
+	fleetnetv1beta1 "go.goms.io/fleet-networking/api/v1beta1"
 	"go.goms.io/fleet-networking/pkg/common/objectmeta"
+	"go.goms.io/fleet-networking/test/common/fleetenv"
 )
 
 func unconflictedServiceExportConflictCondition(svcNamespace string, svcName string) metav1.Condition {
@@ -109,6 +114,8 @@ var _ = Describe("Test ServiceImport Controller", func() {
 			cmpopts.IgnoreFields(metav1.Condition{}, "LastTransitionTime"),
 			cmpopts.IgnoreFields(metav1.ObjectMeta{}, "ManagedFields"),
 		}
+
+		clusterSetDNSNames = []string{testServiceName + "." + testNamespace + ".svc.clusterset.local"}
 	)
 
 	Context("ServiceImport has empty ports spec", func() {
@@ -122,7 +129,7 @@ var _ = Describe("Test ServiceImport Controller", func() {
 			internalServiceExportA = &fleetnetv1alpha1.InternalServiceExport{
 				ObjectMeta: metav1.ObjectMeta{
 					Name:      testNamespace + "-" + testServiceName,
-					Namespace: testMemberClusterA,
+					Namespace: memberA.Namespace,
 				},
 				Spec: internalServiceExportSpec,
 			}
@@ -130,7 +137,7 @@ var _ = Describe("Test ServiceImport Controller", func() {
 			internalServiceExportB = &fleetnetv1alpha1.InternalServiceExport{
 				ObjectMeta: metav1.ObjectMeta{
 					Name:      testNamespace + "-" + testServiceName,
-					Namespace: testMemberClusterB,
+					Namespace: memberB.Namespace,
 				},
 				Spec: fleetnetv1alpha1.InternalServiceExportSpec{
 					Ports: []fleetnetv1alpha1.ServicePort{
@@ -159,7 +166,7 @@ var _ = Describe("Test ServiceImport Controller", func() {
 			internalServiceExportC = &fleetnetv1alpha1.InternalServiceExport{
 				ObjectMeta: metav1.ObjectMeta{
 					Name:      testNamespace + "-othersvc",
-					Namespace: testMemberClusterA,
+					Namespace: memberA.Namespace,
 				},
 				Spec: fleetnetv1alpha1.InternalServiceExportSpec{
 					Ports: importServicePorts,
@@ -180,7 +187,7 @@ var _ = Describe("Test ServiceImport Controller", func() {
 			internalServiceExportAA = &fleetnetv1alpha1.InternalServiceExport{
 				ObjectMeta: metav1.ObjectMeta{
 					Name:      testNamespace + "-" + testServiceName,
-					Namespace: testMemberClusterAA,
+					Namespace: memberAA.Namespace,
 				},
 				Spec: fleetnetv1alpha1.InternalServiceExportSpec{
 					Ports: importServicePorts,
@@ -276,8 +283,9 @@ var _ = Describe("Test ServiceImport Controller", func() {
 							Cluster: testClusterID,
 						},
 					},
-					Type:  fleetnetv1alpha1.ClusterSetIP,
-					Ports: internalServiceExportA.Spec.Ports,
+					Type:     fleetnetv1alpha1.ClusterSetIP,
+					Ports:    internalServiceExportA.Spec.Ports,
+					DNSNames: clusterSetDNSNames,
 				}
 				if len(serviceImport.Status.Clusters) != 1 {
 					return fmt.Sprintf("got %v cluster, want 1", len(serviceImport.Status.Clusters))
@@ -290,13 +298,20 @@ var _ = Describe("Test ServiceImport Controller", func() {
 								Cluster: "member-cluster-b",
 							},
 						},
-						Type:  fleetnetv1alpha1.ClusterSetIP,
-						Ports: internalServiceExportB.Spec.Ports,
+						Type:     fleetnetv1alpha1.ClusterSetIP,
+						Ports:    internalServiceExportB.Spec.Ports,
+						DNSNames: clusterSetDNSNames,
 					}
 				}
 				return cmp.Diff(want, serviceImport.Status, options...)
 			}, timeout, interval).Should(BeEmpty())
 
+			// The resolution of the serviceImport is reported to all its exports, conflicted or not.
+			wantResolution := &fleetnetv1alpha1.ExportResolution{
+				Ports:             serviceImport.Status.Ports,
+				ExportingClusters: []string{resolvedClusterID},
+			}
+
 			By("Checking internalServiceExportA condition")
 			Eventually(func() string {
 				key := types.NamespacedName{
@@ -314,6 +329,7 @@ var _ = Describe("Test ServiceImport Controller", func() {
 						Conditions: []metav1.Condition{
 							unconflictedServiceExportConflictCondition(testNamespace, testServiceName),
 						},
+						Resolution: wantResolution,
 					},
 				}
 				if resolvedClusterID != testClusterID {
@@ -360,6 +376,7 @@ var _ = Describe("Test ServiceImport Controller", func() {
 						Conditions: []metav1.Condition{
 							conflictedServiceExportConflictCondition(testNamespace, testServiceName),
 						},
+						Resolution: wantResolution,
 					},
 				}
 				if resolvedClusterID != testClusterID {
@@ -431,7 +448,8 @@ var _ = Describe("Test ServiceImport Controller", func() {
 							Cluster: testClusterID,
 						},
 					},
-					Type: fleetnetv1alpha1.ClusterSetIP,
+					Type:     fleetnetv1alpha1.ClusterSetIP,
+					DNSNames: clusterSetDNSNames,
 				}
 				return cmp.Diff(want, serviceImport.Status, options...)
 			}, timeout, interval).Should(BeEmpty())
@@ -453,6 +471,10 @@ var _ = Describe("Test ServiceImport Controller", func() {
 						Conditions: []metav1.Condition{
 							unconflictedServiceExportConflictCondition(testNamespace, testServiceName),
 						},
+						Resolution: &fleetnetv1alpha1.ExportResolution{
+							Ports:             internalServiceExportSpec.Ports,
+							ExportingClusters: []string{testClusterID},
+						},
 					},
 				}
 				return cmp.Diff(want, got, options...)
@@ -486,6 +508,213 @@ var _ = Describe("Test ServiceImport Controller", func() {
 		})
 	})
 
+	Context("ServiceImport expects exporters", func() {
+		const expectedSvcName = "expected-svc"
+		expectedServiceImportKey := types.NamespacedName{Namespace: testNamespace, Name: expectedSvcName}
+		var serviceImport *fleetnetv1alpha1.ServiceImport
+		var internalServiceExports []*fleetnetv1alpha1.InternalServiceExport
+
+		newInternalServiceExport := func(namespace, clusterID string, ports []fleetnetv1alpha1.ServicePort) *fleetnetv1alpha1.InternalServiceExport {
+			internalServiceExport := &fleetnetv1alpha1.InternalServiceExport{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      testNamespace + "-" + expectedSvcName + "-" + clusterID,
+					Namespace: namespace,
+				},
+				Spec: fleetnetv1alpha1.InternalServiceExportSpec{
+					Ports: ports,
+					ServiceReference: fleetnetv1alpha1.ExportedObjectReference{
+						ClusterID:       clusterID,
+						Kind:            "Service",
+						Namespace:       testNamespace,
+						Name:            expectedSvcName,
+						ResourceVersion: "0",
+						Generation:      0,
+						UID:             "0",
+						NamespacedName:  testNamespace + "/" + expectedSvcName,
+						ExportedSince:   exportedSince,
+					},
+				},
+			}
+			controllerutil.AddFinalizer(internalServiceExport, objectmeta.InternalServiceExportFinalizer)
+			return internalServiceExport
+		}
+
+		BeforeEach(func() {
+			internalServiceExports = []*fleetnetv1alpha1.InternalServiceExport{
+				newInternalServiceExport(memberA.Namespace, "cluster-1", importServicePorts),
+				newInternalServiceExport(memberB.Namespace, "cluster-2", importServicePorts),
+				newInternalServiceExport(memberAA.Namespace, "cluster-3", importServicePorts),
+			}
+			for _, v := range internalServiceExports {
+				By(fmt.Sprintf("Creating internalServiceExport %s", v.Spec.ServiceReference.ClusterID))
+				Expect(k8sClient.Create(ctx, v)).Should(Succeed())
+			}
+		})
+
+		AfterEach(func() {
+			By("Deleting serviceImport if exists")
+			Expect(client.IgnoreNotFound(k8sClient.Delete(ctx, serviceImport))).Should(Succeed())
+
+			By("Deleting internalServiceExports if exist")
+			for _, v := range internalServiceExports {
+				Eventually(func() error {
+					return client.IgnoreNotFound(deleteInternalServiceExport(v))
+				}, timeout, interval).Should(Succeed())
+			}
+		})
+
+		It("Should report the missing clusters with reasons", func() {
+			By("Creating serviceImport which expects five exporters")
+			serviceImport = &fleetnetv1alpha1.ServiceImport{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      expectedSvcName,
+					Namespace: testNamespace,
+					Annotations: map[string]string{
+						objectmeta.ServiceImportAnnotationExpectedExporters: "cluster-1,cluster-2,cluster-3,cluster-4,cluster-5",
+					},
+				},
+			}
+			Expect(k8sClient.Create(ctx, serviceImport)).Should(Succeed())
+
+			By("Checking serviceImport")
+			Eventually(func() string {
+				if err := k8sClient.Get(ctx, expectedServiceImportKey, serviceImport); err != nil {
+					return err.Error()
+				}
+				want := fleetnetv1alpha1.ServiceImportStatus{
+					Clusters: []fleetnetv1alpha1.ClusterStatus{
+						{Cluster: "cluster-1"},
+						{Cluster: "cluster-2"},
+						{Cluster: "cluster-3"},
+					},
+					Type:  fleetnetv1alpha1.ClusterSetIP,
+					Ports: importServicePorts,
+					MissingClusters: []fleetnetv1alpha1.MissingClusterStatus{
+						{Cluster: "cluster-4", Reason: fleetnetv1alpha1.MissingClusterReasonExportNotFound},
+						{Cluster: "cluster-5", Reason: fleetnetv1alpha1.MissingClusterReasonExportNotFound},
+					},
+					DNSNames: []string{expectedSvcName + "." + testNamespace + ".svc.clusterset.local"},
+				}
+				return cmp.Diff(want, serviceImport.Status, append(options, cmpopts.SortSlices(func(a, b fleetnetv1alpha1.ClusterStatus) bool {
+					return a.Cluster < b.Cluster
+				}))...)
+			}, timeout, interval).Should(BeEmpty())
+
+			By("Creating a conflicted internalServiceExport from cluster-4")
+			conflicted := newInternalServiceExport(memberA.Namespace, "cluster-4", importServicePorts[:1])
+			internalServiceExports = append(internalServiceExports, conflicted)
+			Expect(k8sClient.Create(ctx, conflicted)).Should(Succeed())
+			// The controller may update the status of the new export in the meantime.
+			Eventually(func() error {
+				if err := k8sClient.Get(ctx, client.ObjectKeyFromObject(conflicted), conflicted); err != nil {
+					return err
+				}
+				conflicted.Status.Conditions = []metav1.Condition{
+					conflictedServiceExportConflictCondition(testNamespace, expectedSvcName),
+				}
+				return k8sClient.Status().Update(ctx, conflicted)
+			}, timeout, interval).Should(Succeed())
+
+			By("Checking the missing clusters of serviceImport")
+			Eventually(func() string {
+				if err := k8sClient.Get(ctx, expectedServiceImportKey, serviceImport); err != nil {
+					return err.Error()
+				}
+				want := []fleetnetv1alpha1.MissingClusterStatus{
+					{Cluster: "cluster-4", Reason: fleetnetv1alpha1.MissingClusterReasonExportConflicted},
+					{Cluster: "cluster-5", Reason: fleetnetv1alpha1.MissingClusterReasonExportNotFound},
+				}
+				return cmp.Diff(want, serviceImport.Status.MissingClusters)
+			}, timeout, interval).Should(BeEmpty())
+
+			By("Creating an invalid internalServiceExport without any port from cluster-5")
+			// A nil list would be sent as null, which the API server rejects.
+			invalid := newInternalServiceExport(memberB.Namespace, "cluster-5", []fleetnetv1alpha1.ServicePort{})
+			internalServiceExports = append(internalServiceExports, invalid)
+			Expect(k8sClient.Create(ctx, invalid)).Should(Succeed())
+
+			By("Checking the missing clusters of serviceImport")
+			Eventually(func() string {
+				if err := k8sClient.Get(ctx, expectedServiceImportKey, serviceImport); err != nil {
+					return err.Error()
+				}
+				want := []fleetnetv1alpha1.MissingClusterStatus{
+					{Cluster: "cluster-4", Reason: fleetnetv1alpha1.MissingClusterReasonExportConflicted},
+					{Cluster: "cluster-5", Reason: fleetnetv1alpha1.MissingClusterReasonExportInvalid},
+				}
+				return cmp.Diff(want, serviceImport.Status.MissingClusters)
+			}, timeout, interval).Should(BeEmpty())
+		})
+		It("Should report the importers and mirror their count to the internalServiceExports", func() {
+			By("Creating serviceImport")
+			serviceImport = &fleetnetv1alpha1.ServiceImport{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      expectedSvcName,
+					Namespace: testNamespace,
+				},
+			}
+			Expect(k8sClient.Create(ctx, serviceImport)).Should(Succeed())
+
+			By("Waiting for the serviceImport to be resolved")
+			Eventually(func() int {
+				if err := k8sClient.Get(ctx, expectedServiceImportKey, serviceImport); err != nil {
+					return 0
+				}
+				return len(serviceImport.Status.Clusters)
+			}, timeout, interval).Should(Equal(len(internalServiceExports)))
+
+			checkImporters := func(want []string) {
+				Eventually(func() string {
+					if err := k8sClient.Get(ctx, expectedServiceImportKey, serviceImport); err != nil {
+						return err.Error()
+					}
+					if diff := cmp.Diff(want, serviceImport.Status.ImporterClusters); diff != "" {
+						return fmt.Sprintf("importerClusters mismatch (-want, +got):\n%s", diff)
+					}
+					for _, v := range internalServiceExports {
+						got := &fleetnetv1alpha1.InternalServiceExport{}
+						if err := k8sClient.Get(ctx, types.NamespacedName{Namespace: v.Namespace, Name: v.Name}, got); err != nil {
+							return err.Error()
+						}
+						if got.Status.ActiveImporterCount != int32(len(want)) {
+							return fmt.Sprintf("internalServiceExport %s/%s activeImporterCount = %d, want %d", v.Namespace, v.Name, got.Status.ActiveImporterCount, len(want))
+						}
+					}
+					if got := testutil.ToFloat64(serviceImporterCount.WithLabelValues(testNamespace, expectedSvcName)); got != float64(len(want)) {
+						return fmt.Sprintf("serviceImporterCount = %v, want %d", got, len(want))
+					}
+					return ""
+				}, timeout, interval).Should(BeEmpty())
+			}
+
+			By("Importing the service into member cluster aa")
+			Eventually(func() error {
+				if err := k8sClient.Get(ctx, expectedServiceImportKey, serviceImport); err != nil {
+					return err
+				}
+				serviceImport.Annotations = map[string]string{
+					objectmeta.ServiceImportAnnotationServiceInUseBy: fmt.Sprintf(`{"MemberClusters":{"%s":"cluster-3"}}`, memberAA.Namespace),
+				}
+				return k8sClient.Update(ctx, serviceImport)
+			}, timeout, interval).Should(Succeed())
+
+			By("Checking the importers")
+			checkImporters([]string{"cluster-3"})
+
+			By("Withdrawing the import")
+			Eventually(func() error {
+				if err := k8sClient.Get(ctx, expectedServiceImportKey, serviceImport); err != nil {
+					return err
+				}
+				delete(serviceImport.Annotations, objectmeta.ServiceImportAnnotationServiceInUseBy)
+				return k8sClient.Update(ctx, serviceImport)
+			}, timeout, interval).Should(Succeed())
+
+			By("Checking the importers")
+			checkImporters(nil)
+		})
+	})
+
 	Context("ServiceImport has empty ports spec", func() {
 		var serviceImport *fleetnetv1alpha1.ServiceImport
 		var internalServiceExport *fleetnetv1alpha1.InternalServiceExport
@@ -493,7 +722,7 @@ var _ = Describe("Test ServiceImport Controller", func() {
 			internalServiceExport = &fleetnetv1alpha1.InternalServiceExport{
 				ObjectMeta: metav1.ObjectMeta{
 					Name:      testNamespace + "-" + testServiceName,
-					Namespace: testMemberClusterA,
+					Namespace: memberA.Namespace,
 				},
 				Spec: internalServiceExportSpec,
 			}
@@ -522,4 +751,353 @@ var _ = Describe("Test ServiceImport Controller", func() {
 			}, timeout, interval).Should(BeTrue())
 		})
 	})
+
+	Context("The importing member cluster leaves the fleet", func() {
+		const (
+			leavingMemberNamespace = "fleet-member-leaving"
+			leavingServiceName     = "leaving-member-svc"
+		)
+		var serviceImport *fleetnetv1alpha1.ServiceImport
+		var internalServiceExport *fleetnetv1alpha1.InternalServiceExport
+		leavingServiceImportKey := types.NamespacedName{Namespace: testNamespace, Name: leavingServiceName}
+
+		BeforeEach(func() {
+			By("Creating the namespace of the importing member cluster")
+			Expect(k8sClient.Create(ctx, &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: leavingMemberNamespace}})).Should(Succeed())
+
+			By("Creating internalServiceExport")
+			internalServiceExport = &fleetnetv1alpha1.InternalServiceExport{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      testNamespace + "-" + leavingServiceName,
+					Namespace: memberA.Namespace,
+				},
+				Spec: fleetnetv1alpha1.InternalServiceExportSpec{
+					Ports: importServicePorts,
+					ServiceReference: fleetnetv1alpha1.ExportedObjectReference{
+						ClusterID:       testClusterID,
+						Kind:            "Service",
+						Namespace:       testNamespace,
+						Name:            leavingServiceName,
+						ResourceVersion: "0",
+						Generation:      0,
+						UID:             "0",
+						NamespacedName:  testNamespace + "/" + leavingServiceName,
+						ExportedSince:   exportedSince,
+					},
+				},
+			}
+			controllerutil.AddFinalizer(internalServiceExport, objectmeta.InternalServiceExportFinalizer)
+			Expect(k8sClient.Create(ctx, internalServiceExport)).Should(Succeed())
+
+			By("Creating serviceImport")
+			serviceImport = &fleetnetv1alpha1.ServiceImport{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      leavingServiceName,
+					Namespace: testNamespace,
+				},
+			}
+			Expect(k8sClient.Create(ctx, serviceImport)).Should(Succeed())
+			Eventually(func() error {
+				if err := k8sClient.Get(ctx, leavingServiceImportKey, serviceImport); err != nil {
+					return err
+				}
+				if len(serviceImport.Status.Clusters) != 1 {
+					return fmt.Errorf("got %d clusters, want 1", len(serviceImport.Status.Clusters))
+				}
+				return nil
+			}, timeout, interval).Should(Succeed())
+
+			By("Importing the service into the member cluster, as done by the internalServiceImport controller")
+			Eventually(func() error {
+				if err := k8sClient.Get(ctx, leavingServiceImportKey, serviceImport); err != nil {
+					return err
+				}
+				serviceImport.Annotations = map[string]string{
+					objectmeta.ServiceImportAnnotationServiceInUseBy: fmt.Sprintf(`{"MemberClusters":{"%s":"leaving"}}`, leavingMemberNamespace),
+				}
+				controllerutil.AddFinalizer(serviceImport, objectmeta.ServiceImportCleanupFinalizer)
+				return k8sClient.Update(ctx, serviceImport)
+			}, timeout, interval).Should(Succeed())
+		})
+
+		AfterEach(func() {
+			By("Deleting serviceImport if exists")
+			Eventually(func() error {
+				if err := k8sClient.Get(ctx, leavingServiceImportKey, serviceImport); err != nil {
+					return client.IgnoreNotFound(err)
+				}
+				serviceImport.Finalizers = nil
+				if err := k8sClient.Update(ctx, serviceImport); err != nil {
+					return err
+				}
+				return client.IgnoreNotFound(k8sClient.Delete(ctx, serviceImport))
+			}, timeout, interval).Should(Succeed())
+
+			By("Deleting internalServiceExport if exists")
+			Eventually(func() error {
+				return client.IgnoreNotFound(deleteInternalServiceExport(internalServiceExport))
+			}, timeout, interval).Should(Succeed())
+		})
+
+		It("should drop the importer, so that the serviceImport unexported by the exporters is deleted", func() {
+			By("Checking the importer is kept while the member cluster is in the fleet")
+			Consistently(func() error {
+				if err := k8sClient.Get(ctx, leavingServiceImportKey, serviceImport); err != nil {
+					return err
+				}
+				if _, ok := serviceImport.Annotations[objectmeta.ServiceImportAnnotationServiceInUseBy]; !ok {
+					return fmt.Errorf("serviceImport has no importer, want the importing member cluster")
+				}
+				return nil
+			}, time.Second*2, interval).Should(Succeed())
+
+			By("Unexporting the service, as done by the internalServiceExport controller")
+			Expect(deleteInternalServiceExport(internalServiceExport)).Should(Succeed())
+			Expect(k8sClient.Delete(ctx, serviceImport)).Should(Succeed())
+
+			By("Checking serviceImport is kept by the import of the member cluster")
+			Consistently(func() error {
+				if err := k8sClient.Get(ctx, leavingServiceImportKey, serviceImport); err != nil {
+					return err
+				}
+				if serviceImport.DeletionTimestamp == nil {
+					return fmt.Errorf("serviceImport is not being deleted")
+				}
+				return nil
+			}, time.Second*2, interval).Should(Succeed())
+
+			By("Deleting the namespace of the importing member cluster")
+			// The namespace controller does not run in the test environment, and the namespace stays terminating.
+			Expect(k8sClient.Delete(ctx, &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: leavingMemberNamespace}})).Should(Succeed())
+
+			By("Checking serviceImport is deleted")
+			Eventually(func() bool {
+				return errors.IsNotFound(k8sClient.Get(ctx, leavingServiceImportKey, serviceImport))
+			}, timeout, interval).Should(BeTrue())
+		})
+	})
+
+	Context("ServiceImport is the backend of a trafficManagerProfile", func() {
+		const (
+			atmServiceName = "atm-svc"
+			profileName    = "atm-profile"
+			backendName    = "atm-backend"
+			profileFQDN    = "atm-profile.trafficmanager.net"
+		)
+		atmServiceImportKey := types.NamespacedName{Namespace: testNamespace, Name: atmServiceName}
+		atmClusterSetDNSName := atmServiceName + "." + testNamespace + ".svc.clusterset.local"
+		var serviceImport *fleetnetv1alpha1.ServiceImport
+		var internalServiceExport *fleetnetv1alpha1.InternalServiceExport
+		var profile *fleetnetv1beta1.TrafficManagerProfile
+		var backend *fleetnetv1beta1.TrafficManagerBackend
+
+		checkDNSNames := func(want []string) {
+			Eventually(func() string {
+				if err := k8sClient.Get(ctx, atmServiceImportKey, serviceImport); err != nil {
+					return err.Error()
+				}
+				return cmp.Diff(want, serviceImport.Status.DNSNames)
+			}, timeout, interval).Should(BeEmpty())
+		}
+
+		BeforeEach(func() {
+			By("Creating internalServiceExport")
+			internalServiceExport = &fleetnetv1alpha1.InternalServiceExport{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      testNamespace + "-" + atmServiceName,
+					Namespace: memberA.Namespace,
+				},
+				Spec: fleetnetv1alpha1.InternalServiceExportSpec{
+					Ports: importServicePorts,
+					ServiceReference: fleetnetv1alpha1.ExportedObjectReference{
+						ClusterID:       testClusterID,
+						Kind:            "Service",
+						Namespace:       testNamespace,
+						Name:            atmServiceName,
+						ResourceVersion: "0",
+						Generation:      0,
+						UID:             "0",
+						NamespacedName:  testNamespace + "/" + atmServiceName,
+						ExportedSince:   exportedSince,
+					},
+				},
+			}
+			controllerutil.AddFinalizer(internalServiceExport, objectmeta.InternalServiceExportFinalizer)
+			Expect(k8sClient.Create(ctx, internalServiceExport)).Should(Succeed())
+
+			By("Creating trafficManagerProfile")
+			profile = &fleetnetv1beta1.TrafficManagerProfile{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      profileName,
+					Namespace: testNamespace,
+				},
+			}
+			Expect(k8sClient.Create(ctx, profile)).Should(Succeed())
+
+			By("Creating serviceImport")
+			serviceImport = &fleetnetv1alpha1.ServiceImport{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      atmServiceName,
+					Namespace: testNamespace,
+				},
+			}
+			Expect(k8sClient.Create(ctx, serviceImport)).Should(Succeed())
+		})
+
+		AfterEach(func() {
+			By("Deleting trafficManagerBackend if exists")
+			Expect(client.IgnoreNotFound(k8sClient.Delete(ctx, backend))).Should(Succeed())
+
+			By("Deleting trafficManagerProfile")
+			Expect(client.IgnoreNotFound(k8sClient.Delete(ctx, profile))).Should(Succeed())
+
+			By("Deleting serviceImport if exists")
+			Expect(client.IgnoreNotFound(k8sClient.Delete(ctx, serviceImport))).Should(Succeed())
+
+			By("Deleting internalServiceExport if exists")
+			Eventually(func() error {
+				return client.IgnoreNotFound(deleteInternalServiceExport(internalServiceExport))
+			}, timeout, interval).Should(Succeed())
+		})
+
+		It("should report the FQDN of the profile while the serviceImport is its backend and the profile is programmed", func() {
+			By("Checking the DNS names of the serviceImport without trafficManagerBackend")
+			checkDNSNames([]string{atmClusterSetDNSName})
+
+			By("Creating trafficManagerBackend")
+			backend = &fleetnetv1beta1.TrafficManagerBackend{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      backendName,
+					Namespace: testNamespace,
+				},
+				Spec: fleetnetv1beta1.TrafficManagerBackendSpec{
+					Profile: fleetnetv1beta1.TrafficManagerProfileRef{Name: profileName},
+					Backend: fleetnetv1beta1.TrafficManagerBackendRef{Name: atmServiceName},
+					Weight:  ptr.To(int64(10)),
+				},
+			}
+			Expect(k8sClient.Create(ctx, backend)).Should(Succeed())
+
+			By("Checking the DNS names of the serviceImport while the profile is not programmed")
+			Consistently(func() string {
+				if err := k8sClient.Get(ctx, atmServiceImportKey, serviceImport); err != nil {
+					return err.Error()
+				}
+				return cmp.Diff([]string{atmClusterSetDNSName}, serviceImport.Status.DNSNames)
+			}, time.Second, interval).Should(BeEmpty())
+
+			By("Programming the trafficManagerProfile, as done by the trafficManagerProfile controller")
+			Expect(k8sClient.Get(ctx, types.NamespacedName{Namespace: testNamespace, Name: profileName}, profile)).Should(Succeed())
+			profile.Status = fleetnetv1beta1.TrafficManagerProfileStatus{
+				DNSName: ptr.To(profileFQDN),
+				Conditions: []metav1.Condition{
+					{
+						Type:               string(fleetnetv1beta1.TrafficManagerProfileConditionProgrammed),
+						Status:             metav1.ConditionTrue,
+						ObservedGeneration: profile.Generation,
+						Reason:             string(fleetnetv1beta1.TrafficManagerProfileReasonProgrammed),
+						LastTransitionTime: metav1.Now(),
+					},
+				},
+			}
+			Expect(k8sClient.Status().Update(ctx, profile)).Should(Succeed())
+
+			By("Checking the DNS names of the serviceImport include the FQDN of the profile")
+			checkDNSNames([]string{atmClusterSetDNSName, profileFQDN})
+
+			By("Deleting trafficManagerBackend")
+			Expect(k8sClient.Delete(ctx, backend)).Should(Succeed())
+
+			By("Checking the FQDN of the profile is removed from the DNS names of the serviceImport")
+			checkDNSNames([]string{atmClusterSetDNSName})
+		})
+	})
+
+	Context("When exporting the service from five member clusters", func() {
+		const fiveMemberServiceName = "five-member-svc"
+		fiveMemberServiceImportKey := types.NamespacedName{Namespace: testNamespace, Name: fiveMemberServiceName}
+		var members []*fleetenv.Member
+		var serviceImport *fleetnetv1alpha1.ServiceImport
+
+		BeforeEach(func() {
+			By("Creating the member clusters")
+			members = fleetenv.CreateSimulatedMembers(ctx, k8sClient, fleetenv.MemberNames("five-member", 5)...)
+
+			By("Exporting the service from the member clusters")
+			svc := &corev1.Service{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: testNamespace,
+					Name:      fiveMemberServiceName,
+				},
+				Spec: corev1.ServiceSpec{
+					Type: corev1.ServiceTypeClusterIP,
+					Ports: []corev1.ServicePort{
+						{
+							Name:        "portA",
+							Protocol:    corev1.ProtocolTCP,
+							Port:        8080,
+							AppProtocol: &appProtocol,
+							TargetPort:  intstr.IntOrString{IntVal: 8080},
+						},
+						{
+							Name:       "portB",
+							Protocol:   corev1.ProtocolTCP,
+							Port:       9090,
+							TargetPort: intstr.IntOrString{IntVal: 9090},
+						},
+					},
+				},
+			}
+			for _, member := range members {
+				internalSvcExport := fleetenv.ExportService(ctx, k8sClient, member, svc, nil).InternalServiceExport
+				// The serviceImport controller only resolves the exports processed by the internalServiceExport
+				// controller, which is not running in this suite.
+				patch := client.MergeFrom(internalSvcExport.DeepCopy())
+				controllerutil.AddFinalizer(internalSvcExport, objectmeta.InternalServiceExportFinalizer)
+				Expect(k8sClient.Patch(ctx, internalSvcExport, patch)).Should(Succeed())
+			}
+
+			By("Creating serviceImport")
+			serviceImport = &fleetnetv1alpha1.ServiceImport{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      fiveMemberServiceName,
+					Namespace: testNamespace,
+				},
+			}
+			Expect(k8sClient.Create(ctx, serviceImport)).Should(Succeed())
+		})
+
+		AfterEach(func() {
+			By("Deleting serviceImport")
+			Expect(client.IgnoreNotFound(k8sClient.Delete(ctx, serviceImport))).Should(Succeed())
+
+			By("Deleting the member clusters")
+			fleetenv.DeleteSimulatedMembers(ctx, k8sClient, members)
+		})
+
+		It("Should resolve the service exported from all the member clusters", func() {
+			Eventually(func() string {
+				if err := k8sClient.Get(ctx, fiveMemberServiceImportKey, serviceImport); err != nil {
+					return err.Error()
+				}
+				want := fleetnetv1alpha1.ServiceImportStatus{
+					Clusters: []fleetnetv1alpha1.ClusterStatus{
+						{Cluster: "five-member-1"},
+						{Cluster: "five-member-2"},
+						{Cluster: "five-member-3"},
+						{Cluster: "five-member-4"},
+						{Cluster: "five-member-5"},
+					},
+					Ports: importServicePorts,
+				}
+				got := fleetnetv1alpha1.ServiceImportStatus{
+					Clusters: serviceImport.Status.Clusters,
+					Ports:    serviceImport.Status.Ports,
+				}
+				return cmp.Diff(want, got, cmpopts.SortSlices(func(a, b fleetnetv1alpha1.ClusterStatus) bool {
+					return a.Cluster < b.Cluster
+				}))
+			}, timeout, interval).Should(BeEmpty())
+		})
+	})
 })
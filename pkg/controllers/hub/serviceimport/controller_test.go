@@ -0,0 +1,673 @@
+/*
+Copyright (c) Microsoft Corporation.
+Licensed under the MIT license.
+*/
+
+package serviceimport
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	fleetnetv1alpha1 "go.goms.io/fleet-networking/api/v1alpha1"
+	fleetnetv1beta1 "go.goms.io/fleet-networking/api/v1beta1"
+	"go.goms.io/fleet-networking/pkg/common/objectmeta"
+)
+
+func TestExpectedExporters(t *testing.T) {
+	tests := []struct {
+		name        string
+		annotations map[string]string
+		want        []string
+	}{
+		{
+			name: "no annotation",
+		},
+		{
+			name: "empty annotation",
+			annotations: map[string]string{
+				objectmeta.ServiceImportAnnotationExpectedExporters: "",
+			},
+			want: []string{},
+		},
+		{
+			name: "annotation with spaces and duplicates",
+			annotations: map[string]string{
+				objectmeta.ServiceImportAnnotationExpectedExporters: "member-2, member-1,,member-2 ",
+			},
+			want: []string{"member-1", "member-2"},
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			serviceImport := &fleetnetv1alpha1.ServiceImport{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace:   testNamespace,
+					Name:        testServiceName,
+					Annotations: tc.annotations,
+				},
+			}
+			got := expectedExporters(serviceImport)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("expectedExporters() mismatch (-want, +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestBuildMissingClusters(t *testing.T) {
+	deletionTimestamp := metav1.Now()
+	newInternalServiceExport := func(clusterID string, conflict bool) fleetnetv1alpha1.InternalServiceExport {
+		status := metav1.ConditionFalse
+		if conflict {
+			status = metav1.ConditionTrue
+		}
+		return fleetnetv1alpha1.InternalServiceExport{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: clusterID,
+				Name:      testNamespace + "-" + testServiceName,
+			},
+			Spec: fleetnetv1alpha1.InternalServiceExportSpec{
+				Ports: []fleetnetv1alpha1.ServicePort{
+					{
+						Protocol: corev1.ProtocolTCP,
+						Port:     80,
+					},
+				},
+				ServiceReference: fleetnetv1alpha1.ExportedObjectReference{
+					ClusterID: clusterID,
+				},
+			},
+			Status: fleetnetv1alpha1.InternalServiceExportStatus{
+				Conditions: []metav1.Condition{
+					{
+						Type:   string(fleetnetv1alpha1.ServiceExportConflict),
+						Status: status,
+					},
+				},
+			},
+		}
+	}
+	deletingInternalServiceExport := newInternalServiceExport("member-4", true)
+	deletingInternalServiceExport.DeletionTimestamp = &deletionTimestamp
+	invalidInternalServiceExport := newInternalServiceExport("member-6", false)
+	invalidInternalServiceExport.Spec.Ports = nil
+
+	tests := []struct {
+		name                   string
+		expected               []string
+		clusters               []fleetnetv1alpha1.ClusterStatus
+		internalServiceExports []fleetnetv1alpha1.InternalServiceExport
+		want                   []fleetnetv1alpha1.MissingClusterStatus
+	}{
+		{
+			name: "no expected exporters",
+			clusters: []fleetnetv1alpha1.ClusterStatus{
+				{Cluster: "member-1"},
+			},
+		},
+		{
+			name:     "all expected exporters are present",
+			expected: []string{"member-1"},
+			clusters: []fleetnetv1alpha1.ClusterStatus{
+				{Cluster: "member-1"},
+				{Cluster: "member-2"},
+			},
+			internalServiceExports: []fleetnetv1alpha1.InternalServiceExport{
+				newInternalServiceExport("member-1", false),
+			},
+		},
+		{
+			name:     "missing clusters with reasons",
+			expected: []string{"member-1", "member-2", "member-3", "member-4", "member-5", "member-6"},
+			clusters: []fleetnetv1alpha1.ClusterStatus{
+				{Cluster: "member-1"},
+			},
+			internalServiceExports: []fleetnetv1alpha1.InternalServiceExport{
+				newInternalServiceExport("member-1", false),
+				newInternalServiceExport("member-2", true),
+				newInternalServiceExport("member-3", false),
+				deletingInternalServiceExport,
+				invalidInternalServiceExport,
+			},
+			want: []fleetnetv1alpha1.MissingClusterStatus{
+				{Cluster: "member-2", Reason: fleetnetv1alpha1.MissingClusterReasonExportConflicted},
+				{Cluster: "member-3", Reason: fleetnetv1alpha1.MissingClusterReasonExportPending},
+				{Cluster: "member-4", Reason: fleetnetv1alpha1.MissingClusterReasonExportPending},
+				{Cluster: "member-5", Reason: fleetnetv1alpha1.MissingClusterReasonExportNotFound},
+				{Cluster: "member-6", Reason: fleetnetv1alpha1.MissingClusterReasonExportInvalid},
+			},
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got := buildMissingClusters(tc.expected, tc.clusters, tc.internalServiceExports)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("buildMissingClusters() mismatch (-want, +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestResolvedServiceImportType(t *testing.T) {
+	deletionTimestamp := metav1.Now()
+	newInternalServiceExport := func(clusterID string, headless, conflict bool) fleetnetv1alpha1.InternalServiceExport {
+		status := metav1.ConditionFalse
+		if conflict {
+			status = metav1.ConditionTrue
+		}
+		return fleetnetv1alpha1.InternalServiceExport{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: clusterID,
+				Name:      testNamespace + "-" + testServiceName,
+			},
+			Spec: fleetnetv1alpha1.InternalServiceExportSpec{
+				Headless: headless,
+				ServiceReference: fleetnetv1alpha1.ExportedObjectReference{
+					ClusterID: clusterID,
+				},
+			},
+			Status: fleetnetv1alpha1.InternalServiceExportStatus{
+				Conditions: []metav1.Condition{
+					{
+						Type:   string(fleetnetv1alpha1.ServiceExportConflict),
+						Status: status,
+					},
+				},
+			},
+		}
+	}
+	deletingInternalServiceExport := newInternalServiceExport("member-1", true, false)
+	deletingInternalServiceExport.DeletionTimestamp = &deletionTimestamp
+
+	tests := []struct {
+		name                   string
+		currentType            fleetnetv1alpha1.ServiceImportType
+		internalServiceExports []fleetnetv1alpha1.InternalServiceExport
+		want                   fleetnetv1alpha1.ServiceImportType
+	}{
+		{
+			name: "resolved from a clusterSetIP export",
+			internalServiceExports: []fleetnetv1alpha1.InternalServiceExport{
+				newInternalServiceExport("member-1", false, false),
+			},
+			want: fleetnetv1alpha1.ClusterSetIP,
+		},
+		{
+			name:        "resolved from a headless export",
+			currentType: fleetnetv1alpha1.ClusterSetIP,
+			internalServiceExports: []fleetnetv1alpha1.InternalServiceExport{
+				newInternalServiceExport("member-1", true, false),
+			},
+			want: fleetnetv1alpha1.Headless,
+		},
+		{
+			name:        "conflicted, deleting and not accepted exports are skipped",
+			currentType: fleetnetv1alpha1.ClusterSetIP,
+			internalServiceExports: []fleetnetv1alpha1.InternalServiceExport{
+				deletingInternalServiceExport,
+				newInternalServiceExport("member-2", true, true),
+				newInternalServiceExport("member-3", true, false),
+				newInternalServiceExport("member-1", false, false),
+			},
+			want: fleetnetv1alpha1.ClusterSetIP,
+		},
+		{
+			name:        "current type is kept without any accepted export",
+			currentType: fleetnetv1alpha1.Headless,
+			want:        fleetnetv1alpha1.Headless,
+		},
+		{
+			name: "type is backfilled without any accepted export",
+			want: fleetnetv1alpha1.ClusterSetIP,
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			serviceImport := &fleetnetv1alpha1.ServiceImport{
+				Status: fleetnetv1alpha1.ServiceImportStatus{
+					Type: tc.currentType,
+					Clusters: []fleetnetv1alpha1.ClusterStatus{
+						{Cluster: "member-1"},
+						{Cluster: "member-2"},
+					},
+				},
+			}
+			if got := resolvedServiceImportType(serviceImport, tc.internalServiceExports); got != tc.want {
+				t.Errorf("resolvedServiceImportType() = %q, want %q", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestPruneImportersOfRemovedMembers(t *testing.T) {
+	deletionTimestamp := metav1.Now()
+	activeNamespace := &corev1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "fleet-member-active",
+		},
+	}
+	leavingNamespace := &corev1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:              "fleet-member-leaving",
+			DeletionTimestamp: &deletionTimestamp,
+			// The fake client rejects the objects being deleted without finalizers.
+			Finalizers: []string{"test-namespace-finalizer"},
+		},
+	}
+
+	tests := []struct {
+		name           string
+		importers      map[fleetnetv1alpha1.ClusterNamespace]fleetnetv1alpha1.ClusterID
+		wantPruned     bool
+		wantImporters  map[fleetnetv1alpha1.ClusterNamespace]fleetnetv1alpha1.ClusterID
+		wantFinalizers []string
+	}{
+		{
+			name: "no importer",
+		},
+		{
+			name: "importer is still in the fleet",
+			importers: map[fleetnetv1alpha1.ClusterNamespace]fleetnetv1alpha1.ClusterID{
+				"fleet-member-active": "active",
+			},
+			wantImporters: map[fleetnetv1alpha1.ClusterNamespace]fleetnetv1alpha1.ClusterID{
+				"fleet-member-active": "active",
+			},
+			wantFinalizers: []string{objectmeta.ServiceImportCleanupFinalizer},
+		},
+		{
+			name: "one of the importers is leaving the fleet",
+			importers: map[fleetnetv1alpha1.ClusterNamespace]fleetnetv1alpha1.ClusterID{
+				"fleet-member-active":  "active",
+				"fleet-member-leaving": "leaving",
+			},
+			wantPruned: true,
+			wantImporters: map[fleetnetv1alpha1.ClusterNamespace]fleetnetv1alpha1.ClusterID{
+				"fleet-member-active": "active",
+			},
+			wantFinalizers: []string{objectmeta.ServiceImportCleanupFinalizer},
+		},
+		{
+			name: "importers have left the fleet",
+			importers: map[fleetnetv1alpha1.ClusterNamespace]fleetnetv1alpha1.ClusterID{
+				"fleet-member-leaving": "leaving",
+				"fleet-member-left":    "left",
+			},
+			wantPruned: true,
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			serviceImport := &fleetnetv1alpha1.ServiceImport{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: testNamespace,
+					Name:      testServiceName,
+				},
+			}
+			if tc.importers != nil {
+				data, err := json.Marshal(&fleetnetv1alpha1.ServiceInUseBy{MemberClusters: tc.importers})
+				if err != nil {
+					t.Fatalf("failed to marshal the importers: %v", err)
+				}
+				serviceImport.Annotations = map[string]string{
+					objectmeta.ServiceImportAnnotationServiceInUseBy: string(data),
+				}
+				serviceImport.Finalizers = []string{objectmeta.ServiceImportCleanupFinalizer}
+			}
+			scheme := runtime.NewScheme()
+			if err := clientgoscheme.AddToScheme(scheme); err != nil {
+				t.Fatalf("failed to add the client-go scheme: %v", err)
+			}
+			if err := fleetnetv1alpha1.AddToScheme(scheme); err != nil {
+				t.Fatalf("failed to add the fleet networking scheme: %v", err)
+			}
+			fakeClient := fake.NewClientBuilder().
+				WithScheme(scheme).
+				WithObjects(serviceImport, activeNamespace, leavingNamespace).
+				Build()
+			r := &Reconciler{
+				Client:   fakeClient,
+				Recorder: record.NewFakeRecorder(10),
+			}
+
+			ctx := context.Background()
+			pruned, err := r.pruneImportersOfRemovedMembers(ctx, serviceImport)
+			if err != nil {
+				t.Fatalf("pruneImportersOfRemovedMembers() = %v, want no error", err)
+			}
+			if pruned != tc.wantPruned {
+				t.Errorf("pruneImportersOfRemovedMembers() = %v, want %v", pruned, tc.wantPruned)
+			}
+
+			got := &fleetnetv1alpha1.ServiceImport{}
+			if err := fakeClient.Get(ctx, types.NamespacedName{Namespace: testNamespace, Name: testServiceName}, got); err != nil {
+				t.Fatalf("failed to get the serviceImport: %v", err)
+			}
+			if diff := cmp.Diff(tc.wantFinalizers, got.Finalizers, cmpopts.EquateEmpty()); diff != "" {
+				t.Errorf("serviceImport finalizers mismatch (-want, +got):\n%s", diff)
+			}
+			data, ok := got.Annotations[objectmeta.ServiceImportAnnotationServiceInUseBy]
+			if tc.wantImporters == nil {
+				if ok {
+					t.Errorf("serviceImport ServiceInUseBy annotation = %s, want none", data)
+				}
+				return
+			}
+			gotInUseBy := &fleetnetv1alpha1.ServiceInUseBy{}
+			if err := json.Unmarshal([]byte(data), gotInUseBy); err != nil {
+				t.Fatalf("failed to unmarshal the ServiceInUseBy annotation: %v", err)
+			}
+			if diff := cmp.Diff(tc.wantImporters, gotInUseBy.MemberClusters); diff != "" {
+				t.Errorf("serviceImport importers mismatch (-want, +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestImporterClusters(t *testing.T) {
+	tests := []struct {
+		name    string
+		inUseBy string
+		want    []string
+	}{
+		{
+			name: "no ServiceInUseBy annotation",
+		},
+		{
+			name:    "importers are sorted",
+			inUseBy: `{"MemberClusters":{"fleet-member-b":"b","fleet-member-a":"a"}}`,
+			want:    []string{"a", "b"},
+		},
+		{
+			name:    "no importer",
+			inUseBy: `{"MemberClusters":{}}`,
+		},
+		{
+			name:    "corrupted ServiceInUseBy annotation",
+			inUseBy: "{",
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			serviceImport := &fleetnetv1alpha1.ServiceImport{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: testNamespace,
+					Name:      testServiceName,
+				},
+			}
+			if tc.inUseBy != "" {
+				serviceImport.Annotations = map[string]string{
+					objectmeta.ServiceImportAnnotationServiceInUseBy: tc.inUseBy,
+				}
+			}
+			if diff := cmp.Diff(tc.want, importerClusters(serviceImport)); diff != "" {
+				t.Errorf("importerClusters() mismatch (-want, +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestUpdateActiveImporters(t *testing.T) {
+	tests := []struct {
+		name          string
+		importers     map[fleetnetv1alpha1.ClusterNamespace]fleetnetv1alpha1.ClusterID
+		inUseBy       string
+		oldCount      int32
+		wantCount     int32
+		wantImporters []string
+	}{
+		{
+			name: "no importer",
+		},
+		{
+			name: "importers are added",
+			importers: map[fleetnetv1alpha1.ClusterNamespace]fleetnetv1alpha1.ClusterID{
+				"fleet-member-a": "a",
+				"fleet-member-b": "b",
+			},
+			wantCount:     2,
+			wantImporters: []string{"a", "b"},
+		},
+		{
+			name: "importer is withdrawn",
+			importers: map[fleetnetv1alpha1.ClusterNamespace]fleetnetv1alpha1.ClusterID{
+				"fleet-member-a": "a",
+			},
+			oldCount:      2,
+			wantCount:     1,
+			wantImporters: []string{"a"},
+		},
+		{
+			name:     "all the importers are withdrawn",
+			oldCount: 1,
+		},
+		{
+			name:     "corrupted ServiceInUseBy annotation",
+			inUseBy:  "{",
+			oldCount: 1,
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			serviceImport := &fleetnetv1alpha1.ServiceImport{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: testNamespace,
+					Name:      testServiceName,
+				},
+				Status: fleetnetv1alpha1.ServiceImportStatus{
+					Ports:      []fleetnetv1alpha1.ServicePort{{Port: 80, Protocol: corev1.ProtocolTCP}},
+					IPFamilies: []corev1.IPFamily{corev1.IPv4Protocol},
+					Clusters:   []fleetnetv1alpha1.ClusterStatus{{Cluster: "c"}, {Cluster: "d"}},
+				},
+			}
+			inUseBy := tc.inUseBy
+			if tc.importers != nil {
+				data, err := json.Marshal(&fleetnetv1alpha1.ServiceInUseBy{MemberClusters: tc.importers})
+				if err != nil {
+					t.Fatalf("failed to marshal the importers: %v", err)
+				}
+				inUseBy = string(data)
+			}
+			if inUseBy != "" {
+				serviceImport.Annotations = map[string]string{
+					objectmeta.ServiceImportAnnotationServiceInUseBy: inUseBy,
+				}
+			}
+			internalServiceExports := []fleetnetv1alpha1.InternalServiceExport{
+				{
+					ObjectMeta: metav1.ObjectMeta{
+						Namespace: "fleet-member-c",
+						Name:      testServiceName,
+					},
+					Status: fleetnetv1alpha1.InternalServiceExportStatus{
+						ActiveImporterCount: tc.oldCount,
+					},
+				},
+				{
+					ObjectMeta: metav1.ObjectMeta{
+						Namespace: "fleet-member-d",
+						Name:      testServiceName,
+					},
+					Status: fleetnetv1alpha1.InternalServiceExportStatus{
+						ActiveImporterCount: tc.oldCount,
+					},
+				},
+			}
+			scheme := runtime.NewScheme()
+			if err := fleetnetv1alpha1.AddToScheme(scheme); err != nil {
+				t.Fatalf("failed to add the fleet networking scheme: %v", err)
+			}
+			fakeClient := fake.NewClientBuilder().
+				WithScheme(scheme).
+				WithObjects(&internalServiceExports[0], &internalServiceExports[1]).
+				WithStatusSubresource(&internalServiceExports[0], &internalServiceExports[1]).
+				Build()
+			r := &Reconciler{
+				Client:   fakeClient,
+				Recorder: record.NewFakeRecorder(10),
+			}
+
+			ctx := context.Background()
+			if err := r.updateActiveImporters(ctx, serviceImport, importerClusters(serviceImport), internalServiceExports); err != nil {
+				t.Fatalf("updateActiveImporters() = %v, want no error", err)
+			}
+			wantResolution := &fleetnetv1alpha1.ExportResolution{
+				Ports:             []fleetnetv1alpha1.ServicePort{{Port: 80, Protocol: corev1.ProtocolTCP}},
+				IPFamilies:        []corev1.IPFamily{corev1.IPv4Protocol},
+				ExportingClusters: []string{"c", "d"},
+				ImporterClusters:  tc.wantImporters,
+			}
+			for i := range internalServiceExports {
+				got := &fleetnetv1alpha1.InternalServiceExport{}
+				key := types.NamespacedName{Namespace: internalServiceExports[i].Namespace, Name: internalServiceExports[i].Name}
+				if err := fakeClient.Get(ctx, key, got); err != nil {
+					t.Fatalf("failed to get the internalServiceExport %v: %v", key, err)
+				}
+				if got.Status.ActiveImporterCount != tc.wantCount {
+					t.Errorf("internalServiceExport %v activeImporterCount = %d, want %d", key, got.Status.ActiveImporterCount, tc.wantCount)
+				}
+				if diff := cmp.Diff(wantResolution, got.Status.Resolution); diff != "" {
+					t.Errorf("internalServiceExport %v resolution mismatch (-want, +got):\n%s", key, diff)
+				}
+			}
+		})
+	}
+}
+
+func TestBuildDNSNames(t *testing.T) {
+	clusterSetName := testServiceName + "." + testNamespace + ".svc.clusterset.local"
+	newProfile := func(name, fqdn string, programmed bool, generation int64) *fleetnetv1beta1.TrafficManagerProfile {
+		status := metav1.ConditionFalse
+		if programmed {
+			status = metav1.ConditionTrue
+		}
+		return &fleetnetv1beta1.TrafficManagerProfile{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace:  testNamespace,
+				Name:       name,
+				Generation: generation,
+			},
+			Status: fleetnetv1beta1.TrafficManagerProfileStatus{
+				DNSName: ptr.To(fqdn),
+				Conditions: []metav1.Condition{
+					{
+						Type:               string(fleetnetv1beta1.TrafficManagerProfileConditionProgrammed),
+						Status:             status,
+						ObservedGeneration: 1,
+					},
+				},
+			},
+		}
+	}
+	newBackend := func(namespace, name, profileName, serviceImportName string) *fleetnetv1beta1.TrafficManagerBackend {
+		return &fleetnetv1beta1.TrafficManagerBackend{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: namespace,
+				Name:      name,
+			},
+			Spec: fleetnetv1beta1.TrafficManagerBackendSpec{
+				Profile: fleetnetv1beta1.TrafficManagerProfileRef{Name: profileName},
+				Backend: fleetnetv1beta1.TrafficManagerBackendRef{Name: serviceImportName},
+			},
+		}
+	}
+	tests := []struct {
+		name                        string
+		enableTrafficManagerFeature bool
+		objects                     []client.Object
+		want                        []string
+	}{
+		{
+			name: "traffic manager feature disabled",
+			objects: []client.Object{
+				newProfile("profile-1", "profile-1.trafficmanager.net", true, 1),
+				newBackend(testNamespace, "backend-1", "profile-1", testServiceName),
+			},
+			want: []string{clusterSetName},
+		},
+		{
+			name:                        "no trafficManagerBackend",
+			enableTrafficManagerFeature: true,
+			objects: []client.Object{
+				newProfile("profile-1", "profile-1.trafficmanager.net", true, 1),
+				newBackend(testNamespace, "backend-1", "profile-1", "other-svc"),
+				newBackend("other-ns", "backend-2", "profile-1", testServiceName),
+			},
+			want: []string{clusterSetName},
+		},
+		{
+			name:                        "trafficManagerBackends of programmed profiles",
+			enableTrafficManagerFeature: true,
+			objects: []client.Object{
+				newProfile("profile-1", "profile-1.trafficmanager.net", true, 1),
+				newProfile("profile-0", "profile-0.trafficmanager.net", true, 1),
+				newBackend(testNamespace, "backend-1", "profile-1", testServiceName),
+				newBackend(testNamespace, "backend-2", "profile-1", testServiceName),
+				newBackend(testNamespace, "backend-3", "profile-0", testServiceName),
+			},
+			want: []string{clusterSetName, "profile-0.trafficmanager.net", "profile-1.trafficmanager.net"},
+		},
+		{
+			name:                        "trafficManagerBackends of profiles which are not programmed or not found",
+			enableTrafficManagerFeature: true,
+			objects: []client.Object{
+				newProfile("profile-1", "profile-1.trafficmanager.net", false, 1),
+				newProfile("profile-2", "profile-2.trafficmanager.net", true, 2),
+				newBackend(testNamespace, "backend-1", "profile-1", testServiceName),
+				newBackend(testNamespace, "backend-2", "profile-2", testServiceName),
+				newBackend(testNamespace, "backend-3", "profile-3", testServiceName),
+			},
+			want: []string{clusterSetName},
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			scheme := runtime.NewScheme()
+			if err := fleetnetv1alpha1.AddToScheme(scheme); err != nil {
+				t.Fatalf("failed to add the fleet networking scheme: %v", err)
+			}
+			if err := fleetnetv1beta1.AddToScheme(scheme); err != nil {
+				t.Fatalf("failed to add the fleet networking v1beta1 scheme: %v", err)
+			}
+			fakeClient := fake.NewClientBuilder().
+				WithScheme(scheme).
+				WithObjects(tc.objects...).
+				WithIndex(&fleetnetv1beta1.TrafficManagerBackend{}, trafficManagerBackendServiceImportFieldKey, func(o client.Object) []string {
+					return []string{o.(*fleetnetv1beta1.TrafficManagerBackend).Spec.Backend.Name}
+				}).
+				Build()
+			r := &Reconciler{
+				Client:                      fakeClient,
+				Recorder:                    record.NewFakeRecorder(10),
+				EnableTrafficManagerFeature: tc.enableTrafficManagerFeature,
+			}
+			serviceImport := &fleetnetv1alpha1.ServiceImport{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: testNamespace,
+					Name:      testServiceName,
+				},
+			}
+			got, err := r.buildDNSNames(context.Background(), serviceImport)
+			if err != nil {
+				t.Fatalf("buildDNSNames() = %v, want no error", err)
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("buildDNSNames() mismatch (-want, +got):\n%s", diff)
+			}
+		})
+	}
+}
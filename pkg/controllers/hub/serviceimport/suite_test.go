@@ -28,6 +28,8 @@ import (
 	// +kubebuilder:scaffold:imports
 
 	fleetnetv1alpha1 "go.goms.io/fleet-networking/api/v1alpha1"
+	fleetnetv1beta1 "go.goms.io/fleet-networking/api/v1beta1"
+	"go.goms.io/fleet-networking/test/common/fleetenv"
 )
 
 var (
@@ -37,6 +39,11 @@ var (
 	testEnv   *envtest.Environment
 	ctx       context.Context
 	cancel    context.CancelFunc
+
+	// memberA, memberB and memberAA are the simulated member clusters whose namespaces hold the exports.
+	memberA  *fleetenv.Member
+	memberB  *fleetenv.Member
+	memberAA *fleetenv.Member
 )
 
 const (
@@ -72,6 +79,8 @@ var _ = BeforeSuite(func() {
 
 	err = fleetnetv1alpha1.AddToScheme(scheme.Scheme)
 	Expect(err).NotTo(HaveOccurred())
+	err = fleetnetv1beta1.AddToScheme(scheme.Scheme)
+	Expect(err).NotTo(HaveOccurred())
 
 	//+kubebuilder:scaffold:scheme
 	By("construct the k8s client")
@@ -87,27 +96,10 @@ var _ = BeforeSuite(func() {
 	}
 	Expect(k8sClient.Create(ctx, &ns)).Should(Succeed())
 
-	By("create member namespace")
-	ns = corev1.Namespace{
-		ObjectMeta: metav1.ObjectMeta{
-			Name: testMemberClusterA,
-		},
-	}
-	Expect(k8sClient.Create(ctx, &ns)).Should(Succeed())
-
-	ns = corev1.Namespace{
-		ObjectMeta: metav1.ObjectMeta{
-			Name: testMemberClusterB,
-		},
-	}
-	Expect(k8sClient.Create(ctx, &ns)).Should(Succeed())
-
-	ns = corev1.Namespace{
-		ObjectMeta: metav1.ObjectMeta{
-			Name: testMemberClusterAA,
-		},
-	}
-	Expect(k8sClient.Create(ctx, &ns)).Should(Succeed())
+	By("create simulated member clusters")
+	memberA = fleetenv.CreateSimulatedMember(ctx, k8sClient, testMemberClusterA)
+	memberB = fleetenv.CreateSimulatedMember(ctx, k8sClient, testMemberClusterB)
+	memberAA = fleetenv.CreateSimulatedMember(ctx, k8sClient, testMemberClusterAA)
 
 	By("starting the controller manager")
 	klog.InitFlags(flag.CommandLine)
@@ -122,8 +114,9 @@ var _ = BeforeSuite(func() {
 	Expect(err).NotTo(HaveOccurred())
 
 	err = (&Reconciler{
-		Client:   mgr.GetClient(),
-		Recorder: mgr.GetEventRecorderFor(ControllerName),
+		Client:                      mgr.GetClient(),
+		Recorder:                    mgr.GetEventRecorderFor(ControllerName),
+		EnableTrafficManagerFeature: true,
 	}).SetupWithManager(ctx, mgr)
 	Expect(err).ToNot(HaveOccurred())
 
@@ -138,6 +131,9 @@ var _ = BeforeSuite(func() {
 var _ = AfterSuite(func() {
 	defer klog.Flush()
 
+	By("deleting the simulated member clusters")
+	fleetenv.DeleteSimulatedMembers(ctx, k8sClient, []*fleetenv.Member{memberA, memberB, memberAA})
+
 	cancel()
 	By("tearing down the test environment")
 	err := testEnv.Stop()
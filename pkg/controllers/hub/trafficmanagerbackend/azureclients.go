@@ -0,0 +1,95 @@
+/*
+Copyright (c) Microsoft Corporation.
+Licensed under the MIT license.
+*/
+
+package trafficmanagerbackend
+
+import (
+	"context"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/trafficmanager/armtrafficmanager"
+
+	"go.goms.io/fleet-networking/pkg/common/adaptivelimiter"
+	"go.goms.io/fleet-networking/pkg/common/azureerrors"
+)
+
+// ProfilesClient is the subset of the Azure Traffic Manager profiles client the controller uses; it is satisfied by
+// *armtrafficmanager.ProfilesClient, and can be faked in the unit tests.
+type ProfilesClient interface {
+	// Get gets the Azure Traffic Manager profile.
+	Get(ctx context.Context, resourceGroupName string, profileName string,
+		options *armtrafficmanager.ProfilesClientGetOptions) (armtrafficmanager.ProfilesClientGetResponse, error)
+	// CreateOrUpdate creates or updates the Azure Traffic Manager profile, including its endpoints.
+	CreateOrUpdate(ctx context.Context, resourceGroupName string, profileName string, parameters armtrafficmanager.Profile,
+		options *armtrafficmanager.ProfilesClientCreateOrUpdateOptions) (armtrafficmanager.ProfilesClientCreateOrUpdateResponse, error)
+}
+
+// EndpointsClient is the subset of the Azure Traffic Manager endpoints client the controller uses; it is satisfied by
+// *armtrafficmanager.EndpointsClient, and can be faked in the unit tests.
+type EndpointsClient interface {
+	// CreateOrUpdate creates or updates the Azure Traffic Manager endpoint.
+	CreateOrUpdate(ctx context.Context, resourceGroupName string, profileName string, endpointType armtrafficmanager.EndpointType,
+		endpointName string, parameters armtrafficmanager.Endpoint,
+		options *armtrafficmanager.EndpointsClientCreateOrUpdateOptions) (armtrafficmanager.EndpointsClientCreateOrUpdateResponse, error)
+	// Delete deletes the Azure Traffic Manager endpoint.
+	Delete(ctx context.Context, resourceGroupName string, profileName string, endpointType armtrafficmanager.EndpointType,
+		endpointName string, options *armtrafficmanager.EndpointsClientDeleteOptions) (armtrafficmanager.EndpointsClientDeleteResponse, error)
+}
+
+var (
+	_ ProfilesClient  = &armtrafficmanager.ProfilesClient{}
+	_ EndpointsClient = &armtrafficmanager.EndpointsClient{}
+	_ ProfilesClient  = &limitedProfilesClient{}
+	_ EndpointsClient = &limitedEndpointsClient{}
+)
+
+// limitedProfilesClient is a ProfilesClient whose profile updates are admitted by the concurrency limiter; the reads
+// are never held back.
+type limitedProfilesClient struct {
+	ProfilesClient
+	limiter *adaptivelimiter.Limiter
+}
+
+// CreateOrUpdate implements the ProfilesClient interface.
+func (c *limitedProfilesClient) CreateOrUpdate(ctx context.Context, resourceGroupName string, profileName string, parameters armtrafficmanager.Profile,
+	options *armtrafficmanager.ProfilesClientCreateOrUpdateOptions) (armtrafficmanager.ProfilesClientCreateOrUpdateResponse, error) {
+	permit, err := c.limiter.Acquire(ctx)
+	if err != nil {
+		return armtrafficmanager.ProfilesClientCreateOrUpdateResponse{}, err
+	}
+	res, err := c.ProfilesClient.CreateOrUpdate(ctx, resourceGroupName, profileName, parameters, options)
+	permit.Release(azureerrors.IsThrottled(err))
+	return res, err
+}
+
+// limitedEndpointsClient is an EndpointsClient whose calls are admitted by the concurrency limiter.
+type limitedEndpointsClient struct {
+	EndpointsClient
+	limiter *adaptivelimiter.Limiter
+}
+
+// CreateOrUpdate implements the EndpointsClient interface.
+func (c *limitedEndpointsClient) CreateOrUpdate(ctx context.Context, resourceGroupName string, profileName string, endpointType armtrafficmanager.EndpointType,
+	endpointName string, parameters armtrafficmanager.Endpoint,
+	options *armtrafficmanager.EndpointsClientCreateOrUpdateOptions) (armtrafficmanager.EndpointsClientCreateOrUpdateResponse, error) {
+	permit, err := c.limiter.Acquire(ctx)
+	if err != nil {
+		return armtrafficmanager.EndpointsClientCreateOrUpdateResponse{}, err
+	}
+	res, err := c.EndpointsClient.CreateOrUpdate(ctx, resourceGroupName, profileName, endpointType, endpointName, parameters, options)
+	permit.Release(azureerrors.IsThrottled(err))
+	return res, err
+}
+
+// Delete implements the EndpointsClient interface.
+func (c *limitedEndpointsClient) Delete(ctx context.Context, resourceGroupName string, profileName string, endpointType armtrafficmanager.EndpointType,
+	endpointName string, options *armtrafficmanager.EndpointsClientDeleteOptions) (armtrafficmanager.EndpointsClientDeleteResponse, error) {
+	permit, err := c.limiter.Acquire(ctx)
+	if err != nil {
+		return armtrafficmanager.EndpointsClientDeleteResponse{}, err
+	}
+	res, err := c.EndpointsClient.Delete(ctx, resourceGroupName, profileName, endpointType, endpointName, options)
+	permit.Release(azureerrors.IsThrottled(err))
+	return res, err
+}
@@ -10,24 +10,39 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"hash/fnv"
+	"maps"
 	"math"
+	"net/http"
+	"net/netip"
+	"slices"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
 	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/trafficmanager/armtrafficmanager"
+	"github.com/prometheus/client_golang/prometheus"
 	"golang.org/x/sync/errgroup"
 	corev1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/client-go/tools/record"
 	"k8s.io/klog/v2"
 	"k8s.io/utils/ptr"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	ctrlcontroller "sigs.k8s.io/controller-runtime/pkg/controller"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/event"
 	"sigs.k8s.io/controller-runtime/pkg/handler"
+	ctrlmetrics "sigs.k8s.io/controller-runtime/pkg/metrics"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	"go.goms.io/fleet/pkg/utils/condition"
@@ -35,13 +50,23 @@ import (
 
 	fleetnetv1alpha1 "go.goms.io/fleet-networking/api/v1alpha1"
 	fleetnetv1beta1 "go.goms.io/fleet-networking/api/v1beta1"
+	"go.goms.io/fleet-networking/pkg/common/adaptivelimiter"
 	"go.goms.io/fleet-networking/pkg/common/azureerrors"
+	"go.goms.io/fleet-networking/pkg/common/cloudconfig"
 	"go.goms.io/fleet-networking/pkg/common/defaulter"
+	"go.goms.io/fleet-networking/pkg/common/listguard"
+	"go.goms.io/fleet-networking/pkg/common/metrics"
+	"go.goms.io/fleet-networking/pkg/common/nsallowlist"
 	"go.goms.io/fleet-networking/pkg/common/objectmeta"
+	"go.goms.io/fleet-networking/pkg/common/reconcilewatchdog"
+	"go.goms.io/fleet-networking/pkg/common/sharding"
 	"go.goms.io/fleet-networking/pkg/controllers/hub/trafficmanagerprofile"
 )
 
 const (
+	// ControllerName is the name of the Reconciler.
+	ControllerName = "trafficmanagerbackend-controller"
+
 	trafficManagerBackendProfileFieldKey = ".spec.profile.name"
 	trafficManagerBackendBackendFieldKey = ".spec.backend.name"
 	// fields name used to filter resources
@@ -60,9 +85,70 @@ const (
 	// The cluster name length should be restricted to <= 63 characters.
 	// The endpoint name must contain no more than 260 characters, excluding the following characters "< > * % $ : \ ? + /".
 	AzureResourceEndpointNameFormat = "%s%s#%s"
+
+	// staleExportReason is reported for the exported services which have not been refreshed by their member
+	// clusters for longer than the max staleness.
+	staleExportReason = "StaleExport"
+
+	// localExternalTrafficPolicyReason is reported for the exported services whose external traffic policy is Local,
+	// as the Azure Traffic Manager health checks are then served only by the nodes running their ready pods.
+	localExternalTrafficPolicyReason = "LocalExternalTrafficPolicy"
+
+	// backendEventReasonNamespaceNotAllowed is the reason of the event emitted when the backend is refused as its
+	// namespace is not in the allowlist.
+	backendEventReasonNamespaceNotAllowed = "NamespaceNotAllowed"
+
+	// maxBulkEndpointUpdateAttempts is the maximum number of attempts to submit the endpoints with a single Azure
+	// Traffic Manager profile PUT, when the profile keeps being modified concurrently (e.g. by other backends).
+	maxBulkEndpointUpdateAttempts = 3
+
+	// minEndpointPriority and maxEndpointPriority are the range of the Azure Traffic Manager endpoint priorities.
+	minEndpointPriority = 1
+	maxEndpointPriority = 1000
+)
+
+var (
+	// trafficManagerEndpointWeight reports the weight of each Azure Traffic Manager endpoint created for a
+	// trafficManagerBackend, per member cluster, so that the split of the backend weight across the clusters can be
+	// visualized over time.
+	trafficManagerEndpointWeight = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: metrics.MetricsNamespace,
+			Subsystem: metrics.MetricsSubsystem,
+			Name:      "traffic_manager_endpoint_weight",
+			Help:      "The weight of the Azure Traffic Manager endpoint of a trafficManagerBackend per member cluster",
+		},
+		[]string{"namespace", "backend", "cluster"},
+	)
+
+	// trafficManagerBackendLastSyncedTime reports the last synced time of each trafficManagerBackend, so that alerts
+	// can fire on the time since its Azure Traffic Manager endpoints were last reconciled successfully.
+	trafficManagerBackendLastSyncedTime = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: metrics.MetricsNamespace,
+			Subsystem: metrics.MetricsSubsystem,
+			Name:      "traffic_manager_backend_last_synced_timestamp_seconds",
+			Help:      "The Unix time of the last reconciliation of a trafficManagerBackend which completed all of its Azure Traffic Manager operations successfully",
+		},
+		[]string{"namespace", "backend"},
+	)
 )
 
+func init() {
+	// Register trafficManagerEndpointWeight (fleet_networking_traffic_manager_endpoint_weight),
+	// trafficManagerBackendLastSyncedTime (fleet_networking_traffic_manager_backend_last_synced_timestamp_seconds) and
+	// trafficManagerProfileCacheRequests (fleet_networking_traffic_manager_profile_cache_requests_total) metrics with
+	// the controller runtime global metrics registry.
+	ctrlmetrics.Registry.MustRegister(trafficManagerEndpointWeight, trafficManagerBackendLastSyncedTime, trafficManagerProfileCacheRequests)
+}
+
 var (
+	// errReadOnly is returned for the Azure Traffic Manager requests which are skipped in the read-only mode.
+	errReadOnly = errors.New("read-only mode")
+	// errAgentUpgradeRequired is reported for the exported services whose Azure Traffic Manager information has not
+	// been reported by the member agents of their clusters.
+	errAgentUpgradeRequired = errors.New(string(fleetnetv1beta1.TrafficManagerBackendReasonAgentUpgradeRequired))
+
 	// create the func as a variable so that the integration test can use a customized function.
 	generateAzureTrafficManagerProfileNameFunc = func(profile *fleetnetv1beta1.TrafficManagerProfile) string {
 		return trafficmanagerprofile.GenerateAzureTrafficManagerProfileName(profile)
@@ -72,13 +158,140 @@ var (
 	}
 )
 
+// invalidClusterError is returned for an exported service which cannot be exposed as an Azure Traffic Manager endpoint;
+// the reason is reported in the invalid clusters of the backend status.
+type invalidClusterError struct {
+	reason fleetnetv1beta1.TrafficManagerInvalidClusterReason
+	err    error
+}
+
+func invalidClusterErrorf(reason fleetnetv1beta1.TrafficManagerInvalidClusterReason, format string, a ...any) error {
+	return &invalidClusterError{reason: reason, err: fmt.Errorf(format, a...)}
+}
+
+func (e *invalidClusterError) Error() string { return e.err.Error() }
+
+func (e *invalidClusterError) Unwrap() error { return e.err }
+
+// badEndpointError is returned for an endpoint which is rejected by Azure, along with the cluster exporting the
+// service behind it.
+type badEndpointError struct {
+	cluster string
+	err     error
+}
+
+func (e *badEndpointError) Error() string { return e.err.Error() }
+
+func (e *badEndpointError) Unwrap() error { return e.err }
+
 // Reconciler reconciles a trafficManagerBackend object.
 type Reconciler struct {
 	client.Client
 
-	ProfilesClient    *armtrafficmanager.ProfilesClient
-	EndpointsClient   *armtrafficmanager.EndpointsClient
+	ProfilesClient    ProfilesClient
+	EndpointsClient   EndpointsClient
 	ResourceGroupName string // default resource group name to create azure traffic manager resources
+
+	// MaxExportStaleness is the maximum duration since the last heartbeat of an exported service before it is
+	// excluded from the Azure Traffic Manager endpoints; 0 disables the check.
+	MaxExportStaleness time.Duration
+
+	// Shard is the subset of the trafficManagerBackends reconciled by this replica. When the backends are split across
+	// more than one shard, the controller runs on every replica regardless of leader election.
+	Shard sharding.Shard
+
+	// BulkEndpointUpdateThreshold is the number of endpoint creations or updates in a single reconciliation above
+	// which the endpoints are submitted with a single Azure Traffic Manager profile PUT instead of one request per
+	// endpoint; 0 disables the bulk update.
+	BulkEndpointUpdateThreshold int
+
+	// RejectLocalExternalTrafficPolicy, if set, excludes the exported services whose external traffic policy is Local
+	// from the Azure Traffic Manager endpoints; otherwise, they are exposed and only reported in the condition.
+	RejectLocalExternalTrafficPolicy bool
+
+	// AzureClients, if set, reloads the Azure clients when the cloud config changes; the ProfilesClient, the
+	// EndpointsClient and the ResourceGroupName are then taken from the latest cloud config at the start of each
+	// reconciliation.
+	AzureClients *cloudconfig.Reloader[cloudconfig.TrafficManagerClients]
+
+	// LastSyncedTimeUpdateInterval is the granularity of the last synced time in the status of the backends;
+	// trafficmanagerprofile.DefaultLastSyncedTimeUpdateInterval is used when it is not positive.
+	LastSyncedTimeUpdateInterval time.Duration
+
+	// ReadOnly, if set, only reads the Azure Traffic Manager profiles and endpoints: the endpoints are not created,
+	// updated or deleted, and the requests which would have been sent are logged and reported in the Accepted
+	// condition instead. The finalizer of a deleted backend is kept until its Azure Traffic Manager endpoints are gone.
+	ReadOnly bool
+
+	// AllowedNamespaces are the namespaces whose backends are configured on the Azure Traffic Manager profiles; the
+	// backends of the other namespaces are refused without calling Azure. All the namespaces are allowed when it is
+	// empty.
+	AllowedNamespaces nsallowlist.Allowlist
+
+	// ConcurrencyLimiter, if set, admits the Azure Traffic Manager requests which create, update or delete the
+	// endpoints, including the bulk profile updates. It is shared by all the reconciliations, so that the concurrency
+	// of the requests shrinks when Azure throttles them and gradually recovers afterwards.
+	ConcurrencyLimiter *adaptivelimiter.Limiter
+
+	// ProfileCacheTTL is the duration for which an Azure Traffic Manager profile read to validate a backend or to delete
+	// its endpoints is reused, e.g. by the other backends of the profile; the entry of a profile is dropped as soon as
+	// the controller changes its endpoints. The profiles are not cached when it is not positive.
+	ProfileCacheTTL time.Duration
+
+	// StartupJitterWindow spreads the first reconciliations of the backends after the controller starts over the
+	// window, so that the backends of a restarted hub agent do not all call Azure at once; 0 disables the jitter.
+	StartupJitterWindow time.Duration
+
+	Recorder record.EventRecorder
+
+	// weightClusters tracks the clusters of the last recorded endpoint weights of each trafficManagerBackend, keyed by
+	// its namespaced name, so that the series of the clusters without endpoints can be deleted from the
+	// trafficManagerEndpointWeight metric. It is shared by the copies of the reconciler; the metric is not recorded
+	// when it is nil.
+	weightClusters *sync.Map
+
+	// heartbeats tracks the heartbeat of each exported service, keyed by the namespaced name of its
+	// internalServiceExport, together with the time at which this hub agent first observed it; the staleness of an
+	// exported service is measured with the hub clock only, as the clocks of the member clusters may be skewed.
+	// It is shared by the copies of the reconciler; the heartbeat timestamps are used as is when it is nil.
+	heartbeats *sync.Map
+
+	// profiles caches the Azure Traffic Manager profiles read by the reconciliations; it is shared by the copies of the
+	// reconciler, and nothing is cached when it is nil.
+	profiles *profileCache
+
+	// startup delays the first reconciliations of the backends by the startup jitter; it is shared by the copies of
+	// the reconciler, and the reconciliations are not delayed when it is nil.
+	startup *startupJitter
+}
+
+// startupJitter spreads the first reconciliations of the backends over a window, counting from the first
+// reconciliation of the controller, i.e., once the cache has been synced and, if enabled, the leader elected.
+type startupJitter struct {
+	window time.Duration
+
+	once      sync.Once
+	startedAt time.Time
+}
+
+// delay returns how long the reconciliation of the backend is delayed: each backend is given an offset within the
+// window by the hash of its namespaced name, so that the delays do not change across the requeues.
+func (j *startupJitter) delay(name types.NamespacedName, now time.Time) time.Duration {
+	if j == nil || j.window <= 0 {
+		return 0
+	}
+	j.once.Do(func() { j.startedAt = now })
+	h := fnv.New64a()
+	// The hash.Hash Write never returns an error.
+	_, _ = h.Write([]byte(name.String()))
+	offset := time.Duration(float64(j.window) * (float64(h.Sum64()) / math.MaxUint64))
+	return j.startedAt.Add(offset).Sub(now)
+}
+
+// heartbeatObservation is the heartbeat of an exported service and the time at which it was first observed.
+type heartbeatObservation struct {
+	heartbeat  time.Time
+	observedAt time.Time
 }
 
 //+kubebuilder:rbac:groups=networking.fleet.azure.com,resources=trafficmanagerbackends,verbs=get;list;watch;create;update;patch;delete
@@ -86,6 +299,7 @@ type Reconciler struct {
 //+kubebuilder:rbac:groups=networking.fleet.azure.com,resources=trafficmanagerbackends/finalizers,verbs=get;update
 //+kubebuilder:rbac:groups=networking.fleet.azure.com,resources=trafficmanagerprofiles,verbs=get;list;watch
 //+kubebuilder:rbac:groups=networking.fleet.azure.com,resources=serviceimports,verbs=get;list;watch
+//+kubebuilder:rbac:groups=networking.fleet.azure.com,resources=internalserviceexports,verbs=get;list;watch
 //+kubebuilder:rbac:groups="",resources=events,verbs=create;patch
 
 // Reconcile triggers a single reconcile round.
@@ -100,6 +314,25 @@ func (r *Reconciler) Reconcile(ctx context.Context, req reconcile.Request) (reco
 		klog.V(2).InfoS("Reconciliation ends", "trafficManagerBackend", backendKRef, "latency", latency)
 	}()
 
+	if !r.Shard.Owns(name.Namespace, name.Name) {
+		// The event handlers filter out the backends of other shards; it should never happen.
+		klog.V(2).InfoS("Skipping trafficManagerBackend owned by another shard", "trafficManagerBackend", backendKRef, "shardCount", r.Shard.Count, "shardIndex", r.Shard.Index)
+		return ctrl.Result{}, nil
+	}
+
+	if delay := r.startup.delay(name, startTime); delay > 0 {
+		klog.V(2).InfoS("Delaying the first reconciliation of the trafficManagerBackend after the controller starts", "trafficManagerBackend", backendKRef, "delay", delay)
+		return ctrl.Result{RequeueAfter: delay}, nil
+	}
+
+	r, err := r.withCurrentAzureClients()
+	if err != nil {
+		klog.ErrorS(err, "Failed to get the Azure clients", "trafficManagerBackend", backendKRef)
+		return ctrl.Result{}, err
+	}
+	r = r.withConcurrencyLimiter()
+	r = r.withProfileCache()
+
 	backend := &fleetnetv1beta1.TrafficManagerBackend{}
 	if err := r.Client.Get(ctx, name, backend); err != nil {
 		if apierrors.IsNotFound(err) {
@@ -110,6 +343,10 @@ func (r *Reconciler) Reconcile(ctx context.Context, req reconcile.Request) (reco
 		return ctrl.Result{}, controller.NewAPIServerError(true, err)
 	}
 
+	if !r.AllowedNamespaces.Allows(backend.Namespace) {
+		return r.handleNamespaceNotAllowed(ctx, backend)
+	}
+
 	if !backend.ObjectMeta.DeletionTimestamp.IsZero() {
 		return r.handleDelete(ctx, backend)
 	}
@@ -122,11 +359,52 @@ func (r *Reconciler) Reconcile(ctx context.Context, req reconcile.Request) (reco
 			return ctrl.Result{}, controller.NewUpdateIgnoreConflictError(err)
 		}
 	}
-	// TODO: replace the following with defaulter webhook
+	// The defaults are set by the defaulting webhook at admission; they are set here as well, as a safety net for the
+	// objects created before the webhook existed or when the webhook is disabled.
 	defaulter.SetDefaultsTrafficManagerBackend(backend)
 	return r.handleUpdate(ctx, backend)
 }
 
+// withCurrentAzureClients returns a copy of the reconciler which uses the Azure clients built from the latest cloud
+// config, so that a reload does not change the clients in the middle of a reconciliation.
+func (r *Reconciler) withCurrentAzureClients() (*Reconciler, error) {
+	if r.AzureClients == nil {
+		return r, nil
+	}
+	clients, err := r.AzureClients.Current()
+	if err != nil {
+		return nil, err
+	}
+	rc := *r
+	rc.ProfilesClient = clients.ProfilesClient
+	rc.EndpointsClient = clients.EndpointsClient
+	rc.ResourceGroupName = clients.ResourceGroupName
+	return &rc, nil
+}
+
+// withConcurrencyLimiter returns a copy of the reconciler whose Azure clients send the requests changing the
+// endpoints once the concurrency limiter admits them.
+func (r *Reconciler) withConcurrencyLimiter() *Reconciler {
+	if r.ConcurrencyLimiter == nil {
+		return r
+	}
+	rc := *r
+	rc.ProfilesClient = &limitedProfilesClient{ProfilesClient: r.ProfilesClient, limiter: r.ConcurrencyLimiter}
+	rc.EndpointsClient = &limitedEndpointsClient{EndpointsClient: r.EndpointsClient, limiter: r.ConcurrencyLimiter}
+	return &rc
+}
+
+// withProfileCache returns a copy of the reconciler whose Azure clients invalidate the cached profiles they change.
+func (r *Reconciler) withProfileCache() *Reconciler {
+	if r.profiles == nil {
+		return r
+	}
+	rc := *r
+	rc.ProfilesClient = &invalidatingProfilesClient{ProfilesClient: r.ProfilesClient, cache: r.profiles}
+	rc.EndpointsClient = &invalidatingEndpointsClient{EndpointsClient: r.EndpointsClient, cache: r.profiles}
+	return &rc
+}
+
 func (r *Reconciler) handleDelete(ctx context.Context, backend *fleetnetv1beta1.TrafficManagerBackend) (ctrl.Result, error) {
 	backendKObj := klog.KObj(backend)
 	// The backend is being deleted
@@ -135,7 +413,16 @@ func (r *Reconciler) handleDelete(ctx context.Context, backend *fleetnetv1beta1.
 		return ctrl.Result{}, nil
 	}
 
-	if err := r.deleteAzureTrafficManagerEndpoints(ctx, backend); err != nil {
+	if err := validateBackendReferences(backend); err != nil {
+		// The references are immutable, so no endpoint could have been created for the backend.
+		klog.V(2).InfoS("Skipping deleting Azure Traffic Manager endpoints of the trafficManagerBackend with invalid references", "trafficManagerBackend", backendKObj, "error", err)
+	} else if err := r.deleteAzureTrafficManagerEndpoints(ctx, backend); errors.Is(err, errReadOnly) {
+		// The backend is not requeued: the deletion resumes once the controller runs without the read-only mode, or
+		// once the endpoints are deleted out of band.
+		klog.V(2).InfoS("Keeping the trafficManagerBackend finalizer in the read-only mode", "trafficManagerBackend", backendKObj)
+		setReadOnlyCondition(backend, backend.Status.Endpoints, fmt.Sprintf("Refusing to remove the finalizer as the Azure Traffic Manager endpoints cannot be deleted in the %v", err))
+		return ctrl.Result{}, r.updateTrafficManagerBackendStatus(ctx, backend)
+	} else if err != nil {
 		klog.ErrorS(err, "Failed to delete Azure Traffic Manager endpoints", "trafficManagerBackend", backendKObj)
 		return ctrl.Result{}, err
 	}
@@ -145,10 +432,53 @@ func (r *Reconciler) handleDelete(ctx context.Context, backend *fleetnetv1beta1.
 		klog.ErrorS(err, "Failed to remove trafficManagerBackend finalizer", "trafficManagerBackend", backendKObj)
 		return ctrl.Result{}, controller.NewUpdateIgnoreConflictError(err)
 	}
+	r.recordEndpointWeights(backend, nil)
+	trafficManagerBackendLastSyncedTime.DeleteLabelValues(backend.Namespace, backend.Name)
 	klog.V(2).InfoS("Removed trafficManagerBackend finalizer", "trafficManagerBackend", backendKObj)
 	return ctrl.Result{}, nil
 }
 
+// handleNamespaceNotAllowed refuses the backend whose namespace is not in the allowlist, without calling Azure. The
+// Azure Traffic Manager endpoints created before the namespace was disallowed, if any, are left as is: the finalizer
+// is removed, so that the backend can be deleted, and they are taken back once the namespace is allowed again.
+func (r *Reconciler) handleNamespaceNotAllowed(ctx context.Context, backend *fleetnetv1beta1.TrafficManagerBackend) (ctrl.Result, error) {
+	backendKObj := klog.KObj(backend)
+	if controllerutil.ContainsFinalizer(backend, objectmeta.TrafficManagerBackendFinalizer) {
+		klog.InfoS("Leaving the Azure Traffic Manager endpoints, if any, of the trafficManagerBackend whose namespace is not allowed",
+			"trafficManagerBackend", backendKObj, "endpoints", len(backend.Status.Endpoints))
+		controllerutil.RemoveFinalizer(backend, objectmeta.TrafficManagerBackendFinalizer)
+		if err := r.Client.Update(ctx, backend); err != nil {
+			klog.ErrorS(err, "Failed to remove trafficManagerBackend finalizer", "trafficManagerBackend", backendKObj)
+			return ctrl.Result{}, controller.NewUpdateIgnoreConflictError(err)
+		}
+		r.recordEndpointWeights(backend, nil)
+		trafficManagerBackendLastSyncedTime.DeleteLabelValues(backend.Namespace, backend.Name)
+	}
+	if !backend.ObjectMeta.DeletionTimestamp.IsZero() {
+		return ctrl.Result{}, nil
+	}
+
+	message := fmt.Sprintf("Namespace %q is not allowed to use Azure Traffic Manager", backend.Namespace)
+	cond := metav1.Condition{
+		Type:               string(fleetnetv1beta1.TrafficManagerBackendConditionAccepted),
+		Status:             metav1.ConditionFalse,
+		ObservedGeneration: backend.Generation,
+		Reason:             string(fleetnetv1beta1.TrafficManagerBackendReasonNamespaceNotAllowed),
+		Message:            message,
+	}
+	if condition.EqualCondition(meta.FindStatusCondition(backend.Status.Conditions, cond.Type), &cond) {
+		klog.V(2).InfoS("TrafficManagerBackend has already been refused as its namespace is not allowed", "trafficManagerBackend", backendKObj)
+		return ctrl.Result{}, nil
+	}
+	klog.V(2).InfoS("Refusing the trafficManagerBackend as its namespace is not allowed", "trafficManagerBackend", backendKObj)
+	r.Recorder.Event(backend, corev1.EventTypeWarning, backendEventReasonNamespaceNotAllowed, message)
+	backend.Status.Endpoints = []fleetnetv1beta1.TrafficManagerEndpointStatus{}
+	backend.Status.InvalidClusters = nil
+	backend.Status.TotalInvalid = 0
+	meta.SetStatusCondition(&backend.Status.Conditions, cond)
+	return ctrl.Result{}, r.updateTrafficManagerBackendStatus(ctx, backend)
+}
+
 func (r *Reconciler) deleteAzureTrafficManagerEndpoints(ctx context.Context, backend *fleetnetv1beta1.TrafficManagerBackend) error {
 	backendKObj := klog.KObj(backend)
 	profile := &fleetnetv1beta1.TrafficManagerProfile{}
@@ -163,7 +493,7 @@ func (r *Reconciler) deleteAzureTrafficManagerEndpoints(ctx context.Context, bac
 
 	profileKObj := klog.KObj(profile)
 	atmProfileName := generateAzureTrafficManagerProfileNameFunc(profile)
-	getRes, getErr := r.ProfilesClient.Get(ctx, r.ResourceGroupName, atmProfileName, nil)
+	atmProfile, getErr := r.profiles.get(ctx, r.ProfilesClient, r.ResourceGroupName, atmProfileName)
 	if getErr != nil {
 		if !azureerrors.IsNotFound(getErr) {
 			klog.ErrorS(getErr, "Failed to get the Traffic Manager profile", "trafficManagerBackend", backendKObj, "trafficManagerProfile", profileKObj, "atmProfileName", atmProfileName)
@@ -172,7 +502,7 @@ func (r *Reconciler) deleteAzureTrafficManagerEndpoints(ctx context.Context, bac
 		klog.V(2).InfoS("Azure Traffic Manager profile does not exist", "trafficManagerBackend", backendKObj, "trafficManagerProfile", profileKObj, "atmProfileName", atmProfileName)
 		return nil // skip handling endpoints deletion
 	}
-	return r.cleanupEndpoints(ctx, backend, &getRes.Profile)
+	return r.cleanupEndpoints(ctx, backend, &atmProfile)
 }
 
 func (r *Reconciler) cleanupEndpoints(ctx context.Context, backend *fleetnetv1beta1.TrafficManagerBackend, atmProfile *armtrafficmanager.Profile) error {
@@ -184,6 +514,7 @@ func (r *Reconciler) cleanupEndpoints(ctx context.Context, backend *fleetnetv1be
 
 	klog.V(2).InfoS("Deleting Azure Traffic Manager endpoints", "trafficManagerBackend", backendKObj, "trafficManagerProfile", backend.Spec.Profile.Name)
 	atmProfileName := *atmProfile.Name
+	var skipped []error
 	errs, cctx := errgroup.WithContext(ctx)
 	for i := range atmProfile.Properties.Endpoints {
 		endpoint := atmProfile.Properties.Endpoints[i]
@@ -196,6 +527,10 @@ func (r *Reconciler) cleanupEndpoints(ctx context.Context, backend *fleetnetv1be
 		if !isEndpointOwnedByBackend(backend, *endpoint.Name) {
 			continue // skipping deleting the endpoints which are not created by this backend
 		}
+		if r.ReadOnly {
+			skipped = append(skipped, skipInReadOnlyMode(backend, "delete", atmProfileName, *endpoint.Name))
+			continue
+		}
 		errs.Go(func() error {
 			if _, err := r.EndpointsClient.Delete(cctx, r.ResourceGroupName, atmProfileName, armtrafficmanager.EndpointTypeAzureEndpoints, *endpoint.Name, nil); err != nil {
 				if azureerrors.IsNotFound(err) {
@@ -209,7 +544,65 @@ func (r *Reconciler) cleanupEndpoints(ctx context.Context, backend *fleetnetv1be
 			return nil
 		})
 	}
-	return errs.Wait()
+	if err := errs.Wait(); err != nil {
+		return err
+	}
+	if err := joinReadOnlyErrors(skipped); err != nil {
+		return err
+	}
+	r.recordEndpointWeights(backend, nil)
+	return nil
+}
+
+// skipInReadOnlyMode logs the request on the Azure Traffic Manager endpoint which is skipped in the read-only mode,
+// and returns an error wrapping errReadOnly which describes it.
+func skipInReadOnlyMode(backend *fleetnetv1beta1.TrafficManagerBackend, action, atmProfileName, atmEndpointName string) error {
+	klog.V(2).InfoS("Skipped the Azure Traffic Manager endpoint request in the read-only mode", "trafficManagerBackend", klog.KObj(backend), "action", action, "atmProfile", atmProfileName, "atmEndpoint", atmEndpointName)
+	return fmt.Errorf("%w: would %s endpoint %q of %q", errReadOnly, action, atmEndpointName, atmProfileName)
+}
+
+// joinReadOnlyErrors returns the first of the skipped requests and the number of the others, or nil if none is skipped.
+func joinReadOnlyErrors(skipped []error) error {
+	switch len(skipped) {
+	case 0:
+		return nil
+	case 1:
+		return skipped[0]
+	default:
+		return fmt.Errorf("%w, and %d more request(s)", skipped[0], len(skipped)-1)
+	}
+}
+
+// recordEndpointWeights sets the trafficManagerEndpointWeight metric to the weights of the accepted endpoints of the
+// backend, and deletes the series of the clusters which no longer have an endpoint.
+func (r *Reconciler) recordEndpointWeights(backend *fleetnetv1beta1.TrafficManagerBackend, acceptedEndpoints []fleetnetv1beta1.TrafficManagerEndpointStatus) {
+	if r.weightClusters == nil {
+		return
+	}
+	name := types.NamespacedName{Namespace: backend.Namespace, Name: backend.Name}
+	clusters := make(map[string]bool, len(acceptedEndpoints))
+	for _, endpoint := range acceptedEndpoints {
+		if endpoint.From == nil || endpoint.Weight == nil {
+			continue
+		}
+		clusters[endpoint.From.Cluster] = true
+		trafficManagerEndpointWeight.WithLabelValues(backend.Namespace, backend.Name, endpoint.From.Cluster).Set(float64(*endpoint.Weight))
+	}
+	var old any
+	var loaded bool
+	if len(clusters) == 0 {
+		old, loaded = r.weightClusters.LoadAndDelete(name)
+	} else {
+		old, loaded = r.weightClusters.Swap(name, clusters)
+	}
+	if !loaded {
+		return
+	}
+	for cluster := range old.(map[string]bool) {
+		if !clusters[cluster] {
+			trafficManagerEndpointWeight.DeleteLabelValues(backend.Namespace, backend.Name, cluster)
+		}
+	}
 }
 
 func isEndpointOwnedByBackend(backend *fleetnetv1beta1.TrafficManagerBackend, endpoint string) bool {
@@ -218,6 +611,35 @@ func isEndpointOwnedByBackend(backend *fleetnetv1beta1.TrafficManagerBackend, en
 
 func (r *Reconciler) handleUpdate(ctx context.Context, backend *fleetnetv1beta1.TrafficManagerBackend) (ctrl.Result, error) {
 	backendKObj := klog.KObj(backend)
+	// The invalid clusters are reported only along with the endpoints condition; any other status left by this
+	// reconciliation no longer holds them.
+	backend.Status.InvalidClusters = nil
+	backend.Status.TotalInvalid = 0
+	if err := validateBackendReferences(backend); err != nil {
+		// The references are immutable; the backend stays invalid until it is recreated, so there is no need to requeue.
+		klog.V(2).InfoS("Invalid trafficManagerBackend references", "trafficManagerBackend", backendKObj, "error", err)
+		setFalseCondition(backend, nil, fmt.Sprintf("Invalid trafficManagerBackend spec: %v", err))
+		return ctrl.Result{}, r.updateTrafficManagerBackendStatus(ctx, backend)
+	}
+	if err := validateEndpointOverrides(backend); err != nil {
+		// We don't need to requeue the invalid backend as the controller will be re-triggered when the spec is updated.
+		klog.V(2).InfoS("Invalid endpoint overrides", "trafficManagerBackend", backendKObj, "error", err)
+		setFalseCondition(backend, nil, fmt.Sprintf("Invalid endpoint overrides: %v", err))
+		return ctrl.Result{}, r.updateTrafficManagerBackendStatus(ctx, backend)
+	}
+
+	olderBackend, err := r.findOlderDuplicateBackend(ctx, backend)
+	if err != nil {
+		klog.ErrorS(err, "Failed to list trafficManagerBackends to check for duplicates", "trafficManagerBackend", backendKObj)
+		return ctrl.Result{}, controller.NewAPIServerError(true, err)
+	}
+	if olderBackend != nil {
+		// The backend will be re-triggered when the older backend is deleted.
+		klog.V(2).InfoS("Found an older trafficManagerBackend with the same profile and backend", "trafficManagerBackend", backendKObj, "olderTrafficManagerBackend", klog.KObj(olderBackend))
+		setDuplicateCondition(backend, olderBackend.Name)
+		return ctrl.Result{}, r.updateTrafficManagerBackendStatus(ctx, backend)
+	}
+
 	profile, err := r.validateTrafficManagerProfile(ctx, backend)
 	if err != nil || profile == nil {
 		// We don't need to requeue the invalid Profile (err == nil and profile == nil) because when the profile becomes
@@ -249,10 +671,14 @@ func (r *Reconciler) handleUpdate(ctx context.Context, backend *fleetnetv1beta1.
 
 	if *backend.Spec.Weight == 0 {
 		klog.V(2).InfoS("Weight is 0, deleting all the endpoints", "trafficManagerBackend", backendKObj)
-		if err := r.cleanupEndpoints(ctx, backend, atmProfile); err != nil {
+		if err := r.cleanupEndpoints(ctx, backend, atmProfile); errors.Is(err, errReadOnly) {
+			setReadOnlyCondition(backend, backend.Status.Endpoints, fmt.Sprintf("Not disabled in the %v", err))
+			return ctrl.Result{}, r.updateTrafficManagerBackendStatus(ctx, backend)
+		} else if err != nil {
 			return ctrl.Result{}, err
 		}
-		setTrueCondition(backend, nil)
+		setDisabledCondition(backend)
+		r.refreshLastSyncedTime(backend)
 		return ctrl.Result{}, r.updateTrafficManagerBackendStatus(ctx, backend)
 	}
 
@@ -269,25 +695,106 @@ func (r *Reconciler) handleUpdate(ctx context.Context, backend *fleetnetv1beta1.
 	if err != nil {
 		return ctrl.Result{}, err
 	}
-	if len(invalidServicesMaps) == 0 && len(badEndpointsErr) == 0 {
+	r.recordEndpointWeights(backend, acceptedEndpoints)
+	if len(badEndpointsErr) == 0 {
+		r.refreshLastSyncedTime(backend)
+	}
+	setEndpointsCondition(backend, acceptedEndpoints, badEndpointsErr, invalidServicesMaps)
+	klog.V(2).InfoS("Updated Traffic Manager endpoints for the serviceImport and updating the condition", "trafficManagerBackend", backendKObj, "status", backend.Status)
+	if err := r.updateTrafficManagerBackendStatus(ctx, backend); err != nil {
+		return ctrl.Result{}, err
+	}
+	// Requeue the request when the first of the exported services goes stale, so that its endpoint is removed even
+	// if nothing else changes.
+	return ctrl.Result{RequeueAfter: staleExportRequeueAfter(desiredEndpointsMaps, time.Now())}, nil
+}
+
+// setEndpointsCondition sets the Accepted condition of the backend once its endpoints have been created or updated:
+// ReadOnly when the changes are skipped in the read-only mode, True when all the exported services are accepted, and
+// False with the first bad endpoint and the first invalid exported service otherwise. All the clusters of the bad
+// endpoints and the invalid exported services are listed in the invalid clusters of the status.
+func setEndpointsCondition(backend *fleetnetv1beta1.TrafficManagerBackend, acceptedEndpoints []fleetnetv1beta1.TrafficManagerEndpointStatus, badEndpointsErr []error, invalidServices map[string]error) {
+	backend.Status.InvalidClusters, backend.Status.TotalInvalid = buildInvalidClusters(badEndpointsErr, invalidServices)
+	if len(badEndpointsErr) > 0 && errors.Is(badEndpointsErr[0], errReadOnly) {
+		// No request fails in the read-only mode, so that all the bad endpoints are the skipped ones.
+		setReadOnlyCondition(backend, acceptedEndpoints, fmt.Sprintf("%d change(s) of the Azure Traffic Manager endpoints are not applied in the %v",
+			len(badEndpointsErr), joinReadOnlyErrors(badEndpointsErr)))
+		return
+	}
+	if len(invalidServices) == 0 && len(badEndpointsErr) == 0 {
 		setTrueCondition(backend, acceptedEndpoints)
-	} else {
-		var invalidEndpointErrMessage string
-		if len(badEndpointsErr) > 0 {
-			invalidEndpointErrMessage = fmt.Sprintf("%v endpoint(s) failed to be created/updated in the Azure Traffic Manager, for example, %v; ", len(badEndpointsErr), badEndpointsErr[0])
-		}
-		if len(invalidServicesMaps) > 0 {
-			for clusterID, invalidServiceErr := range invalidServicesMaps {
-				invalidEndpointErrMessage = invalidEndpointErrMessage + fmt.Sprintf("%v service(s) exported from clusters cannot be exposed as the Azure Traffic Manager, for example, service exported from %v is invalid: %v", len(invalidServicesMaps), clusterID, invalidServiceErr)
-				// Here we only populate the message with the first invalid exported service.
-				// Note, the loop of the invalidServicesMaps is not deterministic.
-				break
-			}
+		return
+	}
+	if upgradeClusters := agentUpgradeRequiredClusters(invalidServices); len(upgradeClusters) > 0 && len(badEndpointsErr) == 0 {
+		setFalseConditionWithReason(backend, acceptedEndpoints, fleetnetv1beta1.TrafficManagerBackendReasonAgentUpgradeRequired,
+			fmt.Sprintf("%v service(s) exported from clusters cannot be exposed as the Azure Traffic Manager, as the member agents of the clusters %v must be upgraded, for example, service exported from %v is invalid: %v",
+				len(invalidServices), upgradeClusters, upgradeClusters[0], invalidServices[upgradeClusters[0]]))
+		return
+	}
+	var invalidEndpointErrMessage string
+	if len(badEndpointsErr) > 0 {
+		invalidEndpointErrMessage = fmt.Sprintf("%v endpoint(s) failed to be created/updated in the Azure Traffic Manager, for example, %v; ", len(badEndpointsErr), badEndpointsErr[0])
+	}
+	for clusterID, invalidServiceErr := range invalidServices {
+		invalidEndpointErrMessage = invalidEndpointErrMessage + fmt.Sprintf("%v service(s) exported from clusters cannot be exposed as the Azure Traffic Manager, for example, service exported from %v is invalid: %v", len(invalidServices), clusterID, invalidServiceErr)
+		// Here we only populate the message with the first invalid exported service.
+		// Note, the loop of the invalidServices is not deterministic.
+		break
+	}
+	setFalseCondition(backend, acceptedEndpoints, invalidEndpointErrMessage)
+}
+
+// buildInvalidClusters returns the invalid clusters of the backend sorted by the cluster name, capped at
+// TrafficManagerBackendMaxInvalidClusters entries, and the total number of them. A cluster whose endpoint is rejected
+// by Azure is reported with the Azure error, even if its exported service is reported as invalid too; the requests
+// skipped in the read-only mode are not reported.
+func buildInvalidClusters(badEndpointsErr []error, invalidServices map[string]error) ([]fleetnetv1beta1.TrafficManagerInvalidCluster, int32) {
+	byCluster := make(map[string]fleetnetv1beta1.TrafficManagerInvalidCluster, len(invalidServices)+len(badEndpointsErr))
+	for clusterID, err := range invalidServices {
+		reason := fleetnetv1beta1.TrafficManagerInvalidClusterReasonInvalid
+		var invalidErr *invalidClusterError
+		if errors.As(err, &invalidErr) {
+			reason = invalidErr.reason
 		}
-		setFalseCondition(backend, acceptedEndpoints, invalidEndpointErrMessage)
+		byCluster[clusterID] = fleetnetv1beta1.TrafficManagerInvalidCluster{Cluster: clusterID, Reason: reason, Message: err.Error()}
 	}
-	klog.V(2).InfoS("Updated Traffic Manager endpoints for the serviceImport and updating the condition", "trafficManagerBackend", backendKObj, "status", backend.Status)
-	return ctrl.Result{}, r.updateTrafficManagerBackendStatus(ctx, backend)
+	for _, err := range badEndpointsErr {
+		var badErr *badEndpointError
+		if !errors.As(err, &badErr) {
+			continue
+		}
+		reason := fleetnetv1beta1.TrafficManagerInvalidClusterReasonAzureClientError
+		if azureerrors.IsThrottled(badErr.err) {
+			reason = fleetnetv1beta1.TrafficManagerInvalidClusterReasonThrottled
+		}
+		byCluster[badErr.cluster] = fleetnetv1beta1.TrafficManagerInvalidCluster{Cluster: badErr.cluster, Reason: reason, Message: err.Error()}
+	}
+	if len(byCluster) == 0 {
+		return nil, 0
+	}
+	clusters := make([]string, 0, len(byCluster))
+	for clusterID := range byCluster {
+		clusters = append(clusters, clusterID)
+	}
+	slices.Sort(clusters)
+	res := make([]fleetnetv1beta1.TrafficManagerInvalidCluster, 0, min(len(clusters), fleetnetv1beta1.TrafficManagerBackendMaxInvalidClusters))
+	for _, clusterID := range clusters[:min(len(clusters), fleetnetv1beta1.TrafficManagerBackendMaxInvalidClusters)] {
+		res = append(res, byCluster[clusterID])
+	}
+	return res, int32(len(clusters))
+}
+
+// agentUpgradeRequiredClusters returns the sorted names of the clusters whose exported services are invalid as their
+// member agents have not reported the Azure Traffic Manager information.
+func agentUpgradeRequiredClusters(invalidServices map[string]error) []string {
+	var clusters []string
+	for clusterID, err := range invalidServices {
+		if errors.Is(err, errAgentUpgradeRequired) {
+			clusters = append(clusters, clusterID)
+		}
+	}
+	slices.Sort(clusters)
+	return clusters
 }
 
 // validateTrafficManagerProfile returns not nil profile when the profile is valid.
@@ -311,6 +818,10 @@ func (r *Reconciler) validateTrafficManagerProfile(ctx context.Context, backend
 	programmedCondition := meta.FindStatusCondition(profile.Status.Conditions, string(fleetnetv1beta1.TrafficManagerProfileConditionProgrammed))
 	if condition.IsConditionStatusTrue(programmedCondition, profile.GetGeneration()) {
 		return profile, nil // return directly if the trafficManagerProfile is programmed
+	} else if r.ReadOnly && isReadOnlyCondition(programmedCondition, profile.GetGeneration()) {
+		// The profile is never programmed in the read-only mode; the endpoints are observed against the existing Azure
+		// Traffic Manager profile, if any.
+		return profile, nil
 	} else if condition.IsConditionStatusFalse(programmedCondition, profile.GetGeneration()) {
 		setFalseCondition(backend, nil, fmt.Sprintf("Invalid trafficManagerProfile %q: %v", backend.Spec.Profile.Name, programmedCondition.Message))
 	} else {
@@ -320,12 +831,19 @@ func (r *Reconciler) validateTrafficManagerProfile(ctx context.Context, backend
 	return nil, r.updateTrafficManagerBackendStatus(ctx, backend)
 }
 
+// isReadOnlyCondition returns true if the Programmed condition of the profile of the given generation reports that the
+// profile is not programmed in the read-only mode.
+func isReadOnlyCondition(cond *metav1.Condition, generation int64) bool {
+	return cond != nil && cond.ObservedGeneration == generation && cond.Status == metav1.ConditionUnknown &&
+		cond.Reason == string(fleetnetv1beta1.TrafficManagerProfileReasonReadOnly)
+}
+
 // validateAzureTrafficManagerProfile returns not nil Azure Traffic Manager profile when the atm profile is valid.
 func (r *Reconciler) validateAzureTrafficManagerProfile(ctx context.Context, backend *fleetnetv1beta1.TrafficManagerBackend, profile *fleetnetv1beta1.TrafficManagerProfile) (*armtrafficmanager.Profile, error) {
 	atmProfileName := generateAzureTrafficManagerProfileNameFunc(profile)
 	backendKObj := klog.KObj(backend)
 	profileKObj := klog.KObj(profile)
-	getRes, getErr := r.ProfilesClient.Get(ctx, r.ResourceGroupName, atmProfileName, nil)
+	atmProfile, getErr := r.profiles.get(ctx, r.ProfilesClient, r.ResourceGroupName, atmProfileName)
 	if getErr != nil {
 		if azureerrors.IsNotFound(getErr) {
 			// We've already checked the TrafficManagerProfile condition before getting Azure resource.
@@ -346,7 +864,7 @@ func (r *Reconciler) validateAzureTrafficManagerProfile(ctx context.Context, bac
 		}
 		return nil, getErr // need to return the error to requeue the request
 	}
-	return &getRes.Profile, nil
+	return &atmProfile, nil
 }
 
 // validateServiceImportAndCleanupEndpointsIfInvalid returns not nil serviceImport when the serviceImport is valid.
@@ -357,7 +875,10 @@ func (r *Reconciler) validateServiceImportAndCleanupEndpointsIfInvalid(ctx conte
 	if getServiceImportErr := r.Client.Get(ctx, types.NamespacedName{Name: backend.Spec.Backend.Name, Namespace: backend.Namespace}, serviceImport); getServiceImportErr != nil {
 		if apierrors.IsNotFound(getServiceImportErr) {
 			klog.V(2).InfoS("NotFound serviceImport and starting deleting any stale endpoints", "trafficManagerBackend", backendKObj, "serviceImport", backend.Spec.Backend.Name)
-			if err := r.cleanupEndpoints(ctx, backend, azureProfile); err != nil {
+			if err := r.cleanupEndpoints(ctx, backend, azureProfile); errors.Is(err, errReadOnly) {
+				setReadOnlyCondition(backend, backend.Status.Endpoints, fmt.Sprintf("ServiceImport %q is not found and its endpoints are not deleted in the %v", backend.Spec.Backend.Name, err))
+				return nil, r.updateTrafficManagerBackendStatus(ctx, backend)
+			} else if err != nil {
 				klog.ErrorS(err, "Failed to delete stale endpoints for an invalid serviceImport", "trafficManagerBackend", backendKObj, "serviceImport", backend.Spec.Backend.Name)
 				return nil, err
 			}
@@ -383,11 +904,16 @@ func (r *Reconciler) validateServiceImportAndCleanupEndpointsIfInvalid(ctx conte
 }
 
 func setFalseCondition(backend *fleetnetv1beta1.TrafficManagerBackend, acceptedEndpoints []fleetnetv1beta1.TrafficManagerEndpointStatus, message string) {
+	setFalseConditionWithReason(backend, acceptedEndpoints, fleetnetv1beta1.TrafficManagerBackendReasonInvalid, message)
+}
+
+// setFalseConditionWithReason sets the Accepted condition of the backend to False with the given reason.
+func setFalseConditionWithReason(backend *fleetnetv1beta1.TrafficManagerBackend, acceptedEndpoints []fleetnetv1beta1.TrafficManagerEndpointStatus, reason fleetnetv1beta1.TrafficManagerBackendConditionReason, message string) {
 	cond := metav1.Condition{
 		Type:               string(fleetnetv1beta1.TrafficManagerBackendConditionAccepted),
 		Status:             metav1.ConditionFalse,
 		ObservedGeneration: backend.Generation,
-		Reason:             string(fleetnetv1beta1.TrafficManagerBackendReasonInvalid),
+		Reason:             string(reason),
 		Message:            message,
 	}
 	if len(acceptedEndpoints) == 0 {
@@ -398,6 +924,21 @@ func setFalseCondition(backend *fleetnetv1beta1.TrafficManagerBackend, acceptedE
 	meta.SetStatusCondition(&backend.Status.Conditions, cond)
 }
 
+// setDuplicateCondition reports that the backend is shadowed by an older backend with the same profile and backend,
+// so that none of its endpoints are configured on the Azure Traffic Manager profile.
+func setDuplicateCondition(backend *fleetnetv1beta1.TrafficManagerBackend, olderBackendName string) {
+	cond := metav1.Condition{
+		Type:               string(fleetnetv1beta1.TrafficManagerBackendConditionAccepted),
+		Status:             metav1.ConditionFalse,
+		ObservedGeneration: backend.Generation,
+		Reason:             string(fleetnetv1beta1.TrafficManagerBackendReasonDuplicate),
+		Message: fmt.Sprintf("TrafficManagerBackend %q already refers to trafficManagerProfile %q and serviceImport %q; this backend will be accepted once %q is deleted",
+			olderBackendName, backend.Spec.Profile.Name, backend.Spec.Backend.Name, olderBackendName),
+	}
+	backend.Status.Endpoints = []fleetnetv1beta1.TrafficManagerEndpointStatus{}
+	meta.SetStatusCondition(&backend.Status.Conditions, cond)
+}
+
 func setUnknownCondition(backend *fleetnetv1beta1.TrafficManagerBackend, message string) {
 	cond := metav1.Condition{
 		Type:               string(fleetnetv1beta1.TrafficManagerBackendConditionAccepted),
@@ -422,19 +963,62 @@ func setTrueCondition(backend *fleetnetv1beta1.TrafficManagerBackend, acceptedEn
 	meta.SetStatusCondition(&backend.Status.Conditions, cond)
 }
 
+// setReadOnlyCondition reports that the changes of the Azure Traffic Manager endpoints are not applied in the read-only
+// mode; the accepted endpoints are the existing ones which need no change.
+func setReadOnlyCondition(backend *fleetnetv1beta1.TrafficManagerBackend, acceptedEndpoints []fleetnetv1beta1.TrafficManagerEndpointStatus, message string) {
+	cond := metav1.Condition{
+		Type:               string(fleetnetv1beta1.TrafficManagerBackendConditionAccepted),
+		Status:             metav1.ConditionUnknown,
+		ObservedGeneration: backend.Generation,
+		Reason:             string(fleetnetv1beta1.TrafficManagerBackendReasonReadOnly),
+		Message:            message,
+	}
+	if len(acceptedEndpoints) == 0 {
+		backend.Status.Endpoints = []fleetnetv1beta1.TrafficManagerEndpointStatus{}
+	} else {
+		backend.Status.Endpoints = acceptedEndpoints
+	}
+	meta.SetStatusCondition(&backend.Status.Conditions, cond)
+}
+
+// setDisabledCondition reports that the backend is disabled by its weight of 0, so that none of its endpoints are
+// in the Azure Traffic Manager profile; it is distinguished from a backend without any exported service.
+func setDisabledCondition(backend *fleetnetv1beta1.TrafficManagerBackend) {
+	cond := metav1.Condition{
+		Type:               string(fleetnetv1beta1.TrafficManagerBackendConditionAccepted),
+		Status:             metav1.ConditionTrue,
+		ObservedGeneration: backend.Generation,
+		Reason:             string(fleetnetv1beta1.TrafficManagerBackendReasonDisabled),
+		Message:            "The weight is 0, all the endpoints have been removed from the Azure Traffic Manager profile",
+	}
+	backend.Status.Endpoints = nil
+	meta.SetStatusCondition(&backend.Status.Conditions, cond)
+}
+
 func (r *Reconciler) updateTrafficManagerBackendStatus(ctx context.Context, backend *fleetnetv1beta1.TrafficManagerBackend) error {
 	backendKObj := klog.KObj(backend)
 	if err := r.Client.Status().Update(ctx, backend); err != nil {
 		klog.ErrorS(err, "Failed to update trafficManagerBackend status", "trafficManagerBackend", backendKObj)
 		return controller.NewUpdateIgnoreConflictError(err)
 	}
+	if backend.Status.LastSyncedTime != nil {
+		trafficManagerBackendLastSyncedTime.WithLabelValues(backend.Namespace, backend.Name).Set(float64(backend.Status.LastSyncedTime.Unix()))
+	}
 	klog.V(2).InfoS("Updated trafficManagerBackend status", "trafficManagerBackend", backendKObj, "status", backend.Status)
 	return nil
 }
 
+// refreshLastSyncedTime records in the status that all the Azure Traffic Manager operations of the reconciliation
+// have succeeded.
+func (r *Reconciler) refreshLastSyncedTime(backend *fleetnetv1beta1.TrafficManagerBackend) {
+	backend.Status.LastSyncedTime = trafficmanagerprofile.NextLastSyncedTime(backend.Status.LastSyncedTime, time.Now(), r.LastSyncedTimeUpdateInterval)
+}
+
 type desiredEndpoint struct {
 	Endpoint armtrafficmanager.Endpoint
 	Cluster  fleetnetv1beta1.ClusterStatus
+	// StaleAt is the time at which the exported service goes stale, if it has not been refreshed by then.
+	StaleAt time.Time
 }
 
 // validateExportedServiceForServiceImport returns two maps:
@@ -466,11 +1050,13 @@ func (r *Reconciler) validateExportedServiceForServiceImport(ctx context.Context
 		}
 		return nil, nil, listErr
 	}
+	listguard.ObserveListSize(internalServiceExportList, "trafficManagerBackend", backendKObj, "serviceImport", serviceImportKObj)
 	internalServiceExportMap := make(map[string]*fleetnetv1alpha1.InternalServiceExport, len(internalServiceExportList.Items))
 	for i, export := range internalServiceExportList.Items {
 		internalServiceExportMap[export.Spec.ServiceReference.ClusterID] = &internalServiceExportList.Items[i]
 	}
 
+	now := time.Now()
 	desiredEndpoints := make(map[string]desiredEndpoint, len(serviceImport.Status.Clusters)) // key is the endpoint name
 	invalidServices := make(map[string]error, len(serviceImport.Status.Clusters))            // key is cluster name
 	for _, clusterStatus := range serviceImport.Status.Clusters {
@@ -489,39 +1075,305 @@ func (r *Reconciler) validateExportedServiceForServiceImport(ctx context.Context
 			klog.V(2).InfoS("Invalid service for TrafficManager endpoint", "trafficManagerBackend", backendKObj, "serviceImport", serviceImportKObj, "clusterID", clusterStatus.Cluster, "error", err)
 			continue
 		}
+		staleAt, err := checkExportFreshness(r.heartbeatObservedAt(internalServiceExport, now), r.MaxExportStaleness, now)
+		if err != nil {
+			invalidServices[clusterStatus.Cluster] = err
+			klog.V(2).InfoS("Stale service for TrafficManager endpoint", "trafficManagerBackend", backendKObj, "serviceImport", serviceImportKObj, "clusterID", clusterStatus.Cluster, "error", err)
+			continue
+		}
+		if err := checkExternalTrafficPolicy(internalServiceExport); err != nil {
+			// The service is reported in the condition, so that the operators verify the health checks are served by
+			// enough nodes, but it is still exposed unless rejected explicitly.
+			invalidServices[clusterStatus.Cluster] = err
+			klog.V(2).InfoS("Service with Local external traffic policy for TrafficManager endpoint", "trafficManagerBackend", backendKObj, "serviceImport", serviceImportKObj, "clusterID", clusterStatus.Cluster, "rejected", r.RejectLocalExternalTrafficPolicy, "error", err)
+			if r.RejectLocalExternalTrafficPolicy {
+				continue
+			}
+		}
 		endpoint := generateAzureTrafficManagerEndpoint(backend, internalServiceExport)
 		desiredEndpoints[*endpoint.Name] = desiredEndpoint{
 			Endpoint: endpoint,
 			Cluster: fleetnetv1beta1.ClusterStatus{
 				Cluster: clusterStatus.Cluster,
 			},
+			StaleAt: staleAt,
 		}
 	}
-	desiredWeight := int(math.Ceil(float64(*backend.Spec.Weight) / float64(len(desiredEndpoints))))
+	desiredWeight := splitWeight(*backend.Spec.Weight, len(desiredEndpoints))
+	for _, dp := range desiredEndpoints {
+		dp.Endpoint.Properties.Weight = ptr.To(desiredWeight)
+	}
+	clusters := make([]string, 0, len(desiredEndpoints))
 	for _, dp := range desiredEndpoints {
-		dp.Endpoint.Properties.Weight = ptr.To(int64(desiredWeight))
+		clusters = append(clusters, dp.Cluster.Cluster)
+	}
+	priorities := assignEndpointPriorities(backend, clusters)
+	for _, dp := range desiredEndpoints {
+		if priority, ok := priorities[dp.Cluster.Cluster]; ok {
+			dp.Endpoint.Properties.Priority = ptr.To(priority)
+		}
 	}
 	klog.V(2).InfoS("Finishing validating services", "trafficManagerBackend", backendKObj, "serviceImport", serviceImportKObj, "numberOfDesiredEndpoints", len(desiredEndpoints), "numberOfInvalidServices", len(invalidServices), "desiredWeight", desiredWeight)
 	return desiredEndpoints, invalidServices, nil
 }
 
+// splitWeight returns the weight of each of the n endpoints sharing the weight of a backend; the weight is rounded up,
+// so that the sum of the endpoint weights is never less than the backend weight.
+func splitWeight(weight int64, n int) int64 {
+	return int64(math.Ceil(float64(weight) / float64(n)))
+}
+
+// assignEndpointPriorities returns the priorities of the endpoints of the given clusters (key is the cluster name).
+// The clusters listed in the priorities of the backend get the listed priorities, and the other clusters get the next
+// unused priorities after the highest listed one in the order of their names, wrapping around to the lowest unused
+// priorities when running out of range, so that the assignment is deterministic.
+// It returns nil when the backend lists no priority, leaving the priorities to Azure.
+func assignEndpointPriorities(backend *fleetnetv1beta1.TrafficManagerBackend, clusters []string) map[string]int64 {
+	if len(backend.Spec.Priorities) == 0 {
+		return nil
+	}
+	res := make(map[string]int64, len(clusters))
+	listed := make(map[string]int64, len(backend.Spec.Priorities))
+	used := make(map[int64]bool, len(backend.Spec.Priorities)+len(clusters))
+	var highest int64
+	for _, priority := range backend.Spec.Priorities {
+		listed[priority.Cluster] = priority.Priority
+		used[priority.Priority] = true
+		highest = max(highest, priority.Priority)
+	}
+	var unlisted []string
+	for _, cluster := range clusters {
+		if priority, ok := listed[cluster]; ok {
+			res[cluster] = priority
+			continue
+		}
+		unlisted = append(unlisted, cluster)
+	}
+	slices.Sort(unlisted)
+	next := highest
+	for _, cluster := range unlisted {
+		assigned := false
+		for range maxEndpointPriority {
+			next = next%maxEndpointPriority + 1
+			if !used[next] {
+				assigned = true
+				break
+			}
+		}
+		if !assigned {
+			// All the priorities are used; the endpoint is left to Azure, which rejects it as a bad endpoint.
+			break
+		}
+		used[next] = true
+		res[cluster] = next
+	}
+	return res
+}
+
 // isValidTrafficManagerEndpoint returns error if the service cannot be added as a TrafficManager endpoint.
 func isValidTrafficManagerEndpoint(export *fleetnetv1alpha1.InternalServiceExport) error {
-	if export.Spec.Type != corev1.ServiceTypeLoadBalancer {
-		return fmt.Errorf("unsupported service type %q", export.Spec.Type)
+	if !isTrafficManagerInfoReported(export) {
+		return invalidClusterErrorf(fleetnetv1beta1.TrafficManagerInvalidClusterReasonAgentUpgradeRequired, "%w: the member agent of cluster %q has not reported the type, the public IP address and the DNS label of the service; upgrade the member agent, or enable its traffic manager feature",
+			errAgentUpgradeRequired, export.Spec.ServiceReference.ClusterID)
 	}
-	if export.Spec.IsInternalLoadBalancer {
-		return fmt.Errorf("internal load balancer is not supported")
+	// The public IP specified explicitly for the service, e.g. the public IP of a Gateway, has been validated by the
+	// member agent, and does not depend on the load balancer of the service.
+	if !export.Spec.IsPublicIPExplicit {
+		if export.Spec.Type != corev1.ServiceTypeLoadBalancer {
+			return invalidClusterErrorf(fleetnetv1beta1.TrafficManagerInvalidClusterReasonUnsupportedType, "unsupported service type %q; only LoadBalancer services or services exported with a public IP address can be added as Azure Traffic Manager endpoints", export.Spec.Type)
+		}
+		if export.Spec.IsInternalLoadBalancer {
+			return invalidClusterErrorf(fleetnetv1beta1.TrafficManagerInvalidClusterReasonInternalLoadBalancer, "internal load balancer is not supported")
+		}
+		if export.Spec.IsLoadBalancerPending {
+			// The DNS label cannot be configured to the public IP until the IP is provisioned.
+			return invalidClusterErrorf(fleetnetv1beta1.TrafficManagerInvalidClusterReasonLoadBalancerPending, "load balancer IP not yet provisioned")
+		}
 	}
 	if !export.Spec.IsDNSLabelConfigured {
-		return fmt.Errorf("DNS label is not configured to the public IP")
+		return invalidClusterErrorf(fleetnetv1beta1.TrafficManagerInvalidClusterReasonNoDNSLabel, "DNS label is not configured to the public IP")
+	}
+	// Azure Traffic Manager can only probe the endpoint health over HTTP, HTTPS or TCP.
+	for _, port := range export.Spec.Ports {
+		if port.Protocol != "" && port.Protocol != corev1.ProtocolTCP {
+			return invalidClusterErrorf(fleetnetv1beta1.TrafficManagerInvalidClusterReasonUnsupportedProtocol, "unsupported protocol %q of port %d; only TCP is supported by Azure Traffic Manager", port.Protocol, port.Port)
+		}
+	}
+	return nil
+}
+
+// isTrafficManagerInfoReported returns false if the Azure Traffic Manager information of the exported service is left
+// unset by its member agent, e.g. as the member agent predates it or runs without the traffic manager feature, rather
+// than set to the zero values: neither the member agent marks the information as reported, nor reports the type of
+// the service, any public IP address or DNS label.
+func isTrafficManagerInfoReported(export *fleetnetv1alpha1.InternalServiceExport) bool {
+	return export.Annotations[objectmeta.InternalServiceExportAnnotationTrafficManagerInfoReported] == "true" ||
+		export.Spec.Type != "" || export.Spec.PublicIPResourceID != nil || export.Spec.IsPublicIPExplicit || export.Spec.IsDNSLabelConfigured
+}
+
+// checkExternalTrafficPolicy returns an error if the Azure Traffic Manager health checks of the service may not be
+// served by every node of its cluster, i.e., its external traffic policy is Local.
+func checkExternalTrafficPolicy(export *fleetnetv1alpha1.InternalServiceExport) error {
+	if export.Spec.ExternalTrafficPolicy != corev1.ServiceExternalTrafficPolicyLocal {
+		return nil
+	}
+	return invalidClusterErrorf(fleetnetv1beta1.TrafficManagerInvalidClusterReasonLocalExternalTrafficPolicy, "%s: the monitor port is served only by the nodes running the ready pods of the service, as reported by the health check node port %d; verify that every node behind the load balancer is covered", localExternalTrafficPolicyReason, export.Spec.HealthCheckNodePort)
+}
+
+// heartbeatObservedAt returns the time at which the hub agent first observed the current heartbeat of the exported
+// service, or zero if the service has no heartbeat. A heartbeat which has not been observed before, e.g., after the
+// hub agent restarts, is observed now.
+func (r *Reconciler) heartbeatObservedAt(export *fleetnetv1alpha1.InternalServiceExport, now time.Time) time.Time {
+	key := types.NamespacedName{Namespace: export.Namespace, Name: export.Name}
+	lastHeartbeatTime := export.Status.LastHeartbeatTime
+	if lastHeartbeatTime == nil {
+		if r.heartbeats != nil {
+			r.heartbeats.Delete(key)
+		}
+		return time.Time{}
+	}
+	if r.heartbeats == nil {
+		return lastHeartbeatTime.Time
+	}
+	if v, ok := r.heartbeats.Load(key); ok {
+		if observation := v.(heartbeatObservation); observation.heartbeat.Equal(lastHeartbeatTime.Time) {
+			return observation.observedAt
+		}
+	}
+	r.heartbeats.Store(key, heartbeatObservation{heartbeat: lastHeartbeatTime.Time, observedAt: now})
+	return now
+}
+
+// checkExportFreshness returns an error if the heartbeat of the exported service, first observed at the given time,
+// has not been refreshed within the max staleness; otherwise, it returns the time at which the service goes stale, or
+// zero if it never does.
+func checkExportFreshness(heartbeatObservedAt time.Time, maxStaleness time.Duration, now time.Time) (time.Time, error) {
+	// The services exported by the member agents which do not refresh the heartbeat never go stale.
+	if maxStaleness == 0 || heartbeatObservedAt.IsZero() {
+		return time.Time{}, nil
+	}
+	staleAt := heartbeatObservedAt.Add(maxStaleness)
+	if !now.Before(staleAt) {
+		return time.Time{}, invalidClusterErrorf(fleetnetv1beta1.TrafficManagerInvalidClusterReasonStaleExport, "%s: the exported service has not been refreshed by its member cluster since %s", staleExportReason, heartbeatObservedAt.UTC().Format(time.RFC3339))
+	}
+	return staleAt, nil
+}
+
+// staleExportRequeueAfter returns the duration until the first of the desired endpoints goes stale, or zero if none
+// of them does.
+func staleExportRequeueAfter(desiredEndpoints map[string]desiredEndpoint, now time.Time) time.Duration {
+	var requeueAfter time.Duration
+	for _, dp := range desiredEndpoints {
+		if dp.StaleAt.IsZero() {
+			continue
+		}
+		// Add a second so that the service is surely stale when the request is processed.
+		staleIn := dp.StaleAt.Sub(now) + time.Second
+		if requeueAfter == 0 || staleIn < requeueAfter {
+			requeueAfter = staleIn
+		}
+	}
+	return requeueAfter
+}
+
+// findOlderDuplicateBackend returns the oldest of the other backends in the namespace which refer to the same
+// trafficManagerProfile and serviceImport if it is older than the given backend, or nil otherwise.
+// The backends are listed without the field indexes, as the indexes skip the backends of other shards.
+func (r *Reconciler) findOlderDuplicateBackend(ctx context.Context, backend *fleetnetv1beta1.TrafficManagerBackend) (*fleetnetv1beta1.TrafficManagerBackend, error) {
+	backendList := &fleetnetv1beta1.TrafficManagerBackendList{}
+	if err := r.Client.List(ctx, backendList, client.InNamespace(backend.Namespace)); err != nil {
+		return nil, err
+	}
+	listguard.ObserveListSize(backendList, "trafficManagerBackend", klog.KObj(backend))
+	var oldest *fleetnetv1beta1.TrafficManagerBackend
+	for i := range backendList.Items {
+		other := &backendList.Items[i]
+		if other.Name == backend.Name || !isDuplicateBackend(backend, other) {
+			continue
+		}
+		if isOlderBackend(other, backend) && (oldest == nil || isOlderBackend(other, oldest)) {
+			oldest = other
+		}
+	}
+	return oldest, nil
+}
+
+// isDuplicateBackend returns true if the two backends refer to the same trafficManagerProfile and serviceImport.
+func isDuplicateBackend(backend, other *fleetnetv1beta1.TrafficManagerBackend) bool {
+	return backend.Spec.Profile.Name == other.Spec.Profile.Name && backend.Spec.Backend.Name == other.Spec.Backend.Name
+}
+
+// isOlderBackend returns true if backend a is created before backend b; the names break the tie, as the creation
+// timestamps only have the precision of a second.
+func isOlderBackend(a, b *fleetnetv1beta1.TrafficManagerBackend) bool {
+	if !a.CreationTimestamp.Equal(&b.CreationTimestamp) {
+		return a.CreationTimestamp.Before(&b.CreationTimestamp)
+	}
+	return a.Name < b.Name
+}
+
+// validateBackendReferences returns an error if the name of the referenced trafficManagerProfile or serviceImport is
+// empty or is not a valid object name, so that such a backend is rejected before any API server or Azure call.
+func validateBackendReferences(backend *fleetnetv1beta1.TrafficManagerBackend) error {
+	refs := []struct {
+		field string
+		name  string
+	}{
+		{field: "spec.profile.name", name: backend.Spec.Profile.Name},
+		{field: "spec.backend.name", name: backend.Spec.Backend.Name},
+	}
+	for _, ref := range refs {
+		if ref.name == "" {
+			return fmt.Errorf("%s must not be empty", ref.field)
+		}
+		if errs := validation.IsDNS1123Subdomain(ref.name); len(errs) > 0 {
+			return fmt.Errorf("%s %q is not a valid name: %s", ref.field, ref.name, strings.Join(errs, "; "))
+		}
+	}
+	return nil
+}
+
+// validateEndpointOverrides returns an error if the subnet overrides of the backend are not valid CIDRs or the subnets
+// of different clusters overlap, or if the priorities of the backend are out of range or not unique.
+func validateEndpointOverrides(backend *fleetnetv1beta1.TrafficManagerBackend) error {
+	clusterByPriority := make(map[int64]string, len(backend.Spec.Priorities))
+	for _, priority := range backend.Spec.Priorities {
+		if priority.Priority < minEndpointPriority || priority.Priority > maxEndpointPriority {
+			return fmt.Errorf("priority %d of cluster %q is out of range [%d, %d]", priority.Priority, priority.Cluster, minEndpointPriority, maxEndpointPriority)
+		}
+		if cluster, ok := clusterByPriority[priority.Priority]; ok {
+			return fmt.Errorf("priority %d of cluster %q is already assigned to cluster %q", priority.Priority, priority.Cluster, cluster)
+		}
+		clusterByPriority[priority.Priority] = priority.Cluster
+	}
+
+	type clusterPrefix struct {
+		cluster string
+		prefix  netip.Prefix
+	}
+	var prefixes []clusterPrefix
+	for _, override := range backend.Spec.SubnetOverrides {
+		for _, subnet := range override.Subnets {
+			prefix, err := netip.ParsePrefix(subnet)
+			if err != nil {
+				return fmt.Errorf("invalid subnet %q of cluster %q: %w", subnet, override.Cluster, err)
+			}
+			prefix = prefix.Masked()
+			for _, p := range prefixes {
+				if p.cluster != override.Cluster && p.prefix.Overlaps(prefix) {
+					return fmt.Errorf("subnet %q of cluster %q overlaps with subnet %q of cluster %q", subnet, override.Cluster, p.prefix, p.cluster)
+				}
+			}
+			prefixes = append(prefixes, clusterPrefix{cluster: override.Cluster, prefix: prefix})
+		}
 	}
 	return nil
 }
 
 func generateAzureTrafficManagerEndpoint(backend *fleetnetv1beta1.TrafficManagerBackend, service *fleetnetv1alpha1.InternalServiceExport) armtrafficmanager.Endpoint {
-	endpointName := fmt.Sprintf(AzureResourceEndpointNameFormat, generateAzureTrafficManagerEndpointNamePrefixFunc(backend), backend.Spec.Backend.Name, service.Spec.ServiceReference.ClusterID)
-	return armtrafficmanager.Endpoint{
+	clusterID := service.Spec.ServiceReference.ClusterID
+	endpointName := fmt.Sprintf(AzureResourceEndpointNameFormat, generateAzureTrafficManagerEndpointNamePrefixFunc(backend), backend.Spec.Backend.Name, clusterID)
+	endpoint := armtrafficmanager.Endpoint{
 		Name: &endpointName,
 		Type: ptr.To(string("Microsoft.Network/trafficManagerProfiles/" + armtrafficmanager.EndpointTypeAzureEndpoints)),
 		Properties: &armtrafficmanager.EndpointProperties{
@@ -529,39 +1381,133 @@ func generateAzureTrafficManagerEndpoint(backend *fleetnetv1beta1.TrafficManager
 			EndpointStatus:   ptr.To(armtrafficmanager.EndpointStatusEnabled),
 		},
 	}
+	for _, header := range backend.Spec.CustomHeaders {
+		endpoint.Properties.CustomHeaders = append(endpoint.Properties.CustomHeaders, &armtrafficmanager.EndpointPropertiesCustomHeadersItem{
+			Name:  ptr.To(header.Name),
+			Value: ptr.To(header.Value),
+		})
+	}
+	for _, override := range backend.Spec.SubnetOverrides {
+		if override.Cluster != clusterID {
+			continue
+		}
+		for _, subnet := range override.Subnets {
+			// The subnets have been validated by validateEndpointOverrides.
+			prefix := netip.MustParsePrefix(subnet).Masked()
+			endpoint.Properties.Subnets = append(endpoint.Properties.Subnets, &armtrafficmanager.EndpointPropertiesSubnetsItem{
+				First: ptr.To(prefix.Addr().String()),
+				Scope: ptr.To(int32(prefix.Bits())),
+			})
+		}
+	}
+	return endpoint
 }
 
 func buildAcceptedEndpointStatus(endpoint *armtrafficmanager.Endpoint, cluster fleetnetv1beta1.ClusterStatus) fleetnetv1beta1.TrafficManagerEndpointStatus {
-	return fleetnetv1beta1.TrafficManagerEndpointStatus{
+	status := fleetnetv1beta1.TrafficManagerEndpointStatus{
 		Name:   strings.ToLower(*endpoint.Name), // name is case-insensitive
 		Target: endpoint.Properties.Target,
 		Weight: endpoint.Properties.Weight,
 		From: &fleetnetv1beta1.FromCluster{
 			ClusterStatus: cluster,
 		},
+		Subnets:  endpointSubnets(endpoint.Properties.Subnets),
+		Priority: endpoint.Properties.Priority,
+	}
+	for _, header := range endpoint.Properties.CustomHeaders {
+		if header == nil || header.Name == nil {
+			continue
+		}
+		status.CustomHeaders = append(status.CustomHeaders, fleetnetv1beta1.TrafficManagerEndpointCustomHeader{
+			Name:  *header.Name,
+			Value: ptr.Deref(header.Value, ""),
+		})
 	}
+	return status
+}
+
+// endpointSubnets returns the subnets of the Azure Traffic Manager endpoint in CIDR notation; the subnets given as
+// address ranges are returned as "{first}-{last}".
+func endpointSubnets(subnets []*armtrafficmanager.EndpointPropertiesSubnetsItem) []string {
+	var res []string
+	for _, subnet := range subnets {
+		if subnet == nil || subnet.First == nil {
+			continue
+		}
+		switch {
+		case subnet.Scope != nil:
+			res = append(res, fmt.Sprintf("%s/%d", *subnet.First, *subnet.Scope))
+		case subnet.Last != nil:
+			res = append(res, fmt.Sprintf("%s-%s", *subnet.First, *subnet.Last))
+		default:
+			res = append(res, *subnet.First)
+		}
+	}
+	return res
+}
+
+func equalCustomHeaders(current, desired []*armtrafficmanager.EndpointPropertiesCustomHeadersItem) bool {
+	if len(current) != len(desired) {
+		return false
+	}
+	for i := range current {
+		if current[i] == nil || current[i].Name == nil || current[i].Value == nil ||
+			*current[i].Name != *desired[i].Name || *current[i].Value != *desired[i].Value {
+			return false
+		}
+	}
+	return true
+}
+
+func equalSubnets(current, desired []*armtrafficmanager.EndpointPropertiesSubnetsItem) bool {
+	currentSubnets, desiredSubnets := endpointSubnets(current), endpointSubnets(desired)
+	if len(currentSubnets) != len(desiredSubnets) {
+		return false
+	}
+	for i := range currentSubnets {
+		if currentSubnets[i] != desiredSubnets[i] {
+			return false
+		}
+	}
+	return true
 }
 
 // equalAzureTrafficManagerEndpoint compares only few fields of the current and desired Azure Traffic Manager endpoints
 // by ignoring others.
 // The desired endpoint is built by the controllers and all the required fields should not be nil.
 func equalAzureTrafficManagerEndpoint(current, desired armtrafficmanager.Endpoint) bool {
+	return equalAzureTrafficManagerEndpointExceptWeight(current, desired) &&
+		*current.Properties.Weight == *desired.Properties.Weight
+}
+
+// equalAzureTrafficManagerEndpointExceptWeight is equalAzureTrafficManagerEndpoint ignoring the weight, which may still
+// be rebalanced across the accepted endpoints.
+func equalAzureTrafficManagerEndpointExceptWeight(current, desired armtrafficmanager.Endpoint) bool {
 	if current.Type == nil || *current.Type != *desired.Type {
 		return false
 	}
 	if current.Properties == nil || current.Properties.TargetResourceID == nil || current.Properties.Weight == nil || current.Properties.EndpointStatus == nil {
 		return false
 	}
+	// The priority is left to Azure when the backend lists no priority.
+	if desired.Properties.Priority != nil && (current.Properties.Priority == nil || *current.Properties.Priority != *desired.Properties.Priority) {
+		return false
+	}
 	return strings.EqualFold(*current.Properties.TargetResourceID, *desired.Properties.TargetResourceID) &&
-		*current.Properties.Weight == *desired.Properties.Weight &&
-		*current.Properties.EndpointStatus == *desired.Properties.EndpointStatus
+		*current.Properties.EndpointStatus == *desired.Properties.EndpointStatus &&
+		equalCustomHeaders(current.Properties.CustomHeaders, desired.Properties.CustomHeaders) &&
+		equalSubnets(current.Properties.Subnets, desired.Properties.Subnets)
 }
 
 // updateTrafficManagerEndpointsAndUpdateStatusIfUnknown updates the Azure Traffic Manager endpoints.
 // Returns the accepted endpoints and a list of bad endpoints error when it fails to create/update endpoint or not because of bad request.
+// In the read-only mode, the requests are skipped instead and returned as the bad endpoints errors wrapping errReadOnly.
 func (r *Reconciler) updateTrafficManagerEndpointsAndUpdateStatusIfUnknown(ctx context.Context, backend *fleetnetv1beta1.TrafficManagerBackend, profile *armtrafficmanager.Profile, desiredEndpoints map[string]desiredEndpoint) ([]fleetnetv1beta1.TrafficManagerEndpointStatus, []error, error) {
 	backendKObj := klog.KObj(backend)
+	// Keep all the desired endpoints, as the ones which need no update are removed from the desiredEndpoints below.
+	allDesiredEndpoints := maps.Clone(desiredEndpoints)
 	acceptedEndpoints := make([]fleetnetv1beta1.TrafficManagerEndpointStatus, 0, len(desiredEndpoints))
+	var skipped []error
 	for _, endpoint := range profile.Properties.Endpoints {
 		if endpoint.Name == nil {
 			err := controller.NewUnexpectedBehaviorError(errors.New("azure Traffic Manager endpoint name is nil"))
@@ -575,6 +1521,10 @@ func (r *Reconciler) updateTrafficManagerEndpointsAndUpdateStatusIfUnknown(ctx c
 		}
 
 		desired, ok := desiredEndpoints[endpointName]
+		if !ok && r.ReadOnly {
+			skipped = append(skipped, skipInReadOnlyMode(backend, "delete", *profile.Name, endpointName))
+			continue
+		}
 		if !ok {
 			klog.V(2).InfoS("Deleting the Azure Traffic Manager endpoint", "trafficManagerBackend", backendKObj, "atmProfile", profile.Name, "atmEndpoint", endpointName)
 			if _, deleteErr := r.EndpointsClient.Delete(ctx, r.ResourceGroupName, *profile.Name, armtrafficmanager.EndpointTypeAzureEndpoints, *endpoint.Name, nil); deleteErr != nil {
@@ -592,19 +1542,41 @@ func (r *Reconciler) updateTrafficManagerEndpointsAndUpdateStatusIfUnknown(ctx c
 			klog.V(2).InfoS("Deleted the Azure Traffic Manager endpoint", "trafficManagerBackend", backendKObj, "atmProfile", profile.Name, "atmEndpoint", endpointName)
 			continue
 		}
-		if equalAzureTrafficManagerEndpoint(*endpoint, desired.Endpoint) {
-			klog.V(2).InfoS("Skipping updating the existing Traffic Manager endpoint", "trafficManagerBackend", backendKObj, "atmProfile", profile.Name, "atmEndpoint", endpointName)
+		if equalAzureTrafficManagerEndpointExceptWeight(*endpoint, desired.Endpoint) {
+			if *endpoint.Properties.Weight == *desired.Endpoint.Properties.Weight {
+				klog.V(2).InfoS("Skipping updating the existing Traffic Manager endpoint", "trafficManagerBackend", backendKObj, "atmProfile", profile.Name, "atmEndpoint", endpointName)
+			} else {
+				// The desired weight is split across all the desired endpoints, while the existing one may have been
+				// rebalanced across the accepted endpoints; the weight is rebalanced below once the accepted endpoints
+				// are known, so that the endpoint is not updated back and forth on every reconciliation.
+				klog.V(2).InfoS("Deferring the weight update of the existing Traffic Manager endpoint", "trafficManagerBackend", backendKObj, "atmProfile", profile.Name, "atmEndpoint", endpointName, "currentWeight", *endpoint.Properties.Weight, "desiredWeight", *desired.Endpoint.Properties.Weight)
+			}
 			delete(desiredEndpoints, endpointName) // no need to update the existing endpoint
 			acceptedEndpoints = append(acceptedEndpoints, buildAcceptedEndpointStatus(endpoint, desired.Cluster))
 			continue
 		} // no need to update the endpoint if it's the same
 	}
+	if !r.ReadOnly && r.BulkEndpointUpdateThreshold > 0 && len(desiredEndpoints) > r.BulkEndpointUpdateThreshold {
+		bulkAcceptedEndpoints, bulkErr := r.bulkUpdateTrafficManagerEndpoints(ctx, backend, *profile.Name, desiredEndpoints)
+		if bulkErr != nil {
+			setUnknownCondition(backend, fmt.Sprintf("Failed to create or update the endpoints for %q: %v", *profile.Name, bulkErr))
+			if err := r.updateTrafficManagerBackendStatus(ctx, backend); err != nil {
+				return nil, nil, err
+			}
+			return nil, nil, bulkErr
+		}
+		acceptedEndpoints = append(acceptedEndpoints, bulkAcceptedEndpoints...)
+	}
 	badEndpointsError := make([]error, 0, len(desiredEndpoints))
 	// The remaining endpoints in the desiredEndpoints should be created or updated.
 	for _, endpoint := range desiredEndpoints {
+		endpointName := *endpoint.Endpoint.Name
+		if r.ReadOnly {
+			skipped = append(skipped, skipInReadOnlyMode(backend, "create or update", *profile.Name, endpointName))
+			continue
+		}
 		klog.V(2).InfoS("Creating new Traffic Manager endpoint", "trafficManagerBackend", backendKObj, "atmProfile", profile.Name, "atmEndpoint", endpoint)
 		var responseError *azcore.ResponseError
-		endpointName := *endpoint.Endpoint.Name
 		res, updateErr := r.EndpointsClient.CreateOrUpdate(ctx, r.ResourceGroupName, *profile.Name, armtrafficmanager.EndpointTypeAzureEndpoints, endpointName, endpoint.Endpoint, nil)
 		if updateErr != nil {
 			if !errors.As(updateErr, &responseError) {
@@ -614,7 +1586,7 @@ func (r *Reconciler) updateTrafficManagerEndpointsAndUpdateStatusIfUnknown(ctx c
 			klog.ErrorS(updateErr, "Failed to create or update the Traffic Manager endpoint", "trafficManagerBackend", backendKObj, "atmProfile", *profile.Name, "atmEndpoint", endpointName)
 			if azureerrors.IsClientError(updateErr) && !azureerrors.IsThrottled(updateErr) {
 				// When the failure is caused by the client error, will continue to process others.
-				badEndpointsError = append(badEndpointsError, updateErr)
+				badEndpointsError = append(badEndpointsError, &badEndpointError{cluster: endpoint.Cluster.Cluster, err: updateErr})
 				continue
 			}
 			setUnknownCondition(backend, fmt.Sprintf("Failed to create or update %q for %q: %v", *endpoint.Endpoint.Name, *profile.Name, updateErr))
@@ -626,16 +1598,179 @@ func (r *Reconciler) updateTrafficManagerEndpointsAndUpdateStatusIfUnknown(ctx c
 		klog.V(2).InfoS("Created or updated Traffic Manager endpoint", "trafficManagerBackend", backendKObj, "atmProfile", profile.Name, "atmEndpoint", endpointName)
 		acceptedEndpoints = append(acceptedEndpoints, buildAcceptedEndpointStatus(&res.Endpoint, endpoint.Cluster))
 	}
+	// The weights were split across all the desired endpoints before knowing which of them would be accepted, and the
+	// weight updates of the existing endpoints have been deferred until now.
+	if rebalanceErr := r.rebalanceTrafficManagerEndpointWeights(ctx, backend, *profile.Name, allDesiredEndpoints, acceptedEndpoints); errors.Is(rebalanceErr, errReadOnly) {
+		skipped = append(skipped, rebalanceErr)
+	} else if rebalanceErr != nil {
+		setUnknownCondition(backend, fmt.Sprintf("Failed to update the weights of the endpoints for %q: %v", *profile.Name, rebalanceErr))
+		if err := r.updateTrafficManagerBackendStatus(ctx, backend); err != nil {
+			return nil, nil, err
+		}
+		return nil, nil, rebalanceErr
+	}
+	if len(skipped) > 0 {
+		klog.V(2).InfoS("Skipped updating the Traffic Manager endpoints in the read-only mode", "trafficManagerBackend", backendKObj, "atmProfile", profile.Name, "numberOfAcceptedEndpoints", len(acceptedEndpoints), "numberOfSkippedRequests", len(skipped))
+		return acceptedEndpoints, skipped, nil
+	}
 	klog.V(2).InfoS("Successfully updated the Traffic Manager endpoints", "trafficManagerBackend", backendKObj, "atmProfile", profile.Name, "numberOfAcceptedEndpoints", len(acceptedEndpoints), "numberOfBadEndpoints", len(badEndpointsError))
 	return acceptedEndpoints, badEndpointsError, nil
 }
 
+// rebalanceTrafficManagerEndpointWeights splits the weight of the backend across the accepted endpoints only, so that
+// the endpoints rejected by Azure do not leave the traffic of the backend under-allocated. The endpoints whose weight
+// differs are updated, and so are their statuses in the acceptedEndpoints; the endpoints already carrying the split
+// weight are left untouched, so that the weights stay stable across the reconciliations.
+func (r *Reconciler) rebalanceTrafficManagerEndpointWeights(ctx context.Context, backend *fleetnetv1beta1.TrafficManagerBackend, profileName string, desiredEndpoints map[string]desiredEndpoint, acceptedEndpoints []fleetnetv1beta1.TrafficManagerEndpointStatus) error {
+	if backend.Spec.Weight == nil || len(acceptedEndpoints) == 0 {
+		return nil
+	}
+	backendKObj := klog.KObj(backend)
+	weight := splitWeight(*backend.Spec.Weight, len(acceptedEndpoints))
+	var skipped []error
+	for i := range acceptedEndpoints {
+		if ptr.Deref(acceptedEndpoints[i].Weight, 0) == weight {
+			continue
+		}
+		desired, ok := desiredEndpoints[acceptedEndpoints[i].Name]
+		if !ok {
+			continue
+		}
+		// Copy the properties, which are shared with the copies of the desired endpoint.
+		endpoint := desired.Endpoint
+		properties := *endpoint.Properties
+		properties.Weight = ptr.To(weight)
+		endpoint.Properties = &properties
+		if r.ReadOnly {
+			skipped = append(skipped, skipInReadOnlyMode(backend, "update the weight of", profileName, *endpoint.Name))
+			continue
+		}
+		klog.V(2).InfoS("Updating the weight of the Traffic Manager endpoint", "trafficManagerBackend", backendKObj, "atmProfile", profileName, "atmEndpoint", *endpoint.Name, "weight", weight)
+		res, err := r.EndpointsClient.CreateOrUpdate(ctx, r.ResourceGroupName, profileName, armtrafficmanager.EndpointTypeAzureEndpoints, *endpoint.Name, endpoint, nil)
+		if err != nil {
+			klog.ErrorS(err, "Failed to update the weight of the Traffic Manager endpoint", "trafficManagerBackend", backendKObj, "atmProfile", profileName, "atmEndpoint", *endpoint.Name)
+			return err
+		}
+		acceptedEndpoints[i] = buildAcceptedEndpointStatus(&res.Endpoint, desired.Cluster)
+	}
+	return joinReadOnlyErrors(skipped)
+}
+
+// bulkUpdateTrafficManagerEndpoints creates or updates the desired endpoints with a single Azure Traffic Manager
+// profile PUT, which is much faster and consumes less ARM quota than one request per endpoint.
+//
+// The endpoints are merged into the latest profile, leaving the endpoints of other backends untouched, and the PUT is
+// guarded by the ETag of the profile, so that the concurrent updates of the profile (e.g. by other backends sharing it)
+// are never overwritten; the merge is retried when the profile has been modified in between.
+//
+// The accepted endpoints are removed from the desiredEndpoints, and the remaining ones are expected to be created or
+// updated one by one; this is the case when the bulk update fails with a client error (e.g. one of the endpoints is
+// invalid), when the profile keeps being modified, or when the profile has no ETag to guard the PUT with.
+func (r *Reconciler) bulkUpdateTrafficManagerEndpoints(ctx context.Context, backend *fleetnetv1beta1.TrafficManagerBackend, profileName string, desiredEndpoints map[string]desiredEndpoint) ([]fleetnetv1beta1.TrafficManagerEndpointStatus, error) {
+	backendKObj := klog.KObj(backend)
+	for attempt := 1; attempt <= maxBulkEndpointUpdateAttempts; attempt++ {
+		var getHTTPResp *http.Response
+		getRes, getErr := r.ProfilesClient.Get(policy.WithCaptureResponse(ctx, &getHTTPResp), r.ResourceGroupName, profileName, nil)
+		if getErr != nil {
+			klog.ErrorS(getErr, "Failed to get the Azure Traffic Manager profile for the bulk endpoint update", "trafficManagerBackend", backendKObj, "atmProfile", profileName)
+			return nil, getErr
+		}
+		var etag string
+		if getHTTPResp != nil {
+			etag = getHTTPResp.Header.Get("ETag")
+		}
+		if etag == "" || getRes.Profile.Properties == nil {
+			// The concurrent updates of the profile cannot be detected without the ETag.
+			klog.V(2).InfoS("The Azure Traffic Manager profile has no ETag; falling back to update the endpoints one by one", "trafficManagerBackend", backendKObj, "atmProfile", profileName)
+			return nil, nil
+		}
+
+		profile := getRes.Profile
+		profile.Properties.Endpoints = mergeTrafficManagerEndpoints(profile.Properties.Endpoints, desiredEndpoints)
+		klog.V(2).InfoS("Creating or updating the Traffic Manager endpoints with a single profile update", "trafficManagerBackend", backendKObj, "atmProfile", profileName, "numberOfEndpoints", len(desiredEndpoints), "attempt", attempt)
+		updateCtx := policy.WithHTTPHeader(ctx, http.Header{"If-Match": []string{etag}})
+		res, updateErr := r.ProfilesClient.CreateOrUpdate(updateCtx, r.ResourceGroupName, profileName, profile, nil)
+		switch {
+		case updateErr == nil:
+			return acceptBulkUpdatedEndpoints(&res.Profile, desiredEndpoints), nil
+		case azureerrors.IsPreconditionFailed(updateErr):
+			klog.V(2).InfoS("The Azure Traffic Manager profile has been modified concurrently; retrying the bulk endpoint update", "trafficManagerBackend", backendKObj, "atmProfile", profileName, "attempt", attempt)
+			continue
+		case azureerrors.IsClientError(updateErr) && !azureerrors.IsThrottled(updateErr):
+			// The bulk update is rejected as a whole; update the endpoints one by one to find out the bad ones.
+			klog.ErrorS(updateErr, "Failed to create or update the Traffic Manager endpoints with a single profile update; falling back to update the endpoints one by one", "trafficManagerBackend", backendKObj, "atmProfile", profileName)
+			return nil, nil
+		default:
+			klog.ErrorS(updateErr, "Failed to create or update the Traffic Manager endpoints with a single profile update", "trafficManagerBackend", backendKObj, "atmProfile", profileName)
+			return nil, updateErr
+		}
+	}
+	klog.V(2).InfoS("The Azure Traffic Manager profile keeps being modified concurrently; falling back to update the endpoints one by one", "trafficManagerBackend", backendKObj, "atmProfile", profileName, "attempts", maxBulkEndpointUpdateAttempts)
+	return nil, nil
+}
+
+// mergeTrafficManagerEndpoints returns the endpoints of the profile with the desired endpoints created or replaced;
+// the other endpoints are kept as they are.
+func mergeTrafficManagerEndpoints(current []*armtrafficmanager.Endpoint, desiredEndpoints map[string]desiredEndpoint) []*armtrafficmanager.Endpoint {
+	merged := make([]*armtrafficmanager.Endpoint, 0, len(current)+len(desiredEndpoints))
+	replaced := make(map[string]bool, len(desiredEndpoints))
+	for _, endpoint := range current {
+		if endpoint == nil || endpoint.Name == nil {
+			merged = append(merged, endpoint)
+			continue
+		}
+		endpointName := strings.ToLower(*endpoint.Name) // resource name are case-insensitive
+		desired, ok := desiredEndpoints[endpointName]
+		if !ok {
+			merged = append(merged, endpoint)
+			continue
+		}
+		merged = append(merged, ptr.To(desired.Endpoint))
+		replaced[endpointName] = true
+	}
+	for endpointName, desired := range desiredEndpoints {
+		if !replaced[endpointName] {
+			merged = append(merged, ptr.To(desired.Endpoint))
+		}
+	}
+	return merged
+}
+
+// acceptBulkUpdatedEndpoints returns the status of the desired endpoints found in the updated profile and removes them
+// from the desiredEndpoints.
+func acceptBulkUpdatedEndpoints(profile *armtrafficmanager.Profile, desiredEndpoints map[string]desiredEndpoint) []fleetnetv1beta1.TrafficManagerEndpointStatus {
+	if profile.Properties == nil {
+		return nil
+	}
+	acceptedEndpoints := make([]fleetnetv1beta1.TrafficManagerEndpointStatus, 0, len(desiredEndpoints))
+	for _, endpoint := range profile.Properties.Endpoints {
+		if endpoint == nil || endpoint.Name == nil || endpoint.Properties == nil {
+			continue
+		}
+		endpointName := strings.ToLower(*endpoint.Name) // resource name are case-insensitive
+		desired, ok := desiredEndpoints[endpointName]
+		if !ok {
+			continue
+		}
+		acceptedEndpoints = append(acceptedEndpoints, buildAcceptedEndpointStatus(endpoint, desired.Cluster))
+		delete(desiredEndpoints, endpointName)
+	}
+	return acceptedEndpoints
+}
+
 // SetupWithManager sets up the controller with the Manager.
 func (r *Reconciler) SetupWithManager(ctx context.Context, mgr ctrl.Manager, disableInternalServiceExportIndexer bool) error {
+	r.weightClusters = &sync.Map{}
+	r.heartbeats = &sync.Map{}
+	r.startup = &startupJitter{window: r.StartupJitterWindow}
+	r.profiles = newProfileCache(r.ProfileCacheTTL)
+
 	// set up an index for efficient trafficManagerBackend lookup
+	// The backends of other shards are not indexed, so that the event handlers listing the backends by the indexes
+	// only enqueue the backends of this shard.
 	profileIndexerFunc := func(o client.Object) []string {
 		tmb, ok := o.(*fleetnetv1beta1.TrafficManagerBackend)
-		if !ok {
+		if !ok || !r.Shard.Owns(tmb.Namespace, tmb.Name) {
 			return []string{}
 		}
 		return []string{tmb.Spec.Profile.Name}
@@ -647,7 +1782,7 @@ func (r *Reconciler) SetupWithManager(ctx context.Context, mgr ctrl.Manager, dis
 
 	backendIndexerFunc := func(o client.Object) []string {
 		tmb, ok := o.(*fleetnetv1beta1.TrafficManagerBackend)
-		if !ok {
+		if !ok || !r.Shard.Owns(tmb.Namespace, tmb.Name) {
 			return []string{}
 		}
 		return []string{tmb.Spec.Backend.Name}
@@ -672,8 +1807,14 @@ func (r *Reconciler) SetupWithManager(ctx context.Context, mgr ctrl.Manager, dis
 		}
 	}
 
+	var options ctrlcontroller.Options
+	if r.Shard.Enabled() {
+		klog.V(2).InfoS("Sharding trafficManagerBackend reconciliation", "shardCount", r.Shard.Count, "shardIndex", r.Shard.Index)
+		options.NeedLeaderElection = ptr.To(false)
+	}
 	return ctrl.NewControllerManagedBy(mgr).
-		For(&fleetnetv1beta1.TrafficManagerBackend{}).
+		WithOptions(options).
+		For(&fleetnetv1beta1.TrafficManagerBackend{}, builder.WithPredicates(r.Shard.Predicate())).
 		Watches(
 			&fleetnetv1beta1.TrafficManagerProfile{},
 			handler.EnqueueRequestsFromMapFunc(r.trafficManagerProfileEventHandler()),
@@ -682,11 +1823,83 @@ func (r *Reconciler) SetupWithManager(ctx context.Context, mgr ctrl.Manager, dis
 			&fleetnetv1alpha1.ServiceImport{},
 			handler.EnqueueRequestsFromMapFunc(r.serviceImportEventHandler()),
 		).
+		// The duplicates of a deleted backend are re-triggered, so that the next oldest one is accepted.
+		Watches(
+			&fleetnetv1beta1.TrafficManagerBackend{},
+			handler.EnqueueRequestsFromMapFunc(r.duplicateTrafficManagerBackendEventHandler()),
+			builder.WithPredicates(predicate.Funcs{
+				CreateFunc:  func(event.CreateEvent) bool { return false },
+				UpdateFunc:  func(event.UpdateEvent) bool { return false },
+				DeleteFunc:  func(event.DeleteEvent) bool { return true },
+				GenericFunc: func(event.GenericEvent) bool { return false },
+			}),
+		).
 		Watches(
 			&fleetnetv1alpha1.InternalServiceExport{},
 			handler.EnqueueRequestsFromMapFunc(r.internalServiceExportEventHandler()),
+			builder.WithPredicates(r.internalServiceExportHeartbeatPredicate()),
 		).
-		Complete(r)
+		Complete(reconcilewatchdog.Wrap("trafficmanagerbackend", r))
+}
+
+// internalServiceExportHeartbeatPredicate filters out the updates which only refresh the heartbeat of an exported
+// service without changing its freshness, as they make no difference to the endpoints; when a stale service is
+// refreshed, the update is kept so that its endpoint is added back, and so is the first heartbeat of a service, so
+// that the backends are requeued when it goes stale.
+// The updates of the fields the endpoints are built from are always kept, even when they come with a refreshed
+// heartbeat, so that the endpoint follows, e.g., the new public IP of a recreated service.
+func (r *Reconciler) internalServiceExportHeartbeatPredicate() predicate.Funcs {
+	return predicate.Funcs{
+		UpdateFunc: func(e event.UpdateEvent) bool {
+			oldExport, oldOK := e.ObjectOld.(*fleetnetv1alpha1.InternalServiceExport)
+			newExport, newOK := e.ObjectNew.(*fleetnetv1alpha1.InternalServiceExport)
+			if !oldOK || !newOK {
+				return true
+			}
+			if isEndpointSpecChanged(oldExport, newExport) ||
+				oldExport.Status.LastHeartbeatTime.Equal(newExport.Status.LastHeartbeatTime) ||
+				oldExport.Generation != newExport.Generation ||
+				!oldExport.DeletionTimestamp.Equal(newExport.DeletionTimestamp) {
+				return true
+			}
+			// The old heartbeat is looked up first, so that the freshness of the export before the refresh is
+			// measured from the time its old heartbeat was observed.
+			now := time.Now()
+			oldObservedAt := r.heartbeatObservedAt(oldExport, now)
+			newObservedAt := r.heartbeatObservedAt(newExport, now)
+			if oldObservedAt.IsZero() != newObservedAt.IsZero() {
+				// The export starts or stops reporting its heartbeat; the backends are requeued when it goes stale
+				// only once they have been reconciled with the heartbeat.
+				return true
+			}
+			_, oldErr := checkExportFreshness(oldObservedAt, r.MaxExportStaleness, now)
+			_, newErr := checkExportFreshness(newObservedAt, r.MaxExportStaleness, now)
+			return (oldErr == nil) != (newErr == nil)
+		},
+		DeleteFunc: func(e event.DeleteEvent) bool {
+			if r.heartbeats != nil {
+				r.heartbeats.Delete(types.NamespacedName{Namespace: e.Object.GetNamespace(), Name: e.Object.GetName()})
+			}
+			return true
+		},
+	}
+}
+
+// isEndpointSpecChanged returns true if any of the fields of the exported service which determine its Azure Traffic
+// Manager endpoint, or whether it is reported in the condition, has been changed; the fields being cleared, e.g. the
+// public IP of a service which is no longer of the LoadBalancer type, count as changes, so that the stale endpoint is
+// removed.
+func isEndpointSpecChanged(oldExport, newExport *fleetnetv1alpha1.InternalServiceExport) bool {
+	return !ptr.Equal(oldExport.Spec.PublicIPResourceID, newExport.Spec.PublicIPResourceID) ||
+		oldExport.Spec.IsDNSLabelConfigured != newExport.Spec.IsDNSLabelConfigured ||
+		oldExport.Spec.IsInternalLoadBalancer != newExport.Spec.IsInternalLoadBalancer ||
+		oldExport.Spec.IsLoadBalancerPending != newExport.Spec.IsLoadBalancerPending ||
+		oldExport.Spec.IsPublicIPExplicit != newExport.Spec.IsPublicIPExplicit ||
+		oldExport.Spec.Type != newExport.Spec.Type ||
+		oldExport.Spec.ExternalTrafficPolicy != newExport.Spec.ExternalTrafficPolicy ||
+		oldExport.Spec.HealthCheckNodePort != newExport.Spec.HealthCheckNodePort ||
+		!ptr.Equal(oldExport.Spec.Weight, newExport.Spec.Weight) ||
+		isTrafficManagerInfoReported(oldExport) != isTrafficManagerInfoReported(newExport)
 }
 
 func (r *Reconciler) trafficManagerProfileEventHandler() handler.MapFunc {
@@ -696,12 +1909,14 @@ func (r *Reconciler) trafficManagerProfileEventHandler() handler.MapFunc {
 			trafficManagerBackendProfileFieldKey: object.GetName(),
 		}
 		// For now, we only support the backend and profile in the same namespace.
-		if err := r.Client.List(ctx, trafficManagerBackendList, client.InNamespace(object.GetNamespace()), fieldMatcher); err != nil {
+		// Only the names of the backends are read, so that they are not deep-copied out of the cache.
+		if err := r.Client.List(ctx, trafficManagerBackendList, client.InNamespace(object.GetNamespace()), fieldMatcher, client.UnsafeDisableDeepCopy); err != nil {
 			klog.ErrorS(err,
 				"Failed to list trafficManagerBackends for the profile",
 				"trafficManagerProfile", klog.KObj(object))
 			return []reconcile.Request{}
 		}
+		listguard.ObserveListSize(trafficManagerBackendList, "trafficManagerProfile", klog.KObj(object))
 
 		res := make([]reconcile.Request, 0, len(trafficManagerBackendList.Items))
 		for _, backend := range trafficManagerBackendList.Items {
@@ -716,6 +1931,44 @@ func (r *Reconciler) trafficManagerProfileEventHandler() handler.MapFunc {
 	}
 }
 
+// duplicateTrafficManagerBackendEventHandler enqueues the other backends of this shard which refer to the same
+// trafficManagerProfile and serviceImport as the given backend.
+func (r *Reconciler) duplicateTrafficManagerBackendEventHandler() handler.MapFunc {
+	return func(ctx context.Context, object client.Object) []reconcile.Request {
+		deleted, ok := object.(*fleetnetv1beta1.TrafficManagerBackend)
+		if !ok {
+			return []reconcile.Request{}
+		}
+		trafficManagerBackendList := &fleetnetv1beta1.TrafficManagerBackendList{}
+		fieldMatcher := client.MatchingFields{
+			trafficManagerBackendProfileFieldKey: deleted.Spec.Profile.Name,
+		}
+		// The backends are only read, so that they are not deep-copied out of the cache.
+		if err := r.Client.List(ctx, trafficManagerBackendList, client.InNamespace(deleted.Namespace), fieldMatcher, client.UnsafeDisableDeepCopy); err != nil {
+			klog.ErrorS(err,
+				"Failed to list trafficManagerBackends for the deleted trafficManagerBackend",
+				"trafficManagerBackend", klog.KObj(deleted))
+			return []reconcile.Request{}
+		}
+		listguard.ObserveListSize(trafficManagerBackendList, "trafficManagerBackend", klog.KObj(deleted))
+
+		res := make([]reconcile.Request, 0, len(trafficManagerBackendList.Items))
+		for i := range trafficManagerBackendList.Items {
+			backend := &trafficManagerBackendList.Items[i]
+			if backend.Name == deleted.Name || !isDuplicateBackend(deleted, backend) {
+				continue
+			}
+			res = append(res, reconcile.Request{
+				NamespacedName: types.NamespacedName{
+					Namespace: backend.Namespace,
+					Name:      backend.Name,
+				},
+			})
+		}
+		return res
+	}
+}
+
 func (r *Reconciler) serviceImportEventHandler() handler.MapFunc {
 	return func(ctx context.Context, object client.Object) []reconcile.Request {
 		return r.enqueueTrafficManagerBackendByServiceImport(ctx, object)
@@ -728,12 +1981,14 @@ func (r *Reconciler) enqueueTrafficManagerBackendByServiceImport(ctx context.Con
 		trafficManagerBackendBackendFieldKey: object.GetName(),
 	}
 	// ServiceImport and TrafficManagerBackend should be in the same namespace.
-	if err := r.Client.List(ctx, trafficManagerBackendList, client.InNamespace(object.GetNamespace()), fieldMatcher); err != nil {
+	// Only the names of the backends are read, so that they are not deep-copied out of the cache.
+	if err := r.Client.List(ctx, trafficManagerBackendList, client.InNamespace(object.GetNamespace()), fieldMatcher, client.UnsafeDisableDeepCopy); err != nil {
 		klog.ErrorS(err,
 			"Failed to list trafficManagerBackends for the serviceImport",
 			"serviceImport", klog.KObj(object))
 		return []reconcile.Request{}
 	}
+	listguard.ObserveListSize(trafficManagerBackendList, "serviceImport", klog.KObj(object))
 
 	res := make([]reconcile.Request, 0, len(trafficManagerBackendList.Items))
 	for _, backend := range trafficManagerBackendList.Items {
@@ -10,17 +10,22 @@ import (
 	"fmt"
 	"time"
 
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/utils/ptr"
+	ctrlmetrics "sigs.k8s.io/controller-runtime/pkg/metrics"
 
 	fleetnetv1alpha1 "go.goms.io/fleet-networking/api/v1alpha1"
 	fleetnetv1beta1 "go.goms.io/fleet-networking/api/v1beta1"
 	"go.goms.io/fleet-networking/pkg/common/objectmeta"
+	"go.goms.io/fleet-networking/test/common/fleetenv"
 	"go.goms.io/fleet-networking/test/common/trafficmanager/fakeprovider"
 	"go.goms.io/fleet-networking/test/common/trafficmanager/validator"
 )
@@ -96,6 +101,28 @@ func buildTrueCondition(generation int64) []metav1.Condition {
 	}
 }
 
+func buildDisabledCondition(generation int64) []metav1.Condition {
+	return []metav1.Condition{
+		{
+			Status:             metav1.ConditionTrue,
+			Type:               string(fleetnetv1beta1.TrafficManagerBackendConditionAccepted),
+			Reason:             string(fleetnetv1beta1.TrafficManagerBackendReasonDisabled),
+			ObservedGeneration: generation,
+		},
+	}
+}
+
+func buildDuplicateCondition(generation int64) []metav1.Condition {
+	return []metav1.Condition{
+		{
+			Status:             metav1.ConditionFalse,
+			Type:               string(fleetnetv1beta1.TrafficManagerBackendConditionAccepted),
+			Reason:             string(fleetnetv1beta1.TrafficManagerBackendReasonDuplicate),
+			ObservedGeneration: generation,
+		},
+	}
+}
+
 func updateTrafficManagerProfileStatusToTrue(ctx context.Context, profile *fleetnetv1beta1.TrafficManagerProfile) {
 	cond := metav1.Condition{
 		Status:             metav1.ConditionTrue,
@@ -762,6 +789,10 @@ var _ = Describe("Test TrafficManagerBackend Controller", func() {
 				Spec: backend.Spec,
 				Status: fleetnetv1beta1.TrafficManagerBackendStatus{
 					Conditions: buildFalseCondition(backend.Generation),
+					InvalidClusters: []fleetnetv1beta1.TrafficManagerInvalidCluster{
+						{Cluster: memberClusterNames[1], Reason: fleetnetv1beta1.TrafficManagerInvalidClusterReasonNoDNSLabel},
+					},
+					TotalInvalid: 1,
 					Endpoints: []fleetnetv1beta1.TrafficManagerEndpointStatus{
 						{
 							Name: fmt.Sprintf(AzureResourceEndpointNameFormat, backendName+"#", serviceName, memberClusterNames[0]),
@@ -770,7 +801,7 @@ var _ = Describe("Test TrafficManagerBackend Controller", func() {
 									Cluster: memberClusterNames[0],
 								},
 							},
-							Weight: ptr.To(fakeprovider.Weight), // populate the weight using atm endpoint
+							Weight: ptr.To(int64(10)), // the backend weight is split across the accepted endpoints
 							Target: ptr.To(fakeprovider.ValidEndpointTarget),
 						},
 					},
@@ -780,6 +811,10 @@ var _ = Describe("Test TrafficManagerBackend Controller", func() {
 			validator.ValidateTrafficManagerBackendConsistently(ctx, k8sClient, &want)
 		})
 
+		It("Validating the endpoint weight metric", func() {
+			validateEndpointWeightMetric(backendNamespacedName, map[string]float64{memberClusterNames[0]: 10})
+		})
+
 		It("Updating the ServiceImport status", func() {
 			serviceImport.Status = fleetnetv1alpha1.ServiceImportStatus{
 				Clusters: []fleetnetv1alpha1.ClusterStatus{
@@ -812,7 +847,102 @@ var _ = Describe("Test TrafficManagerBackend Controller", func() {
 									Cluster: memberClusterNames[0],
 								},
 							},
-							Weight: ptr.To(fakeprovider.Weight), // populate the weight using atm endpoint
+							Weight: ptr.To(int64(5)), // the backend weight is split across the accepted endpoints
+							Target: ptr.To(fakeprovider.ValidEndpointTarget),
+						},
+						{
+							Name: fmt.Sprintf(AzureResourceEndpointNameFormat, backendName+"#", serviceName, memberClusterNames[3]),
+							From: &fleetnetv1beta1.FromCluster{
+								ClusterStatus: fleetnetv1beta1.ClusterStatus{
+									Cluster: memberClusterNames[3],
+								},
+							},
+							Weight: ptr.To(int64(5)), // the backend weight is split across the accepted endpoints
+							Target: ptr.To(fakeprovider.ValidEndpointTarget),
+						},
+					},
+				},
+			}
+			validator.ValidateTrafficManagerBackend(ctx, k8sClient, &want)
+			validator.ValidateTrafficManagerBackendConsistently(ctx, k8sClient, &want)
+		})
+
+		It("Validating the endpoint weight metric", func() {
+			validateEndpointWeightMetric(backendNamespacedName, map[string]float64{memberClusterNames[0]: 5, memberClusterNames[3]: 5})
+		})
+
+		It("Sending a heartbeat of the internalServiceExport which is never refreshed to simulate a stale export", func() {
+			// The staleness is measured from the time the hub agent observes the heartbeat, regardless of the clock
+			// of the member cluster; the export goes stale once the max staleness of the suite (a minute) has passed
+			// without a refresh, within the timeout of the validation below.
+			internalServiceExport := &fleetnetv1alpha1.InternalServiceExport{}
+			Expect(k8sClient.Get(ctx, types.NamespacedName{Namespace: internalServiceExports[3].Namespace, Name: internalServiceExports[3].Name}, internalServiceExport)).Should(Succeed())
+			internalServiceExport.Status.LastHeartbeatTime = ptr.To(metav1.NewTime(time.Now().Add(time.Hour)))
+			Expect(k8sClient.Status().Update(ctx, internalServiceExport)).Should(Succeed(), "failed to update internalServiceExport status")
+		})
+
+		It("Validating trafficManagerBackend", func() {
+			want := fleetnetv1beta1.TrafficManagerBackend{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:       backendName,
+					Namespace:  testNamespace,
+					Finalizers: []string{objectmeta.TrafficManagerBackendFinalizer},
+				},
+				Spec: backend.Spec,
+				Status: fleetnetv1beta1.TrafficManagerBackendStatus{
+					Conditions: buildFalseCondition(backend.Generation),
+					InvalidClusters: []fleetnetv1beta1.TrafficManagerInvalidCluster{
+						{Cluster: memberClusterNames[3], Reason: fleetnetv1beta1.TrafficManagerInvalidClusterReasonStaleExport},
+					},
+					TotalInvalid: 1,
+					Endpoints: []fleetnetv1beta1.TrafficManagerEndpointStatus{
+						{
+							Name: fmt.Sprintf(AzureResourceEndpointNameFormat, backendName+"#", serviceName, memberClusterNames[0]),
+							From: &fleetnetv1beta1.FromCluster{
+								ClusterStatus: fleetnetv1beta1.ClusterStatus{
+									Cluster: memberClusterNames[0],
+								},
+							},
+							Weight: ptr.To(int64(10)), // the backend weight is split across the accepted endpoints
+							Target: ptr.To(fakeprovider.ValidEndpointTarget),
+						},
+					},
+				},
+			}
+			validator.ValidateTrafficManagerBackend(ctx, k8sClient, &want)
+			validator.ValidateTrafficManagerBackendConsistently(ctx, k8sClient, &want)
+		})
+
+		It("Validating the endpoint weight metric", func() {
+			validateEndpointWeightMetric(backendNamespacedName, map[string]float64{memberClusterNames[0]: 10})
+		})
+
+		It("Refreshing the heartbeat of the internalServiceExport", func() {
+			internalServiceExport := &fleetnetv1alpha1.InternalServiceExport{}
+			Expect(k8sClient.Get(ctx, types.NamespacedName{Namespace: internalServiceExports[3].Namespace, Name: internalServiceExports[3].Name}, internalServiceExport)).Should(Succeed())
+			internalServiceExport.Status.LastHeartbeatTime = ptr.To(metav1.NewTime(time.Now()))
+			Expect(k8sClient.Status().Update(ctx, internalServiceExport)).Should(Succeed(), "failed to update internalServiceExport status")
+		})
+
+		It("Validating trafficManagerBackend", func() {
+			want := fleetnetv1beta1.TrafficManagerBackend{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:       backendName,
+					Namespace:  testNamespace,
+					Finalizers: []string{objectmeta.TrafficManagerBackendFinalizer},
+				},
+				Spec: backend.Spec,
+				Status: fleetnetv1beta1.TrafficManagerBackendStatus{
+					Conditions: buildTrueCondition(backend.Generation),
+					Endpoints: []fleetnetv1beta1.TrafficManagerEndpointStatus{
+						{
+							Name: fmt.Sprintf(AzureResourceEndpointNameFormat, backendName+"#", serviceName, memberClusterNames[0]),
+							From: &fleetnetv1beta1.FromCluster{
+								ClusterStatus: fleetnetv1beta1.ClusterStatus{
+									Cluster: memberClusterNames[0],
+								},
+							},
+							Weight: ptr.To(int64(5)), // the backend weight is split across the accepted endpoints
 							Target: ptr.To(fakeprovider.ValidEndpointTarget),
 						},
 						{
@@ -822,7 +952,7 @@ var _ = Describe("Test TrafficManagerBackend Controller", func() {
 									Cluster: memberClusterNames[3],
 								},
 							},
-							Weight: ptr.To(fakeprovider.Weight), // populate the weight using atm endpoint
+							Weight: ptr.To(int64(5)), // the backend weight is split across the accepted endpoints
 							Target: ptr.To(fakeprovider.ValidEndpointTarget),
 						},
 					},
@@ -832,6 +962,19 @@ var _ = Describe("Test TrafficManagerBackend Controller", func() {
 			validator.ValidateTrafficManagerBackendConsistently(ctx, k8sClient, &want)
 		})
 
+		It("Validating the endpoint weight metric", func() {
+			validateEndpointWeightMetric(backendNamespacedName, map[string]float64{memberClusterNames[0]: 5, memberClusterNames[3]: 5})
+		})
+
+		It("Dropping the heartbeat of the internalServiceExport", func() {
+			// The heartbeat is never refreshed again, and the internalServiceExport is shared with the following
+			// contexts, which would see the export go stale once the max staleness of the suite has passed.
+			internalServiceExport := &fleetnetv1alpha1.InternalServiceExport{}
+			Expect(k8sClient.Get(ctx, types.NamespacedName{Namespace: internalServiceExports[3].Namespace, Name: internalServiceExports[3].Name}, internalServiceExport)).Should(Succeed())
+			internalServiceExport.Status.LastHeartbeatTime = nil
+			Expect(k8sClient.Status().Update(ctx, internalServiceExport)).Should(Succeed(), "failed to update internalServiceExport status")
+		})
+
 		It("Updating the ServiceImport status", func() {
 			serviceImport.Status = fleetnetv1alpha1.ServiceImportStatus{
 				Clusters: []fleetnetv1alpha1.ClusterStatus{
@@ -856,6 +999,10 @@ var _ = Describe("Test TrafficManagerBackend Controller", func() {
 				Spec: backend.Spec,
 				Status: fleetnetv1beta1.TrafficManagerBackendStatus{
 					Conditions: buildFalseCondition(backend.Generation),
+					InvalidClusters: []fleetnetv1beta1.TrafficManagerInvalidCluster{
+						{Cluster: memberClusterNames[4], Reason: fleetnetv1beta1.TrafficManagerInvalidClusterReasonAzureClientError},
+					},
+					TotalInvalid: 1,
 					Endpoints: []fleetnetv1beta1.TrafficManagerEndpointStatus{
 						{
 							Name: fmt.Sprintf(AzureResourceEndpointNameFormat, backendName+"#", serviceName, memberClusterNames[0]),
@@ -864,7 +1011,7 @@ var _ = Describe("Test TrafficManagerBackend Controller", func() {
 									Cluster: memberClusterNames[0],
 								},
 							},
-							Weight: ptr.To(fakeprovider.Weight), // populate the weight using atm endpoint
+							Weight: ptr.To(int64(10)), // the weight is rebalanced as the other endpoint is rejected
 							Target: ptr.To(fakeprovider.ValidEndpointTarget),
 						},
 					},
@@ -874,6 +1021,10 @@ var _ = Describe("Test TrafficManagerBackend Controller", func() {
 			validator.ValidateTrafficManagerBackendConsistently(ctx, k8sClient, &want)
 		})
 
+		It("Validating the endpoint weight metric", func() {
+			validateEndpointWeightMetric(backendNamespacedName, map[string]float64{memberClusterNames[0]: 10})
+		})
+
 		It("Updating the ServiceImport status", func() {
 			serviceImport.Status = fleetnetv1alpha1.ServiceImportStatus{
 				Clusters: []fleetnetv1alpha1.ClusterStatus{
@@ -932,6 +1083,10 @@ var _ = Describe("Test TrafficManagerBackend Controller", func() {
 				Spec: backend.Spec,
 				Status: fleetnetv1beta1.TrafficManagerBackendStatus{
 					Conditions: buildFalseCondition(backend.Generation),
+					InvalidClusters: []fleetnetv1beta1.TrafficManagerInvalidCluster{
+						{Cluster: memberClusterNames[0], Reason: fleetnetv1beta1.TrafficManagerInvalidClusterReasonInternalLoadBalancer},
+					},
+					TotalInvalid: 1,
 				},
 			}
 			validator.ValidateTrafficManagerBackend(ctx, k8sClient, &want)
@@ -963,7 +1118,162 @@ var _ = Describe("Test TrafficManagerBackend Controller", func() {
 									Cluster: memberClusterNames[0],
 								},
 							},
-							Weight: ptr.To(fakeprovider.Weight), // populate the weight using atm endpoint
+							Weight: ptr.To(int64(10)), // the backend weight is split across the accepted endpoints
+							Target: ptr.To(fakeprovider.ValidEndpointTarget),
+						},
+					},
+				},
+			}
+			validator.ValidateTrafficManagerBackend(ctx, k8sClient, &want)
+			validator.ValidateTrafficManagerBackendConsistently(ctx, k8sClient, &want)
+		})
+
+		var loadBalancerSpec fleetnetv1alpha1.InternalServiceExportSpec
+
+		It("Switching the exported service from LoadBalancer to ClusterIP", func() {
+			// The member agent clears the public IP and the DNS label of a service which is no longer of the
+			// LoadBalancer type, so that the endpoint does not target a public IP which may be released and reassigned.
+			internalServiceExport := &fleetnetv1alpha1.InternalServiceExport{}
+			Expect(k8sClient.Get(ctx, types.NamespacedName{Namespace: internalServiceExports[0].Namespace, Name: internalServiceExports[0].Name}, internalServiceExport)).Should(Succeed())
+			loadBalancerSpec = *internalServiceExport.Spec.DeepCopy()
+			internalServiceExport.Spec.Type = corev1.ServiceTypeClusterIP
+			internalServiceExport.Spec.PublicIPResourceID = nil
+			internalServiceExport.Spec.IsDNSLabelConfigured = false
+			Expect(k8sClient.Update(ctx, internalServiceExport)).Should(Succeed(), "failed to update internalServiceExport")
+		})
+
+		It("Validating trafficManagerBackend and the endpoint is removed", func() {
+			want := fleetnetv1beta1.TrafficManagerBackend{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:       backendName,
+					Namespace:  testNamespace,
+					Finalizers: []string{objectmeta.TrafficManagerBackendFinalizer},
+				},
+				Spec: backend.Spec,
+				Status: fleetnetv1beta1.TrafficManagerBackendStatus{
+					Conditions: buildFalseCondition(backend.Generation),
+					InvalidClusters: []fleetnetv1beta1.TrafficManagerInvalidCluster{
+						{Cluster: memberClusterNames[0], Reason: fleetnetv1beta1.TrafficManagerInvalidClusterReasonUnsupportedType},
+					},
+					TotalInvalid: 1,
+				},
+			}
+			validator.ValidateTrafficManagerBackend(ctx, k8sClient, &want)
+			validator.ValidateTrafficManagerBackendConsistently(ctx, k8sClient, &want)
+
+			got := &fleetnetv1beta1.TrafficManagerBackend{}
+			Expect(k8sClient.Get(ctx, types.NamespacedName{Namespace: testNamespace, Name: backendName}, got)).Should(Succeed())
+			cond := meta.FindStatusCondition(got.Status.Conditions, string(fleetnetv1beta1.TrafficManagerBackendConditionAccepted))
+			Expect(cond.Message).Should(ContainSubstring(`unsupported service type "ClusterIP"`), "the condition should explain why the service is not accepted")
+		})
+
+		It("Switching the exported service back to LoadBalancer", func() {
+			internalServiceExport := &fleetnetv1alpha1.InternalServiceExport{}
+			Expect(k8sClient.Get(ctx, types.NamespacedName{Namespace: internalServiceExports[0].Namespace, Name: internalServiceExports[0].Name}, internalServiceExport)).Should(Succeed())
+			internalServiceExport.Spec = loadBalancerSpec
+			Expect(k8sClient.Update(ctx, internalServiceExport)).Should(Succeed(), "failed to update internalServiceExport")
+		})
+
+		It("Validating trafficManagerBackend", func() {
+			want := fleetnetv1beta1.TrafficManagerBackend{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:       backendName,
+					Namespace:  testNamespace,
+					Finalizers: []string{objectmeta.TrafficManagerBackendFinalizer},
+				},
+				Spec: backend.Spec,
+				Status: fleetnetv1beta1.TrafficManagerBackendStatus{
+					Conditions: buildTrueCondition(backend.Generation),
+					Endpoints: []fleetnetv1beta1.TrafficManagerEndpointStatus{
+						{
+							Name: fmt.Sprintf(AzureResourceEndpointNameFormat, backendName+"#", serviceName, memberClusterNames[0]),
+							From: &fleetnetv1beta1.FromCluster{
+								ClusterStatus: fleetnetv1beta1.ClusterStatus{
+									Cluster: memberClusterNames[0],
+								},
+							},
+							Weight: ptr.To(int64(10)),
+							Target: ptr.To(fakeprovider.ValidEndpointTarget),
+						},
+					},
+				},
+			}
+			validator.ValidateTrafficManagerBackend(ctx, k8sClient, &want)
+			validator.ValidateTrafficManagerBackendConsistently(ctx, k8sClient, &want)
+		})
+
+		var originalPublicIPResourceID *string
+
+		It("Updating the public IP of the internalServiceExport together with its heartbeat", func() {
+			// The service is recreated in the member cluster and gets a new public IP; the heartbeat is refreshed by
+			// the same update, which must not be filtered out as a heartbeat-only update.
+			internalServiceExport := &fleetnetv1alpha1.InternalServiceExport{}
+			Expect(k8sClient.Get(ctx, types.NamespacedName{Namespace: internalServiceExports[0].Namespace, Name: internalServiceExports[0].Name}, internalServiceExport)).Should(Succeed())
+			originalPublicIPResourceID = internalServiceExport.Spec.PublicIPResourceID
+			internalServiceExport.Spec.PublicIPResourceID = ptr.To(fakeprovider.AlternativePublicIPResourceID)
+			Expect(k8sClient.Update(ctx, internalServiceExport)).Should(Succeed(), "failed to update internalServiceExport")
+			internalServiceExport.Status.LastHeartbeatTime = ptr.To(metav1.Now())
+			Expect(k8sClient.Status().Update(ctx, internalServiceExport)).Should(Succeed(), "failed to update internalServiceExport status")
+		})
+
+		It("Validating the trafficManagerBackend endpoint targets the new public IP", func() {
+			want := fleetnetv1beta1.TrafficManagerBackend{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:       backendName,
+					Namespace:  testNamespace,
+					Finalizers: []string{objectmeta.TrafficManagerBackendFinalizer},
+				},
+				Spec: backend.Spec,
+				Status: fleetnetv1beta1.TrafficManagerBackendStatus{
+					Conditions: buildTrueCondition(backend.Generation),
+					Endpoints: []fleetnetv1beta1.TrafficManagerEndpointStatus{
+						{
+							Name: fmt.Sprintf(AzureResourceEndpointNameFormat, backendName+"#", serviceName, memberClusterNames[0]),
+							From: &fleetnetv1beta1.FromCluster{
+								ClusterStatus: fleetnetv1beta1.ClusterStatus{
+									Cluster: memberClusterNames[0],
+								},
+							},
+							Weight: ptr.To(int64(10)), // the backend weight is split across the accepted endpoints
+							Target: ptr.To(fakeprovider.AlternativeEndpointTarget),
+						},
+					},
+				},
+			}
+			validator.ValidateTrafficManagerBackend(ctx, k8sClient, &want)
+			validator.ValidateTrafficManagerBackendConsistently(ctx, k8sClient, &want)
+		})
+
+		It("Updating the public IP of the internalServiceExport back and dropping its heartbeat", func() {
+			internalServiceExport := &fleetnetv1alpha1.InternalServiceExport{}
+			Expect(k8sClient.Get(ctx, types.NamespacedName{Namespace: internalServiceExports[0].Namespace, Name: internalServiceExports[0].Name}, internalServiceExport)).Should(Succeed())
+			internalServiceExport.Spec.PublicIPResourceID = originalPublicIPResourceID
+			Expect(k8sClient.Update(ctx, internalServiceExport)).Should(Succeed(), "failed to update internalServiceExport")
+			// The heartbeat is never refreshed by the following specs, which would see the export go stale once the
+			// max staleness of the suite has passed.
+			internalServiceExport.Status.LastHeartbeatTime = nil
+			Expect(k8sClient.Status().Update(ctx, internalServiceExport)).Should(Succeed(), "failed to update internalServiceExport status")
+		})
+
+		It("Validating the trafficManagerBackend endpoint targets the original public IP", func() {
+			want := fleetnetv1beta1.TrafficManagerBackend{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:       backendName,
+					Namespace:  testNamespace,
+					Finalizers: []string{objectmeta.TrafficManagerBackendFinalizer},
+				},
+				Spec: backend.Spec,
+				Status: fleetnetv1beta1.TrafficManagerBackendStatus{
+					Conditions: buildTrueCondition(backend.Generation),
+					Endpoints: []fleetnetv1beta1.TrafficManagerEndpointStatus{
+						{
+							Name: fmt.Sprintf(AzureResourceEndpointNameFormat, backendName+"#", serviceName, memberClusterNames[0]),
+							From: &fleetnetv1beta1.FromCluster{
+								ClusterStatus: fleetnetv1beta1.ClusterStatus{
+									Cluster: memberClusterNames[0],
+								},
+							},
+							Weight: ptr.To(int64(10)), // the backend weight is split across the accepted endpoints
 							Target: ptr.To(fakeprovider.ValidEndpointTarget),
 						},
 					},
@@ -980,6 +1290,34 @@ var _ = Describe("Test TrafficManagerBackend Controller", func() {
 		})
 
 		It("Validating trafficManagerBackend", func() {
+			want := fleetnetv1beta1.TrafficManagerBackend{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:       backendName,
+					Namespace:  testNamespace,
+					Finalizers: []string{objectmeta.TrafficManagerBackendFinalizer},
+				},
+				Spec: backend.Spec,
+				Status: fleetnetv1beta1.TrafficManagerBackendStatus{
+					Conditions: buildDisabledCondition(backend.Generation),
+				},
+			}
+			validator.ValidateTrafficManagerBackend(ctx, k8sClient, &want)
+			validator.ValidateTrafficManagerBackendConsistently(ctx, k8sClient, &want)
+		})
+
+		It("Validating the endpoint weight metric", func() {
+			validateEndpointWeightMetric(backendNamespacedName, nil)
+		})
+
+		It("Updating weight from 0 back to a positive value", func() {
+			Expect(k8sClient.Get(ctx, backendNamespacedName, backend)).Should(Succeed(), "failed to get trafficManagerBackend")
+			backend.Spec.Weight = ptr.To(int64(10))
+			Expect(k8sClient.Update(ctx, backend)).Should(Succeed(), "failed to update trafficManagerBackend")
+		})
+
+		It("Validating trafficManagerBackend endpoints are recreated", func() {
+			// No other object changes, so the endpoints must be recreated by the reconciliation triggered by the
+			// weight update itself.
 			want := fleetnetv1beta1.TrafficManagerBackend{
 				ObjectMeta: metav1.ObjectMeta{
 					Name:       backendName,
@@ -989,6 +1327,18 @@ var _ = Describe("Test TrafficManagerBackend Controller", func() {
 				Spec: backend.Spec,
 				Status: fleetnetv1beta1.TrafficManagerBackendStatus{
 					Conditions: buildTrueCondition(backend.Generation),
+					Endpoints: []fleetnetv1beta1.TrafficManagerEndpointStatus{
+						{
+							Name: fmt.Sprintf(AzureResourceEndpointNameFormat, backendName+"#", serviceName, memberClusterNames[0]),
+							From: &fleetnetv1beta1.FromCluster{
+								ClusterStatus: fleetnetv1beta1.ClusterStatus{
+									Cluster: memberClusterNames[0],
+								},
+							},
+							Weight: ptr.To(int64(10)), // the backend weight is split across the accepted endpoints
+							Target: ptr.To(fakeprovider.ValidEndpointTarget),
+						},
+					},
 				},
 			}
 			validator.ValidateTrafficManagerBackend(ctx, k8sClient, &want)
@@ -1004,6 +1354,10 @@ var _ = Describe("Test TrafficManagerBackend Controller", func() {
 			validator.IsTrafficManagerBackendDeleted(ctx, k8sClient, backendNamespacedName)
 		})
 
+		It("Validating the endpoint weight metric", func() {
+			validateEndpointWeightMetric(backendNamespacedName, nil)
+		})
+
 		It("Deleting trafficManagerProfile", func() {
 			err := k8sClient.Delete(ctx, profile)
 			Expect(err).Should(Succeed(), "failed to delete trafficManagerProfile")
@@ -1017,7 +1371,620 @@ var _ = Describe("Test TrafficManagerBackend Controller", func() {
 			deleteServiceImport(types.NamespacedName{Namespace: testNamespace, Name: serviceName})
 		})
 	})
-})
+
+	Context("When creating trafficManagerBackend with custom headers and subnet overrides", Ordered, func() {
+		profileName := fakeprovider.ValidProfileName
+		profileNamespacedName := types.NamespacedName{Namespace: testNamespace, Name: profileName}
+		var profile *fleetnetv1beta1.TrafficManagerProfile
+		backendName := fakeprovider.ValidBackendName
+		backendNamespacedName := types.NamespacedName{Namespace: testNamespace, Name: backendName}
+		var backend *fleetnetv1beta1.TrafficManagerBackend
+
+		var serviceImport *fleetnetv1alpha1.ServiceImport
+		customHeaders := []fleetnetv1beta1.TrafficManagerEndpointCustomHeader{
+			{Name: "host", Value: "contoso.com"},
+		}
+
+		It("Creating a new TrafficManagerProfile", func() {
+			By("By creating a new TrafficManagerProfile")
+			profile = trafficManagerProfileForTest(profileName)
+			Expect(k8sClient.Create(ctx, profile)).Should(Succeed())
+		})
+
+		It("Updating TrafficManagerProfile status to programmed true", func() {
+			By("By updating TrafficManagerProfile status")
+			updateTrafficManagerProfileStatusToTrue(ctx, profile)
+		})
+
+		It("Creating a new ServiceImport", func() {
+			serviceImport = &fleetnetv1alpha1.ServiceImport{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      serviceName,
+					Namespace: testNamespace,
+				},
+			}
+			Expect(k8sClient.Create(ctx, serviceImport)).Should(Succeed(), "failed to create serviceImport")
+			serviceImport.Status = fleetnetv1alpha1.ServiceImportStatus{
+				Clusters: []fleetnetv1alpha1.ClusterStatus{
+					{
+						Cluster: memberClusterNames[0],
+					},
+				},
+			}
+			Expect(k8sClient.Status().Update(ctx, serviceImport)).Should(Succeed(), "failed to update serviceImport status")
+		})
+
+		It("Creating TrafficManagerBackend", func() {
+			backend = trafficManagerBackendForTest(backendName, profileName, serviceName)
+			backend.Spec.CustomHeaders = customHeaders
+			backend.Spec.SubnetOverrides = []fleetnetv1beta1.TrafficManagerSubnetOverride{
+				{Cluster: memberClusterNames[0], Subnets: []string{"10.1.0.0/16"}},
+			}
+			Expect(k8sClient.Create(ctx, backend)).Should(Succeed())
+		})
+
+		It("Validating trafficManagerBackend", func() {
+			want := fleetnetv1beta1.TrafficManagerBackend{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:       backendName,
+					Namespace:  testNamespace,
+					Finalizers: []string{objectmeta.TrafficManagerBackendFinalizer},
+				},
+				Spec: backend.Spec,
+				Status: fleetnetv1beta1.TrafficManagerBackendStatus{
+					Conditions: buildTrueCondition(backend.Generation),
+					Endpoints: []fleetnetv1beta1.TrafficManagerEndpointStatus{
+						{
+							Name: fmt.Sprintf(AzureResourceEndpointNameFormat, backendName+"#", serviceName, memberClusterNames[0]),
+							From: &fleetnetv1beta1.FromCluster{
+								ClusterStatus: fleetnetv1beta1.ClusterStatus{
+									Cluster: memberClusterNames[0],
+								},
+							},
+							Weight:        ptr.To(int64(10)), // the backend weight is split across the accepted endpoints
+							Target:        ptr.To(fakeprovider.ValidEndpointTarget),
+							CustomHeaders: customHeaders,
+							Subnets:       []string{"10.1.0.0/16"},
+						},
+					},
+				},
+			}
+			validator.ValidateTrafficManagerBackend(ctx, k8sClient, &want)
+			validator.ValidateTrafficManagerBackendConsistently(ctx, k8sClient, &want)
+		})
+
+		It("Updating the subnet overrides", func() {
+			Expect(k8sClient.Get(ctx, backendNamespacedName, backend)).Should(Succeed(), "failed to get trafficManagerBackend")
+			backend.Spec.SubnetOverrides = []fleetnetv1beta1.TrafficManagerSubnetOverride{
+				{Cluster: memberClusterNames[0], Subnets: []string{"10.2.3.4/24", "2001:db8::/32"}},
+			}
+			Expect(k8sClient.Update(ctx, backend)).Should(Succeed(), "failed to update trafficManagerBackend")
+		})
+
+		It("Validating trafficManagerBackend", func() {
+			want := fleetnetv1beta1.TrafficManagerBackend{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:       backendName,
+					Namespace:  testNamespace,
+					Finalizers: []string{objectmeta.TrafficManagerBackendFinalizer},
+				},
+				Spec: backend.Spec,
+				Status: fleetnetv1beta1.TrafficManagerBackendStatus{
+					Conditions: buildTrueCondition(backend.Generation),
+					Endpoints: []fleetnetv1beta1.TrafficManagerEndpointStatus{
+						{
+							Name: fmt.Sprintf(AzureResourceEndpointNameFormat, backendName+"#", serviceName, memberClusterNames[0]),
+							From: &fleetnetv1beta1.FromCluster{
+								ClusterStatus: fleetnetv1beta1.ClusterStatus{
+									Cluster: memberClusterNames[0],
+								},
+							},
+							Weight:        ptr.To(int64(10)), // the backend weight is split across the accepted endpoints
+							Target:        ptr.To(fakeprovider.ValidEndpointTarget),
+							CustomHeaders: customHeaders,
+							Subnets:       []string{"10.2.3.0/24", "2001:db8::/32"}, // the subnets are masked
+						},
+					},
+				},
+			}
+			validator.ValidateTrafficManagerBackend(ctx, k8sClient, &want)
+			validator.ValidateTrafficManagerBackendConsistently(ctx, k8sClient, &want)
+		})
+
+		It("Updating the subnet overrides to overlap across clusters", func() {
+			Expect(k8sClient.Get(ctx, backendNamespacedName, backend)).Should(Succeed(), "failed to get trafficManagerBackend")
+			backend.Spec.SubnetOverrides = []fleetnetv1beta1.TrafficManagerSubnetOverride{
+				{Cluster: memberClusterNames[0], Subnets: []string{"10.2.0.0/16"}},
+				{Cluster: memberClusterNames[3], Subnets: []string{"10.2.3.0/24"}},
+			}
+			Expect(k8sClient.Update(ctx, backend)).Should(Succeed(), "failed to update trafficManagerBackend")
+		})
+
+		It("Validating trafficManagerBackend", func() {
+			want := fleetnetv1beta1.TrafficManagerBackend{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:       backendName,
+					Namespace:  testNamespace,
+					Finalizers: []string{objectmeta.TrafficManagerBackendFinalizer},
+				},
+				Spec: backend.Spec,
+				Status: fleetnetv1beta1.TrafficManagerBackendStatus{
+					Conditions: buildFalseCondition(backend.Generation),
+				},
+			}
+			validator.ValidateTrafficManagerBackend(ctx, k8sClient, &want)
+			validator.ValidateTrafficManagerBackendConsistently(ctx, k8sClient, &want)
+		})
+
+		It("Deleting trafficManagerBackend", func() {
+			err := k8sClient.Delete(ctx, backend)
+			Expect(err).Should(Succeed(), "failed to delete trafficManagerBackend")
+		})
+
+		It("Validating trafficManagerBackend is deleted", func() {
+			validator.IsTrafficManagerBackendDeleted(ctx, k8sClient, backendNamespacedName)
+		})
+
+		It("Deleting trafficManagerProfile", func() {
+			err := k8sClient.Delete(ctx, profile)
+			Expect(err).Should(Succeed(), "failed to delete trafficManagerProfile")
+		})
+
+		It("Validating trafficManagerProfile is deleted", func() {
+			validator.IsTrafficManagerProfileDeleted(ctx, k8sClient, profileNamespacedName)
+		})
+
+		It("Deleting serviceImport", func() {
+			deleteServiceImport(types.NamespacedName{Namespace: testNamespace, Name: serviceName})
+		})
+	})
+
+	Context("When creating trafficManagerBackend with priorities", Ordered, func() {
+		profileName := fakeprovider.ValidProfileName
+		profileNamespacedName := types.NamespacedName{Namespace: testNamespace, Name: profileName}
+		var profile *fleetnetv1beta1.TrafficManagerProfile
+		backendName := fakeprovider.ValidBackendName
+		backendNamespacedName := types.NamespacedName{Namespace: testNamespace, Name: backendName}
+		var backend *fleetnetv1beta1.TrafficManagerBackend
+
+		var serviceImport *fleetnetv1alpha1.ServiceImport
+
+		It("Creating a new TrafficManagerProfile", func() {
+			By("By creating a new TrafficManagerProfile")
+			profile = trafficManagerProfileForTest(profileName)
+			Expect(k8sClient.Create(ctx, profile)).Should(Succeed())
+		})
+
+		It("Updating TrafficManagerProfile status to programmed true", func() {
+			By("By updating TrafficManagerProfile status")
+			updateTrafficManagerProfileStatusToTrue(ctx, profile)
+		})
+
+		It("Creating a new ServiceImport", func() {
+			serviceImport = &fleetnetv1alpha1.ServiceImport{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      serviceName,
+					Namespace: testNamespace,
+				},
+			}
+			Expect(k8sClient.Create(ctx, serviceImport)).Should(Succeed(), "failed to create serviceImport")
+			serviceImport.Status = fleetnetv1alpha1.ServiceImportStatus{
+				Clusters: []fleetnetv1alpha1.ClusterStatus{
+					{
+						Cluster: memberClusterNames[0],
+					},
+					{
+						Cluster: memberClusterNames[3],
+					},
+				},
+			}
+			Expect(k8sClient.Status().Update(ctx, serviceImport)).Should(Succeed(), "failed to update serviceImport status")
+		})
+
+		It("Creating TrafficManagerBackend with duplicate priorities should fail", func() {
+			invalid := trafficManagerBackendForTest(backendName, profileName, serviceName)
+			invalid.Spec.Priorities = []fleetnetv1beta1.TrafficManagerClusterPriority{
+				{Cluster: memberClusterNames[0], Priority: 1},
+				{Cluster: memberClusterNames[3], Priority: 1},
+			}
+			err := k8sClient.Create(ctx, invalid)
+			Expect(err).ShouldNot(Succeed(), "created trafficManagerBackend with duplicate priorities")
+			Expect(err.Error()).Should(ContainSubstring("priorities must be unique"))
+		})
+
+		It("Creating TrafficManagerBackend", func() {
+			backend = trafficManagerBackendForTest(backendName, profileName, serviceName)
+			backend.Spec.Priorities = []fleetnetv1beta1.TrafficManagerClusterPriority{
+				{Cluster: memberClusterNames[3], Priority: 1},
+			}
+			Expect(k8sClient.Create(ctx, backend)).Should(Succeed())
+		})
+
+		It("Validating trafficManagerBackend", func() {
+			want := fleetnetv1beta1.TrafficManagerBackend{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:       backendName,
+					Namespace:  testNamespace,
+					Finalizers: []string{objectmeta.TrafficManagerBackendFinalizer},
+				},
+				Spec: backend.Spec,
+				Status: fleetnetv1beta1.TrafficManagerBackendStatus{
+					Conditions: buildTrueCondition(backend.Generation),
+					Endpoints: []fleetnetv1beta1.TrafficManagerEndpointStatus{
+						{
+							Name: fmt.Sprintf(AzureResourceEndpointNameFormat, backendName+"#", serviceName, memberClusterNames[0]),
+							From: &fleetnetv1beta1.FromCluster{
+								ClusterStatus: fleetnetv1beta1.ClusterStatus{
+									Cluster: memberClusterNames[0],
+								},
+							},
+							Weight:   ptr.To(int64(5)),
+							Target:   ptr.To(fakeprovider.ValidEndpointTarget),
+							Priority: ptr.To(int64(2)), // the unlisted cluster is assigned the next priority
+						},
+						{
+							Name: fmt.Sprintf(AzureResourceEndpointNameFormat, backendName+"#", serviceName, memberClusterNames[3]),
+							From: &fleetnetv1beta1.FromCluster{
+								ClusterStatus: fleetnetv1beta1.ClusterStatus{
+									Cluster: memberClusterNames[3],
+								},
+							},
+							Weight:   ptr.To(int64(5)),
+							Target:   ptr.To(fakeprovider.ValidEndpointTarget),
+							Priority: ptr.To(int64(1)),
+						},
+					},
+				},
+			}
+			validator.ValidateTrafficManagerBackend(ctx, k8sClient, &want)
+			validator.ValidateTrafficManagerBackendConsistently(ctx, k8sClient, &want)
+		})
+
+		It("Reordering the priorities", func() {
+			Expect(k8sClient.Get(ctx, backendNamespacedName, backend)).Should(Succeed(), "failed to get trafficManagerBackend")
+			backend.Spec.Priorities = []fleetnetv1beta1.TrafficManagerClusterPriority{
+				{Cluster: memberClusterNames[0], Priority: 1},
+				{Cluster: memberClusterNames[3], Priority: 2},
+			}
+			Expect(k8sClient.Update(ctx, backend)).Should(Succeed(), "failed to update trafficManagerBackend")
+		})
+
+		It("Validating trafficManagerBackend", func() {
+			want := fleetnetv1beta1.TrafficManagerBackend{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:       backendName,
+					Namespace:  testNamespace,
+					Finalizers: []string{objectmeta.TrafficManagerBackendFinalizer},
+				},
+				Spec: backend.Spec,
+				Status: fleetnetv1beta1.TrafficManagerBackendStatus{
+					Conditions: buildTrueCondition(backend.Generation),
+					Endpoints: []fleetnetv1beta1.TrafficManagerEndpointStatus{
+						{
+							Name: fmt.Sprintf(AzureResourceEndpointNameFormat, backendName+"#", serviceName, memberClusterNames[0]),
+							From: &fleetnetv1beta1.FromCluster{
+								ClusterStatus: fleetnetv1beta1.ClusterStatus{
+									Cluster: memberClusterNames[0],
+								},
+							},
+							Weight:   ptr.To(int64(5)),
+							Target:   ptr.To(fakeprovider.ValidEndpointTarget),
+							Priority: ptr.To(int64(1)),
+						},
+						{
+							Name: fmt.Sprintf(AzureResourceEndpointNameFormat, backendName+"#", serviceName, memberClusterNames[3]),
+							From: &fleetnetv1beta1.FromCluster{
+								ClusterStatus: fleetnetv1beta1.ClusterStatus{
+									Cluster: memberClusterNames[3],
+								},
+							},
+							Weight:   ptr.To(int64(5)),
+							Target:   ptr.To(fakeprovider.ValidEndpointTarget),
+							Priority: ptr.To(int64(2)),
+						},
+					},
+				},
+			}
+			validator.ValidateTrafficManagerBackend(ctx, k8sClient, &want)
+			validator.ValidateTrafficManagerBackendConsistently(ctx, k8sClient, &want)
+		})
+
+		It("Deleting trafficManagerBackend", func() {
+			err := k8sClient.Delete(ctx, backend)
+			Expect(err).Should(Succeed(), "failed to delete trafficManagerBackend")
+		})
+
+		It("Validating trafficManagerBackend is deleted", func() {
+			validator.IsTrafficManagerBackendDeleted(ctx, k8sClient, backendNamespacedName)
+		})
+
+		It("Deleting trafficManagerProfile", func() {
+			err := k8sClient.Delete(ctx, profile)
+			Expect(err).Should(Succeed(), "failed to delete trafficManagerProfile")
+		})
+
+		It("Validating trafficManagerProfile is deleted", func() {
+			validator.IsTrafficManagerProfileDeleted(ctx, k8sClient, profileNamespacedName)
+		})
+
+		It("Deleting serviceImport", func() {
+			deleteServiceImport(types.NamespacedName{Namespace: testNamespace, Name: serviceName})
+		})
+	})
+
+	Context("When creating two trafficManagerBackends with the same profile and serviceImport", Ordered, func() {
+		// The older backend is named after the newer one, so that the creation order decides which one is accepted.
+		olderName := "duplicate-backend-b"
+		olderNamespacedName := types.NamespacedName{Namespace: testNamespace, Name: olderName}
+		newerName := "duplicate-backend-a"
+		newerNamespacedName := types.NamespacedName{Namespace: testNamespace, Name: newerName}
+		var olderBackend, newerBackend *fleetnetv1beta1.TrafficManagerBackend
+
+		It("Creating the older TrafficManagerBackend", func() {
+			olderBackend = trafficManagerBackendForTest(olderName, "not-exist", "not-exist")
+			Expect(k8sClient.Create(ctx, olderBackend)).Should(Succeed())
+		})
+
+		It("Validating the older trafficManagerBackend", func() {
+			want := fleetnetv1beta1.TrafficManagerBackend{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:       olderName,
+					Namespace:  testNamespace,
+					Finalizers: []string{objectmeta.TrafficManagerBackendFinalizer},
+				},
+				Spec: olderBackend.Spec,
+				Status: fleetnetv1beta1.TrafficManagerBackendStatus{
+					Conditions: buildFalseCondition(olderBackend.Generation),
+				},
+			}
+			validator.ValidateTrafficManagerBackend(ctx, k8sClient, &want)
+		})
+
+		It("Creating the newer TrafficManagerBackend", func() {
+			// The creation timestamps only have the precision of a second.
+			Eventually(func() bool {
+				return time.Now().Truncate(time.Second).After(olderBackend.CreationTimestamp.Time)
+			}, timeout, interval).Should(BeTrue())
+			newerBackend = trafficManagerBackendForTest(newerName, "not-exist", "not-exist")
+			Expect(k8sClient.Create(ctx, newerBackend)).Should(Succeed())
+		})
+
+		It("Validating the newer trafficManagerBackend is rejected as a duplicate", func() {
+			want := fleetnetv1beta1.TrafficManagerBackend{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:       newerName,
+					Namespace:  testNamespace,
+					Finalizers: []string{objectmeta.TrafficManagerBackendFinalizer},
+				},
+				Spec: newerBackend.Spec,
+				Status: fleetnetv1beta1.TrafficManagerBackendStatus{
+					Conditions: buildDuplicateCondition(newerBackend.Generation),
+				},
+			}
+			validator.ValidateTrafficManagerBackend(ctx, k8sClient, &want)
+			validator.ValidateTrafficManagerBackendConsistently(ctx, k8sClient, &want)
+
+			got := &fleetnetv1beta1.TrafficManagerBackend{}
+			Expect(k8sClient.Get(ctx, newerNamespacedName, got)).Should(Succeed())
+			cond := meta.FindStatusCondition(got.Status.Conditions, string(fleetnetv1beta1.TrafficManagerBackendConditionAccepted))
+			Expect(cond.Message).Should(ContainSubstring(olderName), "the condition should name the older backend")
+		})
+
+		It("Validating the older trafficManagerBackend is unaffected", func() {
+			want := fleetnetv1beta1.TrafficManagerBackend{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:       olderName,
+					Namespace:  testNamespace,
+					Finalizers: []string{objectmeta.TrafficManagerBackendFinalizer},
+				},
+				Spec: olderBackend.Spec,
+				Status: fleetnetv1beta1.TrafficManagerBackendStatus{
+					Conditions: buildFalseCondition(olderBackend.Generation),
+				},
+			}
+			validator.ValidateTrafficManagerBackendConsistently(ctx, k8sClient, &want)
+		})
+
+		It("Deleting the older trafficManagerBackend", func() {
+			Expect(k8sClient.Delete(ctx, olderBackend)).Should(Succeed(), "failed to delete trafficManagerBackend")
+		})
+
+		It("Validating the older trafficManagerBackend is deleted", func() {
+			validator.IsTrafficManagerBackendDeleted(ctx, k8sClient, olderNamespacedName)
+		})
+
+		It("Validating the newer trafficManagerBackend takes over", func() {
+			want := fleetnetv1beta1.TrafficManagerBackend{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:       newerName,
+					Namespace:  testNamespace,
+					Finalizers: []string{objectmeta.TrafficManagerBackendFinalizer},
+				},
+				Spec: newerBackend.Spec,
+				Status: fleetnetv1beta1.TrafficManagerBackendStatus{
+					// The backend passes the duplicate check and is now validated against its (not found) profile.
+					Conditions: buildFalseCondition(newerBackend.Generation),
+				},
+			}
+			validator.ValidateTrafficManagerBackend(ctx, k8sClient, &want)
+		})
+
+		It("Deleting the newer trafficManagerBackend", func() {
+			Expect(k8sClient.Delete(ctx, newerBackend)).Should(Succeed(), "failed to delete trafficManagerBackend")
+		})
+
+		It("Validating the newer trafficManagerBackend is deleted", func() {
+			validator.IsTrafficManagerBackendDeleted(ctx, k8sClient, newerNamespacedName)
+		})
+	})
+
+	Context("When exporting the service from five member clusters", Ordered, func() {
+		profileName := fakeprovider.ValidProfileName
+		profileNamespacedName := types.NamespacedName{Namespace: testNamespace, Name: profileName}
+		var profile *fleetnetv1beta1.TrafficManagerProfile
+		backendName := fakeprovider.ValidBackendName
+		backendNamespacedName := types.NamespacedName{Namespace: testNamespace, Name: backendName}
+		var backend *fleetnetv1beta1.TrafficManagerBackend
+		fiveMemberServiceName := "five-member-service"
+		var serviceImport *fleetnetv1alpha1.ServiceImport
+		var fiveMembers []*fleetenv.Member
+
+		wantEndpoints := func(members []*fleetenv.Member, weight int64) []fleetnetv1beta1.TrafficManagerEndpointStatus {
+			endpoints := make([]fleetnetv1beta1.TrafficManagerEndpointStatus, 0, len(members))
+			for _, member := range members {
+				endpoints = append(endpoints, fleetnetv1beta1.TrafficManagerEndpointStatus{
+					Name: fmt.Sprintf(AzureResourceEndpointNameFormat, backendName+"#", fiveMemberServiceName, member.Name),
+					From: &fleetnetv1beta1.FromCluster{
+						ClusterStatus: fleetnetv1beta1.ClusterStatus{
+							Cluster: member.Name,
+						},
+					},
+					Weight: ptr.To(weight),
+					Target: ptr.To(fakeprovider.ValidEndpointTarget),
+				})
+			}
+			return endpoints
+		}
+
+		It("Creating five simulated member clusters exporting the service", func() {
+			fiveMembers = fleetenv.CreateSimulatedMembers(ctx, k8sClient, fleetenv.MemberNames("five-member", 5)...)
+			for _, member := range fiveMembers {
+				member.CloudProvider = fleetenv.CloudProvider{IsDNSLabelConfigured: true}
+				fleetenv.ExportService(ctx, k8sClient, member, loadBalancerServiceForTest(fiveMemberServiceName), nil)
+			}
+		})
+
+		It("Creating a new TrafficManagerProfile", func() {
+			profile = trafficManagerProfileForTest(profileName)
+			Expect(k8sClient.Create(ctx, profile)).Should(Succeed())
+		})
+
+		It("Updating TrafficManagerProfile status to programmed true", func() {
+			updateTrafficManagerProfileStatusToTrue(ctx, profile)
+		})
+
+		It("Creating a new ServiceImport with the five member clusters", func() {
+			serviceImport = &fleetnetv1alpha1.ServiceImport{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      fiveMemberServiceName,
+					Namespace: testNamespace,
+				},
+			}
+			Expect(k8sClient.Create(ctx, serviceImport)).Should(Succeed(), "failed to create serviceImport")
+			for _, member := range fiveMembers {
+				serviceImport.Status.Clusters = append(serviceImport.Status.Clusters, fleetnetv1alpha1.ClusterStatus{Cluster: member.Name})
+			}
+			Expect(k8sClient.Status().Update(ctx, serviceImport)).Should(Succeed(), "failed to update serviceImport status")
+		})
+
+		It("Creating TrafficManagerBackend", func() {
+			backend = trafficManagerBackendForTest(backendName, profileName, fiveMemberServiceName)
+			Expect(k8sClient.Create(ctx, backend)).Should(Succeed())
+		})
+
+		It("Validating trafficManagerBackend", func() {
+			want := fleetnetv1beta1.TrafficManagerBackend{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:       backendName,
+					Namespace:  testNamespace,
+					Finalizers: []string{objectmeta.TrafficManagerBackendFinalizer},
+				},
+				Spec: backend.Spec,
+				Status: fleetnetv1beta1.TrafficManagerBackendStatus{
+					Conditions: buildTrueCondition(backend.Generation),
+					Endpoints:  wantEndpoints(fiveMembers, 2), // the backend weight is split across the five endpoints
+				},
+			}
+			validator.ValidateTrafficManagerBackend(ctx, k8sClient, &want)
+		})
+
+		It("Exporting the service from an outdated member agent of the fifth member cluster", func() {
+			fiveMembers[4].CloudProvider = nil
+			fleetenv.ExportService(ctx, k8sClient, fiveMembers[4], loadBalancerServiceForTest(fiveMemberServiceName), nil)
+		})
+
+		It("Validating trafficManagerBackend reports the member agent to upgrade", func() {
+			want := fleetnetv1beta1.TrafficManagerBackend{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:       backendName,
+					Namespace:  testNamespace,
+					Finalizers: []string{objectmeta.TrafficManagerBackendFinalizer},
+				},
+				Spec: backend.Spec,
+				Status: fleetnetv1beta1.TrafficManagerBackendStatus{
+					Conditions: []metav1.Condition{
+						{
+							Status:             metav1.ConditionFalse,
+							Type:               string(fleetnetv1beta1.TrafficManagerBackendConditionAccepted),
+							Reason:             string(fleetnetv1beta1.TrafficManagerBackendReasonAgentUpgradeRequired),
+							ObservedGeneration: backend.Generation,
+						},
+					},
+					Endpoints: wantEndpoints(fiveMembers[:4], 3), // the backend weight is split across the four accepted endpoints
+					InvalidClusters: []fleetnetv1beta1.TrafficManagerInvalidCluster{
+						{Cluster: fiveMembers[4].Name, Reason: fleetnetv1beta1.TrafficManagerInvalidClusterReasonAgentUpgradeRequired},
+					},
+					TotalInvalid: 1,
+				},
+			}
+			validator.ValidateTrafficManagerBackend(ctx, k8sClient, &want)
+		})
+
+		It("Deleting trafficManagerBackend", func() {
+			Expect(k8sClient.Delete(ctx, backend)).Should(Succeed(), "failed to delete trafficManagerBackend")
+		})
+
+		It("Validating trafficManagerBackend is deleted", func() {
+			validator.IsTrafficManagerBackendDeleted(ctx, k8sClient, backendNamespacedName)
+		})
+
+		It("Deleting trafficManagerProfile", func() {
+			Expect(k8sClient.Delete(ctx, profile)).Should(Succeed(), "failed to delete trafficManagerProfile")
+		})
+
+		It("Validating trafficManagerProfile is deleted", func() {
+			validator.IsTrafficManagerProfileDeleted(ctx, k8sClient, profileNamespacedName)
+		})
+
+		It("Deleting serviceImport", func() {
+			deleteServiceImport(types.NamespacedName{Namespace: testNamespace, Name: fiveMemberServiceName})
+		})
+
+		It("Deleting the five simulated member clusters", func() {
+			fleetenv.DeleteSimulatedMembers(ctx, k8sClient, fiveMembers)
+		})
+	})
+})
+
+// validateEndpointWeightMetric validates the series of the trafficManagerEndpointWeight metric of the backend, keyed by
+// the cluster.
+func validateEndpointWeightMetric(backendName types.NamespacedName, want map[string]float64) {
+	Eventually(func() error {
+		metricFamilies, err := ctrlmetrics.Registry.Gather()
+		if err != nil {
+			return err
+		}
+		got := map[string]float64{}
+		for _, metricFamily := range metricFamilies {
+			if metricFamily.GetName() != "fleet_networking_traffic_manager_endpoint_weight" {
+				continue
+			}
+			for _, metric := range metricFamily.GetMetric() {
+				labels := map[string]string{}
+				for _, label := range metric.GetLabel() {
+					labels[label.GetName()] = label.GetValue()
+				}
+				if labels["namespace"] == backendName.Namespace && labels["backend"] == backendName.Name {
+					got[labels["cluster"]] = metric.GetGauge().GetValue()
+				}
+			}
+		}
+		if diff := cmp.Diff(want, got, cmpopts.EquateEmpty()); diff != "" {
+			return fmt.Errorf("trafficManagerEndpointWeight metric mismatch (-want, +got):\n%s", diff)
+		}
+		return nil
+	}, timeout, interval).Should(Succeed(), "Failed to validate the trafficManagerEndpointWeight metric")
+}
 
 func deleteServiceImport(name types.NamespacedName) {
 	serviceImport := &fleetnetv1alpha1.ServiceImport{
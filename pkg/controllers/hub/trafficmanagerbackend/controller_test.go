@@ -6,13 +6,52 @@ Licensed under the MIT license.
 package trafficmanagerbackend
 
 import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
 	"testing"
+	"time"
 
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/arm"
+	azcorefake "github.com/Azure/azure-sdk-for-go/sdk/azcore/fake"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
 	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/trafficmanager/armtrafficmanager"
+	atmfake "github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/trafficmanager/armtrafficmanager/fake"
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/prometheus/client_golang/prometheus/testutil"
 	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
 	"k8s.io/utils/ptr"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+
+	"go.goms.io/fleet/pkg/utils/cloudconfig/azure"
 
 	fleetnetv1alpha1 "go.goms.io/fleet-networking/api/v1alpha1"
+	fleetnetv1beta1 "go.goms.io/fleet-networking/api/v1beta1"
+	"go.goms.io/fleet-networking/pkg/common/adaptivelimiter"
+	"go.goms.io/fleet-networking/pkg/common/azureerrors"
+	"go.goms.io/fleet-networking/pkg/common/cloudconfig"
+	"go.goms.io/fleet-networking/pkg/common/nsallowlist"
+	"go.goms.io/fleet-networking/pkg/common/objectmeta"
+	"go.goms.io/fleet-networking/test/common/trafficmanager/fakeprovider"
 )
 
 func TestIsValidTrafficManagerEndpoint(t *testing.T) {
@@ -20,6 +59,8 @@ func TestIsValidTrafficManagerEndpoint(t *testing.T) {
 		name    string
 		export  *fleetnetv1alpha1.InternalServiceExport
 		wantErr bool
+		// wantErrMsg is checked only when set.
+		wantErrMsg string
 	}{
 		{
 			name: "valid endpoint",
@@ -41,7 +82,8 @@ func TestIsValidTrafficManagerEndpoint(t *testing.T) {
 					IsInternalLoadBalancer: false,
 				},
 			},
-			wantErr: true,
+			wantErr:    true,
+			wantErrMsg: `unsupported service type "ClusterIP"; only LoadBalancer services or services exported with a public IP address can be added as Azure Traffic Manager endpoints`,
 		},
 		{
 			name: "load balancer type with internal ip",
@@ -54,15 +96,158 @@ func TestIsValidTrafficManagerEndpoint(t *testing.T) {
 			},
 			wantErr: true,
 		},
+		{
+			name: "load balancer type with TCP ports",
+			export: &fleetnetv1alpha1.InternalServiceExport{
+				Spec: fleetnetv1alpha1.InternalServiceExportSpec{
+					Type:                 corev1.ServiceTypeLoadBalancer,
+					IsDNSLabelConfigured: true,
+					Ports: []fleetnetv1alpha1.ServicePort{
+						{
+							Protocol: corev1.ProtocolTCP,
+							Port:     80,
+						},
+					},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "load balancer type with UDP ports",
+			export: &fleetnetv1alpha1.InternalServiceExport{
+				Spec: fleetnetv1alpha1.InternalServiceExportSpec{
+					Type:                 corev1.ServiceTypeLoadBalancer,
+					IsDNSLabelConfigured: true,
+					Ports: []fleetnetv1alpha1.ServicePort{
+						{
+							Protocol: corev1.ProtocolTCP,
+							Port:     80,
+						},
+						{
+							Protocol: corev1.ProtocolUDP,
+							Port:     7777,
+						},
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "load balancer type with SCTP ports",
+			export: &fleetnetv1alpha1.InternalServiceExport{
+				Spec: fleetnetv1alpha1.InternalServiceExportSpec{
+					Type:                 corev1.ServiceTypeLoadBalancer,
+					IsDNSLabelConfigured: true,
+					Ports: []fleetnetv1alpha1.ServicePort{
+						{
+							Protocol: corev1.ProtocolSCTP,
+							Port:     9999,
+						},
+					},
+				},
+			},
+			wantErr: true,
+		},
 		{
 			name: "load balancer type with public ip but dns label not configured",
 			export: &fleetnetv1alpha1.InternalServiceExport{
+				ObjectMeta: metav1.ObjectMeta{
+					Annotations: map[string]string{objectmeta.InternalServiceExportAnnotationTrafficManagerInfoReported: "true"},
+				},
 				Spec: fleetnetv1alpha1.InternalServiceExportSpec{
 					Type:                 corev1.ServiceTypeLoadBalancer,
 					IsDNSLabelConfigured: false,
 				},
 			},
-			wantErr: true,
+			wantErr:    true,
+			wantErrMsg: "DNS label is not configured to the public IP",
+		},
+		{
+			name: "load balancer type with ip not yet provisioned",
+			export: &fleetnetv1alpha1.InternalServiceExport{
+				Spec: fleetnetv1alpha1.InternalServiceExportSpec{
+					Type:                  corev1.ServiceTypeLoadBalancer,
+					IsLoadBalancerPending: true,
+				},
+			},
+			wantErr:    true,
+			wantErrMsg: "load balancer IP not yet provisioned",
+		},
+		{
+			name: "internal load balancer type with ip not yet provisioned",
+			export: &fleetnetv1alpha1.InternalServiceExport{
+				Spec: fleetnetv1alpha1.InternalServiceExportSpec{
+					Type:                   corev1.ServiceTypeLoadBalancer,
+					IsInternalLoadBalancer: true,
+					IsLoadBalancerPending:  true,
+				},
+			},
+			wantErr:    true,
+			wantErrMsg: "internal load balancer is not supported",
+		},
+		{
+			name: "cluster IP type with explicit public ip",
+			export: &fleetnetv1alpha1.InternalServiceExport{
+				Spec: fleetnetv1alpha1.InternalServiceExportSpec{
+					Type:                 corev1.ServiceTypeClusterIP,
+					PublicIPResourceID:   ptr.To("/subscriptions/sub1/resourceGroups/rg/providers/Microsoft.Network/publicIPAddresses/gateway-pip"),
+					IsDNSLabelConfigured: true,
+					IsPublicIPExplicit:   true,
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "cluster IP type with explicit public ip but dns label not configured",
+			export: &fleetnetv1alpha1.InternalServiceExport{
+				Spec: fleetnetv1alpha1.InternalServiceExportSpec{
+					Type:               corev1.ServiceTypeClusterIP,
+					IsPublicIPExplicit: true,
+				},
+			},
+			wantErr:    true,
+			wantErrMsg: "DNS label is not configured to the public IP",
+		},
+		{
+			name: "load balancer type with public ip reported but dns label not configured",
+			export: &fleetnetv1alpha1.InternalServiceExport{
+				Spec: fleetnetv1alpha1.InternalServiceExportSpec{
+					Type:               corev1.ServiceTypeLoadBalancer,
+					PublicIPResourceID: ptr.To("pip"),
+				},
+			},
+			wantErr:    true,
+			wantErrMsg: "DNS label is not configured to the public IP",
+		},
+		{
+			name: "load balancer type reported without the marker but dns label not configured",
+			export: &fleetnetv1alpha1.InternalServiceExport{
+				Spec: fleetnetv1alpha1.InternalServiceExportSpec{
+					Type: corev1.ServiceTypeLoadBalancer,
+				},
+			},
+			wantErr:    true,
+			wantErrMsg: "DNS label is not configured to the public IP",
+		},
+		{
+			name: "traffic manager info not reported by the member agent",
+			export: &fleetnetv1alpha1.InternalServiceExport{
+				Spec: fleetnetv1alpha1.InternalServiceExportSpec{
+					ServiceReference: fleetnetv1alpha1.ExportedObjectReference{ClusterID: "member-1"},
+				},
+			},
+			wantErr:    true,
+			wantErrMsg: "AgentUpgradeRequired: the member agent of cluster \"member-1\" has not reported the type, the public IP address and the DNS label of the service; upgrade the member agent, or enable its traffic manager feature",
+		},
+		{
+			name: "traffic manager info marked as not reported",
+			export: &fleetnetv1alpha1.InternalServiceExport{
+				ObjectMeta: metav1.ObjectMeta{
+					Annotations: map[string]string{objectmeta.InternalServiceExportAnnotationTrafficManagerInfoReported: "false"},
+				},
+			},
+			wantErr:    true,
+			wantErrMsg: "AgentUpgradeRequired: the member agent of cluster \"\" has not reported the type, the public IP address and the DNS label of the service; upgrade the member agent, or enable its traffic manager feature",
 		},
 	}
 	for _, tt := range tests {
@@ -71,6 +256,9 @@ func TestIsValidTrafficManagerEndpoint(t *testing.T) {
 			if got := err != nil; got != tt.wantErr {
 				t.Errorf("isValidTrafficManagerEndpoint() = %v, wantErr %v", err, tt.wantErr)
 			}
+			if tt.wantErrMsg != "" && err != nil && err.Error() != tt.wantErrMsg {
+				t.Errorf("isValidTrafficManagerEndpoint() = %q, want %q", err.Error(), tt.wantErrMsg)
+			}
 		})
 	}
 }
@@ -187,3 +375,2359 @@ func TestEqualAzureTrafficManagerEndpoint(t *testing.T) {
 		})
 	}
 }
+
+func TestEqualAzureTrafficManagerEndpoint_Overrides(t *testing.T) {
+	desired := armtrafficmanager.Endpoint{
+		Type: ptr.To(string(armtrafficmanager.EndpointTypeAzureEndpoints)),
+		Properties: &armtrafficmanager.EndpointProperties{
+			TargetResourceID: ptr.To("resourceID"),
+			EndpointStatus:   ptr.To(armtrafficmanager.EndpointStatusEnabled),
+			Weight:           ptr.To(int64(100)),
+			CustomHeaders: []*armtrafficmanager.EndpointPropertiesCustomHeadersItem{
+				{Name: ptr.To("host"), Value: ptr.To("contoso.com")},
+			},
+			Subnets: []*armtrafficmanager.EndpointPropertiesSubnetsItem{
+				{First: ptr.To("10.1.0.0"), Scope: ptr.To(int32(16))},
+			},
+			Priority: ptr.To(int64(2)),
+		},
+	}
+	tests := []struct {
+		name          string
+		customHeaders []*armtrafficmanager.EndpointPropertiesCustomHeadersItem
+		subnets       []*armtrafficmanager.EndpointPropertiesSubnetsItem
+		priority      *int64
+		want          bool
+	}{
+		{
+			name: "overrides are equal",
+			customHeaders: []*armtrafficmanager.EndpointPropertiesCustomHeadersItem{
+				{Name: ptr.To("host"), Value: ptr.To("contoso.com")},
+			},
+			subnets: []*armtrafficmanager.EndpointPropertiesSubnetsItem{
+				{First: ptr.To("10.1.0.0"), Scope: ptr.To(int32(16))},
+			},
+			priority: ptr.To(int64(2)),
+			want:     true,
+		},
+		{
+			name: "priority is missing",
+			customHeaders: []*armtrafficmanager.EndpointPropertiesCustomHeadersItem{
+				{Name: ptr.To("host"), Value: ptr.To("contoso.com")},
+			},
+			subnets: []*armtrafficmanager.EndpointPropertiesSubnetsItem{
+				{First: ptr.To("10.1.0.0"), Scope: ptr.To(int32(16))},
+			},
+		},
+		{
+			name: "priority is different",
+			customHeaders: []*armtrafficmanager.EndpointPropertiesCustomHeadersItem{
+				{Name: ptr.To("host"), Value: ptr.To("contoso.com")},
+			},
+			subnets: []*armtrafficmanager.EndpointPropertiesSubnetsItem{
+				{First: ptr.To("10.1.0.0"), Scope: ptr.To(int32(16))},
+			},
+			priority: ptr.To(int64(1)),
+		},
+		{
+			name: "custom headers are missing",
+			subnets: []*armtrafficmanager.EndpointPropertiesSubnetsItem{
+				{First: ptr.To("10.1.0.0"), Scope: ptr.To(int32(16))},
+			},
+			priority: ptr.To(int64(2)),
+		},
+		{
+			name: "custom header value is different",
+			customHeaders: []*armtrafficmanager.EndpointPropertiesCustomHeadersItem{
+				{Name: ptr.To("host"), Value: ptr.To("fabrikam.com")},
+			},
+			subnets: []*armtrafficmanager.EndpointPropertiesSubnetsItem{
+				{First: ptr.To("10.1.0.0"), Scope: ptr.To(int32(16))},
+			},
+			priority: ptr.To(int64(2)),
+		},
+		{
+			name: "subnets are missing",
+			customHeaders: []*armtrafficmanager.EndpointPropertiesCustomHeadersItem{
+				{Name: ptr.To("host"), Value: ptr.To("contoso.com")},
+			},
+			subnets:  []*armtrafficmanager.EndpointPropertiesSubnetsItem{},
+			priority: ptr.To(int64(2)),
+		},
+		{
+			name: "subnet scope is different",
+			customHeaders: []*armtrafficmanager.EndpointPropertiesCustomHeadersItem{
+				{Name: ptr.To("host"), Value: ptr.To("contoso.com")},
+			},
+			subnets: []*armtrafficmanager.EndpointPropertiesSubnetsItem{
+				{First: ptr.To("10.1.0.0"), Scope: ptr.To(int32(24))},
+			},
+			priority: ptr.To(int64(2)),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			current := armtrafficmanager.Endpoint{
+				Type: ptr.To(string(armtrafficmanager.EndpointTypeAzureEndpoints)),
+				Properties: &armtrafficmanager.EndpointProperties{
+					TargetResourceID: ptr.To("resourceID"),
+					EndpointStatus:   ptr.To(armtrafficmanager.EndpointStatusEnabled),
+					Weight:           ptr.To(int64(100)),
+					CustomHeaders:    tt.customHeaders,
+					Subnets:          tt.subnets,
+					Priority:         tt.priority,
+				},
+			}
+			if got := equalAzureTrafficManagerEndpoint(current, desired); got != tt.want {
+				t.Errorf("equalAzureTrafficManagerEndpoint() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidateEndpointOverrides(t *testing.T) {
+	tests := []struct {
+		name       string
+		overrides  []fleetnetv1beta1.TrafficManagerSubnetOverride
+		priorities []fleetnetv1beta1.TrafficManagerClusterPriority
+		wantErr    bool
+	}{
+		{
+			name: "no overrides",
+		},
+		{
+			name: "unique priorities",
+			priorities: []fleetnetv1beta1.TrafficManagerClusterPriority{
+				{Cluster: "member-1", Priority: 1},
+				{Cluster: "member-2", Priority: 1000},
+			},
+		},
+		{
+			name: "duplicate priorities",
+			priorities: []fleetnetv1beta1.TrafficManagerClusterPriority{
+				{Cluster: "member-1", Priority: 2},
+				{Cluster: "member-2", Priority: 2},
+			},
+			wantErr: true,
+		},
+		{
+			name: "priority out of range",
+			priorities: []fleetnetv1beta1.TrafficManagerClusterPriority{
+				{Cluster: "member-1", Priority: 1001},
+			},
+			wantErr: true,
+		},
+		{
+			name: "disjoint subnets",
+			overrides: []fleetnetv1beta1.TrafficManagerSubnetOverride{
+				{Cluster: "member-1", Subnets: []string{"10.1.0.0/16", "2001:db8::/32"}},
+				{Cluster: "member-2", Subnets: []string{"10.2.0.0/16", "2001:db9::/32"}},
+			},
+		},
+		{
+			name: "overlapping subnets of the same cluster",
+			overrides: []fleetnetv1beta1.TrafficManagerSubnetOverride{
+				{Cluster: "member-1", Subnets: []string{"10.1.0.0/16", "10.1.1.0/24"}},
+			},
+		},
+		{
+			name: "invalid CIDR",
+			overrides: []fleetnetv1beta1.TrafficManagerSubnetOverride{
+				{Cluster: "member-1", Subnets: []string{"10.1.0.0"}},
+			},
+			wantErr: true,
+		},
+		{
+			name: "overlapping subnets of different clusters",
+			overrides: []fleetnetv1beta1.TrafficManagerSubnetOverride{
+				{Cluster: "member-1", Subnets: []string{"10.1.0.0/16"}},
+				{Cluster: "member-2", Subnets: []string{"10.1.2.3/24"}},
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			backend := &fleetnetv1beta1.TrafficManagerBackend{
+				Spec: fleetnetv1beta1.TrafficManagerBackendSpec{SubnetOverrides: tt.overrides, Priorities: tt.priorities},
+			}
+			if err := validateEndpointOverrides(backend); (err != nil) != tt.wantErr {
+				t.Errorf("validateEndpointOverrides() got error %v, want error %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestAssignEndpointPriorities(t *testing.T) {
+	tests := []struct {
+		name       string
+		priorities []fleetnetv1beta1.TrafficManagerClusterPriority
+		clusters   []string
+		want       map[string]int64
+	}{
+		{
+			name:     "no priorities",
+			clusters: []string{"member-1", "member-2"},
+		},
+		{
+			name: "listed clusters",
+			priorities: []fleetnetv1beta1.TrafficManagerClusterPriority{
+				{Cluster: "member-1", Priority: 2},
+				{Cluster: "member-2", Priority: 1},
+				{Cluster: "member-3", Priority: 3},
+			},
+			clusters: []string{"member-1", "member-2"},
+			want:     map[string]int64{"member-1": 2, "member-2": 1},
+		},
+		{
+			name: "unlisted clusters are assigned after the listed ones in the order of their names",
+			priorities: []fleetnetv1beta1.TrafficManagerClusterPriority{
+				{Cluster: "member-2", Priority: 5},
+				{Cluster: "member-1", Priority: 10},
+			},
+			clusters: []string{"member-4", "member-1", "member-3", "member-2"},
+			want:     map[string]int64{"member-1": 10, "member-2": 5, "member-3": 11, "member-4": 12},
+		},
+		{
+			name: "unlisted clusters wrap around to the lowest unused priorities",
+			priorities: []fleetnetv1beta1.TrafficManagerClusterPriority{
+				{Cluster: "member-1", Priority: 1000},
+				{Cluster: "member-2", Priority: 1},
+			},
+			clusters: []string{"member-1", "member-2", "member-3", "member-4"},
+			want:     map[string]int64{"member-1": 1000, "member-2": 1, "member-3": 2, "member-4": 3},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			backend := &fleetnetv1beta1.TrafficManagerBackend{
+				Spec: fleetnetv1beta1.TrafficManagerBackendSpec{Priorities: tt.priorities},
+			}
+			got := assignEndpointPriorities(backend, tt.clusters)
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("assignEndpointPriorities() mismatch (-want, +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestCheckExportFreshness(t *testing.T) {
+	now := time.Now()
+	freshObservedAt := now.Add(-time.Minute)
+	staleObservedAt := now.Add(-time.Hour)
+	tests := []struct {
+		name                string
+		heartbeatObservedAt time.Time
+		maxStaleness        time.Duration
+		wantStaleAt         time.Time
+		wantErr             bool
+	}{
+		{
+			name:         "no heartbeat",
+			maxStaleness: 15 * time.Minute,
+		},
+		{
+			name:                "fresh heartbeat",
+			heartbeatObservedAt: freshObservedAt,
+			maxStaleness:        15 * time.Minute,
+			wantStaleAt:         freshObservedAt.Add(15 * time.Minute),
+		},
+		{
+			name:                "stale heartbeat",
+			heartbeatObservedAt: staleObservedAt,
+			maxStaleness:        15 * time.Minute,
+			wantErr:             true,
+		},
+		{
+			name:                "staleness check is disabled",
+			heartbeatObservedAt: staleObservedAt,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotStaleAt, err := checkExportFreshness(tt.heartbeatObservedAt, tt.maxStaleness, now)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("checkExportFreshness() got error %v, want error %v", err, tt.wantErr)
+			}
+			if !gotStaleAt.Equal(tt.wantStaleAt) {
+				t.Errorf("checkExportFreshness() = %v, want %v", gotStaleAt, tt.wantStaleAt)
+			}
+		})
+	}
+}
+
+func TestHeartbeatObservedAt(t *testing.T) {
+	now := time.Now()
+	// The clock of the member cluster is an hour ahead of the hub cluster.
+	heartbeat := now.Add(time.Hour)
+	newExport := func(lastHeartbeatTime *time.Time) *fleetnetv1alpha1.InternalServiceExport {
+		export := &fleetnetv1alpha1.InternalServiceExport{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: "member-1",
+				Name:      "app-svc",
+			},
+		}
+		if lastHeartbeatTime != nil {
+			export.Status.LastHeartbeatTime = ptr.To(metav1.NewTime(*lastHeartbeatTime))
+		}
+		return export
+	}
+	r := &Reconciler{heartbeats: &sync.Map{}}
+
+	if got := r.heartbeatObservedAt(newExport(&heartbeat), now); !got.Equal(now) {
+		t.Errorf("heartbeatObservedAt() of a new heartbeat = %v, want %v", got, now)
+	}
+	if got := r.heartbeatObservedAt(newExport(&heartbeat), now.Add(20*time.Minute)); !got.Equal(now) {
+		t.Errorf("heartbeatObservedAt() of an observed heartbeat = %v, want %v", got, now)
+	}
+	refreshedHeartbeat := heartbeat.Add(5 * time.Minute)
+	refreshedAt := now.Add(5 * time.Minute)
+	if got := r.heartbeatObservedAt(newExport(&refreshedHeartbeat), refreshedAt); !got.Equal(refreshedAt) {
+		t.Errorf("heartbeatObservedAt() of a refreshed heartbeat = %v, want %v", got, refreshedAt)
+	}
+	if got := r.heartbeatObservedAt(newExport(nil), now); !got.IsZero() {
+		t.Errorf("heartbeatObservedAt() without a heartbeat = %v, want zero", got)
+	}
+	if _, ok := r.heartbeats.Load(types.NamespacedName{Namespace: "member-1", Name: "app-svc"}); ok {
+		t.Errorf("heartbeatObservedAt() without a heartbeat kept the observation, want it removed")
+	}
+}
+
+func TestStaleExportRequeueAfter(t *testing.T) {
+	now := time.Now()
+	tests := []struct {
+		name             string
+		desiredEndpoints map[string]desiredEndpoint
+		want             time.Duration
+	}{
+		{
+			name: "no endpoints",
+		},
+		{
+			name: "endpoints never go stale",
+			desiredEndpoints: map[string]desiredEndpoint{
+				"member-1": {},
+			},
+		},
+		{
+			name: "endpoints go stale",
+			desiredEndpoints: map[string]desiredEndpoint{
+				"member-1": {},
+				"member-2": {StaleAt: now.Add(10 * time.Minute)},
+				"member-3": {StaleAt: now.Add(5 * time.Minute)},
+			},
+			want: 5*time.Minute + time.Second,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := staleExportRequeueAfter(tt.desiredEndpoints, now); got != tt.want {
+				t.Errorf("staleExportRequeueAfter() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestInternalServiceExportHeartbeatPredicate(t *testing.T) {
+	now := time.Now()
+	newExport := func(generation int64, lastHeartbeatTime time.Time) *fleetnetv1alpha1.InternalServiceExport {
+		return &fleetnetv1alpha1.InternalServiceExport{
+			ObjectMeta: metav1.ObjectMeta{
+				Generation: generation,
+			},
+			Status: fleetnetv1alpha1.InternalServiceExportStatus{
+				LastHeartbeatTime: ptr.To(metav1.NewTime(lastHeartbeatTime)),
+			},
+		}
+	}
+	withSpec := func(export *fleetnetv1alpha1.InternalServiceExport, mutate func(spec *fleetnetv1alpha1.InternalServiceExportSpec)) *fleetnetv1alpha1.InternalServiceExport {
+		mutate(&export.Spec)
+		return export
+	}
+	tests := []struct {
+		name      string
+		oldExport *fleetnetv1alpha1.InternalServiceExport
+		newExport *fleetnetv1alpha1.InternalServiceExport
+		want      bool
+	}{
+		{
+			name:      "heartbeat of a fresh export is refreshed",
+			oldExport: newExport(1, now.Add(-5*time.Minute)),
+			newExport: newExport(1, now),
+		},
+		{
+			name:      "heartbeat of a stale export is refreshed",
+			oldExport: newExport(1, now.Add(-time.Hour)),
+			newExport: newExport(1, now),
+			want:      true,
+		},
+		{
+			name:      "first heartbeat of an export is reported",
+			oldExport: &fleetnetv1alpha1.InternalServiceExport{ObjectMeta: metav1.ObjectMeta{Generation: 1}},
+			newExport: newExport(1, now),
+			want:      true,
+		},
+		{
+			name:      "spec is updated together with the heartbeat",
+			oldExport: newExport(1, now.Add(-5*time.Minute)),
+			newExport: newExport(2, now),
+			want:      true,
+		},
+		{
+			name:      "heartbeat is unchanged",
+			oldExport: newExport(1, now.Add(-5*time.Minute)),
+			newExport: newExport(1, now.Add(-5*time.Minute)),
+			want:      true,
+		},
+		{
+			name:      "public IP is updated together with the heartbeat",
+			oldExport: withSpec(newExport(1, now.Add(-5*time.Minute)), func(spec *fleetnetv1alpha1.InternalServiceExportSpec) { spec.PublicIPResourceID = ptr.To("old-pip") }),
+			newExport: withSpec(newExport(1, now), func(spec *fleetnetv1alpha1.InternalServiceExportSpec) { spec.PublicIPResourceID = ptr.To("new-pip") }),
+			want:      true,
+		},
+		{
+			name:      "public IP is set together with the heartbeat",
+			oldExport: newExport(1, now.Add(-5*time.Minute)),
+			newExport: withSpec(newExport(1, now), func(spec *fleetnetv1alpha1.InternalServiceExportSpec) { spec.PublicIPResourceID = ptr.To("new-pip") }),
+			want:      true,
+		},
+		{
+			name:      "public IP is cleared together with the heartbeat",
+			oldExport: withSpec(newExport(1, now.Add(-5*time.Minute)), func(spec *fleetnetv1alpha1.InternalServiceExportSpec) { spec.PublicIPResourceID = ptr.To("old-pip") }),
+			newExport: newExport(1, now),
+			want:      true,
+		},
+		{
+			name:      "DNS label is configured together with the heartbeat",
+			oldExport: newExport(1, now.Add(-5*time.Minute)),
+			newExport: withSpec(newExport(1, now), func(spec *fleetnetv1alpha1.InternalServiceExportSpec) { spec.IsDNSLabelConfigured = true }),
+			want:      true,
+		},
+		{
+			name:      "DNS label is cleared together with the heartbeat",
+			oldExport: withSpec(newExport(1, now.Add(-5*time.Minute)), func(spec *fleetnetv1alpha1.InternalServiceExportSpec) { spec.IsDNSLabelConfigured = true }),
+			newExport: newExport(1, now),
+			want:      true,
+		},
+		{
+			name:      "load balancer IP becomes pending together with the heartbeat",
+			oldExport: newExport(1, now.Add(-5*time.Minute)),
+			newExport: withSpec(newExport(1, now), func(spec *fleetnetv1alpha1.InternalServiceExportSpec) { spec.IsLoadBalancerPending = true }),
+			want:      true,
+		},
+		{
+			name:      "service is switched to an internal load balancer together with the heartbeat",
+			oldExport: newExport(1, now.Add(-5*time.Minute)),
+			newExport: withSpec(newExport(1, now), func(spec *fleetnetv1alpha1.InternalServiceExportSpec) { spec.IsInternalLoadBalancer = true }),
+			want:      true,
+		},
+		{
+			name:      "service type is updated together with the heartbeat",
+			oldExport: withSpec(newExport(1, now.Add(-5*time.Minute)), func(spec *fleetnetv1alpha1.InternalServiceExportSpec) { spec.Type = corev1.ServiceTypeLoadBalancer }),
+			newExport: withSpec(newExport(1, now), func(spec *fleetnetv1alpha1.InternalServiceExportSpec) { spec.Type = corev1.ServiceTypeClusterIP }),
+			want:      true,
+		},
+		{
+			name:      "weight is updated together with the heartbeat",
+			oldExport: withSpec(newExport(1, now.Add(-5*time.Minute)), func(spec *fleetnetv1alpha1.InternalServiceExportSpec) { spec.Weight = ptr.To(int64(1)) }),
+			newExport: withSpec(newExport(1, now), func(spec *fleetnetv1alpha1.InternalServiceExportSpec) { spec.Weight = ptr.To(int64(2)) }),
+			want:      true,
+		},
+		{
+			name:      "same public IP with the heartbeat of a fresh export refreshed",
+			oldExport: withSpec(newExport(1, now.Add(-5*time.Minute)), func(spec *fleetnetv1alpha1.InternalServiceExportSpec) { spec.PublicIPResourceID = ptr.To("pip") }),
+			newExport: withSpec(newExport(1, now), func(spec *fleetnetv1alpha1.InternalServiceExportSpec) { spec.PublicIPResourceID = ptr.To("pip") }),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// The old heartbeat was observed by the hub agent when it was sent.
+			r := &Reconciler{MaxExportStaleness: 15 * time.Minute, heartbeats: &sync.Map{}}
+			if tt.oldExport.Status.LastHeartbeatTime != nil {
+				r.heartbeats.Store(types.NamespacedName{Namespace: tt.oldExport.Namespace, Name: tt.oldExport.Name}, heartbeatObservation{
+					heartbeat:  tt.oldExport.Status.LastHeartbeatTime.Time,
+					observedAt: tt.oldExport.Status.LastHeartbeatTime.Time,
+				})
+			}
+			e := event.UpdateEvent{ObjectOld: tt.oldExport, ObjectNew: tt.newExport}
+			if got := r.internalServiceExportHeartbeatPredicate().Update(e); got != tt.want {
+				t.Errorf("internalServiceExportHeartbeatPredicate().Update() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+const bulkProfileName = fakeprovider.ValidProfileName + "-bulk"
+
+func newTestDesiredEndpoint(backendName, cluster string, weight int64) desiredEndpoint {
+	return desiredEndpoint{
+		Endpoint: armtrafficmanager.Endpoint{
+			Name: ptr.To(fmt.Sprintf("%s#%s#%s", backendName, fakeprovider.ServiceImportName, cluster)),
+			Type: ptr.To(string("Microsoft.Network/trafficManagerProfiles/" + armtrafficmanager.EndpointTypeAzureEndpoints)),
+			Properties: &armtrafficmanager.EndpointProperties{
+				TargetResourceID: ptr.To(fakeprovider.ValidPublicIPResourceID),
+				EndpointStatus:   ptr.To(armtrafficmanager.EndpointStatusEnabled),
+				Weight:           ptr.To(weight),
+			},
+		},
+		Cluster: fleetnetv1beta1.ClusterStatus{Cluster: cluster},
+	}
+}
+
+func newTestDesiredEndpoints(backendName string, clusters ...string) map[string]desiredEndpoint {
+	res := make(map[string]desiredEndpoint, len(clusters))
+	for _, cluster := range clusters {
+		endpoint := newTestDesiredEndpoint(backendName, cluster, 10)
+		res[*endpoint.Endpoint.Name] = endpoint
+	}
+	return res
+}
+
+func newTestProfileStore(t *testing.T, endpoints ...*armtrafficmanager.Endpoint) (*fakeprovider.ProfileStore, *armtrafficmanager.ProfilesClient) {
+	store := fakeprovider.NewProfileStore()
+	store.Set(armtrafficmanager.Profile{
+		Name:     ptr.To(bulkProfileName),
+		Location: ptr.To("global"),
+		Properties: &armtrafficmanager.ProfileProperties{
+			TrafficRoutingMethod: ptr.To(armtrafficmanager.TrafficRoutingMethodWeighted),
+			Endpoints:            endpoints,
+		},
+	})
+	profilesClient, err := store.NewProfileClient("subscription")
+	if err != nil {
+		t.Fatalf("NewProfileClient() got error %v, want no error", err)
+	}
+	return store, profilesClient
+}
+
+func storedEndpointNames(t *testing.T, store *fakeprovider.ProfileStore) []string {
+	profile, ok := store.Profile(bulkProfileName)
+	if !ok {
+		t.Fatalf("profile %s is not found", bulkProfileName)
+	}
+	var names []string
+	for _, endpoint := range profile.Properties.Endpoints {
+		names = append(names, *endpoint.Name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func acceptedEndpointNames(endpoints []fleetnetv1beta1.TrafficManagerEndpointStatus) []string {
+	var names []string
+	for _, endpoint := range endpoints {
+		names = append(names, endpoint.Name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func TestMergeTrafficManagerEndpoints(t *testing.T) {
+	current := []*armtrafficmanager.Endpoint{
+		{Name: ptr.To("other-endpoint")},
+		{
+			Name:       ptr.To(strings.ToUpper("valid-backend#test-import#member-1")),
+			Properties: &armtrafficmanager.EndpointProperties{Weight: ptr.To(int64(50))},
+		},
+		{Name: ptr.To("valid-backend#test-import#member-3")},
+	}
+	desired := map[string]desiredEndpoint{
+		"valid-backend#test-import#member-1": newTestDesiredEndpoint("valid-backend", "member-1", 10),
+		"valid-backend#test-import#member-2": newTestDesiredEndpoint("valid-backend", "member-2", 10),
+	}
+	want := []*armtrafficmanager.Endpoint{
+		{Name: ptr.To("other-endpoint")},
+		ptr.To(desired["valid-backend#test-import#member-1"].Endpoint),
+		{Name: ptr.To("valid-backend#test-import#member-3")},
+		ptr.To(desired["valid-backend#test-import#member-2"].Endpoint),
+	}
+	if diff := cmp.Diff(want, mergeTrafficManagerEndpoints(current, desired)); diff != "" {
+		t.Errorf("mergeTrafficManagerEndpoints() mismatch (-want, +got):\n%s", diff)
+	}
+}
+
+func TestBulkUpdateTrafficManagerEndpoints_ConcurrentBackends(t *testing.T) {
+	ctx := context.Background()
+	backendA := &fleetnetv1beta1.TrafficManagerBackend{ObjectMeta: metav1.ObjectMeta{Namespace: "app", Name: "backend-a"}}
+	backendB := &fleetnetv1beta1.TrafficManagerBackend{ObjectMeta: metav1.ObjectMeta{Namespace: "app", Name: "backend-b"}}
+	store, profilesClient := newTestProfileStore(t, &armtrafficmanager.Endpoint{Name: ptr.To("other-endpoint")})
+	r := &Reconciler{
+		ProfilesClient:    profilesClient,
+		ResourceGroupName: fakeprovider.DefaultResourceGroupName,
+	}
+
+	// Backend B adds its endpoint right before the first bulk update of backend A is processed.
+	backendBEndpoint := newTestDesiredEndpoint(backendB.Name, "member-1", 10).Endpoint
+	concurrentUpdates := 0
+	store.BeforeCreateOrUpdate = func(profileName string) {
+		if concurrentUpdates > 0 {
+			return
+		}
+		concurrentUpdates++
+		profile, _ := store.Profile(profileName)
+		profile.Properties.Endpoints = append(profile.Properties.Endpoints, &backendBEndpoint)
+		store.Set(profile)
+	}
+
+	desired := newTestDesiredEndpoints(backendA.Name, "member-1", "member-2", "member-3")
+	got, err := r.bulkUpdateTrafficManagerEndpoints(ctx, backendA, bulkProfileName, desired)
+	if err != nil {
+		t.Fatalf("bulkUpdateTrafficManagerEndpoints() got error %v, want no error", err)
+	}
+	wantAccepted := []string{"backend-a#test-import#member-1", "backend-a#test-import#member-2", "backend-a#test-import#member-3"}
+	if diff := cmp.Diff(wantAccepted, acceptedEndpointNames(got)); diff != "" {
+		t.Errorf("bulkUpdateTrafficManagerEndpoints() accepted endpoints mismatch (-want, +got):\n%s", diff)
+	}
+	if len(desired) != 0 {
+		t.Errorf("bulkUpdateTrafficManagerEndpoints() left %d desired endpoints, want 0", len(desired))
+	}
+	// The first update is rejected as the profile has been modified concurrently.
+	if got := store.CreateOrUpdateCount(bulkProfileName); got != 2 {
+		t.Errorf("CreateOrUpdateCount() = %d, want 2", got)
+	}
+	want := []string{"backend-a#test-import#member-1", "backend-a#test-import#member-2", "backend-a#test-import#member-3", "backend-b#test-import#member-1", "other-endpoint"}
+	if diff := cmp.Diff(want, storedEndpointNames(t, store)); diff != "" {
+		t.Errorf("profile endpoints mismatch (-want, +got):\n%s", diff)
+	}
+
+	// The bulk update of backend B keeps the endpoints of backend A.
+	desired = newTestDesiredEndpoints(backendB.Name, "member-1", "member-2")
+	desired["backend-b#test-import#member-1"].Endpoint.Properties.Weight = ptr.To(int64(20))
+	if _, err := r.bulkUpdateTrafficManagerEndpoints(ctx, backendB, bulkProfileName, desired); err != nil {
+		t.Fatalf("bulkUpdateTrafficManagerEndpoints() got error %v, want no error", err)
+	}
+	want = []string{"backend-a#test-import#member-1", "backend-a#test-import#member-2", "backend-a#test-import#member-3", "backend-b#test-import#member-1", "backend-b#test-import#member-2", "other-endpoint"}
+	if diff := cmp.Diff(want, storedEndpointNames(t, store)); diff != "" {
+		t.Errorf("profile endpoints mismatch (-want, +got):\n%s", diff)
+	}
+	profile, _ := store.Profile(bulkProfileName)
+	for _, endpoint := range profile.Properties.Endpoints {
+		if *endpoint.Name == "backend-b#test-import#member-1" && *endpoint.Properties.Weight != 20 {
+			t.Errorf("weight of endpoint %s = %d, want 20", *endpoint.Name, *endpoint.Properties.Weight)
+		}
+	}
+}
+
+func TestUpdateTrafficManagerEndpoints_BulkFallback(t *testing.T) {
+	ctx := context.Background()
+	backend := &fleetnetv1beta1.TrafficManagerBackend{ObjectMeta: metav1.ObjectMeta{Namespace: "app", Name: fakeprovider.ValidBackendName}}
+
+	// The fake endpoints server only accepts the endpoints of the valid backend.
+	originalPrefixFunc := generateAzureTrafficManagerEndpointNamePrefixFunc
+	generateAzureTrafficManagerEndpointNamePrefixFunc = func(backend *fleetnetv1beta1.TrafficManagerBackend) string {
+		return backend.Name + "#"
+	}
+	defer func() { generateAzureTrafficManagerEndpointNamePrefixFunc = originalPrefixFunc }()
+
+	endpointsClient, err := fakeprovider.NewEndpointsClient("subscription")
+	if err != nil {
+		t.Fatalf("NewEndpointsClient() got error %v, want no error", err)
+	}
+	statelessProfilesClient, err := fakeprovider.NewProfileClient("subscription")
+	if err != nil {
+		t.Fatalf("NewProfileClient() got error %v, want no error", err)
+	}
+
+	tests := []struct {
+		name string
+		// useStore uses the stateful profile store, which supports ETags.
+		useStore bool
+		// alwaysModified modifies the stored profile before every update.
+		alwaysModified bool
+		clusters       []string
+		wantAccepted   []string
+		wantBadCount   int
+		// wantBulkUpdates is the number of profile updates received by the store.
+		wantBulkUpdates int
+		// wantStored are the endpoints of the stored profile after the update.
+		wantStored []string
+	}{
+		{
+			name:            "bulk update",
+			useStore:        true,
+			clusters:        []string{"member-1", "member-2", "member-3"},
+			wantAccepted:    []string{"valid-backend#test-import#member-1", "valid-backend#test-import#member-2", "valid-backend#test-import#member-3"},
+			wantBulkUpdates: 1,
+			wantStored:      []string{"other-endpoint", "valid-backend#test-import#member-1", "valid-backend#test-import#member-2", "valid-backend#test-import#member-3"},
+		},
+		{
+			name:            "bulk update rejected with a bad request",
+			useStore:        true,
+			clusters:        []string{"member-1", "member-2", fakeprovider.CreateBadRequestErrEndpointClusterName},
+			wantAccepted:    []string{"valid-backend#test-import#member-1", "valid-backend#test-import#member-2"},
+			wantBadCount:    1,
+			wantBulkUpdates: 1,
+			wantStored:      []string{"other-endpoint"},
+		},
+		{
+			name:            "profile keeps being modified",
+			useStore:        true,
+			alwaysModified:  true,
+			clusters:        []string{"member-1", "member-2", "member-3"},
+			wantAccepted:    []string{"valid-backend#test-import#member-1", "valid-backend#test-import#member-2", "valid-backend#test-import#member-3"},
+			wantBulkUpdates: maxBulkEndpointUpdateAttempts,
+			wantStored:      []string{"other-endpoint"},
+		},
+		{
+			name:         "profile without ETag",
+			clusters:     []string{"member-1", "member-2", "member-3"},
+			wantAccepted: []string{"valid-backend#test-import#member-1", "valid-backend#test-import#member-2", "valid-backend#test-import#member-3"},
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			r := &Reconciler{
+				ProfilesClient:              statelessProfilesClient,
+				EndpointsClient:             endpointsClient,
+				ResourceGroupName:           fakeprovider.DefaultResourceGroupName,
+				BulkEndpointUpdateThreshold: 2,
+			}
+			profile := &armtrafficmanager.Profile{
+				Name:       ptr.To(fakeprovider.ValidProfileName),
+				Properties: &armtrafficmanager.ProfileProperties{},
+			}
+			var store *fakeprovider.ProfileStore
+			if tc.useStore {
+				store, r.ProfilesClient = newTestProfileStore(t, &armtrafficmanager.Endpoint{Name: ptr.To("other-endpoint")})
+				if tc.alwaysModified {
+					store.BeforeCreateOrUpdate = func(profileName string) {
+						profile, _ := store.Profile(profileName)
+						store.Set(profile)
+					}
+				}
+				stored, _ := store.Profile(bulkProfileName)
+				profile = &stored
+			}
+
+			desired := newTestDesiredEndpoints(backend.Name, tc.clusters...)
+			accepted, badErrs, err := r.updateTrafficManagerEndpointsAndUpdateStatusIfUnknown(ctx, backend, profile, desired)
+			if err != nil {
+				t.Fatalf("updateTrafficManagerEndpointsAndUpdateStatusIfUnknown() got error %v, want no error", err)
+			}
+			if diff := cmp.Diff(tc.wantAccepted, acceptedEndpointNames(accepted)); diff != "" {
+				t.Errorf("updateTrafficManagerEndpointsAndUpdateStatusIfUnknown() accepted endpoints mismatch (-want, +got):\n%s", diff)
+			}
+			if len(badErrs) != tc.wantBadCount {
+				t.Errorf("updateTrafficManagerEndpointsAndUpdateStatusIfUnknown() got %d bad endpoints, want %d", len(badErrs), tc.wantBadCount)
+			}
+			if store == nil {
+				return
+			}
+			if got := store.CreateOrUpdateCount(bulkProfileName); got != tc.wantBulkUpdates {
+				t.Errorf("CreateOrUpdateCount() = %d, want %d", got, tc.wantBulkUpdates)
+			}
+			if diff := cmp.Diff(tc.wantStored, storedEndpointNames(t, store)); diff != "" {
+				t.Errorf("profile endpoints mismatch (-want, +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestUpdateTrafficManagerEndpoints_RebalanceWeights(t *testing.T) {
+	ctx := context.Background()
+
+	// The fake endpoints server only accepts the endpoints of the valid backend.
+	originalPrefixFunc := generateAzureTrafficManagerEndpointNamePrefixFunc
+	generateAzureTrafficManagerEndpointNamePrefixFunc = func(backend *fleetnetv1beta1.TrafficManagerBackend) string {
+		return backend.Name + "#"
+	}
+	defer func() { generateAzureTrafficManagerEndpointNamePrefixFunc = originalPrefixFunc }()
+
+	endpointsClient, err := fakeprovider.NewEndpointsClient("subscription")
+	if err != nil {
+		t.Fatalf("NewEndpointsClient() got error %v, want no error", err)
+	}
+
+	tests := []struct {
+		name     string
+		weight   int64
+		clusters []string
+		// existing are the endpoints of the profile before the update, keyed by the cluster, with their weights.
+		existing     map[string]int64
+		wantWeights  map[string]int64
+		wantBadCount int
+	}{
+		{
+			name:        "all endpoints are accepted",
+			weight:      12,
+			clusters:    []string{"member-1", "member-2", "member-3"},
+			wantWeights: map[string]int64{"member-1": 4, "member-2": 4, "member-3": 4},
+		},
+		{
+			name:         "one endpoint fails in the middle of the set",
+			weight:       12,
+			clusters:     []string{"member-1", fakeprovider.CreateBadRequestErrEndpointClusterName, "member-3"},
+			wantWeights:  map[string]int64{"member-1": 6, "member-3": 6},
+			wantBadCount: 1,
+		},
+		{
+			name:         "one endpoint fails and the weight cannot be split evenly",
+			weight:       11,
+			clusters:     []string{"member-1", "member-2", "member-3", fakeprovider.CreateBadRequestErrEndpointClusterName},
+			wantWeights:  map[string]int64{"member-1": 4, "member-2": 4, "member-3": 4},
+			wantBadCount: 1,
+		},
+		{
+			name:     "one endpoint fails while the existing endpoint needs no update",
+			weight:   12,
+			clusters: []string{"member-1", fakeprovider.CreateBadRequestErrEndpointClusterName},
+			// The existing endpoint has the weight split across all the desired endpoints.
+			existing:     map[string]int64{"member-1": 6},
+			wantWeights:  map[string]int64{"member-1": 12},
+			wantBadCount: 1,
+		},
+		{
+			name:         "all endpoints fail",
+			weight:       12,
+			clusters:     []string{fakeprovider.CreateBadRequestErrEndpointClusterName},
+			wantWeights:  map[string]int64{},
+			wantBadCount: 1,
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			r := &Reconciler{
+				EndpointsClient:   endpointsClient,
+				ResourceGroupName: fakeprovider.DefaultResourceGroupName,
+			}
+			backend := &fleetnetv1beta1.TrafficManagerBackend{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "app", Name: fakeprovider.ValidBackendName},
+				Spec:       fleetnetv1beta1.TrafficManagerBackendSpec{Weight: ptr.To(tc.weight)},
+			}
+			desired := newTestDesiredEndpoints(backend.Name, tc.clusters...)
+			desiredWeight := splitWeight(tc.weight, len(desired))
+			for _, dp := range desired {
+				dp.Endpoint.Properties.Weight = ptr.To(desiredWeight)
+			}
+			profile := &armtrafficmanager.Profile{
+				Name:       ptr.To(fakeprovider.ValidProfileName),
+				Properties: &armtrafficmanager.ProfileProperties{},
+			}
+			for cluster, weight := range tc.existing {
+				endpoint := newTestDesiredEndpoint(backend.Name, cluster, weight).Endpoint
+				profile.Properties.Endpoints = append(profile.Properties.Endpoints, &endpoint)
+			}
+
+			accepted, badErrs, err := r.updateTrafficManagerEndpointsAndUpdateStatusIfUnknown(ctx, backend, profile, desired)
+			if err != nil {
+				t.Fatalf("updateTrafficManagerEndpointsAndUpdateStatusIfUnknown() got error %v, want no error", err)
+			}
+			if len(badErrs) != tc.wantBadCount {
+				t.Errorf("updateTrafficManagerEndpointsAndUpdateStatusIfUnknown() got %d bad endpoints, want %d", len(badErrs), tc.wantBadCount)
+			}
+			gotWeights := make(map[string]int64, len(accepted))
+			var sum int64
+			for _, endpoint := range accepted {
+				gotWeights[endpoint.From.Cluster] = ptr.Deref(endpoint.Weight, 0)
+				sum += ptr.Deref(endpoint.Weight, 0)
+			}
+			if diff := cmp.Diff(tc.wantWeights, gotWeights); diff != "" {
+				t.Errorf("updateTrafficManagerEndpointsAndUpdateStatusIfUnknown() weights mismatch (-want, +got):\n%s", diff)
+			}
+			if len(accepted) > 0 && (sum < tc.weight || sum >= tc.weight+int64(len(accepted))) {
+				t.Errorf("updateTrafficManagerEndpointsAndUpdateStatusIfUnknown() sum of the weights = %d, want %d within rounding", sum, tc.weight)
+			}
+		})
+	}
+}
+
+// TestUpdateTrafficManagerEndpoints_StableWeights reconciles the endpoints twice, with one of them rejected by Azure,
+// and verifies that the rebalanced weights of the accepted endpoints are not updated again.
+func TestUpdateTrafficManagerEndpoints_StableWeights(t *testing.T) {
+	ctx := context.Background()
+
+	// The fake endpoints server only accepts the endpoints of the valid backend.
+	originalPrefixFunc := generateAzureTrafficManagerEndpointNamePrefixFunc
+	generateAzureTrafficManagerEndpointNamePrefixFunc = func(backend *fleetnetv1beta1.TrafficManagerBackend) string {
+		return backend.Name + "#"
+	}
+	defer func() { generateAzureTrafficManagerEndpointNamePrefixFunc = originalPrefixFunc }()
+
+	updates := map[string]int{} // key is the endpoint name
+	fakeServer := atmfake.EndpointsServer{
+		CreateOrUpdate: func(ctx context.Context, resourceGroupName string, profileName string, endpointType armtrafficmanager.EndpointType, endpointName string, endpoint armtrafficmanager.Endpoint, options *armtrafficmanager.EndpointsClientCreateOrUpdateOptions) (azcorefake.Responder[armtrafficmanager.EndpointsClientCreateOrUpdateResponse], azcorefake.ErrorResponder) {
+			updates[endpointName]++
+			return fakeprovider.EndpointCreateOrUpdate(ctx, resourceGroupName, profileName, endpointType, endpointName, endpoint, options)
+		},
+	}
+	clientFactory, err := armtrafficmanager.NewClientFactory("subscription", &azcorefake.TokenCredential{},
+		&arm.ClientOptions{ClientOptions: azcore.ClientOptions{Transport: atmfake.NewEndpointsServerTransport(&fakeServer)}})
+	if err != nil {
+		t.Fatalf("NewClientFactory() got error %v, want no error", err)
+	}
+	r := &Reconciler{
+		EndpointsClient:   clientFactory.NewEndpointsClient(),
+		ResourceGroupName: fakeprovider.DefaultResourceGroupName,
+	}
+	backend := &fleetnetv1beta1.TrafficManagerBackend{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "app", Name: fakeprovider.ValidBackendName},
+		Spec:       fleetnetv1beta1.TrafficManagerBackendSpec{Weight: ptr.To(int64(12))},
+	}
+	clusters := []string{"member-1", fakeprovider.CreateBadRequestErrEndpointClusterName, "member-3"}
+	wantWeights := map[string]int64{"member-1": 6, "member-3": 6}
+	profile := &armtrafficmanager.Profile{
+		Name:       ptr.To(fakeprovider.ValidProfileName),
+		Properties: &armtrafficmanager.ProfileProperties{},
+	}
+
+	for i := 1; i <= 2; i++ {
+		// The desired weight is split across all the desired endpoints, as validateExportedServiceForServiceImport does.
+		desired := newTestDesiredEndpoints(backend.Name, clusters...)
+		for _, dp := range desired {
+			dp.Endpoint.Properties.Weight = ptr.To(splitWeight(*backend.Spec.Weight, len(desired)))
+		}
+		for name := range updates {
+			delete(updates, name)
+		}
+
+		accepted, badErrs, err := r.updateTrafficManagerEndpointsAndUpdateStatusIfUnknown(ctx, backend, profile, desired)
+		if err != nil {
+			t.Fatalf("reconciliation %d: updateTrafficManagerEndpointsAndUpdateStatusIfUnknown() got error %v, want no error", i, err)
+		}
+		if len(badErrs) != 1 {
+			t.Errorf("reconciliation %d: updateTrafficManagerEndpointsAndUpdateStatusIfUnknown() got %d bad endpoints, want 1", i, len(badErrs))
+		}
+		gotWeights := make(map[string]int64, len(accepted))
+		for _, endpoint := range accepted {
+			gotWeights[endpoint.From.Cluster] = ptr.Deref(endpoint.Weight, 0)
+		}
+		if diff := cmp.Diff(wantWeights, gotWeights); diff != "" {
+			t.Errorf("reconciliation %d: updateTrafficManagerEndpointsAndUpdateStatusIfUnknown() weights mismatch (-want, +got):\n%s", i, diff)
+		}
+		if i == 2 {
+			for name, count := range updates {
+				if name != fakeprovider.CreateBadRequestErrEndpointName {
+					t.Errorf("reconciliation %d: endpoint %q got %d updates, want none", i, name, count)
+				}
+			}
+		}
+
+		// The profile of the next reconciliation has the accepted endpoints with their weights.
+		profile.Properties.Endpoints = nil
+		for _, endpoint := range accepted {
+			existing := newTestDesiredEndpoint(backend.Name, endpoint.From.Cluster, ptr.Deref(endpoint.Weight, 0)).Endpoint
+			profile.Properties.Endpoints = append(profile.Properties.Endpoints, &existing)
+		}
+	}
+}
+
+func TestWithCurrentAzureClients(t *testing.T) {
+	filePath := filepath.Join(t.TempDir(), "azure.json")
+	writeCloudConfig := func(subscriptionID, resourceGroup string) {
+		contents := fmt.Sprintf(`{"cloud": "AzurePublicCloud", "subscriptionId": %q, "useManagedIdentityExtension": true, "location": "westus", "resourceGroup": %q}`, subscriptionID, resourceGroup)
+		if err := os.WriteFile(filePath, []byte(contents), 0600); err != nil {
+			t.Fatalf("failed to write the cloud config file: %v", err)
+		}
+	}
+	writeCloudConfig("sub-1", "rg-1")
+	reloader := cloudconfig.NewReloader(filePath, "test", time.Minute, func(cloudConfig *azure.CloudConfig) (cloudconfig.TrafficManagerClients, error) {
+		profilesClient, err := fakeprovider.NewProfileClient(cloudConfig.SubscriptionID)
+		if err != nil {
+			return cloudconfig.TrafficManagerClients{}, err
+		}
+		endpointsClient, err := fakeprovider.NewEndpointsClient(cloudConfig.SubscriptionID)
+		if err != nil {
+			return cloudconfig.TrafficManagerClients{}, err
+		}
+		return cloudconfig.TrafficManagerClients{
+			ProfilesClient:    profilesClient,
+			EndpointsClient:   endpointsClient,
+			ResourceGroupName: cloudConfig.ResourceGroup,
+		}, nil
+	})
+
+	r := &Reconciler{ResourceGroupName: "static-rg", AzureClients: reloader}
+	inFlight, err := r.withCurrentAzureClients()
+	if err != nil {
+		t.Fatalf("withCurrentAzureClients() got error %v, want no error", err)
+	}
+	if inFlight.ResourceGroupName != "rg-1" || inFlight.ProfilesClient == nil || inFlight.EndpointsClient == nil {
+		t.Fatalf("withCurrentAzureClients() = %+v, want the clients of resource group rg-1", inFlight)
+	}
+	if r.ResourceGroupName != "static-rg" {
+		t.Errorf("withCurrentAzureClients() changed the resource group of the reconciler to %q, want static-rg", r.ResourceGroupName)
+	}
+
+	writeCloudConfig("sub-2", "rg-2")
+	if _, err := reloader.Reload(); err != nil {
+		t.Fatalf("Reload() got error %v, want no error", err)
+	}
+	next, err := r.withCurrentAzureClients()
+	if err != nil {
+		t.Fatalf("withCurrentAzureClients() got error %v, want no error", err)
+	}
+	if next.ResourceGroupName != "rg-2" || next.ProfilesClient == inFlight.ProfilesClient || next.EndpointsClient == inFlight.EndpointsClient {
+		t.Errorf("withCurrentAzureClients() after the reload = %+v, want the new clients of resource group rg-2", next)
+	}
+	// The in-flight reconciliation keeps using the clients it has started with.
+	if inFlight.ResourceGroupName != "rg-1" {
+		t.Errorf("the in-flight reconciler uses resource group %q, want rg-1", inFlight.ResourceGroupName)
+	}
+
+	static := &Reconciler{ResourceGroupName: "static-rg"}
+	if got, err := static.withCurrentAzureClients(); err != nil || got != static {
+		t.Errorf("withCurrentAzureClients() without the reloader = %p, %v, want the reconciler itself", got, err)
+	}
+}
+
+// countingTransport counts the requests sent to Azure and fails all of them.
+type countingTransport struct {
+	mu       sync.Mutex
+	requests int
+}
+
+func (c *countingTransport) Do(req *http.Request) (*http.Response, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.requests++
+	return nil, fmt.Errorf("unexpected request %s %s", req.Method, req.URL)
+}
+
+func (c *countingTransport) count() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.requests
+}
+
+// TestHandleUpdate_InvalidReferences tests that a backend with invalid references is rejected before any API server
+// or Azure call.
+func TestHandleUpdate_InvalidReferences(t *testing.T) {
+	tests := []struct {
+		name        string
+		profileName string
+		backendName string
+		wantMessage string
+	}{
+		{
+			name:        "empty profile name",
+			backendName: fakeprovider.ServiceImportName,
+			wantMessage: "Invalid trafficManagerBackend spec: spec.profile.name must not be empty",
+		},
+		{
+			name:        "empty backend name",
+			profileName: fakeprovider.ValidProfileName,
+			wantMessage: "Invalid trafficManagerBackend spec: spec.backend.name must not be empty",
+		},
+		{
+			name:        "invalid profile name",
+			profileName: "Invalid_Profile",
+			backendName: fakeprovider.ServiceImportName,
+			wantMessage: `Invalid trafficManagerBackend spec: spec.profile.name "Invalid_Profile" is not a valid name`,
+		},
+		{
+			name:        "invalid backend name",
+			profileName: fakeprovider.ValidProfileName,
+			backendName: "service/import",
+			wantMessage: `Invalid trafficManagerBackend spec: spec.backend.name "service/import" is not a valid name`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			scheme := runtime.NewScheme()
+			if err := fleetnetv1beta1.AddToScheme(scheme); err != nil {
+				t.Fatalf("AddToScheme() got error %v, want no error", err)
+			}
+			backend := &fleetnetv1beta1.TrafficManagerBackend{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace:  "app",
+					Name:       fakeprovider.ValidBackendName,
+					Finalizers: []string{objectmeta.TrafficManagerBackendFinalizer},
+				},
+				Spec: fleetnetv1beta1.TrafficManagerBackendSpec{
+					Profile: fleetnetv1beta1.TrafficManagerProfileRef{Name: tt.profileName},
+					Backend: fleetnetv1beta1.TrafficManagerBackendRef{Name: tt.backendName},
+					Weight:  ptr.To(int64(1)),
+				},
+			}
+			var reads int
+			fakeClient := fake.NewClientBuilder().
+				WithScheme(scheme).
+				WithObjects(backend).
+				WithStatusSubresource(backend).
+				WithInterceptorFuncs(interceptor.Funcs{
+					Get: func(ctx context.Context, c client.WithWatch, key client.ObjectKey, obj client.Object, opts ...client.GetOption) error {
+						reads++
+						return c.Get(ctx, key, obj, opts...)
+					},
+					List: func(ctx context.Context, c client.WithWatch, list client.ObjectList, opts ...client.ListOption) error {
+						reads++
+						return c.List(ctx, list, opts...)
+					},
+				}).
+				Build()
+			transport := &countingTransport{}
+			clientFactory, err := armtrafficmanager.NewClientFactory("subscription", &azcorefake.TokenCredential{},
+				&arm.ClientOptions{ClientOptions: azcore.ClientOptions{Transport: transport}})
+			if err != nil {
+				t.Fatalf("NewClientFactory() got error %v, want no error", err)
+			}
+			r := &Reconciler{
+				Client:            fakeClient,
+				ProfilesClient:    clientFactory.NewProfilesClient(),
+				EndpointsClient:   clientFactory.NewEndpointsClient(),
+				ResourceGroupName: fakeprovider.DefaultResourceGroupName,
+			}
+
+			got, err := r.handleUpdate(ctx, backend)
+			if err != nil {
+				t.Fatalf("handleUpdate() got error %v, want no error", err)
+			}
+			if got != (ctrl.Result{}) {
+				t.Errorf("handleUpdate() = %+v, want %+v", got, ctrl.Result{})
+			}
+			cond := meta.FindStatusCondition(backend.Status.Conditions, string(fleetnetv1beta1.TrafficManagerBackendConditionAccepted))
+			if cond == nil || cond.Status != metav1.ConditionFalse || cond.Reason != string(fleetnetv1beta1.TrafficManagerBackendReasonInvalid) {
+				t.Fatalf("handleUpdate() Accepted condition = %+v, want False with reason %q", cond, fleetnetv1beta1.TrafficManagerBackendReasonInvalid)
+			}
+			if !strings.HasPrefix(cond.Message, tt.wantMessage) {
+				t.Errorf("handleUpdate() Accepted condition message = %q, want prefix %q", cond.Message, tt.wantMessage)
+			}
+
+			// The backend with invalid references is deleted without calling Azure either.
+			if _, err := r.handleDelete(ctx, backend); err != nil {
+				t.Fatalf("handleDelete() got error %v, want no error", err)
+			}
+			if reads != 0 {
+				t.Errorf("handleUpdate() and handleDelete() read %d objects from the API server, want 0", reads)
+			}
+			if got := transport.count(); got != 0 {
+				t.Errorf("handleUpdate() and handleDelete() sent %d requests to Azure, want 0", got)
+			}
+		})
+	}
+}
+
+func TestFindOlderDuplicateBackend(t *testing.T) {
+	now := time.Now().Round(time.Second)
+	backendForTest := func(name, profileName, serviceImportName string, createdAt time.Time) *fleetnetv1beta1.TrafficManagerBackend {
+		return &fleetnetv1beta1.TrafficManagerBackend{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace:         "app",
+				Name:              name,
+				CreationTimestamp: metav1.NewTime(createdAt),
+			},
+			Spec: fleetnetv1beta1.TrafficManagerBackendSpec{
+				Profile: fleetnetv1beta1.TrafficManagerProfileRef{Name: profileName},
+				Backend: fleetnetv1beta1.TrafficManagerBackendRef{Name: serviceImportName},
+				Weight:  ptr.To(int64(1)),
+			},
+		}
+	}
+	tests := []struct {
+		name    string
+		backend *fleetnetv1beta1.TrafficManagerBackend
+		others  []*fleetnetv1beta1.TrafficManagerBackend
+		want    string
+	}{
+		{
+			name:    "no other backends",
+			backend: backendForTest("backend", "profile", "svc", now),
+		},
+		{
+			name:    "other backends refer to different profiles or serviceImports",
+			backend: backendForTest("backend", "profile", "svc", now),
+			others: []*fleetnetv1beta1.TrafficManagerBackend{
+				backendForTest("other-profile", "other", "svc", now.Add(-time.Minute)),
+				backendForTest("other-svc", "profile", "other", now.Add(-time.Minute)),
+			},
+		},
+		{
+			name:    "the duplicate is newer",
+			backend: backendForTest("backend", "profile", "svc", now),
+			others: []*fleetnetv1beta1.TrafficManagerBackend{
+				backendForTest("newer", "profile", "svc", now.Add(time.Minute)),
+			},
+		},
+		{
+			name:    "the oldest of the older duplicates is returned",
+			backend: backendForTest("backend", "profile", "svc", now),
+			others: []*fleetnetv1beta1.TrafficManagerBackend{
+				backendForTest("older", "profile", "svc", now.Add(-time.Minute)),
+				backendForTest("oldest", "profile", "svc", now.Add(-time.Hour)),
+				backendForTest("newer", "profile", "svc", now.Add(time.Minute)),
+			},
+			want: "oldest",
+		},
+		{
+			name:    "the name breaks the tie of the creation timestamps",
+			backend: backendForTest("b-backend", "profile", "svc", now),
+			others: []*fleetnetv1beta1.TrafficManagerBackend{
+				backendForTest("a-backend", "profile", "svc", now),
+				backendForTest("c-backend", "profile", "svc", now),
+			},
+			want: "a-backend",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			scheme := runtime.NewScheme()
+			if err := fleetnetv1beta1.AddToScheme(scheme); err != nil {
+				t.Fatalf("AddToScheme() got error %v, want no error", err)
+			}
+			objs := []client.Object{tt.backend}
+			for _, other := range tt.others {
+				objs = append(objs, other)
+			}
+			r := &Reconciler{Client: fake.NewClientBuilder().WithScheme(scheme).WithObjects(objs...).Build()}
+
+			got, err := r.findOlderDuplicateBackend(context.Background(), tt.backend)
+			if err != nil {
+				t.Fatalf("findOlderDuplicateBackend() got error %v, want no error", err)
+			}
+			var gotName string
+			if got != nil {
+				gotName = got.Name
+			}
+			if gotName != tt.want {
+				t.Errorf("findOlderDuplicateBackend() = %q, want %q", gotName, tt.want)
+			}
+		})
+	}
+}
+
+func TestRecordEndpointWeights(t *testing.T) {
+	backend := &fleetnetv1beta1.TrafficManagerBackend{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "weight-metric",
+			Name:      "backend",
+		},
+	}
+	endpointStatus := func(cluster string, weight int64) fleetnetv1beta1.TrafficManagerEndpointStatus {
+		return fleetnetv1beta1.TrafficManagerEndpointStatus{
+			From:   &fleetnetv1beta1.FromCluster{ClusterStatus: fleetnetv1beta1.ClusterStatus{Cluster: cluster}},
+			Weight: ptr.To(weight),
+		}
+	}
+	r := &Reconciler{weightClusters: &sync.Map{}}
+
+	r.recordEndpointWeights(backend, []fleetnetv1beta1.TrafficManagerEndpointStatus{endpointStatus("member-1", 30), endpointStatus("member-2", 70)})
+	for cluster, want := range map[string]float64{"member-1": 30, "member-2": 70} {
+		if got := testutil.ToFloat64(trafficManagerEndpointWeight.WithLabelValues(backend.Namespace, backend.Name, cluster)); got != want {
+			t.Errorf("trafficManagerEndpointWeight(%s) = %v, want %v", cluster, got, want)
+		}
+	}
+
+	// member-2 no longer has an endpoint.
+	r.recordEndpointWeights(backend, []fleetnetv1beta1.TrafficManagerEndpointStatus{endpointStatus("member-1", 100)})
+	if got := testutil.ToFloat64(trafficManagerEndpointWeight.WithLabelValues(backend.Namespace, backend.Name, "member-1")); got != 100 {
+		t.Errorf("trafficManagerEndpointWeight(member-1) = %v, want 100", got)
+	}
+	if trafficManagerEndpointWeight.DeleteLabelValues(backend.Namespace, backend.Name, "member-2") {
+		t.Errorf("trafficManagerEndpointWeight(member-2) series is not deleted")
+	}
+
+	// The backend is deleted.
+	r.recordEndpointWeights(backend, nil)
+	if trafficManagerEndpointWeight.DeleteLabelValues(backend.Namespace, backend.Name, "member-1") {
+		t.Errorf("trafficManagerEndpointWeight(member-1) series is not deleted")
+	}
+	if _, ok := r.weightClusters.Load(types.NamespacedName{Namespace: backend.Namespace, Name: backend.Name}); ok {
+		t.Errorf("recordEndpointWeights() still tracks the deleted backend")
+	}
+}
+
+func TestValidateExportedServiceForServiceImport_LocalExternalTrafficPolicy(t *testing.T) {
+	newExport := func(cluster string, policy corev1.ServiceExternalTrafficPolicy) *fleetnetv1alpha1.InternalServiceExport {
+		return &fleetnetv1alpha1.InternalServiceExport{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: cluster,
+				Name:      "app-app",
+			},
+			Spec: fleetnetv1alpha1.InternalServiceExportSpec{
+				ServiceReference: fleetnetv1alpha1.ExportedObjectReference{
+					ClusterID:      cluster,
+					Namespace:      "app",
+					Name:           "app",
+					NamespacedName: "app/app",
+				},
+				Type:                  corev1.ServiceTypeLoadBalancer,
+				IsDNSLabelConfigured:  true,
+				PublicIPResourceID:    ptr.To(fmt.Sprintf("/subscriptions/sub/resourceGroups/rg/providers/Microsoft.Network/publicIPAddresses/%s", cluster)),
+				ExternalTrafficPolicy: policy,
+				HealthCheckNodePort:   30123,
+			},
+		}
+	}
+	tests := []struct {
+		name                string
+		reject              bool
+		wantClusters        []string
+		wantInvalidClusters []string
+	}{
+		{
+			name:                "local policy is reported",
+			wantClusters:        []string{"member-1", "member-2"},
+			wantInvalidClusters: []string{"member-2"},
+		},
+		{
+			name:                "local policy is rejected",
+			reject:              true,
+			wantClusters:        []string{"member-1"},
+			wantInvalidClusters: []string{"member-2"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			scheme := runtime.NewScheme()
+			if err := fleetnetv1alpha1.AddToScheme(scheme); err != nil {
+				t.Fatalf("AddToScheme() got error %v, want no error", err)
+			}
+			if err := fleetnetv1beta1.AddToScheme(scheme); err != nil {
+				t.Fatalf("AddToScheme() got error %v, want no error", err)
+			}
+			fakeClient := fake.NewClientBuilder().
+				WithScheme(scheme).
+				WithObjects(
+					newExport("member-1", corev1.ServiceExternalTrafficPolicyCluster),
+					newExport("member-2", corev1.ServiceExternalTrafficPolicyLocal),
+				).
+				WithIndex(&fleetnetv1alpha1.InternalServiceExport{}, exportedServiceFieldNamespacedName, func(o client.Object) []string {
+					return []string{o.(*fleetnetv1alpha1.InternalServiceExport).Spec.ServiceReference.NamespacedName}
+				}).
+				Build()
+			r := &Reconciler{Client: fakeClient, RejectLocalExternalTrafficPolicy: tt.reject}
+			backend := &fleetnetv1beta1.TrafficManagerBackend{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: "app",
+					Name:      fakeprovider.ValidBackendName,
+				},
+				Spec: fleetnetv1beta1.TrafficManagerBackendSpec{
+					Profile: fleetnetv1beta1.TrafficManagerProfileRef{Name: fakeprovider.ValidProfileName},
+					Backend: fleetnetv1beta1.TrafficManagerBackendRef{Name: "app"},
+					Weight:  ptr.To(int64(10)),
+				},
+			}
+			serviceImport := &fleetnetv1alpha1.ServiceImport{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: "app",
+					Name:      "app",
+				},
+				Status: fleetnetv1alpha1.ServiceImportStatus{
+					Clusters: []fleetnetv1alpha1.ClusterStatus{{Cluster: "member-1"}, {Cluster: "member-2"}},
+				},
+			}
+
+			desiredEndpoints, invalidServices, err := r.validateExportedServiceForServiceImport(context.Background(), backend, serviceImport)
+			if err != nil {
+				t.Fatalf("validateExportedServiceForServiceImport() got error %v, want no error", err)
+			}
+			var gotClusters []string
+			for _, dp := range desiredEndpoints {
+				gotClusters = append(gotClusters, dp.Cluster.Cluster)
+			}
+			sort.Strings(gotClusters)
+			if diff := cmp.Diff(tt.wantClusters, gotClusters); diff != "" {
+				t.Errorf("validateExportedServiceForServiceImport() desired endpoint clusters mismatch (-want, +got):\n%s", diff)
+			}
+			var gotInvalidClusters []string
+			for cluster, err := range invalidServices {
+				gotInvalidClusters = append(gotInvalidClusters, cluster)
+				if !strings.HasPrefix(err.Error(), localExternalTrafficPolicyReason) {
+					t.Errorf("validateExportedServiceForServiceImport() error of cluster %s = %v, want prefix %s", cluster, err, localExternalTrafficPolicyReason)
+				}
+			}
+			if diff := cmp.Diff(tt.wantInvalidClusters, gotInvalidClusters); diff != "" {
+				t.Errorf("validateExportedServiceForServiceImport() invalid service clusters mismatch (-want, +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+// TestUpdateTrafficManagerEndpoints_ReadOnly tests that no Azure Traffic Manager endpoint is created, updated or
+// deleted in the read-only mode, and that the skipped requests are reported instead.
+func TestUpdateTrafficManagerEndpoints_ReadOnly(t *testing.T) {
+	ctx := context.Background()
+
+	// The fake endpoints server only accepts the endpoints of the valid backend.
+	originalPrefixFunc := generateAzureTrafficManagerEndpointNamePrefixFunc
+	generateAzureTrafficManagerEndpointNamePrefixFunc = func(backend *fleetnetv1beta1.TrafficManagerBackend) string {
+		return backend.Name + "#"
+	}
+	defer func() { generateAzureTrafficManagerEndpointNamePrefixFunc = originalPrefixFunc }()
+
+	tests := []struct {
+		name                        string
+		clusters                    []string
+		bulkEndpointUpdateThreshold int
+		// existing are the endpoints of the profile before the update, keyed by the cluster, with their weights.
+		existing     map[string]int64
+		wantAccepted map[string]int64
+		wantSkipped  int
+	}{
+		{
+			name:         "new endpoints",
+			clusters:     []string{"member-1", "member-2"},
+			wantAccepted: map[string]int64{},
+			wantSkipped:  2,
+		},
+		{
+			name:                        "new endpoints above the bulk update threshold",
+			clusters:                    []string{"member-1", "member-2", "member-3"},
+			bulkEndpointUpdateThreshold: 1,
+			wantAccepted:                map[string]int64{},
+			wantSkipped:                 3,
+		},
+		{
+			name:         "stale endpoint",
+			clusters:     []string{"member-1"},
+			existing:     map[string]int64{"member-1": 12, "member-2": 6},
+			wantAccepted: map[string]int64{"member-1": 12},
+			wantSkipped:  1,
+		},
+		{
+			name:     "new endpoint and weight to rebalance",
+			clusters: []string{"member-1", "member-2"},
+			existing: map[string]int64{"member-1": 6},
+			// The weight of the existing endpoint is not rebalanced.
+			wantAccepted: map[string]int64{"member-1": 6},
+			wantSkipped:  2,
+		},
+		{
+			name:         "up-to-date endpoint",
+			clusters:     []string{"member-1"},
+			existing:     map[string]int64{"member-1": 12},
+			wantAccepted: map[string]int64{"member-1": 12},
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			mutations := fakeprovider.NewMutationRecorder()
+			profilesClient, err := mutations.NewProfileClient("subscription")
+			if err != nil {
+				t.Fatalf("NewProfileClient() got error %v, want no error", err)
+			}
+			endpointsClient, err := mutations.NewEndpointsClient("subscription")
+			if err != nil {
+				t.Fatalf("NewEndpointsClient() got error %v, want no error", err)
+			}
+			r := &Reconciler{
+				ProfilesClient:              profilesClient,
+				EndpointsClient:             endpointsClient,
+				ResourceGroupName:           fakeprovider.DefaultResourceGroupName,
+				BulkEndpointUpdateThreshold: tc.bulkEndpointUpdateThreshold,
+				ReadOnly:                    true,
+			}
+			backend := &fleetnetv1beta1.TrafficManagerBackend{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "app", Name: fakeprovider.ValidBackendName},
+				Spec:       fleetnetv1beta1.TrafficManagerBackendSpec{Weight: ptr.To(int64(12))},
+			}
+			desired := newTestDesiredEndpoints(backend.Name, tc.clusters...)
+			desiredWeight := splitWeight(*backend.Spec.Weight, len(desired))
+			for _, dp := range desired {
+				dp.Endpoint.Properties.Weight = ptr.To(desiredWeight)
+			}
+			profile := &armtrafficmanager.Profile{
+				Name:       ptr.To(fakeprovider.ValidProfileName),
+				Properties: &armtrafficmanager.ProfileProperties{},
+			}
+			for cluster, weight := range tc.existing {
+				endpoint := newTestDesiredEndpoint(backend.Name, cluster, weight).Endpoint
+				profile.Properties.Endpoints = append(profile.Properties.Endpoints, &endpoint)
+			}
+
+			accepted, skipped, err := r.updateTrafficManagerEndpointsAndUpdateStatusIfUnknown(ctx, backend, profile, desired)
+			if err != nil {
+				t.Fatalf("updateTrafficManagerEndpointsAndUpdateStatusIfUnknown() got error %v, want no error", err)
+			}
+			if len(skipped) != tc.wantSkipped {
+				t.Errorf("updateTrafficManagerEndpointsAndUpdateStatusIfUnknown() got %d skipped requests %v, want %d", len(skipped), skipped, tc.wantSkipped)
+			}
+			for _, err := range skipped {
+				if !errors.Is(err, errReadOnly) {
+					t.Errorf("updateTrafficManagerEndpointsAndUpdateStatusIfUnknown() got bad endpoint error %v, want a read-only error", err)
+				}
+			}
+			gotAccepted := make(map[string]int64, len(accepted))
+			for _, endpoint := range accepted {
+				gotAccepted[endpoint.From.Cluster] = ptr.Deref(endpoint.Weight, 0)
+			}
+			if diff := cmp.Diff(tc.wantAccepted, gotAccepted); diff != "" {
+				t.Errorf("updateTrafficManagerEndpointsAndUpdateStatusIfUnknown() accepted endpoints mismatch (-want, +got):\n%s", diff)
+			}
+			if got := mutations.Requests(); len(got) != 0 {
+				t.Errorf("updateTrafficManagerEndpointsAndUpdateStatusIfUnknown() sent mutating requests %v, want none", got)
+			}
+		})
+	}
+}
+
+// TestHandleDelete_ReadOnly tests that the finalizer of a deleted backend is kept in the read-only mode while the
+// backend still has Azure Traffic Manager endpoints.
+func TestHandleDelete_ReadOnly(t *testing.T) {
+	originalPrefixFunc := generateAzureTrafficManagerEndpointNamePrefixFunc
+	generateAzureTrafficManagerEndpointNamePrefixFunc = func(backend *fleetnetv1beta1.TrafficManagerBackend) string {
+		return backend.Name + "#"
+	}
+	defer func() { generateAzureTrafficManagerEndpointNamePrefixFunc = originalPrefixFunc }()
+	originalProfileNameFunc := generateAzureTrafficManagerProfileNameFunc
+	generateAzureTrafficManagerProfileNameFunc = func(profile *fleetnetv1beta1.TrafficManagerProfile) string {
+		return profile.Name
+	}
+	defer func() { generateAzureTrafficManagerProfileNameFunc = originalProfileNameFunc }()
+
+	tests := []struct {
+		name          string
+		profileName   string
+		wantFinalizer bool
+	}{
+		{
+			name:          "profile with endpoints of the backend",
+			profileName:   fakeprovider.ValidProfileWithEndpointsName,
+			wantFinalizer: true,
+		},
+		{
+			name:        "profile without endpoints of the backend",
+			profileName: fakeprovider.ValidProfileName,
+		},
+		{
+			name:        "profile not found in Azure",
+			profileName: fakeprovider.NewProfileName,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			scheme := runtime.NewScheme()
+			if err := fleetnetv1beta1.AddToScheme(scheme); err != nil {
+				t.Fatalf("AddToScheme() got error %v, want no error", err)
+			}
+			profile := &fleetnetv1beta1.TrafficManagerProfile{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "app", Name: tt.profileName},
+			}
+			backend := &fleetnetv1beta1.TrafficManagerBackend{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace:  "app",
+					Name:       fakeprovider.ValidBackendName,
+					Finalizers: []string{objectmeta.TrafficManagerBackendFinalizer},
+				},
+				Spec: fleetnetv1beta1.TrafficManagerBackendSpec{
+					Profile: fleetnetv1beta1.TrafficManagerProfileRef{Name: tt.profileName},
+					Backend: fleetnetv1beta1.TrafficManagerBackendRef{Name: fakeprovider.ServiceImportName},
+					Weight:  ptr.To(int64(1)),
+				},
+			}
+			fakeClient := fake.NewClientBuilder().
+				WithScheme(scheme).
+				WithObjects(profile, backend).
+				WithStatusSubresource(backend).
+				Build()
+			mutations := fakeprovider.NewMutationRecorder()
+			profilesClient, err := mutations.NewProfileClient("subscription")
+			if err != nil {
+				t.Fatalf("NewProfileClient() got error %v, want no error", err)
+			}
+			endpointsClient, err := mutations.NewEndpointsClient("subscription")
+			if err != nil {
+				t.Fatalf("NewEndpointsClient() got error %v, want no error", err)
+			}
+			r := &Reconciler{
+				Client:            fakeClient,
+				ProfilesClient:    profilesClient,
+				EndpointsClient:   endpointsClient,
+				ResourceGroupName: fakeprovider.DefaultResourceGroupName,
+				ReadOnly:          true,
+			}
+
+			key := types.NamespacedName{Namespace: backend.Namespace, Name: backend.Name}
+			if err := fakeClient.Delete(ctx, backend); err != nil {
+				t.Fatalf("Delete() got error %v, want no error", err)
+			}
+			if err := fakeClient.Get(ctx, key, backend); err != nil {
+				t.Fatalf("Get() got error %v, want no error", err)
+			}
+			if _, err := r.handleDelete(ctx, backend); err != nil {
+				t.Fatalf("handleDelete() got error %v, want no error", err)
+			}
+
+			got := &fleetnetv1beta1.TrafficManagerBackend{}
+			err = fakeClient.Get(ctx, key, got)
+			switch {
+			case tt.wantFinalizer && err != nil:
+				t.Fatalf("Get() got error %v, want the backend with the finalizer", err)
+			case !tt.wantFinalizer && !apierrors.IsNotFound(err):
+				t.Fatalf("Get() got error %v, want not found error", err)
+			case tt.wantFinalizer:
+				cond := meta.FindStatusCondition(got.Status.Conditions, string(fleetnetv1beta1.TrafficManagerBackendConditionAccepted))
+				if cond == nil || cond.Status != metav1.ConditionUnknown || cond.Reason != string(fleetnetv1beta1.TrafficManagerBackendReasonReadOnly) ||
+					!strings.HasPrefix(cond.Message, "Refusing to remove the finalizer") {
+					t.Errorf("handleDelete() Accepted condition = %+v, want the reason of the kept finalizer", cond)
+				}
+			}
+			if got := mutations.Requests(); len(got) != 0 {
+				t.Errorf("handleDelete() sent mutating requests %v, want none", got)
+			}
+		})
+	}
+}
+
+// fakeProfilesClient is a ProfilesClient which returns the given profile or error.
+type fakeProfilesClient struct {
+	profile armtrafficmanager.Profile
+	getErr  error
+}
+
+func (c *fakeProfilesClient) Get(_ context.Context, _ string, _ string, _ *armtrafficmanager.ProfilesClientGetOptions) (armtrafficmanager.ProfilesClientGetResponse, error) {
+	if c.getErr != nil {
+		return armtrafficmanager.ProfilesClientGetResponse{}, c.getErr
+	}
+	return armtrafficmanager.ProfilesClientGetResponse{Profile: c.profile}, nil
+}
+
+func (c *fakeProfilesClient) CreateOrUpdate(_ context.Context, _ string, _ string, parameters armtrafficmanager.Profile, _ *armtrafficmanager.ProfilesClientCreateOrUpdateOptions) (armtrafficmanager.ProfilesClientCreateOrUpdateResponse, error) {
+	c.profile = parameters
+	return armtrafficmanager.ProfilesClientCreateOrUpdateResponse{Profile: parameters}, nil
+}
+
+// fakeEndpointsClient is an EndpointsClient which records the requests and returns the errors set for the endpoints.
+type fakeEndpointsClient struct {
+	// createOrUpdateErrs and deleteErrs are keyed by the endpoint name.
+	createOrUpdateErrs map[string]error
+	deleteErrs         map[string]error
+
+	createdOrUpdated []string
+	deleted          []string
+}
+
+func (c *fakeEndpointsClient) CreateOrUpdate(_ context.Context, _ string, _ string, _ armtrafficmanager.EndpointType, endpointName string, parameters armtrafficmanager.Endpoint, _ *armtrafficmanager.EndpointsClientCreateOrUpdateOptions) (armtrafficmanager.EndpointsClientCreateOrUpdateResponse, error) {
+	c.createdOrUpdated = append(c.createdOrUpdated, endpointName)
+	if err := c.createOrUpdateErrs[endpointName]; err != nil {
+		return armtrafficmanager.EndpointsClientCreateOrUpdateResponse{}, err
+	}
+	return armtrafficmanager.EndpointsClientCreateOrUpdateResponse{Endpoint: parameters}, nil
+}
+
+func (c *fakeEndpointsClient) Delete(_ context.Context, _ string, _ string, _ armtrafficmanager.EndpointType, endpointName string, _ *armtrafficmanager.EndpointsClientDeleteOptions) (armtrafficmanager.EndpointsClientDeleteResponse, error) {
+	c.deleted = append(c.deleted, endpointName)
+	return armtrafficmanager.EndpointsClientDeleteResponse{}, c.deleteErrs[endpointName]
+}
+
+func TestIsEndpointOwnedByBackend(t *testing.T) {
+	backend := &fleetnetv1beta1.TrafficManagerBackend{ObjectMeta: metav1.ObjectMeta{Name: "backend", UID: "abc"}}
+	tests := []struct {
+		endpoint string
+		want     bool
+	}{
+		{endpoint: "fleet-abc#svc#member-1", want: true},
+		{endpoint: "fleet-abc#", want: true},
+		{endpoint: "fleet-abcd#svc#member-1"},
+		{endpoint: "fleet-other#svc#member-1"},
+		{endpoint: "abc#svc#member-1"},
+		{endpoint: "manually-added"},
+	}
+	for _, tc := range tests {
+		t.Run(tc.endpoint, func(t *testing.T) {
+			if got := isEndpointOwnedByBackend(backend, tc.endpoint); got != tc.want {
+				t.Errorf("isEndpointOwnedByBackend(%q) = %v, want %v", tc.endpoint, got, tc.want)
+			}
+		})
+	}
+}
+
+func TestSplitWeight(t *testing.T) {
+	tests := []struct {
+		weight int64
+		n      int
+		want   int64
+	}{
+		{weight: 12, n: 1, want: 12},
+		{weight: 12, n: 3, want: 4},
+		{weight: 10, n: 3, want: 4},
+		{weight: 1, n: 3, want: 1},
+		{weight: 1000, n: 7, want: 143},
+	}
+	for _, tc := range tests {
+		t.Run(fmt.Sprintf("%d/%d", tc.weight, tc.n), func(t *testing.T) {
+			got := splitWeight(tc.weight, tc.n)
+			if got != tc.want {
+				t.Errorf("splitWeight(%d, %d) = %d, want %d", tc.weight, tc.n, got, tc.want)
+			}
+			if got*int64(tc.n) < tc.weight {
+				t.Errorf("splitWeight(%d, %d) = %d, want the sum of the endpoint weights no less than the backend weight", tc.weight, tc.n, got)
+			}
+		})
+	}
+}
+
+// TestUpdateTrafficManagerEndpoints_ErrorClassification tests that the client errors of Azure make bad endpoints
+// while the other errors leave the backend Unknown and are retried.
+func TestUpdateTrafficManagerEndpoints_ErrorClassification(t *testing.T) {
+	ctx := context.Background()
+
+	originalPrefixFunc := generateAzureTrafficManagerEndpointNamePrefixFunc
+	generateAzureTrafficManagerEndpointNamePrefixFunc = func(backend *fleetnetv1beta1.TrafficManagerBackend) string {
+		return backend.Name + "#"
+	}
+	defer func() { generateAzureTrafficManagerEndpointNamePrefixFunc = originalPrefixFunc }()
+
+	backendName := "backend"
+	endpointName := func(cluster string) string {
+		return *newTestDesiredEndpoint(backendName, cluster, 10).Endpoint.Name
+	}
+	staleEndpoint := newTestDesiredEndpoint(backendName, "member-3", 10).Endpoint
+	otherBackendEndpoint := newTestDesiredEndpoint("other-backend", "member-1", 10).Endpoint
+
+	tests := []struct {
+		name               string
+		existing           []*armtrafficmanager.Endpoint
+		createOrUpdateErrs map[string]error
+		deleteErrs         map[string]error
+		wantErr            bool
+		wantBadEndpoints   int
+		wantAccepted       []string
+		wantDeleted        []string
+		// wantCondStatus is empty when the condition is left to the caller.
+		wantCondStatus metav1.ConditionStatus
+	}{
+		{
+			name:         "all the endpoints are accepted",
+			wantAccepted: []string{"member-1", "member-2"},
+		},
+		{
+			name:               "bad request makes a bad endpoint",
+			createOrUpdateErrs: map[string]error{endpointName("member-2"): &azcore.ResponseError{StatusCode: http.StatusBadRequest, ErrorCode: "BadRequest"}},
+			wantBadEndpoints:   1,
+			wantAccepted:       []string{"member-1"},
+		},
+		{
+			name:               "conflict makes a bad endpoint",
+			createOrUpdateErrs: map[string]error{endpointName("member-1"): &azcore.ResponseError{StatusCode: http.StatusConflict, ErrorCode: "Conflict"}},
+			wantBadEndpoints:   1,
+			wantAccepted:       []string{"member-2"},
+		},
+		{
+			name:               "throttled request is retried",
+			createOrUpdateErrs: map[string]error{endpointName("member-1"): &azcore.ResponseError{StatusCode: http.StatusTooManyRequests}},
+			wantErr:            true,
+			wantCondStatus:     metav1.ConditionUnknown,
+		},
+		{
+			name:               "server error is retried",
+			createOrUpdateErrs: map[string]error{endpointName("member-1"): &azcore.ResponseError{StatusCode: http.StatusInternalServerError}},
+			wantErr:            true,
+			wantCondStatus:     metav1.ConditionUnknown,
+		},
+		{
+			name:               "request which is not sent is retried",
+			createOrUpdateErrs: map[string]error{endpointName("member-1"): errors.New("connection reset by peer")},
+			wantErr:            true,
+		},
+		{
+			name:         "stale endpoint is deleted",
+			existing:     []*armtrafficmanager.Endpoint{&staleEndpoint, &otherBackendEndpoint},
+			wantAccepted: []string{"member-1", "member-2"},
+			wantDeleted:  []string{*staleEndpoint.Name},
+		},
+		{
+			name:         "stale endpoint which is already deleted",
+			existing:     []*armtrafficmanager.Endpoint{&staleEndpoint},
+			deleteErrs:   map[string]error{*staleEndpoint.Name: &azcore.ResponseError{StatusCode: http.StatusNotFound}},
+			wantAccepted: []string{"member-1", "member-2"},
+			wantDeleted:  []string{*staleEndpoint.Name},
+		},
+		{
+			name:           "stale endpoint which fails to be deleted",
+			existing:       []*armtrafficmanager.Endpoint{&staleEndpoint},
+			deleteErrs:     map[string]error{*staleEndpoint.Name: &azcore.ResponseError{StatusCode: http.StatusBadRequest}},
+			wantErr:        true,
+			wantDeleted:    []string{*staleEndpoint.Name},
+			wantCondStatus: metav1.ConditionUnknown,
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			scheme := runtime.NewScheme()
+			if err := fleetnetv1beta1.AddToScheme(scheme); err != nil {
+				t.Fatalf("AddToScheme() got error %v, want no error", err)
+			}
+			backend := &fleetnetv1beta1.TrafficManagerBackend{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "app", Name: backendName},
+			}
+			endpointsClient := &fakeEndpointsClient{createOrUpdateErrs: tc.createOrUpdateErrs, deleteErrs: tc.deleteErrs}
+			r := &Reconciler{
+				Client:            fake.NewClientBuilder().WithScheme(scheme).WithObjects(backend).WithStatusSubresource(backend).Build(),
+				EndpointsClient:   endpointsClient,
+				ResourceGroupName: fakeprovider.DefaultResourceGroupName,
+			}
+			if err := r.Client.Get(ctx, client.ObjectKeyFromObject(backend), backend); err != nil {
+				t.Fatalf("Get() got error %v, want no error", err)
+			}
+			profile := &armtrafficmanager.Profile{
+				Name:       ptr.To(fakeprovider.ValidProfileName),
+				Properties: &armtrafficmanager.ProfileProperties{Endpoints: tc.existing},
+			}
+
+			accepted, badErrs, err := r.updateTrafficManagerEndpointsAndUpdateStatusIfUnknown(ctx, backend, profile, newTestDesiredEndpoints(backendName, "member-1", "member-2"))
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("updateTrafficManagerEndpointsAndUpdateStatusIfUnknown() got error %v, want error %v", err, tc.wantErr)
+			}
+			if len(badErrs) != tc.wantBadEndpoints {
+				t.Errorf("updateTrafficManagerEndpointsAndUpdateStatusIfUnknown() got bad endpoints %v, want %d", badErrs, tc.wantBadEndpoints)
+			}
+			var gotAccepted []string
+			for _, endpoint := range accepted {
+				gotAccepted = append(gotAccepted, endpoint.From.Cluster)
+			}
+			sort.Strings(gotAccepted)
+			if diff := cmp.Diff(tc.wantAccepted, gotAccepted); diff != "" {
+				t.Errorf("updateTrafficManagerEndpointsAndUpdateStatusIfUnknown() accepted endpoints mismatch (-want, +got):\n%s", diff)
+			}
+			if diff := cmp.Diff(tc.wantDeleted, endpointsClient.deleted); diff != "" {
+				t.Errorf("updateTrafficManagerEndpointsAndUpdateStatusIfUnknown() deleted endpoints mismatch (-want, +got):\n%s", diff)
+			}
+			var gotCondStatus metav1.ConditionStatus
+			if cond := meta.FindStatusCondition(backend.Status.Conditions, string(fleetnetv1beta1.TrafficManagerBackendConditionAccepted)); cond != nil {
+				gotCondStatus = cond.Status
+			}
+			if gotCondStatus != tc.wantCondStatus {
+				t.Errorf("updateTrafficManagerEndpointsAndUpdateStatusIfUnknown() set the Accepted condition %q, want %q", gotCondStatus, tc.wantCondStatus)
+			}
+		})
+	}
+}
+
+// TestValidateAzureTrafficManagerProfile tests that a missing Azure Traffic Manager profile makes the backend invalid
+// while the other errors leave it Unknown and are retried.
+func TestValidateAzureTrafficManagerProfile(t *testing.T) {
+	ctx := context.Background()
+	tests := []struct {
+		name           string
+		getErr         error
+		wantProfile    bool
+		wantErr        bool
+		wantCondStatus metav1.ConditionStatus
+		wantCondReason string
+	}{
+		{
+			name:        "profile exists",
+			wantProfile: true,
+		},
+		{
+			name:           "profile is not found",
+			getErr:         &azcore.ResponseError{StatusCode: http.StatusNotFound},
+			wantCondStatus: metav1.ConditionFalse,
+			wantCondReason: string(fleetnetv1beta1.TrafficManagerBackendReasonInvalid),
+		},
+		{
+			name:           "failed to get the profile",
+			getErr:         &azcore.ResponseError{StatusCode: http.StatusInternalServerError},
+			wantErr:        true,
+			wantCondStatus: metav1.ConditionUnknown,
+			wantCondReason: string(fleetnetv1beta1.TrafficManagerBackendReasonPending),
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			scheme := runtime.NewScheme()
+			if err := fleetnetv1beta1.AddToScheme(scheme); err != nil {
+				t.Fatalf("AddToScheme() got error %v, want no error", err)
+			}
+			backend := &fleetnetv1beta1.TrafficManagerBackend{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "app", Name: "backend"},
+			}
+			r := &Reconciler{
+				Client: fake.NewClientBuilder().WithScheme(scheme).WithObjects(backend).WithStatusSubresource(backend).Build(),
+				ProfilesClient: &fakeProfilesClient{
+					profile: armtrafficmanager.Profile{Name: ptr.To(fakeprovider.ValidProfileName)},
+					getErr:  tc.getErr,
+				},
+				ResourceGroupName: fakeprovider.DefaultResourceGroupName,
+			}
+			if err := r.Client.Get(ctx, client.ObjectKeyFromObject(backend), backend); err != nil {
+				t.Fatalf("Get() got error %v, want no error", err)
+			}
+			profile := &fleetnetv1beta1.TrafficManagerProfile{ObjectMeta: metav1.ObjectMeta{Namespace: "app", Name: "profile"}}
+
+			got, err := r.validateAzureTrafficManagerProfile(ctx, backend, profile)
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("validateAzureTrafficManagerProfile() got error %v, want error %v", err, tc.wantErr)
+			}
+			if (got != nil) != tc.wantProfile {
+				t.Errorf("validateAzureTrafficManagerProfile() got profile %v, want profile %v", got, tc.wantProfile)
+			}
+			var gotCondStatus metav1.ConditionStatus
+			var gotCondReason string
+			if cond := meta.FindStatusCondition(backend.Status.Conditions, string(fleetnetv1beta1.TrafficManagerBackendConditionAccepted)); cond != nil {
+				gotCondStatus, gotCondReason = cond.Status, cond.Reason
+			}
+			if gotCondStatus != tc.wantCondStatus || gotCondReason != tc.wantCondReason {
+				t.Errorf("validateAzureTrafficManagerProfile() set the Accepted condition %q with reason %q, want %q with reason %q", gotCondStatus, gotCondReason, tc.wantCondStatus, tc.wantCondReason)
+			}
+		})
+	}
+}
+
+func TestSetEndpointsCondition(t *testing.T) {
+	accepted := []fleetnetv1beta1.TrafficManagerEndpointStatus{
+		{Name: "endpoint-1", From: &fleetnetv1beta1.FromCluster{ClusterStatus: fleetnetv1beta1.ClusterStatus{Cluster: "member-1"}}},
+	}
+	tests := []struct {
+		name            string
+		badEndpointsErr []error
+		invalidServices map[string]error
+		wantStatus      metav1.ConditionStatus
+		wantReason      fleetnetv1beta1.TrafficManagerBackendConditionReason
+		wantMessage     string
+		wantInvalid     []fleetnetv1beta1.TrafficManagerInvalidCluster
+	}{
+		{
+			name:        "all the services are accepted",
+			wantStatus:  metav1.ConditionTrue,
+			wantReason:  fleetnetv1beta1.TrafficManagerBackendReasonAccepted,
+			wantMessage: "1 service(s) exported from clusters have been accepted as Traffic Manager endpoints",
+		},
+		{
+			name:            "bad endpoint",
+			badEndpointsErr: []error{&badEndpointError{cluster: "member-1", err: errors.New("bad request")}},
+			wantStatus:      metav1.ConditionFalse,
+			wantReason:      fleetnetv1beta1.TrafficManagerBackendReasonInvalid,
+			wantMessage:     "1 endpoint(s) failed to be created/updated in the Azure Traffic Manager, for example, bad request; ",
+			wantInvalid: []fleetnetv1beta1.TrafficManagerInvalidCluster{
+				{Cluster: "member-1", Reason: fleetnetv1beta1.TrafficManagerInvalidClusterReasonAzureClientError, Message: "bad request"},
+			},
+		},
+		{
+			name:            "invalid service",
+			invalidServices: map[string]error{"member-2": errors.New("not a load balancer")},
+			wantStatus:      metav1.ConditionFalse,
+			wantReason:      fleetnetv1beta1.TrafficManagerBackendReasonInvalid,
+			wantMessage:     "1 service(s) exported from clusters cannot be exposed as the Azure Traffic Manager, for example, service exported from member-2 is invalid: not a load balancer",
+			wantInvalid: []fleetnetv1beta1.TrafficManagerInvalidCluster{
+				{Cluster: "member-2", Reason: fleetnetv1beta1.TrafficManagerInvalidClusterReasonInvalid, Message: "not a load balancer"},
+			},
+		},
+		{
+			name: "member agents to upgrade",
+			invalidServices: map[string]error{
+				"member-3": fmt.Errorf("%w: not reported", errAgentUpgradeRequired),
+				"member-2": invalidClusterErrorf(fleetnetv1beta1.TrafficManagerInvalidClusterReasonAgentUpgradeRequired, "%w: not reported", errAgentUpgradeRequired),
+				"member-4": invalidClusterErrorf(fleetnetv1beta1.TrafficManagerInvalidClusterReasonUnsupportedType, "not a load balancer"),
+			},
+			wantStatus:  metav1.ConditionFalse,
+			wantReason:  fleetnetv1beta1.TrafficManagerBackendReasonAgentUpgradeRequired,
+			wantMessage: "3 service(s) exported from clusters cannot be exposed as the Azure Traffic Manager, as the member agents of the clusters [member-2 member-3] must be upgraded, for example, service exported from member-2 is invalid: AgentUpgradeRequired: not reported",
+			wantInvalid: []fleetnetv1beta1.TrafficManagerInvalidCluster{
+				{Cluster: "member-2", Reason: fleetnetv1beta1.TrafficManagerInvalidClusterReasonAgentUpgradeRequired, Message: "AgentUpgradeRequired: not reported"},
+				{Cluster: "member-3", Reason: fleetnetv1beta1.TrafficManagerInvalidClusterReasonInvalid, Message: "AgentUpgradeRequired: not reported"},
+				{Cluster: "member-4", Reason: fleetnetv1beta1.TrafficManagerInvalidClusterReasonUnsupportedType, Message: "not a load balancer"},
+			},
+		},
+		{
+			name:            "changes skipped in the read-only mode",
+			badEndpointsErr: []error{fmt.Errorf("%w: would create endpoint", errReadOnly)},
+			invalidServices: map[string]error{"member-2": errors.New("not a load balancer")},
+			wantStatus:      metav1.ConditionUnknown,
+			wantReason:      fleetnetv1beta1.TrafficManagerBackendReasonReadOnly,
+			wantMessage:     "1 change(s) of the Azure Traffic Manager endpoints are not applied in the read-only mode: would create endpoint",
+			wantInvalid: []fleetnetv1beta1.TrafficManagerInvalidCluster{
+				{Cluster: "member-2", Reason: fleetnetv1beta1.TrafficManagerInvalidClusterReasonInvalid, Message: "not a load balancer"},
+			},
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			backend := &fleetnetv1beta1.TrafficManagerBackend{ObjectMeta: metav1.ObjectMeta{Generation: 3}}
+			setEndpointsCondition(backend, accepted, tc.badEndpointsErr, tc.invalidServices)
+			want := []metav1.Condition{
+				{
+					Type:               string(fleetnetv1beta1.TrafficManagerBackendConditionAccepted),
+					Status:             tc.wantStatus,
+					ObservedGeneration: 3,
+					Reason:             string(tc.wantReason),
+					Message:            tc.wantMessage,
+				},
+			}
+			if diff := cmp.Diff(want, backend.Status.Conditions, cmpopts.IgnoreFields(metav1.Condition{}, "LastTransitionTime")); diff != "" {
+				t.Errorf("setEndpointsCondition() conditions mismatch (-want, +got):\n%s", diff)
+			}
+			if diff := cmp.Diff(accepted, backend.Status.Endpoints); diff != "" {
+				t.Errorf("setEndpointsCondition() endpoints mismatch (-want, +got):\n%s", diff)
+			}
+			if diff := cmp.Diff(tc.wantInvalid, backend.Status.InvalidClusters); diff != "" {
+				t.Errorf("setEndpointsCondition() invalid clusters mismatch (-want, +got):\n%s", diff)
+			}
+			if got, want := backend.Status.TotalInvalid, int32(len(tc.wantInvalid)); got != want {
+				t.Errorf("setEndpointsCondition() total invalid = %d, want %d", got, want)
+			}
+		})
+	}
+}
+
+func TestBuildInvalidClusters(t *testing.T) {
+	throttled := &azcore.ResponseError{StatusCode: http.StatusTooManyRequests}
+	badRequest := &azcore.ResponseError{StatusCode: http.StatusBadRequest, ErrorCode: "BadRequest"}
+	manyInvalidServices := make(map[string]error, fleetnetv1beta1.TrafficManagerBackendMaxInvalidClusters+5)
+	for i := range fleetnetv1beta1.TrafficManagerBackendMaxInvalidClusters + 5 {
+		manyInvalidServices[fmt.Sprintf("member-%02d", i)] = invalidClusterErrorf(fleetnetv1beta1.TrafficManagerInvalidClusterReasonNoDNSLabel, "no DNS label")
+	}
+	tests := []struct {
+		name            string
+		badEndpointsErr []error
+		invalidServices map[string]error
+		wantClusters    []string
+		wantReasons     []fleetnetv1beta1.TrafficManagerInvalidClusterReason
+		wantTotal       int32
+	}{
+		{
+			name: "no invalid clusters",
+		},
+		{
+			name: "bad endpoints and invalid services",
+			badEndpointsErr: []error{
+				&badEndpointError{cluster: "member-3", err: badRequest},
+				&badEndpointError{cluster: "member-1", err: throttled},
+			},
+			invalidServices: map[string]error{
+				"member-2": invalidClusterErrorf(fleetnetv1beta1.TrafficManagerInvalidClusterReasonInternalLoadBalancer, "internal load balancer is not supported"),
+			},
+			wantClusters: []string{"member-1", "member-2", "member-3"},
+			wantReasons: []fleetnetv1beta1.TrafficManagerInvalidClusterReason{
+				fleetnetv1beta1.TrafficManagerInvalidClusterReasonThrottled,
+				fleetnetv1beta1.TrafficManagerInvalidClusterReasonInternalLoadBalancer,
+				fleetnetv1beta1.TrafficManagerInvalidClusterReasonAzureClientError,
+			},
+			wantTotal: 3,
+		},
+		{
+			name:            "exposed service rejected by Azure",
+			badEndpointsErr: []error{&badEndpointError{cluster: "member-1", err: badRequest}},
+			invalidServices: map[string]error{
+				"member-1": invalidClusterErrorf(fleetnetv1beta1.TrafficManagerInvalidClusterReasonLocalExternalTrafficPolicy, "Local external traffic policy"),
+			},
+			wantClusters: []string{"member-1"},
+			wantReasons:  []fleetnetv1beta1.TrafficManagerInvalidClusterReason{fleetnetv1beta1.TrafficManagerInvalidClusterReasonAzureClientError},
+			wantTotal:    1,
+		},
+		{
+			name:            "changes skipped in the read-only mode",
+			badEndpointsErr: []error{fmt.Errorf("%w: would create endpoint", errReadOnly)},
+		},
+		{
+			name:            "capped",
+			invalidServices: manyInvalidServices,
+			wantClusters: func() []string {
+				clusters := make([]string, fleetnetv1beta1.TrafficManagerBackendMaxInvalidClusters)
+				for i := range clusters {
+					clusters[i] = fmt.Sprintf("member-%02d", i)
+				}
+				return clusters
+			}(),
+			wantTotal: fleetnetv1beta1.TrafficManagerBackendMaxInvalidClusters + 5,
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got, gotTotal := buildInvalidClusters(tc.badEndpointsErr, tc.invalidServices)
+			if gotTotal != tc.wantTotal {
+				t.Errorf("buildInvalidClusters() total = %d, want %d", gotTotal, tc.wantTotal)
+			}
+			var gotClusters []string
+			var gotReasons []fleetnetv1beta1.TrafficManagerInvalidClusterReason
+			for _, invalid := range got {
+				gotClusters = append(gotClusters, invalid.Cluster)
+				gotReasons = append(gotReasons, invalid.Reason)
+				if invalid.Message == "" {
+					t.Errorf("buildInvalidClusters() message of cluster %s is empty, want a message", invalid.Cluster)
+				}
+			}
+			if diff := cmp.Diff(tc.wantClusters, gotClusters); diff != "" {
+				t.Errorf("buildInvalidClusters() clusters mismatch (-want, +got):\n%s", diff)
+			}
+			if tc.wantReasons != nil {
+				if diff := cmp.Diff(tc.wantReasons, gotReasons); diff != "" {
+					t.Errorf("buildInvalidClusters() reasons mismatch (-want, +got):\n%s", diff)
+				}
+			}
+		})
+	}
+}
+
+// TestReconcile_NamespaceAllowlist tests that the backends of the namespaces which are not allowed are refused without
+// calling Azure, and that their existing endpoints are left as is.
+func TestReconcile_NamespaceAllowlist(t *testing.T) {
+	deletionTimestamp := metav1.Now()
+	tests := []struct {
+		name              string
+		allowedNamespaces nsallowlist.Allowlist
+		deleting          bool
+		wantReason        fleetnetv1beta1.TrafficManagerBackendConditionReason
+		wantFinalizer     bool
+		wantDeleted       bool
+		wantEvents        int
+	}{
+		{
+			name:              "namespace allowed",
+			allowedNamespaces: nsallowlist.Parse("app"),
+			// The backend has invalid references, so that it is rejected without calling Azure either.
+			wantReason:    fleetnetv1beta1.TrafficManagerBackendReasonInvalid,
+			wantFinalizer: true,
+		},
+		{
+			name:              "namespace not allowed",
+			allowedNamespaces: nsallowlist.Parse("other"),
+			wantReason:        fleetnetv1beta1.TrafficManagerBackendReasonNamespaceNotAllowed,
+			wantEvents:        1,
+		},
+		{
+			name:              "deleted backend in a namespace not allowed",
+			allowedNamespaces: nsallowlist.Parse("other"),
+			deleting:          true,
+			wantDeleted:       true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			scheme := runtime.NewScheme()
+			if err := fleetnetv1beta1.AddToScheme(scheme); err != nil {
+				t.Fatalf("AddToScheme() got error %v, want no error", err)
+			}
+			// The backend was accepted before its namespace was disallowed.
+			backend := &fleetnetv1beta1.TrafficManagerBackend{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace:  "app",
+					Name:       fakeprovider.ValidBackendName,
+					Finalizers: []string{objectmeta.TrafficManagerBackendFinalizer},
+				},
+				Spec: fleetnetv1beta1.TrafficManagerBackendSpec{
+					Profile: fleetnetv1beta1.TrafficManagerProfileRef{Name: fakeprovider.ValidProfileName},
+					Backend: fleetnetv1beta1.TrafficManagerBackendRef{Name: "service/import"},
+					Weight:  ptr.To(int64(1)),
+				},
+				Status: fleetnetv1beta1.TrafficManagerBackendStatus{
+					Endpoints: []fleetnetv1beta1.TrafficManagerEndpointStatus{
+						{Name: "endpoint", Weight: ptr.To(int64(1))},
+					},
+				},
+			}
+			if tt.deleting {
+				backend.DeletionTimestamp = &deletionTimestamp
+			}
+			fakeClient := fake.NewClientBuilder().
+				WithScheme(scheme).
+				WithObjects(backend).
+				WithStatusSubresource(backend).
+				Build()
+			transport := &countingTransport{}
+			clientFactory, err := armtrafficmanager.NewClientFactory("subscription", &azcorefake.TokenCredential{},
+				&arm.ClientOptions{ClientOptions: azcore.ClientOptions{Transport: transport}})
+			if err != nil {
+				t.Fatalf("NewClientFactory() got error %v, want no error", err)
+			}
+			recorder := record.NewFakeRecorder(10)
+			r := &Reconciler{
+				Client:            fakeClient,
+				ProfilesClient:    clientFactory.NewProfilesClient(),
+				EndpointsClient:   clientFactory.NewEndpointsClient(),
+				ResourceGroupName: fakeprovider.DefaultResourceGroupName,
+				AllowedNamespaces: tt.allowedNamespaces,
+				Recorder:          recorder,
+			}
+
+			key := types.NamespacedName{Namespace: backend.Namespace, Name: backend.Name}
+			// The second reconciliation finds the backend already refused.
+			for i := 0; i < 2; i++ {
+				if _, err := r.Reconcile(ctx, ctrl.Request{NamespacedName: key}); err != nil {
+					t.Fatalf("Reconcile() got error %v, want no error", err)
+				}
+			}
+			if got := transport.count(); got != 0 {
+				t.Errorf("Reconcile() sent %d requests to Azure, want 0", got)
+			}
+			if got := len(recorder.Events); got != tt.wantEvents {
+				t.Errorf("Reconcile() emitted %d events, want %d", got, tt.wantEvents)
+			}
+
+			got := &fleetnetv1beta1.TrafficManagerBackend{}
+			err = fakeClient.Get(ctx, key, got)
+			if tt.wantDeleted {
+				if !apierrors.IsNotFound(err) {
+					t.Errorf("Get() got error %v, want not found error", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Get() got error %v, want no error", err)
+			}
+			cond := meta.FindStatusCondition(got.Status.Conditions, string(fleetnetv1beta1.TrafficManagerBackendConditionAccepted))
+			if cond == nil || cond.Status != metav1.ConditionFalse || cond.Reason != string(tt.wantReason) {
+				t.Errorf("Reconcile() Accepted condition = %+v, want False with reason %q", cond, tt.wantReason)
+			}
+			if len(got.Status.Endpoints) != 0 {
+				t.Errorf("Reconcile() endpoints = %+v, want none", got.Status.Endpoints)
+			}
+			if gotFinalizer := controllerutil.ContainsFinalizer(got, objectmeta.TrafficManagerBackendFinalizer); gotFinalizer != tt.wantFinalizer {
+				t.Errorf("Reconcile() finalizer = %t, want %t", gotFinalizer, tt.wantFinalizer)
+			}
+		})
+	}
+}
+
+func TestStartupJitterDelay(t *testing.T) {
+	var disabled *startupJitter
+	if got := disabled.delay(types.NamespacedName{Namespace: "app", Name: "backend"}, time.Now()); got != 0 {
+		t.Errorf("delay() without the startup jitter = %v, want 0", got)
+	}
+
+	window := 10 * time.Second
+	j := &startupJitter{window: window}
+	startedAt := time.Now()
+	delays := map[time.Duration]bool{}
+	for i := 0; i < 100; i++ {
+		name := types.NamespacedName{Namespace: fmt.Sprintf("app-%d", i), Name: "backend"}
+		now := startedAt.Add(time.Duration(i) * time.Millisecond)
+		got := j.delay(name, now)
+		if got >= window {
+			t.Fatalf("delay(%v) = %v, want less than the window %v", name, got, window)
+		}
+		// The delay of a backend only depends on its name and the first reconciliation of the controller.
+		if again := j.delay(name, now); again != got {
+			t.Errorf("delay(%v) got %v and then %v, want the same delay", name, got, again)
+		}
+		if after := j.delay(name, startedAt.Add(window)); after > 0 {
+			t.Errorf("delay(%v) after the window = %v, want no delay", name, after)
+		}
+		delays[got.Round(time.Second)] = true
+	}
+	if len(delays) < 5 {
+		t.Errorf("delay() spread the backends over %d seconds of the window, want at least 5", len(delays))
+	}
+}
+
+// TestUpdateTrafficManagerEndpoints_ConcurrencyLimiter tests that the backends reconciled concurrently against Azure
+// throttling the endpoint requests above a concurrency threshold all converge when they share the concurrency
+// limiter, each of them being requeued until its reconciliation succeeds.
+func TestUpdateTrafficManagerEndpoints_ConcurrencyLimiter(t *testing.T) {
+	const (
+		backendCount        = 20
+		endpointsPerBackend = 5
+		throttleThreshold   = 4
+		// maxRounds is the number of reconciliation rounds within which all the backends converge; without the
+		// limiter, most of the endpoint requests of every round would be throttled.
+		maxRounds = 10
+	)
+	ctx := context.Background()
+
+	// The fake endpoints server only accepts the endpoints of the valid backend.
+	originalPrefixFunc := generateAzureTrafficManagerEndpointNamePrefixFunc
+	generateAzureTrafficManagerEndpointNamePrefixFunc = func(backend *fleetnetv1beta1.TrafficManagerBackend) string {
+		return backend.Name + "#"
+	}
+	defer func() { generateAzureTrafficManagerEndpointNamePrefixFunc = originalPrefixFunc }()
+
+	var mu sync.Mutex
+	inFlight := 0
+	created := map[string][]armtrafficmanager.Endpoint{} // key is the profile name
+	fakeServer := atmfake.EndpointsServer{
+		CreateOrUpdate: func(ctx context.Context, resourceGroupName string, profileName string, endpointType armtrafficmanager.EndpointType, endpointName string, endpoint armtrafficmanager.Endpoint, options *armtrafficmanager.EndpointsClientCreateOrUpdateOptions) (resp azcorefake.Responder[armtrafficmanager.EndpointsClientCreateOrUpdateResponse], errResp azcorefake.ErrorResponder) {
+			mu.Lock()
+			inFlight++
+			throttled := inFlight > throttleThreshold
+			mu.Unlock()
+			defer func() {
+				mu.Lock()
+				inFlight--
+				mu.Unlock()
+			}()
+			time.Sleep(time.Millisecond)
+			if throttled {
+				errResp.SetResponseError(http.StatusTooManyRequests, "TooManyRequests")
+				return resp, errResp
+			}
+			mu.Lock()
+			created[profileName] = append(created[profileName], endpoint)
+			mu.Unlock()
+			return fakeprovider.EndpointCreateOrUpdate(ctx, resourceGroupName, profileName, endpointType, endpointName, endpoint, options)
+		},
+	}
+	clientFactory, err := armtrafficmanager.NewClientFactory("subscription", &azcorefake.TokenCredential{},
+		&arm.ClientOptions{ClientOptions: azcore.ClientOptions{
+			Transport: atmfake.NewEndpointsServerTransport(&fakeServer),
+			// The throttled requests are retried by requeueing the backends instead.
+			Retry: policy.RetryOptions{MaxRetries: -1},
+		}})
+	if err != nil {
+		t.Fatalf("NewClientFactory() got error %v, want no error", err)
+	}
+
+	scheme := runtime.NewScheme()
+	if err := fleetnetv1beta1.AddToScheme(scheme); err != nil {
+		t.Fatalf("AddToScheme() got error %v, want no error", err)
+	}
+	backends := make([]*fleetnetv1beta1.TrafficManagerBackend, 0, backendCount)
+	for i := 0; i < backendCount; i++ {
+		backends = append(backends, &fleetnetv1beta1.TrafficManagerBackend{
+			ObjectMeta: metav1.ObjectMeta{Namespace: fmt.Sprintf("app-%d", i), Name: fakeprovider.ValidBackendName},
+		})
+	}
+	builder := fake.NewClientBuilder().WithScheme(scheme)
+	for _, backend := range backends {
+		builder = builder.WithObjects(backend).WithStatusSubresource(backend)
+	}
+	limiterName := "test-trafficmanagerbackend"
+	r := &Reconciler{
+		Client:             builder.Build(),
+		EndpointsClient:    clientFactory.NewEndpointsClient(),
+		ResourceGroupName:  fakeprovider.DefaultResourceGroupName,
+		ConcurrencyLimiter: adaptivelimiter.New(limiterName, 32),
+	}
+	clusters := make([]string, 0, endpointsPerBackend)
+	for i := 0; i < endpointsPerBackend; i++ {
+		clusters = append(clusters, fmt.Sprintf("member-%d", i))
+	}
+
+	pending := make(map[int]bool, backendCount)
+	for i := range backends {
+		pending[i] = true
+	}
+	for round := 1; len(pending) > 0; round++ {
+		if round > maxRounds {
+			t.Fatalf("%d backends have not converged after %d reconciliation rounds", len(pending), maxRounds)
+		}
+		reconciled := make([]int, 0, len(pending))
+		for i := range pending {
+			reconciled = append(reconciled, i)
+		}
+		var wg sync.WaitGroup
+		var resultsMu sync.Mutex
+		for _, i := range reconciled {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				backend := &fleetnetv1beta1.TrafficManagerBackend{}
+				if err := r.Client.Get(ctx, client.ObjectKeyFromObject(backends[i]), backend); err != nil {
+					t.Errorf("Get() got error %v, want no error", err)
+					return
+				}
+				// The profile of each backend has the endpoints created by its previous reconciliations.
+				profileName := fmt.Sprintf("%s-%d", fakeprovider.ValidProfileName, i)
+				profile := &armtrafficmanager.Profile{Name: ptr.To(profileName), Properties: &armtrafficmanager.ProfileProperties{}}
+				mu.Lock()
+				for j := range created[profileName] {
+					profile.Properties.Endpoints = append(profile.Properties.Endpoints, &created[profileName][j])
+				}
+				mu.Unlock()
+
+				_, _, err := r.withConcurrencyLimiter().updateTrafficManagerEndpointsAndUpdateStatusIfUnknown(ctx, backend, profile, newTestDesiredEndpoints(backend.Name, clusters...))
+				if err != nil && !azureerrors.IsThrottled(err) {
+					t.Errorf("updateTrafficManagerEndpointsAndUpdateStatusIfUnknown() got error %v, want no error or a throttled error", err)
+					return
+				}
+				resultsMu.Lock()
+				defer resultsMu.Unlock()
+				if err != nil {
+					return
+				}
+				delete(pending, i)
+			}(i)
+		}
+		wg.Wait()
+		if t.Failed() {
+			t.FailNow()
+		}
+	}
+
+	if got := r.ConcurrencyLimiter.Limit(); got >= 32 {
+		t.Errorf("Limit() = %d, want the limit shrunk below 32", got)
+	}
+}
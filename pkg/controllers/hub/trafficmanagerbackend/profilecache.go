@@ -0,0 +1,179 @@
+/*
+Copyright (c) Microsoft Corporation.
+Licensed under the MIT license.
+*/
+
+package trafficmanagerbackend
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/trafficmanager/armtrafficmanager"
+	"github.com/prometheus/client_golang/prometheus"
+
+	"go.goms.io/fleet-networking/pkg/common/metrics"
+)
+
+const (
+	profileCacheResultHit  = "hit"
+	profileCacheResultMiss = "miss"
+)
+
+var (
+	// trafficManagerProfileCacheRequests counts the reads of the Azure Traffic Manager profiles served by the profile
+	// cache (hit) or sent to Azure (miss).
+	trafficManagerProfileCacheRequests = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: metrics.MetricsNamespace,
+			Subsystem: metrics.MetricsSubsystem,
+			Name:      "traffic_manager_profile_cache_requests_total",
+			Help:      "The number of the Azure Traffic Manager profile reads of the trafficManagerBackend controller served by the profile cache (hit) or sent to Azure (miss)",
+		},
+		[]string{"result"},
+	)
+)
+
+// profileCacheKey identifies an Azure Traffic Manager profile; the names are lowercased as the Azure resource names
+// are case-insensitive.
+type profileCacheKey struct {
+	resourceGroupName string
+	profileName       string
+}
+
+func newProfileCacheKey(resourceGroupName, profileName string) profileCacheKey {
+	return profileCacheKey{resourceGroupName: strings.ToLower(resourceGroupName), profileName: strings.ToLower(profileName)}
+}
+
+// profileCacheEntry is the cached Azure Traffic Manager profile, in its JSON form so that every reader gets its own
+// copy. The generation is bumped on each invalidation, so that a read sent before an invalidation does not fill the
+// cache once it returns.
+type profileCacheEntry struct {
+	data       []byte
+	expiresAt  time.Time
+	generation uint64
+}
+
+// profileCache is a short-lived read-through cache of the Azure Traffic Manager profiles, so that the backends of a
+// profile, which are enqueued together by a profile event, do not all read the same profile from Azure. The entry of
+// a profile is invalidated whenever the controller changes its endpoints; the changes made by others, including the
+// trafficManagerProfile controller, are only observed once the entry expires. A nil cache caches nothing.
+type profileCache struct {
+	ttl time.Duration
+	now func() time.Time
+
+	mu      sync.Mutex
+	entries map[profileCacheKey]*profileCacheEntry
+}
+
+func newProfileCache(ttl time.Duration) *profileCache {
+	if ttl <= 0 {
+		return nil
+	}
+	return &profileCache{ttl: ttl, now: time.Now, entries: make(map[profileCacheKey]*profileCacheEntry)}
+}
+
+// get returns the Azure Traffic Manager profile from the cache if it has not expired, or reads it with the client and
+// caches it otherwise; the errors are never cached.
+func (c *profileCache) get(ctx context.Context, client ProfilesClient, resourceGroupName, profileName string) (armtrafficmanager.Profile, error) {
+	if c == nil {
+		res, err := client.Get(ctx, resourceGroupName, profileName, nil)
+		return res.Profile, err
+	}
+	key := newProfileCacheKey(resourceGroupName, profileName)
+	c.mu.Lock()
+	entry, ok := c.entries[key]
+	if !ok {
+		entry = &profileCacheEntry{}
+		c.entries[key] = entry
+	}
+	data, generation := entry.data, entry.generation
+	fresh := data != nil && c.now().Before(entry.expiresAt)
+	c.mu.Unlock()
+
+	if fresh {
+		var profile armtrafficmanager.Profile
+		// The data is marshaled from a profile, so it never fails to be unmarshaled.
+		if err := profile.UnmarshalJSON(data); err == nil {
+			trafficManagerProfileCacheRequests.WithLabelValues(profileCacheResultHit).Inc()
+			return profile, nil
+		}
+	}
+
+	trafficManagerProfileCacheRequests.WithLabelValues(profileCacheResultMiss).Inc()
+	res, err := client.Get(ctx, resourceGroupName, profileName, nil)
+	if err != nil {
+		return armtrafficmanager.Profile{}, err
+	}
+	data, err = res.Profile.MarshalJSON()
+	if err != nil {
+		return res.Profile, nil // skip caching the profile which cannot be copied
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if entry.generation == generation {
+		entry.data = data
+		entry.expiresAt = c.now().Add(c.ttl)
+	}
+	return res.Profile, nil
+}
+
+// invalidate drops the cached Azure Traffic Manager profile, including the reads in flight.
+func (c *profileCache) invalidate(resourceGroupName, profileName string) {
+	if c == nil {
+		return
+	}
+	key := newProfileCacheKey(resourceGroupName, profileName)
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	entry, ok := c.entries[key]
+	if !ok {
+		return
+	}
+	entry.data = nil
+	entry.generation++
+}
+
+var (
+	_ ProfilesClient  = &invalidatingProfilesClient{}
+	_ EndpointsClient = &invalidatingEndpointsClient{}
+)
+
+// invalidatingProfilesClient is a ProfilesClient which invalidates the cached profile once it is updated; the reads
+// are passed through, so that the ones which require the latest profile, e.g. for its ETag, bypass the cache.
+type invalidatingProfilesClient struct {
+	ProfilesClient
+	cache *profileCache
+}
+
+// CreateOrUpdate implements the ProfilesClient interface.
+func (c *invalidatingProfilesClient) CreateOrUpdate(ctx context.Context, resourceGroupName string, profileName string, parameters armtrafficmanager.Profile,
+	options *armtrafficmanager.ProfilesClientCreateOrUpdateOptions) (armtrafficmanager.ProfilesClientCreateOrUpdateResponse, error) {
+	// The profile is invalidated even if the request fails, as it may have been applied.
+	defer c.cache.invalidate(resourceGroupName, profileName)
+	return c.ProfilesClient.CreateOrUpdate(ctx, resourceGroupName, profileName, parameters, options)
+}
+
+// invalidatingEndpointsClient is an EndpointsClient which invalidates the cached profile once its endpoints are
+// changed.
+type invalidatingEndpointsClient struct {
+	EndpointsClient
+	cache *profileCache
+}
+
+// CreateOrUpdate implements the EndpointsClient interface.
+func (c *invalidatingEndpointsClient) CreateOrUpdate(ctx context.Context, resourceGroupName string, profileName string, endpointType armtrafficmanager.EndpointType,
+	endpointName string, parameters armtrafficmanager.Endpoint,
+	options *armtrafficmanager.EndpointsClientCreateOrUpdateOptions) (armtrafficmanager.EndpointsClientCreateOrUpdateResponse, error) {
+	defer c.cache.invalidate(resourceGroupName, profileName)
+	return c.EndpointsClient.CreateOrUpdate(ctx, resourceGroupName, profileName, endpointType, endpointName, parameters, options)
+}
+
+// Delete implements the EndpointsClient interface.
+func (c *invalidatingEndpointsClient) Delete(ctx context.Context, resourceGroupName string, profileName string, endpointType armtrafficmanager.EndpointType,
+	endpointName string, options *armtrafficmanager.EndpointsClientDeleteOptions) (armtrafficmanager.EndpointsClientDeleteResponse, error) {
+	defer c.cache.invalidate(resourceGroupName, profileName)
+	return c.EndpointsClient.Delete(ctx, resourceGroupName, profileName, endpointType, endpointName, options)
+}
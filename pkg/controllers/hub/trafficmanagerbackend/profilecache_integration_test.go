@@ -0,0 +1,110 @@
+/*
+Copyright (c) Microsoft Corporation.
+Licensed under the MIT license.
+*/
+
+package trafficmanagerbackend
+
+import (
+	"fmt"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	fleetnetv1beta1 "go.goms.io/fleet-networking/api/v1beta1"
+	"go.goms.io/fleet-networking/test/common/trafficmanager/fakeprovider"
+	"go.goms.io/fleet-networking/test/common/trafficmanager/validator"
+)
+
+const (
+	profileCacheTestNamespace    = "profile-cache-test"
+	profileCacheTestBackendCount = 10
+)
+
+// The profile cache test reconciles the backends of a profile with a reconciler of its own, which counts the reads of
+// the Azure Traffic Manager profile, as the burst of reconciliations a profile event triggers.
+var _ = Describe("Test the Azure Traffic Manager profile cache", Ordered, func() {
+	var profile *fleetnetv1beta1.TrafficManagerProfile
+	var backends []*fleetnetv1beta1.TrafficManagerBackend
+	var profilesClient *countingProfilesClient
+	var r *Reconciler
+
+	reconcileAll := func() {
+		for _, backend := range backends {
+			req := ctrl.Request{NamespacedName: types.NamespacedName{Namespace: backend.Namespace, Name: backend.Name}}
+			// The backends are reconciled by the controller of the suite as well, so the conflicts are retried.
+			Eventually(func() error {
+				_, err := r.Reconcile(ctx, req)
+				return err
+			}, timeout, interval).Should(Succeed(), "failed to reconcile trafficManagerBackend %s", req.NamespacedName)
+		}
+	}
+
+	BeforeAll(func() {
+		By("Creating the profile cache test namespace")
+		ns := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: profileCacheTestNamespace}}
+		Expect(k8sClient.Create(ctx, ns)).Should(Succeed())
+
+		By("Creating a programmed TrafficManagerProfile")
+		profile = trafficManagerProfileForTest(fakeprovider.ValidProfileName)
+		profile.Namespace = profileCacheTestNamespace
+		Expect(k8sClient.Create(ctx, profile)).Should(Succeed())
+		updateTrafficManagerProfileStatusToTrue(ctx, profile)
+
+		By(fmt.Sprintf("Creating %d TrafficManagerBackends of the profile", profileCacheTestBackendCount))
+		for i := 0; i < profileCacheTestBackendCount; i++ {
+			// The serviceImports do not exist, so that the backends do not change any endpoint.
+			backend := trafficManagerBackendForTest(fmt.Sprintf("backend-%02d", i), profile.Name, fmt.Sprintf("not-exist-%02d", i))
+			backend.Namespace = profileCacheTestNamespace
+			Expect(k8sClient.Create(ctx, backend)).Should(Succeed())
+			backends = append(backends, backend)
+		}
+
+		azureProfilesClient, err := fakeprovider.NewProfileClient("default-sub")
+		Expect(err).Should(Succeed(), "failed to create the fake profile client")
+		endpointsClient, err := fakeprovider.NewEndpointsClient("default-sub")
+		Expect(err).Should(Succeed(), "failed to create the fake endpoint client")
+		profilesClient = &countingProfilesClient{ProfilesClient: azureProfilesClient}
+		r = &Reconciler{
+			// The cached client is required by the indexes the reconciler lists the backends with.
+			Client:            mgr.GetClient(),
+			ProfilesClient:    profilesClient,
+			EndpointsClient:   endpointsClient,
+			ResourceGroupName: fakeprovider.DefaultResourceGroupName,
+			profiles:          newProfileCache(time.Minute),
+		}
+	})
+
+	It("Should read the shared profile at most a couple of times per burst", func() {
+		By("Reconciling the backends until they settle")
+		reconcileAll()
+
+		By("Reconciling the backends in a burst")
+		profilesClient.gets.Store(0)
+		reconcileAll()
+		Expect(profilesClient.gets.Load()).Should(BeNumerically("<=", 2), "the backends sharing the profile read it too many times")
+	})
+
+	AfterAll(func() {
+		By("Deleting the TrafficManagerBackends")
+		for _, backend := range backends {
+			Expect(k8sClient.Delete(ctx, backend)).Should(Succeed())
+		}
+		for _, backend := range backends {
+			validator.IsTrafficManagerBackendDeleted(ctx, k8sClient, types.NamespacedName{Namespace: backend.Namespace, Name: backend.Name})
+		}
+
+		By("Deleting the TrafficManagerProfile")
+		Expect(k8sClient.Delete(ctx, profile)).Should(Succeed())
+		validator.IsTrafficManagerProfileDeleted(ctx, k8sClient, types.NamespacedName{Namespace: profile.Namespace, Name: profile.Name})
+
+		By("Deleting the profile cache test namespace")
+		ns := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: profileCacheTestNamespace}}
+		Expect(k8sClient.Delete(ctx, ns)).Should(Succeed())
+	})
+})
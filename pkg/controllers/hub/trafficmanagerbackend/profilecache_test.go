@@ -0,0 +1,236 @@
+/*
+Copyright (c) Microsoft Corporation.
+Licensed under the MIT license.
+*/
+
+package trafficmanagerbackend
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/trafficmanager/armtrafficmanager"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"k8s.io/utils/ptr"
+)
+
+const (
+	cachedResourceGroupName = "rg"
+	cachedProfileName       = "profile"
+)
+
+// countingProfilesClient is a ProfilesClient which counts the Get calls passed on to the wrapped client.
+type countingProfilesClient struct {
+	ProfilesClient
+	gets atomic.Int32
+	// onGet, if set, is called during each Get call, e.g. to change the profile concurrently.
+	onGet func()
+}
+
+func (c *countingProfilesClient) Get(ctx context.Context, resourceGroupName string, profileName string, options *armtrafficmanager.ProfilesClientGetOptions) (armtrafficmanager.ProfilesClientGetResponse, error) {
+	c.gets.Add(1)
+	if c.onGet != nil {
+		c.onGet()
+	}
+	return c.ProfilesClient.Get(ctx, resourceGroupName, profileName, options)
+}
+
+func cachedProfileForTest() armtrafficmanager.Profile {
+	return armtrafficmanager.Profile{
+		Name: ptr.To(cachedProfileName),
+		Properties: &armtrafficmanager.ProfileProperties{
+			Endpoints: []*armtrafficmanager.Endpoint{{Name: ptr.To("endpoint")}},
+		},
+	}
+}
+
+// fakeClock is a clock which only moves forward when advanced.
+type fakeClock struct {
+	now time.Time
+}
+
+func (c *fakeClock) Now() time.Time { return c.now }
+
+func newProfileCacheForTest(ttl time.Duration) (*profileCache, *fakeClock) {
+	clock := &fakeClock{now: time.Now()}
+	cache := newProfileCache(ttl)
+	cache.now = clock.Now
+	return cache, clock
+}
+
+func TestProfileCache_Hit(t *testing.T) {
+	ctx := context.Background()
+	cache, _ := newProfileCacheForTest(time.Minute)
+	client := &countingProfilesClient{ProfilesClient: &fakeProfilesClient{profile: cachedProfileForTest()}}
+	hits := testutil.ToFloat64(trafficManagerProfileCacheRequests.WithLabelValues(profileCacheResultHit))
+	misses := testutil.ToFloat64(trafficManagerProfileCacheRequests.WithLabelValues(profileCacheResultMiss))
+
+	first, err := cache.get(ctx, client, cachedResourceGroupName, cachedProfileName)
+	if err != nil {
+		t.Fatalf("get() = %v, want no error", err)
+	}
+	// The callers own the returned profiles; changing them must not change the cached one.
+	first.Properties.Endpoints = nil
+	// The Azure resource names are case-insensitive.
+	second, err := cache.get(ctx, client, "RG", "Profile")
+	if err != nil {
+		t.Fatalf("get() = %v, want no error", err)
+	}
+	if got := client.gets.Load(); got != 1 {
+		t.Errorf("Get() called %d times, want 1", got)
+	}
+	if second.Properties == nil || len(second.Properties.Endpoints) != 1 {
+		t.Errorf("get() = %+v from the cache, want the profile with 1 endpoint", second.Properties)
+	}
+	if got := testutil.ToFloat64(trafficManagerProfileCacheRequests.WithLabelValues(profileCacheResultHit)) - hits; got != 1 {
+		t.Errorf("trafficManagerProfileCacheRequests{result=hit} increased by %v, want 1", got)
+	}
+	if got := testutil.ToFloat64(trafficManagerProfileCacheRequests.WithLabelValues(profileCacheResultMiss)) - misses; got != 1 {
+		t.Errorf("trafficManagerProfileCacheRequests{result=miss} increased by %v, want 1", got)
+	}
+}
+
+func TestProfileCache_Expiry(t *testing.T) {
+	ctx := context.Background()
+	cache, clock := newProfileCacheForTest(5 * time.Second)
+	client := &countingProfilesClient{ProfilesClient: &fakeProfilesClient{profile: cachedProfileForTest()}}
+
+	for _, advance := range []time.Duration{0, 4 * time.Second, time.Second, time.Second} {
+		clock.now = clock.now.Add(advance)
+		if _, err := cache.get(ctx, client, cachedResourceGroupName, cachedProfileName); err != nil {
+			t.Fatalf("get() = %v, want no error", err)
+		}
+	}
+	// The profile is read at 0s, served from the cache at 4s, read again at 5s once expired, and served from the
+	// cache at 6s.
+	if got := client.gets.Load(); got != 2 {
+		t.Errorf("Get() called %d times, want 2", got)
+	}
+}
+
+func TestProfileCache_InvalidationOnWrite(t *testing.T) {
+	tests := []struct {
+		name  string
+		write func(ctx context.Context, profiles ProfilesClient, endpoints EndpointsClient) error
+	}{
+		{
+			name: "endpoint created or updated",
+			write: func(ctx context.Context, _ ProfilesClient, endpoints EndpointsClient) error {
+				_, err := endpoints.CreateOrUpdate(ctx, cachedResourceGroupName, cachedProfileName, armtrafficmanager.EndpointTypeAzureEndpoints, "endpoint", armtrafficmanager.Endpoint{}, nil)
+				return err
+			},
+		},
+		{
+			name: "endpoint deleted",
+			write: func(ctx context.Context, _ ProfilesClient, endpoints EndpointsClient) error {
+				_, err := endpoints.Delete(ctx, cachedResourceGroupName, cachedProfileName, armtrafficmanager.EndpointTypeAzureEndpoints, "endpoint", nil)
+				return err
+			},
+		},
+		{
+			name: "endpoint deletion failed",
+			write: func(ctx context.Context, _ ProfilesClient, endpoints EndpointsClient) error {
+				_, err := endpoints.Delete(ctx, cachedResourceGroupName, cachedProfileName, armtrafficmanager.EndpointTypeAzureEndpoints, "failing-endpoint", nil)
+				if err == nil {
+					return errors.New("want the deletion to fail")
+				}
+				return nil
+			},
+		},
+		{
+			name: "endpoints updated with the profile",
+			write: func(ctx context.Context, profiles ProfilesClient, _ EndpointsClient) error {
+				_, err := profiles.CreateOrUpdate(ctx, cachedResourceGroupName, cachedProfileName, cachedProfileForTest(), nil)
+				return err
+			},
+		},
+		{
+			name: "endpoint of the profile in different case updated",
+			write: func(ctx context.Context, _ ProfilesClient, endpoints EndpointsClient) error {
+				_, err := endpoints.CreateOrUpdate(ctx, "RG", "PROFILE", armtrafficmanager.EndpointTypeAzureEndpoints, "endpoint", armtrafficmanager.Endpoint{}, nil)
+				return err
+			},
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx := context.Background()
+			cache, _ := newProfileCacheForTest(time.Minute)
+			client := &countingProfilesClient{ProfilesClient: &fakeProfilesClient{profile: cachedProfileForTest()}}
+			profiles := &invalidatingProfilesClient{ProfilesClient: client, cache: cache}
+			endpoints := &invalidatingEndpointsClient{
+				EndpointsClient: &fakeEndpointsClient{deleteErrs: map[string]error{"failing-endpoint": errors.New("delete error")}},
+				cache:           cache,
+			}
+
+			if _, err := cache.get(ctx, profiles, cachedResourceGroupName, cachedProfileName); err != nil {
+				t.Fatalf("get() = %v, want no error", err)
+			}
+			if err := tc.write(ctx, profiles, endpoints); err != nil {
+				t.Fatalf("write = %v, want no error", err)
+			}
+			if _, err := cache.get(ctx, profiles, cachedResourceGroupName, cachedProfileName); err != nil {
+				t.Fatalf("get() = %v, want no error", err)
+			}
+			if got := client.gets.Load(); got != 2 {
+				t.Errorf("Get() called %d times, want 2 as the cached profile is invalidated", got)
+			}
+		})
+	}
+}
+
+// TestProfileCache_InvalidationInFlight tests that a profile read before a concurrent write returns is not cached.
+func TestProfileCache_InvalidationInFlight(t *testing.T) {
+	ctx := context.Background()
+	cache, _ := newProfileCacheForTest(time.Minute)
+	client := &countingProfilesClient{ProfilesClient: &fakeProfilesClient{profile: cachedProfileForTest()}}
+	client.onGet = func() {
+		client.onGet = nil
+		cache.invalidate(cachedResourceGroupName, cachedProfileName)
+	}
+
+	for i := 0; i < 3; i++ {
+		if _, err := cache.get(ctx, client, cachedResourceGroupName, cachedProfileName); err != nil {
+			t.Fatalf("get() = %v, want no error", err)
+		}
+	}
+	if got := client.gets.Load(); got != 2 {
+		t.Errorf("Get() called %d times, want 2", got)
+	}
+}
+
+func TestProfileCache_ErrorNotCached(t *testing.T) {
+	ctx := context.Background()
+	cache, _ := newProfileCacheForTest(time.Minute)
+	client := &countingProfilesClient{ProfilesClient: &fakeProfilesClient{getErr: errors.New("get error")}}
+
+	for i := 0; i < 2; i++ {
+		if _, err := cache.get(ctx, client, cachedResourceGroupName, cachedProfileName); err == nil {
+			t.Fatalf("get() = nil, want error")
+		}
+	}
+	if got := client.gets.Load(); got != 2 {
+		t.Errorf("Get() called %d times, want 2", got)
+	}
+}
+
+func TestProfileCache_Disabled(t *testing.T) {
+	ctx := context.Background()
+	cache := newProfileCache(0)
+	if cache != nil {
+		t.Fatalf("newProfileCache(0) = %v, want nil", cache)
+	}
+	client := &countingProfilesClient{ProfilesClient: &fakeProfilesClient{profile: cachedProfileForTest()}}
+	for i := 0; i < 2; i++ {
+		if _, err := cache.get(ctx, client, cachedResourceGroupName, cachedProfileName); err != nil {
+			t.Fatalf("get() = %v, want no error", err)
+		}
+	}
+	cache.invalidate(cachedResourceGroupName, cachedProfileName)
+	if got := client.gets.Load(); got != 2 {
+		t.Errorf("Get() called %d times, want 2", got)
+	}
+}
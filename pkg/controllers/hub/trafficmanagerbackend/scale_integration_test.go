@@ -0,0 +1,132 @@
+/*
+Copyright (c) Microsoft Corporation.
+Licensed under the MIT license.
+*/
+
+package trafficmanagerbackend
+
+import (
+	"fmt"
+	"os"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/onsi/gomega/gmeasure"
+	"golang.org/x/sync/errgroup"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	fleetnetv1beta1 "go.goms.io/fleet-networking/api/v1beta1"
+)
+
+const (
+	// runScaleTestsEnv enables the scale tests, which seed thousands of objects and take minutes to run.
+	runScaleTestsEnv = "RUN_SCALE_TESTS"
+
+	scaleTestNamespace     = "scale-test"
+	scaleTestBackendCount  = 5000
+	scaleTestProfileCount  = 50
+	scaleTestSeedingWorker = 20
+	scaleTestSampleCount   = 20
+)
+
+// The scale tests measure the latency of the trafficManagerProfile event handler, which enqueues the backends of a
+// profile, against the baselines of listing the backends of the namespace and of listing the backends by the index
+// with deep copies, which the handler used to do.
+var _ = Describe("Test the trafficManagerBackend event handlers at scale", Ordered, func() {
+	BeforeAll(func() {
+		if os.Getenv(runScaleTestsEnv) != "true" {
+			Skip(fmt.Sprintf("Skipping the scale tests; set %s=true to run them", runScaleTestsEnv))
+		}
+
+		By("Creating the scale test namespace")
+		ns := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: scaleTestNamespace}}
+		Expect(k8sClient.Create(ctx, ns)).Should(Succeed())
+
+		By(fmt.Sprintf("Seeding %d trafficManagerBackends", scaleTestBackendCount))
+		g := &errgroup.Group{}
+		g.SetLimit(scaleTestSeedingWorker)
+		for i := 0; i < scaleTestBackendCount; i++ {
+			backend := &fleetnetv1beta1.TrafficManagerBackend{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      fmt.Sprintf("backend-%04d", i),
+					Namespace: scaleTestNamespace,
+				},
+				Spec: fleetnetv1beta1.TrafficManagerBackendSpec{
+					Profile: fleetnetv1beta1.TrafficManagerProfileRef{
+						Name: fmt.Sprintf("profile-%02d", i%scaleTestProfileCount),
+					},
+					Backend: fleetnetv1beta1.TrafficManagerBackendRef{
+						Name: fmt.Sprintf("svc-%04d", i),
+					},
+					Weight: ptr.To(int64(10)),
+				},
+			}
+			g.Go(func() error {
+				return k8sClient.Create(ctx, backend)
+			})
+		}
+		Expect(g.Wait()).Should(Succeed(), "failed to seed the trafficManagerBackends")
+
+		By("Waiting for the cache to catch up with the seeded trafficManagerBackends")
+		Eventually(func() (int, error) {
+			backendList := &fleetnetv1beta1.TrafficManagerBackendList{}
+			if err := mgr.GetClient().List(ctx, backendList, client.InNamespace(scaleTestNamespace)); err != nil {
+				return 0, err
+			}
+			return len(backendList.Items), nil
+		}, timeout*6, interval).Should(Equal(scaleTestBackendCount), "cache has not caught up with the seeded trafficManagerBackends")
+	})
+
+	AfterAll(func() {
+		By("Deleting the seeded trafficManagerBackends")
+		Expect(client.IgnoreNotFound(k8sClient.DeleteAllOf(ctx, &fleetnetv1beta1.TrafficManagerBackend{}, client.InNamespace(scaleTestNamespace)))).Should(Succeed())
+	})
+
+	It("Should enqueue the backends of a profile faster than listing the backends of the namespace", func() {
+		r := &Reconciler{Client: mgr.GetClient()}
+		profile := &fleetnetv1beta1.TrafficManagerProfile{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "profile-00",
+				Namespace: scaleTestNamespace,
+			},
+		}
+		wantCount := scaleTestBackendCount / scaleTestProfileCount
+
+		experiment := gmeasure.NewExperiment("trafficManagerProfile event handler")
+		AddReportEntry(experiment.Name, experiment)
+		experiment.Sample(func(_ int) {
+			experiment.MeasureDuration("namespace-wide list", func() {
+				backendList := &fleetnetv1beta1.TrafficManagerBackendList{}
+				Expect(r.Client.List(ctx, backendList, client.InNamespace(scaleTestNamespace))).Should(Succeed())
+				count := 0
+				for i := range backendList.Items {
+					if backendList.Items[i].Spec.Profile.Name == profile.Name {
+						count++
+					}
+				}
+				Expect(count).Should(Equal(wantCount))
+			})
+			experiment.MeasureDuration("indexed list", func() {
+				backendList := &fleetnetv1beta1.TrafficManagerBackendList{}
+				Expect(r.Client.List(ctx, backendList, client.InNamespace(scaleTestNamespace),
+					client.MatchingFields{trafficManagerBackendProfileFieldKey: profile.Name})).Should(Succeed())
+				Expect(backendList.Items).Should(HaveLen(wantCount))
+			})
+			experiment.MeasureDuration("event handler", func() {
+				Expect(r.trafficManagerProfileEventHandler()(ctx, profile)).Should(HaveLen(wantCount))
+			})
+		}, gmeasure.SamplingConfig{N: scaleTestSampleCount})
+
+		for _, name := range []string{"namespace-wide list", "indexed list", "event handler"} {
+			stats := experiment.GetStats(name)
+			fmt.Fprintf(GinkgoWriter, "%s: median %v, max %v\n", name,
+				stats.DurationFor(gmeasure.StatMedian), stats.DurationFor(gmeasure.StatMax))
+		}
+		Expect(experiment.GetStats("event handler").DurationFor(gmeasure.StatMedian)).Should(
+			BeNumerically("<", experiment.GetStats("namespace-wide list").DurationFor(gmeasure.StatMedian)),
+			"the event handler should be faster than listing the backends of the namespace")
+	})
+})
@@ -11,6 +11,7 @@ import (
 	"fmt"
 	"path/filepath"
 	"testing"
+	"time"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
@@ -30,6 +31,8 @@ import (
 
 	fleetnetv1alpha1 "go.goms.io/fleet-networking/api/v1alpha1"
 	fleetnetv1beta1 "go.goms.io/fleet-networking/api/v1beta1"
+	"go.goms.io/fleet-networking/pkg/controllers/member/serviceexport"
+	"go.goms.io/fleet-networking/test/common/fleetenv"
 	"go.goms.io/fleet-networking/test/common/trafficmanager/fakeprovider"
 )
 
@@ -46,175 +49,61 @@ var (
 	originalGenerateAzureTrafficManagerProfileNameFunc        = generateAzureTrafficManagerProfileNameFunc
 	originalGenerateAzureTrafficManagerEndpointNamePrefixFunc = generateAzureTrafficManagerEndpointNamePrefixFunc
 
-	memberClusterNames     = []string{fakeprovider.ClusterName, "member-2", "member-3", "member-4", fakeprovider.CreateBadRequestErrEndpointClusterName, fakeprovider.CreateInternalServerErrEndpointClusterName}
-	internalServiceExports = []fleetnetv1alpha1.InternalServiceExport{
-		{
-			ObjectMeta: metav1.ObjectMeta{
-				Name:      "valid-endpoint",
-				Namespace: memberClusterNames[0],
-			},
-			Spec: fleetnetv1alpha1.InternalServiceExportSpec{
-				Ports: []fleetnetv1alpha1.ServicePort{
-					{
-						Name:       "portA",
-						Protocol:   "TCP",
-						Port:       8080,
-						TargetPort: intstr.IntOrString{IntVal: 8080},
-					},
-				},
-				ServiceReference: fleetnetv1alpha1.ExportedObjectReference{
-					ClusterID:       memberClusterNames[0],
-					Kind:            "Service",
-					Namespace:       testNamespace,
-					Name:            serviceName,
-					ResourceVersion: "0",
-					Generation:      0,
-					UID:             "0",
-					NamespacedName:  fmt.Sprintf("%s/%s", testNamespace, serviceName),
-				},
-				Type:                 corev1.ServiceTypeLoadBalancer,
-				IsDNSLabelConfigured: true,
-			},
-		},
-		{
-			ObjectMeta: metav1.ObjectMeta{
-				Name:      "invalid-endpoint",
-				Namespace: memberClusterNames[1],
-			},
-			Spec: fleetnetv1alpha1.InternalServiceExportSpec{
-				Ports: []fleetnetv1alpha1.ServicePort{
-					{
-						Name:       "portA",
-						Protocol:   "TCP",
-						Port:       8080,
-						TargetPort: intstr.IntOrString{IntVal: 8080},
-					},
-				},
-				ServiceReference: fleetnetv1alpha1.ExportedObjectReference{
-					ClusterID:       memberClusterNames[1],
-					Kind:            "Service",
-					Namespace:       testNamespace,
-					Name:            serviceName,
-					ResourceVersion: "0",
-					Generation:      0,
-					UID:             "0",
-					NamespacedName:  fmt.Sprintf("%s/%s", testNamespace, serviceName),
-				},
-				Type: corev1.ServiceTypeLoadBalancer,
-			},
-		},
-		{
-			ObjectMeta: metav1.ObjectMeta{
-				Name:      "other-service",
-				Namespace: memberClusterNames[2],
-			},
-			Spec: fleetnetv1alpha1.InternalServiceExportSpec{
-				Ports: []fleetnetv1alpha1.ServicePort{
-					{
-						Name:       "portA",
-						Protocol:   "TCP",
-						Port:       8080,
-						TargetPort: intstr.IntOrString{IntVal: 8080},
-					},
-				},
-				ServiceReference: fleetnetv1alpha1.ExportedObjectReference{
-					ClusterID:       memberClusterNames[2],
-					Kind:            "Service",
-					Namespace:       testNamespace,
-					Name:            "other-service",
-					ResourceVersion: "0",
-					Generation:      0,
-					UID:             "0",
-					NamespacedName:  fmt.Sprintf("%s/%s", testNamespace, "other-service"),
-				},
-			},
-		},
-		{
-			ObjectMeta: metav1.ObjectMeta{
-				Name:      "endpoint-not-in-the-atm-profile",
-				Namespace: memberClusterNames[3],
-			},
-			Spec: fleetnetv1alpha1.InternalServiceExportSpec{
-				Ports: []fleetnetv1alpha1.ServicePort{
-					{
-						Name:       "portA",
-						Protocol:   "TCP",
-						Port:       8080,
-						TargetPort: intstr.IntOrString{IntVal: 8080},
-					},
-				},
-				ServiceReference: fleetnetv1alpha1.ExportedObjectReference{
-					ClusterID:       memberClusterNames[3],
-					Kind:            "Service",
-					Namespace:       testNamespace,
-					Name:            serviceName,
-					ResourceVersion: "0",
-					Generation:      0,
-					UID:             "0",
-					NamespacedName:  fmt.Sprintf("%s/%s", testNamespace, serviceName),
-				},
-				Type:                 corev1.ServiceTypeLoadBalancer,
-				IsDNSLabelConfigured: true,
-			},
-		},
-		{
-			ObjectMeta: metav1.ObjectMeta{
-				Name:      "endpoint-create-bad-request-err",
-				Namespace: memberClusterNames[4],
-			},
-			Spec: fleetnetv1alpha1.InternalServiceExportSpec{
-				Ports: []fleetnetv1alpha1.ServicePort{
-					{
-						Name:       "portA",
-						Protocol:   "TCP",
-						Port:       8080,
-						TargetPort: intstr.IntOrString{IntVal: 8080},
-					},
-				},
-				ServiceReference: fleetnetv1alpha1.ExportedObjectReference{
-					ClusterID:       memberClusterNames[4],
-					Kind:            "Service",
-					Namespace:       testNamespace,
-					Name:            serviceName,
-					ResourceVersion: "0",
-					Generation:      0,
-					UID:             "0",
-					NamespacedName:  fmt.Sprintf("%s/%s", testNamespace, serviceName),
-				},
-				Type:                 corev1.ServiceTypeLoadBalancer,
-				IsDNSLabelConfigured: true,
-			},
+	memberClusterNames = []string{fakeprovider.ClusterName, "member-2", "member-3", "member-4", fakeprovider.CreateBadRequestErrEndpointClusterName, fakeprovider.CreateInternalServerErrEndpointClusterName}
+	// memberCloudProviders are the cloud providers of the simulated member clusters: the service exported from the
+	// member-2 cluster has no DNS label configured, and the member-3 cluster does not report the Traffic Manager
+	// related information at all.
+	memberCloudProviders = []serviceexport.CloudProvider{
+		fleetenv.CloudProvider{IsDNSLabelConfigured: true},
+		fleetenv.CloudProvider{},
+		nil,
+		fleetenv.CloudProvider{IsDNSLabelConfigured: true},
+		fleetenv.CloudProvider{IsDNSLabelConfigured: true},
+		fleetenv.CloudProvider{IsDNSLabelConfigured: true},
+	}
+	// exportedServices are the services exported from the simulated member clusters respectively.
+	exportedServices = []*corev1.Service{
+		loadBalancerServiceForTest(serviceName),
+		loadBalancerServiceForTest(serviceName),
+		clusterIPServiceForTest("other-service"),
+		loadBalancerServiceForTest(serviceName),
+		loadBalancerServiceForTest(serviceName),
+		loadBalancerServiceForTest(serviceName),
+	}
+
+	members                []*fleetenv.Member
+	internalServiceExports []*fleetnetv1alpha1.InternalServiceExport
+)
+
+// clusterIPServiceForTest returns a service of the ClusterIP type in the test namespace.
+func clusterIPServiceForTest(name string) *corev1.Service {
+	return &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: testNamespace,
 		},
-		{
-			ObjectMeta: metav1.ObjectMeta{
-				Name:      "endpoint-create-internal-server-err",
-				Namespace: memberClusterNames[5],
-			},
-			Spec: fleetnetv1alpha1.InternalServiceExportSpec{
-				Ports: []fleetnetv1alpha1.ServicePort{
-					{
-						Name:       "portA",
-						Protocol:   "TCP",
-						Port:       8080,
-						TargetPort: intstr.IntOrString{IntVal: 8080},
-					},
-				},
-				ServiceReference: fleetnetv1alpha1.ExportedObjectReference{
-					ClusterID:       memberClusterNames[5],
-					Kind:            "Service",
-					Namespace:       testNamespace,
-					Name:            serviceName,
-					ResourceVersion: "0",
-					Generation:      0,
-					UID:             "0",
-					NamespacedName:  fmt.Sprintf("%s/%s", testNamespace, serviceName),
+		Spec: corev1.ServiceSpec{
+			Type: corev1.ServiceTypeClusterIP,
+			Ports: []corev1.ServicePort{
+				{
+					Name:       "portA",
+					Protocol:   corev1.ProtocolTCP,
+					Port:       8080,
+					TargetPort: intstr.IntOrString{IntVal: 8080},
 				},
-				Type:                 corev1.ServiceTypeLoadBalancer,
-				IsDNSLabelConfigured: true,
 			},
 		},
 	}
-)
+}
+
+// loadBalancerServiceForTest returns a service of the LoadBalancer type in the test namespace, whose load balancer IP
+// has been provisioned.
+func loadBalancerServiceForTest(name string) *corev1.Service {
+	svc := clusterIPServiceForTest(name)
+	svc.Spec.Type = corev1.ServiceTypeLoadBalancer
+	svc.Status.LoadBalancer.Ingress = []corev1.LoadBalancerIngress{{IP: "10.0.0.1"}}
+	return svc
+}
 
 func TestAPIs(t *testing.T) {
 	RegisterFailHandler(Fail)
@@ -279,10 +168,11 @@ var _ = BeforeSuite(func() {
 
 	ctx, cancel = context.WithCancel(context.TODO())
 	err = (&Reconciler{
-		Client:            mgr.GetClient(),
-		ProfilesClient:    profileClient,
-		EndpointsClient:   endpointClient,
-		ResourceGroupName: fakeprovider.DefaultResourceGroupName,
+		Client:             mgr.GetClient(),
+		ProfilesClient:     profileClient,
+		EndpointsClient:    endpointClient,
+		ResourceGroupName:  fakeprovider.DefaultResourceGroupName,
+		MaxExportStaleness: time.Minute,
 	}).SetupWithManager(ctx, mgr, false)
 	Expect(err).ToNot(HaveOccurred())
 
@@ -295,16 +185,14 @@ var _ = BeforeSuite(func() {
 	Expect(k8sClient.Create(ctx, &ns)).Should(Succeed())
 
 	for i, name := range memberClusterNames {
-		By(fmt.Sprintf("Create member cluster system namespace %v", name))
-		ns = corev1.Namespace{
-			ObjectMeta: metav1.ObjectMeta{
-				Name: name,
-			},
-		}
-		Expect(k8sClient.Create(ctx, &ns)).Should(Succeed())
-
-		By(fmt.Sprintf("Create internalServiceExport %v", internalServiceExports[i].Name))
-		Expect(k8sClient.Create(ctx, &internalServiceExports[i])).Should(Succeed(), "failed to create internalServiceExport")
+		By(fmt.Sprintf("Create simulated member cluster %v", name))
+		member := fleetenv.CreateSimulatedMember(ctx, k8sClient, name)
+		member.CloudProvider = memberCloudProviders[i]
+		members = append(members, member)
+
+		By(fmt.Sprintf("Export service %v from member cluster %v", exportedServices[i].Name, name))
+		exported := fleetenv.ExportService(ctx, k8sClient, member, exportedServices[i], nil)
+		internalServiceExports = append(internalServiceExports, exported.InternalServiceExport)
 	}
 
 	go func() {
@@ -317,18 +205,8 @@ var _ = BeforeSuite(func() {
 var _ = AfterSuite(func() {
 	defer klog.Flush()
 
-	for i, name := range memberClusterNames {
-		By(fmt.Sprintf("Delete internalServiceExport %v", internalServiceExports[i].Name))
-		Expect(k8sClient.Delete(ctx, &internalServiceExports[i])).Should(Succeed(), "failed to delete internalServiceExport")
-
-		By(fmt.Sprintf("Delete member cluster system namespace %v", name))
-		ns := corev1.Namespace{
-			ObjectMeta: metav1.ObjectMeta{
-				Name: name,
-			},
-		}
-		Expect(k8sClient.Delete(ctx, &ns)).Should(Succeed())
-	}
+	By("Delete simulated member clusters")
+	fleetenv.DeleteSimulatedMembers(ctx, k8sClient, members)
 
 	By("delete profile namespace")
 	ns := corev1.Namespace{
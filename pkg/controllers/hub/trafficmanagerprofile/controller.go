@@ -10,31 +10,51 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"sync"
 	"time"
 
 	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
 	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/trafficmanager/armtrafficmanager"
+	"github.com/prometheus/client_golang/prometheus"
+	corev1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
 	"k8s.io/klog/v2"
 	"k8s.io/utils/ptr"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	ctrlmetrics "sigs.k8s.io/controller-runtime/pkg/metrics"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	"go.goms.io/fleet/pkg/utils/controller"
 
+	fleetnetv1alpha1 "go.goms.io/fleet-networking/api/v1alpha1"
 	fleetnetv1beta1 "go.goms.io/fleet-networking/api/v1beta1"
 	"go.goms.io/fleet-networking/pkg/common/azureerrors"
+	"go.goms.io/fleet-networking/pkg/common/cloudconfig"
+	"go.goms.io/fleet-networking/pkg/common/condition"
 	"go.goms.io/fleet-networking/pkg/common/defaulter"
+	"go.goms.io/fleet-networking/pkg/common/listguard"
+	"go.goms.io/fleet-networking/pkg/common/metrics"
+	"go.goms.io/fleet-networking/pkg/common/nsallowlist"
 	"go.goms.io/fleet-networking/pkg/common/objectmeta"
+	"go.goms.io/fleet-networking/pkg/common/reconcilewatchdog"
 )
 
 const (
+	// ControllerName is the name of the Reconciler.
+	ControllerName = "trafficmanagerprofile-controller"
+
 	// DNSRelativeNameFormat consists of "Profile-Namespace" and "Profile-Name".
 	DNSRelativeNameFormat = "%s-%s"
+	// DNSNameFormat is the format of the fully qualified domain name of an Azure Traffic Manager profile, which
+	// consists of its relative DNS name.
+	DNSNameFormat = "%s.trafficmanager.net"
 	// AzureResourceProfileNameFormat is the name format of the Azure Traffic Manager Profile created by the fleet controller.
 	AzureResourceProfileNameFormat = "fleet-%s"
 
@@ -42,9 +62,84 @@ const (
 	// provided by this Traffic Manager profile.
 	// Defaults to 60 which is the same as the portal's default config.
 	DefaultDNSTTL = int64(60)
+
+	// DefaultLastSyncedTimeUpdateInterval is the default granularity of the last synced time of the
+	// trafficManagerProfiles and trafficManagerBackends.
+	DefaultLastSyncedTimeUpdateInterval = time.Minute
+
+	// DefaultDNSNameAvailabilityCacheTTL is the default duration for which the result of the check of the availability
+	// of a relative DNS name is reused.
+	DefaultDNSNameAvailabilityCacheTTL = 5 * time.Minute
+
+	// dnsNameNotAvailableRetryInterval is the interval at which a profile whose relative DNS name is not available is
+	// reconciled again, as the name may be released by its owner.
+	dnsNameNotAvailableRetryInterval = 5 * time.Minute
+	// azureTrafficManagerProfileResourceType is the resource type of the Azure Traffic Manager profiles.
+	azureTrafficManagerProfileResourceType = "Microsoft.Network/trafficManagerProfiles"
+
+	// profileEventReasonProgrammed is the reason of the event emitted when the Azure Traffic Manager profile is
+	// programmed for a new generation of the trafficManagerProfile.
+	profileEventReasonProgrammed = "Programmed"
+	// profileEventReasonDeleted is the reason of the event emitted when the Azure Traffic Manager profile is deleted.
+	profileEventReasonDeleted = "Deleted"
+	// profileEventReasonAzureAPIError is the reason of the event emitted when an Azure Traffic Manager request fails.
+	profileEventReasonAzureAPIError = "AzureAPIError"
+	// profileEventReasonDNSNameUnavailable is the reason of the event emitted when the relative DNS name of the
+	// profile is taken by another Azure Traffic Manager profile.
+	profileEventReasonDNSNameUnavailable = "DNSNameUnavailable"
+	// profileEventReasonReadOnly is the reason of the event emitted when the finalizer of a deleted profile is kept, as
+	// its Azure Traffic Manager profile cannot be deleted in the read-only mode.
+	profileEventReasonReadOnly = "ReadOnly"
+	// profileEventReasonNamespaceNotAllowed is the reason of the event emitted when the profile is refused as its
+	// namespace is not in the allowlist.
+	profileEventReasonNamespaceNotAllowed = "NamespaceNotAllowed"
+)
+
+var (
+	// trafficManagerProfileLastSyncedTime reports the last synced time of each trafficManagerProfile, so that alerts
+	// can fire on the time since its Azure Traffic Manager profile was last reconciled successfully.
+	trafficManagerProfileLastSyncedTime = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: metrics.MetricsNamespace,
+			Subsystem: metrics.MetricsSubsystem,
+			Name:      "traffic_manager_profile_last_synced_timestamp_seconds",
+			Help:      "The Unix time of the last reconciliation of a trafficManagerProfile which completed all of its Azure Traffic Manager operations successfully",
+		},
+		[]string{"namespace", "profile"},
+	)
+
+	// trafficManagerProfileMonitorStatus reports the monitor status of each trafficManagerProfile: the series of its
+	// current status is set to 1, and the series of its other statuses are removed.
+	trafficManagerProfileMonitorStatus = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: metrics.MetricsNamespace,
+			Subsystem: metrics.MetricsSubsystem,
+			Name:      "traffic_manager_profile_monitor_status",
+			Help:      "The profile-level monitor status of the Azure Traffic Manager profile of a trafficManagerProfile, set to 1 for the current status",
+		},
+		[]string{"namespace", "profile", "status"},
+	)
 )
 
+func init() {
+	// Register trafficManagerProfileLastSyncedTime (fleet_networking_traffic_manager_profile_last_synced_timestamp_seconds)
+	// metric with the controller runtime global metrics registry.
+	ctrlmetrics.Registry.MustRegister(trafficManagerProfileLastSyncedTime)
+	// Register trafficManagerProfileMonitorStatus (fleet_networking_traffic_manager_profile_monitor_status) metric with
+	// the controller runtime global metrics registry.
+	ctrlmetrics.Registry.MustRegister(trafficManagerProfileMonitorStatus)
+}
+
 var (
+	// errMonitorPortNotInheritable is returned when the monitor port cannot be inherited from the backends.
+	errMonitorPortNotInheritable = errors.New("cannot inherit the monitor port from the backends")
+	// errDNSNameNotAvailable is returned when the relative DNS name of the profile is reported as taken by the name
+	// availability check.
+	errDNSNameNotAvailable = errors.New("the relative DNS name is not available")
+	// errReadOnly is returned when creating or updating the Azure Traffic Manager profile is skipped in the read-only
+	// mode.
+	errReadOnly = errors.New("read-only mode")
+
 	// create the func as a variable so that the integration test can use a customized function.
 	generateAzureTrafficManagerProfileNameFunc = func(profile *fleetnetv1beta1.TrafficManagerProfile) string {
 		return GenerateAzureTrafficManagerProfileName(profile)
@@ -56,17 +151,109 @@ func GenerateAzureTrafficManagerProfileName(profile *fleetnetv1beta1.TrafficMana
 	return fmt.Sprintf(AzureResourceProfileNameFormat, profile.UID)
 }
 
+// NextLastSyncedTime returns the last synced time to record after a successful synchronization at now: the current
+// one is kept if it is more recent than the update interval, so that a busy object does not write its status on every
+// reconciliation. DefaultLastSyncedTimeUpdateInterval is used when the interval is not positive.
+func NextLastSyncedTime(current *metav1.Time, now time.Time, interval time.Duration) *metav1.Time {
+	if interval <= 0 {
+		interval = DefaultLastSyncedTimeUpdateInterval
+	}
+	if current != nil && now.Sub(current.Time) < interval {
+		return current
+	}
+	return &metav1.Time{Time: now}
+}
+
 // Reconciler reconciles a TrafficManagerProfile object.
 type Reconciler struct {
 	client.Client
 
 	ProfilesClient    *armtrafficmanager.ProfilesClient
 	ResourceGroupName string // default resource group name to create azure traffic manager profiles
+
+	// AzureClients, if set, reloads the Azure clients when the cloud config changes; the ProfilesClient and the
+	// ResourceGroupName are then taken from the latest cloud config at the start of each reconciliation.
+	AzureClients *cloudconfig.Reloader[cloudconfig.TrafficManagerClients]
+
+	// LastSyncedTimeUpdateInterval is the granularity of the last synced time in the status of the profiles;
+	// DefaultLastSyncedTimeUpdateInterval is used when it is not positive.
+	LastSyncedTimeUpdateInterval time.Duration
+
+	// CheckDNSNameAvailability, if set, checks with Azure that the relative DNS name of a profile is available before
+	// its Azure Traffic Manager profile is first created, so that a taken name is reported without a failed creation.
+	CheckDNSNameAvailability bool
+	// DNSNameAvailabilityCacheTTL is the duration for which the result of the check of a relative DNS name is reused;
+	// DefaultDNSNameAvailabilityCacheTTL is used when it is not positive.
+	DNSNameAvailabilityCacheTTL time.Duration
+
+	// MonitorStatusResyncInterval is the interval at which a profile whose Azure Traffic Manager profile has been
+	// observed is reconciled again, so that the monitor status in its status follows the one reported by Azure; the
+	// profiles are not resynced when it is not positive.
+	MonitorStatusResyncInterval time.Duration
+
+	// ReadOnly, if set, only reads the Azure Traffic Manager profiles: the profiles are not created, updated or
+	// deleted, and the requests which would have been sent are logged and reported in the Programmed condition
+	// instead. The finalizer of a deleted profile is kept until its Azure Traffic Manager profile is gone.
+	ReadOnly bool
+
+	// AllowedNamespaces are the namespaces whose profiles are programmed; the profiles of the other namespaces are
+	// refused without calling Azure. All the namespaces are allowed when it is empty.
+	AllowedNamespaces nsallowlist.Allowlist
+
+	Recorder record.EventRecorder
+
+	// dnsNameAvailability caches the results of the checks of the relative DNS names; it is shared by the copies of
+	// the reconciler made with withCurrentAzureClients.
+	dnsNameAvailability *dnsNameAvailabilityCache
+}
+
+// dnsNameAvailability is the cached result of the check of the availability of a relative DNS name.
+type dnsNameAvailability struct {
+	available bool
+	message   string
+	expiresAt time.Time
+}
+
+// dnsNameAvailabilityCache caches the results of the checks of the availability of the relative DNS names, so that
+// a profile which cannot be created does not call Azure on each retry; a nil cache caches nothing.
+type dnsNameAvailabilityCache struct {
+	mu      sync.Mutex
+	results map[string]dnsNameAvailability
+}
+
+// get returns the cached result of the check of the relative DNS name, if it has not expired at now.
+func (c *dnsNameAvailabilityCache) get(relativeName string, now time.Time) (dnsNameAvailability, bool) {
+	if c == nil {
+		return dnsNameAvailability{}, false
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	res, ok := c.results[relativeName]
+	if !ok {
+		return dnsNameAvailability{}, false
+	}
+	if !now.Before(res.expiresAt) {
+		delete(c.results, relativeName)
+		return dnsNameAvailability{}, false
+	}
+	return res, true
+}
+
+// set caches the result of the check of the relative DNS name.
+func (c *dnsNameAvailabilityCache) set(relativeName string, res dnsNameAvailability) {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.results[relativeName] = res
 }
 
 //+kubebuilder:rbac:groups=networking.fleet.azure.com,resources=trafficmanagerprofiles,verbs=get;list;watch;create;update;patch;delete
 //+kubebuilder:rbac:groups=networking.fleet.azure.com,resources=trafficmanagerprofiles/status,verbs=get;update;patch
 //+kubebuilder:rbac:groups=networking.fleet.azure.com,resources=trafficmanagerprofiles/finalizers,verbs=get;update
+//+kubebuilder:rbac:groups=networking.fleet.azure.com,resources=trafficmanagerbackends,verbs=get;list;watch
+//+kubebuilder:rbac:groups=networking.fleet.azure.com,resources=serviceimports,verbs=get;list;watch
 //+kubebuilder:rbac:groups="",resources=events,verbs=create;patch
 
 // Reconcile triggers a single reconcile round.
@@ -81,6 +268,12 @@ func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Resu
 		klog.V(2).InfoS("Reconciliation ends", "trafficManagerProfile", profileKRef, "latency", latency)
 	}()
 
+	r, err := r.withCurrentAzureClients()
+	if err != nil {
+		klog.ErrorS(err, "Failed to get the Azure clients", "trafficManagerProfile", profileKRef)
+		return ctrl.Result{}, err
+	}
+
 	profile := &fleetnetv1beta1.TrafficManagerProfile{}
 	if err := r.Client.Get(ctx, name, profile); err != nil {
 		if apierrors.IsNotFound(err) {
@@ -91,6 +284,10 @@ func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Resu
 		return ctrl.Result{}, controller.NewAPIServerError(true, err)
 	}
 
+	if !r.AllowedNamespaces.Allows(profile.Namespace) {
+		return r.handleNamespaceNotAllowed(ctx, profile)
+	}
+
 	if !profile.ObjectMeta.DeletionTimestamp.IsZero() {
 		// TODO: handle the deletion when backends are still attached to the profile
 		return r.handleDelete(ctx, profile)
@@ -105,11 +302,28 @@ func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Resu
 		}
 	}
 
-	// TODO: replace the following with defaulter wehbook
+	// The defaults are set by the defaulting webhook at admission; they are set here as well, as a safety net for the
+	// objects created before the webhook existed or when the webhook is disabled.
 	defaulter.SetDefaultsTrafficManagerProfile(profile)
 	return r.handleUpdate(ctx, profile)
 }
 
+// withCurrentAzureClients returns a copy of the reconciler which uses the Azure clients built from the latest cloud
+// config, so that a reload does not change the clients in the middle of a reconciliation.
+func (r *Reconciler) withCurrentAzureClients() (*Reconciler, error) {
+	if r.AzureClients == nil {
+		return r, nil
+	}
+	clients, err := r.AzureClients.Current()
+	if err != nil {
+		return nil, err
+	}
+	rc := *r
+	rc.ProfilesClient = clients.ProfilesClient
+	rc.ResourceGroupName = clients.ResourceGroupName
+	return &rc, nil
+}
+
 func (r *Reconciler) handleDelete(ctx context.Context, profile *fleetnetv1beta1.TrafficManagerProfile) (ctrl.Result, error) {
 	profileKObj := klog.KObj(profile)
 	// The profile is being deleted
@@ -119,36 +333,133 @@ func (r *Reconciler) handleDelete(ctx context.Context, profile *fleetnetv1beta1.
 	}
 
 	atmProfileName := generateAzureTrafficManagerProfileNameFunc(profile)
-	klog.V(2).InfoS("Deleting Azure Traffic Manager profile", "trafficManagerProfile", profileKObj, "atmProfileName", atmProfileName)
-	if _, err := r.ProfilesClient.Delete(ctx, r.ResourceGroupName, atmProfileName, nil); err != nil {
-		if !azureerrors.IsNotFound(err) {
-			klog.ErrorS(err, "Failed to delete Azure Traffic Manager profile", "trafficManagerProfile", profileKObj, "atmProfileName", atmProfileName)
+	if r.ReadOnly {
+		if _, err := r.ProfilesClient.Get(ctx, r.ResourceGroupName, atmProfileName, nil); err == nil {
+			return r.keepFinalizerInReadOnlyMode(ctx, profile, atmProfileName)
+		} else if !azureerrors.IsNotFound(err) {
+			klog.ErrorS(err, "Failed to get the profile", "trafficManagerProfile", profileKObj, "atmProfileName", atmProfileName)
+			r.Recorder.Eventf(profile, corev1.EventTypeWarning, profileEventReasonAzureAPIError,
+				"Failed to get Azure Traffic Manager profile %s: %s", atmProfileName, azureerrors.Summary(err))
 			return ctrl.Result{}, err
 		}
+		klog.V(2).InfoS("Azure Traffic Manager profile does not exist", "trafficManagerProfile", profileKObj, "atmProfileName", atmProfileName)
+	} else {
+		klog.V(2).InfoS("Deleting Azure Traffic Manager profile", "trafficManagerProfile", profileKObj, "atmProfileName", atmProfileName)
+		if _, err := r.ProfilesClient.Delete(ctx, r.ResourceGroupName, atmProfileName, nil); err != nil {
+			if !azureerrors.IsNotFound(err) {
+				klog.ErrorS(err, "Failed to delete Azure Traffic Manager profile", "trafficManagerProfile", profileKObj, "atmProfileName", atmProfileName)
+				r.Recorder.Eventf(profile, corev1.EventTypeWarning, profileEventReasonAzureAPIError,
+					"Failed to delete Azure Traffic Manager profile %s: %s", atmProfileName, azureerrors.Summary(err))
+				return ctrl.Result{}, err
+			}
+		}
+		klog.V(2).InfoS("Deleted Azure Traffic Manager profile", "trafficManagerProfile", profileKObj, "atmProfileName", atmProfileName)
+		r.Recorder.Eventf(profile, corev1.EventTypeNormal, profileEventReasonDeleted, "Deleted Azure Traffic Manager profile %s", atmProfileName)
 	}
-	klog.V(2).InfoS("Deleted Azure Traffic Manager profile", "trafficManagerProfile", profileKObj, "atmProfileName", atmProfileName)
 
 	controllerutil.RemoveFinalizer(profile, objectmeta.TrafficManagerProfileFinalizer)
 	if err := r.Client.Update(ctx, profile); err != nil {
 		klog.ErrorS(err, "Failed to remove trafficManagerProfile finalizer", "trafficManagerProfile", profileKObj)
 		return ctrl.Result{}, controller.NewUpdateIgnoreConflictError(err)
 	}
+	trafficManagerProfileLastSyncedTime.DeleteLabelValues(profile.Namespace, profile.Name)
+	setMonitorStatusMetric(profile.Namespace, profile.Name, "")
 	klog.V(2).InfoS("Removed trafficManagerProfile finalizer", "trafficManagerProfile", profileKObj)
 	return ctrl.Result{}, nil
 }
 
+// handleNamespaceNotAllowed refuses the profile whose namespace is not in the allowlist, without calling Azure. The
+// Azure Traffic Manager profile created before the namespace was disallowed, if any, is left as is: its finalizer is
+// removed, so that the profile can be deleted, and it is taken back once the namespace is allowed again.
+func (r *Reconciler) handleNamespaceNotAllowed(ctx context.Context, profile *fleetnetv1beta1.TrafficManagerProfile) (ctrl.Result, error) {
+	profileKObj := klog.KObj(profile)
+	if controllerutil.ContainsFinalizer(profile, objectmeta.TrafficManagerProfileFinalizer) {
+		klog.InfoS("Leaving the Azure Traffic Manager profile, if any, of the trafficManagerProfile whose namespace is not allowed",
+			"trafficManagerProfile", profileKObj, "atmProfileName", generateAzureTrafficManagerProfileNameFunc(profile))
+		controllerutil.RemoveFinalizer(profile, objectmeta.TrafficManagerProfileFinalizer)
+		if err := r.Client.Update(ctx, profile); err != nil {
+			klog.ErrorS(err, "Failed to remove trafficManagerProfile finalizer", "trafficManagerProfile", profileKObj)
+			return ctrl.Result{}, controller.NewUpdateIgnoreConflictError(err)
+		}
+		trafficManagerProfileLastSyncedTime.DeleteLabelValues(profile.Namespace, profile.Name)
+		setMonitorStatusMetric(profile.Namespace, profile.Name, "")
+	}
+	if !profile.ObjectMeta.DeletionTimestamp.IsZero() {
+		return ctrl.Result{}, nil
+	}
+
+	message := fmt.Sprintf("Namespace %q is not allowed to use Azure Traffic Manager", profile.Namespace)
+	cond := metav1.Condition{
+		Type:               string(fleetnetv1beta1.TrafficManagerProfileConditionProgrammed),
+		Status:             metav1.ConditionFalse,
+		ObservedGeneration: profile.Generation,
+		Reason:             string(fleetnetv1beta1.TrafficManagerProfileReasonNamespaceNotAllowed),
+		Message:            message,
+	}
+	if condition.EqualCondition(meta.FindStatusCondition(profile.Status.Conditions, cond.Type), &cond) {
+		klog.V(2).InfoS("TrafficManagerProfile has already been refused as its namespace is not allowed", "trafficManagerProfile", profileKObj)
+		return ctrl.Result{}, nil
+	}
+	klog.V(2).InfoS("Refusing the trafficManagerProfile as its namespace is not allowed", "trafficManagerProfile", profileKObj)
+	r.Recorder.Event(profile, corev1.EventTypeWarning, profileEventReasonNamespaceNotAllowed, message)
+	profile.Status.DNSName = nil
+	profile.Status.MonitorStatus = ""
+	meta.SetStatusCondition(&profile.Status.Conditions, cond)
+	if err := r.Client.Status().Update(ctx, profile); err != nil {
+		klog.ErrorS(err, "Failed to update trafficManagerProfile status", "trafficManagerProfile", profileKObj)
+		return ctrl.Result{}, controller.NewUpdateIgnoreConflictError(err)
+	}
+	return ctrl.Result{}, nil
+}
+
+// keepFinalizerInReadOnlyMode reports that the finalizer of the deleted profile is kept, as its existing Azure Traffic
+// Manager profile cannot be deleted in the read-only mode. The profile is not requeued: the deletion resumes once the
+// controller runs without the read-only mode, or once the Azure Traffic Manager profile is deleted out of band.
+func (r *Reconciler) keepFinalizerInReadOnlyMode(ctx context.Context, profile *fleetnetv1beta1.TrafficManagerProfile, atmProfileName string) (ctrl.Result, error) {
+	profileKObj := klog.KObj(profile)
+	klog.V(2).InfoS("Skipped deleting Azure Traffic Manager profile in the read-only mode and keeping the finalizer", "trafficManagerProfile", profileKObj, "atmProfileName", atmProfileName)
+	message := fmt.Sprintf("Refusing to remove the finalizer as Azure Traffic Manager profile %s cannot be deleted in the read-only mode", atmProfileName)
+	r.Recorder.Event(profile, corev1.EventTypeWarning, profileEventReasonReadOnly, message)
+	meta.SetStatusCondition(&profile.Status.Conditions, metav1.Condition{
+		Type:               string(fleetnetv1beta1.TrafficManagerProfileConditionProgrammed),
+		Status:             metav1.ConditionUnknown,
+		ObservedGeneration: profile.Generation,
+		Reason:             string(fleetnetv1beta1.TrafficManagerProfileReasonReadOnly),
+		Message:            message,
+	})
+	if err := r.Client.Status().Update(ctx, profile); err != nil {
+		klog.ErrorS(err, "Failed to update trafficManagerProfile status", "trafficManagerProfile", profileKObj)
+		return ctrl.Result{}, controller.NewUpdateIgnoreConflictError(err)
+	}
+	return ctrl.Result{}, nil
+}
+
 func (r *Reconciler) handleUpdate(ctx context.Context, profile *fleetnetv1beta1.TrafficManagerProfile) (ctrl.Result, error) {
 	profileKObj := klog.KObj(profile)
 	atmProfileName := generateAzureTrafficManagerProfileNameFunc(profile)
+	monitorPort, err := r.monitorPort(ctx, profile)
+	if errors.Is(err, errMonitorPortNotInheritable) {
+		klog.V(2).InfoS("Rejected the trafficManagerProfile as the monitor port cannot be inherited", "trafficManagerProfile", profileKObj, "error", err)
+		return r.updateProfileStatus(ctx, profile, armtrafficmanager.Profile{}, err)
+	}
+	if err != nil {
+		return ctrl.Result{}, err
+	}
 	desiredATMProfile := generateAzureTrafficManagerProfile(profile)
+	desiredATMProfile.Properties.MonitorConfig.Port = monitorPort
 	var responseError *azcore.ResponseError
 	getRes, getErr := r.ProfilesClient.Get(ctx, r.ResourceGroupName, atmProfileName, nil)
 	if getErr != nil {
 		if !azureerrors.IsNotFound(getErr) {
 			klog.ErrorS(getErr, "Failed to get the profile", "trafficManagerProfile", profileKObj, "atmProfileName", atmProfileName)
+			r.Recorder.Eventf(profile, corev1.EventTypeWarning, profileEventReasonAzureAPIError,
+				"Failed to get Azure Traffic Manager profile %s: %s", atmProfileName, azureerrors.Summary(getErr))
 			return ctrl.Result{}, getErr
 		}
 		klog.V(2).InfoS("Azure Traffic Manager profile does not exist", "trafficManagerProfile", profileKObj, "atmProfileName", atmProfileName)
+		if err := r.checkDNSNameAvailability(ctx, profile, *desiredATMProfile.Properties.DNSConfig.RelativeName); err != nil {
+			return r.updateProfileStatus(ctx, profile, armtrafficmanager.Profile{}, err)
+		}
 	} else {
 		if EqualAzureTrafficManagerProfile(getRes.Profile, desiredATMProfile) {
 			// skip creating or updating the profile
@@ -157,10 +468,17 @@ func (r *Reconciler) handleUpdate(ctx context.Context, profile *fleetnetv1beta1.
 		}
 	}
 
+	if r.ReadOnly {
+		klog.V(2).InfoS("Skipped creating or updating Azure Traffic Manager profile in the read-only mode", "trafficManagerProfile", profileKObj, "atmProfileName", atmProfileName, "exists", getErr == nil)
+		return r.updateProfileStatus(ctx, profile, getRes.Profile, fmt.Errorf("%w: would create or update Azure Traffic Manager profile %s", errReadOnly, atmProfileName))
+	}
+
 	res, updateErr := r.ProfilesClient.CreateOrUpdate(ctx, r.ResourceGroupName, atmProfileName, desiredATMProfile, nil)
 	if updateErr != nil {
 		if !errors.As(updateErr, &responseError) {
 			klog.ErrorS(updateErr, "Failed to send the createOrUpdate request", "trafficManagerProfile", profileKObj, "atmProfileName", atmProfileName)
+			r.Recorder.Eventf(profile, corev1.EventTypeWarning, profileEventReasonAzureAPIError,
+				"Failed to create or update Azure Traffic Manager profile %s: %s", atmProfileName, azureerrors.Summary(updateErr))
 			return ctrl.Result{}, updateErr
 		}
 		klog.ErrorS(updateErr, "Failed to create or update a profile", "trafficManagerProfile", profileKObj,
@@ -171,6 +489,46 @@ func (r *Reconciler) handleUpdate(ctx context.Context, profile *fleetnetv1beta1.
 	return r.updateProfileStatus(ctx, profile, res.Profile, updateErr)
 }
 
+// checkDNSNameAvailability checks with Azure that the relative DNS name of the profile is available, if enabled; it
+// returns an error wrapping errDNSNameNotAvailable if the name is taken. The check is best-effort: the profile is
+// created anyway if the check fails, as the creation reports a taken name as well.
+func (r *Reconciler) checkDNSNameAvailability(ctx context.Context, profile *fleetnetv1beta1.TrafficManagerProfile, relativeName string) error {
+	if !r.CheckDNSNameAvailability {
+		return nil
+	}
+	profileKObj := klog.KObj(profile)
+	now := time.Now()
+	res, ok := r.dnsNameAvailability.get(relativeName, now)
+	if !ok {
+		checkRes, err := r.ProfilesClient.CheckTrafficManagerRelativeDNSNameAvailability(ctx,
+			armtrafficmanager.CheckTrafficManagerRelativeDNSNameAvailabilityParameters{
+				Name: ptr.To(relativeName),
+				Type: ptr.To(azureTrafficManagerProfileResourceType),
+			}, nil)
+		if err != nil {
+			klog.ErrorS(err, "Failed to check the availability of the relative DNS name; skip the check",
+				"trafficManagerProfile", profileKObj, "relativeName", relativeName)
+			return nil
+		}
+		ttl := r.DNSNameAvailabilityCacheTTL
+		if ttl <= 0 {
+			ttl = DefaultDNSNameAvailabilityCacheTTL
+		}
+		res = dnsNameAvailability{
+			available: ptr.Deref(checkRes.NameAvailable, true),
+			message:   ptr.Deref(checkRes.Message, ""),
+			expiresAt: now.Add(ttl),
+		}
+		r.dnsNameAvailability.set(relativeName, res)
+	}
+	klog.V(2).InfoS("Checked the availability of the relative DNS name", "trafficManagerProfile", profileKObj,
+		"relativeName", relativeName, "available", res.available, "cached", ok)
+	if !res.available {
+		return fmt.Errorf("%w: %s", errDNSNameNotAvailable, res.message)
+	}
+	return nil
+}
+
 // EqualAzureTrafficManagerProfile compares only few fields of the current and desired Azure Traffic Manager profiles
 // by ignoring others.
 // The desired profile is built by the controllers and all the required fields should not be nil.
@@ -218,7 +576,18 @@ func EqualAzureTrafficManagerProfile(current, desired armtrafficmanager.Profile)
 
 func (r *Reconciler) updateProfileStatus(ctx context.Context, profile *fleetnetv1beta1.TrafficManagerProfile, atmProfile armtrafficmanager.Profile, updateErr error) (ctrl.Result, error) {
 	profileKObj := klog.KObj(profile)
-	if updateErr == nil {
+	atmProfileName := generateAzureTrafficManagerProfileNameFunc(profile)
+	res := ctrl.Result{}
+	profile.Status.MonitorStatus = ""
+	if errors.Is(updateErr, errReadOnly) {
+		// The DNS name of the existing Azure Traffic Manager profile, if any, is still served.
+		profile.Status.DNSName = nil
+		if atmProfile.Properties != nil && atmProfile.Properties.DNSConfig != nil {
+			profile.Status.DNSName = atmProfile.Properties.DNSConfig.Fqdn
+		}
+		profile.Status.MonitorStatus = monitorStatusOf(atmProfile)
+	} else if updateErr == nil {
+		profile.Status.MonitorStatus = monitorStatusOf(atmProfile)
 		// atmProfile.Properties.DNSConfig.Fqdn should not be nil
 		if atmProfile.Properties != nil && atmProfile.Properties.DNSConfig != nil {
 			profile.Status.DNSName = atmProfile.Properties.DNSConfig.Fqdn
@@ -238,15 +607,44 @@ func (r *Reconciler) updateProfileStatus(ctx context.Context, profile *fleetnetv
 		Reason:             string(fleetnetv1beta1.TrafficManagerProfileReasonProgrammed),
 		Message:            "Successfully configured the Azure Traffic Manager profile",
 	}
-	if azureerrors.IsConflict(updateErr) {
+	if errors.Is(updateErr, errReadOnly) {
+		cond = metav1.Condition{
+			Type:               string(fleetnetv1beta1.TrafficManagerProfileConditionProgrammed),
+			Status:             metav1.ConditionUnknown,
+			ObservedGeneration: profile.Generation,
+			Reason:             string(fleetnetv1beta1.TrafficManagerProfileReasonReadOnly),
+			Message:            fmt.Sprintf("Not programmed in the %v", updateErr),
+		}
+		// The profile will be reconciled again when it changes; retrying would skip the same request.
+		updateErr = nil
+	} else if errors.Is(updateErr, errMonitorPortNotInheritable) {
+		cond = metav1.Condition{
+			Type:               string(fleetnetv1beta1.TrafficManagerProfileConditionProgrammed),
+			Status:             metav1.ConditionFalse,
+			ObservedGeneration: profile.Generation,
+			Reason:             string(fleetnetv1beta1.TrafficManagerProfileReasonInvalid),
+			Message:            fmt.Sprintf("Invalid profile: %v", updateErr),
+		}
+		// The profile will be reconciled again when its backends or their Services change.
+		updateErr = nil
+	} else if errors.Is(updateErr, errDNSNameNotAvailable) || azureerrors.IsDNSNameNotAvailable(updateErr) {
+		fqdn := fmt.Sprintf(DNSNameFormat, fmt.Sprintf(DNSRelativeNameFormat, profile.Namespace, profile.Name))
+		r.Recorder.Eventf(profile, corev1.EventTypeWarning, profileEventReasonDNSNameUnavailable,
+			"DNS name %s is not available: %s", fqdn, azureerrors.Summary(updateErr))
 		cond = metav1.Condition{
 			Type:               string(fleetnetv1beta1.TrafficManagerProfileConditionProgrammed),
 			Status:             metav1.ConditionFalse,
 			ObservedGeneration: profile.Generation,
 			Reason:             string(fleetnetv1beta1.TrafficManagerProfileReasonDNSNameNotAvailable),
-			Message:            "Domain name is not available. Please choose a different profile name or namespace",
+			Message:            fmt.Sprintf("Domain name %s is not available. Please choose a different profile name or namespace", fqdn),
 		}
+		// Retrying with the error backoff would not help until the name is released; the profile is reconciled again
+		// after an interval instead.
+		updateErr = nil
+		res = ctrl.Result{RequeueAfter: dnsNameNotAvailableRetryInterval}
 	} else if azureerrors.IsClientError(updateErr) && !azureerrors.IsThrottled(updateErr) {
+		r.Recorder.Eventf(profile, corev1.EventTypeWarning, profileEventReasonAzureAPIError,
+			"Failed to create or update Azure Traffic Manager profile %s: %s", atmProfileName, azureerrors.Summary(updateErr))
 		cond = metav1.Condition{
 			Type:               string(fleetnetv1beta1.TrafficManagerProfileConditionProgrammed),
 			Status:             metav1.ConditionFalse,
@@ -255,6 +653,8 @@ func (r *Reconciler) updateProfileStatus(ctx context.Context, profile *fleetnetv
 			Message:            fmt.Sprintf("Invalid profile: %v", updateErr),
 		}
 	} else if updateErr != nil {
+		r.Recorder.Eventf(profile, corev1.EventTypeWarning, profileEventReasonAzureAPIError,
+			"Failed to create or update Azure Traffic Manager profile %s: %s", atmProfileName, azureerrors.Summary(updateErr))
 		cond = metav1.Condition{
 			Type:               string(fleetnetv1beta1.TrafficManagerProfileConditionProgrammed),
 			Status:             metav1.ConditionUnknown,
@@ -263,13 +663,117 @@ func (r *Reconciler) updateProfileStatus(ctx context.Context, profile *fleetnetv
 			Message:            fmt.Sprintf("Failed to configure profile and retyring: %v", updateErr),
 		}
 	}
+	if cond.Status == metav1.ConditionTrue {
+		// Only the first successful programming of each generation is reported, instead of every reconciliation.
+		oldCond := meta.FindStatusCondition(profile.Status.Conditions, cond.Type)
+		if oldCond == nil || oldCond.Status != metav1.ConditionTrue || oldCond.ObservedGeneration != profile.Generation {
+			r.Recorder.Eventf(profile, corev1.EventTypeNormal, profileEventReasonProgrammed,
+				"Programmed Azure Traffic Manager profile %s with DNS name %s", atmProfileName, ptr.Deref(profile.Status.DNSName, ""))
+		}
+		// All the Azure Traffic Manager operations of the reconciliation have succeeded.
+		profile.Status.LastSyncedTime = NextLastSyncedTime(profile.Status.LastSyncedTime, time.Now(), r.LastSyncedTimeUpdateInterval)
+	}
 	meta.SetStatusCondition(&profile.Status.Conditions, cond)
 	if err := r.Client.Status().Update(ctx, profile); err != nil {
 		klog.ErrorS(err, "Failed to update trafficManagerProfile status", "trafficManagerProfile", profileKObj)
 		return ctrl.Result{}, controller.NewUpdateIgnoreConflictError(err)
 	}
+	if profile.Status.LastSyncedTime != nil {
+		trafficManagerProfileLastSyncedTime.WithLabelValues(profile.Namespace, profile.Name).Set(float64(profile.Status.LastSyncedTime.Unix()))
+	}
+	setMonitorStatusMetric(profile.Namespace, profile.Name, profile.Status.MonitorStatus)
 	klog.V(2).InfoS("Updated the trafficProfile status", "trafficManagerProfile", profileKObj, "status", profile.Status)
-	return ctrl.Result{}, updateErr
+	if updateErr == nil && res.IsZero() && atmProfile.Properties != nil && r.MonitorStatusResyncInterval > 0 {
+		// Azure does not notify the changes of the monitor status, which are picked up by the resyncs instead.
+		res = ctrl.Result{RequeueAfter: r.MonitorStatusResyncInterval}
+	}
+	return res, updateErr
+}
+
+// monitorStatusOf returns the monitor status of an Azure Traffic Manager profile, which is Disabled when the profile
+// is disabled, or empty if it is unknown.
+func monitorStatusOf(atmProfile armtrafficmanager.Profile) fleetnetv1beta1.TrafficManagerProfileMonitorStatus {
+	props := atmProfile.Properties
+	if props == nil {
+		return ""
+	}
+	if ptr.Deref(props.ProfileStatus, "") == armtrafficmanager.ProfileStatusDisabled {
+		return fleetnetv1beta1.TrafficManagerProfileMonitorStatusDisabled
+	}
+	if props.MonitorConfig == nil || props.MonitorConfig.ProfileMonitorStatus == nil {
+		return ""
+	}
+	return fleetnetv1beta1.TrafficManagerProfileMonitorStatus(*props.MonitorConfig.ProfileMonitorStatus)
+}
+
+// setMonitorStatusMetric reports the monitor status of the profile, removing the series of its previous status; all
+// its series are removed when the status is empty.
+func setMonitorStatusMetric(namespace, name string, status fleetnetv1beta1.TrafficManagerProfileMonitorStatus) {
+	for _, other := range armtrafficmanager.PossibleProfileMonitorStatusValues() {
+		if fleetnetv1beta1.TrafficManagerProfileMonitorStatus(other) != status {
+			trafficManagerProfileMonitorStatus.DeleteLabelValues(namespace, name, string(other))
+		}
+	}
+	if status != "" {
+		trafficManagerProfileMonitorStatus.WithLabelValues(namespace, name, string(status)).Set(1)
+	}
+}
+
+// monitorPort returns the port probed by Azure Traffic Manager. When the port is inherited from the backends, it is
+// the port of the Services exported by the backends of the profile; the port in the spec is used until any of them
+// is exported.
+func (r *Reconciler) monitorPort(ctx context.Context, profile *fleetnetv1beta1.TrafficManagerProfile) (*int64, error) {
+	mc := profile.Spec.MonitorConfig
+	if mc.InheritPortFromBackend == nil || !*mc.InheritPortFromBackend {
+		return mc.Port, nil
+	}
+
+	profileKObj := klog.KObj(profile)
+	// The backends are listed by the namespace instead of the profile index of the trafficManagerBackend controller,
+	// as the index skips the backends of other shards.
+	backendList := &fleetnetv1beta1.TrafficManagerBackendList{}
+	if err := r.Client.List(ctx, backendList, client.InNamespace(profile.Namespace)); err != nil {
+		klog.ErrorS(err, "Failed to list trafficManagerBackends", "trafficManagerProfile", profileKObj)
+		return nil, controller.NewAPIServerError(true, err)
+	}
+	listguard.ObserveListSize(backendList, "trafficManagerProfile", profileKObj)
+	var port *int64
+	var portSource string
+	for i := range backendList.Items {
+		backend := &backendList.Items[i]
+		if backend.Spec.Profile.Name != profile.Name || !backend.DeletionTimestamp.IsZero() {
+			continue
+		}
+		svcImport := &fleetnetv1alpha1.ServiceImport{}
+		svcImportName := types.NamespacedName{Namespace: backend.Namespace, Name: backend.Spec.Backend.Name}
+		if err := r.Client.Get(ctx, svcImportName, svcImport); err != nil {
+			if apierrors.IsNotFound(err) {
+				continue
+			}
+			klog.ErrorS(err, "Failed to get serviceImport", "trafficManagerProfile", profileKObj, "serviceImport", svcImportName)
+			return nil, controller.NewAPIServerError(true, err)
+		}
+		switch len(svcImport.Status.Ports) {
+		case 0:
+			// The Service has not been exported yet.
+			continue
+		case 1:
+		default:
+			return nil, fmt.Errorf("%w: serviceImport %q of trafficManagerBackend %q has %d ports, while a single port is required",
+				errMonitorPortNotInheritable, svcImportName.Name, backend.Name, len(svcImport.Status.Ports))
+		}
+		backendPort := int64(svcImport.Status.Ports[0].Port)
+		if port != nil && *port != backendPort {
+			return nil, fmt.Errorf("%w: serviceImport %q has port %d, while serviceImport %q has port %d",
+				errMonitorPortNotInheritable, svcImportName.Name, backendPort, portSource, *port)
+		}
+		port, portSource = &backendPort, svcImportName.Name
+	}
+	if port == nil {
+		return mc.Port, nil
+	}
+	klog.V(2).InfoS("Inherited the monitor port from the backends", "trafficManagerProfile", profileKObj, "port", *port)
+	return port, nil
 }
 
 func generateAzureTrafficManagerProfile(profile *fleetnetv1beta1.TrafficManagerProfile) armtrafficmanager.Profile {
@@ -302,7 +806,74 @@ func generateAzureTrafficManagerProfile(profile *fleetnetv1beta1.TrafficManagerP
 
 // SetupWithManager sets up the controller with the Manager.
 func (r *Reconciler) SetupWithManager(mgr ctrl.Manager) error {
+	r.dnsNameAvailability = &dnsNameAvailabilityCache{results: make(map[string]dnsNameAvailability)}
 	return ctrl.NewControllerManagedBy(mgr).
 		For(&fleetnetv1beta1.TrafficManagerProfile{}).
-		Complete(r)
+		Watches(
+			&fleetnetv1beta1.TrafficManagerBackend{},
+			handler.EnqueueRequestsFromMapFunc(r.trafficManagerBackendEventHandler()),
+		).
+		Watches(
+			&fleetnetv1alpha1.ServiceImport{},
+			handler.EnqueueRequestsFromMapFunc(r.serviceImportEventHandler()),
+		).
+		Complete(reconcilewatchdog.Wrap("trafficmanagerprofile", r))
+}
+
+// trafficManagerBackendEventHandler enqueues the profile of the backend if the profile inherits its monitor port.
+func (r *Reconciler) trafficManagerBackendEventHandler() handler.MapFunc {
+	return func(ctx context.Context, object client.Object) []reconcile.Request {
+		backend, ok := object.(*fleetnetv1beta1.TrafficManagerBackend)
+		if !ok {
+			return []reconcile.Request{}
+		}
+		return r.inheritingProfileRequests(ctx, []fleetnetv1beta1.TrafficManagerBackend{*backend})
+	}
+}
+
+// serviceImportEventHandler enqueues the profiles of the backends referencing the serviceImport if the profiles
+// inherit their monitor ports.
+func (r *Reconciler) serviceImportEventHandler() handler.MapFunc {
+	return func(ctx context.Context, object client.Object) []reconcile.Request {
+		backendList := &fleetnetv1beta1.TrafficManagerBackendList{}
+		// ServiceImport and TrafficManagerBackend should be in the same namespace.
+		// The backends are only read, so that they are not deep-copied out of the cache.
+		if err := r.Client.List(ctx, backendList, client.InNamespace(object.GetNamespace()), client.UnsafeDisableDeepCopy); err != nil {
+			klog.ErrorS(err, "Failed to list trafficManagerBackends for the serviceImport", "serviceImport", klog.KObj(object))
+			return []reconcile.Request{}
+		}
+		listguard.ObserveListSize(backendList, "serviceImport", klog.KObj(object))
+		backends := make([]fleetnetv1beta1.TrafficManagerBackend, 0, len(backendList.Items))
+		for i := range backendList.Items {
+			if backendList.Items[i].Spec.Backend.Name == object.GetName() {
+				backends = append(backends, backendList.Items[i])
+			}
+		}
+		return r.inheritingProfileRequests(ctx, backends)
+	}
+}
+
+// inheritingProfileRequests returns the requests of the profiles of the backends which inherit their monitor ports.
+func (r *Reconciler) inheritingProfileRequests(ctx context.Context, backends []fleetnetv1beta1.TrafficManagerBackend) []reconcile.Request {
+	res := make([]reconcile.Request, 0, len(backends))
+	seen := make(map[types.NamespacedName]bool, len(backends))
+	for i := range backends {
+		name := types.NamespacedName{Namespace: backends[i].Namespace, Name: backends[i].Spec.Profile.Name}
+		if seen[name] {
+			continue
+		}
+		seen[name] = true
+		profile := &fleetnetv1beta1.TrafficManagerProfile{}
+		if err := r.Client.Get(ctx, name, profile); err != nil {
+			if !apierrors.IsNotFound(err) {
+				klog.ErrorS(err, "Failed to get trafficManagerProfile", "trafficManagerProfile", name)
+			}
+			continue
+		}
+		if mc := profile.Spec.MonitorConfig; mc == nil || mc.InheritPortFromBackend == nil || !*mc.InheritPortFromBackend {
+			continue
+		}
+		res = append(res, reconcile.Request{NamespacedName: name})
+	}
+	return res
 }
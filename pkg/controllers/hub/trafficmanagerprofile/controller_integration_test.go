@@ -10,10 +10,12 @@ import (
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/utils/ptr"
 
+	fleetnetv1alpha1 "go.goms.io/fleet-networking/api/v1alpha1"
 	fleetnetv1beta1 "go.goms.io/fleet-networking/api/v1beta1"
 	"go.goms.io/fleet-networking/pkg/common/objectmeta"
 	"go.goms.io/fleet-networking/test/common/trafficmanager/fakeprovider"
@@ -111,6 +113,14 @@ var _ = Describe("Test TrafficManagerProfile Controller", func() {
 		It("Validating trafficManagerProfile is deleted", func() {
 			validator.IsTrafficManagerProfileDeleted(ctx, k8sClient, types.NamespacedName{Namespace: testNamespace, Name: name})
 		})
+
+		It("Validating the emitted events", func() {
+			validator.ValidateEmittedEvents(ctx, k8sClient, profile, []corev1.Event{
+				{Type: corev1.EventTypeNormal, Reason: profileEventReasonProgrammed},
+				{Type: corev1.EventTypeWarning, Reason: profileEventReasonAzureAPIError},
+				{Type: corev1.EventTypeNormal, Reason: profileEventReasonDeleted},
+			})
+		})
 	})
 
 	Context("When updating existing valid trafficManagerProfile with no changes", Ordered, func() {
@@ -169,6 +179,13 @@ var _ = Describe("Test TrafficManagerProfile Controller", func() {
 		It("Validating trafficManagerProfile is deleted", func() {
 			validator.IsTrafficManagerProfileDeleted(ctx, k8sClient, types.NamespacedName{Namespace: testNamespace, Name: name})
 		})
+
+		It("Validating the emitted events", func() {
+			validator.ValidateEmittedEvents(ctx, k8sClient, profile, []corev1.Event{
+				{Type: corev1.EventTypeNormal, Reason: profileEventReasonProgrammed},
+				{Type: corev1.EventTypeNormal, Reason: profileEventReasonDeleted},
+			})
+		})
 	})
 
 	Context("When creating trafficManagerProfile and DNS name is not available", Ordered, func() {
@@ -210,6 +227,61 @@ var _ = Describe("Test TrafficManagerProfile Controller", func() {
 		It("Validating trafficManagerProfile is deleted", func() {
 			validator.IsTrafficManagerProfileDeleted(ctx, k8sClient, types.NamespacedName{Namespace: testNamespace, Name: name})
 		})
+
+		It("Validating the emitted events", func() {
+			validator.ValidateEmittedEvents(ctx, k8sClient, profile, []corev1.Event{
+				{Type: corev1.EventTypeWarning, Reason: profileEventReasonDNSNameUnavailable},
+				{Type: corev1.EventTypeNormal, Reason: profileEventReasonDeleted},
+			})
+		})
+	})
+
+	Context("When creating trafficManagerProfile and DNS name is reported as not available by the check", Ordered, func() {
+		name := fakeprovider.DNSNameUnavailableProfileName
+		var profile *fleetnetv1beta1.TrafficManagerProfile
+
+		It("AzureTrafficManager should not be configured", func() {
+			By("By creating a new TrafficManagerProfile")
+			profile = trafficManagerProfileForTest(name)
+			Expect(k8sClient.Create(ctx, profile)).Should(Succeed())
+
+			By("By checking profile")
+			want := fleetnetv1beta1.TrafficManagerProfile{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:       name,
+					Namespace:  testNamespace,
+					Finalizers: []string{objectmeta.TrafficManagerProfileFinalizer},
+				},
+				Spec: profile.Spec,
+				Status: fleetnetv1beta1.TrafficManagerProfileStatus{
+					Conditions: []metav1.Condition{
+						{
+							Status:             metav1.ConditionFalse,
+							Type:               string(fleetnetv1beta1.TrafficManagerProfileConditionProgrammed),
+							Reason:             string(fleetnetv1beta1.TrafficManagerProfileReasonDNSNameNotAvailable),
+							ObservedGeneration: profile.Generation,
+						},
+					},
+				},
+			}
+			validator.ValidateTrafficManagerProfile(ctx, k8sClient, &want)
+		})
+
+		It("Deleting trafficManagerProfile", func() {
+			err := k8sClient.Delete(ctx, profile)
+			Expect(err).Should(Succeed(), "failed to delete trafficManagerProfile")
+		})
+
+		It("Validating trafficManagerProfile is deleted", func() {
+			validator.IsTrafficManagerProfileDeleted(ctx, k8sClient, types.NamespacedName{Namespace: testNamespace, Name: name})
+		})
+
+		It("Validating the emitted events", func() {
+			validator.ValidateEmittedEvents(ctx, k8sClient, profile, []corev1.Event{
+				{Type: corev1.EventTypeWarning, Reason: profileEventReasonDNSNameUnavailable},
+				{Type: corev1.EventTypeNormal, Reason: profileEventReasonDeleted},
+			})
+		})
 	})
 
 	Context("When creating trafficManagerProfile and azure request failed because of too many requests", Ordered, func() {
@@ -251,6 +323,13 @@ var _ = Describe("Test TrafficManagerProfile Controller", func() {
 		It("Validating trafficManagerProfile is deleted", func() {
 			validator.IsTrafficManagerProfileDeleted(ctx, k8sClient, types.NamespacedName{Namespace: testNamespace, Name: name})
 		})
+
+		It("Validating the emitted events", func() {
+			validator.ValidateEmittedEvents(ctx, k8sClient, profile, []corev1.Event{
+				{Type: corev1.EventTypeWarning, Reason: profileEventReasonAzureAPIError},
+				{Type: corev1.EventTypeNormal, Reason: profileEventReasonDeleted},
+			})
+		})
 	})
 
 	Context("When creating trafficManagerProfile and azure request failed because of client side error", Ordered, func() {
@@ -292,6 +371,13 @@ var _ = Describe("Test TrafficManagerProfile Controller", func() {
 		It("Validating trafficManagerProfile is deleted", func() {
 			validator.IsTrafficManagerProfileDeleted(ctx, k8sClient, types.NamespacedName{Namespace: testNamespace, Name: name})
 		})
+
+		It("Validating the emitted events", func() {
+			validator.ValidateEmittedEvents(ctx, k8sClient, profile, []corev1.Event{
+				{Type: corev1.EventTypeWarning, Reason: profileEventReasonAzureAPIError},
+				{Type: corev1.EventTypeNormal, Reason: profileEventReasonDeleted},
+			})
+		})
 	})
 
 	Context("When creating trafficManagerProfile and azure request failed because of internal server error", Ordered, func() {
@@ -333,5 +419,103 @@ var _ = Describe("Test TrafficManagerProfile Controller", func() {
 		It("Validating trafficManagerProfile is deleted", func() {
 			validator.IsTrafficManagerProfileDeleted(ctx, k8sClient, types.NamespacedName{Namespace: testNamespace, Name: name})
 		})
+
+		It("Validating the emitted events", func() {
+			validator.ValidateEmittedEvents(ctx, k8sClient, profile, []corev1.Event{
+				{Type: corev1.EventTypeWarning, Reason: profileEventReasonAzureAPIError},
+				{Type: corev1.EventTypeNormal, Reason: profileEventReasonDeleted},
+			})
+		})
+	})
+
+	Context("When inheriting the monitor port from a multi-port service", Ordered, func() {
+		name := fakeprovider.ValidProfileName
+		var profile *fleetnetv1beta1.TrafficManagerProfile
+		var backend *fleetnetv1beta1.TrafficManagerBackend
+		var svcImport *fleetnetv1alpha1.ServiceImport
+
+		wantProfile := func(reason fleetnetv1beta1.TrafficManagerProfileConditionReason, status metav1.ConditionStatus, dnsName *string) fleetnetv1beta1.TrafficManagerProfile {
+			return fleetnetv1beta1.TrafficManagerProfile{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:       name,
+					Namespace:  testNamespace,
+					Finalizers: []string{objectmeta.TrafficManagerProfileFinalizer},
+				},
+				Spec: profile.Spec,
+				Status: fleetnetv1beta1.TrafficManagerProfileStatus{
+					DNSName: dnsName,
+					Conditions: []metav1.Condition{
+						{
+							Status:             status,
+							Type:               string(fleetnetv1beta1.TrafficManagerProfileConditionProgrammed),
+							Reason:             string(reason),
+							ObservedGeneration: profile.Generation,
+						},
+					},
+				},
+			}
+		}
+
+		It("Creating the serviceImport and trafficManagerBackend", func() {
+			svcImport = &fleetnetv1alpha1.ServiceImport{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      fakeprovider.ServiceImportName,
+					Namespace: testNamespace,
+				},
+			}
+			Expect(k8sClient.Create(ctx, svcImport)).Should(Succeed())
+			svcImport.Status.Ports = []fleetnetv1alpha1.ServicePort{{Name: "http", Port: 8080}, {Name: "https", Port: 8443}}
+			Expect(k8sClient.Status().Update(ctx, svcImport)).Should(Succeed())
+
+			backend = &fleetnetv1beta1.TrafficManagerBackend{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      fakeprovider.ValidBackendName,
+					Namespace: testNamespace,
+				},
+				Spec: fleetnetv1beta1.TrafficManagerBackendSpec{
+					Profile: fleetnetv1beta1.TrafficManagerProfileRef{Name: name},
+					Backend: fleetnetv1beta1.TrafficManagerBackendRef{Name: fakeprovider.ServiceImportName},
+				},
+			}
+			Expect(k8sClient.Create(ctx, backend)).Should(Succeed())
+		})
+
+		It("The trafficManagerProfile should be rejected", func() {
+			profile = trafficManagerProfileForTest(name)
+			profile.Spec.MonitorConfig.InheritPortFromBackend = ptr.To(true)
+			Expect(k8sClient.Create(ctx, profile)).Should(Succeed())
+
+			want := wantProfile(fleetnetv1beta1.TrafficManagerProfileReasonInvalid, metav1.ConditionFalse, nil)
+			validator.ValidateTrafficManagerProfile(ctx, k8sClient, &want)
+		})
+
+		It("Updating the service to a single port", func() {
+			Expect(k8sClient.Get(ctx, types.NamespacedName{Namespace: testNamespace, Name: fakeprovider.ServiceImportName}, svcImport)).Should(Succeed())
+			svcImport.Status.Ports = []fleetnetv1alpha1.ServicePort{{Name: "https", Port: 8443}}
+			Expect(k8sClient.Status().Update(ctx, svcImport)).Should(Succeed())
+		})
+
+		It("The trafficManagerProfile should be programmed", func() {
+			fqdn := fmt.Sprintf(fakeprovider.ProfileDNSNameFormat, fmt.Sprintf(DNSRelativeNameFormat, testNamespace, name))
+			want := wantProfile(fleetnetv1beta1.TrafficManagerProfileReasonProgrammed, metav1.ConditionTrue, ptr.To(fqdn))
+			validator.ValidateTrafficManagerProfile(ctx, k8sClient, &want)
+		})
+
+		It("Deleting the trafficManagerProfile, trafficManagerBackend and serviceImport", func() {
+			Expect(k8sClient.Delete(ctx, profile)).Should(Succeed(), "failed to delete trafficManagerProfile")
+			Expect(k8sClient.Delete(ctx, backend)).Should(Succeed(), "failed to delete trafficManagerBackend")
+			Expect(k8sClient.Delete(ctx, svcImport)).Should(Succeed(), "failed to delete serviceImport")
+		})
+
+		It("Validating trafficManagerProfile is deleted", func() {
+			validator.IsTrafficManagerProfileDeleted(ctx, k8sClient, types.NamespacedName{Namespace: testNamespace, Name: name})
+		})
+
+		It("Validating the emitted events", func() {
+			validator.ValidateEmittedEvents(ctx, k8sClient, profile, []corev1.Event{
+				{Type: corev1.EventTypeNormal, Reason: profileEventReasonProgrammed},
+				{Type: corev1.EventTypeNormal, Reason: profileEventReasonDeleted},
+			})
+		})
 	})
 })
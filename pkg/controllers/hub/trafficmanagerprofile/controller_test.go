@@ -6,13 +6,34 @@ Licensed under the MIT license.
 package trafficmanagerprofile
 
 import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/trafficmanager/armtrafficmanager"
+	"github.com/google/go-cmp/cmp"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
 	"k8s.io/utils/ptr"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 
+	fleetnetv1alpha1 "go.goms.io/fleet-networking/api/v1alpha1"
 	fleetnetv1beta1 "go.goms.io/fleet-networking/api/v1beta1"
+	"go.goms.io/fleet-networking/pkg/common/nsallowlist"
+	"go.goms.io/fleet-networking/pkg/common/objectmeta"
+	"go.goms.io/fleet-networking/test/common/trafficmanager/fakeprovider"
 )
 
 func TestGenerateAzureTrafficManagerProfileName(t *testing.T) {
@@ -278,3 +299,928 @@ func TestEqualAzureTrafficManagerProfile(t *testing.T) {
 		})
 	}
 }
+
+func serviceImportWithPorts(name string, ports ...int32) *fleetnetv1alpha1.ServiceImport {
+	svcImport := &fleetnetv1alpha1.ServiceImport{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: testNamespace,
+			Name:      name,
+		},
+	}
+	for _, port := range ports {
+		svcImport.Status.Ports = append(svcImport.Status.Ports, fleetnetv1alpha1.ServicePort{Port: port})
+	}
+	return svcImport
+}
+
+func backendForTest(name, profileName, svcImportName string) *fleetnetv1beta1.TrafficManagerBackend {
+	return &fleetnetv1beta1.TrafficManagerBackend{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: testNamespace,
+			Name:      name,
+		},
+		Spec: fleetnetv1beta1.TrafficManagerBackendSpec{
+			Profile: fleetnetv1beta1.TrafficManagerProfileRef{Name: profileName},
+			Backend: fleetnetv1beta1.TrafficManagerBackendRef{Name: svcImportName},
+		},
+	}
+}
+
+func inheritingProfileForTest(name string) *fleetnetv1beta1.TrafficManagerProfile {
+	profile := trafficManagerProfileForTest(name)
+	profile.Spec.MonitorConfig.InheritPortFromBackend = ptr.To(true)
+	return profile
+}
+
+func newFakeClient(objs ...client.Object) client.Client {
+	scheme := runtime.NewScheme()
+	if err := fleetnetv1alpha1.AddToScheme(scheme); err != nil {
+		panic(err)
+	}
+	if err := fleetnetv1beta1.AddToScheme(scheme); err != nil {
+		panic(err)
+	}
+	return fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithObjects(objs...).
+		WithStatusSubresource(&fleetnetv1beta1.TrafficManagerProfile{}).
+		Build()
+}
+
+func TestMonitorPort(t *testing.T) {
+	profileName := "profile"
+	tests := []struct {
+		name    string
+		profile *fleetnetv1beta1.TrafficManagerProfile
+		objs    []client.Object
+		want    *int64
+		wantErr bool
+	}{
+		{
+			name:    "explicit port",
+			profile: trafficManagerProfileForTest(profileName),
+			objs: []client.Object{
+				backendForTest("backend", profileName, "app"),
+				serviceImportWithPorts("app", 443, 8443),
+			},
+			want: ptr.To[int64](8080),
+		},
+		{
+			name:    "inherit from a single-port service",
+			profile: inheritingProfileForTest(profileName),
+			objs: []client.Object{
+				backendForTest("backend", profileName, "app"),
+				backendForTest("other-profile-backend", "other-profile", "other-app"),
+				serviceImportWithPorts("app", 443),
+				serviceImportWithPorts("other-app", 8443),
+			},
+			want: ptr.To[int64](443),
+		},
+		{
+			name:    "inherit from multiple services with the same port",
+			profile: inheritingProfileForTest(profileName),
+			objs: []client.Object{
+				backendForTest("backend", profileName, "app"),
+				backendForTest("backend2", profileName, "app2"),
+				backendForTest("backend-not-exported", profileName, "not-exported"),
+				backendForTest("backend-not-found", profileName, "not-found"),
+				serviceImportWithPorts("app", 443),
+				serviceImportWithPorts("app2", 443),
+				serviceImportWithPorts("not-exported"),
+			},
+			want: ptr.To[int64](443),
+		},
+		{
+			name:    "inherit with no exported service falls back to the spec port",
+			profile: inheritingProfileForTest(profileName),
+			objs: []client.Object{
+				backendForTest("backend", profileName, "not-found"),
+			},
+			want: ptr.To[int64](8080),
+		},
+		{
+			name:    "inherit from a multi-port service is rejected",
+			profile: inheritingProfileForTest(profileName),
+			objs: []client.Object{
+				backendForTest("backend", profileName, "app"),
+				serviceImportWithPorts("app", 443, 8443),
+			},
+			wantErr: true,
+		},
+		{
+			name:    "inherit from services with different ports is rejected",
+			profile: inheritingProfileForTest(profileName),
+			objs: []client.Object{
+				backendForTest("backend", profileName, "app"),
+				backendForTest("backend2", profileName, "app2"),
+				serviceImportWithPorts("app", 443),
+				serviceImportWithPorts("app2", 8443),
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := &Reconciler{Client: newFakeClient(tt.objs...)}
+			got, err := r.monitorPort(context.Background(), tt.profile)
+			if gotErr := errors.Is(err, errMonitorPortNotInheritable); gotErr != tt.wantErr {
+				t.Fatalf("monitorPort() got error %v, want errMonitorPortNotInheritable %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if err != nil {
+				t.Fatalf("monitorPort() got error %v, want no error", err)
+			}
+			if diff := cmp.Diff(got, tt.want); diff != "" {
+				t.Errorf("monitorPort() mismatch (-got, +want):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestHandleUpdate_InheritMonitorPort(t *testing.T) {
+	name := fakeprovider.ValidProfileName
+	tests := []struct {
+		name       string
+		ports      []int32
+		wantPort   *int64
+		wantReason fleetnetv1beta1.TrafficManagerProfileConditionReason
+		// wantEvents are the types and the reasons of the emitted events.
+		wantEvents []string
+	}{
+		{
+			name:       "single-port service",
+			ports:      []int32{443},
+			wantPort:   ptr.To[int64](443),
+			wantReason: fleetnetv1beta1.TrafficManagerProfileReasonProgrammed,
+			wantEvents: []string{corev1.EventTypeNormal + " " + profileEventReasonProgrammed},
+		},
+		{
+			name:       "multi-port service",
+			ports:      []int32{443, 8443},
+			wantReason: fleetnetv1beta1.TrafficManagerProfileReasonInvalid,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			profile := inheritingProfileForTest(name)
+			fakeClient := newFakeClient(profile, backendForTest("backend", name, "app"), serviceImportWithPorts("app", tt.ports...))
+			store := fakeprovider.NewProfileStore()
+			profilesClient, err := store.NewProfileClient("default-sub")
+			if err != nil {
+				t.Fatalf("NewProfileClient() got error %v, want no error", err)
+			}
+			recorder := record.NewFakeRecorder(10)
+			r := &Reconciler{
+				Client:            fakeClient,
+				ProfilesClient:    profilesClient,
+				ResourceGroupName: fakeprovider.DefaultResourceGroupName,
+				Recorder:          recorder,
+			}
+			originalGenerateName := generateAzureTrafficManagerProfileNameFunc
+			generateAzureTrafficManagerProfileNameFunc = func(profile *fleetnetv1beta1.TrafficManagerProfile) string {
+				return profile.Name
+			}
+			defer func() { generateAzureTrafficManagerProfileNameFunc = originalGenerateName }()
+
+			if err := fakeClient.Get(ctx, types.NamespacedName{Namespace: testNamespace, Name: name}, profile); err != nil {
+				t.Fatalf("Get() got error %v, want no error", err)
+			}
+			if _, err := r.handleUpdate(ctx, profile); err != nil {
+				t.Fatalf("handleUpdate() got error %v, want no error", err)
+			}
+
+			got := &fleetnetv1beta1.TrafficManagerProfile{}
+			if err := fakeClient.Get(ctx, types.NamespacedName{Namespace: testNamespace, Name: name}, got); err != nil {
+				t.Fatalf("Get() got error %v, want no error", err)
+			}
+			cond := meta.FindStatusCondition(got.Status.Conditions, string(fleetnetv1beta1.TrafficManagerProfileConditionProgrammed))
+			if cond == nil || cond.Reason != string(tt.wantReason) {
+				t.Fatalf("Programmed condition = %+v, want reason %s", cond, tt.wantReason)
+			}
+			var gotEvents []string
+			for len(recorder.Events) > 0 {
+				fields := strings.Fields(<-recorder.Events)
+				gotEvents = append(gotEvents, strings.Join(fields[:2], " "))
+			}
+			if diff := cmp.Diff(tt.wantEvents, gotEvents); diff != "" {
+				t.Errorf("emitted events mismatch (-want, +got):\n%s", diff)
+			}
+
+			atmProfile, ok := store.Profile(name)
+			if tt.wantPort == nil {
+				if ok || store.CreateOrUpdateCount(name) != 0 {
+					t.Errorf("Azure Traffic Manager profile is created for the rejected trafficManagerProfile")
+				}
+				return
+			}
+			if !ok {
+				t.Fatalf("Azure Traffic Manager profile is not created")
+			}
+			if diff := cmp.Diff(atmProfile.Properties.MonitorConfig.Port, tt.wantPort); diff != "" {
+				t.Errorf("Azure Traffic Manager profile monitor port mismatch (-got, +want):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestHandleUpdate_MonitorStatus(t *testing.T) {
+	ctx := context.Background()
+	name := fakeprovider.ValidProfileName
+	profile := trafficManagerProfileForTest(name)
+	profile.Finalizers = []string{objectmeta.TrafficManagerProfileFinalizer}
+	fakeClient := newFakeClient(profile)
+	store := fakeprovider.NewProfileStore()
+	profilesClient, err := store.NewProfileClient("default-sub")
+	if err != nil {
+		t.Fatalf("NewProfileClient() got error %v, want no error", err)
+	}
+	r := &Reconciler{
+		Client:                      fakeClient,
+		ProfilesClient:              profilesClient,
+		ResourceGroupName:           fakeprovider.DefaultResourceGroupName,
+		Recorder:                    record.NewFakeRecorder(10),
+		MonitorStatusResyncInterval: 5 * time.Minute,
+	}
+	originalGenerateName := generateAzureTrafficManagerProfileNameFunc
+	generateAzureTrafficManagerProfileNameFunc = func(profile *fleetnetv1beta1.TrafficManagerProfile) string {
+		return profile.Name
+	}
+	defer func() { generateAzureTrafficManagerProfileNameFunc = originalGenerateName }()
+
+	reconcileAndGet := func() *fleetnetv1beta1.TrafficManagerProfile {
+		got := &fleetnetv1beta1.TrafficManagerProfile{}
+		if err := fakeClient.Get(ctx, types.NamespacedName{Namespace: testNamespace, Name: name}, got); err != nil {
+			t.Fatalf("Get() got error %v, want no error", err)
+		}
+		res, err := r.handleUpdate(ctx, got)
+		if err != nil {
+			t.Fatalf("handleUpdate() got error %v, want no error", err)
+		}
+		if res.RequeueAfter != r.MonitorStatusResyncInterval {
+			t.Errorf("handleUpdate() got RequeueAfter %v, want %v", res.RequeueAfter, r.MonitorStatusResyncInterval)
+		}
+		if err := fakeClient.Get(ctx, types.NamespacedName{Namespace: testNamespace, Name: name}, got); err != nil {
+			t.Fatalf("Get() got error %v, want no error", err)
+		}
+		return got
+	}
+
+	reconcileAndGet()
+	for _, status := range []armtrafficmanager.ProfileMonitorStatus{
+		armtrafficmanager.ProfileMonitorStatusDegraded,
+		armtrafficmanager.ProfileMonitorStatusOnline,
+	} {
+		if !store.SetMonitorStatus(name, status) {
+			t.Fatalf("SetMonitorStatus() got false, want true")
+		}
+		got := reconcileAndGet()
+		if want := fleetnetv1beta1.TrafficManagerProfileMonitorStatus(status); got.Status.MonitorStatus != want {
+			t.Errorf("status.monitorStatus got %q, want %q", got.Status.MonitorStatus, want)
+		}
+		if v := testutil.ToFloat64(trafficManagerProfileMonitorStatus.WithLabelValues(testNamespace, name, string(status))); v != 1 {
+			t.Errorf("%s monitor status metric got %v, want 1", status, v)
+		}
+	}
+	// Only the series of the current status is kept.
+	if got := testutil.CollectAndCount(trafficManagerProfileMonitorStatus); got != 1 {
+		t.Errorf("monitor status metric got %d series, want 1", got)
+	}
+
+	got := &fleetnetv1beta1.TrafficManagerProfile{}
+	if err := fakeClient.Get(ctx, types.NamespacedName{Namespace: testNamespace, Name: name}, got); err != nil {
+		t.Fatalf("Get() got error %v, want no error", err)
+	}
+	if _, err := r.handleDelete(ctx, got); err != nil {
+		t.Fatalf("handleDelete() got error %v, want no error", err)
+	}
+	if got := testutil.CollectAndCount(trafficManagerProfileMonitorStatus); got != 0 {
+		t.Errorf("monitor status metric got %d series after the deletion, want 0", got)
+	}
+}
+
+func TestMonitorStatusOf(t *testing.T) {
+	tests := []struct {
+		name       string
+		atmProfile armtrafficmanager.Profile
+		want       fleetnetv1beta1.TrafficManagerProfileMonitorStatus
+	}{
+		{
+			name: "no properties",
+		},
+		{
+			name: "no monitor status",
+			atmProfile: armtrafficmanager.Profile{
+				Properties: &armtrafficmanager.ProfileProperties{
+					MonitorConfig: &armtrafficmanager.MonitorConfig{},
+				},
+			},
+		},
+		{
+			name: "degraded",
+			atmProfile: armtrafficmanager.Profile{
+				Properties: &armtrafficmanager.ProfileProperties{
+					MonitorConfig: &armtrafficmanager.MonitorConfig{
+						ProfileMonitorStatus: ptr.To(armtrafficmanager.ProfileMonitorStatusDegraded),
+					},
+				},
+			},
+			want: fleetnetv1beta1.TrafficManagerProfileMonitorStatusDegraded,
+		},
+		{
+			name: "disabled profile",
+			atmProfile: armtrafficmanager.Profile{
+				Properties: &armtrafficmanager.ProfileProperties{
+					ProfileStatus: ptr.To(armtrafficmanager.ProfileStatusDisabled),
+					MonitorConfig: &armtrafficmanager.MonitorConfig{
+						ProfileMonitorStatus: ptr.To(armtrafficmanager.ProfileMonitorStatusOnline),
+					},
+				},
+			},
+			want: fleetnetv1beta1.TrafficManagerProfileMonitorStatusDisabled,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := monitorStatusOf(tt.atmProfile); got != tt.want {
+				t.Errorf("monitorStatusOf() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNextLastSyncedTime(t *testing.T) {
+	now := time.Now()
+	recent := &metav1.Time{Time: now.Add(-10 * time.Second)}
+	stale := &metav1.Time{Time: now.Add(-2 * time.Minute)}
+	tests := []struct {
+		name     string
+		current  *metav1.Time
+		interval time.Duration
+		want     *metav1.Time
+	}{
+		{
+			name: "never synced",
+			want: &metav1.Time{Time: now},
+		},
+		{
+			name:    "recently synced",
+			current: recent,
+			want:    recent,
+		},
+		{
+			name:    "synced longer than the default interval ago",
+			current: stale,
+			want:    &metav1.Time{Time: now},
+		},
+		{
+			name:     "synced longer than the custom interval ago",
+			current:  recent,
+			interval: 5 * time.Second,
+			want:     &metav1.Time{Time: now},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := NextLastSyncedTime(tt.current, now, tt.interval)
+			if !got.Equal(tt.want) {
+				t.Errorf("NextLastSyncedTime() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestHandleUpdate_LastSyncedTime(t *testing.T) {
+	// The status is stored with a precision of seconds.
+	recent := metav1.NewTime(time.Now().Add(-10 * time.Second).Truncate(time.Second))
+	stale := metav1.NewTime(time.Now().Add(-2 * time.Minute).Truncate(time.Second))
+	tests := []struct {
+		name           string
+		profileName    string
+		lastSyncedTime *metav1.Time
+		wantErr        bool
+		wantAdvanced   bool
+	}{
+		{
+			name:         "first successful sync",
+			profileName:  fakeprovider.ValidProfileName,
+			wantAdvanced: true,
+		},
+		{
+			name:           "successful sync shortly after the last one",
+			profileName:    fakeprovider.ValidProfileName,
+			lastSyncedTime: &recent,
+		},
+		{
+			name:           "successful sync long after the last one",
+			profileName:    fakeprovider.ValidProfileName,
+			lastSyncedTime: &stale,
+			wantAdvanced:   true,
+		},
+		{
+			name:           "failed sync",
+			profileName:    fakeprovider.InternalServerErrProfileName,
+			lastSyncedTime: &stale,
+			wantErr:        true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			profile := trafficManagerProfileForTest(tt.profileName)
+			profile.Status.LastSyncedTime = tt.lastSyncedTime
+			fakeClient := newFakeClient(profile)
+			profilesClient, err := fakeprovider.NewProfileClient("default-sub")
+			if err != nil {
+				t.Fatalf("NewProfileClient() got error %v, want no error", err)
+			}
+			r := &Reconciler{
+				Client:            fakeClient,
+				ProfilesClient:    profilesClient,
+				ResourceGroupName: fakeprovider.DefaultResourceGroupName,
+				Recorder:          record.NewFakeRecorder(10),
+			}
+			originalGenerateName := generateAzureTrafficManagerProfileNameFunc
+			generateAzureTrafficManagerProfileNameFunc = func(profile *fleetnetv1beta1.TrafficManagerProfile) string {
+				return profile.Name
+			}
+			defer func() { generateAzureTrafficManagerProfileNameFunc = originalGenerateName }()
+
+			key := types.NamespacedName{Namespace: testNamespace, Name: tt.profileName}
+			if err := fakeClient.Get(ctx, key, profile); err != nil {
+				t.Fatalf("Get() got error %v, want no error", err)
+			}
+			start := time.Now().Truncate(time.Second)
+			if _, err := r.handleUpdate(ctx, profile); (err != nil) != tt.wantErr {
+				t.Fatalf("handleUpdate() got error %v, want error %v", err, tt.wantErr)
+			}
+
+			got := &fleetnetv1beta1.TrafficManagerProfile{}
+			if err := fakeClient.Get(ctx, key, got); err != nil {
+				t.Fatalf("Get() got error %v, want no error", err)
+			}
+			if !tt.wantAdvanced {
+				if !got.Status.LastSyncedTime.Equal(tt.lastSyncedTime) {
+					t.Errorf("LastSyncedTime = %v, want %v", got.Status.LastSyncedTime, tt.lastSyncedTime)
+				}
+				return
+			}
+			if got.Status.LastSyncedTime == nil || got.Status.LastSyncedTime.Time.Before(start) {
+				t.Fatalf("LastSyncedTime = %v, want no earlier than %v", got.Status.LastSyncedTime, start)
+			}
+			gotMetric := testutil.ToFloat64(trafficManagerProfileLastSyncedTime.WithLabelValues(testNamespace, tt.profileName))
+			if want := float64(got.Status.LastSyncedTime.Unix()); gotMetric != want {
+				t.Errorf("trafficManagerProfileLastSyncedTime = %v, want %v", gotMetric, want)
+			}
+		})
+	}
+}
+
+func TestHandleUpdate_DNSNameAvailability(t *testing.T) {
+	now := time.Now()
+	tests := []struct {
+		name             string
+		profileName      string
+		checkEnabled     bool
+		cached           *dnsNameAvailability
+		wantErr          bool
+		wantRequeueAfter time.Duration
+		wantReason       fleetnetv1beta1.TrafficManagerProfileConditionReason
+		wantEvent        string
+		wantCached       *bool
+	}{
+		{
+			name:         "available name",
+			profileName:  fakeprovider.NewProfileName,
+			checkEnabled: true,
+			wantReason:   fleetnetv1beta1.TrafficManagerProfileReasonProgrammed,
+			wantCached:   ptr.To(true),
+		},
+		{
+			name:             "taken name reported by the check",
+			profileName:      fakeprovider.DNSNameUnavailableProfileName,
+			checkEnabled:     true,
+			wantRequeueAfter: dnsNameNotAvailableRetryInterval,
+			wantReason:       fleetnetv1beta1.TrafficManagerProfileReasonDNSNameNotAvailable,
+			wantEvent:        "already exists",
+			wantCached:       ptr.To(false),
+		},
+		{
+			name:             "taken name reported by the creation",
+			profileName:      fakeprovider.DNSNameUnavailableProfileName,
+			wantRequeueAfter: dnsNameNotAvailableRetryInterval,
+			wantReason:       fleetnetv1beta1.TrafficManagerProfileReasonDNSNameNotAvailable,
+			wantEvent:        "Conflict (status code 409)",
+		},
+		{
+			name:             "taken name reported by the creation after the check",
+			profileName:      fakeprovider.ConflictErrProfileName,
+			checkEnabled:     true,
+			wantRequeueAfter: dnsNameNotAvailableRetryInterval,
+			wantReason:       fleetnetv1beta1.TrafficManagerProfileReasonDNSNameNotAvailable,
+			wantEvent:        "Conflict (status code 409)",
+			wantCached:       ptr.To(true),
+		},
+		{
+			name:         "failed check",
+			profileName:  fakeprovider.InternalServerErrProfileName,
+			checkEnabled: true,
+			// The profile is created anyway, and the creation fails.
+			wantErr:    true,
+			wantReason: fleetnetv1beta1.TrafficManagerProfileReasonPending,
+		},
+		{
+			name:             "taken name cached",
+			profileName:      fakeprovider.NewProfileName,
+			checkEnabled:     true,
+			cached:           &dnsNameAvailability{message: "cached", expiresAt: now.Add(time.Minute)},
+			wantRequeueAfter: dnsNameNotAvailableRetryInterval,
+			wantReason:       fleetnetv1beta1.TrafficManagerProfileReasonDNSNameNotAvailable,
+			wantEvent:        "cached",
+			wantCached:       ptr.To(false),
+		},
+		{
+			name:         "taken name cache expired",
+			profileName:  fakeprovider.NewProfileName,
+			checkEnabled: true,
+			cached:       &dnsNameAvailability{message: "cached", expiresAt: now.Add(-time.Second)},
+			wantReason:   fleetnetv1beta1.TrafficManagerProfileReasonProgrammed,
+			wantCached:   ptr.To(true),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			profile := trafficManagerProfileForTest(tt.profileName)
+			fakeClient := newFakeClient(profile)
+			profilesClient, err := fakeprovider.NewProfileClient("default-sub")
+			if err != nil {
+				t.Fatalf("NewProfileClient() got error %v, want no error", err)
+			}
+			recorder := record.NewFakeRecorder(10)
+			r := &Reconciler{
+				Client:                   fakeClient,
+				ProfilesClient:           profilesClient,
+				ResourceGroupName:        fakeprovider.DefaultResourceGroupName,
+				CheckDNSNameAvailability: tt.checkEnabled,
+				Recorder:                 recorder,
+				dnsNameAvailability:      &dnsNameAvailabilityCache{results: make(map[string]dnsNameAvailability)},
+			}
+			relativeName := testNamespace + "-" + tt.profileName
+			if tt.cached != nil {
+				r.dnsNameAvailability.set(relativeName, *tt.cached)
+			}
+			originalGenerateName := generateAzureTrafficManagerProfileNameFunc
+			generateAzureTrafficManagerProfileNameFunc = func(profile *fleetnetv1beta1.TrafficManagerProfile) string {
+				return profile.Name
+			}
+			defer func() { generateAzureTrafficManagerProfileNameFunc = originalGenerateName }()
+
+			key := types.NamespacedName{Namespace: testNamespace, Name: tt.profileName}
+			if err := fakeClient.Get(ctx, key, profile); err != nil {
+				t.Fatalf("Get() got error %v, want no error", err)
+			}
+			res, err := r.handleUpdate(ctx, profile)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("handleUpdate() got error %v, want error %v", err, tt.wantErr)
+			}
+			if res.RequeueAfter != tt.wantRequeueAfter {
+				t.Errorf("handleUpdate() got requeueAfter %v, want %v", res.RequeueAfter, tt.wantRequeueAfter)
+			}
+
+			got := &fleetnetv1beta1.TrafficManagerProfile{}
+			if err := fakeClient.Get(ctx, key, got); err != nil {
+				t.Fatalf("Get() got error %v, want no error", err)
+			}
+			cond := meta.FindStatusCondition(got.Status.Conditions, string(fleetnetv1beta1.TrafficManagerProfileConditionProgrammed))
+			if cond == nil || cond.Reason != string(tt.wantReason) {
+				t.Fatalf("Programmed condition = %+v, want reason %s", cond, tt.wantReason)
+			}
+			if tt.wantReason == fleetnetv1beta1.TrafficManagerProfileReasonDNSNameNotAvailable {
+				fqdn := relativeName + ".trafficmanager.net"
+				if !strings.Contains(cond.Message, fqdn) {
+					t.Errorf("Programmed condition message = %q, want it to contain %q", cond.Message, fqdn)
+				}
+			}
+			if tt.wantEvent != "" {
+				found := false
+				for len(recorder.Events) > 0 {
+					event := <-recorder.Events
+					if strings.HasPrefix(event, corev1.EventTypeWarning+" "+profileEventReasonDNSNameUnavailable) && strings.Contains(event, tt.wantEvent) {
+						found = true
+					}
+				}
+				if !found {
+					t.Errorf("handleUpdate() emitted no %s event with %q", profileEventReasonDNSNameUnavailable, tt.wantEvent)
+				}
+			}
+
+			cached, ok := r.dnsNameAvailability.get(relativeName, time.Now())
+			if tt.wantCached == nil {
+				if ok {
+					t.Errorf("cached availability of %s = %+v, want none", relativeName, cached)
+				}
+				return
+			}
+			if !ok || cached.available != *tt.wantCached {
+				t.Errorf("cached availability of %s = %+v (found %t), want available %t", relativeName, cached, ok, *tt.wantCached)
+			}
+		})
+	}
+}
+
+// TestReadOnly tests that no Azure Traffic Manager profile is created, updated or deleted in the read-only mode, and
+// that the finalizer is kept while the Azure Traffic Manager profile exists.
+func TestReadOnly(t *testing.T) {
+	tests := []struct {
+		name        string
+		profileName string
+		wantDNSName *string
+		// wantFinalizer is set when the Azure Traffic Manager profile exists, so that it cannot be deleted.
+		wantFinalizer bool
+		wantEvents    []string
+	}{
+		{
+			name:          "existing profile which needs an update",
+			profileName:   fakeprovider.ValidProfileName,
+			wantDNSName:   ptr.To(fmt.Sprintf(fakeprovider.ProfileDNSNameFormat, fakeprovider.ValidProfileName)),
+			wantFinalizer: true,
+			wantEvents:    []string{corev1.EventTypeWarning + " " + profileEventReasonReadOnly},
+		},
+		{
+			name:        "new profile",
+			profileName: fakeprovider.NewProfileName,
+		},
+		{
+			name:        "new profile which would fail to be created",
+			profileName: fakeprovider.ConflictErrProfileName,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			profile := trafficManagerProfileForTest(tt.profileName)
+			profile.Finalizers = []string{objectmeta.TrafficManagerProfileFinalizer}
+			fakeClient := newFakeClient(profile)
+			mutations := fakeprovider.NewMutationRecorder()
+			profilesClient, err := mutations.NewProfileClient("default-sub")
+			if err != nil {
+				t.Fatalf("NewProfileClient() got error %v, want no error", err)
+			}
+			recorder := record.NewFakeRecorder(10)
+			r := &Reconciler{
+				Client:                   fakeClient,
+				ProfilesClient:           profilesClient,
+				ResourceGroupName:        fakeprovider.DefaultResourceGroupName,
+				CheckDNSNameAvailability: true,
+				ReadOnly:                 true,
+				Recorder:                 recorder,
+			}
+			originalGenerateName := generateAzureTrafficManagerProfileNameFunc
+			generateAzureTrafficManagerProfileNameFunc = func(profile *fleetnetv1beta1.TrafficManagerProfile) string {
+				return profile.Name
+			}
+			defer func() { generateAzureTrafficManagerProfileNameFunc = originalGenerateName }()
+
+			key := types.NamespacedName{Namespace: testNamespace, Name: tt.profileName}
+			if err := fakeClient.Get(ctx, key, profile); err != nil {
+				t.Fatalf("Get() got error %v, want no error", err)
+			}
+			if _, err := r.handleUpdate(ctx, profile); err != nil {
+				t.Fatalf("handleUpdate() got error %v, want no error", err)
+			}
+			got := &fleetnetv1beta1.TrafficManagerProfile{}
+			if err := fakeClient.Get(ctx, key, got); err != nil {
+				t.Fatalf("Get() got error %v, want no error", err)
+			}
+			cond := meta.FindStatusCondition(got.Status.Conditions, string(fleetnetv1beta1.TrafficManagerProfileConditionProgrammed))
+			if cond == nil || cond.Status != metav1.ConditionUnknown || cond.Reason != string(fleetnetv1beta1.TrafficManagerProfileReasonReadOnly) {
+				t.Fatalf("handleUpdate() Programmed condition = %+v, want Unknown with reason %q", cond, fleetnetv1beta1.TrafficManagerProfileReasonReadOnly)
+			}
+			if diff := cmp.Diff(tt.wantDNSName, got.Status.DNSName); diff != "" {
+				t.Errorf("handleUpdate() DNS name mismatch (-want, +got):\n%s", diff)
+			}
+
+			if err := fakeClient.Delete(ctx, got); err != nil {
+				t.Fatalf("Delete() got error %v, want no error", err)
+			}
+			if err := fakeClient.Get(ctx, key, got); err != nil {
+				t.Fatalf("Get() got error %v, want no error", err)
+			}
+			if _, err := r.handleDelete(ctx, got); err != nil {
+				t.Fatalf("handleDelete() got error %v, want no error", err)
+			}
+			err = fakeClient.Get(ctx, key, got)
+			switch {
+			case tt.wantFinalizer && err != nil:
+				t.Fatalf("Get() got error %v, want the profile with the finalizer", err)
+			case !tt.wantFinalizer && !apierrors.IsNotFound(err):
+				t.Fatalf("Get() got error %v, want not found error", err)
+			case tt.wantFinalizer:
+				cond := meta.FindStatusCondition(got.Status.Conditions, string(fleetnetv1beta1.TrafficManagerProfileConditionProgrammed))
+				if cond == nil || cond.Reason != string(fleetnetv1beta1.TrafficManagerProfileReasonReadOnly) || !strings.HasPrefix(cond.Message, "Refusing to remove the finalizer") {
+					t.Errorf("handleDelete() Programmed condition = %+v, want the reason of the kept finalizer", cond)
+				}
+			}
+
+			var gotEvents []string
+			for len(recorder.Events) > 0 {
+				fields := strings.Fields(<-recorder.Events)
+				gotEvents = append(gotEvents, strings.Join(fields[:2], " "))
+			}
+			if diff := cmp.Diff(tt.wantEvents, gotEvents); diff != "" {
+				t.Errorf("emitted events mismatch (-want, +got):\n%s", diff)
+			}
+			if got := mutations.Requests(); len(got) != 0 {
+				t.Errorf("handleUpdate() and handleDelete() sent mutating requests %v, want none", got)
+			}
+		})
+	}
+}
+
+// TestReconcile_NamespaceAllowlist tests that the profiles are programmed only in the allowed namespaces, and that
+// Azure is never called for the others.
+func TestReconcile_NamespaceAllowlist(t *testing.T) {
+	tests := []struct {
+		name              string
+		allowedNamespaces nsallowlist.Allowlist
+		wantReason        fleetnetv1beta1.TrafficManagerProfileConditionReason
+		wantFinalizer     bool
+		wantEvents        []string
+	}{
+		{
+			name:          "all namespaces allowed",
+			wantReason:    fleetnetv1beta1.TrafficManagerProfileReasonProgrammed,
+			wantFinalizer: true,
+			wantEvents:    []string{corev1.EventTypeNormal + " " + profileEventReasonProgrammed},
+		},
+		{
+			name:              "namespace allowed",
+			allowedNamespaces: nsallowlist.Parse(testNamespace + ",other"),
+			wantReason:        fleetnetv1beta1.TrafficManagerProfileReasonProgrammed,
+			wantFinalizer:     true,
+			wantEvents:        []string{corev1.EventTypeNormal + " " + profileEventReasonProgrammed},
+		},
+		{
+			name:              "namespace not allowed",
+			allowedNamespaces: nsallowlist.Parse("other"),
+			wantReason:        fleetnetv1beta1.TrafficManagerProfileReasonNamespaceNotAllowed,
+			wantEvents:        []string{corev1.EventTypeWarning + " " + profileEventReasonNamespaceNotAllowed},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			profile := trafficManagerProfileForTest(fakeprovider.ValidProfileName)
+			fakeClient := newFakeClient(profile)
+			recorder := record.NewFakeRecorder(10)
+			r := &Reconciler{
+				Client:            fakeClient,
+				ResourceGroupName: fakeprovider.DefaultResourceGroupName,
+				AllowedNamespaces: tt.allowedNamespaces,
+				Recorder:          recorder,
+			}
+			if tt.allowedNamespaces.Allows(testNamespace) {
+				profilesClient, err := fakeprovider.NewProfileClient("default-sub")
+				if err != nil {
+					t.Fatalf("NewProfileClient() got error %v, want no error", err)
+				}
+				r.ProfilesClient = profilesClient
+			}
+			// Otherwise, the nil ProfilesClient panics if Azure is called.
+			originalGenerateName := generateAzureTrafficManagerProfileNameFunc
+			generateAzureTrafficManagerProfileNameFunc = func(profile *fleetnetv1beta1.TrafficManagerProfile) string {
+				return profile.Name
+			}
+			defer func() { generateAzureTrafficManagerProfileNameFunc = originalGenerateName }()
+
+			key := types.NamespacedName{Namespace: testNamespace, Name: fakeprovider.ValidProfileName}
+			if _, err := r.Reconcile(ctx, ctrl.Request{NamespacedName: key}); err != nil {
+				t.Fatalf("Reconcile() got error %v, want no error", err)
+			}
+			got := &fleetnetv1beta1.TrafficManagerProfile{}
+			if err := fakeClient.Get(ctx, key, got); err != nil {
+				t.Fatalf("Get() got error %v, want no error", err)
+			}
+			cond := meta.FindStatusCondition(got.Status.Conditions, string(fleetnetv1beta1.TrafficManagerProfileConditionProgrammed))
+			if cond == nil || cond.Reason != string(tt.wantReason) {
+				t.Errorf("Reconcile() Programmed condition = %+v, want reason %q", cond, tt.wantReason)
+			}
+			if gotFinalizer := controllerutil.ContainsFinalizer(got, objectmeta.TrafficManagerProfileFinalizer); gotFinalizer != tt.wantFinalizer {
+				t.Errorf("Reconcile() finalizer = %t, want %t", gotFinalizer, tt.wantFinalizer)
+			}
+			if diff := cmp.Diff(tt.wantEvents, drainEvents(recorder)); diff != "" {
+				t.Errorf("emitted events mismatch (-want, +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+// TestReconcile_NamespaceAllowlistTransition tests that a programmed profile whose namespace is no longer allowed is
+// refused without deleting its Azure Traffic Manager profile, and that it is programmed again once its namespace is
+// allowed back.
+func TestReconcile_NamespaceAllowlistTransition(t *testing.T) {
+	ctx := context.Background()
+	name := fakeprovider.ValidProfileName
+	profile := trafficManagerProfileForTest(name)
+	profile.Finalizers = []string{objectmeta.TrafficManagerProfileFinalizer}
+	profile.Status = fleetnetv1beta1.TrafficManagerProfileStatus{
+		DNSName: ptr.To(fmt.Sprintf(fakeprovider.ProfileDNSNameFormat, name)),
+		Conditions: []metav1.Condition{
+			{
+				Type:               string(fleetnetv1beta1.TrafficManagerProfileConditionProgrammed),
+				Status:             metav1.ConditionTrue,
+				Reason:             string(fleetnetv1beta1.TrafficManagerProfileReasonProgrammed),
+				LastTransitionTime: metav1.Now(),
+			},
+		},
+	}
+	fakeClient := newFakeClient(profile)
+	recorder := record.NewFakeRecorder(10)
+	profilesClient, err := fakeprovider.NewProfileClient("default-sub")
+	if err != nil {
+		t.Fatalf("NewProfileClient() got error %v, want no error", err)
+	}
+	originalGenerateName := generateAzureTrafficManagerProfileNameFunc
+	generateAzureTrafficManagerProfileNameFunc = func(profile *fleetnetv1beta1.TrafficManagerProfile) string {
+		return profile.Name
+	}
+	defer func() { generateAzureTrafficManagerProfileNameFunc = originalGenerateName }()
+	key := types.NamespacedName{Namespace: testNamespace, Name: name}
+
+	// The nil ProfilesClient panics if Azure is called for the refused profile.
+	refusing := &Reconciler{
+		Client:            fakeClient,
+		ResourceGroupName: fakeprovider.DefaultResourceGroupName,
+		AllowedNamespaces: nsallowlist.Parse("other"),
+		Recorder:          recorder,
+	}
+	for i := 0; i < 2; i++ {
+		if _, err := refusing.Reconcile(ctx, ctrl.Request{NamespacedName: key}); err != nil {
+			t.Fatalf("Reconcile() got error %v, want no error", err)
+		}
+	}
+	got := &fleetnetv1beta1.TrafficManagerProfile{}
+	if err := fakeClient.Get(ctx, key, got); err != nil {
+		t.Fatalf("Get() got error %v, want no error", err)
+	}
+	cond := meta.FindStatusCondition(got.Status.Conditions, string(fleetnetv1beta1.TrafficManagerProfileConditionProgrammed))
+	if cond == nil || cond.Status != metav1.ConditionFalse || cond.Reason != string(fleetnetv1beta1.TrafficManagerProfileReasonNamespaceNotAllowed) {
+		t.Errorf("Reconcile() Programmed condition = %+v, want False with reason %q", cond, fleetnetv1beta1.TrafficManagerProfileReasonNamespaceNotAllowed)
+	}
+	if got.Status.DNSName != nil {
+		t.Errorf("Reconcile() DNS name = %v, want nil", *got.Status.DNSName)
+	}
+	if controllerutil.ContainsFinalizer(got, objectmeta.TrafficManagerProfileFinalizer) {
+		t.Errorf("Reconcile() kept the finalizer, want it removed")
+	}
+	// The refusal is reported once, rather than on every reconciliation.
+	wantEvents := []string{corev1.EventTypeWarning + " " + profileEventReasonNamespaceNotAllowed}
+	if diff := cmp.Diff(wantEvents, drainEvents(recorder)); diff != "" {
+		t.Errorf("emitted events mismatch (-want, +got):\n%s", diff)
+	}
+
+	allowing := &Reconciler{
+		Client:            fakeClient,
+		ProfilesClient:    profilesClient,
+		ResourceGroupName: fakeprovider.DefaultResourceGroupName,
+		Recorder:          recorder,
+	}
+	if _, err := allowing.Reconcile(ctx, ctrl.Request{NamespacedName: key}); err != nil {
+		t.Fatalf("Reconcile() got error %v, want no error", err)
+	}
+	if err := fakeClient.Get(ctx, key, got); err != nil {
+		t.Fatalf("Get() got error %v, want no error", err)
+	}
+	cond = meta.FindStatusCondition(got.Status.Conditions, string(fleetnetv1beta1.TrafficManagerProfileConditionProgrammed))
+	if cond == nil || cond.Status != metav1.ConditionTrue {
+		t.Errorf("Reconcile() Programmed condition = %+v, want True", cond)
+	}
+	if !controllerutil.ContainsFinalizer(got, objectmeta.TrafficManagerProfileFinalizer) {
+		t.Errorf("Reconcile() finalizer is missing, want it added back")
+	}
+
+	// A profile deleted while its namespace is not allowed is released without calling Azure to delete its Azure
+	// Traffic Manager profile.
+	if err := fakeClient.Delete(ctx, got); err != nil {
+		t.Fatalf("Delete() got error %v, want no error", err)
+	}
+	if _, err := refusing.Reconcile(ctx, ctrl.Request{NamespacedName: key}); err != nil {
+		t.Fatalf("Reconcile() got error %v, want no error", err)
+	}
+	if err := fakeClient.Get(ctx, key, got); !apierrors.IsNotFound(err) {
+		t.Errorf("Get() got error %v, want not found error", err)
+	}
+}
+
+// drainEvents returns the types and reasons of the events emitted to the recorder.
+func drainEvents(recorder *record.FakeRecorder) []string {
+	var res []string
+	for len(recorder.Events) > 0 {
+		fields := strings.Fields(<-recorder.Events)
+		res = append(res, strings.Join(fields[:2], " "))
+	}
+	return res
+}
@@ -26,6 +26,7 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/manager"
 	metricsserver "sigs.k8s.io/controller-runtime/pkg/metrics/server"
 
+	fleetnetv1alpha1 "go.goms.io/fleet-networking/api/v1alpha1"
 	fleetnetv1beta1 "go.goms.io/fleet-networking/api/v1beta1"
 	"go.goms.io/fleet-networking/test/common/trafficmanager/fakeprovider"
 )
@@ -71,6 +72,8 @@ var _ = BeforeSuite(func() {
 	Expect(err).NotTo(HaveOccurred())
 	Expect(cfg).NotTo(BeNil())
 
+	err = fleetnetv1alpha1.AddToScheme(scheme.Scheme)
+	Expect(err).NotTo(HaveOccurred())
 	err = fleetnetv1beta1.AddToScheme(scheme.Scheme)
 	Expect(err).NotTo(HaveOccurred())
 
@@ -100,9 +103,11 @@ var _ = BeforeSuite(func() {
 	}
 
 	err = (&Reconciler{
-		Client:            mgr.GetClient(),
-		ProfilesClient:    profileClient,
-		ResourceGroupName: fakeprovider.DefaultResourceGroupName,
+		Client:                   mgr.GetClient(),
+		ProfilesClient:           profileClient,
+		ResourceGroupName:        fakeprovider.DefaultResourceGroupName,
+		CheckDNSNameAvailability: true,
+		Recorder:                 mgr.GetEventRecorderFor(ControllerName),
 	}).SetupWithManager(mgr)
 	Expect(err).ToNot(HaveOccurred())
 
@@ -0,0 +1,295 @@
+/*
+Copyright (c) Microsoft Corporation.
+Licensed under the MIT license.
+*/
+
+// Package clustersetdns features the controller which publishes the imported services in a member cluster under
+// their clusterset DNS names (<service>.<namespace>.svc.clusterset.local, per the MCS API DNS conventions), by
+// maintaining the CoreDNS rewrite rules which map the names to the derived services of the MultiClusterServices.
+package clustersetdns
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/klog/v2"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	fleetnetv1alpha1 "go.goms.io/fleet-networking/api/v1alpha1"
+	"go.goms.io/fleet-networking/pkg/common/objectmeta"
+)
+
+const (
+	// ControllerName is the name of the Reconciler.
+	ControllerName = "clusterset-dns-controller"
+
+	// DefaultConfigMap is the CoreDNS custom ConfigMap of AKS clusters, whose keys ending with .override are imported
+	// into the default server block of CoreDNS.
+	DefaultConfigMap = "kube-system/coredns-custom"
+	// DefaultConfigMapKey is the default key of the ConfigMap which holds the rewrite rules.
+	DefaultConfigMapKey = "fleet-networking-clusterset.override"
+
+	clustersetServiceDomain = "svc.clusterset.local"
+	clusterServiceDomain    = "svc.cluster.local"
+
+	// The rewrite rules are written between the marker comments; the content of the ConfigMap outside of them is
+	// never modified.
+	beginMarker = "# BEGIN fleet-networking clusterset DNS; managed by the fleet networking member agent, do not edit"
+	endMarker   = "# END fleet-networking clusterset DNS"
+
+	// resyncPeriod is how often the rewrite rules are reconciled regardless of the MultiClusterService events, as
+	// the ConfigMap itself is not watched.
+	resyncPeriod = 5 * time.Minute
+)
+
+// Reconciler maintains the CoreDNS rewrite rules of the MultiClusterServices of a member cluster in a ConfigMap.
+type Reconciler struct {
+	MemberClient client.Client
+	// ConfigMapReader reads the ConfigMap directly from the API server, so that the ConfigMaps of the member cluster
+	// are not cached.
+	ConfigMapReader client.Reader
+	// ConfigMap is the namespace and name of the CoreDNS custom ConfigMap.
+	ConfigMap types.NamespacedName
+	// ConfigMapKey is the key of the ConfigMap which holds the rewrite rules; other entries of the same key are kept.
+	ConfigMapKey string
+	// FleetSystemNamespace is the namespace of the derived services.
+	FleetSystemNamespace string
+}
+
+// entry maps the clusterset DNS name of an imported service to the DNS name of its derived service.
+type entry struct {
+	clustersetName string
+	derivedName    string
+}
+
+//+kubebuilder:rbac:groups=networking.fleet.azure.com,resources=multiclusterservices,verbs=get;list;watch
+//+kubebuilder:rbac:groups="",resources=configmaps,verbs=get;create;update
+
+// Reconcile writes the rewrite rules of the active MultiClusterServices into the ConfigMap.
+func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	configMapKRef := klog.KRef(req.Namespace, req.Name)
+	startTime := time.Now()
+	klog.V(2).InfoS("Reconciliation starts", "configMap", configMapKRef)
+	defer func() {
+		latency := time.Since(startTime).Milliseconds()
+		klog.V(2).InfoS("Reconciliation ends", "configMap", configMapKRef, "latency", latency)
+	}()
+
+	mcsList := &fleetnetv1alpha1.MultiClusterServiceList{}
+	if err := r.MemberClient.List(ctx, mcsList); err != nil {
+		klog.ErrorS(err, "Failed to list multiClusterServices")
+		return ctrl.Result{}, err
+	}
+	block := renderBlock(r.buildEntries(mcsList.Items))
+
+	configMap := &corev1.ConfigMap{}
+	err := r.ConfigMapReader.Get(ctx, req.NamespacedName, configMap)
+	switch {
+	case errors.IsNotFound(err):
+		if block == "" {
+			return ctrl.Result{RequeueAfter: resyncPeriod}, nil
+		}
+		configMap = &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Namespace: req.Namespace, Name: req.Name},
+			Data:       map[string]string{r.ConfigMapKey: block},
+		}
+		if err := validateCorefile(block); err != nil {
+			klog.ErrorS(err, "Generated an invalid Corefile fragment; skip writing it", "configMap", configMapKRef)
+			return ctrl.Result{}, err
+		}
+		klog.V(2).InfoS("Creating the configMap with the clusterset DNS rewrite rules", "configMap", configMapKRef)
+		if err := r.MemberClient.Create(ctx, configMap); err != nil {
+			klog.ErrorS(err, "Failed to create configMap", "configMap", configMapKRef)
+			return ctrl.Result{}, err
+		}
+		return ctrl.Result{RequeueAfter: resyncPeriod}, nil
+	case err != nil:
+		klog.ErrorS(err, "Failed to get configMap", "configMap", configMapKRef)
+		return ctrl.Result{}, err
+	}
+
+	current := configMap.Data[r.ConfigMapKey]
+	desired, err := spliceBlock(current, block)
+	if err != nil {
+		klog.ErrorS(err, "Failed to locate the clusterset DNS rewrite rules in the configMap", "configMap", configMapKRef, "key", r.ConfigMapKey)
+		return ctrl.Result{}, err
+	}
+	if desired == current {
+		return ctrl.Result{RequeueAfter: resyncPeriod}, nil
+	}
+	if err := validateCorefile(desired); err != nil {
+		klog.ErrorS(err, "Generated an invalid Corefile fragment; skip writing it", "configMap", configMapKRef, "key", r.ConfigMapKey)
+		return ctrl.Result{}, err
+	}
+	if desired == "" {
+		delete(configMap.Data, r.ConfigMapKey)
+	} else {
+		if configMap.Data == nil {
+			configMap.Data = map[string]string{}
+		}
+		configMap.Data[r.ConfigMapKey] = desired
+	}
+	klog.V(2).InfoS("Updating the clusterset DNS rewrite rules", "configMap", configMapKRef, "key", r.ConfigMapKey)
+	if err := r.MemberClient.Update(ctx, configMap); err != nil {
+		klog.ErrorS(err, "Failed to update configMap", "configMap", configMapKRef)
+		return ctrl.Result{}, err
+	}
+	return ctrl.Result{RequeueAfter: resyncPeriod}, nil
+}
+
+// buildEntries returns the entries of the active MultiClusterServices, i.e. the valid ones with a derived service,
+// sorted by the clusterset name. When more than one MultiClusterService imports the same service, the oldest one wins.
+func (r *Reconciler) buildEntries(mcsList []fleetnetv1alpha1.MultiClusterService) []entry {
+	sort.Slice(mcsList, func(i, j int) bool {
+		if !mcsList[i].CreationTimestamp.Equal(&mcsList[j].CreationTimestamp) {
+			return mcsList[i].CreationTimestamp.Before(&mcsList[j].CreationTimestamp)
+		}
+		return mcsList[i].Namespace+"/"+mcsList[i].Name < mcsList[j].Namespace+"/"+mcsList[j].Name
+	})
+	entries := make(map[string]entry, len(mcsList))
+	for i := range mcsList {
+		mcs := &mcsList[i]
+		derivedService := mcs.Labels[objectmeta.MultiClusterServiceLabelDerivedService]
+		if mcs.DeletionTimestamp != nil || derivedService == "" ||
+			!meta.IsStatusConditionTrue(mcs.Status.Conditions, string(fleetnetv1alpha1.MultiClusterServiceValid)) {
+			continue
+		}
+		e := entry{
+			clustersetName: fmt.Sprintf("%s.%s.%s", mcs.Spec.ServiceImport.Name, mcs.Namespace, clustersetServiceDomain),
+			derivedName:    fmt.Sprintf("%s.%s.%s", derivedService, r.FleetSystemNamespace, clusterServiceDomain),
+		}
+		if _, ok := entries[e.clustersetName]; ok {
+			continue
+		}
+		if err := validateEntry(e); err != nil {
+			klog.ErrorS(err, "Skipping the invalid clusterset DNS rewrite rule", "multiClusterService", klog.KObj(mcs))
+			continue
+		}
+		entries[e.clustersetName] = e
+	}
+	res := make([]entry, 0, len(entries))
+	for _, e := range entries {
+		res = append(res, e)
+	}
+	sort.Slice(res, func(i, j int) bool { return res[i].clustersetName < res[j].clustersetName })
+	return res
+}
+
+// renderBlock returns the rewrite rules of the entries between the marker comments, or an empty string if there is
+// no entry. The answers are rewritten back to the clusterset name, as the clients expect the name they queried.
+func renderBlock(entries []entry) string {
+	if len(entries) == 0 {
+		return ""
+	}
+	var b strings.Builder
+	b.WriteString(beginMarker + "\n")
+	for _, e := range entries {
+		fmt.Fprintf(&b, "rewrite stop {\n")
+		fmt.Fprintf(&b, "    name exact %s %s\n", e.clustersetName, e.derivedName)
+		fmt.Fprintf(&b, "    answer name %s %s\n", regexp.QuoteMeta(e.derivedName), e.clustersetName)
+		fmt.Fprintf(&b, "}\n")
+	}
+	b.WriteString(endMarker + "\n")
+	return b.String()
+}
+
+// spliceBlock replaces the block between the marker comments in content with the given block, or appends the block
+// if content has none; the rest of content is kept as is.
+func spliceBlock(content, block string) (string, error) {
+	begin := strings.Index(content, beginMarker)
+	if begin < 0 {
+		if block == "" {
+			return content, nil
+		}
+		if content != "" && !strings.HasSuffix(content, "\n") {
+			content += "\n"
+		}
+		return content + block, nil
+	}
+	end := strings.Index(content[begin:], endMarker)
+	if end < 0 {
+		return "", fmt.Errorf("found the %q marker without the %q marker", beginMarker, endMarker)
+	}
+	end += begin + len(endMarker)
+	if end < len(content) && content[end] == '\n' {
+		end++
+	}
+	return content[:begin] + block + content[end:], nil
+}
+
+// validateEntry returns an error if the names of the entry are not valid DNS names.
+func validateEntry(e entry) error {
+	for _, name := range []string{e.clustersetName, e.derivedName} {
+		if errs := validation.IsDNS1123Subdomain(name); len(errs) != 0 {
+			return fmt.Errorf("invalid DNS name %q: %s", name, strings.Join(errs, "; "))
+		}
+		for _, label := range strings.Split(name, ".") {
+			if errs := validation.IsDNS1123Label(label); len(errs) != 0 {
+				return fmt.Errorf("invalid DNS name %q: %s", name, strings.Join(errs, "; "))
+			}
+		}
+	}
+	return nil
+}
+
+// validateCorefile returns an error if the Corefile fragment is not well-formed, i.e. has an unterminated quote or
+// unbalanced braces, so that a broken fragment is never written for CoreDNS to load.
+func validateCorefile(content string) error {
+	depth := 0
+	for i, line := range strings.Split(content, "\n") {
+		inQuote := false
+		for _, c := range line {
+			if inQuote {
+				if c == '"' {
+					inQuote = false
+				}
+				continue
+			}
+			if c == '#' {
+				break
+			}
+			switch c {
+			case '"':
+				inQuote = true
+			case '{':
+				depth++
+			case '}':
+				depth--
+				if depth < 0 {
+					return fmt.Errorf("line %d: unexpected '}'", i+1)
+				}
+			}
+		}
+		if inQuote {
+			return fmt.Errorf("line %d: unterminated quote", i+1)
+		}
+	}
+	if depth != 0 {
+		return fmt.Errorf("%d unclosed '{'", depth)
+	}
+	return nil
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *Reconciler) SetupWithManager(mgr ctrl.Manager) error {
+	// All the rewrite rules are kept in one ConfigMap, so every MultiClusterService event maps to the ConfigMap.
+	return ctrl.NewControllerManagedBy(mgr).
+		Named(ControllerName).
+		Watches(&fleetnetv1alpha1.MultiClusterService{}, handler.EnqueueRequestsFromMapFunc(func(_ context.Context, _ client.Object) []reconcile.Request {
+			return []reconcile.Request{{NamespacedName: r.ConfigMap}}
+		})).
+		Complete(r)
+}
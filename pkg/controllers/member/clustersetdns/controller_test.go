@@ -0,0 +1,310 @@
+/*
+Copyright (c) Microsoft Corporation.
+Licensed under the MIT license.
+*/
+
+package clustersetdns
+
+import (
+	"context"
+	"log"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	fleetnetv1alpha1 "go.goms.io/fleet-networking/api/v1alpha1"
+	"go.goms.io/fleet-networking/pkg/common/objectmeta"
+)
+
+const (
+	fleetSystemNS = "fleet-system"
+	configMapKey  = "fleet.override"
+
+	userContent = "# hand-edited rewrite rule\nrewrite name exact legacy.example.com legacy.work.svc.cluster.local\n"
+)
+
+var (
+	configMapName = types.NamespacedName{Namespace: "kube-system", Name: "coredns-custom"}
+
+	appBlock = beginMarker + "\n" +
+		"rewrite stop {\n" +
+		"    name exact app.work.svc.clusterset.local work-app.fleet-system.svc.cluster.local\n" +
+		"    answer name work-app\\.fleet-system\\.svc\\.cluster\\.local app.work.svc.clusterset.local\n" +
+		"}\n" +
+		endMarker + "\n"
+)
+
+func TestMain(m *testing.M) {
+	// Add custom APIs to the runtime scheme
+	if err := fleetnetv1alpha1.AddToScheme(scheme.Scheme); err != nil {
+		log.Fatalf("failed to add custom APIs to the runtime scheme: %v", err)
+	}
+
+	os.Exit(m.Run())
+}
+
+func multiClusterService(namespace, name, serviceImport, derivedService string, valid bool, created time.Time) *fleetnetv1alpha1.MultiClusterService {
+	mcs := &fleetnetv1alpha1.MultiClusterService{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace:         namespace,
+			Name:              name,
+			CreationTimestamp: metav1.NewTime(created),
+		},
+		Spec: fleetnetv1alpha1.MultiClusterServiceSpec{
+			ServiceImport: fleetnetv1alpha1.ServiceImportRef{Name: serviceImport},
+		},
+	}
+	if derivedService != "" {
+		mcs.Labels = map[string]string{objectmeta.MultiClusterServiceLabelDerivedService: derivedService}
+	}
+	status := metav1.ConditionFalse
+	if valid {
+		status = metav1.ConditionTrue
+	}
+	mcs.Status.Conditions = []metav1.Condition{
+		{
+			Type:   string(fleetnetv1alpha1.MultiClusterServiceValid),
+			Status: status,
+			Reason: "Test",
+		},
+	}
+	return mcs
+}
+
+// TestReconcile tests the Reconciler.Reconcile method.
+func TestReconcile(t *testing.T) {
+	now := time.Now()
+	testCases := []struct {
+		name          string
+		objs          []client.Object
+		wantConfigMap *corev1.ConfigMap // nil when the ConfigMap should be absent
+	}{
+		{
+			name: "no multiClusterService and no configMap",
+			objs: []client.Object{
+				multiClusterService("work", "app", "app", "", false, now),
+			},
+		},
+		{
+			name: "should create the configMap",
+			objs: []client.Object{
+				multiClusterService("work", "app", "app", "work-app", true, now),
+			},
+			wantConfigMap: &corev1.ConfigMap{
+				Data: map[string]string{configMapKey: appBlock},
+			},
+		},
+		{
+			name: "should add the entries without touching the existing ones",
+			objs: []client.Object{
+				multiClusterService("work", "app", "app", "work-app", true, now),
+				&corev1.ConfigMap{
+					ObjectMeta: metav1.ObjectMeta{Namespace: configMapName.Namespace, Name: configMapName.Name},
+					Data: map[string]string{
+						configMapKey:     userContent,
+						"other.override": "log",
+					},
+				},
+			},
+			wantConfigMap: &corev1.ConfigMap{
+				Data: map[string]string{
+					configMapKey:     userContent + appBlock,
+					"other.override": "log",
+				},
+			},
+		},
+		{
+			name: "should remove the entries of the deleted multiClusterServices",
+			objs: []client.Object{
+				&corev1.ConfigMap{
+					ObjectMeta: metav1.ObjectMeta{Namespace: configMapName.Namespace, Name: configMapName.Name},
+					Data: map[string]string{
+						configMapKey:     userContent + appBlock + "# trailing user comment\n",
+						"other.override": "log",
+					},
+				},
+			},
+			wantConfigMap: &corev1.ConfigMap{
+				Data: map[string]string{
+					configMapKey:     userContent + "# trailing user comment\n",
+					"other.override": "log",
+				},
+			},
+		},
+		{
+			name: "should remove the key holding the entries only",
+			objs: []client.Object{
+				multiClusterService("work", "app", "app", "work-app", false, now),
+				&corev1.ConfigMap{
+					ObjectMeta: metav1.ObjectMeta{Namespace: configMapName.Namespace, Name: configMapName.Name},
+					Data: map[string]string{
+						configMapKey:     appBlock,
+						"other.override": "log",
+					},
+				},
+			},
+			wantConfigMap: &corev1.ConfigMap{
+				Data: map[string]string{
+					"other.override": "log",
+				},
+			},
+		},
+		{
+			name: "should map the name to the derived service of the oldest multiClusterService",
+			objs: []client.Object{
+				multiClusterService("work", "newer", "app", "work-newer", true, now),
+				multiClusterService("work", "app", "app", "work-app", true, now.Add(-time.Hour)),
+			},
+			wantConfigMap: &corev1.ConfigMap{
+				Data: map[string]string{configMapKey: appBlock},
+			},
+		},
+	}
+
+	ctx := context.Background()
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			fakeClient := fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(tc.objs...).Build()
+			r := &Reconciler{
+				MemberClient:         fakeClient,
+				ConfigMapReader:      fakeClient,
+				ConfigMap:            configMapName,
+				ConfigMapKey:         configMapKey,
+				FleetSystemNamespace: fleetSystemNS,
+			}
+			got, err := r.Reconcile(ctx, ctrl.Request{NamespacedName: configMapName})
+			if err != nil {
+				t.Fatalf("Reconcile() = %v, want no error", err)
+			}
+			if want := (ctrl.Result{RequeueAfter: resyncPeriod}); got != want {
+				t.Errorf("Reconcile() = %+v, want %+v", got, want)
+			}
+
+			configMap := &corev1.ConfigMap{}
+			err = fakeClient.Get(ctx, configMapName, configMap)
+			if tc.wantConfigMap == nil {
+				if !errors.IsNotFound(err) {
+					t.Fatalf("configMap Get() = %v, want not found", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("configMap Get() = %v, want no error", err)
+			}
+			if diff := cmp.Diff(tc.wantConfigMap.Data, configMap.Data); diff != "" {
+				t.Errorf("configMap data mismatch (-want, +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+// TestReconcile_BrokenMarkers tests that the Reconciler.Reconcile method does not write the ConfigMap when the
+// markers have been tampered with.
+func TestReconcile_BrokenMarkers(t *testing.T) {
+	content := userContent + beginMarker + "\nrewrite stop {\n"
+	configMap := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Namespace: configMapName.Namespace, Name: configMapName.Name},
+		Data:       map[string]string{configMapKey: content},
+	}
+	fakeClient := fake.NewClientBuilder().
+		WithScheme(scheme.Scheme).
+		WithObjects(configMap, multiClusterService("work", "app", "app", "work-app", true, time.Now())).
+		Build()
+	r := &Reconciler{
+		MemberClient:         fakeClient,
+		ConfigMapReader:      fakeClient,
+		ConfigMap:            configMapName,
+		ConfigMapKey:         configMapKey,
+		FleetSystemNamespace: fleetSystemNS,
+	}
+	ctx := context.Background()
+	if _, err := r.Reconcile(ctx, ctrl.Request{NamespacedName: configMapName}); err == nil {
+		t.Fatalf("Reconcile() = nil, want error")
+	}
+	got := &corev1.ConfigMap{}
+	if err := fakeClient.Get(ctx, configMapName, got); err != nil {
+		t.Fatalf("configMap Get() = %v, want no error", err)
+	}
+	if got.Data[configMapKey] != content {
+		t.Errorf("configMap data = %q, want unchanged %q", got.Data[configMapKey], content)
+	}
+}
+
+// TestValidateCorefile tests the validateCorefile function.
+func TestValidateCorefile(t *testing.T) {
+	testCases := []struct {
+		name    string
+		content string
+		wantErr bool
+	}{
+		{
+			name:    "generated block",
+			content: userContent + appBlock,
+		},
+		{
+			name:    "braces in comments and quotes",
+			content: "# {\nlog . \"{\"\n",
+		},
+		{
+			name:    "unclosed brace",
+			content: "rewrite stop {\n",
+			wantErr: true,
+		},
+		{
+			name:    "unexpected closing brace",
+			content: "}\nrewrite stop {\n",
+			wantErr: true,
+		},
+		{
+			name:    "unterminated quote",
+			content: "log . \"{\n",
+			wantErr: true,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if err := validateCorefile(tc.content); (err != nil) != tc.wantErr {
+				t.Errorf("validateCorefile() = %v, want error %t", err, tc.wantErr)
+			}
+		})
+	}
+}
+
+// TestValidateEntry tests the validateEntry function.
+func TestValidateEntry(t *testing.T) {
+	testCases := []struct {
+		name    string
+		entry   entry
+		wantErr bool
+	}{
+		{
+			name:  "valid names",
+			entry: entry{clustersetName: "app.work.svc.clusterset.local", derivedName: "work-app.fleet-system.svc.cluster.local"},
+		},
+		{
+			name: "derived service name longer than a DNS label",
+			entry: entry{
+				clustersetName: "app.work.svc.clusterset.local",
+				derivedName:    "a-very-long-namespace-name-for-the-multi-cluster-service-and-app.fleet-system.svc.cluster.local",
+			},
+			wantErr: true,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if err := validateEntry(tc.entry); (err != nil) != tc.wantErr {
+				t.Errorf("validateEntry() = %v, want error %t", err, tc.wantErr)
+			}
+		})
+	}
+}
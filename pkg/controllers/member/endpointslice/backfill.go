@@ -0,0 +1,128 @@
+/*
+Copyright (c) Microsoft Corporation.
+Licensed under the MIT license.
+*/
+
+package endpointslice
+
+import (
+	"context"
+	"time"
+
+	"golang.org/x/time/rate"
+	discoveryv1 "k8s.io/api/discovery/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"go.goms.io/fleet-networking/pkg/common/metrics"
+	"go.goms.io/fleet-networking/pkg/common/objectmeta"
+)
+
+const (
+	// DefaultMetricsAnnotationsBackfillQPS is the number of EndpointSlices stamped per second by the metrics
+	// annotations backfill.
+	DefaultMetricsAnnotationsBackfillQPS = 10
+
+	defaultMetricsAnnotationsBackfillPageSize = 500
+)
+
+// MetricsAnnotationsBackfill stamps the exported EndpointSlices which miss the last seen generation and timestamp
+// annotations, e.g. those exported before the annotations were introduced, with their current generation and the
+// current time. It runs once, in the background of the controllers, and stops when all the EndpointSlices have been
+// visited; the EndpointSlice controller repairs the annotations on reconciliation anyway, so that a failed or
+// interrupted backfill is not fatal.
+type MetricsAnnotationsBackfill struct {
+	MemberClient client.Client
+	// APIReader lists the EndpointSlices from the API server page by page, as the cache does not paginate.
+	APIReader client.Reader
+	// Limiter limits the rate at which the EndpointSlices are stamped; nil means no limit.
+	Limiter *rate.Limiter
+	// PageSize is the number of EndpointSlices listed per page; 0 means defaultMetricsAnnotationsBackfillPageSize.
+	PageSize int64
+}
+
+// BackfillSummary counts the exported EndpointSlices visited by the metrics annotations backfill, per outcome.
+type BackfillSummary struct {
+	// Exported is the number of the exported EndpointSlices, i.e. those with a unique name assigned.
+	Exported int
+	// Stamped is the number of the exported EndpointSlices stamped with the annotations.
+	Stamped int
+	// Skipped is the number of the exported EndpointSlices which changed, or were deleted, before they could be
+	// stamped; the EndpointSlice controller repairs their annotations on reconciliation.
+	Skipped int
+}
+
+// Start runs the backfill as a runnable of a controller manager; a failure is logged only, so that the controllers
+// keep running.
+func (b *MetricsAnnotationsBackfill) Start(ctx context.Context) error {
+	if _, err := b.Run(ctx); err != nil {
+		klog.ErrorS(err, "Failed to backfill the metrics annotations of the exported endpoint slices; the remaining ones are repaired on reconciliation")
+	}
+	return nil
+}
+
+// Run stamps the exported EndpointSlices missing the metrics annotations across all the namespaces; it stops at the
+// first error, and returns the EndpointSlices visited so far.
+func (b *MetricsAnnotationsBackfill) Run(ctx context.Context) (BackfillSummary, error) {
+	var summary BackfillSummary
+	pageSize := b.PageSize
+	if pageSize <= 0 {
+		pageSize = defaultMetricsAnnotationsBackfillPageSize
+	}
+	continueToken := ""
+	for {
+		endpointSlices := &discoveryv1.EndpointSliceList{}
+		if err := b.APIReader.List(ctx, endpointSlices, client.Limit(pageSize), client.Continue(continueToken)); err != nil {
+			klog.ErrorS(err, "Failed to list endpoint slices")
+			return summary, err
+		}
+		for i := range endpointSlices.Items {
+			if err := b.stamp(ctx, &endpointSlices.Items[i], &summary); err != nil {
+				return summary, err
+			}
+		}
+		if continueToken = endpointSlices.Continue; continueToken == "" {
+			break
+		}
+	}
+	klog.InfoS("Backfilled the metrics annotations of the exported endpoint slices",
+		"exported", summary.Exported,
+		"stamped", summary.Stamped,
+		"skipped", summary.Skipped)
+	return summary, nil
+}
+
+// stamp stamps an EndpointSlice with the metrics annotations if it is exported and misses any of them.
+func (b *MetricsAnnotationsBackfill) stamp(ctx context.Context, endpointSlice *discoveryv1.EndpointSlice, summary *BackfillSummary) error {
+	if _, ok := endpointSlice.Annotations[objectmeta.ExportedObjectAnnotationUniqueName]; !ok {
+		return nil
+	}
+	summary.Exported++
+	_, hasGeneration := endpointSlice.Annotations[metrics.MetricsAnnotationLastSeenGeneration]
+	_, hasTimestamp := endpointSlice.Annotations[metrics.MetricsAnnotationLastSeenTimestamp]
+	if hasGeneration && hasTimestamp {
+		return nil
+	}
+
+	if b.Limiter != nil {
+		if err := b.Limiter.Wait(ctx); err != nil {
+			return err
+		}
+	}
+	// The patch is guarded by the resource version, so that an EndpointSlice which has changed since it was listed,
+	// and whose generation may hence be stale, is left to the EndpointSlice controller.
+	patch := client.MergeFromWithOptions(endpointSlice.DeepCopy(), client.MergeFromWithOptimisticLock{})
+	stampLastSeenGenerationAndTimestamp(endpointSlice, time.Now())
+	if err := b.MemberClient.Patch(ctx, endpointSlice, patch); err != nil {
+		if errors.IsConflict(err) || errors.IsNotFound(err) {
+			klog.V(2).InfoS("Endpoint slice changed before its metrics annotations were backfilled; skipped", "endpointSlice", klog.KObj(endpointSlice))
+			summary.Skipped++
+			return nil
+		}
+		klog.ErrorS(err, "Failed to backfill the metrics annotations of endpoint slice", "endpointSlice", klog.KObj(endpointSlice))
+		return err
+	}
+	summary.Stamped++
+	return nil
+}
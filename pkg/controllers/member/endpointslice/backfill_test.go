@@ -0,0 +1,203 @@
+/*
+Copyright (c) Microsoft Corporation.
+Licensed under the MIT license.
+*/
+
+package endpointslice
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	discoveryv1 "k8s.io/api/discovery/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+
+	"go.goms.io/fleet-networking/pkg/common/metrics"
+	"go.goms.io/fleet-networking/pkg/common/objectmeta"
+)
+
+// pagedListInterceptor emulates the pagination of the EndpointSlice lists, which the fake client does not support;
+// the continue token is the index of the first EndpointSlice of the next page. The options of each list are recorded.
+func pagedListInterceptor(lists *[]client.ListOptions) interceptor.Funcs {
+	return interceptor.Funcs{
+		List: func(ctx context.Context, c client.WithWatch, list client.ObjectList, opts ...client.ListOption) error {
+			listOpts := client.ListOptions{}
+			listOpts.ApplyOptions(opts)
+			*lists = append(*lists, listOpts)
+			if err := c.List(ctx, list); err != nil {
+				return err
+			}
+			endpointSlices := list.(*discoveryv1.EndpointSliceList)
+			start := 0
+			if listOpts.Continue != "" {
+				var err error
+				if start, err = strconv.Atoi(listOpts.Continue); err != nil {
+					return errors.NewBadRequest("invalid continue token")
+				}
+			}
+			end := len(endpointSlices.Items)
+			if listOpts.Limit > 0 && start+int(listOpts.Limit) < end {
+				end = start + int(listOpts.Limit)
+				endpointSlices.Continue = strconv.Itoa(end)
+			}
+			endpointSlices.Items = endpointSlices.Items[start:end]
+			return nil
+		},
+	}
+}
+
+// backfillEndpointSlice returns an EndpointSlice with the given annotations.
+func backfillEndpointSlice(name string, annotations map[string]string) *discoveryv1.EndpointSlice {
+	return &discoveryv1.EndpointSlice{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace:   memberUserNS,
+			Name:        name,
+			Generation:  endpointSliceGeneration,
+			Annotations: annotations,
+		},
+		AddressType: discoveryv1.AddressTypeIPv4,
+	}
+}
+
+// TestMetricsAnnotationsBackfill_Batching tests that the metrics annotations backfill visits all the EndpointSlices
+// page by page, stamps the exported ones missing the annotations only, and skips those changed since they were listed.
+func TestMetricsAnnotationsBackfill_Batching(t *testing.T) {
+	const (
+		annotatedTimestamp = "2024-01-02T03:04:05Z"
+		conflictedName     = "missing-3"
+	)
+	objs := []client.Object{
+		// Not exported.
+		backfillEndpointSlice("unexported", nil),
+		// Exported, with the annotations already.
+		backfillEndpointSlice("annotated", map[string]string{
+			objectmeta.ExportedObjectAnnotationUniqueName: "bravelion-work-annotated",
+			metrics.MetricsAnnotationLastSeenGeneration:   "1",
+			metrics.MetricsAnnotationLastSeenTimestamp:    annotatedTimestamp,
+		}),
+		// Exported, with the timestamp annotation only.
+		backfillEndpointSlice("partial", map[string]string{
+			objectmeta.ExportedObjectAnnotationUniqueName: "bravelion-work-partial",
+			metrics.MetricsAnnotationLastSeenTimestamp:    annotatedTimestamp,
+		}),
+	}
+	for i := 0; i < 4; i++ {
+		name := fmt.Sprintf("missing-%d", i)
+		objs = append(objs, backfillEndpointSlice(name, map[string]string{
+			objectmeta.ExportedObjectAnnotationUniqueName: "bravelion-work-" + name,
+		}))
+	}
+
+	ctx := context.Background()
+	var lists []client.ListOptions
+	listClient := fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(objs...).
+		WithInterceptorFuncs(pagedListInterceptor(&lists)).
+		Build()
+	var patched []string
+	fakeMemberClient := fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(objs...).
+		WithInterceptorFuncs(interceptor.Funcs{
+			Patch: func(ctx context.Context, c client.WithWatch, obj client.Object, patch client.Patch, opts ...client.PatchOption) error {
+				// One of the EndpointSlices changes after it is listed.
+				if obj.GetName() == conflictedName {
+					return errors.NewConflict(discoveryv1.Resource("endpointslices"), obj.GetName(), fmt.Errorf("the object has been modified"))
+				}
+				patched = append(patched, obj.GetName())
+				return c.Patch(ctx, obj, patch, opts...)
+			},
+		}).
+		Build()
+	backfill := &MetricsAnnotationsBackfill{
+		MemberClient: fakeMemberClient,
+		APIReader:    listClient,
+		PageSize:     2,
+	}
+
+	summary, err := backfill.Run(ctx)
+	if err != nil {
+		t.Fatalf("Run(), got %v, want no error", err)
+	}
+	wantSummary := BackfillSummary{Exported: 6, Stamped: 4, Skipped: 1}
+	if diff := cmp.Diff(summary, wantSummary); diff != "" {
+		t.Errorf("Run() summary (-got, +want): %s", diff)
+	}
+	// 7 EndpointSlices, 2 per page.
+	if got, want := len(lists), 4; got != want {
+		t.Errorf("List() calls, got %d, want %d", got, want)
+	}
+	for i, listOpts := range lists {
+		if listOpts.Limit != 2 {
+			t.Errorf("List() call %d limit, got %d, want 2", i, listOpts.Limit)
+		}
+	}
+	if diff := cmp.Diff(patched, []string{"missing-0", "missing-1", "missing-2", "partial"}); diff != "" {
+		t.Errorf("patched endpointSlices (-got, +want): %s", diff)
+	}
+
+	for _, name := range []string{"missing-0", "partial"} {
+		endpointSlice := &discoveryv1.EndpointSlice{}
+		if err := fakeMemberClient.Get(ctx, client.ObjectKey{Namespace: memberUserNS, Name: name}, endpointSlice); err != nil {
+			t.Fatalf("endpointSlice Get(%s), got %v, want no error", name, err)
+		}
+		if got, want := endpointSlice.Annotations[metrics.MetricsAnnotationLastSeenGeneration], strconv.Itoa(endpointSliceGeneration); got != want {
+			t.Errorf("endpointSlice %s last seen generation, got %q, want %q", name, got, want)
+		}
+		if got := endpointSlice.Annotations[metrics.MetricsAnnotationLastSeenTimestamp]; got == "" || got == annotatedTimestamp {
+			t.Errorf("endpointSlice %s last seen timestamp, got %q, want the backfill time", name, got)
+		}
+	}
+	annotated := &discoveryv1.EndpointSlice{}
+	if err := fakeMemberClient.Get(ctx, client.ObjectKey{Namespace: memberUserNS, Name: "annotated"}, annotated); err != nil {
+		t.Fatalf("endpointSlice Get(annotated), got %v, want no error", err)
+	}
+	if got := annotated.Annotations[metrics.MetricsAnnotationLastSeenTimestamp]; got != annotatedTimestamp {
+		t.Errorf("annotated endpointSlice last seen timestamp, got %q, want %q", got, annotatedTimestamp)
+	}
+}
+
+// TestMetricsAnnotationsBackfill_ListError tests that the metrics annotations backfill stops at a list error and
+// returns the EndpointSlices visited so far.
+func TestMetricsAnnotationsBackfill_ListError(t *testing.T) {
+	ctx := context.Background()
+	lists := 0
+	objs := []client.Object{
+		backfillEndpointSlice("missing-0", map[string]string{objectmeta.ExportedObjectAnnotationUniqueName: "bravelion-work-missing-0"}),
+		backfillEndpointSlice("missing-1", map[string]string{objectmeta.ExportedObjectAnnotationUniqueName: "bravelion-work-missing-1"}),
+	}
+	var paged []client.ListOptions
+	pager := pagedListInterceptor(&paged)
+	fakeMemberClient := fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(objs...).
+		WithInterceptorFuncs(interceptor.Funcs{
+			List: func(ctx context.Context, c client.WithWatch, list client.ObjectList, opts ...client.ListOption) error {
+				lists++
+				if lists > 1 {
+					return errors.NewServiceUnavailable("member API server is unavailable")
+				}
+				return pager.List(ctx, c, list, opts...)
+			},
+		}).
+		Build()
+	backfill := &MetricsAnnotationsBackfill{
+		MemberClient: fakeMemberClient,
+		APIReader:    fakeMemberClient,
+		PageSize:     1,
+	}
+
+	summary, err := backfill.Run(ctx)
+	if !errors.IsServiceUnavailable(err) {
+		t.Fatalf("Run(), got %v, want service unavailable error", err)
+	}
+	if diff := cmp.Diff(summary, BackfillSummary{Exported: 1, Stamped: 1}); diff != "" {
+		t.Errorf("Run() summary (-got, +want): %s", diff)
+	}
+	if err := backfill.Start(ctx); err != nil {
+		t.Errorf("Start(), got %v, want no error", err)
+	}
+}
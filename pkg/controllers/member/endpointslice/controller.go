@@ -9,24 +9,37 @@ package endpointslice
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"strconv"
 	"time"
 
+	"github.com/prometheus/client_golang/prometheus"
+	corev1 "k8s.io/api/core/v1"
 	discoveryv1 "k8s.io/api/discovery/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
 	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/labels"
-	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
 	"k8s.io/klog/v2"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
-	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/event"
 	"sigs.k8s.io/controller-runtime/pkg/handler"
+	ctrlmetrics "sigs.k8s.io/controller-runtime/pkg/metrics"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
 
 	fleetnetv1alpha1 "go.goms.io/fleet-networking/api/v1alpha1"
+	"go.goms.io/fleet-networking/pkg/common/condition"
+	"go.goms.io/fleet-networking/pkg/common/fieldmanager"
+	"go.goms.io/fleet-networking/pkg/common/hubclient"
+	"go.goms.io/fleet-networking/pkg/common/hubconfig"
 	"go.goms.io/fleet-networking/pkg/common/metrics"
 	"go.goms.io/fleet-networking/pkg/common/objectmeta"
 	"go.goms.io/fleet-networking/pkg/common/uniquename"
@@ -43,8 +56,58 @@ const (
 	shouldUnexportEndpointSliceOp skipOrUnexportEndpointSliceOp = 1
 	// noSkipOrUnexportNeededOp notes that an EndpointSlice should not be skipped or unexported.
 	continueReconcileOp skipOrUnexportEndpointSliceOp = 2
+	// shouldSoftUnexportEndpointSliceOp notes that an EndpointSlice should be unexported for a reason which may be
+	// transient; its EndpointSliceExport is marked as a deletion candidate instead of being deleted.
+	shouldSoftUnexportEndpointSliceOp skipOrUnexportEndpointSliceOp = 3
 )
 
+const (
+	// ControllerName is the name of the Reconciler.
+	ControllerName = "endpointslice-controller"
+
+	endpointsTruncatedCondReason    = "EndpointsTruncated"
+	endpointsNotTruncatedCondReason = "EndpointsNotTruncated"
+
+	hostNetworkEndpointsExcludedCondReason   = "HostNetworkEndpointsExcluded"
+	noHostNetworkEndpointsExcludedCondReason = "NoHostNetworkEndpointsExcluded"
+)
+
+const (
+	// KubeControllerManagerEndpointSliceManager is the managed-by label value of the EndpointSlices managed by the
+	// EndpointSlice controller of kube-controller-manager.
+	KubeControllerManagerEndpointSliceManager = "endpointslice-controller.k8s.io"
+	// AllEndpointSliceManagers allows exporting the EndpointSlices regardless of the controllers managing them.
+	AllEndpointSliceManagers = "all"
+)
+
+var (
+	// endpointSliceExportLatency is a Prometheus histogram metric bundle that measures the time it takes for the
+	// EndpointSlice controller to export a new generation of an EndpointSlice. The stopwatch starts when the
+	// generation is first seen (as recorded in the last seen timestamp annotation), and stops when the corresponding
+	// EndpointSliceExport is successfully applied in the hub cluster.
+	//
+	// The data points are labeled by namespace only to keep the cardinality bounded.
+	endpointSliceExportLatency = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Namespace: metrics.MetricsNamespace,
+			Subsystem: metrics.MetricsSubsystem,
+			Name:      "endpointslice_export_latency_seconds",
+			Help:      "The latency between an endpointslice generation change being observed and its export to the hub cluster",
+			Buckets:   metrics.ExportLatencySecondsBuckets,
+		},
+		[]string{
+			// The namespace of the exported EndpointSlice.
+			"namespace",
+		},
+	)
+)
+
+func init() {
+	// Register endpointSliceExportLatency (fleet_networking_endpointslice_export_latency_seconds) metric
+	// with the controller runtime global metrics registry.
+	ctrlmetrics.Registry.MustRegister(endpointSliceExportLatency)
+}
+
 // Reconciler reconciles the export of an EndpointSlice.
 type Reconciler struct {
 	// The ID of the member cluster.
@@ -53,10 +116,34 @@ type Reconciler struct {
 	HubClient       client.Client
 	// The namespace reserved for the current member cluster in the hub cluster.
 	HubNamespace string
+	// HubExportShards is the number of namespaces in the hub cluster, the member cluster namespace included, across
+	// which the exports of the member cluster are sharded by Service; at most 1 keeps all the exports in HubNamespace.
+	HubExportShards int
+	// MaxExportedEndpointsPerService is the maximum number of ready endpoints exported per Service across all its
+	// EndpointSlices; 0 means no limit.
+	MaxExportedEndpointsPerService int
+	// ExportedEndpointSliceManagers are the values of the managed-by label (endpointslice.kubernetes.io/managed-by)
+	// of the EndpointSlices to export, so that the EndpointSlices managed by other controllers (e.g., a service
+	// mirroring controller) are not exported twice; empty means the EndpointSlices are exported regardless of
+	// the controllers managing them.
+	ExportedEndpointSliceManagers []string
+	// PropagateTerminatingEndpoints exports the terminating endpoints which are still serving along with their
+	// conditions, so that the importing clusters can drain them gracefully; it should only be enabled once all the
+	// member clusters are able to import them as not ready.
+	PropagateTerminatingEndpoints bool
+	// HubServerChanges receives an event every time the hub clients are rotated to another hub server, so that all
+	// the EndpointSlices are exported again, as the new hub server might not have them. Nil disables the re-export.
+	HubServerChanges <-chan event.GenericEvent
+	Recorder         record.EventRecorder
 }
 
 //+kubebuilder:rbac:groups=networking.fleet.azure.com,resources=endpointsliceexports,verbs=get;list;watch;create;update;patch;delete
-//+kubebuilder:rbac:groups=discovery.k8s.io,resources=endpointslices,verbs=get;list;watch
+//+kubebuilder:rbac:groups=discovery.k8s.io,resources=endpointslices,verbs=get;list;watch;update;patch
+//+kubebuilder:rbac:groups=networking.fleet.azure.com,resources=serviceexports,verbs=get;list;watch
+//+kubebuilder:rbac:groups=networking.fleet.azure.com,resources=serviceexports/status,verbs=get;update;patch
+//+kubebuilder:rbac:groups="",resources=events,verbs=create;patch
+//+kubebuilder:rbac:groups="",resources=pods,verbs=get;list;watch
+//+kubebuilder:rbac:groups="",resources=namespaces,verbs=get;list;watch
 
 // Reconcile exports an EndpointSlice.
 func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
@@ -108,6 +195,15 @@ func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Resu
 			return ctrl.Result{}, err
 		}
 		return ctrl.Result{}, nil
+	case shouldSoftUnexportEndpointSliceOp:
+		// Mark the EndpointSliceExport as a deletion candidate; the EndpointSlice keeps its unique name, so that it is
+		// exported again under the same name, which clears the mark, if the reason turns out to be transient.
+		klog.V(4).InfoS("Endpoint slice should be soft unexported", "endpointSlice", endpointSliceRef)
+		if err := r.markEndpointSliceExportAsDeletionCandidate(ctx, &endpointSlice); err != nil {
+			klog.ErrorS(err, "Failed to mark the endpoint slice export as a deletion candidate", "endpointSlice", endpointSliceRef)
+			return ctrl.Result{}, err
+		}
+		return ctrl.Result{}, nil
 	}
 
 	// Retrieve the unique name assigned; if none has been assigned, or the one assigned is not valid, possibly due
@@ -129,122 +225,611 @@ func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Resu
 	// If the two values are not present or not valid, annotate EndpointSlice with new values.
 	//
 	// Note that the two values are not tamperproof.
+	// The annotations are missing on the EndpointSlices exported before they were introduced; they are repaired here
+	// regardless of the metrics annotations backfill, and a failure to repair them does not block the export.
 	exportedSince, err := r.collectAndVerifyLastSeenGenerationAndTimestamp(ctx, &endpointSlice, startTime)
 	if err != nil {
-		klog.Warning("Failed to annotate last seen generation and timestamp", "endpointSlice", endpointSliceRef)
+		klog.ErrorS(err, "Failed to annotate last seen generation and timestamp", "endpointSlice", endpointSliceRef)
 	}
 
-	// Create an EndpointSliceExport in the hub cluster if the EndpointSlice has never been exported; otherwise
-	// update the corresponding EndpointSliceExport.
-	extractedEndpoints := extractEndpointsFromEndpointSlice(&endpointSlice)
-	endpointSliceExport := fleetnetv1alpha1.EndpointSliceExport{
-		ObjectMeta: metav1.ObjectMeta{
-			Namespace: r.HubNamespace,
-			Name:      fleetUniqueName,
-		},
+	// Retrieve the name under which the owner Service is exported.
+	svcNamespace := ownerServiceNamespace(&endpointSlice)
+	svcName := endpointSlice.Labels[discoveryv1.LabelServiceName]
+	svcExport := fleetnetv1alpha1.ServiceExport{}
+	if err := r.MemberClient.Get(ctx, types.NamespacedName{Namespace: svcNamespace, Name: svcName}, &svcExport); err != nil {
+		if errors.IsNotFound(err) {
+			// The ServiceExport has been deleted since the EndpointSlice was checked, e.g. along with its namespace;
+			// the deletion enqueues the EndpointSlice again, which will then be unexported, so there is no need to
+			// retry.
+			klog.V(2).InfoS("Service export is deleted; skip the export of the endpoint slice", "endpointSlice", endpointSliceRef, "serviceExport", klog.KRef(svcNamespace, svcName))
+			return ctrl.Result{}, nil
+		}
+		klog.ErrorS(err, "Failed to get service export", "endpointSlice", endpointSliceRef, "serviceExport", klog.KRef(svcNamespace, svcName))
+		return ctrl.Result{}, err
 	}
+	exportedSvcName := objectmeta.ExportedServiceName(&svcExport)
+
+	// Select the endpoints to export; if the Service has more ready endpoints than allowed, only a subset of them
+	// is exported.
+	endpoints, totalEndpointCount, err := r.selectEndpointsToExport(ctx, &svcExport, &endpointSlice)
+	if err != nil {
+		klog.ErrorS(err, "Failed to select the endpoints to export", "endpointSlice", endpointSliceRef, "serviceExport", klog.KObj(&svcExport))
+		return ctrl.Result{}, err
+	}
+	// Exclude the endpoints backed by pods in the host network, whose addresses are the IPs of their nodes, unless the
+	// ServiceExport opts in to exporting them.
+	endpoints, excludedEndpointCount, err := r.excludeHostNetworkEndpoints(ctx, &svcExport, &endpointSlice, endpoints)
+	if err != nil {
+		klog.ErrorS(err, "Failed to exclude the host network endpoints", "endpointSlice", endpointSliceRef, "serviceExport", klog.KObj(&svcExport))
+		return ctrl.Result{}, err
+	}
+
+	// Apply the EndpointSliceExport in the hub cluster.
+	endpointSliceExportKey := types.NamespacedName{Namespace: r.hubExportNamespace(&endpointSlice), Name: fleetUniqueName}
 	klog.V(2).InfoS("Endpoint slice will be exported",
 		"endpointSlice", endpointSliceRef,
-		"endpointSliceExport", klog.KObj(&endpointSliceExport))
-	createOrUpdateOp, err := controllerutil.CreateOrUpdate(ctx, r.HubClient, &endpointSliceExport, func() error {
-		// Set up an EndpointSliceReference and only when an EndpointSliceExport is first created; this is because
-		// most fields in EndpointSliceReference should be immutable after creation.
-		if endpointSliceExport.CreationTimestamp.IsZero() {
-			endpointSliceReference := fleetnetv1alpha1.FromMetaObjects(r.MemberClusterID,
-				endpointSlice.TypeMeta, endpointSlice.ObjectMeta, metav1.NewTime(exportedSince))
-			endpointSliceExport.Spec.EndpointSliceReference = endpointSliceReference
-		}
-
-		// Return an error if an attempt is made to update an EndpointSliceExport that references a different
-		// EndpointSlice from the one that is being reconciled. This usually happens when one unique name is assigned
-		// to multiple EndpointSliceExports, either by chance or through direct manipulation.
-		if !isEndpointSliceExportLinkedWithEndpointSlice(&endpointSliceExport, &endpointSlice) {
-			return errors.NewAlreadyExists(
-				schema.GroupResource{Group: fleetnetv1alpha1.GroupVersion.Group, Resource: "EndpointSliceExport"},
-				fleetUniqueName,
-			)
-		}
-
-		endpointSliceExport.Spec.AddressType = discoveryv1.AddressTypeIPv4
-		endpointSliceExport.Spec.Endpoints = extractedEndpoints
-		endpointSliceExport.Spec.Ports = endpointSlice.Ports
-		endpointSliceExport.Spec.OwnerServiceReference = fleetnetv1alpha1.OwnerServiceReference{
-			// The owner Service is guaranteed to reside in the same namespace as the EndpointSlice to export.
-			Namespace:      endpointSlice.Namespace,
-			Name:           endpointSlice.Labels[discoveryv1.LabelServiceName],
-			NamespacedName: fmt.Sprintf("%s/%s", endpointSlice.Namespace, endpointSlice.Labels[discoveryv1.LabelServiceName]),
-		}
-
-		endpointSliceExport.Spec.EndpointSliceReference.UpdateFromMetaObject(endpointSlice.ObjectMeta, metav1.NewTime(exportedSince))
-		return nil
-	})
-	switch {
-	case errors.IsAlreadyExists(err):
-		// Remove the unique name annotation; a new one will be assigned in future reciliation attempts.
-		klog.V(2).InfoS("The unique name assigned to the endpoint slice has been used; it will be removed", "endpointSlice", endpointSliceRef)
-		delete(endpointSlice.Annotations, objectmeta.ExportedObjectAnnotationUniqueName)
-		if err := r.MemberClient.Update(ctx, &endpointSlice); err != nil {
-			klog.ErrorS(err, "Failed to remove endpointslice unique name annotation", "endpointSlice", endpointSliceRef)
+		"endpointSliceExport", endpointSliceExportKey)
+
+	// Set up an EndpointSliceReference only when an EndpointSliceExport is first created; this is because
+	// most fields in EndpointSliceReference should be immutable after creation.
+	existingEndpointSliceExport := fleetnetv1alpha1.EndpointSliceExport{}
+	var endpointSliceReference fleetnetv1alpha1.ExportedObjectReference
+	switch err := r.HubClient.Get(ctx, endpointSliceExportKey, &existingEndpointSliceExport); {
+	case err == nil:
+		// Check if the existing EndpointSliceExport references a different EndpointSlice from the one that is being
+		// reconciled. This usually happens when one unique name is assigned to multiple EndpointSliceExports,
+		// either by chance or through direct manipulation.
+		if !isEndpointSliceExportLinkedWithEndpointSlice(&existingEndpointSliceExport, &endpointSlice) {
+			// Remove the unique name annotation; a new one will be assigned in future reciliation attempts.
+			klog.V(2).InfoS("The unique name assigned to the endpoint slice has been used; it will be removed", "endpointSlice", endpointSliceRef)
+			delete(endpointSlice.Annotations, objectmeta.ExportedObjectAnnotationUniqueName)
+			if err := r.MemberClient.Update(ctx, &endpointSlice); err != nil {
+				klog.ErrorS(err, "Failed to remove endpointslice unique name annotation", "endpointSlice", endpointSliceRef)
+				return ctrl.Result{}, err
+			}
+			return ctrl.Result{}, nil
+		}
+		// EndpointSliceExports written before the member agent switched to server-side apply have their fields
+		// owned by the Update operations of the agent; hand them over to the apply field manager, so that the
+		// fields the agent no longer sets (e.g., the endpoints removed from the EndpointSlice) are dropped.
+		if err := fieldmanager.UpgradeManagedFields(ctx, r.HubClient, &existingEndpointSliceExport,
+			objectmeta.MemberAgentFieldManager, objectmeta.MemberAgentLegacyFieldManager); err != nil {
+			klog.ErrorS(err, "Failed to upgrade the managed fields of endpointslice export", "endpointSlice", endpointSliceRef, "endpointSliceExport", endpointSliceExportKey)
 			return ctrl.Result{}, err
 		}
-		return ctrl.Result{}, nil
-	case err != nil:
-		klog.ErrorS(err,
-			"Failed to create/update endpointslice export",
-			"endpointSlice", endpointSliceRef,
-			"endpointSliceExport", klog.KObj(&endpointSliceExport),
-			"op", createOrUpdateOp)
+		endpointSliceReference = existingEndpointSliceExport.Spec.EndpointSliceReference
+		if _, ok := existingEndpointSliceExport.Annotations[objectmeta.EndpointSliceExportAnnotationDeletionCandidateSince]; ok {
+			// The EndpointSliceExport is applied without the deletion candidate annotation, which is dropped as it is
+			// owned by the member agent only.
+			klog.V(2).InfoS("The endpoint slice is exported again; the endpoint slice export is no longer a deletion candidate",
+				"endpointSlice", endpointSliceRef, "endpointSliceExport", endpointSliceExportKey)
+		}
+	case errors.IsNotFound(err):
+		endpointSliceReference = fleetnetv1alpha1.FromMetaObjects(r.MemberClusterID,
+			endpointSlice.TypeMeta, endpointSlice.ObjectMeta, metav1.NewTime(exportedSince))
+	default:
+		klog.ErrorS(err, "Failed to get endpointslice export", "endpointSlice", endpointSliceRef, "endpointSliceExport", endpointSliceExportKey)
 		return ctrl.Result{}, err
 	}
+	endpointSliceReference.UpdateFromMetaObject(endpointSlice.ObjectMeta, metav1.NewTime(exportedSince))
+
+	// Check if a data point of the export latency metric has been observed for the current generation of the
+	// EndpointSlice. The last observed generation is recorded on the EndpointSliceExport as a part of the export,
+	// so that no-op reconciliations (e.g. resyncs) and controller restarts never observe a generation twice.
+	currentGenerationStr := strconv.FormatInt(endpointSlice.Generation, 10)
+	isExportLatencyObserved := existingEndpointSliceExport.Annotations[metrics.MetricsAnnotationLastObservedGeneration] == currentGenerationStr
 
+	endpointSliceExport := BuildEndpointSliceExport(endpointSliceExportKey, r.MemberClusterID, &endpointSlice, endpoints,
+		endpointSliceReference, svcNamespace, exportedSvcName)
+	if excludedEndpointCount > 0 {
+		endpointSliceExport.Annotations[objectmeta.EndpointSliceExportAnnotationExcludedEndpoints] = strconv.Itoa(excludedEndpointCount)
+	}
+	if err := hubclient.CreateOrUpdateEndpointSliceExport(ctx, r.HubClient, &endpointSliceExport, "endpointSlice", endpointSliceRef); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	// Observe a data point for the endpointSliceExportLatency metric.
+	if !isExportLatencyObserved {
+		observeExportLatency(&endpointSlice, exportedSince, time.Now())
+	}
+
+	if err := r.updateEndpointsTruncatedCondition(ctx, &svcExport, totalEndpointCount); err != nil {
+		klog.ErrorS(err, "Failed to update the endpoints truncated condition", "endpointSlice", endpointSliceRef, "serviceExport", klog.KObj(&svcExport))
+		return ctrl.Result{}, err
+	}
+	if err := r.updateHostNetworkEndpointsExcludedCondition(ctx, &svcExport, excludedEndpointCount); err != nil {
+		klog.ErrorS(err, "Failed to update the host network endpoints excluded condition", "endpointSlice", endpointSliceRef, "serviceExport", klog.KObj(&svcExport))
+		return ctrl.Result{}, err
+	}
 	return ctrl.Result{}, nil
 }
 
 // SetupWithManager sets up the EndpointSlice controller with a controller manager.
 func (r *Reconciler) SetupWithManager(ctx context.Context, mgr ctrl.Manager) error {
-	// Enqueue EndpointSlices for processing when a ServiceExport changes.
+	// Enqueue EndpointSlices for processing when a ServiceExport changes. Note that on updates the handler runs on
+	// both the old and the new ServiceExports, so that the EndpointSlices from a namespace which is no longer allowed
+	// are enqueued as well.
 	eventHandlers := handler.EnqueueRequestsFromMapFunc(func(_ context.Context, o client.Object) []reconcile.Request {
+		svcExport, ok := o.(*fleetnetv1alpha1.ServiceExport)
+		if !ok {
+			return []reconcile.Request{}
+		}
+		endpointSlices, err := r.listEndpointSlicesOfServiceExport(ctx, svcExport)
+		if err != nil {
+			klog.ErrorS(err, "Failed to list endpoint slices in use by a service", "serviceExport", klog.KObj(svcExport))
+			return []reconcile.Request{}
+		}
+		return endpointSliceRequests(endpointSlices)
+	})
+
+	// Enqueue the other EndpointSlices of the same Service when an EndpointSlice changes, if the number of exported
+	// endpoints per Service is limited; a change in one EndpointSlice might change which endpoints are exported from
+	// the others.
+	siblingEventHandlers := handler.EnqueueRequestsFromMapFunc(func(_ context.Context, o client.Object) []reconcile.Request {
+		endpointSlice, ok := o.(*discoveryv1.EndpointSlice)
+		if !ok || r.MaxExportedEndpointsPerService <= 0 {
+			return []reconcile.Request{}
+		}
+		svcName, ok := endpointSlice.Labels[discoveryv1.LabelServiceName]
+		if !ok {
+			return []reconcile.Request{}
+		}
+		svcExport := &fleetnetv1alpha1.ServiceExport{}
+		svcExportKey := types.NamespacedName{Namespace: ownerServiceNamespace(endpointSlice), Name: svcName}
+		if err := r.MemberClient.Get(ctx, svcExportKey, svcExport); err != nil {
+			if !errors.IsNotFound(err) {
+				klog.ErrorS(err, "Failed to get service export", "endpointSlice", klog.KObj(endpointSlice), "serviceExport", svcExportKey)
+			}
+			return []reconcile.Request{}
+		}
+		endpointSlices, err := r.listEndpointSlicesOfServiceExport(ctx, svcExport)
+		if err != nil {
+			klog.ErrorS(err, "Failed to list endpoint slices in use by a service", "serviceExport", klog.KObj(svcExport))
+			return []reconcile.Request{}
+		}
+		return endpointSliceRequests(endpointSlices)
+	})
+
+	// EndpointSlice controller watches over EndpointSlice and ServiceExport objects.
+	b := ctrl.NewControllerManagedBy(mgr).
+		For(&discoveryv1.EndpointSlice{}).
+		Watches(&fleetnetv1alpha1.ServiceExport{}, eventHandlers, builder.WithPredicates(serviceExportEventFilter())).
+		Watches(&discoveryv1.EndpointSlice{}, siblingEventHandlers)
+	if r.HubServerChanges != nil {
+		// EndpointSlice controller watches over the hub server rotations, so that the EndpointSlices are exported to
+		// the new hub server.
+		b = b.WatchesRawSource(source.Channel(r.HubServerChanges, handler.EnqueueRequestsFromMapFunc(r.allExportedEndpointSlices)))
+	}
+	return b.Complete(r)
+}
+
+// allExportedEndpointSlices maps a hub server rotation to the EndpointSlices of all the ServiceExports in the member
+// cluster.
+func (r *Reconciler) allExportedEndpointSlices(ctx context.Context, _ client.Object) []reconcile.Request {
+	svcExportList := &fleetnetv1alpha1.ServiceExportList{}
+	if err := r.MemberClient.List(ctx, svcExportList); err != nil {
+		klog.ErrorS(err, "Failed to list service exports")
+		return nil
+	}
+	var requests []reconcile.Request
+	for i := range svcExportList.Items {
+		endpointSlices, err := r.listEndpointSlicesOfServiceExport(ctx, &svcExportList.Items[i])
+		if err != nil {
+			klog.ErrorS(err, "Failed to list endpoint slices in use by a service", "serviceExport", klog.KObj(&svcExportList.Items[i]))
+			continue
+		}
+		requests = append(requests, endpointSliceRequests(endpointSlices)...)
+	}
+	return requests
+}
+
+// serviceExportEventFilter drops the ServiceExport updates which change the weight annotation only; the weight is
+// exported with the InternalServiceExport alone, so that a weight change does not have every EndpointSlice of the
+// Service exported again.
+func serviceExportEventFilter() predicate.Funcs {
+	return predicate.Funcs{
+		UpdateFunc: func(e event.UpdateEvent) bool {
+			return !isOnlyWeightChanged(e.ObjectOld, e.ObjectNew)
+		},
+	}
+}
+
+// isOnlyWeightChanged returns if two versions of a ServiceExport differ in the weight annotation only, besides the
+// resource version and the managed fields.
+func isOnlyWeightChanged(oldObj, newObj client.Object) bool {
+	oldSvcExport, ok := oldObj.(*fleetnetv1alpha1.ServiceExport)
+	if !ok {
+		return false
+	}
+	newSvcExport, ok := newObj.(*fleetnetv1alpha1.ServiceExport)
+	if !ok {
+		return false
+	}
+	if oldSvcExport.Annotations[objectmeta.ServiceExportAnnotationWeight] == newSvcExport.Annotations[objectmeta.ServiceExportAnnotationWeight] {
+		return false
+	}
+	oldSvcExport, newSvcExport = oldSvcExport.DeepCopy(), newSvcExport.DeepCopy()
+	for _, svcExport := range []*fleetnetv1alpha1.ServiceExport{oldSvcExport, newSvcExport} {
+		svcExport.ResourceVersion = ""
+		svcExport.ManagedFields = nil
+		delete(svcExport.Annotations, objectmeta.ServiceExportAnnotationWeight)
+		if len(svcExport.Annotations) == 0 {
+			svcExport.Annotations = nil
+		}
+	}
+	return equality.Semantic.DeepEqual(oldSvcExport, newSvcExport)
+}
+
+// listEndpointSlicesOfServiceExport lists the EndpointSlices in use by the Service of a ServiceExport, i.e., the
+// EndpointSlices in the namespace of the ServiceExport, and the EndpointSlices from the allowed namespaces which
+// claim the Service as their owner.
+func (r *Reconciler) listEndpointSlicesOfServiceExport(ctx context.Context, svcExport *fleetnetv1alpha1.ServiceExport) ([]discoveryv1.EndpointSlice, error) {
+	endpointSlices := []discoveryv1.EndpointSlice{}
+	namespaces := append([]string{svcExport.Namespace}, allowedEndpointSliceNamespaces(svcExport)...)
+	for _, ns := range namespaces {
 		endpointSliceList := &discoveryv1.EndpointSliceList{}
 		listOpts := client.ListOptions{
 			LabelSelector: labels.SelectorFromSet(labels.Set{
-				discoveryv1.LabelServiceName: o.GetName(),
+				discoveryv1.LabelServiceName: svcExport.Name,
 			}),
-			Namespace: o.GetNamespace(),
+			Namespace: ns,
 		}
 		if err := r.MemberClient.List(ctx, endpointSliceList, &listOpts); err != nil {
-			klog.ErrorS(err,
-				"Failed to list endpoint slices in use by a service",
-				"serviceExport", klog.KRef(o.GetNamespace(), o.GetName()),
-			)
-			return []reconcile.Request{}
+			return nil, fmt.Errorf("failed to list endpoint slices in namespace %s: %w", ns, err)
 		}
-		reqs := []reconcile.Request{}
-		for _, endpointSlice := range endpointSliceList.Items {
-			reqs = append(reqs, reconcile.Request{
-				NamespacedName: types.NamespacedName{Namespace: endpointSlice.Namespace, Name: endpointSlice.Name},
-			})
+		for i := range endpointSliceList.Items {
+			endpointSlice := &endpointSliceList.Items[i]
+			if ns != svcExport.Namespace && ownerServiceNamespace(endpointSlice) != svcExport.Namespace {
+				continue
+			}
+			endpointSlices = append(endpointSlices, *endpointSlice)
 		}
-		return reqs
-	})
+	}
+	return endpointSlices, nil
+}
 
-	// EndpointSlice controller watches over EndpointSlice and ServiceExport objects.
-	return ctrl.NewControllerManagedBy(mgr).
-		For(&discoveryv1.EndpointSlice{}).
-		Watches(&fleetnetv1alpha1.ServiceExport{}, eventHandlers).
-		Complete(r)
+// listExportableEndpointSlices lists the EndpointSlices of the Service of a ServiceExport which are exported, or are
+// to be exported.
+func (r *Reconciler) listExportableEndpointSlices(ctx context.Context, svcExport *fleetnetv1alpha1.ServiceExport) ([]discoveryv1.EndpointSlice, error) {
+	endpointSlices, err := r.listEndpointSlicesOfServiceExport(ctx, svcExport)
+	if err != nil {
+		return nil, err
+	}
+	exportableEndpointSlices := make([]discoveryv1.EndpointSlice, 0, len(endpointSlices))
+	for i := range endpointSlices {
+		es := &endpointSlices[i]
+		if isEndpointSlicePermanentlyUnexportable(es) || es.DeletionTimestamp != nil || !isEndpointSliceAllowedByServiceExport(svcExport, es) ||
+			!isEndpointSliceManagerAllowed(es, r.ExportedEndpointSliceManagers) {
+			continue
+		}
+		exportableEndpointSlices = append(exportableEndpointSlices, *es)
+	}
+	return exportableEndpointSlices, nil
+}
+
+// endpointSliceRequests returns the reconcile requests of EndpointSlices.
+func endpointSliceRequests(endpointSlices []discoveryv1.EndpointSlice) []reconcile.Request {
+	reqs := make([]reconcile.Request, 0, len(endpointSlices))
+	for i := range endpointSlices {
+		reqs = append(reqs, reconcile.Request{
+			NamespacedName: types.NamespacedName{Namespace: endpointSlices[i].Namespace, Name: endpointSlices[i].Name},
+		})
+	}
+	return reqs
+}
+
+// selectEndpointsToExport returns the endpoints of an EndpointSlice to export, and the total number of ready
+// endpoints of its Service; the total is only counted if the number of exported endpoints per Service is limited.
+//
+// If the Service has more ready endpoints across its EndpointSlices than allowed, only a subset of the endpoints is
+// exported, which is selected deterministically from all the EndpointSlices of the Service (see
+// selectExportedEndpoints).
+func (r *Reconciler) selectEndpointsToExport(ctx context.Context,
+	svcExport *fleetnetv1alpha1.ServiceExport, endpointSlice *discoveryv1.EndpointSlice) ([]fleetnetv1alpha1.Endpoint, int, error) {
+	endpoints := extractEndpointsFromEndpointSlice(endpointSlice, r.PropagateTerminatingEndpoints)
+	if r.MaxExportedEndpointsPerService <= 0 {
+		return endpoints, 0, nil
+	}
+
+	// Only the EndpointSlices that are exported, or are to be exported, count.
+	exportableEndpointSlices, err := r.listExportableEndpointSlices(ctx, svcExport)
+	if err != nil {
+		return nil, 0, err
+	}
+	selected, total := selectExportedEndpoints(exportableEndpointSlices, r.MaxExportedEndpointsPerService, r.PropagateTerminatingEndpoints)
+	if total <= r.MaxExportedEndpointsPerService {
+		return endpoints, total, nil
+	}
+
+	sliceKey := types.NamespacedName{Namespace: endpointSlice.Namespace, Name: endpointSlice.Name}
+	selectedEndpoints := []fleetnetv1alpha1.Endpoint{}
+	for _, endpoint := range endpoints {
+		if selected[exportedEndpointKey{endpointSlice: sliceKey, address: endpointAddressKey(endpoint.Addresses)}] {
+			selectedEndpoints = append(selectedEndpoints, endpoint)
+		}
+	}
+	klog.V(2).InfoS("The service has more ready endpoints than allowed; only a subset of them is exported",
+		"endpointSlice", klog.KObj(endpointSlice),
+		"serviceExport", klog.KObj(svcExport),
+		"totalEndpoints", total,
+		"maxExportedEndpoints", r.MaxExportedEndpointsPerService,
+		"exportedEndpointsInSlice", len(selectedEndpoints))
+	return selectedEndpoints, total, nil
+}
+
+// updateEndpointsTruncatedCondition reports on the ServiceExport whether its endpoints are truncated, given the
+// total number of its ready endpoints.
+//
+// The condition is added once the endpoints are truncated for the first time, and is set to false (rather than
+// removed) when they are no longer truncated; it is removed if the number of exported endpoints is no longer limited.
+func (r *Reconciler) updateEndpointsTruncatedCondition(ctx context.Context, svcExport *fleetnetv1alpha1.ServiceExport, totalEndpointCount int) error {
+	condType := string(fleetnetv1alpha1.ServiceExportEndpointsTruncated)
+	truncatedCond := meta.FindStatusCondition(svcExport.Status.Conditions, condType)
+
+	if r.MaxExportedEndpointsPerService <= 0 {
+		if truncatedCond == nil {
+			return nil
+		}
+		return r.patchServiceExportCondition(ctx, svcExport, condType, nil)
+	}
+
+	isTruncated := totalEndpointCount > r.MaxExportedEndpointsPerService
+	if !isTruncated && truncatedCond == nil {
+		// The endpoints have never been truncated; no condition is needed.
+		return nil
+	}
+
+	var expectedCond *metav1.Condition
+	if isTruncated {
+		expectedCond = &metav1.Condition{
+			Type:               condType,
+			Status:             metav1.ConditionTrue,
+			ObservedGeneration: svcExport.Generation,
+			Reason:             endpointsTruncatedCondReason,
+			Message: fmt.Sprintf("%d of %d ready endpoints of service %s/%s are exported; the maximum per service is %d",
+				r.MaxExportedEndpointsPerService, totalEndpointCount, svcExport.Namespace, svcExport.Name, r.MaxExportedEndpointsPerService),
+		}
+	} else {
+		expectedCond = &metav1.Condition{
+			Type:               condType,
+			Status:             metav1.ConditionFalse,
+			ObservedGeneration: svcExport.Generation,
+			Reason:             endpointsNotTruncatedCondReason,
+			Message: fmt.Sprintf("all %d ready endpoints of service %s/%s are exported; the maximum per service is %d",
+				totalEndpointCount, svcExport.Namespace, svcExport.Name, r.MaxExportedEndpointsPerService),
+		}
+	}
+	// The message carries the endpoint counts, which are compared as well.
+	if condition.EqualCondition(truncatedCond, expectedCond) && truncatedCond.Message == expectedCond.Message {
+		// A stable state has been reached; no further action is needed.
+		return nil
+	}
+
+	if isTruncated && (truncatedCond == nil || truncatedCond.Status != metav1.ConditionTrue) {
+		r.Recorder.Eventf(svcExport, corev1.EventTypeWarning, endpointsTruncatedCondReason,
+			"Service %s has %d ready endpoints, more than the maximum of %d per service; only %d of them are exported",
+			svcExport.Name, totalEndpointCount, r.MaxExportedEndpointsPerService, r.MaxExportedEndpointsPerService)
+	}
+	// Set the condition on a copy of the conditions, so that its last transition time is kept when the status is
+	// unchanged.
+	conds := append([]metav1.Condition(nil), svcExport.Status.Conditions...)
+	meta.SetStatusCondition(&conds, *expectedCond)
+	return r.patchServiceExportCondition(ctx, svcExport, condType, meta.FindStatusCondition(conds, condType))
+}
+
+// excludeHostNetworkEndpoints removes the endpoints backed by pods in the host network from the endpoints of an
+// EndpointSlice to export, unless the ServiceExport opts in to exporting them; it returns the remaining endpoints and
+// the number of endpoints removed.
+func (r *Reconciler) excludeHostNetworkEndpoints(ctx context.Context, svcExport *fleetnetv1alpha1.ServiceExport,
+	endpointSlice *discoveryv1.EndpointSlice, endpoints []fleetnetv1alpha1.Endpoint) ([]fleetnetv1alpha1.Endpoint, int, error) {
+	if allowsHostNetworkEndpoints(svcExport) {
+		return endpoints, 0, nil
+	}
+	hostNetworkAddresses, err := r.hostNetworkEndpointAddresses(ctx, endpointSlice)
+	if err != nil {
+		return nil, 0, err
+	}
+	if len(hostNetworkAddresses) == 0 {
+		return endpoints, 0, nil
+	}
+
+	keptEndpoints := make([]fleetnetv1alpha1.Endpoint, 0, len(endpoints))
+	for _, endpoint := range endpoints {
+		if !hostNetworkAddresses[endpointAddressKey(endpoint.Addresses)] {
+			keptEndpoints = append(keptEndpoints, endpoint)
+		}
+	}
+	excludedEndpointCount := len(endpoints) - len(keptEndpoints)
+	if excludedEndpointCount > 0 {
+		klog.V(2).InfoS("The endpoints backed by pods in the host network are excluded from the export",
+			"endpointSlice", klog.KObj(endpointSlice),
+			"serviceExport", klog.KObj(svcExport),
+			"excludedEndpoints", excludedEndpointCount)
+	}
+	return keptEndpoints, excludedEndpointCount, nil
+}
+
+// hostNetworkEndpointAddresses returns the addresses (see endpointAddressKey) of the endpoints of an EndpointSlice
+// which are backed by pods in the host network.
+//
+// The pods are read from the cache of the member client, so that no request is sent to the API server per endpoint;
+// an endpoint whose pod is not found, e.g. as the pod has just been deleted, is not considered in the host network.
+func (r *Reconciler) hostNetworkEndpointAddresses(ctx context.Context, endpointSlice *discoveryv1.EndpointSlice) (map[string]bool, error) {
+	addresses := map[string]bool{}
+	for _, endpoint := range endpointSlice.Endpoints {
+		podRef := endpoint.TargetRef
+		if podRef == nil || podRef.Kind != "Pod" {
+			continue
+		}
+		podNamespace := podRef.Namespace
+		if podNamespace == "" {
+			podNamespace = endpointSlice.Namespace
+		}
+		pod := corev1.Pod{}
+		if err := r.MemberClient.Get(ctx, types.NamespacedName{Namespace: podNamespace, Name: podRef.Name}, &pod); err != nil {
+			if errors.IsNotFound(err) {
+				continue
+			}
+			return nil, fmt.Errorf("failed to get pod %s/%s: %w", podNamespace, podRef.Name, err)
+		}
+		if isHostNetworkPod(&pod) {
+			addresses[endpointAddressKey(endpoint.Addresses)] = true
+		}
+	}
+	return addresses, nil
+}
+
+// hasHostNetworkEndpoints returns if any of the exportable EndpointSlices of the Service of a ServiceExport has ready
+// endpoints backed by pods in the host network.
+func (r *Reconciler) hasHostNetworkEndpoints(ctx context.Context, svcExport *fleetnetv1alpha1.ServiceExport) (bool, error) {
+	endpointSlices, err := r.listExportableEndpointSlices(ctx, svcExport)
+	if err != nil {
+		return false, err
+	}
+	for i := range endpointSlices {
+		endpointSlice := &endpointSlices[i]
+		_, excludedEndpointCount, err := r.excludeHostNetworkEndpoints(ctx, svcExport, endpointSlice, extractEndpointsFromEndpointSlice(endpointSlice, r.PropagateTerminatingEndpoints))
+		if err != nil {
+			return false, err
+		}
+		if excludedEndpointCount > 0 {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// updateHostNetworkEndpointsExcludedCondition reports on the ServiceExport whether endpoints backed by pods in the
+// host network are excluded from the export, given the number of endpoints excluded from the EndpointSlice being
+// reconciled.
+//
+// The condition is added once endpoints are excluded for the first time, and is set to false (rather than removed)
+// when none of the EndpointSlices of the Service has excluded endpoints any more; it is removed if the ServiceExport
+// opts in to exporting the endpoints backed by pods in the host network.
+func (r *Reconciler) updateHostNetworkEndpointsExcludedCondition(ctx context.Context, svcExport *fleetnetv1alpha1.ServiceExport, excludedEndpointCount int) error {
+	condType := string(fleetnetv1alpha1.ServiceExportHostNetworkEndpointsExcluded)
+	excludedCond := meta.FindStatusCondition(svcExport.Status.Conditions, condType)
+
+	if allowsHostNetworkEndpoints(svcExport) {
+		if excludedCond == nil {
+			return nil
+		}
+		return r.patchServiceExportCondition(ctx, svcExport, condType, nil)
+	}
+
+	isExcluded := excludedEndpointCount > 0
+	if !isExcluded {
+		if excludedCond == nil || excludedCond.Status != metav1.ConditionTrue {
+			// No endpoint has been excluded since the condition was last reported; no update is needed.
+			return nil
+		}
+		// The condition is reported for the Service as a whole, and holds as long as any other EndpointSlice of the
+		// Service has excluded endpoints.
+		var err error
+		if isExcluded, err = r.hasHostNetworkEndpoints(ctx, svcExport); err != nil {
+			return err
+		}
+	}
+
+	var expectedCond *metav1.Condition
+	if isExcluded {
+		expectedCond = &metav1.Condition{
+			Type:               condType,
+			Status:             metav1.ConditionTrue,
+			ObservedGeneration: svcExport.Generation,
+			Reason:             hostNetworkEndpointsExcludedCondReason,
+			Message: fmt.Sprintf("endpoints of service %s/%s backed by pods in the host network are not exported; annotate the service export with %s: \"true\" to export them",
+				svcExport.Namespace, svcExport.Name, objectmeta.ServiceExportAnnotationAllowHostNetworkEndpoints),
+		}
+	} else {
+		expectedCond = &metav1.Condition{
+			Type:               condType,
+			Status:             metav1.ConditionFalse,
+			ObservedGeneration: svcExport.Generation,
+			Reason:             noHostNetworkEndpointsExcludedCondReason,
+			Message:            fmt.Sprintf("no endpoint of service %s/%s is backed by pods in the host network", svcExport.Namespace, svcExport.Name),
+		}
+	}
+	if condition.EqualCondition(excludedCond, expectedCond) {
+		// A stable state has been reached; no further action is needed.
+		return nil
+	}
+
+	if isExcluded && (excludedCond == nil || excludedCond.Status != metav1.ConditionTrue) {
+		r.Recorder.Eventf(svcExport, corev1.EventTypeWarning, hostNetworkEndpointsExcludedCondReason,
+			"Service %s has endpoints backed by pods in the host network, whose addresses are node IPs; they are not exported",
+			svcExport.Name)
+	}
+	// Set the condition on a copy of the conditions, so that its last transition time is kept when the status is
+	// unchanged.
+	conds := append([]metav1.Condition(nil), svcExport.Status.Conditions...)
+	meta.SetStatusCondition(&conds, *expectedCond)
+	return r.patchServiceExportCondition(ctx, svcExport, condType, meta.FindStatusCondition(conds, condType))
+}
+
+// jsonPatchOp is an operation of a JSON patch (RFC 6902).
+type jsonPatchOp struct {
+	Op    string      `json:"op"`
+	Path  string      `json:"path"`
+	Value interface{} `json:"value,omitempty"`
+}
+
+// patchServiceExportCondition sets the condition of the given type of the ServiceExport to the given condition, or
+// removes it if the given condition is nil; it is used for the conditions this controller owns, i.e.,
+// EndpointsTruncated and HostNetworkEndpointsExcluded.
+//
+// The ServiceExport controller owns the other conditions of the ServiceExport, so the condition is patched in place
+// rather than updating the whole status, which would conflict with the status updates of the ServiceExport
+// controller; the patch is rejected, and the request retried, if the conditions have been reordered since the
+// ServiceExport was read.
+func (r *Reconciler) patchServiceExportCondition(ctx context.Context, svcExport *fleetnetv1alpha1.ServiceExport, condType string, cond *metav1.Condition) error {
+	idx := -1
+	for i := range svcExport.Status.Conditions {
+		if svcExport.Status.Conditions[i].Type == condType {
+			idx = i
+			break
+		}
+	}
+
+	var ops []jsonPatchOp
+	switch {
+	case idx >= 0:
+		condPath := fmt.Sprintf("/status/conditions/%d", idx)
+		ops = append(ops, jsonPatchOp{Op: "test", Path: condPath + "/type", Value: condType})
+		if cond == nil {
+			ops = append(ops, jsonPatchOp{Op: "remove", Path: condPath})
+		} else {
+			ops = append(ops, jsonPatchOp{Op: "replace", Path: condPath, Value: cond})
+		}
+	case cond == nil:
+		return nil
+	case len(svcExport.Status.Conditions) == 0:
+		ops = append(ops, jsonPatchOp{Op: "add", Path: "/status/conditions", Value: []metav1.Condition{*cond}})
+	default:
+		ops = append(ops, jsonPatchOp{Op: "add", Path: "/status/conditions/-", Value: cond})
+	}
+	patch, err := json.Marshal(ops)
+	if err != nil {
+		return fmt.Errorf("failed to marshal the %s condition patch: %w", condType, err)
+	}
+	// The ServiceExport may have been deleted since it was read, in which case there is no condition left to report.
+	return client.IgnoreNotFound(r.MemberClient.Status().Patch(ctx, svcExport, client.RawPatch(types.JSONPatchType, patch)))
 }
 
 // shouldSkipOrUnexportEndpointSlice returns the op the controller should take on an EndpointSlice, specifically
 // whether to skip reconciling an EndpointSlice, and whether to unexport an EndpointSlice.
 //
 // The controller can only export an EndpointSlice if
+// * the EndpointSlice is managed by one of the allowed controllers; and
 // * the EndpointSlice is in use by a Service that has been successfully exported (valid with no conflicts); and
 // * the EndpointSlice has not been deleted.
 //
 // If an EndpointSlice has been exported before, but
-// * its owner Service has not been, or is no longer, exported; or
+// * it is no longer managed by one of the allowed controllers; or
+// * its owner Service has not been, or is no longer, exported, e.g. as its namespace is being deleted; or
 // * the EndpointSlice itself has been deleted
-// the EndpointSlice should be unexported.
+// the EndpointSlice should be unexported; if the ServiceExport of its owner Service still exists but is (possibly
+// only for a while) invalid or in conflict, the EndpointSlice should be soft unexported instead.
 //
 // EndpointSlices that are
 // * not exportable; or
@@ -264,6 +849,18 @@ func (r *Reconciler) shouldSkipOrUnexportEndpointSlice(ctx context.Context,
 	// been made to export an EndpointSlice.
 	_, hasUniqueNameAnnotation := endpointSlice.Annotations[objectmeta.ExportedObjectAnnotationUniqueName]
 
+	// Check if the EndpointSlice is managed by one of the allowed controllers; the managed-by label, as well as the
+	// allowed controllers, might have changed since the EndpointSlice was exported.
+	if !isEndpointSliceManagerAllowed(endpointSlice, r.ExportedEndpointSliceManagers) {
+		klog.V(4).InfoS("The endpoint slice is not managed by any of the allowed controllers",
+			"endpointSlice", klog.KObj(endpointSlice),
+			"managedBy", endpointSlice.Labels[discoveryv1.LabelManagedBy])
+		if hasUniqueNameAnnotation {
+			return shouldUnexportEndpointSliceOp, nil
+		}
+		return shouldSkipEndpointSliceOp, nil
+	}
+
 	if !hasSvcNameLabel {
 		if !hasUniqueNameAnnotation {
 			// The Service is not in use by a Service and does not have a unique name annotation (i.e. it has not been
@@ -275,9 +872,9 @@ func (r *Reconciler) shouldSkipOrUnexportEndpointSlice(ctx context.Context,
 		return shouldUnexportEndpointSliceOp, nil
 	}
 
-	// Retrieve the Service Export.
+	// Retrieve the Service Export; the owner Service may reside in a different namespace from the EndpointSlice.
 	svcExport := &fleetnetv1alpha1.ServiceExport{}
-	err := r.MemberClient.Get(ctx, types.NamespacedName{Namespace: endpointSlice.Namespace, Name: svcName}, svcExport)
+	err := r.MemberClient.Get(ctx, types.NamespacedName{Namespace: ownerServiceNamespace(endpointSlice), Name: svcName}, svcExport)
 	switch {
 	case errors.IsNotFound(err) && hasUniqueNameAnnotation:
 		// The Service using the EndpointSlice is not exported but the EndpointSlice has a unique name annotation
@@ -292,13 +889,33 @@ func (r *Reconciler) shouldSkipOrUnexportEndpointSlice(ctx context.Context,
 		return continueReconcileOp, err
 	}
 
+	// Check if the namespace of the ServiceExport is being deleted; the Service is then unexported without the
+	// ServiceExport being marked as invalid, and the EndpointSlice, which is about to be deleted as well, should be
+	// unexported for good instead of being exported again as its endpoints go away.
+	isTerminating, err := r.isNamespaceTerminating(ctx, svcExport.Namespace)
+	if err != nil {
+		return continueReconcileOp, err
+	}
+	if isTerminating {
+		if hasUniqueNameAnnotation {
+			return shouldUnexportEndpointSliceOp, nil
+		}
+		return shouldSkipEndpointSliceOp, nil
+	}
+
 	// Check if the ServiceExport is valid with no conflicts.
 	if !isServiceExportValidWithNoConflict(svcExport) {
+		if hasUniqueNameAnnotation && svcExport.DeletionTimestamp != nil {
+			// The Service using the EndpointSlice is being unexported, and the EndpointSlice has a unique name
+			// annotation present (i.e. it might have been exported before); the EndpointSlice should be unexported.
+			return shouldUnexportEndpointSliceOp, nil
+		}
 		if hasUniqueNameAnnotation {
 			// The Service using the EndpointSlice is not valid for export or has conflicts with other exported
 			// Services, but the EndpointSlice has a unique name annotation present (i.e. it might have been
-			// exported before); the EndpointSlice should be unexported.
-			return shouldUnexportEndpointSliceOp, nil
+			// exported before); as the ServiceExport may only be revalidated for a while, e.g. after the member
+			// agent restarts, the EndpointSlice should be soft unexported.
+			return shouldSoftUnexportEndpointSliceOp, nil
 		}
 		// The Service using the EndpointSlice is not valid for export or has conflicts with other exported
 		// Services, and the EndpointSlice has no unique name annoation present (i.e. it has not been
@@ -306,6 +923,20 @@ func (r *Reconciler) shouldSkipOrUnexportEndpointSlice(ctx context.Context,
 		return shouldSkipEndpointSliceOp, nil
 	}
 
+	// Check if the ServiceExport accepts the EndpointSlice, which matters only when the EndpointSlice claims a Service
+	// from another namespace as its owner.
+	if !isEndpointSliceAllowedByServiceExport(svcExport, endpointSlice) {
+		klog.V(2).InfoS("The endpoint slice claims a service from another namespace which does not allow its namespace",
+			"endpointSlice", klog.KObj(endpointSlice),
+			"serviceExport", klog.KObj(svcExport))
+		if hasUniqueNameAnnotation {
+			// The EndpointSlice might have been exported before, e.g., when its namespace was still allowed; it
+			// should be unexported.
+			return shouldUnexportEndpointSliceOp, nil
+		}
+		return shouldSkipEndpointSliceOp, nil
+	}
+
 	if endpointSlice.DeletionTimestamp != nil {
 		if hasUniqueNameAnnotation {
 			// The Service using the EndpointSlice is exported with no conflicts, and the EndpointSlice has a unique
@@ -324,6 +955,16 @@ func (r *Reconciler) shouldSkipOrUnexportEndpointSlice(ctx context.Context,
 	return continueReconcileOp, nil
 }
 
+// isNamespaceTerminating returns if a namespace is being deleted; a namespace which is not found is not considered
+// as such.
+func (r *Reconciler) isNamespaceTerminating(ctx context.Context, namespace string) (bool, error) {
+	ns := &corev1.Namespace{}
+	if err := r.MemberClient.Get(ctx, types.NamespacedName{Name: namespace}, ns); err != nil {
+		return false, client.IgnoreNotFound(err)
+	}
+	return ns.DeletionTimestamp != nil, nil
+}
+
 // unexportEndpointSlice unexports an EndpointSlice by deleting its corresponding EndpointSliceExport.
 func (r *Reconciler) unexportEndpointSlice(ctx context.Context, endpointSlice *discoveryv1.EndpointSlice) error {
 	// Remove the EndpointSliceExport.
@@ -339,54 +980,103 @@ func (r *Reconciler) unexportEndpointSlice(ctx context.Context, endpointSlice *d
 	return r.MemberClient.Update(ctx, endpointSlice)
 }
 
-// deleteEndpointSliceExportIfLinked deletes an exported EndpointSlice.
-func (r *Reconciler) deleteEndpointSliceExportIfLinked(ctx context.Context, endpointSlice *discoveryv1.EndpointSlice) error {
+// markEndpointSliceExportAsDeletionCandidate marks the EndpointSliceExport of an EndpointSlice, if it is linked with
+// the EndpointSlice, as a deletion candidate, so that the hub cluster withdraws the EndpointSlice from the importing
+// member clusters only if it is not exported again within a grace period.
+func (r *Reconciler) markEndpointSliceExportAsDeletionCandidate(ctx context.Context, endpointSlice *discoveryv1.EndpointSlice) error {
 	fleetUniqueName := endpointSlice.Annotations[objectmeta.ExportedObjectAnnotationUniqueName]
-
-	// Skip the deletion if the unique name assigned as an annotation is not a valid DNS subdomain name; this
-	// helps guard against user tampering with the annotation.
 	if !isUniqueNameValid(fleetUniqueName) {
-		klog.V(2).InfoS("The unique name annotation for exporting the EndpointSlice is not valid; unexport is skipped",
+		klog.V(2).InfoS("The unique name annotation for exporting the EndpointSlice is not valid; soft unexport is skipped",
 			"endpointSlice", klog.KObj(endpointSlice),
 			"uniqueName", fleetUniqueName)
 		return nil
 	}
 
-	endpointSliceExport := fleetnetv1alpha1.EndpointSliceExport{
-		ObjectMeta: metav1.ObjectMeta{
-			Namespace: r.HubNamespace,
-			Name:      fleetUniqueName,
-		},
+	endpointSliceExportKey := types.NamespacedName{Namespace: r.hubExportNamespace(endpointSlice), Name: fleetUniqueName}
+	existingEndpointSliceExport := fleetnetv1alpha1.EndpointSliceExport{}
+	if err := r.HubClient.Get(ctx, endpointSliceExportKey, &existingEndpointSliceExport); err != nil {
+		if errors.IsNotFound(err) {
+			// The EndpointSlice has not been exported, or the hub cluster has deleted its EndpointSliceExport after
+			// the grace period.
+			return nil
+		}
+		klog.ErrorS(err, "Failed to get endpointslice export", "endpointSlice", klog.KObj(endpointSlice), "endpointSliceExport", endpointSliceExportKey)
+		return err
 	}
-	endpointSliceExportKey := types.NamespacedName{Namespace: r.HubNamespace, Name: fleetUniqueName}
-	err := r.HubClient.Get(ctx, endpointSliceExportKey, &endpointSliceExport)
-	switch {
-	case errors.IsNotFound(err):
-		// It is guaranteed that a unique name annotation is always added before an EndpointSlice is exported; and
-		// in some rare occasions it could happen that an EndpointSlice has a unique name annotation present yet has
-		// not been exported to the hub cluster. It is an expected behavior and no action is needed on this controller's
-		// end.
+	if !isEndpointSliceExportLinkedWithEndpointSlice(&existingEndpointSliceExport, endpointSlice) {
+		klog.V(2).InfoS("The endpoint slice export is linked with another endpoint slice; soft unexport is skipped",
+			"endpointSlice", klog.KObj(endpointSlice), "endpointSliceExport", endpointSliceExportKey)
+		return nil
+	}
+	if _, ok := existingEndpointSliceExport.Annotations[objectmeta.EndpointSliceExportAnnotationDeletionCandidateSince]; ok {
+		// The grace period keeps counting from when the EndpointSliceExport is first marked.
 		return nil
-	case err != nil:
-		// An unexpected error has occurred.
-		return err
 	}
 
-	if !isEndpointSliceExportLinkedWithEndpointSlice(&endpointSliceExport, endpointSlice) {
-		// The EndpointSliceExport to which the unique name annotation on the EndpointSlice refers is not actually
-		// linked with the EndpointSlice. This could happen if direct manipulation forces unique name annotations
-		// on two different EndpointSlices to point to the same EndpointSliceExport. In this case the
-		// EndpointSliceExport will not be deleted.
+	// The EndpointSliceExport is applied as last exported, with the annotation added, so that the annotation is owned
+	// by the same field manager as the rest of the export, and is dropped when the EndpointSlice is exported again.
+	endpointSliceExport := BuildEndpointSliceExport(endpointSliceExportKey, r.MemberClusterID, endpointSlice,
+		existingEndpointSliceExport.Spec.Endpoints, existingEndpointSliceExport.Spec.EndpointSliceReference,
+		existingEndpointSliceExport.Spec.OwnerServiceReference.Namespace, existingEndpointSliceExport.Spec.OwnerServiceReference.Name)
+	endpointSliceExport.Spec.AddressType = existingEndpointSliceExport.Spec.AddressType
+	endpointSliceExport.Spec.Ports = existingEndpointSliceExport.Spec.Ports
+	for k, v := range existingEndpointSliceExport.Annotations {
+		if k == objectmeta.EndpointSliceExportAnnotationExcludedEndpoints || k == metrics.MetricsAnnotationLastObservedGeneration {
+			endpointSliceExport.Annotations[k] = v
+		}
+	}
+	endpointSliceExport.Annotations[objectmeta.EndpointSliceExportAnnotationDeletionCandidateSince] = time.Now().UTC().Format(time.RFC3339)
+	klog.V(2).InfoS("Mark the endpoint slice export as a deletion candidate",
+		"endpointSlice", klog.KObj(endpointSlice), "endpointSliceExport", endpointSliceExportKey)
+	return hubclient.CreateOrUpdateEndpointSliceExport(ctx, r.HubClient, &endpointSliceExport, "endpointSlice", klog.KObj(endpointSlice))
+}
+
+// deleteEndpointSliceExportIfLinked deletes an exported EndpointSlice.
+func (r *Reconciler) deleteEndpointSliceExportIfLinked(ctx context.Context, endpointSlice *discoveryv1.EndpointSlice) error {
+	fleetUniqueName := endpointSlice.Annotations[objectmeta.ExportedObjectAnnotationUniqueName]
+
+	// Skip the deletion if the unique name assigned as an annotation is not a valid DNS subdomain name; this
+	// helps guard against user tampering with the annotation.
+	if !isUniqueNameValid(fleetUniqueName) {
+		klog.V(2).InfoS("The unique name annotation for exporting the EndpointSlice is not valid; unexport is skipped",
+			"endpointSlice", klog.KObj(endpointSlice),
+			"uniqueName", fleetUniqueName)
 		return nil
 	}
 
-	if err := r.HubClient.Delete(ctx, &endpointSliceExport); err != nil && !errors.IsNotFound(err) {
-		// An unexpected error has occurred.
-		return err
+	// It is guaranteed that a unique name annotation is always added before an EndpointSlice is exported; and
+	// in some rare occasions it could happen that an EndpointSlice has a unique name annotation present yet has
+	// not been exported to the hub cluster. It is an expected behavior and no action is needed on this controller's
+	// end.
+	//
+	// The EndpointSliceExport to which the unique name annotation on the EndpointSlice refers may not be actually
+	// linked with the EndpointSlice either. This could happen if direct manipulation forces unique name annotations
+	// on two different EndpointSlices to point to the same EndpointSliceExport. In this case the
+	// EndpointSliceExport will not be deleted.
+	//
+	// The EndpointSlice may no longer point to the Service it was exported for, e.g. after its service name label has
+	// been changed; with the exports sharded, every shard namespace is checked, as the EndpointSliceExport may reside
+	// in the shard of the previous owner Service.
+	for _, ns := range hubconfig.ExportNamespaces(r.HubNamespace, r.HubExportShards) {
+		endpointSliceExport := fleetnetv1alpha1.EndpointSliceExport{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: ns,
+				Name:      fleetUniqueName,
+			},
+		}
+		if _, err := hubclient.DeleteIfLinked(ctx, r.HubClient, &endpointSliceExport, endpointSlice.UID, "endpointSlice", klog.KObj(endpointSlice)); err != nil {
+			return err
+		}
 	}
 	return nil
 }
 
+// hubExportNamespace returns the namespace in the hub cluster of the EndpointSliceExport of an EndpointSlice, which
+// is the shard selected by the namespace and the name of its owner Service, as for the export of the Service itself.
+func (r *Reconciler) hubExportNamespace(endpointSlice *discoveryv1.EndpointSlice) string {
+	return hubconfig.ExportNamespace(r.HubNamespace, r.HubExportShards, ownerServiceNamespace(endpointSlice), endpointSlice.Labels[discoveryv1.LabelServiceName])
+}
+
 // assignUniqueNameAsAnnotation assigns a new unique name as an annotation.
 func (r *Reconciler) assignUniqueNameAsAnnotation(ctx context.Context, endpointSlice *discoveryv1.EndpointSlice) (string, error) {
 	fleetUniqueName, err := uniquename.FleetScopedUniqueName(uniquename.DNS1123Subdomain,
@@ -433,9 +1123,31 @@ func (r *Reconciler) collectAndVerifyLastSeenGenerationAndTimestamp(ctx context.
 
 // annotateLastSeenGenerationAndTimestamp annotates an EndpointSlice with last seen generation and timestamp.
 func (r *Reconciler) annotateLastSeenGenerationAndTimestamp(ctx context.Context, endpointSlice *discoveryv1.EndpointSlice, startTime time.Time) error {
-	// Since the controller always annotate last seen generation and timestamp after assigning a unique name,
-	// the annotations map must have been initialized at this point.
+	// The annotations are patched, so that the repair does not conflict with the metrics annotations backfill
+	// stamping the same EndpointSlice.
+	patch := client.MergeFrom(endpointSlice.DeepCopy())
+	stampLastSeenGenerationAndTimestamp(endpointSlice, startTime)
+	return r.MemberClient.Patch(ctx, endpointSlice, patch)
+}
+
+// stampLastSeenGenerationAndTimestamp sets the last seen generation and timestamp annotations of an EndpointSlice to
+// its current generation and the given time.
+func stampLastSeenGenerationAndTimestamp(endpointSlice *discoveryv1.EndpointSlice, t time.Time) {
+	if endpointSlice.Annotations == nil {
+		endpointSlice.Annotations = map[string]string{}
+	}
 	endpointSlice.Annotations[metrics.MetricsAnnotationLastSeenGeneration] = strconv.FormatInt(endpointSlice.Generation, 10)
-	endpointSlice.Annotations[metrics.MetricsAnnotationLastSeenTimestamp] = startTime.Format(metrics.MetricsLastSeenTimestampFormat)
-	return r.MemberClient.Update(ctx, endpointSlice)
+	endpointSlice.Annotations[metrics.MetricsAnnotationLastSeenTimestamp] = t.Format(metrics.MetricsLastSeenTimestampFormat)
+}
+
+// observeExportLatency observes a data point for the endpointSliceExportLatency metric.
+func observeExportLatency(endpointSlice *discoveryv1.EndpointSlice, exportedSince, endTime time.Time) {
+	// The last seen timestamp has a resolution of one second, and the annotations are not tamperproof; the latency
+	// is clamped, so that the data points which do not make sense do not affect data analysis.
+	timeSpent := metrics.ClampExportDuration(endTime.Sub(exportedSince)).Seconds()
+	endpointSliceExportLatency.WithLabelValues(endpointSlice.Namespace).Observe(timeSpent)
+	klog.V(2).InfoS("endpointSliceExportLatencySeconds",
+		"value", timeSpent,
+		"endpointSlice", klog.KObj(endpointSlice),
+		"generation", endpointSlice.Generation)
 }
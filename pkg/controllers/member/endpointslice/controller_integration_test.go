@@ -18,18 +18,30 @@ import (
 	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	ctrlmetrics "sigs.k8s.io/controller-runtime/pkg/metrics"
 
 	fleetnetv1alpha1 "go.goms.io/fleet-networking/api/v1alpha1"
+	"go.goms.io/fleet-networking/pkg/common/hubconfig"
 	"go.goms.io/fleet-networking/pkg/common/metrics"
 	"go.goms.io/fleet-networking/pkg/common/objectmeta"
+	"go.goms.io/fleet-networking/pkg/common/uniquename"
 )
 
 const (
 	ipv4Addr             = "1.2.3.4"
 	altIPv4Addr          = "2.3.4.5"
 	ipv6Addr             = "2001:db8:1::ab9:C0A8:102"
+	fqdnAddr             = "app-1.example.com"
 	altEndpointSliceName = "app-endpointslice-2"
+	// memberMirrorUserNS is the namespace of the EndpointSlices owned by a Service in another namespace.
+	memberMirrorUserNS = "mirror"
+
+	// hubExportShards is the number of hub namespaces across which the exports of the member cluster are sharded.
+	hubExportShards = 2
+	// hubShardNSForMember is the hub export shard namespace other than the member cluster namespace.
+	hubShardNSForMember = hubNSForMember + "-shard-1"
 
 	eventuallyTimeout    = time.Second * 10
 	eventuallyInterval   = time.Millisecond * 250
@@ -43,8 +55,32 @@ var (
 		Namespace: memberUserNS,
 		Name:      svcName,
 	}
+	// hubExportNSForSvc is the hub export shard namespace of the Service.
+	hubExportNSForSvc = hubconfig.ExportNamespace(hubNSForMember, hubExportShards, memberUserNS, svcName)
 )
 
+// endpointSliceExportLatencySampleCount returns the number of data points of the endpointslice export latency metric
+// observed for EndpointSlices in a namespace.
+func endpointSliceExportLatencySampleCount(namespace string) (uint64, error) {
+	metricFamilies, err := ctrlmetrics.Registry.Gather()
+	if err != nil {
+		return 0, fmt.Errorf("metrics Gather(), got %w, want no error", err)
+	}
+	for _, metricFamily := range metricFamilies {
+		if metricFamily.GetName() != "fleet_networking_endpointslice_export_latency_seconds" {
+			continue
+		}
+		for _, metric := range metricFamily.GetMetric() {
+			for _, label := range metric.GetLabel() {
+				if label.GetName() == "namespace" && label.GetValue() == namespace {
+					return metric.GetHistogram().GetSampleCount(), nil
+				}
+			}
+		}
+	}
+	return 0, nil
+}
+
 var (
 	// endpointSliceUniqueNameIsNotAssignedActual runs with Eventually and Consistently assertion to make sure that
 	// no unique name has been assigned to an EndpointSlice.
@@ -62,9 +98,9 @@ var (
 	// endpointSliceIsNotExportedActual runs with Eventually and Consistently assertion to make sure that no
 	// EndpointSlice has been exported.
 	endpointSliceIsNotExportedActual = func() error {
+		// The exports of the member cluster are sharded across multiple hub namespaces.
 		endpointSliceExportList := &fleetnetv1alpha1.EndpointSliceExportList{}
-		listOption := &client.ListOptions{Namespace: hubNSForMember}
-		if err := hubClient.List(ctx, endpointSliceExportList, listOption); err != nil {
+		if err := hubClient.List(ctx, endpointSliceExportList); err != nil {
 			return fmt.Errorf("endpointSliceExport List(), got %w, want no error", err)
 		}
 
@@ -74,6 +110,34 @@ var (
 		return nil
 	}
 
+	// endpointSliceIsSoftUnexportedActual runs with Eventually and Consistently assertion to make sure that the
+	// EndpointSlice has been soft unexported, i.e. its EndpointSliceExport is kept, marked as a deletion candidate, and
+	// it keeps its unique name.
+	endpointSliceIsSoftUnexportedActual = func() error {
+		endpointSlice := &discoveryv1.EndpointSlice{}
+		if err := memberClient.Get(ctx, endpointSliceKey, endpointSlice); err != nil {
+			return fmt.Errorf("endpointSlice Get(%+v), got %w, want no error", endpointSliceKey, err)
+		}
+		uniqueName, ok := endpointSlice.Annotations[objectmeta.ExportedObjectAnnotationUniqueName]
+		if !ok {
+			return fmt.Errorf("endpointSlice unique name annotation is absent")
+		}
+
+		endpointSliceExportKey := types.NamespacedName{Namespace: hubExportNSForSvc, Name: uniqueName}
+		endpointSliceExport := &fleetnetv1alpha1.EndpointSliceExport{}
+		if err := hubClient.Get(ctx, endpointSliceExportKey, endpointSliceExport); err != nil {
+			return fmt.Errorf("endpointSliceExport Get(%+v), got %w, want no error", endpointSliceExportKey, err)
+		}
+		candidateSince, ok := endpointSliceExport.Annotations[objectmeta.EndpointSliceExportAnnotationDeletionCandidateSince]
+		if !ok {
+			return fmt.Errorf("endpointSliceExport deletion candidate annotation is absent")
+		}
+		if _, err := time.Parse(time.RFC3339, candidateSince); err != nil {
+			return fmt.Errorf("endpointSliceExport deletion candidate annotation Parse(%s), got %w, want no error", candidateSince, err)
+		}
+		return nil
+	}
+
 	// endpointSliceIsAbsentActual runs with Eventually and Consistently assertion to make sure that a given
 	// EndpointSlice no longer exists.
 	endpointSliceIsAbsentActual = func() error {
@@ -128,7 +192,7 @@ func notYetFulfilledServiceExport() *fleetnetv1alpha1.ServiceExport {
 }
 
 var _ = Describe("endpointslice controller (skip endpointslice)", Serial, Ordered, func() {
-	Context("IPv6 endpointSlice", func() {
+	Context("FQDN endpointSlice", func() {
 		var (
 			endpointSlice *discoveryv1.EndpointSlice
 			svcExport     *fleetnetv1alpha1.ServiceExport
@@ -143,10 +207,10 @@ var _ = Describe("endpointslice controller (skip endpointslice)", Serial, Ordere
 						discoveryv1.LabelServiceName: svcName,
 					},
 				},
-				AddressType: discoveryv1.AddressTypeIPv6,
+				AddressType: discoveryv1.AddressTypeFQDN,
 				Endpoints: []discoveryv1.Endpoint{
 					{
-						Addresses: []string{ipv6Addr},
+						Addresses: []string{fqdnAddr},
 					},
 				},
 				Ports: []discoveryv1.EndpointPort{
@@ -178,7 +242,7 @@ var _ = Describe("endpointslice controller (skip endpointslice)", Serial, Ordere
 			Eventually(serviceExportIsAbsentActual, eventuallyTimeout, eventuallyInterval).Should(BeNil())
 		})
 
-		It("should not export ipv6 endpointslice", func() {
+		It("should not export fqdn endpointslice", func() {
 			// Wait until the state stablizes to run consistently check; this helps make the test less flaky.
 			Eventually(endpointSliceUniqueNameIsNotAssignedActual, eventuallyTimeout, eventuallyInterval).Should(BeNil())
 			Consistently(endpointSliceUniqueNameIsNotAssignedActual, consistentlyDuration, consistentlyInterval).Should(BeNil())
@@ -359,7 +423,7 @@ var _ = Describe("endpointslice controller (skip endpointslice)", Serial, Ordere
 var _ = Describe("endpointslice controller (unexport endpointslice)", Serial, Ordered, func() {
 	endpointSliceExportTemplate := &fleetnetv1alpha1.EndpointSliceExport{
 		ObjectMeta: metav1.ObjectMeta{
-			Namespace: hubNSForMember,
+			Namespace: hubExportNSForSvc,
 			Name:      endpointSliceUniqueName,
 		},
 		Spec: fleetnetv1alpha1.EndpointSliceExportSpec{
@@ -532,11 +596,17 @@ var _ = Describe("endpointslice controller (unexport endpointslice)", Serial, Or
 			Expect(memberClient.Delete(ctx, svcExport)).Should(Succeed())
 			// Confirm that the ServiceExport is deleted; this helps make the test less flaky.
 			Eventually(serviceExportIsAbsentActual, eventuallyTimeout, eventuallyInterval).Should(BeNil())
-		})
 
-		It("should remove exported endpointslice from invalid service", func() {
+			// The EndpointSliceExport marked as a deletion candidate is deleted by the hub cluster, which does not run
+			// in the test environment.
+			Expect(hubClient.DeleteAllOf(ctx, &fleetnetv1alpha1.EndpointSliceExport{}, client.InNamespace(hubExportNSForSvc))).Should(Succeed())
+			// Confirm that all EndpointSliceExports have been deleted; this helps make the test less flaky.
 			Eventually(endpointSliceIsNotExportedActual, eventuallyTimeout, eventuallyInterval).Should(BeNil())
-			Eventually(endpointSliceUniqueNameIsNotAssignedActual, eventuallyTimeout, eventuallyInterval).Should(BeNil())
+		})
+
+		It("should mark exported endpointslice from invalid service as a deletion candidate", func() {
+			Eventually(endpointSliceIsSoftUnexportedActual, eventuallyTimeout, eventuallyInterval).Should(BeNil())
+			Consistently(endpointSliceIsSoftUnexportedActual, consistentlyDuration, consistentlyInterval).Should(BeNil())
 		})
 	})
 
@@ -586,11 +656,17 @@ var _ = Describe("endpointslice controller (unexport endpointslice)", Serial, Or
 			Expect(memberClient.Delete(ctx, svcExport)).Should(Succeed())
 			// Confirm that the ServiceExport is deleted; this helps make the test less flaky.
 			Eventually(serviceExportIsAbsentActual, eventuallyTimeout, eventuallyInterval).Should(BeNil())
-		})
 
-		It("should remove exported endpointslice from conflicted exported service", func() {
+			// The EndpointSliceExport marked as a deletion candidate is deleted by the hub cluster, which does not run
+			// in the test environment.
+			Expect(hubClient.DeleteAllOf(ctx, &fleetnetv1alpha1.EndpointSliceExport{}, client.InNamespace(hubExportNSForSvc))).Should(Succeed())
+			// Confirm that all EndpointSliceExports have been deleted; this helps make the test less flaky.
 			Eventually(endpointSliceIsNotExportedActual, eventuallyTimeout, eventuallyInterval).Should(BeNil())
-			Eventually(endpointSliceUniqueNameIsNotAssignedActual, eventuallyTimeout, eventuallyInterval).Should(BeNil())
+		})
+
+		It("should mark exported endpointslice from conflicted exported service as a deletion candidate", func() {
+			Eventually(endpointSliceIsSoftUnexportedActual, eventuallyTimeout, eventuallyInterval).Should(BeNil())
+			Consistently(endpointSliceIsSoftUnexportedActual, consistentlyDuration, consistentlyInterval).Should(BeNil())
 		})
 	})
 
@@ -712,7 +788,7 @@ var _ = Describe("endpointslice controller (export endpointslice or update expor
 			// Confirm that the ServiceExport is deleted; this helps make the test less flaky.
 			Eventually(serviceExportIsAbsentActual, eventuallyTimeout, eventuallyInterval).Should(BeNil())
 
-			Expect(hubClient.DeleteAllOf(ctx, &fleetnetv1alpha1.EndpointSliceExport{}, client.InNamespace(hubNSForMember))).Should(Succeed())
+			Expect(hubClient.DeleteAllOf(ctx, &fleetnetv1alpha1.EndpointSliceExport{}, client.InNamespace(hubExportNSForSvc))).Should(Succeed())
 			// Confirm that all EndpointSliceExports have been deleted; this helps make the test less flaky.
 			Eventually(endpointSliceIsNotExportedActual, eventuallyTimeout, eventuallyInterval).Should(BeNil())
 		})
@@ -750,7 +826,7 @@ var _ = Describe("endpointslice controller (export endpointslice or update expor
 
 			Eventually(func() error {
 				endpointSliceExportList := &fleetnetv1alpha1.EndpointSliceExportList{}
-				if err := hubClient.List(ctx, endpointSliceExportList, &client.ListOptions{Namespace: hubNSForMember}); err != nil {
+				if err := hubClient.List(ctx, endpointSliceExportList, &client.ListOptions{Namespace: hubExportNSForSvc}); err != nil {
 					return fmt.Errorf("endpointSliceExport List(), got %w, want no error", err)
 				}
 
@@ -803,7 +879,7 @@ var _ = Describe("endpointslice controller (export endpointslice or update expor
 			// Confirm that the ServiceExport is deleted; this helps make the test less flaky.
 			Eventually(serviceExportIsAbsentActual, eventuallyTimeout, eventuallyInterval).Should(BeNil())
 
-			Expect(hubClient.DeleteAllOf(ctx, &fleetnetv1alpha1.EndpointSliceExport{}, client.InNamespace(hubNSForMember))).Should(Succeed())
+			Expect(hubClient.DeleteAllOf(ctx, &fleetnetv1alpha1.EndpointSliceExport{}, client.InNamespace(hubExportNSForSvc))).Should(Succeed())
 			// Confirm that all EndpointSliceExports have been deleted; this helps make the test less flaky.
 			Eventually(endpointSliceIsNotExportedActual, eventuallyTimeout, eventuallyInterval).Should(BeNil())
 		})
@@ -843,7 +919,7 @@ var _ = Describe("endpointslice controller (export endpointslice or update expor
 			var endpointSliceExport *fleetnetv1alpha1.EndpointSliceExport
 			Eventually(func() error {
 				endpointSliceExportList := &fleetnetv1alpha1.EndpointSliceExportList{}
-				if err := hubClient.List(ctx, endpointSliceExportList, &client.ListOptions{Namespace: hubNSForMember}); err != nil {
+				if err := hubClient.List(ctx, endpointSliceExportList, &client.ListOptions{Namespace: hubExportNSForSvc}); err != nil {
 					return fmt.Errorf("endpointSliceExport List(), got %w, want no error", err)
 				}
 
@@ -865,6 +941,21 @@ var _ = Describe("endpointslice controller (export endpointslice or update expor
 				return nil
 			}, eventuallyTimeout, eventuallyInterval).Should(BeNil())
 
+			// Confirm that the export latency of the generation has been observed.
+			endpointSliceExportKey := types.NamespacedName{Namespace: hubExportNSForSvc, Name: endpointSliceExport.Name}
+			Eventually(func() error {
+				if err := hubClient.Get(ctx, endpointSliceExportKey, endpointSliceExport); err != nil {
+					return fmt.Errorf("endpointSliceExport Get(%+v), got %w, want no error", endpointSliceExportKey, err)
+				}
+				lastObservedGeneration := endpointSliceExport.Annotations[metrics.MetricsAnnotationLastObservedGeneration]
+				if lastObservedGeneration != fmt.Sprintf("%d", endpointSlice.Generation) {
+					return fmt.Errorf("lastObservedGeneration, got %s, want %d", lastObservedGeneration, endpointSlice.Generation)
+				}
+				return nil
+			}, eventuallyTimeout, eventuallyInterval).Should(BeNil())
+			sampleCount, err := endpointSliceExportLatencySampleCount(memberUserNS)
+			Expect(err).ToNot(HaveOccurred())
+
 			// Update the EndpointSlice.
 			endpointSlice.Endpoints = append(endpointSlice.Endpoints, discoveryv1.Endpoint{
 				Addresses: []string{altIPv4Addr},
@@ -899,7 +990,6 @@ var _ = Describe("endpointslice controller (export endpointslice or update expor
 			}, eventuallyTimeout, eventuallyInterval).Should(BeNil())
 
 			// Confirm that the EndpointSliceExport has been updated.
-			endpointSliceExportKey := types.NamespacedName{Namespace: hubNSForMember, Name: endpointSliceExport.Name}
 			Eventually(func() error {
 				if err := hubClient.Get(ctx, endpointSliceExportKey, endpointSliceExport); err != nil {
 					return fmt.Errorf("endpointSliceExport Get(%+v), got %w, want no error", endpointSliceExportKey, err)
@@ -929,6 +1019,99 @@ var _ = Describe("endpointslice controller (export endpointslice or update expor
 				}
 				return nil
 			}, eventuallyTimeout, eventuallyInterval).Should(BeNil())
+
+			// Confirm that exactly one data point has been observed for the new generation.
+			wantSampleCount := sampleCount + 1
+			Eventually(endpointSliceExportLatencySampleCount, eventuallyTimeout, eventuallyInterval).
+				WithArguments(memberUserNS).Should(Equal(wantSampleCount))
+			Consistently(endpointSliceExportLatencySampleCount, consistentlyDuration, consistentlyInterval).
+				WithArguments(memberUserNS).Should(Equal(wantSampleCount))
+		})
+	})
+
+	Context("exported endpointslice with terminating endpoints", func() {
+		var (
+			endpointSlice *discoveryv1.EndpointSlice
+			svcExport     *fleetnetv1alpha1.ServiceExport
+		)
+
+		BeforeEach(func() {
+			svcExport = notYetFulfilledServiceExport()
+			Expect(memberClient.Create(ctx, svcExport)).Should(Succeed())
+			meta.SetStatusCondition(&svcExport.Status.Conditions, serviceExportValidCondition(memberUserNS, svcName))
+			meta.SetStatusCondition(&svcExport.Status.Conditions, serviceExportNoConflictCondition(memberUserNS, svcName))
+			Expect(memberClient.Status().Update(ctx, svcExport)).Should(Succeed())
+
+			endpointSlice = managedIPv4EndpointSliceWithoutUniqueNameAnnotation()
+			endpointSlice.Endpoints = append(endpointSlice.Endpoints, discoveryv1.Endpoint{
+				Addresses: []string{altIPv4Addr},
+			})
+			Expect(memberClient.Create(ctx, endpointSlice)).Should(Succeed())
+		})
+
+		AfterEach(func() {
+			Expect(memberClient.Delete(ctx, endpointSlice)).Should(Succeed())
+			// Confirm that the EndpointSlice is deleted; this helps make the test less flaky.
+			Eventually(endpointSliceIsAbsentActual, eventuallyTimeout, eventuallyInterval).Should(BeNil())
+
+			Expect(memberClient.Delete(ctx, svcExport)).Should(Succeed())
+			// Confirm that the ServiceExport is deleted; this helps make the test less flaky.
+			Eventually(serviceExportIsAbsentActual, eventuallyTimeout, eventuallyInterval).Should(BeNil())
+
+			Expect(hubClient.DeleteAllOf(ctx, &fleetnetv1alpha1.EndpointSliceExport{}, client.InNamespace(hubExportNSForSvc))).Should(Succeed())
+			// Confirm that all EndpointSliceExports have been deleted; this helps make the test less flaky.
+			Eventually(endpointSliceIsNotExportedActual, eventuallyTimeout, eventuallyInterval).Should(BeNil())
+		})
+
+		It("should export the serving terminating endpoints with their conditions", func() {
+			exportedEndpointsActual := func(expectedEndpoints []fleetnetv1alpha1.Endpoint) func() error {
+				return func() error {
+					endpointSliceExportList := &fleetnetv1alpha1.EndpointSliceExportList{}
+					if err := hubClient.List(ctx, endpointSliceExportList, &client.ListOptions{Namespace: hubExportNSForSvc}); err != nil {
+						return fmt.Errorf("endpointSliceExport List(), got %w, want no error", err)
+					}
+					if len(endpointSliceExportList.Items) != 1 {
+						return fmt.Errorf("endpointSliceExportList length, got %d, want %d", len(endpointSliceExportList.Items), 1)
+					}
+					if diff := cmp.Diff(endpointSliceExportList.Items[0].Spec.Endpoints, expectedEndpoints); diff != "" {
+						return fmt.Errorf("endpoints (-got, +want): %s", diff)
+					}
+					return nil
+				}
+			}
+
+			// Verify first that the EndpointSlice has been exported.
+			Eventually(exportedEndpointsActual([]fleetnetv1alpha1.Endpoint{
+				{
+					Addresses: []string{ipv4Addr},
+				},
+				{
+					Addresses: []string{altIPv4Addr},
+				},
+			}), eventuallyTimeout, eventuallyInterval).Should(BeNil())
+
+			// Terminate the pods behind the endpoints; one of them keeps serving the in-flight requests while the
+			// other has stopped serving.
+			Expect(memberClient.Get(ctx, endpointSliceKey, endpointSlice)).Should(Succeed())
+			endpointSlice.Endpoints[0].Conditions = discoveryv1.EndpointConditions{
+				Ready:       ptr.To(false),
+				Serving:     ptr.To(true),
+				Terminating: ptr.To(true),
+			}
+			endpointSlice.Endpoints[1].Conditions = discoveryv1.EndpointConditions{
+				Ready:       ptr.To(false),
+				Serving:     ptr.To(false),
+				Terminating: ptr.To(true),
+			}
+			Expect(memberClient.Update(ctx, endpointSlice)).Should(Succeed())
+
+			Eventually(exportedEndpointsActual([]fleetnetv1alpha1.Endpoint{
+				{
+					Addresses:   []string{ipv4Addr},
+					Serving:     ptr.To(true),
+					Terminating: ptr.To(true),
+				},
+			}), eventuallyTimeout, eventuallyInterval).Should(BeNil())
 		})
 	})
 
@@ -962,7 +1145,7 @@ var _ = Describe("endpointslice controller (export endpointslice or update expor
 			// Confirm that the ServiceExport is deleted; this helps make the test less flaky.
 			Eventually(serviceExportIsAbsentActual, eventuallyTimeout, eventuallyInterval).Should(BeNil())
 
-			Expect(hubClient.DeleteAllOf(ctx, &fleetnetv1alpha1.EndpointSliceExport{}, client.InNamespace(hubNSForMember))).Should(Succeed())
+			Expect(hubClient.DeleteAllOf(ctx, &fleetnetv1alpha1.EndpointSliceExport{}, client.InNamespace(hubExportNSForSvc))).Should(Succeed())
 			// Confirm that all EndpointSliceExports have been deleted; this helps make the test less flaky.
 			Eventually(endpointSliceIsNotExportedActual, eventuallyTimeout, eventuallyInterval).Should(BeNil())
 		})
@@ -1002,7 +1185,7 @@ var _ = Describe("endpointslice controller (export endpointslice or update expor
 			var originalEndpointSliceExport *fleetnetv1alpha1.EndpointSliceExport
 			Eventually(func() error {
 				endpointSliceExportList := &fleetnetv1alpha1.EndpointSliceExportList{}
-				if err := hubClient.List(ctx, endpointSliceExportList, &client.ListOptions{Namespace: hubNSForMember}); err != nil {
+				if err := hubClient.List(ctx, endpointSliceExportList, &client.ListOptions{Namespace: hubExportNSForSvc}); err != nil {
 					return fmt.Errorf("endpointSliceExport List(), got %w, want no error", err)
 				}
 
@@ -1031,7 +1214,7 @@ var _ = Describe("endpointslice controller (export endpointslice or update expor
 			// Confirm that the EndpointSlice has been exported again with a new name.
 			Eventually(func() error {
 				endpointSliceExportList := &fleetnetv1alpha1.EndpointSliceExportList{}
-				if err := hubClient.List(ctx, endpointSliceExportList, &client.ListOptions{Namespace: hubNSForMember}); err != nil {
+				if err := hubClient.List(ctx, endpointSliceExportList, &client.ListOptions{Namespace: hubExportNSForSvc}); err != nil {
 					return fmt.Errorf("endpointSliceExport List(), got %w, want no error", err)
 				}
 
@@ -1048,7 +1231,7 @@ var _ = Describe("endpointslice controller (export endpointslice or update expor
 
 			newEndpointSliceExport := &fleetnetv1alpha1.EndpointSliceExport{}
 			newEndpointSliceExportKey := types.NamespacedName{
-				Namespace: hubNSForMember,
+				Namespace: hubExportNSForSvc,
 				Name:      newEndpointSliceExportName,
 			}
 			Expect(hubClient.Get(ctx, newEndpointSliceExportKey, newEndpointSliceExport)).Should(Succeed())
@@ -1075,7 +1258,7 @@ var _ = Describe("endpointslice controller (export endpointslice or update expor
 
 		altEndpointSliceExport := &fleetnetv1alpha1.EndpointSliceExport{
 			ObjectMeta: metav1.ObjectMeta{
-				Namespace: hubNSForMember,
+				Namespace: hubExportNSForSvc,
 				Name:      endpointSliceUniqueName,
 			},
 			Spec: fleetnetv1alpha1.EndpointSliceExportSpec{
@@ -1129,7 +1312,7 @@ var _ = Describe("endpointslice controller (export endpointslice or update expor
 			// Confirm that the ServiceExport is deleted; this helps make the test less flaky.
 			Eventually(serviceExportIsAbsentActual, eventuallyTimeout, eventuallyInterval).Should(BeNil())
 
-			Expect(hubClient.DeleteAllOf(ctx, &fleetnetv1alpha1.EndpointSliceExport{}, client.InNamespace(hubNSForMember))).Should(Succeed())
+			Expect(hubClient.DeleteAllOf(ctx, &fleetnetv1alpha1.EndpointSliceExport{}, client.InNamespace(hubExportNSForSvc))).Should(Succeed())
 			// Confirm that all EndpointSliceExports have been deleted; this helps make the test less flaky.
 			Eventually(endpointSliceIsNotExportedActual, eventuallyTimeout, eventuallyInterval).Should(BeNil())
 		})
@@ -1169,7 +1352,7 @@ var _ = Describe("endpointslice controller (export endpointslice or update expor
 			var originalEndpointSliceExport *fleetnetv1alpha1.EndpointSliceExport
 			Eventually(func() error {
 				endpointSliceExportList := &fleetnetv1alpha1.EndpointSliceExportList{}
-				if err := hubClient.List(ctx, endpointSliceExportList, &client.ListOptions{Namespace: hubNSForMember}); err != nil {
+				if err := hubClient.List(ctx, endpointSliceExportList, &client.ListOptions{Namespace: hubExportNSForSvc}); err != nil {
 					return fmt.Errorf("endpointSliceExport List(), got %w, want no error", err)
 				}
 
@@ -1209,7 +1392,7 @@ var _ = Describe("endpointslice controller (export endpointslice or update expor
 			// Confirm that the EndpointSlice has been exported again with a new name.
 			Eventually(func() error {
 				endpointSliceExportList := &fleetnetv1alpha1.EndpointSliceExportList{}
-				if err := hubClient.List(ctx, endpointSliceExportList, &client.ListOptions{Namespace: hubNSForMember}); err != nil {
+				if err := hubClient.List(ctx, endpointSliceExportList, &client.ListOptions{Namespace: hubExportNSForSvc}); err != nil {
 					return fmt.Errorf("endpointSliceExport List(), got %w, want no error", err)
 				}
 
@@ -1226,7 +1409,7 @@ var _ = Describe("endpointslice controller (export endpointslice or update expor
 
 			newEndpointSliceExport := &fleetnetv1alpha1.EndpointSliceExport{}
 			newEndpointSliceExportKey := types.NamespacedName{
-				Namespace: hubNSForMember,
+				Namespace: hubExportNSForSvc,
 				Name:      newEndpointSliceExportName,
 			}
 			Expect(hubClient.Get(ctx, newEndpointSliceExportKey, newEndpointSliceExport)).Should(Succeed())
@@ -1308,7 +1491,7 @@ var _ = Describe("endpointslice controller (service export status changes)", Ser
 			// Confirm that the ServiceExport is deleted; this helps make the test less flaky.
 			Eventually(serviceExportIsAbsentActual, eventuallyTimeout, eventuallyInterval).Should(BeNil())
 
-			Expect(hubClient.DeleteAllOf(ctx, &fleetnetv1alpha1.EndpointSliceExport{}, client.InNamespace(hubNSForMember))).Should(Succeed())
+			Expect(hubClient.DeleteAllOf(ctx, &fleetnetv1alpha1.EndpointSliceExport{}, client.InNamespace(hubExportNSForSvc))).Should(Succeed())
 			// Confirm that all EndpointSliceExports have been deleted; this helps make the test less flaky.
 			Eventually(endpointSliceIsNotExportedActual, eventuallyTimeout, eventuallyInterval).Should(BeNil())
 		})
@@ -1374,7 +1557,7 @@ var _ = Describe("endpointslice controller (service export status changes)", Ser
 
 			Eventually(func() error {
 				endpointSliceExportList := &fleetnetv1alpha1.EndpointSliceExportList{}
-				if err := hubClient.List(ctx, endpointSliceExportList, &client.ListOptions{Namespace: hubNSForMember}); err != nil {
+				if err := hubClient.List(ctx, endpointSliceExportList, &client.ListOptions{Namespace: hubExportNSForSvc}); err != nil {
 					return fmt.Errorf("endpointSliceExport List(), got %w, want no error", err)
 				}
 
@@ -1415,9 +1598,15 @@ var _ = Describe("endpointslice controller (service export status changes)", Ser
 			Expect(memberClient.Delete(ctx, svcExport)).Should(Succeed())
 			// Confirm that the ServiceExport is deleted; this helps make the test less flaky.
 			Eventually(serviceExportIsAbsentActual, eventuallyTimeout, eventuallyInterval).Should(BeNil())
+
+			// The EndpointSliceExports left behind are deleted by the hub cluster, which does not run in the test
+			// environment.
+			Expect(hubClient.DeleteAllOf(ctx, &fleetnetv1alpha1.EndpointSliceExport{}, client.InNamespace(hubExportNSForSvc))).Should(Succeed())
+			// Confirm that all EndpointSliceExports have been deleted; this helps make the test less flaky.
+			Eventually(endpointSliceIsNotExportedActual, eventuallyTimeout, eventuallyInterval).Should(BeNil())
 		})
 
-		It("should unexport endpointslices when service export becomes invalid", func() {
+		It("should soft unexport endpointslices when service export becomes invalid, and export them again once valid", func() {
 			// Confirm that the EndpointSlice has been exported.
 			Eventually(func() error {
 				if err := memberClient.Get(ctx, endpointSliceKey, endpointSlice); err != nil {
@@ -1451,7 +1640,7 @@ var _ = Describe("endpointslice controller (service export status changes)", Ser
 
 			Eventually(func() error {
 				endpointSliceExportList := &fleetnetv1alpha1.EndpointSliceExportList{}
-				if err := hubClient.List(ctx, endpointSliceExportList, &client.ListOptions{Namespace: hubNSForMember}); err != nil {
+				if err := hubClient.List(ctx, endpointSliceExportList, &client.ListOptions{Namespace: hubExportNSForSvc}); err != nil {
 					return fmt.Errorf("endpointSliceExport List(), got %w, want no error", err)
 				}
 
@@ -1476,19 +1665,33 @@ var _ = Describe("endpointslice controller (service export status changes)", Ser
 			meta.SetStatusCondition(&svcExport.Status.Conditions, serviceExportInvalidNotFoundCondition(memberUserNS, svcName))
 			Expect(memberClient.Status().Update(ctx, svcExport)).Should(Succeed())
 
-			// Confirm that the EndpointSlice has been unexported.
+			// Confirm that the EndpointSlice has been soft unexported.
+			Eventually(endpointSliceIsSoftUnexportedActual, eventuallyTimeout, eventuallyInterval).Should(BeNil())
+
+			// Update the status of ServiceExport (invalid -> valid), as when it is revalidated.
+			meta.SetStatusCondition(&svcExport.Status.Conditions, serviceExportValidCondition(memberUserNS, svcName))
+			Expect(memberClient.Status().Update(ctx, svcExport)).Should(Succeed())
+
+			// Confirm that the EndpointSlice has been exported again under the same name, and that its
+			// EndpointSliceExport is no longer a deletion candidate.
 			Eventually(func() error {
-				if err := memberClient.Get(ctx, endpointSliceKey, endpointSlice); err != nil {
-					return fmt.Errorf("endpointSlice Get(%+v), got %w, want no error", endpointSliceKey, err)
+				endpointSliceExportList := &fleetnetv1alpha1.EndpointSliceExportList{}
+				if err := hubClient.List(ctx, endpointSliceExportList, &client.ListOptions{Namespace: hubExportNSForSvc}); err != nil {
+					return fmt.Errorf("endpointSliceExport List(), got %w, want no error", err)
 				}
 
-				if len(endpointSlice.Annotations) != 0 {
-					return fmt.Errorf("endpointSlice annotations, got %v, want empty", endpointSlice.Annotations)
+				if len(endpointSliceExportList.Items) != 1 {
+					return fmt.Errorf("endpointSliceExport list length, got %d, want %d", len(endpointSliceExportList.Items), 1)
+				}
+				endpointSliceExport := endpointSliceExportList.Items[0]
+				if endpointSliceExport.Name != endpointSlice.Annotations[objectmeta.ExportedObjectAnnotationUniqueName] {
+					return fmt.Errorf("endpointSliceExport name, got %s, want %s", endpointSliceExport.Name, endpointSlice.Annotations[objectmeta.ExportedObjectAnnotationUniqueName])
+				}
+				if _, ok := endpointSliceExport.Annotations[objectmeta.EndpointSliceExportAnnotationDeletionCandidateSince]; ok {
+					return fmt.Errorf("endpointSliceExport deletion candidate annotation is present")
 				}
 				return nil
 			}, eventuallyTimeout, eventuallyInterval).Should(BeNil())
-
-			Eventually(endpointSliceIsNotExportedActual, eventuallyTimeout, eventuallyInterval).Should(BeNil())
 		})
 	})
 
@@ -1521,9 +1724,15 @@ var _ = Describe("endpointslice controller (service export status changes)", Ser
 			Expect(memberClient.Delete(ctx, svcExport)).Should(Succeed())
 			// Confirm that the ServiceExport is deleted; this helps make the test less flaky.
 			Eventually(serviceExportIsAbsentActual, eventuallyTimeout, eventuallyInterval).Should(BeNil())
+
+			// The EndpointSliceExports left behind are deleted by the hub cluster, which does not run in the test
+			// environment.
+			Expect(hubClient.DeleteAllOf(ctx, &fleetnetv1alpha1.EndpointSliceExport{}, client.InNamespace(hubExportNSForSvc))).Should(Succeed())
+			// Confirm that all EndpointSliceExports have been deleted; this helps make the test less flaky.
+			Eventually(endpointSliceIsNotExportedActual, eventuallyTimeout, eventuallyInterval).Should(BeNil())
 		})
 
-		It("should unexport endpointslices when service export becomes conflicted", func() {
+		It("should soft unexport endpointslices when service export becomes conflicted", func() {
 			// Confirm that the EndpointSlice has been exported.
 			Eventually(func() error {
 				if err := memberClient.Get(ctx, endpointSliceKey, endpointSlice); err != nil {
@@ -1557,7 +1766,7 @@ var _ = Describe("endpointslice controller (service export status changes)", Ser
 
 			Eventually(func() error {
 				endpointSliceExportList := &fleetnetv1alpha1.EndpointSliceExportList{}
-				if err := hubClient.List(ctx, endpointSliceExportList, &client.ListOptions{Namespace: hubNSForMember}); err != nil {
+				if err := hubClient.List(ctx, endpointSliceExportList, &client.ListOptions{Namespace: hubExportNSForSvc}); err != nil {
 					return fmt.Errorf("endpointSliceExport List(), got %w, want no error", err)
 				}
 
@@ -1582,19 +1791,299 @@ var _ = Describe("endpointslice controller (service export status changes)", Ser
 			meta.SetStatusCondition(&svcExport.Status.Conditions, serviceExportConflictedCondition(memberUserNS, svcName))
 			Expect(memberClient.Status().Update(ctx, svcExport)).Should(Succeed())
 
-			// Confirm that the EndpointSlice has been unexported
-			Eventually(func() error {
+			// Confirm that the EndpointSlice has been soft unexported
+			Eventually(endpointSliceIsSoftUnexportedActual, eventuallyTimeout, eventuallyInterval).Should(BeNil())
+		})
+	})
+})
+
+var _ = Describe("endpointslice controller (endpointslice owned by a service in another namespace)", Serial, Ordered, func() {
+	var (
+		mirroredEndpointSliceKey = types.NamespacedName{
+			Namespace: memberMirrorUserNS,
+			Name:      endpointSliceName,
+		}
+		mirroredEndpointSlice *discoveryv1.EndpointSlice
+		svcExport             *fleetnetv1alpha1.ServiceExport
+	)
+
+	BeforeEach(func() {
+		mirroredEndpointSlice = managedIPv4EndpointSliceWithoutUniqueNameAnnotation()
+		mirroredEndpointSlice.Namespace = memberMirrorUserNS
+		mirroredEndpointSlice.Annotations = map[string]string{
+			objectmeta.EndpointSliceAnnotationOwnerServiceNamespace: memberUserNS,
+		}
+		Expect(memberClient.Create(ctx, mirroredEndpointSlice)).Should(Succeed())
+
+		svcExport = notYetFulfilledServiceExport()
+		Expect(memberClient.Create(ctx, svcExport)).Should(Succeed())
+		meta.SetStatusCondition(&svcExport.Status.Conditions, serviceExportValidCondition(memberUserNS, svcName))
+		meta.SetStatusCondition(&svcExport.Status.Conditions, serviceExportNoConflictCondition(memberUserNS, svcName))
+		Expect(memberClient.Status().Update(ctx, svcExport)).Should(Succeed())
+	})
+
+	AfterEach(func() {
+		Expect(memberClient.Delete(ctx, mirroredEndpointSlice)).Should(Succeed())
+		// Confirm that the EndpointSlice is deleted; this helps make the test less flaky.
+		Eventually(func() error {
+			endpointSlice := &discoveryv1.EndpointSlice{}
+			if err := memberClient.Get(ctx, mirroredEndpointSliceKey, endpointSlice); !errors.IsNotFound(err) {
+				return fmt.Errorf("endpointSlice Get(%+v), got %w, want not found", mirroredEndpointSliceKey, err)
+			}
+			return nil
+		}, eventuallyTimeout, eventuallyInterval).Should(BeNil())
+
+		Expect(memberClient.Delete(ctx, svcExport)).Should(Succeed())
+		// Confirm that the ServiceExport is deleted; this helps make the test less flaky.
+		Eventually(serviceExportIsAbsentActual, eventuallyTimeout, eventuallyInterval).Should(BeNil())
+
+		Expect(hubClient.DeleteAllOf(ctx, &fleetnetv1alpha1.EndpointSliceExport{}, client.InNamespace(hubExportNSForSvc))).Should(Succeed())
+		// Confirm that all EndpointSliceExports have been deleted; this helps make the test less flaky.
+		Eventually(endpointSliceIsNotExportedActual, eventuallyTimeout, eventuallyInterval).Should(BeNil())
+	})
+
+	It("should not export the endpointslice if its namespace is not allowed by the service export", func() {
+		Consistently(func() error {
+			endpointSlice := &discoveryv1.EndpointSlice{}
+			if err := memberClient.Get(ctx, mirroredEndpointSliceKey, endpointSlice); err != nil {
+				return fmt.Errorf("endpointSlice Get(%+v), got %w, want no error", mirroredEndpointSliceKey, err)
+			}
+			if _, ok := endpointSlice.Annotations[objectmeta.ExportedObjectAnnotationUniqueName]; ok {
+				return fmt.Errorf("endpointSlice unique name annotation is present")
+			}
+			return nil
+		}, consistentlyDuration, consistentlyInterval).Should(BeNil())
+		Consistently(endpointSliceIsNotExportedActual, consistentlyDuration, consistentlyInterval).Should(BeNil())
+	})
+
+	It("should export the endpointslice once its namespace is allowed and unexport it once disallowed", func() {
+		// Allow the namespace of the EndpointSlice on the ServiceExport.
+		Expect(memberClient.Get(ctx, svcKey, svcExport)).Should(Succeed())
+		svcExport.Annotations = map[string]string{
+			objectmeta.ServiceExportAnnotationAllowedEndpointSliceNamespaces: memberMirrorUserNS,
+		}
+		Expect(memberClient.Update(ctx, svcExport)).Should(Succeed())
+
+		var uniqueName string
+		Eventually(func() error {
+			endpointSlice := &discoveryv1.EndpointSlice{}
+			if err := memberClient.Get(ctx, mirroredEndpointSliceKey, endpointSlice); err != nil {
+				return fmt.Errorf("endpointSlice Get(%+v), got %w, want no error", mirroredEndpointSliceKey, err)
+			}
+			var ok bool
+			uniqueName, ok = endpointSlice.Annotations[objectmeta.ExportedObjectAnnotationUniqueName]
+			wantPrefix := fmt.Sprintf("%s-%s-%s-", memberClusterID, memberMirrorUserNS, endpointSliceName)
+			if !ok || !strings.HasPrefix(uniqueName, wantPrefix) {
+				return fmt.Errorf("endpointSlice unique name, got %s, want prefix %s", uniqueName, wantPrefix)
+			}
+			return nil
+		}, eventuallyTimeout, eventuallyInterval).Should(BeNil())
+
+		Eventually(func() error {
+			endpointSliceExport := &fleetnetv1alpha1.EndpointSliceExport{}
+			endpointSliceExportKey := types.NamespacedName{Namespace: hubExportNSForSvc, Name: uniqueName}
+			if err := hubClient.Get(ctx, endpointSliceExportKey, endpointSliceExport); err != nil {
+				return fmt.Errorf("endpointSliceExport Get(%+v), got %w, want no error", endpointSliceExportKey, err)
+			}
+			wantOwnerSvcRef := fleetnetv1alpha1.OwnerServiceReference{
+				Namespace:      memberUserNS,
+				Name:           svcName,
+				NamespacedName: fmt.Sprintf("%s/%s", memberUserNS, svcName),
+			}
+			if diff := cmp.Diff(endpointSliceExport.Spec.OwnerServiceReference, wantOwnerSvcRef); diff != "" {
+				return fmt.Errorf("ownerServiceReference (-got, +want): %s", diff)
+			}
+			if got := endpointSliceExport.Spec.EndpointSliceReference.Namespace; got != memberMirrorUserNS {
+				return fmt.Errorf("endpointSliceReference namespace, got %s, want %s", got, memberMirrorUserNS)
+			}
+			return nil
+		}, eventuallyTimeout, eventuallyInterval).Should(BeNil())
+
+		// Revoke the permission.
+		Expect(memberClient.Get(ctx, svcKey, svcExport)).Should(Succeed())
+		svcExport.Annotations = nil
+		Expect(memberClient.Update(ctx, svcExport)).Should(Succeed())
+
+		Eventually(func() error {
+			endpointSlice := &discoveryv1.EndpointSlice{}
+			if err := memberClient.Get(ctx, mirroredEndpointSliceKey, endpointSlice); err != nil {
+				return fmt.Errorf("endpointSlice Get(%+v), got %w, want no error", mirroredEndpointSliceKey, err)
+			}
+			if _, ok := endpointSlice.Annotations[objectmeta.ExportedObjectAnnotationUniqueName]; ok {
+				return fmt.Errorf("endpointSlice unique name annotation is present")
+			}
+			return nil
+		}, eventuallyTimeout, eventuallyInterval).Should(BeNil())
+		Eventually(endpointSliceIsNotExportedActual, eventuallyTimeout, eventuallyInterval).Should(BeNil())
+	})
+})
+
+var _ = Describe("endpointslice controller (long member cluster ID)", Serial, Ordered, func() {
+	// Member cluster IDs derived from Azure resource IDs are often 60+ characters long.
+	longMemberClusterID := "aks-" + strings.Repeat("0123456789", 6)
+	// The namespace and the EndpointSlice name are at their maximum lengths.
+	longNS := strings.Repeat("n", 63)
+	longEndpointSliceName := strings.Repeat("a", 150) + "." + strings.Repeat("b", 102)
+
+	AfterAll(func() {
+		Expect(hubClient.DeleteAllOf(ctx, &fleetnetv1alpha1.EndpointSliceExport{}, client.InNamespace(hubExportNSForSvc))).Should(Succeed())
+		// Confirm that all EndpointSliceExports have been deleted; this helps make the test less flaky.
+		Eventually(endpointSliceIsNotExportedActual, eventuallyTimeout, eventuallyInterval).Should(BeNil())
+	})
+
+	It("should create endpointSliceExports with the unique names assigned with a long cluster ID", func() {
+		seen := map[string]bool{}
+		for _, name := range []string{longEndpointSliceName, longEndpointSliceName[:252], endpointSliceName} {
+			uniqueName, err := uniquename.FleetScopedUniqueName(uniquename.DNS1123Subdomain, longMemberClusterID, longNS, name)
+			Expect(err).ToNot(HaveOccurred(), "failed to format a unique name for endpointSlice %s/%s", longNS, name)
+			Expect(uniqueName).Should(HavePrefix(longMemberClusterID+"-"), "unique name should keep the cluster ID")
+			Expect(seen).ShouldNot(HaveKey(uniqueName), "unique names should not collide")
+			seen[uniqueName] = true
+
+			endpointSliceExport := &fleetnetv1alpha1.EndpointSliceExport{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: hubExportNSForSvc,
+					Name:      uniqueName,
+				},
+				Spec: fleetnetv1alpha1.EndpointSliceExportSpec{
+					AddressType: discoveryv1.AddressTypeIPv4,
+					Endpoints: []fleetnetv1alpha1.Endpoint{
+						{
+							Addresses: []string{ipv4Addr},
+						},
+					},
+					EndpointSliceReference: fleetnetv1alpha1.ExportedObjectReference{
+						ClusterID:       longMemberClusterID,
+						Kind:            "EndpointSlice",
+						Namespace:       longNS,
+						Name:            name,
+						ResourceVersion: "0",
+						Generation:      1,
+						UID:             types.UID(uniqueName),
+						NamespacedName:  fmt.Sprintf("%s/%s", longNS, name),
+					},
+					OwnerServiceReference: fleetnetv1alpha1.OwnerServiceReference{
+						Namespace:      longNS,
+						Name:           svcName,
+						NamespacedName: fmt.Sprintf("%s/%s", longNS, svcName),
+					},
+				},
+			}
+			Expect(hubClient.Create(ctx, endpointSliceExport)).Should(Succeed(), "hub should accept endpointSliceExport %s", uniqueName)
+		}
+	})
+})
+
+var _ = Describe("endpointslice controller (export endpointslices across the hub export shards)", Serial, Ordered, func() {
+	Context("endpointslices of services in different shards", func() {
+		// The Services are sharded by their namespaces and names; app lands in the member cluster namespace and web
+		// in the other shard namespace.
+		shardedSvcName := "web"
+		shardedEndpointSliceName := "web-endpointslice"
+		wantHubNamespaces := map[string]string{
+			svcName:        hubNSForMember,
+			shardedSvcName: hubShardNSForMember,
+		}
+		var (
+			svcExports     []*fleetnetv1alpha1.ServiceExport
+			endpointSlices []*discoveryv1.EndpointSlice
+		)
+
+		// endpointSliceIsExportedToActual runs with Eventually assertion to make sure that an EndpointSlice has been
+		// exported to the given hub namespace only.
+		endpointSliceIsExportedToActual := func(endpointSliceName, wantHubNS string) func() error {
+			return func() error {
+				endpointSlice := &discoveryv1.EndpointSlice{}
+				endpointSliceKey := types.NamespacedName{Namespace: memberUserNS, Name: endpointSliceName}
 				if err := memberClient.Get(ctx, endpointSliceKey, endpointSlice); err != nil {
 					return fmt.Errorf("endpointSlice Get(%+v), got %w, want no error", endpointSliceKey, err)
 				}
+				uniqueName, ok := endpointSlice.Annotations[objectmeta.ExportedObjectAnnotationUniqueName]
+				if !ok {
+					return fmt.Errorf("endpointSlice unique name annotation is absent")
+				}
+				for _, hubNS := range hubconfig.ExportNamespaces(hubNSForMember, hubExportShards) {
+					endpointSliceExportKey := types.NamespacedName{Namespace: hubNS, Name: uniqueName}
+					endpointSliceExport := &fleetnetv1alpha1.EndpointSliceExport{}
+					err := hubClient.Get(ctx, endpointSliceExportKey, endpointSliceExport)
+					switch {
+					case hubNS != wantHubNS && !errors.IsNotFound(err):
+						return fmt.Errorf("endpointSliceExport Get(%+v), got %w, want not found", endpointSliceExportKey, err)
+					case hubNS != wantHubNS:
+						continue
+					case err != nil:
+						return fmt.Errorf("endpointSliceExport Get(%+v), got %w, want no error", endpointSliceExportKey, err)
+					}
+					if got := endpointSliceExport.Labels[objectmeta.ExportedObjectLabelMemberCluster]; got != memberClusterID {
+						return fmt.Errorf("endpointSliceExport member cluster label, got %q, want %q", got, memberClusterID)
+					}
+				}
+				return nil
+			}
+		}
+
+		BeforeEach(func() {
+			svcExports = nil
+			endpointSlices = nil
+			for name, endpointSliceName := range map[string]string{svcName: endpointSliceName, shardedSvcName: shardedEndpointSliceName} {
+				svcExport := notYetFulfilledServiceExport()
+				svcExport.Name = name
+				Expect(memberClient.Create(ctx, svcExport)).Should(Succeed())
+				meta.SetStatusCondition(&svcExport.Status.Conditions, serviceExportValidCondition(memberUserNS, name))
+				meta.SetStatusCondition(&svcExport.Status.Conditions, serviceExportNoConflictCondition(memberUserNS, name))
+				Expect(memberClient.Status().Update(ctx, svcExport)).Should(Succeed())
+				svcExports = append(svcExports, svcExport)
+
+				endpointSlice := managedIPv4EndpointSliceWithoutUniqueNameAnnotation()
+				endpointSlice.Name = endpointSliceName
+				endpointSlice.Labels[discoveryv1.LabelServiceName] = name
+				Expect(memberClient.Create(ctx, endpointSlice)).Should(Succeed())
+				endpointSlices = append(endpointSlices, endpointSlice)
+			}
+		})
+
+		AfterEach(func() {
+			for _, endpointSlice := range endpointSlices {
+				Expect(client.IgnoreNotFound(memberClient.Delete(ctx, endpointSlice))).Should(Succeed())
+			}
+			for _, svcExport := range svcExports {
+				Expect(client.IgnoreNotFound(memberClient.Delete(ctx, svcExport))).Should(Succeed())
+			}
+
+			for _, hubNS := range hubconfig.ExportNamespaces(hubNSForMember, hubExportShards) {
+				Expect(hubClient.DeleteAllOf(ctx, &fleetnetv1alpha1.EndpointSliceExport{}, client.InNamespace(hubNS))).Should(Succeed())
+			}
+			// Confirm that all EndpointSliceExports have been deleted; this helps make the test less flaky.
+			Eventually(endpointSliceIsNotExportedActual, eventuallyTimeout, eventuallyInterval).Should(BeNil())
+		})
 
-				if len(endpointSlice.Annotations) != 0 {
-					return fmt.Errorf("endpointSlice annotations, got %v, want empty", endpointSlice.Annotations)
+		It("should export the endpointslices to the shards of their services + should unexport them from the shards", func() {
+			for name, endpointSliceName := range map[string]string{svcName: endpointSliceName, shardedSvcName: shardedEndpointSliceName} {
+				wantHubNS := wantHubNamespaces[name]
+				By(fmt.Sprintf("exporting endpointslice %s to hub namespace %s", endpointSliceName, wantHubNS))
+				Expect(hubconfig.ExportNamespace(hubNSForMember, hubExportShards, memberUserNS, name)).Should(Equal(wantHubNS))
+				Eventually(endpointSliceIsExportedToActual(endpointSliceName, wantHubNS), eventuallyTimeout, eventuallyInterval).Should(BeNil())
+			}
+
+			By("unexporting the endpointslice from the shard namespace")
+			for _, svcExport := range svcExports {
+				if svcExport.Name == shardedSvcName {
+					Expect(memberClient.Delete(ctx, svcExport)).Should(Succeed())
+				}
+			}
+			Eventually(func() error {
+				endpointSliceExportList := &fleetnetv1alpha1.EndpointSliceExportList{}
+				if err := hubClient.List(ctx, endpointSliceExportList, client.InNamespace(hubShardNSForMember)); err != nil {
+					return fmt.Errorf("endpointSliceExport List(), got %w, want no error", err)
+				}
+				if len(endpointSliceExportList.Items) > 0 {
+					return fmt.Errorf("endpointSliceExportList, got %+v, want empty list", endpointSliceExportList.Items)
 				}
 				return nil
 			}, eventuallyTimeout, eventuallyInterval).Should(BeNil())
 
-			Eventually(endpointSliceIsNotExportedActual, eventuallyTimeout, eventuallyInterval).Should(BeNil())
+			By("keeping the endpointslice exported to the member cluster namespace")
+			Consistently(endpointSliceIsExportedToActual(endpointSliceName, hubNSForMember), consistentlyDuration, consistentlyInterval).Should(BeNil())
 		})
 	})
 })
@@ -10,17 +10,27 @@ import (
 	"fmt"
 	"log"
 	"os"
+	"sort"
 	"strings"
 	"testing"
 	"time"
 
 	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	corev1 "k8s.io/api/core/v1"
 	discoveryv1 "k8s.io/api/discovery/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/ptr"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+	"sigs.k8s.io/controller-runtime/pkg/event"
 
 	fleetnetv1alpha1 "go.goms.io/fleet-networking/api/v1alpha1"
 	"go.goms.io/fleet-networking/pkg/common/metrics"
@@ -122,7 +132,7 @@ func TestIsEndpointSlicePermanentlyUnexportable(t *testing.T) {
 			want: false,
 		},
 		{
-			name: "should not be exportable (IPv6 endpointslice)",
+			name: "should be exportable (IPv6 endpointslice)",
 			endpointSlice: &discoveryv1.EndpointSlice{
 				ObjectMeta: metav1.ObjectMeta{
 					Namespace: memberUserNS,
@@ -130,6 +140,17 @@ func TestIsEndpointSlicePermanentlyUnexportable(t *testing.T) {
 				},
 				AddressType: discoveryv1.AddressTypeIPv6,
 			},
+			want: false,
+		},
+		{
+			name: "should not be exportable (FQDN endpointslice)",
+			endpointSlice: &discoveryv1.EndpointSlice{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: memberUserNS,
+					Name:      endpointSliceName,
+				},
+				AddressType: discoveryv1.AddressTypeFQDN,
+			},
 			want: true,
 		},
 	}
@@ -150,11 +171,46 @@ func TestExtractEndpointsFromEndpointSlice(t *testing.T) {
 	readyAddress := "1.2.3.4"
 	unknownStateAddress := "2.3.4.5"
 	notReadyAddress := "3.4.5.6"
+	nodeName := "node-1"
+	servingTerminatingAddress := "4.5.6.7"
+	terminatingAddress := "5.6.7.8"
+	terminatingEndpointSlice := &discoveryv1.EndpointSlice{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: memberUserNS,
+			Name:      endpointSliceName,
+		},
+		Endpoints: []discoveryv1.Endpoint{
+			{
+				Addresses: []string{readyAddress},
+				Conditions: discoveryv1.EndpointConditions{
+					Ready: &isReady,
+				},
+			},
+			{
+				Addresses: []string{servingTerminatingAddress},
+				Conditions: discoveryv1.EndpointConditions{
+					Ready:       &isNotReady,
+					Serving:     &isReady,
+					Terminating: &isReady,
+				},
+				NodeName: &nodeName,
+			},
+			{
+				Addresses: []string{terminatingAddress},
+				Conditions: discoveryv1.EndpointConditions{
+					Ready:       &isNotReady,
+					Serving:     &isNotReady,
+					Terminating: &isReady,
+				},
+			},
+		},
+	}
 
 	testCases := []struct {
-		name              string
-		endpointSlice     *discoveryv1.EndpointSlice
-		expectedEndpoints []fleetnetv1alpha1.Endpoint
+		name                 string
+		endpointSlice        *discoveryv1.EndpointSlice
+		propagateTerminating bool
+		expectedEndpoints    []fleetnetv1alpha1.Endpoint
 	}{
 		{
 			name: "should extract ready endpoints only",
@@ -191,11 +247,60 @@ func TestExtractEndpointsFromEndpointSlice(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "should keep the node names of the endpoints",
+			endpointSlice: &discoveryv1.EndpointSlice{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: memberUserNS,
+					Name:      endpointSliceName,
+				},
+				Endpoints: []discoveryv1.Endpoint{
+					{
+						Addresses: []string{readyAddress},
+						Conditions: discoveryv1.EndpointConditions{
+							Ready: &isReady,
+						},
+						NodeName: &nodeName,
+					},
+				},
+			},
+			expectedEndpoints: []fleetnetv1alpha1.Endpoint{
+				{
+					Addresses: []string{readyAddress},
+					NodeName:  &nodeName,
+				},
+			},
+		},
+		{
+			name:          "should skip terminating endpoints",
+			endpointSlice: terminatingEndpointSlice,
+			expectedEndpoints: []fleetnetv1alpha1.Endpoint{
+				{
+					Addresses: []string{readyAddress},
+				},
+			},
+		},
+		{
+			name:                 "should extract serving terminating endpoints with their conditions",
+			endpointSlice:        terminatingEndpointSlice,
+			propagateTerminating: true,
+			expectedEndpoints: []fleetnetv1alpha1.Endpoint{
+				{
+					Addresses: []string{readyAddress},
+				},
+				{
+					Addresses:   []string{servingTerminatingAddress},
+					NodeName:    &nodeName,
+					Serving:     &isReady,
+					Terminating: &isReady,
+				},
+			},
+		},
 	}
 
 	for _, tc := range testCases {
 		t.Run(tc.name, func(t *testing.T) {
-			extractedEndpoints := extractEndpointsFromEndpointSlice(tc.endpointSlice)
+			extractedEndpoints := extractEndpointsFromEndpointSlice(tc.endpointSlice, tc.propagateTerminating)
 			if !cmp.Equal(extractedEndpoints, tc.expectedEndpoints) {
 				t.Fatalf("extractEndpointsFromEndpointSlice(%+v) = %+v, want %+v", tc.endpointSlice, extractedEndpoints, tc.expectedEndpoints)
 			}
@@ -296,6 +401,133 @@ func TestUnexportLinkedEndpointSlice(t *testing.T) {
 	}
 }
 
+// TestMarkEndpointSliceExportAsDeletionCandidate tests the *Reconciler.markEndpointSliceExportAsDeletionCandidate
+// method.
+func TestMarkEndpointSliceExportAsDeletionCandidate(t *testing.T) {
+	endpointSlice := &discoveryv1.EndpointSlice{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: memberUserNS,
+			Name:      endpointSliceName,
+			Labels: map[string]string{
+				discoveryv1.LabelServiceName: svcName,
+			},
+			Annotations: map[string]string{
+				objectmeta.ExportedObjectAnnotationUniqueName: endpointSliceUniqueName,
+			},
+			UID: "1",
+		},
+		AddressType: discoveryv1.AddressTypeIPv4,
+	}
+	endpointSliceExport := func(uid types.UID, annotations map[string]string) *fleetnetv1alpha1.EndpointSliceExport {
+		return &fleetnetv1alpha1.EndpointSliceExport{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace:   hubNSForMember,
+				Name:        endpointSliceUniqueName,
+				Annotations: annotations,
+			},
+			Spec: fleetnetv1alpha1.EndpointSliceExportSpec{
+				AddressType: discoveryv1.AddressTypeIPv4,
+				Endpoints: []fleetnetv1alpha1.Endpoint{
+					{
+						Addresses: []string{"1.2.3.4"},
+					},
+				},
+				EndpointSliceReference: fleetnetv1alpha1.ExportedObjectReference{
+					ClusterID: memberClusterID,
+					Kind:      "EndpointSlice",
+					Namespace: memberUserNS,
+					Name:      endpointSliceName,
+					UID:       uid,
+				},
+				OwnerServiceReference: fleetnetv1alpha1.OwnerServiceReference{
+					Namespace:      memberUserNS,
+					Name:           svcName,
+					NamespacedName: memberUserNS + "/" + svcName,
+				},
+			},
+		}
+	}
+
+	testCases := []struct {
+		name                string
+		endpointSliceExport *fleetnetv1alpha1.EndpointSliceExport
+		wantApplied         bool
+	}{
+		{
+			name: "should mark the linked endpoint slice export",
+			endpointSliceExport: endpointSliceExport("1", map[string]string{
+				objectmeta.EndpointSliceExportAnnotationExcludedEndpoints: "2",
+			}),
+			wantApplied: true,
+		},
+		{
+			name:                "should skip the endpoint slice export linked with another endpoint slice",
+			endpointSliceExport: endpointSliceExport("2", nil),
+		},
+		{
+			name: "should skip the endpoint slice export marked already",
+			endpointSliceExport: endpointSliceExport("1", map[string]string{
+				objectmeta.EndpointSliceExportAnnotationDeletionCandidateSince: "2024-01-01T00:00:00Z",
+			}),
+		},
+		{
+			name: "should skip the endpoint slice not exported",
+		},
+	}
+
+	ctx := context.Background()
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var applied *fleetnetv1alpha1.EndpointSliceExport
+			fakeHubClientBuilder := fake.NewClientBuilder().
+				WithScheme(scheme.Scheme).
+				WithInterceptorFuncs(interceptor.Funcs{
+					Patch: func(_ context.Context, _ client.WithWatch, obj client.Object, patch client.Patch, _ ...client.PatchOption) error {
+						if patch.Type() != types.ApplyPatchType {
+							return fmt.Errorf("patch type, got %s, want %s", patch.Type(), types.ApplyPatchType)
+						}
+						applied = obj.(*fleetnetv1alpha1.EndpointSliceExport)
+						return nil
+					},
+				})
+			if tc.endpointSliceExport != nil {
+				fakeHubClientBuilder = fakeHubClientBuilder.WithObjects(tc.endpointSliceExport)
+			}
+			reconciler := &Reconciler{
+				MemberClusterID: memberClusterID,
+				HubClient:       fakeHubClientBuilder.Build(),
+				HubNamespace:    hubNSForMember,
+			}
+
+			if err := reconciler.markEndpointSliceExportAsDeletionCandidate(ctx, endpointSlice); err != nil {
+				t.Fatalf("markEndpointSliceExportAsDeletionCandidate(), got %v, want no error", err)
+			}
+			if !tc.wantApplied {
+				if applied != nil {
+					t.Fatalf("markEndpointSliceExportAsDeletionCandidate() applied %+v, want no apply", applied)
+				}
+				return
+			}
+			if applied == nil {
+				t.Fatalf("markEndpointSliceExportAsDeletionCandidate() applied nothing, want the endpoint slice export marked")
+			}
+			if diff := cmp.Diff(tc.endpointSliceExport.Spec, applied.Spec); diff != "" {
+				t.Errorf("applied endpointSliceExport spec (-want, +got):\n%s", diff)
+			}
+			if got := applied.Annotations[objectmeta.EndpointSliceExportAnnotationExcludedEndpoints]; got != "2" {
+				t.Errorf("applied endpointSliceExport excluded endpoints annotation, got %q, want %q", got, "2")
+			}
+			candidateSince, ok := applied.Annotations[objectmeta.EndpointSliceExportAnnotationDeletionCandidateSince]
+			if !ok {
+				t.Fatalf("applied endpointSliceExport annotations, got %v, want the deletion candidate annotation", applied.Annotations)
+			}
+			if _, err := time.Parse(time.RFC3339, candidateSince); err != nil {
+				t.Errorf("deletion candidate annotation Parse(%s), got %v, want no error", candidateSince, err)
+			}
+		})
+	}
+}
+
 // TestUnexportUnlinkedEndpointSlice tests the *Reconciler.unexportEndpointSlice and the
 // *Reconciler.deleteEndpointSliceIfLinked method.
 func TestUnexportUnlinkedEndpointSlice(t *testing.T) {
@@ -440,7 +672,7 @@ func TestShouldSkipOrUnexportEndpointSlice_NoServiceExport(t *testing.T) {
 					Namespace: memberUserNS,
 					Name:      endpointSliceName,
 				},
-				AddressType: discoveryv1.AddressTypeIPv6,
+				AddressType: discoveryv1.AddressTypeFQDN,
 			},
 			want: shouldSkipEndpointSliceOp,
 		},
@@ -539,7 +771,7 @@ func TestShouldSkipOrUnexportEndpointSlice_InvalidOrConflictedServiceExport(t *t
 		want          skipOrUnexportEndpointSliceOp
 	}{
 		{
-			name: "should unexport endpoint slice (invalid svc export)",
+			name: "should soft unexport endpoint slice (invalid svc export)",
 			endpointSlice: &discoveryv1.EndpointSlice{
 				ObjectMeta: metav1.ObjectMeta{
 					Namespace: memberUserNS,
@@ -564,10 +796,10 @@ func TestShouldSkipOrUnexportEndpointSlice_InvalidOrConflictedServiceExport(t *t
 					},
 				},
 			},
-			want: shouldUnexportEndpointSliceOp,
+			want: shouldSoftUnexportEndpointSliceOp,
 		},
 		{
-			name: "should unexport endpoint slice (conflicted svc export)",
+			name: "should soft unexport endpoint slice (conflicted svc export)",
 			endpointSlice: &discoveryv1.EndpointSlice{
 				ObjectMeta: metav1.ObjectMeta{
 					Namespace: memberUserNS,
@@ -593,7 +825,7 @@ func TestShouldSkipOrUnexportEndpointSlice_InvalidOrConflictedServiceExport(t *t
 					},
 				},
 			},
-			want: shouldUnexportEndpointSliceOp,
+			want: shouldSoftUnexportEndpointSliceOp,
 		},
 		{
 			name: "should unexport endpoint slice (svc export is deleted)",
@@ -865,84 +1097,812 @@ func TestShouldSkipOrUnexportEndpointSlice_ExportedService(t *testing.T) {
 	}
 }
 
-// TestIsServiceExportValidWithNoConflict tests the isServiceExportValidWithNoConflict function.
-func TestIsServiceExportValidWithNoConflict(t *testing.T) {
+// TestShouldSkipOrUnexportEndpointSlice_TerminatingNamespace tests the shouldSkipOrUnexportEndpointSlice method
+// (EndpointSlices owned by an exported Service whose namespace is being deleted).
+func TestShouldSkipOrUnexportEndpointSlice_TerminatingNamespace(t *testing.T) {
 	deletionTimestamp := metav1.Now()
+	ns := &corev1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:              memberUserNS,
+			DeletionTimestamp: &deletionTimestamp,
+			// Note that fake client will reject object that is deleted (has the deletion
+			// timestamp) but does not have finalizers.
+			Finalizers: []string{
+				customDeletionBlockerFinalizer,
+			},
+		},
+	}
+	svcExport := &fleetnetv1alpha1.ServiceExport{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: memberUserNS,
+			Name:      svcName,
+		},
+		Status: fleetnetv1alpha1.ServiceExportStatus{
+			Conditions: []metav1.Condition{
+				serviceExportValidCondition(memberUserNS, svcName),
+				serviceExportNoConflictCondition(memberUserNS, svcName),
+			},
+		},
+	}
 
 	testCases := []struct {
-		name      string
-		svcExport *fleetnetv1alpha1.ServiceExport
-		want      bool
+		name          string
+		endpointSlice *discoveryv1.EndpointSlice
+		want          skipOrUnexportEndpointSliceOp
 	}{
 		{
-			name: "svc export is valid with no conflicts",
-			svcExport: &fleetnetv1alpha1.ServiceExport{
+			name: "should unexport endpoint slice (exported)",
+			endpointSlice: &discoveryv1.EndpointSlice{
 				ObjectMeta: metav1.ObjectMeta{
 					Namespace: memberUserNS,
-					Name:      svcName,
-				},
-				Status: fleetnetv1alpha1.ServiceExportStatus{
-					Conditions: []metav1.Condition{
-						serviceExportValidCondition(memberUserNS, svcName),
-						serviceExportNoConflictCondition(memberUserNS, svcName),
+					Name:      endpointSliceName,
+					Labels: map[string]string{
+						discoveryv1.LabelServiceName: svcName,
+					},
+					Annotations: map[string]string{
+						objectmeta.ExportedObjectAnnotationUniqueName: endpointSliceUniqueName,
 					},
 				},
+				AddressType: discoveryv1.AddressTypeIPv4,
 			},
-			want: true,
+			want: shouldUnexportEndpointSliceOp,
 		},
 		{
-			name: "svc export is invalid",
-			svcExport: &fleetnetv1alpha1.ServiceExport{
+			name: "should skip endpoint slice (not exported)",
+			endpointSlice: &discoveryv1.EndpointSlice{
 				ObjectMeta: metav1.ObjectMeta{
 					Namespace: memberUserNS,
-					Name:      svcName,
-				},
-				Status: fleetnetv1alpha1.ServiceExportStatus{
-					Conditions: []metav1.Condition{
-						serviceExportInvalidNotFoundCondition(memberUserNS, svcName),
+					Name:      endpointSliceName,
+					Labels: map[string]string{
+						discoveryv1.LabelServiceName: svcName,
 					},
 				},
+				AddressType: discoveryv1.AddressTypeIPv4,
 			},
-			want: false,
+			want: shouldSkipEndpointSliceOp,
 		},
+	}
+
+	ctx := context.Background()
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			fakeMemberClient := fake.NewClientBuilder().
+				WithScheme(scheme.Scheme).
+				WithObjects(ns, tc.endpointSlice, svcExport).
+				WithStatusSubresource(tc.endpointSlice, svcExport).
+				Build()
+			reconciler := &Reconciler{
+				MemberClient: fakeMemberClient,
+				HubClient:    fake.NewClientBuilder().WithScheme(scheme.Scheme).Build(),
+				HubNamespace: hubNSForMember,
+			}
+
+			op, err := reconciler.shouldSkipOrUnexportEndpointSlice(ctx, tc.endpointSlice)
+			if err != nil {
+				t.Fatalf("shouldSkipOrUnexportEndpointSlice(%+v), got %v, want no error", tc.endpointSlice, err)
+			}
+			if op != tc.want {
+				t.Fatalf("shouldSkipOrUnexportEndpointSlice(%+v) = %d, want %d", tc.endpointSlice, op, tc.want)
+			}
+		})
+	}
+}
+
+// TestShouldSkipOrUnexportEndpointSlice_CrossNamespace tests the shouldSkipOrUnexportEndpointSlice method
+// (EndpointSlices owned by a Service in another namespace).
+func TestShouldSkipOrUnexportEndpointSlice_CrossNamespace(t *testing.T) {
+	endpointSliceNS := "mirror"
+
+	testCases := []struct {
+		name          string
+		allowedNS     string
+		endpointSlice *discoveryv1.EndpointSlice
+		want          skipOrUnexportEndpointSliceOp
+	}{
 		{
-			name: "svc export is valid but with conflicts",
-			svcExport: &fleetnetv1alpha1.ServiceExport{
+			name:      "should export endpoint slice from an allowed namespace",
+			allowedNS: endpointSliceNS,
+			endpointSlice: &discoveryv1.EndpointSlice{
 				ObjectMeta: metav1.ObjectMeta{
-					Namespace: memberUserNS,
-					Name:      svcName,
-				},
-				Status: fleetnetv1alpha1.ServiceExportStatus{
-					Conditions: []metav1.Condition{
-						serviceExportValidCondition(memberUserNS, svcName),
-						serviceExportConflictedCondition(memberUserNS, svcName),
+					Namespace: endpointSliceNS,
+					Name:      endpointSliceName,
+					Labels: map[string]string{
+						discoveryv1.LabelServiceName: svcName,
+					},
+					Annotations: map[string]string{
+						objectmeta.EndpointSliceAnnotationOwnerServiceNamespace: memberUserNS,
 					},
 				},
+				AddressType: discoveryv1.AddressTypeIPv4,
 			},
-			want: false,
+			want: continueReconcileOp,
 		},
 		{
-			name: "svc export has no conditions set yet",
-			svcExport: &fleetnetv1alpha1.ServiceExport{
+			name: "should skip endpoint slice from a namespace not allowed",
+			endpointSlice: &discoveryv1.EndpointSlice{
 				ObjectMeta: metav1.ObjectMeta{
-					Namespace: memberUserNS,
-					Name:      svcName,
+					Namespace: endpointSliceNS,
+					Name:      endpointSliceName,
+					Labels: map[string]string{
+						discoveryv1.LabelServiceName: svcName,
+					},
+					Annotations: map[string]string{
+						objectmeta.EndpointSliceAnnotationOwnerServiceNamespace: memberUserNS,
+					},
 				},
+				AddressType: discoveryv1.AddressTypeIPv4,
 			},
-			want: false,
+			want: shouldSkipEndpointSliceOp,
 		},
 		{
-			name: "svc export is valid with no conflicts, but has been deleted",
-			svcExport: &fleetnetv1alpha1.ServiceExport{
+			name:      "should unexport endpoint slice from a namespace no longer allowed",
+			allowedNS: "other",
+			endpointSlice: &discoveryv1.EndpointSlice{
 				ObjectMeta: metav1.ObjectMeta{
-					Namespace:         memberUserNS,
-					Name:              svcName,
-					DeletionTimestamp: &deletionTimestamp,
-				},
-				Status: fleetnetv1alpha1.ServiceExportStatus{
-					Conditions: []metav1.Condition{
-						serviceExportValidCondition(memberUserNS, svcName),
-						serviceExportNoConflictCondition(memberUserNS, svcName),
+					Namespace: endpointSliceNS,
+					Name:      endpointSliceName,
+					Labels: map[string]string{
+						discoveryv1.LabelServiceName: svcName,
+					},
+					Annotations: map[string]string{
+						objectmeta.EndpointSliceAnnotationOwnerServiceNamespace: memberUserNS,
+						objectmeta.ExportedObjectAnnotationUniqueName:           endpointSliceUniqueName,
+					},
+				},
+				AddressType: discoveryv1.AddressTypeIPv4,
+			},
+			want: shouldUnexportEndpointSliceOp,
+		},
+		{
+			name:      "should skip endpoint slice without the owner service namespace annotation",
+			allowedNS: endpointSliceNS,
+			endpointSlice: &discoveryv1.EndpointSlice{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: endpointSliceNS,
+					Name:      endpointSliceName,
+					Labels: map[string]string{
+						discoveryv1.LabelServiceName: svcName,
+					},
+				},
+				AddressType: discoveryv1.AddressTypeIPv4,
+			},
+			want: shouldSkipEndpointSliceOp,
+		},
+	}
+
+	ctx := context.Background()
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			svcExport := &fleetnetv1alpha1.ServiceExport{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: memberUserNS,
+					Name:      svcName,
+				},
+				Status: fleetnetv1alpha1.ServiceExportStatus{
+					Conditions: []metav1.Condition{
+						serviceExportValidCondition(memberUserNS, svcName),
+						serviceExportNoConflictCondition(memberUserNS, svcName),
+					},
+				},
+			}
+			if tc.allowedNS != "" {
+				svcExport.Annotations = map[string]string{
+					objectmeta.ServiceExportAnnotationAllowedEndpointSliceNamespaces: tc.allowedNS,
+				}
+			}
+			fakeMemberClient := fake.NewClientBuilder().
+				WithScheme(scheme.Scheme).
+				WithObjects(tc.endpointSlice, svcExport).
+				WithStatusSubresource(tc.endpointSlice, svcExport).
+				Build()
+			fakeHubClient := fake.NewClientBuilder().WithScheme(scheme.Scheme).Build()
+			reconciler := &Reconciler{
+				MemberClient: fakeMemberClient,
+				HubClient:    fakeHubClient,
+				HubNamespace: hubNSForMember,
+			}
+
+			op, err := reconciler.shouldSkipOrUnexportEndpointSlice(ctx, tc.endpointSlice)
+			if err != nil {
+				t.Fatalf("shouldSkipOrUnexportEndpointSlice(%+v), got %v, want no error", tc.endpointSlice, err)
+			}
+			if op != tc.want {
+				t.Fatalf("shouldSkipOrUnexportEndpointSlice(%+v) = %d, want %d", tc.endpointSlice, op, tc.want)
+			}
+		})
+	}
+}
+
+// TestShouldSkipOrUnexportEndpointSlice_ManagedBy tests the shouldSkipOrUnexportEndpointSlice method
+// (EndpointSlices managed by controllers that are or are not allowed).
+func TestShouldSkipOrUnexportEndpointSlice_ManagedBy(t *testing.T) {
+	mirroringManager := "endpointslicemirroring-controller.k8s.io"
+
+	testCases := []struct {
+		name       string
+		managers   []string
+		managedBy  string
+		isExported bool
+		want       skipOrUnexportEndpointSliceOp
+	}{
+		{
+			name:      "should export endpoint slice managed by an allowed controller",
+			managers:  []string{KubeControllerManagerEndpointSliceManager},
+			managedBy: KubeControllerManagerEndpointSliceManager,
+			want:      continueReconcileOp,
+		},
+		{
+			name:      "should skip endpoint slice managed by a controller not allowed",
+			managers:  []string{KubeControllerManagerEndpointSliceManager},
+			managedBy: mirroringManager,
+			want:      shouldSkipEndpointSliceOp,
+		},
+		{
+			name:     "should skip endpoint slice without the managed-by label",
+			managers: []string{KubeControllerManagerEndpointSliceManager},
+			want:     shouldSkipEndpointSliceOp,
+		},
+		{
+			name:       "should unexport endpoint slice managed by a controller no longer allowed",
+			managers:   []string{KubeControllerManagerEndpointSliceManager},
+			managedBy:  mirroringManager,
+			isExported: true,
+			want:       shouldUnexportEndpointSliceOp,
+		},
+		{
+			name:       "should export endpoint slice managed by a controller which has been allowed",
+			managers:   []string{KubeControllerManagerEndpointSliceManager, mirroringManager},
+			managedBy:  mirroringManager,
+			isExported: true,
+			want:       continueReconcileOp,
+		},
+		{
+			name:      "should export endpoint slice managed by any controller when all are allowed",
+			managedBy: mirroringManager,
+			want:      continueReconcileOp,
+		},
+	}
+
+	ctx := context.Background()
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			endpointSlice := &discoveryv1.EndpointSlice{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: memberUserNS,
+					Name:      endpointSliceName,
+					Labels: map[string]string{
+						discoveryv1.LabelServiceName: svcName,
+					},
+				},
+				AddressType: discoveryv1.AddressTypeIPv4,
+			}
+			if tc.managedBy != "" {
+				endpointSlice.Labels[discoveryv1.LabelManagedBy] = tc.managedBy
+			}
+			if tc.isExported {
+				endpointSlice.Annotations = map[string]string{
+					objectmeta.ExportedObjectAnnotationUniqueName: endpointSliceUniqueName,
+				}
+			}
+			svcExport := &fleetnetv1alpha1.ServiceExport{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: memberUserNS,
+					Name:      svcName,
+				},
+				Status: fleetnetv1alpha1.ServiceExportStatus{
+					Conditions: []metav1.Condition{
+						serviceExportValidCondition(memberUserNS, svcName),
+						serviceExportNoConflictCondition(memberUserNS, svcName),
+					},
+				},
+			}
+			fakeMemberClient := fake.NewClientBuilder().
+				WithScheme(scheme.Scheme).
+				WithObjects(endpointSlice, svcExport).
+				WithStatusSubresource(endpointSlice, svcExport).
+				Build()
+			fakeHubClient := fake.NewClientBuilder().WithScheme(scheme.Scheme).Build()
+			reconciler := &Reconciler{
+				MemberClient:                  fakeMemberClient,
+				HubClient:                     fakeHubClient,
+				HubNamespace:                  hubNSForMember,
+				ExportedEndpointSliceManagers: tc.managers,
+			}
+
+			op, err := reconciler.shouldSkipOrUnexportEndpointSlice(ctx, endpointSlice)
+			if err != nil {
+				t.Fatalf("shouldSkipOrUnexportEndpointSlice(%+v), got %v, want no error", endpointSlice, err)
+			}
+			if op != tc.want {
+				t.Fatalf("shouldSkipOrUnexportEndpointSlice(%+v) = %d, want %d", endpointSlice, op, tc.want)
+			}
+		})
+	}
+}
+
+// TestReconcile_EndpointSliceManagerNoLongerAllowed tests the *Reconciler.Reconcile method on an exported
+// EndpointSlice whose managed-by label value is no longer allowed.
+func TestReconcile_EndpointSliceManagerNoLongerAllowed(t *testing.T) {
+	endpointSlice := &discoveryv1.EndpointSlice{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: memberUserNS,
+			Name:      endpointSliceName,
+			Labels: map[string]string{
+				discoveryv1.LabelServiceName: svcName,
+				discoveryv1.LabelManagedBy:   "endpointslicemirroring-controller.k8s.io",
+			},
+			Annotations: map[string]string{
+				objectmeta.ExportedObjectAnnotationUniqueName: endpointSliceUniqueName,
+			},
+			UID: "1",
+		},
+		AddressType: discoveryv1.AddressTypeIPv4,
+	}
+	svcExport := &fleetnetv1alpha1.ServiceExport{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: memberUserNS,
+			Name:      svcName,
+		},
+		Status: fleetnetv1alpha1.ServiceExportStatus{
+			Conditions: []metav1.Condition{
+				serviceExportValidCondition(memberUserNS, svcName),
+				serviceExportNoConflictCondition(memberUserNS, svcName),
+			},
+		},
+	}
+	endpointSliceExport := &fleetnetv1alpha1.EndpointSliceExport{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: hubNSForMember,
+			Name:      endpointSliceUniqueName,
+		},
+		Spec: fleetnetv1alpha1.EndpointSliceExportSpec{
+			EndpointSliceReference: fleetnetv1alpha1.ExportedObjectReference{
+				ClusterID: memberClusterID,
+				Kind:      "EndpointSlice",
+				Namespace: memberUserNS,
+				Name:      endpointSliceName,
+				UID:       "1",
+			},
+		},
+	}
+
+	ctx := context.Background()
+	fakeMemberClient := fake.NewClientBuilder().
+		WithScheme(scheme.Scheme).
+		WithObjects(endpointSlice, svcExport).
+		WithStatusSubresource(endpointSlice, svcExport).
+		Build()
+	fakeHubClient := fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(endpointSliceExport).Build()
+	reconciler := &Reconciler{
+		MemberClusterID:               memberClusterID,
+		MemberClient:                  fakeMemberClient,
+		HubClient:                     fakeHubClient,
+		HubNamespace:                  hubNSForMember,
+		ExportedEndpointSliceManagers: []string{KubeControllerManagerEndpointSliceManager},
+	}
+
+	if _, err := reconciler.Reconcile(ctx, ctrl.Request{NamespacedName: endpointSliceKey}); err != nil {
+		t.Fatalf("Reconcile(%v), got %v, want no error", endpointSliceKey, err)
+	}
+
+	if err := fakeHubClient.Get(ctx, endpointSliceExportKey, &fleetnetv1alpha1.EndpointSliceExport{}); !errors.IsNotFound(err) {
+		t.Fatalf("endpointSliceExport Get(%v), got %v, want not found error", endpointSliceExportKey, err)
+	}
+	updatedEndpointSlice := &discoveryv1.EndpointSlice{}
+	if err := fakeMemberClient.Get(ctx, endpointSliceKey, updatedEndpointSlice); err != nil {
+		t.Fatalf("endpointSlice Get(%v), got %v, want no error", endpointSliceKey, err)
+	}
+	if _, ok := updatedEndpointSlice.Annotations[objectmeta.ExportedObjectAnnotationUniqueName]; ok {
+		t.Fatalf("endpointSlice annotations, got %+v, want no %s annotation", updatedEndpointSlice.Annotations, objectmeta.ExportedObjectAnnotationUniqueName)
+	}
+}
+
+// TestReconcile_ServiceExportDeleted tests that the Reconcile method neither exports an EndpointSlice nor requeues
+// it when the ServiceExport of its owner Service is deleted mid-reconciliation.
+func TestReconcile_ServiceExportDeleted(t *testing.T) {
+	endpointSlice := &discoveryv1.EndpointSlice{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: memberUserNS,
+			Name:      endpointSliceName,
+			Labels: map[string]string{
+				discoveryv1.LabelServiceName: svcName,
+			},
+			Annotations: map[string]string{
+				objectmeta.ExportedObjectAnnotationUniqueName: endpointSliceUniqueName,
+			},
+			UID: "1",
+		},
+		AddressType: discoveryv1.AddressTypeIPv4,
+	}
+	svcExport := &fleetnetv1alpha1.ServiceExport{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: memberUserNS,
+			Name:      svcName,
+		},
+		Status: fleetnetv1alpha1.ServiceExportStatus{
+			Conditions: []metav1.Condition{
+				serviceExportValidCondition(memberUserNS, svcName),
+				serviceExportNoConflictCondition(memberUserNS, svcName),
+			},
+		},
+	}
+
+	ctx := context.Background()
+	// The ServiceExport is found when the EndpointSlice is checked, and deleted right after.
+	svcExportGets := 0
+	fakeMemberClient := fake.NewClientBuilder().
+		WithScheme(scheme.Scheme).
+		WithObjects(endpointSlice, svcExport).
+		WithStatusSubresource(endpointSlice, svcExport).
+		WithInterceptorFuncs(interceptor.Funcs{
+			Get: func(ctx context.Context, c client.WithWatch, key client.ObjectKey, obj client.Object, opts ...client.GetOption) error {
+				if _, ok := obj.(*fleetnetv1alpha1.ServiceExport); ok {
+					svcExportGets++
+					if svcExportGets > 1 {
+						return errors.NewNotFound(fleetnetv1alpha1.GroupVersion.WithResource("serviceexports").GroupResource(), key.Name)
+					}
+				}
+				return c.Get(ctx, key, obj, opts...)
+			},
+		}).
+		Build()
+	fakeHubClient := fake.NewClientBuilder().WithScheme(scheme.Scheme).Build()
+	reconciler := &Reconciler{
+		MemberClusterID: memberClusterID,
+		MemberClient:    fakeMemberClient,
+		HubClient:       fakeHubClient,
+		HubNamespace:    hubNSForMember,
+	}
+
+	res, err := reconciler.Reconcile(ctx, ctrl.Request{NamespacedName: endpointSliceKey})
+	if err != nil {
+		t.Fatalf("Reconcile(%v), got %v, want no error", endpointSliceKey, err)
+	}
+	if !cmp.Equal(res, ctrl.Result{}) {
+		t.Fatalf("Reconcile(%v) = %+v, want %+v", endpointSliceKey, res, ctrl.Result{})
+	}
+	if svcExportGets != 2 {
+		t.Fatalf("service export Get() calls, got %d, want 2", svcExportGets)
+	}
+	if err := fakeHubClient.Get(ctx, endpointSliceExportKey, &fleetnetv1alpha1.EndpointSliceExport{}); !errors.IsNotFound(err) {
+		t.Fatalf("endpointSliceExport Get(%v), got %v, want not found error", endpointSliceExportKey, err)
+	}
+}
+
+// TestReconcile_RepairsMetricsAnnotations tests that the Reconcile method stamps an exported EndpointSlice missing the
+// metrics annotations, e.g. one exported before they were introduced, and that a failure to stamp it does not block
+// the export.
+func TestReconcile_RepairsMetricsAnnotations(t *testing.T) {
+	testCases := []struct {
+		name            string
+		patchErr        error
+		wantAnnotations bool
+	}{
+		{
+			name:            "missing annotations are repaired",
+			wantAnnotations: true,
+		},
+		{
+			name:     "failed repair does not block the export",
+			patchErr: errors.NewServiceUnavailable("member API server is unavailable"),
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			endpointSlice := &discoveryv1.EndpointSlice{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace:  memberUserNS,
+					Name:       endpointSliceName,
+					Generation: endpointSliceGeneration,
+					Labels: map[string]string{
+						discoveryv1.LabelServiceName: svcName,
+					},
+					Annotations: map[string]string{
+						objectmeta.ExportedObjectAnnotationUniqueName: endpointSliceUniqueName,
+					},
+					UID: "1",
+				},
+				AddressType: discoveryv1.AddressTypeIPv4,
+			}
+			svcExport := &fleetnetv1alpha1.ServiceExport{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: memberUserNS,
+					Name:      svcName,
+				},
+				Status: fleetnetv1alpha1.ServiceExportStatus{
+					Conditions: []metav1.Condition{
+						serviceExportValidCondition(memberUserNS, svcName),
+						serviceExportNoConflictCondition(memberUserNS, svcName),
+					},
+				},
+			}
+
+			ctx := context.Background()
+			fakeMemberClient := fake.NewClientBuilder().
+				WithScheme(scheme.Scheme).
+				WithObjects(endpointSlice, svcExport).
+				WithStatusSubresource(endpointSlice, svcExport).
+				WithInterceptorFuncs(interceptor.Funcs{
+					Patch: func(ctx context.Context, c client.WithWatch, obj client.Object, patch client.Patch, opts ...client.PatchOption) error {
+						if _, ok := obj.(*discoveryv1.EndpointSlice); ok && tc.patchErr != nil {
+							return tc.patchErr
+						}
+						return c.Patch(ctx, obj, patch, opts...)
+					},
+				}).
+				Build()
+			// The fake client does not support server-side apply; the applied EndpointSliceExports are recorded only.
+			var applied []string
+			fakeHubClient := fake.NewClientBuilder().
+				WithScheme(scheme.Scheme).
+				WithInterceptorFuncs(interceptor.Funcs{
+					Patch: func(ctx context.Context, c client.WithWatch, obj client.Object, patch client.Patch, opts ...client.PatchOption) error {
+						if patch.Type() != types.ApplyPatchType {
+							return c.Patch(ctx, obj, patch, opts...)
+						}
+						applied = append(applied, obj.GetName())
+						return nil
+					},
+				}).
+				Build()
+			reconciler := &Reconciler{
+				MemberClusterID: memberClusterID,
+				MemberClient:    fakeMemberClient,
+				HubClient:       fakeHubClient,
+				HubNamespace:    hubNSForMember,
+			}
+
+			if _, err := reconciler.Reconcile(ctx, ctrl.Request{NamespacedName: endpointSliceKey}); err != nil {
+				t.Fatalf("Reconcile(%v), got %v, want no error", endpointSliceKey, err)
+			}
+			if diff := cmp.Diff(applied, []string{endpointSliceUniqueName}); diff != "" {
+				t.Errorf("applied endpointSliceExports (-got, +want): %s", diff)
+			}
+
+			updatedEndpointSlice := &discoveryv1.EndpointSlice{}
+			if err := fakeMemberClient.Get(ctx, endpointSliceKey, updatedEndpointSlice); err != nil {
+				t.Fatalf("endpointSlice Get(%v), got %v, want no error", endpointSliceKey, err)
+			}
+			_, hasGeneration := updatedEndpointSlice.Annotations[metrics.MetricsAnnotationLastSeenGeneration]
+			_, hasTimestamp := updatedEndpointSlice.Annotations[metrics.MetricsAnnotationLastSeenTimestamp]
+			if hasGeneration != tc.wantAnnotations || hasTimestamp != tc.wantAnnotations {
+				t.Errorf("endpointSlice annotations, got %+v, want metrics annotations present: %t", updatedEndpointSlice.Annotations, tc.wantAnnotations)
+			}
+			if tc.wantAnnotations {
+				if got, want := updatedEndpointSlice.Annotations[metrics.MetricsAnnotationLastSeenGeneration], fmt.Sprintf("%d", endpointSliceGeneration); got != want {
+					t.Errorf("endpointSlice last seen generation, got %s, want %s", got, want)
+				}
+			}
+		})
+	}
+}
+
+// TestParseExportedEndpointSliceManagers tests the ParseExportedEndpointSliceManagers function.
+func TestParseExportedEndpointSliceManagers(t *testing.T) {
+	testCases := []struct {
+		name string
+		s    string
+		want []string
+	}{
+		{
+			name: "kube-controller-manager only",
+			s:    KubeControllerManagerEndpointSliceManager,
+			want: []string{KubeControllerManagerEndpointSliceManager},
+		},
+		{
+			name: "multiple controllers with whitespace",
+			s:    " endpointslice-controller.k8s.io, custom-controller ,",
+			want: []string{KubeControllerManagerEndpointSliceManager, "custom-controller"},
+		},
+		{
+			name: "all controllers",
+			s:    "all",
+		},
+		{
+			name: "all controllers among others",
+			s:    "custom-controller,all",
+		},
+		{
+			name: "empty",
+			s:    "",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := ParseExportedEndpointSliceManagers(tc.s); !cmp.Equal(got, tc.want) {
+				t.Errorf("ParseExportedEndpointSliceManagers(%q) = %v, want %v", tc.s, got, tc.want)
+			}
+		})
+	}
+}
+
+// TestOwnerServiceNamespace tests the ownerServiceNamespace function.
+func TestOwnerServiceNamespace(t *testing.T) {
+	testCases := []struct {
+		name        string
+		annotations map[string]string
+		want        string
+	}{
+		{
+			name: "no owner service namespace annotation",
+			want: memberUserNS,
+		},
+		{
+			name: "owner service namespace annotation",
+			annotations: map[string]string{
+				objectmeta.EndpointSliceAnnotationOwnerServiceNamespace: " other ",
+			},
+			want: "other",
+		},
+		{
+			name: "empty owner service namespace annotation",
+			annotations: map[string]string{
+				objectmeta.EndpointSliceAnnotationOwnerServiceNamespace: "",
+			},
+			want: memberUserNS,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			endpointSlice := &discoveryv1.EndpointSlice{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace:   memberUserNS,
+					Name:        endpointSliceName,
+					Annotations: tc.annotations,
+				},
+			}
+			if got := ownerServiceNamespace(endpointSlice); got != tc.want {
+				t.Fatalf("ownerServiceNamespace(%+v) = %q, want %q", endpointSlice, got, tc.want)
+			}
+		})
+	}
+}
+
+// TestIsEndpointSliceAllowedByServiceExport tests the isEndpointSliceAllowedByServiceExport function.
+func TestIsEndpointSliceAllowedByServiceExport(t *testing.T) {
+	testCases := []struct {
+		name            string
+		allowedNS       *string
+		endpointSliceNS string
+		want            bool
+	}{
+		{
+			name:            "same namespace",
+			endpointSliceNS: memberUserNS,
+			want:            true,
+		},
+		{
+			name:            "other namespace, no allow annotation",
+			endpointSliceNS: "mirror",
+			want:            false,
+		},
+		{
+			name:            "other namespace, allowed",
+			allowedNS:       ptr.To("foo, mirror ,,bar"),
+			endpointSliceNS: "mirror",
+			want:            true,
+		},
+		{
+			name:            "other namespace, not in the allowed list",
+			allowedNS:       ptr.To("foo,bar"),
+			endpointSliceNS: "mirror",
+			want:            false,
+		},
+		{
+			name:            "other namespace, empty allow annotation",
+			allowedNS:       ptr.To(""),
+			endpointSliceNS: "mirror",
+			want:            false,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			svcExport := &fleetnetv1alpha1.ServiceExport{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: memberUserNS,
+					Name:      svcName,
+				},
+			}
+			if tc.allowedNS != nil {
+				svcExport.Annotations = map[string]string{
+					objectmeta.ServiceExportAnnotationAllowedEndpointSliceNamespaces: *tc.allowedNS,
+				}
+			}
+			endpointSlice := &discoveryv1.EndpointSlice{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: tc.endpointSliceNS,
+					Name:      endpointSliceName,
+				},
+			}
+			if got := isEndpointSliceAllowedByServiceExport(svcExport, endpointSlice); got != tc.want {
+				t.Fatalf("isEndpointSliceAllowedByServiceExport(%+v, %+v) = %t, want %t", svcExport, endpointSlice, got, tc.want)
+			}
+		})
+	}
+}
+
+// TestIsServiceExportValidWithNoConflict tests the isServiceExportValidWithNoConflict function.
+func TestIsServiceExportValidWithNoConflict(t *testing.T) {
+	deletionTimestamp := metav1.Now()
+
+	testCases := []struct {
+		name      string
+		svcExport *fleetnetv1alpha1.ServiceExport
+		want      bool
+	}{
+		{
+			name: "svc export is valid with no conflicts",
+			svcExport: &fleetnetv1alpha1.ServiceExport{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: memberUserNS,
+					Name:      svcName,
+				},
+				Status: fleetnetv1alpha1.ServiceExportStatus{
+					Conditions: []metav1.Condition{
+						serviceExportValidCondition(memberUserNS, svcName),
+						serviceExportNoConflictCondition(memberUserNS, svcName),
+					},
+				},
+			},
+			want: true,
+		},
+		{
+			name: "svc export is invalid",
+			svcExport: &fleetnetv1alpha1.ServiceExport{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: memberUserNS,
+					Name:      svcName,
+				},
+				Status: fleetnetv1alpha1.ServiceExportStatus{
+					Conditions: []metav1.Condition{
+						serviceExportInvalidNotFoundCondition(memberUserNS, svcName),
+					},
+				},
+			},
+			want: false,
+		},
+		{
+			name: "svc export is valid but with conflicts",
+			svcExport: &fleetnetv1alpha1.ServiceExport{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: memberUserNS,
+					Name:      svcName,
+				},
+				Status: fleetnetv1alpha1.ServiceExportStatus{
+					Conditions: []metav1.Condition{
+						serviceExportValidCondition(memberUserNS, svcName),
+						serviceExportConflictedCondition(memberUserNS, svcName),
+					},
+				},
+			},
+			want: false,
+		},
+		{
+			name: "svc export has no conditions set yet",
+			svcExport: &fleetnetv1alpha1.ServiceExport{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: memberUserNS,
+					Name:      svcName,
+				},
+			},
+			want: false,
+		},
+		{
+			name: "svc export is valid with no conflicts, but has been deleted",
+			svcExport: &fleetnetv1alpha1.ServiceExport{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace:         memberUserNS,
+					Name:              svcName,
+					DeletionTimestamp: &deletionTimestamp,
+				},
+				Status: fleetnetv1alpha1.ServiceExportStatus{
+					Conditions: []metav1.Condition{
+						serviceExportValidCondition(memberUserNS, svcName),
+						serviceExportNoConflictCondition(memberUserNS, svcName),
 					},
 				},
 			},
@@ -1275,3 +2235,598 @@ func TestCollectAndVerifyLastSeenGenerationAndTimestamp(t *testing.T) {
 		})
 	}
 }
+
+// ipv4EndpointSliceWithAddresses returns an IPv4 EndpointSlice of the Service with one ready endpoint per address.
+func ipv4EndpointSliceWithAddresses(name string, addresses ...string) *discoveryv1.EndpointSlice {
+	endpointSlice := &discoveryv1.EndpointSlice{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: memberUserNS,
+			Name:      name,
+			Labels: map[string]string{
+				discoveryv1.LabelServiceName: svcName,
+			},
+		},
+		AddressType: discoveryv1.AddressTypeIPv4,
+	}
+	for _, address := range addresses {
+		endpointSlice.Endpoints = append(endpointSlice.Endpoints, discoveryv1.Endpoint{
+			Addresses: []string{address},
+		})
+	}
+	return endpointSlice
+}
+
+// TestSelectExportedEndpoints tests the selectExportedEndpoints function.
+func TestSelectExportedEndpoints(t *testing.T) {
+	sliceA := ipv4EndpointSliceWithAddresses("app-a", "10.0.0.1", "10.0.0.2", "10.0.0.3", "10.0.0.4")
+	sliceB := ipv4EndpointSliceWithAddresses("app-b", "10.0.1.1", "10.0.1.2", "10.0.1.3")
+	notReady := false
+	sliceB.Endpoints = append(sliceB.Endpoints, discoveryv1.Endpoint{
+		Addresses:  []string{"10.0.1.4"},
+		Conditions: discoveryv1.EndpointConditions{Ready: &notReady},
+	})
+
+	t.Run("all ready endpoints are selected under the limit", func(t *testing.T) {
+		selected, total := selectExportedEndpoints([]discoveryv1.EndpointSlice{*sliceA, *sliceB}, 10, false)
+		if total != 7 {
+			t.Fatalf("selectExportedEndpoints() total = %d, want %d", total, 7)
+		}
+		if len(selected) != 7 {
+			t.Fatalf("selectExportedEndpoints() selected %d endpoints, want %d", len(selected), 7)
+		}
+		if selected[exportedEndpointKey{endpointSlice: types.NamespacedName{Namespace: memberUserNS, Name: "app-b"}, address: "10.0.1.4"}] {
+			t.Fatalf("selectExportedEndpoints() selected a not ready endpoint")
+		}
+	})
+
+	t.Run("selection is stable across reconciles", func(t *testing.T) {
+		want, total := selectExportedEndpoints([]discoveryv1.EndpointSlice{*sliceA, *sliceB}, 4, false)
+		if total != 7 || len(want) != 4 {
+			t.Fatalf("selectExportedEndpoints() = %d selected of %d, want %d of %d", len(want), total, 4, 7)
+		}
+
+		// Reverse the order of the EndpointSlices and of the endpoints in them, which must not affect the selection.
+		reversedA, reversedB := sliceA.DeepCopy(), sliceB.DeepCopy()
+		for _, es := range []*discoveryv1.EndpointSlice{reversedA, reversedB} {
+			for i, j := 0, len(es.Endpoints)-1; i < j; i, j = i+1, j-1 {
+				es.Endpoints[i], es.Endpoints[j] = es.Endpoints[j], es.Endpoints[i]
+			}
+		}
+		for i := 0; i < 3; i++ {
+			got, _ := selectExportedEndpoints([]discoveryv1.EndpointSlice{*reversedB, *reversedA}, 4, false)
+			if diff := cmp.Diff(got, want, cmp.AllowUnexported(exportedEndpointKey{})); diff != "" {
+				t.Fatalf("selectExportedEndpoints() (-got, +want): %s", diff)
+			}
+		}
+	})
+
+	t.Run("a new endpoint displaces at most one selected endpoint", func(t *testing.T) {
+		before, _ := selectExportedEndpoints([]discoveryv1.EndpointSlice{*sliceA, *sliceB}, 4, false)
+		grownB := ipv4EndpointSliceWithAddresses("app-b", "10.0.1.1", "10.0.1.2", "10.0.1.3", "10.0.1.5")
+		after, total := selectExportedEndpoints([]discoveryv1.EndpointSlice{*sliceA, *grownB}, 4, false)
+		if total != 8 || len(after) != 4 {
+			t.Fatalf("selectExportedEndpoints() = %d selected of %d, want %d of %d", len(after), total, 4, 8)
+		}
+		kept := 0
+		for key := range before {
+			if after[key] {
+				kept++
+			}
+		}
+		if kept < 3 {
+			t.Fatalf("selectExportedEndpoints() kept %d of the previously selected endpoints, want at least %d", kept, 3)
+		}
+	})
+}
+
+// TestSelectEndpointsToExport tests the selectEndpointsToExport method.
+func TestSelectEndpointsToExport(t *testing.T) {
+	sliceA := ipv4EndpointSliceWithAddresses("app-a", "10.0.0.1", "10.0.0.2", "10.0.0.3")
+	sliceB := ipv4EndpointSliceWithAddresses("app-b", "10.0.1.1", "10.0.1.2", "10.0.1.3")
+	// An FQDN EndpointSlice is never exported and does not count.
+	fqdnSlice := ipv4EndpointSliceWithAddresses("app-fqdn", "app-1.example.com", "app-2.example.com")
+	fqdnSlice.AddressType = discoveryv1.AddressTypeFQDN
+	svcExport := &fleetnetv1alpha1.ServiceExport{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: memberUserNS,
+			Name:      svcName,
+		},
+	}
+
+	testCases := []struct {
+		name         string
+		maxEndpoints int
+		wantTotal    int
+		wantExported int
+	}{
+		{
+			name:         "no limit",
+			maxEndpoints: 0,
+			wantTotal:    0,
+			wantExported: 6,
+		},
+		{
+			name:         "under the limit",
+			maxEndpoints: 6,
+			wantTotal:    6,
+			wantExported: 6,
+		},
+		{
+			name:         "over the limit",
+			maxEndpoints: 4,
+			wantTotal:    6,
+			wantExported: 4,
+		},
+	}
+
+	ctx := context.Background()
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			fakeMemberClient := fake.NewClientBuilder().
+				WithScheme(scheme.Scheme).
+				WithObjects(sliceA, sliceB, fqdnSlice, svcExport).
+				Build()
+			reconciler := &Reconciler{
+				MemberClient:                   fakeMemberClient,
+				MaxExportedEndpointsPerService: tc.maxEndpoints,
+			}
+
+			exported := 0
+			for _, es := range []*discoveryv1.EndpointSlice{sliceA, sliceB} {
+				endpoints, total, err := reconciler.selectEndpointsToExport(ctx, svcExport, es)
+				if err != nil {
+					t.Fatalf("selectEndpointsToExport(%s), got %v, want no error", es.Name, err)
+				}
+				if total != tc.wantTotal {
+					t.Fatalf("selectEndpointsToExport(%s) total = %d, want %d", es.Name, total, tc.wantTotal)
+				}
+				// Repeated selections must return the same endpoints.
+				again, _, err := reconciler.selectEndpointsToExport(ctx, svcExport, es)
+				if err != nil {
+					t.Fatalf("selectEndpointsToExport(%s), got %v, want no error", es.Name, err)
+				}
+				if diff := cmp.Diff(again, endpoints); diff != "" {
+					t.Fatalf("selectEndpointsToExport(%s) is not stable (-got, +want): %s", es.Name, diff)
+				}
+				exported += len(endpoints)
+			}
+			if exported != tc.wantExported {
+				t.Fatalf("selectEndpointsToExport() exported %d endpoints in total, want %d", exported, tc.wantExported)
+			}
+		})
+	}
+}
+
+// TestUpdateEndpointsTruncatedCondition tests the updateEndpointsTruncatedCondition method through the lifecycle
+// of the condition; the ServiceExport is updated concurrently at every step, as the ServiceExport controller does.
+func TestUpdateEndpointsTruncatedCondition(t *testing.T) {
+	ctx := context.Background()
+	validCond := metav1.Condition{
+		Type:    string(fleetnetv1alpha1.ServiceExportValid),
+		Status:  metav1.ConditionTrue,
+		Reason:  "ServiceIsValid",
+		Message: "service work/app is valid for export",
+	}
+	svcExport := &fleetnetv1alpha1.ServiceExport{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: memberUserNS,
+			Name:      svcName,
+		},
+		Status: fleetnetv1alpha1.ServiceExportStatus{
+			Conditions: []metav1.Condition{validCond},
+		},
+	}
+	fakeMemberClient := fake.NewClientBuilder().
+		WithScheme(scheme.Scheme).
+		WithObjects(svcExport).
+		WithStatusSubresource(svcExport).
+		Build()
+	recorder := record.NewFakeRecorder(10)
+	reconciler := &Reconciler{
+		MemberClient:                   fakeMemberClient,
+		MaxExportedEndpointsPerService: 4,
+		Recorder:                       recorder,
+	}
+
+	steps := []struct {
+		name         string
+		maxEndpoints int
+		total        int
+		wantCond     *metav1.Condition
+		wantEvent    bool
+	}{
+		{
+			name:         "never truncated",
+			maxEndpoints: 4,
+			total:        3,
+		},
+		{
+			name:         "truncated",
+			maxEndpoints: 4,
+			total:        6,
+			wantCond: &metav1.Condition{
+				Type:    string(fleetnetv1alpha1.ServiceExportEndpointsTruncated),
+				Status:  metav1.ConditionTrue,
+				Reason:  endpointsTruncatedCondReason,
+				Message: "4 of 6 ready endpoints of service work/app are exported; the maximum per service is 4",
+			},
+			wantEvent: true,
+		},
+		{
+			name:         "still truncated with more endpoints",
+			maxEndpoints: 4,
+			total:        8,
+			wantCond: &metav1.Condition{
+				Type:    string(fleetnetv1alpha1.ServiceExportEndpointsTruncated),
+				Status:  metav1.ConditionTrue,
+				Reason:  endpointsTruncatedCondReason,
+				Message: "4 of 8 ready endpoints of service work/app are exported; the maximum per service is 4",
+			},
+		},
+		{
+			name:         "no longer truncated",
+			maxEndpoints: 4,
+			total:        4,
+			wantCond: &metav1.Condition{
+				Type:    string(fleetnetv1alpha1.ServiceExportEndpointsTruncated),
+				Status:  metav1.ConditionFalse,
+				Reason:  endpointsNotTruncatedCondReason,
+				Message: "all 4 ready endpoints of service work/app are exported; the maximum per service is 4",
+			},
+		},
+		{
+			name:         "truncated again",
+			maxEndpoints: 4,
+			total:        5,
+			wantCond: &metav1.Condition{
+				Type:    string(fleetnetv1alpha1.ServiceExportEndpointsTruncated),
+				Status:  metav1.ConditionTrue,
+				Reason:  endpointsTruncatedCondReason,
+				Message: "4 of 5 ready endpoints of service work/app are exported; the maximum per service is 4",
+			},
+			wantEvent: true,
+		},
+		{
+			name:         "limit removed",
+			maxEndpoints: 0,
+			total:        0,
+		},
+	}
+
+	for _, step := range steps {
+		reconciler.MaxExportedEndpointsPerService = step.maxEndpoints
+		current := &fleetnetv1alpha1.ServiceExport{}
+		if err := fakeMemberClient.Get(ctx, types.NamespacedName{Namespace: memberUserNS, Name: svcName}, current); err != nil {
+			t.Fatalf("%s: serviceExport Get(), got %v, want no error", step.name, err)
+		}
+		// Update the ServiceExport behind the back of the reconciler, so that its copy becomes stale.
+		concurrent := current.DeepCopy()
+		concurrent.Status.Conditions[0].Message = fmt.Sprintf("%s: %s", step.name, validCond.Message)
+		if err := fakeMemberClient.Status().Update(ctx, concurrent); err != nil {
+			t.Fatalf("%s: serviceExport Status().Update(), got %v, want no error", step.name, err)
+		}
+		if err := reconciler.updateEndpointsTruncatedCondition(ctx, current, step.total); err != nil {
+			t.Fatalf("%s: updateEndpointsTruncatedCondition(), got %v, want no error", step.name, err)
+		}
+
+		updated := &fleetnetv1alpha1.ServiceExport{}
+		if err := fakeMemberClient.Get(ctx, types.NamespacedName{Namespace: memberUserNS, Name: svcName}, updated); err != nil {
+			t.Fatalf("%s: serviceExport Get(), got %v, want no error", step.name, err)
+		}
+		gotCond := meta.FindStatusCondition(updated.Status.Conditions, string(fleetnetv1alpha1.ServiceExportEndpointsTruncated))
+		if diff := cmp.Diff(gotCond, step.wantCond, cmpopts.IgnoreFields(metav1.Condition{}, "LastTransitionTime", "ObservedGeneration")); diff != "" {
+			t.Fatalf("%s: endpoints truncated condition (-got, +want): %s", step.name, diff)
+		}
+		gotValidCond := meta.FindStatusCondition(updated.Status.Conditions, string(fleetnetv1alpha1.ServiceExportValid))
+		if gotValidCond == nil || gotValidCond.Message != concurrent.Status.Conditions[0].Message {
+			t.Fatalf("%s: valid condition got %+v, want the concurrently updated condition %+v", step.name, gotValidCond, concurrent.Status.Conditions[0])
+		}
+
+		gotEvent := false
+		select {
+		case event := <-recorder.Events:
+			gotEvent = strings.HasPrefix(event, corev1.EventTypeWarning+" "+endpointsTruncatedCondReason)
+		default:
+		}
+		if gotEvent != step.wantEvent {
+			t.Fatalf("%s: warning event emitted = %t, want %t", step.name, gotEvent, step.wantEvent)
+		}
+	}
+}
+
+// podWithIPs returns a pod with the given pod and host IPs.
+func podWithIPs(name, podIP, hostIP string, hostNetwork bool) *corev1.Pod {
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: memberUserNS,
+			Name:      name,
+		},
+		Spec: corev1.PodSpec{
+			HostNetwork: hostNetwork,
+		},
+		Status: corev1.PodStatus{
+			PodIP:  podIP,
+			HostIP: hostIP,
+		},
+	}
+}
+
+// endpointSliceWithPods returns an IPv4 EndpointSlice whose endpoints are backed by the given pods, keyed by address;
+// an empty pod name leaves the endpoint without a target reference.
+func endpointSliceWithPods(name string, pods map[string]string) *discoveryv1.EndpointSlice {
+	addresses := make([]string, 0, len(pods))
+	for address := range pods {
+		addresses = append(addresses, address)
+	}
+	sort.Strings(addresses)
+	endpointSlice := ipv4EndpointSliceWithAddresses(name, addresses...)
+	for i := range endpointSlice.Endpoints {
+		if podName := pods[endpointSlice.Endpoints[i].Addresses[0]]; podName != "" {
+			endpointSlice.Endpoints[i].TargetRef = &corev1.ObjectReference{Kind: "Pod", Namespace: memberUserNS, Name: podName}
+		}
+	}
+	return endpointSlice
+}
+
+// TestExcludeHostNetworkEndpoints tests the excludeHostNetworkEndpoints method.
+func TestExcludeHostNetworkEndpoints(t *testing.T) {
+	// A mixed EndpointSlice, with endpoints backed by regular pods, pods in the host network, a pod which is not found,
+	// and no pod at all.
+	endpointSlice := endpointSliceWithPods(endpointSliceName, map[string]string{
+		"10.0.0.1": "app-1",
+		"10.0.0.2": "app-2",
+		"10.1.0.1": "app-hostnetwork",
+		"10.1.0.2": "app-same-ip",
+		"10.0.0.3": "app-deleted",
+		"10.0.0.4": "",
+	})
+	pods := []*corev1.Pod{
+		podWithIPs("app-1", "10.0.0.1", "10.1.0.1", false),
+		podWithIPs("app-2", "10.0.0.2", "10.1.0.2", false),
+		podWithIPs("app-hostnetwork", "10.1.0.1", "10.1.0.1", true),
+		// A pod whose IP equals the IP of its node, e.g. with a host network spec not exposed to the API.
+		podWithIPs("app-same-ip", "10.1.0.2", "10.1.0.2", false),
+	}
+
+	testCases := []struct {
+		name          string
+		annotations   map[string]string
+		wantAddresses []string
+		wantExcluded  int
+	}{
+		{
+			name:          "host network endpoints are excluded by default",
+			wantAddresses: []string{"10.0.0.1", "10.0.0.2", "10.0.0.3", "10.0.0.4"},
+			wantExcluded:  2,
+		},
+		{
+			name: "host network endpoints are excluded with an opt-in other than true",
+			annotations: map[string]string{
+				objectmeta.ServiceExportAnnotationAllowHostNetworkEndpoints: "yes",
+			},
+			wantAddresses: []string{"10.0.0.1", "10.0.0.2", "10.0.0.3", "10.0.0.4"},
+			wantExcluded:  2,
+		},
+		{
+			name: "host network endpoints are exported with the opt-in",
+			annotations: map[string]string{
+				objectmeta.ServiceExportAnnotationAllowHostNetworkEndpoints: "true",
+			},
+			wantAddresses: []string{"10.0.0.1", "10.0.0.2", "10.0.0.3", "10.0.0.4", "10.1.0.1", "10.1.0.2"},
+		},
+	}
+
+	ctx := context.Background()
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			svcExport := &fleetnetv1alpha1.ServiceExport{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace:   memberUserNS,
+					Name:        svcName,
+					Annotations: tc.annotations,
+				},
+			}
+			builder := fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(endpointSlice, svcExport)
+			for _, pod := range pods {
+				builder = builder.WithObjects(pod)
+			}
+			reconciler := &Reconciler{MemberClient: builder.Build()}
+
+			endpoints, excluded, err := reconciler.excludeHostNetworkEndpoints(ctx, svcExport, endpointSlice, extractEndpointsFromEndpointSlice(endpointSlice, false))
+			if err != nil {
+				t.Fatalf("excludeHostNetworkEndpoints(), got %v, want no error", err)
+			}
+			if excluded != tc.wantExcluded {
+				t.Errorf("excludeHostNetworkEndpoints() excluded = %d, want %d", excluded, tc.wantExcluded)
+			}
+			gotAddresses := []string{}
+			for _, endpoint := range endpoints {
+				gotAddresses = append(gotAddresses, endpoint.Addresses...)
+			}
+			sort.Strings(gotAddresses)
+			if diff := cmp.Diff(tc.wantAddresses, gotAddresses); diff != "" {
+				t.Errorf("excludeHostNetworkEndpoints() addresses mismatch (-want, +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+// TestUpdateHostNetworkEndpointsExcludedCondition tests the updateHostNetworkEndpointsExcludedCondition method through
+// the lifecycle of the condition.
+func TestUpdateHostNetworkEndpointsExcludedCondition(t *testing.T) {
+	ctx := context.Background()
+	svcExportKey := types.NamespacedName{Namespace: memberUserNS, Name: svcName}
+	svcExport := &fleetnetv1alpha1.ServiceExport{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: memberUserNS,
+			Name:      svcName,
+		},
+		Status: fleetnetv1alpha1.ServiceExportStatus{
+			Conditions: []metav1.Condition{
+				serviceExportValidCondition(memberUserNS, svcName),
+				serviceExportNoConflictCondition(memberUserNS, svcName),
+			},
+		},
+	}
+	// Another EndpointSlice of the Service with an endpoint backed by a pod in the host network.
+	otherEndpointSlice := endpointSliceWithPods("app-other", map[string]string{"10.1.0.1": "app-hostnetwork"})
+	fakeMemberClient := fake.NewClientBuilder().
+		WithScheme(scheme.Scheme).
+		WithObjects(svcExport, otherEndpointSlice, podWithIPs("app-hostnetwork", "10.1.0.1", "10.1.0.1", true)).
+		WithStatusSubresource(svcExport).
+		Build()
+	recorder := record.NewFakeRecorder(10)
+	reconciler := &Reconciler{
+		MemberClient: fakeMemberClient,
+		Recorder:     recorder,
+	}
+
+	excludedCond := &metav1.Condition{
+		Type:    string(fleetnetv1alpha1.ServiceExportHostNetworkEndpointsExcluded),
+		Status:  metav1.ConditionTrue,
+		Reason:  hostNetworkEndpointsExcludedCondReason,
+		Message: `endpoints of service work/app backed by pods in the host network are not exported; annotate the service export with networking.fleet.azure.com/allow-hostnetwork-endpoints: "true" to export them`,
+	}
+	steps := []struct {
+		name      string
+		excluded  int
+		setup     func(svcExport *fleetnetv1alpha1.ServiceExport) error
+		wantCond  *metav1.Condition
+		wantEvent bool
+	}{
+		{
+			name: "never excluded",
+		},
+		{
+			name:      "excluded",
+			excluded:  2,
+			wantCond:  excludedCond,
+			wantEvent: true,
+		},
+		{
+			name:     "still excluded from the endpoint slice",
+			excluded: 1,
+			wantCond: excludedCond,
+		},
+		{
+			name:     "still excluded from another endpoint slice",
+			wantCond: excludedCond,
+		},
+		{
+			name: "no longer excluded",
+			setup: func(_ *fleetnetv1alpha1.ServiceExport) error {
+				return fakeMemberClient.Delete(ctx, otherEndpointSlice)
+			},
+			wantCond: &metav1.Condition{
+				Type:    string(fleetnetv1alpha1.ServiceExportHostNetworkEndpointsExcluded),
+				Status:  metav1.ConditionFalse,
+				Reason:  noHostNetworkEndpointsExcludedCondReason,
+				Message: "no endpoint of service work/app is backed by pods in the host network",
+			},
+		},
+		{
+			name:      "excluded again",
+			excluded:  1,
+			wantCond:  excludedCond,
+			wantEvent: true,
+		},
+		{
+			name: "opted in",
+			setup: func(svcExport *fleetnetv1alpha1.ServiceExport) error {
+				svcExport.Annotations = map[string]string{objectmeta.ServiceExportAnnotationAllowHostNetworkEndpoints: "true"}
+				return fakeMemberClient.Update(ctx, svcExport)
+			},
+		},
+	}
+
+	for _, step := range steps {
+		current := &fleetnetv1alpha1.ServiceExport{}
+		if err := fakeMemberClient.Get(ctx, svcExportKey, current); err != nil {
+			t.Fatalf("%s: serviceExport Get(), got %v, want no error", step.name, err)
+		}
+		if step.setup != nil {
+			if err := step.setup(current); err != nil {
+				t.Fatalf("%s: setup, got %v, want no error", step.name, err)
+			}
+		}
+		if err := reconciler.updateHostNetworkEndpointsExcludedCondition(ctx, current, step.excluded); err != nil {
+			t.Fatalf("%s: updateHostNetworkEndpointsExcludedCondition(), got %v, want no error", step.name, err)
+		}
+
+		updated := &fleetnetv1alpha1.ServiceExport{}
+		if err := fakeMemberClient.Get(ctx, svcExportKey, updated); err != nil {
+			t.Fatalf("%s: serviceExport Get(), got %v, want no error", step.name, err)
+		}
+		gotCond := meta.FindStatusCondition(updated.Status.Conditions, string(fleetnetv1alpha1.ServiceExportHostNetworkEndpointsExcluded))
+		if diff := cmp.Diff(gotCond, step.wantCond, cmpopts.IgnoreFields(metav1.Condition{}, "LastTransitionTime", "ObservedGeneration")); diff != "" {
+			t.Fatalf("%s: host network endpoints excluded condition (-got, +want): %s", step.name, diff)
+		}
+
+		gotEvent := false
+		select {
+		case event := <-recorder.Events:
+			gotEvent = strings.HasPrefix(event, corev1.EventTypeWarning+" "+hostNetworkEndpointsExcludedCondReason)
+		default:
+		}
+		if gotEvent != step.wantEvent {
+			t.Fatalf("%s: warning event emitted = %t, want %t", step.name, gotEvent, step.wantEvent)
+		}
+	}
+}
+
+// TestServiceExportEventFilter tests the serviceExportEventFilter function.
+func TestServiceExportEventFilter(t *testing.T) {
+	svcExportWith := func(resourceVersion string, annotations map[string]string) *fleetnetv1alpha1.ServiceExport {
+		return &fleetnetv1alpha1.ServiceExport{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace:       memberUserNS,
+				Name:            svcName,
+				ResourceVersion: resourceVersion,
+				Annotations:     annotations,
+			},
+		}
+	}
+	weight := func(w string) map[string]string {
+		return map[string]string{objectmeta.ServiceExportAnnotationWeight: w}
+	}
+	testCases := []struct {
+		name   string
+		oldObj *fleetnetv1alpha1.ServiceExport
+		newObj *fleetnetv1alpha1.ServiceExport
+		want   bool
+	}{
+		{
+			name:   "weight changed",
+			oldObj: svcExportWith("1", weight("10")),
+			newObj: svcExportWith("2", weight("20")),
+		},
+		{
+			name:   "weight added",
+			oldObj: svcExportWith("1", nil),
+			newObj: svcExportWith("2", weight("20")),
+		},
+		{
+			name:   "weight and other annotation changed",
+			oldObj: svcExportWith("1", weight("10")),
+			newObj: svcExportWith("2", map[string]string{
+				objectmeta.ServiceExportAnnotationWeight:                         "20",
+				objectmeta.ServiceExportAnnotationAllowedEndpointSliceNamespaces: "other",
+			}),
+			want: true,
+		},
+		{
+			name:   "resource version changed only",
+			oldObj: svcExportWith("1", weight("10")),
+			newObj: svcExportWith("2", weight("10")),
+			want:   true,
+		},
+	}
+	filter := serviceExportEventFilter()
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := filter.Update(event.UpdateEvent{ObjectOld: tc.oldObj, ObjectNew: tc.newObj}); got != tc.want {
+				t.Errorf("Update() = %t, want %t", got, tc.want)
+			}
+		})
+	}
+}
@@ -22,6 +22,7 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/log/zap"
 
 	fleetnetv1alpha1 "go.goms.io/fleet-networking/api/v1alpha1"
+	"go.goms.io/fleet-networking/pkg/common/hubconfig"
 )
 
 var (
@@ -43,12 +44,21 @@ func setUpResources() {
 	}
 	Expect(memberClient.Create(ctx, &memberNS)).Should(Succeed())
 
-	hubNS := corev1.Namespace{
+	memberMirrorNS := corev1.Namespace{
 		ObjectMeta: metav1.ObjectMeta{
-			Name: hubNSForMember,
+			Name: memberMirrorUserNS,
 		},
 	}
-	Expect(hubClient.Create(ctx, &hubNS)).Should(Succeed())
+	Expect(memberClient.Create(ctx, &memberMirrorNS)).Should(Succeed())
+
+	for _, ns := range hubconfig.ExportNamespaces(hubNSForMember, hubExportShards) {
+		hubNS := corev1.Namespace{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: ns,
+			},
+		}
+		Expect(hubClient.Create(ctx, &hubNS)).Should(Succeed())
+	}
 }
 
 func TestAPIs(t *testing.T) {
@@ -104,6 +114,9 @@ var _ = BeforeSuite(func() {
 		MemberClient:    memberClient,
 		HubClient:       hubClient,
 		HubNamespace:    hubNSForMember,
+		HubExportShards: hubExportShards,
+		// The terminating endpoints are propagated, so that their export can be tested.
+		PropagateTerminatingEndpoints: true,
 	}).SetupWithManager(ctx, ctrlMgr)
 	Expect(err).NotTo(HaveOccurred())
 
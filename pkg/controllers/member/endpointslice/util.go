@@ -6,18 +6,62 @@ Licensed under the MIT license.
 package endpointslice
 
 import (
+	"fmt"
+	"hash/fnv"
+	"sort"
+	"strconv"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
 	discoveryv1 "k8s.io/api/discovery/v1"
 	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/utils/ptr"
 
 	fleetnetv1alpha1 "go.goms.io/fleet-networking/api/v1alpha1"
+	"go.goms.io/fleet-networking/pkg/common/metrics"
+	"go.goms.io/fleet-networking/pkg/common/objectmeta"
 )
 
 // isEndpointSlicePermanentlyUnexportable returns if an EndpointSlice is permanently unexportable.
 func isEndpointSlicePermanentlyUnexportable(endpointSlice *discoveryv1.EndpointSlice) bool {
-	// At this moment only IPv4 endpointslices can be exported; note that AddressType is an immutable field.
-	return endpointSlice.AddressType != discoveryv1.AddressTypeIPv4
+	// Only IPv4 and IPv6 endpointslices can be exported, the latter for the IPv6 and dual-stack Services; note that
+	// AddressType is an immutable field.
+	return endpointSlice.AddressType != discoveryv1.AddressTypeIPv4 && endpointSlice.AddressType != discoveryv1.AddressTypeIPv6
+}
+
+// ParseExportedEndpointSliceManagers parses the comma-separated values of the managed-by label of the EndpointSlices
+// to export; AllEndpointSliceManagers, or an empty string, means the EndpointSlices of all controllers are exported,
+// for which nil is returned.
+func ParseExportedEndpointSliceManagers(s string) []string {
+	var managers []string
+	for _, m := range strings.Split(s, ",") {
+		m = strings.TrimSpace(m)
+		if m == AllEndpointSliceManagers {
+			return nil
+		}
+		if m != "" {
+			managers = append(managers, m)
+		}
+	}
+	return managers
+}
+
+// isEndpointSliceManagerAllowed returns if an EndpointSlice is managed by one of the allowed controllers, as told by
+// its managed-by label; all controllers are allowed if none is specified.
+func isEndpointSliceManagerAllowed(endpointSlice *discoveryv1.EndpointSlice, managers []string) bool {
+	if len(managers) == 0 {
+		return true
+	}
+	managedBy := endpointSlice.Labels[discoveryv1.LabelManagedBy]
+	for _, m := range managers {
+		if m == managedBy {
+			return true
+		}
+	}
+	return false
 }
 
 // isServiceExportValidWithNoConflict returns if a ServiceExport
@@ -32,6 +76,43 @@ func isServiceExportValidWithNoConflict(svcExport *fleetnetv1alpha1.ServiceExpor
 	return (isValid && hasNoConflict && svcExport.DeletionTimestamp == nil)
 }
 
+// ownerServiceNamespace returns the namespace of the Service which owns an EndpointSlice; it is the namespace of the
+// EndpointSlice itself, unless overridden with the owner Service namespace annotation.
+func ownerServiceNamespace(endpointSlice *discoveryv1.EndpointSlice) string {
+	if ns := strings.TrimSpace(endpointSlice.Annotations[objectmeta.EndpointSliceAnnotationOwnerServiceNamespace]); ns != "" {
+		return ns
+	}
+	return endpointSlice.Namespace
+}
+
+// allowedEndpointSliceNamespaces returns the namespaces, other than its own, from which a ServiceExport accepts
+// EndpointSlices.
+func allowedEndpointSliceNamespaces(svcExport *fleetnetv1alpha1.ServiceExport) []string {
+	var namespaces []string
+	for _, ns := range strings.Split(svcExport.Annotations[objectmeta.ServiceExportAnnotationAllowedEndpointSliceNamespaces], ",") {
+		if ns = strings.TrimSpace(ns); ns != "" && ns != svcExport.Namespace {
+			namespaces = append(namespaces, ns)
+		}
+	}
+	return namespaces
+}
+
+// isEndpointSliceAllowedByServiceExport returns if a ServiceExport accepts an EndpointSlice as the endpoints of its
+// Service. EndpointSlices in the namespace of the ServiceExport are always accepted; EndpointSlices from other
+// namespaces are accepted only if their namespaces are explicitly allowed by the ServiceExport, which guards
+// against a tenant exporting the endpoints of another namespace under its own Service.
+func isEndpointSliceAllowedByServiceExport(svcExport *fleetnetv1alpha1.ServiceExport, endpointSlice *discoveryv1.EndpointSlice) bool {
+	if endpointSlice.Namespace == svcExport.Namespace {
+		return true
+	}
+	for _, ns := range allowedEndpointSliceNamespaces(svcExport) {
+		if ns == endpointSlice.Namespace {
+			return true
+		}
+	}
+	return false
+}
+
 // isUniqueNameValid returns if an assigned unique name is a valid DNS subdomain name.
 func isUniqueNameValid(name string) bool {
 	if errs := validation.IsDNS1123Subdomain(name); len(errs) != 0 {
@@ -46,20 +127,147 @@ func isEndpointSliceExportLinkedWithEndpointSlice(endpointSliceExport *fleetnetv
 	return (endpointSliceExport.Spec.EndpointSliceReference.UID == endpointSlice.UID)
 }
 
-// extractEndpointsFromEndpointSlice extracts endpoints from an EndpointSlice.
-func extractEndpointsFromEndpointSlice(endpointSlice *discoveryv1.EndpointSlice) []fleetnetv1alpha1.Endpoint {
+// BuildEndpointSliceExport builds the EndpointSliceExport of an EndpointSlice with the endpoints to export, which is
+// applied in the hub cluster under the given key; the owner Service of the EndpointSlice is referred to by the
+// namespace and the name under which it is exported.
+func BuildEndpointSliceExport(key types.NamespacedName, memberClusterID string, endpointSlice *discoveryv1.EndpointSlice, endpoints []fleetnetv1alpha1.Endpoint,
+	endpointSliceReference fleetnetv1alpha1.ExportedObjectReference, svcNamespace, exportedSvcName string) fleetnetv1alpha1.EndpointSliceExport {
+	// The EndpointSliceExport is built from the desired state only, and is applied with server-side apply, so
+	// that the fields added by the hub side are left untouched.
+	return fleetnetv1alpha1.EndpointSliceExport{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: fleetnetv1alpha1.GroupVersion.String(),
+			Kind:       "EndpointSliceExport",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: key.Namespace,
+			Name:      key.Name,
+			Labels: map[string]string{
+				objectmeta.ExportedObjectLabelMemberCluster: memberClusterID,
+			},
+			Annotations: map[string]string{
+				metrics.MetricsAnnotationLastObservedGeneration: strconv.FormatInt(endpointSlice.Generation, 10),
+			},
+		},
+		Spec: fleetnetv1alpha1.EndpointSliceExportSpec{
+			AddressType:            endpointSlice.AddressType,
+			Endpoints:              endpoints,
+			Ports:                  endpointSlice.Ports,
+			EndpointSliceReference: endpointSliceReference,
+			OwnerServiceReference: fleetnetv1alpha1.OwnerServiceReference{
+				// The owner Service resides in the same namespace as the EndpointSlice to export, unless the
+				// EndpointSlice claims otherwise.
+				Namespace:      svcNamespace,
+				Name:           exportedSvcName,
+				NamespacedName: fmt.Sprintf("%s/%s", svcNamespace, exportedSvcName),
+			},
+		},
+	}
+}
+
+// extractEndpointsFromEndpointSlice extracts endpoints from an EndpointSlice; the terminating endpoints which are
+// still serving are extracted as well if propagateTerminating is set.
+func extractEndpointsFromEndpointSlice(endpointSlice *discoveryv1.EndpointSlice, propagateTerminating bool) []fleetnetv1alpha1.Endpoint {
 	extractedEndpoints := []fleetnetv1alpha1.Endpoint{}
 	for _, endpoint := range endpointSlice.Endpoints {
-		// Only ready endpoints can be exported; EndpointSlice API dictates that consumers should interpret
+		// Ready endpoints are always exported; EndpointSlice API dictates that consumers should interpret
 		// unknown ready state, represented by a nil value, as true ready state.
-		// TO-DO (chenyu1): In newer API versions the EndpointConditions API (V1) introduces a serving state, which
-		// allows a backend to serve traffic even if it is already terminating (EndpointSliceTerminationCondition
-		// feature gate).
 		if endpoint.Conditions.Ready == nil || *(endpoint.Conditions.Ready) {
 			extractedEndpoints = append(extractedEndpoints, fleetnetv1alpha1.Endpoint{
 				Addresses: endpoint.Addresses,
+				NodeName:  endpoint.NodeName,
+			})
+			continue
+		}
+		// A terminating endpoint is not ready, yet it may still serve the in-flight requests until its pod is gone;
+		// it is exported with its conditions, so that the importing clusters can drain it gracefully.
+		if propagateTerminating && ptr.Deref(endpoint.Conditions.Terminating, false) && ptr.Deref(endpoint.Conditions.Serving, false) {
+			extractedEndpoints = append(extractedEndpoints, fleetnetv1alpha1.Endpoint{
+				Addresses:   endpoint.Addresses,
+				NodeName:    endpoint.NodeName,
+				Serving:     ptr.To(true),
+				Terminating: ptr.To(true),
 			})
 		}
 	}
 	return extractedEndpoints
 }
+
+// exportedEndpointKey identifies an endpoint among all the EndpointSlices of a Service.
+type exportedEndpointKey struct {
+	endpointSlice types.NamespacedName
+	address       string
+}
+
+// endpointAddressKey returns the address which identifies an endpoint in an EndpointSlice.
+func endpointAddressKey(addresses []string) string {
+	return strings.Join(addresses, ",")
+}
+
+// endpointAddressHash returns a stable hash of the address of an endpoint.
+func endpointAddressHash(address string) uint64 {
+	h := fnv.New64a()
+	// Hash.Write never returns an error.
+	_, _ = h.Write([]byte(address))
+	return h.Sum64()
+}
+
+// selectExportedEndpoints selects at most maxEndpoints ready endpoints from the EndpointSlices of a Service for
+// export; it returns the selected endpoints and the total number of ready endpoints. The terminating endpoints
+// which are still serving count as ready ones if propagateTerminating is set.
+//
+// The endpoints are selected by the hash of their addresses rather than by their order in the EndpointSlices, so
+// that the selected subset stays the same across reconciliations, and the arrival or departure of one endpoint
+// changes at most one endpoint in the subset.
+func selectExportedEndpoints(endpointSlices []discoveryv1.EndpointSlice, maxEndpoints int, propagateTerminating bool) (map[exportedEndpointKey]bool, int) {
+	type candidate struct {
+		key  exportedEndpointKey
+		hash uint64
+	}
+	candidates := []candidate{}
+	for i := range endpointSlices {
+		endpointSlice := &endpointSlices[i]
+		sliceKey := types.NamespacedName{Namespace: endpointSlice.Namespace, Name: endpointSlice.Name}
+		for _, endpoint := range extractEndpointsFromEndpointSlice(endpointSlice, propagateTerminating) {
+			address := endpointAddressKey(endpoint.Addresses)
+			candidates = append(candidates, candidate{
+				key:  exportedEndpointKey{endpointSlice: sliceKey, address: address},
+				hash: endpointAddressHash(address),
+			})
+		}
+	}
+
+	// Break the ties by address and EndpointSlice, so that the order is total.
+	sort.Slice(candidates, func(i, j int) bool {
+		a, b := candidates[i], candidates[j]
+		if a.hash != b.hash {
+			return a.hash < b.hash
+		}
+		if a.key.address != b.key.address {
+			return a.key.address < b.key.address
+		}
+		return a.key.endpointSlice.String() < b.key.endpointSlice.String()
+	})
+
+	total := len(candidates)
+	if total > maxEndpoints {
+		candidates = candidates[:maxEndpoints]
+	}
+	selected := make(map[exportedEndpointKey]bool, len(candidates))
+	for _, c := range candidates {
+		selected[c.key] = true
+	}
+	return selected, total
+}
+
+// allowsHostNetworkEndpoints returns if a ServiceExport opts in to exporting the endpoints backed by pods in the host
+// network.
+func allowsHostNetworkEndpoints(svcExport *fleetnetv1alpha1.ServiceExport) bool {
+	return svcExport.Annotations[objectmeta.ServiceExportAnnotationAllowHostNetworkEndpoints] == "true"
+}
+
+// isHostNetworkPod returns if a pod runs in the host network, in which case its IP is the IP of its node; a pod whose
+// IP equals the IP of its node is considered to run in the host network as well.
+func isHostNetworkPod(pod *corev1.Pod) bool {
+	return pod.Spec.HostNetwork || (pod.Status.PodIP != "" && pod.Status.PodIP == pod.Status.HostIP)
+}
@@ -16,9 +16,13 @@ import (
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/klog/v2"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
 
 	fleetnetv1alpha1 "go.goms.io/fleet-networking/api/v1alpha1"
+	"go.goms.io/fleet-networking/pkg/common/hubclient"
 	"go.goms.io/fleet-networking/pkg/common/objectmeta"
 )
 
@@ -102,15 +106,38 @@ func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Resu
 func (r *Reconciler) SetupWithManager(mgr ctrl.Manager) error {
 	return ctrl.NewControllerManagedBy(mgr).
 		// The EndpointSliceExport controller watches over EndpointSliceExport objects.
-		// TO-DO (chenyu1): use predicates to filter out some events.
-		For(&fleetnetv1alpha1.EndpointSliceExport{}).
+		For(&fleetnetv1alpha1.EndpointSliceExport{}, builder.WithPredicates(endpointSliceExportEventFilter())).
 		Complete(r)
 }
 
+// endpointSliceExportEventFilter filters the EndpointSliceExport events down to the ones which may unlink an
+// EndpointSliceExport from its EndpointSlice.
+//
+// Each reconciliation looks up the single EndpointSlice referred to by the EndpointSliceExport, so it is only needed
+// when the EndpointSliceExport is created or its spec changes; the updates which leave the generation unchanged, e.g.
+// the ones touching the resource version or the managed fields only, are dropped, as are the deletions, so that a busy
+// hub namespace does not keep the member cluster busy. The periodic re-scan still covers the EndpointSlices deleted
+// without an event on the EndpointSliceExport.
+func endpointSliceExportEventFilter() predicate.Funcs {
+	return predicate.Funcs{
+		CreateFunc: func(_ event.CreateEvent) bool {
+			return true
+		},
+		UpdateFunc: func(e event.UpdateEvent) bool {
+			return e.ObjectOld.GetGeneration() != e.ObjectNew.GetGeneration()
+		},
+		DeleteFunc: func(_ event.DeleteEvent) bool {
+			return false
+		},
+		GenericFunc: func(_ event.GenericEvent) bool {
+			return true
+		},
+	}
+}
+
 // deleteEndpointSliceExport deletes an EndpointSliceExport from the hub cluster.
 func (r *Reconciler) deleteEndpointSliceExport(ctx context.Context, endpointSliceExport *fleetnetv1alpha1.EndpointSliceExport) (ctrl.Result, error) {
-	if err := r.HubClient.Delete(ctx, endpointSliceExport); err != nil && !errors.IsNotFound(err) {
-		klog.ErrorS(err, "Failed to delete endpoint slice export", "endpointSliceExport", klog.KObj(endpointSliceExport))
+	if err := hubclient.Delete(ctx, r.HubClient, endpointSliceExport); err != nil {
 		return ctrl.Result{}, err
 	}
 	return ctrl.Result{}, nil
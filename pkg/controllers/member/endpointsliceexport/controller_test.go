@@ -7,6 +7,7 @@ package endpointsliceexport
 
 import (
 	"context"
+	"fmt"
 	"log"
 	"os"
 	"testing"
@@ -16,7 +17,11 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/kubernetes/scheme"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+	"sigs.k8s.io/controller-runtime/pkg/event"
 
 	fleetnetv1alpha1 "go.goms.io/fleet-networking/api/v1alpha1"
 	"go.goms.io/fleet-networking/pkg/common/objectmeta"
@@ -163,3 +168,149 @@ func TestDeleteEndpointSliceExport(t *testing.T) {
 		})
 	}
 }
+
+func endpointSliceExportWithGeneration(generation int64, resourceVersion string) *fleetnetv1alpha1.EndpointSliceExport {
+	return &fleetnetv1alpha1.EndpointSliceExport{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace:       hubNSForMember,
+			Name:            endpointSliceExportName,
+			Generation:      generation,
+			ResourceVersion: resourceVersion,
+		},
+		Spec: fleetnetv1alpha1.EndpointSliceExportSpec{
+			EndpointSliceReference: fleetnetv1alpha1.ExportedObjectReference{
+				Namespace: memberUserNS,
+				Name:      endpointSliceName,
+			},
+		},
+	}
+}
+
+// TestEndpointSliceExportEventFilter tests the endpointSliceExportEventFilter function.
+func TestEndpointSliceExportEventFilter(t *testing.T) {
+	filter := endpointSliceExportEventFilter()
+	testCases := []struct {
+		name   string
+		filter func() bool
+		want   bool
+	}{
+		{
+			name: "create",
+			filter: func() bool {
+				return filter.Create(event.CreateEvent{Object: endpointSliceExportWithGeneration(1, "1")})
+			},
+			want: true,
+		},
+		{
+			name: "update with spec change",
+			filter: func() bool {
+				return filter.Update(event.UpdateEvent{
+					ObjectOld: endpointSliceExportWithGeneration(1, "1"),
+					ObjectNew: endpointSliceExportWithGeneration(2, "2"),
+				})
+			},
+			want: true,
+		},
+		{
+			name: "update of resource version only",
+			filter: func() bool {
+				return filter.Update(event.UpdateEvent{
+					ObjectOld: endpointSliceExportWithGeneration(1, "1"),
+					ObjectNew: endpointSliceExportWithGeneration(1, "2"),
+				})
+			},
+		},
+		{
+			name: "delete",
+			filter: func() bool {
+				return filter.Delete(event.DeleteEvent{Object: endpointSliceExportWithGeneration(1, "1")})
+			},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := tc.filter(); got != tc.want {
+				t.Errorf("endpointSliceExportEventFilter(), got %t, want %t", got, tc.want)
+			}
+		})
+	}
+}
+
+// TestHubUpdateMemberCalls fires a burst of EndpointSliceExport updates in the hub cluster through the event filter
+// and the Reconciler, and counts the calls made to the member cluster.
+func TestHubUpdateMemberCalls(t *testing.T) {
+	const updates = 1000
+	testCases := []struct {
+		name string
+		// bumpGeneration is true if the updates change the spec of the EndpointSliceExport.
+		bumpGeneration bool
+		wantGets       int
+	}{
+		{
+			name: "updates of the resource version and the managed fields only",
+		},
+		{
+			name:           "updates of the spec",
+			bumpGeneration: true,
+			wantGets:       updates,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx := context.Background()
+			var gets, lists int
+			fakeMemberClient := fake.NewClientBuilder().
+				WithScheme(scheme.Scheme).
+				WithObjects(&discoveryv1.EndpointSlice{
+					ObjectMeta: metav1.ObjectMeta{
+						Namespace: memberUserNS,
+						Name:      endpointSliceName,
+						Annotations: map[string]string{
+							objectmeta.ExportedObjectAnnotationUniqueName: endpointSliceExportName,
+						},
+					},
+					AddressType: discoveryv1.AddressTypeIPv4,
+				}).
+				WithInterceptorFuncs(interceptor.Funcs{
+					Get: func(ctx context.Context, c client.WithWatch, key client.ObjectKey, obj client.Object, opts ...client.GetOption) error {
+						gets++
+						return c.Get(ctx, key, obj, opts...)
+					},
+					List: func(ctx context.Context, c client.WithWatch, list client.ObjectList, opts ...client.ListOption) error {
+						lists++
+						return c.List(ctx, list, opts...)
+					},
+				}).
+				Build()
+			fakeHubClient := fake.NewClientBuilder().
+				WithScheme(scheme.Scheme).
+				WithObjects(endpointSliceExportWithGeneration(1, "")).
+				Build()
+			reconciler := &Reconciler{
+				MemberClient: fakeMemberClient,
+				HubClient:    fakeHubClient,
+			}
+			filter := endpointSliceExportEventFilter()
+
+			old := endpointSliceExportWithGeneration(1, "1")
+			for i := 0; i < updates; i++ {
+				generation := old.Generation
+				if tc.bumpGeneration {
+					generation++
+				}
+				updated := endpointSliceExportWithGeneration(generation, fmt.Sprintf("%d", i+2))
+				updated.ManagedFields = []metav1.ManagedFieldsEntry{{Manager: fmt.Sprintf("manager-%d", i)}}
+				if filter.Update(event.UpdateEvent{ObjectOld: old, ObjectNew: updated}) {
+					if _, err := reconciler.Reconcile(ctx, ctrl.Request{NamespacedName: endpointSliceExportKey}); err != nil {
+						t.Fatalf("Reconcile(), got %v, want no error", err)
+					}
+				}
+				old = updated
+			}
+
+			if gets != tc.wantGets || lists != 0 {
+				t.Errorf("member cluster calls after %d hub updates, got %d Gets and %d Lists, want %d Gets and no List", updates, gets, lists, tc.wantGets)
+			}
+		})
+	}
+}
@@ -9,35 +9,64 @@ package endpointsliceimport
 
 import (
 	"context"
+	stderrors "errors"
 	"fmt"
+	"slices"
+	"strings"
 	"time"
 
 	"github.com/prometheus/client_golang/prometheus"
 	corev1 "k8s.io/api/core/v1"
 	discoveryv1 "k8s.io/api/discovery/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
 	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/intstr"
 	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/client-go/tools/record"
 	"k8s.io/klog/v2"
+	"k8s.io/utils/ptr"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
 	ctrlmetrics "sigs.k8s.io/controller-runtime/pkg/metrics"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
 
 	fleetnetv1alpha1 "go.goms.io/fleet-networking/api/v1alpha1"
+	"go.goms.io/fleet-networking/pkg/common/cloudconfig"
+	"go.goms.io/fleet-networking/pkg/common/condition"
+	"go.goms.io/fleet-networking/pkg/common/exporthealth"
+	"go.goms.io/fleet-networking/pkg/common/ipfamily"
+	"go.goms.io/fleet-networking/pkg/common/listguard"
 	"go.goms.io/fleet-networking/pkg/common/metrics"
+	"go.goms.io/fleet-networking/pkg/common/networkingmode"
 	"go.goms.io/fleet-networking/pkg/common/objectmeta"
 )
 
 const (
+	// ControllerName is the name of the Reconciler.
+	ControllerName = "endpointsliceimport-controller"
+
 	// controllerID helps identify that imported EndpointSlices are managed by this controller.
 	controllerID                        = "endpointsliceimport-controller.networking.fleet.azure.com"
 	endpointSliceImportCleanupFinalizer = "networking.fleet.azure.com/endpointsliceimport-cleanup"
 
-	mcsServiceImportRefFieldKey = ".spec.serviceImport.name"
+	mcsServiceImportRefFieldKey                       = ".spec.serviceImport.name"
+	endpointSliceImportOwnerSvcNamespacedNameFieldKey = ".spec.ownerServiceReference.namespacedName"
 
 	endpointSliceImportRetryInterval = time.Second * 2
+	// unsupportedAddressTypeResyncInterval is the interval at which the EndpointSliceImports whose address type is not
+	// supported by the member cluster are retried, so that they are imported once the cluster supports it.
+	unsupportedAddressTypeResyncInterval = time.Minute * 10
+
+	importedCondReason               = "Imported"
+	unsupportedAddressTypeCondReason = "UnsupportedAddressType"
 )
 
 var (
@@ -67,12 +96,34 @@ var (
 			"is_first_import",
 		},
 	)
+
+	// deduplicatedEndpoints is a Prometheus gauge metric bundle that reports the number of the endpoints of a Service
+	// exported from a cluster which are not imported, as the same endpoints are imported from another cluster; it is
+	// only reported for the MCSes which deduplicate the endpoints.
+	deduplicatedEndpoints = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: metrics.MetricsNamespace,
+			Subsystem: metrics.MetricsSubsystem,
+			Name:      "imported_endpoints_deduplicated",
+			Help:      "The number of the endpoints exported from a cluster which are deduplicated on import",
+		},
+		[]string{
+			// The namespace and the name of the Service.
+			"namespace",
+			"name",
+			// The ID of the origin cluster, whose endpoints are deduplicated.
+			"origin_cluster_id",
+		},
+	)
 )
 
 func init() {
 	// Register endpointSliceExportImportDuration (endpointslice_export_import_duration_milliseconds) metric
 	// with the controller runtime global metrics registry.
 	ctrlmetrics.Registry.MustRegister(endpointSliceExportImportDuration)
+	// Register deduplicatedEndpoints (imported_endpoints_deduplicated) metric with the controller runtime global
+	// metrics registry.
+	ctrlmetrics.Registry.MustRegister(deduplicatedEndpoints)
 }
 
 // Reconciler reconciles an EndpointSliceImport.
@@ -82,12 +133,29 @@ type Reconciler struct {
 	HubClient       client.Client
 	// The namespace reserved for fleet resources in the member cluster.
 	FleetSystemNamespace string
+
+	// NetworkProperties are the network properties of the member cluster.
+	NetworkProperties cloudconfig.NetworkProperties
+	// SkipUnreachableClusters skips importing the endpoints exported from the clusters which are unreachable from
+	// the member cluster, per the network properties of the clusters; the skipped clusters are recorded in the status
+	// of the MCS.
+	SkipUnreachableClusters bool
+
+	// NetworkingMode is the networking mode of the member cluster; the imported EndpointSlices are removed while the
+	// member cluster is in the export-only mode. Nil enables the import.
+	NetworkingMode *networkingmode.Gate
+
+	Recorder record.EventRecorder
 }
 
 //+kubebuilder:rbac:groups=networking.fleet.azure.com,resources=endpointsliceimports,verbs=get;list;watch;update;patch
-//+kubebuilder:rbac:groups=discovery.k8s.io,resources=endpointslices,verbs=get;create;update;patch;delete
+//+kubebuilder:rbac:groups=networking.fleet.azure.com,resources=endpointsliceimports/status,verbs=get;update;patch
+//+kubebuilder:rbac:groups=discovery.k8s.io,resources=endpointslices,verbs=get;list;watch;create;update;patch;delete
 //+kubebuilder:rbac:groups=networking.fleet.azure.com,resources=multiclusterservices,verbs=get;list;watch
-//+kubebuilder:rbac:groups="",resources=services,verbs=get;list
+//+kubebuilder:rbac:groups=networking.fleet.azure.com,resources=multiclusterservices/status,verbs=get;update;patch
+//+kubebuilder:rbac:groups=networking.fleet.azure.com,resources=serviceimports,verbs=get;list;watch
+//+kubebuilder:rbac:groups="",resources=services,verbs=get;list;watch
+//+kubebuilder:rbac:groups="",resources=events,verbs=create;patch
 
 // Reconcile imports an EndpointSlice from hub cluster.
 func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
@@ -116,15 +184,25 @@ func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Resu
 	// Check if the EndpointSliceImport has been deleted and needs cleanup (unimport EndpointSlice).
 	// An EndpointSliceImport needs cleanup when it has the EndpointSliceImport cleanup finalizer added;
 	// the absence of this finalizer guarantees that the EndpointSliceImport has never been imported.
-	endpointSliceRef := klog.KRef(r.FleetSystemNamespace, req.Name)
 	if endpointSliceImport.DeletionTimestamp != nil {
 		klog.V(2).InfoS("EndpointSliceImport is deleted; unimport EndpointSlice",
-			"endpointSliceImport", endpointSliceImportRef,
-			"endpointSlice", endpointSliceRef)
+			"endpointSliceImport", endpointSliceImportRef)
 		if err := r.unimportEndpointSlice(ctx, endpointSliceImport); err != nil {
 			klog.ErrorS(err, "Failed to unimport EndpointSlice",
-				"endpointSliceImport", endpointSliceImportRef,
-				"endpointSlice", endpointSliceRef)
+				"endpointSliceImport", endpointSliceImportRef)
+			return ctrl.Result{}, err
+		}
+		return ctrl.Result{}, nil
+	}
+
+	// Unimport the EndpointSlice if the member cluster is not allowed to import Services; the hub cluster retracts
+	// the EndpointSliceImport once the imports of the member cluster are withdrawn.
+	if !r.NetworkingMode.ImportEnabled() {
+		klog.V(2).InfoS("Import is disabled; unimport EndpointSlice",
+			"endpointSliceImport", endpointSliceImportRef, "mode", r.NetworkingMode.Mode())
+		if err := r.unimportEndpointSlice(ctx, endpointSliceImport); err != nil {
+			klog.ErrorS(err, "Failed to unimport EndpointSlice",
+				"endpointSliceImport", endpointSliceImportRef)
 			return ctrl.Result{}, err
 		}
 		return ctrl.Result{}, nil
@@ -168,6 +246,7 @@ func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Resu
 	// one member cluster or from multiple clusters from the fleet, attempt to import the same Service, it is
 	// guaranteed that only one will succeed.
 	derivedSvcName := scanForDerivedServiceName(multiClusterSvcList)
+	hasPortFilter := scanForPortFilter(multiClusterSvcList, derivedSvcName)
 
 	// Verify if the found derived Service label points to a Service that the controller can associate the
 	// EndpointSlice with. In most cases this check will always pass as the hub cluster will only distribute
@@ -179,14 +258,14 @@ func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Resu
 	// * a connectivity issue has kept the member cluster out of sync with the hub cluster, with the member cluster
 	//   not knowing that a Service has been successfully claimed by itself; or
 	// * the controller for processing MCSes lags, and has not created the derived Service in time.
-	isValid, err := r.isDerivedServiceValid(ctx, derivedSvcName)
+	derivedSvc, err := r.getValidDerivedService(ctx, derivedSvcName)
 	switch {
 	case err != nil:
 		klog.ErrorS(err, "Failed to check if derived Service is valid",
 			"derivedServiceName", derivedSvcName,
 			"endpointSliceImport", endpointSliceImportRef)
 		return ctrl.Result{}, err
-	case !isValid:
+	case derivedSvc == nil:
 		// Retry importing the EndpointSlice at a later time if no valid derived Service can be found.
 		klog.V(2).InfoS("No valid derived Service; will retry importing EndpointSlice later",
 			"derivedServiceName", derivedSvcName,
@@ -196,13 +275,65 @@ func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Resu
 
 	// Special note:
 	// There exists a corner case where an MCS that imports a specific Service have multiple derived Services created;
-	// this is usually the result of direct label manipulation on the user's end. Ideally, this controller should watch
-	// for changes on MCS resources and (re)associate imported EndpointSlices to the latest dervied Service in use;
-	// however, unfortunately, with the current implementation of controller-runtime package, it is not possible
-	// for the controller to watch for resources on two different directions (member cluster and hub cluster),
-	// and as a result, an imported EndpointSlice could be bound to a derived Service that is no longer in use.
-	// Periodic resyncs can help address this issue, but it may take a quite long while before the situation is
-	// corrected, should this corner case happens.
+	// this is usually the result of direct label manipulation on the user's end. The controller watches for changes
+	// on derived Services (see SetupWithManager) and re-imports the EndpointSlices of the MCSes which currently claim
+	// them; an imported EndpointSlice bound to a derived Service that is no longer claimed by any MCS, however, is
+	// only corrected by periodic resyncs, which may take a quite long while.
+
+	// Skip importing the EndpointSlice if the cluster which exports it is unreachable from the member cluster, or
+	// reports the Service as Unhealthy per the exporter health policy of the MCS.
+	originClusterID := endpointSliceImport.Spec.EndpointSliceReference.ClusterID
+	multiClusterSvc := scanForClaimingMultiClusterService(multiClusterSvcList, derivedSvcName)
+	var healthPolicy fleetnetv1alpha1.ExporterHealthPolicy
+	if multiClusterSvc != nil {
+		healthPolicy = multiClusterSvc.Spec.ExporterHealthPolicy
+	}
+	skipped, skipReason := false, ""
+	if r.SkipUnreachableClusters || healthPolicy == fleetnetv1alpha1.ExporterHealthPolicyExcludeUnhealthy {
+		skipped, skipReason, err = r.shouldSkipOriginCluster(ctx, endpointSliceImport, healthPolicy)
+		if err != nil {
+			klog.ErrorS(err, "Failed to check if the origin cluster should be skipped",
+				"endpointSliceImport", endpointSliceImportRef,
+				"originClusterID", originClusterID)
+			return ctrl.Result{}, err
+		}
+	}
+	if err := r.recordSkippedCluster(ctx, multiClusterSvcList, derivedSvcName, originClusterID, skipped); err != nil {
+		klog.ErrorS(err, "Failed to record the skipped cluster in MCS status",
+			"endpointSliceImport", endpointSliceImportRef,
+			"originClusterID", originClusterID)
+		return ctrl.Result{}, err
+	}
+	if skipped {
+		// Endpoints imported before the cluster was skipped are removed as well.
+		klog.V(2).InfoS("The origin cluster is skipped; EndpointSlice will not be imported",
+			"endpointSliceImport", endpointSliceImportRef,
+			"originClusterID", originClusterID,
+			"reason", skipReason)
+		if err := r.removeFromEndpointSlices(ctx, endpointSliceImport); err != nil {
+			klog.ErrorS(err, "Failed to remove the endpoints of the skipped cluster",
+				"endpointSliceImport", endpointSliceImportRef)
+			return ctrl.Result{}, err
+		}
+		return ctrl.Result{}, nil
+	}
+
+	// Skip importing the EndpointSlice if the derived Service does not have its IP family; this happens when a
+	// dual-stack Service is imported into a cluster which does not support dual-stack, and the derived Service falls
+	// back to the primary IP family.
+	if !ipfamily.SupportsAddressType(derivedSvc.Spec.IPFamilies, endpointSliceImport.Spec.AddressType) {
+		klog.V(2).InfoS("The derived Service does not have the IP family of the EndpointSlice; EndpointSlice will not be imported",
+			"endpointSliceImport", endpointSliceImportRef,
+			"derivedService", klog.KObj(derivedSvc),
+			"addressType", endpointSliceImport.Spec.AddressType,
+			"ipFamilies", derivedSvc.Spec.IPFamilies)
+		if err := r.removeFromEndpointSlices(ctx, endpointSliceImport); err != nil {
+			klog.ErrorS(err, "Failed to remove the endpoints of the unsupported IP family",
+				"endpointSliceImport", endpointSliceImportRef)
+			return ctrl.Result{}, err
+		}
+		return ctrl.Result{}, nil
+	}
 
 	// Add the cleanup finalizer (if one has not been added earlier); this must happen before
 	// the EndpointSlice is imported.
@@ -212,21 +343,33 @@ func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Resu
 		return ctrl.Result{}, err
 	}
 
-	// Associate the EndpointSlice with the Service.
-	klog.V(2).InfoS("Import the EndpointSlice", "endpointSlice", endpointSliceRef)
-	endpointSlice := &discoveryv1.EndpointSlice{
-		ObjectMeta: metav1.ObjectMeta{
-			Namespace: r.FleetSystemNamespace,
-			Name:      endpointSliceImport.Name,
-		},
+	// Associate the EndpointSlice with the Service. The endpoints of the EndpointSliceImports of the same Service and
+	// address type from one cluster are packed into as few EndpointSlices as possible, so that a Service whose
+	// endpoints are spread over many small EndpointSlices in the exporting cluster does not bloat the processing
+	// of kube-proxy in this cluster.
+	klog.V(2).InfoS("Import the EndpointSlice", "endpointSliceImport", endpointSliceImportRef, "derivedService", klog.KObj(derivedSvc))
+	if err := r.importEndpointSlices(ctx, endpointSliceImport, derivedSvc, hasPortFilter, multiClusterSvc); err != nil {
+		if isUnsupportedAddressTypeError(err) {
+			// Retrying right away would fail the same way; the EndpointSliceImport is retried on a slow resync
+			// instead, which picks it up once the member cluster supports the address type.
+			klog.V(2).InfoS("The member cluster does not support the address type of the EndpointSlice; will retry importing it later",
+				"endpointSliceImport", endpointSliceImportRef,
+				"addressType", endpointSliceImport.Spec.AddressType,
+				"error", err)
+			if err := r.updateImportedCondition(ctx, endpointSliceImport, multiClusterSvc, err); err != nil {
+				klog.ErrorS(err, "Failed to update the imported condition of EndpointSliceImport",
+					"endpointSliceImport", endpointSliceImportRef)
+				return ctrl.Result{}, err
+			}
+			return ctrl.Result{RequeueAfter: unsupportedAddressTypeResyncInterval}, nil
+		}
+		klog.ErrorS(err, "Failed to import EndpointSlice",
+			"endpointSliceImport", endpointSliceImportRef,
+			"derivedService", klog.KObj(derivedSvc))
+		return ctrl.Result{}, err
 	}
-	if op, err := controllerutil.CreateOrUpdate(ctx, r.MemberClient, endpointSlice, func() error {
-		formatEndpointSliceFromImport(endpointSlice, derivedSvcName, endpointSliceImport)
-		return nil
-	}); err != nil {
-		klog.ErrorS(err, "Failed to create/update EndpointSlice",
-			"endpointSlice", endpointSliceRef,
-			"op", op,
+	if err := r.updateImportedCondition(ctx, endpointSliceImport, nil, nil); err != nil {
+		klog.ErrorS(err, "Failed to update the imported condition of EndpointSliceImport",
 			"endpointSliceImport", endpointSliceImportRef)
 		return ctrl.Result{}, err
 	}
@@ -258,11 +401,194 @@ func (r *Reconciler) SetupWithManager(ctx context.Context, memberCtrlMgr, hubCtr
 		return err
 	}
 
+	// Set up an index for efficient EndpointSliceImport lookup **on the controller manager for hub cluster
+	// controllers**.
+	if err := hubCtrlMgr.GetFieldIndexer().IndexField(ctx,
+		&fleetnetv1alpha1.EndpointSliceImport{},
+		endpointSliceImportOwnerSvcNamespacedNameFieldKey,
+		func(o client.Object) []string {
+			endpointSliceImport, ok := o.(*fleetnetv1alpha1.EndpointSliceImport)
+			if !ok {
+				return []string{}
+			}
+			return []string{endpointSliceImport.Spec.OwnerServiceReference.NamespacedName}
+		},
+	); err != nil {
+		return err
+	}
+
 	// The controller itself is managed by the controller manager for hub cluster controllers.
-	return ctrl.NewControllerManagedBy(hubCtrlMgr).
+	b := ctrl.NewControllerManagedBy(hubCtrlMgr).
 		// The EndpointSliceImport controller watches over EndpointSliceImport objects.
 		For(&fleetnetv1alpha1.EndpointSliceImport{}).
-		Complete(r)
+		// The EndpointSliceImport controller also watches over derived Services in the member cluster, so that
+		// imported EndpointSlices are updated when the ports exposed by an MCS change.
+		WatchesRawSource(source.Kind(memberCtrlMgr.GetCache(),
+			&corev1.Service{},
+			handler.TypedEnqueueRequestsFromMapFunc(r.derivedServiceEventHandler()),
+		)).
+		// The EndpointSliceImport controller also watches over MCSes in the member cluster, so that imported
+		// EndpointSlices are updated when an MCS starts or stops deduplicating the endpoints.
+		WatchesRawSource(source.Kind(memberCtrlMgr.GetCache(),
+			&fleetnetv1alpha1.MultiClusterService{},
+			handler.TypedEnqueueRequestsFromMapFunc(r.multiClusterServiceEventHandler()),
+			predicate.TypedGenerationChangedPredicate[*fleetnetv1alpha1.MultiClusterService]{},
+		)).
+		// The EndpointSliceImport controller also watches over ServiceImports in the member cluster, so that the
+		// endpoints of a cluster are skipped or imported again when its reported health or network properties change.
+		WatchesRawSource(source.Kind(memberCtrlMgr.GetCache(),
+			&fleetnetv1alpha1.ServiceImport{},
+			handler.TypedEnqueueRequestsFromMapFunc(r.serviceImportEventHandler()),
+			predicate.TypedFuncs[*fleetnetv1alpha1.ServiceImport]{
+				UpdateFunc: func(e event.TypedUpdateEvent[*fleetnetv1alpha1.ServiceImport]) bool {
+					return !equality.Semantic.DeepEqual(e.ObjectOld.Status.Clusters, e.ObjectNew.Status.Clusters) ||
+						!equality.Semantic.DeepEqual(e.ObjectOld.Status.ClusterHealth, e.ObjectNew.Status.ClusterHealth)
+				},
+			},
+		)).
+		// The endpoints deduplicated across the clusters depend on the EndpointSliceImports of all the clusters, so
+		// the other EndpointSliceImports of the Service are reconciled as well when one changes.
+		Watches(&fleetnetv1alpha1.EndpointSliceImport{},
+			handler.EnqueueRequestsFromMapFunc(r.deduplicatingSiblingsEventHandler))
+	if r.NetworkingMode != nil {
+		// The EndpointSliceImport controller watches over the networking mode of the member cluster as well, so that
+		// the imported EndpointSlices are removed or restored when the import is disabled or enabled.
+		b = b.WatchesRawSource(source.Channel(r.NetworkingMode.Subscribe(),
+			handler.EnqueueRequestsFromMapFunc(r.allEndpointSliceImports)))
+	}
+	return b.Complete(r)
+}
+
+// allEndpointSliceImports maps a networking mode change to all the EndpointSliceImports of the member cluster.
+func (r *Reconciler) allEndpointSliceImports(ctx context.Context, _ client.Object) []reconcile.Request {
+	endpointSliceImportList := &fleetnetv1alpha1.EndpointSliceImportList{}
+	// Only the names of the EndpointSliceImports are read, so that they are not deep-copied out of the cache.
+	if err := r.HubClient.List(ctx, endpointSliceImportList, client.UnsafeDisableDeepCopy); err != nil {
+		klog.ErrorS(err, "Failed to list EndpointSliceImports")
+		return []reconcile.Request{}
+	}
+	reqs := make([]reconcile.Request, 0, len(endpointSliceImportList.Items))
+	for _, endpointSliceImport := range endpointSliceImportList.Items {
+		reqs = append(reqs, reconcile.Request{
+			NamespacedName: types.NamespacedName{Namespace: endpointSliceImport.Namespace, Name: endpointSliceImport.Name},
+		})
+	}
+	return reqs
+}
+
+// derivedServiceEventHandler enqueues the EndpointSliceImports of the Service imported by the MCSes which claim
+// a derived Service.
+func (r *Reconciler) derivedServiceEventHandler() handler.TypedMapFunc[*corev1.Service, reconcile.Request] {
+	return func(ctx context.Context, svc *corev1.Service) []reconcile.Request {
+		if svc.Namespace != r.FleetSystemNamespace {
+			return []reconcile.Request{}
+		}
+
+		multiClusterSvcList := &fleetnetv1alpha1.MultiClusterServiceList{}
+		if err := r.MemberClient.List(ctx,
+			multiClusterSvcList,
+			client.MatchingLabels{objectmeta.MultiClusterServiceLabelDerivedService: svc.Name}); err != nil {
+			klog.ErrorS(err, "Failed to list MCS", "derivedService", klog.KObj(svc))
+			return []reconcile.Request{}
+		}
+
+		reqs := []reconcile.Request{}
+		for _, multiClusterSvc := range multiClusterSvcList.Items {
+			ownerSvcNamespacedName := types.NamespacedName{Namespace: multiClusterSvc.Namespace, Name: multiClusterSvc.Spec.ServiceImport.Name}
+			svcReqs, err := r.endpointSliceImportRequests(ctx, ownerSvcNamespacedName)
+			if err != nil {
+				klog.ErrorS(err, "Failed to list EndpointSliceImports", "derivedService", klog.KObj(svc), "serviceImport", ownerSvcNamespacedName)
+				return []reconcile.Request{}
+			}
+			reqs = append(reqs, svcReqs...)
+		}
+		return reqs
+	}
+}
+
+// multiClusterServiceEventHandler enqueues the EndpointSliceImports of the Service imported by an MCS, so that the
+// imported EndpointSlices are updated when the MCS starts or stops deduplicating the endpoints.
+func (r *Reconciler) multiClusterServiceEventHandler() handler.TypedMapFunc[*fleetnetv1alpha1.MultiClusterService, reconcile.Request] {
+	return func(ctx context.Context, multiClusterSvc *fleetnetv1alpha1.MultiClusterService) []reconcile.Request {
+		ownerSvcNamespacedName := types.NamespacedName{Namespace: multiClusterSvc.Namespace, Name: multiClusterSvc.Spec.ServiceImport.Name}
+		reqs, err := r.endpointSliceImportRequests(ctx, ownerSvcNamespacedName)
+		if err != nil {
+			klog.ErrorS(err, "Failed to list EndpointSliceImports", "multiClusterService", klog.KObj(multiClusterSvc), "serviceImport", ownerSvcNamespacedName)
+			return []reconcile.Request{}
+		}
+		return reqs
+	}
+}
+
+// serviceImportEventHandler enqueues the EndpointSliceImports of the Service imported with a ServiceImport.
+func (r *Reconciler) serviceImportEventHandler() handler.TypedMapFunc[*fleetnetv1alpha1.ServiceImport, reconcile.Request] {
+	return func(ctx context.Context, svcImport *fleetnetv1alpha1.ServiceImport) []reconcile.Request {
+		ownerSvcNamespacedName := types.NamespacedName{Namespace: svcImport.Namespace, Name: svcImport.Name}
+		reqs, err := r.endpointSliceImportRequests(ctx, ownerSvcNamespacedName)
+		if err != nil {
+			klog.ErrorS(err, "Failed to list EndpointSliceImports", "serviceImport", ownerSvcNamespacedName)
+			return []reconcile.Request{}
+		}
+		return reqs
+	}
+}
+
+// deduplicatingSiblingsEventHandler enqueues the other EndpointSliceImports of the Service of an EndpointSliceImport
+// if the Service is imported by an MCS which deduplicates the endpoints, as the endpoints the siblings import depend
+// on the ones of the EndpointSliceImport.
+func (r *Reconciler) deduplicatingSiblingsEventHandler(ctx context.Context, o client.Object) []reconcile.Request {
+	endpointSliceImport, ok := o.(*fleetnetv1alpha1.EndpointSliceImport)
+	if !ok {
+		return []reconcile.Request{}
+	}
+	ownerSvcRef := endpointSliceImport.Spec.OwnerServiceReference
+	multiClusterSvcList := &fleetnetv1alpha1.MultiClusterServiceList{}
+	if err := r.MemberClient.List(ctx,
+		multiClusterSvcList,
+		client.InNamespace(ownerSvcRef.Namespace),
+		client.MatchingFields{mcsServiceImportRefFieldKey: ownerSvcRef.Name}); err != nil {
+		klog.ErrorS(err, "Failed to list MCS", "endpointSliceImport", klog.KObj(endpointSliceImport))
+		return []reconcile.Request{}
+	}
+	deduplicate := false
+	for _, multiClusterSvc := range multiClusterSvcList.Items {
+		if multiClusterSvc.DeletionTimestamp == nil && multiClusterSvc.Spec.DeduplicateEndpoints {
+			deduplicate = true
+		}
+	}
+	if !deduplicate {
+		return []reconcile.Request{}
+	}
+
+	ownerSvcNamespacedName := types.NamespacedName{Namespace: ownerSvcRef.Namespace, Name: ownerSvcRef.Name}
+	reqs, err := r.endpointSliceImportRequests(ctx, ownerSvcNamespacedName)
+	if err != nil {
+		klog.ErrorS(err, "Failed to list EndpointSliceImports", "endpointSliceImport", klog.KObj(endpointSliceImport), "serviceImport", ownerSvcNamespacedName)
+		return []reconcile.Request{}
+	}
+	return slices.DeleteFunc(reqs, func(req reconcile.Request) bool {
+		return req.Namespace == endpointSliceImport.Namespace && req.Name == endpointSliceImport.Name
+	})
+}
+
+// endpointSliceImportRequests returns the requests to reconcile the EndpointSliceImports of a Service.
+func (r *Reconciler) endpointSliceImportRequests(ctx context.Context, ownerSvcNamespacedName types.NamespacedName) ([]reconcile.Request, error) {
+	endpointSliceImportList := &fleetnetv1alpha1.EndpointSliceImportList{}
+	// Only the names of the EndpointSliceImports are read, so that they are not deep-copied out of the cache.
+	if err := r.HubClient.List(ctx,
+		endpointSliceImportList,
+		client.MatchingFields{endpointSliceImportOwnerSvcNamespacedNameFieldKey: ownerSvcNamespacedName.String()},
+		client.UnsafeDisableDeepCopy); err != nil {
+		return nil, err
+	}
+	listguard.ObserveListSize(endpointSliceImportList, "serviceImport", ownerSvcNamespacedName)
+	reqs := make([]reconcile.Request, 0, len(endpointSliceImportList.Items))
+	for _, endpointSliceImport := range endpointSliceImportList.Items {
+		reqs = append(reqs, reconcile.Request{
+			NamespacedName: types.NamespacedName{Namespace: endpointSliceImport.Namespace, Name: endpointSliceImport.Name},
+		})
+	}
+	return reqs, nil
 }
 
 // unimportEndpointSlice unimports an EndpointSlice.
@@ -274,17 +600,11 @@ func (r *Reconciler) unimportEndpointSlice(ctx context.Context, endpointSliceImp
 	}
 
 	// Unimport the EndpointSlice.
-	endpointSlice := &discoveryv1.EndpointSlice{
-		ObjectMeta: metav1.ObjectMeta{
-			Namespace: r.FleetSystemNamespace,
-			Name:      endpointSliceImport.Name,
-		},
-	}
-	if err := r.MemberClient.Delete(ctx, endpointSlice); err != nil && !errors.IsNotFound(err) {
+	if err := r.removeFromEndpointSlices(ctx, endpointSliceImport); err != nil {
 		// It is guaranteed that a finalizer is always added before an EndpointSlice is imported; in some rare
-		// occasions it could happen that an EndpointSliceImport has a finalizer added yet the corresponding
-		// EndpointSlice has not been imported in the member cluster. It is an expected behavior and no action
-		// is needed on this controller's end.
+		// occasions it could happen that an EndpointSliceImport has a finalizer added yet its endpoints have not
+		// been imported in the member cluster. It is an expected behavior and no action is needed on this
+		// controller's end.
 		return err
 	}
 
@@ -302,11 +622,11 @@ func (r *Reconciler) addEndpointSliceImportCleanupFinalizer(ctx context.Context,
 	return nil
 }
 
-// isDerivedServiceValid returns if a derived Service is valid for EndpointSlice association.
-func (r *Reconciler) isDerivedServiceValid(ctx context.Context, derivedSvcName string) (bool, error) {
+// getValidDerivedService returns the derived Service if it is valid for EndpointSlice association, or nil otherwise.
+func (r *Reconciler) getValidDerivedService(ctx context.Context, derivedSvcName string) (*corev1.Service, error) {
 	// Check if the given name is a valid Service name; this helps guard against user tampering the label.
 	if errs := validation.IsDNS1035Label(derivedSvcName); len(errs) != 0 {
-		return false, nil
+		return nil, nil
 	}
 
 	// Check if the derived Service has been created and has not been marked for deletion.
@@ -315,9 +635,78 @@ func (r *Reconciler) isDerivedServiceValid(ctx context.Context, derivedSvcName s
 	derivedSvc := &corev1.Service{}
 	derivedSvcKey := types.NamespacedName{Namespace: r.FleetSystemNamespace, Name: derivedSvcName}
 	if err := r.MemberClient.Get(ctx, derivedSvcKey, derivedSvc); err != nil {
-		return false, client.IgnoreNotFound(err)
+		return nil, client.IgnoreNotFound(err)
+	}
+	if derivedSvc.DeletionTimestamp != nil {
+		return nil, nil
+	}
+	return derivedSvc, nil
+}
+
+// shouldSkipOriginCluster returns if the endpoints exported from the cluster which exports an EndpointSliceImport
+// should not be imported, and why, per the exporting clusters recorded on the ServiceImport: the cluster is unreachable
+// from the member cluster, when the unreachable clusters are skipped, or it reports the Service as Unhealthy, per the
+// exporter health policy.
+func (r *Reconciler) shouldSkipOriginCluster(ctx context.Context, endpointSliceImport *fleetnetv1alpha1.EndpointSliceImport,
+	healthPolicy fleetnetv1alpha1.ExporterHealthPolicy) (bool, string, error) {
+	svcImport := &fleetnetv1alpha1.ServiceImport{}
+	svcImportKey := types.NamespacedName{
+		Namespace: endpointSliceImport.Spec.OwnerServiceReference.Namespace,
+		Name:      endpointSliceImport.Spec.OwnerServiceReference.Name,
+	}
+	if err := r.MemberClient.Get(ctx, svcImportKey, svcImport); err != nil {
+		if errors.IsNotFound(err) {
+			return false, "", nil
+		}
+		return false, "", err
+	}
+	clusterID := endpointSliceImport.Spec.EndpointSliceReference.ClusterID
+	if r.SkipUnreachableClusters && !r.isClusterReachable(&svcImport.Status, clusterID) {
+		return true, "Unreachable", nil
+	}
+	if exporthealth.ShouldExclude(healthPolicy, &svcImport.Status, clusterID) {
+		return true, "Unhealthy", nil
 	}
-	return derivedSvc.DeletionTimestamp == nil, nil
+	return false, "", nil
+}
+
+// isClusterReachable returns if an exporting cluster is reachable from the member cluster, per its network
+// properties; the clusters whose network properties are unknown are considered reachable.
+func (r *Reconciler) isClusterReachable(status *fleetnetv1alpha1.ServiceImportStatus, clusterID string) bool {
+	for _, cluster := range status.Clusters {
+		if cluster.Cluster == clusterID {
+			return r.NetworkProperties.CanReach(cloudconfig.NetworkProperties{Region: cluster.Region, VNetID: cluster.VNetID})
+		}
+	}
+	return true
+}
+
+// recordSkippedCluster adds the origin cluster to, or removes it from, the skipped clusters in the status of the MCS
+// which claims the derived Service.
+func (r *Reconciler) recordSkippedCluster(ctx context.Context, multiClusterSvcList *fleetnetv1alpha1.MultiClusterServiceList, derivedSvcName, clusterID string, skipped bool) error {
+	for i := range multiClusterSvcList.Items {
+		multiClusterSvc := &multiClusterSvcList.Items[i]
+		if multiClusterSvc.DeletionTimestamp != nil ||
+			multiClusterSvc.Labels[objectmeta.MultiClusterServiceLabelDerivedService] != derivedSvcName {
+			continue
+		}
+
+		found := slices.Contains(multiClusterSvc.Status.SkippedClusters, clusterID)
+		if found == skipped {
+			return nil
+		}
+		if skipped {
+			multiClusterSvc.Status.SkippedClusters = append(multiClusterSvc.Status.SkippedClusters, clusterID)
+		} else {
+			multiClusterSvc.Status.SkippedClusters = slices.DeleteFunc(multiClusterSvc.Status.SkippedClusters, func(c string) bool {
+				return c == clusterID
+			})
+		}
+		klog.V(2).InfoS("Updating the skipped clusters of MCS", "multiClusterService", klog.KObj(multiClusterSvc),
+			"skippedClusters", multiClusterSvc.Status.SkippedClusters)
+		return r.MemberClient.Status().Update(ctx, multiClusterSvc)
+	}
+	return nil
 }
 
 // scanForDerivedServiceName scans a list of MCSes and returns the first found derived Service label in the list.
@@ -337,6 +726,148 @@ func scanForDerivedServiceName(multiClusterSvcList *fleetnetv1alpha1.MultiCluste
 	return derivedSvcName
 }
 
+// scanForClaimingMultiClusterService scans a list of MCSes and returns the MCS which claims the derived Service, or
+// nil if none does.
+func scanForClaimingMultiClusterService(multiClusterSvcList *fleetnetv1alpha1.MultiClusterServiceList, derivedSvcName string) *fleetnetv1alpha1.MultiClusterService {
+	for i := range multiClusterSvcList.Items {
+		multiClusterSvc := &multiClusterSvcList.Items[i]
+		if multiClusterSvc.DeletionTimestamp != nil {
+			continue
+		}
+		if svcName, ok := multiClusterSvc.Labels[objectmeta.MultiClusterServiceLabelDerivedService]; ok && svcName == derivedSvcName {
+			return multiClusterSvc
+		}
+	}
+	return nil
+}
+
+// isUnsupportedAddressTypeError returns if an EndpointSlice is rejected by the member cluster because of its address
+// type or the addresses of its endpoints, e.g. an IPv6 EndpointSlice imported into a cluster with IPv6 disabled.
+func isUnsupportedAddressTypeError(err error) bool {
+	var statusErr errors.APIStatus
+	if !errors.IsInvalid(err) || !stderrors.As(err, &statusErr) || statusErr.Status().Details == nil {
+		return false
+	}
+	for _, cause := range statusErr.Status().Details.Causes {
+		if cause.Field == "addressType" || strings.Contains(cause.Field, ".addresses") {
+			return true
+		}
+	}
+	return false
+}
+
+// updateImportedCondition reports on the EndpointSliceImport whether its endpoints are imported, given the error
+// which prevents them from being imported, if any.
+//
+// The condition is only added once the endpoints cannot be imported, and is set to true (rather than removed) when
+// they are imported later; a Warning event is emitted on the MCS which claims the derived Service whenever the
+// condition turns false.
+func (r *Reconciler) updateImportedCondition(ctx context.Context, endpointSliceImport *fleetnetv1alpha1.EndpointSliceImport, multiClusterSvc *fleetnetv1alpha1.MultiClusterService, importErr error) error {
+	condType := string(fleetnetv1alpha1.EndpointSliceImportImported)
+	importedCond := meta.FindStatusCondition(endpointSliceImport.Status.Conditions, condType)
+	if importErr == nil && importedCond == nil {
+		// The endpoints have never failed to be imported; no condition is needed.
+		return nil
+	}
+
+	expectedCond := metav1.Condition{
+		Type:               condType,
+		Status:             metav1.ConditionTrue,
+		ObservedGeneration: endpointSliceImport.Generation,
+		Reason:             importedCondReason,
+		Message:            fmt.Sprintf("endpoints are imported into member cluster %s", r.MemberClusterID),
+	}
+	if importErr != nil {
+		expectedCond = metav1.Condition{
+			Type:               condType,
+			Status:             metav1.ConditionFalse,
+			ObservedGeneration: endpointSliceImport.Generation,
+			Reason:             unsupportedAddressTypeCondReason,
+			Message: fmt.Sprintf("member cluster %s does not support the %s address type: %v",
+				r.MemberClusterID, endpointSliceImport.Spec.AddressType, importErr),
+		}
+	}
+	if condition.EqualCondition(importedCond, &expectedCond) {
+		// A stable state has been reached; no further action is needed.
+		return nil
+	}
+
+	if importErr != nil && multiClusterSvc != nil && (importedCond == nil || importedCond.Status != metav1.ConditionFalse) {
+		r.Recorder.Eventf(multiClusterSvc, corev1.EventTypeWarning, unsupportedAddressTypeCondReason,
+			"Endpoints of service %s from cluster %s cannot be imported, as the %s address type is not supported by this cluster",
+			endpointSliceImport.Spec.OwnerServiceReference.NamespacedName, endpointSliceImport.Spec.EndpointSliceReference.ClusterID,
+			endpointSliceImport.Spec.AddressType)
+	}
+	meta.SetStatusCondition(&endpointSliceImport.Status.Conditions, expectedCond)
+	return r.HubClient.Status().Update(ctx, endpointSliceImport)
+}
+
+// scanForPortFilter scans a list of MCSes and returns if the MCS which claims the derived Service restricts the
+// ports to import.
+func scanForPortFilter(multiClusterSvcList *fleetnetv1alpha1.MultiClusterServiceList, derivedSvcName string) bool {
+	for _, multiClusterSvc := range multiClusterSvcList.Items {
+		if multiClusterSvc.DeletionTimestamp != nil {
+			continue
+		}
+
+		if svcName, ok := multiClusterSvc.Labels[objectmeta.MultiClusterServiceLabelDerivedService]; ok && svcName == derivedSvcName {
+			return len(multiClusterSvc.Spec.Ports) != 0
+		}
+	}
+	return false
+}
+
+// filterEndpointPorts returns the EndpointSlice ports which have a matching port in the derived Service.
+// An EndpointSlice port matches a Service port if they share the same name, or if the port number of the
+// EndpointSlice port, which is the target port of the Service, and its protocol match those of the Service port; the
+// latter covers the EndpointSlices exported with their ports unnamed or named differently.
+func filterEndpointPorts(endpointPorts []discoveryv1.EndpointPort, svcPorts []corev1.ServicePort) []discoveryv1.EndpointPort {
+	filtered := make([]discoveryv1.EndpointPort, 0, len(endpointPorts))
+	for _, endpointPort := range endpointPorts {
+		for i := range svcPorts {
+			if endpointPortMatches(endpointPort, &svcPorts[i]) {
+				filtered = append(filtered, endpointPort)
+				break
+			}
+		}
+	}
+	return filtered
+}
+
+// endpointPortMatches returns if an EndpointSlice port matches a Service port, by name or by port number and protocol.
+func endpointPortMatches(endpointPort discoveryv1.EndpointPort, svcPort *corev1.ServicePort) bool {
+	var name string
+	if endpointPort.Name != nil {
+		name = *endpointPort.Name
+	}
+	if name == svcPort.Name {
+		return true
+	}
+	if endpointPort.Port == nil {
+		return false
+	}
+	endpointProtocol, svcProtocol := corev1.ProtocolTCP, corev1.ProtocolTCP
+	if endpointPort.Protocol != nil {
+		endpointProtocol = *endpointPort.Protocol
+	}
+	if svcPort.Protocol != "" {
+		svcProtocol = svcPort.Protocol
+	}
+	if endpointProtocol != svcProtocol {
+		return false
+	}
+	switch {
+	case svcPort.TargetPort.Type == intstr.Int && svcPort.TargetPort.IntVal != 0:
+		return svcPort.TargetPort.IntVal == *endpointPort.Port
+	case svcPort.TargetPort.Type == intstr.String && svcPort.TargetPort.StrVal != "":
+		// A named target port is resolved per pod and cannot be compared with a port number.
+		return false
+	default:
+		// The target port defaults to the port of the Service.
+		return svcPort.Port == *endpointPort.Port
+	}
+}
+
 // formatEndpointSliceFromImport formats an EndpointSlice using an EndpointSliceImport.
 func formatEndpointSliceFromImport(endpointSlice *discoveryv1.EndpointSlice, derivedSvcName string, endpointSliceImport *fleetnetv1alpha1.EndpointSliceImport) {
 	endpointSlice.AddressType = endpointSliceImport.Spec.AddressType
@@ -348,13 +879,32 @@ func formatEndpointSliceFromImport(endpointSlice *discoveryv1.EndpointSlice, der
 
 	endpoints := []discoveryv1.Endpoint{}
 	for _, importedEndpoint := range endpointSliceImport.Spec.Endpoints {
-		endpoints = append(endpoints, discoveryv1.Endpoint{
-			Addresses: importedEndpoint.Addresses,
-		})
+		endpoints = append(endpoints, toImportedEndpoint(importedEndpoint))
 	}
 	endpointSlice.Endpoints = endpoints
 }
 
+// toImportedEndpoint returns the endpoint of an imported EndpointSlice; the node name is kept when available, so
+// that the derived Service can use the Local internal traffic policy.
+//
+// A terminating endpoint is imported as not ready, with its serving and terminating conditions, so that only the
+// consumers which drain the terminating endpoints gracefully keep sending it traffic; the conditions of the other
+// endpoints are left unset, i.e., ready.
+func toImportedEndpoint(endpoint fleetnetv1alpha1.Endpoint) discoveryv1.Endpoint {
+	importedEndpoint := discoveryv1.Endpoint{
+		Addresses: endpoint.Addresses,
+		NodeName:  endpoint.NodeName,
+	}
+	if ptr.Deref(endpoint.Terminating, false) {
+		importedEndpoint.Conditions = discoveryv1.EndpointConditions{
+			Ready:       ptr.To(false),
+			Serving:     ptr.To(ptr.Deref(endpoint.Serving, false)),
+			Terminating: ptr.To(true),
+		}
+	}
+	return importedEndpoint
+}
+
 // Observe data points for metrics.
 func (r *Reconciler) observeMetrics(ctx context.Context, endpointSliceImport *fleetnetv1alpha1.EndpointSliceImport, startTime time.Time) error {
 	// Check if a metric data point has been observed for the current generation of the object; this helps guard
@@ -396,27 +946,20 @@ func (r *Reconciler) observeMetrics(ctx context.Context, endpointSliceImport *fl
 			"endpointSliceImport", klog.KObj(endpointSliceImport))
 		return nil
 	}
-	timeSpent := startTime.Sub(endpointSliceImport.Spec.EndpointSliceReference.ExportedSince.Time).Milliseconds()
+	duration := startTime.Sub(endpointSliceImport.Spec.EndpointSliceReference.ExportedSince.Time)
 	// Under some rare circumstances (such as time sync not being configured properly across clusters), it could
 	// happen that the export timestamp of an EndpointSlice appears later than its import timestamp. Unfortunately,
-	// clock discrepancies are out of Fleet networking's control and there is not an easy way to determine how
-	// much clocks drift from each other and if (when) it will recover. To avoid negative outliers affecting
-	// data analysis, this controller assigns a constant of exactly 1 second when the calculated duration does
-	// not make sense.
-	if timeSpent <= 0 {
-		timeSpent = time.Second.Milliseconds() * 1
-		klog.V(4).Info("A negative endpointSlice export/import duration data point has been observed; time sync might be out of order",
+	// clock discrepancies are out of Fleet networking's control; the skew of each member cluster against the hub
+	// cluster is reported by the fleet_networking_member_clock_skew_seconds metric, and the duration is clamped.
+	if duration <= 0 {
+		klog.V(4).InfoS("A negative endpointSlice export/import duration data point has been observed; time sync might be out of order",
 			"serviceNamespacedName", endpointSliceImport.Spec.OwnerServiceReference.NamespacedName,
 			"endpointSliceNamespacedName", endpointSliceImport.Spec.EndpointSliceReference.NamespacedName,
 			"originClusterID", endpointSliceImport.Spec.EndpointSliceReference.ClusterID,
 			"destinationClusterID", r.MemberClusterID,
 			"isFirstImport", isFirstImport)
 	}
-	// Similarly, to avoid large outliers skewing the stats (e.g. averages), this controller caps the data point
-	// to a constant value.
-	if timeSpent > int64(metrics.ExportDurationRightBound) {
-		timeSpent = int64(metrics.ExportDurationRightBound)
-	}
+	timeSpent := metrics.ClampExportDuration(duration).Milliseconds()
 	endpointSliceExportImportDuration.
 		WithLabelValues(endpointSliceImport.Spec.EndpointSliceReference.ClusterID, r.MemberClusterID, fmt.Sprintf("%t", isFirstImport)).
 		Observe(float64(timeSpent))
@@ -11,6 +11,7 @@ import (
 	"time"
 
 	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 	corev1 "k8s.io/api/core/v1"
@@ -18,6 +19,7 @@ import (
 	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/intstr"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	fleetnetv1alpha1 "go.goms.io/fleet-networking/api/v1alpha1"
@@ -378,6 +380,82 @@ var _ = Describe("endpointsliceimport controller", func() {
 		})
 	})
 
+	Context("import endpointslice with a port filter", func() {
+		var (
+			endpointSliceImport *fleetnetv1alpha1.EndpointSliceImport
+			multiClusterSvc     *fleetnetv1alpha1.MultiClusterService
+			derivedSvc          *corev1.Service
+		)
+
+		// endpointSlicePortsActual returns a function which runs with Eventually assertion to make sure that the
+		// imported EndpointSlice has the given ports.
+		endpointSlicePortsActual := func(wantPorts []discoveryv1.EndpointPort) func() error {
+			return func() error {
+				endpointSlice := &discoveryv1.EndpointSlice{}
+				if err := memberClient.Get(ctx, endpointSliceKey, endpointSlice); err != nil {
+					return fmt.Errorf("endpointSlice Get(%+v), got %w, want no error", endpointSliceKey, err)
+				}
+				if diff := cmp.Diff(endpointSlice.Ports, wantPorts); diff != "" {
+					return fmt.Errorf("endpointSlice ports (-got, +want): %s", diff)
+				}
+				return nil
+			}
+		}
+
+		BeforeEach(func() {
+			multiClusterSvc = fulfilledMultiClusterSvc()
+			multiClusterSvc.Spec.Ports = []intstr.IntOrString{intstr.FromString(tcpPortName)}
+			Expect(memberClient.Create(ctx, multiClusterSvc)).Should(Succeed())
+
+			// The MCS controller restricts the derived Service to the requested ports.
+			derivedSvc = svcDerivedByMultiClusterSvc()
+			derivedSvc.Spec.Ports = derivedSvc.Spec.Ports[1:]
+			Expect(memberClient.Create(ctx, derivedSvc)).Should(Succeed())
+
+			endpointSliceImport = ipv4EndpointSliceImport()
+			endpointSliceImport.Spec.OwnerServiceReference.NamespacedName = types.NamespacedName{Namespace: memberUserNS, Name: svcName}.String()
+			Expect(hubClient.Create(ctx, endpointSliceImport)).Should(Succeed())
+		})
+
+		AfterEach(func() {
+			Expect(hubClient.Delete(ctx, endpointSliceImport)).Should(Succeed())
+			Expect(memberClient.Delete(ctx, derivedSvc)).Should(Succeed())
+			Expect(memberClient.Delete(ctx, multiClusterSvc)).Should(Succeed())
+
+			// Confirm that all created objects have been deleted; this helps make the test less flaky.
+			Eventually(endpointSliceImportIsAbsentActual, eventuallyTimeout, eventuallyInterval).Should(BeNil())
+			Eventually(multiClusterServiceIsAbsentActual, eventuallyTimeout, eventuallyInterval).Should(BeNil())
+			Eventually(derivedServiceIsAbsentActual, eventuallyTimeout, eventuallyInterval).Should(BeNil())
+
+			// Make sure that all imported EndpointSlices are removed.
+			Eventually(endpointSliceIsNotImportedActual, eventuallyTimeout, eventuallyInterval).Should(BeNil())
+		})
+
+		It("should import the requested ports and follow filter changes", func() {
+			Eventually(endpointSliceImportIsProcessedActual, eventuallyTimeout, eventuallyInterval).Should(BeNil())
+			allPorts := importedIPv4EndpointSlice().Ports
+			Eventually(endpointSlicePortsActual(allPorts[1:]), eventuallyTimeout, eventuallyInterval).Should(BeNil())
+
+			By("requesting another port")
+			Expect(memberClient.Get(ctx, multiClusterSvcKey, multiClusterSvc)).Should(Succeed())
+			multiClusterSvc.Spec.Ports = []intstr.IntOrString{intstr.FromString(httpPortName)}
+			Expect(memberClient.Update(ctx, multiClusterSvc)).Should(Succeed())
+			Expect(memberClient.Get(ctx, derivedSvcKey, derivedSvc)).Should(Succeed())
+			derivedSvc.Spec.Ports = svcDerivedByMultiClusterSvc().Spec.Ports[:1]
+			Expect(memberClient.Update(ctx, derivedSvc)).Should(Succeed())
+			Eventually(endpointSlicePortsActual(allPorts[:1]), eventuallyTimeout, eventuallyInterval).Should(BeNil())
+
+			By("removing the port filter")
+			Expect(memberClient.Get(ctx, multiClusterSvcKey, multiClusterSvc)).Should(Succeed())
+			multiClusterSvc.Spec.Ports = nil
+			Expect(memberClient.Update(ctx, multiClusterSvc)).Should(Succeed())
+			Expect(memberClient.Get(ctx, derivedSvcKey, derivedSvc)).Should(Succeed())
+			derivedSvc.Spec.Ports = svcDerivedByMultiClusterSvc().Spec.Ports
+			Expect(memberClient.Update(ctx, derivedSvc)).Should(Succeed())
+			Eventually(endpointSlicePortsActual(allPorts), eventuallyTimeout, eventuallyInterval).Should(BeNil())
+		})
+	})
+
 	// This test is expected to fail in Kubernetes versions earlier than 1.24, as hybrid protocol service support
 	// has not yet been enabled by default.
 	Context("import endpointslice (hybrid protocol)", func() {
@@ -517,4 +595,114 @@ var _ = Describe("endpointsliceimport controller", func() {
 			}, eventuallyTimeout, eventuallyInterval).Should(BeNil())
 		})
 	})
+
+	Context("import endpointslices from many small source endpointslices", func() {
+		const (
+			sourceEndpointSliceCount = 20
+			endpointsPerSourceSlice  = 10
+		)
+
+		var (
+			endpointSliceImports []*fleetnetv1alpha1.EndpointSliceImport
+			multiClusterSvc      *fleetnetv1alpha1.MultiClusterService
+			derivedSvc           *corev1.Service
+		)
+
+		// importedEndpointSlicesActual returns a function which runs with Eventually assertion to make sure that the
+		// endpoints of the given imports are packed into a single EndpointSlice.
+		importedEndpointSlicesActual := func(imports []*fleetnetv1alpha1.EndpointSliceImport) func() error {
+			return func() error {
+				endpointSliceList := &discoveryv1.EndpointSliceList{}
+				if err := memberClient.List(ctx, endpointSliceList, client.InNamespace(fleetSystemNS)); err != nil {
+					return fmt.Errorf("endpointSlice List(), got %w, want no error", err)
+				}
+				if len(endpointSliceList.Items) != 1 {
+					return fmt.Errorf("endpointSlices, got %d, want 1", len(endpointSliceList.Items))
+				}
+				endpointSlice := endpointSliceList.Items[0]
+
+				wantImportNames := make([]string, 0, len(imports))
+				wantAddresses := make([]string, 0, len(imports)*endpointsPerSourceSlice)
+				for _, endpointSliceImport := range imports {
+					wantImportNames = append(wantImportNames, endpointSliceImport.Name)
+					for _, endpoint := range endpointSliceImport.Spec.Endpoints {
+						wantAddresses = append(wantAddresses, endpoint.Addresses...)
+					}
+				}
+				gotImportNames := strings.Split(endpointSlice.Annotations[objectmeta.EndpointSliceAnnotationImports], ",")
+				if diff := cmp.Diff(wantImportNames, gotImportNames, cmpopts.SortSlices(func(a, b string) bool { return a < b })); diff != "" {
+					return fmt.Errorf("endpointSlice imports (-want, +got): %s", diff)
+				}
+				gotAddresses := make([]string, 0, len(endpointSlice.Endpoints))
+				for _, endpoint := range endpointSlice.Endpoints {
+					gotAddresses = append(gotAddresses, endpoint.Addresses...)
+				}
+				if diff := cmp.Diff(wantAddresses, gotAddresses, cmpopts.SortSlices(func(a, b string) bool { return a < b })); diff != "" {
+					return fmt.Errorf("endpointSlice addresses (-want, +got): %s", diff)
+				}
+				if diff := cmp.Diff(endpointSlice.Ports, importedIPv4EndpointSlice().Ports); diff != "" {
+					return fmt.Errorf("endpointSlice ports (-got, +want): %s", diff)
+				}
+				return nil
+			}
+		}
+
+		BeforeEach(func() {
+			multiClusterSvc = fulfilledMultiClusterSvc()
+			Expect(memberClient.Create(ctx, multiClusterSvc)).Should(Succeed())
+
+			derivedSvc = svcDerivedByMultiClusterSvc()
+			Expect(memberClient.Create(ctx, derivedSvc)).Should(Succeed())
+
+			endpointSliceImports = nil
+			for i := 0; i < sourceEndpointSliceCount; i++ {
+				endpointSliceImport := ipv4EndpointSliceImport()
+				endpointSliceImport.Name = fmt.Sprintf("%s-%02d", endpointSliceImportName, i)
+				endpointSliceImport.Spec.OwnerServiceReference.NamespacedName = types.NamespacedName{Namespace: memberUserNS, Name: svcName}.String()
+				endpointSliceImport.Spec.Endpoints = nil
+				for j := 0; j < endpointsPerSourceSlice; j++ {
+					endpointSliceImport.Spec.Endpoints = append(endpointSliceImport.Spec.Endpoints, fleetnetv1alpha1.Endpoint{
+						Addresses: []string{fmt.Sprintf("10.0.%d.%d", i, j+1)},
+					})
+				}
+				Expect(hubClient.Create(ctx, endpointSliceImport)).Should(Succeed())
+				endpointSliceImports = append(endpointSliceImports, endpointSliceImport)
+			}
+		})
+
+		AfterEach(func() {
+			for _, endpointSliceImport := range endpointSliceImports {
+				Expect(client.IgnoreNotFound(hubClient.Delete(ctx, endpointSliceImport))).Should(Succeed())
+			}
+			Expect(memberClient.Delete(ctx, derivedSvc)).Should(Succeed())
+			Expect(memberClient.Delete(ctx, multiClusterSvc)).Should(Succeed())
+
+			// Confirm that all created objects have been deleted; this helps make the test less flaky.
+			Eventually(func() error {
+				endpointSliceImportList := &fleetnetv1alpha1.EndpointSliceImportList{}
+				if err := hubClient.List(ctx, endpointSliceImportList, client.InNamespace(hubNSForMember)); err != nil {
+					return fmt.Errorf("endpointSliceImport List(), got %w, want no error", err)
+				}
+				if len(endpointSliceImportList.Items) != 0 {
+					return fmt.Errorf("endpointSliceImports, got %d, want 0", len(endpointSliceImportList.Items))
+				}
+				return nil
+			}, eventuallyTimeout, eventuallyInterval).Should(BeNil())
+			Eventually(multiClusterServiceIsAbsentActual, eventuallyTimeout, eventuallyInterval).Should(BeNil())
+			Eventually(derivedServiceIsAbsentActual, eventuallyTimeout, eventuallyInterval).Should(BeNil())
+
+			// Make sure that all imported EndpointSlices are removed.
+			Eventually(endpointSliceIsNotImportedActual, eventuallyTimeout, eventuallyInterval).Should(BeNil())
+		})
+
+		It("should pack the endpoints into a single endpointslice and follow the removal of source endpointslices", func() {
+			Eventually(importedEndpointSlicesActual(endpointSliceImports), eventuallyTimeout, eventuallyInterval).Should(BeNil())
+
+			By("removing half of the source endpointslices")
+			for _, endpointSliceImport := range endpointSliceImports[:sourceEndpointSliceCount/2] {
+				Expect(hubClient.Delete(ctx, endpointSliceImport)).Should(Succeed())
+			}
+			Eventually(importedEndpointSlicesActual(endpointSliceImports[sourceEndpointSliceCount/2:]), eventuallyTimeout, eventuallyInterval).Should(BeNil())
+		})
+	})
 })
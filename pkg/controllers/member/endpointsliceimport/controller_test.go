@@ -15,15 +15,26 @@ import (
 	"time"
 
 	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
 	"github.com/prometheus/client_golang/prometheus/testutil"
 	corev1 "k8s.io/api/core/v1"
 	discoveryv1 "k8s.io/api/discovery/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/apimachinery/pkg/util/validation/field"
 	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/ptr"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
 
 	fleetnetv1alpha1 "go.goms.io/fleet-networking/api/v1alpha1"
+	"go.goms.io/fleet-networking/pkg/common/cloudconfig"
 	"go.goms.io/fleet-networking/pkg/common/metrics"
 	"go.goms.io/fleet-networking/pkg/common/objectmeta"
 )
@@ -33,6 +44,7 @@ const (
 	hubNSForMember                 = "bravelion"
 	memberUserNS                   = "work"
 	fleetSystemNS                  = "fleet-system"
+	customFleetSystemNS            = "custom-fleet-system"
 	svcName                        = "app"
 	derivedSvcName                 = "work-app-1d2ef"
 	endpointSliceName              = "app-endpointslice"
@@ -235,6 +247,154 @@ func TestScanForDerivedServiceName(t *testing.T) {
 	}
 }
 
+// TestScanForPortFilter tests the scanForPortFilter function.
+func TestScanForPortFilter(t *testing.T) {
+	multiClusterSvc := func(name, derivedSvcName string, ports ...intstr.IntOrString) fleetnetv1alpha1.MultiClusterService {
+		multiClusterSvc := fleetnetv1alpha1.MultiClusterService{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: memberUserNS,
+				Name:      name,
+			},
+			Spec: fleetnetv1alpha1.MultiClusterServiceSpec{
+				Ports: ports,
+			},
+		}
+		if derivedSvcName != "" {
+			multiClusterSvc.Labels = map[string]string{
+				objectmeta.MultiClusterServiceLabelDerivedService: derivedSvcName,
+			}
+		}
+		return multiClusterSvc
+	}
+
+	testCases := []struct {
+		name                string
+		multiClusterSvcList *fleetnetv1alpha1.MultiClusterServiceList
+		want                bool
+	}{
+		{
+			name: "mcs claiming the derived svc has no port filter",
+			multiClusterSvcList: &fleetnetv1alpha1.MultiClusterServiceList{
+				Items: []fleetnetv1alpha1.MultiClusterService{
+					multiClusterSvc("mcs-1", "", intstr.FromString(httpPortName)),
+					multiClusterSvc("mcs-2", derivedSvcName),
+				},
+			},
+		},
+		{
+			name: "mcs claiming the derived svc has a port filter",
+			multiClusterSvcList: &fleetnetv1alpha1.MultiClusterServiceList{
+				Items: []fleetnetv1alpha1.MultiClusterService{
+					multiClusterSvc("mcs-1", ""),
+					multiClusterSvc("mcs-2", derivedSvcName, intstr.FromString(httpPortName)),
+				},
+			},
+			want: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := scanForPortFilter(tc.multiClusterSvcList, derivedSvcName); got != tc.want {
+				t.Fatalf("scanForPortFilter() = %t, want %t", got, tc.want)
+			}
+		})
+	}
+}
+
+// TestFilterEndpointPorts tests the filterEndpointPorts function.
+func TestFilterEndpointPorts(t *testing.T) {
+	endpointPorts := ipv4EndpointSliceImport().Spec.Ports
+
+	testCases := []struct {
+		name          string
+		endpointPorts []discoveryv1.EndpointPort
+		svcPorts      []corev1.ServicePort
+		want          []discoveryv1.EndpointPort
+	}{
+		{
+			name:          "all ports are exposed",
+			endpointPorts: endpointPorts,
+			svcPorts:      svcDerivedByMultiClusterSvc().Spec.Ports,
+			want:          endpointPorts,
+		},
+		{
+			name:          "subset of ports are exposed",
+			endpointPorts: endpointPorts,
+			svcPorts:      svcDerivedByMultiClusterSvc().Spec.Ports[1:],
+			want:          endpointPorts[1:],
+		},
+		{
+			name: "unnamed port",
+			endpointPorts: []discoveryv1.EndpointPort{
+				{
+					Protocol: &tcpPortProtocol,
+					Port:     &tcpPort,
+				},
+			},
+			svcPorts: []corev1.ServicePort{
+				{
+					Protocol: tcpPortProtocol,
+					Port:     tcpPort,
+				},
+			},
+			want: []discoveryv1.EndpointPort{
+				{
+					Protocol: &tcpPortProtocol,
+					Port:     &tcpPort,
+				},
+			},
+		},
+		{
+			name:          "ports matched by target port and protocol",
+			endpointPorts: endpointPorts,
+			svcPorts: []corev1.ServicePort{
+				{
+					Name:       "renamed",
+					Protocol:   tcpPortProtocol,
+					Port:       8081,
+					TargetPort: intstr.FromInt32(tcpPort),
+				},
+			},
+			want: endpointPorts[1:],
+		},
+		{
+			name:          "ports with the same number but a different protocol",
+			endpointPorts: endpointPorts,
+			svcPorts: []corev1.ServicePort{
+				{
+					Name:     "renamed",
+					Protocol: udpPortProtocol,
+					Port:     tcpPort,
+				},
+			},
+			want: []discoveryv1.EndpointPort{},
+		},
+		{
+			name:          "named target port",
+			endpointPorts: endpointPorts,
+			svcPorts: []corev1.ServicePort{
+				{
+					Name:       "renamed",
+					Protocol:   tcpPortProtocol,
+					Port:       tcpPort,
+					TargetPort: intstr.FromString(tcpPortName),
+				},
+			},
+			want: []discoveryv1.EndpointPort{},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got := filterEndpointPorts(tc.endpointPorts, tc.svcPorts)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Fatalf("filterEndpointPorts() mismatch (-want, +got):\n%s", diff)
+			}
+		})
+	}
+}
+
 // TestFormatEndpointSliceFromImport tests the formatEndpointSliceFromImport function.
 func TestFormatEndpointSliceFromImport(t *testing.T) {
 	testCases := []struct {
@@ -247,6 +407,37 @@ func TestFormatEndpointSliceFromImport(t *testing.T) {
 			endpointSliceImport: ipv4EndpointSliceImport(),
 			want:                importedIPv4EndpointSlice(),
 		},
+		{
+			name: "should keep the node names of the imported endpoints",
+			endpointSliceImport: func() *fleetnetv1alpha1.EndpointSliceImport {
+				endpointSliceImport := ipv4EndpointSliceImport()
+				endpointSliceImport.Spec.Endpoints[0].NodeName = ptr.To("node-1")
+				return endpointSliceImport
+			}(),
+			want: func() *discoveryv1.EndpointSlice {
+				endpointSlice := importedIPv4EndpointSlice()
+				endpointSlice.Endpoints[0].NodeName = ptr.To("node-1")
+				return endpointSlice
+			}(),
+		},
+		{
+			name: "should import the terminating endpoints as not ready",
+			endpointSliceImport: func() *fleetnetv1alpha1.EndpointSliceImport {
+				endpointSliceImport := ipv4EndpointSliceImport()
+				endpointSliceImport.Spec.Endpoints[0].Serving = ptr.To(true)
+				endpointSliceImport.Spec.Endpoints[0].Terminating = ptr.To(true)
+				return endpointSliceImport
+			}(),
+			want: func() *discoveryv1.EndpointSlice {
+				endpointSlice := importedIPv4EndpointSlice()
+				endpointSlice.Endpoints[0].Conditions = discoveryv1.EndpointConditions{
+					Ready:       ptr.To(false),
+					Serving:     ptr.To(true),
+					Terminating: ptr.To(true),
+				}
+				return endpointSlice
+			}(),
+		},
 	}
 
 	for _, tc := range testCases {
@@ -266,8 +457,8 @@ func TestFormatEndpointSliceFromImport(t *testing.T) {
 	}
 }
 
-// TestIsDerivedServiceValid tests the isDerivedServiceValid function.
-func TestIsDerivedServiceValid(t *testing.T) {
+// TestGetValidDerivedService tests the getValidDerivedService function.
+func TestGetValidDerivedService(t *testing.T) {
 	deletionTimestamp := metav1.Now()
 
 	testCases := []struct {
@@ -275,6 +466,8 @@ func TestIsDerivedServiceValid(t *testing.T) {
 		derivedSvcName string
 		derivedSvc     *corev1.Service
 		want           bool
+		// fleetSystemNamespace is the reserved namespace of the reconciler; it defaults to fleetSystemNS.
+		fleetSystemNamespace string
 	}{
 		{
 			name:           "derived svc is valid",
@@ -314,12 +507,40 @@ func TestIsDerivedServiceValid(t *testing.T) {
 			},
 			want: false,
 		},
+		{
+			name:           "derived svc is valid (custom reserved namespace)",
+			derivedSvcName: derivedSvcName,
+			derivedSvc: &corev1.Service{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: customFleetSystemNS,
+					Name:      derivedSvcName,
+				},
+			},
+			fleetSystemNamespace: customFleetSystemNS,
+			want:                 true,
+		},
+		{
+			name:           "derived svc is invalid (svc in the default reserved namespace)",
+			derivedSvcName: derivedSvcName,
+			derivedSvc: &corev1.Service{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: fleetSystemNS,
+					Name:      derivedSvcName,
+				},
+			},
+			fleetSystemNamespace: customFleetSystemNS,
+			want:                 false,
+		},
 	}
 
 	ctx := context.Background()
 
 	for _, tc := range testCases {
 		t.Run(tc.name, func(t *testing.T) {
+			fleetSystemNamespace := tc.fleetSystemNamespace
+			if fleetSystemNamespace == "" {
+				fleetSystemNamespace = fleetSystemNS
+			}
 			fakeMemberClientBuilder := fake.NewClientBuilder().WithScheme(scheme.Scheme)
 			if tc.derivedSvc != nil {
 				fakeMemberClientBuilder = fakeMemberClientBuilder.WithObjects(tc.derivedSvc)
@@ -329,11 +550,15 @@ func TestIsDerivedServiceValid(t *testing.T) {
 			reconciler := Reconciler{
 				MemberClient:         fakeMemberClient,
 				HubClient:            fakeHubClient,
-				FleetSystemNamespace: fleetSystemNS,
+				FleetSystemNamespace: fleetSystemNamespace,
 			}
 
-			if got, err := reconciler.isDerivedServiceValid(ctx, tc.derivedSvcName); got != tc.want || err != nil {
-				t.Fatalf("isDerivedServiceValid(%+v) = %t, %v, want %t, no error", tc.derivedSvcName, got, err, tc.want)
+			got, err := reconciler.getValidDerivedService(ctx, tc.derivedSvcName)
+			if err != nil {
+				t.Fatalf("getValidDerivedService(%+v) got error %v, want no error", tc.derivedSvcName, err)
+			}
+			if isValid := got != nil; isValid != tc.want {
+				t.Fatalf("getValidDerivedService(%+v) = %v, want valid %t", tc.derivedSvcName, got, tc.want)
 			}
 		})
 	}
@@ -720,3 +945,447 @@ func TestObserveMetrics(t *testing.T) {
 		})
 	}
 }
+
+// TestReconcile_SkipUnreachableClusters tests that the endpoints exported from unreachable clusters are not imported
+// only when the member agent is configured to skip them.
+func TestReconcile_SkipUnreachableClusters(t *testing.T) {
+	svcImport := &fleetnetv1alpha1.ServiceImport{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: memberUserNS,
+			Name:      svcName,
+		},
+		Status: fleetnetv1alpha1.ServiceImportStatus{
+			Clusters: []fleetnetv1alpha1.ClusterStatus{
+				{Cluster: hubNSForMember, Region: "eastus"},
+			},
+		},
+	}
+	testCases := []struct {
+		name                    string
+		skipUnreachableClusters bool
+		localRegion             string
+		skippedClusters         []string
+		wantImported            bool
+		wantSkippedClusters     []string
+	}{
+		{
+			name:                    "flag off, unreachable cluster",
+			skipUnreachableClusters: false,
+			localRegion:             "westus",
+			wantImported:            true,
+		},
+		{
+			name:                    "flag off, previously skipped cluster",
+			skipUnreachableClusters: false,
+			localRegion:             "westus",
+			skippedClusters:         []string{hubNSForMember},
+			wantImported:            true,
+		},
+		{
+			name:                    "flag on, reachable cluster",
+			skipUnreachableClusters: true,
+			localRegion:             "eastus",
+			wantImported:            true,
+		},
+		{
+			name:                    "flag on, unknown local network properties",
+			skipUnreachableClusters: true,
+			wantImported:            true,
+		},
+		{
+			name:                    "flag on, unreachable cluster",
+			skipUnreachableClusters: true,
+			localRegion:             "westus",
+			wantImported:            false,
+			wantSkippedClusters:     []string{hubNSForMember},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx := context.Background()
+			multiClusterSvc := fulfilledMultiClusterSvc()
+			multiClusterSvc.Status.SkippedClusters = tc.skippedClusters
+			fakeMemberClient := fake.NewClientBuilder().
+				WithScheme(scheme.Scheme).
+				WithObjects(multiClusterSvc, svcDerivedByMultiClusterSvc(), svcImport).
+				WithStatusSubresource(multiClusterSvc).
+				WithIndex(&fleetnetv1alpha1.MultiClusterService{}, mcsServiceImportRefFieldKey, func(o client.Object) []string {
+					return []string{o.(*fleetnetv1alpha1.MultiClusterService).Spec.ServiceImport.Name}
+				}).
+				Build()
+			fakeHubClient := newFakeHubClient(ipv4EndpointSliceImport())
+			reconciler := Reconciler{
+				MemberClusterID:         memberClusterID,
+				MemberClient:            fakeMemberClient,
+				HubClient:               fakeHubClient,
+				FleetSystemNamespace:    fleetSystemNS,
+				NetworkProperties:       cloudconfig.NetworkProperties{Region: tc.localRegion},
+				SkipUnreachableClusters: tc.skipUnreachableClusters,
+			}
+
+			if _, err := reconciler.Reconcile(ctx, ctrl.Request{NamespacedName: endpointSliceImportKey}); err != nil {
+				t.Fatalf("Reconcile(), got %v, want no error", err)
+			}
+
+			endpointSliceList := &discoveryv1.EndpointSliceList{}
+			if err := fakeMemberClient.List(ctx, endpointSliceList, client.InNamespace(fleetSystemNS)); err != nil {
+				t.Fatalf("endpointSlice List(), got %v, want no error", err)
+			}
+			if got := len(endpointSliceList.Items) != 0; got != tc.wantImported {
+				t.Errorf("endpointSlice imported = %v, want %v", got, tc.wantImported)
+			}
+
+			gotMultiClusterSvc := &fleetnetv1alpha1.MultiClusterService{}
+			if err := fakeMemberClient.Get(ctx, types.NamespacedName{Namespace: memberUserNS, Name: multiClusterSvcName}, gotMultiClusterSvc); err != nil {
+				t.Fatalf("multiClusterService Get(), got %v, want no error", err)
+			}
+			if diff := cmp.Diff(tc.wantSkippedClusters, gotMultiClusterSvc.Status.SkippedClusters, cmpopts.EquateEmpty()); diff != "" {
+				t.Errorf("skipped clusters mismatch (-want, +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+// TestReconcile_ExporterHealthPolicy tests that the endpoints exported from a cluster reporting the Service as
+// Unhealthy are not imported only when the MCS excludes the unhealthy clusters and another cluster is not Unhealthy.
+func TestReconcile_ExporterHealthPolicy(t *testing.T) {
+	unhealthy := fleetnetv1alpha1.ExportHealth{State: fleetnetv1alpha1.ExportUnhealthy, Message: "503 Service Unavailable"}
+	healthy := fleetnetv1alpha1.ExportHealth{State: fleetnetv1alpha1.ExportHealthy, Score: 100}
+	testCases := []struct {
+		name                string
+		policy              fleetnetv1alpha1.ExporterHealthPolicy
+		clusterHealth       []fleetnetv1alpha1.ClusterExportHealth
+		skippedClusters     []string
+		wantImported        bool
+		wantSkippedClusters []string
+	}{
+		{
+			name:   "ignore policy, unhealthy cluster",
+			policy: fleetnetv1alpha1.ExporterHealthPolicyIgnore,
+			clusterHealth: []fleetnetv1alpha1.ClusterExportHealth{
+				{Cluster: hubNSForMember, Health: unhealthy},
+				{Cluster: "member-2", Health: healthy},
+			},
+			wantImported: true,
+		},
+		{
+			name:   "exclude policy, healthy cluster",
+			policy: fleetnetv1alpha1.ExporterHealthPolicyExcludeUnhealthy,
+			clusterHealth: []fleetnetv1alpha1.ClusterExportHealth{
+				{Cluster: hubNSForMember, Health: healthy},
+			},
+			wantImported: true,
+		},
+		{
+			name:   "exclude policy, unhealthy cluster",
+			policy: fleetnetv1alpha1.ExporterHealthPolicyExcludeUnhealthy,
+			clusterHealth: []fleetnetv1alpha1.ClusterExportHealth{
+				{Cluster: hubNSForMember, Health: unhealthy},
+				{Cluster: "member-2", Health: healthy},
+			},
+			wantImported:        false,
+			wantSkippedClusters: []string{hubNSForMember},
+		},
+		{
+			name:   "exclude policy, all clusters unhealthy",
+			policy: fleetnetv1alpha1.ExporterHealthPolicyExcludeUnhealthy,
+			clusterHealth: []fleetnetv1alpha1.ClusterExportHealth{
+				{Cluster: hubNSForMember, Health: unhealthy},
+				{Cluster: "member-2", Health: unhealthy},
+			},
+			wantImported: true,
+		},
+		{
+			name:   "exclude policy, previously skipped cluster which has recovered",
+			policy: fleetnetv1alpha1.ExporterHealthPolicyExcludeUnhealthy,
+			clusterHealth: []fleetnetv1alpha1.ClusterExportHealth{
+				{Cluster: hubNSForMember, Health: healthy},
+			},
+			skippedClusters: []string{hubNSForMember},
+			wantImported:    true,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx := context.Background()
+			svcImport := &fleetnetv1alpha1.ServiceImport{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: memberUserNS,
+					Name:      svcName,
+				},
+				Status: fleetnetv1alpha1.ServiceImportStatus{
+					Clusters: []fleetnetv1alpha1.ClusterStatus{
+						{Cluster: hubNSForMember},
+						{Cluster: "member-2"},
+					},
+					ClusterHealth: tc.clusterHealth,
+				},
+			}
+			multiClusterSvc := fulfilledMultiClusterSvc()
+			multiClusterSvc.Spec.ExporterHealthPolicy = tc.policy
+			multiClusterSvc.Status.SkippedClusters = tc.skippedClusters
+			fakeMemberClient := fake.NewClientBuilder().
+				WithScheme(scheme.Scheme).
+				WithObjects(multiClusterSvc, svcDerivedByMultiClusterSvc(), svcImport).
+				WithStatusSubresource(multiClusterSvc).
+				WithIndex(&fleetnetv1alpha1.MultiClusterService{}, mcsServiceImportRefFieldKey, func(o client.Object) []string {
+					return []string{o.(*fleetnetv1alpha1.MultiClusterService).Spec.ServiceImport.Name}
+				}).
+				Build()
+			fakeHubClient := newFakeHubClient(ipv4EndpointSliceImport())
+			reconciler := Reconciler{
+				MemberClusterID:      memberClusterID,
+				MemberClient:         fakeMemberClient,
+				HubClient:            fakeHubClient,
+				FleetSystemNamespace: fleetSystemNS,
+			}
+
+			if _, err := reconciler.Reconcile(ctx, ctrl.Request{NamespacedName: endpointSliceImportKey}); err != nil {
+				t.Fatalf("Reconcile(), got %v, want no error", err)
+			}
+
+			endpointSliceList := &discoveryv1.EndpointSliceList{}
+			if err := fakeMemberClient.List(ctx, endpointSliceList, client.InNamespace(fleetSystemNS)); err != nil {
+				t.Fatalf("endpointSlice List(), got %v, want no error", err)
+			}
+			if got := len(endpointSliceList.Items) != 0; got != tc.wantImported {
+				t.Errorf("endpointSlice imported = %v, want %v", got, tc.wantImported)
+			}
+
+			gotMultiClusterSvc := &fleetnetv1alpha1.MultiClusterService{}
+			if err := fakeMemberClient.Get(ctx, types.NamespacedName{Namespace: memberUserNS, Name: multiClusterSvcName}, gotMultiClusterSvc); err != nil {
+				t.Fatalf("multiClusterService Get(), got %v, want no error", err)
+			}
+			if diff := cmp.Diff(tc.wantSkippedClusters, gotMultiClusterSvc.Status.SkippedClusters, cmpopts.EquateEmpty()); diff != "" {
+				t.Errorf("skipped clusters mismatch (-want, +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+// TestReconcile_IPFamilies tests that the endpoints are imported only if the derived Service has their IP family.
+func TestReconcile_IPFamilies(t *testing.T) {
+	testCases := []struct {
+		name         string
+		ipFamilies   []corev1.IPFamily
+		addressType  discoveryv1.AddressType
+		wantImported bool
+	}{
+		{
+			name:         "unknown families",
+			addressType:  discoveryv1.AddressTypeIPv6,
+			wantImported: true,
+		},
+		{
+			name:         "IPv6 endpoints of dual-stack service",
+			ipFamilies:   []corev1.IPFamily{corev1.IPv4Protocol, corev1.IPv6Protocol},
+			addressType:  discoveryv1.AddressTypeIPv6,
+			wantImported: true,
+		},
+		{
+			name:         "IPv6 endpoints of dual-stack service fallen back to IPv4",
+			ipFamilies:   []corev1.IPFamily{corev1.IPv4Protocol},
+			addressType:  discoveryv1.AddressTypeIPv6,
+			wantImported: false,
+		},
+		{
+			name:         "IPv4 endpoints of dual-stack service fallen back to IPv4",
+			ipFamilies:   []corev1.IPFamily{corev1.IPv4Protocol},
+			addressType:  discoveryv1.AddressTypeIPv4,
+			wantImported: true,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx := context.Background()
+			derivedSvc := svcDerivedByMultiClusterSvc()
+			derivedSvc.Spec.IPFamilies = tc.ipFamilies
+			multiClusterSvc := fulfilledMultiClusterSvc()
+			fakeMemberClient := fake.NewClientBuilder().
+				WithScheme(scheme.Scheme).
+				WithObjects(multiClusterSvc, derivedSvc).
+				WithStatusSubresource(multiClusterSvc).
+				WithIndex(&fleetnetv1alpha1.MultiClusterService{}, mcsServiceImportRefFieldKey, func(o client.Object) []string {
+					return []string{o.(*fleetnetv1alpha1.MultiClusterService).Spec.ServiceImport.Name}
+				}).
+				Build()
+			endpointSliceImport := ipv4EndpointSliceImport()
+			endpointSliceImport.Spec.AddressType = tc.addressType
+			fakeHubClient := newFakeHubClient(endpointSliceImport)
+			reconciler := Reconciler{
+				MemberClusterID:      memberClusterID,
+				MemberClient:         fakeMemberClient,
+				HubClient:            fakeHubClient,
+				FleetSystemNamespace: fleetSystemNS,
+			}
+
+			if _, err := reconciler.Reconcile(ctx, ctrl.Request{NamespacedName: endpointSliceImportKey}); err != nil {
+				t.Fatalf("Reconcile(), got %v, want no error", err)
+			}
+
+			endpointSliceList := &discoveryv1.EndpointSliceList{}
+			if err := fakeMemberClient.List(ctx, endpointSliceList, client.InNamespace(fleetSystemNS)); err != nil {
+				t.Fatalf("endpointSlice List(), got %v, want no error", err)
+			}
+			if got := len(endpointSliceList.Items) != 0; got != tc.wantImported {
+				t.Errorf("endpointSlice imported = %v, want %v", got, tc.wantImported)
+			}
+		})
+	}
+}
+
+// TestReconcile_UnsupportedAddressType tests that an EndpointSliceImport whose address type is not supported by the
+// member cluster is reported and retried on a slow resync, until the member cluster supports it.
+func TestReconcile_UnsupportedAddressType(t *testing.T) {
+	ctx := context.Background()
+	supported := false
+	multiClusterSvc := fulfilledMultiClusterSvc()
+	fakeMemberClient := fake.NewClientBuilder().
+		WithScheme(scheme.Scheme).
+		WithObjects(multiClusterSvc, svcDerivedByMultiClusterSvc()).
+		WithStatusSubresource(multiClusterSvc).
+		WithIndex(&fleetnetv1alpha1.MultiClusterService{}, mcsServiceImportRefFieldKey, func(o client.Object) []string {
+			return []string{o.(*fleetnetv1alpha1.MultiClusterService).Spec.ServiceImport.Name}
+		}).
+		WithInterceptorFuncs(interceptor.Funcs{
+			Create: func(ctx context.Context, c client.WithWatch, obj client.Object, opts ...client.CreateOption) error {
+				if _, ok := obj.(*discoveryv1.EndpointSlice); ok && !supported {
+					return apierrors.NewInvalid(discoveryv1.SchemeGroupVersion.WithKind("EndpointSlice").GroupKind(), obj.GetName(), field.ErrorList{
+						field.Invalid(field.NewPath("endpoints").Index(0).Child("addresses").Index(0), "fd00::1", "IPv6 is not enabled"),
+					})
+				}
+				return c.Create(ctx, obj, opts...)
+			},
+		}).
+		Build()
+	endpointSliceImport := ipv4EndpointSliceImport()
+	endpointSliceImport.Spec.AddressType = discoveryv1.AddressTypeIPv6
+	endpointSliceImport.Spec.Endpoints = []fleetnetv1alpha1.Endpoint{{Addresses: []string{"fd00::1"}}}
+	fakeHubClient := newFakeHubClient(endpointSliceImport)
+	recorder := record.NewFakeRecorder(10)
+	reconciler := Reconciler{
+		MemberClusterID:      memberClusterID,
+		MemberClient:         fakeMemberClient,
+		HubClient:            fakeHubClient,
+		FleetSystemNamespace: fleetSystemNS,
+		Recorder:             recorder,
+	}
+
+	steps := []struct {
+		name         string
+		supported    bool
+		wantResult   ctrl.Result
+		wantCond     *metav1.Condition
+		wantEvent    bool
+		wantImported bool
+	}{
+		{
+			name:       "unsupported",
+			wantResult: ctrl.Result{RequeueAfter: unsupportedAddressTypeResyncInterval},
+			wantCond: &metav1.Condition{
+				Type:   string(fleetnetv1alpha1.EndpointSliceImportImported),
+				Status: metav1.ConditionFalse,
+				Reason: unsupportedAddressTypeCondReason,
+			},
+			wantEvent: true,
+		},
+		{
+			name:       "still unsupported on resync",
+			wantResult: ctrl.Result{RequeueAfter: unsupportedAddressTypeResyncInterval},
+			wantCond: &metav1.Condition{
+				Type:   string(fleetnetv1alpha1.EndpointSliceImportImported),
+				Status: metav1.ConditionFalse,
+				Reason: unsupportedAddressTypeCondReason,
+			},
+		},
+		{
+			name:      "supported after cluster upgrade",
+			supported: true,
+			wantCond: &metav1.Condition{
+				Type:   string(fleetnetv1alpha1.EndpointSliceImportImported),
+				Status: metav1.ConditionTrue,
+				Reason: importedCondReason,
+			},
+			wantImported: true,
+		},
+	}
+	for _, step := range steps {
+		supported = step.supported
+		got, err := reconciler.Reconcile(ctx, ctrl.Request{NamespacedName: endpointSliceImportKey})
+		if err != nil {
+			t.Fatalf("%s: Reconcile(), got %v, want no error", step.name, err)
+		}
+		if !cmp.Equal(got, step.wantResult) {
+			t.Errorf("%s: Reconcile() = %+v, want %+v", step.name, got, step.wantResult)
+		}
+
+		updated := &fleetnetv1alpha1.EndpointSliceImport{}
+		if err := fakeHubClient.Get(ctx, endpointSliceImportKey, updated); err != nil {
+			t.Fatalf("%s: endpointSliceImport Get(), got %v, want no error", step.name, err)
+		}
+		gotCond := meta.FindStatusCondition(updated.Status.Conditions, string(fleetnetv1alpha1.EndpointSliceImportImported))
+		if diff := cmp.Diff(step.wantCond, gotCond, cmpopts.IgnoreFields(metav1.Condition{}, "LastTransitionTime", "ObservedGeneration", "Message")); diff != "" {
+			t.Errorf("%s: imported condition (-want, +got):\n%s", step.name, diff)
+		}
+
+		gotEvent := false
+		select {
+		case event := <-recorder.Events:
+			gotEvent = strings.HasPrefix(event, corev1.EventTypeWarning+" "+unsupportedAddressTypeCondReason)
+		default:
+		}
+		if gotEvent != step.wantEvent {
+			t.Errorf("%s: warning event emitted = %t, want %t", step.name, gotEvent, step.wantEvent)
+		}
+
+		endpointSliceList := &discoveryv1.EndpointSliceList{}
+		if err := fakeMemberClient.List(ctx, endpointSliceList, client.InNamespace(fleetSystemNS)); err != nil {
+			t.Fatalf("%s: endpointSlice List(), got %v, want no error", step.name, err)
+		}
+		if gotImported := len(endpointSliceList.Items) != 0; gotImported != step.wantImported {
+			t.Errorf("%s: endpointSlice imported = %t, want %t", step.name, gotImported, step.wantImported)
+		}
+	}
+}
+
+// TestIsUnsupportedAddressTypeError tests the isUnsupportedAddressTypeError function.
+func TestIsUnsupportedAddressTypeError(t *testing.T) {
+	endpointSliceGK := discoveryv1.SchemeGroupVersion.WithKind("EndpointSlice").GroupKind()
+	testCases := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{
+			name: "invalid address",
+			err: fmt.Errorf("failed to create/update EndpointSlice: %w", apierrors.NewInvalid(endpointSliceGK, "slice", field.ErrorList{
+				field.Invalid(field.NewPath("endpoints").Index(0).Child("addresses").Index(0), "fd00::1", "IPv6 is not enabled"),
+			})),
+			want: true,
+		},
+		{
+			name: "invalid address type",
+			err: apierrors.NewInvalid(endpointSliceGK, "slice", field.ErrorList{
+				field.NotSupported(field.NewPath("addressType"), "IPv6", []string{"IPv4"}),
+			}),
+			want: true,
+		},
+		{
+			name: "invalid ports",
+			err: apierrors.NewInvalid(endpointSliceGK, "slice", field.ErrorList{
+				field.Invalid(field.NewPath("ports").Index(0).Child("name"), "HTTP", "must be lower case"),
+			}),
+		},
+		{
+			name: "conflict",
+			err:  apierrors.NewConflict(discoveryv1.Resource("endpointslices"), "slice", fmt.Errorf("conflict")),
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := isUnsupportedAddressTypeError(tc.err); got != tc.want {
+				t.Errorf("isUnsupportedAddressTypeError() = %t, want %t", got, tc.want)
+			}
+		})
+	}
+}
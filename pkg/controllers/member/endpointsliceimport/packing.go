@@ -0,0 +1,508 @@
+/*
+Copyright (c) Microsoft Corporation.
+Licensed under the MIT license.
+*/
+
+package endpointsliceimport
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/klog/v2"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	fleetnetv1alpha1 "go.goms.io/fleet-networking/api/v1alpha1"
+	"go.goms.io/fleet-networking/pkg/common/listguard"
+	"go.goms.io/fleet-networking/pkg/common/objectmeta"
+)
+
+const (
+	// maxEndpointsPerSlice is the maximum number of endpoints an imported EndpointSlice holds, which is also the
+	// maximum Kubernetes allows in an EndpointSlice.
+	maxEndpointsPerSlice = 1000
+)
+
+// importedEndpoints are the endpoints an EndpointSliceImport contributes to an imported EndpointSlice.
+type importedEndpoints struct {
+	endpointSliceImport *fleetnetv1alpha1.EndpointSliceImport
+	// endpoints are the endpoints of the EndpointSliceImport, without the ones deduplicated into the imports of
+	// another cluster.
+	endpoints []fleetnetv1alpha1.Endpoint
+	// duplicateSources are the exported EndpointSlices of the other clusters which duplicate the endpoints.
+	duplicateSources []objectmeta.ProvenanceSource
+	// ports are the ports of the EndpointSliceImport, restricted to the ones exposed by the derived Service.
+	ports []discoveryv1.EndpointPort
+	// portsKey identifies the set of the ports regardless of their order.
+	portsKey string
+	size     int
+}
+
+// packedSlice is an imported EndpointSlice and the names of the EndpointSliceImports whose endpoints it holds.
+type packedSlice struct {
+	name     string
+	portsKey string
+	members  []string
+}
+
+// packImports assigns the imports to as few EndpointSlices as possible: the imports of an EndpointSlice share the
+// same set of ports, and hold no more than maxEndpoints endpoints in total.
+//
+// The assignment is stable: an import stays in its current EndpointSlice as long as it still fits there, and the new
+// (or moved) imports fill the current EndpointSlices before a new one, named after the first import it holds, is
+// added. The imports are never rebalanced across the EndpointSlices, so that a small change of the imports does not
+// rewrite every EndpointSlice; only an EndpointSlice whose imports all fit into an earlier one with the same ports is
+// merged into it, so that the EndpointSlices emptied by the removed imports do not accumulate.
+//
+// It returns the EndpointSlices which hold at least one import; the current EndpointSlices missing from the result
+// are no longer needed.
+func packImports(current []packedSlice, imports map[string]importedEndpoints, maxEndpoints int) []packedSlice {
+	current = append([]packedSlice{}, current...)
+	sort.Slice(current, func(i, j int) bool { return current[i].name < current[j].name })
+
+	assigned := make(map[string]bool, len(imports))
+	var packed []packedSlice
+	var sizes []int
+	for _, slice := range current {
+		kept := packedSlice{name: slice.name}
+		size := 0
+		for _, name := range slice.members {
+			imported, ok := imports[name]
+			if !ok || assigned[name] {
+				continue
+			}
+			if len(kept.members) == 0 {
+				// The first import kept in the EndpointSlice decides its ports.
+				kept.portsKey = imported.portsKey
+			}
+			if imported.portsKey != kept.portsKey || size+imported.size > maxEndpoints {
+				continue
+			}
+			kept.members = append(kept.members, name)
+			size += imported.size
+			assigned[name] = true
+		}
+		if len(kept.members) > 0 {
+			packed = append(packed, kept)
+			sizes = append(sizes, size)
+		}
+	}
+
+	var merged []packedSlice
+	var mergedSizes []int
+	for i, slice := range packed {
+		into := -1
+		for j := range merged {
+			if merged[j].portsKey == slice.portsKey && mergedSizes[j]+sizes[i] <= maxEndpoints {
+				into = j
+				break
+			}
+		}
+		if into < 0 {
+			merged = append(merged, slice)
+			mergedSizes = append(mergedSizes, sizes[i])
+			continue
+		}
+		merged[into].members = append(merged[into].members, slice.members...)
+		mergedSizes[into] += sizes[i]
+	}
+	packed, sizes = merged, mergedSizes
+
+	unassigned := make([]string, 0, len(imports))
+	for name := range imports {
+		if !assigned[name] {
+			unassigned = append(unassigned, name)
+		}
+	}
+	sort.Strings(unassigned)
+	for _, name := range unassigned {
+		imported := imports[name]
+		fitted := false
+		for i := range packed {
+			if packed[i].portsKey == imported.portsKey && sizes[i]+imported.size <= maxEndpoints {
+				packed[i].members = append(packed[i].members, name)
+				sizes[i] += imported.size
+				fitted = true
+				break
+			}
+		}
+		if !fitted {
+			packed = append(packed, packedSlice{name: newSliceName(name, packed), portsKey: imported.portsKey, members: []string{name}})
+			sizes = append(sizes, imported.size)
+		}
+	}
+	return packed
+}
+
+// newSliceName returns the name of a new EndpointSlice opened by an import: the name of the import itself, unless an
+// EndpointSlice already has it.
+func newSliceName(importName string, packed []packedSlice) string {
+	taken := make(map[string]bool, len(packed))
+	for _, slice := range packed {
+		taken[slice.name] = true
+	}
+	name := importName
+	for i := 1; taken[name]; i++ {
+		name = fmt.Sprintf("%s-%d", importName, i)
+	}
+	return name
+}
+
+// portsKeyOf returns a key which identifies a set of EndpointSlice ports regardless of their order.
+func portsKeyOf(ports []discoveryv1.EndpointPort) string {
+	keys := make([]string, 0, len(ports))
+	for _, port := range ports {
+		keys = append(keys, fmt.Sprintf("%s/%s/%d/%s",
+			ptr.Deref(port.Name, ""), ptr.Deref(port.Protocol, ""), ptr.Deref(port.Port, 0), ptr.Deref(port.AppProtocol, "")))
+	}
+	sort.Strings(keys)
+	return strings.Join(keys, ",")
+}
+
+// importNamesOf returns the names of the EndpointSliceImports whose endpoints an imported EndpointSlice holds. An
+// EndpointSlice imported before the imports were packed holds the endpoints of the EndpointSliceImport of the same
+// name.
+func importNamesOf(endpointSlice *discoveryv1.EndpointSlice) []string {
+	names, ok := endpointSlice.Annotations[objectmeta.EndpointSliceAnnotationImports]
+	if !ok {
+		return []string{endpointSlice.Name}
+	}
+	if names == "" {
+		return nil
+	}
+	return strings.Split(names, ",")
+}
+
+// newImportedEndpoints returns the endpoints an EndpointSliceImport contributes to an imported EndpointSlice.
+func newImportedEndpoints(endpointSliceImport *fleetnetv1alpha1.EndpointSliceImport, derivedSvc *corev1.Service, hasPortFilter bool) importedEndpoints {
+	ports := endpointSliceImport.Spec.Ports
+	if hasPortFilter {
+		// The MCS exposes only a subset of the imported ports; the derived Service has been restricted to the
+		// subset by the MCS controller.
+		ports = filterEndpointPorts(ports, derivedSvc.Spec.Ports)
+	}
+	return importedEndpoints{
+		endpointSliceImport: endpointSliceImport,
+		endpoints:           endpointSliceImport.Spec.Endpoints,
+		ports:               ports,
+		portsKey:            portsKeyOf(ports),
+		size:                len(endpointSliceImport.Spec.Endpoints),
+	}
+}
+
+// formatPackedEndpointSlice formats an imported EndpointSlice which holds the endpoints of multiple imports.
+func formatPackedEndpointSlice(endpointSlice *discoveryv1.EndpointSlice, derivedSvcName string, packed packedSlice, imports map[string]importedEndpoints) {
+	sources := make([]objectmeta.ProvenanceSource, 0, len(packed.members))
+	for i, name := range packed.members {
+		imported := imports[name]
+		sources = append(sources, provenanceSourceOf(imported.endpointSliceImport))
+		sources = append(sources, imported.duplicateSources...)
+		if i == 0 {
+			formatEndpointSliceFromImport(endpointSlice, derivedSvcName, imported.endpointSliceImport)
+			endpointSlice.Ports = imported.ports
+			endpointSlice.Endpoints = []discoveryv1.Endpoint{}
+		}
+		for _, importedEndpoint := range imported.endpoints {
+			endpointSlice.Endpoints = append(endpointSlice.Endpoints, toImportedEndpoint(importedEndpoint))
+		}
+	}
+	if endpointSlice.Annotations == nil {
+		endpointSlice.Annotations = map[string]string{}
+	}
+	endpointSlice.Annotations[objectmeta.EndpointSliceAnnotationImports] = strings.Join(packed.members, ",")
+	// The imports of the EndpointSlice are all exported from the same cluster, and hence share its weight.
+	if weight, ok := imports[packed.members[0]].endpointSliceImport.Annotations[objectmeta.EndpointSliceAnnotationEndpointWeight]; ok {
+		endpointSlice.Annotations[objectmeta.EndpointSliceAnnotationEndpointWeight] = weight
+	} else {
+		delete(endpointSlice.Annotations, objectmeta.EndpointSliceAnnotationEndpointWeight)
+	}
+	// The provenance annotations include the origin cluster ID, which all the imports of the EndpointSlice share, along
+	// with the IDs of the clusters which duplicate its endpoints.
+	objectmeta.SetProvenanceAnnotations(endpointSlice, sources)
+}
+
+// provenanceSourceOf returns the exported EndpointSlice from which an EndpointSliceImport is imported.
+func provenanceSourceOf(endpointSliceImport *fleetnetv1alpha1.EndpointSliceImport) objectmeta.ProvenanceSource {
+	ref := endpointSliceImport.Spec.EndpointSliceReference
+	return objectmeta.ProvenanceSource{
+		ClusterID:     ref.ClusterID,
+		Namespace:     ref.Namespace,
+		Name:          ref.Name,
+		UID:           string(ref.UID),
+		ExportedSince: ref.ExportedSince.Time,
+	}
+}
+
+// endpointKeyOf returns a key which identifies an endpoint by its addresses and the set of its ports.
+func endpointKeyOf(endpoint fleetnetv1alpha1.Endpoint, portsKey string) string {
+	addresses := append([]string{}, endpoint.Addresses...)
+	sort.Strings(addresses)
+	return strings.Join(addresses, ",") + "|" + portsKey
+}
+
+// deduplicateImports removes from the imports of a cluster the endpoints which the imports of another cluster of a
+// smaller ID hold as well, with the same addresses and ports, so that every such endpoint is imported only once, by
+// the cluster of the smallest ID; the kept endpoints are attributed to the other clusters which hold them as well.
+//
+// It returns the number of the removed endpoints.
+func deduplicateImports(imports map[string]importedEndpoints, clusterID string, others []importedEndpoints) int {
+	holders := make(map[string][]importedEndpoints)
+	for _, other := range others {
+		for _, endpoint := range other.endpoints {
+			key := endpointKeyOf(endpoint, other.portsKey)
+			holders[key] = append(holders[key], other)
+		}
+	}
+
+	removed := 0
+	for name, imported := range imports {
+		endpoints := make([]fleetnetv1alpha1.Endpoint, 0, len(imported.endpoints))
+		var duplicateSources []objectmeta.ProvenanceSource
+		attributed := make(map[string]bool)
+		for _, endpoint := range imported.endpoints {
+			duplicates := holders[endpointKeyOf(endpoint, imported.portsKey)]
+			kept := true
+			for _, duplicate := range duplicates {
+				if duplicate.endpointSliceImport.Spec.EndpointSliceReference.ClusterID < clusterID {
+					kept = false
+					break
+				}
+			}
+			if !kept {
+				removed++
+				continue
+			}
+			endpoints = append(endpoints, endpoint)
+			for _, duplicate := range duplicates {
+				if !attributed[duplicate.endpointSliceImport.Name] {
+					attributed[duplicate.endpointSliceImport.Name] = true
+					duplicateSources = append(duplicateSources, provenanceSourceOf(duplicate.endpointSliceImport))
+				}
+			}
+		}
+		imported.endpoints = endpoints
+		imported.size = len(endpoints)
+		imported.duplicateSources = duplicateSources
+		imports[name] = imported
+	}
+	return removed
+}
+
+// importEndpointSlices imports the endpoints of an EndpointSliceImport, along with the ones of the other
+// EndpointSliceImports of the same Service and address type from the same cluster, packing them into as few
+// EndpointSlices as possible.
+//
+// If the MCS which claims the derived Service deduplicates the endpoints, the endpoints which the imports of the
+// other (imported and not skipped) clusters hold as well are imported only once.
+func (r *Reconciler) importEndpointSlices(ctx context.Context, endpointSliceImport *fleetnetv1alpha1.EndpointSliceImport, derivedSvc *corev1.Service, hasPortFilter bool, multiClusterSvc *fleetnetv1alpha1.MultiClusterService) error {
+	originClusterID := endpointSliceImport.Spec.EndpointSliceReference.ClusterID
+	addressType := endpointSliceImport.Spec.AddressType
+
+	endpointSliceImportList := &fleetnetv1alpha1.EndpointSliceImportList{}
+	if err := r.HubClient.List(ctx,
+		endpointSliceImportList,
+		client.InNamespace(endpointSliceImport.Namespace),
+		client.MatchingFields{endpointSliceImportOwnerSvcNamespacedNameFieldKey: endpointSliceImport.Spec.OwnerServiceReference.NamespacedName}); err != nil {
+		return fmt.Errorf("failed to list the EndpointSliceImports of the Service: %w", err)
+	}
+	listguard.ObserveListSize(endpointSliceImportList, "endpointSliceImport", klog.KObj(endpointSliceImport))
+	deduplicate := multiClusterSvc != nil && multiClusterSvc.Spec.DeduplicateEndpoints
+	candidates := make(map[string]*fleetnetv1alpha1.EndpointSliceImport, len(endpointSliceImportList.Items))
+	var others []importedEndpoints
+	for i := range endpointSliceImportList.Items {
+		item := &endpointSliceImportList.Items[i]
+		if item.DeletionTimestamp != nil || item.Spec.AddressType != addressType {
+			continue
+		}
+		itemClusterID := item.Spec.EndpointSliceReference.ClusterID
+		if itemClusterID == originClusterID {
+			candidates[item.Name] = item
+			continue
+		}
+		if deduplicate && controllerutil.ContainsFinalizer(item, endpointSliceImportCleanupFinalizer) &&
+			!slices.Contains(multiClusterSvc.Status.SkippedClusters, itemClusterID) {
+			others = append(others, newImportedEndpoints(item, derivedSvc, hasPortFilter))
+		}
+	}
+	// The cache may not have caught up with the cleanup finalizer just added.
+	candidates[endpointSliceImport.Name] = endpointSliceImport
+
+	endpointSliceList := &discoveryv1.EndpointSliceList{}
+	if err := r.MemberClient.List(ctx,
+		endpointSliceList,
+		client.InNamespace(r.FleetSystemNamespace),
+		client.MatchingLabels{discoveryv1.LabelManagedBy: controllerID}); err != nil {
+		return fmt.Errorf("failed to list the imported EndpointSlices: %w", err)
+	}
+	listguard.ObserveListSize(endpointSliceList, "endpointSliceImport", klog.KObj(endpointSliceImport))
+	var current []packedSlice
+	currentSlices := make(map[string]bool)
+	isPacked := make(map[string]bool)
+	for i := range endpointSliceList.Items {
+		endpointSlice := &endpointSliceList.Items[i]
+		if endpointSlice.AddressType != addressType {
+			continue
+		}
+		members := importNamesOf(endpointSlice)
+		// An EndpointSlice belongs to the Service if it holds any of its imports, which also covers the ones
+		// associated with a previous derived Service.
+		belongs := endpointSlice.Labels[discoveryv1.LabelServiceName] == derivedSvc.Name &&
+			endpointSlice.Annotations[objectmeta.EndpointSliceAnnotationOriginClusterID] == originClusterID
+		for _, name := range members {
+			if candidates[name] != nil {
+				belongs = true
+			}
+		}
+		if !belongs {
+			continue
+		}
+		current = append(current, packedSlice{name: endpointSlice.Name, members: members})
+		currentSlices[endpointSlice.Name] = true
+		for _, name := range members {
+			isPacked[name] = true
+		}
+	}
+
+	imports := make(map[string]importedEndpoints, len(candidates))
+	for name, candidate := range candidates {
+		// The endpoints of an EndpointSliceImport are imported only after its cleanup finalizer has been added, so
+		// that they are always removed when it is deleted.
+		if !controllerutil.ContainsFinalizer(candidate, endpointSliceImportCleanupFinalizer) && !isPacked[name] {
+			continue
+		}
+		imports[name] = newImportedEndpoints(candidate, derivedSvc, hasPortFilter)
+	}
+	if deduplicate {
+		deduplicated := deduplicateImports(imports, originClusterID, others)
+		observeDeduplicatedEndpoints(endpointSliceImport, deduplicated)
+		if deduplicated > 0 {
+			klog.V(2).InfoS("Deduplicated the endpoints exported from multiple clusters",
+				"endpointSliceImport", klog.KObj(endpointSliceImport), "originClusterID", originClusterID, "count", deduplicated)
+		}
+	} else {
+		observeDeduplicatedEndpoints(endpointSliceImport, 0)
+	}
+
+	packed := packImports(current, imports, maxEndpointsPerSlice)
+	for _, p := range packed {
+		endpointSlice := &discoveryv1.EndpointSlice{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: r.FleetSystemNamespace,
+				Name:      p.name,
+			},
+		}
+		op, err := controllerutil.CreateOrUpdate(ctx, r.MemberClient, endpointSlice, func() error {
+			formatPackedEndpointSlice(endpointSlice, derivedSvc.Name, p, imports)
+			return nil
+		})
+		if err != nil {
+			return fmt.Errorf("failed to create/update EndpointSlice %s: %w", p.name, err)
+		}
+		klog.V(2).InfoS("Imported EndpointSlice", "endpointSlice", klog.KObj(endpointSlice), "op", op, "endpointSliceImports", p.members)
+		delete(currentSlices, p.name)
+	}
+	for name := range currentSlices {
+		endpointSlice := &discoveryv1.EndpointSlice{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: r.FleetSystemNamespace,
+				Name:      name,
+			},
+		}
+		if err := r.MemberClient.Delete(ctx, endpointSlice); err != nil && !errors.IsNotFound(err) {
+			return fmt.Errorf("failed to delete EndpointSlice %s: %w", name, err)
+		}
+		klog.V(2).InfoS("Deleted the imported EndpointSlice which holds no endpoints", "endpointSlice", klog.KObj(endpointSlice))
+	}
+	return nil
+}
+
+// observeDeduplicatedEndpoints records the number of the endpoints of a Service exported from the origin cluster of an
+// EndpointSliceImport which are deduplicated into the imports of another cluster.
+func observeDeduplicatedEndpoints(endpointSliceImport *fleetnetv1alpha1.EndpointSliceImport, count int) {
+	ownerSvc := endpointSliceImport.Spec.OwnerServiceReference
+	originClusterID := endpointSliceImport.Spec.EndpointSliceReference.ClusterID
+	if count == 0 {
+		deduplicatedEndpoints.DeleteLabelValues(ownerSvc.Namespace, ownerSvc.Name, originClusterID)
+		return
+	}
+	deduplicatedEndpoints.WithLabelValues(ownerSvc.Namespace, ownerSvc.Name, originClusterID).Set(float64(count))
+}
+
+// removeFromEndpointSlices removes the endpoints of an EndpointSliceImport from the imported EndpointSlices which hold
+// them; an EndpointSlice left with no imports is deleted.
+func (r *Reconciler) removeFromEndpointSlices(ctx context.Context, endpointSliceImport *fleetnetv1alpha1.EndpointSliceImport) error {
+	endpointSliceList := &discoveryv1.EndpointSliceList{}
+	if err := r.MemberClient.List(ctx,
+		endpointSliceList,
+		client.InNamespace(r.FleetSystemNamespace),
+		client.MatchingLabels{discoveryv1.LabelManagedBy: controllerID}); err != nil {
+		return fmt.Errorf("failed to list the imported EndpointSlices: %w", err)
+	}
+	listguard.ObserveListSize(endpointSliceList, "endpointSliceImport", klog.KObj(endpointSliceImport))
+	for i := range endpointSliceList.Items {
+		endpointSlice := &endpointSliceList.Items[i]
+		members := importNamesOf(endpointSlice)
+		found := false
+		for _, name := range members {
+			if name == endpointSliceImport.Name {
+				found = true
+			}
+		}
+		if !found {
+			continue
+		}
+
+		// Rebuild the endpoints and the provenance from the remaining imports; the ports are kept as the imports
+		// share them. The endpoints deduplicated across the clusters are restored as a whole once the remaining
+		// imports are reconciled again.
+		var remaining []string
+		var sources []objectmeta.ProvenanceSource
+		endpoints := []discoveryv1.Endpoint{}
+		for _, name := range members {
+			if name == endpointSliceImport.Name {
+				continue
+			}
+			member := &fleetnetv1alpha1.EndpointSliceImport{}
+			if err := r.HubClient.Get(ctx, types.NamespacedName{Namespace: endpointSliceImport.Namespace, Name: name}, member); err != nil {
+				if errors.IsNotFound(err) {
+					continue
+				}
+				return fmt.Errorf("failed to get EndpointSliceImport %s: %w", name, err)
+			}
+			if member.DeletionTimestamp != nil {
+				continue
+			}
+			remaining = append(remaining, name)
+			sources = append(sources, provenanceSourceOf(member))
+			for _, importedEndpoint := range member.Spec.Endpoints {
+				endpoints = append(endpoints, toImportedEndpoint(importedEndpoint))
+			}
+		}
+		if len(remaining) == 0 {
+			if err := r.MemberClient.Delete(ctx, endpointSlice); err != nil && !errors.IsNotFound(err) {
+				return fmt.Errorf("failed to delete EndpointSlice %s: %w", endpointSlice.Name, err)
+			}
+			continue
+		}
+		endpointSlice.Endpoints = endpoints
+		if endpointSlice.Annotations == nil {
+			endpointSlice.Annotations = map[string]string{}
+		}
+		endpointSlice.Annotations[objectmeta.EndpointSliceAnnotationImports] = strings.Join(remaining, ",")
+		objectmeta.SetProvenanceAnnotations(endpointSlice, sources)
+		if err := r.MemberClient.Update(ctx, endpointSlice); err != nil {
+			return fmt.Errorf("failed to update EndpointSlice %s: %w", endpointSlice.Name, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,714 @@
+/*
+Copyright (c) Microsoft Corporation.
+Licensed under the MIT license.
+*/
+
+package endpointsliceimport
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	corev1 "k8s.io/api/core/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	fleetnetv1alpha1 "go.goms.io/fleet-networking/api/v1alpha1"
+	"go.goms.io/fleet-networking/pkg/common/objectmeta"
+)
+
+// imported returns the endpoints imported from an EndpointSliceImport with the given ports key and size.
+func imported(portsKey string, size int) importedEndpoints {
+	return importedEndpoints{portsKey: portsKey, size: size}
+}
+
+func TestPackImports(t *testing.T) {
+	twentyImports := map[string]importedEndpoints{}
+	var twentyNames []string
+	for i := 0; i < 20; i++ {
+		name := fmt.Sprintf("import-%02d", i)
+		twentyImports[name] = imported("http", 10)
+		twentyNames = append(twentyNames, name)
+	}
+
+	testCases := []struct {
+		name         string
+		current      []packedSlice
+		imports      map[string]importedEndpoints
+		maxEndpoints int
+		want         []packedSlice
+	}{
+		{
+			name:         "no imports",
+			maxEndpoints: maxEndpointsPerSlice,
+		},
+		{
+			name:         "many small imports are packed into one slice named after the first import",
+			imports:      twentyImports,
+			maxEndpoints: maxEndpointsPerSlice,
+			want: []packedSlice{
+				{name: "import-00", portsKey: "http", members: twentyNames},
+			},
+		},
+		{
+			name:         "imports are split by the endpoint limit",
+			imports:      twentyImports,
+			maxEndpoints: 75,
+			want: []packedSlice{
+				{name: "import-00", portsKey: "http", members: twentyNames[0:7]},
+				{name: "import-07", portsKey: "http", members: twentyNames[7:14]},
+				{name: "import-14", portsKey: "http", members: twentyNames[14:20]},
+			},
+		},
+		{
+			name: "imports are split by the port set",
+			imports: map[string]importedEndpoints{
+				"a": imported("http", 10),
+				"b": imported("http,tcp", 10),
+				"c": imported("http", 10),
+				"d": imported("http,tcp", 10),
+			},
+			maxEndpoints: maxEndpointsPerSlice,
+			want: []packedSlice{
+				{name: "a", portsKey: "http", members: []string{"a", "c"}},
+				{name: "b", portsKey: "http,tcp", members: []string{"b", "d"}},
+			},
+		},
+		{
+			name: "the current assignment is kept and a new import fills the first slice with room",
+			current: []packedSlice{
+				{name: "b", members: []string{"b", "c"}},
+				{name: "a", members: []string{"a"}},
+			},
+			imports: map[string]importedEndpoints{
+				"a": imported("http", 25),
+				"b": imported("http", 10),
+				"c": imported("http", 10),
+				"d": imported("http", 10),
+			},
+			maxEndpoints: 30,
+			want: []packedSlice{
+				{name: "a", portsKey: "http", members: []string{"a"}},
+				{name: "b", portsKey: "http", members: []string{"b", "c", "d"}},
+			},
+		},
+		{
+			name: "an import which has outgrown its slice moves and the others stay",
+			current: []packedSlice{
+				{name: "a", members: []string{"a", "b", "c"}},
+			},
+			imports: map[string]importedEndpoints{
+				"a": imported("http", 10),
+				"b": imported("http", 25),
+				"c": imported("http", 10),
+			},
+			maxEndpoints: 30,
+			want: []packedSlice{
+				{name: "a", portsKey: "http", members: []string{"a", "c"}},
+				{name: "b", portsKey: "http", members: []string{"b"}},
+			},
+		},
+		{
+			name: "removed imports leave their slices and empty slices are dropped",
+			current: []packedSlice{
+				{name: "a", members: []string{"a", "b", "c"}},
+				{name: "d", members: []string{"d"}},
+			},
+			imports: map[string]importedEndpoints{
+				"a": imported("http", 10),
+				"c": imported("http", 10),
+			},
+			maxEndpoints: maxEndpointsPerSlice,
+			want: []packedSlice{
+				{name: "a", portsKey: "http", members: []string{"a", "c"}},
+			},
+		},
+		{
+			name: "an import whose ports have changed moves to a slice named after it with a suffix when the name is taken",
+			current: []packedSlice{
+				{name: "a", members: []string{"a", "b"}},
+			},
+			imports: map[string]importedEndpoints{
+				"a": imported("http,tcp", 10),
+				"b": imported("http", 10),
+			},
+			maxEndpoints: maxEndpointsPerSlice,
+			want: []packedSlice{
+				// The first import kept in the slice, i.e., a, decides its ports.
+				{name: "a", portsKey: "http,tcp", members: []string{"a"}},
+				{name: "b", portsKey: "http", members: []string{"b"}},
+			},
+		},
+		{
+			name: "a moved import takes a suffixed name when a slice already has its name",
+			current: []packedSlice{
+				{name: "a", members: []string{"b", "a"}},
+			},
+			imports: map[string]importedEndpoints{
+				"a": imported("http,tcp", 10),
+				"b": imported("http", 10),
+			},
+			maxEndpoints: maxEndpointsPerSlice,
+			want: []packedSlice{
+				{name: "a", portsKey: "http", members: []string{"b"}},
+				{name: "a-1", portsKey: "http,tcp", members: []string{"a"}},
+			},
+		},
+		{
+			name: "an import listed in multiple slices is kept in the first one",
+			current: []packedSlice{
+				{name: "a", members: []string{"a"}},
+				{name: "b", members: []string{"a", "b"}},
+			},
+			imports: map[string]importedEndpoints{
+				"a": imported("http", 10),
+				"b": imported("http", 10),
+			},
+			maxEndpoints: maxEndpointsPerSlice,
+			want: []packedSlice{
+				{name: "a", portsKey: "http", members: []string{"a", "b"}},
+			},
+		},
+		{
+			name: "a slice whose imports fit into an earlier one is merged into it",
+			current: []packedSlice{
+				{name: "a", members: []string{"a", "b"}},
+				{name: "c", members: []string{"c"}},
+				{name: "d", members: []string{"d"}},
+			},
+			imports: map[string]importedEndpoints{
+				"a": imported("http", 10),
+				"b": imported("http", 10),
+				"c": imported("http", 15),
+				"d": imported("http", 5),
+			},
+			maxEndpoints: 30,
+			want: []packedSlice{
+				{name: "a", portsKey: "http", members: []string{"a", "b", "d"}},
+				{name: "c", portsKey: "http", members: []string{"c"}},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got := packImports(tc.current, tc.imports, tc.maxEndpoints)
+			if diff := cmp.Diff(tc.want, got, cmp.AllowUnexported(packedSlice{}), cmpopts.EquateEmpty()); diff != "" {
+				t.Errorf("packImports() mismatch (-want, +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestPortsKeyOf(t *testing.T) {
+	httpEndpointPort := discoveryv1.EndpointPort{Name: &httpPortName, Protocol: &httpPortProtocol, Port: &httpPort, AppProtocol: &httpPortAppProtocol}
+	tcpEndpointPort := discoveryv1.EndpointPort{Name: &tcpPortName, Protocol: &tcpPortProtocol, Port: &tcpPort, AppProtocol: &tcpPortAppProtocol}
+
+	if got, want := portsKeyOf([]discoveryv1.EndpointPort{httpEndpointPort, tcpEndpointPort}), portsKeyOf([]discoveryv1.EndpointPort{tcpEndpointPort, httpEndpointPort}); got != want {
+		t.Errorf("portsKeyOf() of the reordered ports = %q, want %q", got, want)
+	}
+	if got, other := portsKeyOf([]discoveryv1.EndpointPort{httpEndpointPort}), portsKeyOf([]discoveryv1.EndpointPort{httpEndpointPort, tcpEndpointPort}); got == other {
+		t.Errorf("portsKeyOf() of different port sets = %q, want different keys", got)
+	}
+}
+
+func TestImportNamesOf(t *testing.T) {
+	testCases := []struct {
+		name        string
+		annotations map[string]string
+		want        []string
+	}{
+		{
+			name: "endpointslice imported before the imports were packed",
+			want: []string{endpointSliceImportName},
+		},
+		{
+			name:        "packed endpointslice",
+			annotations: map[string]string{objectmeta.EndpointSliceAnnotationImports: "a,b"},
+			want:        []string{"a", "b"},
+		},
+		{
+			name:        "empty endpointslice",
+			annotations: map[string]string{objectmeta.EndpointSliceAnnotationImports: ""},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			endpointSlice := &discoveryv1.EndpointSlice{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:        endpointSliceImportName,
+					Annotations: tc.annotations,
+				},
+			}
+			if diff := cmp.Diff(tc.want, importNamesOf(endpointSlice)); diff != "" {
+				t.Errorf("importNamesOf() mismatch (-want, +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+// endpointSliceImportWithEndpoints returns an EndpointSliceImport of the given name with the given endpoint addresses.
+func endpointSliceImportWithEndpoints(name string, addresses ...string) *fleetnetv1alpha1.EndpointSliceImport {
+	endpointSliceImport := ipv4EndpointSliceImport()
+	endpointSliceImport.Name = name
+	endpointSliceImport.Finalizers = []string{endpointSliceImportCleanupFinalizer}
+	endpointSliceImport.Spec.Endpoints = nil
+	for _, address := range addresses {
+		endpointSliceImport.Spec.Endpoints = append(endpointSliceImport.Spec.Endpoints, fleetnetv1alpha1.Endpoint{Addresses: []string{address}})
+	}
+	return endpointSliceImport
+}
+
+func newFakeHubClient(objs ...client.Object) client.Client {
+	return fake.NewClientBuilder().
+		WithScheme(scheme.Scheme).
+		WithObjects(objs...).
+		WithStatusSubresource(&fleetnetv1alpha1.EndpointSliceImport{}).
+		WithIndex(&fleetnetv1alpha1.EndpointSliceImport{}, endpointSliceImportOwnerSvcNamespacedNameFieldKey, func(o client.Object) []string {
+			return []string{o.(*fleetnetv1alpha1.EndpointSliceImport).Spec.OwnerServiceReference.NamespacedName}
+		}).
+		Build()
+}
+
+func TestImportEndpointSlices(t *testing.T) {
+	ctx := context.Background()
+	derivedSvc := svcDerivedByMultiClusterSvc()
+
+	// An EndpointSlice imported before the imports were packed.
+	legacyEndpointSlice := importedIPv4EndpointSlice()
+	legacyEndpointSlice.Name = "import-a"
+	legacyEndpointSlice.Endpoints = []discoveryv1.Endpoint{{Addresses: []string{"10.0.0.1"}}}
+	legacyEndpointSlice.Annotations = map[string]string{"unrelated": "value"}
+
+	fakeHubClient := newFakeHubClient(
+		endpointSliceImportWithEndpoints("import-a", "10.0.0.1"),
+		endpointSliceImportWithEndpoints("import-b", "10.0.0.2", "10.0.0.3"),
+	)
+	fakeMemberClient := fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(legacyEndpointSlice).Build()
+	reconciler := Reconciler{
+		MemberClient:         fakeMemberClient,
+		HubClient:            fakeHubClient,
+		FleetSystemNamespace: fleetSystemNS,
+	}
+
+	// A new import without the cleanup finalizer yet is not imported unless it is the one being reconciled.
+	withoutFinalizer := endpointSliceImportWithEndpoints("import-c", "10.0.0.4")
+	withoutFinalizer.Finalizers = nil
+	if err := fakeHubClient.Create(ctx, withoutFinalizer); err != nil {
+		t.Fatalf("endpointSliceImport Create(), got %v, want no error", err)
+	}
+
+	current := &fleetnetv1alpha1.EndpointSliceImport{}
+	if err := fakeHubClient.Get(ctx, types.NamespacedName{Namespace: hubNSForMember, Name: "import-b"}, current); err != nil {
+		t.Fatalf("endpointSliceImport Get(), got %v, want no error", err)
+	}
+	if err := reconciler.importEndpointSlices(ctx, current, derivedSvc, false, nil); err != nil {
+		t.Fatalf("importEndpointSlices(), got %v, want no error", err)
+	}
+
+	endpointSliceList := &discoveryv1.EndpointSliceList{}
+	if err := fakeMemberClient.List(ctx, endpointSliceList, client.InNamespace(fleetSystemNS)); err != nil {
+		t.Fatalf("endpointSlice List(), got %v, want no error", err)
+	}
+	// The provenance records the earliest export time of the imports.
+	exportedSince := current.Spec.EndpointSliceReference.ExportedSince.Time
+	importA := &fleetnetv1alpha1.EndpointSliceImport{}
+	if err := fakeHubClient.Get(ctx, types.NamespacedName{Namespace: hubNSForMember, Name: "import-a"}, importA); err != nil {
+		t.Fatalf("endpointSliceImport Get(), got %v, want no error", err)
+	}
+	if importA.Spec.EndpointSliceReference.ExportedSince.Time.Before(exportedSince) {
+		exportedSince = importA.Spec.EndpointSliceReference.ExportedSince.Time
+	}
+	want := importedIPv4EndpointSlice()
+	want.Name = "import-a"
+	want.Annotations = map[string]string{
+		"unrelated": "value",
+		objectmeta.EndpointSliceAnnotationImports:      "import-a,import-b",
+		objectmeta.ProvenanceAnnotationOriginClusterID: hubNSForMember,
+		objectmeta.ProvenanceAnnotationOriginObject:    memberUserNS + "/" + endpointSliceName,
+		objectmeta.ProvenanceAnnotationOriginUID:       "00000000-0000-0000-0000-000000000000",
+		objectmeta.ProvenanceAnnotationExportedSince:   exportedSince.UTC().Format(time.RFC3339),
+		objectmeta.ProvenanceAnnotationAgentVersion:    objectmeta.AgentVersion,
+	}
+	want.Endpoints = []discoveryv1.Endpoint{
+		{Addresses: []string{"10.0.0.1"}},
+		{Addresses: []string{"10.0.0.2"}},
+		{Addresses: []string{"10.0.0.3"}},
+	}
+	if diff := cmp.Diff([]discoveryv1.EndpointSlice{*want}, endpointSliceList.Items, cmpopts.IgnoreFields(metav1.ObjectMeta{}, "ResourceVersion"), cmpopts.IgnoreTypes(metav1.TypeMeta{})); diff != "" {
+		t.Errorf("imported endpointslices mismatch (-want, +got):\n%s", diff)
+	}
+
+	// The removal of an import rewrites only the slice holding it.
+	deleted := &fleetnetv1alpha1.EndpointSliceImport{}
+	if err := fakeHubClient.Get(ctx, types.NamespacedName{Namespace: hubNSForMember, Name: "import-a"}, deleted); err != nil {
+		t.Fatalf("endpointSliceImport Get(), got %v, want no error", err)
+	}
+	if err := fakeHubClient.Delete(ctx, deleted); err != nil {
+		t.Fatalf("endpointSliceImport Delete(), got %v, want no error", err)
+	}
+	if err := reconciler.removeFromEndpointSlices(ctx, deleted); err != nil {
+		t.Fatalf("removeFromEndpointSlices(), got %v, want no error", err)
+	}
+	got := &discoveryv1.EndpointSlice{}
+	if err := fakeMemberClient.Get(ctx, types.NamespacedName{Namespace: fleetSystemNS, Name: "import-a"}, got); err != nil {
+		t.Fatalf("endpointSlice Get(), got %v, want no error", err)
+	}
+	wantAnnotations := map[string]string{
+		"unrelated": "value",
+		objectmeta.EndpointSliceAnnotationImports:      "import-b",
+		objectmeta.ProvenanceAnnotationOriginClusterID: hubNSForMember,
+		objectmeta.ProvenanceAnnotationOriginObject:    memberUserNS + "/" + endpointSliceName,
+		objectmeta.ProvenanceAnnotationOriginUID:       "00000000-0000-0000-0000-000000000000",
+		objectmeta.ProvenanceAnnotationExportedSince:   current.Spec.EndpointSliceReference.ExportedSince.UTC().Format(time.RFC3339),
+		objectmeta.ProvenanceAnnotationAgentVersion:    objectmeta.AgentVersion,
+	}
+	if diff := cmp.Diff(wantAnnotations, got.Annotations); diff != "" {
+		t.Errorf("endpointSlice annotations mismatch (-want, +got):\n%s", diff)
+	}
+	wantEndpoints := []discoveryv1.Endpoint{
+		{Addresses: []string{"10.0.0.2"}},
+		{Addresses: []string{"10.0.0.3"}},
+	}
+	if diff := cmp.Diff(wantEndpoints, got.Endpoints); diff != "" {
+		t.Errorf("endpointSlice endpoints mismatch (-want, +got):\n%s", diff)
+	}
+
+	// The removal of the last import deletes the slice.
+	deleted = &fleetnetv1alpha1.EndpointSliceImport{}
+	if err := fakeHubClient.Get(ctx, types.NamespacedName{Namespace: hubNSForMember, Name: "import-b"}, deleted); err != nil {
+		t.Fatalf("endpointSliceImport Get(), got %v, want no error", err)
+	}
+	if err := fakeHubClient.Delete(ctx, deleted); err != nil {
+		t.Fatalf("endpointSliceImport Delete(), got %v, want no error", err)
+	}
+	if err := reconciler.removeFromEndpointSlices(ctx, deleted); err != nil {
+		t.Fatalf("removeFromEndpointSlices(), got %v, want no error", err)
+	}
+	if err := fakeMemberClient.Get(ctx, types.NamespacedName{Namespace: fleetSystemNS, Name: "import-a"}, got); !errors.IsNotFound(err) {
+		t.Errorf("endpointSlice Get(), got %v, want not found", err)
+	}
+}
+
+// TestImportEndpointSlices_EndpointWeight tests that the imported EndpointSlices carry the weight of the exporting
+// cluster, as annotated on their EndpointSliceImports, through its updates and removal.
+func TestImportEndpointSlices_EndpointWeight(t *testing.T) {
+	ctx := context.Background()
+	derivedSvc := svcDerivedByMultiClusterSvc()
+
+	fakeHubClient := newFakeHubClient(endpointSliceImportWithEndpoints("import-a", "10.0.0.1"))
+	fakeMemberClient := fake.NewClientBuilder().WithScheme(scheme.Scheme).Build()
+	reconciler := Reconciler{
+		MemberClient:         fakeMemberClient,
+		HubClient:            fakeHubClient,
+		FleetSystemNamespace: fleetSystemNS,
+	}
+
+	testCases := []struct {
+		name       string
+		weight     *string
+		wantWeight *string
+	}{
+		{
+			name:       "weight is set",
+			weight:     ptr.To("3"),
+			wantWeight: ptr.To("3"),
+		},
+		{
+			name:       "weight is updated",
+			weight:     ptr.To("5"),
+			wantWeight: ptr.To("5"),
+		},
+		{
+			name: "weight is removed",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			current := &fleetnetv1alpha1.EndpointSliceImport{}
+			if err := fakeHubClient.Get(ctx, types.NamespacedName{Namespace: hubNSForMember, Name: "import-a"}, current); err != nil {
+				t.Fatalf("endpointSliceImport Get(), got %v, want no error", err)
+			}
+			if current.Annotations == nil {
+				current.Annotations = map[string]string{}
+			}
+			delete(current.Annotations, objectmeta.EndpointSliceAnnotationEndpointWeight)
+			if tc.weight != nil {
+				current.Annotations[objectmeta.EndpointSliceAnnotationEndpointWeight] = *tc.weight
+			}
+			if err := fakeHubClient.Update(ctx, current); err != nil {
+				t.Fatalf("endpointSliceImport Update(), got %v, want no error", err)
+			}
+			if err := reconciler.importEndpointSlices(ctx, current, derivedSvc, false, nil); err != nil {
+				t.Fatalf("importEndpointSlices(), got %v, want no error", err)
+			}
+
+			got := &discoveryv1.EndpointSlice{}
+			if err := fakeMemberClient.Get(ctx, types.NamespacedName{Namespace: fleetSystemNS, Name: "import-a"}, got); err != nil {
+				t.Fatalf("endpointSlice Get(), got %v, want no error", err)
+			}
+			weight, ok := got.Annotations[objectmeta.EndpointSliceAnnotationEndpointWeight]
+			var gotWeight *string
+			if ok {
+				gotWeight = &weight
+			}
+			if diff := cmp.Diff(tc.wantWeight, gotWeight); diff != "" {
+				t.Errorf("endpointSlice weight annotation mismatch (-want, +got):\n%s", diff)
+			}
+			wantEndpoints := []discoveryv1.Endpoint{{Addresses: []string{"10.0.0.1"}}}
+			if diff := cmp.Diff(wantEndpoints, got.Endpoints); diff != "" {
+				t.Errorf("endpointSlice endpoints mismatch (-want, +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+// TestImportEndpointSlices_CustomFleetSystemNamespace tests that the imported EndpointSlices are placed in, and only
+// in, the configured reserved namespace.
+func TestImportEndpointSlices_CustomFleetSystemNamespace(t *testing.T) {
+	ctx := context.Background()
+	derivedSvc := svcDerivedByMultiClusterSvc()
+	derivedSvc.Namespace = customFleetSystemNS
+
+	// An EndpointSlice of the same name in the default reserved namespace is left untouched.
+	unrelatedEndpointSlice := importedIPv4EndpointSlice()
+	unrelatedEndpointSlice.Name = "import-a"
+
+	fakeHubClient := newFakeHubClient(endpointSliceImportWithEndpoints("import-a", "10.0.0.1"))
+	fakeMemberClient := fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(unrelatedEndpointSlice).Build()
+	reconciler := Reconciler{
+		MemberClient:         fakeMemberClient,
+		HubClient:            fakeHubClient,
+		FleetSystemNamespace: customFleetSystemNS,
+	}
+
+	current := &fleetnetv1alpha1.EndpointSliceImport{}
+	if err := fakeHubClient.Get(ctx, types.NamespacedName{Namespace: hubNSForMember, Name: "import-a"}, current); err != nil {
+		t.Fatalf("endpointSliceImport Get(), got %v, want no error", err)
+	}
+	if err := reconciler.importEndpointSlices(ctx, current, derivedSvc, false, nil); err != nil {
+		t.Fatalf("importEndpointSlices(), got %v, want no error", err)
+	}
+
+	got := &discoveryv1.EndpointSlice{}
+	if err := fakeMemberClient.Get(ctx, types.NamespacedName{Namespace: customFleetSystemNS, Name: "import-a"}, got); err != nil {
+		t.Fatalf("endpointSlice Get(), got %v, want no error", err)
+	}
+	wantEndpoints := []discoveryv1.Endpoint{{Addresses: []string{"10.0.0.1"}}}
+	if diff := cmp.Diff(wantEndpoints, got.Endpoints); diff != "" {
+		t.Errorf("endpointSlice endpoints mismatch (-want, +got):\n%s", diff)
+	}
+
+	unrelated := &discoveryv1.EndpointSlice{}
+	if err := fakeMemberClient.Get(ctx, types.NamespacedName{Namespace: fleetSystemNS, Name: "import-a"}, unrelated); err != nil {
+		t.Fatalf("endpointSlice Get(), got %v, want no error", err)
+	}
+	if diff := cmp.Diff(unrelatedEndpointSlice.Endpoints, unrelated.Endpoints); diff != "" {
+		t.Errorf("endpointSlice in the default reserved namespace mismatch (-want, +got):\n%s", diff)
+	}
+}
+
+func TestNewImportedEndpoints(t *testing.T) {
+	derivedSvc := svcDerivedByMultiClusterSvc()
+	derivedSvc.Spec.Ports = []corev1.ServicePort{derivedSvc.Spec.Ports[1]}
+	endpointSliceImport := ipv4EndpointSliceImport()
+
+	got := newImportedEndpoints(endpointSliceImport, derivedSvc, true)
+	wantPorts := importedIPv4EndpointSlice().Ports[1:]
+	if diff := cmp.Diff(wantPorts, got.ports); diff != "" {
+		t.Errorf("newImportedEndpoints() ports mismatch (-want, +got):\n%s", diff)
+	}
+	if got.portsKey != portsKeyOf(wantPorts) {
+		t.Errorf("newImportedEndpoints() portsKey = %q, want %q", got.portsKey, portsKeyOf(wantPorts))
+	}
+	if got.size != 2 {
+		t.Errorf("newImportedEndpoints() size = %d, want 2", got.size)
+	}
+}
+
+// importedFromCluster returns the endpoints imported from an EndpointSliceImport of the given name exported from the
+// given cluster with the given ports key and endpoint addresses.
+func importedFromCluster(name, clusterID, portsKey string, addresses ...string) importedEndpoints {
+	endpointSliceImport := endpointSliceImportWithEndpoints(name, addresses...)
+	endpointSliceImport.Spec.EndpointSliceReference.ClusterID = clusterID
+	return importedEndpoints{
+		endpointSliceImport: endpointSliceImport,
+		endpoints:           endpointSliceImport.Spec.Endpoints,
+		portsKey:            portsKey,
+		size:                len(addresses),
+	}
+}
+
+func TestDeduplicateImports(t *testing.T) {
+	testCases := []struct {
+		name          string
+		clusterID     string
+		imported      importedEndpoints
+		others        []importedEndpoints
+		wantAddresses []string
+		wantSources   []string
+		wantRemoved   int
+	}{
+		{
+			name:          "duplicate of a cluster of a smaller ID is removed",
+			clusterID:     "cluster-b",
+			imported:      importedFromCluster("import-b", "cluster-b", "http", "10.0.0.1", "10.0.0.2"),
+			others:        []importedEndpoints{importedFromCluster("import-a", "cluster-a", "http", "10.0.0.1")},
+			wantAddresses: []string{"10.0.0.2"},
+			wantRemoved:   1,
+		},
+		{
+			name:          "duplicate of a cluster of a larger ID is kept and attributed to it",
+			clusterID:     "cluster-a",
+			imported:      importedFromCluster("import-a", "cluster-a", "http", "10.0.0.1", "10.0.0.2"),
+			others:        []importedEndpoints{importedFromCluster("import-b", "cluster-b", "http", "10.0.0.1")},
+			wantAddresses: []string{"10.0.0.1", "10.0.0.2"},
+			wantSources:   []string{"cluster-b"},
+		},
+		{
+			name:          "same address with different ports is not a duplicate",
+			clusterID:     "cluster-b",
+			imported:      importedFromCluster("import-b", "cluster-b", "http", "10.0.0.1"),
+			others:        []importedEndpoints{importedFromCluster("import-a", "cluster-a", "tcp", "10.0.0.1")},
+			wantAddresses: []string{"10.0.0.1"},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			imports := map[string]importedEndpoints{tc.imported.endpointSliceImport.Name: tc.imported}
+			removed := deduplicateImports(imports, tc.clusterID, tc.others)
+			if removed != tc.wantRemoved {
+				t.Errorf("deduplicateImports() = %d, want %d", removed, tc.wantRemoved)
+			}
+			got := imports[tc.imported.endpointSliceImport.Name]
+			var gotAddresses []string
+			for _, endpoint := range got.endpoints {
+				gotAddresses = append(gotAddresses, endpoint.Addresses...)
+			}
+			if diff := cmp.Diff(tc.wantAddresses, gotAddresses); diff != "" {
+				t.Errorf("deduplicateImports() endpoints mismatch (-want, +got):\n%s", diff)
+			}
+			if got.size != len(tc.wantAddresses) {
+				t.Errorf("deduplicateImports() size = %d, want %d", got.size, len(tc.wantAddresses))
+			}
+			var gotSources []string
+			for _, source := range got.duplicateSources {
+				gotSources = append(gotSources, source.ClusterID)
+			}
+			if diff := cmp.Diff(tc.wantSources, gotSources); diff != "" {
+				t.Errorf("deduplicateImports() duplicate sources mismatch (-want, +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+// TestImportEndpointSlices_DeduplicateEndpoints tests that the endpoints exported from multiple clusters are imported
+// once only if the MCS deduplicates the endpoints.
+func TestImportEndpointSlices_DeduplicateEndpoints(t *testing.T) {
+	testCases := []struct {
+		name               string
+		multiClusterSvc    *fleetnetv1alpha1.MultiClusterService
+		wantEndpoints      []discoveryv1.Endpoint
+		wantOriginClusters string
+	}{
+		{
+			name:               "no MCS",
+			wantEndpoints:      []discoveryv1.Endpoint{{Addresses: []string{"10.0.0.1"}}, {Addresses: []string{"10.0.0.2"}}},
+			wantOriginClusters: "cluster-b",
+		},
+		{
+			name:               "deduplication is disabled by default",
+			multiClusterSvc:    &fleetnetv1alpha1.MultiClusterService{},
+			wantEndpoints:      []discoveryv1.Endpoint{{Addresses: []string{"10.0.0.1"}}, {Addresses: []string{"10.0.0.2"}}},
+			wantOriginClusters: "cluster-b",
+		},
+		{
+			name: "deduplication is enabled",
+			multiClusterSvc: &fleetnetv1alpha1.MultiClusterService{
+				Spec: fleetnetv1alpha1.MultiClusterServiceSpec{DeduplicateEndpoints: true},
+			},
+			wantEndpoints:      []discoveryv1.Endpoint{{Addresses: []string{"10.0.0.2"}}},
+			wantOriginClusters: "cluster-b",
+		},
+		{
+			name: "endpoints of a skipped cluster are not duplicates",
+			multiClusterSvc: &fleetnetv1alpha1.MultiClusterService{
+				Spec:   fleetnetv1alpha1.MultiClusterServiceSpec{DeduplicateEndpoints: true},
+				Status: fleetnetv1alpha1.MultiClusterServiceStatus{SkippedClusters: []string{"cluster-a"}},
+			},
+			wantEndpoints:      []discoveryv1.Endpoint{{Addresses: []string{"10.0.0.1"}}, {Addresses: []string{"10.0.0.2"}}},
+			wantOriginClusters: "cluster-b",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx := context.Background()
+			importA := endpointSliceImportWithEndpoints("import-a", "10.0.0.1")
+			importA.Spec.EndpointSliceReference.ClusterID = "cluster-a"
+			importB := endpointSliceImportWithEndpoints("import-b", "10.0.0.1", "10.0.0.2")
+			importB.Spec.EndpointSliceReference.ClusterID = "cluster-b"
+			fakeHubClient := newFakeHubClient(importA, importB)
+			fakeMemberClient := fake.NewClientBuilder().WithScheme(scheme.Scheme).Build()
+			reconciler := Reconciler{
+				MemberClient:         fakeMemberClient,
+				HubClient:            fakeHubClient,
+				FleetSystemNamespace: fleetSystemNS,
+			}
+
+			if err := reconciler.importEndpointSlices(ctx, importB, svcDerivedByMultiClusterSvc(), false, tc.multiClusterSvc); err != nil {
+				t.Fatalf("importEndpointSlices(), got %v, want no error", err)
+			}
+
+			got := &discoveryv1.EndpointSlice{}
+			if err := fakeMemberClient.Get(ctx, types.NamespacedName{Namespace: fleetSystemNS, Name: "import-b"}, got); err != nil {
+				t.Fatalf("endpointSlice Get(), got %v, want no error", err)
+			}
+			if diff := cmp.Diff(tc.wantEndpoints, got.Endpoints); diff != "" {
+				t.Errorf("endpointSlice endpoints mismatch (-want, +got):\n%s", diff)
+			}
+			if gotOriginClusters := got.Annotations[objectmeta.ProvenanceAnnotationOriginClusterID]; gotOriginClusters != tc.wantOriginClusters {
+				t.Errorf("endpointSlice origin clusters = %q, want %q", gotOriginClusters, tc.wantOriginClusters)
+			}
+		})
+	}
+
+	// The cluster of the smaller ID keeps the duplicate endpoint and attributes it to both clusters.
+	ctx := context.Background()
+	importA := endpointSliceImportWithEndpoints("import-a", "10.0.0.1")
+	importA.Spec.EndpointSliceReference.ClusterID = "cluster-a"
+	importB := endpointSliceImportWithEndpoints("import-b", "10.0.0.1", "10.0.0.2")
+	importB.Spec.EndpointSliceReference.ClusterID = "cluster-b"
+	fakeMemberClient := fake.NewClientBuilder().WithScheme(scheme.Scheme).Build()
+	reconciler := Reconciler{
+		MemberClient:         fakeMemberClient,
+		HubClient:            newFakeHubClient(importA, importB),
+		FleetSystemNamespace: fleetSystemNS,
+	}
+	multiClusterSvc := &fleetnetv1alpha1.MultiClusterService{
+		Spec: fleetnetv1alpha1.MultiClusterServiceSpec{DeduplicateEndpoints: true},
+	}
+	if err := reconciler.importEndpointSlices(ctx, importA, svcDerivedByMultiClusterSvc(), false, multiClusterSvc); err != nil {
+		t.Fatalf("importEndpointSlices(), got %v, want no error", err)
+	}
+	got := &discoveryv1.EndpointSlice{}
+	if err := fakeMemberClient.Get(ctx, types.NamespacedName{Namespace: fleetSystemNS, Name: "import-a"}, got); err != nil {
+		t.Fatalf("endpointSlice Get(), got %v, want no error", err)
+	}
+	if diff := cmp.Diff([]discoveryv1.Endpoint{{Addresses: []string{"10.0.0.1"}}}, got.Endpoints); diff != "" {
+		t.Errorf("endpointSlice endpoints mismatch (-want, +got):\n%s", diff)
+	}
+	if gotOriginClusters := got.Annotations[objectmeta.ProvenanceAnnotationOriginClusterID]; gotOriginClusters != "cluster-a,cluster-b" {
+		t.Errorf("endpointSlice origin clusters = %q, want %q", gotOriginClusters, "cluster-a,cluster-b")
+	}
+}
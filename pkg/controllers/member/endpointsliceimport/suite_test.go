@@ -119,6 +119,7 @@ var _ = BeforeSuite(func() {
 	err = (&Reconciler{
 		MemberClient:         memberClient,
 		HubClient:            hubClient,
+		Recorder:             memberCtrlMgr.GetEventRecorderFor(ControllerName),
 		FleetSystemNamespace: fleetSystemNS,
 	}).SetupWithManager(ctx, memberCtrlMgr, hubCtrlMgr)
 	Expect(err).NotTo(HaveOccurred())
@@ -23,6 +23,9 @@ import (
 
 	fleetnetv1alpha1 "go.goms.io/fleet-networking/api/v1alpha1"
 	"go.goms.io/fleet-networking/pkg/common/apiretry"
+	"go.goms.io/fleet-networking/pkg/common/cloudconfig"
+	"go.goms.io/fleet-networking/pkg/common/networkingmode"
+	"go.goms.io/fleet-networking/pkg/common/objectmeta"
 )
 
 const (
@@ -38,9 +41,17 @@ type Reconciler struct {
 	MemberClient client.Client
 	HubClient    client.Client
 	AgentType    clusterv1beta1.AgentType
+
+	// NetworkProperties are the network properties of the member cluster, which are published on the internal member
+	// cluster as annotations; the empty properties are not published.
+	NetworkProperties cloudconfig.NetworkProperties
+
+	// NetworkingMode is set to the networking mode read from the labels of the internal member cluster, so that the
+	// member controllers turn the export or the import of Services on and off accordingly; nil skips the reading.
+	NetworkingMode *networkingmode.Gate
 }
 
-//+kubebuilder:rbac:groups=cluster.kubernetes-fleet.io,resources=internalmemberclusters,verbs=get;list;watch
+//+kubebuilder:rbac:groups=cluster.kubernetes-fleet.io,resources=internalmemberclusters,verbs=get;list;watch;patch
 //+kubebuilder:rbac:groups=cluster.kubernetes-fleet.io,resources=internalmemberclusters/status,verbs=get;update;patch
 //+kubebuilder:rbac:groups=networking.fleet.azure.com,resources=multiclusterservices,verbs=get;list;delete
 //+kubebuilder:rbac:groups=networking.fleet.azure.com,resources=serviceexports,verbs=get;list;delete
@@ -87,7 +98,12 @@ func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Resu
 		// Update the agent status.
 		return ctrl.Result{}, r.updateAgentStatus(ctx, &imc)
 	case clusterv1beta1.ClusterStateJoin:
-		// The member cluster still has an active membership in the fleet; update the agent status.
+		// The member cluster still has an active membership in the fleet; apply the networking mode, publish the
+		// network properties and update the agent status.
+		r.applyNetworkingMode(&imc)
+		if err := r.publishNetworkProperties(ctx, &imc); err != nil {
+			return ctrl.Result{}, err
+		}
 		if err := r.updateAgentStatus(ctx, &imc); err != nil {
 			return ctrl.Result{}, err
 		}
@@ -104,6 +120,63 @@ func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Resu
 	return ctrl.Result{}, nil
 }
 
+// applyNetworkingMode sets the networking mode of the member cluster from the label of the internal member cluster;
+// an unknown mode is reported and ignored, keeping the current mode.
+func (r *Reconciler) applyNetworkingMode(imc *clusterv1beta1.InternalMemberCluster) {
+	if r.NetworkingMode == nil {
+		return
+	}
+	value := imc.GetLabels()[objectmeta.MemberClusterLabelNetworkingMode]
+	mode, ok := networkingmode.Parse(value)
+	if !ok {
+		klog.ErrorS(fmt.Errorf("unknown networking mode %q", value), "Ignoring the networking mode of the internal member cluster",
+			"internalMemberCluster", klog.KObj(imc), "currentMode", r.NetworkingMode.Mode())
+		return
+	}
+	if r.NetworkingMode.Set(mode) {
+		klog.V(2).InfoS("Networking mode has changed", "internalMemberCluster", klog.KObj(imc), "mode", mode)
+	}
+}
+
+// publishNetworkProperties annotates the internal member cluster with the known network properties of the member
+// cluster, so that the hub cluster can mirror them onto the member cluster.
+func (r *Reconciler) publishNetworkProperties(ctx context.Context, imc *clusterv1beta1.InternalMemberCluster) error {
+	wantAnnotations := map[string]string{}
+	if r.NetworkProperties.Region != "" {
+		wantAnnotations[objectmeta.MemberClusterAnnotationRegion] = r.NetworkProperties.Region
+	}
+	if r.NetworkProperties.VNetID != "" {
+		wantAnnotations[objectmeta.MemberClusterAnnotationVNetID] = r.NetworkProperties.VNetID
+	}
+	changed := false
+	for k, v := range wantAnnotations {
+		if imc.GetAnnotations()[k] != v {
+			changed = true
+			break
+		}
+	}
+	if !changed {
+		return nil
+	}
+
+	imcKObj := klog.KObj(imc)
+	patch := client.MergeFrom(imc.DeepCopy())
+	annotations := imc.GetAnnotations()
+	if annotations == nil {
+		annotations = map[string]string{}
+	}
+	for k, v := range wantAnnotations {
+		annotations[k] = v
+	}
+	imc.SetAnnotations(annotations)
+	klog.V(2).InfoS("Publishing the network properties", "internalMemberCluster", imcKObj, "region", r.NetworkProperties.Region, "vnetID", r.NetworkProperties.VNetID)
+	if err := r.HubClient.Patch(ctx, imc, patch); err != nil {
+		klog.ErrorS(err, "Failed to publish the network properties", "internalMemberCluster", imcKObj)
+		return err
+	}
+	return nil
+}
+
 // updateAgentStatus reports the status of the agent via internal member cluster object.
 func (r *Reconciler) updateAgentStatus(ctx context.Context, imc *clusterv1beta1.InternalMemberCluster) error {
 	imcKObj := klog.KObj(imc)
@@ -21,6 +21,9 @@ import (
 	clusterv1beta1 "go.goms.io/fleet/apis/cluster/v1beta1"
 
 	fleetnetv1alpha1 "go.goms.io/fleet-networking/api/v1alpha1"
+	"go.goms.io/fleet-networking/pkg/common/cloudconfig"
+	"go.goms.io/fleet-networking/pkg/common/networkingmode"
+	"go.goms.io/fleet-networking/pkg/common/objectmeta"
 )
 
 const (
@@ -197,6 +200,134 @@ func TestUpdateAgentStatus(t *testing.T) {
 	}
 }
 
+// TestPublishNetworkProperties tests the publishNetworkProperties method.
+func TestPublishNetworkProperties(t *testing.T) {
+	vnetID := "/subscriptions/sub/resourceGroups/rg/providers/Microsoft.Network/virtualNetworks/vnet"
+	testCases := []struct {
+		name              string
+		annotations       map[string]string
+		networkProperties cloudconfig.NetworkProperties
+		wantAnnotations   map[string]string
+	}{
+		{
+			name: "no network properties",
+		},
+		{
+			name:              "network properties are published",
+			annotations:       map[string]string{"other": "value"},
+			networkProperties: cloudconfig.NetworkProperties{Region: "westus", VNetID: vnetID},
+			wantAnnotations: map[string]string{
+				"other":                                  "value",
+				objectmeta.MemberClusterAnnotationRegion: "westus",
+				objectmeta.MemberClusterAnnotationVNetID: vnetID,
+			},
+		},
+		{
+			name: "network properties are updated",
+			annotations: map[string]string{
+				objectmeta.MemberClusterAnnotationRegion: "eastus",
+				objectmeta.MemberClusterAnnotationVNetID: vnetID,
+			},
+			networkProperties: cloudconfig.NetworkProperties{Region: "westus"},
+			wantAnnotations: map[string]string{
+				objectmeta.MemberClusterAnnotationRegion: "westus",
+				objectmeta.MemberClusterAnnotationVNetID: vnetID,
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			imc := &clusterv1beta1.InternalMemberCluster{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:        memberClusterName,
+					Namespace:   memberClusterNamespace,
+					Annotations: tc.annotations,
+				},
+				Spec: clusterv1beta1.InternalMemberClusterSpec{
+					State: clusterv1beta1.ClusterStateJoin,
+				},
+			}
+			fakeHubClient := fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(imc).Build()
+			reconciler := &Reconciler{
+				MemberClient:      fake.NewClientBuilder().WithScheme(scheme.Scheme).Build(),
+				HubClient:         fakeHubClient,
+				AgentType:         clusterv1beta1.ServiceExportImportAgent,
+				NetworkProperties: tc.networkProperties,
+			}
+
+			ctx := context.Background()
+			if err := reconciler.publishNetworkProperties(ctx, imc); err != nil {
+				t.Fatalf("publishNetworkProperties() = %v, want no err", err)
+			}
+
+			got := &clusterv1beta1.InternalMemberCluster{}
+			if err := fakeHubClient.Get(ctx, types.NamespacedName{Namespace: memberClusterNamespace, Name: memberClusterName}, got); err != nil {
+				t.Fatalf("Get() internalMemberCluster = %v, want no error", err)
+			}
+			want := tc.wantAnnotations
+			if want == nil {
+				want = tc.annotations
+			}
+			if diff := cmp.Diff(want, got.GetAnnotations(), cmpopts.EquateEmpty()); diff != "" {
+				t.Errorf("internalMemberCluster annotations mismatch (-want, +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+// TestApplyNetworkingMode tests the applyNetworkingMode method.
+func TestApplyNetworkingMode(t *testing.T) {
+	testCases := []struct {
+		name        string
+		currentMode networkingmode.Mode
+		labels      map[string]string
+		wantMode    networkingmode.Mode
+	}{
+		{
+			name:        "no networking mode label",
+			currentMode: networkingmode.ModeExportOnly,
+			wantMode:    networkingmode.ModeBoth,
+		},
+		{
+			name:        "export only",
+			currentMode: networkingmode.ModeBoth,
+			labels:      map[string]string{objectmeta.MemberClusterLabelNetworkingMode: "export-only"},
+			wantMode:    networkingmode.ModeExportOnly,
+		},
+		{
+			name:        "import only",
+			currentMode: networkingmode.ModeExportOnly,
+			labels:      map[string]string{objectmeta.MemberClusterLabelNetworkingMode: "import-only"},
+			wantMode:    networkingmode.ModeImportOnly,
+		},
+		{
+			name:        "unknown networking mode is ignored",
+			currentMode: networkingmode.ModeImportOnly,
+			labels:      map[string]string{objectmeta.MemberClusterLabelNetworkingMode: "none"},
+			wantMode:    networkingmode.ModeImportOnly,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			gate := networkingmode.NewGate()
+			gate.Set(tc.currentMode)
+			reconciler := &Reconciler{NetworkingMode: gate}
+			reconciler.applyNetworkingMode(&clusterv1beta1.InternalMemberCluster{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      memberClusterName,
+					Namespace: memberClusterNamespace,
+					Labels:    tc.labels,
+				},
+			})
+			if got := gate.Mode(); got != tc.wantMode {
+				t.Errorf("applyNetworkingMode(), got mode %q, want %q", got, tc.wantMode)
+			}
+		})
+	}
+}
+
 // TestCleanupMCSRelatedResources tests the cleanupMCSRelatedResources method.
 func TestCleanupMCSRelatedResources(t *testing.T) {
 	multiClusterSvcs := []fleetnetv1alpha1.MultiClusterService{
@@ -104,6 +104,10 @@ func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Resu
 	// Check if the exported Service exists.
 	svcNS := internalSvcExport.Spec.ServiceReference.Namespace
 	svcName := internalSvcExport.Spec.ServiceReference.Name
+	if len(internalSvcExport.Spec.LocalServiceName) != 0 {
+		// The Service is exported under a different name.
+		svcName = internalSvcExport.Spec.LocalServiceName
+	}
 	svcExportRef := klog.KRef(svcNS, svcName)
 	var svcExport fleetnetv1alpha1.ServiceExport
 	err := r.MemberClient.Get(ctx, types.NamespacedName{Namespace: svcNS, Name: svcName}, &svcExport)
@@ -137,6 +141,12 @@ func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Resu
 		return ctrl.Result{}, err
 	}
 
+	// Report back the number of member clusters importing the Service.
+	if err := r.reportBackActiveImporterCount(ctx, &svcExport, &internalSvcExport); err != nil {
+		klog.ErrorS(err, "Failed to report back active importer count", "serviceExport", svcExportRef)
+		return ctrl.Result{}, err
+	}
+
 	// Observe a data point for the svcExportDuration metric.
 	// Note that an observation happens only when there is a conflict resolution result to report back.
 	if reported {
@@ -191,6 +201,22 @@ func (r *Reconciler) reportBackConflictCondition(ctx context.Context,
 	return true, r.MemberClient.Status().Update(ctx, svcExport)
 }
 
+// reportBackActiveImporterCount mirrors the number of member clusters importing the Service, as computed by the hub
+// cluster in the InternalServiceExport status, to the ServiceExport status in the member cluster.
+func (r *Reconciler) reportBackActiveImporterCount(ctx context.Context,
+	svcExport *fleetnetv1alpha1.ServiceExport,
+	internalSvcExport *fleetnetv1alpha1.InternalServiceExport) error {
+	if svcExport.Status.ActiveImporterCount == internalSvcExport.Status.ActiveImporterCount {
+		return nil
+	}
+	klog.V(2).InfoS("Report back active importer count",
+		"serviceExport", klog.KObj(svcExport),
+		"activeImporterCount", internalSvcExport.Status.ActiveImporterCount,
+		"oldActiveImporterCount", svcExport.Status.ActiveImporterCount)
+	svcExport.Status.ActiveImporterCount = internalSvcExport.Status.ActiveImporterCount
+	return r.MemberClient.Status().Update(ctx, svcExport)
+}
+
 // Observe data points for metrics.
 func (r *Reconciler) observeMetrics(ctx context.Context,
 	internalSvcExport *fleetnetv1alpha1.InternalServiceExport,
@@ -225,23 +251,16 @@ func (r *Reconciler) observeMetrics(ctx context.Context,
 			"internalServiceExport", klog.KObj(internalSvcExport))
 		return nil
 	}
-	timeSpent := startTime.Sub(internalSvcExport.Spec.ServiceReference.ExportedSince.Time).Milliseconds()
+	duration := startTime.Sub(internalSvcExport.Spec.ServiceReference.ExportedSince.Time)
 	// Under some rare circumstances (such as user manipulating the timestamps; note that for this specific metric
 	// clock drifts are less of an issue as all timestamps are from the same local lock), it could
 	// happen that the valid timestamp of an ServiceExport appears later than its conflict resolution timestamp.
-	// To avoid negative outliers affecting data analysis, this controller assigns a constant of exactly 1 second
-	// when the calculated duration does not make sense.
-	if timeSpent <= 0 {
-		timeSpent = time.Second.Milliseconds() * 1
+	if duration <= 0 {
 		klog.V(4).InfoS("A negative service export duration data point has been observed",
 			"serviceNamespacedName", internalSvcExport.Spec.ServiceReference.NamespacedName,
 			"originClusterID", internalSvcExport.Spec.ServiceReference.ClusterID)
 	}
-	// Similarly, to avoid large outliers skewing the stats (e.g. averages), this controller caps the data point
-	// to a constant value.
-	if timeSpent > int64(metrics.ExportDurationRightBound) {
-		timeSpent = int64(metrics.ExportDurationRightBound)
-	}
+	timeSpent := metrics.ClampExportDuration(duration).Milliseconds()
 	svcExportDuration.WithLabelValues(r.MemberClusterID).Observe(float64(timeSpent))
 	// TO-DO (chenyu1): Remove the metric logs when histogram metrics are supported in the backend.
 	klog.V(2).InfoS("serviceExportDurationMilliseconds",
@@ -216,6 +216,80 @@ func TestReportBackConflictCondition(t *testing.T) {
 	}
 }
 
+// TestReportBackActiveImporterCount tests the *Reconciler.reportBackActiveImporterCount method.
+func TestReportBackActiveImporterCount(t *testing.T) {
+	testCases := []struct {
+		name                    string
+		svcExportCount          int32
+		internalSvcExportCount  int32
+		wantActiveImporterCount int32
+	}{
+		{
+			name:                    "should report back new importers",
+			internalSvcExportCount:  2,
+			wantActiveImporterCount: 2,
+		},
+		{
+			name:                    "should report back withdrawn importers",
+			svcExportCount:          2,
+			wantActiveImporterCount: 0,
+		},
+		{
+			name:                    "should skip unchanged count",
+			svcExportCount:          1,
+			internalSvcExportCount:  1,
+			wantActiveImporterCount: 1,
+		},
+	}
+
+	ctx := context.Background()
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			svcExport := &fleetnetv1alpha1.ServiceExport{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: memberUserNS,
+					Name:      svcName,
+				},
+				Status: fleetnetv1alpha1.ServiceExportStatus{
+					ActiveImporterCount: tc.svcExportCount,
+				},
+			}
+			internalSvcExport := &fleetnetv1alpha1.InternalServiceExport{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: hubNSForMember,
+					Name:      internalSvcExportName,
+				},
+				Status: fleetnetv1alpha1.InternalServiceExportStatus{
+					ActiveImporterCount: tc.internalSvcExportCount,
+				},
+			}
+			fakeMemberClient := fake.NewClientBuilder().
+				WithScheme(scheme.Scheme).
+				WithObjects(svcExport).
+				WithStatusSubresource(svcExport).
+				Build()
+			reconciler := Reconciler{
+				MemberClient: fakeMemberClient,
+				HubClient:    fake.NewClientBuilder().Build(),
+				Recorder:     record.NewFakeRecorder(10),
+			}
+
+			if err := reconciler.reportBackActiveImporterCount(ctx, svcExport, internalSvcExport); err != nil {
+				t.Fatalf("reportBackActiveImporterCount() = %v, want no error", err)
+			}
+
+			var updatedSvcExport = &fleetnetv1alpha1.ServiceExport{}
+			if err := fakeMemberClient.Get(ctx, svcExportKey, updatedSvcExport); err != nil {
+				t.Fatalf("failed to get updated svc export: %v", err)
+			}
+			if got := updatedSvcExport.Status.ActiveImporterCount; got != tc.wantActiveImporterCount {
+				t.Fatalf("activeImporterCount, got %d, want %d", got, tc.wantActiveImporterCount)
+			}
+		})
+	}
+}
+
 // TestObserveMetrics tests the Reconciler.observeMetrics function.
 func TestObserveMetrics(t *testing.T) {
 	metricMetadata := `
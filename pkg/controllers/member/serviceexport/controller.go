@@ -14,25 +14,35 @@ import (
 	"strings"
 	"time"
 
-	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/network/armnetwork/v4"
 	corev1 "k8s.io/api/core/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
-	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/validation"
 	"k8s.io/client-go/tools/record"
 	"k8s.io/klog/v2"
-	"sigs.k8s.io/cloud-provider-azure/pkg/azclient/publicipaddressclient"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/event"
 	"sigs.k8s.io/controller-runtime/pkg/handler"
-
-	"go.goms.io/fleet/pkg/utils/controller"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
 
 	fleetnetv1alpha1 "go.goms.io/fleet-networking/api/v1alpha1"
+	"go.goms.io/fleet-networking/pkg/common/cloudconfig"
 	"go.goms.io/fleet-networking/pkg/common/condition"
+	"go.goms.io/fleet-networking/pkg/common/fieldmanager"
+	"go.goms.io/fleet-networking/pkg/common/hubclient"
+	"go.goms.io/fleet-networking/pkg/common/hubconfig"
 	"go.goms.io/fleet-networking/pkg/common/metrics"
+	"go.goms.io/fleet-networking/pkg/common/networkingmode"
 	"go.goms.io/fleet-networking/pkg/common/objectmeta"
 )
 
@@ -40,7 +50,21 @@ const (
 	svcExportValidCondReason                 = "ServiceIsValid"
 	svcExportInvalidNotFoundCondReason       = "ServiceNotFound"
 	svcExportInvalidIneligibleCondReason     = "ServiceIneligible"
+	svcExportInvalidExportedNameCondReason   = "ExportedNameInvalid"
+	svcExportNamespaceNotOnboardedReason     = "NamespaceNotOnboarded"
+	svcExportExportDisabledReason            = "ExportDisabled"
 	svcExportPendingConflictResolutionReason = "ServicePendingConflictResolution"
+	svcExportNoReadyBackendsCondReason       = "NoReadyEndpoints"
+	svcExportReadyBackendsFoundCondReason    = "ReadyEndpointsFound"
+	svcExportLBPendingCondReason             = "LoadBalancerIPPending"
+	svcExportLBProvisionedCondReason         = "LoadBalancerIPProvisioned"
+	svcExportPublicIPValidCondReason         = "PublicIPValid"
+	svcExportPublicIPInvalidCondReason       = "PublicIPInvalid"
+	svcExportDNSLabelNotSetCondReason        = "DNSLabelNotSet"
+
+	// invalidPublicIPRetryInterval is the maximum interval at which an invalid public IP address specified for a
+	// Service is revalidated, as it can be fixed outside of the cluster, e.g. by configuring its DNS label.
+	invalidPublicIPRetryInterval = time.Minute
 
 	// svcExportCleanupFinalizer is the finalizer ServiceExport controllers adds to mark that
 	// a ServiceExport can only be deleted after its corresponding Service has been unexported from the hub cluster.
@@ -57,19 +81,50 @@ type Reconciler struct {
 	HubClient       client.Client
 	// The namespace reserved for the current member cluster in the hub cluster.
 	HubNamespace string
-	Recorder     record.EventRecorder
-
-	ResourceGroupName          string // default resource group name to create public IP address
-	AzurePublicIPAddressClient publicipaddressclient.Interface
+	// HubExportShards is the number of namespaces in the hub cluster, the member cluster namespace included, across
+	// which the exports of the member cluster are sharded by Service; at most 1 keeps all the exports in HubNamespace.
+	HubExportShards int
+	Recorder        record.EventRecorder
 
+	// CloudProvider populates the load balancer information of the exported Services when the Traffic Manager
+	// feature is enabled.
+	CloudProvider               CloudProvider
 	EnableTrafficManagerFeature bool
+
+	// HeartbeatInterval is how often the heartbeat of an exported Service is refreshed in the hub cluster, even
+	// when nothing has changed; 0 disables the heartbeat.
+	HeartbeatInterval time.Duration
+
+	// RequiredNamespaceLabels are the labels a namespace must have before the ServiceExports in it are honored, so
+	// that fleet admins control which namespaces participate in multi-cluster networking; the Services of a namespace
+	// are unexported once it loses any of the labels. Empty disables the check.
+	RequiredNamespaceLabels labels.Set
+
+	// NetworkProperties are the network properties of the member cluster, which are copied to the exported Services
+	// so that the importing clusters can tell whether the endpoints of the Services are reachable.
+	NetworkProperties cloudconfig.NetworkProperties
+
+	// NetworkingMode is the networking mode of the member cluster; the Services are unexported while the member
+	// cluster is in the import-only mode. Nil enables the export.
+	NetworkingMode *networkingmode.Gate
+
+	// DeletionProtectionGracePeriod is how long the unexport of a deleted ServiceExport with deletion protection
+	// enabled is delayed while other member clusters still import the Service; 0 disables the protection.
+	DeletionProtectionGracePeriod time.Duration
+
+	// HubServerChanges receives an event every time the hub clients are rotated to another hub server, so that all
+	// the Services are exported again, as the new hub server might not have them. Nil disables the re-export.
+	HubServerChanges <-chan event.GenericEvent
 }
 
 //+kubebuilder:rbac:groups=networking.fleet.azure.com,resources=serviceexports,verbs=get;list;watch;create;update;patch;delete
 //+kubebuilder:rbac:groups=networking.fleet.azure.com,resources=serviceexports/status,verbs=get;update;patch
 //+kubebuilder:rbac:groups=networking.fleet.azure.com,resources=serviceexports/finalizers,verbs=update
 //+kubebuilder:rbac:groups=networking.fleet.azure.com,resources=internalserviceexports,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups=networking.fleet.azure.com,resources=internalserviceexports/status,verbs=get;update;patch
 //+kubebuilder:rbac:groups="",resources=services,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups="",resources=namespaces,verbs=get;list;watch
+//+kubebuilder:rbac:groups=discovery.k8s.io,resources=endpointslices,verbs=get;list;watch
 //+kubebuilder:rbac:groups="",resources=events,verbs=create;patch
 
 // Reconcile exports a Service.
@@ -104,6 +159,16 @@ func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Resu
 	// is needed.
 	if svcExport.DeletionTimestamp != nil {
 		if controllerutil.ContainsFinalizer(&svcExport, svcExportCleanupFinalizer) {
+			if remaining := r.deletionProtectionRemaining(&svcExport, time.Now()); remaining > 0 {
+				klog.V(2).InfoS("Service export is deleted but the service is still imported; delay the unexport",
+					"service", svcRef,
+					"activeImporterCount", svcExport.Status.ActiveImporterCount,
+					"remaining", remaining)
+				r.Recorder.Eventf(&svcExport, corev1.EventTypeWarning, "UnexportDelayed",
+					"Service %s is still imported by %d member cluster(s); it will be unexported in %s, or as soon as no member cluster imports it",
+					svcExport.Name, svcExport.Status.ActiveImporterCount, remaining.Round(time.Second))
+				return ctrl.Result{RequeueAfter: remaining}, nil
+			}
 			klog.V(4).InfoS("Service export is deleted; unexport the service", "service", svcRef)
 			res, err := r.unexportService(ctx, &svcExport)
 			if err != nil {
@@ -114,6 +179,72 @@ func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Resu
 		return ctrl.Result{}, nil
 	}
 
+	// Check if the namespace of the ServiceExport is being deleted; its Services and ServiceExports are then deleted
+	// in no particular order, and marking the ServiceExports as invalid as their Services disappear would only churn
+	// the hub cluster and the importing member clusters moments before the ServiceExports are gone. Instead, all the
+	// Services of the namespace are unexported quietly at once.
+	isTerminating, err := r.isNamespaceTerminating(ctx, req.Namespace)
+	if err != nil {
+		klog.ErrorS(err, "Failed to check if the namespace is being deleted", "service", svcRef)
+		return ctrl.Result{}, err
+	}
+	if isTerminating {
+		klog.V(2).InfoS("Namespace is being deleted; unexport the services of the namespace", "service", svcRef)
+		if err := r.unexportTerminatingNamespace(ctx, &svcExport); err != nil {
+			klog.ErrorS(err, "Failed to unexport the service", "service", svcRef)
+			return ctrl.Result{}, err
+		}
+		return ctrl.Result{}, nil
+	}
+
+	// Check if the member cluster is allowed to export Services.
+	if !r.NetworkingMode.ExportEnabled() {
+		// Unexport the Service if the ServiceExport has the cleanup finalizer added.
+		if controllerutil.ContainsFinalizer(&svcExport, svcExportCleanupFinalizer) {
+			klog.V(4).InfoS("Export is disabled; unexport the service", "service", svcRef)
+			r.Recorder.Eventf(&svcExport, corev1.EventTypeNormal, "ExportDisabled", "Member cluster is in the %s networking mode; the service is unexported", r.NetworkingMode.Mode())
+			if _, err := r.unexportService(ctx, &svcExport); err != nil {
+				klog.ErrorS(err, "Failed to unexport the service", "service", svcRef)
+				return ctrl.Result{}, err
+			}
+		}
+		// Mark the ServiceExport as invalid.
+		klog.V(4).InfoS("Mark service export as invalid (export disabled)", "service", svcRef)
+		err := r.markServiceExportAsInvalidExportDisabled(ctx, &svcExport)
+		if err != nil {
+			klog.ErrorS(err, "Failed to mark service export as invalid (export disabled)", "service", svcRef)
+		}
+		return ctrl.Result{}, err
+	}
+
+	// Check if the namespace of the ServiceExport has been onboarded to multi-cluster networking.
+	if len(r.RequiredNamespaceLabels) > 0 {
+		isOnboarded, err := r.isNamespaceOnboarded(ctx, req.Namespace)
+		if err != nil {
+			klog.ErrorS(err, "Failed to check if the namespace is onboarded", "service", svcRef)
+			return ctrl.Result{}, err
+		}
+		if !isOnboarded {
+			r.Recorder.Eventf(&svcExport, corev1.EventTypeWarning, "NamespaceNotOnboarded", "Namespace %s is not onboarded to multi-cluster networking", req.Namespace)
+
+			// Unexport the Service if the ServiceExport has the cleanup finalizer added.
+			if controllerutil.ContainsFinalizer(&svcExport, svcExportCleanupFinalizer) {
+				klog.V(4).InfoS("Namespace is not onboarded; unexport the service", "service", svcRef)
+				if _, err := r.unexportService(ctx, &svcExport); err != nil {
+					klog.ErrorS(err, "Failed to unexport the service", "service", svcRef)
+					return ctrl.Result{}, err
+				}
+			}
+			// Mark the ServiceExport as invalid.
+			klog.V(4).InfoS("Mark service export as invalid (namespace not onboarded)", "service", svcRef)
+			err := r.markServiceExportAsInvalidNamespaceNotOnboarded(ctx, &svcExport)
+			if err != nil {
+				klog.ErrorS(err, "Failed to mark service export as invalid (namespace not onboarded)", "service", svcRef)
+			}
+			return ctrl.Result{}, err
+		}
+	}
+
 	// Check if the Service to export exists.
 	svc := corev1.Service{
 		ObjectMeta: metav1.ObjectMeta{
@@ -121,7 +252,7 @@ func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Resu
 			Name:      req.Name,
 		},
 	}
-	err := r.MemberClient.Get(ctx, req.NamespacedName, &svc)
+	err = r.MemberClient.Get(ctx, req.NamespacedName, &svc)
 	switch {
 	// The Service to export does not exist or has been deleted.
 	case apierrors.IsNotFound(err) || svc.DeletionTimestamp != nil:
@@ -169,6 +300,56 @@ func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Resu
 		return ctrl.Result{}, err
 	}
 
+	// Check if the name under which the Service is exported is valid.
+	exportedName := objectmeta.ExportedServiceName(&svcExport)
+	if errs := validation.IsDNS1035Label(exportedName); len(errs) != 0 {
+		r.Recorder.Eventf(&svcExport, corev1.EventTypeWarning, "InvalidExportedName", "Exported name %q of service %s is invalid: %s", exportedName, svc.Name, strings.Join(errs, "; "))
+
+		// Unexport the Service if the ServiceExport has the cleanup finalizer added.
+		if controllerutil.ContainsFinalizer(&svcExport, svcExportCleanupFinalizer) {
+			klog.V(4).InfoS("Exported name is invalid; unexport the service", "service", svcRef, "exportedName", exportedName)
+			if _, err = r.unexportService(ctx, &svcExport); err != nil {
+				klog.ErrorS(err, "Failed to unexport the service", "service", svcRef)
+				return ctrl.Result{}, err
+			}
+		}
+		// Mark the ServiceExport as invalid.
+		klog.V(4).InfoS("Mark service export as invalid (exported name invalid)", "service", svcRef, "exportedName", exportedName)
+		err := r.markServiceExportAsInvalidExportedName(ctx, &svcExport, &svc, exportedName)
+		if err != nil {
+			klog.ErrorS(err, "Failed to mark service export as invalid (exported name invalid)", "service", svcRef)
+		}
+		return ctrl.Result{}, err
+	}
+
+	// Preview the export of the pending changes to the Service instead of exporting them while the ServiceExport is in
+	// the preview mode; nothing is written to the hub cluster but the heartbeat of the current export, so that the
+	// exported Service is not considered stale meanwhile.
+	if isPreviewEnabled(&svcExport) {
+		klog.V(4).InfoS("Service export is in the preview mode; preview the export of the service", "service", svcRef)
+		existingInternalSvcExport, err := r.previewExport(ctx, &svcExport, &svc)
+		if err != nil {
+			klog.ErrorS(err, "Failed to preview the export of the service", "service", svcRef)
+			return ctrl.Result{}, err
+		}
+		if existingInternalSvcExport != nil && r.HeartbeatInterval != 0 {
+			if err := r.refreshHeartbeat(ctx, existingInternalSvcExport, time.Now()); err != nil {
+				klog.ErrorS(err, "Failed to refresh the heartbeat of InternalServiceExport",
+					"internalServiceExport", klog.KObj(existingInternalSvcExport),
+					"service", svcRef)
+				return ctrl.Result{}, err
+			}
+		}
+		return ctrl.Result{RequeueAfter: previewRefreshInterval}, nil
+	}
+	if svcExport.Status.Preview != nil {
+		klog.V(4).InfoS("Service export is no longer in the preview mode; clear the preview", "service", svcRef)
+		if err := r.updatePreview(ctx, &svcExport, nil); err != nil {
+			klog.ErrorS(err, "Failed to clear the preview of service export", "service", svcRef)
+			return ctrl.Result{}, err
+		}
+	}
+
 	// Add the cleanup finalizer to the ServiceExport; this must happen before the Service is actually exported.
 	if !controllerutil.ContainsFinalizer(&svcExport, svcExportCleanupFinalizer) {
 		klog.V(4).InfoS("Add cleanup finalizer to service export", "service", svcRef)
@@ -185,6 +366,16 @@ func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Resu
 		return ctrl.Result{}, err
 	}
 
+	// Report whether the Service has any ready backend; this is informational only and a failure here does not
+	// block the export.
+	if err := r.updateNoReadyBackendsCondition(ctx, &svcExport, &svc); err != nil {
+		klog.ErrorS(err, "Failed to update the no ready backends condition of service export", "service", svcRef)
+	}
+	lbPending := isLoadBalancerPending(&svc)
+	if err := r.updateLoadBalancerPendingCondition(ctx, &svcExport, &svc, lbPending); err != nil {
+		klog.ErrorS(err, "Failed to update the load balancer pending condition of service export", "service", svcRef)
+	}
+
 	// Retrieve the last seen resource version and the last seen timestamp; these two values are used for metric collection.
 	// If the two values are not present or not valid, annotate ServiceExport with new values.
 	//
@@ -196,181 +387,324 @@ func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Resu
 
 	// Export the Service or update the exported Service.
 
-	// Create or update the InternalServiceExport object.
-	internalSvcExport := fleetnetv1alpha1.InternalServiceExport{
-		ObjectMeta: metav1.ObjectMeta{
-			Namespace: r.HubNamespace,
-			Name:      formatInternalServiceExportName(&svcExport),
-		},
-	}
-	svcExportPorts := extractServicePorts(&svc)
+	// Apply the InternalServiceExport object.
+	internalSvcExportKey := types.NamespacedName{Namespace: r.hubExportNamespace(&svcExport), Name: formatInternalServiceExportName(&svcExport)}
 	klog.V(2).InfoS("Export the service or update the exported service",
 		"service", svcExport,
-		"internalServiceExport", klog.KObj(&internalSvcExport))
-	createOrUpdateOp, err := controllerutil.CreateOrUpdate(ctx, r.HubClient, &internalSvcExport, func() error {
-		if internalSvcExport.CreationTimestamp.IsZero() {
-			// Set the ServiceReference only when the InternalServiceExport is created; most of the fields in
-			// an ExportedObjectReference should be immutable.
-			internalSvcExport.Spec.ServiceReference = fleetnetv1alpha1.FromMetaObjects(r.MemberClusterID,
-				svc.TypeMeta, svc.ObjectMeta, metav1.NewTime(exportedSince))
-		}
-
-		// Return an error if an attempt is made to update an InternalServiceExport that references a different
-		// Service from the one that is being reconciled. This usually happens when a service is deleted and
-		// re-created immediately.
-		if internalSvcExport.Spec.ServiceReference.UID != svc.UID {
-			klog.V(4).InfoS("Failed to create/update internalServiceExport, UIDs mismatch",
+		"internalServiceExport", internalSvcExportKey)
+
+	// Most of the fields in an ExportedObjectReference should be immutable after creation; read them from the
+	// existing InternalServiceExport, if any.
+	existingInternalSvcExport := fleetnetv1alpha1.InternalServiceExport{}
+	var svcReference fleetnetv1alpha1.ExportedObjectReference
+	isExported := false
+	switch err := r.HubClient.Get(ctx, internalSvcExportKey, &existingInternalSvcExport); {
+	case err == nil:
+		isExported = true
+		// Check if the existing InternalServiceExport references a different Service from the one that is being
+		// reconciled, or exports the Service under a different name. This usually happens when a service is
+		// deleted and re-created immediately, or when the exported name annotation of the ServiceExport is changed.
+		existingRef := existingInternalSvcExport.Spec.ServiceReference
+		if existingRef.UID != svc.UID || existingRef.Name != exportedName {
+			klog.V(4).InfoS("Failed to apply internalServiceExport, UIDs or exported names mismatch",
 				"service", svcRef,
-				"internalServiceExport", klog.KObj(&internalSvcExport),
+				"internalServiceExport", internalSvcExportKey,
 				"newUID", svc.UID,
-				"oldUID", internalSvcExport.Spec.ServiceReference.UID)
-			// The AlreadyExists error returned here features a different GVR source (service, rather than
-			// internalServiceExport); such an error would never be yielded in the normal workflow.
-			return apierrors.NewAlreadyExists(
-				schema.GroupResource{Group: fleetnetv1alpha1.GroupVersion.Group, Resource: "Service"},
-				fmt.Sprintf("%s/%s", svc.Namespace, svc.Name),
-			)
-		}
-
-		internalSvcExport.Spec.Ports = svcExportPorts
-		internalSvcExport.Spec.ServiceReference.UpdateFromMetaObject(svc.ObjectMeta, metav1.NewTime(exportedSince))
-
-		if r.EnableTrafficManagerFeature {
-			klog.V(2).InfoS("Collecting Traffic Manager related information", "service", svcRef)
-			if err := r.setAzureRelatedInformation(ctx, &svc, &internalSvcExport); err != nil {
-				klog.ErrorS(err, "Failed to populate the Azure information for the Traffic Manager feature", "service", svcRef)
-				return err
+				"oldUID", existingRef.UID,
+				"newExportedName", exportedName,
+				"oldExportedName", existingRef.Name)
+			// Unexport the Service first, and requeue a new attempt to export the Service.
+			if _, err := r.unexportService(ctx, &svcExport); err != nil {
+				klog.ErrorS(err, "Failed to unexport the service", "service", svcRef)
+				return ctrl.Result{}, err
 			}
+			// Unexporting a Service removes the cleanup finalizer from the ServiceExport, which in normal cases
+			// will trigger another reconciliation loop automatically; for better clarity here the controller requests
+			// the new reconciliation attempt explicitly.
+			return ctrl.Result{Requeue: true}, nil
 		}
-		return nil
-	})
-	statusErr := &apierrors.StatusError{}
-	ok := errors.As(err, &statusErr)
-	switch {
-	case apierrors.IsAlreadyExists(err) && ok && statusErr.Status().Details.Kind == "Service":
-		// An export with the same key but different UID already exists; unexport the Service first, and
-		// requeue a new attempt to export the Service.
-		// Additional checks are performed here as two forms of AlreadyExists error can be returned in the CreateOrUpdate
-		// call: it could be that an actual UID mismatch is found, however, since CreateOrUpdate is, in essence, a two-part op
-		// (the function first gets the object, and then decides whether to create the object or update it according to the get
-		// result), a racing condition may lead to an AlreadyExists error being yielded even if there is no UID mismatch at all.
-		// This can happen, albeit quite rarely, when the system is under heavy load, and the informers cannot sync caches
-		// fast enough; the out-of-date cache will return that an object does not exist when read, even though the object is
-		// already present in the persistent store, and any subsequent create call would fail.
-		if _, err := r.unexportService(ctx, &svcExport); err != nil {
-			klog.ErrorS(err, "Failed to unexport the service", "service", svcRef)
+		// InternalServiceExports written before the member agent switched to server-side apply have their fields
+		// owned by the Update operations of the agent; hand them over to the apply field manager, so that the
+		// fields the agent no longer sets (e.g., the ports removed from the Service) are dropped.
+		if err := fieldmanager.UpgradeManagedFields(ctx, r.HubClient, &existingInternalSvcExport,
+			objectmeta.MemberAgentFieldManager, objectmeta.MemberAgentLegacyFieldManager); err != nil {
+			klog.ErrorS(err, "Failed to upgrade the managed fields of internalServiceExport", "internalServiceExport", internalSvcExportKey, "service", svcRef)
 			return ctrl.Result{}, err
 		}
-		// Unexporting a Service removes the cleanup finalizer from the ServiceExport, which in normal cases
-		// will trigger another reconciliation loop automatically; for better clarity here the controller requests
-		// the new reconciliation attempt explicitly.
-		return ctrl.Result{Requeue: true}, nil
-	case err != nil:
-		klog.ErrorS(err, "Failed to create/update InternalServiceExport",
-			"internalServiceExport", klog.KObj(&internalSvcExport),
-			"service", svcRef,
-			"op", createOrUpdateOp)
+		svcReference = existingRef
+	case apierrors.IsNotFound(err):
+		svcReference = newServiceReference(r.MemberClusterID, &svc, exportedName, exportedSince)
+	default:
+		klog.ErrorS(err, "Failed to get internalServiceExport", "internalServiceExport", internalSvcExportKey, "service", svcRef)
 		return ctrl.Result{}, err
 	}
-	return ctrl.Result{}, nil
-}
+	svcReference.UpdateFromMetaObject(svc.ObjectMeta, metav1.NewTime(exportedSince))
 
-func (r *Reconciler) setAzureRelatedInformation(ctx context.Context, service *corev1.Service, export *fleetnetv1alpha1.InternalServiceExport) error {
-	export.Spec.Type = service.Spec.Type
-	if service.Spec.Type != corev1.ServiceTypeLoadBalancer {
-		return nil
+	internalSvcExport := BuildInternalServiceExport(internalSvcExportKey, r.MemberClusterID, &svc, &svcExport, svcReference, r.NetworkProperties)
+	var publicIPErr, dnsLabelErr error
+	if r.EnableTrafficManagerFeature {
+		publicIPResourceID, isPublicIPExplicit := explicitPublicIPResourceID(&svcExport)
+		klog.V(2).InfoS("Collecting Traffic Manager related information", "service", svcRef, "isLoadBalancerPending", lbPending, "publicIPResourceID", publicIPResourceID)
+		publicIPErr, dnsLabelErr, err = SetTrafficManagerInformation(ctx, r.CloudProvider, &svc, &svcExport, &internalSvcExport)
+		if err != nil {
+			klog.ErrorS(err, "Failed to populate the Traffic Manager related information", "service", svcRef, "publicIPResourceID", publicIPResourceID)
+			return ctrl.Result{}, err
+		}
+		if err := r.updatePublicIPValidCondition(ctx, &svcExport, isPublicIPExplicit, publicIPResourceID, publicIPErr); err != nil {
+			klog.ErrorS(err, "Failed to update the public IP valid condition", "serviceExport", svcRef)
+			return ctrl.Result{}, err
+		}
+		if err := r.updateDNSLabelConfiguredCondition(ctx, &svcExport, dnsLabelErr); err != nil {
+			klog.ErrorS(err, "Failed to update the DNS label configured condition", "serviceExport", svcRef)
+			return ctrl.Result{}, err
+		}
 	}
-	// The annotation value is case-sensitive.
-	// https://github.com/kubernetes-sigs/cloud-provider-azure/blob/release-1.31/pkg/provider/azure_loadbalancer.go#L3559
-	export.Spec.IsInternalLoadBalancer = service.Annotations[objectmeta.ServiceAnnotationAzureLoadBalancerInternal] == "true"
-	if export.Spec.IsInternalLoadBalancer {
-		// no need to populate the PublicIPResourceID and IsDNSLabelConfigured which are only applicable for external load balancer
-		return nil
+	weight, err := exportWeight(&svcExport)
+	if err != nil {
+		r.Recorder.Eventf(&svcExport, corev1.EventTypeWarning, "InvalidWeight", "Weight of service %s is invalid and left unchanged: %v", svc.Name, err)
+		weight = existingInternalSvcExport.Spec.Weight
+	}
+	internalSvcExport.Spec.Weight = weight
+	if isExported && isOnlyWeightChanged(&existingInternalSvcExport, &internalSvcExport) {
+		// Patch the weight alone, so that a weight change takes effect without the whole InternalServiceExport being
+		// rewritten, and without the hub cluster evaluating the conflicts of the export again.
+		klog.V(2).InfoS("Only the weight of the exported service has changed; patch the weight", "service", svcRef,
+			"internalServiceExport", internalSvcExportKey, "weight", weight, "oldWeight", existingInternalSvcExport.Spec.Weight)
+		if err := r.patchWeight(ctx, &existingInternalSvcExport, weight); err != nil {
+			return ctrl.Result{}, err
+		}
+		internalSvcExport = existingInternalSvcExport
+	} else {
+		if err := hubclient.EnsureInternalServiceExport(ctx, r.HubClient, &internalSvcExport, "service", svcRef); err != nil {
+			return ctrl.Result{}, err
+		}
+		// A weight patched alone is owned by a field manager of its own, which the apply does not remove once the
+		// weight annotation is gone.
+		if !equality.Semantic.DeepEqual(internalSvcExport.Spec.Weight, weight) {
+			if err := r.patchWeight(ctx, &internalSvcExport, weight); err != nil {
+				return ctrl.Result{}, err
+			}
+		}
 	}
 
-	serviceKObj := klog.KObj(service)
-	if len(service.Status.LoadBalancer.Ingress) == 0 {
-		// Assuming once the service status is updated, the controller will be triggered again.
-		klog.V(2).InfoS("The load balancer IP is not assigned yet", "service", serviceKObj)
-		return nil
+	if r.HeartbeatInterval != 0 {
+		if err := r.refreshHeartbeat(ctx, &internalSvcExport, time.Now()); err != nil {
+			klog.ErrorS(err, "Failed to refresh the heartbeat of InternalServiceExport",
+				"internalServiceExport", internalSvcExportKey,
+				"service", svcRef)
+			return ctrl.Result{}, err
+		}
+	}
+	if lbPending {
+		// The update of the Service status triggers another reconciliation once the IP is provisioned; the request
+		// is still requeued, with the backoff of the rate limiter, in case the cloud provider gets stuck.
+		klog.V(2).InfoS("Requeue the request as the load balancer IP is not yet provisioned", "service", svcRef)
+		return ctrl.Result{Requeue: true}, nil
 	}
+	// Requeue to refresh the heartbeat periodically even if nothing has changed.
+	requeueAfter := r.HeartbeatInterval
+	if publicIPErr != nil && (requeueAfter == 0 || requeueAfter > invalidPublicIPRetryInterval) {
+		klog.V(2).InfoS("Requeue the request to revalidate the public IP address", "service", svcRef, "error", publicIPErr)
+		requeueAfter = invalidPublicIPRetryInterval
+	}
+	return ctrl.Result{RequeueAfter: requeueAfter}, nil
+}
 
-	if service.Status.LoadBalancer.Ingress[0].IP == "" {
-		err := errors.New("the service ingress is not nil but with empty IP")
-		klog.ErrorS(controller.NewUnexpectedBehaviorError(err), "Failed to get the load balancer IP from service", "service", serviceKObj, "status", service.Status)
-		return nil
+// setExternalTrafficPolicyInformation populates the external traffic policy and the health check node port of a load
+// balancer Service, so that the hub cluster can tell whether the Azure Traffic Manager health checks are served by
+// every node or only by the nodes running the ready pods of the Service.
+func setExternalTrafficPolicyInformation(svc *corev1.Service, export *fleetnetv1alpha1.InternalServiceExport) {
+	if svc.Spec.Type != corev1.ServiceTypeLoadBalancer {
+		return
 	}
+	export.Spec.ExternalTrafficPolicy = svc.Spec.ExternalTrafficPolicy
+	export.Spec.HealthCheckNodePort = svc.Spec.HealthCheckNodePort
+}
 
-	pip, err := r.lookupPublicIPResourceIDByLoadBalancerIP(ctx, service)
-	if err != nil {
-		return err
+// BuildInternalServiceExport builds the InternalServiceExport of an exported Service from its desired state only,
+// which is applied in the hub cluster under the given key; the Traffic Manager related information is populated
+// separately by SetTrafficManagerInformation.
+func BuildInternalServiceExport(key types.NamespacedName, memberClusterID string, svc *corev1.Service, svcExport *fleetnetv1alpha1.ServiceExport,
+	svcReference fleetnetv1alpha1.ExportedObjectReference, networkProperties cloudconfig.NetworkProperties) fleetnetv1alpha1.InternalServiceExport {
+	// The InternalServiceExport is applied with server-side apply; the member agent owns the fields it sets, and the
+	// fields (e.g. labels, annotations and finalizers) added by the hub side are left untouched. Fields owned by the
+	// member agent but no longer set are removed.
+	internalSvcExport := fleetnetv1alpha1.InternalServiceExport{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: fleetnetv1alpha1.GroupVersion.String(),
+			Kind:       "InternalServiceExport",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: key.Namespace,
+			Name:      key.Name,
+			Labels: map[string]string{
+				objectmeta.ExportedObjectLabelMemberCluster: memberClusterID,
+			},
+		},
+		Spec: fleetnetv1alpha1.InternalServiceExportSpec{
+			Ports:            extractServicePorts(svc),
+			ServiceReference: svcReference,
+			AddressFamilies:  svc.Spec.IPFamilies,
+			Headless:         svc.Spec.ClusterIP == corev1.ClusterIPNone,
+			ClusterRegion:    networkProperties.Region,
+			ClusterVNetID:    networkProperties.VNetID,
+			ConsumerPolicy:   svcExport.Spec.ConsumerPolicy,
+		},
 	}
-	if pip == nil {
-		klog.V(2).InfoS("The public IP is in the progressing", "service", serviceKObj, "ip", service.Status.LoadBalancer.Ingress[0].IP)
-		// Assuming once the service status is updated, the controller will be triggered again in instead of retrying here
-		// to avoid sending Azure requests.
-		return nil
+	if exportedName := objectmeta.ExportedServiceName(svcExport); exportedName != svc.Name {
+		internalSvcExport.Spec.LocalServiceName = svc.Name
 	}
-	export.Spec.PublicIPResourceID = pip.ID
+	return internalSvcExport
+}
 
-	// Note the user can set the dns label via the Azure portal or Azure CLI without updating service.
-	// This information may be stale as we don't monitor the public IP address resource.
-	export.Spec.IsDNSLabelConfigured = pip.Properties != nil && pip.Properties.DNSSettings != nil && pip.Properties.DNSSettings.DomainNameLabel != nil
+// explicitPublicIPResourceID returns the public IP address specified explicitly for the exported Service, if any.
+func explicitPublicIPResourceID(svcExport *fleetnetv1alpha1.ServiceExport) (string, bool) {
+	publicIPResourceID, isPublicIPExplicit := svcExport.Annotations[objectmeta.ServiceExportAnnotationAzurePublicIPResourceID]
+	return strings.TrimSpace(publicIPResourceID), isPublicIPExplicit
+}
 
-	// No matter if the customer bring your own IP or not, the cloud provider will reconcile the DNS label based on the
-	// DNS annotation.
-	dnsName, found := service.Annotations[objectmeta.ServiceAnnotationAzureDNSLabelName]
-	klog.V(2).InfoS("Finding whether the DNS is assigned", "service", serviceKObj, "dnsName", dnsName, "isSetOnService", found, "isConfiguredOnPIP", export.Spec.IsDNSLabelConfigured)
-	// If the annotation is not set, the cloud provider won't reconcile the DNS label and return the current status.
-	if !found {
-		// cloud provider won't delete DNS label on pip if the annotation is not set.
-		return nil
+// SetTrafficManagerInformation populates the Traffic Manager related information of an exported Service in its
+// InternalServiceExport with the cloud provider. The information is derived from the current state of the Service
+// only; once the Service is no longer of the LoadBalancer type or loses its load balancer ingress, the fields are left
+// unset and hence removed from the InternalServiceExport, so that no Traffic Manager endpoint keeps targeting a public
+// IP address which may have been released.
+//
+// An invalid public IP address and a DNS label which cannot be set are returned as publicIPErr and dnsLabelErr
+// respectively, with the Service still exported without them, so that they are reported on the ServiceExport; any
+// other error is returned as err and should be retried.
+func SetTrafficManagerInformation(ctx context.Context, provider CloudProvider, svc *corev1.Service, svcExport *fleetnetv1alpha1.ServiceExport,
+	internalSvcExport *fleetnetv1alpha1.InternalServiceExport) (publicIPErr, dnsLabelErr, err error) {
+	// Mark the Traffic Manager related fields as reported, so that the hub agent tells the fields set to their zero
+	// values from the ones left unset by the member agents which do not report them.
+	if internalSvcExport.Annotations == nil {
+		internalSvcExport.Annotations = map[string]string{}
 	}
-	if len(dnsName) == 0 {
-		export.Spec.IsDNSLabelConfigured = false // cloud provider will delete the DNS label on the pip.
-		return nil
+	internalSvcExport.Annotations[objectmeta.InternalServiceExportAnnotationTrafficManagerInfoReported] = "true"
+	if publicIPResourceID, isPublicIPExplicit := explicitPublicIPResourceID(svcExport); isPublicIPExplicit {
+		// The Service is exposed by another resource, e.g. a Gateway, with the specified public IP address instead
+		// of its own load balancer.
+		publicIPErr = provider.SetPublicIPInformation(ctx, svc, publicIPResourceID, internalSvcExport)
+		if publicIPErr != nil && !errors.Is(publicIPErr, errInvalidPublicIP) {
+			return nil, nil, publicIPErr
+		}
+		return publicIPErr, nil, nil
 	}
-	if !export.Spec.IsDNSLabelConfigured {
-		err = fmt.Errorf("in the process of adding DNS to the public ip address %s", *pip.ID)
-		klog.ErrorS(err, "Requeue the request to see if the DNS is ready or not", "service", serviceKObj)
-		return err
+	internalSvcExport.Spec.IsLoadBalancerPending = isLoadBalancerPending(svc)
+	setExternalTrafficPolicyInformation(svc, internalSvcExport)
+	dnsLabelErr = provider.SetLoadBalancerInformation(ctx, svc, internalSvcExport)
+	if dnsLabelErr != nil && !errors.Is(dnsLabelErr, errDNSLabelNotSet) {
+		return nil, nil, dnsLabelErr
 	}
-	return nil
+	return nil, dnsLabelErr, nil
 }
 
-// TODO: can improve the performance by caching the public IP address resource ID.
-// Note: we don't support "service.beta.kubernetes.io/azure-pip-prefix-id" annotation, and public ip cannot be found in
-// this case.
-func (r *Reconciler) lookupPublicIPResourceIDByLoadBalancerIP(ctx context.Context, service *corev1.Service) (*armnetwork.PublicIPAddress, error) {
-	// The customer can specify the resource group for the public IP address in the service annotation.
-	rg := strings.TrimSpace(service.Annotations[objectmeta.ServiceAnnotationLoadBalancerResourceGroup])
-	if len(rg) == 0 {
-		rg = r.ResourceGroupName
-	}
-	serviceKObj := klog.KObj(service)
-	pips, err := r.AzurePublicIPAddressClient.List(ctx, rg)
-	if err != nil {
-		klog.ErrorS(err, "Failed to list Azure public IP addresses", "service", serviceKObj, "resourceGroup", rg)
-		return nil, err
-	}
-	for _, pip := range pips {
-		if pip.Properties != nil && pip.Properties.IPAddress != nil &&
-			*pip.Properties.IPAddress == service.Status.LoadBalancer.Ingress[0].IP {
-			return pip, nil
-		}
+// refreshHeartbeat refreshes the heartbeat timestamp of an InternalServiceExport with a status patch. The write is
+// skipped if the heartbeat has been refreshed recently, e.g. by a reconciliation triggered by a Service change.
+func (r *Reconciler) refreshHeartbeat(ctx context.Context, internalSvcExport *fleetnetv1alpha1.InternalServiceExport, now time.Time) error {
+	if lastHeartbeatTime := internalSvcExport.Status.LastHeartbeatTime; lastHeartbeatTime != nil && now.Sub(lastHeartbeatTime.Time) < r.HeartbeatInterval/2 {
+		return nil
 	}
-	klog.V(2).InfoS("The public IP address resource ID cannot be found in the public IP lists", "service", serviceKObj, "ip", service.Status.LoadBalancer.Ingress[0].IP, "resourceGroup", rg)
-	return nil, nil
+	patch := client.MergeFrom(internalSvcExport.DeepCopy())
+	internalSvcExport.Status.LastHeartbeatTime = &metav1.Time{Time: now}
+	return r.HubClient.Status().Patch(ctx, internalSvcExport, patch)
 }
 
 // SetupWithManager builds a controller with Reconciler and sets it up with a controller manager.
 func (r *Reconciler) SetupWithManager(mgr ctrl.Manager) error {
-	return ctrl.NewControllerManagedBy(mgr).
+	b := ctrl.NewControllerManagedBy(mgr).
 		// The ServiceExport controller watches over ServiceExport objects.
 		For(&fleetnetv1alpha1.ServiceExport{}).
 		// The ServiceExport controller watches over Service objects.
 		Watches(&corev1.Service{}, &handler.EnqueueRequestForObject{}).
-		Complete(r)
+		// The ServiceExport controller watches over EndpointSlice objects, so that the no ready backends condition
+		// is refreshed when the endpoints of an exported Service change.
+		Watches(&discoveryv1.EndpointSlice{}, handler.EnqueueRequestsFromMapFunc(endpointSliceToServiceExport))
+	if len(r.RequiredNamespaceLabels) > 0 {
+		// The ServiceExport controller watches over the labels of Namespace objects, so that the Services are exported
+		// or unexported when a namespace is onboarded or offboarded.
+		b = b.Watches(&corev1.Namespace{}, handler.EnqueueRequestsFromMapFunc(r.namespaceToServiceExports),
+			builder.WithPredicates(predicate.LabelChangedPredicate{}))
+	}
+	if r.NetworkingMode != nil {
+		// The ServiceExport controller watches over the networking mode of the member cluster, so that the Services
+		// are exported or unexported when the export is enabled or disabled.
+		b = b.WatchesRawSource(source.Channel(r.NetworkingMode.Subscribe(), handler.EnqueueRequestsFromMapFunc(r.allServiceExports)))
+	}
+	if r.HubServerChanges != nil {
+		// The ServiceExport controller watches over the hub server rotations, so that the Services are exported to
+		// the new hub server.
+		b = b.WatchesRawSource(source.Channel(r.HubServerChanges, handler.EnqueueRequestsFromMapFunc(r.allServiceExports)))
+	}
+	return b.Complete(r)
+}
+
+// allServiceExports maps a networking mode change or a hub server rotation to all the ServiceExports in the member
+// cluster.
+func (r *Reconciler) allServiceExports(ctx context.Context, _ client.Object) []reconcile.Request {
+	svcExportList := &fleetnetv1alpha1.ServiceExportList{}
+	if err := r.MemberClient.List(ctx, svcExportList); err != nil {
+		klog.ErrorS(err, "Failed to list service exports")
+		return nil
+	}
+	requests := make([]reconcile.Request, 0, len(svcExportList.Items))
+	for _, svcExport := range svcExportList.Items {
+		requests = append(requests, reconcile.Request{
+			NamespacedName: types.NamespacedName{Namespace: svcExport.Namespace, Name: svcExport.Name},
+		})
+	}
+	return requests
+}
+
+// namespaceToServiceExports maps a Namespace to all the ServiceExports in it.
+func (r *Reconciler) namespaceToServiceExports(ctx context.Context, obj client.Object) []reconcile.Request {
+	svcExportList := &fleetnetv1alpha1.ServiceExportList{}
+	if err := r.MemberClient.List(ctx, svcExportList, client.InNamespace(obj.GetName())); err != nil {
+		klog.ErrorS(err, "Failed to list service exports in the namespace", "namespace", obj.GetName())
+		return nil
+	}
+	requests := make([]reconcile.Request, 0, len(svcExportList.Items))
+	for _, svcExport := range svcExportList.Items {
+		requests = append(requests, reconcile.Request{
+			NamespacedName: types.NamespacedName{Namespace: svcExport.Namespace, Name: svcExport.Name},
+		})
+	}
+	return requests
+}
+
+// isNamespaceOnboarded returns if a namespace has all the required labels.
+func (r *Reconciler) isNamespaceOnboarded(ctx context.Context, namespace string) (bool, error) {
+	ns := &corev1.Namespace{}
+	if err := r.MemberClient.Get(ctx, types.NamespacedName{Name: namespace}, ns); err != nil {
+		return false, err
+	}
+	return labels.SelectorFromSet(r.RequiredNamespaceLabels).Matches(labels.Set(ns.Labels)), nil
+}
+
+// isNamespaceTerminating returns if a namespace is being deleted; a namespace which is not found is not considered
+// as such, as the ServiceExports in it are gone as well.
+func (r *Reconciler) isNamespaceTerminating(ctx context.Context, namespace string) (bool, error) {
+	ns := &corev1.Namespace{}
+	if err := r.MemberClient.Get(ctx, types.NamespacedName{Name: namespace}, ns); err != nil {
+		return false, client.IgnoreNotFound(err)
+	}
+	return ns.DeletionTimestamp != nil, nil
+}
+
+// endpointSliceToServiceExport maps an EndpointSlice to the ServiceExport of the Service it belongs to, if any.
+func endpointSliceToServiceExport(_ context.Context, obj client.Object) []reconcile.Request {
+	svcName, ok := obj.GetLabels()[discoveryv1.LabelServiceName]
+	if !ok || svcName == "" {
+		return nil
+	}
+	return []reconcile.Request{
+		{NamespacedName: types.NamespacedName{Namespace: obj.GetNamespace(), Name: svcName}},
+	}
+}
+
+// hubExportNamespace returns the namespace in the hub cluster of the InternalServiceExport of a Service, which is the
+// shard selected by the namespace and the name of the Service.
+func (r *Reconciler) hubExportNamespace(svcExport *fleetnetv1alpha1.ServiceExport) string {
+	return hubconfig.ExportNamespace(r.HubNamespace, r.HubExportShards, svcExport.Namespace, svcExport.Name)
 }
 
 // unexportService unexports a Service, specifically, it deletes the corresponding InternalServiceExport from the
@@ -382,19 +716,19 @@ func (r *Reconciler) unexportService(ctx context.Context, svcExport *fleetnetv1a
 	internalSvcExportName := formatInternalServiceExportName(svcExport)
 	internalSvcExport := &fleetnetv1alpha1.InternalServiceExport{
 		ObjectMeta: metav1.ObjectMeta{
-			Namespace: r.HubNamespace,
+			Namespace: r.hubExportNamespace(svcExport),
 			Name:      internalSvcExportName,
 		},
 	}
 
 	// Unexport the Service.
-	if err := r.HubClient.Delete(ctx, internalSvcExport); err != nil && !apierrors.IsNotFound(err) {
-		// It is guaranteed that a finalizer is always added to a ServiceExport before the corresponding Service is
-		// actually exported; in some rare occasions, e.g. the controller crashes right after it adds the finalizer
-		// to the ServiceExport but before the it gets a chance to actually export the Service to the
-		// hub cluster, it could happen that a ServiceExport has a finalizer present yet the corresponding Service
-		// has not been exported to the hub cluster. It is an expected behavior and no action is needed on this
-		// controller's end.
+	// It is guaranteed that a finalizer is always added to a ServiceExport before the corresponding Service is
+	// actually exported; in some rare occasions, e.g. the controller crashes right after it adds the finalizer
+	// to the ServiceExport but before the it gets a chance to actually export the Service to the
+	// hub cluster, it could happen that a ServiceExport has a finalizer present yet the corresponding Service
+	// has not been exported to the hub cluster. It is an expected behavior and no action is needed on this
+	// controller's end.
+	if err := hubclient.Delete(ctx, r.HubClient, internalSvcExport, "serviceExport", klog.KObj(svcExport)); err != nil {
 		return ctrl.Result{}, err
 	}
 
@@ -405,6 +739,64 @@ func (r *Reconciler) unexportService(ctx context.Context, svcExport *fleetnetv1a
 	return ctrl.Result{}, nil
 }
 
+// unexportTerminatingNamespace unexports the Services of a namespace which is being deleted in one pass, starting
+// with the Service of the given ServiceExport, without updating the status of the ServiceExports or emitting events.
+// The unexport of the other Services is best effort, as each ServiceExport is reconciled on its own as well.
+func (r *Reconciler) unexportTerminatingNamespace(ctx context.Context, svcExport *fleetnetv1alpha1.ServiceExport) error {
+	if err := r.unexportServiceOfTerminatingNamespace(ctx, svcExport); err != nil {
+		return err
+	}
+
+	svcExportList := &fleetnetv1alpha1.ServiceExportList{}
+	if err := r.MemberClient.List(ctx, svcExportList, client.InNamespace(svcExport.Namespace)); err != nil {
+		return err
+	}
+	for i := range svcExportList.Items {
+		other := &svcExportList.Items[i]
+		if other.Name == svcExport.Name {
+			continue
+		}
+		if err := r.unexportServiceOfTerminatingNamespace(ctx, other); err != nil {
+			klog.V(2).InfoS("Failed to unexport the service of the deleted namespace; it will be retried when its service export is reconciled",
+				"serviceExport", klog.KObj(other), "err", err)
+		}
+	}
+	return nil
+}
+
+// unexportServiceOfTerminatingNamespace unexports the Service of a ServiceExport whose namespace is being deleted, if
+// it has been exported; the Services with the deletion protection in effect are left exported, as their unexport is
+// delayed once their ServiceExports are deleted with the namespace.
+func (r *Reconciler) unexportServiceOfTerminatingNamespace(ctx context.Context, svcExport *fleetnetv1alpha1.ServiceExport) error {
+	if !controllerutil.ContainsFinalizer(svcExport, svcExportCleanupFinalizer) || r.isDeletionProtected(svcExport) {
+		return nil
+	}
+	_, err := r.unexportService(ctx, svcExport)
+	return err
+}
+
+// isDeletionProtected returns if the unexport of a ServiceExport is delayed by the deletion protection once it is
+// deleted, i.e. if the protection is enabled for it and other member clusters still import the Service.
+func (r *Reconciler) isDeletionProtected(svcExport *fleetnetv1alpha1.ServiceExport) bool {
+	return r.DeletionProtectionGracePeriod > 0 &&
+		svcExport.Annotations[objectmeta.ServiceExportAnnotationDeletionProtection] == "true" &&
+		svcExport.Status.ActiveImporterCount > 0
+}
+
+// deletionProtectionRemaining returns how much longer the unexport of a deleted ServiceExport is delayed by the
+// deletion protection, i.e. the rest of the grace period since its deletion if the protection is enabled for it and
+// other member clusters still import the Service; 0 means that the Service can be unexported now.
+func (r *Reconciler) deletionProtectionRemaining(svcExport *fleetnetv1alpha1.ServiceExport, now time.Time) time.Duration {
+	if !r.isDeletionProtected(svcExport) {
+		return 0
+	}
+	remaining := svcExport.DeletionTimestamp.Add(r.DeletionProtectionGracePeriod).Sub(now)
+	if remaining < 0 {
+		return 0
+	}
+	return remaining
+}
+
 // removeServiceExportCleanupFinalizer removes the cleanup finalizer from a ServiceExport.
 func (r *Reconciler) removeServiceExportCleanupFinalizer(ctx context.Context, svcExport *fleetnetv1alpha1.ServiceExport) error {
 	controllerutil.RemoveFinalizer(svcExport, svcExportCleanupFinalizer)
@@ -449,6 +841,64 @@ func (r *Reconciler) markServiceExportAsInvalidSvcIneligible(ctx context.Context
 	return r.MemberClient.Status().Update(ctx, svcExport)
 }
 
+// markServiceExportAsInvalidExportedName marks a ServiceExport as invalid.
+func (r *Reconciler) markServiceExportAsInvalidExportedName(ctx context.Context, svcExport *fleetnetv1alpha1.ServiceExport, svc *corev1.Service, exportedName string) error {
+	validCond := meta.FindStatusCondition(svcExport.Status.Conditions, string(fleetnetv1alpha1.ServiceExportValid))
+	expectedValidCond := &metav1.Condition{
+		Type:               string(fleetnetv1alpha1.ServiceExportValid),
+		Status:             metav1.ConditionFalse,
+		Reason:             svcExportInvalidExportedNameCondReason,
+		ObservedGeneration: svc.Generation,
+		Message:            fmt.Sprintf("exported name %q of service %s/%s is not a valid DNS-1035 label", exportedName, svcExport.Namespace, svcExport.Name),
+	}
+	if condition.EqualCondition(validCond, expectedValidCond) {
+		// A stable state has been reached; no further action is needed.
+		return nil
+	}
+
+	meta.SetStatusCondition(&svcExport.Status.Conditions, *expectedValidCond)
+	return r.MemberClient.Status().Update(ctx, svcExport)
+}
+
+// markServiceExportAsInvalidNamespaceNotOnboarded marks a ServiceExport as invalid.
+func (r *Reconciler) markServiceExportAsInvalidNamespaceNotOnboarded(ctx context.Context, svcExport *fleetnetv1alpha1.ServiceExport) error {
+	validCond := meta.FindStatusCondition(svcExport.Status.Conditions, string(fleetnetv1alpha1.ServiceExportValid))
+	expectedValidCond := &metav1.Condition{
+		Type:   string(fleetnetv1alpha1.ServiceExportValid),
+		Status: metav1.ConditionFalse,
+		// The Service is not checked, therefore the observedGeneration field is ignored.
+		Reason: svcExportNamespaceNotOnboardedReason,
+		Message: fmt.Sprintf("namespace %s is not onboarded to multi-cluster networking; it must have the labels %q",
+			svcExport.Namespace, r.RequiredNamespaceLabels.String()),
+	}
+	if condition.EqualCondition(validCond, expectedValidCond) {
+		// A stable state has been reached; no further action is needed.
+		return nil
+	}
+
+	meta.SetStatusCondition(&svcExport.Status.Conditions, *expectedValidCond)
+	return r.MemberClient.Status().Update(ctx, svcExport)
+}
+
+// markServiceExportAsInvalidExportDisabled marks a ServiceExport as invalid.
+func (r *Reconciler) markServiceExportAsInvalidExportDisabled(ctx context.Context, svcExport *fleetnetv1alpha1.ServiceExport) error {
+	validCond := meta.FindStatusCondition(svcExport.Status.Conditions, string(fleetnetv1alpha1.ServiceExportValid))
+	expectedValidCond := &metav1.Condition{
+		Type:   string(fleetnetv1alpha1.ServiceExportValid),
+		Status: metav1.ConditionFalse,
+		// The Service is not checked, therefore the observedGeneration field is ignored.
+		Reason:  svcExportExportDisabledReason,
+		Message: fmt.Sprintf("member cluster is in the %s networking mode; services cannot be exported", r.NetworkingMode.Mode()),
+	}
+	if condition.EqualCondition(validCond, expectedValidCond) {
+		// A stable state has been reached; no further action is needed.
+		return nil
+	}
+
+	meta.SetStatusCondition(&svcExport.Status.Conditions, *expectedValidCond)
+	return r.MemberClient.Status().Update(ctx, svcExport)
+}
+
 // addServiceExportCleanupFinalizer adds the cleanup finalizer to a ServiceExport.
 func (r *Reconciler) addServiceExportCleanupFinalizer(ctx context.Context, svcExport *fleetnetv1alpha1.ServiceExport) error {
 	controllerutil.AddFinalizer(svcExport, svcExportCleanupFinalizer)
@@ -486,6 +936,171 @@ func (r *Reconciler) markServiceExportAsValid(ctx context.Context, svcExport *fl
 	return r.MemberClient.Status().Update(ctx, svcExport)
 }
 
+// updateNoReadyBackendsCondition reports on the ServiceExport whether none of the EndpointSlices of the Service has a
+// ready endpoint, which usually means that the selector of the Service matches no (ready) pods.
+//
+// The condition is added once the Service is found to have no ready backend, and is set to false (rather than
+// removed) when ready backends appear.
+func (r *Reconciler) updateNoReadyBackendsCondition(ctx context.Context, svcExport *fleetnetv1alpha1.ServiceExport, svc *corev1.Service) error {
+	condType := string(fleetnetv1alpha1.ServiceExportNoReadyBackends)
+	noReadyBackendsCond := meta.FindStatusCondition(svcExport.Status.Conditions, condType)
+
+	endpointSliceList := &discoveryv1.EndpointSliceList{}
+	if err := r.MemberClient.List(ctx, endpointSliceList,
+		client.InNamespace(svc.Namespace),
+		client.MatchingLabels{discoveryv1.LabelServiceName: svc.Name}); err != nil {
+		return fmt.Errorf("failed to list endpointSlices of service %s/%s: %w", svc.Namespace, svc.Name, err)
+	}
+	hasReadyBackends := hasReadyEndpoints(endpointSliceList.Items)
+	if hasReadyBackends && noReadyBackendsCond == nil {
+		// The Service has never been found without ready backends; no condition is needed.
+		return nil
+	}
+
+	var expectedCond *metav1.Condition
+	if hasReadyBackends {
+		expectedCond = &metav1.Condition{
+			Type:               condType,
+			Status:             metav1.ConditionFalse,
+			ObservedGeneration: svc.Generation,
+			Reason:             svcExportReadyBackendsFoundCondReason,
+			Message:            fmt.Sprintf("service %s/%s has ready endpoints", svc.Namespace, svc.Name),
+		}
+	} else {
+		expectedCond = &metav1.Condition{
+			Type:               condType,
+			Status:             metav1.ConditionTrue,
+			ObservedGeneration: svc.Generation,
+			Reason:             svcExportNoReadyBackendsCondReason,
+			Message:            formatNoReadyBackendsMessage(svc),
+		}
+	}
+	// The message carries the selector of the Service, which is compared as well.
+	if condition.EqualCondition(noReadyBackendsCond, expectedCond) && noReadyBackendsCond.Message == expectedCond.Message {
+		// A stable state has been reached; no further action is needed.
+		return nil
+	}
+
+	if expectedCond.Status == metav1.ConditionTrue {
+		r.Recorder.Eventf(svcExport, corev1.EventTypeWarning, "NoReadyBackends", "Service %s has no ready endpoints", svc.Name)
+	}
+	meta.SetStatusCondition(&svcExport.Status.Conditions, *expectedCond)
+	return r.MemberClient.Status().Update(ctx, svcExport)
+}
+
+// updateLoadBalancerPendingCondition reports on the ServiceExport whether the Service is of the LoadBalancer type
+// but its load balancer IP has not been provisioned yet.
+//
+// The condition is added once the load balancer IP is found to be pending, and is set to false (rather than removed)
+// when the IP is provisioned or the Service is no longer of the LoadBalancer type.
+func (r *Reconciler) updateLoadBalancerPendingCondition(ctx context.Context, svcExport *fleetnetv1alpha1.ServiceExport, svc *corev1.Service, lbPending bool) error {
+	condType := string(fleetnetv1alpha1.ServiceExportLoadBalancerPending)
+	lbPendingCond := meta.FindStatusCondition(svcExport.Status.Conditions, condType)
+	if !lbPending && lbPendingCond == nil {
+		// The load balancer IP has never been found pending; no condition is needed.
+		return nil
+	}
+
+	var expectedCond *metav1.Condition
+	if lbPending {
+		expectedCond = &metav1.Condition{
+			Type:               condType,
+			Status:             metav1.ConditionTrue,
+			ObservedGeneration: svc.Generation,
+			Reason:             svcExportLBPendingCondReason,
+			Message:            formatLoadBalancerPendingMessage(svc),
+		}
+	} else {
+		expectedCond = &metav1.Condition{
+			Type:               condType,
+			Status:             metav1.ConditionFalse,
+			ObservedGeneration: svc.Generation,
+			Reason:             svcExportLBProvisionedCondReason,
+			Message:            fmt.Sprintf("service %s/%s is not pending a load balancer IP", svc.Namespace, svc.Name),
+		}
+	}
+	if condition.EqualCondition(lbPendingCond, expectedCond) && lbPendingCond.Message == expectedCond.Message {
+		// A stable state has been reached; no further action is needed.
+		return nil
+	}
+
+	if expectedCond.Status == metav1.ConditionTrue {
+		r.Recorder.Eventf(svcExport, corev1.EventTypeNormal, "LoadBalancerPending", "The load balancer IP of service %s is not yet provisioned", svc.Name)
+	}
+	meta.SetStatusCondition(&svcExport.Status.Conditions, *expectedCond)
+	return r.MemberClient.Status().Update(ctx, svcExport)
+}
+
+// updatePublicIPValidCondition reports whether the public IP address specified by the annotation of a ServiceExport
+// is valid; the condition is removed once the annotation is removed.
+func (r *Reconciler) updatePublicIPValidCondition(ctx context.Context, svcExport *fleetnetv1alpha1.ServiceExport,
+	isPublicIPExplicit bool, publicIPResourceID string, publicIPErr error) error {
+	condType := string(fleetnetv1alpha1.ServiceExportPublicIPValid)
+	publicIPValidCond := meta.FindStatusCondition(svcExport.Status.Conditions, condType)
+	if !isPublicIPExplicit {
+		if publicIPValidCond == nil {
+			// No public IP address has been specified; no condition is needed.
+			return nil
+		}
+		meta.RemoveStatusCondition(&svcExport.Status.Conditions, condType)
+		return r.MemberClient.Status().Update(ctx, svcExport)
+	}
+
+	expectedCond := &metav1.Condition{
+		Type:               condType,
+		Status:             metav1.ConditionTrue,
+		ObservedGeneration: svcExport.Generation,
+		Reason:             svcExportPublicIPValidCondReason,
+		Message:            fmt.Sprintf("public IP address %s is valid", publicIPResourceID),
+	}
+	if publicIPErr != nil {
+		expectedCond.Status = metav1.ConditionFalse
+		expectedCond.Reason = svcExportPublicIPInvalidCondReason
+		expectedCond.Message = publicIPErr.Error()
+	}
+	if condition.EqualCondition(publicIPValidCond, expectedCond) && publicIPValidCond.Message == expectedCond.Message {
+		// A stable state has been reached; no further action is needed.
+		return nil
+	}
+
+	if expectedCond.Status == metav1.ConditionFalse {
+		r.Recorder.Eventf(svcExport, corev1.EventTypeWarning, "InvalidPublicIP", "The public IP address specified for service %s is invalid: %v", svcExport.Name, publicIPErr)
+	}
+	meta.SetStatusCondition(&svcExport.Status.Conditions, *expectedCond)
+	return r.MemberClient.Status().Update(ctx, svcExport)
+}
+
+// updateDNSLabelConfiguredCondition reports on the ServiceExport that the DNS label cannot be set on the public IP
+// address of the load balancer of the Service; the condition is removed once the error is gone.
+func (r *Reconciler) updateDNSLabelConfiguredCondition(ctx context.Context, svcExport *fleetnetv1alpha1.ServiceExport, dnsLabelErr error) error {
+	condType := string(fleetnetv1alpha1.ServiceExportDNSLabelConfigured)
+	dnsLabelConfiguredCond := meta.FindStatusCondition(svcExport.Status.Conditions, condType)
+	if dnsLabelErr == nil {
+		if dnsLabelConfiguredCond == nil {
+			// The DNS label is configured or not managed; no condition is needed.
+			return nil
+		}
+		meta.RemoveStatusCondition(&svcExport.Status.Conditions, condType)
+		return r.MemberClient.Status().Update(ctx, svcExport)
+	}
+
+	expectedCond := &metav1.Condition{
+		Type:               condType,
+		Status:             metav1.ConditionFalse,
+		ObservedGeneration: svcExport.Generation,
+		Reason:             svcExportDNSLabelNotSetCondReason,
+		Message:            dnsLabelErr.Error(),
+	}
+	if condition.EqualCondition(dnsLabelConfiguredCond, expectedCond) && dnsLabelConfiguredCond.Message == expectedCond.Message {
+		// A stable state has been reached; no further action is needed.
+		return nil
+	}
+
+	r.Recorder.Eventf(svcExport, corev1.EventTypeWarning, "DNSLabelNotSet", "The DNS label cannot be set on the public IP address of service %s: %v", svcExport.Name, dnsLabelErr)
+	meta.SetStatusCondition(&svcExport.Status.Conditions, *expectedCond)
+	return r.MemberClient.Status().Update(ctx, svcExport)
+}
+
 // collectAndVerifyLastSeenResourceVersionAndTime collects and verifies the last seen resource version and timestamp annotations
 // on ServiceExports; it will assign new values if the annotations are not present or not valid.
 func (r *Reconciler) collectAndVerifyLastSeenResourceVersionAndTimestamp(ctx context.Context,
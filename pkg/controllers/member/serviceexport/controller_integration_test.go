@@ -6,6 +6,7 @@ Licensed under the MIT license.
 package serviceexport
 
 import (
+	"context"
 	"fmt"
 	"time"
 
@@ -14,6 +15,7 @@ import (
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 	corev1 "k8s.io/api/core/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -23,16 +25,27 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	fleetnetv1alpha1 "go.goms.io/fleet-networking/api/v1alpha1"
+	"go.goms.io/fleet-networking/pkg/common/hubconfig"
 	"go.goms.io/fleet-networking/pkg/common/metrics"
+	"go.goms.io/fleet-networking/pkg/common/objectmeta"
 )
 
 const (
 	memberClusterID  = "bravelion"
+	memberRegion     = "westus"
 	svcPortName      = "port1"
 	svcPort          = 80
 	targetPort       = 8080
 	externalNameAddr = "example.com"
 
+	// notOnboardedMemberUserNS is a namespace without the required namespace labels.
+	notOnboardedMemberUserNS = "work-not-onboarded"
+
+	// hubExportShards is the number of hub namespaces across which the exports of the member cluster are sharded.
+	hubExportShards = 2
+	// hubShardNSForMember is the hub export shard namespace other than the member cluster namespace.
+	hubShardNSForMember = hubNSForMember + "-shard-1"
+
 	eventuallyTimeout    = time.Second * 10
 	eventuallyInterval   = time.Millisecond * 250
 	consistentlyDuration = time.Millisecond * 1000
@@ -125,7 +138,7 @@ var (
 		Name:      svcName,
 	}
 	internalSvcExportKey = types.NamespacedName{
-		Namespace: hubNSForMember,
+		Namespace: hubconfig.ExportNamespace(hubNSForMember, hubExportShards, memberUserNS, svcName),
 		Name:      fmt.Sprintf("%s-%s", memberUserNS, svcName),
 	}
 
@@ -154,9 +167,9 @@ var (
 	// serviceIsNotExportedActual runs with Eventually and Consistently assertion to make sure that no
 	// Service has been exported.
 	serviceIsNotExportedActual = func() error {
+		// The exports of the member cluster are sharded across multiple hub namespaces.
 		internalSvcExportList := &fleetnetv1alpha1.InternalServiceExportList{}
-		listOption := &client.ListOptions{Namespace: hubNSForMember}
-		if err := hubClient.List(ctx, internalSvcExportList, listOption); err != nil {
+		if err := hubClient.List(ctx, internalSvcExportList); err != nil {
 			return fmt.Errorf("endpointSliceExport List(), got %w, want no error", err)
 		}
 
@@ -301,7 +314,14 @@ func serviceIsExportedToHubActual(serviceType corev1.ServiceType, isPublicAzureL
 				svc.ObjectMeta,
 				metav1.NewTime(lastSeenTimestamp),
 			),
-			Type: serviceType,
+			Type:          serviceType,
+			ClusterRegion: memberRegion,
+			// The API server defaults the Services to the single-stack IP family of the cluster.
+			AddressFamilies: []corev1.IPFamily{corev1.IPv4Protocol},
+		}
+		if serviceType == corev1.ServiceTypeLoadBalancer {
+			// The API server defaults the external traffic policy of the load balancer Services.
+			expectedInternalSvcExportSpec.ExternalTrafficPolicy = corev1.ServiceExternalTrafficPolicyCluster
 		}
 		if isPublicAzureLoadBalancer {
 			expectedInternalSvcExportSpec.IsDNSLabelConfigured = true
@@ -472,7 +492,9 @@ var _ = Describe("serviceexport controller", func() {
 						svc.ObjectMeta,
 						metav1.Now(),
 					),
-					Type: svc.Spec.Type,
+					Type:            svc.Spec.Type,
+					ClusterRegion:   memberRegion,
+					AddressFamilies: []corev1.IPFamily{corev1.IPv4Protocol},
 				}
 				if diff := cmp.Diff(internalSvcExport.Spec, expectedInternalSvcExportSpec, ignoredRefFields); diff != "" {
 					return fmt.Errorf("internalServiceExport spec (-got, +want): %s", diff)
@@ -696,8 +718,8 @@ var _ = Describe("serviceexport controller", func() {
 		BeforeEach(func() {
 			internalSvcExport = &fleetnetv1alpha1.InternalServiceExport{
 				ObjectMeta: metav1.ObjectMeta{
-					Namespace: hubNSForMember,
-					Name:      fmt.Sprintf("%s-%s", memberUserNS, svcName),
+					Namespace: internalSvcExportKey.Namespace,
+					Name:      internalSvcExportKey.Name,
 				},
 				Spec: fleetnetv1alpha1.InternalServiceExportSpec{
 					Ports: []fleetnetv1alpha1.ServicePort{
@@ -749,6 +771,317 @@ var _ = Describe("serviceexport controller", func() {
 		})
 	})
 
+	Context("export UDP service", func() {
+		var svcExport = &fleetnetv1alpha1.ServiceExport{}
+		var svc = &corev1.Service{}
+
+		BeforeEach(func() {
+			svc = clusterIPService()
+			svc.Spec.Ports[0].Protocol = corev1.ProtocolUDP
+			Expect(memberClient.Create(ctx, svc)).Should(Succeed())
+
+			svcExport = notYetFulfilledServiceExport()
+			Expect(memberClient.Create(ctx, svcExport)).Should(Succeed())
+		})
+
+		AfterEach(func() {
+			Expect(memberClient.Delete(ctx, svcExport)).Should(Succeed())
+			Expect(memberClient.Delete(ctx, svc)).Should(Succeed())
+
+			// Confirm that the Service has been unexported; this helps make the tests less flaky.
+			Eventually(serviceIsNotExportedActual, eventuallyTimeout, eventuallyInterval).Should(Succeed())
+
+			Eventually(serviceExportIsAbsentActual, eventuallyTimeout, eventuallyInterval).Should(Succeed())
+			Eventually(serviceIsAbsentActual, eventuallyTimeout, eventuallyInterval).Should(Succeed())
+		})
+
+		It("should export the service with the UDP protocol", func() {
+			Eventually(serviceIsExportedFromMemberActual, eventuallyTimeout, eventuallyInterval).Should(Succeed())
+			Eventually(func() error {
+				internalSvcExport := &fleetnetv1alpha1.InternalServiceExport{}
+				if err := hubClient.Get(ctx, internalSvcExportKey, internalSvcExport); err != nil {
+					return fmt.Errorf("internalServiceExport Get(%+v), got %w, want no error", internalSvcExportKey, err)
+				}
+				wantPorts := []fleetnetv1alpha1.ServicePort{
+					{
+						Protocol:   corev1.ProtocolUDP,
+						Port:       svcPort,
+						TargetPort: intstr.FromInt(targetPort),
+					},
+				}
+				if diff := cmp.Diff(internalSvcExport.Spec.Ports, wantPorts); diff != "" {
+					return fmt.Errorf("internalServiceExport ports (-got, +want): %s", diff)
+				}
+				return nil
+			}, eventuallyTimeout, eventuallyInterval).Should(Succeed())
+		})
+	})
+
+	Context("export service with hub-managed fields on the exported service", func() {
+		var svcExport = &fleetnetv1alpha1.ServiceExport{}
+		var svc = &corev1.Service{}
+		hubLabelKey := "hub.example.com/managed"
+		hubLabelValue := "true"
+		altSvcPort := 81
+
+		BeforeEach(func() {
+			svc = clusterIPService()
+			Expect(memberClient.Create(ctx, svc)).Should(Succeed())
+
+			svcExport = notYetFulfilledServiceExport()
+			Expect(memberClient.Create(ctx, svcExport)).Should(Succeed())
+		})
+
+		AfterEach(func() {
+			Expect(memberClient.Delete(ctx, svcExport)).Should(Succeed())
+			Expect(memberClient.Delete(ctx, svc)).Should(Succeed())
+
+			// Confirm that the Service has been unexported; this helps make the tests less flaky.
+			Eventually(serviceIsNotExportedActual, eventuallyTimeout, eventuallyInterval).Should(Succeed())
+
+			Eventually(serviceExportIsAbsentActual, eventuallyTimeout, eventuallyInterval).Should(Succeed())
+			Eventually(serviceIsAbsentActual, eventuallyTimeout, eventuallyInterval).Should(Succeed())
+		})
+
+		It("should keep the hub-managed fields when the exported service is updated", func() {
+			By("confirm that the service has been exported")
+			Eventually(serviceIsExportedFromMemberActual, eventuallyTimeout, eventuallyInterval).Should(Succeed())
+			Eventually(serviceIsExportedToHubActual(svc.Spec.Type, false), eventuallyTimeout, eventuallyInterval).Should(Succeed())
+
+			By("add a label to the exported service on the hub side")
+			Eventually(func() error {
+				internalSvcExport := &fleetnetv1alpha1.InternalServiceExport{}
+				if err := hubClient.Get(ctx, internalSvcExportKey, internalSvcExport); err != nil {
+					return err
+				}
+				if internalSvcExport.Labels == nil {
+					internalSvcExport.Labels = map[string]string{}
+				}
+				internalSvcExport.Labels[hubLabelKey] = hubLabelValue
+				return hubClient.Update(ctx, internalSvcExport)
+			}, eventuallyTimeout, eventuallyInterval).Should(Succeed())
+
+			By("update the service")
+			Expect(memberClient.Get(ctx, svcOrSvcExportKey, svc)).Should(Succeed())
+			svc.Spec.Ports[0].Port = int32(altSvcPort)
+			Expect(memberClient.Update(ctx, svc)).Should(Succeed())
+
+			By("confirm that the exported service has been updated and the label is kept")
+			Eventually(func() error {
+				internalSvcExport := &fleetnetv1alpha1.InternalServiceExport{}
+				if err := hubClient.Get(ctx, internalSvcExportKey, internalSvcExport); err != nil {
+					return fmt.Errorf("internalServiceExport Get(%+v), got %w, want no error", internalSvcExportKey, err)
+				}
+				if len(internalSvcExport.Spec.Ports) != 1 || internalSvcExport.Spec.Ports[0].Port != int32(altSvcPort) {
+					return fmt.Errorf("internalServiceExport ports, got %+v, want port %d", internalSvcExport.Spec.Ports, altSvcPort)
+				}
+				if got := internalSvcExport.Labels[hubLabelKey]; got != hubLabelValue {
+					return fmt.Errorf("internalServiceExport label %s, got %q, want %q", hubLabelKey, got, hubLabelValue)
+				}
+				return nil
+			}, eventuallyTimeout, eventuallyInterval).Should(Succeed())
+		})
+	})
+
+	Context("export service previously exported with client-side updates", func() {
+		var svcExport = &fleetnetv1alpha1.ServiceExport{}
+		var svc = &corev1.Service{}
+		stalePort := 81
+
+		BeforeEach(func() {
+			svc = clusterIPService()
+			Expect(memberClient.Create(ctx, svc)).Should(Succeed())
+
+			// Create the InternalServiceExport the way the member agent did before it switched to server-side
+			// apply, with a port which is no longer exposed by the Service.
+			svcReference := fleetnetv1alpha1.FromMetaObjects(memberClusterID, metav1.TypeMeta{APIVersion: "v1", Kind: "Service"}, svc.ObjectMeta, metav1.Now())
+			internalSvcExport := &fleetnetv1alpha1.InternalServiceExport{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: internalSvcExportKey.Namespace,
+					Name:      internalSvcExportKey.Name,
+				},
+				Spec: fleetnetv1alpha1.InternalServiceExportSpec{
+					Ports: []fleetnetv1alpha1.ServicePort{
+						{
+							Protocol:   corev1.ProtocolTCP,
+							Port:       svcPort,
+							TargetPort: intstr.FromInt(targetPort),
+						},
+						{
+							Protocol:   corev1.ProtocolTCP,
+							Port:       int32(stalePort),
+							TargetPort: intstr.FromInt(targetPort),
+						},
+					},
+					ServiceReference: svcReference,
+				},
+			}
+			Expect(hubClient.Create(ctx, internalSvcExport, client.FieldOwner(objectmeta.MemberAgentLegacyFieldManager))).Should(Succeed())
+
+			svcExport = notYetFulfilledServiceExport()
+			Expect(memberClient.Create(ctx, svcExport)).Should(Succeed())
+		})
+
+		AfterEach(func() {
+			Expect(memberClient.Delete(ctx, svcExport)).Should(Succeed())
+			Expect(memberClient.Delete(ctx, svc)).Should(Succeed())
+
+			// Confirm that the Service has been unexported; this helps make the tests less flaky.
+			Eventually(serviceIsNotExportedActual, eventuallyTimeout, eventuallyInterval).Should(Succeed())
+
+			Eventually(serviceExportIsAbsentActual, eventuallyTimeout, eventuallyInterval).Should(Succeed())
+			Eventually(serviceIsAbsentActual, eventuallyTimeout, eventuallyInterval).Should(Succeed())
+		})
+
+		It("should move the fields to the apply field manager and drop the stale port", func() {
+			By("confirm that the stale port has been removed from the exported service")
+			Eventually(func() error {
+				internalSvcExport := &fleetnetv1alpha1.InternalServiceExport{}
+				if err := hubClient.Get(ctx, internalSvcExportKey, internalSvcExport); err != nil {
+					return fmt.Errorf("internalServiceExport Get(%+v), got %w, want no error", internalSvcExportKey, err)
+				}
+				if len(internalSvcExport.Spec.Ports) != 1 || internalSvcExport.Spec.Ports[0].Port != svcPort {
+					return fmt.Errorf("internalServiceExport ports, got %+v, want port %d only", internalSvcExport.Spec.Ports, svcPort)
+				}
+				for _, entry := range internalSvcExport.ManagedFields {
+					if entry.Manager == objectmeta.MemberAgentLegacyFieldManager {
+						return fmt.Errorf("internalServiceExport managed fields, got an entry of %s, want none", objectmeta.MemberAgentLegacyFieldManager)
+					}
+				}
+				return nil
+			}, eventuallyTimeout, eventuallyInterval).Should(Succeed())
+		})
+	})
+
+	Context("export service while the hub cluster keeps mutating its export", func() {
+		const (
+			churnUpdates  = 50
+			churnInterval = time.Millisecond * 20
+		)
+		var svcExport = &fleetnetv1alpha1.ServiceExport{}
+		var svc = &corev1.Service{}
+		hubLabelKey := "hub.example.com/generation"
+		// legacyInternalSvcExportKey is the key of an InternalServiceExport written with read-modify-write updates,
+		// the way the member agent wrote the exports before it switched to server-side apply, for comparison.
+		legacyInternalSvcExportKey := types.NamespacedName{Namespace: hubNSForMember, Name: "legacy-" + internalSvcExportKey.Name}
+
+		BeforeEach(func() {
+			svc = clusterIPService()
+			Expect(memberClient.Create(ctx, svc)).Should(Succeed())
+
+			svcExport = notYetFulfilledServiceExport()
+			Expect(memberClient.Create(ctx, svcExport)).Should(Succeed())
+
+			legacyInternalSvcExport := &fleetnetv1alpha1.InternalServiceExport{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: legacyInternalSvcExportKey.Namespace,
+					Name:      legacyInternalSvcExportKey.Name,
+				},
+				Spec: fleetnetv1alpha1.InternalServiceExportSpec{
+					Ports: []fleetnetv1alpha1.ServicePort{
+						{
+							Protocol:   corev1.ProtocolTCP,
+							Port:       svcPort,
+							TargetPort: intstr.FromInt(targetPort),
+						},
+					},
+					ServiceReference: fleetnetv1alpha1.FromMetaObjects(memberClusterID, metav1.TypeMeta{APIVersion: "v1", Kind: "Service"}, svc.ObjectMeta, metav1.Now()),
+				},
+			}
+			Expect(hubClient.Create(ctx, legacyInternalSvcExport, client.FieldOwner(objectmeta.MemberAgentLegacyFieldManager))).Should(Succeed())
+		})
+
+		AfterEach(func() {
+			Expect(memberClient.Delete(ctx, svcExport)).Should(Succeed())
+			Expect(memberClient.Delete(ctx, svc)).Should(Succeed())
+			Expect(hubClient.Delete(ctx, &fleetnetv1alpha1.InternalServiceExport{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: legacyInternalSvcExportKey.Namespace,
+					Name:      legacyInternalSvcExportKey.Name,
+				},
+			})).Should(Succeed())
+
+			// Confirm that the Service has been unexported; this helps make the tests less flaky.
+			Eventually(serviceIsNotExportedActual, eventuallyTimeout, eventuallyInterval).Should(Succeed())
+
+			Eventually(serviceExportIsAbsentActual, eventuallyTimeout, eventuallyInterval).Should(Succeed())
+			Eventually(serviceIsAbsentActual, eventuallyTimeout, eventuallyInterval).Should(Succeed())
+		})
+
+		It("should apply the exported service without any conflict", func() {
+			By("confirm that the service has been exported")
+			Eventually(serviceIsExportedFromMemberActual, eventuallyTimeout, eventuallyInterval).Should(Succeed())
+			Eventually(serviceIsExportedToHubActual(svc.Spec.Type, false), eventuallyTimeout, eventuallyInterval).Should(Succeed())
+
+			By("mutate the labels of the exports on the hub side")
+			churnCtx, stopChurn := context.WithCancel(ctx)
+			churnDone := make(chan struct{})
+			go func() {
+				defer close(churnDone)
+				for i := 0; churnCtx.Err() == nil; i++ {
+					for _, key := range []types.NamespacedName{internalSvcExportKey, legacyInternalSvcExportKey} {
+						internalSvcExport := &fleetnetv1alpha1.InternalServiceExport{
+							ObjectMeta: metav1.ObjectMeta{Namespace: key.Namespace, Name: key.Name},
+						}
+						patch := fmt.Sprintf(`{"metadata":{"labels":{%q:"%d"}}}`, hubLabelKey, i)
+						// The errors are ignored; the churn only has to move the resource versions of the exports.
+						_ = hubClient.Patch(churnCtx, internalSvcExport, client.RawPatch(types.MergePatchType, []byte(patch)))
+					}
+					time.Sleep(churnInterval)
+				}
+			}()
+			DeferCleanup(func() {
+				stopChurn()
+				<-churnDone
+			})
+
+			By("update the service repeatedly")
+			writesBefore, conflictsBefore := hubWrites.writes.Load(), hubWrites.conflicts.Load()
+			for i := 1; i <= churnUpdates; i++ {
+				Eventually(func() error {
+					if err := memberClient.Get(ctx, svcOrSvcExportKey, svc); err != nil {
+						return err
+					}
+					svc.Spec.Ports[0].TargetPort = intstr.FromInt(targetPort + i)
+					return memberClient.Update(ctx, svc)
+				}, eventuallyTimeout, eventuallyInterval).Should(Succeed())
+				time.Sleep(churnInterval)
+			}
+			Eventually(func() error {
+				internalSvcExport := &fleetnetv1alpha1.InternalServiceExport{}
+				if err := hubClient.Get(ctx, internalSvcExportKey, internalSvcExport); err != nil {
+					return fmt.Errorf("internalServiceExport Get(%+v), got %w, want no error", internalSvcExportKey, err)
+				}
+				if len(internalSvcExport.Spec.Ports) != 1 || internalSvcExport.Spec.Ports[0].TargetPort.IntValue() != targetPort+churnUpdates {
+					return fmt.Errorf("internalServiceExport ports, got %+v, want target port %d", internalSvcExport.Spec.Ports, targetPort+churnUpdates)
+				}
+				return nil
+			}, eventuallyTimeout, eventuallyInterval).Should(Succeed())
+			applies, applyConflicts := hubWrites.writes.Load()-writesBefore, hubWrites.conflicts.Load()-conflictsBefore
+
+			By("update the legacy export repeatedly with read-modify-write updates")
+			updateConflicts := 0
+			for i := 1; i <= churnUpdates; i++ {
+				internalSvcExport := &fleetnetv1alpha1.InternalServiceExport{}
+				Expect(hubClient.Get(ctx, legacyInternalSvcExportKey, internalSvcExport)).Should(Succeed())
+				internalSvcExport.Spec.Ports[0].TargetPort = intstr.FromInt(targetPort + i)
+				err := hubClient.Update(ctx, internalSvcExport, client.FieldOwner(objectmeta.MemberAgentLegacyFieldManager))
+				if errors.IsConflict(err) {
+					updateConflicts++
+				} else {
+					Expect(err).Should(Succeed())
+				}
+				time.Sleep(churnInterval)
+			}
+
+			report := fmt.Sprintf("read-modify-write updates: %d conflicts out of %d; server-side applies: %d conflicts out of %d",
+				updateConflicts, churnUpdates, applyConflicts, applies)
+			AddReportEntry("hub write conflicts under churn", report)
+			GinkgoWriter.Println(report)
+			Expect(applyConflicts).Should(BeZero(), "server-side applies of the exported service must not conflict with the hub-side mutations")
+		})
+	})
+
 	// More complicated scenarios with LoadBalancer services is covered in the unit tests.
 	Context("export existing public load balancer service", func() {
 		var svcExport = &fleetnetv1alpha1.ServiceExport{}
@@ -793,5 +1126,431 @@ var _ = Describe("serviceexport controller", func() {
 			Eventually(serviceIsExportedFromMemberActual, eventuallyTimeout, eventuallyInterval).Should(Succeed())
 			Eventually(serviceIsExportedToHubActual(svc.Spec.Type, true), eventuallyTimeout, eventuallyInterval).Should(Succeed())
 		})
+
+		It("should clear the public IP information once the service is switched to ClusterIP", func() {
+			Eventually(serviceIsExportedToHubActual(corev1.ServiceTypeLoadBalancer, true), eventuallyTimeout, eventuallyInterval).Should(Succeed())
+
+			Eventually(func() error {
+				if err := memberClient.Get(ctx, svcOrSvcExportKey, svc); err != nil {
+					return err
+				}
+				svc.Spec.Type = corev1.ServiceTypeClusterIP
+				svc.Spec.ExternalTrafficPolicy = ""
+				svc.Spec.AllocateLoadBalancerNodePorts = nil
+				for i := range svc.Spec.Ports {
+					svc.Spec.Ports[i].NodePort = 0
+				}
+				return memberClient.Update(ctx, svc)
+			}, eventuallyTimeout, eventuallyInterval).Should(Succeed(), "Failed to switch the service to ClusterIP")
+
+			// The public IP may be released and reassigned once the service is no longer a load balancer; it must not
+			// be left behind in the InternalServiceExport for the Traffic Manager endpoints.
+			Eventually(serviceIsExportedToHubActual(corev1.ServiceTypeClusterIP, false), eventuallyTimeout, eventuallyInterval).Should(Succeed())
+		})
+	})
+
+	Context("export service with no ready backends", func() {
+		var svcExport = &fleetnetv1alpha1.ServiceExport{}
+		var svc = &corev1.Service{}
+		var endpointSlice = &discoveryv1.EndpointSlice{}
+
+		// noReadyBackendsCondActual returns a function which runs with Eventually assertion to make sure that
+		// the ServiceExport has the expected NoReadyBackends condition.
+		noReadyBackendsCondActual := func(want *metav1.Condition) func() error {
+			return func() error {
+				svcExport := &fleetnetv1alpha1.ServiceExport{}
+				if err := memberClient.Get(ctx, svcOrSvcExportKey, svcExport); err != nil {
+					return fmt.Errorf("serviceExport Get(%+v), got %w, want no error", svcOrSvcExportKey, err)
+				}
+				cond := meta.FindStatusCondition(svcExport.Status.Conditions, string(fleetnetv1alpha1.ServiceExportNoReadyBackends))
+				if diff := cmp.Diff(cond, want, ignoredCondFields); diff != "" {
+					return fmt.Errorf("serviceExportNoReadyBackends condition (-got, +want): %s", diff)
+				}
+				return nil
+			}
+		}
+
+		BeforeEach(func() {
+			svc = clusterIPService()
+			svc.Spec.Selector = map[string]string{"app": "nginx"}
+			Expect(memberClient.Create(ctx, svc)).Should(Succeed())
+
+			svcExport = notYetFulfilledServiceExport()
+			Expect(memberClient.Create(ctx, svcExport)).Should(Succeed())
+
+			endpointSlice = &discoveryv1.EndpointSlice{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: memberUserNS,
+					Name:      fmt.Sprintf("%s-1", svcName),
+					Labels:    map[string]string{discoveryv1.LabelServiceName: svcName},
+				},
+				AddressType: discoveryv1.AddressTypeIPv4,
+				Endpoints: []discoveryv1.Endpoint{
+					{
+						Addresses:  []string{"1.2.3.4"},
+						Conditions: discoveryv1.EndpointConditions{Ready: ptr.To(true)},
+					},
+				},
+			}
+		})
+
+		AfterEach(func() {
+			Expect(client.IgnoreNotFound(memberClient.Delete(ctx, endpointSlice))).Should(Succeed())
+			Expect(memberClient.Delete(ctx, svcExport)).Should(Succeed())
+			Expect(memberClient.Delete(ctx, svc)).Should(Succeed())
+
+			// Confirm that the Service has been unexported; this helps make the tests less flaky.
+			Eventually(serviceIsNotExportedActual, eventuallyTimeout, eventuallyInterval).Should(Succeed())
+
+			Eventually(serviceExportIsAbsentActual, eventuallyTimeout, eventuallyInterval).Should(Succeed())
+			Eventually(serviceIsAbsentActual, eventuallyTimeout, eventuallyInterval).Should(Succeed())
+		})
+
+		It("should export the service + should report no ready backends until ready endpoints appear", func() {
+			Eventually(serviceIsExportedFromMemberActual, eventuallyTimeout, eventuallyInterval).Should(Succeed())
+			Eventually(serviceIsExportedToHubActual(svc.Spec.Type, false), eventuallyTimeout, eventuallyInterval).Should(Succeed())
+
+			noReadyBackendsCond := serviceExportNoReadyBackendsCondition(memberUserNS, svcName, "app=nginx")
+			Eventually(noReadyBackendsCondActual(&noReadyBackendsCond), eventuallyTimeout, eventuallyInterval).Should(Succeed())
+
+			By("creating an endpointSlice with a ready endpoint")
+			Expect(memberClient.Create(ctx, endpointSlice)).Should(Succeed())
+
+			readyBackendsFoundCond := serviceExportReadyBackendsFoundCondition(memberUserNS, svcName)
+			Eventually(noReadyBackendsCondActual(&readyBackendsFoundCond), eventuallyTimeout, eventuallyInterval).Should(Succeed())
+			Consistently(serviceIsExportedFromMemberActual, consistentlyDuration, consistentlyInterval).Should(Succeed())
+		})
+	})
+
+	Context("export service from a namespace which is onboarded and offboarded", func() {
+		var svcExport = &fleetnetv1alpha1.ServiceExport{}
+		var svc = &corev1.Service{}
+		nsKey := types.NamespacedName{Name: notOnboardedMemberUserNS}
+		svcExportKey := types.NamespacedName{Namespace: notOnboardedMemberUserNS, Name: svcName}
+		internalSvcExportKey := types.NamespacedName{
+			Namespace: hubconfig.ExportNamespace(hubNSForMember, hubExportShards, notOnboardedMemberUserNS, svcName),
+			Name:      fmt.Sprintf("%s-%s", notOnboardedMemberUserNS, svcName),
+		}
+
+		setNamespaceLabels := func(nsLabels map[string]string) {
+			Eventually(func() error {
+				ns := &corev1.Namespace{}
+				if err := memberClient.Get(ctx, nsKey, ns); err != nil {
+					return err
+				}
+				ns.Labels = nsLabels
+				return memberClient.Update(ctx, ns)
+			}, eventuallyTimeout, eventuallyInterval).Should(Succeed(), "Failed to update the namespace labels")
+		}
+		// serviceIsNotOnboardedActual runs with Eventually assertion to make sure that the ServiceExport has been
+		// marked as invalid due to its namespace not being onboarded, and that the Service is not exported.
+		serviceIsNotOnboardedActual := func() error {
+			svcExport := &fleetnetv1alpha1.ServiceExport{}
+			if err := memberClient.Get(ctx, svcExportKey, svcExport); err != nil {
+				return fmt.Errorf("serviceExport Get(%+v), got %w, want no error", svcExportKey, err)
+			}
+			if len(svcExport.Finalizers) != 0 {
+				return fmt.Errorf("serviceExport finalizers, got %v, want empty list", svcExport.Finalizers)
+			}
+			expectedCond := serviceExportInvalidNamespaceNotOnboardedCondition(notOnboardedMemberUserNS)
+			validCond := meta.FindStatusCondition(svcExport.Status.Conditions, string(fleetnetv1alpha1.ServiceExportValid))
+			if diff := cmp.Diff(validCond, &expectedCond, ignoredCondFields); diff != "" {
+				return fmt.Errorf("serviceExportValid condition (-got, +want): %s", diff)
+			}
+			internalSvcExport := &fleetnetv1alpha1.InternalServiceExport{}
+			if err := hubClient.Get(ctx, internalSvcExportKey, internalSvcExport); !errors.IsNotFound(err) {
+				return fmt.Errorf("internalServiceExport Get(%+v), got %w, want not found", internalSvcExportKey, err)
+			}
+			return nil
+		}
+		// serviceIsOnboardedActual runs with Eventually assertion to make sure that the ServiceExport has been
+		// marked as valid, and that the Service has been exported.
+		serviceIsOnboardedActual := func() error {
+			svcExport := &fleetnetv1alpha1.ServiceExport{}
+			if err := memberClient.Get(ctx, svcExportKey, svcExport); err != nil {
+				return fmt.Errorf("serviceExport Get(%+v), got %w, want no error", svcExportKey, err)
+			}
+			if !cmp.Equal(svcExport.Finalizers, []string{svcExportCleanupFinalizer}) {
+				return fmt.Errorf("serviceExport finalizers, got %v, want %v", svcExport.Finalizers, []string{svcExportCleanupFinalizer})
+			}
+			expectedCond := serviceExportValidCondition(notOnboardedMemberUserNS, svcName)
+			validCond := meta.FindStatusCondition(svcExport.Status.Conditions, string(fleetnetv1alpha1.ServiceExportValid))
+			if diff := cmp.Diff(validCond, &expectedCond, ignoredCondFields); diff != "" {
+				return fmt.Errorf("serviceExportValid condition (-got, +want): %s", diff)
+			}
+			internalSvcExport := &fleetnetv1alpha1.InternalServiceExport{}
+			if err := hubClient.Get(ctx, internalSvcExportKey, internalSvcExport); err != nil {
+				return fmt.Errorf("internalServiceExport Get(%+v), got %w, want no error", internalSvcExportKey, err)
+			}
+			return nil
+		}
+
+		BeforeEach(func() {
+			svc = clusterIPService()
+			svc.Namespace = notOnboardedMemberUserNS
+			Expect(memberClient.Create(ctx, svc)).Should(Succeed())
+
+			svcExport = notYetFulfilledServiceExport()
+			svcExport.Namespace = notOnboardedMemberUserNS
+			Expect(memberClient.Create(ctx, svcExport)).Should(Succeed())
+		})
+
+		AfterEach(func() {
+			setNamespaceLabels(nil)
+			Expect(memberClient.Delete(ctx, svcExport)).Should(Succeed())
+			Expect(memberClient.Delete(ctx, svc)).Should(Succeed())
+
+			// Confirm that the Service has been unexported; this helps make the tests less flaky.
+			Eventually(serviceIsNotExportedActual, eventuallyTimeout, eventuallyInterval).Should(Succeed())
+			Eventually(func() error {
+				if err := memberClient.Get(ctx, svcExportKey, &fleetnetv1alpha1.ServiceExport{}); !errors.IsNotFound(err) {
+					return fmt.Errorf("serviceExport Get(%+v), got %w, want not found", svcExportKey, err)
+				}
+				return nil
+			}, eventuallyTimeout, eventuallyInterval).Should(Succeed())
+		})
+
+		It("should export the service only while the namespace is onboarded", func() {
+			By("not exporting the service from the namespace which is not onboarded")
+			Eventually(serviceIsNotOnboardedActual, eventuallyTimeout, eventuallyInterval).Should(Succeed())
+			Consistently(serviceIsNotExportedActual, consistentlyDuration, consistentlyInterval).Should(Succeed())
+
+			By("onboarding the namespace")
+			setNamespaceLabels(requiredNamespaceLabelsForTest)
+			Eventually(serviceIsOnboardedActual, eventuallyTimeout, eventuallyInterval).Should(Succeed())
+
+			By("offboarding the namespace")
+			setNamespaceLabels(map[string]string{"team": "web"})
+			Eventually(serviceIsNotOnboardedActual, eventuallyTimeout, eventuallyInterval).Should(Succeed())
+			Consistently(serviceIsNotExportedActual, consistentlyDuration, consistentlyInterval).Should(Succeed())
+		})
+	})
+
+	Context("export services across the hub export shards", func() {
+		// The Services are sharded by their namespaces and names; app lands in the member cluster namespace and web
+		// in the other shard namespace.
+		shardedSvcName := "web"
+		svcKeys := []types.NamespacedName{
+			{Namespace: memberUserNS, Name: svcName},
+			{Namespace: memberUserNS, Name: shardedSvcName},
+		}
+		wantHubNamespaces := map[types.NamespacedName]string{
+			{Namespace: memberUserNS, Name: svcName}:        hubNSForMember,
+			{Namespace: memberUserNS, Name: shardedSvcName}: hubShardNSForMember,
+		}
+		internalSvcExportKeyFor := func(svcKey types.NamespacedName, hubNS string) types.NamespacedName {
+			return types.NamespacedName{Namespace: hubNS, Name: fmt.Sprintf("%s-%s", svcKey.Namespace, svcKey.Name)}
+		}
+
+		BeforeEach(func() {
+			for _, svcKey := range svcKeys {
+				svc := clusterIPService()
+				svc.Name = svcKey.Name
+				Expect(memberClient.Create(ctx, svc)).Should(Succeed())
+
+				svcExport := notYetFulfilledServiceExport()
+				svcExport.Name = svcKey.Name
+				Expect(memberClient.Create(ctx, svcExport)).Should(Succeed())
+			}
+		})
+
+		AfterEach(func() {
+			for _, svcKey := range svcKeys {
+				Expect(client.IgnoreNotFound(memberClient.Delete(ctx, &fleetnetv1alpha1.ServiceExport{
+					ObjectMeta: metav1.ObjectMeta{Namespace: svcKey.Namespace, Name: svcKey.Name},
+				}))).Should(Succeed())
+				Expect(memberClient.Delete(ctx, &corev1.Service{
+					ObjectMeta: metav1.ObjectMeta{Namespace: svcKey.Namespace, Name: svcKey.Name},
+				})).Should(Succeed())
+			}
+
+			// Confirm that the Services have been unexported; this helps make the tests less flaky.
+			Eventually(serviceIsNotExportedActual, eventuallyTimeout, eventuallyInterval).Should(Succeed())
+		})
+
+		It("should export the services to their shard namespaces + should unexport them from the shards", func() {
+			for _, svcKey := range svcKeys {
+				wantHubNS := wantHubNamespaces[svcKey]
+				By(fmt.Sprintf("exporting service %s to hub namespace %s", svcKey, wantHubNS))
+				Expect(hubconfig.ExportNamespace(hubNSForMember, hubExportShards, svcKey.Namespace, svcKey.Name)).Should(Equal(wantHubNS))
+				key := internalSvcExportKeyFor(svcKey, wantHubNS)
+				Eventually(func() error {
+					internalSvcExport := &fleetnetv1alpha1.InternalServiceExport{}
+					if err := hubClient.Get(ctx, key, internalSvcExport); err != nil {
+						return fmt.Errorf("internalServiceExport Get(%+v), got %w, want no error", key, err)
+					}
+					if got := internalSvcExport.Labels[objectmeta.ExportedObjectLabelMemberCluster]; got != memberClusterID {
+						return fmt.Errorf("internalServiceExport member cluster label, got %q, want %q", got, memberClusterID)
+					}
+					return nil
+				}, eventuallyTimeout, eventuallyInterval).Should(Succeed())
+
+				for _, hubNS := range hubconfig.ExportNamespaces(hubNSForMember, hubExportShards) {
+					if hubNS == wantHubNS {
+						continue
+					}
+					otherKey := internalSvcExportKeyFor(svcKey, hubNS)
+					Consistently(func() error {
+						if err := hubClient.Get(ctx, otherKey, &fleetnetv1alpha1.InternalServiceExport{}); !errors.IsNotFound(err) {
+							return fmt.Errorf("internalServiceExport Get(%+v), got %w, want not found", otherKey, err)
+						}
+						return nil
+					}, consistentlyDuration, consistentlyInterval).Should(Succeed())
+				}
+			}
+
+			By("unexporting the service from the shard namespace")
+			shardedSvcKey := svcKeys[1]
+			Expect(memberClient.Delete(ctx, &fleetnetv1alpha1.ServiceExport{
+				ObjectMeta: metav1.ObjectMeta{Namespace: shardedSvcKey.Namespace, Name: shardedSvcKey.Name},
+			})).Should(Succeed())
+			shardedKey := internalSvcExportKeyFor(shardedSvcKey, hubShardNSForMember)
+			Eventually(func() error {
+				if err := hubClient.Get(ctx, shardedKey, &fleetnetv1alpha1.InternalServiceExport{}); !errors.IsNotFound(err) {
+					return fmt.Errorf("internalServiceExport Get(%+v), got %w, want not found", shardedKey, err)
+				}
+				return nil
+			}, eventuallyTimeout, eventuallyInterval).Should(Succeed())
+			Eventually(func() error {
+				if err := memberClient.Get(ctx, shardedSvcKey, &fleetnetv1alpha1.ServiceExport{}); !errors.IsNotFound(err) {
+					return fmt.Errorf("serviceExport Get(%+v), got %w, want not found", shardedSvcKey, err)
+				}
+				return nil
+			}, eventuallyTimeout, eventuallyInterval).Should(Succeed())
+
+			By("keeping the service exported to the member cluster namespace")
+			key := internalSvcExportKeyFor(svcKeys[0], hubNSForMember)
+			Consistently(func() error {
+				return hubClient.Get(ctx, key, &fleetnetv1alpha1.InternalServiceExport{})
+			}, consistentlyDuration, consistentlyInterval).Should(Succeed())
+		})
+	})
+
+	Context("unexport services from a namespace which is being deleted", func() {
+		deletedNS := "work-deleted"
+		svcNames := []string{"app", "web", "db"}
+		svcExportKeyFor := func(name string) types.NamespacedName {
+			return types.NamespacedName{Namespace: deletedNS, Name: name}
+		}
+		internalSvcExportKeyFor := func(name string) types.NamespacedName {
+			return types.NamespacedName{
+				Namespace: hubconfig.ExportNamespace(hubNSForMember, hubExportShards, deletedNS, name),
+				Name:      fmt.Sprintf("%s-%s", deletedNS, name),
+			}
+		}
+		// hubWritesSettledActual runs with Eventually assertion to make sure that the controller has stopped writing
+		// to the hub cluster, i.e. no write has been sent since the last poll.
+		lastHubWrites := int64(-1)
+		hubWritesSettledActual := func() error {
+			writes := hubWrites.writes.Load() + hubWrites.deletes.Load()
+			defer func() { lastHubWrites = writes }()
+			if writes != lastHubWrites {
+				return fmt.Errorf("hub writes, got %d, want %d (no write since the last poll)", writes, lastHubWrites)
+			}
+			return nil
+		}
+
+		BeforeEach(func() {
+			ns := &corev1.Namespace{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:   deletedNS,
+					Labels: requiredNamespaceLabelsForTest,
+				},
+			}
+			Expect(memberClient.Create(ctx, ns)).Should(Succeed())
+
+			for _, name := range svcNames {
+				svc := clusterIPService()
+				svc.Namespace = deletedNS
+				svc.Name = name
+				Expect(memberClient.Create(ctx, svc)).Should(Succeed())
+
+				svcExport := notYetFulfilledServiceExport()
+				svcExport.Namespace = deletedNS
+				svcExport.Name = name
+				Expect(memberClient.Create(ctx, svcExport)).Should(Succeed())
+			}
+		})
+
+		AfterEach(func() {
+			// The test environment runs no namespace controller, which would delete the ServiceExports of the deleted
+			// namespace; delete them, which must not be blocked by the cleanup finalizer.
+			for _, name := range svcNames {
+				Expect(client.IgnoreNotFound(memberClient.Delete(ctx, &fleetnetv1alpha1.ServiceExport{
+					ObjectMeta: metav1.ObjectMeta{Namespace: deletedNS, Name: name},
+				}))).Should(Succeed())
+			}
+			Eventually(func() error {
+				for _, name := range svcNames {
+					key := svcExportKeyFor(name)
+					if err := memberClient.Get(ctx, key, &fleetnetv1alpha1.ServiceExport{}); !errors.IsNotFound(err) {
+						return fmt.Errorf("serviceExport Get(%+v), got %w, want not found", key, err)
+					}
+				}
+				return nil
+			}, eventuallyTimeout, eventuallyInterval).Should(Succeed())
+		})
+
+		It("should unexport the services without marking the service exports as invalid + with bounded hub writes", func() {
+			By("exporting the services")
+			Eventually(func() error {
+				for _, name := range svcNames {
+					key := internalSvcExportKeyFor(name)
+					if err := hubClient.Get(ctx, key, &fleetnetv1alpha1.InternalServiceExport{}); err != nil {
+						return fmt.Errorf("internalServiceExport Get(%+v), got %w, want no error", key, err)
+					}
+				}
+				return nil
+			}, eventuallyTimeout, eventuallyInterval).Should(Succeed())
+			Eventually(hubWritesSettledActual, eventuallyTimeout, eventuallyInterval).Should(Succeed())
+			writesBefore, deletesBefore := hubWrites.writes.Load(), hubWrites.deletes.Load()
+
+			By("deleting the namespace and the services in it")
+			Expect(memberClient.Delete(ctx, &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: deletedNS}})).Should(Succeed())
+			for _, name := range svcNames {
+				Expect(memberClient.Delete(ctx, &corev1.Service{
+					ObjectMeta: metav1.ObjectMeta{Namespace: deletedNS, Name: name},
+				})).Should(Succeed())
+			}
+
+			By("unexporting the services")
+			Eventually(func() error {
+				for _, name := range svcNames {
+					key := internalSvcExportKeyFor(name)
+					if err := hubClient.Get(ctx, key, &fleetnetv1alpha1.InternalServiceExport{}); !errors.IsNotFound(err) {
+						return fmt.Errorf("internalServiceExport Get(%+v), got %w, want not found", key, err)
+					}
+					svcExport := &fleetnetv1alpha1.ServiceExport{}
+					if err := memberClient.Get(ctx, svcExportKeyFor(name), svcExport); err != nil {
+						return fmt.Errorf("serviceExport Get(%+v), got %w, want no error", svcExportKeyFor(name), err)
+					}
+					if len(svcExport.Finalizers) != 0 {
+						return fmt.Errorf("serviceExport finalizers, got %v, want empty list", svcExport.Finalizers)
+					}
+				}
+				return nil
+			}, eventuallyTimeout, eventuallyInterval).Should(Succeed())
+
+			By("keeping the service exports valid")
+			Consistently(func() error {
+				for _, name := range svcNames {
+					svcExport := &fleetnetv1alpha1.ServiceExport{}
+					if err := memberClient.Get(ctx, svcExportKeyFor(name), svcExport); err != nil {
+						return fmt.Errorf("serviceExport Get(%+v), got %w, want no error", svcExportKeyFor(name), err)
+					}
+					expectedCond := serviceExportValidCondition(deletedNS, name)
+					validCond := meta.FindStatusCondition(svcExport.Status.Conditions, string(fleetnetv1alpha1.ServiceExportValid))
+					if diff := cmp.Diff(validCond, &expectedCond, ignoredCondFields); diff != "" {
+						return fmt.Errorf("serviceExportValid condition (-got, +want): %s", diff)
+					}
+				}
+				return nil
+			}, consistentlyDuration, consistentlyInterval).Should(Succeed())
+
+			By("deleting each exported service from the hub cluster once, with no other hub writes")
+			Eventually(hubWritesSettledActual, eventuallyTimeout, eventuallyInterval).Should(Succeed())
+			Expect(hubWrites.writes.Load() - writesBefore).Should(BeZero())
+			Expect(hubWrites.deletes.Load() - deletesBefore).Should(BeNumerically("<=", len(svcNames)))
+		})
 	})
 })
@@ -7,19 +7,20 @@ package serviceexport
 
 import (
 	"context"
-	"errors"
 	"fmt"
 	"log"
 	"os"
+	"strings"
 	"testing"
 	"time"
 
-	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/network/armnetwork/v4"
 	"github.com/google/go-cmp/cmp"
 	"github.com/google/go-cmp/cmp/cmpopts"
 	corev1 "k8s.io/api/core/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/intstr"
 	"k8s.io/client-go/kubernetes/scheme"
@@ -27,12 +28,17 @@ import (
 	"k8s.io/utils/ptr"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	fleetnetv1alpha1 "go.goms.io/fleet-networking/api/v1alpha1"
 	"go.goms.io/fleet-networking/pkg/common/metrics"
+	"go.goms.io/fleet-networking/pkg/common/networkingmode"
 	"go.goms.io/fleet-networking/pkg/common/objectmeta"
 )
 
+// requiredNamespaceLabelsForTest are the labels a namespace must have before its ServiceExports are honored.
+var requiredNamespaceLabelsForTest = labels.Set{objectmeta.NamespaceLabelExportAllowed: "true"}
+
 const (
 	memberUserNS       = "work"
 	hubNSForMember     = "bravelion"
@@ -78,6 +84,42 @@ func serviceExportInvalidIneligibleCondition(userNS, svcName string) metav1.Cond
 	}
 }
 
+// serviceExportInvalidExportedNameCondition returns a ServiceExportValid condition for exporting a Service under
+// an invalid name.
+func serviceExportInvalidExportedNameCondition(userNS, svcName, exportedName string) metav1.Condition {
+	return metav1.Condition{
+		Type:               string(fleetnetv1alpha1.ServiceExportValid),
+		Status:             metav1.ConditionFalse,
+		LastTransitionTime: metav1.Now(),
+		Reason:             svcExportInvalidExportedNameCondReason,
+		Message:            fmt.Sprintf("exported name %q of service %s/%s is not a valid DNS-1035 label", exportedName, userNS, svcName),
+	}
+}
+
+// serviceExportInvalidNamespaceNotOnboardedCondition returns a ServiceExportValid condition for exporting a Service
+// from a namespace which has not been onboarded.
+func serviceExportInvalidNamespaceNotOnboardedCondition(userNS string) metav1.Condition {
+	return metav1.Condition{
+		Type:               string(fleetnetv1alpha1.ServiceExportValid),
+		Status:             metav1.ConditionFalse,
+		LastTransitionTime: metav1.Now(),
+		Reason:             svcExportNamespaceNotOnboardedReason,
+		Message:            fmt.Sprintf("namespace %s is not onboarded to multi-cluster networking; it must have the labels %q", userNS, requiredNamespaceLabelsForTest.String()),
+	}
+}
+
+// serviceExportInvalidExportDisabledCondition returns a ServiceExportValid condition for exporting a Service from a
+// member cluster in the import-only networking mode.
+func serviceExportInvalidExportDisabledCondition() metav1.Condition {
+	return metav1.Condition{
+		Type:               string(fleetnetv1alpha1.ServiceExportValid),
+		Status:             metav1.ConditionFalse,
+		LastTransitionTime: metav1.Now(),
+		Reason:             svcExportExportDisabledReason,
+		Message:            "member cluster is in the import-only networking mode; services cannot be exported",
+	}
+}
+
 // serviceExportPendingConflictResolutionCondition returns a ServiceExportConflict condition which reports that
 // a confliction resolution is in progress.
 func serviceExportPendingConflictResolutionCondition(userNS, svcName string) metav1.Condition {
@@ -102,6 +144,54 @@ func serviceExportNoConflictCondition(userNS, svcName string) metav1.Condition {
 	}
 }
 
+// serviceExportNoReadyBackendsCondition returns a ServiceExportNoReadyBackends condition which reports that a
+// service with the given selector has no ready endpoints.
+func serviceExportNoReadyBackendsCondition(userNS, svcName, selector string) metav1.Condition {
+	return metav1.Condition{
+		Type:               string(fleetnetv1alpha1.ServiceExportNoReadyBackends),
+		Status:             metav1.ConditionTrue,
+		LastTransitionTime: metav1.Now(),
+		Reason:             svcExportNoReadyBackendsCondReason,
+		Message:            fmt.Sprintf("service %s/%s has no ready endpoints; check that its selector %q matches ready pods", userNS, svcName, selector),
+	}
+}
+
+// serviceExportReadyBackendsFoundCondition returns a ServiceExportNoReadyBackends condition which reports that a
+// service has ready endpoints.
+func serviceExportReadyBackendsFoundCondition(userNS, svcName string) metav1.Condition {
+	return metav1.Condition{
+		Type:               string(fleetnetv1alpha1.ServiceExportNoReadyBackends),
+		Status:             metav1.ConditionFalse,
+		LastTransitionTime: metav1.Now(),
+		Reason:             svcExportReadyBackendsFoundCondReason,
+		Message:            fmt.Sprintf("service %s/%s has ready endpoints", userNS, svcName),
+	}
+}
+
+// serviceExportLoadBalancerPendingCondition returns a ServiceExportLoadBalancerPending condition which reports that
+// the public load balancer IP of a service is not yet provisioned.
+func serviceExportLoadBalancerPendingCondition(userNS, svcName string) metav1.Condition {
+	return metav1.Condition{
+		Type:               string(fleetnetv1alpha1.ServiceExportLoadBalancerPending),
+		Status:             metav1.ConditionTrue,
+		LastTransitionTime: metav1.Now(),
+		Reason:             svcExportLBPendingCondReason,
+		Message:            fmt.Sprintf("the public load balancer IP of service %s/%s is not yet provisioned", userNS, svcName),
+	}
+}
+
+// serviceExportLoadBalancerProvisionedCondition returns a ServiceExportLoadBalancerPending condition which reports
+// that a service is not pending a load balancer IP.
+func serviceExportLoadBalancerProvisionedCondition(userNS, svcName string) metav1.Condition {
+	return metav1.Condition{
+		Type:               string(fleetnetv1alpha1.ServiceExportLoadBalancerPending),
+		Status:             metav1.ConditionFalse,
+		LastTransitionTime: metav1.Now(),
+		Reason:             svcExportLBProvisionedCondReason,
+		Message:            fmt.Sprintf("service %s/%s is not pending a load balancer IP", userNS, svcName),
+	}
+}
+
 // TestMain bootstraps the test environment.
 func TestMain(m *testing.M) {
 	// Add custom APIs to the runtime scheme
@@ -198,6 +288,16 @@ func TestFormatInternalServiceExportName(t *testing.T) {
 			},
 			want: "work-app",
 		},
+		{
+			name: "should return truncated name",
+			svcExport: &fleetnetv1alpha1.ServiceExport{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: memberUserNS,
+					Name:      strings.Repeat("a", 253),
+				},
+			},
+			want: "work-" + strings.Repeat("a", 239) + "-1759e226",
+		},
 	}
 
 	for _, tc := range testCases {
@@ -237,6 +337,63 @@ func TestExtractServicePorts(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "should extract UDP and SCTP ports",
+			svc: &corev1.Service{
+				Spec: corev1.ServiceSpec{
+					Ports: []corev1.ServicePort{
+						{
+							Name:       "game",
+							Protocol:   corev1.ProtocolUDP,
+							Port:       7777,
+							TargetPort: intstr.FromInt(7777),
+						},
+						{
+							Name:       "signal",
+							Protocol:   corev1.ProtocolSCTP,
+							Port:       9999,
+							TargetPort: intstr.FromInt(9999),
+						},
+					},
+				},
+			},
+			want: []fleetnetv1alpha1.ServicePort{
+				{
+					Name:       "game",
+					Protocol:   corev1.ProtocolUDP,
+					Port:       7777,
+					TargetPort: intstr.FromInt(7777),
+				},
+				{
+					Name:       "signal",
+					Protocol:   corev1.ProtocolSCTP,
+					Port:       9999,
+					TargetPort: intstr.FromInt(9999),
+				},
+			},
+		},
+		{
+			name: "should default unset protocol to TCP",
+			svc: &corev1.Service{
+				Spec: corev1.ServiceSpec{
+					Ports: []corev1.ServicePort{
+						{
+							Name:       "web",
+							Port:       80,
+							TargetPort: intstr.FromInt(8080),
+						},
+					},
+				},
+			},
+			want: []fleetnetv1alpha1.ServicePort{
+				{
+					Name:       "web",
+					Protocol:   corev1.ProtocolTCP,
+					Port:       80,
+					TargetPort: intstr.FromInt(8080),
+				},
+			},
+		},
 	}
 
 	for _, tc := range testCases {
@@ -406,8 +563,9 @@ func TestMarkServiceExportAsInvalidIneligible(t *testing.T) {
 	}
 }
 
-// TestMarkServiceExportAsValid tests the *Reconciler.markServiceExportAsValid method.
-func TestMarkServiceExportAsValid(t *testing.T) {
+// TestMarkServiceExportAsInvalidExportedName tests the *Reconciler.markServiceExportAsInvalidExportedName method.
+func TestMarkServiceExportAsInvalidExportedName(t *testing.T) {
+	invalidExportedName := "Billing.v1"
 	testCases := []struct {
 		name      string
 		svcExport *fleetnetv1alpha1.ServiceExport
@@ -415,85 +573,12 @@ func TestMarkServiceExportAsValid(t *testing.T) {
 		wantConds []metav1.Condition
 	}{
 		{
-			name: "should mark a new svc export as valid",
-			svcExport: &fleetnetv1alpha1.ServiceExport{
-				ObjectMeta: metav1.ObjectMeta{
-					Namespace: memberUserNS,
-					Name:      svcName,
-				},
-			},
-			svc: &corev1.Service{
-				ObjectMeta: metav1.ObjectMeta{
-					Namespace: memberUserNS,
-					Name:      svcName,
-				},
-			},
-			wantConds: []metav1.Condition{
-				serviceExportValidCondition(memberUserNS, svcName),
-				serviceExportPendingConflictResolutionCondition(memberUserNS, svcName),
-			},
-		},
-		{
-			name: "should mark an invalid svc export (not found) as valid",
-			svcExport: &fleetnetv1alpha1.ServiceExport{
-				ObjectMeta: metav1.ObjectMeta{
-					Namespace: memberUserNS,
-					Name:      svcName,
-				},
-				Status: fleetnetv1alpha1.ServiceExportStatus{
-					Conditions: []metav1.Condition{
-						serviceExportInvalidNotFoundCondition(memberUserNS, svcName),
-					},
-				},
-			},
-			svc: &corev1.Service{
-				ObjectMeta: metav1.ObjectMeta{
-					Namespace: memberUserNS,
-					Name:      svcName,
-				},
-			},
-			wantConds: []metav1.Condition{
-				serviceExportValidCondition(memberUserNS, svcName),
-				serviceExportPendingConflictResolutionCondition(memberUserNS, svcName),
-			},
-		},
-		{
-			name: "should mark an invalid svc export (ineligible) as valid",
-			svcExport: &fleetnetv1alpha1.ServiceExport{
-				ObjectMeta: metav1.ObjectMeta{
-					Namespace: memberUserNS,
-					Name:      svcName,
-				},
-				Status: fleetnetv1alpha1.ServiceExportStatus{
-					Conditions: []metav1.Condition{
-						serviceExportInvalidIneligibleCondition(memberUserNS, svcName),
-					},
-				},
-			},
-			svc: &corev1.Service{
-				ObjectMeta: metav1.ObjectMeta{
-					Namespace: memberUserNS,
-					Name:      svcName,
-				},
-			},
-			wantConds: []metav1.Condition{
-				serviceExportValidCondition(memberUserNS, svcName),
-				serviceExportPendingConflictResolutionCondition(memberUserNS, svcName),
-			},
-		},
-		{
-			name: "should not mark a svc export that is valid already with a conflict condition (pending)",
+			name: "should mark a new svc export as invalid (exported name invalid)",
 			svcExport: &fleetnetv1alpha1.ServiceExport{
 				ObjectMeta: metav1.ObjectMeta{
 					Namespace: memberUserNS,
 					Name:      svcName,
 				},
-				Status: fleetnetv1alpha1.ServiceExportStatus{
-					Conditions: []metav1.Condition{
-						serviceExportValidCondition(memberUserNS, svcName),
-						serviceExportPendingConflictResolutionCondition(memberUserNS, svcName),
-					},
-				},
 			},
 			svc: &corev1.Service{
 				ObjectMeta: metav1.ObjectMeta{
@@ -502,12 +587,11 @@ func TestMarkServiceExportAsValid(t *testing.T) {
 				},
 			},
 			wantConds: []metav1.Condition{
-				serviceExportValidCondition(memberUserNS, svcName),
-				serviceExportPendingConflictResolutionCondition(memberUserNS, svcName),
+				serviceExportInvalidExportedNameCondition(memberUserNS, svcName, invalidExportedName),
 			},
 		},
 		{
-			name: "should not mark a svc export that is valid already with a conflict condition (no conflict)",
+			name: "should mark a valid svc export as invalid (exported name invalid)",
 			svcExport: &fleetnetv1alpha1.ServiceExport{
 				ObjectMeta: metav1.ObjectMeta{
 					Namespace: memberUserNS,
@@ -516,7 +600,6 @@ func TestMarkServiceExportAsValid(t *testing.T) {
 				Status: fleetnetv1alpha1.ServiceExportStatus{
 					Conditions: []metav1.Condition{
 						serviceExportValidCondition(memberUserNS, svcName),
-						serviceExportNoConflictCondition(memberUserNS, svcName),
 					},
 				},
 			},
@@ -527,8 +610,7 @@ func TestMarkServiceExportAsValid(t *testing.T) {
 				},
 			},
 			wantConds: []metav1.Condition{
-				serviceExportValidCondition(memberUserNS, svcName),
-				serviceExportNoConflictCondition(memberUserNS, svcName),
+				serviceExportInvalidExportedNameCondition(memberUserNS, svcName, invalidExportedName),
 			},
 		},
 	}
@@ -550,7 +632,7 @@ func TestMarkServiceExportAsValid(t *testing.T) {
 				Recorder:     record.NewFakeRecorder(10),
 			}
 
-			if err := reconciler.markServiceExportAsValid(ctx, tc.svcExport, tc.svc); err != nil {
+			if err := reconciler.markServiceExportAsInvalidExportedName(ctx, tc.svcExport, tc.svc, invalidExportedName); err != nil {
 				t.Fatalf("failed to mark svc export: %v", err)
 			}
 
@@ -567,23 +649,44 @@ func TestMarkServiceExportAsValid(t *testing.T) {
 	}
 }
 
-// TestRemoveServiceExportCleanupFinalizer tests the *Reconciler.removeServiceExportCleanupFinalizer method.
-func TestRemoveServiceExportCleanupFinalizer(t *testing.T) {
+// TestMarkServiceExportAsInvalidNamespaceNotOnboarded tests the
+// *Reconciler.markServiceExportAsInvalidNamespaceNotOnboarded method.
+func TestMarkServiceExportAsInvalidNamespaceNotOnboarded(t *testing.T) {
 	testCases := []struct {
 		name      string
 		svcExport *fleetnetv1alpha1.ServiceExport
-		want      []string
+		wantConds []metav1.Condition
 	}{
 		{
-			name: "should remove cleanup finalizer",
+			name: "should mark a new svc export as invalid (namespace not onboarded)",
 			svcExport: &fleetnetv1alpha1.ServiceExport{
 				ObjectMeta: metav1.ObjectMeta{
-					Namespace:  memberUserNS,
-					Name:       svcName,
-					Finalizers: []string{svcExportCleanupFinalizer},
+					Namespace: memberUserNS,
+					Name:      svcName,
 				},
 			},
-			want: nil,
+			wantConds: []metav1.Condition{
+				serviceExportInvalidNamespaceNotOnboardedCondition(memberUserNS),
+			},
+		},
+		{
+			name: "should mark a valid svc export as invalid (namespace not onboarded)",
+			svcExport: &fleetnetv1alpha1.ServiceExport{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: memberUserNS,
+					Name:      svcName,
+				},
+				Status: fleetnetv1alpha1.ServiceExportStatus{
+					Conditions: []metav1.Condition{
+						serviceExportValidCondition(memberUserNS, svcName),
+						serviceExportNoConflictCondition(memberUserNS, svcName),
+					},
+				},
+			},
+			wantConds: []metav1.Condition{
+				serviceExportInvalidNamespaceNotOnboardedCondition(memberUserNS),
+				serviceExportNoConflictCondition(memberUserNS, svcName),
+			},
 		},
 	}
 
@@ -594,48 +697,69 @@ func TestRemoveServiceExportCleanupFinalizer(t *testing.T) {
 			fakeMemberClient := fake.NewClientBuilder().
 				WithScheme(scheme.Scheme).
 				WithObjects(tc.svcExport).
+				WithStatusSubresource(tc.svcExport).
 				Build()
-			fakeHubClient := fake.NewClientBuilder().Build()
 			reconciler := Reconciler{
-				MemberClient: fakeMemberClient,
-				HubClient:    fakeHubClient,
-				HubNamespace: hubNSForMember,
-				Recorder:     record.NewFakeRecorder(10),
+				MemberClient:            fakeMemberClient,
+				HubClient:               fake.NewClientBuilder().Build(),
+				HubNamespace:            hubNSForMember,
+				Recorder:                record.NewFakeRecorder(10),
+				RequiredNamespaceLabels: requiredNamespaceLabelsForTest,
 			}
 
-			if err := reconciler.removeServiceExportCleanupFinalizer(ctx, tc.svcExport); err != nil {
-				t.Fatalf("removeServiceExportCleanupFinalizer(%+v) = %v, want no error", tc.svcExport, err)
+			if err := reconciler.markServiceExportAsInvalidNamespaceNotOnboarded(ctx, tc.svcExport); err != nil {
+				t.Fatalf("failed to mark svc export: %v", err)
 			}
 
 			var updatedSvcExport = &fleetnetv1alpha1.ServiceExport{}
-			updatedSvcExportKey := types.NamespacedName{Namespace: memberUserNS, Name: svcName}
-			if err := fakeMemberClient.Get(ctx, updatedSvcExportKey, updatedSvcExport); err != nil {
-				t.Fatalf("svc export Get(%+v), got %v, want no error", updatedSvcExportKey, err)
+			svcExportKey := types.NamespacedName{Namespace: tc.svcExport.Namespace, Name: tc.svcExport.Name}
+			if err := fakeMemberClient.Get(ctx, svcExportKey, updatedSvcExport); err != nil {
+				t.Fatalf("svc export Get(%+v): %v", svcExportKey, err)
 			}
-
-			if !cmp.Equal(updatedSvcExport.ObjectMeta.Finalizers, tc.want) {
-				t.Fatalf("svc export finalizer, got %+v, want %+v", updatedSvcExport.ObjectMeta.Finalizers, tc.want)
+			if diff := cmp.Diff(tc.wantConds, updatedSvcExport.Status.Conditions, ignoredCondFields); diff != "" {
+				t.Fatalf("svc export conditions (-want, +got):\n%s", diff)
 			}
 		})
 	}
 }
 
-// TestAddServiceExportCleanupFinalizer tests the *Reconciler.addServiceExportCleanupFinalizer method.
-func TestAddServiceExportCleanupFinalizer(t *testing.T) {
+// TestMarkServiceExportAsInvalidExportDisabled tests the *Reconciler.markServiceExportAsInvalidExportDisabled method.
+func TestMarkServiceExportAsInvalidExportDisabled(t *testing.T) {
 	testCases := []struct {
 		name      string
 		svcExport *fleetnetv1alpha1.ServiceExport
-		want      []string
+		wantConds []metav1.Condition
 	}{
 		{
-			name: "should add cleanup finalizer",
+			name: "should mark a new svc export as invalid (export disabled)",
 			svcExport: &fleetnetv1alpha1.ServiceExport{
 				ObjectMeta: metav1.ObjectMeta{
 					Namespace: memberUserNS,
 					Name:      svcName,
 				},
 			},
-			want: []string{svcExportCleanupFinalizer},
+			wantConds: []metav1.Condition{
+				serviceExportInvalidExportDisabledCondition(),
+			},
+		},
+		{
+			name: "should mark a valid svc export as invalid (export disabled)",
+			svcExport: &fleetnetv1alpha1.ServiceExport{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: memberUserNS,
+					Name:      svcName,
+				},
+				Status: fleetnetv1alpha1.ServiceExportStatus{
+					Conditions: []metav1.Condition{
+						serviceExportValidCondition(memberUserNS, svcName),
+						serviceExportNoConflictCondition(memberUserNS, svcName),
+					},
+				},
+			},
+			wantConds: []metav1.Condition{
+				serviceExportInvalidExportDisabledCondition(),
+				serviceExportNoConflictCondition(memberUserNS, svcName),
+			},
 		},
 	}
 
@@ -646,27 +770,1042 @@ func TestAddServiceExportCleanupFinalizer(t *testing.T) {
 			fakeMemberClient := fake.NewClientBuilder().
 				WithScheme(scheme.Scheme).
 				WithObjects(tc.svcExport).
+				WithStatusSubresource(tc.svcExport).
 				Build()
-			fakeHubClient := fake.NewClientBuilder().Build()
+			gate := networkingmode.NewGate()
+			gate.Set(networkingmode.ModeImportOnly)
 			reconciler := Reconciler{
-				MemberClient: fakeMemberClient,
-				HubClient:    fakeHubClient,
-				HubNamespace: hubNSForMember,
-				Recorder:     record.NewFakeRecorder(10),
+				MemberClient:   fakeMemberClient,
+				HubClient:      fake.NewClientBuilder().Build(),
+				HubNamespace:   hubNSForMember,
+				Recorder:       record.NewFakeRecorder(10),
+				NetworkingMode: gate,
 			}
 
-			if err := reconciler.addServiceExportCleanupFinalizer(ctx, tc.svcExport); err != nil {
-				t.Fatalf("addServiceExportCleanupFinalizer(%+v), got %v, want no error", tc.svcExport, err)
+			if err := reconciler.markServiceExportAsInvalidExportDisabled(ctx, tc.svcExport); err != nil {
+				t.Fatalf("failed to mark svc export: %v", err)
+			}
+
+			var updatedSvcExport = &fleetnetv1alpha1.ServiceExport{}
+			svcExportKey := types.NamespacedName{Namespace: tc.svcExport.Namespace, Name: tc.svcExport.Name}
+			if err := fakeMemberClient.Get(ctx, svcExportKey, updatedSvcExport); err != nil {
+				t.Fatalf("svc export Get(%+v): %v", svcExportKey, err)
+			}
+			if diff := cmp.Diff(tc.wantConds, updatedSvcExport.Status.Conditions, ignoredCondFields); diff != "" {
+				t.Fatalf("svc export conditions (-want, +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+// TestIsNamespaceOnboarded tests the *Reconciler.isNamespaceOnboarded method.
+func TestIsNamespaceOnboarded(t *testing.T) {
+	testCases := []struct {
+		name     string
+		nsLabels map[string]string
+		want     bool
+	}{
+		{
+			name:     "namespace with the required labels",
+			nsLabels: map[string]string{objectmeta.NamespaceLabelExportAllowed: "true", "team": "web"},
+			want:     true,
+		},
+		{
+			name:     "namespace with a different label value",
+			nsLabels: map[string]string{objectmeta.NamespaceLabelExportAllowed: "false"},
+		},
+		{
+			name: "namespace without labels",
+		},
+	}
+
+	ctx := context.Background()
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ns := &corev1.Namespace{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:   memberUserNS,
+					Labels: tc.nsLabels,
+				},
+			}
+			reconciler := Reconciler{
+				MemberClient:            fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(ns).Build(),
+				RequiredNamespaceLabels: requiredNamespaceLabelsForTest,
+			}
+			got, err := reconciler.isNamespaceOnboarded(ctx, memberUserNS)
+			if err != nil {
+				t.Fatalf("isNamespaceOnboarded(), got %v, want no error", err)
+			}
+			if got != tc.want {
+				t.Errorf("isNamespaceOnboarded(), got %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
+
+// TestMarkServiceExportAsValid tests the *Reconciler.markServiceExportAsValid method.
+func TestMarkServiceExportAsValid(t *testing.T) {
+	testCases := []struct {
+		name      string
+		svcExport *fleetnetv1alpha1.ServiceExport
+		svc       *corev1.Service
+		wantConds []metav1.Condition
+	}{
+		{
+			name: "should mark a new svc export as valid",
+			svcExport: &fleetnetv1alpha1.ServiceExport{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: memberUserNS,
+					Name:      svcName,
+				},
+			},
+			svc: &corev1.Service{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: memberUserNS,
+					Name:      svcName,
+				},
+			},
+			wantConds: []metav1.Condition{
+				serviceExportValidCondition(memberUserNS, svcName),
+				serviceExportPendingConflictResolutionCondition(memberUserNS, svcName),
+			},
+		},
+		{
+			name: "should mark an invalid svc export (not found) as valid",
+			svcExport: &fleetnetv1alpha1.ServiceExport{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: memberUserNS,
+					Name:      svcName,
+				},
+				Status: fleetnetv1alpha1.ServiceExportStatus{
+					Conditions: []metav1.Condition{
+						serviceExportInvalidNotFoundCondition(memberUserNS, svcName),
+					},
+				},
+			},
+			svc: &corev1.Service{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: memberUserNS,
+					Name:      svcName,
+				},
+			},
+			wantConds: []metav1.Condition{
+				serviceExportValidCondition(memberUserNS, svcName),
+				serviceExportPendingConflictResolutionCondition(memberUserNS, svcName),
+			},
+		},
+		{
+			name: "should mark an invalid svc export (ineligible) as valid",
+			svcExport: &fleetnetv1alpha1.ServiceExport{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: memberUserNS,
+					Name:      svcName,
+				},
+				Status: fleetnetv1alpha1.ServiceExportStatus{
+					Conditions: []metav1.Condition{
+						serviceExportInvalidIneligibleCondition(memberUserNS, svcName),
+					},
+				},
+			},
+			svc: &corev1.Service{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: memberUserNS,
+					Name:      svcName,
+				},
+			},
+			wantConds: []metav1.Condition{
+				serviceExportValidCondition(memberUserNS, svcName),
+				serviceExportPendingConflictResolutionCondition(memberUserNS, svcName),
+			},
+		},
+		{
+			name: "should not mark a svc export that is valid already with a conflict condition (pending)",
+			svcExport: &fleetnetv1alpha1.ServiceExport{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: memberUserNS,
+					Name:      svcName,
+				},
+				Status: fleetnetv1alpha1.ServiceExportStatus{
+					Conditions: []metav1.Condition{
+						serviceExportValidCondition(memberUserNS, svcName),
+						serviceExportPendingConflictResolutionCondition(memberUserNS, svcName),
+					},
+				},
+			},
+			svc: &corev1.Service{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: memberUserNS,
+					Name:      svcName,
+				},
+			},
+			wantConds: []metav1.Condition{
+				serviceExportValidCondition(memberUserNS, svcName),
+				serviceExportPendingConflictResolutionCondition(memberUserNS, svcName),
+			},
+		},
+		{
+			name: "should not mark a svc export that is valid already with a conflict condition (no conflict)",
+			svcExport: &fleetnetv1alpha1.ServiceExport{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: memberUserNS,
+					Name:      svcName,
+				},
+				Status: fleetnetv1alpha1.ServiceExportStatus{
+					Conditions: []metav1.Condition{
+						serviceExportValidCondition(memberUserNS, svcName),
+						serviceExportNoConflictCondition(memberUserNS, svcName),
+					},
+				},
+			},
+			svc: &corev1.Service{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: memberUserNS,
+					Name:      svcName,
+				},
+			},
+			wantConds: []metav1.Condition{
+				serviceExportValidCondition(memberUserNS, svcName),
+				serviceExportNoConflictCondition(memberUserNS, svcName),
+			},
+		},
+	}
+
+	ctx := context.Background()
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			fakeMemberClient := fake.NewClientBuilder().
+				WithScheme(scheme.Scheme).
+				WithObjects(tc.svcExport).
+				WithStatusSubresource(tc.svcExport).
+				Build()
+			fakeHubClient := fake.NewClientBuilder().Build()
+			reconciler := Reconciler{
+				MemberClient: fakeMemberClient,
+				HubClient:    fakeHubClient,
+				HubNamespace: hubNSForMember,
+				Recorder:     record.NewFakeRecorder(10),
+			}
+
+			if err := reconciler.markServiceExportAsValid(ctx, tc.svcExport, tc.svc); err != nil {
+				t.Fatalf("failed to mark svc export: %v", err)
+			}
+
+			var updatedSvcExport = &fleetnetv1alpha1.ServiceExport{}
+			svcExportKey := types.NamespacedName{Namespace: tc.svcExport.Namespace, Name: tc.svcExport.Name}
+			if err := fakeMemberClient.Get(ctx, svcExportKey, updatedSvcExport); err != nil {
+				t.Fatalf("svc export Get(%+v), got %v, want no error", svcExportKey, err)
+			}
+			conds := updatedSvcExport.Status.Conditions
+			if !cmp.Equal(conds, tc.wantConds, ignoredCondFields) {
+				t.Fatalf("svc export conditions, got %+v, want %+v", conds, tc.wantConds)
+			}
+		})
+	}
+}
+
+// TestRemoveServiceExportCleanupFinalizer tests the *Reconciler.removeServiceExportCleanupFinalizer method.
+func TestRemoveServiceExportCleanupFinalizer(t *testing.T) {
+	testCases := []struct {
+		name      string
+		svcExport *fleetnetv1alpha1.ServiceExport
+		want      []string
+	}{
+		{
+			name: "should remove cleanup finalizer",
+			svcExport: &fleetnetv1alpha1.ServiceExport{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace:  memberUserNS,
+					Name:       svcName,
+					Finalizers: []string{svcExportCleanupFinalizer},
+				},
+			},
+			want: nil,
+		},
+	}
+
+	ctx := context.Background()
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			fakeMemberClient := fake.NewClientBuilder().
+				WithScheme(scheme.Scheme).
+				WithObjects(tc.svcExport).
+				Build()
+			fakeHubClient := fake.NewClientBuilder().Build()
+			reconciler := Reconciler{
+				MemberClient: fakeMemberClient,
+				HubClient:    fakeHubClient,
+				HubNamespace: hubNSForMember,
+				Recorder:     record.NewFakeRecorder(10),
+			}
+
+			if err := reconciler.removeServiceExportCleanupFinalizer(ctx, tc.svcExport); err != nil {
+				t.Fatalf("removeServiceExportCleanupFinalizer(%+v) = %v, want no error", tc.svcExport, err)
+			}
+
+			var updatedSvcExport = &fleetnetv1alpha1.ServiceExport{}
+			updatedSvcExportKey := types.NamespacedName{Namespace: memberUserNS, Name: svcName}
+			if err := fakeMemberClient.Get(ctx, updatedSvcExportKey, updatedSvcExport); err != nil {
+				t.Fatalf("svc export Get(%+v), got %v, want no error", updatedSvcExportKey, err)
+			}
+
+			if !cmp.Equal(updatedSvcExport.ObjectMeta.Finalizers, tc.want) {
+				t.Fatalf("svc export finalizer, got %+v, want %+v", updatedSvcExport.ObjectMeta.Finalizers, tc.want)
+			}
+		})
+	}
+}
+
+// TestAddServiceExportCleanupFinalizer tests the *Reconciler.addServiceExportCleanupFinalizer method.
+func TestAddServiceExportCleanupFinalizer(t *testing.T) {
+	testCases := []struct {
+		name      string
+		svcExport *fleetnetv1alpha1.ServiceExport
+		want      []string
+	}{
+		{
+			name: "should add cleanup finalizer",
+			svcExport: &fleetnetv1alpha1.ServiceExport{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: memberUserNS,
+					Name:      svcName,
+				},
+			},
+			want: []string{svcExportCleanupFinalizer},
+		},
+	}
+
+	ctx := context.Background()
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			fakeMemberClient := fake.NewClientBuilder().
+				WithScheme(scheme.Scheme).
+				WithObjects(tc.svcExport).
+				Build()
+			fakeHubClient := fake.NewClientBuilder().Build()
+			reconciler := Reconciler{
+				MemberClient: fakeMemberClient,
+				HubClient:    fakeHubClient,
+				HubNamespace: hubNSForMember,
+				Recorder:     record.NewFakeRecorder(10),
+			}
+
+			if err := reconciler.addServiceExportCleanupFinalizer(ctx, tc.svcExport); err != nil {
+				t.Fatalf("addServiceExportCleanupFinalizer(%+v), got %v, want no error", tc.svcExport, err)
+			}
+
+			var updatedSvcExport = &fleetnetv1alpha1.ServiceExport{}
+			updatedSvcExportKey := types.NamespacedName{Namespace: memberUserNS, Name: svcName}
+			if err := fakeMemberClient.Get(ctx, updatedSvcExportKey, updatedSvcExport); err != nil {
+				t.Fatalf("svc export Get(%+v), got %v, want no error", updatedSvcExportKey, err)
+			}
+
+			if !cmp.Equal(updatedSvcExport.ObjectMeta.Finalizers, tc.want) {
+				t.Fatalf("svc export finalizer, got %+v, want %+v", updatedSvcExport.ObjectMeta.Finalizers, tc.want)
+			}
+		})
+	}
+}
+
+// TestRefreshHeartbeat tests the *Reconciler.refreshHeartbeat method.
+func TestRefreshHeartbeat(t *testing.T) {
+	now := time.Now().Truncate(time.Second)
+	recentHeartbeatTime := metav1.NewTime(now.Add(-time.Minute))
+	oldHeartbeatTime := metav1.NewTime(now.Add(-3 * time.Minute))
+
+	testCases := []struct {
+		name                  string
+		lastHeartbeatTime     *metav1.Time
+		wantLastHeartbeatTime metav1.Time
+	}{
+		{
+			name:                  "should add heartbeat",
+			wantLastHeartbeatTime: metav1.NewTime(now),
+		},
+		{
+			name:                  "should refresh old heartbeat",
+			lastHeartbeatTime:     &oldHeartbeatTime,
+			wantLastHeartbeatTime: metav1.NewTime(now),
+		},
+		{
+			name:                  "should skip recent heartbeat",
+			lastHeartbeatTime:     &recentHeartbeatTime,
+			wantLastHeartbeatTime: recentHeartbeatTime,
+		},
+	}
+
+	ctx := context.Background()
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			internalSvcExport := &fleetnetv1alpha1.InternalServiceExport{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: hubNSForMember,
+					Name:      formatInternalServiceExportName(&fleetnetv1alpha1.ServiceExport{ObjectMeta: metav1.ObjectMeta{Namespace: memberUserNS, Name: svcName}}),
+				},
+				Status: fleetnetv1alpha1.InternalServiceExportStatus{
+					LastHeartbeatTime: tc.lastHeartbeatTime,
+				},
+			}
+			fakeHubClient := fake.NewClientBuilder().
+				WithScheme(scheme.Scheme).
+				WithObjects(internalSvcExport).
+				WithStatusSubresource(internalSvcExport).
+				Build()
+			reconciler := Reconciler{
+				MemberClient:      fake.NewClientBuilder().Build(),
+				HubClient:         fakeHubClient,
+				HubNamespace:      hubNSForMember,
+				Recorder:          record.NewFakeRecorder(10),
+				HeartbeatInterval: 5 * time.Minute,
+			}
+
+			if err := reconciler.refreshHeartbeat(ctx, internalSvcExport, now); err != nil {
+				t.Fatalf("refreshHeartbeat(), got %v, want no error", err)
+			}
+
+			got := &fleetnetv1alpha1.InternalServiceExport{}
+			if err := fakeHubClient.Get(ctx, types.NamespacedName{Namespace: internalSvcExport.Namespace, Name: internalSvcExport.Name}, got); err != nil {
+				t.Fatalf("internalServiceExport Get(), got %v, want no error", err)
+			}
+			if got.Status.LastHeartbeatTime == nil || !got.Status.LastHeartbeatTime.Equal(&tc.wantLastHeartbeatTime) {
+				t.Fatalf("lastHeartbeatTime, got %v, want %v", got.Status.LastHeartbeatTime, tc.wantLastHeartbeatTime)
+			}
+		})
+	}
+}
+
+// TestUpdateNoReadyBackendsCondition tests the *Reconciler.updateNoReadyBackendsCondition method.
+func TestUpdateNoReadyBackendsCondition(t *testing.T) {
+	svc := &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: memberUserNS,
+			Name:      svcName,
+		},
+		Spec: corev1.ServiceSpec{
+			Selector: map[string]string{"app": "nginx"},
+		},
+	}
+	endpointSlice := func(name, svcName string, ready ...*bool) *discoveryv1.EndpointSlice {
+		endpointSlice := &discoveryv1.EndpointSlice{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: memberUserNS,
+				Name:      name,
+				Labels:    map[string]string{discoveryv1.LabelServiceName: svcName},
+			},
+			AddressType: discoveryv1.AddressTypeIPv4,
+		}
+		for i, r := range ready {
+			endpointSlice.Endpoints = append(endpointSlice.Endpoints, discoveryv1.Endpoint{
+				Addresses:  []string{fmt.Sprintf("1.2.3.%d", i+1)},
+				Conditions: discoveryv1.EndpointConditions{Ready: r},
+			})
+		}
+		return endpointSlice
+	}
+
+	testCases := []struct {
+		name           string
+		conds          []metav1.Condition
+		endpointSlices []*discoveryv1.EndpointSlice
+		wantConds      []metav1.Condition
+	}{
+		{
+			name: "should not add the condition when the service has ready endpoints",
+			endpointSlices: []*discoveryv1.EndpointSlice{
+				endpointSlice("app-1", svcName, ptr.To(false)),
+				endpointSlice("app-2", svcName, nil),
+			},
+		},
+		{
+			name: "should add the condition when the service has no endpointSlices",
+			endpointSlices: []*discoveryv1.EndpointSlice{
+				endpointSlice("other-1", "other", ptr.To(true)),
+			},
+			wantConds: []metav1.Condition{
+				serviceExportNoReadyBackendsCondition(memberUserNS, svcName, "app=nginx"),
+			},
+		},
+		{
+			name: "should add the condition when the service has no ready endpoints",
+			conds: []metav1.Condition{
+				serviceExportValidCondition(memberUserNS, svcName),
+			},
+			endpointSlices: []*discoveryv1.EndpointSlice{
+				endpointSlice("app-1", svcName),
+				endpointSlice("app-2", svcName, ptr.To(false)),
+			},
+			wantConds: []metav1.Condition{
+				serviceExportValidCondition(memberUserNS, svcName),
+				serviceExportNoReadyBackendsCondition(memberUserNS, svcName, "app=nginx"),
+			},
+		},
+		{
+			name: "should clear the condition when ready endpoints appear",
+			conds: []metav1.Condition{
+				serviceExportValidCondition(memberUserNS, svcName),
+				serviceExportNoReadyBackendsCondition(memberUserNS, svcName, "app=nginx"),
+			},
+			endpointSlices: []*discoveryv1.EndpointSlice{
+				endpointSlice("app-1", svcName, ptr.To(true)),
+			},
+			wantConds: []metav1.Condition{
+				serviceExportValidCondition(memberUserNS, svcName),
+				serviceExportReadyBackendsFoundCondition(memberUserNS, svcName),
+			},
+		},
+		{
+			name: "should set the condition again when ready endpoints disappear",
+			conds: []metav1.Condition{
+				serviceExportReadyBackendsFoundCondition(memberUserNS, svcName),
+			},
+			wantConds: []metav1.Condition{
+				serviceExportNoReadyBackendsCondition(memberUserNS, svcName, "app=nginx"),
+			},
+		},
+	}
+
+	ctx := context.Background()
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			svcExport := &fleetnetv1alpha1.ServiceExport{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: memberUserNS,
+					Name:      svcName,
+				},
+				Status: fleetnetv1alpha1.ServiceExportStatus{
+					Conditions: tc.conds,
+				},
+			}
+			builder := fake.NewClientBuilder().
+				WithScheme(scheme.Scheme).
+				WithObjects(svcExport).
+				WithStatusSubresource(svcExport)
+			for _, endpointSlice := range tc.endpointSlices {
+				builder = builder.WithObjects(endpointSlice)
+			}
+			fakeMemberClient := builder.Build()
+			reconciler := Reconciler{
+				MemberClient: fakeMemberClient,
+				HubClient:    fake.NewClientBuilder().Build(),
+				HubNamespace: hubNSForMember,
+				Recorder:     record.NewFakeRecorder(10),
+			}
+
+			if err := reconciler.updateNoReadyBackendsCondition(ctx, svcExport, svc); err != nil {
+				t.Fatalf("updateNoReadyBackendsCondition(), got %v, want no error", err)
+			}
+
+			updatedSvcExport := &fleetnetv1alpha1.ServiceExport{}
+			svcExportKey := types.NamespacedName{Namespace: svcExport.Namespace, Name: svcExport.Name}
+			if err := fakeMemberClient.Get(ctx, svcExportKey, updatedSvcExport); err != nil {
+				t.Fatalf("svc export Get(%+v), got %v, want no error", svcExportKey, err)
+			}
+			if diff := cmp.Diff(tc.wantConds, updatedSvcExport.Status.Conditions, ignoredCondFields, cmpopts.EquateEmpty()); diff != "" {
+				t.Fatalf("svc export conditions (-want, +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+// TestFormatNoReadyBackendsMessage tests the formatNoReadyBackendsMessage function.
+func TestFormatNoReadyBackendsMessage(t *testing.T) {
+	testCases := []struct {
+		name     string
+		selector map[string]string
+		want     string
+	}{
+		{
+			name:     "service with selector",
+			selector: map[string]string{"tier": "web", "app": "nginx"},
+			want:     `service work/app has no ready endpoints; check that its selector "app=nginx,tier=web" matches ready pods`,
+		},
+		{
+			name: "service without selector",
+			want: "service work/app has no selector and none of its endpointSlices has a ready endpoint",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			svc := &corev1.Service{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: memberUserNS,
+					Name:      svcName,
+				},
+				Spec: corev1.ServiceSpec{
+					Selector: tc.selector,
+				},
+			}
+			if got := formatNoReadyBackendsMessage(svc); got != tc.want {
+				t.Errorf("formatNoReadyBackendsMessage(), got %q, want %q", got, tc.want)
+			}
+		})
+	}
+}
+
+// TestIsLoadBalancerPending tests the isLoadBalancerPending function.
+func TestIsLoadBalancerPending(t *testing.T) {
+	testCases := []struct {
+		name    string
+		svcType corev1.ServiceType
+		ingress []corev1.LoadBalancerIngress
+		want    bool
+	}{
+		{
+			name:    "cluster IP service",
+			svcType: corev1.ServiceTypeClusterIP,
+		},
+		{
+			name:    "load balancer service without ingress",
+			svcType: corev1.ServiceTypeLoadBalancer,
+			want:    true,
+		},
+		{
+			name:    "load balancer service with ingress of empty IP",
+			svcType: corev1.ServiceTypeLoadBalancer,
+			ingress: []corev1.LoadBalancerIngress{{Hostname: "example.com"}},
+			want:    true,
+		},
+		{
+			name:    "load balancer service with ingress IP",
+			svcType: corev1.ServiceTypeLoadBalancer,
+			ingress: []corev1.LoadBalancerIngress{{IP: "1.2.3.4"}},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			svc := &corev1.Service{
+				Spec: corev1.ServiceSpec{
+					Type: tc.svcType,
+				},
+				Status: corev1.ServiceStatus{
+					LoadBalancer: corev1.LoadBalancerStatus{
+						Ingress: tc.ingress,
+					},
+				},
+			}
+			if got := isLoadBalancerPending(svc); got != tc.want {
+				t.Errorf("isLoadBalancerPending(), got %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
+
+// TestSetExternalTrafficPolicyInformation tests the setExternalTrafficPolicyInformation function.
+func TestSetExternalTrafficPolicyInformation(t *testing.T) {
+	testCases := []struct {
+		name string
+		spec corev1.ServiceSpec
+		want fleetnetv1alpha1.InternalServiceExportSpec
+	}{
+		{
+			name: "cluster IP service",
+			spec: corev1.ServiceSpec{
+				Type: corev1.ServiceTypeClusterIP,
+			},
+		},
+		{
+			name: "node port service",
+			spec: corev1.ServiceSpec{
+				Type:                  corev1.ServiceTypeNodePort,
+				ExternalTrafficPolicy: corev1.ServiceExternalTrafficPolicyLocal,
+			},
+		},
+		{
+			name: "load balancer service with cluster policy",
+			spec: corev1.ServiceSpec{
+				Type:                  corev1.ServiceTypeLoadBalancer,
+				ExternalTrafficPolicy: corev1.ServiceExternalTrafficPolicyCluster,
+			},
+			want: fleetnetv1alpha1.InternalServiceExportSpec{
+				ExternalTrafficPolicy: corev1.ServiceExternalTrafficPolicyCluster,
+			},
+		},
+		{
+			name: "load balancer service with local policy",
+			spec: corev1.ServiceSpec{
+				Type:                  corev1.ServiceTypeLoadBalancer,
+				ExternalTrafficPolicy: corev1.ServiceExternalTrafficPolicyLocal,
+				HealthCheckNodePort:   30123,
+			},
+			want: fleetnetv1alpha1.InternalServiceExportSpec{
+				ExternalTrafficPolicy: corev1.ServiceExternalTrafficPolicyLocal,
+				HealthCheckNodePort:   30123,
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			svc := &corev1.Service{Spec: tc.spec}
+			export := &fleetnetv1alpha1.InternalServiceExport{}
+			setExternalTrafficPolicyInformation(svc, export)
+			if diff := cmp.Diff(export.Spec, tc.want); diff != "" {
+				t.Errorf("setExternalTrafficPolicyInformation() spec mismatch (-got, +want):\n%s", diff)
+			}
+		})
+	}
+}
+
+// TestFormatLoadBalancerPendingMessage tests the formatLoadBalancerPendingMessage function.
+func TestFormatLoadBalancerPendingMessage(t *testing.T) {
+	testCases := []struct {
+		name        string
+		annotations map[string]string
+		want        string
+	}{
+		{
+			name: "public load balancer",
+			want: "the public load balancer IP of service work/app is not yet provisioned",
+		},
+		{
+			name:        "internal load balancer",
+			annotations: map[string]string{objectmeta.ServiceAnnotationAzureLoadBalancerInternal: "true"},
+			want:        "the internal load balancer IP of service work/app is not yet provisioned",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			svc := &corev1.Service{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace:   memberUserNS,
+					Name:        svcName,
+					Annotations: tc.annotations,
+				},
+			}
+			if got := formatLoadBalancerPendingMessage(svc); got != tc.want {
+				t.Errorf("formatLoadBalancerPendingMessage(), got %q, want %q", got, tc.want)
+			}
+		})
+	}
+}
+
+// TestUpdateLoadBalancerPendingCondition tests the *Reconciler.updateLoadBalancerPendingCondition method.
+func TestUpdateLoadBalancerPendingCondition(t *testing.T) {
+	svc := &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: memberUserNS,
+			Name:      svcName,
+		},
+		Spec: corev1.ServiceSpec{
+			Type: corev1.ServiceTypeLoadBalancer,
+		},
+	}
+
+	testCases := []struct {
+		name      string
+		conds     []metav1.Condition
+		lbPending bool
+		wantConds []metav1.Condition
+	}{
+		{
+			name: "should not add the condition when the load balancer IP has never been pending",
+		},
+		{
+			name: "should add the condition when the load balancer IP is pending",
+			conds: []metav1.Condition{
+				serviceExportValidCondition(memberUserNS, svcName),
+			},
+			lbPending: true,
+			wantConds: []metav1.Condition{
+				serviceExportValidCondition(memberUserNS, svcName),
+				serviceExportLoadBalancerPendingCondition(memberUserNS, svcName),
+			},
+		},
+		{
+			name: "should keep the condition when the load balancer IP is still pending",
+			conds: []metav1.Condition{
+				serviceExportLoadBalancerPendingCondition(memberUserNS, svcName),
+			},
+			lbPending: true,
+			wantConds: []metav1.Condition{
+				serviceExportLoadBalancerPendingCondition(memberUserNS, svcName),
+			},
+		},
+		{
+			name: "should clear the condition when the load balancer IP is provisioned",
+			conds: []metav1.Condition{
+				serviceExportValidCondition(memberUserNS, svcName),
+				serviceExportLoadBalancerPendingCondition(memberUserNS, svcName),
+			},
+			wantConds: []metav1.Condition{
+				serviceExportValidCondition(memberUserNS, svcName),
+				serviceExportLoadBalancerProvisionedCondition(memberUserNS, svcName),
+			},
+		},
+	}
+
+	ctx := context.Background()
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			svcExport := &fleetnetv1alpha1.ServiceExport{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: memberUserNS,
+					Name:      svcName,
+				},
+				Status: fleetnetv1alpha1.ServiceExportStatus{
+					Conditions: tc.conds,
+				},
+			}
+			fakeMemberClient := fake.NewClientBuilder().
+				WithScheme(scheme.Scheme).
+				WithObjects(svcExport).
+				WithStatusSubresource(svcExport).
+				Build()
+			reconciler := Reconciler{
+				MemberClient: fakeMemberClient,
+				HubClient:    fake.NewClientBuilder().Build(),
+				HubNamespace: hubNSForMember,
+				Recorder:     record.NewFakeRecorder(10),
 			}
 
-			var updatedSvcExport = &fleetnetv1alpha1.ServiceExport{}
-			updatedSvcExportKey := types.NamespacedName{Namespace: memberUserNS, Name: svcName}
-			if err := fakeMemberClient.Get(ctx, updatedSvcExportKey, updatedSvcExport); err != nil {
-				t.Fatalf("svc export Get(%+v), got %v, want no error", updatedSvcExportKey, err)
+			if err := reconciler.updateLoadBalancerPendingCondition(ctx, svcExport, svc, tc.lbPending); err != nil {
+				t.Fatalf("updateLoadBalancerPendingCondition(), got %v, want no error", err)
 			}
 
-			if !cmp.Equal(updatedSvcExport.ObjectMeta.Finalizers, tc.want) {
-				t.Fatalf("svc export finalizer, got %+v, want %+v", updatedSvcExport.ObjectMeta.Finalizers, tc.want)
+			updatedSvcExport := &fleetnetv1alpha1.ServiceExport{}
+			svcExportKey := types.NamespacedName{Namespace: svcExport.Namespace, Name: svcExport.Name}
+			if err := fakeMemberClient.Get(ctx, svcExportKey, updatedSvcExport); err != nil {
+				t.Fatalf("svc export Get(%+v), got %v, want no error", svcExportKey, err)
+			}
+			if diff := cmp.Diff(tc.wantConds, updatedSvcExport.Status.Conditions, ignoredCondFields, cmpopts.EquateEmpty()); diff != "" {
+				t.Fatalf("svc export conditions (-want, +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+// TestUpdatePublicIPValidCondition tests the *Reconciler.updatePublicIPValidCondition method.
+func TestUpdatePublicIPValidCondition(t *testing.T) {
+	validCond := metav1.Condition{
+		Type:    string(fleetnetv1alpha1.ServiceExportPublicIPValid),
+		Status:  metav1.ConditionTrue,
+		Reason:  svcExportPublicIPValidCondReason,
+		Message: fmt.Sprintf("public IP address %s is valid", gatewayPublicIPResourceID),
+	}
+	invalidErr := fmt.Errorf("%w: DNS label is not configured to the public IP address %s", errInvalidPublicIP, gatewayPublicIPResourceID)
+	invalidCond := metav1.Condition{
+		Type:    string(fleetnetv1alpha1.ServiceExportPublicIPValid),
+		Status:  metav1.ConditionFalse,
+		Reason:  svcExportPublicIPInvalidCondReason,
+		Message: invalidErr.Error(),
+	}
+
+	testCases := []struct {
+		name               string
+		conds              []metav1.Condition
+		isPublicIPExplicit bool
+		publicIPErr        error
+		wantConds          []metav1.Condition
+		wantEvents         int
+	}{
+		{
+			name: "should not add the condition when no public IP is specified",
+		},
+		{
+			name:               "should add the condition when the public IP is valid",
+			isPublicIPExplicit: true,
+			wantConds:          []metav1.Condition{validCond},
+		},
+		{
+			name:               "should report the invalid public IP",
+			conds:              []metav1.Condition{validCond},
+			isPublicIPExplicit: true,
+			publicIPErr:        invalidErr,
+			wantConds:          []metav1.Condition{invalidCond},
+			wantEvents:         1,
+		},
+		{
+			name:               "should not emit the event again when the public IP is still invalid",
+			conds:              []metav1.Condition{invalidCond},
+			isPublicIPExplicit: true,
+			publicIPErr:        invalidErr,
+			wantConds:          []metav1.Condition{invalidCond},
+		},
+		{
+			name:  "should remove the condition when the public IP is no longer specified",
+			conds: []metav1.Condition{serviceExportValidCondition(memberUserNS, svcName), invalidCond},
+			wantConds: []metav1.Condition{
+				serviceExportValidCondition(memberUserNS, svcName),
+			},
+		},
+	}
+
+	ctx := context.Background()
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			svcExport := &fleetnetv1alpha1.ServiceExport{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: memberUserNS,
+					Name:      svcName,
+				},
+				Status: fleetnetv1alpha1.ServiceExportStatus{
+					Conditions: tc.conds,
+				},
+			}
+			fakeMemberClient := fake.NewClientBuilder().
+				WithScheme(scheme.Scheme).
+				WithObjects(svcExport).
+				WithStatusSubresource(svcExport).
+				Build()
+			recorder := record.NewFakeRecorder(10)
+			reconciler := Reconciler{
+				MemberClient: fakeMemberClient,
+				HubClient:    fake.NewClientBuilder().Build(),
+				HubNamespace: hubNSForMember,
+				Recorder:     recorder,
+			}
+
+			if err := reconciler.updatePublicIPValidCondition(ctx, svcExport, tc.isPublicIPExplicit, gatewayPublicIPResourceID, tc.publicIPErr); err != nil {
+				t.Fatalf("updatePublicIPValidCondition(), got %v, want no error", err)
+			}
+
+			updatedSvcExport := &fleetnetv1alpha1.ServiceExport{}
+			svcExportKey := types.NamespacedName{Namespace: svcExport.Namespace, Name: svcExport.Name}
+			if err := fakeMemberClient.Get(ctx, svcExportKey, updatedSvcExport); err != nil {
+				t.Fatalf("svc export Get(%+v), got %v, want no error", svcExportKey, err)
+			}
+			if diff := cmp.Diff(tc.wantConds, updatedSvcExport.Status.Conditions, ignoredCondFields, cmpopts.EquateEmpty()); diff != "" {
+				t.Fatalf("svc export conditions (-want, +got):\n%s", diff)
+			}
+			if got := len(recorder.Events); got != tc.wantEvents {
+				t.Errorf("updatePublicIPValidCondition() emitted %d events, want %d", got, tc.wantEvents)
+			}
+		})
+	}
+}
+
+// TestUpdateDNSLabelConfiguredCondition tests the *Reconciler.updateDNSLabelConfiguredCondition method.
+func TestUpdateDNSLabelConfiguredCondition(t *testing.T) {
+	pipID := "/subscriptions/sub1/resourceGroups/pip-rg/providers/Microsoft.Network/publicIPAddresses/pip"
+	forbiddenErr := fmt.Errorf("%w %s: no permission to update the public IP address: AuthorizationFailed (status code 403)", errDNSLabelNotSet, pipID)
+	notSetCond := metav1.Condition{
+		Type:    string(fleetnetv1alpha1.ServiceExportDNSLabelConfigured),
+		Status:  metav1.ConditionFalse,
+		Reason:  svcExportDNSLabelNotSetCondReason,
+		Message: forbiddenErr.Error(),
+	}
+
+	testCases := []struct {
+		name        string
+		conds       []metav1.Condition
+		dnsLabelErr error
+		wantConds   []metav1.Condition
+		wantEvents  int
+	}{
+		{
+			name: "should not add the condition when the dns label is configured",
+		},
+		{
+			name:        "should report the dns label which cannot be set",
+			conds:       []metav1.Condition{serviceExportValidCondition(memberUserNS, svcName)},
+			dnsLabelErr: forbiddenErr,
+			wantConds: []metav1.Condition{
+				serviceExportValidCondition(memberUserNS, svcName),
+				notSetCond,
+			},
+			wantEvents: 1,
+		},
+		{
+			name:        "should not emit the event again when the dns label still cannot be set",
+			conds:       []metav1.Condition{notSetCond},
+			dnsLabelErr: forbiddenErr,
+			wantConds:   []metav1.Condition{notSetCond},
+		},
+		{
+			name:  "should remove the condition once the dns label is configured",
+			conds: []metav1.Condition{serviceExportValidCondition(memberUserNS, svcName), notSetCond},
+			wantConds: []metav1.Condition{
+				serviceExportValidCondition(memberUserNS, svcName),
+			},
+		},
+	}
+
+	ctx := context.Background()
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			svcExport := &fleetnetv1alpha1.ServiceExport{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: memberUserNS,
+					Name:      svcName,
+				},
+				Status: fleetnetv1alpha1.ServiceExportStatus{
+					Conditions: tc.conds,
+				},
+			}
+			fakeMemberClient := fake.NewClientBuilder().
+				WithScheme(scheme.Scheme).
+				WithObjects(svcExport).
+				WithStatusSubresource(svcExport).
+				Build()
+			recorder := record.NewFakeRecorder(10)
+			reconciler := Reconciler{
+				MemberClient: fakeMemberClient,
+				HubClient:    fake.NewClientBuilder().Build(),
+				HubNamespace: hubNSForMember,
+				Recorder:     recorder,
+			}
+
+			if err := reconciler.updateDNSLabelConfiguredCondition(ctx, svcExport, tc.dnsLabelErr); err != nil {
+				t.Fatalf("updateDNSLabelConfiguredCondition(), got %v, want no error", err)
+			}
+
+			updatedSvcExport := &fleetnetv1alpha1.ServiceExport{}
+			svcExportKey := types.NamespacedName{Namespace: svcExport.Namespace, Name: svcExport.Name}
+			if err := fakeMemberClient.Get(ctx, svcExportKey, updatedSvcExport); err != nil {
+				t.Fatalf("svc export Get(%+v), got %v, want no error", svcExportKey, err)
+			}
+			if diff := cmp.Diff(tc.wantConds, updatedSvcExport.Status.Conditions, ignoredCondFields, cmpopts.EquateEmpty()); diff != "" {
+				t.Fatalf("svc export conditions (-want, +got):\n%s", diff)
+			}
+			if got := len(recorder.Events); got != tc.wantEvents {
+				t.Errorf("updateDNSLabelConfiguredCondition() emitted %d events, want %d", got, tc.wantEvents)
+			}
+		})
+	}
+}
+
+// TestEndpointSliceToServiceExport tests the endpointSliceToServiceExport function.
+func TestEndpointSliceToServiceExport(t *testing.T) {
+	testCases := []struct {
+		name   string
+		labels map[string]string
+		want   []reconcile.Request
+	}{
+		{
+			name:   "endpointSlice of a service",
+			labels: map[string]string{discoveryv1.LabelServiceName: svcName},
+			want: []reconcile.Request{
+				{NamespacedName: types.NamespacedName{Namespace: memberUserNS, Name: svcName}},
+			},
+		},
+		{
+			name: "endpointSlice without service name label",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			endpointSlice := &discoveryv1.EndpointSlice{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: memberUserNS,
+					Name:      "app-1",
+					Labels:    tc.labels,
+				},
+			}
+			got := endpointSliceToServiceExport(context.Background(), endpointSlice)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("endpointSliceToServiceExport() mismatch (-want, +got):\n%s", diff)
 			}
 		})
 	}
@@ -756,6 +1895,248 @@ func TestUnexportService(t *testing.T) {
 	}
 }
 
+// TestReconcileDeletionProtection tests that the *Reconciler.Reconcile method delays the unexport of a deleted
+// ServiceExport with deletion protection enabled while the Service is still imported.
+func TestReconcileDeletionProtection(t *testing.T) {
+	internalSvcExportName := fmt.Sprintf("%s-%s", memberUserNS, svcName)
+	protectedAnnotations := map[string]string{objectmeta.ServiceExportAnnotationDeletionProtection: "true"}
+	gracePeriod := 5 * time.Minute
+
+	testCases := []struct {
+		name                string
+		annotations         map[string]string
+		activeImporterCount int32
+		gracePeriod         time.Duration
+		deletedFor          time.Duration
+		wantDelayed         bool
+	}{
+		{
+			name:                "should delay the unexport of an imported svc",
+			annotations:         protectedAnnotations,
+			activeImporterCount: 2,
+			gracePeriod:         gracePeriod,
+			deletedFor:          time.Minute,
+			wantDelayed:         true,
+		},
+		{
+			name:                "should unexport an imported svc after the grace period",
+			annotations:         protectedAnnotations,
+			activeImporterCount: 2,
+			gracePeriod:         gracePeriod,
+			deletedFor:          10 * time.Minute,
+		},
+		{
+			name:        "should unexport a svc with no importers",
+			annotations: protectedAnnotations,
+			gracePeriod: gracePeriod,
+			deletedFor:  time.Minute,
+		},
+		{
+			name:                "should unexport an imported svc without deletion protection",
+			activeImporterCount: 2,
+			gracePeriod:         gracePeriod,
+			deletedFor:          time.Minute,
+		},
+		{
+			name:                "should unexport an imported svc when deletion protection is disabled",
+			annotations:         protectedAnnotations,
+			activeImporterCount: 2,
+			deletedFor:          time.Minute,
+		},
+	}
+
+	ctx := context.Background()
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			deletionTimestamp := metav1.NewTime(time.Now().Add(-tc.deletedFor).Truncate(time.Second))
+			svcExport := &fleetnetv1alpha1.ServiceExport{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace:         memberUserNS,
+					Name:              svcName,
+					Annotations:       tc.annotations,
+					Finalizers:        []string{svcExportCleanupFinalizer},
+					DeletionTimestamp: &deletionTimestamp,
+				},
+				Status: fleetnetv1alpha1.ServiceExportStatus{
+					ActiveImporterCount: tc.activeImporterCount,
+				},
+			}
+			internalSvcExport := &fleetnetv1alpha1.InternalServiceExport{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: hubNSForMember,
+					Name:      internalSvcExportName,
+				},
+			}
+			fakeMemberClient := fake.NewClientBuilder().
+				WithScheme(scheme.Scheme).
+				WithObjects(svcExport).
+				WithStatusSubresource(svcExport).
+				Build()
+			fakeHubClient := fake.NewClientBuilder().
+				WithScheme(scheme.Scheme).
+				WithObjects(internalSvcExport).
+				Build()
+			recorder := record.NewFakeRecorder(10)
+			reconciler := Reconciler{
+				MemberClient:                  fakeMemberClient,
+				HubClient:                     fakeHubClient,
+				HubNamespace:                  hubNSForMember,
+				Recorder:                      recorder,
+				DeletionProtectionGracePeriod: tc.gracePeriod,
+			}
+
+			svcExportKey := types.NamespacedName{Namespace: memberUserNS, Name: svcName}
+			res, err := reconciler.Reconcile(ctx, ctrl.Request{NamespacedName: svcExportKey})
+			if err != nil {
+				t.Fatalf("Reconcile() = %v, want no error", err)
+			}
+
+			internalSvcExportKey := types.NamespacedName{Namespace: hubNSForMember, Name: internalSvcExportName}
+			err = fakeHubClient.Get(ctx, internalSvcExportKey, &fleetnetv1alpha1.InternalServiceExport{})
+			if !tc.wantDelayed {
+				if !cmp.Equal(res, ctrl.Result{}) {
+					t.Fatalf("Reconcile() = %+v, want %+v", res, ctrl.Result{})
+				}
+				if !apierrors.IsNotFound(err) {
+					t.Fatalf("internalSvcExport Get(%+v), got error %v, want not found error", internalSvcExportKey, err)
+				}
+				return
+			}
+
+			wantRemaining := gracePeriod - tc.deletedFor
+			if res.RequeueAfter <= 0 || res.RequeueAfter > wantRemaining {
+				t.Fatalf("Reconcile() requeueAfter = %v, want in (0, %v]", res.RequeueAfter, wantRemaining)
+			}
+			if err != nil {
+				t.Fatalf("internalSvcExport Get(%+v), got %v, want no error", internalSvcExportKey, err)
+			}
+			updatedSvcExport := &fleetnetv1alpha1.ServiceExport{}
+			if err := fakeMemberClient.Get(ctx, svcExportKey, updatedSvcExport); err != nil {
+				t.Fatalf("svc export Get(%+v), got %v, want no error", svcExportKey, err)
+			}
+			if !cmp.Equal(updatedSvcExport.Finalizers, []string{svcExportCleanupFinalizer}) {
+				t.Fatalf("svc export finalizers, got %+v, want %+v", updatedSvcExport.Finalizers, []string{svcExportCleanupFinalizer})
+			}
+			select {
+			case event := <-recorder.Events:
+				if !strings.HasPrefix(event, "Warning UnexportDelayed") || !strings.Contains(event, "imported by 2 member cluster(s)") {
+					t.Fatalf("event, got %q, want an UnexportDelayed warning with the importer count", event)
+				}
+			default:
+				t.Fatalf("no event is emitted, want an UnexportDelayed warning")
+			}
+		})
+	}
+}
+
+// TestReconcileTerminatingNamespace tests that the Reconcile method unexports the Services of a namespace which is
+// being deleted at once, without marking the ServiceExports as invalid.
+func TestReconcileTerminatingNamespace(t *testing.T) {
+	deletionTimestamp := metav1.Now()
+	ns := &corev1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:              memberUserNS,
+			DeletionTimestamp: &deletionTimestamp,
+			// Note that fake client will reject object that is deleted (has the deletion
+			// timestamp) but does not have finalizers.
+			Finalizers: []string{"kubernetes"},
+		},
+	}
+	newSvcExport := func(name string, finalizers ...string) *fleetnetv1alpha1.ServiceExport {
+		return &fleetnetv1alpha1.ServiceExport{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace:  memberUserNS,
+				Name:       name,
+				Finalizers: finalizers,
+			},
+			Status: fleetnetv1alpha1.ServiceExportStatus{
+				Conditions: []metav1.Condition{
+					serviceExportValidCondition(memberUserNS, name),
+				},
+			},
+		}
+	}
+	// The Services of the ServiceExports have been deleted already.
+	svcExport := newSvcExport(svcName, svcExportCleanupFinalizer)
+	otherSvcExport := newSvcExport("web", svcExportCleanupFinalizer)
+	protectedSvcExport := newSvcExport("db", svcExportCleanupFinalizer)
+	protectedSvcExport.Annotations = map[string]string{objectmeta.ServiceExportAnnotationDeletionProtection: "true"}
+	protectedSvcExport.Status.ActiveImporterCount = 2
+	unexportedSvcExport := newSvcExport("cache")
+	svcExports := []*fleetnetv1alpha1.ServiceExport{svcExport, otherSvcExport, protectedSvcExport, unexportedSvcExport}
+
+	fakeMemberClient := fake.NewClientBuilder().
+		WithScheme(scheme.Scheme).
+		WithObjects(ns, svcExport, otherSvcExport, protectedSvcExport, unexportedSvcExport).
+		WithStatusSubresource(svcExport, otherSvcExport, protectedSvcExport, unexportedSvcExport).
+		Build()
+	hubClientBuilder := fake.NewClientBuilder().WithScheme(scheme.Scheme)
+	for _, export := range []*fleetnetv1alpha1.ServiceExport{svcExport, otherSvcExport, protectedSvcExport} {
+		hubClientBuilder = hubClientBuilder.WithObjects(&fleetnetv1alpha1.InternalServiceExport{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: hubNSForMember,
+				Name:      formatInternalServiceExportName(export),
+			},
+		})
+	}
+	fakeHubClient := hubClientBuilder.Build()
+	recorder := record.NewFakeRecorder(10)
+	reconciler := Reconciler{
+		MemberClient:                  fakeMemberClient,
+		HubClient:                     fakeHubClient,
+		HubNamespace:                  hubNSForMember,
+		Recorder:                      recorder,
+		RequiredNamespaceLabels:       requiredNamespaceLabelsForTest,
+		DeletionProtectionGracePeriod: 5 * time.Minute,
+	}
+
+	ctx := context.Background()
+	svcExportKey := types.NamespacedName{Namespace: memberUserNS, Name: svcName}
+	res, err := reconciler.Reconcile(ctx, ctrl.Request{NamespacedName: svcExportKey})
+	if err != nil {
+		t.Fatalf("Reconcile() = %v, want no error", err)
+	}
+	if !cmp.Equal(res, ctrl.Result{}) {
+		t.Fatalf("Reconcile() = %+v, want %+v", res, ctrl.Result{})
+	}
+
+	wantExported := map[string]bool{protectedSvcExport.Name: true}
+	for _, export := range svcExports {
+		key := types.NamespacedName{Namespace: memberUserNS, Name: export.Name}
+		updatedSvcExport := &fleetnetv1alpha1.ServiceExport{}
+		if err := fakeMemberClient.Get(ctx, key, updatedSvcExport); err != nil {
+			t.Fatalf("svc export Get(%+v), got %v, want no error", key, err)
+		}
+		if diff := cmp.Diff(updatedSvcExport.Status.Conditions, export.Status.Conditions, ignoredCondFields); diff != "" {
+			t.Errorf("svc export %s conditions (-got, +want): %s", export.Name, diff)
+		}
+
+		internalSvcExportKey := types.NamespacedName{Namespace: hubNSForMember, Name: formatInternalServiceExportName(export)}
+		err := fakeHubClient.Get(ctx, internalSvcExportKey, &fleetnetv1alpha1.InternalServiceExport{})
+		if wantExported[export.Name] {
+			if err != nil {
+				t.Errorf("internalSvcExport Get(%+v), got %v, want no error", internalSvcExportKey, err)
+			}
+			if !cmp.Equal(updatedSvcExport.Finalizers, []string{svcExportCleanupFinalizer}) {
+				t.Errorf("svc export %s finalizers, got %+v, want %+v", export.Name, updatedSvcExport.Finalizers, []string{svcExportCleanupFinalizer})
+			}
+			continue
+		}
+		if !apierrors.IsNotFound(err) {
+			t.Errorf("internalSvcExport Get(%+v), got %v, want not found error", internalSvcExportKey, err)
+		}
+		if len(updatedSvcExport.Finalizers) != 0 {
+			t.Errorf("svc export %s finalizers, got %+v, want none", export.Name, updatedSvcExport.Finalizers)
+		}
+	}
+	select {
+	case event := <-recorder.Events:
+		t.Errorf("event %q is emitted, want none", event)
+	default:
+	}
+}
+
 // TestCollectAndVerifyLastSeenResourceVersionAndTimestamp tests the
 // *Reconciler.collectAndVerifyLastSeenResourceVersionAndTimestamp method.
 func TestCollectAndVerifyLastSeenResourceVersionAndTimestamp(t *testing.T) {
@@ -990,492 +2371,3 @@ func TestAnnotateLastSeenResourceVersionAndTimestamp(t *testing.T) {
 		})
 	}
 }
-
-func TestSetAzureRelatedInformation(t *testing.T) {
-	tests := []struct {
-		name                           string
-		service                        *corev1.Service
-		publicIPAddressListResponse    []*armnetwork.PublicIPAddress
-		publicIPAddressListResponseErr error
-		want                           *fleetnetv1alpha1.InternalServiceExport
-		wantErr                        bool
-	}{
-		{
-			name: "load balancer type with public ip and dns is assigned to pip",
-			service: &corev1.Service{
-				ObjectMeta: metav1.ObjectMeta{
-					UID: "uid",
-					Annotations: map[string]string{
-						objectmeta.ServiceAnnotationLoadBalancerResourceGroup: "   ",
-						objectmeta.ServiceAnnotationAzureLoadBalancerInternal: "True", // case sensitive
-					},
-				},
-				Spec: corev1.ServiceSpec{
-					Type: corev1.ServiceTypeLoadBalancer,
-				},
-				Status: corev1.ServiceStatus{
-					LoadBalancer: corev1.LoadBalancerStatus{
-						Ingress: []corev1.LoadBalancerIngress{
-							{
-								IP: "1.2.3.4",
-							},
-						},
-					},
-				},
-			},
-			publicIPAddressListResponse: []*armnetwork.PublicIPAddress{
-				{
-					Properties: &armnetwork.PublicIPAddressPropertiesFormat{
-						DNSSettings: &armnetwork.PublicIPAddressDNSSettings{
-							DomainNameLabel: ptr.To("dnsLabel"),
-						},
-						IPAddress: ptr.To("1.2.3.4"),
-					},
-					ID: ptr.To("/subscriptions/sub1/resourceGroups/rg/providers/Microsoft.Network/publicIPAddresses/pip"),
-				},
-				{
-					Properties: &armnetwork.PublicIPAddressPropertiesFormat{
-						IPAddress: ptr.To("1.2.5.6"),
-					},
-				},
-			},
-			want: &fleetnetv1alpha1.InternalServiceExport{
-				Spec: fleetnetv1alpha1.InternalServiceExportSpec{
-					Type:                   corev1.ServiceTypeLoadBalancer,
-					IsDNSLabelConfigured:   true,
-					IsInternalLoadBalancer: false,
-					PublicIPResourceID:     ptr.To("/subscriptions/sub1/resourceGroups/rg/providers/Microsoft.Network/publicIPAddresses/pip"),
-				},
-			},
-		},
-		{
-			name: "load balancer type with public ip and dns label is not set and dns is not assigned to pip",
-			service: &corev1.Service{
-				ObjectMeta: metav1.ObjectMeta{
-					UID: "uid",
-				},
-				Spec: corev1.ServiceSpec{
-					Type: corev1.ServiceTypeLoadBalancer,
-				},
-				Status: corev1.ServiceStatus{
-					LoadBalancer: corev1.LoadBalancerStatus{
-						Ingress: []corev1.LoadBalancerIngress{
-							{
-								IP: "1.2.3.4",
-							},
-						},
-					},
-				},
-			},
-			publicIPAddressListResponse: []*armnetwork.PublicIPAddress{
-				{
-					Properties: &armnetwork.PublicIPAddressPropertiesFormat{
-						DNSSettings: &armnetwork.PublicIPAddressDNSSettings{},
-						IPAddress:   ptr.To("1.2.3.4"),
-					},
-					ID: ptr.To("/subscriptions/sub1/resourceGroups/rg/providers/Microsoft.Network/publicIPAddresses/pip"),
-				},
-				{
-					Properties: &armnetwork.PublicIPAddressPropertiesFormat{},
-				},
-			},
-			want: &fleetnetv1alpha1.InternalServiceExport{
-				Spec: fleetnetv1alpha1.InternalServiceExportSpec{
-					Type:               corev1.ServiceTypeLoadBalancer,
-					PublicIPResourceID: ptr.To("/subscriptions/sub1/resourceGroups/rg/providers/Microsoft.Network/publicIPAddresses/pip"),
-				},
-			},
-		},
-		{
-			name: "load balancer type with public ip and dns label and dns label is not assigned in pip",
-			service: &corev1.Service{
-				ObjectMeta: metav1.ObjectMeta{
-					UID: "uid",
-					Annotations: map[string]string{
-						objectmeta.ServiceAnnotationLoadBalancerResourceGroup: "   ",
-						objectmeta.ServiceAnnotationAzureLoadBalancerInternal: "True", // case sensitive
-						objectmeta.ServiceAnnotationAzureDNSLabelName:         "dnsLabel",
-					},
-				},
-				Spec: corev1.ServiceSpec{
-					Type: corev1.ServiceTypeLoadBalancer,
-				},
-				Status: corev1.ServiceStatus{
-					LoadBalancer: corev1.LoadBalancerStatus{
-						Ingress: []corev1.LoadBalancerIngress{
-							{
-								IP: "1.2.3.4",
-							},
-						},
-					},
-				},
-			},
-			publicIPAddressListResponse: []*armnetwork.PublicIPAddress{
-				{
-					Properties: &armnetwork.PublicIPAddressPropertiesFormat{
-						DNSSettings: &armnetwork.PublicIPAddressDNSSettings{},
-						IPAddress:   ptr.To("1.2.3.4"),
-					},
-					ID: ptr.To("/subscriptions/sub1/resourceGroups/rg/providers/Microsoft.Network/publicIPAddresses/pip"),
-				},
-				{
-					Properties: &armnetwork.PublicIPAddressPropertiesFormat{
-						IPAddress: ptr.To("1.2.5.6"),
-					},
-				},
-			},
-			wantErr: true,
-		},
-		{
-			name: "load balancer type with public ip and dns label and dns is assigned to pip",
-			service: &corev1.Service{
-				ObjectMeta: metav1.ObjectMeta{
-					UID: "uid",
-					Annotations: map[string]string{
-						objectmeta.ServiceAnnotationLoadBalancerResourceGroup: "   ",
-						objectmeta.ServiceAnnotationAzureLoadBalancerInternal: "True", // case sensitive
-						objectmeta.ServiceAnnotationAzureDNSLabelName:         "dnsLabel",
-					},
-				},
-				Spec: corev1.ServiceSpec{
-					Type: corev1.ServiceTypeLoadBalancer,
-				},
-				Status: corev1.ServiceStatus{
-					LoadBalancer: corev1.LoadBalancerStatus{
-						Ingress: []corev1.LoadBalancerIngress{
-							{
-								IP: "1.2.3.4",
-							},
-						},
-					},
-				},
-			},
-			publicIPAddressListResponse: []*armnetwork.PublicIPAddress{
-				{
-					Properties: &armnetwork.PublicIPAddressPropertiesFormat{
-						DNSSettings: &armnetwork.PublicIPAddressDNSSettings{
-							DomainNameLabel: ptr.To("dnsLabel"),
-						},
-						IPAddress: ptr.To("1.2.3.4"),
-					},
-					ID: ptr.To("/subscriptions/sub1/resourceGroups/rg/providers/Microsoft.Network/publicIPAddresses/pip"),
-				},
-				{
-					Properties: &armnetwork.PublicIPAddressPropertiesFormat{
-						IPAddress: ptr.To("1.2.5.6"),
-					},
-				},
-			},
-			want: &fleetnetv1alpha1.InternalServiceExport{
-				Spec: fleetnetv1alpha1.InternalServiceExportSpec{
-					Type:                   corev1.ServiceTypeLoadBalancer,
-					IsDNSLabelConfigured:   true,
-					IsInternalLoadBalancer: false,
-					PublicIPResourceID:     ptr.To("/subscriptions/sub1/resourceGroups/rg/providers/Microsoft.Network/publicIPAddresses/pip"),
-				},
-			},
-		},
-		{
-			name: "load balancer type with public ip and empty dns label and dns is assigned to pip",
-			service: &corev1.Service{
-				ObjectMeta: metav1.ObjectMeta{
-					UID: "uid",
-					Annotations: map[string]string{
-						objectmeta.ServiceAnnotationLoadBalancerResourceGroup: "   ",
-						objectmeta.ServiceAnnotationAzureLoadBalancerInternal: "True", // case sensitive
-						objectmeta.ServiceAnnotationAzureDNSLabelName:         "",
-					},
-				},
-				Spec: corev1.ServiceSpec{
-					Type: corev1.ServiceTypeLoadBalancer,
-				},
-				Status: corev1.ServiceStatus{
-					LoadBalancer: corev1.LoadBalancerStatus{
-						Ingress: []corev1.LoadBalancerIngress{
-							{
-								IP: "1.2.3.4",
-							},
-						},
-					},
-				},
-			},
-			publicIPAddressListResponse: []*armnetwork.PublicIPAddress{
-				{
-					Properties: &armnetwork.PublicIPAddressPropertiesFormat{
-						DNSSettings: &armnetwork.PublicIPAddressDNSSettings{
-							DomainNameLabel: ptr.To("dnsLabel"),
-						},
-						IPAddress: ptr.To("1.2.3.4"),
-					},
-					ID: ptr.To("/subscriptions/sub1/resourceGroups/rg/providers/Microsoft.Network/publicIPAddresses/pip"),
-				},
-				{
-					Properties: &armnetwork.PublicIPAddressPropertiesFormat{
-						IPAddress: ptr.To("1.2.5.6"),
-					},
-				},
-			},
-			want: &fleetnetv1alpha1.InternalServiceExport{
-				Spec: fleetnetv1alpha1.InternalServiceExportSpec{
-					Type:                   corev1.ServiceTypeLoadBalancer,
-					IsDNSLabelConfigured:   false,
-					IsInternalLoadBalancer: false,
-					PublicIPResourceID:     ptr.To("/subscriptions/sub1/resourceGroups/rg/providers/Microsoft.Network/publicIPAddresses/pip"),
-				},
-			},
-		},
-		{
-			name: "load balancer type with public ip and empty dns label and dns is not assigned to pip",
-			service: &corev1.Service{
-				ObjectMeta: metav1.ObjectMeta{
-					UID: "uid",
-					Annotations: map[string]string{
-						objectmeta.ServiceAnnotationLoadBalancerResourceGroup: "   ",
-						objectmeta.ServiceAnnotationAzureLoadBalancerInternal: "True", // case sensitive
-						objectmeta.ServiceAnnotationAzureDNSLabelName:         "",
-					},
-				},
-				Spec: corev1.ServiceSpec{
-					Type: corev1.ServiceTypeLoadBalancer,
-				},
-				Status: corev1.ServiceStatus{
-					LoadBalancer: corev1.LoadBalancerStatus{
-						Ingress: []corev1.LoadBalancerIngress{
-							{
-								IP: "1.2.3.4",
-							},
-						},
-					},
-				},
-			},
-			publicIPAddressListResponse: []*armnetwork.PublicIPAddress{
-				{
-					Properties: &armnetwork.PublicIPAddressPropertiesFormat{
-						IPAddress: ptr.To("1.2.3.4"),
-					},
-					ID: ptr.To("/subscriptions/sub1/resourceGroups/rg/providers/Microsoft.Network/publicIPAddresses/pip"),
-				},
-				{
-					Properties: &armnetwork.PublicIPAddressPropertiesFormat{
-						IPAddress: ptr.To("1.2.5.6"),
-					},
-				},
-			},
-			want: &fleetnetv1alpha1.InternalServiceExport{
-				Spec: fleetnetv1alpha1.InternalServiceExportSpec{
-					Type:                   corev1.ServiceTypeLoadBalancer,
-					IsDNSLabelConfigured:   false,
-					IsInternalLoadBalancer: false,
-					PublicIPResourceID:     ptr.To("/subscriptions/sub1/resourceGroups/rg/providers/Microsoft.Network/publicIPAddresses/pip"),
-				},
-			},
-		},
-		{
-			name: "load balancer type with internal ip",
-			service: &corev1.Service{
-				ObjectMeta: metav1.ObjectMeta{
-					UID: "uid",
-					Annotations: map[string]string{
-						objectmeta.ServiceAnnotationAzureLoadBalancerInternal: "true",
-					},
-				},
-				Spec: corev1.ServiceSpec{
-					Type: corev1.ServiceTypeLoadBalancer,
-				},
-			},
-			want: &fleetnetv1alpha1.InternalServiceExport{
-				Spec: fleetnetv1alpha1.InternalServiceExportSpec{
-					Type:                   corev1.ServiceTypeLoadBalancer,
-					IsInternalLoadBalancer: true,
-				},
-			},
-		},
-		{
-			name: "NodePort type service",
-			service: &corev1.Service{
-				ObjectMeta: metav1.ObjectMeta{
-					UID: "uid",
-					Annotations: map[string]string{
-						objectmeta.ServiceAnnotationAzureLoadBalancerInternal: "true",
-					},
-				},
-				Spec: corev1.ServiceSpec{
-					Type: corev1.ServiceTypeNodePort,
-				},
-			},
-			want: &fleetnetv1alpha1.InternalServiceExport{
-				Spec: fleetnetv1alpha1.InternalServiceExportSpec{
-					Type: corev1.ServiceTypeNodePort,
-				},
-			},
-		},
-		{
-			name: "error when getting public ip resource",
-			service: &corev1.Service{
-				ObjectMeta: metav1.ObjectMeta{
-					UID: "uid",
-				},
-				Spec: corev1.ServiceSpec{
-					Type: corev1.ServiceTypeLoadBalancer,
-				},
-				Status: corev1.ServiceStatus{
-					LoadBalancer: corev1.LoadBalancerStatus{
-						Ingress: []corev1.LoadBalancerIngress{
-							{
-								IP: "1.2.3.4",
-							},
-						},
-					},
-				},
-			},
-			publicIPAddressListResponseErr: errors.New("error"),
-			wantErr:                        true,
-		},
-		{
-			name: "stale service ingress ip",
-			service: &corev1.Service{
-				ObjectMeta: metav1.ObjectMeta{
-					UID: "uid",
-				},
-				Spec: corev1.ServiceSpec{
-					Type: corev1.ServiceTypeLoadBalancer,
-				},
-				Status: corev1.ServiceStatus{
-					LoadBalancer: corev1.LoadBalancerStatus{
-						Ingress: []corev1.LoadBalancerIngress{
-							{
-								IP: "1.2.3.4",
-							},
-						},
-					},
-				},
-			},
-			publicIPAddressListResponse: []*armnetwork.PublicIPAddress{
-				{
-					Properties: &armnetwork.PublicIPAddressPropertiesFormat{
-						DNSSettings: &armnetwork.PublicIPAddressDNSSettings{},
-						IPAddress:   ptr.To("1.2.3.7"),
-					},
-					ID: ptr.To("/subscriptions/sub1/resourceGroups/rg/providers/Microsoft.Network/publicIPAddresses/pip"),
-				},
-				{
-					Properties: &armnetwork.PublicIPAddressPropertiesFormat{},
-				},
-			},
-			want: &fleetnetv1alpha1.InternalServiceExport{
-				Spec: fleetnetv1alpha1.InternalServiceExportSpec{
-					Type: corev1.ServiceTypeLoadBalancer,
-				},
-			},
-		},
-		{
-			name: "service ingress ip is not set",
-			service: &corev1.Service{
-				ObjectMeta: metav1.ObjectMeta{
-					UID: "uid",
-				},
-				Spec: corev1.ServiceSpec{
-					Type: corev1.ServiceTypeLoadBalancer,
-				},
-			},
-			want: &fleetnetv1alpha1.InternalServiceExport{
-				Spec: fleetnetv1alpha1.InternalServiceExportSpec{
-					Type: corev1.ServiceTypeLoadBalancer,
-				},
-			},
-		},
-		{
-			name: "service ingress ip is set but empty",
-			service: &corev1.Service{
-				ObjectMeta: metav1.ObjectMeta{
-					UID: "uid",
-				},
-				Spec: corev1.ServiceSpec{
-					Type: corev1.ServiceTypeLoadBalancer,
-				},
-				Status: corev1.ServiceStatus{
-					LoadBalancer: corev1.LoadBalancerStatus{
-						Ingress: []corev1.LoadBalancerIngress{
-							{
-								IP: "",
-							},
-						},
-					},
-				},
-			},
-			want: &fleetnetv1alpha1.InternalServiceExport{
-				Spec: fleetnetv1alpha1.InternalServiceExportSpec{
-					Type: corev1.ServiceTypeLoadBalancer,
-				},
-			},
-		},
-		{
-			name: "invalid load balancer resource group",
-			service: &corev1.Service{
-				ObjectMeta: metav1.ObjectMeta{
-					UID: "uid",
-					Annotations: map[string]string{
-						objectmeta.ServiceAnnotationLoadBalancerResourceGroup: "invalid",
-					},
-				},
-				Spec: corev1.ServiceSpec{
-					Type: corev1.ServiceTypeLoadBalancer,
-				},
-				Status: corev1.ServiceStatus{
-					LoadBalancer: corev1.LoadBalancerStatus{
-						Ingress: []corev1.LoadBalancerIngress{
-							{
-								IP: "1.2.3.4",
-							},
-						},
-					},
-				},
-			},
-			wantErr: true,
-		},
-	}
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			r := &Reconciler{
-				AzurePublicIPAddressClient: &fakePublicIPAddressClient{ListResponse: tt.publicIPAddressListResponse, ListError: tt.publicIPAddressListResponseErr},
-				ResourceGroupName:          validResourceGroup,
-			}
-			got := &fleetnetv1alpha1.InternalServiceExport{}
-			err := r.setAzureRelatedInformation(context.Background(), tt.service, got)
-			if (err != nil) != tt.wantErr {
-				t.Errorf("setAzureRelatedInformation() error = %v, wantErr %v", err, tt.wantErr)
-				return
-			}
-			if err != nil {
-				return
-			}
-			if diff := cmp.Diff(tt.want, got); diff != "" {
-				t.Errorf("setAzureRelatedInformation() internalServiceExport mismatch (-want, +got):\n%s", diff)
-			}
-		})
-	}
-}
-
-type fakePublicIPAddressClient struct {
-	ListResponse []*armnetwork.PublicIPAddress
-	ListError    error
-}
-
-func (c *fakePublicIPAddressClient) Get(_ context.Context, _ string, _ string, _ *string) (*armnetwork.PublicIPAddress, error) {
-	return nil, nil
-}
-
-func (c *fakePublicIPAddressClient) CreateOrUpdate(_ context.Context, _ string, _ string, _ armnetwork.PublicIPAddress) (*armnetwork.PublicIPAddress, error) {
-	return nil, nil
-}
-
-func (c *fakePublicIPAddressClient) Delete(_ context.Context, _ string, _ string) error {
-	return nil
-}
-
-func (c *fakePublicIPAddressClient) List(_ context.Context, rg string) ([]*armnetwork.PublicIPAddress, error) {
-	if rg == validResourceGroup {
-		return c.ListResponse, c.ListError
-	}
-	return nil, errors.New("invalid resource group")
-}
@@ -0,0 +1,269 @@
+/*
+Copyright (c) Microsoft Corporation.
+Licensed under the MIT license.
+*/
+
+package serviceexport
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"golang.org/x/time/rate"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/klog/v2"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	ctrlmetrics "sigs.k8s.io/controller-runtime/pkg/metrics"
+
+	fleetnetv1alpha1 "go.goms.io/fleet-networking/api/v1alpha1"
+	"go.goms.io/fleet-networking/pkg/common/hubconfig"
+	"go.goms.io/fleet-networking/pkg/common/metrics"
+	"go.goms.io/fleet-networking/pkg/common/objectmeta"
+)
+
+const (
+	// HealthProbeControllerName is the name of the HealthProbeReconciler.
+	HealthProbeControllerName = "serviceexport-healthprobe-controller"
+
+	// HealthScoreHeader is the header of a health probe response which carries the score of the health.
+	HealthScoreHeader = "X-Fleet-Health-Score"
+
+	defaultHealthProbePath    = "/"
+	defaultHealthProbePeriod  = 30 * time.Second
+	defaultHealthProbeTimeout = 5 * time.Second
+	maxHealthScore            = 100
+
+	healthProbeResultHealthy   = "healthy"
+	healthProbeResultUnhealthy = "unhealthy"
+	healthProbeResultError     = "error"
+)
+
+var (
+	// exportHealthProbes counts the health probes sent to the exported Services by result: healthy and unhealthy
+	// for the probes which got a response, and error for the ones which did not, e.g. because they timed out.
+	exportHealthProbes = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: metrics.MetricsNamespace,
+			Subsystem: metrics.MetricsSubsystem,
+			Name:      "export_health_probes_total",
+			Help:      "The number of health probes sent to the exported services by result (healthy, unhealthy or error)",
+		},
+		[]string{"result"},
+	)
+)
+
+func init() {
+	// Register exportHealthProbes (fleet_networking_export_health_probes_total) metric with the controller runtime
+	// global metrics registry.
+	ctrlmetrics.Registry.MustRegister(exportHealthProbes)
+}
+
+// HealthProbeReconciler probes the health of the exported Services whose ServiceExports configure a health probe, and
+// publishes it to their InternalServiceExports in the hub cluster whenever it changes.
+type HealthProbeReconciler struct {
+	MemberClient client.Client
+	HubClient    client.Client
+	// The namespace reserved for the current member cluster in the hub cluster.
+	HubNamespace string
+	// HubExportShards is the number of namespaces in the hub cluster across which the exports of the member cluster
+	// are sharded by Service; it must match the one of the ServiceExport Reconciler.
+	HubExportShards int
+
+	// Limiter limits the rate of the health probes sent across all the exported Services, so that many exports
+	// probed at short intervals cannot flood the cluster network; nil sends the probes without limit.
+	Limiter *rate.Limiter
+	// Transport sends the health probes; nil uses http.DefaultTransport.
+	Transport http.RoundTripper
+}
+
+//+kubebuilder:rbac:groups=networking.fleet.azure.com,resources=serviceexports,verbs=get;list;watch
+//+kubebuilder:rbac:groups=networking.fleet.azure.com,resources=internalserviceexports,verbs=get;list;watch;patch
+//+kubebuilder:rbac:groups="",resources=services,verbs=get;list;watch
+
+// Reconcile probes the health of an exported Service.
+func (r *HealthProbeReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	svcExportRef := klog.KRef(req.Namespace, req.Name)
+	startTime := time.Now()
+	klog.V(2).InfoS("Reconciliation starts", "serviceExport", svcExportRef)
+	defer func() {
+		latency := time.Since(startTime).Milliseconds()
+		klog.V(2).InfoS("Reconciliation ends", "serviceExport", svcExportRef, "latency", latency)
+	}()
+
+	var svcExport fleetnetv1alpha1.ServiceExport
+	if err := r.MemberClient.Get(ctx, req.NamespacedName, &svcExport); err != nil {
+		// The health is dropped along with the InternalServiceExport once the Service is unexported.
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+	if svcExport.DeletionTimestamp != nil || !meta.IsStatusConditionTrue(svcExport.Status.Conditions, string(fleetnetv1alpha1.ServiceExportValid)) {
+		klog.V(4).InfoS("Skipping the health probe of a service which is not exported", "serviceExport", svcExportRef)
+		return ctrl.Result{}, nil
+	}
+
+	internalSvcExport := fleetnetv1alpha1.InternalServiceExport{}
+	internalSvcExportKey := types.NamespacedName{
+		Namespace: hubconfig.ExportNamespace(r.HubNamespace, r.HubExportShards, svcExport.Namespace, svcExport.Name),
+		Name:      formatInternalServiceExportName(&svcExport),
+	}
+	probe := svcExport.Spec.HealthProbe
+	if err := r.HubClient.Get(ctx, internalSvcExportKey, &internalSvcExport); err != nil {
+		if !apierrors.IsNotFound(err) {
+			klog.ErrorS(err, "Failed to get internalServiceExport", "internalServiceExport", internalSvcExportKey, "serviceExport", svcExportRef)
+			return ctrl.Result{}, err
+		}
+		if probe == nil {
+			return ctrl.Result{}, nil
+		}
+		// The Service is about to be exported; its health is published once the InternalServiceExport is created.
+		klog.V(2).InfoS("Waiting for the internalServiceExport to be created", "internalServiceExport", internalSvcExportKey, "serviceExport", svcExportRef)
+		return ctrl.Result{RequeueAfter: healthProbePeriod(probe)}, nil
+	}
+
+	if probe == nil {
+		// The health probe has been removed from the ServiceExport; the health it reported is no longer up to date.
+		if internalSvcExport.Spec.Health == nil {
+			return ctrl.Result{}, nil
+		}
+		klog.V(2).InfoS("Clearing the health of the service as it is no longer probed", "internalServiceExport", internalSvcExportKey, "serviceExport", svcExportRef)
+		return ctrl.Result{}, r.patchHealth(ctx, &internalSvcExport, nil)
+	}
+
+	var svc corev1.Service
+	if err := r.MemberClient.Get(ctx, req.NamespacedName, &svc); err != nil {
+		// The ServiceExport is marked as invalid by the ServiceExport controller once the Service is gone.
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+	if r.Limiter != nil {
+		if err := r.Limiter.Wait(ctx); err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+	health := r.probe(ctx, &svc, probe)
+	if !isHealthChanged(internalSvcExport.Spec.Health, &health) {
+		return ctrl.Result{RequeueAfter: healthProbePeriod(probe)}, nil
+	}
+	health.LastTransitionTime = metav1.Now()
+	if current := internalSvcExport.Spec.Health; current != nil && current.State == health.State {
+		// Only the score has changed.
+		health.LastTransitionTime = current.LastTransitionTime
+	}
+	klog.V(2).InfoS("Publishing the health of the service", "internalServiceExport", internalSvcExportKey, "serviceExport", svcExportRef,
+		"state", health.State, "score", health.Score, "message", health.Message)
+	if err := r.patchHealth(ctx, &internalSvcExport, &health); err != nil {
+		return ctrl.Result{}, err
+	}
+	return ctrl.Result{RequeueAfter: healthProbePeriod(probe)}, nil
+}
+
+// probe sends the health probe to the cluster IP of a Service. A response with a 2xx status code reports the Service
+// as Healthy, and any other response as Unhealthy; a probe which gets no response, e.g. because the connection is
+// refused or the probe times out, leaves the health Unknown, as a transport error cannot be told apart from a
+// problem of the network within the cluster.
+func (r *HealthProbeReconciler) probe(ctx context.Context, svc *corev1.Service, probe *fleetnetv1alpha1.HealthProbe) fleetnetv1alpha1.ExportHealth {
+	if svc.Spec.ClusterIP == "" || svc.Spec.ClusterIP == corev1.ClusterIPNone {
+		exportHealthProbes.WithLabelValues(healthProbeResultError).Inc()
+		return fleetnetv1alpha1.ExportHealth{State: fleetnetv1alpha1.ExportHealthUnknown, Message: "the service has no cluster IP"}
+	}
+	path := probe.Path
+	if path == "" {
+		path = defaultHealthProbePath
+	}
+	url := fmt.Sprintf("http://%s%s", net.JoinHostPort(svc.Spec.ClusterIP, strconv.Itoa(int(probe.Port))), path)
+
+	timeout := defaultHealthProbeTimeout
+	if probe.TimeoutSeconds > 0 {
+		timeout = time.Duration(probe.TimeoutSeconds) * time.Second
+	}
+	httpClient := &http.Client{
+		Transport: r.Transport,
+		Timeout:   timeout,
+		// A redirect is reported as is, i.e. as Unhealthy, instead of probing another endpoint.
+		CheckRedirect: func(*http.Request, []*http.Request) error { return http.ErrUseLastResponse },
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		exportHealthProbes.WithLabelValues(healthProbeResultError).Inc()
+		return fleetnetv1alpha1.ExportHealth{State: fleetnetv1alpha1.ExportHealthUnknown, Message: fmt.Sprintf("invalid health probe: %v", err)}
+	}
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		exportHealthProbes.WithLabelValues(healthProbeResultError).Inc()
+		return fleetnetv1alpha1.ExportHealth{State: fleetnetv1alpha1.ExportHealthUnknown, Message: fmt.Sprintf("the health probe got no response: %v", err)}
+	}
+	defer resp.Body.Close()
+
+	health := fleetnetv1alpha1.ExportHealth{
+		State:   fleetnetv1alpha1.ExportUnhealthy,
+		Message: fmt.Sprintf("the health probe got the status %s", resp.Status),
+	}
+	if resp.StatusCode >= http.StatusOK && resp.StatusCode < http.StatusMultipleChoices {
+		health.State = fleetnetv1alpha1.ExportHealthy
+		health.Score = maxHealthScore
+		exportHealthProbes.WithLabelValues(healthProbeResultHealthy).Inc()
+	} else {
+		exportHealthProbes.WithLabelValues(healthProbeResultUnhealthy).Inc()
+	}
+	if score, err := strconv.Atoi(resp.Header.Get(HealthScoreHeader)); err == nil && score >= 0 && score <= maxHealthScore {
+		health.Score = int32(score)
+	}
+	return health
+}
+
+// patchHealth publishes the health of an exported Service to its InternalServiceExport with a JSON patch, under a
+// field manager of its own, so that the server-side apply of the rest of the spec by the member agent leaves it as is;
+// the health is replaced as a whole, so that no field of the previous health (e.g. the score) is left over, and a nil
+// health removes it.
+func (r *HealthProbeReconciler) patchHealth(ctx context.Context, internalSvcExport *fleetnetv1alpha1.InternalServiceExport, health *fleetnetv1alpha1.ExportHealth) error {
+	op := map[string]interface{}{"op": "add", "path": "/spec/health", "value": health}
+	if health == nil {
+		op = map[string]interface{}{"op": "remove", "path": "/spec/health"}
+	}
+	data, err := json.Marshal([]interface{}{op})
+	if err != nil {
+		return err
+	}
+	patch := client.RawPatch(types.JSONPatchType, data)
+	if err := r.HubClient.Patch(ctx, internalSvcExport, patch, client.FieldOwner(objectmeta.MemberAgentHealthProbeFieldManager)); err != nil {
+		klog.ErrorS(err, "Failed to patch the health of internalServiceExport", "internalServiceExport", klog.KObj(internalSvcExport))
+		return client.IgnoreNotFound(err)
+	}
+	return nil
+}
+
+// isHealthChanged returns if the state or the score of the health has changed; the message alone, e.g. the error of
+// a probe which got no response, does not warrant a write to the hub cluster.
+func isHealthChanged(current, desired *fleetnetv1alpha1.ExportHealth) bool {
+	if current == nil {
+		return true
+	}
+	return current.State != desired.State || current.Score != desired.Score
+}
+
+// healthProbePeriod returns the interval at which the Service is probed.
+func healthProbePeriod(probe *fleetnetv1alpha1.HealthProbe) time.Duration {
+	if probe.PeriodSeconds > 0 {
+		return time.Duration(probe.PeriodSeconds) * time.Second
+	}
+	return defaultHealthProbePeriod
+}
+
+// SetupWithManager builds a controller with HealthProbeReconciler and sets it up with a controller manager.
+func (r *HealthProbeReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		Named(HealthProbeControllerName).
+		// The health probe controller watches over ServiceExport objects, so that the probe starts, changes or stops
+		// along with the export of the Service; it is requeued at the probe period afterwards.
+		For(&fleetnetv1alpha1.ServiceExport{}).
+		Complete(r)
+}
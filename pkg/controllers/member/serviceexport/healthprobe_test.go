@@ -0,0 +1,267 @@
+/*
+Copyright (c) Microsoft Corporation.
+Licensed under the MIT license.
+*/
+
+package serviceexport
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	fleetnetv1alpha1 "go.goms.io/fleet-networking/api/v1alpha1"
+	"go.goms.io/fleet-networking/pkg/common/exporthealth"
+)
+
+// flippingHealthServer is a fake application whose health check responds with the current status code and score.
+type flippingHealthServer struct {
+	status atomic.Int32
+	score  atomic.Int32
+	// hang makes the health check never respond, until the server is closed.
+	hang atomic.Bool
+}
+
+func (s *flippingHealthServer) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	if s.hang.Load() {
+		<-req.Context().Done()
+		return
+	}
+	if req.URL.Path != "/healthz" {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	if score := s.score.Load(); score >= 0 {
+		w.Header().Set(HealthScoreHeader, strconv.Itoa(int(score)))
+	}
+	w.WriteHeader(int(s.status.Load()))
+}
+
+// newHealthProbeTestFixture starts a fake application and returns a HealthProbeReconciler probing it, along with the
+// fake hub client holding the InternalServiceExport of the Service.
+func newHealthProbeTestFixture(t *testing.T, app *flippingHealthServer, probe *fleetnetv1alpha1.HealthProbe) (*HealthProbeReconciler, client.Client) {
+	server := httptest.NewServer(app)
+	t.Cleanup(server.Close)
+	host, port, err := net.SplitHostPort(server.Listener.Addr().String())
+	if err != nil {
+		t.Fatalf("SplitHostPort() got error %v, want no error", err)
+	}
+	portNumber, err := strconv.Atoi(port)
+	if err != nil {
+		t.Fatalf("Atoi() got error %v, want no error", err)
+	}
+	if probe != nil {
+		probe.Port = int32(portNumber)
+	}
+
+	svcExport := &fleetnetv1alpha1.ServiceExport{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: memberUserNS,
+			Name:      svcName,
+		},
+		Spec: fleetnetv1alpha1.ServiceExportSpec{HealthProbe: probe},
+		Status: fleetnetv1alpha1.ServiceExportStatus{
+			Conditions: []metav1.Condition{serviceExportValidCondition(memberUserNS, svcName)},
+		},
+	}
+	svc := &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: memberUserNS,
+			Name:      svcName,
+		},
+		Spec: corev1.ServiceSpec{ClusterIP: host},
+	}
+	internalSvcExport := &fleetnetv1alpha1.InternalServiceExport{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: hubNSForMember,
+			Name:      formatInternalServiceExportName(svcExport),
+		},
+	}
+	fakeMemberClient := fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(svcExport, svc).Build()
+	fakeHubClient := fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(internalSvcExport).Build()
+	return &HealthProbeReconciler{
+		MemberClient: fakeMemberClient,
+		HubClient:    fakeHubClient,
+		HubNamespace: hubNSForMember,
+	}, fakeHubClient
+}
+
+// reconcileHealth reconciles the health of the Service and returns the health published to its InternalServiceExport.
+func reconcileHealth(t *testing.T, r *HealthProbeReconciler, hubClient client.Client) (ctrl.Result, *fleetnetv1alpha1.ExportHealth) {
+	ctx := context.Background()
+	res, err := r.Reconcile(ctx, ctrl.Request{NamespacedName: types.NamespacedName{Namespace: memberUserNS, Name: svcName}})
+	if err != nil {
+		t.Fatalf("Reconcile() got error %v, want no error", err)
+	}
+	internalSvcExport := &fleetnetv1alpha1.InternalServiceExport{}
+	key := types.NamespacedName{Namespace: hubNSForMember, Name: formatInternalServiceExportName(&fleetnetv1alpha1.ServiceExport{
+		ObjectMeta: metav1.ObjectMeta{Namespace: memberUserNS, Name: svcName},
+	})}
+	if err := hubClient.Get(ctx, key, internalSvcExport); err != nil {
+		t.Fatalf("internalServiceExport Get() got error %v, want no error", err)
+	}
+	return res, internalSvcExport.Spec.Health
+}
+
+// TestHealthProbeReconciler_FlippingHealth tests that the health published to the InternalServiceExport follows the
+// health check of the Service as it flips, and that an importing cluster excludes the exporting cluster per the
+// exporter health policy while it reports the Service as Unhealthy.
+func TestHealthProbeReconciler_FlippingHealth(t *testing.T) {
+	app := &flippingHealthServer{}
+	app.status.Store(http.StatusOK)
+	app.score.Store(-1)
+	probe := &fleetnetv1alpha1.HealthProbe{Path: "/healthz", PeriodSeconds: 10, TimeoutSeconds: 1}
+	r, hubClient := newHealthProbeTestFixture(t, app, probe)
+
+	ignoreMessageAndTime := cmpopts.IgnoreFields(fleetnetv1alpha1.ExportHealth{}, "Message", "LastTransitionTime")
+	// The other cluster exporting the Service is always healthy.
+	const otherCluster = "member-2"
+	status := &fleetnetv1alpha1.ServiceImportStatus{
+		Clusters: []fleetnetv1alpha1.ClusterStatus{{Cluster: hubNSForMember}, {Cluster: otherCluster}},
+	}
+	exporthealth.Set(status, otherCluster, &fleetnetv1alpha1.ExportHealth{State: fleetnetv1alpha1.ExportHealthy, Score: 100})
+
+	steps := []struct {
+		name        string
+		status      int32
+		score       int32
+		hang        bool
+		want        fleetnetv1alpha1.ExportHealth
+		wantExclude bool
+	}{
+		{
+			name:   "healthy",
+			status: http.StatusOK,
+			score:  -1,
+			want:   fleetnetv1alpha1.ExportHealth{State: fleetnetv1alpha1.ExportHealthy, Score: 100},
+		},
+		{
+			name:        "unhealthy",
+			status:      http.StatusServiceUnavailable,
+			score:       -1,
+			want:        fleetnetv1alpha1.ExportHealth{State: fleetnetv1alpha1.ExportUnhealthy},
+			wantExclude: true,
+		},
+		{
+			name:   "healthy with a reported score",
+			status: http.StatusOK,
+			score:  40,
+			want:   fleetnetv1alpha1.ExportHealth{State: fleetnetv1alpha1.ExportHealthy, Score: 40},
+		},
+		{
+			name:   "no response",
+			status: http.StatusOK,
+			score:  40,
+			hang:   true,
+			want:   fleetnetv1alpha1.ExportHealth{State: fleetnetv1alpha1.ExportHealthUnknown},
+		},
+		{
+			name:        "unhealthy again",
+			status:      http.StatusInternalServerError,
+			score:       10,
+			want:        fleetnetv1alpha1.ExportHealth{State: fleetnetv1alpha1.ExportUnhealthy, Score: 10},
+			wantExclude: true,
+		},
+	}
+	for _, step := range steps {
+		app.status.Store(step.status)
+		app.score.Store(step.score)
+		app.hang.Store(step.hang)
+		res, got := reconcileHealth(t, r, hubClient)
+		if got == nil {
+			t.Fatalf("%s: health = nil, want %v", step.name, step.want)
+		}
+		if diff := cmp.Diff(step.want, *got, ignoreMessageAndTime); diff != "" {
+			t.Errorf("%s: health mismatch (-want, +got):\n%s", step.name, diff)
+		}
+		if got.LastTransitionTime.IsZero() {
+			t.Errorf("%s: health lastTransitionTime is not set", step.name)
+		}
+		if want := 10 * time.Second; res.RequeueAfter != want {
+			t.Errorf("%s: Reconcile() requeueAfter = %v, want %v", step.name, res.RequeueAfter, want)
+		}
+
+		// The hub cluster aggregates the health into the ServiceImport imported by the member clusters.
+		exporthealth.Set(status, hubNSForMember, got)
+		if exclude := exporthealth.ShouldExclude(fleetnetv1alpha1.ExporterHealthPolicyExcludeUnhealthy, status, hubNSForMember); exclude != step.wantExclude {
+			t.Errorf("%s: ShouldExclude() = %v, want %v", step.name, exclude, step.wantExclude)
+		}
+		if exporthealth.ShouldExclude(fleetnetv1alpha1.ExporterHealthPolicyIgnore, status, hubNSForMember) {
+			t.Errorf("%s: ShouldExclude() with the ignore policy = true, want false", step.name)
+		}
+	}
+}
+
+// TestHealthProbeReconciler_UnchangedHealth tests that the health is not written again while it does not change.
+func TestHealthProbeReconciler_UnchangedHealth(t *testing.T) {
+	app := &flippingHealthServer{}
+	app.status.Store(http.StatusServiceUnavailable)
+	app.score.Store(-1)
+	r, hubClient := newHealthProbeTestFixture(t, app, &fleetnetv1alpha1.HealthProbe{Path: "/healthz"})
+
+	_, first := reconcileHealth(t, r, hubClient)
+	if first == nil || first.State != fleetnetv1alpha1.ExportUnhealthy {
+		t.Fatalf("health = %v, want Unhealthy", first)
+	}
+	errorsBefore := testutil.ToFloat64(exportHealthProbes.WithLabelValues(healthProbeResultError))
+	unhealthyBefore := testutil.ToFloat64(exportHealthProbes.WithLabelValues(healthProbeResultUnhealthy))
+	res, second := reconcileHealth(t, r, hubClient)
+	if diff := cmp.Diff(first, second); diff != "" {
+		t.Errorf("health mismatch after an unchanged probe (-want, +got):\n%s", diff)
+	}
+	if want := defaultHealthProbePeriod; res.RequeueAfter != want {
+		t.Errorf("Reconcile() requeueAfter = %v, want %v", res.RequeueAfter, want)
+	}
+	// An unhealthy response is not accounted for as a transport error.
+	if got := testutil.ToFloat64(exportHealthProbes.WithLabelValues(healthProbeResultUnhealthy)) - unhealthyBefore; got != 1 {
+		t.Errorf("unhealthy probes metric increase = %v, want 1", got)
+	}
+	if got := testutil.ToFloat64(exportHealthProbes.WithLabelValues(healthProbeResultError)) - errorsBefore; got != 0 {
+		t.Errorf("error probes metric increase = %v, want 0", got)
+	}
+}
+
+// TestHealthProbeReconciler_ProbeRemoved tests that the health is cleared once the ServiceExport no longer configures
+// a health probe.
+func TestHealthProbeReconciler_ProbeRemoved(t *testing.T) {
+	app := &flippingHealthServer{}
+	app.status.Store(http.StatusOK)
+	app.score.Store(-1)
+	r, hubClient := newHealthProbeTestFixture(t, app, &fleetnetv1alpha1.HealthProbe{Path: "/healthz"})
+	if _, health := reconcileHealth(t, r, hubClient); health == nil {
+		t.Fatal("health = nil, want Healthy")
+	}
+
+	ctx := context.Background()
+	svcExport := &fleetnetv1alpha1.ServiceExport{}
+	if err := r.MemberClient.Get(ctx, types.NamespacedName{Namespace: memberUserNS, Name: svcName}, svcExport); err != nil {
+		t.Fatalf("serviceExport Get() got error %v, want no error", err)
+	}
+	svcExport.Spec.HealthProbe = nil
+	if err := r.MemberClient.Update(ctx, svcExport); err != nil {
+		t.Fatalf("serviceExport Update() got error %v, want no error", err)
+	}
+	res, health := reconcileHealth(t, r, hubClient)
+	if health != nil {
+		t.Errorf("health = %v, want nil", health)
+	}
+	if res.RequeueAfter != 0 {
+		t.Errorf("Reconcile() requeueAfter = %v, want 0", res.RequeueAfter)
+	}
+}
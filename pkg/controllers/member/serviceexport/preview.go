@@ -0,0 +1,145 @@
+/*
+Copyright (c) Microsoft Corporation.
+Licensed under the MIT license.
+*/
+
+package serviceexport
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/klog/v2"
+
+	fleetnetv1alpha1 "go.goms.io/fleet-networking/api/v1alpha1"
+	"go.goms.io/fleet-networking/pkg/common/exportconflict"
+	"go.goms.io/fleet-networking/pkg/common/objectmeta"
+	"go.goms.io/fleet-networking/pkg/common/rejectiondiff"
+)
+
+// previewRefreshInterval is how often the preview of a ServiceExport is recomputed, as the resolution of the Service
+// in the hub cluster may change in the meantime.
+const previewRefreshInterval = time.Minute
+
+// isPreviewEnabled returns true if a ServiceExport is in the preview mode.
+func isPreviewEnabled(svcExport *fleetnetv1alpha1.ServiceExport) bool {
+	return svcExport.Annotations[objectmeta.ServiceExportAnnotationPreview] == "true"
+}
+
+// previewedService returns the Service whose export is previewed: the Service with the candidate spec of the
+// ServiceExport, if any, or the Service as is.
+func previewedService(svcExport *fleetnetv1alpha1.ServiceExport, svc *corev1.Service) (*corev1.Service, error) {
+	data, ok := svcExport.Annotations[objectmeta.ServiceExportAnnotationPreviewServiceSpec]
+	if !ok {
+		return svc, nil
+	}
+	spec := corev1.ServiceSpec{}
+	if err := json.Unmarshal([]byte(data), &spec); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal the candidate service spec: %w", err)
+	}
+	candidate := svc.DeepCopy()
+	candidate.Spec = spec
+	return candidate, nil
+}
+
+// newServiceReference returns the reference of a Service exported for the first time under the given name.
+func newServiceReference(memberClusterID string, svc *corev1.Service, exportedName string, exportedSince time.Time) fleetnetv1alpha1.ExportedObjectReference {
+	svcReference := fleetnetv1alpha1.FromMetaObjects(memberClusterID, svc.TypeMeta, svc.ObjectMeta, metav1.NewTime(exportedSince))
+	if exportedName != svc.Name {
+		// The Service is exported under a different name; the hub cluster groups exported Services
+		// by the name in the reference, so the original name is kept separately.
+		svcReference.Name = exportedName
+		svcReference.NamespacedName = types.NamespacedName{Namespace: svc.Namespace, Name: exportedName}.String()
+	}
+	return svcReference
+}
+
+// previewExport computes the outcome of exporting the pending changes to a Service, i.e. the change of its
+// InternalServiceExport, the outcome of the conflict resolution in the hub cluster and the member clusters importing
+// the Service, and publishes it in the status of the ServiceExport. Nothing is written to the hub cluster; the current
+// InternalServiceExport, if any, is returned.
+func (r *Reconciler) previewExport(ctx context.Context, svcExport *fleetnetv1alpha1.ServiceExport, svc *corev1.Service) (*fleetnetv1alpha1.InternalServiceExport, error) {
+	preview := &fleetnetv1alpha1.ServiceExportPreview{ServiceGeneration: svc.Generation}
+	candidate, err := previewedService(svcExport, svc)
+	if err != nil {
+		preview.Conflict = fleetnetv1alpha1.ExportConflictUnknown
+		preview.Message = err.Error()
+		return nil, r.updatePreview(ctx, svcExport, preview)
+	}
+
+	key := types.NamespacedName{Namespace: r.hubExportNamespace(svcExport), Name: formatInternalServiceExportName(svcExport)}
+	existing := &fleetnetv1alpha1.InternalServiceExport{}
+	var existingSpec *fleetnetv1alpha1.InternalServiceExportSpec
+	var resolution *fleetnetv1alpha1.ExportResolution
+	var svcReference fleetnetv1alpha1.ExportedObjectReference
+	switch err := r.HubClient.Get(ctx, key, existing); {
+	case err == nil:
+		existingSpec = &existing.Spec
+		resolution = existing.Status.Resolution
+		// The reference only tracks the version of the Service; it is kept as is, so that the diff shows the
+		// changes which matter to the fleet only.
+		svcReference = existing.Spec.ServiceReference
+	case apierrors.IsNotFound(err):
+		existing = nil
+		svcReference = newServiceReference(r.MemberClusterID, candidate, objectmeta.ExportedServiceName(svcExport), time.Now())
+	default:
+		klog.ErrorS(err, "Failed to get internalServiceExport", "internalServiceExport", key, "service", klog.KObj(svc))
+		return nil, err
+	}
+
+	previewed := BuildInternalServiceExport(key, r.MemberClusterID, candidate, svcExport, svcReference, r.NetworkProperties)
+	if existingSpec != nil {
+		// The Traffic Manager related information and the health are reported from the cloud provider and the health
+		// probes, which are not consulted for a preview; they are assumed unchanged.
+		keepReportedFields(&previewed.Spec, existingSpec)
+		if !equality.Semantic.DeepEqual(*existingSpec, previewed.Spec) {
+			preview.Diff = rejectiondiff.Diff(*existingSpec, previewed.Spec, rejectiondiff.MaxDiffBytes)
+		}
+	} else {
+		preview.Diff = rejectiondiff.Diff(fleetnetv1alpha1.InternalServiceExportSpec{}, previewed.Spec, rejectiondiff.MaxDiffBytes)
+	}
+	if resolution != nil {
+		preview.ImporterClusters = resolution.ImporterClusters
+	}
+	if !isServiceEligibleForExport(candidate) {
+		preview.Conflict = fleetnetv1alpha1.ExportConflictUnknown
+		preview.Message = fmt.Sprintf("service %s/%s is not eligible for export", svc.Namespace, svc.Name)
+	} else {
+		preview.Conflict, preview.Message = exportconflict.Evaluate(resolution, &previewed.Spec)
+	}
+	return existing, r.updatePreview(ctx, svcExport, preview)
+}
+
+// keepReportedFields copies the fields of an InternalServiceExport spec which are not derived from the Service spec
+// alone from the existing spec.
+func keepReportedFields(spec, existing *fleetnetv1alpha1.InternalServiceExportSpec) {
+	spec.Type = existing.Type
+	spec.IsDNSLabelConfigured = existing.IsDNSLabelConfigured
+	spec.IsInternalLoadBalancer = existing.IsInternalLoadBalancer
+	spec.IsLoadBalancerPending = existing.IsLoadBalancerPending
+	spec.IsPublicIPExplicit = existing.IsPublicIPExplicit
+	spec.PublicIPResourceID = existing.PublicIPResourceID
+	spec.ExternalTrafficPolicy = existing.ExternalTrafficPolicy
+	spec.HealthCheckNodePort = existing.HealthCheckNodePort
+	spec.Weight = existing.Weight
+	spec.Health = existing.Health
+}
+
+// updatePreview sets the preview of a ServiceExport; a nil preview clears it.
+func (r *Reconciler) updatePreview(ctx context.Context, svcExport *fleetnetv1alpha1.ServiceExport, preview *fleetnetv1alpha1.ServiceExportPreview) error {
+	if equality.Semantic.DeepEqual(svcExport.Status.Preview, preview) {
+		return nil
+	}
+	if preview != nil {
+		klog.V(2).InfoS("Previewed the export of the service", "serviceExport", klog.KObj(svcExport), "conflict", preview.Conflict, "importerClusters", preview.ImporterClusters)
+	}
+	svcExport.Status.Preview = preview
+	return r.MemberClient.Status().Update(ctx, svcExport)
+}
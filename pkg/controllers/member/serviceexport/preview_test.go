@@ -0,0 +1,304 @@
+/*
+Copyright (c) Microsoft Corporation.
+Licensed under the MIT license.
+*/
+
+package serviceexport
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+
+	fleetnetv1alpha1 "go.goms.io/fleet-networking/api/v1alpha1"
+	"go.goms.io/fleet-networking/pkg/common/objectmeta"
+)
+
+// previewService returns a Service exposing port 80.
+func previewService() *corev1.Service {
+	return &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: memberUserNS,
+			Name:      svcName,
+		},
+		Spec: corev1.ServiceSpec{
+			Type:      corev1.ServiceTypeClusterIP,
+			ClusterIP: "10.0.0.1",
+			Ports: []corev1.ServicePort{
+				{Name: "http", Protocol: corev1.ProtocolTCP, Port: 80, TargetPort: intstr.FromInt32(8080)},
+			},
+		},
+	}
+}
+
+// previewInternalServiceExport returns the InternalServiceExport of the Service as exported currently, with the given
+// resolution reported by the hub cluster.
+func previewInternalServiceExport(svcExport *fleetnetv1alpha1.ServiceExport, resolution *fleetnetv1alpha1.ExportResolution) *fleetnetv1alpha1.InternalServiceExport {
+	return &fleetnetv1alpha1.InternalServiceExport{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: hubNSForMember,
+			Name:      formatInternalServiceExportName(svcExport),
+		},
+		Spec: fleetnetv1alpha1.InternalServiceExportSpec{
+			Ports: extractServicePorts(previewService()),
+			ServiceReference: fleetnetv1alpha1.ExportedObjectReference{
+				ClusterID:       memberClusterID,
+				Kind:            "Service",
+				Namespace:       memberUserNS,
+				Name:            svcName,
+				ResourceVersion: "1",
+				NamespacedName:  memberUserNS + "/" + svcName,
+			},
+			Type: corev1.ServiceTypeClusterIP,
+		},
+		Status: fleetnetv1alpha1.InternalServiceExportStatus{
+			LastHeartbeatTime: &metav1.Time{Time: time.Now().Add(-time.Hour)},
+			Resolution:        resolution,
+		},
+	}
+}
+
+// hubWriteInterceptor fails and counts the writes to the hub cluster other than the status patches, i.e. the
+// heartbeat refreshes.
+func hubWriteInterceptor(writes *int) interceptor.Funcs {
+	return interceptor.Funcs{
+		Create: func(_ context.Context, _ client.WithWatch, _ client.Object, _ ...client.CreateOption) error {
+			*writes++
+			return apierrors.NewBadRequest("unexpected create")
+		},
+		Update: func(_ context.Context, _ client.WithWatch, _ client.Object, _ ...client.UpdateOption) error {
+			*writes++
+			return apierrors.NewBadRequest("unexpected update")
+		},
+		Patch: func(_ context.Context, _ client.WithWatch, _ client.Object, _ client.Patch, _ ...client.PatchOption) error {
+			*writes++
+			return apierrors.NewBadRequest("unexpected patch")
+		},
+		Delete: func(_ context.Context, _ client.WithWatch, _ client.Object, _ ...client.DeleteOption) error {
+			*writes++
+			return apierrors.NewBadRequest("unexpected delete")
+		},
+	}
+}
+
+// TestReconcile_Preview tests that a ServiceExport in the preview mode has the outcome of the export of the candidate
+// spec published in its status, while nothing but the heartbeat is written to the hub cluster.
+func TestReconcile_Preview(t *testing.T) {
+	svcExport := &fleetnetv1alpha1.ServiceExport{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: memberUserNS,
+			Name:      svcName,
+			Annotations: map[string]string{
+				objectmeta.ServiceExportAnnotationPreview:            "true",
+				objectmeta.ServiceExportAnnotationPreviewServiceSpec: `{"type":"ClusterIP","clusterIP":"10.0.0.1","ports":[{"name":"http","protocol":"TCP","port":8080,"targetPort":8080}]}`,
+			},
+		},
+	}
+	resolution := &fleetnetv1alpha1.ExportResolution{
+		Ports:             extractServicePorts(previewService()),
+		ExportingClusters: []string{memberClusterID, "member-2"},
+		ImporterClusters:  []string{"member-3", "member-4"},
+	}
+	internalSvcExport := previewInternalServiceExport(svcExport, resolution)
+
+	fakeMemberClient := fake.NewClientBuilder().
+		WithScheme(scheme.Scheme).
+		WithObjects(svcExport, previewService()).
+		WithStatusSubresource(svcExport).
+		Build()
+	var hubWrites int
+	fakeHubClient := fake.NewClientBuilder().
+		WithScheme(scheme.Scheme).
+		WithObjects(internalSvcExport).
+		WithStatusSubresource(internalSvcExport).
+		WithInterceptorFuncs(hubWriteInterceptor(&hubWrites)).
+		Build()
+	reconciler := &Reconciler{
+		MemberClusterID:   memberClusterID,
+		MemberClient:      fakeMemberClient,
+		HubClient:         fakeHubClient,
+		HubNamespace:      hubNSForMember,
+		Recorder:          record.NewFakeRecorder(10),
+		HeartbeatInterval: 5 * time.Minute,
+	}
+
+	ctx := context.Background()
+	svcExportKey := types.NamespacedName{Namespace: memberUserNS, Name: svcName}
+	res, err := reconciler.Reconcile(ctx, ctrl.Request{NamespacedName: svcExportKey})
+	if err != nil {
+		t.Fatalf("Reconcile() = %v, want no error", err)
+	}
+	if want := (ctrl.Result{RequeueAfter: previewRefreshInterval}); !cmp.Equal(res, want) {
+		t.Errorf("Reconcile() = %+v, want %+v", res, want)
+	}
+	if hubWrites != 0 {
+		t.Errorf("hub writes, got %d, want 0", hubWrites)
+	}
+
+	gotInternalSvcExport := &fleetnetv1alpha1.InternalServiceExport{}
+	if err := fakeHubClient.Get(ctx, client.ObjectKeyFromObject(internalSvcExport), gotInternalSvcExport); err != nil {
+		t.Fatalf("internalSvcExport Get() = %v, want no error", err)
+	}
+	if diff := cmp.Diff(internalSvcExport.Spec, gotInternalSvcExport.Spec); diff != "" {
+		t.Errorf("internalSvcExport spec changed (-want, +got):\n%s", diff)
+	}
+	if !gotInternalSvcExport.Status.LastHeartbeatTime.After(internalSvcExport.Status.LastHeartbeatTime.Time) {
+		t.Errorf("internalSvcExport heartbeat, got %v, want refreshed", gotInternalSvcExport.Status.LastHeartbeatTime)
+	}
+
+	gotSvcExport := &fleetnetv1alpha1.ServiceExport{}
+	if err := fakeMemberClient.Get(ctx, svcExportKey, gotSvcExport); err != nil {
+		t.Fatalf("svcExport Get() = %v, want no error", err)
+	}
+	preview := gotSvcExport.Status.Preview
+	if preview == nil {
+		t.Fatal("svcExport preview = nil, want a preview")
+	}
+	if preview.Conflict != fleetnetv1alpha1.ExportConflictConflicted {
+		t.Errorf("preview conflict, got %s, want %s", preview.Conflict, fleetnetv1alpha1.ExportConflictConflicted)
+	}
+	if diff := cmp.Diff(resolution.ImporterClusters, preview.ImporterClusters); diff != "" {
+		t.Errorf("preview importer clusters (-want, +got):\n%s", diff)
+	}
+	if !strings.Contains(preview.Diff, "8080") {
+		t.Errorf("preview diff, got %q, want the port change", preview.Diff)
+	}
+	if len(gotSvcExport.Finalizers) != 0 {
+		t.Errorf("svcExport finalizers, got %v, want none", gotSvcExport.Finalizers)
+	}
+}
+
+// TestPreviewExport tests the *Reconciler.previewExport method.
+func TestPreviewExport(t *testing.T) {
+	withOtherExporter := &fleetnetv1alpha1.ExportResolution{
+		Ports:             extractServicePorts(previewService()),
+		ExportingClusters: []string{memberClusterID, "member-2"},
+		ImporterClusters:  []string{"member-3"},
+	}
+	soleExporter := &fleetnetv1alpha1.ExportResolution{
+		Ports:             extractServicePorts(previewService()),
+		ExportingClusters: []string{memberClusterID},
+		ImporterClusters:  []string{"member-3"},
+	}
+	const portChange = `{"type":"ClusterIP","clusterIP":"10.0.0.1","ports":[{"name":"http","protocol":"TCP","port":8080,"targetPort":8080}]}`
+	testCases := []struct {
+		name          string
+		candidateSpec string
+		notExported   bool
+		resolution    *fleetnetv1alpha1.ExportResolution
+		wantConflict  fleetnetv1alpha1.ExportConflictOutcome
+		wantDiff      bool
+	}{
+		{
+			name:         "unchanged service",
+			resolution:   withOtherExporter,
+			wantConflict: fleetnetv1alpha1.ExportConflictNone,
+		},
+		{
+			name:          "port change with another exporting cluster",
+			candidateSpec: portChange,
+			resolution:    withOtherExporter,
+			wantConflict:  fleetnetv1alpha1.ExportConflictConflicted,
+			wantDiff:      true,
+		},
+		{
+			name:          "port change of the sole exporting cluster",
+			candidateSpec: portChange,
+			resolution:    soleExporter,
+			wantConflict:  fleetnetv1alpha1.ExportConflictNone,
+			wantDiff:      true,
+		},
+		{
+			name:          "resolution not reported",
+			candidateSpec: portChange,
+			wantConflict:  fleetnetv1alpha1.ExportConflictUnknown,
+			wantDiff:      true,
+		},
+		{
+			name:         "service not exported yet",
+			notExported:  true,
+			wantConflict: fleetnetv1alpha1.ExportConflictUnknown,
+			wantDiff:     true,
+		},
+		{
+			name:          "ineligible candidate spec",
+			candidateSpec: `{"type":"ExternalName","externalName":"example.com"}`,
+			resolution:    withOtherExporter,
+			wantConflict:  fleetnetv1alpha1.ExportConflictUnknown,
+			wantDiff:      true,
+		},
+		{
+			name:          "invalid candidate spec",
+			candidateSpec: `{`,
+			resolution:    withOtherExporter,
+			wantConflict:  fleetnetv1alpha1.ExportConflictUnknown,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			svcExport := &fleetnetv1alpha1.ServiceExport{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace:   memberUserNS,
+					Name:        svcName,
+					Annotations: map[string]string{objectmeta.ServiceExportAnnotationPreview: "true"},
+				},
+			}
+			if tc.candidateSpec != "" {
+				svcExport.Annotations[objectmeta.ServiceExportAnnotationPreviewServiceSpec] = tc.candidateSpec
+			}
+			fakeMemberClient := fake.NewClientBuilder().
+				WithScheme(scheme.Scheme).
+				WithObjects(svcExport).
+				WithStatusSubresource(svcExport).
+				Build()
+			hubClientBuilder := fake.NewClientBuilder().WithScheme(scheme.Scheme)
+			if !tc.notExported {
+				hubClientBuilder = hubClientBuilder.WithObjects(previewInternalServiceExport(svcExport, tc.resolution))
+			}
+			var hubWrites int
+			reconciler := &Reconciler{
+				MemberClusterID: memberClusterID,
+				MemberClient:    fakeMemberClient,
+				HubClient:       hubClientBuilder.WithInterceptorFuncs(hubWriteInterceptor(&hubWrites)).Build(),
+				HubNamespace:    hubNSForMember,
+			}
+
+			ctx := context.Background()
+			if _, err := reconciler.previewExport(ctx, svcExport, previewService()); err != nil {
+				t.Fatalf("previewExport() = %v, want no error", err)
+			}
+			if hubWrites != 0 {
+				t.Errorf("hub writes, got %d, want 0", hubWrites)
+			}
+			gotSvcExport := &fleetnetv1alpha1.ServiceExport{}
+			if err := fakeMemberClient.Get(ctx, client.ObjectKeyFromObject(svcExport), gotSvcExport); err != nil {
+				t.Fatalf("svcExport Get() = %v, want no error", err)
+			}
+			preview := gotSvcExport.Status.Preview
+			if preview == nil {
+				t.Fatal("svcExport preview = nil, want a preview")
+			}
+			if preview.Conflict != tc.wantConflict {
+				t.Errorf("preview conflict, got %s (%s), want %s", preview.Conflict, preview.Message, tc.wantConflict)
+			}
+			if gotDiff := preview.Diff != ""; gotDiff != tc.wantDiff {
+				t.Errorf("preview diff, got %q, want diff: %t", preview.Diff, tc.wantDiff)
+			}
+		})
+	}
+}
@@ -0,0 +1,417 @@
+/*
+Copyright (c) Microsoft Corporation.
+Licensed under the MIT license.
+*/
+
+package serviceexport
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/arm"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/network/armnetwork/v4"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/cloud-provider-azure/pkg/azclient"
+	"sigs.k8s.io/cloud-provider-azure/pkg/azclient/policy/ratelimit"
+	"sigs.k8s.io/cloud-provider-azure/pkg/azclient/publicipaddressclient"
+
+	"go.goms.io/fleet/pkg/utils/cloudconfig/azure"
+	"go.goms.io/fleet/pkg/utils/controller"
+
+	fleetnetv1alpha1 "go.goms.io/fleet-networking/api/v1alpha1"
+	"go.goms.io/fleet-networking/pkg/common/azureerrors"
+	"go.goms.io/fleet-networking/pkg/common/cloudconfig"
+	"go.goms.io/fleet-networking/pkg/common/objectmeta"
+)
+
+const (
+	// CloudProviderAzure is the name of the Azure cloud provider.
+	CloudProviderAzure = "azure"
+	// CloudProviderNone is the name of the cloud provider used by the member clusters which are not hosted on any
+	// supported cloud; the Services are exported without any cloud specific information.
+	CloudProviderNone = "none"
+
+	// azureUserAgent is the user agent of the Azure requests sent by the member agent.
+	azureUserAgent = "fleet-member-net-controller-manager"
+
+	// azurePublicIPAddressResourceType is the resource type of the Azure public IP addresses.
+	azurePublicIPAddressResourceType = "Microsoft.Network/publicIPAddresses"
+
+	// publicIPDNSLabelPrefix is the prefix of the DNS labels the member agent sets on the public IP addresses.
+	publicIPDNSLabelPrefix = "fleet-"
+	// publicIPDNSLabelHashLength is the number of the hex characters of the hash in the DNS labels the member agent sets
+	// on the public IP addresses.
+	publicIPDNSLabelHashLength = 16
+	// maxPublicIPDNSLabelAttempts is the maximum number of the DNS labels tried for a public IP address when the
+	// previous ones are taken by other public IP addresses in the region.
+	maxPublicIPDNSLabelAttempts = 3
+)
+
+var (
+	// errInvalidPublicIP is returned when the public IP address specified for a Service cannot expose it, so that the
+	// error is reported to the user instead of being retried.
+	errInvalidPublicIP = errors.New("invalid public IP address")
+	// errDNSLabelNotSet is returned when the DNS label cannot be set on the public IP address of the load balancer of a
+	// Service, e.g. for lack of permission, so that the error is reported to the user instead of being retried; the
+	// Service is exported without the DNS label as if the DNS labels were not managed.
+	errDNSLabelNotSet = errors.New("DNS label cannot be set on the public IP address")
+)
+
+// CloudProvider resolves the cloud specific information about how a Service is exposed publicly by its load
+// balancer, which is required by the Traffic Manager feature.
+type CloudProvider interface {
+	// SetLoadBalancerInformation populates the load balancer information of the Service in the InternalServiceExport;
+	// an error wrapping errDNSLabelNotSet is returned, with the information populated, if the DNS label of the public
+	// IP address of the load balancer is managed but cannot be set.
+	SetLoadBalancerInformation(ctx context.Context, service *corev1.Service, export *fleetnetv1alpha1.InternalServiceExport) error
+	// SetPublicIPInformation validates the public IP address specified explicitly for the Service and populates it in
+	// the InternalServiceExport; an error wrapping errInvalidPublicIP is returned if the public IP cannot expose the
+	// Service.
+	SetPublicIPInformation(ctx context.Context, service *corev1.Service, publicIPResourceID string, export *fleetnetv1alpha1.InternalServiceExport) error
+}
+
+// NewCloudProvider returns the CloudProvider of the given name; the Azure cloud config is not loaded until the
+// provider is used or AzureCloudProvider.LoadCloudConfig is called, and is reloaded every cloudConfigReloadInterval
+// once the provider is started.
+func NewCloudProvider(name, cloudConfigFile string, cloudConfigReloadInterval time.Duration) (CloudProvider, error) {
+	switch name {
+	case CloudProviderAzure:
+		return &AzureCloudProvider{CloudConfigFile: cloudConfigFile, CloudConfigReloadInterval: cloudConfigReloadInterval}, nil
+	case CloudProviderNone:
+		return NoneCloudProvider{}, nil
+	default:
+		return nil, fmt.Errorf("unsupported cloud provider %q, must be one of %q, %q", name, CloudProviderAzure, CloudProviderNone)
+	}
+}
+
+// NoneCloudProvider is the CloudProvider of the member clusters which are not hosted on any supported cloud; it does
+// not populate any information.
+type NoneCloudProvider struct{}
+
+// SetLoadBalancerInformation implements the CloudProvider interface; only the type of the Service is populated.
+func (NoneCloudProvider) SetLoadBalancerInformation(_ context.Context, service *corev1.Service, export *fleetnetv1alpha1.InternalServiceExport) error {
+	export.Spec.Type = service.Spec.Type
+	return nil
+}
+
+// SetPublicIPInformation implements the CloudProvider interface; the public IP addresses are not supported.
+func (NoneCloudProvider) SetPublicIPInformation(_ context.Context, service *corev1.Service, _ string, export *fleetnetv1alpha1.InternalServiceExport) error {
+	export.Spec.Type = service.Spec.Type
+	return fmt.Errorf("%w: public IP addresses are not supported by the cloud provider %q", errInvalidPublicIP, CloudProviderNone)
+}
+
+// AzureCloudProvider is the CloudProvider of the AKS member clusters, which looks up the Azure public IP addresses
+// of the load balancers.
+//
+// The Azure cloud config is loaded from CloudConfigFile by LoadCloudConfig or on the first use, unless the
+// PublicIPAddressClient is set, and the Azure clients are rebuilt when the file changes.
+//
+// AzureCloudProvider implements the controller-runtime Runnable interface, which reloads the cloud config file.
+type AzureCloudProvider struct {
+	// CloudConfigFile is the path to the Azure cloud config file.
+	CloudConfigFile string
+	// CloudConfigReloadInterval is how often the cloud config file is checked for changes; 0 disables the reload.
+	CloudConfigReloadInterval time.Duration
+
+	ResourceGroupName     string // default resource group name to create public IP address
+	PublicIPAddressClient publicipaddressclient.Interface
+
+	// ManagePublicIPDNSLabel sets a DNS label on the public IP address of the load balancer of an exported Service
+	// when it has none and the Service does not request one with the azure-dns-label-name annotation, which requires
+	// the write permission on the public IP addresses.
+	ManagePublicIPDNSLabel bool
+	// ClusterID is the ID of the member cluster, from which the DNS labels set on the public IP addresses are derived.
+	ClusterID string
+
+	mu           sync.Mutex
+	azureClients *cloudconfig.Reloader[azureNetworkClients]
+}
+
+// azureNetworkClients are the Azure network resource clients built from the cloud config.
+type azureNetworkClients struct {
+	publicIPAddressClient publicipaddressclient.Interface
+	resourceGroupName     string
+}
+
+// reloader returns the reloader of the cloud config file, creating it on the first call.
+func (p *AzureCloudProvider) reloader() *cloudconfig.Reloader[azureNetworkClients] {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.azureClients == nil {
+		p.azureClients = cloudconfig.NewReloader(p.CloudConfigFile, azureUserAgent, p.CloudConfigReloadInterval, initAzureNetworkClients)
+	}
+	return p.azureClients
+}
+
+// publicIPAddressClient returns the Azure public IP address client and the default resource group built from the
+// latest cloud config, loading the cloud config if it has not been loaded yet; a failed load is retried on the next
+// call.
+func (p *AzureCloudProvider) publicIPAddressClient() (publicipaddressclient.Interface, string, error) {
+	if p.PublicIPAddressClient != nil {
+		return p.PublicIPAddressClient, p.ResourceGroupName, nil
+	}
+	clients, err := p.reloader().Current()
+	if err != nil {
+		klog.ErrorS(err, "Unable to create Azure network clients", "cloudConfigFile", p.CloudConfigFile)
+		return nil, "", err
+	}
+	return clients.publicIPAddressClient, clients.resourceGroupName, nil
+}
+
+// LoadCloudConfig loads the cloud config file and builds the Azure clients, so that an invalid cloud config is
+// reported at startup instead of on the first export of a load balancer Service.
+func (p *AzureCloudProvider) LoadCloudConfig() error {
+	_, _, err := p.publicIPAddressClient()
+	return err
+}
+
+// NeedLeaderElection implements the LeaderElectionRunnable interface.
+func (p *AzureCloudProvider) NeedLeaderElection() bool {
+	return false
+}
+
+// Start reloads the cloud config file periodically and blocks until the context is done.
+func (p *AzureCloudProvider) Start(ctx context.Context) error {
+	if p.PublicIPAddressClient != nil {
+		<-ctx.Done()
+		return nil
+	}
+	return p.reloader().Start(ctx)
+}
+
+// SetLoadBalancerInformation implements the CloudProvider interface.
+func (p *AzureCloudProvider) SetLoadBalancerInformation(ctx context.Context, service *corev1.Service, export *fleetnetv1alpha1.InternalServiceExport) error {
+	export.Spec.Type = service.Spec.Type
+	if service.Spec.Type != corev1.ServiceTypeLoadBalancer {
+		return nil
+	}
+	// The annotation value is case-sensitive.
+	// https://github.com/kubernetes-sigs/cloud-provider-azure/blob/release-1.31/pkg/provider/azure_loadbalancer.go#L3559
+	export.Spec.IsInternalLoadBalancer = service.Annotations[objectmeta.ServiceAnnotationAzureLoadBalancerInternal] == "true"
+	if export.Spec.IsInternalLoadBalancer {
+		// no need to populate the PublicIPResourceID and IsDNSLabelConfigured which are only applicable for external load balancer
+		return nil
+	}
+
+	serviceKObj := klog.KObj(service)
+	if len(service.Status.LoadBalancer.Ingress) == 0 {
+		// Assuming once the service status is updated, the controller will be triggered again.
+		klog.V(2).InfoS("The load balancer IP is not assigned yet", "service", serviceKObj)
+		return nil
+	}
+
+	if service.Status.LoadBalancer.Ingress[0].IP == "" {
+		err := errors.New("the service ingress is not nil but with empty IP")
+		klog.ErrorS(controller.NewUnexpectedBehaviorError(err), "Failed to get the load balancer IP from service", "service", serviceKObj, "status", service.Status)
+		return nil
+	}
+
+	pip, err := p.lookupPublicIPResourceIDByLoadBalancerIP(ctx, service)
+	if err != nil {
+		return err
+	}
+	if pip == nil {
+		klog.V(2).InfoS("The public IP is in the progressing", "service", serviceKObj, "ip", service.Status.LoadBalancer.Ingress[0].IP)
+		// Assuming once the service status is updated, the controller will be triggered again in instead of retrying here
+		// to avoid sending Azure requests.
+		return nil
+	}
+	export.Spec.PublicIPResourceID = pip.ID
+
+	// Note the user can set the dns label via the Azure portal or Azure CLI without updating service.
+	// This information may be stale as we don't monitor the public IP address resource.
+	export.Spec.IsDNSLabelConfigured = pip.Properties != nil && pip.Properties.DNSSettings != nil && pip.Properties.DNSSettings.DomainNameLabel != nil
+
+	// No matter if the customer bring your own IP or not, the cloud provider will reconcile the DNS label based on the
+	// DNS annotation.
+	dnsName, found := service.Annotations[objectmeta.ServiceAnnotationAzureDNSLabelName]
+	klog.V(2).InfoS("Finding whether the DNS is assigned", "service", serviceKObj, "dnsName", dnsName, "isSetOnService", found, "isConfiguredOnPIP", export.Spec.IsDNSLabelConfigured)
+	// If the annotation is not set, the cloud provider won't reconcile the DNS label and return the current status.
+	if !found {
+		// cloud provider won't delete DNS label on pip if the annotation is not set.
+		if export.Spec.IsDNSLabelConfigured || !p.ManagePublicIPDNSLabel {
+			return nil
+		}
+		if err := p.setPublicIPDNSLabel(ctx, service, pip); err != nil {
+			return err
+		}
+		export.Spec.IsDNSLabelConfigured = true
+		return nil
+	}
+	if len(dnsName) == 0 {
+		export.Spec.IsDNSLabelConfigured = false // cloud provider will delete the DNS label on the pip.
+		return nil
+	}
+	if !export.Spec.IsDNSLabelConfigured {
+		err = fmt.Errorf("in the process of adding DNS to the public ip address %s", *pip.ID)
+		klog.ErrorS(err, "Requeue the request to see if the DNS is ready or not", "service", serviceKObj)
+		return err
+	}
+	return nil
+}
+
+// SetPublicIPInformation implements the CloudProvider interface. The public IP address must be in the subscription of
+// the member cluster and have a DNS label configured, which is required by Azure Traffic Manager.
+func (p *AzureCloudProvider) SetPublicIPInformation(ctx context.Context, service *corev1.Service, publicIPResourceID string, export *fleetnetv1alpha1.InternalServiceExport) error {
+	export.Spec.Type = service.Spec.Type
+	resourceID, err := arm.ParseResourceID(publicIPResourceID)
+	if err != nil || !strings.EqualFold(resourceID.ResourceType.String(), azurePublicIPAddressResourceType) {
+		return fmt.Errorf("%w: %q is not the resource ID of an Azure public IP address", errInvalidPublicIP, publicIPResourceID)
+	}
+
+	pipClient, _, err := p.publicIPAddressClient()
+	if err != nil {
+		return err
+	}
+	serviceKObj := klog.KObj(service)
+	pip, err := pipClient.Get(ctx, resourceID.ResourceGroupName, resourceID.Name, nil)
+	switch {
+	case azureerrors.IsNotFound(err):
+		return fmt.Errorf("%w: public IP address %s is not found", errInvalidPublicIP, publicIPResourceID)
+	case err != nil:
+		klog.ErrorS(err, "Failed to get the Azure public IP address", "service", serviceKObj, "publicIPResourceID", publicIPResourceID)
+		return err
+	case pip == nil || pip.ID == nil:
+		// The client returns a nil public IP address with no error when it is not found.
+		return fmt.Errorf("%w: public IP address %s is not found", errInvalidPublicIP, publicIPResourceID)
+	}
+	if pip.Properties == nil || pip.Properties.DNSSettings == nil || pip.Properties.DNSSettings.DomainNameLabel == nil {
+		return fmt.Errorf("%w: DNS label is not configured to the public IP address %s", errInvalidPublicIP, publicIPResourceID)
+	}
+	klog.V(2).InfoS("Found the public IP address specified for the service", "service", serviceKObj, "publicIPResourceID", *pip.ID)
+	export.Spec.PublicIPResourceID = pip.ID
+	export.Spec.IsDNSLabelConfigured = true
+	export.Spec.IsPublicIPExplicit = true
+	return nil
+}
+
+// setPublicIPDNSLabel sets the DNS label derived from the member cluster and the Service on the public IP address,
+// trying the next candidate when the label is taken by another public IP address in the region. The labels are
+// deterministic, so that a retry after a failed or lost response sets the same label.
+func (p *AzureCloudProvider) setPublicIPDNSLabel(ctx context.Context, service *corev1.Service, pip *armnetwork.PublicIPAddress) error {
+	resourceID, err := arm.ParseResourceID(*pip.ID)
+	if err != nil {
+		klog.ErrorS(controller.NewUnexpectedBehaviorError(err), "Failed to parse the resource ID of the public IP address", "publicIPResourceID", *pip.ID)
+		return err
+	}
+	pipClient, _, err := p.publicIPAddressClient()
+	if err != nil {
+		return err
+	}
+	serviceKObj := klog.KObj(service)
+	for attempt := 0; attempt < maxPublicIPDNSLabelAttempts; attempt++ {
+		label := publicIPDNSLabel(p.ClusterID, service.Namespace, service.Name, attempt)
+		_, err = pipClient.CreateOrUpdate(ctx, resourceID.ResourceGroupName, resourceID.Name, withDNSLabel(pip, label))
+		switch {
+		case err == nil:
+			klog.V(2).InfoS("Set the DNS label on the public IP address", "service", serviceKObj, "publicIPResourceID", *pip.ID, "dnsLabel", label)
+			return nil
+		case azureerrors.IsDNSRecordInUse(err):
+			klog.V(2).InfoS("The DNS label is taken by another public IP address", "service", serviceKObj, "publicIPResourceID", *pip.ID, "dnsLabel", label)
+		case azureerrors.IsForbidden(err):
+			klog.ErrorS(err, "No permission to set the DNS label on the public IP address", "service", serviceKObj, "publicIPResourceID", *pip.ID)
+			return fmt.Errorf("%w %s: no permission to update the public IP address: %s", errDNSLabelNotSet, *pip.ID, azureerrors.Summary(err))
+		default:
+			klog.ErrorS(err, "Failed to set the DNS label on the public IP address", "service", serviceKObj, "publicIPResourceID", *pip.ID, "dnsLabel", label)
+			return err
+		}
+	}
+	return fmt.Errorf("%w %s: all the %d candidate DNS labels are taken by other public IP addresses", errDNSLabelNotSet, *pip.ID, maxPublicIPDNSLabelAttempts)
+}
+
+// publicIPDNSLabel returns the DNS label of the given attempt for the public IP address of the Service; it is the
+// hash of the member cluster ID, the namespace and the name of the Service (and the attempt after the first one), so
+// that it is unique across the fleet and a valid DNS label regardless of the length of the names.
+func publicIPDNSLabel(clusterID, namespace, name string, attempt int) string {
+	input := fmt.Sprintf("%s/%s/%s", clusterID, namespace, name)
+	if attempt > 0 {
+		input = fmt.Sprintf("%s/%d", input, attempt)
+	}
+	hash := sha256.Sum256([]byte(input))
+	return publicIPDNSLabelPrefix + hex.EncodeToString(hash[:])[:publicIPDNSLabelHashLength]
+}
+
+// withDNSLabel returns a copy of the public IP address with the DNS label set, leaving the other settings unchanged.
+func withDNSLabel(pip *armnetwork.PublicIPAddress, label string) armnetwork.PublicIPAddress {
+	updated := *pip
+	properties := armnetwork.PublicIPAddressPropertiesFormat{}
+	if pip.Properties != nil {
+		properties = *pip.Properties
+	}
+	dnsSettings := armnetwork.PublicIPAddressDNSSettings{}
+	if properties.DNSSettings != nil {
+		dnsSettings = *properties.DNSSettings
+	}
+	dnsSettings.DomainNameLabel = &label
+	properties.DNSSettings = &dnsSettings
+	updated.Properties = &properties
+	return updated
+}
+
+// TODO: can improve the performance by caching the public IP address resource ID.
+// Note: we don't support "service.beta.kubernetes.io/azure-pip-prefix-id" annotation, and public ip cannot be found in
+// this case.
+func (p *AzureCloudProvider) lookupPublicIPResourceIDByLoadBalancerIP(ctx context.Context, service *corev1.Service) (*armnetwork.PublicIPAddress, error) {
+	pipClient, defaultRG, err := p.publicIPAddressClient()
+	if err != nil {
+		return nil, err
+	}
+	// The customer can specify the resource group for the public IP address in the service annotation.
+	rg := strings.TrimSpace(service.Annotations[objectmeta.ServiceAnnotationLoadBalancerResourceGroup])
+	if len(rg) == 0 {
+		rg = defaultRG
+	}
+	serviceKObj := klog.KObj(service)
+	pips, err := pipClient.List(ctx, rg)
+	if err != nil {
+		klog.ErrorS(err, "Failed to list Azure public IP addresses", "service", serviceKObj, "resourceGroup", rg)
+		return nil, err
+	}
+	for _, pip := range pips {
+		if pip.Properties != nil && pip.Properties.IPAddress != nil &&
+			*pip.Properties.IPAddress == service.Status.LoadBalancer.Ingress[0].IP {
+			return pip, nil
+		}
+	}
+	klog.V(2).InfoS("The public IP address resource ID cannot be found in the public IP lists", "service", serviceKObj, "ip", service.Status.LoadBalancer.Ingress[0].IP, "resourceGroup", rg)
+	return nil, nil
+}
+
+// initAzureNetworkClients initializes the Azure network resource clients, currently only publicIPAddressClient.
+func initAzureNetworkClients(cloudConfig *azure.CloudConfig) (azureNetworkClients, error) {
+	authProvider, err := azclient.NewAuthProvider(&cloudConfig.ARMClientConfig, &cloudConfig.AzureAuthConfig)
+	if err != nil {
+		return azureNetworkClients{}, fmt.Errorf("failed to create Azure auth provider: %w", err)
+	}
+
+	factoryConfig := &azclient.ClientFactoryConfig{
+		CloudProviderBackoff: true,
+		SubscriptionID:       cloudConfig.SubscriptionID,
+	}
+	options, err := azclient.GetDefaultResourceClientOption(&cloudConfig.ARMClientConfig, factoryConfig)
+	if err != nil {
+		return azureNetworkClients{}, fmt.Errorf("failed to get default resource client option: %w", err)
+	}
+
+	if rateLimitPolicy := ratelimit.NewRateLimitPolicy(cloudConfig.Config); rateLimitPolicy != nil {
+		options.ClientOptions.PerCallPolicies = append(options.ClientOptions.PerCallPolicies, rateLimitPolicy)
+	}
+
+	pipClient, err := publicipaddressclient.New(cloudConfig.SubscriptionID, authProvider.GetAzIdentity(), options)
+	if err != nil {
+		return azureNetworkClients{}, fmt.Errorf("failed to create Azure PublicIPAddress client: %w", err)
+	}
+
+	return azureNetworkClients{
+		publicIPAddressClient: pipClient,
+		resourceGroupName:     cloudConfig.ResourceGroup,
+	}, nil
+}
@@ -0,0 +1,951 @@
+/*
+Copyright (c) Microsoft Corporation.
+Licensed under the MIT license.
+*/
+
+package serviceexport
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/network/armnetwork/v4"
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+
+	fleetnetv1alpha1 "go.goms.io/fleet-networking/api/v1alpha1"
+	"go.goms.io/fleet-networking/pkg/common/objectmeta"
+)
+
+const (
+	gatewayPublicIPResourceID = "/subscriptions/sub1/resourceGroups/valid-rg/providers/Microsoft.Network/publicIPAddresses/gateway-pip"
+)
+
+func TestNewCloudProvider(t *testing.T) {
+	tests := []struct {
+		name     string
+		provider string
+		want     CloudProvider
+		wantErr  bool
+	}{
+		{
+			name:     "azure",
+			provider: CloudProviderAzure,
+			want:     &AzureCloudProvider{CloudConfigFile: "azure.json", CloudConfigReloadInterval: time.Minute},
+		},
+		{
+			name:     "none",
+			provider: CloudProviderNone,
+			want:     NoneCloudProvider{},
+		},
+		{
+			name:     "unsupported",
+			provider: "gcp",
+			wantErr:  true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := NewCloudProvider(tt.provider, "azure.json", time.Minute)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("NewCloudProvider() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if diff := cmp.Diff(tt.want, got, cmpopts.IgnoreUnexported(AzureCloudProvider{})); diff != "" {
+				t.Errorf("NewCloudProvider() mismatch (-want, +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestNoneCloudProviderSetLoadBalancerInformation(t *testing.T) {
+	service := &corev1.Service{
+		Spec: corev1.ServiceSpec{
+			Type: corev1.ServiceTypeLoadBalancer,
+		},
+		Status: corev1.ServiceStatus{
+			LoadBalancer: corev1.LoadBalancerStatus{
+				Ingress: []corev1.LoadBalancerIngress{
+					{
+						IP: "1.2.3.4",
+					},
+				},
+			},
+		},
+	}
+	got := &fleetnetv1alpha1.InternalServiceExport{}
+	if err := (NoneCloudProvider{}).SetLoadBalancerInformation(context.Background(), service, got); err != nil {
+		t.Fatalf("SetLoadBalancerInformation() got error %v, want no error", err)
+	}
+	want := &fleetnetv1alpha1.InternalServiceExport{
+		Spec: fleetnetv1alpha1.InternalServiceExportSpec{
+			Type: corev1.ServiceTypeLoadBalancer,
+		},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("SetLoadBalancerInformation() internalServiceExport mismatch (-want, +got):\n%s", diff)
+	}
+}
+
+func TestNoneCloudProviderSetPublicIPInformation(t *testing.T) {
+	service := &corev1.Service{
+		Spec: corev1.ServiceSpec{
+			Type: corev1.ServiceTypeClusterIP,
+		},
+	}
+	got := &fleetnetv1alpha1.InternalServiceExport{}
+	err := (NoneCloudProvider{}).SetPublicIPInformation(context.Background(), service, gatewayPublicIPResourceID, got)
+	if !errors.Is(err, errInvalidPublicIP) {
+		t.Fatalf("SetPublicIPInformation() got error %v, want %v", err, errInvalidPublicIP)
+	}
+	want := &fleetnetv1alpha1.InternalServiceExport{
+		Spec: fleetnetv1alpha1.InternalServiceExportSpec{
+			Type: corev1.ServiceTypeClusterIP,
+		},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("SetPublicIPInformation() internalServiceExport mismatch (-want, +got):\n%s", diff)
+	}
+}
+
+func TestAzureCloudProviderSetPublicIPInformation(t *testing.T) {
+	getResponse := map[string]*armnetwork.PublicIPAddress{
+		"gateway-pip": {
+			ID: ptr.To(gatewayPublicIPResourceID),
+			Properties: &armnetwork.PublicIPAddressPropertiesFormat{
+				DNSSettings: &armnetwork.PublicIPAddressDNSSettings{
+					DomainNameLabel: ptr.To("gateway"),
+				},
+				IPAddress: ptr.To("1.2.3.4"),
+			},
+		},
+		"no-dns-pip": {
+			ID: ptr.To("/subscriptions/sub1/resourceGroups/valid-rg/providers/Microsoft.Network/publicIPAddresses/no-dns-pip"),
+			Properties: &armnetwork.PublicIPAddressPropertiesFormat{
+				IPAddress: ptr.To("1.2.3.5"),
+			},
+		},
+	}
+	service := &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "work",
+			Name:      "gateway-backed-svc",
+		},
+		Spec: corev1.ServiceSpec{
+			Type: corev1.ServiceTypeClusterIP,
+		},
+	}
+	tests := []struct {
+		name               string
+		publicIPResourceID string
+		getError           error
+		want               *fleetnetv1alpha1.InternalServiceExport
+		wantInvalid        bool
+		wantErr            bool
+	}{
+		{
+			name:               "valid public ip",
+			publicIPResourceID: gatewayPublicIPResourceID,
+			want: &fleetnetv1alpha1.InternalServiceExport{
+				Spec: fleetnetv1alpha1.InternalServiceExportSpec{
+					Type:                 corev1.ServiceTypeClusterIP,
+					PublicIPResourceID:   ptr.To(gatewayPublicIPResourceID),
+					IsDNSLabelConfigured: true,
+					IsPublicIPExplicit:   true,
+				},
+			},
+		},
+		{
+			name:               "public ip without dns label",
+			publicIPResourceID: "/subscriptions/sub1/resourceGroups/valid-rg/providers/Microsoft.Network/publicIPAddresses/no-dns-pip",
+			wantInvalid:        true,
+		},
+		{
+			name:               "nonexistent public ip",
+			publicIPResourceID: "/subscriptions/sub1/resourceGroups/valid-rg/providers/Microsoft.Network/publicIPAddresses/missing-pip",
+			wantInvalid:        true,
+		},
+		{
+			name:               "public ip in another resource group",
+			publicIPResourceID: "/subscriptions/sub1/resourceGroups/other-rg/providers/Microsoft.Network/publicIPAddresses/gateway-pip",
+			wantInvalid:        true,
+		},
+		{
+			name:               "not a public ip resource id",
+			publicIPResourceID: "/subscriptions/sub1/resourceGroups/valid-rg/providers/Microsoft.Network/loadBalancers/kubernetes",
+			wantInvalid:        true,
+		},
+		{
+			name:               "malformed resource id",
+			publicIPResourceID: "gateway-pip",
+			wantInvalid:        true,
+		},
+		{
+			name:               "failed to get the public ip",
+			publicIPResourceID: gatewayPublicIPResourceID,
+			getError:           &azcore.ResponseError{StatusCode: http.StatusInternalServerError},
+			wantErr:            true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := &AzureCloudProvider{
+				PublicIPAddressClient: &fakePublicIPAddressClient{GetResponse: getResponse, GetError: tt.getError},
+				ResourceGroupName:     validResourceGroup,
+			}
+			got := &fleetnetv1alpha1.InternalServiceExport{}
+			err := p.SetPublicIPInformation(context.Background(), service, tt.publicIPResourceID, got)
+			if gotInvalid := errors.Is(err, errInvalidPublicIP); gotInvalid != tt.wantInvalid {
+				t.Fatalf("SetPublicIPInformation() got error %v, want invalid public IP error %v", err, tt.wantInvalid)
+			}
+			if gotErr := err != nil && !tt.wantInvalid; gotErr != tt.wantErr {
+				t.Fatalf("SetPublicIPInformation() got error %v, want error %v", err, tt.wantErr)
+			}
+			if err != nil {
+				if got.Spec.PublicIPResourceID != nil || got.Spec.IsPublicIPExplicit {
+					t.Errorf("SetPublicIPInformation() populated the rejected public IP %+v", got.Spec)
+				}
+				return
+			}
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("SetPublicIPInformation() internalServiceExport mismatch (-want, +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestAzureCloudProviderLoadCloudConfig(t *testing.T) {
+	invalidCloudConfigFile := filepath.Join(t.TempDir(), "azure.json")
+	if err := os.WriteFile(invalidCloudConfigFile, []byte("{invalid"), 0600); err != nil {
+		t.Fatalf("WriteFile() got error %v, want no error", err)
+	}
+	tests := []struct {
+		name     string
+		provider *AzureCloudProvider
+		wantErr  bool
+	}{
+		{
+			name:     "missing cloud config file",
+			provider: &AzureCloudProvider{CloudConfigFile: filepath.Join(t.TempDir(), "missing.json")},
+			wantErr:  true,
+		},
+		{
+			name:     "invalid cloud config file",
+			provider: &AzureCloudProvider{CloudConfigFile: invalidCloudConfigFile},
+			wantErr:  true,
+		},
+		{
+			name: "client is set",
+			provider: &AzureCloudProvider{
+				CloudConfigFile:       filepath.Join(t.TempDir(), "missing.json"),
+				PublicIPAddressClient: &fakePublicIPAddressClient{},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.provider.LoadCloudConfig(); (err != nil) != tt.wantErr {
+				t.Errorf("LoadCloudConfig() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestAzureCloudProviderSetLoadBalancerInformation(t *testing.T) {
+	tests := []struct {
+		name                           string
+		service                        *corev1.Service
+		publicIPAddressListResponse    []*armnetwork.PublicIPAddress
+		publicIPAddressListResponseErr error
+		want                           *fleetnetv1alpha1.InternalServiceExport
+		wantErr                        bool
+	}{
+		{
+			name: "load balancer type with public ip and dns is assigned to pip",
+			service: &corev1.Service{
+				ObjectMeta: metav1.ObjectMeta{
+					UID: "uid",
+					Annotations: map[string]string{
+						objectmeta.ServiceAnnotationLoadBalancerResourceGroup: "   ",
+						objectmeta.ServiceAnnotationAzureLoadBalancerInternal: "True", // case sensitive
+					},
+				},
+				Spec: corev1.ServiceSpec{
+					Type: corev1.ServiceTypeLoadBalancer,
+				},
+				Status: corev1.ServiceStatus{
+					LoadBalancer: corev1.LoadBalancerStatus{
+						Ingress: []corev1.LoadBalancerIngress{
+							{
+								IP: "1.2.3.4",
+							},
+						},
+					},
+				},
+			},
+			publicIPAddressListResponse: []*armnetwork.PublicIPAddress{
+				{
+					Properties: &armnetwork.PublicIPAddressPropertiesFormat{
+						DNSSettings: &armnetwork.PublicIPAddressDNSSettings{
+							DomainNameLabel: ptr.To("dnsLabel"),
+						},
+						IPAddress: ptr.To("1.2.3.4"),
+					},
+					ID: ptr.To("/subscriptions/sub1/resourceGroups/rg/providers/Microsoft.Network/publicIPAddresses/pip"),
+				},
+				{
+					Properties: &armnetwork.PublicIPAddressPropertiesFormat{
+						IPAddress: ptr.To("1.2.5.6"),
+					},
+				},
+			},
+			want: &fleetnetv1alpha1.InternalServiceExport{
+				Spec: fleetnetv1alpha1.InternalServiceExportSpec{
+					Type:                   corev1.ServiceTypeLoadBalancer,
+					IsDNSLabelConfigured:   true,
+					IsInternalLoadBalancer: false,
+					PublicIPResourceID:     ptr.To("/subscriptions/sub1/resourceGroups/rg/providers/Microsoft.Network/publicIPAddresses/pip"),
+				},
+			},
+		},
+		{
+			name: "load balancer type with public ip and dns label is not set and dns is not assigned to pip",
+			service: &corev1.Service{
+				ObjectMeta: metav1.ObjectMeta{
+					UID: "uid",
+				},
+				Spec: corev1.ServiceSpec{
+					Type: corev1.ServiceTypeLoadBalancer,
+				},
+				Status: corev1.ServiceStatus{
+					LoadBalancer: corev1.LoadBalancerStatus{
+						Ingress: []corev1.LoadBalancerIngress{
+							{
+								IP: "1.2.3.4",
+							},
+						},
+					},
+				},
+			},
+			publicIPAddressListResponse: []*armnetwork.PublicIPAddress{
+				{
+					Properties: &armnetwork.PublicIPAddressPropertiesFormat{
+						DNSSettings: &armnetwork.PublicIPAddressDNSSettings{},
+						IPAddress:   ptr.To("1.2.3.4"),
+					},
+					ID: ptr.To("/subscriptions/sub1/resourceGroups/rg/providers/Microsoft.Network/publicIPAddresses/pip"),
+				},
+				{
+					Properties: &armnetwork.PublicIPAddressPropertiesFormat{},
+				},
+			},
+			want: &fleetnetv1alpha1.InternalServiceExport{
+				Spec: fleetnetv1alpha1.InternalServiceExportSpec{
+					Type:               corev1.ServiceTypeLoadBalancer,
+					PublicIPResourceID: ptr.To("/subscriptions/sub1/resourceGroups/rg/providers/Microsoft.Network/publicIPAddresses/pip"),
+				},
+			},
+		},
+		{
+			name: "load balancer type with public ip and dns label and dns label is not assigned in pip",
+			service: &corev1.Service{
+				ObjectMeta: metav1.ObjectMeta{
+					UID: "uid",
+					Annotations: map[string]string{
+						objectmeta.ServiceAnnotationLoadBalancerResourceGroup: "   ",
+						objectmeta.ServiceAnnotationAzureLoadBalancerInternal: "True", // case sensitive
+						objectmeta.ServiceAnnotationAzureDNSLabelName:         "dnsLabel",
+					},
+				},
+				Spec: corev1.ServiceSpec{
+					Type: corev1.ServiceTypeLoadBalancer,
+				},
+				Status: corev1.ServiceStatus{
+					LoadBalancer: corev1.LoadBalancerStatus{
+						Ingress: []corev1.LoadBalancerIngress{
+							{
+								IP: "1.2.3.4",
+							},
+						},
+					},
+				},
+			},
+			publicIPAddressListResponse: []*armnetwork.PublicIPAddress{
+				{
+					Properties: &armnetwork.PublicIPAddressPropertiesFormat{
+						DNSSettings: &armnetwork.PublicIPAddressDNSSettings{},
+						IPAddress:   ptr.To("1.2.3.4"),
+					},
+					ID: ptr.To("/subscriptions/sub1/resourceGroups/rg/providers/Microsoft.Network/publicIPAddresses/pip"),
+				},
+				{
+					Properties: &armnetwork.PublicIPAddressPropertiesFormat{
+						IPAddress: ptr.To("1.2.5.6"),
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "load balancer type with public ip and dns label and dns is assigned to pip",
+			service: &corev1.Service{
+				ObjectMeta: metav1.ObjectMeta{
+					UID: "uid",
+					Annotations: map[string]string{
+						objectmeta.ServiceAnnotationLoadBalancerResourceGroup: "   ",
+						objectmeta.ServiceAnnotationAzureLoadBalancerInternal: "True", // case sensitive
+						objectmeta.ServiceAnnotationAzureDNSLabelName:         "dnsLabel",
+					},
+				},
+				Spec: corev1.ServiceSpec{
+					Type: corev1.ServiceTypeLoadBalancer,
+				},
+				Status: corev1.ServiceStatus{
+					LoadBalancer: corev1.LoadBalancerStatus{
+						Ingress: []corev1.LoadBalancerIngress{
+							{
+								IP: "1.2.3.4",
+							},
+						},
+					},
+				},
+			},
+			publicIPAddressListResponse: []*armnetwork.PublicIPAddress{
+				{
+					Properties: &armnetwork.PublicIPAddressPropertiesFormat{
+						DNSSettings: &armnetwork.PublicIPAddressDNSSettings{
+							DomainNameLabel: ptr.To("dnsLabel"),
+						},
+						IPAddress: ptr.To("1.2.3.4"),
+					},
+					ID: ptr.To("/subscriptions/sub1/resourceGroups/rg/providers/Microsoft.Network/publicIPAddresses/pip"),
+				},
+				{
+					Properties: &armnetwork.PublicIPAddressPropertiesFormat{
+						IPAddress: ptr.To("1.2.5.6"),
+					},
+				},
+			},
+			want: &fleetnetv1alpha1.InternalServiceExport{
+				Spec: fleetnetv1alpha1.InternalServiceExportSpec{
+					Type:                   corev1.ServiceTypeLoadBalancer,
+					IsDNSLabelConfigured:   true,
+					IsInternalLoadBalancer: false,
+					PublicIPResourceID:     ptr.To("/subscriptions/sub1/resourceGroups/rg/providers/Microsoft.Network/publicIPAddresses/pip"),
+				},
+			},
+		},
+		{
+			name: "load balancer type with public ip and empty dns label and dns is assigned to pip",
+			service: &corev1.Service{
+				ObjectMeta: metav1.ObjectMeta{
+					UID: "uid",
+					Annotations: map[string]string{
+						objectmeta.ServiceAnnotationLoadBalancerResourceGroup: "   ",
+						objectmeta.ServiceAnnotationAzureLoadBalancerInternal: "True", // case sensitive
+						objectmeta.ServiceAnnotationAzureDNSLabelName:         "",
+					},
+				},
+				Spec: corev1.ServiceSpec{
+					Type: corev1.ServiceTypeLoadBalancer,
+				},
+				Status: corev1.ServiceStatus{
+					LoadBalancer: corev1.LoadBalancerStatus{
+						Ingress: []corev1.LoadBalancerIngress{
+							{
+								IP: "1.2.3.4",
+							},
+						},
+					},
+				},
+			},
+			publicIPAddressListResponse: []*armnetwork.PublicIPAddress{
+				{
+					Properties: &armnetwork.PublicIPAddressPropertiesFormat{
+						DNSSettings: &armnetwork.PublicIPAddressDNSSettings{
+							DomainNameLabel: ptr.To("dnsLabel"),
+						},
+						IPAddress: ptr.To("1.2.3.4"),
+					},
+					ID: ptr.To("/subscriptions/sub1/resourceGroups/rg/providers/Microsoft.Network/publicIPAddresses/pip"),
+				},
+				{
+					Properties: &armnetwork.PublicIPAddressPropertiesFormat{
+						IPAddress: ptr.To("1.2.5.6"),
+					},
+				},
+			},
+			want: &fleetnetv1alpha1.InternalServiceExport{
+				Spec: fleetnetv1alpha1.InternalServiceExportSpec{
+					Type:                   corev1.ServiceTypeLoadBalancer,
+					IsDNSLabelConfigured:   false,
+					IsInternalLoadBalancer: false,
+					PublicIPResourceID:     ptr.To("/subscriptions/sub1/resourceGroups/rg/providers/Microsoft.Network/publicIPAddresses/pip"),
+				},
+			},
+		},
+		{
+			name: "load balancer type with public ip and empty dns label and dns is not assigned to pip",
+			service: &corev1.Service{
+				ObjectMeta: metav1.ObjectMeta{
+					UID: "uid",
+					Annotations: map[string]string{
+						objectmeta.ServiceAnnotationLoadBalancerResourceGroup: "   ",
+						objectmeta.ServiceAnnotationAzureLoadBalancerInternal: "True", // case sensitive
+						objectmeta.ServiceAnnotationAzureDNSLabelName:         "",
+					},
+				},
+				Spec: corev1.ServiceSpec{
+					Type: corev1.ServiceTypeLoadBalancer,
+				},
+				Status: corev1.ServiceStatus{
+					LoadBalancer: corev1.LoadBalancerStatus{
+						Ingress: []corev1.LoadBalancerIngress{
+							{
+								IP: "1.2.3.4",
+							},
+						},
+					},
+				},
+			},
+			publicIPAddressListResponse: []*armnetwork.PublicIPAddress{
+				{
+					Properties: &armnetwork.PublicIPAddressPropertiesFormat{
+						IPAddress: ptr.To("1.2.3.4"),
+					},
+					ID: ptr.To("/subscriptions/sub1/resourceGroups/rg/providers/Microsoft.Network/publicIPAddresses/pip"),
+				},
+				{
+					Properties: &armnetwork.PublicIPAddressPropertiesFormat{
+						IPAddress: ptr.To("1.2.5.6"),
+					},
+				},
+			},
+			want: &fleetnetv1alpha1.InternalServiceExport{
+				Spec: fleetnetv1alpha1.InternalServiceExportSpec{
+					Type:                   corev1.ServiceTypeLoadBalancer,
+					IsDNSLabelConfigured:   false,
+					IsInternalLoadBalancer: false,
+					PublicIPResourceID:     ptr.To("/subscriptions/sub1/resourceGroups/rg/providers/Microsoft.Network/publicIPAddresses/pip"),
+				},
+			},
+		},
+		{
+			name: "load balancer type with internal ip",
+			service: &corev1.Service{
+				ObjectMeta: metav1.ObjectMeta{
+					UID: "uid",
+					Annotations: map[string]string{
+						objectmeta.ServiceAnnotationAzureLoadBalancerInternal: "true",
+					},
+				},
+				Spec: corev1.ServiceSpec{
+					Type: corev1.ServiceTypeLoadBalancer,
+				},
+			},
+			want: &fleetnetv1alpha1.InternalServiceExport{
+				Spec: fleetnetv1alpha1.InternalServiceExportSpec{
+					Type:                   corev1.ServiceTypeLoadBalancer,
+					IsInternalLoadBalancer: true,
+				},
+			},
+		},
+		{
+			name: "NodePort type service",
+			service: &corev1.Service{
+				ObjectMeta: metav1.ObjectMeta{
+					UID: "uid",
+					Annotations: map[string]string{
+						objectmeta.ServiceAnnotationAzureLoadBalancerInternal: "true",
+					},
+				},
+				Spec: corev1.ServiceSpec{
+					Type: corev1.ServiceTypeNodePort,
+				},
+			},
+			want: &fleetnetv1alpha1.InternalServiceExport{
+				Spec: fleetnetv1alpha1.InternalServiceExportSpec{
+					Type: corev1.ServiceTypeNodePort,
+				},
+			},
+		},
+		{
+			name: "error when getting public ip resource",
+			service: &corev1.Service{
+				ObjectMeta: metav1.ObjectMeta{
+					UID: "uid",
+				},
+				Spec: corev1.ServiceSpec{
+					Type: corev1.ServiceTypeLoadBalancer,
+				},
+				Status: corev1.ServiceStatus{
+					LoadBalancer: corev1.LoadBalancerStatus{
+						Ingress: []corev1.LoadBalancerIngress{
+							{
+								IP: "1.2.3.4",
+							},
+						},
+					},
+				},
+			},
+			publicIPAddressListResponseErr: errors.New("error"),
+			wantErr:                        true,
+		},
+		{
+			name: "stale service ingress ip",
+			service: &corev1.Service{
+				ObjectMeta: metav1.ObjectMeta{
+					UID: "uid",
+				},
+				Spec: corev1.ServiceSpec{
+					Type: corev1.ServiceTypeLoadBalancer,
+				},
+				Status: corev1.ServiceStatus{
+					LoadBalancer: corev1.LoadBalancerStatus{
+						Ingress: []corev1.LoadBalancerIngress{
+							{
+								IP: "1.2.3.4",
+							},
+						},
+					},
+				},
+			},
+			publicIPAddressListResponse: []*armnetwork.PublicIPAddress{
+				{
+					Properties: &armnetwork.PublicIPAddressPropertiesFormat{
+						DNSSettings: &armnetwork.PublicIPAddressDNSSettings{},
+						IPAddress:   ptr.To("1.2.3.7"),
+					},
+					ID: ptr.To("/subscriptions/sub1/resourceGroups/rg/providers/Microsoft.Network/publicIPAddresses/pip"),
+				},
+				{
+					Properties: &armnetwork.PublicIPAddressPropertiesFormat{},
+				},
+			},
+			want: &fleetnetv1alpha1.InternalServiceExport{
+				Spec: fleetnetv1alpha1.InternalServiceExportSpec{
+					Type: corev1.ServiceTypeLoadBalancer,
+				},
+			},
+		},
+		{
+			name: "service ingress ip is not set",
+			service: &corev1.Service{
+				ObjectMeta: metav1.ObjectMeta{
+					UID: "uid",
+				},
+				Spec: corev1.ServiceSpec{
+					Type: corev1.ServiceTypeLoadBalancer,
+				},
+			},
+			want: &fleetnetv1alpha1.InternalServiceExport{
+				Spec: fleetnetv1alpha1.InternalServiceExportSpec{
+					Type: corev1.ServiceTypeLoadBalancer,
+				},
+			},
+		},
+		{
+			name: "service ingress ip is set but empty",
+			service: &corev1.Service{
+				ObjectMeta: metav1.ObjectMeta{
+					UID: "uid",
+				},
+				Spec: corev1.ServiceSpec{
+					Type: corev1.ServiceTypeLoadBalancer,
+				},
+				Status: corev1.ServiceStatus{
+					LoadBalancer: corev1.LoadBalancerStatus{
+						Ingress: []corev1.LoadBalancerIngress{
+							{
+								IP: "",
+							},
+						},
+					},
+				},
+			},
+			want: &fleetnetv1alpha1.InternalServiceExport{
+				Spec: fleetnetv1alpha1.InternalServiceExportSpec{
+					Type: corev1.ServiceTypeLoadBalancer,
+				},
+			},
+		},
+		{
+			name: "invalid load balancer resource group",
+			service: &corev1.Service{
+				ObjectMeta: metav1.ObjectMeta{
+					UID: "uid",
+					Annotations: map[string]string{
+						objectmeta.ServiceAnnotationLoadBalancerResourceGroup: "invalid",
+					},
+				},
+				Spec: corev1.ServiceSpec{
+					Type: corev1.ServiceTypeLoadBalancer,
+				},
+				Status: corev1.ServiceStatus{
+					LoadBalancer: corev1.LoadBalancerStatus{
+						Ingress: []corev1.LoadBalancerIngress{
+							{
+								IP: "1.2.3.4",
+							},
+						},
+					},
+				},
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := &AzureCloudProvider{
+				PublicIPAddressClient: &fakePublicIPAddressClient{ListResponse: tt.publicIPAddressListResponse, ListError: tt.publicIPAddressListResponseErr},
+				ResourceGroupName:     validResourceGroup,
+			}
+			got := &fleetnetv1alpha1.InternalServiceExport{}
+			err := p.SetLoadBalancerInformation(context.Background(), tt.service, got)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("SetLoadBalancerInformation() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if err != nil {
+				return
+			}
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("SetLoadBalancerInformation() internalServiceExport mismatch (-want, +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+// TestAzureCloudProviderSetLoadBalancerInformation_ManagePublicIPDNSLabel tests that the DNS label is set on the public
+// IP address of the load balancer when the DNS labels are managed.
+func TestAzureCloudProviderSetLoadBalancerInformation_ManagePublicIPDNSLabel(t *testing.T) {
+	pipID := "/subscriptions/sub1/resourceGroups/pip-rg/providers/Microsoft.Network/publicIPAddresses/pip"
+	firstLabel := publicIPDNSLabel("member-1", "work", "app", 0)
+	secondLabel := publicIPDNSLabel("member-1", "work", "app", 1)
+	newPIP := func(dnsLabel *string) *armnetwork.PublicIPAddress {
+		pip := &armnetwork.PublicIPAddress{
+			ID:       ptr.To(pipID),
+			Location: ptr.To("westus"),
+			Properties: &armnetwork.PublicIPAddressPropertiesFormat{
+				IPAddress: ptr.To("1.2.3.4"),
+			},
+		}
+		if dnsLabel != nil {
+			pip.Properties.DNSSettings = &armnetwork.PublicIPAddressDNSSettings{DomainNameLabel: dnsLabel}
+		}
+		return pip
+	}
+	forbiddenErr := &azcore.ResponseError{StatusCode: http.StatusForbidden, ErrorCode: "AuthorizationFailed"}
+	inUseErr := &azcore.ResponseError{StatusCode: http.StatusBadRequest, ErrorCode: "DnsRecordInUse"}
+
+	tests := []struct {
+		name                   string
+		annotations            map[string]string
+		manage                 bool
+		pip                    *armnetwork.PublicIPAddress
+		createOrUpdateErrors   map[string]error
+		wantIsDNSLabelSet      bool
+		wantDNSLabelNotSet     bool
+		wantErr                bool
+		wantCreateOrUpdateDNSs []string
+	}{
+		{
+			name:                   "should set the dns label",
+			manage:                 true,
+			pip:                    newPIP(nil),
+			wantIsDNSLabelSet:      true,
+			wantCreateOrUpdateDNSs: []string{firstLabel},
+		},
+		{
+			name:              "should not update the pip with the dns label already set",
+			manage:            true,
+			pip:               newPIP(ptr.To("existing")),
+			wantIsDNSLabelSet: true,
+		},
+		{
+			name: "should not set the dns label when it is not managed",
+			pip:  newPIP(nil),
+		},
+		{
+			name:   "should leave the dns label to the cloud provider when the service requests one",
+			manage: true,
+			annotations: map[string]string{
+				objectmeta.ServiceAnnotationAzureDNSLabelName: "requested",
+			},
+			pip:     newPIP(nil),
+			wantErr: true,
+		},
+		{
+			name:                   "should report the missing permission",
+			manage:                 true,
+			pip:                    newPIP(nil),
+			createOrUpdateErrors:   map[string]error{firstLabel: forbiddenErr},
+			wantDNSLabelNotSet:     true,
+			wantCreateOrUpdateDNSs: []string{firstLabel},
+		},
+		{
+			name:                   "should try the next dns label when it is taken",
+			manage:                 true,
+			pip:                    newPIP(nil),
+			createOrUpdateErrors:   map[string]error{firstLabel: inUseErr},
+			wantIsDNSLabelSet:      true,
+			wantCreateOrUpdateDNSs: []string{firstLabel, secondLabel},
+		},
+		{
+			name:   "should report that all the dns labels are taken",
+			manage: true,
+			pip:    newPIP(nil),
+			createOrUpdateErrors: map[string]error{
+				firstLabel:  inUseErr,
+				secondLabel: inUseErr,
+				publicIPDNSLabel("member-1", "work", "app", 2): inUseErr,
+			},
+			wantDNSLabelNotSet:     true,
+			wantCreateOrUpdateDNSs: []string{firstLabel, secondLabel, publicIPDNSLabel("member-1", "work", "app", 2)},
+		},
+		{
+			name:                   "should retry on the other errors",
+			manage:                 true,
+			pip:                    newPIP(nil),
+			createOrUpdateErrors:   map[string]error{firstLabel: &azcore.ResponseError{StatusCode: http.StatusInternalServerError}},
+			wantErr:                true,
+			wantCreateOrUpdateDNSs: []string{firstLabel},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := &corev1.Service{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace:   "work",
+					Name:        "app",
+					Annotations: tt.annotations,
+				},
+				Spec: corev1.ServiceSpec{
+					Type: corev1.ServiceTypeLoadBalancer,
+				},
+				Status: corev1.ServiceStatus{
+					LoadBalancer: corev1.LoadBalancerStatus{
+						Ingress: []corev1.LoadBalancerIngress{{IP: "1.2.3.4"}},
+					},
+				},
+			}
+			pipClient := &fakePublicIPAddressClient{
+				ListResponse:         []*armnetwork.PublicIPAddress{tt.pip},
+				CreateOrUpdateErrors: tt.createOrUpdateErrors,
+			}
+			p := &AzureCloudProvider{
+				PublicIPAddressClient:  pipClient,
+				ResourceGroupName:      validResourceGroup,
+				ManagePublicIPDNSLabel: tt.manage,
+				ClusterID:              "member-1",
+			}
+			hadNoDNSSettings := tt.pip.Properties.DNSSettings == nil
+			got := &fleetnetv1alpha1.InternalServiceExport{}
+			err := p.SetLoadBalancerInformation(context.Background(), service, got)
+			if gotDNSLabelNotSet := errors.Is(err, errDNSLabelNotSet); gotDNSLabelNotSet != tt.wantDNSLabelNotSet {
+				t.Fatalf("SetLoadBalancerInformation() got error %v, want DNS label not set error %v", err, tt.wantDNSLabelNotSet)
+			}
+			if gotErr := err != nil && !tt.wantDNSLabelNotSet; gotErr != tt.wantErr {
+				t.Fatalf("SetLoadBalancerInformation() got error %v, want error %v", err, tt.wantErr)
+			}
+			if !tt.wantErr {
+				want := &fleetnetv1alpha1.InternalServiceExport{
+					Spec: fleetnetv1alpha1.InternalServiceExportSpec{
+						Type:                 corev1.ServiceTypeLoadBalancer,
+						PublicIPResourceID:   ptr.To(pipID),
+						IsDNSLabelConfigured: tt.wantIsDNSLabelSet,
+					},
+				}
+				if diff := cmp.Diff(want, got); diff != "" {
+					t.Errorf("SetLoadBalancerInformation() internalServiceExport mismatch (-want, +got):\n%s", diff)
+				}
+			}
+
+			var gotDNSs []string
+			for _, updated := range pipClient.CreateOrUpdateRequests {
+				if diff := cmp.Diff(ptr.To("westus"), updated.Location); diff != "" {
+					t.Errorf("CreateOrUpdate() location mismatch (-want, +got):\n%s", diff)
+				}
+				gotDNSs = append(gotDNSs, *updated.Properties.DNSSettings.DomainNameLabel)
+			}
+			if diff := cmp.Diff(tt.wantCreateOrUpdateDNSs, gotDNSs); diff != "" {
+				t.Errorf("CreateOrUpdate() dns labels mismatch (-want, +got):\n%s", diff)
+			}
+			if hadNoDNSSettings && tt.pip.Properties.DNSSettings != nil {
+				t.Errorf("SetLoadBalancerInformation() changed the listed public IP address, want a copy updated")
+			}
+		})
+	}
+}
+
+// TestPublicIPDNSLabel tests the publicIPDNSLabel function.
+func TestPublicIPDNSLabel(t *testing.T) {
+	got := publicIPDNSLabel("member-1", "work", "app", 0)
+	if !strings.HasPrefix(got, publicIPDNSLabelPrefix) || len(got) != len(publicIPDNSLabelPrefix)+publicIPDNSLabelHashLength {
+		t.Errorf("publicIPDNSLabel() = %q, want %q followed by %d hex characters", got, publicIPDNSLabelPrefix, publicIPDNSLabelHashLength)
+	}
+	if again := publicIPDNSLabel("member-1", "work", "app", 0); again != got {
+		t.Errorf("publicIPDNSLabel() = %q, want the same label %q", again, got)
+	}
+	for _, other := range []string{
+		publicIPDNSLabel("member-2", "work", "app", 0),
+		publicIPDNSLabel("member-1", "work-app", "", 0),
+		publicIPDNSLabel("member-1", "work", "app", 1),
+	} {
+		if other == got {
+			t.Errorf("publicIPDNSLabel() = %q for different inputs, want different labels", got)
+		}
+	}
+}
+
+type fakePublicIPAddressClient struct {
+	ListResponse []*armnetwork.PublicIPAddress
+	ListError    error
+	// GetResponse is the public IP addresses of the valid resource group keyed by their names.
+	GetResponse map[string]*armnetwork.PublicIPAddress
+	GetError    error
+	// CreateOrUpdateErrors is the errors returned for the DNS labels set on the public IP addresses.
+	CreateOrUpdateErrors map[string]error
+	// CreateOrUpdateRequests records the public IP addresses sent to CreateOrUpdate.
+	CreateOrUpdateRequests []armnetwork.PublicIPAddress
+}
+
+func (c *fakePublicIPAddressClient) Get(_ context.Context, rg string, name string, _ *string) (*armnetwork.PublicIPAddress, error) {
+	if c.GetError != nil {
+		return nil, c.GetError
+	}
+	if pip, ok := c.GetResponse[name]; ok && rg == validResourceGroup {
+		return pip, nil
+	}
+	return nil, &azcore.ResponseError{StatusCode: http.StatusNotFound}
+}
+
+func (c *fakePublicIPAddressClient) CreateOrUpdate(_ context.Context, _ string, _ string, pip armnetwork.PublicIPAddress) (*armnetwork.PublicIPAddress, error) {
+	c.CreateOrUpdateRequests = append(c.CreateOrUpdateRequests, pip)
+	if pip.Properties != nil && pip.Properties.DNSSettings != nil && pip.Properties.DNSSettings.DomainNameLabel != nil {
+		if err := c.CreateOrUpdateErrors[*pip.Properties.DNSSettings.DomainNameLabel]; err != nil {
+			return nil, err
+		}
+	}
+	return &pip, nil
+}
+
+func (c *fakePublicIPAddressClient) Delete(_ context.Context, _ string, _ string) error {
+	return nil
+}
+
+func (c *fakePublicIPAddressClient) List(_ context.Context, rg string) ([]*armnetwork.PublicIPAddress, error) {
+	if rg == validResourceGroup {
+		return c.ListResponse, c.ListError
+	}
+	return nil, errors.New("invalid resource group")
+}
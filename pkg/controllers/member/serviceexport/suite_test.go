@@ -8,24 +8,29 @@ package serviceexport
 import (
 	"context"
 	"path/filepath"
+	"sync/atomic"
 	"testing"
 
 	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/network/armnetwork/v4"
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/kubernetes/scheme"
 	"k8s.io/klog/v2"
 	"k8s.io/utils/ptr"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
 	"sigs.k8s.io/controller-runtime/pkg/envtest"
 	"sigs.k8s.io/controller-runtime/pkg/log"
 	"sigs.k8s.io/controller-runtime/pkg/log/zap"
 	metricsserver "sigs.k8s.io/controller-runtime/pkg/metrics/server"
 
 	fleetnetv1alpha1 "go.goms.io/fleet-networking/api/v1alpha1"
+	"go.goms.io/fleet-networking/pkg/common/cloudconfig"
+	"go.goms.io/fleet-networking/pkg/common/hubconfig"
 )
 
 var (
@@ -35,24 +40,83 @@ var (
 	hubClient     client.Client
 	ctx           context.Context
 	cancel        context.CancelFunc
+
+	// hubWrites counts the writes the controller sends to the hub cluster.
+	hubWrites = &hubWriteCounter{}
 )
 
+// hubWriteCounter counts the writes sent to the hub cluster, deletions apart from the other writes, and the writes
+// rejected for a conflict.
+type hubWriteCounter struct {
+	writes    atomic.Int64
+	deletes   atomic.Int64
+	conflicts atomic.Int64
+}
+
+// result counts the error of a write if it is a conflict, and returns it.
+func (c *hubWriteCounter) result(err error) error {
+	if errors.IsConflict(err) {
+		c.conflicts.Add(1)
+	}
+	return err
+}
+
+// funcs returns the interceptor functions which count the writes.
+func (c *hubWriteCounter) funcs() interceptor.Funcs {
+	return interceptor.Funcs{
+		Create: func(ctx context.Context, cl client.WithWatch, obj client.Object, opts ...client.CreateOption) error {
+			c.writes.Add(1)
+			return c.result(cl.Create(ctx, obj, opts...))
+		},
+		Update: func(ctx context.Context, cl client.WithWatch, obj client.Object, opts ...client.UpdateOption) error {
+			c.writes.Add(1)
+			return c.result(cl.Update(ctx, obj, opts...))
+		},
+		Patch: func(ctx context.Context, cl client.WithWatch, obj client.Object, patch client.Patch, opts ...client.PatchOption) error {
+			c.writes.Add(1)
+			return c.result(cl.Patch(ctx, obj, patch, opts...))
+		},
+		SubResourceUpdate: func(ctx context.Context, cl client.Client, subResourceName string, obj client.Object, opts ...client.SubResourceUpdateOption) error {
+			c.writes.Add(1)
+			return c.result(cl.SubResource(subResourceName).Update(ctx, obj, opts...))
+		},
+		SubResourcePatch: func(ctx context.Context, cl client.Client, subResourceName string, obj client.Object, patch client.Patch, opts ...client.SubResourcePatchOption) error {
+			c.writes.Add(1)
+			return c.result(cl.SubResource(subResourceName).Patch(ctx, obj, patch, opts...))
+		},
+		Delete: func(ctx context.Context, cl client.WithWatch, obj client.Object, opts ...client.DeleteOption) error {
+			c.deletes.Add(1)
+			return c.result(cl.Delete(ctx, obj, opts...))
+		},
+	}
+}
+
 // setUpResources help set up resources in the test environment.
 func setUpResources() {
 	// Add the namespaces.
 	memberNS := corev1.Namespace{
 		ObjectMeta: metav1.ObjectMeta{
-			Name: memberUserNS,
+			Name:   memberUserNS,
+			Labels: requiredNamespaceLabelsForTest,
 		},
 	}
 	Expect(memberClient.Create(ctx, &memberNS)).Should(Succeed())
 
-	hubNS := corev1.Namespace{
+	notOnboardedMemberNS := corev1.Namespace{
 		ObjectMeta: metav1.ObjectMeta{
-			Name: hubNSForMember,
+			Name: notOnboardedMemberUserNS,
 		},
 	}
-	Expect(hubClient.Create(ctx, &hubNS)).Should(Succeed())
+	Expect(memberClient.Create(ctx, &notOnboardedMemberNS)).Should(Succeed())
+
+	for _, ns := range hubconfig.ExportNamespaces(hubNSForMember, hubExportShards) {
+		hubNS := corev1.Namespace{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: ns,
+			},
+		}
+		Expect(hubClient.Create(ctx, &hubNS)).Should(Succeed())
+	}
 }
 
 func TestAPIs(t *testing.T) {
@@ -101,6 +165,10 @@ var _ = BeforeSuite(func() {
 	// Set up resources.
 	setUpResources()
 
+	// The controller writes to the hub cluster with a client which counts the writes.
+	hubWatchClient, err := client.NewWithWatch(hubCfg, client.Options{Scheme: scheme.Scheme})
+	Expect(err).NotTo(HaveOccurred())
+
 	// Start up the InternalServiceExport controller.
 	ctrlMgr, err := ctrl.NewManager(memberCfg, ctrl.Options{
 		Scheme: scheme.Scheme,
@@ -128,14 +196,19 @@ var _ = BeforeSuite(func() {
 	}
 
 	err = (&Reconciler{
-		MemberClusterID:             memberClusterID,
-		MemberClient:                memberClient,
-		HubClient:                   hubClient,
-		HubNamespace:                hubNSForMember,
-		Recorder:                    ctrlMgr.GetEventRecorderFor(ControllerName),
-		AzurePublicIPAddressClient:  &fakePublicIPAddressClient{ListResponse: publicIPAddressListResponse},
-		ResourceGroupName:           validResourceGroup,
+		MemberClusterID: memberClusterID,
+		MemberClient:    memberClient,
+		HubClient:       interceptor.NewClient(hubWatchClient, hubWrites.funcs()),
+		HubNamespace:    hubNSForMember,
+		HubExportShards: hubExportShards,
+		Recorder:        ctrlMgr.GetEventRecorderFor(ControllerName),
+		CloudProvider: &AzureCloudProvider{
+			PublicIPAddressClient: &fakePublicIPAddressClient{ListResponse: publicIPAddressListResponse},
+			ResourceGroupName:     validResourceGroup,
+		},
 		EnableTrafficManagerFeature: true,
+		RequiredNamespaceLabels:     requiredNamespaceLabelsForTest,
+		NetworkProperties:           cloudconfig.NetworkProperties{Region: memberRegion},
 	}).SetupWithManager(ctrlMgr)
 	Expect(err).NotTo(HaveOccurred())
 
@@ -9,13 +9,18 @@ import (
 	"fmt"
 
 	corev1 "k8s.io/api/core/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
+	"k8s.io/apimachinery/pkg/labels"
 
 	fleetnetv1alpha1 "go.goms.io/fleet-networking/api/v1alpha1"
+	"go.goms.io/fleet-networking/pkg/common/objectmeta"
+	"go.goms.io/fleet-networking/pkg/common/uniquename"
 )
 
-// formatInternalServiceExportName returns the unique name assigned to an exported Service.
+// formatInternalServiceExportName returns the unique name assigned to an exported Service; the name is truncated
+// deterministically if the namespace and the name of the Service are too long to fit in an object name.
 func formatInternalServiceExportName(svcExport *fleetnetv1alpha1.ServiceExport) string {
-	return fmt.Sprintf("%s-%s", svcExport.Namespace, svcExport.Name)
+	return uniquename.ClusterScopedDeterministicName(svcExport.Namespace, svcExport.Name)
 }
 
 // isServiceEligibleForExport returns if a Service is eligible for export; at this stage, headless Services
@@ -31,9 +36,15 @@ func isServiceEligibleForExport(svc *corev1.Service) bool {
 func extractServicePorts(svc *corev1.Service) []fleetnetv1alpha1.ServicePort {
 	svcExportPorts := []fleetnetv1alpha1.ServicePort{}
 	for _, svcPort := range svc.Spec.Ports {
+		// The protocol is part of the exported port spec and must be carried as it is, as ports of different
+		// protocols are in conflict; the API server defaults an unset protocol to TCP.
+		protocol := svcPort.Protocol
+		if protocol == "" {
+			protocol = corev1.ProtocolTCP
+		}
 		svcExportPorts = append(svcExportPorts, fleetnetv1alpha1.ServicePort{
 			Name:        svcPort.Name,
-			Protocol:    svcPort.Protocol,
+			Protocol:    protocol,
 			AppProtocol: svcPort.AppProtocol,
 			Port:        svcPort.Port,
 			TargetPort:  svcPort.TargetPort,
@@ -42,3 +53,48 @@ func extractServicePorts(svc *corev1.Service) []fleetnetv1alpha1.ServicePort {
 
 	return svcExportPorts
 }
+
+// hasReadyEndpoints returns if any of the EndpointSlices has a ready endpoint; EndpointSlice API dictates that
+// consumers should interpret unknown ready state, represented by a nil value, as true ready state.
+func hasReadyEndpoints(endpointSlices []discoveryv1.EndpointSlice) bool {
+	for _, endpointSlice := range endpointSlices {
+		for _, endpoint := range endpointSlice.Endpoints {
+			if endpoint.Conditions.Ready == nil || *endpoint.Conditions.Ready {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// formatNoReadyBackendsMessage returns the message of the NoReadyBackends condition of a Service.
+func formatNoReadyBackendsMessage(svc *corev1.Service) string {
+	if len(svc.Spec.Selector) == 0 {
+		return fmt.Sprintf("service %s/%s has no selector and none of its endpointSlices has a ready endpoint", svc.Namespace, svc.Name)
+	}
+	return fmt.Sprintf("service %s/%s has no ready endpoints; check that its selector %q matches ready pods",
+		svc.Namespace, svc.Name, labels.SelectorFromSet(svc.Spec.Selector).String())
+}
+
+// isLoadBalancerPending returns if a Service is of the LoadBalancer type but the cloud provider has not provisioned
+// an IP address for its load balancer yet.
+func isLoadBalancerPending(svc *corev1.Service) bool {
+	if svc.Spec.Type != corev1.ServiceTypeLoadBalancer {
+		return false
+	}
+	for _, ingress := range svc.Status.LoadBalancer.Ingress {
+		if ingress.IP != "" {
+			return false
+		}
+	}
+	return true
+}
+
+// formatLoadBalancerPendingMessage returns the message of the LoadBalancerPending condition of a Service.
+func formatLoadBalancerPendingMessage(svc *corev1.Service) string {
+	lbType := "public"
+	if svc.Annotations[objectmeta.ServiceAnnotationAzureLoadBalancerInternal] == "true" {
+		lbType = "internal"
+	}
+	return fmt.Sprintf("the %s load balancer IP of service %s/%s is not yet provisioned", lbType, svc.Namespace, svc.Name)
+}
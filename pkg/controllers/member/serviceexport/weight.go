@@ -0,0 +1,86 @@
+/*
+Copyright (c) Microsoft Corporation.
+Licensed under the MIT license.
+*/
+
+package serviceexport
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	fleetnetv1alpha1 "go.goms.io/fleet-networking/api/v1alpha1"
+	"go.goms.io/fleet-networking/pkg/common/objectmeta"
+)
+
+const (
+	// maxExportWeight is the maximum weight of an exported Service.
+	maxExportWeight = 1000
+)
+
+// exportWeight returns the weight of the exported Service as specified by the weight annotation of its ServiceExport,
+// or nil if it is not specified, in which case the weight defaults to 1.
+func exportWeight(svcExport *fleetnetv1alpha1.ServiceExport) (*int64, error) {
+	value, ok := svcExport.Annotations[objectmeta.ServiceExportAnnotationWeight]
+	if !ok {
+		return nil, nil
+	}
+	weight, err := strconv.ParseInt(strings.TrimSpace(value), 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("weight %q is not an integer", value)
+	}
+	if weight < 0 || weight > maxExportWeight {
+		return nil, fmt.Errorf("weight %d is not in the range [0, %d]", weight, maxExportWeight)
+	}
+	return &weight, nil
+}
+
+// isOnlyWeightChanged returns if the desired InternalServiceExport differs from the existing one in the weight only,
+// in which case the weight is patched alone instead of applying the whole InternalServiceExport. The health is
+// ignored, as it is published separately by the HealthProbeReconciler.
+func isOnlyWeightChanged(existing, desired *fleetnetv1alpha1.InternalServiceExport) bool {
+	if equality.Semantic.DeepEqual(existing.Spec.Weight, desired.Spec.Weight) {
+		return false
+	}
+	for k, v := range desired.Labels {
+		if existing.Labels[k] != v {
+			return false
+		}
+	}
+	if existing.Annotations[objectmeta.InternalServiceExportAnnotationTrafficManagerInfoReported] !=
+		desired.Annotations[objectmeta.InternalServiceExportAnnotationTrafficManagerInfoReported] {
+		return false
+	}
+	existingSpec, desiredSpec := existing.Spec.DeepCopy(), desired.Spec.DeepCopy()
+	existingSpec.Weight, desiredSpec.Weight = nil, nil
+	existingSpec.Health, desiredSpec.Health = nil, nil
+	return equality.Semantic.DeepEqual(existingSpec, desiredSpec)
+}
+
+// patchWeight patches the weight of an exported Service alone to its InternalServiceExport with a JSON patch, under a
+// field manager of its own, so that a weight change neither rewrites the whole InternalServiceExport nor has the
+// hub cluster evaluate the conflicts of the export again; a nil weight removes it.
+func (r *Reconciler) patchWeight(ctx context.Context, internalSvcExport *fleetnetv1alpha1.InternalServiceExport, weight *int64) error {
+	op := map[string]interface{}{"op": "add", "path": "/spec/weight", "value": weight}
+	if weight == nil {
+		op = map[string]interface{}{"op": "remove", "path": "/spec/weight"}
+	}
+	data, err := json.Marshal([]interface{}{op})
+	if err != nil {
+		return err
+	}
+	patch := client.RawPatch(types.JSONPatchType, data)
+	if err := r.HubClient.Patch(ctx, internalSvcExport, patch, client.FieldOwner(objectmeta.MemberAgentWeightFieldManager)); err != nil {
+		klog.ErrorS(err, "Failed to patch the weight of internalServiceExport", "internalServiceExport", klog.KObj(internalSvcExport))
+		return err
+	}
+	return nil
+}
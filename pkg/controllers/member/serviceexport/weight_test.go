@@ -0,0 +1,224 @@
+/*
+Copyright (c) Microsoft Corporation.
+Licensed under the MIT license.
+*/
+
+package serviceexport
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/ptr"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+
+	fleetnetv1alpha1 "go.goms.io/fleet-networking/api/v1alpha1"
+	"go.goms.io/fleet-networking/pkg/common/objectmeta"
+)
+
+// TestExportWeight tests the exportWeight function.
+func TestExportWeight(t *testing.T) {
+	testCases := []struct {
+		name        string
+		annotations map[string]string
+		want        *int64
+		wantErr     bool
+	}{
+		{
+			name: "no weight",
+		},
+		{
+			name:        "weight",
+			annotations: map[string]string{objectmeta.ServiceExportAnnotationWeight: " 100 "},
+			want:        ptr.To(int64(100)),
+		},
+		{
+			name:        "zero weight",
+			annotations: map[string]string{objectmeta.ServiceExportAnnotationWeight: "0"},
+			want:        ptr.To(int64(0)),
+		},
+		{
+			name:        "weight out of range",
+			annotations: map[string]string{objectmeta.ServiceExportAnnotationWeight: "1001"},
+			wantErr:     true,
+		},
+		{
+			name:        "weight not an integer",
+			annotations: map[string]string{objectmeta.ServiceExportAnnotationWeight: "heavy"},
+			wantErr:     true,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			svcExport := &fleetnetv1alpha1.ServiceExport{ObjectMeta: metav1.ObjectMeta{Annotations: tc.annotations}}
+			got, err := exportWeight(svcExport)
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("exportWeight() = %v, want error %t", err, tc.wantErr)
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("exportWeight() mismatch (-want, +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+// hubPatchRecorder records the patches sent to the hub cluster: "apply" for a server-side apply, which is emulated
+// with a create or an update as the fake client does not support it, and the data of the patch otherwise.
+func hubPatchRecorder(patches *[]string) interceptor.Funcs {
+	return interceptor.Funcs{
+		Patch: func(ctx context.Context, c client.WithWatch, obj client.Object, patch client.Patch, opts ...client.PatchOption) error {
+			if patch.Type() != types.ApplyPatchType {
+				data, err := patch.Data(obj)
+				if err != nil {
+					return err
+				}
+				*patches = append(*patches, string(data))
+				return c.Patch(ctx, obj, patch, opts...)
+			}
+			*patches = append(*patches, "apply")
+			existing := &fleetnetv1alpha1.InternalServiceExport{}
+			if err := c.Get(ctx, client.ObjectKeyFromObject(obj), existing); err != nil {
+				if !apierrors.IsNotFound(err) {
+					return err
+				}
+				return c.Create(ctx, obj)
+			}
+			obj.SetResourceVersion(existing.ResourceVersion)
+			return c.Update(ctx, obj)
+		},
+	}
+}
+
+// TestReconcile_Weight tests that a change of the weight alone is patched to the InternalServiceExport, while any
+// other change has the whole InternalServiceExport applied.
+func TestReconcile_Weight(t *testing.T) {
+	svcExport := &fleetnetv1alpha1.ServiceExport{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: memberUserNS,
+			Name:      svcName,
+		},
+	}
+	fakeMemberClient := fake.NewClientBuilder().
+		WithScheme(scheme.Scheme).
+		WithObjects(svcExport, previewService()).
+		WithStatusSubresource(svcExport).
+		Build()
+	var patches []string
+	fakeHubClient := fake.NewClientBuilder().
+		WithScheme(scheme.Scheme).
+		WithStatusSubresource(&fleetnetv1alpha1.InternalServiceExport{}).
+		WithInterceptorFuncs(hubPatchRecorder(&patches)).
+		Build()
+	reconciler := &Reconciler{
+		MemberClusterID: memberClusterID,
+		MemberClient:    fakeMemberClient,
+		HubClient:       fakeHubClient,
+		HubNamespace:    hubNSForMember,
+		Recorder:        record.NewFakeRecorder(10),
+	}
+
+	ctx := context.Background()
+	svcExportKey := types.NamespacedName{Namespace: memberUserNS, Name: svcName}
+	internalSvcExportKey := types.NamespacedName{Namespace: hubNSForMember, Name: formatInternalServiceExportName(svcExport)}
+	steps := []struct {
+		name        string
+		weight      *string
+		port        int32
+		wantPatches []string
+		wantWeight  *int64
+	}{
+		{
+			name:        "exported with a weight",
+			weight:      ptr.To("10"),
+			port:        80,
+			wantPatches: []string{"apply"},
+			wantWeight:  ptr.To(int64(10)),
+		},
+		{
+			name:        "weight changed",
+			weight:      ptr.To("20"),
+			port:        80,
+			wantPatches: []string{`[{"op":"add","path":"/spec/weight","value":20}]`},
+			wantWeight:  ptr.To(int64(20)),
+		},
+		{
+			name:        "invalid weight",
+			weight:      ptr.To("heavy"),
+			port:        80,
+			wantPatches: []string{"apply"},
+			wantWeight:  ptr.To(int64(20)),
+		},
+		{
+			name:        "port changed",
+			weight:      ptr.To("20"),
+			port:        8080,
+			wantPatches: []string{"apply"},
+			wantWeight:  ptr.To(int64(20)),
+		},
+		{
+			name:        "weight removed",
+			port:        8080,
+			wantPatches: []string{`[{"op":"remove","path":"/spec/weight"}]`},
+		},
+		{
+			name:        "port and weight changed",
+			weight:      ptr.To("30"),
+			port:        80,
+			wantPatches: []string{"apply"},
+			wantWeight:  ptr.To(int64(30)),
+		},
+	}
+	for _, step := range steps {
+		gotSvcExport := &fleetnetv1alpha1.ServiceExport{}
+		if err := fakeMemberClient.Get(ctx, svcExportKey, gotSvcExport); err != nil {
+			t.Fatalf("%s: svcExport Get() = %v, want no error", step.name, err)
+		}
+		if gotSvcExport.Annotations == nil {
+			gotSvcExport.Annotations = map[string]string{}
+		}
+		delete(gotSvcExport.Annotations, objectmeta.ServiceExportAnnotationWeight)
+		if step.weight != nil {
+			gotSvcExport.Annotations[objectmeta.ServiceExportAnnotationWeight] = *step.weight
+		}
+		if err := fakeMemberClient.Update(ctx, gotSvcExport); err != nil {
+			t.Fatalf("%s: svcExport Update() = %v, want no error", step.name, err)
+		}
+		svc := previewService()
+		if err := fakeMemberClient.Get(ctx, svcExportKey, svc); err != nil {
+			t.Fatalf("%s: svc Get() = %v, want no error", step.name, err)
+		}
+		if svc.Spec.Ports[0].Port != step.port {
+			svc.Spec.Ports[0].Port = step.port
+			if err := fakeMemberClient.Update(ctx, svc); err != nil {
+				t.Fatalf("%s: svc Update() = %v, want no error", step.name, err)
+			}
+		}
+
+		patches = nil
+		if _, err := reconciler.Reconcile(ctx, ctrl.Request{NamespacedName: svcExportKey}); err != nil {
+			t.Fatalf("%s: Reconcile() = %v, want no error", step.name, err)
+		}
+		if diff := cmp.Diff(step.wantPatches, patches); diff != "" {
+			t.Errorf("%s: hub patches mismatch (-want, +got):\n%s", step.name, diff)
+		}
+		internalSvcExport := &fleetnetv1alpha1.InternalServiceExport{}
+		if err := fakeHubClient.Get(ctx, internalSvcExportKey, internalSvcExport); err != nil {
+			t.Fatalf("%s: internalSvcExport Get() = %v, want no error", step.name, err)
+		}
+		if diff := cmp.Diff(step.wantWeight, internalSvcExport.Spec.Weight); diff != "" {
+			t.Errorf("%s: internalSvcExport weight mismatch (-want, +got):\n%s", step.name, diff)
+		}
+		if got := internalSvcExport.Spec.Ports[0].Port; got != step.port {
+			t.Errorf("%s: internalSvcExport port = %d, want %d", step.name, got, step.port)
+		}
+	}
+}
@@ -12,15 +12,23 @@ import (
 	"fmt"
 	"time"
 
+	"k8s.io/apimachinery/pkg/api/equality"
 	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/klog/v2"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
 
 	fleetnetv1alpha1 "go.goms.io/fleet-networking/api/v1alpha1"
+	"go.goms.io/fleet-networking/pkg/common/fieldmanager"
+	"go.goms.io/fleet-networking/pkg/common/networkingmode"
+	"go.goms.io/fleet-networking/pkg/common/objectmeta"
 )
 
 const (
@@ -36,6 +44,10 @@ type Reconciler struct {
 
 	HubClient    client.Client
 	MemberClient client.Client
+
+	// NetworkingMode is the networking mode of the member cluster; the imports are withdrawn while the member
+	// cluster is in the export-only mode. Nil enables the import.
+	NetworkingMode *networkingmode.Gate
 }
 
 //+kubebuilder:rbac:groups=networking.fleet.azure.com,resources=serviceimports,verbs=get;list;watch;update;patch
@@ -72,8 +84,9 @@ func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Resu
 	}
 	internalServiceImportRef := klog.KObj(internalServiceImport)
 
-	// Examine DeletionTimestamp to determine if service import is under deletion.
-	if serviceImport.ObjectMeta.DeletionTimestamp != nil {
+	// Examine DeletionTimestamp to determine if service import is under deletion; the import is withdrawn the same
+	// way when the member cluster is not allowed to import Services.
+	if serviceImport.ObjectMeta.DeletionTimestamp != nil || !r.NetworkingMode.ImportEnabled() {
 		// When finalizer is not found, we can return early as the cleanup work should have been done.
 		if !controllerutil.ContainsFinalizer(serviceImport, ServiceImportFinalizer) {
 			return ctrl.Result{}, nil
@@ -91,7 +104,7 @@ func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Resu
 			klog.ErrorS(err, "Failed to remove serviceimport finalizer", "ServiceImport", serviceImportRef, "finalizer", ServiceImportFinalizer)
 			return ctrl.Result{}, err
 		}
-		// Stop reconciliation as the item is being deleted
+		// Stop reconciliation as the item is being deleted, or the import is disabled
 		return ctrl.Result{}, nil
 	}
 
@@ -104,30 +117,107 @@ func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Resu
 		}
 	}
 
-	klog.V(2).InfoS("Create or update internal service import", "InternalServiceImport", internalServiceImportRef)
-	if op, err := controllerutil.CreateOrUpdate(ctx, r.HubClient, internalServiceImport, func() error {
-		if internalServiceImport.CreationTimestamp.IsZero() {
-			// Set the ServiceReference only when the InternalServiceImport is created; most of the fields in
-			// an ExportedObjectReference should be immutable.
-			internalServiceImport.Spec.ServiceImportReference = fleetnetv1alpha1.FromMetaObjects(r.MemberClusterID, serviceImport.TypeMeta, serviceImport.ObjectMeta, serviceImport.CreationTimestamp)
-		}
+	if err := r.applyInternalServiceImport(ctx, serviceImport, internalServiceImport); err != nil {
+		klog.ErrorS(err, "Failed to apply InternalServiceImport from ServiceImport", "InternalServiceImport", internalServiceImportRef, "ServiceImport", serviceImportRef)
+		return ctrl.Result{}, err
+	}
+	return ctrl.Result{}, nil
+}
 
-		// TO-DO: InternalServiceImport object is not an exported object and the ServiceImportReference (an
-		// exportedObject field) will be removed; information updated here is not used.
-		internalServiceImport.Spec.ServiceImportReference.UpdateFromMetaObject(serviceImport.ObjectMeta, serviceImport.CreationTimestamp)
+// applyInternalServiceImport applies the spec of the InternalServiceImport of a service import to the hub cluster.
+//
+// The member cluster owns the spec of the InternalServiceImport, i.e. the import request, and the hub cluster owns
+// its status; the spec is fully derived from the service import and applied with server-side apply under the member
+// agent field manager, taking it back from any other manager which changed it in the hub cluster. The spec is
+// applied only when it drifts from the derived one, so that the two sides do not keep overwriting each other.
+func (r *Reconciler) applyInternalServiceImport(ctx context.Context, serviceImport *fleetnetv1alpha1.ServiceImport, internalServiceImport *fleetnetv1alpha1.InternalServiceImport) error {
+	internalServiceImportRef := klog.KObj(internalServiceImport)
+	// TO-DO: InternalServiceImport object is not an exported object and the ServiceImportReference (an
+	// exportedObject field) will be removed; information updated here is not used.
+	wantSpec := fleetnetv1alpha1.InternalServiceImportSpec{
+		ServiceImportReference: fleetnetv1alpha1.FromMetaObjects(r.MemberClusterID, serviceImport.TypeMeta, serviceImport.ObjectMeta, serviceImport.CreationTimestamp),
+	}
+
+	existing := &fleetnetv1alpha1.InternalServiceImport{}
+	err := r.HubClient.Get(ctx, client.ObjectKeyFromObject(internalServiceImport), existing)
+	switch {
+	case err == nil && equality.Semantic.DeepEqual(existing.Spec, wantSpec):
+		// The spec has converged; skip the apply.
 		return nil
-	}); err != nil {
-		klog.ErrorS(err, "Failed to create or update InternalServiceImport from ServiceImport", "InternalServiceImport", internalServiceImportRef, "ServiceImport", serviceImportRef, "op", op)
-		return ctrl.Result{}, err
+	case err == nil:
+		klog.V(2).InfoS("Spec of internal service import drifts from the service import", "InternalServiceImport", internalServiceImportRef,
+			"spec", wantSpec, "oldSpec", existing.Spec)
+		// InternalServiceImports written before the member agent switched to server-side apply have their spec owned
+		// by the Update operations of the agent; hand it over to the apply field manager.
+		if err := fieldmanager.UpgradeManagedFields(ctx, r.HubClient, existing,
+			objectmeta.MemberAgentFieldManager, objectmeta.MemberAgentLegacyFieldManager); err != nil {
+			klog.ErrorS(err, "Failed to upgrade the managed fields of internal service import", "InternalServiceImport", internalServiceImportRef)
+			return err
+		}
+	case !errors.IsNotFound(err):
+		klog.ErrorS(err, "Failed to get internal service import", "InternalServiceImport", internalServiceImportRef)
+		return err
 	}
 
-	return ctrl.Result{}, nil
+	klog.V(2).InfoS("Apply internal service import", "InternalServiceImport", internalServiceImportRef)
+	internalServiceImport.TypeMeta = metav1.TypeMeta{
+		APIVersion: fleetnetv1alpha1.GroupVersion.String(),
+		Kind:       "InternalServiceImport",
+	}
+	internalServiceImport.Spec = wantSpec
+	return r.HubClient.Patch(ctx, internalServiceImport, client.Apply,
+		client.FieldOwner(objectmeta.MemberAgentFieldManager), client.ForceOwnership)
 }
 
-func (r *Reconciler) SetupWithManager(mgr ctrl.Manager) error {
-	return ctrl.NewControllerManagedBy(mgr).
+// SetupWithManager sets up the controller with the controller managers of the member and the hub clusters.
+func (r *Reconciler) SetupWithManager(memberMgr, hubMgr ctrl.Manager) error {
+	b := ctrl.NewControllerManagedBy(memberMgr).
 		For(&fleetnetv1alpha1.ServiceImport{}).
-		Complete(r)
+		// Watch over the spec of the InternalServiceImports in the hub cluster, so that the spec is restored when
+		// it is changed or the InternalServiceImport is deleted by anyone other than the member agent; the status
+		// changes made by the hub cluster are filtered out.
+		WatchesRawSource(source.Kind(hubMgr.GetCache(),
+			client.Object(&fleetnetv1alpha1.InternalServiceImport{}),
+			handler.EnqueueRequestsFromMapFunc(r.internalServiceImportOwner),
+			predicate.GenerationChangedPredicate{},
+		))
+	if r.NetworkingMode != nil {
+		// Watch over the networking mode of the member cluster, so that the imports are withdrawn or requested again
+		// when the import is disabled or enabled.
+		b = b.WatchesRawSource(source.Channel(r.NetworkingMode.Subscribe(), handler.EnqueueRequestsFromMapFunc(r.allServiceImports)))
+	}
+	return b.Complete(r)
+}
+
+// internalServiceImportOwner maps an InternalServiceImport of the member cluster to the service import it is
+// derived from. On updates both the old and the new InternalServiceImports are mapped, so that the service import is
+// enqueued even if the reference itself has been changed.
+func (r *Reconciler) internalServiceImportOwner(_ context.Context, o client.Object) []reconcile.Request {
+	internalServiceImport, ok := o.(*fleetnetv1alpha1.InternalServiceImport)
+	if !ok || internalServiceImport.Namespace != r.HubNamespace {
+		return nil
+	}
+	ref := internalServiceImport.Spec.ServiceImportReference
+	if ref.Namespace == "" || ref.Name == "" {
+		return nil
+	}
+	return []reconcile.Request{{NamespacedName: types.NamespacedName{Namespace: ref.Namespace, Name: ref.Name}}}
+}
+
+// allServiceImports maps a networking mode change to all the service imports in the member cluster.
+func (r *Reconciler) allServiceImports(ctx context.Context, _ client.Object) []reconcile.Request {
+	serviceImportList := &fleetnetv1alpha1.ServiceImportList{}
+	if err := r.MemberClient.List(ctx, serviceImportList); err != nil {
+		klog.ErrorS(err, "Failed to list serviceImports")
+		return nil
+	}
+	requests := make([]reconcile.Request, 0, len(serviceImportList.Items))
+	for _, serviceImport := range serviceImportList.Items {
+		requests = append(requests, reconcile.Request{
+			NamespacedName: types.NamespacedName{Namespace: serviceImport.Namespace, Name: serviceImport.Name},
+		})
+	}
+	return requests
 }
 
 // formatInternalServiceImportName returns the unique name assigned to an service import
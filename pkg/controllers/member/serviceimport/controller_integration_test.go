@@ -12,12 +12,15 @@ import (
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/uuid"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 
 	fleetnetv1alpha1 "go.goms.io/fleet-networking/api/v1alpha1"
+	"go.goms.io/fleet-networking/pkg/common/networkingmode"
 )
 
 var (
@@ -134,4 +137,53 @@ var _ = Describe("Create or update a service import", func() {
 			}, duration, interval).Should(Equal(0))
 		})
 	})
+
+	When("Toggle the networking mode of the member cluster", func() {
+		AfterEach(func() {
+			networkingModeGate.Set(networkingmode.ModeBoth)
+		})
+
+		It("should withdraw the import in the export-only mode and request it again in the both mode", func() {
+			serviceImport := &fleetnetv1alpha1.ServiceImport{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "service-import-name",
+					Namespace: testNamespace,
+				},
+			}
+			By("By creating a service import")
+			Expect(memberClient.Create(ctx, serviceImport)).Should(Succeed())
+			serviceImportLookupKey := types.NamespacedName{Name: serviceImport.Name, Namespace: serviceImport.Namespace}
+			internalServiceImportLookupKey := types.NamespacedName{Name: formatInternalServiceImportName(serviceImport), Namespace: HubNamespace}
+			By("By checking internal service import is created")
+			Eventually(func() error {
+				return hubClient.Get(ctx, internalServiceImportLookupKey, &fleetnetv1alpha1.InternalServiceImport{})
+			}, timeout, interval).Should(Succeed())
+
+			By("By switching to the export-only mode")
+			networkingModeGate.Set(networkingmode.ModeExportOnly)
+			By("By checking internal service import is deleted and the finalizer is removed")
+			Eventually(func() bool {
+				return errors.IsNotFound(hubClient.Get(ctx, internalServiceImportLookupKey, &fleetnetv1alpha1.InternalServiceImport{}))
+			}, timeout, interval).Should(BeTrue())
+			Eventually(func() (bool, error) {
+				if err := memberClient.Get(ctx, serviceImportLookupKey, serviceImport); err != nil {
+					return false, err
+				}
+				return controllerutil.ContainsFinalizer(serviceImport, ServiceImportFinalizer), nil
+			}, timeout, interval).Should(BeFalse())
+
+			By("By switching back to the both mode")
+			networkingModeGate.Set(networkingmode.ModeBoth)
+			By("By checking internal service import is created again")
+			Eventually(func() error {
+				return hubClient.Get(ctx, internalServiceImportLookupKey, &fleetnetv1alpha1.InternalServiceImport{})
+			}, timeout, interval).Should(Succeed())
+
+			By("By deleting the service import")
+			Expect(memberClient.Delete(ctx, serviceImport)).Should(Succeed())
+			Eventually(func() bool {
+				return errors.IsNotFound(hubClient.Get(ctx, internalServiceImportLookupKey, &fleetnetv1alpha1.InternalServiceImport{}))
+			}, timeout, interval).Should(BeTrue())
+		})
+	})
 })
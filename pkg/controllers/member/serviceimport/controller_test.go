@@ -0,0 +1,233 @@
+/*
+Copyright (c) Microsoft Corporation.
+Licensed under the MIT license.
+*/
+
+package serviceimport
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	fleetnetv1alpha1 "go.goms.io/fleet-networking/api/v1alpha1"
+	"go.goms.io/fleet-networking/pkg/common/objectmeta"
+)
+
+const (
+	testUserNS        = "work"
+	testSvcImportName = "app"
+)
+
+var (
+	testSvcImportKey         = types.NamespacedName{Namespace: testUserNS, Name: testSvcImportName}
+	testInternalSvcImportKey = types.NamespacedName{Namespace: HubNamespace, Name: testUserNS + "-" + testSvcImportName}
+)
+
+func newTestScheme(t *testing.T) *runtime.Scheme {
+	scheme := runtime.NewScheme()
+	if err := fleetnetv1alpha1.AddToScheme(scheme); err != nil {
+		t.Fatalf("AddToScheme() got error %v, want no error", err)
+	}
+	return scheme
+}
+
+// specApplyInterceptor emulates the server-side apply of the InternalServiceImport spec, which the fake client does
+// not support; the applied spec replaces the spec as the service import reference is an atomic struct.
+func specApplyInterceptor(applies *int) interceptor.Funcs {
+	return interceptor.Funcs{
+		Patch: func(ctx context.Context, c client.WithWatch, obj client.Object, patch client.Patch, opts ...client.PatchOption) error {
+			if patch.Type() != types.ApplyPatchType {
+				return c.Patch(ctx, obj, patch, opts...)
+			}
+			patchOpts := &client.PatchOptions{}
+			patchOpts.ApplyOptions(opts)
+			if patchOpts.FieldManager != objectmeta.MemberAgentFieldManager || patchOpts.Force == nil || !*patchOpts.Force {
+				return apierrors.NewBadRequest("not a forced apply by the member agent")
+			}
+			*applies++
+			applied := obj.(*fleetnetv1alpha1.InternalServiceImport)
+			live := &fleetnetv1alpha1.InternalServiceImport{}
+			err := c.Get(ctx, client.ObjectKeyFromObject(obj), live)
+			switch {
+			case apierrors.IsNotFound(err):
+				live = &fleetnetv1alpha1.InternalServiceImport{
+					ObjectMeta: metav1.ObjectMeta{Namespace: applied.Namespace, Name: applied.Name},
+					Spec:       applied.Spec,
+				}
+				return c.Create(ctx, live)
+			case err != nil:
+				return err
+			}
+			live.Spec = applied.Spec
+			return c.Update(ctx, live)
+		},
+	}
+}
+
+// TestReconcile_SpecDrift tests that the spec of an InternalServiceImport changed out-of-band in the hub cluster is
+// restored without touching the status owned by the hub cluster, and that it stays untouched once restored over the
+// following reconciliations.
+func TestReconcile_SpecDrift(t *testing.T) {
+	svcImport := &fleetnetv1alpha1.ServiceImport{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace:         testUserNS,
+			Name:              testSvcImportName,
+			UID:               "svc-import-uid",
+			Generation:        2,
+			CreationTimestamp: metav1.NewTime(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)),
+			Finalizers:        []string{ServiceImportFinalizer},
+		},
+	}
+	hubStatus := fleetnetv1alpha1.ServiceImportStatus{
+		Type:     fleetnetv1alpha1.ClusterSetIP,
+		Clusters: []fleetnetv1alpha1.ClusterStatus{{Cluster: "member-2"}},
+	}
+
+	testCases := []struct {
+		name    string
+		deleted bool
+		// spec is the spec changed out-of-band; nil leaves the spec unchanged.
+		spec        *fleetnetv1alpha1.InternalServiceImportSpec
+		wantApplies int
+	}{
+		{
+			name:        "internal service import deleted",
+			deleted:     true,
+			wantApplies: 1,
+		},
+		{
+			name: "spec not changed",
+		},
+		{
+			name: "reference changed",
+			spec: &fleetnetv1alpha1.InternalServiceImportSpec{
+				ServiceImportReference: fleetnetv1alpha1.ExportedObjectReference{
+					ClusterID:      "another-member",
+					Kind:           "ServiceImport",
+					Namespace:      testUserNS,
+					Name:           "another-app",
+					UID:            "another-uid",
+					NamespacedName: testUserNS + "/another-app",
+				},
+			},
+			wantApplies: 1,
+		},
+		{
+			name:        "reference cleared",
+			spec:        &fleetnetv1alpha1.InternalServiceImportSpec{},
+			wantApplies: 1,
+		},
+	}
+
+	ctx := context.Background()
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			scheme := newTestScheme(t)
+			fakeMemberClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(svcImport.DeepCopy()).Build()
+			liveSvcImport := &fleetnetv1alpha1.ServiceImport{}
+			if err := fakeMemberClient.Get(ctx, testSvcImportKey, liveSvcImport); err != nil {
+				t.Fatalf("serviceImport Get() got error %v, want no error", err)
+			}
+			wantSpec := fleetnetv1alpha1.InternalServiceImportSpec{
+				ServiceImportReference: fleetnetv1alpha1.FromMetaObjects(MemberClusterID, liveSvcImport.TypeMeta, liveSvcImport.ObjectMeta, liveSvcImport.CreationTimestamp),
+			}
+
+			hubClientBuilder := fake.NewClientBuilder().WithScheme(scheme)
+			wantStatus := fleetnetv1alpha1.ServiceImportStatus{}
+			if !tc.deleted {
+				spec := wantSpec
+				if tc.spec != nil {
+					spec = *tc.spec
+				}
+				internalSvcImport := &fleetnetv1alpha1.InternalServiceImport{
+					ObjectMeta: metav1.ObjectMeta{
+						Namespace: testInternalSvcImportKey.Namespace,
+						Name:      testInternalSvcImportKey.Name,
+					},
+					Spec:   spec,
+					Status: hubStatus,
+				}
+				hubClientBuilder = hubClientBuilder.WithObjects(internalSvcImport).WithStatusSubresource(internalSvcImport)
+				wantStatus = hubStatus
+			}
+			applies := 0
+			fakeHubClient := hubClientBuilder.WithInterceptorFuncs(specApplyInterceptor(&applies)).Build()
+			r := &Reconciler{
+				MemberClusterID: MemberClusterID,
+				HubNamespace:    HubNamespace,
+				HubClient:       fakeHubClient,
+				MemberClient:    fakeMemberClient,
+			}
+
+			// The spec converges within the first reconciliation; the following ones find nothing to restore.
+			for i := 0; i < 3; i++ {
+				if _, err := r.Reconcile(ctx, ctrl.Request{NamespacedName: testSvcImportKey}); err != nil {
+					t.Fatalf("Reconcile() #%d got error %v, want no error", i, err)
+				}
+				got := &fleetnetv1alpha1.InternalServiceImport{}
+				if err := fakeHubClient.Get(ctx, testInternalSvcImportKey, got); err != nil {
+					t.Fatalf("internalServiceImport Get() got error %v, want no error", err)
+				}
+				if diff := cmp.Diff(wantSpec, got.Spec); diff != "" {
+					t.Errorf("Reconcile() #%d internalServiceImport spec mismatch (-want, +got):\n%s", i, diff)
+				}
+				if diff := cmp.Diff(wantStatus, got.Status); diff != "" {
+					t.Errorf("Reconcile() #%d internalServiceImport status mismatch (-want, +got):\n%s", i, diff)
+				}
+			}
+			if applies != tc.wantApplies {
+				t.Errorf("Reconcile() applies = %d, want %d", applies, tc.wantApplies)
+			}
+		})
+	}
+}
+
+// TestInternalServiceImportOwner tests the Reconciler.internalServiceImportOwner method.
+func TestInternalServiceImportOwner(t *testing.T) {
+	testCases := []struct {
+		name      string
+		namespace string
+		ref       fleetnetv1alpha1.ExportedObjectReference
+		want      []reconcile.Request
+	}{
+		{
+			name:      "internal service import of the member cluster",
+			namespace: HubNamespace,
+			ref:       fleetnetv1alpha1.ExportedObjectReference{Namespace: testUserNS, Name: testSvcImportName},
+			want:      []reconcile.Request{{NamespacedName: testSvcImportKey}},
+		},
+		{
+			name:      "internal service import of another member cluster",
+			namespace: "another-hub-namespace",
+			ref:       fleetnetv1alpha1.ExportedObjectReference{Namespace: testUserNS, Name: testSvcImportName},
+		},
+		{
+			name:      "internal service import without a reference",
+			namespace: HubNamespace,
+		},
+	}
+	r := &Reconciler{HubNamespace: HubNamespace}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			internalSvcImport := &fleetnetv1alpha1.InternalServiceImport{
+				ObjectMeta: metav1.ObjectMeta{Namespace: tc.namespace, Name: testInternalSvcImportKey.Name},
+				Spec:       fleetnetv1alpha1.InternalServiceImportSpec{ServiceImportReference: tc.ref},
+			}
+			if diff := cmp.Diff(tc.want, r.internalServiceImportOwner(context.Background(), internalSvcImport)); diff != "" {
+				t.Errorf("internalServiceImportOwner() mismatch (-want, +got):\n%s", diff)
+			}
+		})
+	}
+}
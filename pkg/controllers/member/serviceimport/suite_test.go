@@ -26,6 +26,7 @@ import (
 	// +kubebuilder:scaffold:imports
 
 	fleetnetv1alpha1 "go.goms.io/fleet-networking/api/v1alpha1"
+	"go.goms.io/fleet-networking/pkg/common/networkingmode"
 )
 
 // These tests use Ginkgo (BDD-style Go testing framework). Refer to
@@ -38,6 +39,8 @@ var (
 	hubClient     client.Client
 	ctx           context.Context
 	cancel        context.CancelFunc
+
+	networkingModeGate = networkingmode.NewGate()
 )
 
 const (
@@ -99,13 +102,21 @@ var _ = BeforeSuite(func() {
 		},
 	})
 	Expect(err).NotTo(HaveOccurred())
+	hubMgr, err := ctrl.NewManager(hubCfg, ctrl.Options{
+		Scheme: scheme.Scheme,
+		Metrics: metricsserver.Options{
+			BindAddress: "0",
+		},
+	})
+	Expect(err).NotTo(HaveOccurred())
 
 	err = (&Reconciler{
 		MemberClusterID: MemberClusterID,
 		HubNamespace:    HubNamespace,
 		MemberClient:    memberClient,
 		HubClient:       hubClient,
-	}).SetupWithManager(mgr)
+		NetworkingMode:  networkingModeGate,
+	}).SetupWithManager(mgr, hubMgr)
 	Expect(err).ToNot(HaveOccurred())
 
 	setupResources()
@@ -116,6 +127,11 @@ var _ = BeforeSuite(func() {
 		err = mgr.Start(ctx)
 		Expect(err).ToNot(HaveOccurred(), "failed to run manager")
 	}()
+	go func() {
+		defer GinkgoRecover()
+		err := hubMgr.Start(ctx)
+		Expect(err).ToNot(HaveOccurred(), "failed to run hub manager")
+	}()
 })
 
 func setupResources() {
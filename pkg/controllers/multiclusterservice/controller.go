@@ -10,16 +10,21 @@ package multiclusterservice
 import (
 	"context"
 	"fmt"
+	"slices"
+	"sort"
 	"strconv"
+	"strings"
 	"time"
 
 	corev1 "k8s.io/api/core/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
 	"k8s.io/apimachinery/pkg/api/equality"
 	"k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/intstr"
 	"k8s.io/client-go/tools/record"
 	"k8s.io/klog/v2"
 	ctrl "sigs.k8s.io/controller-runtime"
@@ -30,6 +35,7 @@ import (
 
 	fleetnetv1alpha1 "go.goms.io/fleet-networking/api/v1alpha1"
 	"go.goms.io/fleet-networking/pkg/common/condition"
+	"go.goms.io/fleet-networking/pkg/common/ipfamily"
 	"go.goms.io/fleet-networking/pkg/common/objectmeta"
 )
 
@@ -44,6 +50,15 @@ const (
 
 	conditionReasonUnknownServiceImport = "UnknownServiceImport"
 	conditionReasonFoundServiceImport   = "FoundServiceImport"
+	conditionReasonFoundPorts           = "FoundPorts"
+	conditionReasonPortNotFound         = "PortNotFound"
+	conditionReasonNoMatchingPorts      = "NoMatchingPorts"
+
+	conditionReasonInternalTrafficPolicyApplied = "InternalTrafficPolicyApplied"
+	conditionReasonEndpointsMissingNodeNames    = "EndpointsMissingNodeNames"
+
+	conditionReasonIPFamiliesApplied     = "IPFamiliesApplied"
+	conditionReasonDualStackNotSupported = "DualStackNotSupported"
 
 	mcsRetryInterval = time.Second * 5
 
@@ -54,7 +69,11 @@ const (
 	multiClusterServiceAnnotationInternalLoadBalancer = "networking.fleet.azure.com/azure-load-balancer-internal"
 
 	// service annotation
-	serviceAnnotationInternalLoadBalancer = "service.beta.kubernetes.io/azure-load-balancer-internal"
+	serviceAnnotationInternalLoadBalancer    = "service.beta.kubernetes.io/azure-load-balancer-internal"
+	serviceAnnotationLoadBalancerIdleTimeout = "service.beta.kubernetes.io/azure-load-balancer-tcp-idle-timeout"
+
+	// azureLoadBalancerDefaultIdleTimeoutMinutes is the TCP idle timeout Azure applies when none is configured.
+	azureLoadBalancerDefaultIdleTimeoutMinutes = 4
 )
 
 // Reconciler reconciles a MultiClusterService object.
@@ -71,6 +90,8 @@ type Reconciler struct {
 //+kubebuilder:rbac:groups=networking.fleet.azure.com,resources=multiclusterservices/finalizers,verbs=get;update
 //+kubebuilder:rbac:groups=networking.fleet.azure.com,resources=serviceimports,verbs=get;list;watch;create;update;patch;delete
 //+kubebuilder:rbac:groups="",resources=events,verbs=create;patch
+//+kubebuilder:rbac:groups="",resources=namespaces,verbs=get
+//+kubebuilder:rbac:groups=discovery.k8s.io,resources=endpointslices,verbs=get;list;watch
 
 // Reconcile triggers a single reconcile round.
 func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
@@ -120,7 +141,7 @@ func (r *Reconciler) handleDelete(ctx context.Context, mcs *fleetnetv1alpha1.Mul
 
 	klog.V(2).InfoS("Removing mcs", "multiClusterService", mcsKObj)
 
-	// delete derived service in the fleet-system namespace
+	// delete derived service in the reserved fleet namespace
 	serviceName := r.derivedServiceFromLabel(mcs)
 	if err := r.deleteDerivedService(ctx, serviceName); err != nil {
 		klog.ErrorS(err, "Failed to remove derived service of mcs", "multiClusterService", mcsKObj)
@@ -128,13 +149,11 @@ func (r *Reconciler) handleDelete(ctx context.Context, mcs *fleetnetv1alpha1.Mul
 			return ctrl.Result{}, err
 		}
 	}
-	// delete service import in the same namespace as the multi-cluster service
+	// release service import in the same namespace as the multi-cluster service
 	serviceImportName := r.serviceImportFromLabel(mcs)
-	if err := r.deleteServiceImport(ctx, serviceImportName); err != nil {
+	if err := r.releaseServiceImport(ctx, mcs, serviceImportName); err != nil {
 		klog.ErrorS(err, "Failed to remove service import of mcs", "multiClusterService", mcsKObj)
-		if !errors.IsNotFound(err) {
-			return ctrl.Result{}, err
-		}
+		return ctrl.Result{}, err
 	}
 	r.Recorder.Eventf(mcs, corev1.EventTypeNormal, "UnimportedService", "Unimported service %s", serviceImportName)
 
@@ -159,17 +178,81 @@ func (r *Reconciler) deleteDerivedService(ctx context.Context, serviceName *type
 	return r.Client.Delete(ctx, &service)
 }
 
-func (r *Reconciler) deleteServiceImport(ctx context.Context, serviceImportName *types.NamespacedName) error {
+// releaseServiceImport removes the mcs from the references of the service import, and deletes the service import
+// once it is no longer referenced by any mcs. A service import still referenced by other multi-cluster services is
+// kept and released by the mcs, so that one of the others can take it over.
+func (r *Reconciler) releaseServiceImport(ctx context.Context, mcs *fleetnetv1alpha1.MultiClusterService, serviceImportName *types.NamespacedName) error {
 	if serviceImportName == nil {
 		return nil
 	}
-	serviceImport := fleetnetv1alpha1.ServiceImport{
-		ObjectMeta: metav1.ObjectMeta{
-			Namespace: serviceImportName.Namespace,
-			Name:      serviceImportName.Name,
-		},
+	serviceImport := &fleetnetv1alpha1.ServiceImport{}
+	if err := r.Client.Get(ctx, *serviceImportName, serviceImport); err != nil {
+		return client.IgnoreNotFound(err)
+	}
+	serviceImportKObj := klog.KObj(serviceImport)
+	if _, ok := serviceImport.GetAnnotations()[objectmeta.ServiceImportAnnotationMultiClusterServices]; !ok {
+		// The service import is created before the references are recorded; only its owner could delete it.
+		if isServiceImportOwnedByOthers(mcs, serviceImport) {
+			klog.V(2).InfoS("Skipping deleting the serviceImport owned by other mcs", "multiClusterService", klog.KObj(mcs), "serviceImport", serviceImportKObj)
+			return nil
+		}
+		return client.IgnoreNotFound(r.Client.Delete(ctx, serviceImport))
+	}
+
+	var remaining []string
+	for _, name := range serviceImportReferences(serviceImport) {
+		if name == mcs.Name {
+			continue
+		}
+		// Drop the references of the multi-cluster services which have been deleted without releasing the service import.
+		if err := r.Client.Get(ctx, types.NamespacedName{Namespace: mcs.Namespace, Name: name}, &fleetnetv1alpha1.MultiClusterService{}); err != nil {
+			if errors.IsNotFound(err) {
+				continue
+			}
+			return err
+		}
+		remaining = append(remaining, name)
+	}
+	if len(remaining) == 0 {
+		klog.V(2).InfoS("Deleting the serviceImport which is no longer referenced by any mcs", "multiClusterService", klog.KObj(mcs), "serviceImport", serviceImportKObj)
+		return client.IgnoreNotFound(r.Client.Delete(ctx, serviceImport))
+	}
+
+	klog.V(2).InfoS("Releasing the serviceImport still referenced by other mcs", "multiClusterService", klog.KObj(mcs), "serviceImport", serviceImportKObj, "references", remaining)
+	serviceImport.Annotations[objectmeta.ServiceImportAnnotationMultiClusterServices] = strings.Join(remaining, ",")
+	ownerRefs := make([]metav1.OwnerReference, 0, len(serviceImport.OwnerReferences))
+	for _, owner := range serviceImport.OwnerReferences {
+		if owner.UID != mcs.UID {
+			ownerRefs = append(ownerRefs, owner)
+		}
+	}
+	serviceImport.OwnerReferences = ownerRefs
+	return r.Client.Update(ctx, serviceImport)
+}
+
+// serviceImportReferences returns the names of the multi-cluster services referencing the service import.
+func serviceImportReferences(serviceImport *fleetnetv1alpha1.ServiceImport) []string {
+	var names []string
+	for _, name := range strings.Split(serviceImport.GetAnnotations()[objectmeta.ServiceImportAnnotationMultiClusterServices], ",") {
+		if name = strings.TrimSpace(name); name != "" {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// addServiceImportReference records the mcs as one of the multi-cluster services referencing the service import.
+func addServiceImportReference(serviceImport *fleetnetv1alpha1.ServiceImport, mcs *fleetnetv1alpha1.MultiClusterService) {
+	names := serviceImportReferences(serviceImport)
+	if slices.Contains(names, mcs.Name) {
+		return
+	}
+	names = append(names, mcs.Name)
+	sort.Strings(names)
+	if serviceImport.Annotations == nil {
+		serviceImport.Annotations = map[string]string{}
 	}
-	return r.Client.Delete(ctx, &serviceImport)
+	serviceImport.Annotations[objectmeta.ServiceImportAnnotationMultiClusterServices] = strings.Join(names, ",")
 }
 
 // mcs-controller will record derived service name as the label to make sure the derived name is unique.
@@ -193,11 +276,9 @@ func (r *Reconciler) handleUpdate(ctx context.Context, mcs *fleetnetv1alpha1.Mul
 	currentServiceImportName := r.serviceImportFromLabel(mcs)
 	desiredServiceImportName := types.NamespacedName{Namespace: mcs.Namespace, Name: mcs.Spec.ServiceImport.Name}
 	if currentServiceImportName != nil && currentServiceImportName.Name != desiredServiceImportName.Name {
-		if err := r.deleteServiceImport(ctx, currentServiceImportName); err != nil {
+		if err := r.releaseServiceImport(ctx, mcs, currentServiceImportName); err != nil {
 			klog.ErrorS(err, "Failed to remove service import of mcs", "multiClusterService", mcsKObj, "serviceImport", klog.KRef(currentServiceImportName.Namespace, currentServiceImportName.Name))
-			if !errors.IsNotFound(err) {
-				return ctrl.Result{}, err
-			}
+			return ctrl.Result{}, err
 		}
 	}
 	// update mcs service import label first to prevent the controller abort before we create the resource
@@ -215,27 +296,25 @@ func (r *Reconciler) handleUpdate(ctx context.Context, mcs *fleetnetv1alpha1.Mul
 	// OR 2) Update a serviceImport if the desired state does not match with current state.
 	// OR 3) Get a serviceImport when ServiceImport status change triggers the MCS reconcile.
 	if op, err := controllerutil.CreateOrUpdate(ctx, r.Client, serviceImport, func() error {
+		// The mcs is recorded as a reference even if the service import is owned by another mcs, so that the service
+		// import is not deleted until both are deleted.
+		addServiceImportReference(serviceImport, mcs)
+		if isServiceImportOwnedByOthers(mcs, serviceImport) {
+			return nil
+		}
 		return r.ensureServiceImport(serviceImport, mcs)
 	}); err != nil {
-		serviceImportKObj := klog.KObj(serviceImport)
 		// If the service import is already owned by another MultiClusterService, serviceImport update or creation will fail.
 		if err := r.Client.Get(ctx, desiredServiceImportName, serviceImport); err == nil && isServiceImportOwnedByOthers(mcs, serviceImport) { // check if NO error
-			// reset the current serviceImport to empty as input so that internal func will update mcs status based on the serviceImport status
-			// it won't change the serviceImport in the API server
-			// TODO could be improved by moving into the mutate func and creating a customized error
-			serviceImport.Status = fleetnetv1alpha1.ServiceImportStatus{}
-			if err := r.handleInvalidServiceImport(ctx, mcs, serviceImport); err != nil {
-				klog.ErrorS(err, "Failed to update status of mcs as serviceImport has been owned by other mcs", "multiClusterService", mcsKObj, "serviceImport", serviceImportKObj, "owner", serviceImport.OwnerReferences)
-				return ctrl.Result{}, err
-			}
-			// have to requeue the request to see if the service import is deleted by owner or not
-			klog.V(3).InfoS("ServiceImport has been owned by other mcs and requeue the request", "multiClusterService", mcsKObj, "serviceImport", serviceImportKObj)
-			return ctrl.Result{RequeueAfter: mcsRetryInterval}, nil
+			return r.handleServiceImportOwnedByOthers(ctx, mcs, serviceImport)
 		}
 
-		klog.ErrorS(err, "Failed to create or update service import of mcs", "multiClusterService", mcsKObj, "serviceImport", serviceImportKObj, "op", op)
+		klog.ErrorS(err, "Failed to create or update service import of mcs", "multiClusterService", mcsKObj, "serviceImport", klog.KObj(serviceImport), "op", op)
 		return ctrl.Result{}, err
 	}
+	if isServiceImportOwnedByOthers(mcs, serviceImport) {
+		return r.handleServiceImportOwnedByOthers(ctx, mcs, serviceImport)
+	}
 
 	if len(serviceImport.Status.Clusters) == 0 {
 		// Since there is no services exported in the clusters, delete derived service if exists.
@@ -243,6 +322,12 @@ func (r *Reconciler) handleUpdate(ctx context.Context, mcs *fleetnetv1alpha1.Mul
 		// it will do nothing.
 		return ctrl.Result{}, r.handleInvalidServiceImport(ctx, mcs, serviceImport)
 	}
+	if ports, missing := filterServiceImportPorts(mcs.Spec.Ports, serviceImport.Status.Ports); len(mcs.Spec.Ports) != 0 && len(ports) == 0 {
+		// A load balancer service requires at least one port; since none of the requested ports is exported, delete
+		// the derived service if exists.
+		klog.V(2).InfoS("None of the requested ports is found in the service import", "multiClusterService", mcsKObj, "serviceImport", klog.KObj(serviceImport), "missingPorts", missing)
+		return ctrl.Result{}, r.handleNoMatchingPorts(ctx, mcs, serviceImport, missing)
+	}
 	r.Recorder.Eventf(mcs, corev1.EventTypeNormal, "FoundValidService", "Found valid service %s and importing", serviceImport.Name)
 
 	serviceName := r.derivedServiceFromLabel(mcs)
@@ -255,6 +340,34 @@ func (r *Reconciler) handleUpdate(ctx context.Context, mcs *fleetnetv1alpha1.Mul
 		return ctrl.Result{}, err
 	}
 
+	internalTrafficPolicy := mcs.Spec.InternalTrafficPolicy
+	if internalTrafficPolicy == corev1.ServiceInternalTrafficPolicyLocal {
+		hasNodeNames, err := r.importedEndpointsHaveNodeNames(ctx, serviceName)
+		if err != nil {
+			klog.ErrorS(err, "Failed to list the imported endpointSlices of the derived service", "multiClusterService", mcsKObj, "service", serviceName)
+			return ctrl.Result{}, err
+		}
+		if !hasNodeNames {
+			// The endpoints without node names are never routed to under the Local policy; keep the Cluster policy
+			// instead of dropping all the traffic.
+			klog.V(2).InfoS("None of the imported endpoints has a node name; keeping the Cluster internal traffic policy", "multiClusterService", mcsKObj, "service", serviceName)
+			r.Recorder.Eventf(mcs, corev1.EventTypeWarning, "InternalTrafficPolicyNotApplied", "None of the imported endpoints of %s service has a node name; the Local internal traffic policy is not applied", serviceImport.Name)
+			internalTrafficPolicy = corev1.ServiceInternalTrafficPolicyCluster
+		}
+	}
+
+	ipFamilies := desiredIPFamilies(mcs, serviceImport)
+	deleted, err := r.deleteDerivedServiceOnPrimaryIPFamilyChange(ctx, serviceName, ipFamilies)
+	if err != nil {
+		klog.ErrorS(err, "Failed to recreate derived service of mcs for the new primary IP family", "multiClusterService", mcsKObj, "service", serviceName, "ipFamilies", ipFamilies)
+		return ctrl.Result{}, err
+	}
+	if deleted {
+		// The derived service is created with the new primary IP family once its deletion is observed.
+		r.Recorder.Eventf(mcs, corev1.EventTypeNormal, "RecreatingDerivedService", "The primary IP family of %s service has changed to %s; recreating the derived service", serviceImport.Name, ipFamilies[0])
+		return ctrl.Result{RequeueAfter: mcsRetryInterval}, nil
+	}
+
 	service := &corev1.Service{
 		ObjectMeta: metav1.ObjectMeta{
 			Namespace: serviceName.Namespace,
@@ -265,9 +378,20 @@ func (r *Reconciler) handleUpdate(ctx context.Context, mcs *fleetnetv1alpha1.Mul
 	// 1) Create a service if not exists.
 	// OR 2) Update a service if the desired state does not match with current state.
 	// OR 3) Get a service when Service status change triggers the MCS reconcile.
-	if op, err := controllerutil.CreateOrUpdate(ctx, r.Client, service, func() error {
-		return r.ensureDerivedService(mcs, serviceImport, service)
-	}); err != nil {
+	op, err := controllerutil.CreateOrUpdate(ctx, r.Client, service, func() error {
+		return r.ensureDerivedService(mcs, serviceImport, service, internalTrafficPolicy, ipFamilies)
+	})
+	if err != nil && errors.IsInvalid(err) && ipfamily.IsDualStack(ipFamilies) {
+		// The dual-stack service is rejected if the cluster does not support dual-stack; fall back to the primary IP
+		// family instead of failing the whole import.
+		klog.V(2).InfoS("Dual-stack derived service is rejected; falling back to the primary IP family", "multiClusterService", mcsKObj, "service", klog.KObj(service), "ipFamilies", ipFamilies, "err", err)
+		r.Recorder.Eventf(mcs, corev1.EventTypeWarning, "DualStackNotSupported", "The cluster does not support dual-stack services; %s service is imported as a %s single-stack service", serviceImport.Name, ipFamilies[0])
+		ipFamilies = ipFamilies[:1]
+		op, err = controllerutil.CreateOrUpdate(ctx, r.Client, service, func() error {
+			return r.ensureDerivedService(mcs, serviceImport, service, internalTrafficPolicy, ipFamilies)
+		})
+	}
+	if err != nil {
 		klog.ErrorS(err, "Failed to create or update derived service of mcs", "multiClusterService", mcsKObj, "service", klog.KObj(service), "op", op)
 		return ctrl.Result{}, err
 	}
@@ -278,6 +402,23 @@ func (r *Reconciler) handleUpdate(ctx context.Context, mcs *fleetnetv1alpha1.Mul
 	return ctrl.Result{}, nil
 }
 
+// handleServiceImportOwnedByOthers updates the mcs status when the service import is owned by another mcs, and
+// requeues the request to see if the service import is released by its owner.
+func (r *Reconciler) handleServiceImportOwnedByOthers(ctx context.Context, mcs *fleetnetv1alpha1.MultiClusterService, serviceImport *fleetnetv1alpha1.ServiceImport) (ctrl.Result, error) {
+	mcsKObj := klog.KObj(mcs)
+	serviceImportKObj := klog.KObj(serviceImport)
+	// reset the current serviceImport to empty as input so that internal func will update mcs status based on the serviceImport status
+	// it won't change the serviceImport in the API server
+	serviceImport.Status = fleetnetv1alpha1.ServiceImportStatus{}
+	if err := r.handleInvalidServiceImport(ctx, mcs, serviceImport); err != nil {
+		klog.ErrorS(err, "Failed to update status of mcs as serviceImport has been owned by other mcs", "multiClusterService", mcsKObj, "serviceImport", serviceImportKObj, "owner", serviceImport.OwnerReferences)
+		return ctrl.Result{}, err
+	}
+	// have to requeue the request to see if the service import is deleted or released by owner or not
+	klog.V(3).InfoS("ServiceImport has been owned by other mcs and requeue the request", "multiClusterService", mcsKObj, "serviceImport", serviceImportKObj)
+	return ctrl.Result{RequeueAfter: mcsRetryInterval}, nil
+}
+
 func isServiceImportOwnedByOthers(mcs *fleetnetv1alpha1.MultiClusterService, serviceImport *fleetnetv1alpha1.ServiceImport) bool {
 	for _, owner := range serviceImport.OwnerReferences {
 		if owner.APIVersion == mcs.APIVersion &&
@@ -301,7 +442,22 @@ func (r *Reconciler) handleInvalidServiceImport(ctx context.Context, mcs *fleetn
 		return err
 	}
 	r.Recorder.Eventf(mcs, corev1.EventTypeNormal, "SuccessfulUpdateStatus", "Importing %s service and updated %s status", serviceImport.Name, mcs.Name)
+	return r.removeDerivedService(ctx, mcs)
+}
+
+// handleNoMatchingPorts deletes derived service and updates its label when none of the ports requested by the mcs is
+// found in the service import, as a load balancer service requires at least one port.
+func (r *Reconciler) handleNoMatchingPorts(ctx context.Context, mcs *fleetnetv1alpha1.MultiClusterService, serviceImport *fleetnetv1alpha1.ServiceImport, missing []string) error {
+	// The derived service is about to be deleted; reset the existing mcs load balancer status.
+	if err := r.updateMultiClusterServiceStatus(ctx, mcs, serviceImport, &corev1.Service{}); err != nil {
+		return err
+	}
+	r.Recorder.Eventf(mcs, corev1.EventTypeWarning, conditionReasonNoMatchingPorts, "None of the requested ports %v is found in %s service", missing, serviceImport.Name)
+	return r.removeDerivedService(ctx, mcs)
+}
 
+// removeDerivedService deletes the derived service of the mcs, if any, and removes the derived service label.
+func (r *Reconciler) removeDerivedService(ctx context.Context, mcs *fleetnetv1alpha1.MultiClusterService) error {
 	serviceName := r.derivedServiceFromLabel(mcs)
 	mcsKObj := klog.KObj(mcs)
 	if serviceName == nil {
@@ -352,9 +508,147 @@ func configureInternalLoadBalancer(mcs *fleetnetv1alpha1.MultiClusterService, se
 	service.Annotations[serviceAnnotationInternalLoadBalancer] = "true"
 }
 
-func (r *Reconciler) ensureDerivedService(mcs *fleetnetv1alpha1.MultiClusterService, serviceImport *fleetnetv1alpha1.ServiceImport, service *corev1.Service) error {
-	svcPorts := make([]corev1.ServicePort, len(serviceImport.Status.Ports))
-	for i, importPort := range serviceImport.Status.Ports {
+func configureLoadBalancerIdleTimeout(mcs *fleetnetv1alpha1.MultiClusterService, service *corev1.Service) {
+	if mcs.Spec.IdleTimeoutMinutes == nil {
+		delete(service.Annotations, serviceAnnotationLoadBalancerIdleTimeout)
+		return
+	}
+	if service.GetAnnotations() == nil { // in case annotation map is nil
+		service.Annotations = map[string]string{}
+	}
+	service.Annotations[serviceAnnotationLoadBalancerIdleTimeout] = strconv.Itoa(int(*mcs.Spec.IdleTimeoutMinutes))
+}
+
+func configureExternalTrafficPolicy(mcs *fleetnetv1alpha1.MultiClusterService, service *corev1.Service) {
+	policy := mcs.Spec.ExternalTrafficPolicy
+	if policy == "" && service.Spec.ExternalTrafficPolicy == corev1.ServiceExternalTrafficPolicyLocal {
+		// The policy has been removed from the mcs; revert the derived service to the default policy.
+		policy = corev1.ServiceExternalTrafficPolicyCluster
+	}
+	if policy == "" {
+		return
+	}
+	service.Spec.ExternalTrafficPolicy = policy
+	if policy != corev1.ServiceExternalTrafficPolicyLocal {
+		// The health check node port is only allowed when the policy is Local.
+		service.Spec.HealthCheckNodePort = 0
+		return
+	}
+	// If the health check node port is not specified, keep the one allocated to the derived service.
+	if mcs.Spec.HealthCheckNodePort != nil {
+		service.Spec.HealthCheckNodePort = *mcs.Spec.HealthCheckNodePort
+	}
+}
+
+func configureInternalTrafficPolicy(policy corev1.ServiceInternalTrafficPolicy, service *corev1.Service) {
+	if policy == "" {
+		if service.Spec.InternalTrafficPolicy == nil || *service.Spec.InternalTrafficPolicy != corev1.ServiceInternalTrafficPolicyLocal {
+			return
+		}
+		// The policy has been removed from the mcs; revert the derived service to the default policy.
+		policy = corev1.ServiceInternalTrafficPolicyCluster
+	}
+	service.Spec.InternalTrafficPolicy = &policy
+}
+
+// desiredIPFamilies returns the IP families to request on the derived service. A dual-stack service import falls back
+// to its primary family once the cluster has rejected the dual-stack derived service, as recorded by the IP families
+// condition, so that the rejected request is not repeated on every reconcile.
+func desiredIPFamilies(mcs *fleetnetv1alpha1.MultiClusterService, serviceImport *fleetnetv1alpha1.ServiceImport) []corev1.IPFamily {
+	families := serviceImport.Status.IPFamilies
+	if !ipfamily.IsDualStack(families) {
+		return families
+	}
+	cond := meta.FindStatusCondition(mcs.Status.Conditions, string(fleetnetv1alpha1.MultiClusterServiceIPFamiliesApplied))
+	if cond != nil && cond.Status == metav1.ConditionFalse && cond.Reason == conditionReasonDualStackNotSupported {
+		return families[:1]
+	}
+	return families
+}
+
+// deleteDerivedServiceOnPrimaryIPFamilyChange deletes the derived service if its primary IP family differs from the
+// requested one; the primary IP family of a service is immutable, so the derived service has to be recreated.
+func (r *Reconciler) deleteDerivedServiceOnPrimaryIPFamilyChange(ctx context.Context, serviceName *types.NamespacedName, families []corev1.IPFamily) (bool, error) {
+	if len(families) == 0 {
+		return false, nil
+	}
+	service := &corev1.Service{}
+	if err := r.Client.Get(ctx, *serviceName, service); err != nil {
+		return false, client.IgnoreNotFound(err)
+	}
+	if service.DeletionTimestamp != nil {
+		// Wait for the deletion to complete.
+		return true, nil
+	}
+	if len(service.Spec.IPFamilies) == 0 || service.Spec.IPFamilies[0] == families[0] {
+		return false, nil
+	}
+	klog.V(2).InfoS("Deleting the derived service to change its primary IP family", "service", klog.KObj(service), "ipFamilies", service.Spec.IPFamilies, "desiredIPFamilies", families)
+	if err := r.Client.Delete(ctx, service); err != nil && !errors.IsNotFound(err) {
+		return false, err
+	}
+	return true, nil
+}
+
+// configureIPFamilies requests the IP families of the exported services on the derived service; a dual-stack service
+// requires dual-stack, so that the API server rejects it instead of silently dropping a family.
+func configureIPFamilies(families []corev1.IPFamily, service *corev1.Service) {
+	if len(families) == 0 {
+		// The exporting clusters do not report the families; leave them to the cluster defaults.
+		return
+	}
+	policy := corev1.IPFamilyPolicySingleStack
+	if ipfamily.IsDualStack(families) {
+		policy = corev1.IPFamilyPolicyRequireDualStack
+	}
+	service.Spec.IPFamilyPolicy = &policy
+	service.Spec.IPFamilies = slices.Clone(families)
+	if len(service.Spec.ClusterIPs) > len(families) {
+		// Release the cluster IP of the dropped family when downgrading to single-stack.
+		service.Spec.ClusterIPs = service.Spec.ClusterIPs[:len(families)]
+	}
+}
+
+// internalTrafficPolicyOf returns the internal traffic policy in effect on the derived service.
+func internalTrafficPolicyOf(service *corev1.Service) corev1.ServiceInternalTrafficPolicy {
+	if service.Spec.InternalTrafficPolicy == nil {
+		return corev1.ServiceInternalTrafficPolicyCluster
+	}
+	return *service.Spec.InternalTrafficPolicy
+}
+
+// importedEndpointsHaveNodeNames returns whether any endpoint imported for the derived service carries a node name.
+func (r *Reconciler) importedEndpointsHaveNodeNames(ctx context.Context, serviceName *types.NamespacedName) (bool, error) {
+	endpointSliceList := &discoveryv1.EndpointSliceList{}
+	if err := r.Client.List(ctx, endpointSliceList, client.InNamespace(serviceName.Namespace), client.MatchingLabels{discoveryv1.LabelServiceName: serviceName.Name}); err != nil {
+		return false, err
+	}
+	for i := range endpointSliceList.Items {
+		for _, endpoint := range endpointSliceList.Items[i].Endpoints {
+			if endpoint.NodeName != nil && *endpoint.NodeName != "" {
+				return true, nil
+			}
+		}
+	}
+	return false, nil
+}
+
+// loadBalancerIdleTimeoutMinutes returns the TCP idle timeout in effect on the load balancer of the derived service.
+func loadBalancerIdleTimeoutMinutes(service *corev1.Service) int32 {
+	if service.Name == "" { // there is no derived service
+		return 0
+	}
+	timeout, err := strconv.ParseInt(service.Annotations[serviceAnnotationLoadBalancerIdleTimeout], 10, 32)
+	if err != nil {
+		return azureLoadBalancerDefaultIdleTimeoutMinutes
+	}
+	return int32(timeout)
+}
+
+func (r *Reconciler) ensureDerivedService(mcs *fleetnetv1alpha1.MultiClusterService, serviceImport *fleetnetv1alpha1.ServiceImport, service *corev1.Service, internalTrafficPolicy corev1.ServiceInternalTrafficPolicy, ipFamilies []corev1.IPFamily) error {
+	importPorts, _ := filterServiceImportPorts(mcs.Spec.Ports, serviceImport.Status.Ports)
+	svcPorts := make([]corev1.ServicePort, len(importPorts))
+	for i, importPort := range importPorts {
 		svcPorts[i] = importPort.ToServicePort()
 	}
 	service.Spec.Ports = svcPorts
@@ -367,9 +661,67 @@ func (r *Reconciler) ensureDerivedService(mcs *fleetnetv1alpha1.MultiClusterServ
 	service.Labels[serviceLabelMCSName] = mcs.Name
 	service.Labels[serviceLabelMCSNamespace] = mcs.Namespace
 	configureInternalLoadBalancer(mcs, service)
+	configureLoadBalancerIdleTimeout(mcs, service)
+	configureExternalTrafficPolicy(mcs, service)
+	configureInternalTrafficPolicy(internalTrafficPolicy, service)
+	configureIPFamilies(ipFamilies, service)
+	setDerivedServiceProvenance(serviceImport, service)
 	return nil
 }
 
+// setDerivedServiceProvenance records the clusters exporting the services behind the derived service; the services
+// are exported under the namespace and name of the service import.
+func setDerivedServiceProvenance(serviceImport *fleetnetv1alpha1.ServiceImport, service *corev1.Service) {
+	sources := make([]objectmeta.ProvenanceSource, 0, len(serviceImport.Status.Clusters))
+	for _, cluster := range serviceImport.Status.Clusters {
+		sources = append(sources, objectmeta.ProvenanceSource{
+			ClusterID: cluster.Cluster,
+			Namespace: serviceImport.Namespace,
+			Name:      serviceImport.Name,
+		})
+	}
+	objectmeta.SetProvenanceAnnotations(service, sources)
+}
+
+// filterServiceImportPorts returns the service import ports selected by the ports filter, together with the requested
+// ports which cannot be found in the service import. A port is selected if either its name or its port number is
+// requested; all the ports are selected if the filter is empty.
+func filterServiceImportPorts(filter []intstr.IntOrString, importPorts []fleetnetv1alpha1.ServicePort) ([]fleetnetv1alpha1.ServicePort, []string) {
+	if len(filter) == 0 {
+		return importPorts, nil
+	}
+	selected := make([]fleetnetv1alpha1.ServicePort, 0, len(importPorts))
+	for _, importPort := range importPorts {
+		for _, requested := range filter {
+			if portMatches(requested, importPort) {
+				selected = append(selected, importPort)
+				break
+			}
+		}
+	}
+	var missing []string
+	for _, requested := range filter {
+		found := false
+		for _, importPort := range importPorts {
+			if portMatches(requested, importPort) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			missing = append(missing, requested.String())
+		}
+	}
+	return selected, missing
+}
+
+func portMatches(requested intstr.IntOrString, importPort fleetnetv1alpha1.ServicePort) bool {
+	if requested.Type == intstr.Int {
+		return requested.IntVal == importPort.Port
+	}
+	return requested.StrVal == importPort.Name
+}
+
 // generateDerivedServiceName appends multiclusterservice name and namespace as the derived service name since a service
 // import may be exported by the multiple MCSs.
 // It makes sure the service name is unique and less than 63 characters.
@@ -388,7 +740,9 @@ func (r *Reconciler) updateMultiClusterServiceStatus(ctx context.Context, mcs *f
 		ObservedGeneration: mcs.GetGeneration(),
 		Message:            "found valid service import",
 	}
-	if len(serviceImport.Status.Clusters) == 0 {
+	selectedPorts, missingPorts := filterServiceImportPorts(mcs.Spec.Ports, serviceImport.Status.Ports)
+	switch {
+	case len(serviceImport.Status.Clusters) == 0:
 		desiredCond = &metav1.Condition{
 			Type:               string(fleetnetv1alpha1.MultiClusterServiceValid),
 			Status:             metav1.ConditionUnknown,
@@ -396,16 +750,116 @@ func (r *Reconciler) updateMultiClusterServiceStatus(ctx context.Context, mcs *f
 			ObservedGeneration: mcs.GetGeneration(),
 			Message:            "importing service; if the condition remains for a while, please verify that service has been exported or service has been exported by other multiClusterService",
 		}
+	case len(mcs.Spec.Ports) != 0 && len(selectedPorts) == 0:
+		desiredCond = &metav1.Condition{
+			Type:               string(fleetnetv1alpha1.MultiClusterServiceValid),
+			Status:             metav1.ConditionFalse,
+			Reason:             conditionReasonNoMatchingPorts,
+			ObservedGeneration: mcs.GetGeneration(),
+			Message:            fmt.Sprintf("none of the requested ports %v is found in the service import; the service is not imported", missingPorts),
+		}
+	}
+
+	// The ports condition is only reported when a ports filter is specified and the service import is valid.
+	currentPortsCond := meta.FindStatusCondition(mcs.Status.Conditions, string(fleetnetv1alpha1.MultiClusterServicePortsFound))
+	var desiredPortsCond *metav1.Condition
+	if len(mcs.Spec.Ports) != 0 && len(serviceImport.Status.Clusters) != 0 {
+		desiredPortsCond = &metav1.Condition{
+			Type:               string(fleetnetv1alpha1.MultiClusterServicePortsFound),
+			Status:             metav1.ConditionTrue,
+			Reason:             conditionReasonFoundPorts,
+			ObservedGeneration: mcs.GetGeneration(),
+			Message:            "found all the requested ports in the service import",
+		}
+		if len(missingPorts) != 0 {
+			desiredPortsCond = &metav1.Condition{
+				Type:               string(fleetnetv1alpha1.MultiClusterServicePortsFound),
+				Status:             metav1.ConditionFalse,
+				Reason:             conditionReasonPortNotFound,
+				ObservedGeneration: mcs.GetGeneration(),
+				Message:            fmt.Sprintf("requested ports %v are not found in the service import", missingPorts),
+			}
+		}
+	}
+
+	// The internal traffic policy condition is only reported when a policy is specified and the derived service exists.
+	currentPolicyCond := meta.FindStatusCondition(mcs.Status.Conditions, string(fleetnetv1alpha1.MultiClusterServiceInternalTrafficPolicyApplied))
+	var desiredPolicyCond *metav1.Condition
+	if mcs.Spec.InternalTrafficPolicy != "" && service.Name != "" {
+		desiredPolicyCond = &metav1.Condition{
+			Type:               string(fleetnetv1alpha1.MultiClusterServiceInternalTrafficPolicyApplied),
+			Status:             metav1.ConditionTrue,
+			Reason:             conditionReasonInternalTrafficPolicyApplied,
+			ObservedGeneration: mcs.GetGeneration(),
+			Message:            fmt.Sprintf("applied the %s internal traffic policy to the derived service", mcs.Spec.InternalTrafficPolicy),
+		}
+		if internalTrafficPolicyOf(service) != mcs.Spec.InternalTrafficPolicy {
+			desiredPolicyCond = &metav1.Condition{
+				Type:               string(fleetnetv1alpha1.MultiClusterServiceInternalTrafficPolicyApplied),
+				Status:             metav1.ConditionFalse,
+				Reason:             conditionReasonEndpointsMissingNodeNames,
+				ObservedGeneration: mcs.GetGeneration(),
+				Message:            "none of the imported endpoints has a node name and the traffic would be dropped; using the Cluster internal traffic policy instead",
+			}
+		}
+	}
+
+	// The IP families condition is only reported when the service import is dual-stack and the derived service exists.
+	currentFamiliesCond := meta.FindStatusCondition(mcs.Status.Conditions, string(fleetnetv1alpha1.MultiClusterServiceIPFamiliesApplied))
+	var desiredFamiliesCond *metav1.Condition
+	if ipfamily.IsDualStack(serviceImport.Status.IPFamilies) && service.Name != "" {
+		desiredFamiliesCond = &metav1.Condition{
+			Type:               string(fleetnetv1alpha1.MultiClusterServiceIPFamiliesApplied),
+			Status:             metav1.ConditionTrue,
+			Reason:             conditionReasonIPFamiliesApplied,
+			ObservedGeneration: mcs.GetGeneration(),
+			Message:            fmt.Sprintf("applied the %v IP families to the derived service", serviceImport.Status.IPFamilies),
+		}
+		if !ipfamily.IsDualStack(service.Spec.IPFamilies) {
+			desiredFamiliesCond = &metav1.Condition{
+				Type:               string(fleetnetv1alpha1.MultiClusterServiceIPFamiliesApplied),
+				Status:             metav1.ConditionFalse,
+				Reason:             conditionReasonDualStackNotSupported,
+				ObservedGeneration: mcs.GetGeneration(),
+				Message:            fmt.Sprintf("the cluster does not support dual-stack services; using the %v IP families instead", service.Spec.IPFamilies),
+			}
+		}
 	}
 
 	mcsKObj := klog.KObj(mcs)
+	idleTimeoutMinutes := loadBalancerIdleTimeoutMinutes(service)
 	if equality.Semantic.DeepEqual(mcs.Status.LoadBalancer, service.Status.LoadBalancer) &&
-		condition.EqualCondition(currentCond, desiredCond) {
+		mcs.Status.HealthCheckNodePort == service.Spec.HealthCheckNodePort &&
+		mcs.Status.IdleTimeoutMinutes == idleTimeoutMinutes &&
+		mcs.Status.FleetSystemNamespace == service.Namespace &&
+		condition.EqualCondition(currentCond, desiredCond) &&
+		condition.EqualCondition(currentPortsCond, desiredPortsCond) &&
+		condition.EqualCondition(currentPolicyCond, desiredPolicyCond) &&
+		condition.EqualCondition(currentFamiliesCond, desiredFamiliesCond) {
 		klog.V(4).InfoS("Status is in the desired state and skipping updating status", "multiClusterService", mcsKObj)
 		return nil
 	}
 	mcs.Status.LoadBalancer = service.Status.LoadBalancer
+	mcs.Status.HealthCheckNodePort = service.Spec.HealthCheckNodePort
+	mcs.Status.IdleTimeoutMinutes = idleTimeoutMinutes
+	// Record where the derived Service lives, so that the debugging tools know where to look.
+	mcs.Status.FleetSystemNamespace = service.Namespace
 	meta.SetStatusCondition(&mcs.Status.Conditions, *desiredCond)
+	if desiredPortsCond != nil {
+		meta.SetStatusCondition(&mcs.Status.Conditions, *desiredPortsCond)
+	} else {
+		meta.RemoveStatusCondition(&mcs.Status.Conditions, string(fleetnetv1alpha1.MultiClusterServicePortsFound))
+	}
+	if desiredPolicyCond != nil {
+		meta.SetStatusCondition(&mcs.Status.Conditions, *desiredPolicyCond)
+	} else {
+		meta.RemoveStatusCondition(&mcs.Status.Conditions, string(fleetnetv1alpha1.MultiClusterServiceInternalTrafficPolicyApplied))
+	}
+	if desiredFamiliesCond != nil {
+		meta.SetStatusCondition(&mcs.Status.Conditions, *desiredFamiliesCond)
+	} else {
+		meta.RemoveStatusCondition(&mcs.Status.Conditions, string(fleetnetv1alpha1.MultiClusterServiceIPFamiliesApplied))
+	}
 
 	klog.V(2).InfoS("Updating mcs status", "multiClusterService", mcsKObj)
 	if err := r.Status().Update(ctx, mcs); err != nil {
@@ -426,18 +880,25 @@ func (r *Reconciler) SetupWithManager(mgr ctrl.Manager) error {
 		// filtering logic to enqueue those service event.
 		Watches(
 			&corev1.Service{},
-			handler.EnqueueRequestsFromMapFunc(r.serviceEventHandler()),
+			handler.EnqueueRequestsFromMapFunc(derivedObjectEventHandler(r.FleetSystemNamespace)),
+		).
+		// The node names of the imported endpoints decide whether the Local internal traffic policy can be applied.
+		Watches(
+			&discoveryv1.EndpointSlice{},
+			handler.EnqueueRequestsFromMapFunc(r.endpointSliceEventHandler()),
 		).
 		Complete(r)
 }
 
-func (r *Reconciler) serviceEventHandler() handler.MapFunc {
+// derivedObjectEventHandler enqueues the mcs an object in the fleet system namespace, i.e. a derived service or its
+// referenceGrant, is labeled with.
+func derivedObjectEventHandler(fleetSystemNamespace string) handler.MapFunc {
 	return func(_ context.Context, object client.Object) []reconcile.Request {
 		namespace := object.GetLabels()[serviceLabelMCSNamespace]
 		name := object.GetLabels()[serviceLabelMCSName]
 
-		// ignore any service which is not in the fleet system namespace and does not have two labels
-		if object.GetNamespace() != r.FleetSystemNamespace || namespace == "" || name == "" {
+		// ignore any object which is not in the fleet system namespace and does not have two labels
+		if object.GetNamespace() != fleetSystemNamespace || namespace == "" || name == "" {
 			return []reconcile.Request{}
 		}
 		return []reconcile.Request{
@@ -447,3 +908,27 @@ func (r *Reconciler) serviceEventHandler() handler.MapFunc {
 		}
 	}
 }
+
+// endpointSliceEventHandler enqueues the mcs of the derived service an imported endpointSlice belongs to, if the mcs
+// requests the Local internal traffic policy.
+func (r *Reconciler) endpointSliceEventHandler() handler.MapFunc {
+	return func(ctx context.Context, object client.Object) []reconcile.Request {
+		serviceName := object.GetLabels()[discoveryv1.LabelServiceName]
+		if object.GetNamespace() != r.FleetSystemNamespace || serviceName == "" {
+			return []reconcile.Request{}
+		}
+		service := &corev1.Service{}
+		if err := r.Client.Get(ctx, types.NamespacedName{Namespace: r.FleetSystemNamespace, Name: serviceName}, service); err != nil {
+			return []reconcile.Request{}
+		}
+		mcsName := types.NamespacedName{Namespace: service.Labels[serviceLabelMCSNamespace], Name: service.Labels[serviceLabelMCSName]}
+		if mcsName.Namespace == "" || mcsName.Name == "" {
+			return []reconcile.Request{}
+		}
+		mcs := &fleetnetv1alpha1.MultiClusterService{}
+		if err := r.Client.Get(ctx, mcsName, mcs); err != nil || mcs.Spec.InternalTrafficPolicy != corev1.ServiceInternalTrafficPolicyLocal {
+			return []reconcile.Request{}
+		}
+		return []reconcile.Request{{NamespacedName: mcsName}}
+	}
+}
@@ -15,8 +15,10 @@ import (
 	. "github.com/onsi/gomega"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/intstr"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	fleetnetv1alpha1 "go.goms.io/fleet-networking/api/v1alpha1"
@@ -337,4 +339,429 @@ var _ = Describe("Test MultiClusterService Controller", func() {
 			}, timeout, interval).Should(BeTrue())
 		})
 	})
+
+	Context("When MultiClusterServices reference a ServiceImport", func() {
+		serviceImportOwner := func(serviceImportKey types.NamespacedName) (string, error) {
+			serviceImport := &fleetnetv1alpha1.ServiceImport{}
+			if err := k8sClient.Get(ctx, serviceImportKey, serviceImport); err != nil {
+				return "", err
+			}
+			if owner := metav1.GetControllerOf(serviceImport); owner != nil {
+				return owner.Name, nil
+			}
+			return "", nil
+		}
+		serviceImportReferences := func(serviceImportKey types.NamespacedName) (string, error) {
+			serviceImport := &fleetnetv1alpha1.ServiceImport{}
+			if err := k8sClient.Get(ctx, serviceImportKey, serviceImport); err != nil {
+				return "", err
+			}
+			return serviceImport.Annotations[objectmeta.ServiceImportAnnotationMultiClusterServices], nil
+		}
+		deleteMultiClusterService := func(mcs *fleetnetv1alpha1.MultiClusterService) {
+			Expect(k8sClient.Delete(ctx, mcs)).Should(Succeed())
+			Eventually(func() bool {
+				return errors.IsNotFound(k8sClient.Get(ctx, types.NamespacedName{Namespace: mcs.Namespace, Name: mcs.Name}, &fleetnetv1alpha1.MultiClusterService{}))
+			}, timeout, interval).Should(BeTrue())
+		}
+
+		It("Should delete the service import with its only mcs", func() {
+			serviceImportKey := types.NamespacedName{Namespace: testNamespace, Name: "single-owner-svc"}
+			mcs := multiClusterServiceForTest()
+			mcs.Name = "single-owner-mcs"
+			mcs.Spec.ServiceImport.Name = serviceImportKey.Name
+			Expect(k8sClient.Create(ctx, mcs)).Should(Succeed())
+
+			By("By checking the service import is owned and referenced by the mcs")
+			Eventually(func() (string, error) {
+				return serviceImportOwner(serviceImportKey)
+			}, timeout, interval).Should(Equal(mcs.Name))
+			Expect(serviceImportReferences(serviceImportKey)).Should(Equal(mcs.Name))
+
+			By("By deleting mcs")
+			deleteMultiClusterService(mcs)
+
+			By("By checking the service import is deleted")
+			Eventually(func() bool {
+				return errors.IsNotFound(k8sClient.Get(ctx, serviceImportKey, &fleetnetv1alpha1.ServiceImport{}))
+			}, timeout, interval).Should(BeTrue())
+		})
+
+		It("Should keep the service import until the last mcs referencing it is deleted", func() {
+			serviceImportKey := types.NamespacedName{Namespace: testNamespace, Name: "shared-svc"}
+			firstMCS := multiClusterServiceForTest()
+			firstMCS.Name = "shared-mcs-a"
+			firstMCS.Spec.ServiceImport.Name = serviceImportKey.Name
+			Expect(k8sClient.Create(ctx, firstMCS)).Should(Succeed())
+			Eventually(func() (string, error) {
+				return serviceImportOwner(serviceImportKey)
+			}, timeout, interval).Should(Equal(firstMCS.Name))
+
+			secondMCS := multiClusterServiceForTest()
+			secondMCS.Name = "shared-mcs-b"
+			secondMCS.Spec.ServiceImport.Name = serviceImportKey.Name
+			Expect(k8sClient.Create(ctx, secondMCS)).Should(Succeed())
+
+			By("By checking the service import is referenced by both mcs")
+			Eventually(func() (string, error) {
+				return serviceImportReferences(serviceImportKey)
+			}, timeout, interval).Should(Equal(firstMCS.Name + "," + secondMCS.Name))
+			Expect(serviceImportOwner(serviceImportKey)).Should(Equal(firstMCS.Name))
+
+			By("By deleting the owner mcs")
+			deleteMultiClusterService(firstMCS)
+
+			By("By checking the service import is taken over by the other mcs")
+			Eventually(func() (string, error) {
+				return serviceImportOwner(serviceImportKey)
+			}, timeout, interval).Should(Equal(secondMCS.Name))
+			Expect(serviceImportReferences(serviceImportKey)).Should(Equal(secondMCS.Name))
+
+			By("By deleting the last mcs")
+			deleteMultiClusterService(secondMCS)
+
+			By("By checking the service import is deleted")
+			Eventually(func() bool {
+				return errors.IsNotFound(k8sClient.Get(ctx, serviceImportKey, &fleetnetv1alpha1.ServiceImport{}))
+			}, timeout, interval).Should(BeTrue())
+		})
+	})
+
+	Context("When creating new MultiClusterService with load balancer settings", func() {
+		It("Should configure and update the derived service", func() {
+			healthCheckNodePort := int32(30080)
+			idleTimeoutMinutes := int32(30)
+
+			By("By creating a new MultiClusterService")
+			multiClusterService := multiClusterServiceForTest()
+			multiClusterService.Spec.ExternalTrafficPolicy = corev1.ServiceExternalTrafficPolicyLocal
+			multiClusterService.Spec.HealthCheckNodePort = &healthCheckNodePort
+			multiClusterService.Spec.IdleTimeoutMinutes = &idleTimeoutMinutes
+			Expect(k8sClient.Create(ctx, multiClusterService)).Should(Succeed())
+
+			By("By checking service import")
+			serviceImportLookupKey := types.NamespacedName{Name: testServiceName, Namespace: testNamespace}
+			createdServiceImport := &fleetnetv1alpha1.ServiceImport{}
+			Eventually(func() error {
+				return k8sClient.Get(ctx, serviceImportLookupKey, createdServiceImport)
+			}, timeout, interval).Should(Succeed())
+
+			By("By updating service import status")
+			createdServiceImport.Status = fleetnetv1alpha1.ServiceImportStatus{
+				Type: fleetnetv1alpha1.ClusterSetIP,
+				Clusters: []fleetnetv1alpha1.ClusterStatus{
+					{
+						Cluster: "member1",
+					},
+				},
+				Ports: []fleetnetv1alpha1.ServicePort{
+					{
+						Name:     "http",
+						Port:     8080,
+						Protocol: corev1.ProtocolTCP,
+					},
+				},
+			}
+			Expect(k8sClient.Status().Update(ctx, createdServiceImport)).Should(Succeed())
+
+			mcsLookupKey := types.NamespacedName{Name: testName, Namespace: testNamespace}
+			createdMultiClusterService := &fleetnetv1alpha1.MultiClusterService{}
+			Eventually(func() bool {
+				if err := k8sClient.Get(ctx, mcsLookupKey, createdMultiClusterService); err != nil {
+					return false
+				}
+				return createdMultiClusterService.GetLabels()[objectmeta.MultiClusterServiceLabelDerivedService] != ""
+			}, timeout, interval).Should(BeTrue())
+			derivedServiceLookupKey := types.NamespacedName{Name: createdMultiClusterService.GetLabels()[objectmeta.MultiClusterServiceLabelDerivedService], Namespace: systemNamespace}
+
+			By("By checking derived service")
+			createdService := &corev1.Service{}
+			Eventually(func() error {
+				if err := k8sClient.Get(ctx, derivedServiceLookupKey, createdService); err != nil {
+					return err
+				}
+				if got := createdService.Annotations[serviceAnnotationLoadBalancerIdleTimeout]; got != "30" {
+					return fmt.Errorf("idle timeout annotation got %q, want %q", got, "30")
+				}
+				if createdService.Spec.ExternalTrafficPolicy != corev1.ServiceExternalTrafficPolicyLocal {
+					return fmt.Errorf("externalTrafficPolicy got %v, want %v", createdService.Spec.ExternalTrafficPolicy, corev1.ServiceExternalTrafficPolicyLocal)
+				}
+				if createdService.Spec.HealthCheckNodePort != healthCheckNodePort {
+					return fmt.Errorf("healthCheckNodePort got %d, want %d", createdService.Spec.HealthCheckNodePort, healthCheckNodePort)
+				}
+				return nil
+			}, timeout, interval).Should(Succeed())
+
+			By("By checking mcs status")
+			Eventually(func() error {
+				if err := k8sClient.Get(ctx, mcsLookupKey, createdMultiClusterService); err != nil {
+					return err
+				}
+				if got := createdMultiClusterService.Status.HealthCheckNodePort; got != healthCheckNodePort {
+					return fmt.Errorf("status healthCheckNodePort got %d, want %d", got, healthCheckNodePort)
+				}
+				if got := createdMultiClusterService.Status.IdleTimeoutMinutes; got != idleTimeoutMinutes {
+					return fmt.Errorf("status idleTimeoutMinutes got %d, want %d", got, idleTimeoutMinutes)
+				}
+				return nil
+			}, timeout, interval).Should(Succeed())
+
+			By("By updating the load balancer settings of mcs")
+			Eventually(func() error {
+				if err := k8sClient.Get(ctx, mcsLookupKey, createdMultiClusterService); err != nil {
+					return err
+				}
+				createdMultiClusterService.Spec.ExternalTrafficPolicy = ""
+				createdMultiClusterService.Spec.HealthCheckNodePort = nil
+				createdMultiClusterService.Spec.IdleTimeoutMinutes = nil
+				return k8sClient.Update(ctx, createdMultiClusterService)
+			}, timeout, interval).Should(Succeed())
+
+			By("By checking derived service is updated in place")
+			Eventually(func() error {
+				if err := k8sClient.Get(ctx, derivedServiceLookupKey, createdService); err != nil {
+					return err
+				}
+				if got, ok := createdService.Annotations[serviceAnnotationLoadBalancerIdleTimeout]; ok {
+					return fmt.Errorf("idle timeout annotation got %q, want not set", got)
+				}
+				if createdService.Spec.ExternalTrafficPolicy != corev1.ServiceExternalTrafficPolicyCluster {
+					return fmt.Errorf("externalTrafficPolicy got %v, want %v", createdService.Spec.ExternalTrafficPolicy, corev1.ServiceExternalTrafficPolicyCluster)
+				}
+				if createdService.Spec.HealthCheckNodePort != 0 {
+					return fmt.Errorf("healthCheckNodePort got %d, want 0", createdService.Spec.HealthCheckNodePort)
+				}
+				return nil
+			}, timeout, interval).Should(Succeed())
+
+			By("By checking mcs status")
+			Eventually(func() error {
+				if err := k8sClient.Get(ctx, mcsLookupKey, createdMultiClusterService); err != nil {
+					return err
+				}
+				if got := createdMultiClusterService.Status.HealthCheckNodePort; got != 0 {
+					return fmt.Errorf("status healthCheckNodePort got %d, want 0", got)
+				}
+				if got := createdMultiClusterService.Status.IdleTimeoutMinutes; got != azureLoadBalancerDefaultIdleTimeoutMinutes {
+					return fmt.Errorf("status idleTimeoutMinutes got %d, want %d", got, azureLoadBalancerDefaultIdleTimeoutMinutes)
+				}
+				return nil
+			}, timeout, interval).Should(Succeed())
+
+			By("By rejecting an idle timeout out of range")
+			Expect(k8sClient.Get(ctx, mcsLookupKey, createdMultiClusterService)).Should(Succeed())
+			outOfRangeIdleTimeoutMinutes := int32(31)
+			createdMultiClusterService.Spec.IdleTimeoutMinutes = &outOfRangeIdleTimeoutMinutes
+			Expect(k8sClient.Update(ctx, createdMultiClusterService)).ShouldNot(Succeed())
+
+			By("By deleting mcs")
+			Expect(k8sClient.Delete(ctx, multiClusterService)).Should(Succeed())
+
+			By("By checking derived Service in the fleet-system")
+			Eventually(func() (int, error) {
+				serviceList := &corev1.ServiceList{}
+				if err := k8sClient.List(ctx, serviceList, &client.ListOptions{Namespace: systemNamespace}); err != nil {
+					return -1, err
+				}
+				return len(serviceList.Items), nil
+			}, duration, interval).Should(Equal(0))
+
+			By("By checking mcs")
+			Eventually(func() bool {
+				return errors.IsNotFound(k8sClient.Get(ctx, mcsLookupKey, createdMultiClusterService))
+			}, timeout, interval).Should(BeTrue())
+		})
+	})
+
+	Context("When creating new MultiClusterService with a ports filter", func() {
+		It("Should restrict the ports of the derived service", func() {
+			derivedServicePortNames := func(service *corev1.Service) []string {
+				names := make([]string, 0, len(service.Spec.Ports))
+				for _, port := range service.Spec.Ports {
+					names = append(names, port.Name)
+				}
+				return names
+			}
+
+			By("By creating a new MultiClusterService")
+			multiClusterService := multiClusterServiceForTest()
+			multiClusterService.Spec.Ports = []intstr.IntOrString{intstr.FromString("http"), intstr.FromInt32(9090)}
+			Expect(k8sClient.Create(ctx, multiClusterService)).Should(Succeed())
+
+			By("By checking service import")
+			serviceImportLookupKey := types.NamespacedName{Name: testServiceName, Namespace: testNamespace}
+			createdServiceImport := &fleetnetv1alpha1.ServiceImport{}
+			Eventually(func() error {
+				return k8sClient.Get(ctx, serviceImportLookupKey, createdServiceImport)
+			}, timeout, interval).Should(Succeed())
+
+			By("By updating service import status")
+			createdServiceImport.Status = fleetnetv1alpha1.ServiceImportStatus{
+				Type: fleetnetv1alpha1.ClusterSetIP,
+				Clusters: []fleetnetv1alpha1.ClusterStatus{
+					{
+						Cluster: "member1",
+					},
+				},
+				Ports: []fleetnetv1alpha1.ServicePort{
+					{
+						Name:     "http",
+						Port:     8080,
+						Protocol: corev1.ProtocolTCP,
+					},
+					{
+						Name:     "https",
+						Port:     8443,
+						Protocol: corev1.ProtocolTCP,
+					},
+					{
+						Name:     "metrics",
+						Port:     9090,
+						Protocol: corev1.ProtocolTCP,
+					},
+				},
+			}
+			Expect(k8sClient.Status().Update(ctx, createdServiceImport)).Should(Succeed())
+
+			mcsLookupKey := types.NamespacedName{Name: testName, Namespace: testNamespace}
+			createdMultiClusterService := &fleetnetv1alpha1.MultiClusterService{}
+			Eventually(func() bool {
+				if err := k8sClient.Get(ctx, mcsLookupKey, createdMultiClusterService); err != nil {
+					return false
+				}
+				return createdMultiClusterService.GetLabels()[objectmeta.MultiClusterServiceLabelDerivedService] != ""
+			}, timeout, interval).Should(BeTrue())
+			derivedServiceLookupKey := types.NamespacedName{Name: createdMultiClusterService.GetLabels()[objectmeta.MultiClusterServiceLabelDerivedService], Namespace: systemNamespace}
+
+			By("By checking derived service only exposes the requested ports")
+			createdService := &corev1.Service{}
+			Eventually(func() error {
+				if err := k8sClient.Get(ctx, derivedServiceLookupKey, createdService); err != nil {
+					return err
+				}
+				if diff := cmp.Diff([]string{"http", "metrics"}, derivedServicePortNames(createdService)); diff != "" {
+					return fmt.Errorf("derived service ports mismatch (-want, +got):\n%s", diff)
+				}
+				return nil
+			}, timeout, interval).Should(Succeed())
+
+			By("By checking mcs ports condition")
+			Eventually(func() error {
+				if err := k8sClient.Get(ctx, mcsLookupKey, createdMultiClusterService); err != nil {
+					return err
+				}
+				cond := meta.FindStatusCondition(createdMultiClusterService.Status.Conditions, string(fleetnetv1alpha1.MultiClusterServicePortsFound))
+				if cond == nil || cond.Status != metav1.ConditionTrue {
+					return fmt.Errorf("ports condition got %+v, want true", cond)
+				}
+				return nil
+			}, timeout, interval).Should(Succeed())
+
+			By("By requesting a port which does not exist")
+			Eventually(func() error {
+				if err := k8sClient.Get(ctx, mcsLookupKey, createdMultiClusterService); err != nil {
+					return err
+				}
+				createdMultiClusterService.Spec.Ports = []intstr.IntOrString{intstr.FromString("https"), intstr.FromString("grpc")}
+				return k8sClient.Update(ctx, createdMultiClusterService)
+			}, timeout, interval).Should(Succeed())
+
+			By("By checking derived service is updated in place")
+			Eventually(func() error {
+				if err := k8sClient.Get(ctx, derivedServiceLookupKey, createdService); err != nil {
+					return err
+				}
+				if diff := cmp.Diff([]string{"https"}, derivedServicePortNames(createdService)); diff != "" {
+					return fmt.Errorf("derived service ports mismatch (-want, +got):\n%s", diff)
+				}
+				return nil
+			}, timeout, interval).Should(Succeed())
+
+			By("By checking mcs ports condition reports the missing port")
+			Eventually(func() error {
+				if err := k8sClient.Get(ctx, mcsLookupKey, createdMultiClusterService); err != nil {
+					return err
+				}
+				cond := meta.FindStatusCondition(createdMultiClusterService.Status.Conditions, string(fleetnetv1alpha1.MultiClusterServicePortsFound))
+				if cond == nil || cond.Status != metav1.ConditionFalse || cond.Reason != conditionReasonPortNotFound {
+					return fmt.Errorf("ports condition got %+v, want false with reason %s", cond, conditionReasonPortNotFound)
+				}
+				return nil
+			}, timeout, interval).Should(Succeed())
+
+			By("By requesting only a port which does not exist")
+			Eventually(func() error {
+				if err := k8sClient.Get(ctx, mcsLookupKey, createdMultiClusterService); err != nil {
+					return err
+				}
+				createdMultiClusterService.Spec.Ports = []intstr.IntOrString{intstr.FromString("grpc")}
+				return k8sClient.Update(ctx, createdMultiClusterService)
+			}, timeout, interval).Should(Succeed())
+
+			By("By checking derived service is deleted")
+			Eventually(func() bool {
+				return errors.IsNotFound(k8sClient.Get(ctx, derivedServiceLookupKey, createdService))
+			}, timeout, interval).Should(BeTrue())
+
+			By("By checking mcs reports that no requested port matches")
+			Eventually(func() error {
+				if err := k8sClient.Get(ctx, mcsLookupKey, createdMultiClusterService); err != nil {
+					return err
+				}
+				cond := meta.FindStatusCondition(createdMultiClusterService.Status.Conditions, string(fleetnetv1alpha1.MultiClusterServiceValid))
+				if cond == nil || cond.Status != metav1.ConditionFalse || cond.Reason != conditionReasonNoMatchingPorts {
+					return fmt.Errorf("valid condition got %+v, want false with reason %s", cond, conditionReasonNoMatchingPorts)
+				}
+				return nil
+			}, timeout, interval).Should(Succeed())
+
+			By("By removing the ports filter")
+			Eventually(func() error {
+				if err := k8sClient.Get(ctx, mcsLookupKey, createdMultiClusterService); err != nil {
+					return err
+				}
+				createdMultiClusterService.Spec.Ports = nil
+				return k8sClient.Update(ctx, createdMultiClusterService)
+			}, timeout, interval).Should(Succeed())
+
+			By("By checking derived service exposes all the ports")
+			Eventually(func() error {
+				if err := k8sClient.Get(ctx, derivedServiceLookupKey, createdService); err != nil {
+					return err
+				}
+				if diff := cmp.Diff([]string{"http", "https", "metrics"}, derivedServicePortNames(createdService)); diff != "" {
+					return fmt.Errorf("derived service ports mismatch (-want, +got):\n%s", diff)
+				}
+				return nil
+			}, timeout, interval).Should(Succeed())
+
+			By("By checking mcs ports condition is removed")
+			Eventually(func() error {
+				if err := k8sClient.Get(ctx, mcsLookupKey, createdMultiClusterService); err != nil {
+					return err
+				}
+				if cond := meta.FindStatusCondition(createdMultiClusterService.Status.Conditions, string(fleetnetv1alpha1.MultiClusterServicePortsFound)); cond != nil {
+					return fmt.Errorf("ports condition got %+v, want nil", cond)
+				}
+				return nil
+			}, timeout, interval).Should(Succeed())
+
+			By("By deleting mcs")
+			Expect(k8sClient.Delete(ctx, multiClusterService)).Should(Succeed())
+
+			By("By checking derived Service in the fleet-system")
+			Eventually(func() (int, error) {
+				serviceList := &corev1.ServiceList{}
+				if err := k8sClient.List(ctx, serviceList, &client.ListOptions{Namespace: systemNamespace}); err != nil {
+					return -1, err
+				}
+				return len(serviceList.Items), nil
+			}, duration, interval).Should(Equal(0))
+
+			By("By checking mcs")
+			Eventually(func() bool {
+				return errors.IsNotFound(k8sClient.Get(ctx, mcsLookupKey, createdMultiClusterService))
+			}, timeout, interval).Should(BeTrue())
+		})
+	})
 })
@@ -13,14 +13,20 @@ import (
 	"github.com/google/go-cmp/cmp"
 	"github.com/google/go-cmp/cmp/cmpopts"
 	corev1 "k8s.io/api/core/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/apimachinery/pkg/util/validation/field"
 	"k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
 
 	fleetnetv1alpha1 "go.goms.io/fleet-networking/api/v1alpha1"
 	"go.goms.io/fleet-networking/pkg/common/objectmeta"
@@ -54,6 +60,9 @@ func multiClusterServiceScheme(t *testing.T) *runtime.Scheme {
 	if err := corev1.AddToScheme(scheme); err != nil {
 		t.Fatalf("failed to add scheme: %v", err)
 	}
+	if err := discoveryv1.AddToScheme(scheme); err != nil {
+		t.Fatalf("failed to add scheme: %v", err)
+	}
 	return scheme
 }
 
@@ -211,6 +220,136 @@ func TestHandleDelete(t *testing.T) {
 	}
 }
 
+func TestHandleDelete_SharedServiceImport(t *testing.T) {
+	otherMCSName := "other-mcs"
+	tests := []struct {
+		name           string
+		references     string
+		otherMCSExists bool
+		wantDeleted    bool
+		wantReferences string
+	}{
+		{
+			name:           "service import referenced by another mcs",
+			references:     testName + "," + otherMCSName,
+			otherMCSExists: true,
+			wantReferences: otherMCSName,
+		},
+		{
+			name:        "service import referenced by another mcs which has been deleted",
+			references:  testName + "," + otherMCSName,
+			wantDeleted: true,
+		},
+		{
+			name:        "service import only referenced by the mcs",
+			references:  testName,
+			wantDeleted: true,
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx := context.Background()
+
+			mcsObj := multiClusterServiceForTest()
+			mcsObj.UID = "mcs-uid"
+			mcsObj.Finalizers = []string{multiClusterServiceFinalizer}
+			mcsObj.ObjectMeta.Labels = map[string]string{
+				multiClusterServiceLabelServiceImport: testServiceName,
+			}
+			now := metav1.Now()
+			mcsObj.DeletionTimestamp = &now
+			controller := true
+			serviceImport := &fleetnetv1alpha1.ServiceImport{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      testServiceName,
+					Namespace: testNamespace,
+					Annotations: map[string]string{
+						objectmeta.ServiceImportAnnotationMultiClusterServices: tc.references,
+					},
+					OwnerReferences: []metav1.OwnerReference{
+						{
+							APIVersion: multiClusterServiceType.APIVersion,
+							Kind:       multiClusterServiceType.Kind,
+							Name:       testName,
+							UID:        mcsObj.UID,
+							Controller: &controller,
+						},
+					},
+				},
+			}
+			objects := []client.Object{mcsObj, serviceImport}
+			if tc.otherMCSExists {
+				otherMCS := multiClusterServiceForTest()
+				otherMCS.Name = otherMCSName
+				objects = append(objects, otherMCS)
+			}
+			fakeClient := fake.NewClientBuilder().
+				WithScheme(multiClusterServiceScheme(t)).
+				WithObjects(objects...).
+				Build()
+
+			r := multiClusterServiceReconciler(fakeClient)
+			if _, err := r.handleDelete(ctx, mcsObj); err != nil {
+				t.Fatalf("failed to handle delete: %v", err)
+			}
+
+			got := fleetnetv1alpha1.ServiceImport{}
+			err := fakeClient.Get(ctx, types.NamespacedName{Namespace: testNamespace, Name: testServiceName}, &got)
+			if tc.wantDeleted {
+				if !errors.IsNotFound(err) {
+					t.Fatalf("ServiceImport Get() = %+v, got error %v, want not found error", got, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ServiceImport Get() got error %v, want no error", err)
+			}
+			if gotReferences := got.Annotations[objectmeta.ServiceImportAnnotationMultiClusterServices]; gotReferences != tc.wantReferences {
+				t.Errorf("ServiceImport references = %q, want %q", gotReferences, tc.wantReferences)
+			}
+			if len(got.OwnerReferences) != 0 {
+				t.Errorf("ServiceImport ownerReferences = %+v, want none", got.OwnerReferences)
+			}
+		})
+	}
+}
+
+func TestAddServiceImportReference(t *testing.T) {
+	tests := []struct {
+		name        string
+		annotations map[string]string
+		want        string
+	}{
+		{
+			name: "no references",
+			want: testName,
+		},
+		{
+			name: "other references",
+			annotations: map[string]string{
+				objectmeta.ServiceImportAnnotationMultiClusterServices: "a-mcs,z-mcs",
+			},
+			want: "a-mcs,my-mcs,z-mcs",
+		},
+		{
+			name: "already referenced",
+			annotations: map[string]string{
+				objectmeta.ServiceImportAnnotationMultiClusterServices: "z-mcs," + testName,
+			},
+			want: "z-mcs," + testName,
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			serviceImport := &fleetnetv1alpha1.ServiceImport{ObjectMeta: metav1.ObjectMeta{Annotations: tc.annotations}}
+			addServiceImportReference(serviceImport, multiClusterServiceForTest())
+			if got := serviceImport.Annotations[objectmeta.ServiceImportAnnotationMultiClusterServices]; got != tc.want {
+				t.Errorf("addServiceImportReference() = %q, want %q", got, tc.want)
+			}
+		})
+	}
+}
+
 func TestHandleUpdate(t *testing.T) {
 	controller := true
 	blockOwnerDeletion := true
@@ -285,6 +424,11 @@ func TestHandleUpdate(t *testing.T) {
 		serviceLabelMCSName:      testName,
 		serviceLabelMCSNamespace: testNamespace,
 	}
+	provenanceAnnotations := map[string]string{
+		objectmeta.ProvenanceAnnotationOriginClusterID: "member1",
+		objectmeta.ProvenanceAnnotationOriginObject:    testNamespace + "/" + testServiceName,
+		objectmeta.ProvenanceAnnotationAgentVersion:    objectmeta.AgentVersion,
+	}
 
 	tests := []struct {
 		name                string
@@ -304,8 +448,11 @@ func TestHandleUpdate(t *testing.T) {
 			want: ctrl.Result{},
 			wantServiceImport: &fleetnetv1alpha1.ServiceImport{
 				ObjectMeta: metav1.ObjectMeta{
-					Name:            testServiceName,
-					Namespace:       testNamespace,
+					Name:      testServiceName,
+					Namespace: testNamespace,
+					Annotations: map[string]string{
+						objectmeta.ServiceImportAnnotationMultiClusterServices: testName,
+					},
 					OwnerReferences: []metav1.OwnerReference{ownerRef},
 				},
 			},
@@ -345,8 +492,11 @@ func TestHandleUpdate(t *testing.T) {
 			want: ctrl.Result{},
 			wantServiceImport: &fleetnetv1alpha1.ServiceImport{
 				ObjectMeta: metav1.ObjectMeta{
-					Name:            testServiceName,
-					Namespace:       testNamespace,
+					Name:      testServiceName,
+					Namespace: testNamespace,
+					Annotations: map[string]string{
+						objectmeta.ServiceImportAnnotationMultiClusterServices: testName,
+					},
 					OwnerReferences: []metav1.OwnerReference{ownerRef},
 				},
 			},
@@ -393,8 +543,11 @@ func TestHandleUpdate(t *testing.T) {
 			want:                ctrl.Result{},
 			wantServiceImport: &fleetnetv1alpha1.ServiceImport{
 				ObjectMeta: metav1.ObjectMeta{
-					Name:            testServiceName,
-					Namespace:       testNamespace,
+					Name:      testServiceName,
+					Namespace: testNamespace,
+					Annotations: map[string]string{
+						objectmeta.ServiceImportAnnotationMultiClusterServices: testName,
+					},
 					OwnerReferences: []metav1.OwnerReference{ownerRef},
 				},
 			},
@@ -428,8 +581,11 @@ func TestHandleUpdate(t *testing.T) {
 			want: ctrl.Result{},
 			wantServiceImport: &fleetnetv1alpha1.ServiceImport{
 				ObjectMeta: metav1.ObjectMeta{
-					Name:            testServiceName,
-					Namespace:       testNamespace,
+					Name:      testServiceName,
+					Namespace: testNamespace,
+					Annotations: map[string]string{
+						objectmeta.ServiceImportAnnotationMultiClusterServices: testName,
+					},
 					OwnerReferences: []metav1.OwnerReference{ownerRef},
 				},
 			},
@@ -463,8 +619,11 @@ func TestHandleUpdate(t *testing.T) {
 			want: ctrl.Result{},
 			wantServiceImport: &fleetnetv1alpha1.ServiceImport{
 				ObjectMeta: metav1.ObjectMeta{
-					Name:            testServiceName,
-					Namespace:       testNamespace,
+					Name:      testServiceName,
+					Namespace: testNamespace,
+					Annotations: map[string]string{
+						objectmeta.ServiceImportAnnotationMultiClusterServices: testName,
+					},
 					OwnerReferences: []metav1.OwnerReference{ownerRef},
 				},
 			},
@@ -511,8 +670,11 @@ func TestHandleUpdate(t *testing.T) {
 			want: ctrl.Result{},
 			wantServiceImport: &fleetnetv1alpha1.ServiceImport{
 				ObjectMeta: metav1.ObjectMeta{
-					Name:            testServiceName,
-					Namespace:       testNamespace,
+					Name:      testServiceName,
+					Namespace: testNamespace,
+					Annotations: map[string]string{
+						objectmeta.ServiceImportAnnotationMultiClusterServices: testName,
+					},
 					OwnerReferences: []metav1.OwnerReference{ownerRef},
 				},
 			},
@@ -568,8 +730,11 @@ func TestHandleUpdate(t *testing.T) {
 			want: ctrl.Result{},
 			wantServiceImport: &fleetnetv1alpha1.ServiceImport{
 				ObjectMeta: metav1.ObjectMeta{
-					Name:            testServiceName,
-					Namespace:       testNamespace,
+					Name:      testServiceName,
+					Namespace: testNamespace,
+					Annotations: map[string]string{
+						objectmeta.ServiceImportAnnotationMultiClusterServices: testName,
+					},
 					OwnerReferences: []metav1.OwnerReference{ownerRef},
 				},
 			},
@@ -615,8 +780,11 @@ func TestHandleUpdate(t *testing.T) {
 			want: ctrl.Result{},
 			wantServiceImport: &fleetnetv1alpha1.ServiceImport{
 				ObjectMeta: metav1.ObjectMeta{
-					Name:            testServiceName,
-					Namespace:       testNamespace,
+					Name:      testServiceName,
+					Namespace: testNamespace,
+					Annotations: map[string]string{
+						objectmeta.ServiceImportAnnotationMultiClusterServices: testName,
+					},
 					OwnerReferences: []metav1.OwnerReference{ownerRef},
 				},
 				Status: fleetnetv1alpha1.ServiceImportStatus{
@@ -628,9 +796,10 @@ func TestHandleUpdate(t *testing.T) {
 			},
 			wantDerivedService: &corev1.Service{
 				ObjectMeta: metav1.ObjectMeta{
-					Name:      derivedServiceName,
-					Namespace: systemNamespace,
-					Labels:    serviceLabel,
+					Name:        derivedServiceName,
+					Namespace:   systemNamespace,
+					Labels:      serviceLabel,
+					Annotations: provenanceAnnotations,
 				},
 				Spec: corev1.ServiceSpec{
 					Ports: servicePorts,
@@ -653,7 +822,9 @@ func TestHandleUpdate(t *testing.T) {
 					},
 				},
 				Status: fleetnetv1alpha1.MultiClusterServiceStatus{
-					LoadBalancer: corev1.LoadBalancerStatus{},
+					LoadBalancer:         corev1.LoadBalancerStatus{},
+					IdleTimeoutMinutes:   azureLoadBalancerDefaultIdleTimeoutMinutes,
+					FleetSystemNamespace: systemNamespace,
 					Conditions: []metav1.Condition{
 						validCondition,
 					},
@@ -681,8 +852,11 @@ func TestHandleUpdate(t *testing.T) {
 			want: ctrl.Result{},
 			wantServiceImport: &fleetnetv1alpha1.ServiceImport{
 				ObjectMeta: metav1.ObjectMeta{
-					Name:            testServiceName,
-					Namespace:       testNamespace,
+					Name:      testServiceName,
+					Namespace: testNamespace,
+					Annotations: map[string]string{
+						objectmeta.ServiceImportAnnotationMultiClusterServices: testName,
+					},
 					OwnerReferences: []metav1.OwnerReference{ownerRef},
 				},
 				Status: fleetnetv1alpha1.ServiceImportStatus{
@@ -694,9 +868,10 @@ func TestHandleUpdate(t *testing.T) {
 			},
 			wantDerivedService: &corev1.Service{
 				ObjectMeta: metav1.ObjectMeta{
-					Name:      derivedServiceName,
-					Namespace: systemNamespace,
-					Labels:    serviceLabel,
+					Name:        derivedServiceName,
+					Namespace:   systemNamespace,
+					Labels:      serviceLabel,
+					Annotations: provenanceAnnotations,
 				},
 				Spec: corev1.ServiceSpec{
 					Ports: servicePorts,
@@ -719,7 +894,9 @@ func TestHandleUpdate(t *testing.T) {
 					},
 				},
 				Status: fleetnetv1alpha1.MultiClusterServiceStatus{
-					LoadBalancer: corev1.LoadBalancerStatus{},
+					LoadBalancer:         corev1.LoadBalancerStatus{},
+					IdleTimeoutMinutes:   azureLoadBalancerDefaultIdleTimeoutMinutes,
+					FleetSystemNamespace: systemNamespace,
 					Conditions: []metav1.Condition{
 						validCondition,
 					},
@@ -767,6 +944,11 @@ func TestHandleUpdate(t *testing.T) {
 				ObjectMeta: metav1.ObjectMeta{
 					Name:      derivedServiceName,
 					Namespace: systemNamespace,
+					Annotations: map[string]string{
+						"unrelated": "value",
+						objectmeta.ProvenanceAnnotationOriginClusterID: "member2",
+						objectmeta.ProvenanceAnnotationOriginUID:       "stale-uid",
+					},
 				},
 				Spec: corev1.ServiceSpec{
 					Ports: []corev1.ServicePort{
@@ -786,8 +968,11 @@ func TestHandleUpdate(t *testing.T) {
 			},
 			wantServiceImport: &fleetnetv1alpha1.ServiceImport{
 				ObjectMeta: metav1.ObjectMeta{
-					Name:            testServiceName,
-					Namespace:       testNamespace,
+					Name:      testServiceName,
+					Namespace: testNamespace,
+					Annotations: map[string]string{
+						objectmeta.ServiceImportAnnotationMultiClusterServices: testName,
+					},
 					OwnerReferences: []metav1.OwnerReference{ownerRef},
 				},
 				Status: fleetnetv1alpha1.ServiceImportStatus{
@@ -803,6 +988,12 @@ func TestHandleUpdate(t *testing.T) {
 					Name:      derivedServiceName,
 					Namespace: systemNamespace,
 					Labels:    serviceLabel,
+					Annotations: map[string]string{
+						"unrelated": "value",
+						objectmeta.ProvenanceAnnotationOriginClusterID: "member1",
+						objectmeta.ProvenanceAnnotationOriginObject:    testNamespace + "/" + testServiceName,
+						objectmeta.ProvenanceAnnotationAgentVersion:    objectmeta.AgentVersion,
+					},
 				},
 				Spec: corev1.ServiceSpec{
 					Ports: servicePorts,
@@ -828,7 +1019,9 @@ func TestHandleUpdate(t *testing.T) {
 					},
 				},
 				Status: fleetnetv1alpha1.MultiClusterServiceStatus{
-					LoadBalancer: loadBalancerStatus,
+					LoadBalancer:         loadBalancerStatus,
+					IdleTimeoutMinutes:   azureLoadBalancerDefaultIdleTimeoutMinutes,
+					FleetSystemNamespace: systemNamespace,
 					Conditions: []metav1.Condition{
 						validCondition,
 					},
@@ -863,6 +1056,9 @@ func TestHandleUpdate(t *testing.T) {
 				ObjectMeta: metav1.ObjectMeta{
 					Name:      testServiceName,
 					Namespace: testNamespace,
+					Annotations: map[string]string{
+						objectmeta.ServiceImportAnnotationMultiClusterServices: testName,
+					},
 					OwnerReferences: []metav1.OwnerReference{
 						{
 							APIVersion:         multiClusterServiceType.APIVersion,
@@ -926,8 +1122,11 @@ func TestHandleUpdate(t *testing.T) {
 			want: ctrl.Result{},
 			wantServiceImport: &fleetnetv1alpha1.ServiceImport{
 				ObjectMeta: metav1.ObjectMeta{
-					Name:            testServiceName,
-					Namespace:       testNamespace,
+					Name:      testServiceName,
+					Namespace: testNamespace,
+					Annotations: map[string]string{
+						objectmeta.ServiceImportAnnotationMultiClusterServices: testName,
+					},
 					OwnerReferences: []metav1.OwnerReference{ownerRef},
 				},
 				Status: fleetnetv1alpha1.ServiceImportStatus{
@@ -943,7 +1142,10 @@ func TestHandleUpdate(t *testing.T) {
 					Namespace: systemNamespace,
 					Labels:    serviceLabel,
 					Annotations: map[string]string{
-						serviceAnnotationInternalLoadBalancer: "true",
+						serviceAnnotationInternalLoadBalancer:          "true",
+						objectmeta.ProvenanceAnnotationOriginClusterID: "member1",
+						objectmeta.ProvenanceAnnotationOriginObject:    testNamespace + "/" + testServiceName,
+						objectmeta.ProvenanceAnnotationAgentVersion:    objectmeta.AgentVersion,
 					},
 				},
 				Spec: corev1.ServiceSpec{
@@ -970,7 +1172,9 @@ func TestHandleUpdate(t *testing.T) {
 					},
 				},
 				Status: fleetnetv1alpha1.MultiClusterServiceStatus{
-					LoadBalancer: corev1.LoadBalancerStatus{},
+					LoadBalancer:         corev1.LoadBalancerStatus{},
+					IdleTimeoutMinutes:   azureLoadBalancerDefaultIdleTimeoutMinutes,
+					FleetSystemNamespace: systemNamespace,
 					Conditions: []metav1.Condition{
 						validCondition,
 					},
@@ -1055,6 +1259,431 @@ func TestHandleUpdate(t *testing.T) {
 	}
 }
 
+// TestHandleUpdate_CustomFleetSystemNamespace tests the *Reconciler.handleUpdate method with a reserved namespace other
+// than the default one.
+func TestHandleUpdate_CustomFleetSystemNamespace(t *testing.T) {
+	ctx := context.Background()
+	customSystemNamespace := "custom-fleet-system"
+
+	mcsObj := multiClusterServiceForTest()
+	serviceImport := &fleetnetv1alpha1.ServiceImport{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      testServiceName,
+			Namespace: testNamespace,
+		},
+		Status: fleetnetv1alpha1.ServiceImportStatus{
+			Ports: []fleetnetv1alpha1.ServicePort{
+				{
+					Name:     "portA",
+					Protocol: corev1.ProtocolTCP,
+					Port:     8080,
+				},
+			},
+			Clusters: []fleetnetv1alpha1.ClusterStatus{
+				{Cluster: "member1"},
+			},
+		},
+	}
+	fakeClient := fake.NewClientBuilder().
+		WithScheme(multiClusterServiceScheme(t)).
+		WithObjects(mcsObj, serviceImport).
+		WithStatusSubresource(mcsObj, serviceImport).
+		Build()
+
+	r := multiClusterServiceReconciler(fakeClient)
+	r.FleetSystemNamespace = customSystemNamespace
+	if _, err := r.handleUpdate(ctx, mcsObj); err != nil {
+		t.Fatalf("failed to handle update: %v", err)
+	}
+
+	service := corev1.Service{}
+	name := types.NamespacedName{Namespace: customSystemNamespace, Name: derivedServiceName}
+	if err := fakeClient.Get(ctx, name, &service); err != nil {
+		t.Fatalf("Service Get(%v) got error %v, want no error", name, err)
+	}
+	name = types.NamespacedName{Namespace: systemNamespace, Name: derivedServiceName}
+	if err := fakeClient.Get(ctx, name, &service); !errors.IsNotFound(err) {
+		t.Errorf("Service Get(%v) got error %v, want not found error", name, err)
+	}
+
+	mcs := fleetnetv1alpha1.MultiClusterService{}
+	name = types.NamespacedName{Namespace: testNamespace, Name: testName}
+	if err := fakeClient.Get(ctx, name, &mcs); err != nil {
+		t.Fatalf("MultiClusterService Get() got error %v, want no error", err)
+	}
+	if got := mcs.Status.FleetSystemNamespace; got != customSystemNamespace {
+		t.Errorf("MultiClusterService status fleetSystemNamespace = %q, want %q", got, customSystemNamespace)
+	}
+}
+
+func TestHandleUpdate_InternalTrafficPolicy(t *testing.T) {
+	nodeName := "node-1"
+	tests := []struct {
+		name          string
+		nodeName      *string
+		wantPolicy    corev1.ServiceInternalTrafficPolicy
+		wantCondition metav1.Condition
+	}{
+		{
+			name:       "imported endpoints have node names",
+			nodeName:   &nodeName,
+			wantPolicy: corev1.ServiceInternalTrafficPolicyLocal,
+			wantCondition: metav1.Condition{
+				Type:   string(fleetnetv1alpha1.MultiClusterServiceInternalTrafficPolicyApplied),
+				Status: metav1.ConditionTrue,
+				Reason: conditionReasonInternalTrafficPolicyApplied,
+			},
+		},
+		{
+			name:       "imported endpoints do not have node names",
+			wantPolicy: corev1.ServiceInternalTrafficPolicyCluster,
+			wantCondition: metav1.Condition{
+				Type:   string(fleetnetv1alpha1.MultiClusterServiceInternalTrafficPolicyApplied),
+				Status: metav1.ConditionFalse,
+				Reason: conditionReasonEndpointsMissingNodeNames,
+			},
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx := context.Background()
+			mcsObj := multiClusterServiceForTest()
+			mcsObj.Spec.InternalTrafficPolicy = corev1.ServiceInternalTrafficPolicyLocal
+			serviceImport := &fleetnetv1alpha1.ServiceImport{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      testServiceName,
+					Namespace: testNamespace,
+				},
+				Status: fleetnetv1alpha1.ServiceImportStatus{
+					Ports: []fleetnetv1alpha1.ServicePort{
+						{
+							Name:     "portA",
+							Protocol: corev1.ProtocolTCP,
+							Port:     8080,
+						},
+					},
+					Clusters: []fleetnetv1alpha1.ClusterStatus{
+						{Cluster: "member1"},
+					},
+				},
+			}
+			endpointSlice := &discoveryv1.EndpointSlice{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "imported-slice",
+					Namespace: systemNamespace,
+					Labels: map[string]string{
+						discoveryv1.LabelServiceName: derivedServiceName,
+					},
+				},
+				AddressType: discoveryv1.AddressTypeIPv4,
+				Endpoints: []discoveryv1.Endpoint{
+					{
+						Addresses: []string{"1.2.3.4"},
+						NodeName:  tc.nodeName,
+					},
+				},
+			}
+			fakeClient := fake.NewClientBuilder().
+				WithScheme(multiClusterServiceScheme(t)).
+				WithObjects(mcsObj, serviceImport, endpointSlice).
+				WithStatusSubresource(mcsObj, serviceImport).
+				Build()
+
+			r := multiClusterServiceReconciler(fakeClient)
+			if _, err := r.handleUpdate(ctx, mcsObj); err != nil {
+				t.Fatalf("failed to handle update: %v", err)
+			}
+
+			service := corev1.Service{}
+			name := types.NamespacedName{Namespace: systemNamespace, Name: derivedServiceName}
+			if err := fakeClient.Get(ctx, name, &service); err != nil {
+				t.Fatalf("Service Get(%v) got error %v, want no error", name, err)
+			}
+			if got := internalTrafficPolicyOf(&service); got != tc.wantPolicy {
+				t.Errorf("Service internalTrafficPolicy = %q, want %q", got, tc.wantPolicy)
+			}
+
+			mcs := fleetnetv1alpha1.MultiClusterService{}
+			name = types.NamespacedName{Namespace: testNamespace, Name: testName}
+			if err := fakeClient.Get(ctx, name, &mcs); err != nil {
+				t.Fatalf("MultiClusterService Get() got error %v, want no error", err)
+			}
+			got := meta.FindStatusCondition(mcs.Status.Conditions, string(fleetnetv1alpha1.MultiClusterServiceInternalTrafficPolicyApplied))
+			if diff := cmp.Diff(&tc.wantCondition, got, cmpopts.IgnoreFields(metav1.Condition{}, "LastTransitionTime", "Message", "ObservedGeneration")); diff != "" {
+				t.Errorf("MultiClusterService internalTrafficPolicyApplied condition mismatch (-want, +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestHandleUpdate_IPFamilies(t *testing.T) {
+	dualStack := []corev1.IPFamily{corev1.IPv4Protocol, corev1.IPv6Protocol}
+	tests := []struct {
+		name                   string
+		importFamilies         []corev1.IPFamily
+		existingFamilies       []corev1.IPFamily
+		supportsDualStack      bool
+		wantFamilies           []corev1.IPFamily
+		wantPolicy             corev1.IPFamilyPolicy
+		wantCondition          *metav1.Condition
+		wantDualStackRejection int
+	}{
+		{
+			name:              "dual-stack service imported into a dual-stack cluster",
+			importFamilies:    dualStack,
+			supportsDualStack: true,
+			wantFamilies:      dualStack,
+			wantPolicy:        corev1.IPFamilyPolicyRequireDualStack,
+			wantCondition: &metav1.Condition{
+				Type:   string(fleetnetv1alpha1.MultiClusterServiceIPFamiliesApplied),
+				Status: metav1.ConditionTrue,
+				Reason: conditionReasonIPFamiliesApplied,
+			},
+		},
+		{
+			name:           "dual-stack service imported into a single-stack cluster",
+			importFamilies: dualStack,
+			wantFamilies:   []corev1.IPFamily{corev1.IPv4Protocol},
+			wantPolicy:     corev1.IPFamilyPolicySingleStack,
+			wantCondition: &metav1.Condition{
+				Type:   string(fleetnetv1alpha1.MultiClusterServiceIPFamiliesApplied),
+				Status: metav1.ConditionFalse,
+				Reason: conditionReasonDualStackNotSupported,
+			},
+			// The dual-stack service is rejected once; the second reconcile falls back to the primary family directly.
+			wantDualStackRejection: 1,
+		},
+		{
+			name:           "single-stack service",
+			importFamilies: []corev1.IPFamily{corev1.IPv6Protocol},
+			wantFamilies:   []corev1.IPFamily{corev1.IPv6Protocol},
+			wantPolicy:     corev1.IPFamilyPolicySingleStack,
+		},
+		{
+			// The derived service is recreated, which releases the cluster IP of the old family.
+			name:             "primary family of the derived service is changed",
+			importFamilies:   []corev1.IPFamily{corev1.IPv6Protocol},
+			existingFamilies: []corev1.IPFamily{corev1.IPv4Protocol},
+			wantFamilies:     []corev1.IPFamily{corev1.IPv6Protocol},
+			wantPolicy:       corev1.IPFamilyPolicySingleStack,
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx := context.Background()
+			mcsObj := multiClusterServiceForTest()
+			serviceImport := &fleetnetv1alpha1.ServiceImport{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      testServiceName,
+					Namespace: testNamespace,
+				},
+				Status: fleetnetv1alpha1.ServiceImportStatus{
+					Ports: []fleetnetv1alpha1.ServicePort{
+						{
+							Name:     "portA",
+							Protocol: corev1.ProtocolTCP,
+							Port:     8080,
+						},
+					},
+					IPFamilies: tc.importFamilies,
+					Clusters: []fleetnetv1alpha1.ClusterStatus{
+						{Cluster: "member1"},
+					},
+				},
+			}
+			objs := []client.Object{mcsObj, serviceImport}
+			if tc.existingFamilies != nil {
+				objs = append(objs, &corev1.Service{
+					ObjectMeta: metav1.ObjectMeta{
+						Namespace: systemNamespace,
+						Name:      derivedServiceName,
+					},
+					Spec: corev1.ServiceSpec{
+						IPFamilies: tc.existingFamilies,
+						ClusterIPs: []string{"10.0.0.1"},
+					},
+				})
+			}
+			rejections := 0
+			// rejectDualStack mimics the API server of a cluster which does not support dual-stack services.
+			rejectDualStack := func(obj client.Object) error {
+				svc, ok := obj.(*corev1.Service)
+				if tc.supportsDualStack || !ok || svc.Spec.IPFamilyPolicy == nil || *svc.Spec.IPFamilyPolicy != corev1.IPFamilyPolicyRequireDualStack {
+					return nil
+				}
+				rejections++
+				return errors.NewInvalid(schema.GroupKind{Kind: "Service"}, svc.Name, field.ErrorList{
+					field.Invalid(field.NewPath("spec", "ipFamilyPolicy"), *svc.Spec.IPFamilyPolicy, "this cluster is not configured for dual-stack services"),
+				})
+			}
+			fakeClient := fake.NewClientBuilder().
+				WithScheme(multiClusterServiceScheme(t)).
+				WithObjects(objs...).
+				WithStatusSubresource(mcsObj, serviceImport).
+				WithInterceptorFuncs(interceptor.Funcs{
+					Create: func(ctx context.Context, c client.WithWatch, obj client.Object, opts ...client.CreateOption) error {
+						if err := rejectDualStack(obj); err != nil {
+							return err
+						}
+						return c.Create(ctx, obj, opts...)
+					},
+					Update: func(ctx context.Context, c client.WithWatch, obj client.Object, opts ...client.UpdateOption) error {
+						if err := rejectDualStack(obj); err != nil {
+							return err
+						}
+						return c.Update(ctx, obj, opts...)
+					},
+				}).
+				Build()
+
+			r := multiClusterServiceReconciler(fakeClient)
+			mcsName := types.NamespacedName{Namespace: testNamespace, Name: testName}
+			// Reconcile twice to verify that the fallback is stable and the deleted derived service is recreated.
+			for i := 0; i < 2; i++ {
+				mcs := &fleetnetv1alpha1.MultiClusterService{}
+				if err := fakeClient.Get(ctx, mcsName, mcs); err != nil {
+					t.Fatalf("MultiClusterService Get() got error %v, want no error", err)
+				}
+				if _, err := r.handleUpdate(ctx, mcs); err != nil {
+					t.Fatalf("failed to handle update: %v", err)
+				}
+			}
+			if rejections != tc.wantDualStackRejection {
+				t.Errorf("dual-stack service rejections = %d, want %d", rejections, tc.wantDualStackRejection)
+			}
+
+			service := corev1.Service{}
+			name := types.NamespacedName{Namespace: systemNamespace, Name: derivedServiceName}
+			if err := fakeClient.Get(ctx, name, &service); err != nil {
+				t.Fatalf("Service Get(%v) got error %v, want no error", name, err)
+			}
+			if diff := cmp.Diff(tc.wantFamilies, service.Spec.IPFamilies); diff != "" {
+				t.Errorf("Service ipFamilies mismatch (-want, +got):\n%s", diff)
+			}
+			if len(service.Spec.ClusterIPs) != 0 {
+				t.Errorf("Service clusterIPs = %v, want none", service.Spec.ClusterIPs)
+			}
+			if service.Spec.IPFamilyPolicy == nil || *service.Spec.IPFamilyPolicy != tc.wantPolicy {
+				t.Errorf("Service ipFamilyPolicy = %v, want %q", service.Spec.IPFamilyPolicy, tc.wantPolicy)
+			}
+
+			mcs := fleetnetv1alpha1.MultiClusterService{}
+			if err := fakeClient.Get(ctx, mcsName, &mcs); err != nil {
+				t.Fatalf("MultiClusterService Get() got error %v, want no error", err)
+			}
+			got := meta.FindStatusCondition(mcs.Status.Conditions, string(fleetnetv1alpha1.MultiClusterServiceIPFamiliesApplied))
+			if diff := cmp.Diff(tc.wantCondition, got, cmpopts.IgnoreFields(metav1.Condition{}, "LastTransitionTime", "Message", "ObservedGeneration")); diff != "" {
+				t.Errorf("MultiClusterService ipFamiliesApplied condition mismatch (-want, +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestConfigureInternalTrafficPolicy(t *testing.T) {
+	local := corev1.ServiceInternalTrafficPolicyLocal
+	cluster := corev1.ServiceInternalTrafficPolicyCluster
+	tests := []struct {
+		name          string
+		policy        corev1.ServiceInternalTrafficPolicy
+		servicePolicy *corev1.ServiceInternalTrafficPolicy
+		want          *corev1.ServiceInternalTrafficPolicy
+	}{
+		{
+			name: "policy is not set",
+		},
+		{
+			name:          "policy is not set and the service uses the Cluster policy",
+			servicePolicy: &cluster,
+			want:          &cluster,
+		},
+		{
+			name:   "policy is set to Local",
+			policy: corev1.ServiceInternalTrafficPolicyLocal,
+			want:   &local,
+		},
+		{
+			name:          "policy is updated to Cluster",
+			policy:        corev1.ServiceInternalTrafficPolicyCluster,
+			servicePolicy: &local,
+			want:          &cluster,
+		},
+		{
+			name:          "policy is removed from the mcs",
+			servicePolicy: &local,
+			want:          &cluster,
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			service := &corev1.Service{Spec: corev1.ServiceSpec{InternalTrafficPolicy: tc.servicePolicy}}
+			configureInternalTrafficPolicy(tc.policy, service)
+			if diff := cmp.Diff(tc.want, service.Spec.InternalTrafficPolicy); diff != "" {
+				t.Errorf("configureInternalTrafficPolicy() mismatch (-want, +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestConfigureIPFamilies(t *testing.T) {
+	singleStack := corev1.IPFamilyPolicySingleStack
+	requireDualStack := corev1.IPFamilyPolicyRequireDualStack
+	tests := []struct {
+		name         string
+		families     []corev1.IPFamily
+		service      corev1.ServiceSpec
+		wantFamilies []corev1.IPFamily
+		wantPolicy   *corev1.IPFamilyPolicy
+		wantIPs      []string
+	}{
+		{
+			name:    "families are not reported",
+			service: corev1.ServiceSpec{ClusterIPs: []string{"10.0.0.1"}},
+			wantIPs: []string{"10.0.0.1"},
+		},
+		{
+			name:         "single-stack",
+			families:     []corev1.IPFamily{corev1.IPv6Protocol},
+			wantFamilies: []corev1.IPFamily{corev1.IPv6Protocol},
+			wantPolicy:   &singleStack,
+		},
+		{
+			name:         "dual-stack",
+			families:     []corev1.IPFamily{corev1.IPv4Protocol, corev1.IPv6Protocol},
+			service:      corev1.ServiceSpec{ClusterIPs: []string{"10.0.0.1"}},
+			wantFamilies: []corev1.IPFamily{corev1.IPv4Protocol, corev1.IPv6Protocol},
+			wantPolicy:   &requireDualStack,
+			wantIPs:      []string{"10.0.0.1"},
+		},
+		{
+			name:     "downgraded to single-stack",
+			families: []corev1.IPFamily{corev1.IPv4Protocol},
+			service: corev1.ServiceSpec{
+				IPFamilies:     []corev1.IPFamily{corev1.IPv4Protocol, corev1.IPv6Protocol},
+				IPFamilyPolicy: &requireDualStack,
+				ClusterIPs:     []string{"10.0.0.1", "fd00::1"},
+			},
+			wantFamilies: []corev1.IPFamily{corev1.IPv4Protocol},
+			wantPolicy:   &singleStack,
+			wantIPs:      []string{"10.0.0.1"},
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			service := &corev1.Service{Spec: tc.service}
+			configureIPFamilies(tc.families, service)
+			if diff := cmp.Diff(tc.wantFamilies, service.Spec.IPFamilies); diff != "" {
+				t.Errorf("configureIPFamilies() ipFamilies mismatch (-want, +got):\n%s", diff)
+			}
+			if diff := cmp.Diff(tc.wantPolicy, service.Spec.IPFamilyPolicy); diff != "" {
+				t.Errorf("configureIPFamilies() ipFamilyPolicy mismatch (-want, +got):\n%s", diff)
+			}
+			if diff := cmp.Diff(tc.wantIPs, service.Spec.ClusterIPs); diff != "" {
+				t.Errorf("configureIPFamilies() clusterIPs mismatch (-want, +got):\n%s", diff)
+			}
+		})
+	}
+}
+
 func TestConfigureInternalLoadBalancer(t *testing.T) {
 	tests := []struct {
 		name        string
@@ -1111,3 +1740,228 @@ func TestConfigureInternalLoadBalancer(t *testing.T) {
 		})
 	}
 }
+
+func TestConfigureLoadBalancerIdleTimeout(t *testing.T) {
+	idleTimeoutMinutes := int32(30)
+	tests := []struct {
+		name               string
+		idleTimeoutMinutes *int32
+		annotations        map[string]string
+		want               map[string]string
+	}{
+		{
+			name: "idle timeout is not set",
+		},
+		{
+			name:               "idle timeout is set",
+			idleTimeoutMinutes: &idleTimeoutMinutes,
+			want: map[string]string{
+				serviceAnnotationLoadBalancerIdleTimeout: "30",
+			},
+		},
+		{
+			name:               "idle timeout is updated",
+			idleTimeoutMinutes: &idleTimeoutMinutes,
+			annotations: map[string]string{
+				serviceAnnotationLoadBalancerIdleTimeout: "10",
+			},
+			want: map[string]string{
+				serviceAnnotationLoadBalancerIdleTimeout: "30",
+			},
+		},
+		{
+			name: "idle timeout is removed",
+			annotations: map[string]string{
+				serviceAnnotationLoadBalancerIdleTimeout: "10",
+				serviceAnnotationInternalLoadBalancer:    "true",
+			},
+			want: map[string]string{
+				serviceAnnotationInternalLoadBalancer: "true",
+			},
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			mcs := &fleetnetv1alpha1.MultiClusterService{
+				Spec: fleetnetv1alpha1.MultiClusterServiceSpec{
+					IdleTimeoutMinutes: tc.idleTimeoutMinutes,
+				},
+			}
+			service := &corev1.Service{
+				ObjectMeta: metav1.ObjectMeta{
+					Annotations: tc.annotations,
+				},
+			}
+			configureLoadBalancerIdleTimeout(mcs, service)
+			if diff := cmp.Diff(tc.want, service.Annotations, cmpopts.EquateEmpty()); diff != "" {
+				t.Errorf("configureLoadBalancerIdleTimeout() annotations mismatch (-want, +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestConfigureExternalTrafficPolicy(t *testing.T) {
+	healthCheckNodePort := int32(30080)
+	tests := []struct {
+		name                string
+		policy              corev1.ServiceExternalTrafficPolicy
+		healthCheckNodePort *int32
+		serviceSpec         corev1.ServiceSpec
+		wantServiceSpec     corev1.ServiceSpec
+	}{
+		{
+			name: "policy is not set",
+		},
+		{
+			name:   "policy is set to Cluster",
+			policy: corev1.ServiceExternalTrafficPolicyCluster,
+			wantServiceSpec: corev1.ServiceSpec{
+				ExternalTrafficPolicy: corev1.ServiceExternalTrafficPolicyCluster,
+			},
+		},
+		{
+			name:                "policy is set to Local with health check node port",
+			policy:              corev1.ServiceExternalTrafficPolicyLocal,
+			healthCheckNodePort: &healthCheckNodePort,
+			wantServiceSpec: corev1.ServiceSpec{
+				ExternalTrafficPolicy: corev1.ServiceExternalTrafficPolicyLocal,
+				HealthCheckNodePort:   healthCheckNodePort,
+			},
+		},
+		{
+			name:   "policy is set to Local and the allocated health check node port is kept",
+			policy: corev1.ServiceExternalTrafficPolicyLocal,
+			serviceSpec: corev1.ServiceSpec{
+				ExternalTrafficPolicy: corev1.ServiceExternalTrafficPolicyLocal,
+				HealthCheckNodePort:   31000,
+			},
+			wantServiceSpec: corev1.ServiceSpec{
+				ExternalTrafficPolicy: corev1.ServiceExternalTrafficPolicyLocal,
+				HealthCheckNodePort:   31000,
+			},
+		},
+		{
+			name: "policy is removed from the mcs",
+			serviceSpec: corev1.ServiceSpec{
+				ExternalTrafficPolicy: corev1.ServiceExternalTrafficPolicyLocal,
+				HealthCheckNodePort:   31000,
+			},
+			wantServiceSpec: corev1.ServiceSpec{
+				ExternalTrafficPolicy: corev1.ServiceExternalTrafficPolicyCluster,
+			},
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			mcs := &fleetnetv1alpha1.MultiClusterService{
+				Spec: fleetnetv1alpha1.MultiClusterServiceSpec{
+					ExternalTrafficPolicy: tc.policy,
+					HealthCheckNodePort:   tc.healthCheckNodePort,
+				},
+			}
+			service := &corev1.Service{Spec: tc.serviceSpec}
+			configureExternalTrafficPolicy(mcs, service)
+			if diff := cmp.Diff(tc.wantServiceSpec, service.Spec); diff != "" {
+				t.Errorf("configureExternalTrafficPolicy() spec mismatch (-want, +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestLoadBalancerIdleTimeoutMinutes(t *testing.T) {
+	tests := []struct {
+		name    string
+		service *corev1.Service
+		want    int32
+	}{
+		{
+			name:    "no derived service",
+			service: &corev1.Service{},
+		},
+		{
+			name: "annotation is not set",
+			service: &corev1.Service{
+				ObjectMeta: metav1.ObjectMeta{Name: derivedServiceName},
+			},
+			want: azureLoadBalancerDefaultIdleTimeoutMinutes,
+		},
+		{
+			name: "annotation is set",
+			service: &corev1.Service{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: derivedServiceName,
+					Annotations: map[string]string{
+						serviceAnnotationLoadBalancerIdleTimeout: "15",
+					},
+				},
+			},
+			want: 15,
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := loadBalancerIdleTimeoutMinutes(tc.service); got != tc.want {
+				t.Errorf("loadBalancerIdleTimeoutMinutes() = %d, want %d", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestFilterServiceImportPorts(t *testing.T) {
+	importPorts := []fleetnetv1alpha1.ServicePort{
+		{Name: "http", Protocol: corev1.ProtocolTCP, Port: 80},
+		{Name: "https", Protocol: corev1.ProtocolTCP, Port: 443},
+		{Name: "metrics", Protocol: corev1.ProtocolTCP, Port: 9090},
+	}
+	tests := []struct {
+		name        string
+		filter      []intstr.IntOrString
+		want        []fleetnetv1alpha1.ServicePort
+		wantMissing []string
+	}{
+		{
+			name: "no filter",
+			want: importPorts,
+		},
+		{
+			name:   "filter by name and number",
+			filter: []intstr.IntOrString{intstr.FromString("metrics"), intstr.FromInt32(80)},
+			want: []fleetnetv1alpha1.ServicePort{
+				{Name: "http", Protocol: corev1.ProtocolTCP, Port: 80},
+				{Name: "metrics", Protocol: corev1.ProtocolTCP, Port: 9090},
+			},
+		},
+		{
+			name:   "port is requested by both name and number",
+			filter: []intstr.IntOrString{intstr.FromString("https"), intstr.FromInt32(443)},
+			want: []fleetnetv1alpha1.ServicePort{
+				{Name: "https", Protocol: corev1.ProtocolTCP, Port: 443},
+			},
+		},
+		{
+			name:   "some requested ports are missing",
+			filter: []intstr.IntOrString{intstr.FromString("http"), intstr.FromString("grpc"), intstr.FromInt32(8080)},
+			want: []fleetnetv1alpha1.ServicePort{
+				{Name: "http", Protocol: corev1.ProtocolTCP, Port: 80},
+			},
+			wantMissing: []string{"grpc", "8080"},
+		},
+		{
+			name:        "all requested ports are missing",
+			filter:      []intstr.IntOrString{intstr.FromString("grpc")},
+			want:        []fleetnetv1alpha1.ServicePort{},
+			wantMissing: []string{"grpc"},
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got, gotMissing := filterServiceImportPorts(tc.filter, importPorts)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("filterServiceImportPorts() ports mismatch (-want, +got):\n%s", diff)
+			}
+			if diff := cmp.Diff(tc.wantMissing, gotMissing); diff != "" {
+				t.Errorf("filterServiceImportPorts() missing ports mismatch (-want, +got):\n%s", diff)
+			}
+		})
+	}
+}
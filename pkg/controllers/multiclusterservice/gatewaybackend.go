@@ -0,0 +1,201 @@
+/*
+Copyright (c) Microsoft Corporation.
+Licensed under the MIT license.
+*/
+
+package multiclusterservice
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/klog/v2"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+
+	fleetnetv1alpha1 "go.goms.io/fleet-networking/api/v1alpha1"
+	"go.goms.io/fleet-networking/pkg/common/objectmeta"
+)
+
+const (
+	// GatewayBackendControllerName is the name of the GatewayBackendReconciler.
+	GatewayBackendControllerName = "gatewaybackend-controller"
+
+	// multiClusterServiceAnnotationGatewayBackend marks the mcs whose derived service can be referenced by the
+	// HTTPRoutes in the namespace of the mcs.
+	multiClusterServiceAnnotationGatewayBackend = "networking.fleet.azure.com/gateway-backend"
+
+	gatewayAPIGroup = "gateway.networking.k8s.io"
+	httpRouteKind   = "HTTPRoute"
+	serviceKind     = "Service"
+)
+
+var (
+	// ReferenceGrantGVK is the Gateway API ReferenceGrant maintained for the mcs used as gateway backends.
+	ReferenceGrantGVK = schema.GroupVersionKind{Group: gatewayAPIGroup, Version: "v1beta1", Kind: "ReferenceGrant"}
+
+	referenceGrantListGVK = ReferenceGrantGVK.GroupVersion().WithKind(ReferenceGrantGVK.Kind + "List")
+)
+
+// GatewayBackendReconciler allows the HTTPRoutes in the namespace of a MultiClusterService annotated with
+// networking.fleet.azure.com/gateway-backend: "true" to reference its derived service in the fleet system namespace,
+// by maintaining a ReferenceGrant next to the derived service.
+//
+// The ReferenceGrants are handled as unstructured objects, so that neither the Gateway API types nor their CRDs are
+// required unless the reconciler is set up.
+type GatewayBackendReconciler struct {
+	client.Client
+	Scheme               *runtime.Scheme
+	FleetSystemNamespace string // reserved fleet namespace
+}
+
+//+kubebuilder:rbac:groups=gateway.networking.k8s.io,resources=referencegrants,verbs=get;list;watch;create;update;patch;delete
+
+// Reconcile triggers a single reconcile round.
+func (r *GatewayBackendReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	name := req.NamespacedName
+	mcsKRef := klog.KRef(name.Namespace, name.Name)
+
+	startTime := time.Now()
+	klog.V(2).InfoS("Reconciliation starts", "multiClusterService", mcsKRef)
+	defer func() {
+		latency := time.Since(startTime).Milliseconds()
+		klog.V(2).InfoS("Reconciliation ends", "multiClusterService", mcsKRef, "latency", latency)
+	}()
+
+	mcs := fleetnetv1alpha1.MultiClusterService{}
+	if err := r.Client.Get(ctx, name, &mcs); err != nil {
+		if errors.IsNotFound(err) {
+			klog.V(4).InfoS("Deleting the referenceGrants of the NotFound multiClusterService", "multiClusterService", mcsKRef)
+			return ctrl.Result{}, r.deleteReferenceGrants(ctx, name, "")
+		}
+		klog.ErrorS(err, "Failed to get multiClusterService", "multiClusterService", mcsKRef)
+		return ctrl.Result{}, err
+	}
+
+	derivedServiceName := mcs.GetLabels()[objectmeta.MultiClusterServiceLabelDerivedService]
+	if mcs.DeletionTimestamp != nil || !isGatewayBackend(&mcs) || derivedServiceName == "" {
+		return ctrl.Result{}, r.deleteReferenceGrants(ctx, name, "")
+	}
+
+	service := &corev1.Service{}
+	serviceKey := types.NamespacedName{Namespace: r.FleetSystemNamespace, Name: derivedServiceName}
+	if err := r.Client.Get(ctx, serviceKey, service); err != nil {
+		if errors.IsNotFound(err) {
+			// The reconciler is triggered again once the derived service is created.
+			klog.V(4).InfoS("Derived service is not found", "multiClusterService", mcsKRef, "service", klog.KRef(serviceKey.Namespace, serviceKey.Name))
+			return ctrl.Result{}, r.deleteReferenceGrants(ctx, name, "")
+		}
+		klog.ErrorS(err, "Failed to get derived service", "multiClusterService", mcsKRef, "service", klog.KRef(serviceKey.Namespace, serviceKey.Name))
+		return ctrl.Result{}, err
+	}
+
+	if err := r.applyReferenceGrant(ctx, &mcs, service); err != nil {
+		return ctrl.Result{}, err
+	}
+	// The referenceGrant of a previous derived service is no longer needed.
+	return ctrl.Result{}, r.deleteReferenceGrants(ctx, name, service.Name)
+}
+
+// isGatewayBackend returns true if the derived service of the mcs can be referenced by the HTTPRoutes.
+func isGatewayBackend(mcs *fleetnetv1alpha1.MultiClusterService) bool {
+	isBackend, err := strconv.ParseBool(mcs.Annotations[multiClusterServiceAnnotationGatewayBackend])
+	return err == nil && isBackend
+}
+
+// applyReferenceGrant creates or updates the referenceGrant, named after the derived service, which allows the
+// HTTPRoutes in the namespace of the mcs to reference the derived service.
+func (r *GatewayBackendReconciler) applyReferenceGrant(ctx context.Context, mcs *fleetnetv1alpha1.MultiClusterService, service *corev1.Service) error {
+	grant := &unstructured.Unstructured{}
+	grant.SetGroupVersionKind(ReferenceGrantGVK)
+	grant.SetNamespace(service.Namespace)
+	grant.SetName(service.Name)
+	grantKRef := klog.KRef(grant.GetNamespace(), grant.GetName())
+
+	op, err := controllerutil.CreateOrUpdate(ctx, r.Client, grant, func() error {
+		if grant.GetResourceVersion() == "" {
+			labels := grant.GetLabels()
+			if labels == nil {
+				labels = map[string]string{}
+			}
+			labels[serviceLabelMCSName] = mcs.Name
+			labels[serviceLabelMCSNamespace] = mcs.Namespace
+			grant.SetLabels(labels)
+		} else if labels := grant.GetLabels(); labels[serviceLabelMCSName] != mcs.Name || labels[serviceLabelMCSNamespace] != mcs.Namespace {
+			return fmt.Errorf("referenceGrant %s is not managed for multiClusterService %s/%s", grantKRef, mcs.Namespace, mcs.Name)
+		}
+		// The referenceGrant is garbage collected together with the derived service.
+		if err := controllerutil.SetOwnerReference(service, grant, r.Scheme); err != nil {
+			return err
+		}
+		grant.Object["spec"] = map[string]interface{}{
+			"from": []interface{}{
+				map[string]interface{}{"group": gatewayAPIGroup, "kind": httpRouteKind, "namespace": mcs.Namespace},
+			},
+			"to": []interface{}{
+				map[string]interface{}{"group": "", "kind": serviceKind, "name": service.Name},
+			},
+		}
+		return nil
+	})
+	if err != nil {
+		klog.ErrorS(err, "Failed to create or update referenceGrant", "multiClusterService", klog.KObj(mcs), "referenceGrant", grantKRef)
+		return err
+	}
+	klog.V(2).InfoS("Applied referenceGrant", "multiClusterService", klog.KObj(mcs), "referenceGrant", grantKRef, "op", op)
+	return nil
+}
+
+// deleteReferenceGrants deletes the referenceGrants maintained for the mcs, except the one named keep.
+func (r *GatewayBackendReconciler) deleteReferenceGrants(ctx context.Context, mcsName types.NamespacedName, keep string) error {
+	mcsKRef := klog.KRef(mcsName.Namespace, mcsName.Name)
+	grants := &unstructured.UnstructuredList{}
+	grants.SetGroupVersionKind(referenceGrantListGVK)
+	if err := r.Client.List(ctx, grants, client.InNamespace(r.FleetSystemNamespace), client.MatchingLabels{
+		serviceLabelMCSName:      mcsName.Name,
+		serviceLabelMCSNamespace: mcsName.Namespace,
+	}); err != nil {
+		klog.ErrorS(err, "Failed to list referenceGrants", "multiClusterService", mcsKRef)
+		return err
+	}
+	for i := range grants.Items {
+		grant := &grants.Items[i]
+		if grant.GetName() == keep {
+			continue
+		}
+		if err := r.Client.Delete(ctx, grant); err != nil && !errors.IsNotFound(err) {
+			klog.ErrorS(err, "Failed to delete referenceGrant", "multiClusterService", mcsKRef, "referenceGrant", klog.KObj(grant))
+			return err
+		}
+		klog.V(2).InfoS("Deleted referenceGrant", "multiClusterService", mcsKRef, "referenceGrant", klog.KObj(grant))
+	}
+	return nil
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *GatewayBackendReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	grant := &unstructured.Unstructured{}
+	grant.SetGroupVersionKind(ReferenceGrantGVK)
+	return ctrl.NewControllerManagedBy(mgr).
+		Named(GatewayBackendControllerName).
+		For(&fleetnetv1alpha1.MultiClusterService{}).
+		Watches(
+			&corev1.Service{},
+			handler.EnqueueRequestsFromMapFunc(derivedObjectEventHandler(r.FleetSystemNamespace)),
+		).
+		Watches(
+			grant,
+			handler.EnqueueRequestsFromMapFunc(derivedObjectEventHandler(r.FleetSystemNamespace)),
+		).
+		Complete(r)
+}
@@ -0,0 +1,117 @@
+/*
+Copyright (c) Microsoft Corporation.
+Licensed under the MIT license.
+*/
+
+package multiclusterservice
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+
+	fleetnetv1alpha1 "go.goms.io/fleet-networking/api/v1alpha1"
+	"go.goms.io/fleet-networking/pkg/common/objectmeta"
+)
+
+var _ = Describe("Test GatewayBackend Controller", func() {
+	const (
+		timeout  = time.Second * 10
+		interval = time.Millisecond * 250
+
+		gatewayMCSName     = "gateway-mcs"
+		gatewayServiceName = "gateway-svc"
+	)
+
+	BeforeEach(func() {
+		if !gatewayAPIInstalled {
+			Skip("the Gateway API CRDs are not installed; set GATEWAY_API_CRD_PATH to run the test")
+		}
+	})
+
+	It("Should maintain the referenceGrant of an annotated MultiClusterService", func() {
+		By("By creating a new annotated MultiClusterService")
+		mcs := multiClusterServiceForTest()
+		mcs.Name = gatewayMCSName
+		mcs.Spec.ServiceImport.Name = gatewayServiceName
+		mcs.Annotations = map[string]string{multiClusterServiceAnnotationGatewayBackend: "true"}
+		Expect(k8sClient.Create(ctx, mcs)).Should(Succeed())
+
+		By("By updating service import status")
+		serviceImport := &fleetnetv1alpha1.ServiceImport{}
+		serviceImportKey := types.NamespacedName{Name: gatewayServiceName, Namespace: testNamespace}
+		Eventually(func() error {
+			return k8sClient.Get(ctx, serviceImportKey, serviceImport)
+		}, timeout, interval).Should(Succeed())
+		serviceImport.Status = fleetnetv1alpha1.ServiceImportStatus{
+			Type:     fleetnetv1alpha1.ClusterSetIP,
+			Clusters: []fleetnetv1alpha1.ClusterStatus{{Cluster: "member1"}},
+			Ports:    []fleetnetv1alpha1.ServicePort{{Name: "http", Port: 8080, Protocol: corev1.ProtocolTCP}},
+		}
+		Expect(k8sClient.Status().Update(ctx, serviceImport)).Should(Succeed())
+
+		mcsKey := types.NamespacedName{Name: gatewayMCSName, Namespace: testNamespace}
+		var derivedName string
+		Eventually(func() string {
+			if err := k8sClient.Get(ctx, mcsKey, mcs); err != nil {
+				return ""
+			}
+			derivedName = mcs.GetLabels()[objectmeta.MultiClusterServiceLabelDerivedService]
+			return derivedName
+		}, timeout, interval).ShouldNot(BeEmpty())
+
+		grantKey := types.NamespacedName{Name: derivedName, Namespace: systemNamespace}
+		getGrant := func() (*unstructured.Unstructured, error) {
+			grant := &unstructured.Unstructured{}
+			grant.SetGroupVersionKind(ReferenceGrantGVK)
+			return grant, k8sClient.Get(ctx, grantKey, grant)
+		}
+
+		By("By checking the referenceGrant")
+		Eventually(func() (string, error) {
+			grant, err := getGrant()
+			if err != nil {
+				return "", err
+			}
+			from, _, err := unstructured.NestedSlice(grant.Object, "spec", "from")
+			if err != nil || len(from) != 1 {
+				return "", err
+			}
+			namespace, _, err := unstructured.NestedString(from[0].(map[string]interface{}), "namespace")
+			return namespace, err
+		}, timeout, interval).Should(Equal(testNamespace))
+
+		By("By removing the annotation")
+		Expect(k8sClient.Get(ctx, mcsKey, mcs)).Should(Succeed())
+		mcs.Annotations = nil
+		Expect(k8sClient.Update(ctx, mcs)).Should(Succeed())
+		Eventually(func() bool {
+			_, err := getGrant()
+			return errors.IsNotFound(err)
+		}, timeout, interval).Should(BeTrue())
+
+		By("By adding the annotation back")
+		Expect(k8sClient.Get(ctx, mcsKey, mcs)).Should(Succeed())
+		mcs.Annotations = map[string]string{multiClusterServiceAnnotationGatewayBackend: "true"}
+		Expect(k8sClient.Update(ctx, mcs)).Should(Succeed())
+		Eventually(func() error {
+			_, err := getGrant()
+			return err
+		}, timeout, interval).Should(Succeed())
+
+		By("By deleting the MultiClusterService")
+		Expect(k8sClient.Delete(ctx, mcs)).Should(Succeed())
+		Eventually(func() bool {
+			_, err := getGrant()
+			return errors.IsNotFound(err)
+		}, timeout, interval).Should(BeTrue())
+		Eventually(func() bool {
+			return errors.IsNotFound(k8sClient.Get(ctx, mcsKey, mcs))
+		}, timeout, interval).Should(BeTrue())
+	})
+})
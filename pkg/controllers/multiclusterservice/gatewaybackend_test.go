@@ -0,0 +1,205 @@
+/*
+Copyright (c) Microsoft Corporation.
+Licensed under the MIT license.
+*/
+
+package multiclusterservice
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	fleetnetv1alpha1 "go.goms.io/fleet-networking/api/v1alpha1"
+	"go.goms.io/fleet-networking/pkg/common/objectmeta"
+)
+
+const (
+	previousDerivedServiceName = "my-ns-my-mcs-previous"
+)
+
+func gatewayBackendMCSForTest(annotation string) *fleetnetv1alpha1.MultiClusterService {
+	mcs := multiClusterServiceForTest()
+	mcs.Labels = map[string]string{objectmeta.MultiClusterServiceLabelDerivedService: derivedServiceName}
+	if annotation != "" {
+		mcs.Annotations = map[string]string{multiClusterServiceAnnotationGatewayBackend: annotation}
+	}
+	return mcs
+}
+
+func derivedServiceForTest(name string) *corev1.Service {
+	return &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: systemNamespace,
+			UID:       types.UID(name),
+			Labels: map[string]string{
+				serviceLabelMCSName:      testName,
+				serviceLabelMCSNamespace: testNamespace,
+			},
+		},
+	}
+}
+
+func referenceGrantForTest(name string, labels map[string]string) *unstructured.Unstructured {
+	grant := &unstructured.Unstructured{}
+	grant.SetGroupVersionKind(ReferenceGrantGVK)
+	grant.SetNamespace(systemNamespace)
+	grant.SetName(name)
+	grant.SetLabels(labels)
+	return grant
+}
+
+func mcsLabels() map[string]string {
+	return map[string]string{
+		serviceLabelMCSName:      testName,
+		serviceLabelMCSNamespace: testNamespace,
+	}
+}
+
+func wantReferenceGrant(serviceName string) *unstructured.Unstructured {
+	grant := referenceGrantForTest(serviceName, mcsLabels())
+	grant.SetOwnerReferences([]metav1.OwnerReference{
+		{APIVersion: "v1", Kind: "Service", Name: serviceName, UID: types.UID(serviceName)},
+	})
+	grant.Object["spec"] = map[string]interface{}{
+		"from": []interface{}{
+			map[string]interface{}{"group": gatewayAPIGroup, "kind": httpRouteKind, "namespace": testNamespace},
+		},
+		"to": []interface{}{
+			map[string]interface{}{"group": "", "kind": serviceKind, "name": serviceName},
+		},
+	}
+	return grant
+}
+
+func TestGatewayBackendReconciler(t *testing.T) {
+	tests := []struct {
+		name       string
+		objects    []client.Object
+		wantGrants []*unstructured.Unstructured
+		wantErr    bool
+	}{
+		{
+			name: "annotated mcs",
+			objects: []client.Object{
+				gatewayBackendMCSForTest("true"),
+				derivedServiceForTest(derivedServiceName),
+			},
+			wantGrants: []*unstructured.Unstructured{wantReferenceGrant(derivedServiceName)},
+		},
+		{
+			name: "annotated mcs with an outdated referenceGrant",
+			objects: []client.Object{
+				gatewayBackendMCSForTest("true"),
+				derivedServiceForTest(derivedServiceName),
+				referenceGrantForTest(derivedServiceName, mcsLabels()),
+			},
+			wantGrants: []*unstructured.Unstructured{wantReferenceGrant(derivedServiceName)},
+		},
+		{
+			name: "annotated mcs whose derived service has changed",
+			objects: []client.Object{
+				gatewayBackendMCSForTest("true"),
+				derivedServiceForTest(derivedServiceName),
+				referenceGrantForTest(previousDerivedServiceName, mcsLabels()),
+			},
+			wantGrants: []*unstructured.Unstructured{wantReferenceGrant(derivedServiceName)},
+		},
+		{
+			name: "annotated mcs whose derived service is not created yet",
+			objects: []client.Object{
+				gatewayBackendMCSForTest("true"),
+			},
+		},
+		{
+			name: "annotation removed",
+			objects: []client.Object{
+				gatewayBackendMCSForTest(""),
+				derivedServiceForTest(derivedServiceName),
+				referenceGrantForTest(derivedServiceName, mcsLabels()),
+			},
+		},
+		{
+			name: "annotation set to false",
+			objects: []client.Object{
+				gatewayBackendMCSForTest("false"),
+				derivedServiceForTest(derivedServiceName),
+				referenceGrantForTest(derivedServiceName, mcsLabels()),
+			},
+		},
+		{
+			name: "mcs deleted",
+			objects: []client.Object{
+				derivedServiceForTest(derivedServiceName),
+				referenceGrantForTest(derivedServiceName, mcsLabels()),
+			},
+		},
+		{
+			name: "referenceGrant of another mcs is kept",
+			objects: []client.Object{
+				referenceGrantForTest(derivedServiceName, map[string]string{
+					serviceLabelMCSName:      "other-mcs",
+					serviceLabelMCSNamespace: testNamespace,
+				}),
+			},
+			wantGrants: []*unstructured.Unstructured{
+				referenceGrantForTest(derivedServiceName, map[string]string{
+					serviceLabelMCSName:      "other-mcs",
+					serviceLabelMCSNamespace: testNamespace,
+				}),
+			},
+		},
+		{
+			name: "referenceGrant not managed by the controller",
+			objects: []client.Object{
+				gatewayBackendMCSForTest("true"),
+				derivedServiceForTest(derivedServiceName),
+				referenceGrantForTest(derivedServiceName, nil),
+			},
+			wantGrants: []*unstructured.Unstructured{referenceGrantForTest(derivedServiceName, nil)},
+			wantErr:    true,
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx := context.Background()
+			fakeClient := fake.NewClientBuilder().
+				WithScheme(multiClusterServiceScheme(t)).
+				WithObjects(tc.objects...).
+				Build()
+			r := &GatewayBackendReconciler{
+				Client:               fakeClient,
+				Scheme:               fakeClient.Scheme(),
+				FleetSystemNamespace: systemNamespace,
+			}
+			if _, err := r.Reconcile(ctx, multiClusterServiceRequest()); (err != nil) != tc.wantErr {
+				t.Fatalf("Reconcile() got error %v, want error %t", err, tc.wantErr)
+			}
+
+			grants := &unstructured.UnstructuredList{}
+			grants.SetGroupVersionKind(referenceGrantListGVK)
+			if err := fakeClient.List(ctx, grants, client.InNamespace(systemNamespace)); err != nil {
+				t.Fatalf("failed to list referenceGrants: %v", err)
+			}
+			got := make([]*unstructured.Unstructured, 0, len(grants.Items))
+			for i := range grants.Items {
+				grant := &grants.Items[i]
+				unstructured.RemoveNestedField(grant.Object, "metadata", "resourceVersion")
+				unstructured.RemoveNestedField(grant.Object, "metadata", "creationTimestamp")
+				got = append(got, grant)
+			}
+			if diff := cmp.Diff(tc.wantGrants, got, cmpopts.EquateEmpty()); diff != "" {
+				t.Errorf("referenceGrants mismatch (-want, +got):\n%s", diff)
+			}
+		})
+	}
+}
@@ -8,6 +8,7 @@ package multiclusterservice
 import (
 	"context"
 	"flag"
+	"os"
 	"path/filepath"
 	"testing"
 
@@ -15,6 +16,7 @@ import (
 	. "github.com/onsi/gomega"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/discovery"
 	"k8s.io/client-go/kubernetes/scheme"
 	"k8s.io/client-go/rest"
 	"k8s.io/klog/v2"
@@ -26,6 +28,7 @@ import (
 	metricsserver "sigs.k8s.io/controller-runtime/pkg/metrics/server"
 
 	// +kubebuilder:scaffold:imports
+	"go.goms.io/fleet/pkg/utils"
 
 	fleetnetv1alpha1 "go.goms.io/fleet-networking/api/v1alpha1"
 )
@@ -37,6 +40,10 @@ var (
 	testEnv   *envtest.Environment
 	ctx       context.Context
 	cancel    context.CancelFunc
+
+	// gatewayAPIInstalled is true if the Gateway API CRDs, which are not part of this repo, are installed in the test
+	// environment from the directory set in the GATEWAY_API_CRD_PATH environment variable.
+	gatewayAPIInstalled bool
 )
 
 func TestAPIs(t *testing.T) {
@@ -51,8 +58,12 @@ var _ = BeforeSuite(func() {
 	ctx, cancel = context.WithCancel(context.TODO())
 
 	By("bootstrapping test environment")
+	crdPaths := []string{filepath.Join("../../../", "config", "crd", "bases")}
+	if gatewayAPICRDPath := os.Getenv("GATEWAY_API_CRD_PATH"); gatewayAPICRDPath != "" {
+		crdPaths = append(crdPaths, gatewayAPICRDPath)
+	}
 	testEnv = &envtest.Environment{
-		CRDDirectoryPaths:     []string{filepath.Join("../../../", "config", "crd", "bases")},
+		CRDDirectoryPaths:     crdPaths,
 		ErrorIfCRDPathMissing: true,
 	}
 
@@ -90,6 +101,16 @@ var _ = BeforeSuite(func() {
 	}).SetupWithManager(mgr)
 	Expect(err).ToNot(HaveOccurred())
 
+	gatewayAPIInstalled = utils.CheckCRDInstalled(discovery.NewDiscoveryClientForConfigOrDie(cfg), ReferenceGrantGVK) == nil
+	if gatewayAPIInstalled {
+		err = (&GatewayBackendReconciler{
+			Client:               mgr.GetClient(),
+			Scheme:               mgr.GetScheme(),
+			FleetSystemNamespace: "fleet-system",
+		}).SetupWithManager(mgr)
+		Expect(err).ToNot(HaveOccurred())
+	}
+
 	ctx, cancel = context.WithCancel(context.TODO())
 
 	By("Create multiClusterService namespace")
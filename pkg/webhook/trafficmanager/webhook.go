@@ -0,0 +1,84 @@
+/*
+Copyright (c) Microsoft Corporation.
+Licensed under the MIT license.
+*/
+
+// Package trafficmanager features the mutating admission webhooks which set the default values of the
+// TrafficManagerProfile and TrafficManagerBackend.
+package trafficmanager
+
+import (
+	"context"
+	"fmt"
+
+	admissionv1 "k8s.io/api/admission/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/klog/v2"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	fleetnetv1beta1 "go.goms.io/fleet-networking/api/v1beta1"
+	"go.goms.io/fleet-networking/pkg/common/defaulter"
+)
+
+//+kubebuilder:webhook:path=/mutate-networking-fleet-azure-com-v1beta1-trafficmanagerprofile,mutating=true,failurePolicy=fail,sideEffects=None,groups=networking.fleet.azure.com,resources=trafficmanagerprofiles,verbs=create;update,versions=v1beta1,name=mtrafficmanagerprofile.networking.fleet.azure.com,admissionReviewVersions=v1
+//+kubebuilder:webhook:path=/mutate-networking-fleet-azure-com-v1beta1-trafficmanagerbackend,mutating=true,failurePolicy=fail,sideEffects=None,groups=networking.fleet.azure.com,resources=trafficmanagerbackends,verbs=create;update,versions=v1beta1,name=mtrafficmanagerbackend.networking.fleet.azure.com,admissionReviewVersions=v1
+
+// SetupWebhooksWithManager registers the defaulting webhooks of the traffic manager APIs with the webhook server
+// of the manager.
+func SetupWebhooksWithManager(mgr ctrl.Manager) error {
+	if err := ctrl.NewWebhookManagedBy(mgr).
+		For(&fleetnetv1beta1.TrafficManagerProfile{}).
+		WithDefaulter(&profileDefaulter{}).
+		Complete(); err != nil {
+		return fmt.Errorf("failed to set up the trafficManagerProfile defaulting webhook: %w", err)
+	}
+	if err := ctrl.NewWebhookManagedBy(mgr).
+		For(&fleetnetv1beta1.TrafficManagerBackend{}).
+		WithDefaulter(&backendDefaulter{}).
+		Complete(); err != nil {
+		return fmt.Errorf("failed to set up the trafficManagerBackend defaulting webhook: %w", err)
+	}
+	return nil
+}
+
+// profileDefaulter sets the default values of a TrafficManagerProfile at admission.
+type profileDefaulter struct{}
+
+// Default implements the admission.CustomDefaulter interface.
+func (d *profileDefaulter) Default(ctx context.Context, obj runtime.Object) error {
+	profile, ok := obj.(*fleetnetv1beta1.TrafficManagerProfile)
+	if !ok {
+		return fmt.Errorf("unexpected type %T, want *v1beta1.TrafficManagerProfile", obj)
+	}
+	setDefaultsVersionOnCreate(ctx, profile)
+	defaulter.SetDefaultsTrafficManagerProfile(profile)
+	klog.V(4).InfoS("Set the defaults of trafficManagerProfile", "trafficManagerProfile", klog.KObj(profile))
+	return nil
+}
+
+// backendDefaulter sets the default values of a TrafficManagerBackend at admission.
+type backendDefaulter struct{}
+
+// Default implements the admission.CustomDefaulter interface.
+func (d *backendDefaulter) Default(ctx context.Context, obj runtime.Object) error {
+	backend, ok := obj.(*fleetnetv1beta1.TrafficManagerBackend)
+	if !ok {
+		return fmt.Errorf("unexpected type %T, want *v1beta1.TrafficManagerBackend", obj)
+	}
+	setDefaultsVersionOnCreate(ctx, backend)
+	defaulter.SetDefaultsTrafficManagerBackend(backend)
+	klog.V(4).InfoS("Set the defaults of trafficManagerBackend", "trafficManagerBackend", klog.KObj(backend))
+	return nil
+}
+
+// setDefaultsVersionOnCreate records the latest version of the defaults on the newly created objects; the existing
+// objects keep the version they have been created with.
+func setDefaultsVersionOnCreate(ctx context.Context, obj client.Object) {
+	req, err := admission.RequestFromContext(ctx)
+	if err != nil || req.Operation != admissionv1.Create {
+		return
+	}
+	defaulter.SetLatestDefaultsVersion(obj)
+}
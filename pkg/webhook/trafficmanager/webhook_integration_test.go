@@ -0,0 +1,125 @@
+/*
+Copyright (c) Microsoft Corporation.
+Licensed under the MIT license.
+*/
+
+package trafficmanager
+
+import (
+	"github.com/google/go-cmp/cmp"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
+
+	fleetnetv1beta1 "go.goms.io/fleet-networking/api/v1beta1"
+	"go.goms.io/fleet-networking/pkg/common/defaulter"
+	"go.goms.io/fleet-networking/pkg/common/objectmeta"
+)
+
+const (
+	profileName = "test-profile"
+	backendName = "test-backend"
+)
+
+var _ = Describe("Test TrafficManager defaulting webhooks", func() {
+	Context("When creating trafficManagerProfile", Ordered, func() {
+		profile := &fleetnetv1beta1.TrafficManagerProfile{}
+
+		AfterAll(func() {
+			Expect(hubClient.Delete(ctx, profile)).Should(Succeed())
+		})
+
+		It("Creating a profile without monitorConfig", func() {
+			profile = &fleetnetv1beta1.TrafficManagerProfile{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      profileName,
+					Namespace: testNamespace,
+				},
+				Spec: fleetnetv1beta1.TrafficManagerProfileSpec{
+					ResourceGroup: "test-resource-group",
+				},
+			}
+			Expect(hubClient.Create(ctx, profile)).Should(Succeed())
+		})
+
+		It("Should persist the profile with the defaults", func() {
+			got := &fleetnetv1beta1.TrafficManagerProfile{}
+			Expect(hubClient.Get(ctx, types.NamespacedName{Namespace: testNamespace, Name: profileName}, got)).Should(Succeed())
+			want := &fleetnetv1beta1.MonitorConfig{
+				IntervalInSeconds:         ptr.To(int64(30)),
+				Path:                      ptr.To("/"),
+				Port:                      ptr.To(int64(80)),
+				Protocol:                  ptr.To(fleetnetv1beta1.TrafficManagerMonitorProtocolHTTP),
+				TimeoutInSeconds:          ptr.To(int64(10)),
+				ToleratedNumberOfFailures: ptr.To(int64(3)),
+			}
+			Expect(cmp.Diff(want, got.Spec.MonitorConfig)).Should(BeEmpty(), "monitorConfig mismatch (-want, +got)")
+			Expect(got.Annotations).Should(HaveKeyWithValue(objectmeta.DefaultsVersionAnnotation, defaulter.LatestDefaultsVersion))
+		})
+
+		It("Updating the profile with a partial monitorConfig", func() {
+			Expect(hubClient.Get(ctx, types.NamespacedName{Namespace: testNamespace, Name: profileName}, profile)).Should(Succeed())
+			profile.Spec.MonitorConfig = &fleetnetv1beta1.MonitorConfig{
+				IntervalInSeconds: ptr.To(int64(10)),
+			}
+			Expect(hubClient.Update(ctx, profile)).Should(Succeed())
+		})
+
+		It("Should persist the updated profile with the defaults", func() {
+			got := &fleetnetv1beta1.TrafficManagerProfile{}
+			Expect(hubClient.Get(ctx, types.NamespacedName{Namespace: testNamespace, Name: profileName}, got)).Should(Succeed())
+			want := &fleetnetv1beta1.MonitorConfig{
+				IntervalInSeconds:         ptr.To(int64(10)),
+				Path:                      ptr.To("/"),
+				Port:                      ptr.To(int64(80)),
+				Protocol:                  ptr.To(fleetnetv1beta1.TrafficManagerMonitorProtocolHTTP),
+				TimeoutInSeconds:          ptr.To(int64(9)),
+				ToleratedNumberOfFailures: ptr.To(int64(3)),
+			}
+			Expect(cmp.Diff(want, got.Spec.MonitorConfig)).Should(BeEmpty(), "monitorConfig mismatch (-want, +got)")
+		})
+	})
+
+	Context("When creating trafficManagerBackend", Ordered, func() {
+		backend := &fleetnetv1beta1.TrafficManagerBackend{}
+
+		AfterAll(func() {
+			Expect(hubClient.Delete(ctx, backend)).Should(Succeed())
+		})
+
+		It("Creating a backend without weight", func() {
+			backend = &fleetnetv1beta1.TrafficManagerBackend{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      backendName,
+					Namespace: testNamespace,
+				},
+				Spec: fleetnetv1beta1.TrafficManagerBackendSpec{
+					Profile: fleetnetv1beta1.TrafficManagerProfileRef{Name: profileName},
+					Backend: fleetnetv1beta1.TrafficManagerBackendRef{Name: "test-service"},
+				},
+			}
+			Expect(hubClient.Create(ctx, backend)).Should(Succeed())
+		})
+
+		It("Should persist the backend with the defaults", func() {
+			got := &fleetnetv1beta1.TrafficManagerBackend{}
+			Expect(hubClient.Get(ctx, types.NamespacedName{Namespace: testNamespace, Name: backendName}, got)).Should(Succeed())
+			Expect(got.Spec.Weight).Should(Equal(ptr.To(int64(1))))
+			Expect(got.Annotations).Should(HaveKeyWithValue(objectmeta.DefaultsVersionAnnotation, defaulter.LatestDefaultsVersion))
+		})
+
+		It("Updating the backend to remove the weight", func() {
+			Expect(hubClient.Get(ctx, types.NamespacedName{Namespace: testNamespace, Name: backendName}, backend)).Should(Succeed())
+			backend.Spec.Weight = nil
+			Expect(hubClient.Update(ctx, backend)).Should(Succeed())
+		})
+
+		It("Should persist the updated backend with the defaults", func() {
+			got := &fleetnetv1beta1.TrafficManagerBackend{}
+			Expect(hubClient.Get(ctx, types.NamespacedName{Namespace: testNamespace, Name: backendName}, got)).Should(Succeed())
+			Expect(got.Spec.Weight).Should(Equal(ptr.To(int64(1))))
+		})
+	})
+})
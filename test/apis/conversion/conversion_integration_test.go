@@ -0,0 +1,177 @@
+/*
+Copyright (c) Microsoft Corporation.
+Licensed under the MIT license.
+*/
+
+package conversion
+
+import (
+	"github.com/google/go-cmp/cmp"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
+
+	fleetnetv1alpha1 "go.goms.io/fleet-networking/api/v1alpha1"
+	fleetnetv1beta1 "go.goms.io/fleet-networking/api/v1beta1"
+)
+
+var _ = Describe("Test conversion between the networking v1alpha1 and v1beta1 APIs", func() {
+	Context("Test TrafficManagerProfile conversion", func() {
+		name := "traffic-manager-profile-conversion"
+		key := types.NamespacedName{Namespace: testNamespace, Name: name}
+
+		AfterEach(func() {
+			profile := &fleetnetv1beta1.TrafficManagerProfile{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: testNamespace,
+					Name:      name,
+				},
+			}
+			Expect(hubClient.Delete(ctx, profile)).Should(Succeed())
+		})
+
+		It("should read and write the same object through both versions", func() {
+			By("Creating the profile using the v1alpha1 API")
+			protocol := fleetnetv1alpha1.TrafficManagerMonitorProtocolHTTPS
+			alphaProfile := &fleetnetv1alpha1.TrafficManagerProfile{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: testNamespace,
+					Name:      name,
+				},
+				Spec: fleetnetv1alpha1.TrafficManagerProfileSpec{
+					ResourceGroup: "test-resource-group",
+					MonitorConfig: &fleetnetv1alpha1.MonitorConfig{
+						IntervalInSeconds:         ptr.To(int64(10)),
+						Path:                      ptr.To("/healthz"),
+						Port:                      ptr.To(int64(8080)),
+						Protocol:                  &protocol,
+						TimeoutInSeconds:          ptr.To(int64(9)),
+						ToleratedNumberOfFailures: ptr.To(int64(4)),
+					},
+				},
+			}
+			Expect(hubClient.Create(ctx, alphaProfile)).Should(Succeed())
+
+			By("Reading the profile using the v1beta1 API")
+			betaProfile := &fleetnetv1beta1.TrafficManagerProfile{}
+			Expect(hubClient.Get(ctx, key, betaProfile)).Should(Succeed())
+			betaProtocol := fleetnetv1beta1.TrafficManagerMonitorProtocolHTTPS
+			wantBetaSpec := fleetnetv1beta1.TrafficManagerProfileSpec{
+				ResourceGroup: "test-resource-group",
+				MonitorConfig: &fleetnetv1beta1.MonitorConfig{
+					IntervalInSeconds:         ptr.To(int64(10)),
+					Path:                      ptr.To("/healthz"),
+					Port:                      ptr.To(int64(8080)),
+					Protocol:                  &betaProtocol,
+					TimeoutInSeconds:          ptr.To(int64(9)),
+					ToleratedNumberOfFailures: ptr.To(int64(4)),
+				},
+			}
+			Expect(cmp.Diff(wantBetaSpec, betaProfile.Spec)).Should(BeEmpty(), "v1beta1 spec mismatch (-want, +got)")
+
+			By("Updating the profile status using the v1beta1 API")
+			betaProfile.Status = fleetnetv1beta1.TrafficManagerProfileStatus{
+				DNSName:    ptr.To("test-dns-name.trafficmanager.net"),
+				ResourceID: "test-resource-id",
+				Conditions: []metav1.Condition{
+					{
+						Type:               string(fleetnetv1beta1.TrafficManagerProfileConditionProgrammed),
+						Status:             metav1.ConditionTrue,
+						Reason:             string(fleetnetv1beta1.TrafficManagerProfileReasonProgrammed),
+						ObservedGeneration: betaProfile.Generation,
+						LastTransitionTime: metav1.Now(),
+					},
+				},
+			}
+			Expect(hubClient.Status().Update(ctx, betaProfile)).Should(Succeed())
+
+			By("Reading the profile using the v1alpha1 API")
+			gotAlphaProfile := &fleetnetv1alpha1.TrafficManagerProfile{}
+			Expect(hubClient.Get(ctx, key, gotAlphaProfile)).Should(Succeed())
+			Expect(cmp.Diff(alphaProfile.Spec, gotAlphaProfile.Spec)).Should(BeEmpty(), "v1alpha1 spec mismatch (-want, +got)")
+			Expect(gotAlphaProfile.Status.DNSName).Should(Equal(betaProfile.Status.DNSName))
+			Expect(gotAlphaProfile.Status.ResourceID).Should(Equal(betaProfile.Status.ResourceID))
+			Expect(gotAlphaProfile.Status.Conditions).Should(HaveLen(1))
+			Expect(gotAlphaProfile.Status.Conditions[0].Reason).Should(Equal(string(fleetnetv1alpha1.TrafficManagerProfileReasonProgrammed)))
+		})
+	})
+
+	Context("Test TrafficManagerBackend conversion", func() {
+		name := "traffic-manager-backend-conversion"
+		key := types.NamespacedName{Namespace: testNamespace, Name: name}
+
+		AfterEach(func() {
+			backend := &fleetnetv1alpha1.TrafficManagerBackend{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: testNamespace,
+					Name:      name,
+				},
+			}
+			Expect(hubClient.Delete(ctx, backend)).Should(Succeed())
+		})
+
+		It("should read and write the same object through both versions", func() {
+			By("Creating the backend using the v1beta1 API")
+			betaBackend := &fleetnetv1beta1.TrafficManagerBackend{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: testNamespace,
+					Name:      name,
+				},
+				Spec: fleetnetv1beta1.TrafficManagerBackendSpec{
+					Profile: fleetnetv1beta1.TrafficManagerProfileRef{
+						Name: "test-profile",
+					},
+					Backend: fleetnetv1beta1.TrafficManagerBackendRef{
+						Name: "test-backend",
+					},
+					Weight: ptr.To(int64(100)),
+				},
+			}
+			Expect(hubClient.Create(ctx, betaBackend)).Should(Succeed())
+
+			By("Updating the backend status using the v1alpha1 API")
+			alphaBackend := &fleetnetv1alpha1.TrafficManagerBackend{}
+			Expect(hubClient.Get(ctx, key, alphaBackend)).Should(Succeed())
+			Expect(alphaBackend.Spec.Profile.Name).Should(Equal("test-profile"))
+			Expect(alphaBackend.Spec.Backend.Name).Should(Equal("test-backend"))
+			Expect(alphaBackend.Spec.Weight).Should(Equal(ptr.To(int64(100))))
+			alphaBackend.Status.Endpoints = []fleetnetv1alpha1.TrafficManagerEndpointStatus{
+				{
+					Name:       "test-endpoint",
+					ResourceID: "test-endpoint-resource-id",
+					Weight:     ptr.To(int64(100)),
+					Target:     ptr.To("1.2.3.4"),
+					From: &fleetnetv1alpha1.FromCluster{
+						ClusterStatus: fleetnetv1alpha1.ClusterStatus{
+							Cluster: "member-1",
+						},
+						Weight: ptr.To(int64(50)),
+					},
+				},
+			}
+			Expect(hubClient.Status().Update(ctx, alphaBackend)).Should(Succeed())
+
+			By("Reading the backend using the v1beta1 API")
+			gotBetaBackend := &fleetnetv1beta1.TrafficManagerBackend{}
+			Expect(hubClient.Get(ctx, key, gotBetaBackend)).Should(Succeed())
+			Expect(cmp.Diff(betaBackend.Spec, gotBetaBackend.Spec)).Should(BeEmpty(), "v1beta1 spec mismatch (-want, +got)")
+			wantEndpoints := []fleetnetv1beta1.TrafficManagerEndpointStatus{
+				{
+					Name:       "test-endpoint",
+					ResourceID: "test-endpoint-resource-id",
+					Weight:     ptr.To(int64(100)),
+					Target:     ptr.To("1.2.3.4"),
+					From: &fleetnetv1beta1.FromCluster{
+						ClusterStatus: fleetnetv1beta1.ClusterStatus{
+							Cluster: "member-1",
+						},
+						Weight: ptr.To(int64(50)),
+					},
+				},
+			}
+			Expect(cmp.Diff(wantEndpoints, gotBetaBackend.Status.Endpoints)).Should(BeEmpty(), "v1beta1 endpoints mismatch (-want, +got)")
+		})
+	})
+})
@@ -287,4 +287,56 @@ var _ = Describe("Test networking v1alpha1 API validation", func() {
 			Expect(hubClient.Delete(ctx, trafficManagerBackendName)).Should(Succeed(), "failed to delete trafficManagerBackend")
 		})
 	})
+
+	Context("Test TrafficManagerBackend API validation - weight", func() {
+		It("should deny creating API with negative weight", func() {
+			backend := &fleetnetv1beta1.TrafficManagerBackend{
+				ObjectMeta: objectMetaWithNameValid,
+				Spec:       *trafficManagerBackendSpec.DeepCopy(),
+			}
+			backend.Spec.Weight = ptr.To(int64(-1))
+			By("expecting denial of CREATE API with weight -1")
+			var err = hubClient.Create(ctx, backend)
+			Expect(errors.As(err, &statusErr)).To(BeTrue(), fmt.Sprintf("Create API call produced error %s. Error type wanted is %s.", reflect.TypeOf(err), reflect.TypeOf(&k8serrors.StatusError{})))
+			Expect(statusErr.Status().Message).Should(ContainSubstring("spec.weight: Invalid value: -1: spec.weight in body should be greater than or equal to 0"))
+		})
+
+		It("should deny creating API with weight greater than 1000", func() {
+			backend := &fleetnetv1beta1.TrafficManagerBackend{
+				ObjectMeta: objectMetaWithNameValid,
+				Spec:       *trafficManagerBackendSpec.DeepCopy(),
+			}
+			backend.Spec.Weight = ptr.To(int64(1001))
+			By("expecting denial of CREATE API with weight 1001")
+			var err = hubClient.Create(ctx, backend)
+			Expect(errors.As(err, &statusErr)).To(BeTrue(), fmt.Sprintf("Create API call produced error %s. Error type wanted is %s.", reflect.TypeOf(err), reflect.TypeOf(&k8serrors.StatusError{})))
+			Expect(statusErr.Status().Message).Should(ContainSubstring("spec.weight: Invalid value: 1001: spec.weight in body should be less than or equal to 1000"))
+		})
+
+		It("should allow creating API with the weight of 0 and 1000", func() {
+			for _, weight := range []int64{0, 1000} {
+				backend := &fleetnetv1beta1.TrafficManagerBackend{
+					ObjectMeta: objectMetaWithNameValid,
+					Spec:       *trafficManagerBackendSpec.DeepCopy(),
+				}
+				backend.Spec.Weight = ptr.To(weight)
+				Expect(hubClient.Create(ctx, backend)).Should(Succeed(), "failed to create trafficManagerBackend with weight %d", weight)
+				Expect(hubClient.Delete(ctx, backend)).Should(Succeed(), "failed to delete trafficManagerBackend")
+			}
+		})
+
+		It("should deny updating API to a negative weight", func() {
+			backend := &fleetnetv1beta1.TrafficManagerBackend{
+				ObjectMeta: objectMetaWithNameValid,
+				Spec:       *trafficManagerBackendSpec.DeepCopy(),
+			}
+			Expect(hubClient.Create(ctx, backend)).Should(Succeed(), "failed to create trafficManagerBackend")
+			backend.Spec.Weight = ptr.To(int64(-5))
+			By("expecting denial of UPDATE API with weight -5")
+			var err = hubClient.Update(ctx, backend)
+			Expect(errors.As(err, &statusErr)).To(BeTrue(), fmt.Sprintf("Update API call produced error %s. Error type wanted is %s.", reflect.TypeOf(err), reflect.TypeOf(&k8serrors.StatusError{})))
+			Expect(statusErr.Status().Message).Should(ContainSubstring("spec.weight in body should be greater than or equal to 0"))
+			Expect(hubClient.Delete(ctx, backend)).Should(Succeed(), "failed to delete trafficManagerBackend")
+		})
+	})
 })
@@ -0,0 +1,264 @@
+/*
+Copyright (c) Microsoft Corporation.
+Licensed under the MIT license.
+*/
+
+// Package fleetenv provides utils to simulate the member clusters of a fleet in the hub cluster of a single envtest
+// environment, so that the integration tests of the hub controllers can export Services from any number of member
+// clusters without running the member agents.
+//
+// The objects a simulated member cluster writes in the hub cluster are built with the builders of the member agent
+// and written the same way, so that they never drift from the ones written by the real member agents.
+package fleetenv
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	fleetnetv1alpha1 "go.goms.io/fleet-networking/api/v1alpha1"
+	"go.goms.io/fleet-networking/pkg/common/cloudconfig"
+	"go.goms.io/fleet-networking/pkg/common/hubclient"
+	"go.goms.io/fleet-networking/pkg/common/hubconfig"
+	"go.goms.io/fleet-networking/pkg/common/uniquename"
+	"go.goms.io/fleet-networking/pkg/controllers/member/endpointslice"
+	"go.goms.io/fleet-networking/pkg/controllers/member/serviceexport"
+)
+
+const (
+	timeout  = time.Second * 10
+	interval = time.Millisecond * 250
+)
+
+// Member is a member cluster simulated in the hub cluster.
+type Member struct {
+	// Name is the name, i.e. the ID, of the member cluster.
+	Name string
+	// Namespace is the namespace reserved for the member cluster in the hub cluster, where its exports are written.
+	Namespace string
+	// CloudProvider populates the Traffic Manager related information of the exported Services, as the member agent
+	// does when its Traffic Manager feature is enabled; the information is not reported when it is nil.
+	CloudProvider serviceexport.CloudProvider
+	// NetworkProperties are the network properties of the member cluster copied to the exported Services.
+	NetworkProperties cloudconfig.NetworkProperties
+}
+
+// ExportedService is a Service exported by a simulated member cluster.
+type ExportedService struct {
+	InternalServiceExport *fleetnetv1alpha1.InternalServiceExport
+	// EndpointSliceExport is nil when the Service is exported without any endpoint.
+	EndpointSliceExport *fleetnetv1alpha1.EndpointSliceExport
+}
+
+// CloudProvider is the CloudProvider of the simulated member clusters; it populates the given public IP address
+// information for the external load balancer Services, without looking up any cloud resource.
+type CloudProvider struct {
+	// PublicIPResourceID is the resource ID of the public IP address of the load balancers, if any.
+	PublicIPResourceID *string
+	// IsDNSLabelConfigured tells whether a DNS label is configured to the public IP address of the load balancers.
+	IsDNSLabelConfigured bool
+}
+
+var _ serviceexport.CloudProvider = CloudProvider{}
+
+// SetLoadBalancerInformation implements the CloudProvider interface.
+func (p CloudProvider) SetLoadBalancerInformation(_ context.Context, service *corev1.Service, export *fleetnetv1alpha1.InternalServiceExport) error {
+	export.Spec.Type = service.Spec.Type
+	if service.Spec.Type != corev1.ServiceTypeLoadBalancer || len(service.Status.LoadBalancer.Ingress) == 0 {
+		return nil
+	}
+	export.Spec.PublicIPResourceID = p.PublicIPResourceID
+	export.Spec.IsDNSLabelConfigured = p.IsDNSLabelConfigured
+	return nil
+}
+
+// SetPublicIPInformation implements the CloudProvider interface; any public IP address specified is accepted.
+func (p CloudProvider) SetPublicIPInformation(_ context.Context, service *corev1.Service, publicIPResourceID string, export *fleetnetv1alpha1.InternalServiceExport) error {
+	export.Spec.Type = service.Spec.Type
+	export.Spec.PublicIPResourceID = ptr.To(publicIPResourceID)
+	export.Spec.IsDNSLabelConfigured = true
+	export.Spec.IsPublicIPExplicit = true
+	return nil
+}
+
+// CreateSimulatedMember creates the namespace reserved for the member cluster of the given name in the hub cluster
+// and returns the simulated member cluster.
+func CreateSimulatedMember(ctx context.Context, k8sClient client.Client, name string) *Member {
+	member := &Member{
+		Name:      name,
+		Namespace: fmt.Sprintf(hubconfig.HubNamespaceNameFormat, name),
+	}
+	ns := corev1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: member.Namespace,
+		},
+	}
+	gomega.Expect(k8sClient.Create(ctx, &ns)).Should(gomega.Succeed(), "Failed to create the namespace of member cluster %s", name)
+	return member
+}
+
+// CreateSimulatedMembers creates the member clusters of the given names; see CreateSimulatedMember.
+func CreateSimulatedMembers(ctx context.Context, k8sClient client.Client, names ...string) []*Member {
+	members := make([]*Member, 0, len(names))
+	for _, name := range names {
+		members = append(members, CreateSimulatedMember(ctx, k8sClient, name))
+	}
+	return members
+}
+
+// MemberNames returns the names of the member clusters, e.g. member-1, ..., member-<count> for the "member" prefix.
+func MemberNames(prefix string, count int) []string {
+	names := make([]string, 0, count)
+	for i := 1; i <= count; i++ {
+		names = append(names, fmt.Sprintf("%s-%d", prefix, i))
+	}
+	return names
+}
+
+// ExportService exports the Service from the member cluster as its member agent would: the InternalServiceExport and,
+// when any endpoint is given, the EndpointSliceExport of the Service are applied in the hub cluster. The Service is
+// exported under its own name, and may be exported again to update its exports.
+func ExportService(ctx context.Context, k8sClient client.Client, member *Member, svc *corev1.Service, endpoints []fleetnetv1alpha1.Endpoint) *ExportedService {
+	svc = withIdentity(member, svc)
+	svcExport := &fleetnetv1alpha1.ServiceExport{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: svc.Namespace,
+			Name:      svc.Name,
+		},
+	}
+	exportedSince := metav1.NewTime(svc.CreationTimestamp.Time)
+
+	internalSvcExportKey := types.NamespacedName{Namespace: member.Namespace, Name: uniquename.ClusterScopedDeterministicName(svc.Namespace, svc.Name)}
+	svcReference := fleetnetv1alpha1.FromMetaObjects(member.Name, svc.TypeMeta, svc.ObjectMeta, exportedSince)
+	internalSvcExport := serviceexport.BuildInternalServiceExport(internalSvcExportKey, member.Name, svc, svcExport, svcReference, member.NetworkProperties)
+	if member.CloudProvider != nil {
+		_, _, err := serviceexport.SetTrafficManagerInformation(ctx, member.CloudProvider, svc, svcExport, &internalSvcExport)
+		gomega.Expect(err).Should(gomega.Succeed(), "Failed to populate the Traffic Manager information of service %s exported from %s", client.ObjectKeyFromObject(svc), member.Name)
+	}
+	gomega.Expect(hubclient.EnsureInternalServiceExport(ctx, k8sClient, &internalSvcExport)).Should(gomega.Succeed(),
+		"Failed to apply the internalServiceExport of service %s exported from %s", client.ObjectKeyFromObject(svc), member.Name)
+	exported := &ExportedService{InternalServiceExport: &internalSvcExport}
+	if len(endpoints) == 0 {
+		return exported
+	}
+
+	endpointSlice := endpointSliceOf(svc)
+	endpointSliceExportKey := types.NamespacedName{Namespace: member.Namespace, Name: internalSvcExportKey.Name}
+	endpointSliceReference := fleetnetv1alpha1.FromMetaObjects(member.Name, endpointSlice.TypeMeta, endpointSlice.ObjectMeta, exportedSince)
+	endpointSliceExport := endpointslice.BuildEndpointSliceExport(endpointSliceExportKey, member.Name, endpointSlice, endpoints,
+		endpointSliceReference, svc.Namespace, svc.Name)
+	gomega.Expect(hubclient.CreateOrUpdateEndpointSliceExport(ctx, k8sClient, &endpointSliceExport)).Should(gomega.Succeed(),
+		"Failed to apply the endpointSliceExport of service %s exported from %s", client.ObjectKeyFromObject(svc), member.Name)
+	exported.EndpointSliceExport = &endpointSliceExport
+	return exported
+}
+
+// UnexportService deletes the exports of the Service exported from the member cluster, with their finalizers removed
+// as the hub controllers are not guaranteed to be running.
+func UnexportService(ctx context.Context, k8sClient client.Client, exported *ExportedService) {
+	deleteExport(ctx, k8sClient, exported.InternalServiceExport)
+	if exported.EndpointSliceExport != nil {
+		deleteExport(ctx, k8sClient, exported.EndpointSliceExport)
+	}
+}
+
+// DeleteSimulatedMember deletes all the exports of the member cluster and its namespace; the namespace is left
+// terminating, as no namespace controller runs in an envtest environment.
+func DeleteSimulatedMember(ctx context.Context, k8sClient client.Client, member *Member) {
+	internalSvcExportList := &fleetnetv1alpha1.InternalServiceExportList{}
+	gomega.Expect(k8sClient.List(ctx, internalSvcExportList, client.InNamespace(member.Namespace))).Should(gomega.Succeed(),
+		"Failed to list the internalServiceExports of member cluster %s", member.Name)
+	for i := range internalSvcExportList.Items {
+		deleteExport(ctx, k8sClient, &internalSvcExportList.Items[i])
+	}
+	endpointSliceExportList := &fleetnetv1alpha1.EndpointSliceExportList{}
+	gomega.Expect(k8sClient.List(ctx, endpointSliceExportList, client.InNamespace(member.Namespace))).Should(gomega.Succeed(),
+		"Failed to list the endpointSliceExports of member cluster %s", member.Name)
+	for i := range endpointSliceExportList.Items {
+		deleteExport(ctx, k8sClient, &endpointSliceExportList.Items[i])
+	}
+	ns := corev1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: member.Namespace,
+		},
+	}
+	gomega.Expect(client.IgnoreNotFound(k8sClient.Delete(ctx, &ns))).Should(gomega.Succeed(), "Failed to delete the namespace of member cluster %s", member.Name)
+}
+
+// DeleteSimulatedMembers deletes the member clusters; see DeleteSimulatedMember.
+func DeleteSimulatedMembers(ctx context.Context, k8sClient client.Client, members []*Member) {
+	for _, member := range members {
+		DeleteSimulatedMember(ctx, k8sClient, member)
+	}
+}
+
+// deleteExport removes the finalizers of the exported object, if any, and deletes it.
+func deleteExport(ctx context.Context, k8sClient client.Client, obj client.Object) {
+	key := client.ObjectKeyFromObject(obj)
+	gomega.Eventually(func() error {
+		if err := k8sClient.Get(ctx, key, obj); err != nil {
+			return client.IgnoreNotFound(err)
+		}
+		if len(obj.GetFinalizers()) > 0 {
+			for _, finalizer := range obj.GetFinalizers() {
+				controllerutil.RemoveFinalizer(obj, finalizer)
+			}
+			if err := k8sClient.Update(ctx, obj); err != nil {
+				return client.IgnoreNotFound(err)
+			}
+		}
+		return client.IgnoreNotFound(hubclient.Delete(ctx, k8sClient, obj))
+	}, timeout, interval).Should(gomega.Succeed(), "Failed to delete the exported object %s", key)
+}
+
+// withIdentity returns a copy of the Service with the type, the UID, the resource version and the creation timestamp
+// set, if unset, as the API server of a member cluster would, since the Service is never written.
+func withIdentity(member *Member, svc *corev1.Service) *corev1.Service {
+	svc = svc.DeepCopy()
+	svc.TypeMeta = metav1.TypeMeta{APIVersion: "v1", Kind: "Service"}
+	if svc.UID == "" {
+		svc.UID = types.UID(fmt.Sprintf("%s-%s-%s", member.Name, svc.Namespace, svc.Name))
+	}
+	if svc.ResourceVersion == "" {
+		svc.ResourceVersion = "1"
+	}
+	if svc.CreationTimestamp.IsZero() {
+		svc.CreationTimestamp = metav1.NewTime(time.Now().Truncate(time.Second))
+	}
+	return svc
+}
+
+// endpointSliceOf returns the single EndpointSlice of the Service whose endpoints are exported.
+func endpointSliceOf(svc *corev1.Service) *discoveryv1.EndpointSlice {
+	endpointSlice := &discoveryv1.EndpointSlice{
+		TypeMeta: metav1.TypeMeta{APIVersion: discoveryv1.SchemeGroupVersion.String(), Kind: "EndpointSlice"},
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace:       svc.Namespace,
+			Name:            svc.Name,
+			UID:             svc.UID + "-endpointslice",
+			ResourceVersion: svc.ResourceVersion,
+			Generation:      1,
+			Labels: map[string]string{
+				discoveryv1.LabelServiceName: svc.Name,
+			},
+		},
+		AddressType: discoveryv1.AddressTypeIPv4,
+	}
+	for _, port := range svc.Spec.Ports {
+		endpointSlice.Ports = append(endpointSlice.Ports, discoveryv1.EndpointPort{
+			Name:     ptr.To(port.Name),
+			Protocol: ptr.To(port.Protocol),
+			Port:     ptr.To(port.TargetPort.IntVal),
+		})
+	}
+	return endpointSlice
+}
@@ -22,6 +22,11 @@ const (
 	ValidPublicIPResourceID = "valid-public-ip-resource-id"
 	ValidEndpointTarget     = "valid-endpoint-target"
 
+	// AlternativePublicIPResourceID is the public IP of a recreated service, whose endpoint targets
+	// AlternativeEndpointTarget.
+	AlternativePublicIPResourceID = "alternative-public-ip-resource-id"
+	AlternativeEndpointTarget     = "alternative-endpoint-target"
+
 	Weight = int64(50)
 )
 
@@ -70,7 +75,7 @@ func EndpointDelete(_ context.Context, resourceGroupName string, profileName str
 	return resp, errResp
 }
 
-func EndpointCreateOrUpdate(_ context.Context, resourceGroupName string, profileName string, endpointType armtrafficmanager.EndpointType, endpointName string, _ armtrafficmanager.Endpoint, _ *armtrafficmanager.EndpointsClientCreateOrUpdateOptions) (resp azcorefake.Responder[armtrafficmanager.EndpointsClientCreateOrUpdateResponse], errResp azcorefake.ErrorResponder) {
+func EndpointCreateOrUpdate(_ context.Context, resourceGroupName string, profileName string, endpointType armtrafficmanager.EndpointType, endpointName string, endpoint armtrafficmanager.Endpoint, _ *armtrafficmanager.EndpointsClientCreateOrUpdateOptions) (resp azcorefake.Responder[armtrafficmanager.EndpointsClientCreateOrUpdateResponse], errResp azcorefake.ErrorResponder) {
 	if resourceGroupName != DefaultResourceGroupName {
 		errResp.SetResponseError(http.StatusNotFound, "ResourceGroupNotFound")
 		return resp, errResp
@@ -95,6 +100,19 @@ func EndpointCreateOrUpdate(_ context.Context, resourceGroupName string, profile
 				Type: ptr.To(string(azureTrafficManagerEndpointTypePrefix + armtrafficmanager.EndpointTypeAzureEndpoints)),
 			},
 		}
+		if endpoint.Properties != nil {
+			// echo the overrides, the priority and the weight so that the callers can verify the request.
+			endpointResp.Endpoint.Properties.CustomHeaders = endpoint.Properties.CustomHeaders
+			endpointResp.Endpoint.Properties.Subnets = endpoint.Properties.Subnets
+			endpointResp.Endpoint.Properties.Priority = endpoint.Properties.Priority
+			if endpoint.Properties.Weight != nil {
+				endpointResp.Endpoint.Properties.Weight = endpoint.Properties.Weight
+			}
+			if ptr.Deref(endpoint.Properties.TargetResourceID, "") == AlternativePublicIPResourceID {
+				endpointResp.Endpoint.Properties.TargetResourceID = ptr.To(AlternativePublicIPResourceID)
+				endpointResp.Endpoint.Properties.Target = ptr.To(AlternativeEndpointTarget)
+			}
+		}
 		resp.SetResponse(http.StatusOK, endpointResp, nil)
 	} else {
 		if endpointType != armtrafficmanager.EndpointTypeAzureEndpoints {
@@ -0,0 +1,104 @@
+/*
+Copyright (c) Microsoft Corporation.
+Licensed under the MIT license.
+*/
+
+package fakeprovider
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/arm"
+	azcorefake "github.com/Azure/azure-sdk-for-go/sdk/azcore/fake"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/trafficmanager/armtrafficmanager"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/trafficmanager/armtrafficmanager/fake"
+)
+
+// MutationRecorder records the requests which change the Azure Traffic Manager resources, i.e., all the requests
+// except the GETs and the checks of the availability of the relative DNS names, before passing them on to the fake
+// servers; it is used to verify that no resource is changed in the read-only mode.
+type MutationRecorder struct {
+	mu       sync.Mutex
+	requests []string
+}
+
+// NewMutationRecorder creates a recorder without any recorded request.
+func NewMutationRecorder() *MutationRecorder {
+	return &MutationRecorder{}
+}
+
+// NewProfileClient creates a client which talks to the fake profile server through the recorder.
+func (m *MutationRecorder) NewProfileClient(subscriptionID string) (*armtrafficmanager.ProfilesClient, error) {
+	clientFactory, err := m.newClientFactory(subscriptionID, fake.NewProfilesServerTransport(&fake.ProfilesServer{
+		CheckTrafficManagerRelativeDNSNameAvailability: ProfileCheckRelativeDNSNameAvailability,
+		CreateOrUpdate: ProfileCreateOrUpdate,
+		Delete:         ProfileDelete,
+		Get:            ProfileGet,
+	}))
+	if err != nil {
+		return nil, err
+	}
+	return clientFactory.NewProfilesClient(), nil
+}
+
+// NewEndpointsClient creates a client which talks to the fake endpoint server through the recorder.
+func (m *MutationRecorder) NewEndpointsClient(subscriptionID string) (*armtrafficmanager.EndpointsClient, error) {
+	clientFactory, err := m.newClientFactory(subscriptionID, fake.NewEndpointsServerTransport(&fake.EndpointsServer{
+		Delete:         EndpointDelete,
+		CreateOrUpdate: EndpointCreateOrUpdate,
+	}))
+	if err != nil {
+		return nil, err
+	}
+	return clientFactory.NewEndpointsClient(), nil
+}
+
+// Requests returns the method and the path of the recorded requests.
+func (m *MutationRecorder) Requests() []string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]string(nil), m.requests...)
+}
+
+func (m *MutationRecorder) newClientFactory(subscriptionID string, next policy.Transporter) (*armtrafficmanager.ClientFactory, error) {
+	return armtrafficmanager.NewClientFactory(subscriptionID, &azcorefake.TokenCredential{},
+		&arm.ClientOptions{
+			ClientOptions: azcore.ClientOptions{
+				Transport: &recordingTransport{recorder: m, next: next},
+			},
+		})
+}
+
+// recordingTransport records the mutating requests with the recorder and passes all the requests on to the next
+// transport.
+type recordingTransport struct {
+	recorder *MutationRecorder
+	next     policy.Transporter
+}
+
+// Do implements the policy.Transporter interface.
+func (t *recordingTransport) Do(req *http.Request) (*http.Response, error) {
+	if isMutatingRequest(req) {
+		t.recorder.mu.Lock()
+		t.recorder.requests = append(t.recorder.requests, fmt.Sprintf("%s %s", req.Method, req.URL.Path))
+		t.recorder.mu.Unlock()
+	}
+	return t.next.Do(req)
+}
+
+func isMutatingRequest(req *http.Request) bool {
+	switch req.Method {
+	case http.MethodGet, http.MethodHead:
+		return false
+	case http.MethodPost:
+		// The check of the availability of a relative DNS name is a POST which reads only.
+		return !strings.Contains(req.URL.Path, "/checkTrafficManagerNameAvailability")
+	default:
+		return true
+	}
+}
@@ -27,10 +27,12 @@ const (
 	DefaultResourceGroupName = "default-resource-group-name"
 
 	ValidProfileName                         = "valid-profile"
+	NewProfileName                           = "new-profile"
 	ValidProfileWithEndpointsName            = "valid-profile-with-endpoints"
 	ValidProfileWithNilPropertiesName        = "valid-profile-with-empty-properties"
 	ValidProfileWithFailToDeleteEndpointName = "valid-profile-with-fail-to-delete-endpoint"
 	ConflictErrProfileName                   = "conflict-err-profile"
+	DNSNameUnavailableProfileName            = "dns-name-unavailable-profile"
 	InternalServerErrProfileName             = "internal-server-err-profile"
 	ThrottledErrProfileName                  = "throttled-err-profile"
 	RequestTimeoutProfileName                = "request-timeout-profile"
@@ -58,6 +60,7 @@ var (
 // NewProfileClient creates a client which talks to a fake profile server.
 func NewProfileClient(subscriptionID string) (*armtrafficmanager.ProfilesClient, error) {
 	fakeServer := fake.ProfilesServer{
+		CheckTrafficManagerRelativeDNSNameAvailability: ProfileCheckRelativeDNSNameAvailability,
 		CreateOrUpdate: ProfileCreateOrUpdate,
 		Delete:         ProfileDelete,
 		Get:            ProfileGet,
@@ -158,13 +161,13 @@ func ProfileCreateOrUpdate(_ context.Context, resourceGroupName string, profileN
 		return resp, errResp
 	}
 	switch profileName {
-	case ConflictErrProfileName:
+	case ConflictErrProfileName, DNSNameUnavailableProfileName:
 		errResp.SetResponseError(http.StatusConflict, "Conflict")
 	case InternalServerErrProfileName:
 		errResp.SetResponseError(http.StatusInternalServerError, "InternalServerError")
 	case ThrottledErrProfileName:
 		errResp.SetResponseError(http.StatusTooManyRequests, "ThrottledError")
-	case ValidProfileName:
+	case ValidProfileName, NewProfileName:
 		if parameters.Properties.MonitorConfig.IntervalInSeconds != nil && *parameters.Properties.MonitorConfig.IntervalInSeconds == 10 {
 			if parameters.Properties.MonitorConfig.TimeoutInSeconds != nil && *parameters.Properties.MonitorConfig.TimeoutInSeconds > 9 {
 				errResp.SetResponseError(http.StatusBadRequest, "BadRequestError")
@@ -195,6 +198,29 @@ func ProfileCreateOrUpdate(_ context.Context, resourceGroupName string, profileN
 	return resp, errResp
 }
 
+// ProfileCheckRelativeDNSNameAvailability returns the availability of the relative DNS name based on the profile name
+// it ends with; the relative names of the profiles named DNSNameUnavailableProfileName are taken, and the check fails
+// for the profiles named InternalServerErrProfileName.
+func ProfileCheckRelativeDNSNameAvailability(_ context.Context, parameters armtrafficmanager.CheckTrafficManagerRelativeDNSNameAvailabilityParameters, _ *armtrafficmanager.ProfilesClientCheckTrafficManagerRelativeDNSNameAvailabilityOptions) (resp azcorefake.Responder[armtrafficmanager.ProfilesClientCheckTrafficManagerRelativeDNSNameAvailabilityResponse], errResp azcorefake.ErrorResponder) {
+	relativeName := ptr.Deref(parameters.Name, "")
+	availability := armtrafficmanager.NameAvailability{
+		Name:          parameters.Name,
+		Type:          parameters.Type,
+		NameAvailable: ptr.To(true),
+	}
+	switch {
+	case strings.HasSuffix(relativeName, InternalServerErrProfileName):
+		errResp.SetResponseError(http.StatusInternalServerError, "InternalServerError")
+		return resp, errResp
+	case strings.HasSuffix(relativeName, DNSNameUnavailableProfileName):
+		availability.NameAvailable = ptr.To(false)
+		availability.Reason = ptr.To("AlreadyExists")
+		availability.Message = ptr.To(fmt.Sprintf("Domain name %s already exists. Please choose a different DNS prefix.", fmt.Sprintf(ProfileDNSNameFormat, relativeName)))
+	}
+	resp.SetResponse(http.StatusOK, armtrafficmanager.ProfilesClientCheckTrafficManagerRelativeDNSNameAvailabilityResponse{NameAvailability: availability}, nil)
+	return resp, errResp
+}
+
 // ProfileDelete returns the http status code based on the profileName.
 func ProfileDelete(_ context.Context, resourceGroupName string, profileName string, _ *armtrafficmanager.ProfilesClientDeleteOptions) (resp azcorefake.Responder[armtrafficmanager.ProfilesClientDeleteResponse], errResp azcorefake.ErrorResponder) {
 	if resourceGroupName != DefaultResourceGroupName {
@@ -0,0 +1,228 @@
+/*
+Copyright (c) Microsoft Corporation.
+Licensed under the MIT license.
+*/
+
+package fakeprovider
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"path"
+	"strings"
+	"sync"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/arm"
+	azcorefake "github.com/Azure/azure-sdk-for-go/sdk/azcore/fake"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/trafficmanager/armtrafficmanager"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/trafficmanager/armtrafficmanager/fake"
+	"k8s.io/utils/ptr"
+)
+
+// ProfileStore is a stateful fake of the Azure Traffic Manager profiles which supports optimistic concurrency: the
+// Get and CreateOrUpdate responses carry the ETag of the profile, and a CreateOrUpdate request whose If-Match header
+// does not match the current ETag fails with 412 Precondition Failed.
+//
+// A CreateOrUpdate request fails with 400 Bad Request if any of its endpoints belongs to the
+// CreateBadRequestErrEndpointClusterName cluster. The monitor status of a profile, which Azure reports on its own, is
+// set with SetMonitorStatus and kept across the CreateOrUpdate requests.
+type ProfileStore struct {
+	// BeforeCreateOrUpdate, if set, is called before a CreateOrUpdate request is processed; it can be used to
+	// simulate concurrent updates of the profile.
+	BeforeCreateOrUpdate func(profileName string)
+
+	// requestMu serializes the requests, so that the ETag check and the update are atomic.
+	requestMu sync.Mutex
+
+	mu                   sync.Mutex
+	profiles             map[string]armtrafficmanager.Profile
+	versions             map[string]int
+	createOrUpdateCounts map[string]int
+	transport            *fake.ProfilesServerTransport
+}
+
+// NewProfileStore creates an empty profile store.
+func NewProfileStore() *ProfileStore {
+	s := &ProfileStore{
+		profiles:             make(map[string]armtrafficmanager.Profile),
+		versions:             make(map[string]int),
+		createOrUpdateCounts: make(map[string]int),
+	}
+	s.transport = fake.NewProfilesServerTransport(&fake.ProfilesServer{
+		CreateOrUpdate: s.createOrUpdate,
+		Delete:         s.delete,
+		Get:            s.get,
+	})
+	return s
+}
+
+// NewProfileClient creates a client which talks to the profile store.
+func (s *ProfileStore) NewProfileClient(subscriptionID string) (*armtrafficmanager.ProfilesClient, error) {
+	clientFactory, err := armtrafficmanager.NewClientFactory(subscriptionID, &azcorefake.TokenCredential{},
+		&arm.ClientOptions{
+			ClientOptions: azcore.ClientOptions{
+				Transport: s,
+			},
+		})
+	if err != nil {
+		return nil, err
+	}
+	return clientFactory.NewProfilesClient(), nil
+}
+
+// Set stores the profile, as if it was updated by another client, and changes its ETag.
+func (s *ProfileStore) Set(profile armtrafficmanager.Profile) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.profiles[*profile.Name] = profile
+	s.versions[*profile.Name]++
+}
+
+// SetMonitorStatus sets the profile-level monitor status of the stored profile, as if Azure reported a change of the
+// health of its endpoints; the ETag of the profile is kept. It returns false if the profile does not exist.
+func (s *ProfileStore) SetMonitorStatus(profileName string, status armtrafficmanager.ProfileMonitorStatus) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	profile, ok := s.profiles[profileName]
+	if !ok {
+		return false
+	}
+	props := armtrafficmanager.ProfileProperties{}
+	if profile.Properties != nil {
+		props = *profile.Properties
+	}
+	monitorConfig := armtrafficmanager.MonitorConfig{}
+	if props.MonitorConfig != nil {
+		monitorConfig = *props.MonitorConfig
+	}
+	monitorConfig.ProfileMonitorStatus = ptr.To(status)
+	props.MonitorConfig = &monitorConfig
+	profile.Properties = &props
+	s.profiles[profileName] = profile
+	return true
+}
+
+// Profile returns the stored profile.
+func (s *ProfileStore) Profile(profileName string) (armtrafficmanager.Profile, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	profile, ok := s.profiles[profileName]
+	return profile, ok
+}
+
+// CreateOrUpdateCount returns the number of CreateOrUpdate requests received for the profile, including the failed
+// ones.
+func (s *ProfileStore) CreateOrUpdateCount(profileName string) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.createOrUpdateCounts[profileName]
+}
+
+// Do implements the policy.Transporter interface.
+func (s *ProfileStore) Do(req *http.Request) (*http.Response, error) {
+	profileName := path.Base(req.URL.Path)
+	if req.Method == http.MethodPut && s.BeforeCreateOrUpdate != nil {
+		s.BeforeCreateOrUpdate(profileName)
+	}
+
+	s.requestMu.Lock()
+	defer s.requestMu.Unlock()
+	if req.Method == http.MethodPut {
+		s.mu.Lock()
+		s.createOrUpdateCounts[profileName]++
+		etag := s.etag(profileName)
+		s.mu.Unlock()
+		if ifMatch := req.Header.Get("If-Match"); ifMatch != "" && ifMatch != etag {
+			return &http.Response{
+				StatusCode: http.StatusPreconditionFailed,
+				Header: http.Header{
+					"Content-Type":    []string{"application/json"},
+					"X-Ms-Error-Code": []string{"PreconditionFailed"},
+				},
+				Body:    io.NopCloser(strings.NewReader(`{"error":{"code":"PreconditionFailed","message":"The ETag does not match"}}`)),
+				Request: req,
+			}, nil
+		}
+	}
+
+	resp, err := s.transport.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode == http.StatusOK {
+		if resp.Header == nil {
+			resp.Header = http.Header{}
+		}
+		s.mu.Lock()
+		resp.Header.Set("ETag", s.etag(profileName))
+		s.mu.Unlock()
+	}
+	return resp, nil
+}
+
+// etag returns the ETag of the profile; the caller must hold the lock.
+func (s *ProfileStore) etag(profileName string) string {
+	return fmt.Sprintf(`"%d"`, s.versions[profileName])
+}
+
+func (s *ProfileStore) get(_ context.Context, resourceGroupName string, profileName string, _ *armtrafficmanager.ProfilesClientGetOptions) (resp azcorefake.Responder[armtrafficmanager.ProfilesClientGetResponse], errResp azcorefake.ErrorResponder) {
+	if resourceGroupName != DefaultResourceGroupName {
+		errResp.SetResponseError(http.StatusNotFound, "ResourceGroupNotFound")
+		return resp, errResp
+	}
+	profile, ok := s.Profile(profileName)
+	if !ok {
+		errResp.SetResponseError(http.StatusNotFound, "NotFoundError")
+		return resp, errResp
+	}
+	resp.SetResponse(http.StatusOK, armtrafficmanager.ProfilesClientGetResponse{Profile: profile}, nil)
+	return resp, errResp
+}
+
+func (s *ProfileStore) createOrUpdate(_ context.Context, resourceGroupName string, profileName string, parameters armtrafficmanager.Profile, _ *armtrafficmanager.ProfilesClientCreateOrUpdateOptions) (resp azcorefake.Responder[armtrafficmanager.ProfilesClientCreateOrUpdateResponse], errResp azcorefake.ErrorResponder) {
+	if resourceGroupName != DefaultResourceGroupName {
+		errResp.SetResponseError(http.StatusNotFound, "ResourceGroupNotFound")
+		return resp, errResp
+	}
+	if parameters.Properties != nil {
+		for _, endpoint := range parameters.Properties.Endpoints {
+			if endpoint != nil && endpoint.Name != nil && strings.HasSuffix(*endpoint.Name, "#"+CreateBadRequestErrEndpointClusterName) {
+				errResp.SetResponseError(http.StatusBadRequest, "BadRequest")
+				return resp, errResp
+			}
+		}
+		for _, endpoint := range parameters.Properties.Endpoints {
+			if endpoint != nil && endpoint.Properties != nil && endpoint.Properties.Target == nil {
+				endpoint.Properties.Target = ptr.To(ValidEndpointTarget)
+			}
+		}
+	}
+	parameters.Name = ptr.To(profileName)
+	if current, ok := s.Profile(profileName); ok && current.Properties != nil && current.Properties.MonitorConfig != nil &&
+		parameters.Properties != nil && parameters.Properties.MonitorConfig != nil {
+		parameters.Properties.MonitorConfig.ProfileMonitorStatus = current.Properties.MonitorConfig.ProfileMonitorStatus
+	}
+	s.Set(parameters)
+	resp.SetResponse(http.StatusOK, armtrafficmanager.ProfilesClientCreateOrUpdateResponse{Profile: parameters}, nil)
+	return resp, errResp
+}
+
+func (s *ProfileStore) delete(_ context.Context, resourceGroupName string, profileName string, _ *armtrafficmanager.ProfilesClientDeleteOptions) (resp azcorefake.Responder[armtrafficmanager.ProfilesClientDeleteResponse], errResp azcorefake.ErrorResponder) {
+	if resourceGroupName != DefaultResourceGroupName {
+		errResp.SetResponseError(http.StatusNotFound, "ResourceGroupNotFound")
+		return resp, errResp
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.profiles[profileName]; !ok {
+		errResp.SetResponseError(http.StatusNotFound, "NotFoundError")
+		return resp, errResp
+	}
+	delete(s.profiles, profileName)
+	delete(s.versions, profileName)
+	resp.SetResponse(http.StatusOK, armtrafficmanager.ProfilesClientDeleteResponse{}, nil)
+	return resp, errResp
+}
@@ -29,11 +29,13 @@ var (
 		cmpopts.SortSlices(func(s1, s2 fleetnetv1beta1.TrafficManagerEndpointStatus) bool {
 			return s1.From.Cluster < s2.From.Cluster
 		}),
+		cmpopts.IgnoreFields(fleetnetv1beta1.TrafficManagerInvalidCluster{}, "Message"), // the message is for humans only
 		cmpConditionOptions,
 	}
 
 	cmpTrafficManagerBackendStatusByIgnoringEndpointName = cmp.Options{
 		cmpConditionOptions,
+		cmpLastSyncedTimeOptions,
 		cmpopts.IgnoreFields(fleetnetv1beta1.TrafficManagerEndpointStatus{}, "Name"), // ignore the generated endpoint name
 		cmpopts.SortSlices(func(s1, s2 fleetnetv1beta1.TrafficManagerEndpointStatus) bool {
 			return s1.From.Cluster < s2.From.Cluster
@@ -103,6 +105,34 @@ func ValidateTrafficManagerBackendIfAcceptedAndIgnoringEndpointName(ctx context.
 	return gotStatus
 }
 
+// ValidateTrafficManagerBackendDisabled validates the trafficManagerBackend object is disabled by its weight of 0,
+// without any endpoints.
+func ValidateTrafficManagerBackendDisabled(ctx context.Context, k8sClient client.Client, backendName types.NamespacedName) fleetnetv1beta1.TrafficManagerBackendStatus {
+	var gotStatus fleetnetv1beta1.TrafficManagerBackendStatus
+	gomega.Eventually(func() error {
+		backend := &fleetnetv1beta1.TrafficManagerBackend{}
+		if err := k8sClient.Get(ctx, backendName, backend); err != nil {
+			return err
+		}
+		wantStatus := fleetnetv1beta1.TrafficManagerBackendStatus{
+			Conditions: []metav1.Condition{
+				{
+					Status:             metav1.ConditionTrue,
+					Type:               string(fleetnetv1beta1.TrafficManagerBackendConditionAccepted),
+					Reason:             string(fleetnetv1beta1.TrafficManagerBackendReasonDisabled),
+					ObservedGeneration: backend.Generation,
+				},
+			},
+		}
+		gotStatus = backend.Status
+		if diff := cmp.Diff(gotStatus, wantStatus, cmpConditionOptions, cmpLastSyncedTimeOptions); diff != "" {
+			return fmt.Errorf("trafficManagerBackend status diff (-got, +want): \n%s, got %+v", diff, gotStatus)
+		}
+		return nil
+	}, timeout, interval).Should(gomega.Succeed(), "Get() trafficManagerBackend status mismatch")
+	return gotStatus
+}
+
 // ValidateTrafficManagerBackendStatusAndIgnoringEndpointNameConsistently validates the trafficManagerBackend status consistently
 // while ignoring the generated endpoint name.
 func ValidateTrafficManagerBackendStatusAndIgnoringEndpointNameConsistently(ctx context.Context, k8sClient client.Client, backendName types.NamespacedName, want fleetnetv1beta1.TrafficManagerBackendStatus) {
@@ -0,0 +1,49 @@
+/*
+Copyright (c) Microsoft Corporation.
+Licensed under the MIT license.
+*/
+
+package validator
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// ValidateEmittedEvents validates the events emitted for the object by comparing their types and reasons only; the
+// repeated events are aggregated by the recorder, so each type and reason is expected once.
+func ValidateEmittedEvents(ctx context.Context, k8sClient client.Client, obj client.Object, want []corev1.Event) {
+	gomega.Eventually(func() error {
+		eventList := &corev1.EventList{}
+		if err := k8sClient.List(ctx, eventList, client.InNamespace(obj.GetNamespace())); err != nil {
+			return err
+		}
+		got := make([]corev1.Event, 0, len(eventList.Items))
+		seen := make(map[string]bool, len(eventList.Items))
+		for _, event := range eventList.Items {
+			// The objects may be recreated with the same name by the tests.
+			if event.InvolvedObject.UID != obj.GetUID() {
+				continue
+			}
+			key := event.Type + "/" + event.Reason
+			if seen[key] {
+				continue
+			}
+			seen[key] = true
+			got = append(got, corev1.Event{Type: event.Type, Reason: event.Reason})
+		}
+		sortEvents := cmpopts.SortSlices(func(e1, e2 corev1.Event) bool {
+			return e1.Type+"/"+e1.Reason < e2.Type+"/"+e2.Reason
+		})
+		if diff := cmp.Diff(want, got, sortEvents, cmpopts.EquateEmpty()); diff != "" {
+			return fmt.Errorf("events of %s mismatch (-want, +got):\n%s", obj.GetName(), diff)
+		}
+		return nil
+	}, timeout, interval).Should(gomega.Succeed(), "List() events mismatch")
+}
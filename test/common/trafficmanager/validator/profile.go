@@ -37,10 +37,17 @@ var (
 		cmpopts.SortSlices(func(c1, c2 metav1.Condition) bool {
 			return c1.Type < c2.Type
 		}),
+		cmpLastSyncedTimeOptions,
 	}
 	cmpConditionOptions = cmp.Options{
 		cmpopts.IgnoreFields(metav1.Condition{}, "Message", "LastTransitionTime"),
 	}
+	// cmpLastSyncedTimeOptions ignores the last synced time, which keeps moving while the objects are reconciled, and
+	// the monitor status of the profiles, which follows the probes of Azure Traffic Manager.
+	cmpLastSyncedTimeOptions = cmp.Options{
+		cmpopts.IgnoreFields(fleetnetv1beta1.TrafficManagerProfileStatus{}, "LastSyncedTime", "MonitorStatus"),
+		cmpopts.IgnoreFields(fleetnetv1beta1.TrafficManagerBackendStatus{}, "LastSyncedTime"),
+	}
 	cmpTrafficManagerProfileOptions = cmp.Options{
 		commonCmpOptions,
 		cmpConditionOptions,
@@ -86,6 +93,7 @@ func ValidateIfTrafficManagerProfileIsProgrammed(ctx context.Context, k8sClient
 			profile.Status,
 			wantStatus,
 			cmpConditionOptions,
+			cmpLastSyncedTimeOptions,
 		); diff != "" {
 			return fmt.Errorf("trafficManagerProfile status diff (-got, +want): %s", diff)
 		}
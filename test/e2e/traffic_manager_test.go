@@ -573,7 +573,7 @@ var _ = Describe("Test exporting service via Azure traffic manager", Ordered, fu
 			}, framework.PollTimeout, framework.PollInterval).Should(Succeed(), "Failed to update the trafficManagerBackend")
 
 			By("Validating the trafficManagerBackend status")
-			status := validator.ValidateTrafficManagerBackendIfAcceptedAndIgnoringEndpointName(ctx, hubClient, backendName, true, nil)
+			status := validator.ValidateTrafficManagerBackendDisabled(ctx, hubClient, backendName)
 			validator.ValidateTrafficManagerBackendStatusAndIgnoringEndpointNameConsistently(ctx, hubClient, backendName, status)
 
 			By("Validating the Azure traffic manager profile")